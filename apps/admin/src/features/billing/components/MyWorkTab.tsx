@@ -158,6 +158,17 @@ export function MyWorkTab() {
                         <span className="text-[10px] text-muted-foreground uppercase font-mono tracking-wider">
                           {item.entity_type}
                         </span>
+                        {item.last_email_status && (
+                          <span className={cn(
+                            "flex items-center gap-1 text-[10px]",
+                            ["bounced", "complained", "failed"].includes(item.last_email_status)
+                              ? "text-red-600"
+                              : "text-muted-foreground"
+                          )}>
+                            <Mail className="h-3 w-3" />
+                            Last email {item.last_email_status}
+                          </span>
+                        )}
                       </div>
                     </TableCell>
                     <TableCell className="font-mono text-xs tabular-nums">
@@ -92,6 +92,22 @@ const providerFields: Record<string, ProviderField[]> = {
       type: "password",
       helper: "Used to validate settlement webhooks.",
     },
+    {
+      key: "webhook_tolerance_seconds",
+      label: "Webhook tolerance (seconds)",
+      placeholder: "300",
+      type: "text",
+      helper: "Webhooks signed longer ago than this are rejected as replays. Defaults to 300.",
+      optional: true,
+    },
+    {
+      key: "stripe_account_id",
+      label: "Connected account ID",
+      placeholder: "acct_...",
+      type: "text",
+      helper: "For Stripe Connect platforms: charges and payment methods are created on this account.",
+      optional: true,
+    },
   ],
   midtrans: [
     {
@@ -15,6 +15,8 @@ export type ResolutionType =
   | "escalated_to_manager"
   | "other"
 
+export type EmailDeliveryStatus = "sent" | "delivered" | "bounced" | "complained" | "failed"
+
 // ===== Inbox Types =====
 
 export interface InboxItem {
@@ -33,6 +35,7 @@ export interface InboxItem {
   invoice_id?: string
   invoice_number?: string
   last_attempt?: string | null
+  last_email_status?: EmailDeliveryStatus
 }
 
 export interface InboxResponse {
@@ -71,6 +74,9 @@ export interface MyWorkItem {
   status: AssignmentStatus
   sla_status: SLAStatus
 
+  // Delivery status of the latest follow-up email sent by Railzway
+  last_email_status?: EmailDeliveryStatus
+
   // Snapshot metadata (full JSON)
   snapshot_metadata?: Record<string, any>
 }
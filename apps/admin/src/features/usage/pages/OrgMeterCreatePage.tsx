@@ -18,9 +18,9 @@ import { useOrgStore } from "@/stores/orgStore"
 const aggregationOptions = [
   { label: "Sum", value: "SUM" },
   { label: "Count", value: "COUNT" },
+  { label: "Unique count", value: "UNIQUE_COUNT" },
   { label: "Max", value: "MAX" },
-  { label: "Min", value: "MIN" },
-  { label: "Average", value: "AVG" },
+  { label: "Last", value: "LAST" },
 ]
 
 export default function OrgMeterCreatePage() {
@@ -120,6 +120,9 @@ export default function OrgMeterCreatePage() {
                 onChange={(event) => setCode(event.target.value)}
                 required
               />
+              <p className="text-xs text-text-muted">
+                Lowercase letters, digits and underscores. Usage events match it regardless of case.
+              </p>
             </div>
             <div className="space-y-2">
               <Label>Aggregation method</Label>
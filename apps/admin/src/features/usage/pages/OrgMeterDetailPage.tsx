@@ -74,7 +74,8 @@ const formatTimestamp = (value: string) => {
 const formatAggregation = (value: string) => {
   const trimmed = value.trim()
   if (!trimmed) return "-"
-  return trimmed.charAt(0).toUpperCase() + trimmed.slice(1).toLowerCase()
+  const words = trimmed.replace(/_/g, " ")
+  return words.charAt(0).toUpperCase() + words.slice(1).toLowerCase()
 }
 
 const formatLiveValue = (value: number) => {
@@ -272,7 +273,7 @@ export default function OrgMeterDetailPage() {
             }}
             disabled={!canManage}
           >
-            Delete meter
+            Archive meter
           </Button>
         </div>
       </div>
@@ -458,7 +459,7 @@ export default function OrgMeterDetailPage() {
                   <SelectValue placeholder="Select aggregation method" />
                 </SelectTrigger>
                 <SelectContent>
-                  {["SUM", "COUNT", "MAX", "MIN", "AVG"].map((option) => (
+                  {["SUM", "COUNT", "UNIQUE_COUNT", "MAX", "LAST"].map((option) => (
                     <SelectItem key={option} value={option}>
                       {option}
                     </SelectItem>
@@ -538,9 +539,9 @@ export default function OrgMeterDetailPage() {
       >
         <AlertDialogContent>
           <AlertDialogHeader>
-            <AlertDialogTitle>Delete meter</AlertDialogTitle>
+            <AlertDialogTitle>Archive meter</AlertDialogTitle>
             <AlertDialogDescription>
-              This permanently deletes the meter and cannot be undone. Billing events tied to this meter will stop ingesting.
+              Archived meters can no longer be added to subscriptions but stay available for past billing. Meters still used by a subscription that has not ended cannot be archived.
             </AlertDialogDescription>
           </AlertDialogHeader>
           {deleteError && <div className="text-status-error text-sm">{deleteError}</div>}
@@ -556,12 +557,12 @@ export default function OrgMeterDetailPage() {
                   await admin.delete(`/meters/${meterId}`)
                   navigate(`/orgs/${orgId}/meter`, { replace: true })
                 } catch (err) {
-                  setDeleteError(getErrorMessage(err, "Unable to delete meter."))
+                  setDeleteError(getErrorMessage(err, "Unable to archive meter."))
                   setIsDeleting(false)
                 }
               }}
             >
-              {isDeleting ? "Deleting..." : "Delete meter"}
+              {isDeleting ? "Archiving..." : "Archive meter"}
             </AlertDialogAction>
           </AlertDialogFooter>
         </AlertDialogContent>
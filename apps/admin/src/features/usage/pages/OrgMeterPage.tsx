@@ -33,7 +33,8 @@ type Meter = {
 const formatAggregation = (value: string) => {
   const trimmed = value.trim()
   if (!trimmed) return "-"
-  return trimmed.charAt(0).toUpperCase() + trimmed.slice(1).toLowerCase()
+  const words = trimmed.replace(/_/g, " ")
+  return words.charAt(0).toUpperCase() + words.slice(1).toLowerCase()
 }
 
 const formatTimestamp = (value: string) => {
@@ -15,6 +15,8 @@ import (
 	"github.com/railzwaylabs/railzway/internal/bootstrap"
 	"github.com/railzwaylabs/railzway/internal/clock"
 	"github.com/railzwaylabs/railzway/internal/config"
+	"github.com/railzwaylabs/railzway/internal/coupon"
+	"github.com/railzwaylabs/railzway/internal/events"
 	"github.com/railzwaylabs/railzway/internal/feature"
 	"github.com/railzwaylabs/railzway/internal/invoice"
 	"github.com/railzwaylabs/railzway/internal/invoicetemplate"
@@ -146,7 +148,9 @@ func runScheduler() {
 		bootstrap.Module,
 		fx.Invoke(bootstrap.EnforceSchemaGate),
 		scheduler.Module,
+		events.Module,
 		rating.Module,
+		coupon.Module,
 		invoice.Module,
 		ledger.Module,
 		subscription.Module,
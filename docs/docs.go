@@ -15,6 +15,43 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/billing-cycles/{id}/rating-preview": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Compute the arrears rating of a billing cycle in any status without storing the results",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "billing_cycles"
+                ],
+                "summary": "Preview Billing Cycle Rating",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Billing Cycle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/countries": {
             "get": {
                 "security": [
@@ -43,6 +80,85 @@ const docTemplate = `{
                 }
             }
         },
+        "/coupons": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the organization's coupons, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "coupons"
+                ],
+                "summary": "List Coupons",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Active filter",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a percent or fixed amount coupon that can be applied to subscriptions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "coupons"
+                ],
+                "summary": "Create Coupon",
+                "parameters": [
+                    {
+                        "description": "Create Coupon Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.createCouponRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/currencies": {
             "get": {
                 "security": [
@@ -223,14 +339,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/features": {
-            "get": {
+        "/customers/{id}/charges": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List available features",
+                "description": "Invoice a customer for one-time charges outside any subscription. The invoice is finalized and charged to the customer's default payment method when there is one",
                 "consumes": [
                     "application/json"
                 ],
@@ -238,75 +354,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "invoices"
                 ],
-                "summary": "List Features",
+                "summary": "Create One-Time Charge",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Name",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Code",
-                        "name": "code",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Feature Type",
-                        "name": "feature_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Active",
-                        "name": "active",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort By",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Order By",
-                        "name": "order_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Page Token",
-                        "name": "page_token",
-                        "in": "query"
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page Size",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "Create One-Time Charge Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.createOneTimeChargeRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/server.ListResponse"
+                            "$ref": "#/definitions/server.DataResponse"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/customers/{id}/currency": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new feature",
+                "description": "Change the customer's billing currency; refused while the customer has subscriptions that have not ended or unpaid invoices",
                 "consumes": [
                     "application/json"
                 ],
@@ -314,23 +400,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "customers"
                 ],
-                "summary": "Create Feature",
+                "summary": "Update Customer Currency",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Idempotency Key",
-                        "name": "Idempotency-Key",
-                        "in": "header"
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "Create Feature Request",
+                        "description": "Update Customer Currency Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.createFeatureRequest"
+                            "$ref": "#/definitions/server.updateCustomerCurrencyRequest"
                         }
                     }
                 ],
@@ -344,14 +431,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/features/{id}": {
-            "patch": {
+        "/customers/{id}/entitlements/{feature_code}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update a feature",
+                "description": "Check whether a customer's active subscription grants a feature, with the remaining included quota for metered features",
                 "consumes": [
                     "application/json"
                 ],
@@ -359,25 +446,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "customers"
                 ],
-                "summary": "Update Feature",
+                "summary": "Check Customer Entitlement",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Feature ID",
+                        "description": "Customer ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update Feature Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.updateFeatureRequest"
-                        }
+                        "type": "string",
+                        "description": "Feature Code",
+                        "name": "feature_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "At (RFC3339 or YYYY-MM-DD, defaults to now)",
+                        "name": "at",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -390,14 +481,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/features/{id}/archive": {
-            "post": {
+        "/customers/{id}/payment-terms": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Archive a feature",
+                "description": "Set the customer's net payment terms, overriding the organization's for invoices finalized afterwards; null clears the override",
                 "consumes": [
                     "application/json"
                 ],
@@ -405,16 +496,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "customers"
                 ],
-                "summary": "Archive Feature",
+                "summary": "Update Customer Payment Terms",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Feature ID",
+                        "description": "Customer ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update Customer Payment Terms Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.updateCustomerPaymentTermsRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -427,14 +527,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/invoices": {
+        "/customers/{id}/statement": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List available invoices",
+                "description": "Get the customer's balance and account activity between from and to, in the customer's currency",
                 "consumes": [
                     "application/json"
                 ],
@@ -442,124 +542,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "invoices"
+                    "customers"
                 ],
-                "summary": "List Invoices",
+                "summary": "Get Customer Statement",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Status",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Invoice Number",
-                        "name": "invoice_number",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "description": "Customer ID",
-                        "name": "customer_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Created From",
-                        "name": "created_from",
-                        "in": "query"
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Created To",
-                        "name": "created_to",
+                        "description": "From (defaults to 30 days before to)",
+                        "name": "from",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Due From",
-                        "name": "due_from",
+                        "description": "To (defaults to now)",
+                        "name": "to",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Due To",
-                        "name": "due_to",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Finalized From",
-                        "name": "finalized_from",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Finalized To",
-                        "name": "finalized_to",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Total Min",
-                        "name": "total_min",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Total Max",
-                        "name": "total_max",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Page Token",
-                        "name": "page_token",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page Size",
-                        "name": "page_size",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/server.ListResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/invoices/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get invoice by ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "invoices"
-                ],
-                "summary": "Get Invoice",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Invoice ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -572,46 +576,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/invoices/{id}/render": {
+        "/features": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Render invoice PDF/HTML",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "invoices"
-                ],
-                "summary": "Render Invoice",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Invoice ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.RenderInvoiceResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meters": {
-            "get": {
-                "description": "List meters",
+                "description": "List available features",
                 "consumes": [
                     "application/json"
                 ],
@@ -619,9 +591,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meters"
+                    "features"
                 ],
-                "summary": "List Meters",
+                "summary": "List Features",
                 "parameters": [
                     {
                         "type": "string",
@@ -635,6 +607,12 @@ const docTemplate = `{
                         "name": "code",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Feature Type",
+                        "name": "feature_type",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
                         "description": "Active",
@@ -676,7 +654,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new meter",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new feature",
                 "consumes": [
                     "application/json"
                 ],
@@ -684,9 +667,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meters"
+                    "features"
                 ],
-                "summary": "Create Meter",
+                "summary": "Create Feature",
                 "parameters": [
                     {
                         "type": "string",
@@ -695,12 +678,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Create Meter Request",
+                        "description": "Create Feature Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.createMeterRequest"
+                            "$ref": "#/definitions/server.createFeatureRequest"
                         }
                     }
                 ],
@@ -714,9 +697,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/meters/{id}": {
-            "get": {
-                "description": "Get meter by ID",
+        "/features/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update a feature",
                 "consumes": [
                     "application/json"
                 ],
@@ -724,16 +712,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meters"
+                    "features"
                 ],
-                "summary": "Get Meter",
+                "summary": "Update Feature",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Meter ID",
+                        "description": "Feature ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update Feature Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.updateFeatureRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -744,36 +741,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete meter",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "meters"
-                ],
-                "summary": "Delete Meter",
-                "parameters": [
+            }
+        },
+        "/features/{id}/archive": {
+            "post": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Meter ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "ApiKeyAuth": []
                     }
                 ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
-            },
-            "patch": {
-                "description": "Update meter",
+                "description": "Archive a feature",
                 "consumes": [
                     "application/json"
                 ],
@@ -781,25 +758,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meters"
+                    "features"
                 ],
-                "summary": "Update Meter",
+                "summary": "Archive Feature",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Meter ID",
+                        "description": "Feature ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update Meter Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.updateMeterRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -812,14 +780,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/price_amounts": {
+        "/invoices": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List available price amounts",
+                "description": "List available invoices",
                 "consumes": [
                     "application/json"
                 ],
@@ -827,63 +795,113 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "price_amounts"
+                    "invoices"
                 ],
-                "summary": "List Price Amounts",
+                "summary": "List Invoices",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Price ID",
-                        "name": "price_id",
+                        "description": "Status",
+                        "name": "status",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Currency (ISO-4217)",
-                        "name": "currency",
+                        "description": "Invoice Number",
+                        "name": "invoice_number",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Effective From (RFC3339)",
-                        "name": "effective_from",
+                        "description": "Customer ID",
+                        "name": "customer_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Effective To (RFC3339)",
-                        "name": "effective_to",
+                        "description": "Created From",
+                        "name": "created_from",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Page Token",
-                        "name": "page_token",
+                        "description": "Created To",
+                        "name": "created_to",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Page Size",
-                        "name": "page_size",
+                        "type": "string",
+                        "description": "Due From",
+                        "name": "due_from",
                         "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/server.ListResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
+                    },
+                    {
+                        "type": "string",
+                        "description": "Due To",
+                        "name": "due_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Finalized From",
+                        "name": "finalized_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Finalized To",
+                        "name": "finalized_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Total Min",
+                        "name": "total_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Total Max",
+                        "name": "total_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated columns to return, e.g. id,status,total_amount",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page Token",
+                        "name": "page_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}": {
+            "get": {
+                "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new price amount",
+                "description": "Get invoice by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -891,24 +909,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "price_amounts"
+                    "invoices"
                 ],
-                "summary": "Create Price Amount",
+                "summary": "Get Invoice",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Idempotency Key",
-                        "name": "Idempotency-Key",
-                        "in": "header"
-                    },
-                    {
-                        "description": "Create Price Amount Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_railzwaylabs_railzway_internal_priceamount_domain.CreateRequest"
-                        }
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -921,28 +931,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/price_amounts/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get price amount by ID",
-                "consumes": [
-                    "application/json"
-                ],
+        "/invoices/{id}/charge": {
+            "post": {
+                "description": "Re-run auto-charge on an unpaid invoice of a charge_automatically subscription and wait for the provider. A partially paid invoice is charged the remaining balance. A failed charge is reported in the response and recorded on the invoice",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "price_amounts"
+                    "invoices"
                 ],
-                "summary": "Get Price Amount",
+                "summary": "Charge Invoice",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Price Amount ID",
+                        "description": "Invoice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -952,20 +954,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.DataResponse"
+                            "$ref": "#/definitions/domain.ChargeInvoiceResponse"
                         }
                     }
                 }
             }
         },
-        "/price_tiers": {
+        "/invoices/{id}/credit-notes": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List available price tiers",
+                "description": "List credit notes issued against an invoice",
                 "consumes": [
                     "application/json"
                 ],
@@ -973,21 +975,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "price_tiers"
+                    "invoices"
                 ],
-                "summary": "List Price Tiers",
+                "summary": "List Invoice Credit Notes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Page Token",
-                        "name": "page_token",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page Size",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -998,59 +995,54 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/invoices/{id}/pdf": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new price tier",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download the invoice PDF; responds 202 with the generation status while the file is not ready yet",
                 "produces": [
-                    "application/json"
+                    "application/pdf"
                 ],
                 "tags": [
-                    "price_tiers"
+                    "invoices"
                 ],
-                "summary": "Create Price Tier",
+                "summary": "Download Invoice PDF",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Idempotency Key",
-                        "name": "Idempotency-Key",
-                        "in": "header"
-                    },
-                    {
-                        "description": "Create Price Tier Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_railzwaylabs_railzway_internal_pricetier_domain.CreateRequest"
-                        }
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "$ref": "#/definitions/server.DataResponse"
                         }
                     }
                 }
-            }
-        },
-        "/price_tiers/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get price tier by ID",
+                "description": "Queue PDF generation for a finalized invoice; poll GET /invoices/{id}/pdf for the file",
                 "consumes": [
                     "application/json"
                 ],
@@ -1058,21 +1050,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "price_tiers"
+                    "invoices"
                 ],
-                "summary": "Get Price Tier",
+                "summary": "Generate Invoice PDF",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Price Tier ID",
+                        "description": "Invoice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "$ref": "#/definitions/server.DataResponse"
                         }
@@ -1080,14 +1072,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/prices": {
+        "/invoices/{id}/render": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List available prices",
+                "description": "Render invoice PDF/HTML",
                 "consumes": [
                     "application/json"
                 ],
@@ -1095,51 +1087,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "prices"
+                    "invoices"
                 ],
-                "summary": "List Prices",
+                "summary": "Render Invoice",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
-                        "name": "product_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Code",
-                        "name": "code",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Page Token",
-                        "name": "page_token",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page Size",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.ListResponse"
+                            "$ref": "#/definitions/domain.RenderInvoiceResponse"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/me": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new price",
+                "description": "Get the calling API key's organization, granted scopes and rate limit tier",
                 "consumes": [
                     "application/json"
                 ],
@@ -1147,26 +1124,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "prices"
-                ],
-                "summary": "Create Price",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Idempotency Key",
-                        "name": "Idempotency-Key",
-                        "in": "header"
-                    },
-                    {
-                        "description": "Create Price Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.createPriceRequest"
-                        }
-                    }
+                    "api_keys"
                 ],
+                "summary": "Get Calling API Key",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1177,14 +1137,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/prices/{id}": {
+        "/me/rate-limit": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get price by ID",
+                "description": "Get the rate limit tier and per-minute limit of the calling API key",
                 "consumes": [
                     "application/json"
                 ],
@@ -1192,18 +1152,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "prices"
-                ],
-                "summary": "Get Price",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Price ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "api_keys"
                 ],
+                "summary": "Get API Key Rate Limit",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1214,14 +1165,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/pricings": {
+        "/meters": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "List available pricings",
+                "description": "List meters",
                 "consumes": [
                     "application/json"
                 ],
@@ -1229,14 +1175,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pricings"
+                    "meters"
                 ],
-                "summary": "List Pricings",
+                "summary": "List Meters",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
-                        "name": "product_id",
+                        "description": "Name",
+                        "name": "name",
                         "in": "query"
                     },
                     {
@@ -1246,8 +1192,26 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Page Token",
+                        "type": "boolean",
+                        "description": "Active",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort By",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order By",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page Token",
                         "name": "page_token",
                         "in": "query"
                     },
@@ -1268,12 +1232,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Create a new pricing",
+                "description": "Create a new meter. Codes are stored lowercased and may only contain letters, digits and underscores",
                 "consumes": [
                     "application/json"
                 ],
@@ -1281,9 +1240,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pricings"
+                    "meters"
                 ],
-                "summary": "Create Pricing",
+                "summary": "Create Meter",
                 "parameters": [
                     {
                         "type": "string",
@@ -1292,12 +1251,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Create Pricing Request",
+                        "description": "Create Meter Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_railzwaylabs_railzway_internal_price_domain.CreateRequest"
+                            "$ref": "#/definitions/server.createMeterRequest"
                         }
                     }
                 ],
@@ -1311,14 +1270,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/pricings/{id}": {
+        "/meters/{id}": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get pricing by ID",
+                "description": "Get meter by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1326,13 +1280,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pricings"
+                    "meters"
                 ],
-                "summary": "Get Pricing",
+                "summary": "Get Meter",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Pricing ID",
+                        "description": "Meter ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1346,16 +1300,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/products": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "List available products",
+            },
+            "delete": {
+                "description": "Archive a meter. Meters still used by subscriptions that have not ended cannot be archived.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1363,63 +1310,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "meters"
                 ],
-                "summary": "List Products",
+                "summary": "Archive Meter",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Name",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Active",
-                        "name": "active",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort By",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Order By",
-                        "name": "order_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Page Token",
-                        "name": "page_token",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page Size",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "Meter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/server.ListResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             },
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Create a new product",
+            "patch": {
+                "description": "Update meter",
                 "consumes": [
                     "application/json"
                 ],
@@ -1427,23 +1337,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "meters"
                 ],
-                "summary": "Create Product",
+                "summary": "Update Meter",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Idempotency Key",
-                        "name": "Idempotency-Key",
-                        "in": "header"
+                        "description": "Meter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "Create Product Request",
+                        "description": "Update Meter Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.createProductRequest"
+                            "$ref": "#/definitions/server.updateMeterRequest"
                         }
                     }
                 ],
@@ -1457,14 +1368,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{id}": {
+        "/organization/billing-preferences": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get product by ID",
+                "description": "Get the organization's billing defaults: currency, payment terms, dunning schedule, rounding mode and cancellation refunds",
                 "consumes": [
                     "application/json"
                 ],
@@ -1472,18 +1383,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
-                ],
-                "summary": "Get Product",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "organization"
                 ],
+                "summary": "Get Billing Preferences",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1493,13 +1395,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update product details",
+                "description": "Update the organization's billing defaults; omitted fields keep their value. New subscriptions and invoices use the updated defaults",
                 "consumes": [
                     "application/json"
                 ],
@@ -1507,24 +1409,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "organization"
                 ],
-                "summary": "Update Product",
+                "summary": "Update Billing Preferences",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update Product Request",
+                        "description": "Update Billing Preferences Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.updateProductRequest"
+                            "$ref": "#/definitions/domain.UpdateRequest"
                         }
                     }
                 ],
@@ -1538,14 +1433,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{id}/archive": {
+        "/payments/{id}/refund": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Archive a product",
+                "description": "Refund a settled payment through its provider, in full or partially when amount is set; the ledger is updated when the provider confirms the refund",
                 "consumes": [
                     "application/json"
                 ],
@@ -1553,16 +1448,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "payments"
                 ],
-                "summary": "Archive Product",
+                "summary": "Refund Payment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
+                        "description": "Payment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Refund Payment Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefundPaymentRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1575,14 +1478,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriptions": {
+        "/price_amounts": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List available subscriptions",
+                "description": "List available price amounts",
                 "consumes": [
                     "application/json"
                 ],
@@ -1590,32 +1493,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "price_amounts"
                 ],
-                "summary": "List Subscriptions",
+                "summary": "List Price Amounts",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Status",
-                        "name": "status",
+                        "description": "Price ID",
+                        "name": "price_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Customer ID",
-                        "name": "customer_id",
+                        "description": "Currency (ISO-4217)",
+                        "name": "currency",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Created From",
-                        "name": "created_from",
+                        "description": "Effective From (RFC3339)",
+                        "name": "effective_from",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Created To",
-                        "name": "created_to",
+                        "description": "Effective To (RFC3339)",
+                        "name": "effective_to",
                         "in": "query"
                     },
                     {
@@ -1646,7 +1549,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new subscription",
+                "description": "Create a new price amount",
                 "consumes": [
                     "application/json"
                 ],
@@ -1654,9 +1557,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "price_amounts"
                 ],
-                "summary": "Create Subscription",
+                "summary": "Create Price Amount",
                 "parameters": [
                     {
                         "type": "string",
@@ -1665,12 +1568,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Create Subscription Request",
+                        "description": "Create Price Amount Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.CreateSubscriptionRequest"
+                            "$ref": "#/definitions/github_com_railzwaylabs_railzway_internal_priceamount_domain.CreateRequest"
                         }
                     }
                 ],
@@ -1684,14 +1587,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriptions/{id}": {
+        "/price_amounts/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get subscription by ID",
+                "description": "Get price amount by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1699,13 +1602,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "price_amounts"
                 ],
-                "summary": "Get Subscription",
+                "summary": "Get Price Amount",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription ID",
+                        "description": "Price Amount ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1721,14 +1624,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriptions/{id}/activate": {
-            "post": {
+        "/price_tiers": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Activate a subscription",
+                "description": "List available price tiers",
                 "consumes": [
                     "application/json"
                 ],
@@ -1736,33 +1639,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "price_tiers"
                 ],
-                "summary": "Activate Subscription",
+                "summary": "List Price Tiers",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page Token",
+                        "name": "page_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/subscriptions/{id}/cancel": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Cancel a subscription",
+                "description": "Create a new price tier",
                 "consumes": [
                     "application/json"
                 ],
@@ -1770,33 +1679,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "price_tiers"
                 ],
-                "summary": "Cancel Subscription",
+                "summary": "Create Price Tier",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Idempotency Key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Create Price Tier Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_railzwaylabs_railzway_internal_pricetier_domain.CreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
                     }
                 }
             }
         },
-        "/subscriptions/{id}/entitlements": {
+        "/price_tiers/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List entitlements for a subscription",
+                "description": "Get price tier by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1804,21 +1724,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "price_tiers"
                 ],
-                "summary": "List Subscription Entitlements",
+                "summary": "Get Price Tier",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription ID",
+                        "description": "Price Tier ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List available prices",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "List Prices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Effective At (RFC3339 or YYYY-MM-DD)",
-                        "name": "effective_at",
+                        "description": "Code",
+                        "name": "code",
                         "in": "query"
                     },
                     {
@@ -1842,16 +1798,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/subscriptions/{id}/items": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Replace items in a subscription",
+                "description": "Create a new price",
                 "consumes": [
                     "application/json"
                 ],
@@ -1859,24 +1813,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "prices"
                 ],
-                "summary": "Replace Subscription Items",
+                "summary": "Create Price",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Idempotency Key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     },
                     {
-                        "description": "Replace Subscription Items Request",
+                        "description": "Create Price Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.replaceSubscriptionItemsRequest"
+                            "$ref": "#/definitions/server.createPriceRequest"
                         }
                     }
                 ],
@@ -1890,14 +1843,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriptions/{id}/pause": {
-            "post": {
+        "/prices/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Pause a subscription",
+                "description": "Get price by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1905,33 +1858,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "prices"
                 ],
-                "summary": "Pause Subscription",
+                "summary": "Get Price",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription ID",
+                        "description": "Price ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
                     }
                 }
             }
         },
-        "/subscriptions/{id}/resume": {
+        "/prices/{id}/amounts/schedule": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Resume a subscription",
+                "description": "Schedule a future amount for a price. The currently active amount ends when the scheduled one takes effect.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1939,49 +1895,64 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "price_amounts"
                 ],
-                "summary": "Resume Subscription",
+                "summary": "Schedule Price Amount",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription ID",
+                        "description": "Price ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idempotency Key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Schedule Price Amount Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_railzwaylabs_railzway_internal_priceamount_domain.ScheduleRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
                     }
                 }
             }
         },
-        "/timezones": {
+        "/prices/{id}/tiers/export": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List timezones for a country",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download the tiers of a price as CSV, in the format accepted by the import endpoint",
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
-                    "reference"
+                    "price_tiers"
                 ],
-                "summary": "List Timezones",
+                "summary": "Export Price Tiers",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Country Code",
-                        "name": "country",
-                        "in": "query",
+                        "description": "Price ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -1989,71 +1960,91 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.ListResponse"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/usage": {
-            "get": {
+        "/prices/{id}/tiers/import": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List usage events",
+                "description": "Replace the tiers of a price with a CSV file of start_quantity, end_quantity, unit_amount_cents and flat_amount_cents. Tiers must be contiguous from 0; a malformed row rejects the whole file.",
                 "consumes": [
-                    "application/json"
+                    "text/csv",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "usage"
+                    "price_tiers"
                 ],
-                "summary": "List Usage",
+                "summary": "Import Price Tiers",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Customer ID",
-                        "name": "customer_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Subscription ID",
-                        "name": "subscription_id",
-                        "in": "query"
+                        "description": "Price ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Meter ID",
-                        "name": "meter_id",
-                        "in": "query"
+                        "type": "file",
+                        "description": "CSV file (start_quantity, end_quantity, unit_amount_cents, flat_amount_cents)",
+                        "name": "file",
+                        "in": "formData"
                     },
                     {
                         "type": "string",
-                        "description": "Meter Code",
-                        "name": "meter_code",
+                        "description": "Tier unit; defaults to the unit of the current tiers",
+                        "name": "unit",
                         "in": "query"
-                    },
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pricings": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Usage Status",
-                        "name": "status",
-                        "in": "query"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List available pricings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricings"
+                ],
+                "summary": "List Pricings",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Recorded From (RFC3339 or YYYY-MM-DD)",
-                        "name": "recorded_from",
+                        "description": "Product ID",
+                        "name": "product_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Recorded To (RFC3339 or YYYY-MM-DD)",
-                        "name": "recorded_to",
+                        "description": "Code",
+                        "name": "code",
                         "in": "query"
                     },
                     {
@@ -2084,7 +2075,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Ingest usage event",
+                "description": "Create a new pricing",
                 "consumes": [
                     "application/json"
                 ],
@@ -2092,17 +2083,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "usage"
+                    "pricings"
                 ],
-                "summary": "Ingest Usage",
+                "summary": "Create Pricing",
                 "parameters": [
                     {
-                        "description": "Ingest Usage Request",
+                        "type": "string",
+                        "description": "Idempotency Key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Create Pricing Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.CreateIngestRequest"
+                            "$ref": "#/definitions/github_com_railzwaylabs_railzway_internal_price_domain.CreateRequest"
                         }
                     }
                 ],
@@ -2116,14 +2113,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/usage/summary": {
+        "/pricings/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get aggregated usage summary for a customer",
+                "description": "Get pricing by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2131,15 +2128,1404 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "usage"
+                    "pricings"
                 ],
-                "summary": "Get Usage Summary",
+                "summary": "Get Pricing",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Customer ID",
-                        "name": "customer_id",
-                        "in": "query",
+                        "description": "Pricing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List available products",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "List Products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Active",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort By",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order By",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page Token",
+                        "name": "page_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Create Product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idempotency Key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Create Product Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.createProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get product by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get Product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update product details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Update Product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Product Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.updateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Archive a product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Archive Product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List available subscriptions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List Subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created From",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created To",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated columns to return, e.g. id,status,customer_id",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page Token",
+                        "name": "page_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Create Subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idempotency Key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Create Subscription Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CreateSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get subscription by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get Subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/activate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Activate a subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Activate Subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/billing-cycles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the billing cycles of a subscription with the rated amount of each cycle",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List Subscription Billing Cycles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cycle Status (OPEN, CLOSING, CLOSED)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page Token",
+                        "name": "page_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancel a subscription immediately, or at the end of the current billing cycle with cancel_at=period_end. Immediate cancellations credit or refund the unused upfront period when the organization opted in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Cancel Subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancel Subscription Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/server.cancelSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/discounts": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Apply a coupon, by ID or code, to a subscription. The discount applies from the current billing cycle",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Apply Subscription Discount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Apply Subscription Discount Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.applySubscriptionDiscountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/discounts/{discount_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stop a subscription discount. Billing cycles it already covered keep their discount",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Remove Subscription Discount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Discount ID",
+                        "name": "discount_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/entitlements": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List entitlements for a subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List Subscription Entitlements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Effective At (RFC3339 or YYYY-MM-DD)",
+                        "name": "effective_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page Token",
+                        "name": "page_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/entitlements/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the effective windows of a feature entitlement with the event that opened and closed each window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get Subscription Entitlement History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Feature Code",
+                        "name": "feature_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page Token",
+                        "name": "page_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the status changes of a subscription oldest first, with the reason and the actor that triggered each one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List Subscription Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page Token",
+                        "name": "page_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/items": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the items of a subscription with the unit amount each price currently bills in the subscription currency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List Subscription Items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page Token",
+                        "name": "page_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace items in a subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Replace Subscription Items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Replace Subscription Items Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.replaceSubscriptionItemsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/items/{item_id}/quantity": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change the seat count of a licensed item and prorate the change over the open billing cycle",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Update Subscription Item Quantity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subscription Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Subscription Item Quantity Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.updateSubscriptionItemQuantityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/metadata": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Merge keys into subscription metadata; a null value removes the key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Update Subscription Metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Subscription Metadata Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.updateSubscriptionMetadataRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Pause a subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Pause Subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/preview-proration": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Preview the prorated credits and charges that replacing the items or changing the plan of a subscription would add to the current billing cycle. Nothing is persisted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Preview Subscription Proration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preview Proration Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.previewProrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Resume a subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Resume Subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/seat-usage": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Compare the seats counted by each licensed item's seat meter in the current billing cycle with the licensed quantity and flag overages",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get Subscription Seat Usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/timezones": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List timezones for a country",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reference"
+                ],
+                "summary": "List Timezones",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Country Code",
+                        "name": "country",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/usage": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List usage events",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "usage"
+                ],
+                "summary": "List Usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Meter ID",
+                        "name": "meter_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Meter Code",
+                        "name": "meter_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Usage Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV Import ID",
+                        "name": "import_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recorded From (RFC3339 or YYYY-MM-DD)",
+                        "name": "recorded_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recorded To (RFC3339 or YYYY-MM-DD)",
+                        "name": "recorded_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page Token",
+                        "name": "page_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ingest usage event",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "usage"
+                ],
+                "summary": "Ingest Usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idempotency Key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Ingest Usage Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CreateIngestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.DataResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/usage/batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ingest up to 500 usage events at once. Every event is validated on its own and reported as ingested, duplicate or rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "usage"
+                ],
+                "summary": "Batch Ingest Usage",
+                "parameters": [
+                    {
+                        "description": "Batch Ingest Usage Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.batchIngestUsageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.BatchIngestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/usage/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Bulk import historical usage from a CSV file. The response is streamed as newline-delimited JSON: one result per data row followed by a summary line.",
+                "consumes": [
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "usage"
+                ],
+                "summary": "Import Usage",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file (meter_code, customer_external_id, value, recorded_at, idempotency_key)",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum age of recorded_at in days",
+                        "name": "backfill_days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ImportRowResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/usage/summary": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get aggregated usage summary for a customer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "usage"
+                ],
+                "summary": "Get Usage Summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "customer_id",
+                        "in": "query",
                         "required": true
                     },
                     {
@@ -2169,6 +3555,79 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "datatypes.JSONMap": {
+            "type": "object",
+            "additionalProperties": true
+        },
+        "domain.BatchIngestResponse": {
+            "type": "object",
+            "properties": {
+                "duplicates": {
+                    "type": "integer"
+                },
+                "ingested": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BatchIngestResult"
+                    }
+                }
+            }
+        },
+        "domain.BatchIngestResult": {
+            "type": "object",
+            "properties": {
+                "idempotency_key": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "usage_event_id": {
+                    "type": "string"
+                },
+                "warning": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ChargeInvoiceResponse": {
+            "type": "object",
+            "properties": {
+                "error_code": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "invoice": {
+                    "$ref": "#/definitions/domain.Invoice"
+                },
+                "payment_intent_id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "provider_invoice_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "domain.CreateIngestRequest": {
             "type": "object",
             "required": [
@@ -2183,63 +3642,306 @@ const docTemplate = `{
                     "type": "string",
                     "minLength": 1
                 },
-                "idempotency_key": {
-                    "description": "Required; must be non-empty. Uniqueness enforced at DB level.",
-                    "type": "string",
-                    "minLength": 1
+                "dimensions": {
+                    "description": "Dimensions tag the event for per-dimension rating; keys and values must be strings.",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "idempotency_key": {
+                    "description": "Required; must be non-empty. Uniqueness enforced at DB level.",
+                    "type": "string",
+                    "minLength": 1
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "meter_code": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is usage by default. An adjustment corrects earlier usage of a\nSUM meter, e.g. a negative value to take back over-reported usage.",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Usage can be zero or fractional; semantics resolved in rating.\nOnly adjustments may be negative.",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.CreateSubscriptionItemRequest": {
+            "type": "object",
+            "properties": {
+                "dimension_key": {
+                    "description": "DimensionKey and DimensionValue restrict a metered item to usage events\ntagged with that dimension, e.g. region=us.",
+                    "type": "string"
+                },
+                "dimension_value": {
+                    "type": "string"
+                },
+                "included_quantity": {
+                    "description": "IncludedQuantity is the allowance of a metered item rated at zero\nbefore its price applies, e.g. 1000 included API calls per cycle.",
+                    "type": "number"
+                },
+                "overage_price_id": {
+                    "type": "string"
+                },
+                "price_id": {
+                    "type": "string"
+                },
+                "proration_behavior": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "seat_meter_id": {
+                    "description": "SeatMeterID counts the active seats of a licensed item, e.g. distinct\nactive users. Seats above the item's quantity are overage, billed at\nthe per-unit OveragePriceID when one is set.",
+                    "type": "string"
+                },
+                "usage_behavior": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CreateSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "billing_cycle_type": {
+                    "type": "string"
+                },
+                "collection_mode": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CreateSubscriptionItemRequest"
+                    }
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "minimum_commitment_cents": {
+                    "type": "integer"
+                },
+                "spend_alert_threshold_cents": {
+                    "type": "integer"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "trial_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "idempotency_key": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "usage_event_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Invoice": {
+            "type": "object",
+            "properties": {
+                "amountPaid": {
+                    "type": "integer"
+                },
+                "billingCycleID": {
+                    "type": "integer"
+                },
+                "billingPhase": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customerID": {
+                    "type": "integer"
+                },
+                "dueAt": {
+                    "type": "string"
+                },
+                "finalizedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoiceNumber": {
+                    "type": "string"
+                },
+                "invoiceSeq": {
+                    "type": "integer"
+                },
+                "invoiceTemplateID": {
+                    "type": "integer"
+                },
+                "invoiceTemplateVersion": {
+                    "type": "integer"
+                },
+                "issuedAt": {
+                    "type": "string"
+                },
+                "items": {
+                    "description": "Items is populated for API responses, not persisted",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.InvoiceItem"
+                    }
+                },
+                "metadata": {
+                    "$ref": "#/definitions/datatypes.JSONMap"
+                },
+                "orgID": {
+                    "type": "integer"
+                },
+                "paidAt": {
+                    "type": "string"
+                },
+                "periodEnd": {
+                    "type": "string"
+                },
+                "periodStart": {
+                    "type": "string"
+                },
+                "renderedHTML": {
+                    "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "renderedPDFURL": {
+                    "type": "string"
                 },
-                "meter_code": {
-                    "type": "string",
-                    "minLength": 1
+                "status": {
+                    "$ref": "#/definitions/domain.InvoiceStatus"
                 },
-                "recorded_at": {
+                "subscriptionID": {
+                    "type": "integer"
+                },
+                "subtotalAmount": {
+                    "type": "integer"
+                },
+                "taxAmount": {
+                    "type": "integer"
+                },
+                "taxCode": {
                     "type": "string"
                 },
-                "value": {
-                    "description": "Usage can be zero or fractional; semantics resolved in rating.",
+                "taxRate": {
                     "type": "number"
-                }
-            }
-        },
-        "domain.CreateSubscriptionItemRequest": {
-            "type": "object",
-            "properties": {
-                "price_id": {
-                    "type": "string"
                 },
-                "quantity": {
+                "totalAmount": {
                     "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "voidedAt": {
+                    "type": "string"
                 }
             }
         },
-        "domain.CreateSubscriptionRequest": {
+        "domain.InvoiceItem": {
             "type": "object",
             "properties": {
-                "billing_cycle_type": {
-                    "type": "string"
+                "amount": {
+                    "type": "integer"
                 },
-                "collection_mode": {
+                "createdAt": {
                     "type": "string"
                 },
-                "customer_id": {
+                "description": {
                     "type": "string"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.CreateSubscriptionItemRequest"
-                    }
+                "id": {
+                    "type": "integer"
+                },
+                "invoiceID": {
+                    "type": "integer"
+                },
+                "lineType": {
+                    "$ref": "#/definitions/domain.InvoiceItemLineType"
                 },
                 "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
+                    "$ref": "#/definitions/datatypes.JSONMap"
                 },
-                "trial_days": {
+                "orgID": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "ratingResultID": {
+                    "type": "integer"
+                },
+                "unitPrice": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.InvoiceItemLineType": {
+            "type": "string",
+            "enum": [
+                "subscription",
+                "usage",
+                "credit",
+                "one_off",
+                "tax"
+            ],
+            "x-enum-varnames": [
+                "InvoiceItemLineTypeSubscription",
+                "InvoiceItemLineTypeUsage",
+                "InvoiceItemLineTypeCredit",
+                "InvoiceItemLineTypeOneOff",
+                "InvoiceItemLineTypeTax"
+            ]
+        },
+        "domain.InvoiceStatus": {
+            "type": "string",
+            "enum": [
+                "DRAFT",
+                "FINALIZED",
+                "VOID"
+            ],
+            "x-enum-varnames": [
+                "InvoiceStatusDraft",
+                "InvoiceStatusFinalized",
+                "InvoiceStatusVoid"
+            ]
+        },
+        "domain.RefundPaymentRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
                     "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
                 }
             }
         },
@@ -2356,6 +4058,33 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_railzwaylabs_railzway_internal_priceamount_domain.ScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "maximum_amount_cents": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "meter_id": {
+                    "type": "string"
+                },
+                "minimum_amount_cents": {
+                    "type": "integer"
+                },
+                "unit_amount_cents": {
+                    "type": "integer"
+                }
+            }
+        },
         "github_com_railzwaylabs_railzway_internal_pricetier_domain.CreateRequest": {
             "type": "object",
             "properties": {
@@ -2386,6 +4115,36 @@ const docTemplate = `{
                 }
             }
         },
+        "domain.UpdateRequest": {
+            "type": "object",
+            "properties": {
+                "cancellation_refund": {
+                    "description": "CancellationRefund is none, credit or refund: what customers get back\nfor the unused part of an upfront billed cycle on immediate cancel.",
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "dunning_days": {
+                    "description": "DunningDays is the reminder schedule in days past due.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "past_due_grace_days": {
+                    "description": "PastDueGraceDays is how many days a subscription stays past due after\na failed auto-charge before it is canceled.",
+                    "type": "integer"
+                },
+                "payment_terms_days": {
+                    "description": "PaymentTermsDays is how many days after finalization invoices are due.",
+                    "type": "integer"
+                },
+                "rounding_mode": {
+                    "type": "string"
+                }
+            }
+        },
         "pagination.PageInfo": {
             "type": "object",
             "properties": {
@@ -2395,6 +4154,9 @@ const docTemplate = `{
                 "next_page_token": {
                     "type": "string"
                 },
+                "page_size": {
+                    "type": "integer"
+                },
                 "previous_page_token": {
                     "type": "string"
                 }
@@ -2415,14 +4177,96 @@ const docTemplate = `{
                 }
             }
         },
+        "server.applySubscriptionDiscountRequest": {
+            "type": "object",
+            "properties": {
+                "coupon_code": {
+                    "type": "string"
+                },
+                "coupon_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.batchIngestUsageRequest": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CreateIngestRequest"
+                    }
+                }
+            }
+        },
+        "server.cancelSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "cancel_at": {
+                    "description": "Either immediate (default) or period_end.",
+                    "type": "string"
+                }
+            }
+        },
+        "server.createCouponRequest": {
+            "type": "object",
+            "properties": {
+                "amount_off_cents": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "discount_type": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "string"
+                },
+                "duration_in_cycles": {
+                    "type": "integer"
+                },
+                "max_redemptions": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percent_off": {
+                    "type": "number"
+                },
+                "redeem_by": {
+                    "type": "string"
+                }
+            }
+        },
         "server.createCustomerRequest": {
             "type": "object",
             "properties": {
+                "country": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
+                },
+                "payment_terms_days": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "tax_id": {
+                    "type": "string"
                 }
             }
         },
@@ -2471,11 +4315,30 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "reset_interval": {
+                    "description": "BILLING_CYCLE (default) or NEVER: whether included allowances on the\nmeter start over each billing cycle.",
+                    "type": "string"
+                },
                 "unit": {
                     "type": "string"
                 }
             }
         },
+        "server.createOneTimeChargeRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency defaults to the customer's billing currency.",
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.oneTimeChargeItemRequest"
+                    }
+                }
+            }
+        },
         "server.createPriceRequest": {
             "type": "object",
             "properties": {
@@ -2569,11 +4432,52 @@ const docTemplate = `{
                 "price_id": {
                     "type": "string"
                 },
+                "proration_behavior": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "usage_behavior": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.oneTimeChargeItemRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
                 "quantity": {
+                    "description": "Quantity defaults to 1.",
+                    "type": "number"
+                },
+                "unit_amount": {
                     "type": "integer"
                 }
             }
         },
+        "server.previewProrationRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.createSubscriptionItemRequest"
+                    }
+                },
+                "new_price_id": {
+                    "type": "string"
+                },
+                "new_product_id": {
+                    "type": "string"
+                },
+                "proration_behavior": {
+                    "type": "string"
+                }
+            }
+        },
         "server.replaceSubscriptionItemsRequest": {
             "type": "object",
             "properties": {
@@ -2585,6 +4489,15 @@ const docTemplate = `{
                 }
             }
         },
+        "server.updateCustomerPaymentTermsRequest": {
+            "type": "object",
+            "properties": {
+                "payment_terms_days": {
+                    "description": "PaymentTermsDays is how many days after finalization the customer's\ninvoices are due; null falls back to the organization's terms.",
+                    "type": "integer"
+                }
+            }
+        },
         "server.updateFeatureRequest": {
             "type": "object",
             "properties": {
@@ -2621,6 +4534,10 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "reset_interval": {
+                    "description": "BILLING_CYCLE (default) or NEVER: whether included allowances on the\nmeter start over each billing cycle.",
+                    "type": "string"
+                },
                 "unit": {
                     "type": "string"
                 }
@@ -2643,6 +4560,31 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "server.updateSubscriptionMetadataRequest": {
+            "type": "object",
+            "properties": {
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "server.updateSubscriptionItemQuantityRequest": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "server.updateCustomerCurrencyRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
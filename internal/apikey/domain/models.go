@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
@@ -21,7 +22,27 @@ type APIKey struct {
 	LastUsedAt       *time.Time     `gorm:"column:last_used_at"`
 	ExpiresAt        *time.Time     `gorm:"column:expires_at"`
 	RotatedFromKeyID *string        `gorm:"column:rotated_from_key_id;type:text"`
+	RateLimitTier    string         `gorm:"column:rate_limit_tier;type:text;not null;default:free"`
 }
 
 // TableName sets the database table name.
 func (APIKey) TableName() string { return "api_keys" }
+
+// Rate limit tiers control how many requests per minute a key may make.
+const (
+	RateLimitTierFree = "free"
+	RateLimitTierPro  = "pro"
+)
+
+// NormalizeRateLimitTier defaults an empty tier to free and rejects unknown
+// tiers.
+func NormalizeRateLimitTier(tier string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(tier)) {
+	case "", RateLimitTierFree:
+		return RateLimitTierFree, nil
+	case RateLimitTierPro:
+		return RateLimitTierPro, nil
+	default:
+		return "", ErrInvalidRateLimitTier
+	}
+}
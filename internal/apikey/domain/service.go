@@ -14,8 +14,9 @@ type Service interface {
 }
 
 type CreateRequest struct {
-	Name   string   `json:"name"`
-	Scopes []string `json:"scopes"`
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	RateLimitTier string   `json:"rate_limit_tier,omitempty"`
 }
 
 type Response struct {
@@ -27,6 +28,7 @@ type Response struct {
 	LastUsedAt       *time.Time `json:"last_used_at"`
 	ExpiresAt        *time.Time `json:"expires_at"`
 	RotatedFromKeyID *string    `json:"rotated_from_key_id"`
+	RateLimitTier    string     `json:"rate_limit_tier"`
 }
 
 type SecretResponse struct {
@@ -35,8 +37,9 @@ type SecretResponse struct {
 }
 
 var (
-	ErrInvalidOrganization = errors.New("invalid_organization")
-	ErrInvalidName         = errors.New("invalid_name")
-	ErrInvalidKeyID        = errors.New("invalid_key_id")
-	ErrNotFound            = errors.New("not_found")
+	ErrInvalidOrganization  = errors.New("invalid_organization")
+	ErrInvalidName          = errors.New("invalid_name")
+	ErrInvalidKeyID         = errors.New("invalid_key_id")
+	ErrNotFound             = errors.New("not_found")
+	ErrInvalidRateLimitTier = errors.New("invalid_rate_limit_tier")
 )
@@ -84,6 +84,11 @@ func (s *Service) Create(ctx context.Context, req apikeydomain.CreateRequest) (*
 		return nil, err
 	}
 
+	tier, err := apikeydomain.NormalizeRateLimitTier(req.RateLimitTier)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now().UTC()
 	id := s.genID.Generate()
 	keyID := newKeyID(id)
@@ -93,15 +98,16 @@ func (s *Service) Create(ctx context.Context, req apikeydomain.CreateRequest) (*
 	}
 
 	key := &apikeydomain.APIKey{
-		ID:        id,
-		OrgID:     orgID,
-		KeyID:     keyID,
-		Name:      name,
-		Scopes:    scopes,
-		KeyHash:   hash,
-		IsActive:  true,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:            id,
+		OrgID:         orgID,
+		KeyID:         keyID,
+		Name:          name,
+		Scopes:        scopes,
+		KeyHash:       hash,
+		IsActive:      true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		RateLimitTier: tier,
 	}
 
 	if err := s.repo.Insert(ctx, s.db, key); err != nil {
@@ -164,6 +170,7 @@ func (s *Service) Rotate(ctx context.Context, keyID string) (*apikeydomain.Secre
 			CreatedAt:        now,
 			UpdatedAt:        now,
 			RotatedFromKeyID: &rotatedFrom,
+			RateLimitTier:    current.RateLimitTier,
 		}
 
 		if err := s.repo.Insert(ctx, tx, next); err != nil {
@@ -221,6 +228,7 @@ func (s *Service) toResponse(key *apikeydomain.APIKey) apikeydomain.Response {
 		LastUsedAt:       key.LastUsedAt,
 		ExpiresAt:        key.ExpiresAt,
 		RotatedFromKeyID: key.RotatedFromKeyID,
+		RateLimitTier:    key.RateLimitTier,
 	}
 }
 
@@ -19,6 +19,7 @@ const (
 	ScopeSubscriptionResume   Scope = "subscription:resume"
 	ScopeSubscriptionCancel   Scope = "subscription:cancel"
 	ScopeSubscriptionEnd      Scope = "subscription:end"
+	ScopeSubscriptionUpdate   Scope = "subscription:update"
 
 	ScopeBillingCycleOpen         Scope = "billing_cycle:open"
 	ScopeBillingCycleStartClosing Scope = "billing_cycle:start_closing"
@@ -29,6 +30,8 @@ const (
 	ScopeInvoiceGenerate Scope = "invoice:generate"
 	ScopeInvoiceFinalize Scope = "invoice:finalize"
 	ScopeInvoiceVoid     Scope = "invoice:void"
+	ScopeInvoiceCharge   Scope = "invoice:charge"
+	ScopeInvoiceUpdate   Scope = "invoice:update"
 
 	ScopeAPIKeyView   Scope = "api_key:view"
 	ScopeAPIKeyCreate Scope = "api_key:create"
@@ -39,6 +42,14 @@ const (
 
 	ScopeUsageIngest Scope = "usage:ingest"
 	ScopeUsageWrite  Scope = "usage:write"
+	ScopeUsageView   Scope = "usage:view"
+
+	ScopeCheckoutCreate Scope = "checkout:create"
+
+	ScopeTestClockManage Scope = "test_clock:manage"
+
+	ScopeCouponView   Scope = "coupon:view"
+	ScopeCouponCreate Scope = "coupon:create"
 
 	// New CRUD Scopes
 	ScopeProductView   Scope = "product:view"
@@ -90,6 +101,7 @@ var authzScopeMap = map[authzKey]Scope{
 	{normalize(authorization.ObjectSubscription), normalize(authorization.ActionSubscriptionResume)}:   ScopeSubscriptionResume,
 	{normalize(authorization.ObjectSubscription), normalize(authorization.ActionSubscriptionCancel)}:   ScopeSubscriptionCancel,
 	{normalize(authorization.ObjectSubscription), normalize(authorization.ActionSubscriptionEnd)}:      ScopeSubscriptionEnd,
+	{normalize(authorization.ObjectSubscription), normalize(authorization.ActionSubscriptionUpdate)}:   ScopeSubscriptionUpdate,
 
 	{normalize(authorization.ObjectBillingCycle), normalize(authorization.ActionBillingCycleOpen)}:         ScopeBillingCycleOpen,
 	{normalize(authorization.ObjectBillingCycle), normalize(authorization.ActionBillingCycleStartClosing)}: ScopeBillingCycleStartClosing,
@@ -100,6 +112,8 @@ var authzScopeMap = map[authzKey]Scope{
 	{normalize(authorization.ObjectInvoice), normalize(authorization.ActionInvoiceGenerate)}: ScopeInvoiceGenerate,
 	{normalize(authorization.ObjectInvoice), normalize(authorization.ActionInvoiceFinalize)}: ScopeInvoiceFinalize,
 	{normalize(authorization.ObjectInvoice), normalize(authorization.ActionInvoiceVoid)}:     ScopeInvoiceVoid,
+	{normalize(authorization.ObjectInvoice), normalize(authorization.ActionInvoiceCharge)}:   ScopeInvoiceCharge,
+	{normalize(authorization.ObjectInvoice), normalize(authorization.ActionInvoiceUpdate)}:   ScopeInvoiceUpdate,
 
 	{normalize(authorization.ObjectAPIKey), normalize(authorization.ActionAPIKeyView)}:   ScopeAPIKeyView,
 	{normalize(authorization.ObjectAPIKey), normalize(authorization.ActionAPIKeyCreate)}: ScopeAPIKeyCreate,
@@ -108,6 +122,16 @@ var authzScopeMap = map[authzKey]Scope{
 
 	{normalize(authorization.ObjectAuditLog), normalize(authorization.ActionAuditLogView)}: ScopeAuditLogView,
 
+	{normalize(authorization.ObjectUsage), normalize(authorization.ActionUsageIngest)}: ScopeUsageIngest,
+	{normalize(authorization.ObjectUsage), normalize(authorization.ActionUsageView)}:   ScopeUsageView,
+
+	{normalize(authorization.ObjectCheckout), normalize(authorization.ActionCheckoutCreate)}: ScopeCheckoutCreate,
+
+	{normalize(authorization.ObjectTestClock), normalize(authorization.ActionTestClockManage)}: ScopeTestClockManage,
+
+	{normalize(authorization.ObjectCoupon), normalize(authorization.ActionCouponView)}:   ScopeCouponView,
+	{normalize(authorization.ObjectCoupon), normalize(authorization.ActionCouponCreate)}: ScopeCouponCreate,
+
 	// New Mappings
 	{normalize(authorization.ObjectProduct), normalize(authorization.ActionProductView)}:   ScopeProductView,
 	{normalize(authorization.ObjectProduct), normalize(authorization.ActionProductCreate)}: ScopeProductCreate,
@@ -153,6 +177,7 @@ var allScopes = []Scope{
 	ScopeSubscriptionResume,
 	ScopeSubscriptionCancel,
 	ScopeSubscriptionEnd,
+	ScopeSubscriptionUpdate,
 	ScopeBillingCycleOpen,
 	ScopeBillingCycleStartClosing,
 	ScopeBillingCycleClose,
@@ -161,6 +186,8 @@ var allScopes = []Scope{
 	ScopeInvoiceGenerate,
 	ScopeInvoiceFinalize,
 	ScopeInvoiceVoid,
+	ScopeInvoiceCharge,
+	ScopeInvoiceUpdate,
 	ScopeAPIKeyView,
 	ScopeAPIKeyCreate,
 	ScopeAPIKeyRotate,
@@ -168,6 +195,11 @@ var allScopes = []Scope{
 	ScopeAuditLogView,
 	ScopeUsageIngest,
 	ScopeUsageWrite,
+	ScopeUsageView,
+	ScopeCheckoutCreate,
+	ScopeTestClockManage,
+	ScopeCouponView,
+	ScopeCouponCreate,
 	ScopeProductView,
 	ScopeProductCreate,
 	ScopeProductUpdate,
@@ -213,6 +245,11 @@ func All() []string {
 	return values
 }
 
+// impliedScopes lists scopes granted by a broader one besides its own.
+var impliedScopes = map[Scope][]Scope{
+	ScopeUsageWrite: {ScopeUsageIngest},
+}
+
 func FromAuthz(object string, action string) Scope {
 	key := authzKey{object: normalize(object), action: normalize(action)}
 	if scope, ok := authzScopeMap[key]; ok {
@@ -243,6 +280,11 @@ func Has(scopes []string, required Scope) bool {
 		if requiredObject != "" && (normalized == requiredObject+":*" || normalized == requiredObject+".*") {
 			return true
 		}
+		for _, implied := range impliedScopes[Scope(normalized)] {
+			if normalize(string(implied)) == requiredScope {
+				return true
+			}
+		}
 	}
 	return false
 }
@@ -0,0 +1,53 @@
+package scope
+
+import (
+	"testing"
+
+	"github.com/railzwaylabs/railzway/internal/authorization"
+)
+
+func TestFromAuthzCoversAPIRoutes(t *testing.T) {
+	cases := []struct {
+		object string
+		action string
+		want   Scope
+	}{
+		{authorization.ObjectUsage, authorization.ActionUsageIngest, ScopeUsageIngest},
+		{authorization.ObjectUsage, authorization.ActionUsageView, ScopeUsageView},
+		{authorization.ObjectSubscription, authorization.ActionSubscriptionUpdate, ScopeSubscriptionUpdate},
+		{authorization.ObjectInvoice, authorization.ActionInvoiceUpdate, ScopeInvoiceUpdate},
+		{authorization.ObjectCheckout, authorization.ActionCheckoutCreate, ScopeCheckoutCreate},
+		{authorization.ObjectTestClock, authorization.ActionTestClockManage, ScopeTestClockManage},
+		{authorization.ObjectCoupon, authorization.ActionCouponView, ScopeCouponView},
+		{authorization.ObjectCoupon, authorization.ActionCouponCreate, ScopeCouponCreate},
+	}
+	for _, tc := range cases {
+		if got := FromAuthz(tc.object, tc.action); got != tc.want {
+			t.Fatalf("FromAuthz(%q, %q) = %q, want %q", tc.object, tc.action, got, tc.want)
+		}
+	}
+}
+
+func TestHas(t *testing.T) {
+	cases := []struct {
+		name     string
+		scopes   []string
+		required Scope
+		want     bool
+	}{
+		{name: "exact", scopes: []string{"subscription:view"}, required: ScopeSubscriptionView, want: true},
+		{name: "object_wildcard", scopes: []string{"subscription:*"}, required: ScopeSubscriptionCancel, want: true},
+		{name: "global_wildcard", scopes: []string{"*"}, required: ScopeInvoiceVoid, want: true},
+		{name: "implied", scopes: []string{"usage:write"}, required: ScopeUsageIngest, want: true},
+		{name: "other_object", scopes: []string{"usage:write"}, required: ScopeSubscriptionView, want: false},
+		{name: "missing", scopes: []string{"subscription:view"}, required: ScopeSubscriptionCreate, want: false},
+		{name: "unmapped", scopes: []string{"*"}, required: "", want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Has(tc.scopes, tc.required); got != tc.want {
+				t.Fatalf("Has(%v, %q) = %v, want %v", tc.scopes, tc.required, got, tc.want)
+			}
+		})
+	}
+}
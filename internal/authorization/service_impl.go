@@ -37,6 +37,9 @@ const (
 	ObjectPaymentProvider   = "payment_provider"
 	ObjectUsage             = "usage"
 	ObjectOrganization      = "organization"
+	ObjectCheckout          = "checkout"
+	ObjectTestClock         = "test_clock"
+	ObjectCoupon            = "coupon"
 )
 
 const (
@@ -54,6 +57,7 @@ const (
 	ActionInvoiceGenerate = "invoice.generate"
 	ActionInvoiceFinalize = "invoice.finalize"
 	ActionInvoiceVoid     = "invoice.void"
+	ActionInvoiceCharge   = "invoice.charge"
 
 	ActionBillingDashboardView  = "billing_dashboard.view"
 	ActionBillingOperationsView = "billing_operations.view"
@@ -125,6 +129,13 @@ const (
 
 	ActionOrganizationView   = "organization.view"
 	ActionOrganizationUpdate = "organization.update"
+
+	ActionCheckoutCreate = "checkout.create"
+
+	ActionTestClockManage = "test_clock.manage"
+
+	ActionCouponView   = "coupon.view"
+	ActionCouponCreate = "coupon.create"
 )
 
 type Params struct {
@@ -376,6 +387,7 @@ func seedPolicies(enforcer *casbin.SyncedEnforcer) error {
 		{"role:admin", ObjectSubscription, ActionSubscriptionPause},
 		{"role:admin", ObjectSubscription, ActionSubscriptionResume},
 		{"role:admin", ObjectInvoice, ActionInvoiceFinalize},
+		{"role:admin", ObjectInvoice, ActionInvoiceCharge},
 		{"role:admin", ObjectBillingDashboard, ActionBillingDashboardView},
 		{"role:admin", ObjectBillingOperations, ActionBillingOperationsView},
 		{"role:admin", ObjectBillingOperations, ActionBillingOperationsAct},
@@ -394,6 +406,7 @@ func seedPolicies(enforcer *casbin.SyncedEnforcer) error {
 		{"role:owner", ObjectSubscription, ActionSubscriptionCancel},
 		{"role:owner", ObjectInvoice, ActionInvoiceFinalize},
 		{"role:owner", ObjectInvoice, ActionInvoiceVoid},
+		{"role:owner", ObjectInvoice, ActionInvoiceCharge},
 		{"role:owner", ObjectBillingDashboard, ActionBillingDashboardView},
 		{"role:owner", ObjectBillingOperations, ActionBillingOperationsView},
 		{"role:owner", ObjectBillingOperations, ActionBillingOperationsAct},
@@ -428,6 +441,8 @@ func seedPolicies(enforcer *casbin.SyncedEnforcer) error {
 		{"role:billing_approver", ObjectBillingOperations, ActionBillingOperationsView},
 
 		// System permissions (for automated processes and API keys)
+		{"role:system", ObjectSubscription, ActionSubscriptionActivate},
+		{"role:system", ObjectSubscription, ActionSubscriptionCancel},
 		{"role:system", ObjectSubscription, ActionSubscriptionEnd},
 		{"role:system", ObjectBillingCycle, ActionBillingCycleOpen},
 		{"role:system", ObjectBillingCycle, ActionBillingCycleStartClosing},
@@ -485,6 +500,10 @@ func seedPolicies(enforcer *casbin.SyncedEnforcer) error {
 		{"role:system", ObjectUsage, ActionUsageIngest},
 		{"role:system", ObjectUsage, ActionUsageView},
 		{"role:system", ObjectOrganization, ActionOrganizationView},
+		{"role:system", ObjectCheckout, ActionCheckoutCreate},
+		{"role:system", ObjectTestClock, ActionTestClockManage},
+		{"role:system", ObjectCoupon, ActionCouponView},
+		{"role:system", ObjectCoupon, ActionCouponCreate},
 	}
 
 	for _, policy := range policies {
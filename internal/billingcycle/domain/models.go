@@ -16,6 +16,22 @@ const (
 	BillingCycleStatusClosed  BillingCycleStatus = "CLOSED"
 )
 
+// BillingPhase identifies when charges for a cycle are billed.
+type BillingPhase string
+
+const (
+	// BillingPhaseAdvance bills charges when the cycle opens.
+	BillingPhaseAdvance BillingPhase = "advance"
+	// BillingPhaseArrears bills charges after the cycle closes.
+	BillingPhaseArrears BillingPhase = "arrears"
+	// BillingPhaseThreshold bills metered usage mid-cycle once an item
+	// crosses its billing threshold.
+	BillingPhaseThreshold BillingPhase = "threshold"
+	// BillingPhaseOneOff bills a one-time charge outside any cycle; its
+	// invoice has no billing cycle or subscription.
+	BillingPhaseOneOff BillingPhase = "one_off"
+)
+
 // BillingCycle represents a billing period for a subscription.
 type BillingCycle struct {
 	ID                 snowflake.ID       `gorm:"primaryKey"`
@@ -23,11 +39,12 @@ type BillingCycle struct {
 	TestClockID        *snowflake.ID      `gorm:"index"`
 	SubscriptionID     snowflake.ID       `gorm:"not null;index;uniqueIndex:ux_billing_cycle_period,priority:1"`
 	PeriodStart        time.Time          `gorm:"not null;uniqueIndex:ux_billing_cycle_period,priority:2"`
-	PeriodEnd          time.Time          `gorm:"not null;uniqueIndex:ux_billing_cycle_period,priority:3"`
+	PeriodEnd          time.Time          `gorm:"not null"`
 	Status             BillingCycleStatus `gorm:"type:text;not null;default:'OPEN'"`
 	OpenedAt           *time.Time         `gorm:"column:opened_at"`
 	ClosingStartedAt   *time.Time         `gorm:"column:closing_started_at"`
 	RatingCompletedAt  *time.Time         `gorm:"column:rating_completed_at"`
+	AdvanceInvoicedAt  *time.Time         `gorm:"column:advance_invoiced_at"`
 	InvoicedAt         *time.Time         `gorm:"column:invoiced_at"`
 	InvoiceFinalizedAt *time.Time         `gorm:"column:invoice_finalized_at"`
 	ClosedAt           *time.Time         `gorm:"column:closed_at"`
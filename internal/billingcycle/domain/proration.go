@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// ProrationFactor returns the share of a billing cycle covered by the window
+// [start, end), clamped to [0, 1].
+func ProrationFactor(start, end time.Time, cycleDurationSeconds float64) float64 {
+	if cycleDurationSeconds <= 0 {
+		return 0
+	}
+	activeSeconds := end.Sub(start).Seconds()
+	factor := activeSeconds / cycleDurationSeconds
+	if factor > 1.0 {
+		return 1.0
+	}
+	if factor < 0.0 {
+		return 0.0
+	}
+	return factor
+}
@@ -88,9 +88,10 @@ func (s *Service) listActiveSubscriptions(ctx context.Context) ([]activeSubscrip
 	err := s.db.WithContext(ctx).Raw(
 		`SELECT id, org_id, status, activated_at, billing_cycle_type
 		 FROM subscriptions
-		 WHERE status = ?
+		 WHERE status IN (?, ?)
 		 ORDER BY id`,
 		subscriptiondomain.SubscriptionStatusActive,
+		subscriptiondomain.SubscriptionStatusPastDue,
 	).Scan(&subscriptions).Error
 	if err != nil {
 		return nil, err
@@ -104,7 +105,7 @@ func (s *Service) ensureSubscriptionCycles(ctx context.Context, subscription act
 		if err != nil {
 			return err
 		}
-		if locked == nil || locked.Status != subscriptiondomain.SubscriptionStatusActive {
+		if locked == nil || !locked.Status.Billable() {
 			return nil
 		}
 		if locked.ActivatedAt == nil {
@@ -290,7 +291,8 @@ func (s *Service) insertCycle(ctx context.Context, tx *gorm.DB, orgID, subscript
 	return tx.WithContext(ctx).Exec(
 		`INSERT INTO billing_cycles (
 			id, org_id, subscription_id, period_start, period_end, status, opened_at, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`,
 		s.genID.Generate(),
 		orgID,
 		subscriptionID,
@@ -305,6 +307,8 @@ func (s *Service) insertCycle(ctx context.Context, tx *gorm.DB, orgID, subscript
 
 func nextPeriodEnd(start time.Time, cycleType string) (time.Time, error) {
 	switch strings.ToLower(strings.TrimSpace(cycleType)) {
+	case "yearly":
+		return start.AddDate(1, 0, 0), nil
 	case "monthly":
 		return start.AddDate(0, 1, 0), nil
 	case "weekly":
@@ -3,6 +3,8 @@ package domain
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 )
 
 // Constants
@@ -12,6 +14,9 @@ const (
 	PeriodTypeMonthly = "monthly"
 
 	ScoringVersionV1EqualWeight = "v1_equal_weight"
+	// ScoringVersionV2Weighted totals the dimensions with the org's
+	// configured scoring weights.
+	ScoringVersionV2Weighted = "v2_weighted"
 )
 
 type GetPerformanceRequest struct {
@@ -28,12 +33,26 @@ type PerformanceResponse struct {
 	Snapshots      []APISnapshot `json:"snapshots"`
 }
 
+// PerformanceHistoryRequest pages through a user's persisted snapshots,
+// newest period first. An empty PeriodType returns every period type.
+type PerformanceHistoryRequest struct {
+	PeriodType string `json:"period_type" form:"period_type"`
+	PageToken  string `json:"page_token" form:"page_token"`
+	PageSize   int    `json:"page_size" form:"page_size"`
+}
+
+type PerformanceHistoryResponse struct {
+	pagination.PageInfo
+	Snapshots []FinOpsScoreSnapshot `json:"snapshots"`
+}
+
 type APISnapshot struct {
-	PeriodStart time.Time         `json:"period_start"`
-	PeriodEnd   time.Time         `json:"period_end"`
-	Metrics     APIMetrics        `json:"metrics"`
-	Scores      PerformanceScores `json:"scores"`
-	TotalScore  int               `json:"total_score"`
+	ScoringVersion string            `json:"scoring_version"`
+	PeriodStart    time.Time         `json:"period_start"`
+	PeriodEnd      time.Time         `json:"period_end"`
+	Metrics        APIMetrics        `json:"metrics"`
+	Scores         PerformanceScores `json:"scores"`
+	TotalScore     int               `json:"total_score"`
 }
 
 type APIMetrics struct {
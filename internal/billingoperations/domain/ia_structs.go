@@ -19,6 +19,10 @@ type InboxItem struct {
 	DaysOverdue  int        `json:"days_overdue,omitempty"`
 	LastAttempt  *time.Time `json:"last_attempt,omitempty"`
 	PublicToken  string     `json:"public_token,omitempty"`
+
+	// Delivery status of the latest follow-up email Railzway sent, e.g.
+	// "sent", "delivered", "bounced" or "complained".
+	LastEmailStatus string `json:"last_email_status,omitempty"`
 }
 
 type InboxResponse struct {
@@ -55,6 +59,8 @@ type MyWorkItem struct {
 	Status        string     `json:"status"`         // "claimed" | "in_progress"
 	LastActionAt  *time.Time `json:"last_action_at,omitempty"`
 	PublicToken   string     `json:"public_token,omitempty"`
+
+	LastEmailStatus string `json:"last_email_status,omitempty"`
 }
 
 type MyWorkResponse struct {
@@ -147,7 +153,7 @@ type PaymentDetail struct {
 	Method      string    `json:"method,omitempty"` // "card", "bank_transfer"
 	CardBrand   string    `json:"card_brand,omitempty"`   // "visa", "mastercard"
 	CardLast4   string    `json:"card_last4,omitempty"`   // "4242"
-	Status      string    `json:"status"`           // "succeeded", "failed"
+	Status      string    `json:"status"`           // "succeeded", "failed", "refunded"
 }
 
 type InvoicePaymentsResponse struct {
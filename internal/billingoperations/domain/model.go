@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	"gorm.io/datatypes"
 )
 
@@ -22,6 +23,27 @@ type BillingActionRecord struct {
 	CreatedAt      time.Time
 }
 
+// EmailLogRecord is an email sent through the configured provider, with the
+// latest delivery status reported for it.
+type EmailLogRecord struct {
+	ID                snowflake.ID
+	OrgID             snowflake.ID
+	Provider          string
+	ProviderMessageID string
+	Recipient         string
+	Template          sql.NullString
+	BillingActionID   *snowflake.ID
+	Status            string
+	StatusReason      sql.NullString
+	StatusUpdatedAt   time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (EmailLogRecord) TableName() string {
+	return "email_logs"
+}
+
 type BillingAssignmentRecord struct {
 	ID                  snowflake.ID
 	OrgID               snowflake.ID
@@ -48,6 +70,24 @@ func (BillingAssignmentRecord) TableName() string {
 	return "billing_operation_assignments"
 }
 
+// ActiveAssignmentRow is an open, unbreached assignment with the SLA window,
+// in minutes, configured for its organization.
+type ActiveAssignmentRow struct {
+	BillingAssignmentRecord
+	SLAMinutes int `gorm:"column:sla_minutes"`
+}
+
+// BillingActionRow is a billing action recorded on an entity.
+type BillingActionRow struct {
+	ID          snowflake.ID      `gorm:"column:id"`
+	ActionType  string            `gorm:"column:action_type"`
+	ActorType   sql.NullString    `gorm:"column:actor_type"`
+	ActorID     sql.NullString    `gorm:"column:actor_id"`
+	Metadata    datatypes.JSONMap `gorm:"column:metadata"`
+	EmailStatus sql.NullString    `gorm:"column:email_status"`
+	CreatedAt   time.Time         `gorm:"column:created_at"`
+}
+
 type BillingActionLookup struct {
 	ID snowflake.ID `gorm:"column:id"`
 }
@@ -58,6 +98,7 @@ type OverdueInvoiceRow struct {
 	CustomerID          snowflake.ID   `gorm:"column:customer_id"`
 	CustomerName        string         `gorm:"column:customer_name"`
 	AmountDue           int64          `gorm:"column:amount_due"`
+	Currency            string         `gorm:"column:currency"`
 	DueAt               time.Time      `gorm:"column:due_at"`
 	AssignedTo          sql.NullString `gorm:"column:assigned_to"`
 	AssignedAt          sql.NullTime   `gorm:"column:assigned_at"`
@@ -72,10 +113,29 @@ type OverdueInvoiceRow struct {
 	TokenHash           sql.NullString `gorm:"column:token_hash"`
 }
 
+// DunningCandidateRow is an overdue invoice with no active assignment, joined
+// with what a reminder email needs.
+type DunningCandidateRow struct {
+	InvoiceID     snowflake.ID   `gorm:"column:invoice_id"`
+	OrgID         snowflake.ID   `gorm:"column:org_id"`
+	InvoiceNumber string         `gorm:"column:invoice_number"`
+	CustomerID    snowflake.ID   `gorm:"column:customer_id"`
+	CustomerEmail string         `gorm:"column:customer_email"`
+	TotalAmount   int64          `gorm:"column:total_amount"`
+	Currency      string         `gorm:"column:currency"`
+	DueAt         time.Time      `gorm:"column:due_at"`
+	OrgName       string         `gorm:"column:org_name"`
+	SupportEmail  sql.NullString `gorm:"column:support_email"`
+	DunningDays   datatypes.JSON `gorm:"column:dunning_days"`
+	TokenHash     sql.NullString `gorm:"column:token_hash"`
+}
+
 type OutstandingCustomerRow struct {
 	CustomerID                 snowflake.ID   `gorm:"column:customer_id"`
 	CustomerName               string         `gorm:"column:customer_name"`
 	Outstanding                int64          `gorm:"column:outstanding"`
+	ConvertedOutstanding       int64          `gorm:"column:converted_outstanding"`
+	UnconvertedInvoices        int64          `gorm:"column:unconverted_invoices"`
 	OldestOverdueInvoiceID     sql.NullString `gorm:"column:oldest_overdue_invoice_id"`
 	OldestOverdueInvoiceNumber sql.NullString `gorm:"column:oldest_overdue_invoice_number"`
 	OldestOverdueAt            sql.NullTime   `gorm:"column:oldest_overdue_at"`
@@ -190,10 +250,14 @@ type PerformanceScores struct {
 	Effectiveness  int `json:"effectiveness"` // Exposure Handled score
 	Risk           int `json:"risk"`          // Low Escalation score
 	Total          int `json:"total"`
+	// Weights are the org's weights the total was scored with under
+	// ScoringVersionV2Weighted; equal-weight scores carry none.
+	Weights *organizationdomain.ScoringWeights `json:"weights,omitempty"`
 }
 
 
 type FinOpsScoreSnapshot struct {
+	ID             string             `json:"id,omitempty"`
 	OrgID          string             `json:"org_id"`
 	UserID         string             `json:"user_id"`
 	PeriodType     string             `json:"period_type"`
@@ -205,6 +269,7 @@ type FinOpsScoreSnapshot struct {
 }
 
 type FinOpsSnapshotRow struct {
+	ID             snowflake.ID   `gorm:"column:id"`
 	OrgID          snowflake.ID   `gorm:"column:org_id"`
 	UserID         string         `gorm:"column:user_id"`
 	PeriodType     string         `gorm:"column:period_type"`
@@ -226,6 +291,8 @@ type InboxRow struct {
 	LastAttempt  sql.NullTime   `gorm:"column:last_attempt"`
 	TokenHash    sql.NullString `gorm:"column:token_hash"`
 	RiskScore    int            `gorm:"column:risk_score"`
+
+	LastEmailStatus sql.NullString `gorm:"column:last_email_status"`
 }
 
 type MyWorkRow struct {
@@ -243,6 +310,7 @@ type MyWorkRow struct {
 	CurrentAmountDue   sql.NullInt64   `gorm:"column:current_amount_due"`
 	CurrentDaysOverdue sql.NullFloat64 `gorm:"column:current_days_overdue"`
 	TokenHash          sql.NullString  `gorm:"column:token_hash"`
+	LastEmailStatus    sql.NullString  `gorm:"column:last_email_status"`
 }
 
 type ResolvedRow struct {
@@ -265,12 +333,18 @@ type TeamRow struct {
 	EscalationCount         int    `gorm:"column:escalation_count"`
 }
 
+// PaymentRow is a payment event recorded against an invoice. Payment
+// events only store the raw provider payload, so ProviderPaymentID, Amount
+// and Currency are read from it and Status is derived from EventType.
 type PaymentRow struct {
-	ProviderPaymentID string         `gorm:"column:provider_payment_id"`
+	ProviderEventID   string         `gorm:"column:provider_event_id"`
+	ProviderPaymentID string         `gorm:"-"`
 	Provider          string         `gorm:"column:provider"`
 	EventType         string         `gorm:"column:event_type"`
 	ReceivedAt        time.Time      `gorm:"column:received_at"`
-	Currency          string         `gorm:"column:currency"`
+	Amount            int64          `gorm:"-"`
+	Currency          string         `gorm:"-"`
+	Status            string         `gorm:"-"`
 	Payload           datatypes.JSON `gorm:"column:payload"`
 }
 
@@ -5,26 +5,37 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 	"gorm.io/gorm"
 )
 
 type Repository interface {
 	WithTx(tx *gorm.DB) Repository
 	FetchOrgCurrency(ctx context.Context, orgID snowflake.ID) (string, error)
+	FetchScoringWeights(ctx context.Context, orgID snowflake.ID) (*organizationdomain.ScoringWeights, error)
 	LoadEntitySnapshot(ctx context.Context, orgID snowflake.ID, entityType string, entityID snowflake.ID) (map[string]any, error)
-	ListOverdueInvoices(ctx context.Context, orgID snowflake.ID, currency string, now time.Time, limit int) ([]OverdueInvoiceRow, error)
-	ListOutstandingCustomers(ctx context.Context, orgID snowflake.ID, currency string, now time.Time, limit int) ([]OutstandingCustomerRow, error)
+	FindFXRate(ctx context.Context, orgID snowflake.ID, from, to string, asOf time.Time) (float64, bool, error)
+	ListOverdueInvoices(ctx context.Context, orgID snowflake.ID, now time.Time, limit int, filter AssignmentFilter) ([]OverdueInvoiceRow, error)
+	ListOutstandingCustomers(ctx context.Context, orgID snowflake.ID, baseCurrency string, now time.Time, limit int) ([]OutstandingCustomerRow, error)
 	ListPaymentIssues(ctx context.Context, orgID snowflake.ID, now time.Time, limit int) ([]PaymentIssueRow, error)
 	LoadActionSummary(ctx context.Context, orgID snowflake.ID, currency string, now time.Time) (ActionSummaryRow, error)
-	ListCollectionQueue(ctx context.Context, orgID snowflake.ID, currency string, now time.Time, limit int) ([]CollectionQueueRow, error)
+	ListCollectionQueue(ctx context.Context, orgID snowflake.ID, currency string, now time.Time, limit int, filter AssignmentFilter) ([]CollectionQueueRow, error)
 	ListFailedPaymentActions(ctx context.Context, orgID snowflake.ID, currency string, now time.Time, limit int) ([]FailedPaymentActionRow, error)
 	LoadAssignment(ctx context.Context, orgID snowflake.ID, entityType string, entityID snowflake.ID) (*AssignmentRow, error)
 	LoadAssignmentForUpdate(ctx context.Context, orgID snowflake.ID, entityType string, entityID snowflake.ID) (*BillingAssignmentRecord, error)
-	ListActiveAssignments(ctx context.Context) ([]BillingAssignmentRecord, error)
+	ListActiveAssignments(ctx context.Context) ([]ActiveAssignmentRow, error)
+	ListDunningCandidates(ctx context.Context, now time.Time, limit int) ([]DunningCandidateRow, error)
 
 	InsertBillingAction(ctx context.Context, record BillingActionRecord) (bool, error)
 	FindActionByIdempotencyKey(ctx context.Context, orgID snowflake.ID, key string) (*BillingActionLookup, error)
 	FindActionByBucket(ctx context.Context, orgID snowflake.ID, entityType string, entityID snowflake.ID, actionType string, bucket time.Time) (*BillingActionLookup, error)
+	ListEntityActions(ctx context.Context, orgID snowflake.ID, entityType string, entityID snowflake.ID) ([]BillingActionRow, error)
+
+	InsertEmailLog(ctx context.Context, record EmailLogRecord) error
+	FindEmailLogByMessageIDForUpdate(ctx context.Context, messageID string) (*EmailLogRecord, error)
+	UpdateEmailLogStatus(ctx context.Context, id snowflake.ID, status, reason string, at time.Time) error
+	UpdateActionEmailStatus(ctx context.Context, actionID snowflake.ID, status string, at time.Time) error
 
 	UpsertAssignment(ctx context.Context, record BillingAssignmentRecord) error
 	UpdateAssignmentStatus(ctx context.Context, orgID snowflake.ID, entityType string, entityID snowflake.ID, oldStatus, newStatus string, now time.Time) error
@@ -44,4 +55,5 @@ type Repository interface {
 	FindSnapshotsByUser(ctx context.Context, orgID snowflake.ID, userID string, periodType string, start, end time.Time) ([]FinOpsScoreSnapshot, error)
 	FindSnapshotsByUserWithLimit(ctx context.Context, orgID snowflake.ID, userID string, periodType string, start, end time.Time, limit int) ([]FinOpsScoreSnapshot, error)
 	FindSnapshotsByOrg(ctx context.Context, orgID snowflake.ID, periodType string, start, end time.Time) ([]FinOpsScoreSnapshot, error)
+	ListSnapshotHistory(ctx context.Context, orgID snowflake.ID, userID string, periodType string, page pagination.Pagination) ([]FinOpsScoreSnapshot, error)
 }
@@ -7,16 +7,28 @@ import (
 )
 
 type OverdueInvoice struct {
-	InvoiceID     string      `json:"invoice_id"`
-	InvoiceNumber string      `json:"invoice_number"`
-	CustomerID    string      `json:"customer_id"`
-	CustomerName  string      `json:"customer_name"`
-	AmountDue     int64       `json:"amount_due"`
-	Currency      string      `json:"currency"`
-	DueAt         time.Time   `json:"due_at"`
-	DaysOverdue   int         `json:"days_overdue"`
-	PublicToken   string      `json:"public_token,omitempty"`
-	Assignment    *Assignment `json:"assignment,omitempty"`
+	InvoiceID       string      `json:"invoice_id"`
+	InvoiceNumber   string      `json:"invoice_number"`
+	CustomerID      string      `json:"customer_id"`
+	CustomerName    string      `json:"customer_name"`
+	AmountDue       int64       `json:"amount_due"`
+	Currency        string      `json:"currency"`
+	ConvertedAmount int64       `json:"converted_amount"`
+	MissingFXRate   bool        `json:"missing_fx_rate,omitempty"`
+	DueAt           time.Time   `json:"due_at"`
+	DaysOverdue     int         `json:"days_overdue"`
+	PublicToken     string      `json:"public_token,omitempty"`
+	Assignment      *Assignment `json:"assignment,omitempty"`
+}
+
+// AssignmentFilter narrows overdue invoices and the collection queue by
+// whether an item has a live assignment. The zero value matches every item.
+type AssignmentFilter struct {
+	// Assigned keeps only assigned items when true and only unassigned items
+	// when false. Nil matches both.
+	Assigned *bool
+	// AssignedTo keeps only items assigned to this user.
+	AssignedTo string
 }
 
 type OverdueInvoicesResponse struct {
@@ -30,6 +42,8 @@ type OutstandingCustomer struct {
 	CustomerName           string      `json:"customer_name"`
 	OutstandingBalance     int64       `json:"outstanding_balance"`
 	Currency               string      `json:"currency"`
+	ConvertedAmount        int64       `json:"converted_amount"`
+	MissingFXRate          bool        `json:"missing_fx_rate,omitempty"`
 	OldestOverdueInvoiceID string      `json:"oldest_overdue_invoice_id,omitempty"`
 	OldestOverdueInvoice   string      `json:"oldest_overdue_invoice,omitempty"`
 	OldestOverdueAt        *time.Time  `json:"oldest_overdue_at,omitempty"`
@@ -146,6 +160,23 @@ type ReleaseAssignmentRequest struct {
 	ReleasedBy string `json:"released_by"`
 }
 
+// MaxBulkAssignments caps how many entities one bulk claim or release may touch.
+const MaxBulkAssignments = 100
+
+// BulkAssignmentResult reports what a bulk claim or release did to one
+// entity. Error carries the reason when Status is conflict or invalid.
+type BulkAssignmentResult struct {
+	EntityType string      `json:"entity_type"`
+	EntityID   string      `json:"entity_id"`
+	Status     string      `json:"status"`
+	Assignment *Assignment `json:"assignment,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+type BulkAssignmentResponse struct {
+	Results []BulkAssignmentResult `json:"results"`
+}
+
 type ResolveAssignmentRequest struct {
 	EntityType string `json:"entity_type"`
 	EntityID   string `json:"entity_id"`
@@ -170,9 +201,50 @@ type Assignment struct {
 	TimeSinceAssigned   string     `json:"time_since_assigned"`
 }
 
+// AssignmentDetailResponse is an entity's current assignment, nil when it
+// was never claimed, with the billing actions recorded on it, oldest first.
+type AssignmentDetailResponse struct {
+	Assignment *Assignment     `json:"assignment"`
+	Actions    []BillingAction `json:"actions"`
+}
+
+type BillingAction struct {
+	ActionID   string         `json:"action_id"`
+	ActionType string         `json:"action_type"`
+	ActorType  string         `json:"actor_type,omitempty"`
+	ActorID    string         `json:"actor_id,omitempty"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+
+	// Delivery status of the follow-up email sent with this action, if any.
+	EmailStatus string `json:"email_status,omitempty"`
+}
+
 type RecordFollowUpRequest struct {
 	AssignmentID  string `json:"assignment_id"`
 	EmailProvider string `json:"email_provider"` // "gmail", "outlook", "default"
+
+	// Internal callers such as dunning follow up on invoices nobody claimed:
+	// InvoiceID is used when AssignmentID is empty, and IdempotencyKey
+	// dedupes the action instead of the daily bucket.
+	InvoiceID      string         `json:"-"`
+	IdempotencyKey string         `json:"-"`
+	Metadata       map[string]any `json:"-"`
+
+	// Set when the email was sent by Railzway rather than the collector's
+	// own client, so its delivery can be tracked against the action.
+	EmailMessageID string `json:"-"`
+	EmailRecipient string `json:"-"`
+	EmailTemplate  string `json:"-"`
+}
+
+// EmailDeliveryUpdate is a delivery, bounce or complaint reported by an email
+// provider for a message sent with MessageID.
+type EmailDeliveryUpdate struct {
+	MessageID  string
+	Status     string
+	Reason     string
+	OccurredAt time.Time
 }
 
 const (
@@ -189,6 +261,14 @@ const (
 	ActionTypeResolve      = "resolve"
 )
 
+const (
+	BulkStatusClaimed  = "claimed"
+	BulkStatusReleased = "released"
+	BulkStatusSkipped  = "skipped"
+	BulkStatusConflict = "conflict"
+	BulkStatusInvalid  = "invalid"
+)
+
 const (
 	ActionStatusRecorded  = "recorded"
 	ActionStatusDuplicate = "duplicate"
@@ -221,18 +301,23 @@ const (
 
 
 type Service interface {
-	ListOverdueInvoices(ctx context.Context, limit int) (OverdueInvoicesResponse, error)
+	ListOverdueInvoices(ctx context.Context, limit int, filter AssignmentFilter) (OverdueInvoicesResponse, error)
 	ListOutstandingCustomers(ctx context.Context, limit int) (OutstandingCustomersResponse, error)
 	ListPaymentIssues(ctx context.Context, limit int) (PaymentIssuesResponse, error)
-	GetOperations(ctx context.Context, limit int) (BillingOperationsResponse, error)
+	GetOperations(ctx context.Context, limit int, filter AssignmentFilter) (BillingOperationsResponse, error)
 	RecordAction(ctx context.Context, req RecordActionRequest) (RecordActionResponse, error)
 	ClaimAssignment(ctx context.Context, req ClaimAssignmentRequest) (AssignmentResponse, error)
 	ReleaseAssignment(ctx context.Context, req ReleaseAssignmentRequest) error
+	BulkClaimAssignments(ctx context.Context, reqs []ClaimAssignmentRequest) (BulkAssignmentResponse, error)
+	BulkReleaseAssignments(ctx context.Context, reqs []ReleaseAssignmentRequest) (BulkAssignmentResponse, error)
 	ResolveAssignment(ctx context.Context, req ResolveAssignmentRequest) error
+	GetAssignmentDetail(ctx context.Context, entityType, entityID string) (AssignmentDetailResponse, error)
 	EvaluateSLAs(ctx context.Context) error
 	CalculatePerformance(ctx context.Context, userID string, start, end time.Time) (FinOpsScoreSnapshot, error)
-	GetPerformanceHistory(ctx context.Context, userID string, limit int) ([]FinOpsScoreSnapshot, error)
+	GetPerformanceHistory(ctx context.Context, userID string, req PerformanceHistoryRequest) (PerformanceHistoryResponse, error)
 	AggregateDailyPerformance(ctx context.Context) error
+	AggregateWeeklyPerformance(ctx context.Context) error
+	AggregateMonthlyPerformance(ctx context.Context) error
 
 	// API Methods (Read-Only from Snapshots)
 	GetMyPerformance(ctx context.Context, userID string, req GetPerformanceRequest) (*PerformanceResponse, error)
@@ -248,6 +333,12 @@ type Service interface {
 	// Follow-Up Email (opens user's email client)
 	RecordFollowUp(ctx context.Context, req RecordFollowUpRequest) error
 
+	// Dunning: reminder emails for overdue invoices nobody is working on
+	SendDunningReminders(ctx context.Context, limit int) (int, error)
+
+	// Email delivery tracking for follow-ups sent by Railzway
+	UpdateEmailDeliveryStatus(ctx context.Context, update EmailDeliveryUpdate) error
+
 	// Invoice Payment Details
 	GetInvoicePayments(ctx context.Context, invoiceID string) (InvoicePaymentsResponse, error)
 }
@@ -261,4 +352,9 @@ var (
 	ErrInvalidIdempotencyKey = errors.New("invalid_idempotency_key")
 	ErrInvalidAssignmentTTL  = errors.New("invalid_assignment_ttl")
 	ErrAssignmentConflict    = errors.New("assignment_conflict")
+	ErrAssignmentNotFound    = errors.New("assignment_not_found")
+	ErrInvalidPeriodType     = errors.New("invalid_period_type")
+	ErrInvalidBulkSize       = errors.New("invalid_bulk_size")
+	ErrEmailLogNotFound      = errors.New("email_log_not_found")
+	ErrInvalidEmailStatus    = errors.New("invalid_email_status")
 )
@@ -7,6 +7,8 @@ import (
 	"github.com/bwmarrin/snowflake"
 	"github.com/railzwaylabs/railzway/internal/billingoperations/domain"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -80,6 +82,43 @@ func (r *FinOpsSnapshotRepository) FindByOrg(ctx context.Context, orgID snowflak
 	return mapRowsToSnapshots(rows), nil
 }
 
+// ListHistory pages through a user's snapshots, newest period first. The page
+// token's cursor carries the period start of the last snapshot returned; one
+// extra row is fetched so the caller can tell whether more remain.
+func (r *FinOpsSnapshotRepository) ListHistory(ctx context.Context, orgID snowflake.ID, userID string, periodType string, page pagination.Pagination) ([]domain.FinOpsScoreSnapshot, error) {
+	if ctxOrgID, ok := orgcontext.OrgIDFromContext(ctx); ok && ctxOrgID != orgID {
+		return nil, domain.ErrInvalidOrganization
+	}
+
+	query := r.db.WithContext(ctx).Table("finops_performance_snapshots").
+		Where("org_id = ? AND user_id = ?", orgID, userID)
+	if periodType != "" {
+		query = query.Where("period_type = ?", periodType)
+	}
+	if page.PageToken != "" {
+		cursor, err := pagination.DecodeCursor(page.PageToken)
+		if err == nil {
+			periodStart, perr := time.Parse(time.RFC3339Nano, cursor.CreatedAt)
+			cursorID, ierr := snowflake.ParseString(cursor.ID)
+			if perr == nil && ierr == nil {
+				query = query.Where("(period_start < ? OR (period_start = ? AND id < ?))", periodStart, periodStart, cursorID)
+			}
+		} else {
+			zap.L().Warn("Failed to decode cursor", zap.String("cursor", page.PageToken), zap.Error(err))
+		}
+	}
+	if page.PageSize > 0 {
+		query = query.Limit(page.PageSize + 1)
+	}
+
+	var rows []domain.FinOpsSnapshotRow
+	if err := query.Order("period_start DESC, id DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return mapRowsToSnapshots(rows), nil
+}
+
 func mapRowsToSnapshots(rows []domain.FinOpsSnapshotRow) []domain.FinOpsScoreSnapshot {
 	snapshots := make([]domain.FinOpsScoreSnapshot, len(rows))
 	for i, r := range rows {
@@ -107,6 +146,9 @@ func mapRowsToSnapshots(rows []domain.FinOpsSnapshotRow) []domain.FinOpsScoreSna
 			Metrics:        m,
 			Scores:         s,
 		}
+		if r.ID != 0 {
+			snapshots[i].ID = r.ID.String()
+		}
 	}
 	return snapshots
 }
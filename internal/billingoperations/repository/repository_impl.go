@@ -2,14 +2,16 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"strings"
 
 	"github.com/bwmarrin/snowflake"
 	billingopsdomain "github.com/railzwaylabs/railzway/internal/billingoperations/domain"
-	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -51,35 +53,72 @@ func (r *RepositoryImpl) FetchOrgCurrency(ctx context.Context, orgID snowflake.I
 	return currency, nil
 }
 
+// FetchScoringWeights returns the org's FinOps scoring weights, or nil when it
+// scores with equal weights.
+func (r *RepositoryImpl) FetchScoringWeights(ctx context.Context, orgID snowflake.ID) (*organizationdomain.ScoringWeights, error) {
+	var row struct {
+		ScoringWeights datatypes.JSON `gorm:"column:scoring_weights"`
+	}
+	if err := r.db.WithContext(ctx).Raw(
+		`SELECT scoring_weights FROM organization_billing_preferences WHERE org_id = ? LIMIT 1`,
+		orgID,
+	).Scan(&row).Error; err != nil {
+		return nil, err
+	}
+	if len(row.ScoringWeights) == 0 {
+		return nil, nil
+	}
+	var weights organizationdomain.ScoringWeights
+	if err := json.Unmarshal(row.ScoringWeights, &weights); err != nil {
+		return nil, err
+	}
+	if !weights.Valid() {
+		return nil, nil
+	}
+	return &weights, nil
+}
+
+// FindFXRate returns the latest rate converting from into to that is in
+// effect at asOf.
+func (r *RepositoryImpl) FindFXRate(ctx context.Context, orgID snowflake.ID, from, to string, asOf time.Time) (float64, bool, error) {
+	var rows []struct {
+		Rate float64 `gorm:"column:rate"`
+	}
+	if err := r.db.WithContext(ctx).Raw(
+		`SELECT rate FROM fx_rates
+		 WHERE org_id = ? AND from_currency = ? AND to_currency = ? AND effective_at <= ?
+		 ORDER BY effective_at DESC
+		 LIMIT 1`,
+		orgID,
+		from,
+		to,
+		asOf,
+	).Scan(&rows).Error; err != nil {
+		return 0, false, err
+	}
+	if len(rows) == 0 {
+		return 0, false, nil
+	}
+	return rows[0].Rate, true, nil
+}
+
 func (r *RepositoryImpl) ListOverdueInvoices(
 	ctx context.Context,
 	orgID snowflake.ID,
-	currency string,
 	now time.Time,
 	limit int,
+	filter billingopsdomain.AssignmentFilter,
 ) ([]billingopsdomain.OverdueInvoiceRow, error) {
 	var rows []billingopsdomain.OverdueInvoiceRow
+	assignmentClause, assignmentArgs := assignmentFilterClause(filter)
 	query := `
-		WITH settled AS (
-			SELECT
-				(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-				SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-			FROM ledger_entries le
-			JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-			JOIN ledger_accounts a ON a.id = l.account_id
-			JOIN payment_events pe ON pe.id = le.source_id
-			WHERE le.org_id = ?
-			  AND le.currency = ?
-			  AND le.source_type = ?
-			  AND a.code = ?
-			GROUP BY 1
-		)
 		SELECT
 			i.id AS invoice_id,
 			COALESCE(i.invoice_number::text, '') AS invoice_number,
 			c.id AS customer_id,
 			c.name AS customer_name,
-			GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS amount_due,
+			GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS amount_due,
+			i.currency AS currency,
 			i.due_at AS due_at,
 			boa.assigned_to AS assigned_to,
 			boa.assigned_at AS assigned_at,
@@ -92,7 +131,6 @@ func (r *RepositoryImpl) ListOverdueInvoices(
 			ipt.token_hash AS token_hash
 		FROM invoices i
 		JOIN customers c ON c.id = i.customer_id
-		LEFT JOIN settled s ON s.invoice_id_text = i.id::text
 		LEFT JOIN invoice_public_tokens ipt ON ipt.invoice_id = i.id AND ipt.revoked_at IS NULL
 		LEFT JOIN billing_operation_assignments boa
 			ON boa.org_id = ?
@@ -103,26 +141,17 @@ func (r *RepositoryImpl) ListOverdueInvoices(
 		  AND i.status = 'FINALIZED'
 		  AND i.voided_at IS NULL
 		  AND i.paid_at IS NULL
-		  AND i.currency = ?
 		  AND i.due_at IS NOT NULL
 		  AND i.due_at < ?
-		  AND GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) > 0
+		  AND GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) > 0` +
+		assignmentClause + `
 		ORDER BY i.due_at ASC
 		LIMIT ?`
 
-	if err := r.db.WithContext(ctx).Raw(
-		query,
-		orgID,
-		currency,
-		string(ledgerdomain.SourceTypePayment),
-		string(ledgerdomain.AccountCodeAccountsReceivable),
-		orgID,
-		billingopsdomain.EntityTypeInvoice,
-		orgID,
-		currency,
-		now,
-		limit,
-	).Scan(&rows).Error; err != nil {
+	args := []any{orgID, billingopsdomain.EntityTypeInvoice, orgID, now}
+	args = append(args, assignmentArgs...)
+	args = append(args, limit)
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
 		return nil, err
 	}
 	return rows, nil
@@ -131,42 +160,53 @@ func (r *RepositoryImpl) ListOverdueInvoices(
 func (r *RepositoryImpl) ListOutstandingCustomers(
 	ctx context.Context,
 	orgID snowflake.ID,
-	currency string,
+	baseCurrency string,
 	now time.Time,
 	limit int,
 ) ([]billingopsdomain.OutstandingCustomerRow, error) {
 	var rows []billingopsdomain.OutstandingCustomerRow
 	query := `
-		WITH settled AS (
-			SELECT
-				(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-				SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-			FROM ledger_entries le
-			JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-			JOIN ledger_accounts a ON a.id = l.account_id
-			JOIN payment_events pe ON pe.id = le.source_id
-			WHERE le.org_id = ?
-			  AND le.currency = ?
-			  AND le.source_type = ?
-			  AND a.code = ?
-			GROUP BY 1
-		), invoice_outstanding AS (
+		WITH invoice_outstanding AS (
 			SELECT
 				i.id AS invoice_id,
 				i.customer_id,
 				COALESCE(i.invoice_number::text, '') AS invoice_number,
+				i.currency,
 				i.due_at,
-				GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS outstanding
+				GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS outstanding
 			FROM invoices i
-			LEFT JOIN settled s ON s.invoice_id_text = i.id::text
 			WHERE i.org_id = ?
 			  AND i.status = 'FINALIZED'
 			  AND i.voided_at IS NULL
-			  AND i.currency = ?
+		), invoice_converted AS (
+			-- Same lookup as FindFXRate: the latest rate in effect at the due date.
+			SELECT
+				io.customer_id,
+				io.currency,
+				io.outstanding,
+				CASE
+					WHEN io.currency = ? THEN io.outstanding
+					ELSE ROUND(io.outstanding * fx.rate)::bigint
+				END AS converted
+			FROM invoice_outstanding io
+			LEFT JOIN LATERAL (
+				SELECT rate
+				FROM fx_rates
+				WHERE org_id = ?
+				  AND from_currency = io.currency
+				  AND to_currency = ?
+				  AND effective_at <= COALESCE(io.due_at, ?)
+				ORDER BY effective_at DESC
+				LIMIT 1
+			) fx ON TRUE
+			WHERE io.outstanding > 0
 		), totals AS (
-			SELECT customer_id, SUM(outstanding) AS outstanding
-			FROM invoice_outstanding
-			WHERE outstanding > 0
+			SELECT
+				customer_id,
+				COALESCE(SUM(outstanding) FILTER (WHERE currency = ?), 0) AS outstanding,
+				COALESCE(SUM(converted), 0) AS converted_outstanding,
+				COUNT(*) FILTER (WHERE converted IS NULL) AS unconverted_invoices
+			FROM invoice_converted
 			GROUP BY customer_id
 		), oldest_overdue AS (
 			SELECT DISTINCT ON (customer_id)
@@ -187,6 +227,8 @@ func (r *RepositoryImpl) ListOutstandingCustomers(
 			c.id AS customer_id,
 			c.name AS customer_name,
 			t.outstanding AS outstanding,
+			t.converted_outstanding AS converted_outstanding,
+			t.unconverted_invoices AS unconverted_invoices,
 			oo.invoice_id::text AS oldest_overdue_invoice_id,
 			oo.invoice_number AS oldest_overdue_invoice_number,
 			oo.due_at AS oldest_overdue_at,
@@ -211,17 +253,17 @@ func (r *RepositoryImpl) ListOutstandingCustomers(
 			AND boa.entity_id = c.id
 			AND boa.status != 'released'
 		WHERE c.org_id = ?
-		ORDER BY t.outstanding DESC
+		ORDER BY t.converted_outstanding DESC
 		LIMIT ?`
 
 	if err := r.db.WithContext(ctx).Raw(
 		query,
 		orgID,
-		currency,
-		string(ledgerdomain.SourceTypePayment),
-		string(ledgerdomain.AccountCodeAccountsReceivable),
+		baseCurrency,
 		orgID,
-		currency,
+		baseCurrency,
+		now,
+		baseCurrency,
 		now,
 		orgID,
 		orgID,
@@ -279,27 +321,13 @@ func (r *RepositoryImpl) ListPaymentIssues(ctx context.Context, orgID snowflake.
 func (r *RepositoryImpl) LoadActionSummary(ctx context.Context, orgID snowflake.ID, currency string, now time.Time) (billingopsdomain.ActionSummaryRow, error) {
 	var row billingopsdomain.ActionSummaryRow
 	query := `
-		WITH settled AS (
-			SELECT
-				(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-				SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-			FROM ledger_entries le
-			JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-			JOIN ledger_accounts a ON a.id = l.account_id
-			JOIN payment_events pe ON pe.id = le.source_id
-			WHERE le.org_id = ?
-			  AND le.currency = ?
-			  AND le.source_type = ?
-			  AND a.code = ?
-			GROUP BY 1
-		), invoice_outstanding AS (
+		WITH invoice_outstanding AS (
 			SELECT
 				i.id AS invoice_id,
 				i.customer_id,
 				i.due_at,
-				GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS outstanding
+				GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS outstanding
 			FROM invoices i
-			LEFT JOIN settled s ON s.invoice_id_text = i.id::text
 			WHERE i.org_id = ?
 			  AND i.status = 'FINALIZED'
 			  AND i.voided_at IS NULL
@@ -320,10 +348,6 @@ func (r *RepositoryImpl) LoadActionSummary(ctx context.Context, orgID snowflake.
 		query,
 		orgID,
 		currency,
-		string(ledgerdomain.SourceTypePayment),
-		string(ledgerdomain.AccountCodeAccountsReceivable),
-		orgID,
-		currency,
 		now,
 		orgID,
 		paymentdomain.EventTypePaymentFailed,
@@ -339,32 +363,20 @@ func (r *RepositoryImpl) ListCollectionQueue(
 	currency string,
 	now time.Time,
 	limit int,
+	filter billingopsdomain.AssignmentFilter,
 ) ([]billingopsdomain.CollectionQueueRow, error) {
 	var rows []billingopsdomain.CollectionQueueRow
+	assignmentClause, assignmentArgs := assignmentFilterClause(filter)
 	query := `
-		WITH settled AS (
-			SELECT
-				(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-				SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-			FROM ledger_entries le
-			JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-			JOIN ledger_accounts a ON a.id = l.account_id
-			JOIN payment_events pe ON pe.id = le.source_id
-			WHERE le.org_id = ?
-			  AND le.currency = ?
-			  AND le.source_type = ?
-			  AND a.code = ?
-			GROUP BY 1
-		), invoice_outstanding AS (
+		WITH invoice_outstanding AS (
 			SELECT
 				i.id AS invoice_id,
 				i.customer_id,
 				COALESCE(i.invoice_number::text, '') AS invoice_number,
 				i.due_at,
 				COALESCE(i.issued_at, i.created_at) AS issued_at,
-				GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS outstanding
+				GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS outstanding
 			FROM invoices i
-			LEFT JOIN settled s ON s.invoice_id_text = i.id::text
 			WHERE i.org_id = ?
 			  AND i.status = 'FINALIZED'
 			  AND i.voided_at IS NULL
@@ -417,7 +429,8 @@ func (r *RepositoryImpl) ListCollectionQueue(
 			AND boa.entity_type = ?
 			AND boa.entity_id = c.id
 			AND boa.status != 'released'
-		WHERE c.org_id = ?
+		WHERE c.org_id = ?` +
+		assignmentClause + `
 		ORDER BY
 			CASE
 				WHEN ou.due_at IS NULL THEN 1
@@ -429,27 +442,34 @@ func (r *RepositoryImpl) ListCollectionQueue(
 			c.id ASC
 		LIMIT ?`
 
-	if err := r.db.WithContext(ctx).Raw(
-		query,
-		orgID,
-		currency,
-		string(ledgerdomain.SourceTypePayment),
-		string(ledgerdomain.AccountCodeAccountsReceivable),
-		orgID,
-		currency,
-		orgID,
-		orgID,
-		billingopsdomain.EntityTypeCustomer,
-		orgID,
-		now,
-		now,
-		limit,
-	).Scan(&rows).Error; err != nil {
+	args := []any{orgID, currency, orgID, orgID, billingopsdomain.EntityTypeCustomer, orgID}
+	args = append(args, assignmentArgs...)
+	args = append(args, now, now, limit)
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
 		return nil, err
 	}
 	return rows, nil
 }
 
+// assignmentFilterClause returns the conditions applying filter to rows
+// joined to their live assignment as boa, with their arguments.
+func assignmentFilterClause(filter billingopsdomain.AssignmentFilter) (string, []any) {
+	var clause strings.Builder
+	var args []any
+	if filter.Assigned != nil {
+		if *filter.Assigned {
+			clause.WriteString(" AND boa.entity_id IS NOT NULL")
+		} else {
+			clause.WriteString(" AND boa.entity_id IS NULL")
+		}
+	}
+	if assignedTo := strings.TrimSpace(filter.AssignedTo); assignedTo != "" {
+		clause.WriteString(" AND boa.assigned_to = ?")
+		args = append(args, assignedTo)
+	}
+	return clause.String(), args
+}
+
 func (r *RepositoryImpl) ListFailedPaymentActions(
 	ctx context.Context,
 	orgID snowflake.ID,
@@ -459,20 +479,7 @@ func (r *RepositoryImpl) ListFailedPaymentActions(
 ) ([]billingopsdomain.FailedPaymentActionRow, error) {
 	var rows []billingopsdomain.FailedPaymentActionRow
 	query := `
-		WITH settled AS (
-			SELECT
-				(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-				SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-			FROM ledger_entries le
-			JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-			JOIN ledger_accounts a ON a.id = l.account_id
-			JOIN payment_events pe ON pe.id = le.source_id
-			WHERE le.org_id = ?
-			  AND le.currency = ?
-			  AND le.source_type = ?
-			  AND a.code = ?
-			GROUP BY 1
-		), failed AS (
+		WITH failed AS (
 			SELECT
 				pe.customer_id AS customer_id,
 				c.name AS customer_name,
@@ -489,7 +496,7 @@ func (r *RepositoryImpl) ListFailedPaymentActions(
 			f.customer_name AS customer_name,
 			f.invoice_id_text AS invoice_id,
 			COALESCE(i.invoice_number::text, '') AS invoice_number,
-			GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS amount_due,
+			GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS amount_due,
 			i.due_at AS due_at,
 			f.last_attempt AS last_attempt,
 			boa.assigned_to AS assigned_to,
@@ -508,7 +515,6 @@ func (r *RepositoryImpl) ListFailedPaymentActions(
 			AND i.status = 'FINALIZED'
 			AND i.voided_at IS NULL
 			AND i.currency = ?
-		LEFT JOIN settled s ON s.invoice_id_text = i.id::text
 		LEFT JOIN invoice_public_tokens ipt ON ipt.invoice_id = i.id AND ipt.revoked_at IS NULL
 		LEFT JOIN billing_operation_assignments boa
 			ON boa.org_id = ?
@@ -516,17 +522,13 @@ func (r *RepositoryImpl) ListFailedPaymentActions(
 
 			AND boa.entity_id = f.customer_id
 			AND boa.status != 'released'
-		WHERE (i.id IS NULL OR GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) > 0)
+		WHERE (i.id IS NULL OR GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) > 0)
 		ORDER BY f.last_attempt DESC
 		LIMIT ?`
 
 	if err := r.db.WithContext(ctx).Raw(
 		query,
 		orgID,
-		currency,
-		string(ledgerdomain.SourceTypePayment),
-		string(ledgerdomain.AccountCodeAccountsReceivable),
-		orgID,
 		paymentdomain.EventTypePaymentFailed,
 		orgID,
 		currency,
@@ -620,11 +622,100 @@ func (r *RepositoryImpl) FindActionByBucket(ctx context.Context, orgID snowflake
 	return &row, nil
 }
 
+// ListEntityActions returns every billing action recorded on an entity,
+// oldest first.
+func (r *RepositoryImpl) ListEntityActions(ctx context.Context, orgID snowflake.ID, entityType string, entityID snowflake.ID) ([]billingopsdomain.BillingActionRow, error) {
+	var rows []billingopsdomain.BillingActionRow
+	if err := r.db.WithContext(ctx).Raw(
+		`SELECT id, action_type, actor_type, actor_id, metadata, email_status, created_at
+		 FROM billing_operation_actions
+		 WHERE org_id = ? AND entity_type = ? AND entity_id = ?
+		 ORDER BY created_at ASC, id ASC`,
+		orgID,
+		entityType,
+		entityID,
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *RepositoryImpl) InsertEmailLog(ctx context.Context, record billingopsdomain.EmailLogRecord) error {
+	if record.ID == 0 {
+		return billingopsdomain.ErrInvalidEntityID
+	}
+	return r.db.WithContext(ctx).Exec(
+		`INSERT INTO email_logs (
+			id, org_id, provider, provider_message_id, recipient, template,
+			billing_action_id, status, status_reason, status_updated_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.ID,
+		record.OrgID,
+		record.Provider,
+		record.ProviderMessageID,
+		record.Recipient,
+		record.Template,
+		record.BillingActionID,
+		record.Status,
+		record.StatusReason,
+		record.StatusUpdatedAt,
+		record.CreatedAt,
+		record.UpdatedAt,
+	).Error
+}
+
+func (r *RepositoryImpl) FindEmailLogByMessageIDForUpdate(ctx context.Context, messageID string) (*billingopsdomain.EmailLogRecord, error) {
+	var row billingopsdomain.EmailLogRecord
+	query := `SELECT id, org_id, provider, provider_message_id, recipient, template,
+		        billing_action_id, status, status_reason, status_updated_at, created_at, updated_at
+		 FROM email_logs
+		 WHERE provider_message_id = ?`
+
+	if r.db.Dialector.Name() != "sqlite" {
+		query += " FOR UPDATE"
+	}
+
+	if err := r.db.WithContext(ctx).Raw(query, messageID).Scan(&row).Error; err != nil {
+		return nil, err
+	}
+	if row.ID == 0 {
+		return nil, nil
+	}
+	return &row, nil
+}
+
+func (r *RepositoryImpl) UpdateEmailLogStatus(ctx context.Context, id snowflake.ID, status, reason string, at time.Time) error {
+	var reasonValue any
+	if reason != "" {
+		reasonValue = reason
+	}
+	return r.db.WithContext(ctx).Exec(
+		`UPDATE email_logs
+		 SET status = ?, status_reason = ?, status_updated_at = ?, updated_at = ?
+		 WHERE id = ?`,
+		status,
+		reasonValue,
+		at,
+		at,
+		id,
+	).Error
+}
+
+func (r *RepositoryImpl) UpdateActionEmailStatus(ctx context.Context, actionID snowflake.ID, status string, at time.Time) error {
+	return r.db.WithContext(ctx).Exec(
+		`UPDATE billing_operation_actions SET email_status = ?, email_status_at = ? WHERE id = ?`,
+		status,
+		at,
+		actionID,
+	).Error
+}
+
 func (r *RepositoryImpl) LoadAssignment(ctx context.Context, orgID snowflake.ID, entityType string, entityID snowflake.ID) (*billingopsdomain.AssignmentRow, error) {
 	var row billingopsdomain.AssignmentRow
 	if err := r.db.WithContext(ctx).Raw(
 		`SELECT assigned_to, assigned_at, assignment_expires_at,
-		        status, released_at, released_by, release_reason, last_action_at
+		        status, released_at, released_by, release_reason,
+		        breached_at, breach_level, last_action_at
 		 FROM billing_operation_assignments
 		 WHERE org_id = ? AND entity_type = ? AND entity_id = ?
 		 LIMIT 1`,
@@ -669,16 +760,81 @@ func (r *RepositoryImpl) LoadAssignmentForUpdate(
 	return &row, nil
 }
 
-func (r *RepositoryImpl) ListActiveAssignments(ctx context.Context) ([]billingopsdomain.BillingAssignmentRecord, error) {
-	var records []billingopsdomain.BillingAssignmentRecord
-	if err := r.db.WithContext(ctx).Where("status IN ? AND breached_at IS NULL",
-		[]string{billingopsdomain.AssignmentStatusAssigned, billingopsdomain.AssignmentStatusInProgress}).
-		Find(&records).Error; err != nil {
+// ListActiveAssignments returns the open assignments that have not breached
+// their SLA yet, with each organization's SLA window.
+func (r *RepositoryImpl) ListActiveAssignments(ctx context.Context) ([]billingopsdomain.ActiveAssignmentRow, error) {
+	var records []billingopsdomain.ActiveAssignmentRow
+	if err := r.db.WithContext(ctx).Raw(
+		`SELECT boa.id, boa.org_id, boa.entity_type, boa.entity_id,
+		        boa.assigned_to, boa.assigned_at, boa.assignment_expires_at,
+		        boa.status, boa.released_at, boa.released_by, boa.release_reason,
+		        boa.breached_at, boa.breach_level, boa.last_action_at,
+		        boa.snapshot_metadata, boa.created_at, boa.updated_at,
+		        COALESCE(obp.assignment_sla_minutes, ?) AS sla_minutes
+		 FROM billing_operation_assignments boa
+		 LEFT JOIN organization_billing_preferences obp ON obp.org_id = boa.org_id
+		 WHERE boa.status IN ? AND boa.breached_at IS NULL
+		 ORDER BY boa.assigned_at ASC, boa.id ASC`,
+		organizationdomain.DefaultAssignmentSLAMinutes,
+		[]string{billingopsdomain.AssignmentStatusAssigned, billingopsdomain.AssignmentStatusInProgress},
+	).Scan(&records).Error; err != nil {
 		return nil, err
 	}
 	return records, nil
 }
 
+// ListDunningCandidates returns overdue invoices across organizations that no
+// one has claimed, oldest due date first.
+func (r *RepositoryImpl) ListDunningCandidates(ctx context.Context, now time.Time, limit int) ([]billingopsdomain.DunningCandidateRow, error) {
+	var rows []billingopsdomain.DunningCandidateRow
+	if err := r.db.WithContext(ctx).Raw(
+		`SELECT
+			i.id AS invoice_id,
+			i.org_id AS org_id,
+			COALESCE(CAST(i.invoice_number AS TEXT), '') AS invoice_number,
+			i.customer_id AS customer_id,
+			COALESCE(c.email, '') AS customer_email,
+			i.total_amount AS total_amount,
+			i.currency AS currency,
+			i.due_at AS due_at,
+			o.name AS org_name,
+			o.support_email AS support_email,
+			obp.dunning_days AS dunning_days,
+			ipt.token_hash AS token_hash
+		FROM invoices i
+		JOIN customers c ON c.id = i.customer_id
+		JOIN organizations o ON o.id = i.org_id
+		LEFT JOIN organization_billing_preferences obp ON obp.org_id = i.org_id
+		LEFT JOIN invoice_public_tokens ipt ON ipt.invoice_id = i.id AND ipt.revoked_at IS NULL
+		WHERE i.status = 'FINALIZED'
+		  AND i.voided_at IS NULL
+		  AND i.paid_at IS NULL
+		  AND i.total_amount - i.amount_paid > COALESCE((i.metadata->>'amount_credited')::bigint, 0)
+		  AND i.due_at IS NOT NULL
+		  AND i.due_at < ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM billing_operation_assignments boa
+			WHERE boa.org_id = i.org_id
+			  AND boa.entity_type = ?
+			  AND boa.entity_id = i.id
+			  AND boa.status IN ?
+		  )
+		ORDER BY i.due_at ASC, i.id ASC
+		LIMIT ?`,
+		now,
+		billingopsdomain.EntityTypeInvoice,
+		[]string{
+			billingopsdomain.AssignmentStatusAssigned,
+			billingopsdomain.AssignmentStatusInProgress,
+			billingopsdomain.AssignmentStatusEscalated,
+		},
+		limit,
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 func (r *RepositoryImpl) UpsertAssignment(
 	ctx context.Context,
 	record billingopsdomain.BillingAssignmentRecord,
@@ -748,7 +904,7 @@ func (r *RepositoryImpl) EscalateAssignment(
 	now time.Time,
 ) error {
 	return r.db.WithContext(ctx).Model(&billingopsdomain.BillingAssignmentRecord{}).
-		Where("org_id = ? AND entity_type = ? AND entity_id = ?", orgID, entityType, entityID).
+		Where("org_id = ? AND entity_type = ? AND entity_id = ? AND breached_at IS NULL", orgID, entityType, entityID).
 		Updates(map[string]interface{}{
 			"status":       billingopsdomain.AssignmentStatusEscalated,
 			"breached_at":  now,
@@ -771,6 +927,10 @@ func (r *RepositoryImpl) FindSnapshotsByOrg(ctx context.Context, orgID snowflake
 	return r.finOpsRepo.FindByOrg(ctx, orgID, periodType, start, end)
 }
 
+func (r *RepositoryImpl) ListSnapshotHistory(ctx context.Context, orgID snowflake.ID, userID string, periodType string, page pagination.Pagination) ([]billingopsdomain.FinOpsScoreSnapshot, error) {
+	return r.finOpsRepo.ListHistory(ctx, orgID, userID, periodType, page)
+}
+
 func (r *RepositoryImpl) LoadEntitySnapshot(
 	ctx context.Context,
 	orgID snowflake.ID,
@@ -808,40 +968,15 @@ func (r *RepositoryImpl) loadInvoiceSnapshot(ctx context.Context, orgID, invoice
 			c.name AS customer_name,
 			i.currency AS currency,
 			i.due_at AS due_at,
-			GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS amount_due
+			GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS amount_due
 		FROM invoices i
 		JOIN customers c ON c.id = i.customer_id
-		LEFT JOIN (
-			SELECT
-				(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-				SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-			FROM ledger_entries le
-			JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-			JOIN ledger_accounts a ON a.id = l.account_id
-			JOIN payment_events pe ON pe.id = le.source_id
-			WHERE le.org_id = ?
-			  AND le.currency = (SELECT currency FROM invoices WHERE id = ? AND org_id = ?)
-			  AND le.source_type = ?
-			  AND a.code = ?
-			GROUP BY 1
-		) s ON s.invoice_id_text = i.id::text
 		WHERE i.org_id = ? AND i.id = ?
 		LIMIT 1`
 
-	currency, err := r.FetchOrgCurrency(ctx, orgID)
-	if err != nil {
-		return nil, err
-	}
-
 	if err := r.db.WithContext(ctx).Raw(
 		query,
 		orgID,
-		invoiceID, orgID,
-		orgID,
-		currency,
-		string(ledgerdomain.SourceTypePayment),
-		string(ledgerdomain.AccountCodeAccountsReceivable),
-		orgID,
 		invoiceID,
 	).Scan(&row).Error; err != nil {
 		return nil, err
@@ -889,29 +1024,15 @@ func (r *RepositoryImpl) loadCustomerSnapshot(ctx context.Context, orgID, custom
 	}
 
 	query := `
-		WITH settled AS (
-			SELECT
-				(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-				SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-			FROM ledger_entries le
-			JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-			JOIN ledger_accounts a ON a.id = l.account_id
-			JOIN payment_events pe ON pe.id = le.source_id
-			WHERE le.org_id = ?
-			  AND le.currency = ?
-			  AND le.source_type = ?
-			  AND a.code = ?
-			GROUP BY 1
-		), invoice_outstanding AS (
+		WITH invoice_outstanding AS (
 			SELECT
 				i.id AS invoice_id,
 				i.customer_id,
 				COALESCE(i.invoice_number::text, '') AS invoice_number,
 				i.due_at,
 				COALESCE(i.issued_at, i.created_at) AS issued_at,
-				GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS outstanding
+				GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS outstanding
 			FROM invoices i
-			LEFT JOIN settled s ON s.invoice_id_text = i.id::text
 			WHERE i.org_id = ?
 			  AND i.status = 'FINALIZED'
 			  AND i.voided_at IS NULL
@@ -956,10 +1077,6 @@ func (r *RepositoryImpl) loadCustomerSnapshot(ctx context.Context, orgID, custom
 		query,
 		orgID,
 		currency,
-		string(ledgerdomain.SourceTypePayment),
-		string(ledgerdomain.AccountCodeAccountsReceivable),
-		orgID,
-		currency,
 		orgID,
 		orgID,
 		customerID,
@@ -1039,29 +1156,29 @@ func (r *RepositoryImpl) ListInboxItems(
 				i.id::text AS entity_id,
 				COALESCE(i.invoice_number::text, i.id::text) AS entity_name,
 				'overdue' AS risk_category,
-				GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS amount_due,
+				GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS amount_due,
 				i.due_at,
 				EXTRACT(EPOCH FROM (? - i.due_at)) / 86400 AS days_overdue,
 				NULL::timestamp AS last_attempt,
 				ipt.token_hash,
-				-- Risk score: higher = more urgent
-				(EXTRACT(EPOCH FROM (? - i.due_at)) / 86400 * 10 + i.subtotal_amount / 10000)::int AS risk_score
+				-- Risk score: higher = more urgent; a breached SLA adds to it
+				(EXTRACT(EPOCH FROM (? - i.due_at)) / 86400 * 10 + i.subtotal_amount / 10000)::int
+					+ CASE WHEN esc.id IS NOT NULL THEN 100 ELSE 0 END AS risk_score,
+				(
+					SELECT a.email_status FROM billing_operation_actions a
+					WHERE a.org_id = i.org_id AND a.entity_type = 'invoice' AND a.entity_id = i.id
+						AND a.email_status IS NOT NULL
+					ORDER BY a.created_at DESC
+					LIMIT 1
+				) AS last_email_status
 			FROM invoices i
-			LEFT JOIN (
-				SELECT
-					(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-					SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-				FROM ledger_entries le
-				JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-				JOIN ledger_accounts a ON a.id = l.account_id
-				JOIN payment_events pe ON pe.id = le.source_id
-				WHERE le.org_id = ? AND le.currency = ? AND le.source_type = ? AND a.code = ?
-				GROUP BY 1
-			) s ON s.invoice_id_text = i.id::text
 			LEFT JOIN invoice_public_tokens ipt ON ipt.invoice_id = i.id AND ipt.revoked_at IS NULL
 			LEFT JOIN billing_operation_assignments boa 
 				ON boa.org_id = ? AND boa.entity_type = 'invoice' AND boa.entity_id = i.id 
 				AND boa.status IN ('assigned', 'in_progress')
+			LEFT JOIN billing_operation_assignments esc
+				ON esc.org_id = i.org_id AND esc.entity_type = 'invoice' AND esc.entity_id = i.id
+				AND esc.status = 'escalated'
 			WHERE i.org_id = ?
 				AND i.status = 'FINALIZED'
 				AND i.voided_at IS NULL
@@ -1069,7 +1186,7 @@ func (r *RepositoryImpl) ListInboxItems(
 				AND i.currency = ?
 				AND i.due_at IS NOT NULL
 				AND i.due_at < ?
-				AND GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) > 0
+				AND GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) > 0
 				AND boa.id IS NULL  -- No active assignment
 		),
 		risky_customers AS (
@@ -1083,25 +1200,22 @@ func (r *RepositoryImpl) ListInboxItems(
 				EXTRACT(EPOCH FROM (? - oo.due_at)) / 86400 AS days_overdue,
 				NULL::timestamp AS last_attempt,
 				ipt.token_hash,
-				(t.outstanding / 10000)::int AS risk_score
+				(t.outstanding / 10000)::int
+					+ CASE WHEN esc.id IS NOT NULL THEN 100 ELSE 0 END AS risk_score,
+				(
+					SELECT a.email_status FROM billing_operation_actions a
+					WHERE a.org_id = c.org_id AND a.entity_type = 'customer' AND a.entity_id = c.id
+						AND a.email_status IS NOT NULL
+					ORDER BY a.created_at DESC
+					LIMIT 1
+				) AS last_email_status
 			FROM (
 				SELECT customer_id, SUM(outstanding) AS outstanding
 				FROM (
 					SELECT
 						i.customer_id,
-						GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS outstanding
+						GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS outstanding
 					FROM invoices i
-					LEFT JOIN (
-						SELECT
-							(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-							SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-						FROM ledger_entries le
-						JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-						JOIN ledger_accounts a ON a.id = l.account_id
-						JOIN payment_events pe ON pe.id = le.source_id
-						WHERE le.org_id = ? AND le.currency = ? AND le.source_type = ? AND a.code = ?
-						GROUP BY 1
-					) s ON s.invoice_id_text = i.id::text
 					WHERE i.org_id = ? AND i.status = 'FINALIZED' AND i.voided_at IS NULL AND i.currency = ?
 				) inv
 				WHERE outstanding > 0
@@ -1116,22 +1230,11 @@ func (r *RepositoryImpl) ListInboxItems(
 						i.customer_id,
 						i.due_at
 					FROM invoices i
-					LEFT JOIN (
-						SELECT
-							(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-							SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-						FROM ledger_entries le
-						JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-						JOIN ledger_accounts a ON a.id = l.account_id
-						JOIN payment_events pe ON pe.id = le.source_id
-						WHERE le.org_id = ? AND le.currency = ? AND le.source_type = ? AND a.code = ?
-						GROUP BY 1
-					) s ON s.invoice_id_text = i.id::text
 					WHERE i.org_id = ?
 						AND i.status = 'FINALIZED'
 						AND i.voided_at IS NULL
 						AND i.currency = ?
-						AND GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) > 0
+						AND GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) > 0
 						AND i.due_at IS NOT NULL
 						AND i.due_at < ?
 				) inv
@@ -1143,6 +1246,9 @@ func (r *RepositoryImpl) ListInboxItems(
 			LEFT JOIN billing_operation_assignments boa 
 				ON boa.org_id = ? AND boa.entity_type = 'customer' AND boa.entity_id = c.id 
 				AND boa.status IN ('assigned', 'in_progress')
+			LEFT JOIN billing_operation_assignments esc
+				ON esc.org_id = c.org_id AND esc.entity_type = 'customer' AND esc.entity_id = c.id
+				AND esc.status = 'escalated'
 			WHERE c.org_id = ?
 				AND t.outstanding >= 100000  -- High exposure threshold
 				AND boa.id IS NULL  -- No active assignment
@@ -1164,12 +1270,9 @@ func (r *RepositoryImpl) ListInboxItems(
 	if err := r.db.WithContext(ctx).Raw(
 		query,
 		now, now,
-		orgID, currency, string(ledgerdomain.SourceTypePayment), string(ledgerdomain.AccountCodeAccountsReceivable),
 		orgID, orgID, currency, now,
 		now,
-		orgID, currency, string(ledgerdomain.SourceTypePayment), string(ledgerdomain.AccountCodeAccountsReceivable),
 		orgID, currency,
-		orgID, currency, string(ledgerdomain.SourceTypePayment), string(ledgerdomain.AccountCodeAccountsReceivable),
 		orgID, currency, now,
 		orgID, orgID,
 		limit,
@@ -1213,7 +1316,7 @@ func (r *RepositoryImpl) ListMyWorkItems(
 				ELSE NULL
 			END AS invoice_number,
 			CASE
-				WHEN boa.entity_type = 'invoice' THEN GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0)
+				WHEN boa.entity_type = 'invoice' THEN GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0)
 				WHEN boa.entity_type = 'customer' THEN t.outstanding
 			END AS current_amount_due,
 			CASE
@@ -1225,40 +1328,25 @@ func (r *RepositoryImpl) ListMyWorkItems(
 			CASE
 				WHEN boa.entity_type = 'invoice' THEN ipt_inv.token_hash
 				WHEN boa.entity_type = 'customer' THEN ipt_cust.token_hash
-			END AS token_hash
+			END AS token_hash,
+			(
+				SELECT a.email_status FROM billing_operation_actions a
+				WHERE a.org_id = boa.org_id AND a.entity_type = boa.entity_type AND a.entity_id = boa.entity_id
+					AND a.email_status IS NOT NULL
+				ORDER BY a.created_at DESC
+				LIMIT 1
+			) AS last_email_status
 		FROM billing_operation_assignments boa
 		LEFT JOIN invoices i ON boa.entity_type = 'invoice' AND boa.entity_id = i.id
 		LEFT JOIN customers c ON boa.entity_type = 'customer' AND boa.entity_id = c.id
 		LEFT JOIN customers c_inv ON boa.entity_type = 'invoice' AND i.customer_id = c_inv.id
-		LEFT JOIN (
-			SELECT
-				(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-				SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-			FROM ledger_entries le
-			JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-			JOIN ledger_accounts a ON a.id = l.account_id
-			JOIN payment_events pe ON pe.id = le.source_id
-			WHERE le.org_id = ? AND le.currency = ? AND le.source_type = ? AND a.code = ?
-			GROUP BY 1
-		) s ON s.invoice_id_text = i.id::text
 		LEFT JOIN (
 			SELECT customer_id, SUM(outstanding) AS outstanding
 			FROM (
 				SELECT
 					i.customer_id,
-					GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS outstanding
+					GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS outstanding
 				FROM invoices i
-				LEFT JOIN (
-					SELECT
-						(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-						SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-					FROM ledger_entries le
-					JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-					JOIN ledger_accounts a ON a.id = l.account_id
-					JOIN payment_events pe ON pe.id = le.source_id
-					WHERE le.org_id = ? AND le.currency = ? AND le.source_type = ? AND a.code = ?
-					GROUP BY 1
-				) s ON s.invoice_id_text = i.id::text
 				WHERE i.org_id = ? AND i.status = 'FINALIZED' AND i.voided_at IS NULL AND i.currency = ?
 			) inv
 			WHERE outstanding > 0
@@ -1270,19 +1358,8 @@ func (r *RepositoryImpl) ListMyWorkItems(
 			FROM (
 				SELECT i.customer_id, i.due_at
 				FROM invoices i
-				LEFT JOIN (
-					SELECT
-						(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-						SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-					FROM ledger_entries le
-					JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-					JOIN ledger_accounts a ON a.id = l.account_id
-					JOIN payment_events pe ON pe.id = le.source_id
-					WHERE le.org_id = ? AND le.currency = ? AND le.source_type = ? AND a.code = ?
-					GROUP BY 1
-				) s ON s.invoice_id_text = i.id::text
 				WHERE i.org_id = ? AND i.status = 'FINALIZED' AND i.voided_at IS NULL AND i.currency = ?
-					AND GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) > 0
+					AND GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) > 0
 					AND i.due_at IS NOT NULL AND i.due_at < ?
 			) inv
 			ORDER BY customer_id, due_at ASC
@@ -1306,10 +1383,7 @@ func (r *RepositoryImpl) ListMyWorkItems(
 	if err := r.db.WithContext(ctx).Raw(
 		query,
 		now, now,
-		orgID, currency, string(ledgerdomain.SourceTypePayment), string(ledgerdomain.AccountCodeAccountsReceivable),
-		orgID, currency, string(ledgerdomain.SourceTypePayment), string(ledgerdomain.AccountCodeAccountsReceivable),
 		orgID, currency,
-		orgID, currency, string(ledgerdomain.SourceTypePayment), string(ledgerdomain.AccountCodeAccountsReceivable),
 		orgID, currency, now,
 		orgID, userID,
 		limit,
@@ -1396,11 +1470,10 @@ func (r *RepositoryImpl) ListInvoicePayments(
 ) ([]billingopsdomain.PaymentRow, error) {
 	query := `
 		SELECT
-			pe.provider_payment_id,
+			pe.provider_event_id,
 			pe.provider,
 			pe.event_type,
 			pe.received_at,
-			pe.currency,
 			pe.payload
 		FROM payment_events pe
 		WHERE pe.org_id = ?
@@ -1411,9 +1484,55 @@ func (r *RepositoryImpl) ListInvoicePayments(
 	if err := r.db.WithContext(ctx).Raw(query, orgID, invoiceID.String()).Scan(&rows).Error; err != nil {
 		return nil, err
 	}
+	for i := range rows {
+		fillPaymentRow(&rows[i])
+	}
 	return rows, nil
 }
 
+// fillPaymentRow reads the payment object of a stored provider payload. Refund
+// events report the refunded amount rather than the original charge.
+func fillPaymentRow(row *billingopsdomain.PaymentRow) {
+	var payload struct {
+		Data struct {
+			Object struct {
+				ID             string `json:"id"`
+				Amount         int64  `json:"amount"`
+				AmountRefunded int64  `json:"amount_refunded"`
+				Currency       string `json:"currency"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if len(row.Payload) > 0 {
+		_ = json.Unmarshal(row.Payload, &payload)
+	}
+	object := payload.Data.Object
+
+	row.ProviderPaymentID = object.ID
+	if row.ProviderPaymentID == "" {
+		row.ProviderPaymentID = row.ProviderEventID
+	}
+	row.Amount = object.Amount
+	if row.EventType == paymentdomain.EventTypeRefunded && object.AmountRefunded > 0 {
+		row.Amount = object.AmountRefunded
+	}
+	row.Currency = strings.ToUpper(strings.TrimSpace(object.Currency))
+	row.Status = paymentStatusForEventType(row.EventType)
+}
+
+func paymentStatusForEventType(eventType string) string {
+	switch eventType {
+	case paymentdomain.EventTypePaymentSucceeded:
+		return "succeeded"
+	case paymentdomain.EventTypePaymentFailed:
+		return "failed"
+	case paymentdomain.EventTypeRefunded:
+		return "refunded"
+	default:
+		return eventType
+	}
+}
+
 func (r *RepositoryImpl) GetExposureStats(
 	ctx context.Context,
 	orgID snowflake.ID,
@@ -1430,20 +1549,9 @@ func (r *RepositoryImpl) GetExposureStats(
 			COUNT(CASE WHEN days_overdue > 0 THEN 1 END) AS overdue_count
 		FROM (
 			SELECT
-				GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS outstanding,
+				GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS outstanding,
 				EXTRACT(EPOCH FROM (? - i.due_at)) / 86400 AS days_overdue
 			FROM invoices i
-			LEFT JOIN (
-				SELECT
-					(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-					SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-				FROM ledger_entries le
-				JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-				JOIN ledger_accounts a ON a.id = l.account_id
-				JOIN payment_events pe ON pe.id = le.source_id
-				WHERE le.org_id = ? AND le.currency = ? AND le.source_type = ? AND a.code = ?
-				GROUP BY 1
-			) s ON s.invoice_id_text = i.id::text
 			WHERE i.org_id = ?
 				AND i.status = 'FINALIZED'
 				AND i.voided_at IS NULL
@@ -1462,7 +1570,6 @@ func (r *RepositoryImpl) GetExposureStats(
 	if err := r.db.WithContext(ctx).Raw(
 		query,
 		now,
-		orgID, currency, string(ledgerdomain.SourceTypePayment), string(ledgerdomain.AccountCodeAccountsReceivable),
 		orgID, currency,
 	).Scan(&stats).Error; err != nil {
 		return billingopsdomain.ExposureStatsRow{}, err
@@ -1484,20 +1591,9 @@ func (r *RepositoryImpl) ListTopHighExposure(
 		FROM (
 			SELECT
 				i.customer_id,
-				GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0) AS outstanding,
+				GREATEST(i.total_amount - i.amount_paid - COALESCE((i.metadata->>'amount_credited')::bigint, 0), 0) AS outstanding,
 				(EXTRACT(EPOCH FROM (? - i.due_at)) / 86400)::int AS days_overdue
 			FROM invoices i
-			LEFT JOIN (
-				SELECT
-					(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-					SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-				FROM ledger_entries le
-				JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-				JOIN ledger_accounts a ON a.id = l.account_id
-				JOIN payment_events pe ON pe.id = le.source_id
-				WHERE le.org_id = ? AND le.currency = ? AND le.source_type = ? AND a.code = ?
-				GROUP BY 1
-			) s ON s.invoice_id_text = i.id::text
 			WHERE i.org_id = ? AND i.status = 'FINALIZED' AND i.voided_at IS NULL AND i.currency = ?
 		) inv
 		JOIN customers c ON c.id = inv.customer_id
@@ -1515,7 +1611,6 @@ func (r *RepositoryImpl) ListTopHighExposure(
 	if err := r.db.WithContext(ctx).Raw(
 		query,
 		now,
-		orgID, currency, string(ledgerdomain.SourceTypePayment), string(ledgerdomain.AccountCodeAccountsReceivable),
 		orgID, currency,
 	).Scan(&rows).Error; err != nil {
 		return nil, err
@@ -1537,4 +1632,3 @@ func (r *RepositoryImpl) ListBillingAssignmentsForPerformance(
 	}
 	return assignments, nil
 }
-
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,6 +13,7 @@ import (
 	auditcontext "github.com/railzwaylabs/railzway/internal/auditcontext"
 	"github.com/railzwaylabs/railzway/internal/billingoperations/domain"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/providers/email"
 	"go.uber.org/zap"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
@@ -144,20 +146,124 @@ func (s *Service) RecordAction(ctx context.Context, req domain.RecordActionReque
 	}, nil
 }
 
+// claimTarget is a validated claim request.
+type claimTarget struct {
+	entityType string
+	entityID   snowflake.ID
+	assignedTo string
+	expiresAt  time.Time
+}
+
+// releaseTarget is a validated release request.
+type releaseTarget struct {
+	entityType string
+	entityID   snowflake.ID
+	releasedBy string
+	reason     string
+}
+
 func (s *Service) ClaimAssignment(ctx context.Context, req domain.ClaimAssignmentRequest) (domain.AssignmentResponse, error) {
 	orgID, ok := orgcontext.OrgIDFromContext(ctx)
 	if !ok || orgID == 0 {
 		return domain.AssignmentResponse{}, domain.ErrInvalidOrganization
 	}
 
+	now := s.clock.Now(ctx).UTC()
+	target, err := parseClaimRequest(ctx, req, now)
+	if err != nil {
+		return domain.AssignmentResponse{}, err
+	}
+
+	var result *domain.AssignmentResponse
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		claimed, err := s.claimInTx(ctx, s.repo.WithTx(tx), snowflake.ID(orgID), target, now)
+		result = claimed
+		return err
+	})
+
+	if err != nil {
+		return domain.AssignmentResponse{}, err
+	}
+	if result == nil {
+		return domain.AssignmentResponse{}, fmt.Errorf("internal error: result not set in transaction")
+	}
+
+	if result.Status == domain.AssignmentStatusAssigned {
+		s.auditClaim(ctx, snowflake.ID(orgID), target)
+	}
+
+	return *result, nil
+}
+
+// BulkClaimAssignments claims every entity in one transaction. Entities that
+// fail validation or are held by someone else are reported in their result
+// and skipped; only storage errors fail the batch.
+func (s *Service) BulkClaimAssignments(ctx context.Context, reqs []domain.ClaimAssignmentRequest) (domain.BulkAssignmentResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return domain.BulkAssignmentResponse{}, domain.ErrInvalidOrganization
+	}
+	if len(reqs) == 0 || len(reqs) > domain.MaxBulkAssignments {
+		return domain.BulkAssignmentResponse{}, domain.ErrInvalidBulkSize
+	}
+
+	now := s.clock.Now(ctx).UTC()
+	results := make([]domain.BulkAssignmentResult, len(reqs))
+	var claimed []claimTarget
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repoTx := s.repo.WithTx(tx)
+		claimed = claimed[:0]
+
+		for i, req := range reqs {
+			results[i] = domain.BulkAssignmentResult{
+				EntityType: strings.TrimSpace(req.EntityType),
+				EntityID:   strings.TrimSpace(req.EntityID),
+			}
+
+			target, err := parseClaimRequest(ctx, req, now)
+			if err != nil {
+				results[i].Status = domain.BulkStatusInvalid
+				results[i].Error = err.Error()
+				continue
+			}
+
+			resp, err := s.claimInTx(ctx, repoTx, snowflake.ID(orgID), target, now)
+			if errors.Is(err, domain.ErrAssignmentConflict) {
+				results[i].Status = domain.BulkStatusConflict
+				results[i].Error = err.Error()
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			results[i].Status = domain.BulkStatusClaimed
+			results[i].Assignment = &resp.Assignment
+			claimed = append(claimed, target)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.BulkAssignmentResponse{}, err
+	}
+
+	for _, target := range claimed {
+		s.auditClaim(ctx, snowflake.ID(orgID), target)
+	}
+
+	return domain.BulkAssignmentResponse{Results: results}, nil
+}
+
+func parseClaimRequest(ctx context.Context, req domain.ClaimAssignmentRequest, now time.Time) (claimTarget, error) {
 	entityType := strings.TrimSpace(req.EntityType)
 	if entityType != domain.EntityTypeInvoice && entityType != domain.EntityTypeCustomer {
-		return domain.AssignmentResponse{}, domain.ErrInvalidEntityType
+		return claimTarget{}, domain.ErrInvalidEntityType
 	}
 
 	entityID, err := parseSnowflakeID(req.EntityID)
 	if err != nil {
-		return domain.AssignmentResponse{}, domain.ErrInvalidEntityID
+		return claimTarget{}, domain.ErrInvalidEntityID
 	}
 
 	assignedTo := strings.TrimSpace(req.AssignedTo)
@@ -166,7 +272,7 @@ func (s *Service) ClaimAssignment(ctx context.Context, req domain.ClaimAssignmen
 		assignedTo = strings.TrimSpace(actorID)
 	}
 	if assignedTo == "" {
-		return domain.AssignmentResponse{}, domain.ErrInvalidAssignee
+		return claimTarget{}, domain.ErrInvalidAssignee
 	}
 
 	ttlMinutes := req.AssignmentTTLMinutes
@@ -174,138 +280,131 @@ func (s *Service) ClaimAssignment(ctx context.Context, req domain.ClaimAssignmen
 		ttlMinutes = 120
 	}
 	if ttlMinutes < 0 {
-		return domain.AssignmentResponse{}, domain.ErrInvalidAssignmentTTL
+		return claimTarget{}, domain.ErrInvalidAssignmentTTL
 	}
 
-	now := s.clock.Now(ctx).UTC()
-	expiresAt := now.Add(time.Duration(ttlMinutes) * time.Minute)
-
-	var result *domain.AssignmentResponse
-	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		repoTx := s.repo.WithTx(tx)
-
-		existing, err := repoTx.LoadAssignmentForUpdate(ctx, snowflake.ID(orgID), entityType, entityID)
-		if err != nil {
-			return err
-		}
-
-		if existing != nil && existing.Status != domain.AssignmentStatusReleased {
-			if existing.AssignedTo != assignedTo {
-				return domain.ErrAssignmentConflict
-			}
-			record := *existing
-			record.AssignmentExpiresAt = expiresAt
-			record.UpdatedAt = now
-
-			if err := repoTx.UpsertAssignment(ctx, record); err != nil {
-				return err
-			}
-
-			result = &domain.AssignmentResponse{
-				Assignment: domain.Assignment{
-					EntityType:          entityType,
-					EntityID:            entityID.String(),
-					Status:              existing.Status,
-					AssignedTo:          assignedTo,
-					AssignedAt:          existing.AssignedAt,
-					AssignmentExpiresAt: expiresAt,
-					LastActionAt:        timePtr(existing.LastActionAt),
-				},
-				Status: domain.AssignmentStatusAssigned,
-			}
-			return nil
-		}
-
-		snapshot, err := s.repo.LoadEntitySnapshot(ctx, snowflake.ID(orgID), req.EntityType, entityID)
-		if err != nil {
-			s.log.Warn("failed to load entity snapshot", zap.Error(err))
-			snapshot = make(map[string]interface{})
-		}
+	return claimTarget{
+		entityType: entityType,
+		entityID:   entityID,
+		assignedTo: assignedTo,
+		expiresAt:  now.Add(time.Duration(ttlMinutes) * time.Minute),
+	}, nil
+}
 
-		snapshotJSON, err := json.Marshal(snapshot)
-		if err != nil {
-			s.log.Warn("failed to marshal snapshot", zap.Error(err))
-			snapshotJSON = []byte("{}")
-		}
+// claimInTx claims one entity inside the caller's transaction. A claim held
+// by someone else returns ErrAssignmentConflict before anything is written.
+func (s *Service) claimInTx(ctx context.Context, repoTx domain.Repository, orgID snowflake.ID, target claimTarget, now time.Time) (*domain.AssignmentResponse, error) {
+	existing, err := repoTx.LoadAssignmentForUpdate(ctx, orgID, target.entityType, target.entityID)
+	if err != nil {
+		return nil, err
+	}
 
-		record := domain.BillingAssignmentRecord{
-			ID:                  s.genID.Generate(),
-			OrgID:               snowflake.ID(orgID),
-			EntityType:          entityType,
-			EntityID:            entityID,
-			AssignedTo:          assignedTo,
-			AssignedAt:          now,
-			AssignmentExpiresAt: expiresAt,
-			Status:              domain.AssignmentStatusAssigned,
-			SnapshotMetadata:    datatypes.JSON(snapshotJSON),
-			CreatedAt:           now,
-			UpdatedAt:           now,
+	if existing != nil && existing.Status != domain.AssignmentStatusReleased {
+		if existing.AssignedTo != target.assignedTo {
+			return nil, domain.ErrAssignmentConflict
 		}
+		record := *existing
+		record.AssignmentExpiresAt = target.expiresAt
+		record.UpdatedAt = now
 
 		if err := repoTx.UpsertAssignment(ctx, record); err != nil {
-			return err
+			return nil, err
 		}
 
-		result = &domain.AssignmentResponse{
+		return &domain.AssignmentResponse{
 			Assignment: domain.Assignment{
-				EntityType:          entityType,
-				EntityID:            entityID.String(),
-				Status:              domain.AssignmentStatusAssigned,
-				AssignedTo:          assignedTo,
-				AssignedAt:          now,
-				AssignmentExpiresAt: expiresAt,
+				EntityType:          target.entityType,
+				EntityID:            target.entityID.String(),
+				Status:              existing.Status,
+				AssignedTo:          target.assignedTo,
+				AssignedAt:          existing.AssignedAt,
+				AssignmentExpiresAt: target.expiresAt,
+				LastActionAt:        timePtr(existing.LastActionAt),
 			},
 			Status: domain.AssignmentStatusAssigned,
-		}
-
-		actionID := s.genID.Generate()
-		bucket := now.Truncate(24 * time.Hour)
-
-		if _, err := repoTx.InsertBillingAction(ctx, domain.BillingActionRecord{
-			ID:           actionID,
-			OrgID:        snowflake.ID(orgID),
-			EntityType:   entityType,
-			EntityID:     entityID,
-			ActionType:   domain.ActionTypeClaim,
-			ActionBucket: bucket,
-			Metadata: datatypes.JSONMap{
-				"assignment_id": record.ID.String(),
-				"expires_at":    expiresAt,
-			},
-			ActorType: "user",
-			ActorID:   assignedTo,
-			CreatedAt: now,
-		}); err != nil {
-			return err
-		}
+		}, nil
+	}
 
-		return nil
-	})
+	snapshot, err := repoTx.LoadEntitySnapshot(ctx, orgID, target.entityType, target.entityID)
+	if err != nil {
+		s.log.Warn("failed to load entity snapshot", zap.Error(err))
+		snapshot = make(map[string]interface{})
+	}
 
+	snapshotJSON, err := json.Marshal(snapshot)
 	if err != nil {
-		return domain.AssignmentResponse{}, err
+		s.log.Warn("failed to marshal snapshot", zap.Error(err))
+		snapshotJSON = []byte("{}")
 	}
-	if result == nil {
-		return domain.AssignmentResponse{}, fmt.Errorf("internal error: result not set in transaction")
+
+	record := domain.BillingAssignmentRecord{
+		ID:                  s.genID.Generate(),
+		OrgID:               orgID,
+		EntityType:          target.entityType,
+		EntityID:            target.entityID,
+		AssignedTo:          target.assignedTo,
+		AssignedAt:          now,
+		AssignmentExpiresAt: target.expiresAt,
+		Status:              domain.AssignmentStatusAssigned,
+		SnapshotMetadata:    datatypes.JSON(snapshotJSON),
+		CreatedAt:           now,
+		UpdatedAt:           now,
 	}
 
-	if s.auditSvc != nil && result.Status == domain.AssignmentStatusAssigned {
-		targetID := result.Assignment.EntityID
-		oid := snowflake.ID(orgID)
-		_ = s.auditSvc.AuditLog(ctx, &oid, "", nil,
-			"billing_operations.assignment.claimed",
-			"billing_operation_assignment",
-			&targetID,
-			map[string]any{
-				"entity_type": entityType,
-				"entity_id":   entityID.String(),
-				"assigned_to": assignedTo,
-				"expires_at":  expiresAt.Format(time.RFC3339),
-			},
-		)
+	if err := repoTx.UpsertAssignment(ctx, record); err != nil {
+		return nil, err
 	}
 
-	return *result, nil
+	actionID := s.genID.Generate()
+	bucket := now.Truncate(24 * time.Hour)
+
+	if _, err := repoTx.InsertBillingAction(ctx, domain.BillingActionRecord{
+		ID:           actionID,
+		OrgID:        orgID,
+		EntityType:   target.entityType,
+		EntityID:     target.entityID,
+		ActionType:   domain.ActionTypeClaim,
+		ActionBucket: bucket,
+		Metadata: datatypes.JSONMap{
+			"assignment_id": record.ID.String(),
+			"expires_at":    target.expiresAt,
+		},
+		ActorType: "user",
+		ActorID:   target.assignedTo,
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &domain.AssignmentResponse{
+		Assignment: domain.Assignment{
+			EntityType:          target.entityType,
+			EntityID:            target.entityID.String(),
+			Status:              domain.AssignmentStatusAssigned,
+			AssignedTo:          target.assignedTo,
+			AssignedAt:          now,
+			AssignmentExpiresAt: target.expiresAt,
+		},
+		Status: domain.AssignmentStatusAssigned,
+	}, nil
+}
+
+func (s *Service) auditClaim(ctx context.Context, orgID snowflake.ID, target claimTarget) {
+	if s.auditSvc == nil {
+		return
+	}
+	targetID := target.entityID.String()
+	_ = s.auditSvc.AuditLog(ctx, &orgID, "", nil,
+		"billing_operations.assignment.claimed",
+		"billing_operation_assignment",
+		&targetID,
+		map[string]any{
+			"entity_type": target.entityType,
+			"entity_id":   target.entityID.String(),
+			"assigned_to": target.assignedTo,
+			"expires_at":  target.expiresAt.Format(time.RFC3339),
+		},
+	)
 }
 
 func (s *Service) ReleaseAssignment(ctx context.Context, req domain.ReleaseAssignmentRequest) error {
@@ -314,102 +413,187 @@ func (s *Service) ReleaseAssignment(ctx context.Context, req domain.ReleaseAssig
 		return domain.ErrInvalidOrganization
 	}
 
-	entityType := strings.TrimSpace(req.EntityType)
-	if entityType != domain.EntityTypeInvoice && entityType != domain.EntityTypeCustomer {
-		return domain.ErrInvalidEntityType
+	target, err := parseReleaseRequest(ctx, req)
+	if err != nil {
+		return err
 	}
 
-	entityID, err := parseSnowflakeID(req.EntityID)
+	now := s.clock.Now(ctx).UTC()
+
+	var released bool
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		didRelease, err := s.releaseInTx(ctx, s.repo.WithTx(tx), snowflake.ID(orgID), target, now)
+		released = didRelease
+		return err
+	})
 	if err != nil {
-		return domain.ErrInvalidEntityID
+		return err
 	}
 
-	releasedBy := strings.TrimSpace(req.ReleasedBy)
-	if releasedBy == "" {
-		_, actorID := auditcontext.ActorFromContext(ctx)
-		releasedBy = strings.TrimSpace(actorID)
+	if released {
+		s.auditRelease(ctx, snowflake.ID(orgID), target)
 	}
-	if releasedBy == "" {
-		return domain.ErrInvalidAssignee
+
+	return nil
+}
+
+// BulkReleaseAssignments releases every entity in one transaction. Invalid
+// requests and entities with nothing to release are reported in their
+// result; only storage errors fail the batch.
+func (s *Service) BulkReleaseAssignments(ctx context.Context, reqs []domain.ReleaseAssignmentRequest) (domain.BulkAssignmentResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return domain.BulkAssignmentResponse{}, domain.ErrInvalidOrganization
+	}
+	if len(reqs) == 0 || len(reqs) > domain.MaxBulkAssignments {
+		return domain.BulkAssignmentResponse{}, domain.ErrInvalidBulkSize
 	}
 
 	now := s.clock.Now(ctx).UTC()
+	results := make([]domain.BulkAssignmentResult, len(reqs))
+	var released []releaseTarget
 
-	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		repoTx := s.repo.WithTx(tx)
+		released = released[:0]
 
-		existing, err := repoTx.LoadAssignmentForUpdate(ctx, snowflake.ID(orgID), entityType, entityID)
-		if err != nil {
-			return err
-		}
-		if existing == nil || existing.Status == domain.AssignmentStatusReleased {
-			return nil
-		}
+		for i, req := range reqs {
+			results[i] = domain.BulkAssignmentResult{
+				EntityType: strings.TrimSpace(req.EntityType),
+				EntityID:   strings.TrimSpace(req.EntityID),
+			}
 
-		existing.Status = domain.AssignmentStatusReleased
-		existing.ReleasedAt = sql.NullTime{Time: now, Valid: true}
-		existing.ReleasedBy = sql.NullString{String: releasedBy, Valid: true}
-		existing.ReleaseReason = sql.NullString{String: req.Reason, Valid: true}
-		existing.ResolvedAt = sql.NullTime{Time: now, Valid: true}
-		existing.ResolvedBy = sql.NullString{String: releasedBy, Valid: true}
-		existing.UpdatedAt = now
+			target, err := parseReleaseRequest(ctx, req)
+			if err != nil {
+				results[i].Status = domain.BulkStatusInvalid
+				results[i].Error = err.Error()
+				continue
+			}
 
-		if err := repoTx.UpsertAssignment(ctx, *existing); err != nil {
-			return err
+			didRelease, err := s.releaseInTx(ctx, repoTx, snowflake.ID(orgID), target, now)
+			if err != nil {
+				return err
+			}
+			if !didRelease {
+				results[i].Status = domain.BulkStatusSkipped
+				continue
+			}
+
+			results[i].Status = domain.BulkStatusReleased
+			released = append(released, target)
 		}
+		return nil
+	})
+	if err != nil {
+		return domain.BulkAssignmentResponse{}, err
+	}
 
-		actionID := s.genID.Generate()
-		bucket := now.Truncate(24 * time.Hour)
+	for _, target := range released {
+		s.auditRelease(ctx, snowflake.ID(orgID), target)
+	}
 
-		snapshot, err := s.repo.LoadEntitySnapshot(ctx, snowflake.ID(orgID), entityType, entityID)
-		if err != nil {
-			s.log.Warn("failed to load entity snapshot", zap.Error(err))
-			snapshot = make(map[string]interface{})
-		}
+	return domain.BulkAssignmentResponse{Results: results}, nil
+}
 
-		if _, err := repoTx.InsertBillingAction(ctx, domain.BillingActionRecord{
-			ID:           actionID,
-			OrgID:        snowflake.ID(orgID),
-			EntityType:   entityType,
-			EntityID:     entityID,
-			ActionType:   domain.ActionTypeRelease,
-			ActionBucket: bucket,
-			Metadata: datatypes.JSONMap{
-				"assignment_id": existing.ID.String(),
-				"released_by":   releasedBy,
-				"reason":        req.Reason,
-				"snapshot":      snapshot,
-			},
-			ActorType: "user",
-			ActorID:   releasedBy,
-			CreatedAt: now,
-		}); err != nil {
-			return err
-		}
+func parseReleaseRequest(ctx context.Context, req domain.ReleaseAssignmentRequest) (releaseTarget, error) {
+	entityType := strings.TrimSpace(req.EntityType)
+	if entityType != domain.EntityTypeInvoice && entityType != domain.EntityTypeCustomer {
+		return releaseTarget{}, domain.ErrInvalidEntityType
+	}
 
-		return nil
-	})
+	entityID, err := parseSnowflakeID(req.EntityID)
 	if err != nil {
-		return err
+		return releaseTarget{}, domain.ErrInvalidEntityID
 	}
 
-	if s.auditSvc != nil {
-		targetID := entityID.String()
-		oid := snowflake.ID(orgID)
-		_ = s.auditSvc.AuditLog(ctx, &oid, "", nil,
-			"billing_operations.assignment.released",
-			"billing_operation_assignment",
-			&targetID,
-			map[string]any{
-				"entity_type": entityType,
-				"entity_id":   entityID.String(),
-				"released_by": releasedBy,
-				"reason":      req.Reason,
-			},
-		)
+	releasedBy := strings.TrimSpace(req.ReleasedBy)
+	if releasedBy == "" {
+		_, actorID := auditcontext.ActorFromContext(ctx)
+		releasedBy = strings.TrimSpace(actorID)
+	}
+	if releasedBy == "" {
+		return releaseTarget{}, domain.ErrInvalidAssignee
 	}
 
-	return nil
+	return releaseTarget{
+		entityType: entityType,
+		entityID:   entityID,
+		releasedBy: releasedBy,
+		reason:     req.Reason,
+	}, nil
+}
+
+// releaseInTx releases one entity inside the caller's transaction and
+// reports whether there was an active assignment to release.
+func (s *Service) releaseInTx(ctx context.Context, repoTx domain.Repository, orgID snowflake.ID, target releaseTarget, now time.Time) (bool, error) {
+	existing, err := repoTx.LoadAssignmentForUpdate(ctx, orgID, target.entityType, target.entityID)
+	if err != nil {
+		return false, err
+	}
+	if existing == nil || existing.Status == domain.AssignmentStatusReleased {
+		return false, nil
+	}
+
+	existing.Status = domain.AssignmentStatusReleased
+	existing.ReleasedAt = sql.NullTime{Time: now, Valid: true}
+	existing.ReleasedBy = sql.NullString{String: target.releasedBy, Valid: true}
+	existing.ReleaseReason = sql.NullString{String: target.reason, Valid: true}
+	existing.ResolvedAt = sql.NullTime{Time: now, Valid: true}
+	existing.ResolvedBy = sql.NullString{String: target.releasedBy, Valid: true}
+	existing.UpdatedAt = now
+
+	if err := repoTx.UpsertAssignment(ctx, *existing); err != nil {
+		return false, err
+	}
+
+	actionID := s.genID.Generate()
+	bucket := now.Truncate(24 * time.Hour)
+
+	snapshot, err := repoTx.LoadEntitySnapshot(ctx, orgID, target.entityType, target.entityID)
+	if err != nil {
+		s.log.Warn("failed to load entity snapshot", zap.Error(err))
+		snapshot = make(map[string]interface{})
+	}
+
+	if _, err := repoTx.InsertBillingAction(ctx, domain.BillingActionRecord{
+		ID:           actionID,
+		OrgID:        orgID,
+		EntityType:   target.entityType,
+		EntityID:     target.entityID,
+		ActionType:   domain.ActionTypeRelease,
+		ActionBucket: bucket,
+		Metadata: datatypes.JSONMap{
+			"assignment_id": existing.ID.String(),
+			"released_by":   target.releasedBy,
+			"reason":        target.reason,
+			"snapshot":      snapshot,
+		},
+		ActorType: "user",
+		ActorID:   target.releasedBy,
+		CreatedAt: now,
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Service) auditRelease(ctx context.Context, orgID snowflake.ID, target releaseTarget) {
+	if s.auditSvc == nil {
+		return
+	}
+	targetID := target.entityID.String()
+	_ = s.auditSvc.AuditLog(ctx, &orgID, "", nil,
+		"billing_operations.assignment.released",
+		"billing_operation_assignment",
+		&targetID,
+		map[string]any{
+			"entity_type": target.entityType,
+			"entity_id":   target.entityID.String(),
+			"released_by": target.releasedBy,
+			"reason":      target.reason,
+		},
+	)
 }
 
 func (s *Service) ResolveAssignment(ctx context.Context, req domain.ResolveAssignmentRequest) error {
@@ -511,19 +695,102 @@ func (s *Service) ResolveAssignment(ctx context.Context, req domain.ResolveAssig
 	return nil
 }
 
+// GetAssignmentDetail returns an entity's current assignment with every
+// billing action recorded on it, so the collection timeline can be reviewed
+// before acting. Entities that were never claimed nor acted on are not found.
+func (s *Service) GetAssignmentDetail(ctx context.Context, entityType, entityID string) (domain.AssignmentDetailResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return domain.AssignmentDetailResponse{}, domain.ErrInvalidOrganization
+	}
+
+	entityType = strings.TrimSpace(entityType)
+	if entityType != domain.EntityTypeInvoice && entityType != domain.EntityTypeCustomer {
+		return domain.AssignmentDetailResponse{}, domain.ErrInvalidEntityType
+	}
+
+	id, err := parseSnowflakeID(entityID)
+	if err != nil {
+		return domain.AssignmentDetailResponse{}, domain.ErrInvalidEntityID
+	}
+
+	row, err := s.repo.LoadAssignment(ctx, snowflake.ID(orgID), entityType, id)
+	if err != nil {
+		return domain.AssignmentDetailResponse{}, err
+	}
+	actions, err := s.repo.ListEntityActions(ctx, snowflake.ID(orgID), entityType, id)
+	if err != nil {
+		return domain.AssignmentDetailResponse{}, err
+	}
+	if row == nil && len(actions) == 0 {
+		return domain.AssignmentDetailResponse{}, domain.ErrAssignmentNotFound
+	}
+
+	resp := domain.AssignmentDetailResponse{
+		Actions: make([]domain.BillingAction, 0, len(actions)),
+	}
+	if row != nil {
+		assignment := assignmentFields(
+			sql.NullString{String: row.AssignedTo, Valid: true},
+			row.AssignedAt.UTC(),
+			sql.NullTime{Time: row.AssignmentExpiresAt, Valid: true},
+			row.Status,
+			row.ReleasedAt,
+			row.ReleasedBy,
+			row.ReleaseReason,
+			row.BreachedAt,
+			row.BreachLevel,
+			row.LastActionAt,
+			s.clock.Now(ctx).UTC(),
+		)
+		assignment.EntityType = entityType
+		assignment.EntityID = id.String()
+		resp.Assignment = &assignment
+	}
+	for _, action := range actions {
+		resp.Actions = append(resp.Actions, domain.BillingAction{
+			ActionID:    action.ID.String(),
+			ActionType:  action.ActionType,
+			ActorType:   strings.TrimSpace(action.ActorType.String),
+			ActorID:     strings.TrimSpace(action.ActorID.String),
+			Metadata:    action.Metadata,
+			CreatedAt:   action.CreatedAt.UTC(),
+			EmailStatus: strings.TrimSpace(action.EmailStatus.String),
+		})
+	}
+
+	return resp, nil
+}
+
 func (s *Service) RecordFollowUp(ctx context.Context, req domain.RecordFollowUpRequest) error {
 	orgID, ok := orgcontext.OrgIDFromContext(ctx)
 	if !ok || orgID == 0 {
 		return domain.ErrInvalidOrganization
 	}
 
+	now := s.clock.Now(ctx).UTC()
+
+	if strings.TrimSpace(req.AssignmentID) == "" && strings.TrimSpace(req.InvoiceID) != "" {
+		invoiceID, err := parseSnowflakeID(strings.TrimSpace(req.InvoiceID))
+		if err != nil {
+			return domain.ErrInvalidEntityID
+		}
+		action := s.buildFollowUpAction(ctx, orgID, domain.EntityTypeInvoice, invoiceID, req, nil, now)
+		return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			repo := s.repo.WithTx(tx)
+			inserted, err := repo.InsertBillingAction(ctx, action)
+			if err != nil || !inserted {
+				return err
+			}
+			return s.recordFollowUpEmail(ctx, repo, snowflake.ID(orgID), action.ID, req, now)
+		})
+	}
+
 	assignmentID, err := parseSnowflakeID(req.AssignmentID)
 	if err != nil {
 		return domain.ErrInvalidEntityID // Or a specific error for assignment ID
 	}
 
-	now := s.clock.Now(ctx).UTC()
-
 	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// This is a simplified version of what follow_up.go might have done
 		// Usually it records an action and potentially updates the assignment's last action time
@@ -533,27 +800,18 @@ func (s *Service) RecordFollowUp(ctx context.Context, req domain.RecordFollowUpR
 			return err
 		}
 
-		actionID := s.genID.Generate()
-		bucket := now.Truncate(24 * time.Hour)
-		_, actorID := auditcontext.ActorFromContext(ctx)
-
-		if _, err := s.repo.WithTx(tx).InsertBillingAction(ctx, domain.BillingActionRecord{
-			ID:           actionID,
-			OrgID:        snowflake.ID(orgID),
-			EntityType:   assignment.EntityType,
-			EntityID:     assignment.EntityID,
-			ActionType:   domain.ActionTypeFollowUp,
-			ActionBucket: bucket,
-			Metadata: datatypes.JSONMap{
-				"assignment_id":  assignmentID.String(),
-				"email_provider": req.EmailProvider,
-			},
-			ActorType: "user",
-			ActorID:   actorID,
-			CreatedAt: now,
-		}); err != nil {
+		metadata := datatypes.JSONMap{"assignment_id": assignmentID.String()}
+		repo := s.repo.WithTx(tx)
+		action := s.buildFollowUpAction(ctx, orgID, assignment.EntityType, assignment.EntityID, req, metadata, now)
+		inserted, err := repo.InsertBillingAction(ctx, action)
+		if err != nil {
 			return err
 		}
+		if inserted {
+			if err := s.recordFollowUpEmail(ctx, repo, snowflake.ID(orgID), action.ID, req, now); err != nil {
+				return err
+			}
+		}
 
 		// Update last action at
 		return tx.Model(&domain.BillingAssignmentRecord{}).
@@ -566,3 +824,139 @@ func (s *Service) RecordFollowUp(ctx context.Context, req domain.RecordFollowUpR
 
 	return err
 }
+
+func (s *Service) buildFollowUpAction(
+	ctx context.Context,
+	orgID snowflake.ID,
+	entityType string,
+	entityID snowflake.ID,
+	req domain.RecordFollowUpRequest,
+	metadata datatypes.JSONMap,
+	now time.Time,
+) domain.BillingActionRecord {
+	if metadata == nil {
+		metadata = datatypes.JSONMap{}
+	}
+	for key, value := range req.Metadata {
+		metadata[key] = value
+	}
+	metadata["email_provider"] = req.EmailProvider
+	if messageID := strings.TrimSpace(req.EmailMessageID); messageID != "" {
+		metadata["email_message_id"] = messageID
+	}
+
+	actorType, actorID := auditcontext.ActorFromContext(ctx)
+	if actorType == "" {
+		actorType = "user"
+	}
+
+	// Keyed follow-ups may repeat within a day, so they take the exact time
+	// as their bucket.
+	idempotencyKey := normalizeIdempotencyKey(req.IdempotencyKey)
+	bucket := now.Truncate(24 * time.Hour)
+	if idempotencyKey != "" {
+		bucket = now
+	}
+
+	return domain.BillingActionRecord{
+		ID:             s.genID.Generate(),
+		OrgID:          orgID,
+		EntityType:     entityType,
+		EntityID:       entityID,
+		ActionType:     domain.ActionTypeFollowUp,
+		ActionBucket:   bucket,
+		IdempotencyKey: idempotencyKey,
+		Metadata:       metadata,
+		ActorType:      actorType,
+		ActorID:        actorID,
+		CreatedAt:      now,
+	}
+}
+
+// recordFollowUpEmail logs an email Railzway sent for a follow-up action so
+// provider delivery events can update the action's email status. Follow-ups
+// sent from the collector's own client have no message ID and are skipped.
+func (s *Service) recordFollowUpEmail(
+	ctx context.Context,
+	repo domain.Repository,
+	orgID snowflake.ID,
+	actionID snowflake.ID,
+	req domain.RecordFollowUpRequest,
+	now time.Time,
+) error {
+	messageID := strings.TrimSpace(req.EmailMessageID)
+	if messageID == "" {
+		return nil
+	}
+
+	var template sql.NullString
+	if name := strings.TrimSpace(req.EmailTemplate); name != "" {
+		template = sql.NullString{String: name, Valid: true}
+	}
+	if err := repo.InsertEmailLog(ctx, domain.EmailLogRecord{
+		ID:                s.genID.Generate(),
+		OrgID:             orgID,
+		Provider:          req.EmailProvider,
+		ProviderMessageID: messageID,
+		Recipient:         strings.TrimSpace(req.EmailRecipient),
+		Template:          template,
+		BillingActionID:   &actionID,
+		Status:            email.DeliveryStatusSent,
+		StatusUpdatedAt:   now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}); err != nil {
+		return err
+	}
+	return repo.UpdateActionEmailStatus(ctx, actionID, email.DeliveryStatusSent, now)
+}
+
+// UpdateEmailDeliveryStatus applies a provider delivery event to the email it
+// refers to and to the follow-up action that sent it. Providers do not
+// guarantee ordering, so a status never moves back: a late "delivered" does
+// not hide an earlier bounce or complaint.
+func (s *Service) UpdateEmailDeliveryStatus(ctx context.Context, update domain.EmailDeliveryUpdate) error {
+	messageID := strings.TrimSpace(update.MessageID)
+	if messageID == "" {
+		return domain.ErrEmailLogNotFound
+	}
+	rank, ok := emailStatusRank[update.Status]
+	if !ok {
+		return domain.ErrInvalidEmailStatus
+	}
+
+	at := update.OccurredAt.UTC()
+	if update.OccurredAt.IsZero() {
+		at = s.clock.Now(ctx).UTC()
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		entry, err := repo.FindEmailLogByMessageIDForUpdate(ctx, messageID)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return domain.ErrEmailLogNotFound
+		}
+		if rank < emailStatusRank[entry.Status] {
+			return nil
+		}
+
+		if err := repo.UpdateEmailLogStatus(ctx, entry.ID, update.Status, strings.TrimSpace(update.Reason), at); err != nil {
+			return err
+		}
+		if entry.BillingActionID == nil {
+			return nil
+		}
+		return repo.UpdateActionEmailStatus(ctx, *entry.BillingActionID, update.Status, at)
+	})
+}
+
+var emailStatusRank = map[string]int{
+	email.DeliveryStatusSent:       0,
+	email.DeliveryStatusDelivered:  1,
+	email.DeliveryStatusBounced:    2,
+	email.DeliveryStatusFailed:     2,
+	email.DeliveryStatusComplained: 3,
+}
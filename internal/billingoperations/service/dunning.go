@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	auditcontext "github.com/railzwaylabs/railzway/internal/auditcontext"
+	"github.com/railzwaylabs/railzway/internal/billingoperations/domain"
+	"github.com/railzwaylabs/railzway/internal/currency"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/providers/email"
+	"go.uber.org/zap"
+)
+
+const dunningEmailTemplate = "invoice_overdue"
+
+// SendDunningReminders emails customers about overdue invoices that nobody
+// has claimed. Each invoice gets one reminder per configured dunning day,
+// recorded as a follow-up action; an invoice that skipped a step while the
+// scheduler was down only gets the latest one. It returns the number of
+// reminders sent.
+func (s *Service) SendDunningReminders(ctx context.Context, limit int) (int, error) {
+	if s.email == nil || limit <= 0 {
+		return 0, nil
+	}
+
+	now := s.clock.Now(ctx).UTC()
+	rows, err := s.repo.ListDunningCandidates(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, row := range rows {
+		day, ok := dunningStepDue(parseDunningDays(row.DunningDays), row.DueAt, now)
+		if !ok {
+			continue
+		}
+
+		key := fmt.Sprintf("dunning:%s:%d", row.InvoiceID.String(), day)
+		existing, err := s.repo.FindActionByIdempotencyKey(ctx, row.OrgID, key)
+		if err != nil {
+			return sent, err
+		}
+		if existing != nil {
+			continue
+		}
+
+		if strings.TrimSpace(row.CustomerEmail) == "" {
+			s.log.Warn("dunning skipped, customer email missing",
+				zap.String("invoice_id", row.InvoiceID.String()),
+			)
+			continue
+		}
+
+		// A failed send is not recorded, so the next run tries again.
+		messageID := email.NewMessageID(s.genID.Generate().String())
+		if err := s.sendDunningEmail(ctx, row, messageID, now); err != nil {
+			s.log.Warn("failed to send dunning email",
+				zap.Error(err),
+				zap.String("invoice_id", row.InvoiceID.String()),
+				zap.Int("dunning_day", day),
+			)
+			continue
+		}
+		sent++
+
+		orgCtx := orgcontext.WithOrgID(ctx, int64(row.OrgID))
+		orgCtx = auditcontext.WithActor(orgCtx, "system", "dunning")
+		if err := s.RecordFollowUp(orgCtx, domain.RecordFollowUpRequest{
+			InvoiceID:      row.InvoiceID.String(),
+			EmailProvider:  "default",
+			IdempotencyKey: key,
+			Metadata: map[string]any{
+				"dunning_day": day,
+			},
+			EmailMessageID: messageID,
+			EmailRecipient: row.CustomerEmail,
+			EmailTemplate:  dunningEmailTemplate,
+		}); err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+func (s *Service) sendDunningEmail(ctx context.Context, row domain.DunningCandidateRow, messageID string, now time.Time) error {
+	supportEmail := strings.TrimSpace(row.SupportEmail.String)
+	if supportEmail == "" {
+		supportEmail = "support@railzway.com"
+	}
+
+	data := struct {
+		OrgName         string
+		Total           string
+		DueDate         string
+		DaysOverdue     int
+		PaymentLink     string
+		InvoiceNumber   string
+		OrgContactEmail string
+	}{
+		OrgName:         row.OrgName,
+		Total:           currency.Format(row.TotalAmount, row.Currency),
+		DueDate:         row.DueAt.Format("January 2, 2006"),
+		DaysOverdue:     int(now.Sub(row.DueAt) / (24 * time.Hour)),
+		InvoiceNumber:   row.InvoiceNumber,
+		OrgContactEmail: supportEmail,
+	}
+	if row.TokenHash.Valid && row.TokenHash.String != "" {
+		data.PaymentLink = fmt.Sprintf("%s/%s/%s", s.publicURL, row.OrgID, row.TokenHash.String)
+	}
+
+	msg := email.EmailMessage{
+		To:         []string{row.CustomerEmail},
+		SenderName: row.OrgName,
+		ReplyTo:    supportEmail,
+		Subject:    fmt.Sprintf("Reminder: invoice #%s from %s is overdue", row.InvoiceNumber, row.OrgName),
+		MessageID:  messageID,
+	}
+	return s.email.SendTemplate(ctx, msg, dunningEmailTemplate, data)
+}
+
+// dunningStepDue returns the latest dunning day reached by an invoice due at
+// dueAt.
+func dunningStepDue(days []int, dueAt time.Time, now time.Time) (int, bool) {
+	daysOverdue := int(now.Sub(dueAt) / (24 * time.Hour))
+	step, ok := 0, false
+	for _, day := range days {
+		if day > daysOverdue {
+			break
+		}
+		step, ok = day, true
+	}
+	return step, ok
+}
+
+func parseDunningDays(raw []byte) []int {
+	var days []int
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &days); err != nil {
+			days = nil
+		}
+	}
+	if len(days) == 0 {
+		return organizationdomain.DefaultDunningDays
+	}
+	sort.Ints(days)
+	return days
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/billingoperations/domain"
+	"github.com/railzwaylabs/railzway/internal/config"
+	"github.com/railzwaylabs/railzway/internal/providers/email"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type recordingEmailProvider struct {
+	email.NoOpProvider
+	data []any
+}
+
+func (p *recordingEmailProvider) SendTemplate(ctx context.Context, msg email.EmailMessage, templateName string, data interface{}) error {
+	p.data = append(p.data, data)
+	return nil
+}
+
+func TestDunningStepDue(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	days := []int{1, 7, 14}
+
+	cases := []struct {
+		name    string
+		dueAt   time.Time
+		wantDay int
+		wantOK  bool
+	}{
+		{"not a full day overdue", now.Add(-23 * time.Hour), 0, false},
+		{"first reminder", now.Add(-25 * time.Hour), 1, true},
+		{"between steps", now.Add(-5 * 24 * time.Hour), 1, true},
+		{"second reminder", now.Add(-7 * 24 * time.Hour), 7, true},
+		{"missed steps only send the latest", now.Add(-30 * 24 * time.Hour), 14, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			day, ok := dunningStepDue(days, tc.dueAt, now)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantDay, day)
+		})
+	}
+}
+
+func TestParseDunningDays(t *testing.T) {
+	assert.Equal(t, []int{3, 10}, parseDunningDays([]byte(`[10, 3]`)))
+	assert.Equal(t, []int{1, 7, 14}, parseDunningDays(nil))
+	assert.Equal(t, []int{1, 7, 14}, parseDunningDays([]byte(`not json`)))
+}
+
+func TestSendDunningEmail_PaymentLinkUsesPublicURL(t *testing.T) {
+	provider := &recordingEmailProvider{}
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(Params{
+		Log:   zap.NewNop(),
+		GenID: node,
+		Email: provider,
+		Cfg:   config.Config{PublicURL: "https://billing.example.com/"},
+	}).(*Service)
+
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	row := domain.DunningCandidateRow{
+		InvoiceID:     node.Generate(),
+		OrgID:         node.Generate(),
+		InvoiceNumber: "INV-9",
+		CustomerEmail: "billing@acme.test",
+		TotalAmount:   150000,
+		Currency:      "IDR",
+		DueAt:         now.Add(-8 * 24 * time.Hour),
+		OrgName:       "Acme",
+		TokenHash:     sql.NullString{String: "tok_123", Valid: true},
+	}
+	require.NoError(t, svc.sendDunningEmail(context.Background(), row, "msg-1", now))
+
+	require.Len(t, provider.data, 1)
+	data := reflect.ValueOf(provider.data[0])
+	assert.Equal(t, "https://billing.example.com/"+row.OrgID.String()+"/tok_123", data.FieldByName("PaymentLink").String())
+	assert.Equal(t, "150000 IDR", data.FieldByName("Total").String())
+}
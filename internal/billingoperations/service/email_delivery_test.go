@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/billingoperations/domain"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	"github.com/railzwaylabs/railzway/internal/config"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/providers/email"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestFollowUpEmailDeliveryStatus(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE billing_operation_actions (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		entity_type TEXT NOT NULL,
+		entity_id BIGINT NOT NULL,
+		action_type TEXT NOT NULL,
+		action_bucket TIMESTAMP NOT NULL,
+		idempotency_key TEXT,
+		metadata TEXT,
+		actor_type TEXT,
+		actor_id TEXT,
+		email_status TEXT,
+		email_status_at TIMESTAMP,
+		created_at TIMESTAMP NOT NULL
+	)`).Error)
+	require.NoError(t, db.AutoMigrate(&domain.EmailLogRecord{}))
+
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(Params{
+		DB:    db,
+		Log:   zap.NewNop(),
+		Clock: clock.SystemClock{},
+		GenID: node,
+		Cfg:   config.Config{},
+	})
+
+	orgID := node.Generate()
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	messageID := email.NewMessageID(node.Generate().String())
+	require.NoError(t, svc.RecordFollowUp(ctx, domain.RecordFollowUpRequest{
+		InvoiceID:      node.Generate().String(),
+		EmailProvider:  "default",
+		IdempotencyKey: "dunning:test:1",
+		EmailMessageID: messageID,
+		EmailRecipient: "billing@acme.test",
+		EmailTemplate:  dunningEmailTemplate,
+	}))
+
+	statuses := func() (string, string) {
+		var logStatus, actionStatus string
+		require.NoError(t, db.Raw(`SELECT status FROM email_logs WHERE provider_message_id = ?`, messageID).Scan(&logStatus).Error)
+		require.NoError(t, db.Raw(`SELECT email_status FROM billing_operation_actions WHERE idempotency_key = ?`, "dunning:test:1").Scan(&actionStatus).Error)
+		return logStatus, actionStatus
+	}
+	update := func(status string) error {
+		return svc.UpdateEmailDeliveryStatus(context.Background(), domain.EmailDeliveryUpdate{
+			MessageID:  messageID,
+			Status:     status,
+			OccurredAt: time.Now(),
+		})
+	}
+
+	logStatus, actionStatus := statuses()
+	assert.Equal(t, email.DeliveryStatusSent, logStatus)
+	assert.Equal(t, email.DeliveryStatusSent, actionStatus)
+
+	require.NoError(t, update(email.DeliveryStatusBounced))
+	logStatus, actionStatus = statuses()
+	assert.Equal(t, email.DeliveryStatusBounced, logStatus)
+	assert.Equal(t, email.DeliveryStatusBounced, actionStatus)
+
+	// A delivery event arriving after the bounce must not hide it.
+	require.NoError(t, update(email.DeliveryStatusDelivered))
+	_, actionStatus = statuses()
+	assert.Equal(t, email.DeliveryStatusBounced, actionStatus)
+
+	err = svc.UpdateEmailDeliveryStatus(context.Background(), domain.EmailDeliveryUpdate{
+		MessageID: "unknown@railzway",
+		Status:    email.DeliveryStatusDelivered,
+	})
+	assert.ErrorIs(t, err, domain.ErrEmailLogNotFound)
+}
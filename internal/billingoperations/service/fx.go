@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+)
+
+// ConvertToBaseCurrency converts amount, in minor units of currency, into
+// baseCurrency at the latest fx_rates rate in effect at asOf. It reports false
+// when the org has no such rate; amounts already in baseCurrency are returned
+// unchanged.
+func (s *Service) ConvertToBaseCurrency(
+	ctx context.Context,
+	orgID snowflake.ID,
+	amount int64,
+	currency string,
+	baseCurrency string,
+	asOf time.Time,
+) (int64, bool, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	baseCurrency = strings.ToUpper(strings.TrimSpace(baseCurrency))
+	if currency == baseCurrency {
+		return amount, true, nil
+	}
+
+	rate, ok, err := s.repo.FindFXRate(ctx, orgID, currency, baseCurrency, asOf)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	return int64(math.Round(float64(amount) * rate)), true, nil
+}
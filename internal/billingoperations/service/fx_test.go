@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/billingoperations/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestConvertToBaseCurrency(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE IF NOT EXISTS fx_rates (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		from_currency TEXT NOT NULL,
+		to_currency TEXT NOT NULL,
+		rate NUMERIC NOT NULL,
+		effective_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	)`).Error)
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	otherOrgID := node.Generate()
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	for _, rate := range []struct {
+		orgID       snowflake.ID
+		rate        float64
+		effectiveAt time.Time
+	}{
+		{orgID, 1.10, march},
+		{orgID, 1.20, april},
+		{otherOrgID, 2.00, march},
+	} {
+		require.NoError(t, db.Exec(
+			`INSERT INTO fx_rates (id, org_id, from_currency, to_currency, rate, effective_at, created_at)
+			 VALUES (?, ?, 'EUR', 'USD', ?, ?, ?)`,
+			node.Generate(), rate.orgID, rate.rate, rate.effectiveAt, rate.effectiveAt,
+		).Error)
+	}
+
+	svc := &Service{repo: repository.NewRepository(db)}
+	ctx := context.Background()
+
+	amount, ok, err := svc.ConvertToBaseCurrency(ctx, orgID, 10000, "EUR", "USD", march.AddDate(0, 0, 15))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(11000), amount)
+
+	// Rates are taken as of the given date, not the latest one.
+	amount, ok, err = svc.ConvertToBaseCurrency(ctx, orgID, 10000, "eur", "USD", april.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(12000), amount)
+
+	_, ok, err = svc.ConvertToBaseCurrency(ctx, orgID, 10000, "EUR", "USD", march.AddDate(0, 0, -1))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	amount, ok, err = svc.ConvertToBaseCurrency(ctx, orgID, 10000, "USD", "USD", march)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(10000), amount)
+}
@@ -50,6 +50,8 @@ func TestAssignmentLifecycle(t *testing.T) {
 		released_at TIMESTAMP,
 		released_by TEXT,
 		release_reason TEXT,
+		breached_at TIMESTAMP,
+		breach_level TEXT,
 		last_action_at TIMESTAMP,
 
 		snapshot_metadata TEXT,
@@ -68,6 +70,7 @@ func TestAssignmentLifecycle(t *testing.T) {
 		metadata TEXT,
 		actor_type TEXT,
 		actor_id TEXT,
+		email_status TEXT,
 		created_at TIMESTAMP NOT NULL
 	)`)
 
@@ -184,4 +187,86 @@ func TestAssignmentLifecycle(t *testing.T) {
 		}
 		assert.Equal(t, "Escalated", assignment.ReleaseReason.String)
 	})
+	t.Run("Bulk Claim - Skips Conflicts", func(t *testing.T) {
+		mockAudit.On("AuditLog", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "billing_operations.assignment.claimed", mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(3)
+
+		held := node.Generate()
+		_, err := svc.ClaimAssignment(ctx, domain.ClaimAssignmentRequest{EntityType: entityType, EntityID: held.String(), AssignedTo: "agent_008"})
+		assert.NoError(t, err)
+
+		free := node.Generate()
+		resp, err := svc.BulkClaimAssignments(ctx, []domain.ClaimAssignmentRequest{
+			{EntityType: entityType, EntityID: free.String(), AssignedTo: "agent_007"},
+			{EntityType: entityType, EntityID: held.String(), AssignedTo: "agent_007"},
+			{EntityType: "subscription", EntityID: free.String(), AssignedTo: "agent_007"},
+			{EntityType: entityType, EntityID: entityID.String(), AssignedTo: "agent_007"},
+		})
+		assert.NoError(t, err)
+		if !assert.Len(t, resp.Results, 4) {
+			return
+		}
+		assert.Equal(t, domain.BulkStatusClaimed, resp.Results[0].Status)
+		assert.Equal(t, "agent_007", resp.Results[0].Assignment.AssignedTo)
+		assert.Equal(t, domain.BulkStatusConflict, resp.Results[1].Status)
+		assert.Equal(t, domain.ErrAssignmentConflict.Error(), resp.Results[1].Error)
+		assert.Equal(t, domain.BulkStatusInvalid, resp.Results[2].Status)
+		assert.Equal(t, domain.ErrInvalidEntityType.Error(), resp.Results[2].Error)
+		assert.Equal(t, domain.BulkStatusClaimed, resp.Results[3].Status)
+
+		var assignment domain.BillingAssignmentRecord
+		err = db.Where("org_id = ? AND entity_type = ? AND entity_id = ?", orgID, entityType, held).First(&assignment).Error
+		assert.NoError(t, err)
+		assert.Equal(t, "agent_008", assignment.AssignedTo)
+
+		_, err = svc.BulkClaimAssignments(ctx, nil)
+		assert.Equal(t, domain.ErrInvalidBulkSize, err)
+	})
+
+	t.Run("Bulk Release - Skips Unassigned", func(t *testing.T) {
+		mockAudit.On("AuditLog", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "billing_operations.assignment.released", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+		resp, err := svc.BulkReleaseAssignments(ctx, []domain.ReleaseAssignmentRequest{
+			{EntityType: entityType, EntityID: entityID.String(), Reason: "Shift ended"},
+			{EntityType: entityType, EntityID: node.Generate().String(), Reason: "Shift ended"},
+		})
+		assert.NoError(t, err)
+		if !assert.Len(t, resp.Results, 2) {
+			return
+		}
+		assert.Equal(t, domain.BulkStatusReleased, resp.Results[0].Status)
+		assert.Equal(t, domain.BulkStatusSkipped, resp.Results[1].Status)
+
+		var assignment domain.BillingAssignmentRecord
+		err = db.Where("org_id = ? AND entity_type = ? AND entity_id = ?", orgID, entityType, entityID).First(&assignment).Error
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AssignmentStatusReleased, assignment.Status)
+		assert.Equal(t, "user_123", assignment.ReleasedBy.String)
+	})
+
+	t.Run("Assignment Detail - Timeline", func(t *testing.T) {
+		resp, err := svc.GetAssignmentDetail(ctx, entityType, entityID.String())
+		assert.NoError(t, err)
+		if assert.NotNil(t, resp.Assignment) {
+			assert.Equal(t, entityID.String(), resp.Assignment.EntityID)
+			assert.Equal(t, domain.AssignmentStatusReleased, resp.Assignment.Status)
+			assert.Equal(t, "user_123", resp.Assignment.ReleasedBy)
+		}
+		if assert.Len(t, resp.Actions, 6) {
+			for i, action := range resp.Actions {
+				want := domain.ActionTypeClaim
+				if i%2 == 1 {
+					want = domain.ActionTypeRelease
+				}
+				assert.Equal(t, want, action.ActionType)
+			}
+			assert.Equal(t, "agent_007", resp.Actions[0].ActorID)
+			assert.Equal(t, "agent_008", resp.Actions[2].ActorID)
+		}
+
+		_, err = svc.GetAssignmentDetail(ctx, entityType, node.Generate().String())
+		assert.Equal(t, domain.ErrAssignmentNotFound, err)
+
+		_, err = svc.GetAssignmentDetail(ctx, "subscription", entityID.String())
+		assert.Equal(t, domain.ErrInvalidEntityType, err)
+	})
 }
@@ -3,11 +3,15 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
 	"github.com/railzwaylabs/railzway/internal/billingoperations/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 	"go.uber.org/zap"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
@@ -19,6 +23,11 @@ func (s *Service) CalculatePerformance(ctx context.Context, userID string, start
 		return domain.FinOpsScoreSnapshot{}, domain.ErrInvalidOrganization
 	}
 
+	weights, err := s.repo.FetchScoringWeights(ctx, snowflake.ID(orgID))
+	if err != nil {
+		return domain.FinOpsScoreSnapshot{}, err
+	}
+
 	assignments, err := s.repo.ListBillingAssignmentsForPerformance(ctx, snowflake.ID(orgID), userID, start, end)
 	if err != nil {
 		return domain.FinOpsScoreSnapshot{}, err
@@ -35,7 +44,7 @@ func (s *Service) CalculatePerformance(ctx context.Context, userID string, start
 			PeriodType:     domain.PeriodTypeDaily,
 			PeriodStart:    start,
 			PeriodEnd:      end,
-			ScoringVersion: domain.ScoringVersionV1EqualWeight,
+			ScoringVersion: scoringVersion(weights),
 			Metrics:        metrics,
 			Scores:         domain.PerformanceScores{},
 		}, nil
@@ -103,6 +112,24 @@ func (s *Service) CalculatePerformance(ctx context.Context, userID string, start
 	metrics.CompletionRatio = float64(metrics.TotalResolved) / float64(metrics.TotalAssigned)
 	metrics.EscalationRate = float64(metrics.TotalEscalated) / float64(metrics.TotalAssigned)
 
+	scores := scorePerformance(metrics, weights)
+
+	return domain.FinOpsScoreSnapshot{
+		OrgID:          snowflake.ID(orgID).String(),
+		UserID:         userID,
+		PeriodType:     domain.PeriodTypeDaily,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		ScoringVersion: scoringVersion(weights),
+		Metrics:        metrics,
+		Scores:         scores,
+	}, nil
+}
+
+// scorePerformance derives the dimension scores from raw metrics. Daily
+// snapshots and their weekly and monthly rollups are scored the same way.
+// Without weights the total is the plain average of the dimensions.
+func scorePerformance(metrics domain.PerformanceMetrics, weights *organizationdomain.ScoringWeights) domain.PerformanceScores {
 	scores := domain.PerformanceScores{}
 
 	if metrics.AvgResponseMS > 0 {
@@ -138,64 +165,74 @@ func (s *Service) CalculatePerformance(ctx context.Context, userID string, start
 		scores.Effectiveness = 0
 	}
 
-	scores.Total = (scores.Responsiveness + scores.Completion + scores.Risk + scores.Effectiveness) / 4
+	if weights == nil {
+		scores.Total = (scores.Responsiveness + scores.Completion + scores.Risk + scores.Effectiveness) / 4
+		return scores
+	}
 
-	return domain.FinOpsScoreSnapshot{
-		OrgID:          snowflake.ID(orgID).String(),
-		UserID:         userID,
-		PeriodType:     domain.PeriodTypeDaily,
-		PeriodStart:    start,
-		PeriodEnd:      end,
-		ScoringVersion: domain.ScoringVersionV1EqualWeight,
-		Metrics:        metrics,
-		Scores:         scores,
-	}, nil
+	scores.Total = int(math.Round(
+		weights.Responsiveness*float64(scores.Responsiveness) +
+			weights.Completion*float64(scores.Completion) +
+			weights.Risk*float64(scores.Risk) +
+			weights.Effectiveness*float64(scores.Effectiveness),
+	))
+	scores.Weights = weights
+	return scores
 }
 
-func (s *Service) GetPerformanceHistory(ctx context.Context, userID string, limit int) ([]domain.FinOpsScoreSnapshot, error) {
+// scoringVersion names how a total scored with weights was computed, so a
+// snapshot stays interpretable after the org changes its weights.
+func scoringVersion(weights *organizationdomain.ScoringWeights) string {
+	if weights == nil {
+		return domain.ScoringVersionV1EqualWeight
+	}
+	return domain.ScoringVersionV2Weighted
+}
+
+// GetPerformanceHistory pages through the user's persisted snapshots, newest
+// period first, optionally limited to one period type.
+func (s *Service) GetPerformanceHistory(ctx context.Context, userID string, req domain.PerformanceHistoryRequest) (domain.PerformanceHistoryResponse, error) {
 	orgID, ok := orgcontext.OrgIDFromContext(ctx)
 	if !ok || orgID == 0 {
-		return nil, domain.ErrInvalidOrganization
+		return domain.PerformanceHistoryResponse{}, domain.ErrInvalidOrganization
 	}
-	if limit <= 0 {
-		limit = 30
+	if userID == "" {
+		return domain.PerformanceHistoryResponse{}, domain.ErrInvalidAssignee
 	}
 
-	type outputRow struct {
-		OrgID       snowflake.ID
-		UserID      string
-		PeriodStart time.Time
-		PeriodEnd   time.Time
-		Metrics     datatypes.JSON
-		Scores      datatypes.JSON
+	periodType := strings.ToLower(strings.TrimSpace(req.PeriodType))
+	switch periodType {
+	case "", domain.PeriodTypeDaily, domain.PeriodTypeWeekly, domain.PeriodTypeMonthly:
+	default:
+		return domain.PerformanceHistoryResponse{}, domain.ErrInvalidPeriodType
 	}
 
-	var rows []outputRow
-	if err := s.db.WithContext(ctx).Table("finops_performance_snapshots").
-		Where("org_id = ? AND user_id = ?", orgID, userID).
-		Order("period_start DESC").
-		Limit(limit).
-		Find(&rows).Error; err != nil {
-		return nil, err
+	pageSize := pagination.ClampPageSize(req.PageSize)
+
+	snapshots, err := s.repo.ListSnapshotHistory(ctx, snowflake.ID(orgID), userID, periodType, pagination.Pagination{
+		PageToken: strings.TrimSpace(req.PageToken),
+		PageSize:  pageSize,
+	})
+	if err != nil {
+		return domain.PerformanceHistoryResponse{}, err
 	}
 
-	snapshots := make([]domain.FinOpsScoreSnapshot, len(rows))
-	for i, r := range rows {
-		var m domain.PerformanceMetrics
-		var sc domain.PerformanceScores
-		_ = json.Unmarshal(r.Metrics, &m)
-		_ = json.Unmarshal(r.Scores, &sc)
-
-		snapshots[i] = domain.FinOpsScoreSnapshot{
-			OrgID:       r.OrgID.String(),
-			UserID:      r.UserID,
-			PeriodStart: r.PeriodStart,
-			PeriodEnd:   r.PeriodEnd,
-			Metrics:     m,
-			Scores:      sc,
+	resp := domain.PerformanceHistoryResponse{Snapshots: snapshots}
+	resp.PageSize = pageSize
+	if len(snapshots) > pageSize {
+		resp.Snapshots = snapshots[:pageSize]
+		last := resp.Snapshots[pageSize-1]
+		token, err := pagination.EncodeCursor(pagination.Cursor{
+			ID:        last.ID,
+			CreatedAt: last.PeriodStart.UTC().Format(time.RFC3339Nano),
+		})
+		if err != nil {
+			return domain.PerformanceHistoryResponse{}, err
 		}
+		resp.HasMore = true
+		resp.NextPageToken = token
 	}
-	return snapshots, nil
+	return resp, nil
 }
 
 func (s *Service) AggregateDailyPerformance(ctx context.Context) error {
@@ -228,32 +265,132 @@ func (s *Service) AggregateDailyPerformance(ctx context.Context) error {
 			continue
 		}
 
-		err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-			if err := tx.Exec(`
-					DELETE FROM finops_performance_snapshots 
-					WHERE org_id = ? AND user_id = ? AND period_type = ? AND period_start = ?
-				`, uo.OrgID, uo.AssignedTo, snapshot.PeriodType, start).Error; err != nil {
-				return err
-			}
+		if err := s.persistSnapshot(ctx, uo.OrgID, snapshot, now); err != nil {
+			s.log.Error("failed to persist snapshot", zap.Error(err))
+		}
+	}
+	return nil
+}
 
-			return tx.Exec(`
-					INSERT INTO finops_performance_snapshots 
-					(id, org_id, user_id, period_type, period_start, period_end, scoring_version, metrics, scores, total_score, created_at, updated_at)
-					VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-				`, s.genID.Generate(), uo.OrgID, uo.AssignedTo, snapshot.PeriodType, start, end, snapshot.ScoringVersion,
-				datatypes.JSON(toJson(snapshot.Metrics)),
-				datatypes.JSON(toJson(snapshot.Scores)),
-				snapshot.Scores.Total,
-				now, now).Error
-		})
+// AggregateWeeklyPerformance rolls the daily snapshots of the last completed
+// ISO week (Monday to Monday, UTC) into weekly snapshots.
+func (s *Service) AggregateWeeklyPerformance(ctx context.Context) error {
+	now := s.clock.Now(ctx).UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	daysSinceMonday := (int(today.Weekday()) + 6) % 7
+	end := today.AddDate(0, 0, -daysSinceMonday)
+	return s.rollupPerformance(ctx, domain.PeriodTypeWeekly, end.AddDate(0, 0, -7), end, now)
+}
+
+// AggregateMonthlyPerformance rolls the daily snapshots of the last completed
+// calendar month (UTC) into monthly snapshots.
+func (s *Service) AggregateMonthlyPerformance(ctx context.Context) error {
+	now := s.clock.Now(ctx).UTC()
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return s.rollupPerformance(ctx, domain.PeriodTypeMonthly, end.AddDate(0, -1, 0), end, now)
+}
 
+// rollupPerformance sums each user's daily metrics in [start, end) and
+// rescores them as one snapshot of periodType. Re-running replaces the
+// snapshot, as the daily job does.
+func (s *Service) rollupPerformance(ctx context.Context, periodType string, start, end, now time.Time) error {
+	var orgIDs []snowflake.ID
+	if err := s.db.WithContext(ctx).Table("finops_performance_snapshots").
+		Distinct("org_id").
+		Where("period_type = ? AND period_start >= ? AND period_start < ?", domain.PeriodTypeDaily, start, end).
+		Pluck("org_id", &orgIDs).Error; err != nil {
+		return err
+	}
+
+	for _, orgID := range orgIDs {
+		orgCtx := orgcontext.WithOrgID(ctx, orgID.Int64())
+
+		weights, err := s.repo.FetchScoringWeights(ctx, orgID)
 		if err != nil {
-			s.log.Error("failed to persist snapshot", zap.Error(err))
+			s.log.Error("failed to load scoring weights", zap.Error(err), zap.String("org_id", orgID.String()))
+			continue
+		}
+
+		dailies, err := s.repo.FindSnapshotsByOrg(orgCtx, orgID, domain.PeriodTypeDaily, start, end)
+		if err != nil {
+			s.log.Error("failed to load daily snapshots", zap.Error(err), zap.String("org_id", orgID.String()), zap.String("period_type", periodType))
+			continue
+		}
+
+		byUser := make(map[string][]domain.FinOpsScoreSnapshot)
+		var users []string
+		for _, daily := range dailies {
+			if _, ok := byUser[daily.UserID]; !ok {
+				users = append(users, daily.UserID)
+			}
+			byUser[daily.UserID] = append(byUser[daily.UserID], daily)
+		}
+
+		for _, userID := range users {
+			metrics := rollupMetrics(byUser[userID])
+			snapshot := domain.FinOpsScoreSnapshot{
+				OrgID:          orgID.String(),
+				UserID:         userID,
+				PeriodType:     periodType,
+				PeriodStart:    start,
+				PeriodEnd:      end,
+				ScoringVersion: scoringVersion(weights),
+				Metrics:        metrics,
+				Scores:         scorePerformance(metrics, weights),
+			}
+			if err := s.persistSnapshot(ctx, orgID, snapshot, now); err != nil {
+				s.log.Error("failed to persist snapshot", zap.Error(err), zap.String("period_type", periodType))
+			}
 		}
 	}
 	return nil
 }
 
+// rollupMetrics sums daily metrics. The average response time is weighted by
+// resolved assignments, matching the team view.
+func rollupMetrics(dailies []domain.FinOpsScoreSnapshot) domain.PerformanceMetrics {
+	var metrics domain.PerformanceMetrics
+	var weightedResponseMS float64
+	for _, daily := range dailies {
+		metrics.TotalAssigned += daily.Metrics.TotalAssigned
+		metrics.TotalResolved += daily.Metrics.TotalResolved
+		metrics.TotalEscalated += daily.Metrics.TotalEscalated
+		metrics.ExposureHandled += daily.Metrics.ExposureHandled
+		weightedResponseMS += float64(daily.Metrics.AvgResponseMS) * float64(daily.Metrics.TotalResolved)
+	}
+	if metrics.TotalResolved > 0 {
+		metrics.AvgResponseMS = int64(weightedResponseMS / float64(metrics.TotalResolved))
+	}
+	if metrics.TotalAssigned > 0 {
+		metrics.CompletionRatio = float64(metrics.TotalResolved) / float64(metrics.TotalAssigned)
+		metrics.EscalationRate = float64(metrics.TotalEscalated) / float64(metrics.TotalAssigned)
+	}
+	return metrics
+}
+
+// persistSnapshot replaces the user's snapshot for the period, so re-running
+// an aggregation never duplicates it.
+func (s *Service) persistSnapshot(ctx context.Context, orgID snowflake.ID, snapshot domain.FinOpsScoreSnapshot, now time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+				DELETE FROM finops_performance_snapshots 
+				WHERE org_id = ? AND user_id = ? AND period_type = ? AND period_start = ?
+			`, orgID, snapshot.UserID, snapshot.PeriodType, snapshot.PeriodStart).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+				INSERT INTO finops_performance_snapshots 
+				(id, org_id, user_id, period_type, period_start, period_end, scoring_version, metrics, scores, total_score, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`, s.genID.Generate(), orgID, snapshot.UserID, snapshot.PeriodType, snapshot.PeriodStart, snapshot.PeriodEnd, snapshot.ScoringVersion,
+			datatypes.JSON(toJson(snapshot.Metrics)),
+			datatypes.JSON(toJson(snapshot.Scores)),
+			snapshot.Scores.Total,
+			now, now).Error
+	})
+}
+
 func (s *Service) GetMyPerformance(ctx context.Context, userID string, req domain.GetPerformanceRequest) (*domain.PerformanceResponse, error) {
 	orgID, ok := orgcontext.OrgIDFromContext(ctx)
 	if !ok || orgID == 0 {
@@ -287,10 +424,11 @@ func (s *Service) GetMyPerformance(ctx context.Context, userID string, req domai
 	apiSnapshots := make([]domain.APISnapshot, len(snapshots))
 	for i, s := range snapshots {
 		apiSnapshots[i] = domain.APISnapshot{
-			PeriodStart: s.PeriodStart,
-			PeriodEnd:   s.PeriodEnd,
-			TotalScore:  s.Scores.Total,
-			Scores:      s.Scores,
+			ScoringVersion: s.ScoringVersion,
+			PeriodStart:    s.PeriodStart,
+			PeriodEnd:      s.PeriodEnd,
+			TotalScore:     s.Scores.Total,
+			Scores:         s.Scores,
 			Metrics: domain.APIMetrics{
 				AvgResponseMinutes: float64(s.Metrics.AvgResponseMS) / 60000.0,
 				CompletionRatio:    s.Metrics.CompletionRatio,
@@ -300,10 +438,17 @@ func (s *Service) GetMyPerformance(ctx context.Context, userID string, req domai
 		}
 	}
 
+	// The response reports the version of the newest snapshot; each snapshot
+	// carries its own.
+	version := domain.ScoringVersionV1EqualWeight
+	if len(snapshots) > 0 {
+		version = snapshots[0].ScoringVersion
+	}
+
 	return &domain.PerformanceResponse{
 		UserID:         userID,
 		PeriodType:     req.PeriodType,
-		ScoringVersion: domain.ScoringVersionV1EqualWeight,
+		ScoringVersion: version,
 		Snapshots:      apiSnapshots,
 	}, nil
 }
@@ -45,6 +45,7 @@ func TestCalculatePerformance(t *testing.T) {
 		created_at TIMESTAMP NOT NULL,
 		metadata TEXT
 	)`)
+	db.Exec(`CREATE TABLE IF NOT EXISTS organization_billing_preferences (org_id BIGINT PRIMARY KEY, scoring_weights TEXT)`)
 
 	node, _ := snowflake.NewNode(1)
 	repo := repository.NewRepository(db)
@@ -206,6 +207,7 @@ func TestAggregateDailyPerformance_Immutability(t *testing.T) {
 
 	// Create "Actions/Assignments" tables needed for CalculatePerformance to not error out
 	db.Exec(`CREATE TABLE IF NOT EXISTS billing_operation_actions (id BIGINT, org_id BIGINT, entity_id BIGINT, action_type TEXT, created_at TIMESTAMP, metadata TEXT)`)
+	db.Exec(`CREATE TABLE IF NOT EXISTS organization_billing_preferences (org_id BIGINT PRIMARY KEY, scoring_weights TEXT)`)
 
 	err := svc.AggregateDailyPerformance(context.Background())
 	assert.NoError(t, err)
@@ -239,3 +241,89 @@ func TestAggregateDailyPerformance_Immutability(t *testing.T) {
 
 	assert.NotEqual(t, firstSnap.ID, secondSnap.ID, "Snapshot ID should change (Delete+Insert)")
 }
+
+func TestAggregateWeeklyPerformance_RollsUpAndWeightsScores(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	db.Exec(`CREATE TABLE IF NOT EXISTS finops_performance_snapshots (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		user_id TEXT NOT NULL,
+		period_type TEXT NOT NULL,
+		period_start TIMESTAMP NOT NULL,
+		period_end TIMESTAMP NOT NULL,
+		scoring_version TEXT NOT NULL,
+		metrics TEXT NOT NULL,
+		scores TEXT NOT NULL,
+		total_score INTEGER NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	)`)
+	db.Exec(`CREATE TABLE IF NOT EXISTS organization_billing_preferences (org_id BIGINT PRIMARY KEY, scoring_weights TEXT)`)
+
+	node, _ := snowflake.NewNode(1)
+	// Wednesday; the last completed week is Monday 1 June to Monday 8 June.
+	now := time.Date(2026, 6, 10, 9, 0, 0, 0, time.UTC)
+	weekStart := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	svc := &Service{
+		db:         db,
+		log:        zap.NewNop(),
+		clock:      clock.NewFakeClock(now),
+		genID:      node,
+		billingCfg: &config.BillingConfigHolder{},
+		repo:       repository.NewRepository(db),
+	}
+
+	orgID := node.Generate()
+	userID := "user_weekly"
+	daily := func(day time.Time, metrics string) {
+		db.Exec(`INSERT INTO finops_performance_snapshots 
+			(id, org_id, user_id, period_type, period_start, period_end, scoring_version, metrics, scores, total_score, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			node.Generate().Int64(), orgID.Int64(), userID, domain.PeriodTypeDaily, day, day.Add(24*time.Hour),
+			domain.ScoringVersionV1EqualWeight, metrics, `{"total": 50}`, 50, now, now)
+	}
+	daily(weekStart, `{"total_assigned": 4, "total_resolved": 2, "avg_response_ms": 1800000, "exposure_handled": 20000}`)
+	daily(weekStart.AddDate(0, 0, 6), `{"total_assigned": 6, "total_resolved": 3, "total_escalated": 1, "avg_response_ms": 7200000, "exposure_handled": 30000}`)
+	// Falls in the current week and is left out.
+	daily(weekStart.AddDate(0, 0, 7), `{"total_assigned": 100, "total_resolved": 100}`)
+
+	assert.NoError(t, svc.AggregateWeeklyPerformance(context.Background()))
+	assert.NoError(t, svc.AggregateWeeklyPerformance(context.Background()))
+
+	ctx := orgcontext.WithOrgID(context.Background(), orgID.Int64())
+	weekly, err := repository.NewRepository(db).FindSnapshotsByUser(ctx, orgID, userID, domain.PeriodTypeWeekly, weekStart, weekStart.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	if !assert.Len(t, weekly, 1) {
+		return
+	}
+	snap := weekly[0]
+	assert.True(t, snap.PeriodEnd.Equal(weekStart.AddDate(0, 0, 7)))
+	assert.Equal(t, 10, snap.Metrics.TotalAssigned)
+	assert.Equal(t, 5, snap.Metrics.TotalResolved)
+	assert.Equal(t, int64(50000), snap.Metrics.ExposureHandled)
+	// Response time is weighted by resolved assignments: (0.5h*2 + 2h*3) / 5.
+	assert.Equal(t, int64(5040000), snap.Metrics.AvgResponseMS)
+	assert.Equal(t, 50, snap.Scores.Completion)
+	assert.Equal(t, 90, snap.Scores.Risk)
+	assert.Equal(t, 75, snap.Scores.Effectiveness)
+	assert.Equal(t, 78, snap.Scores.Total)
+	assert.Equal(t, domain.ScoringVersionV1EqualWeight, snap.ScoringVersion)
+	assert.Nil(t, snap.Scores.Weights)
+
+	// Configured weights replace the plain average and stamp the new version.
+	db.Exec(`INSERT INTO organization_billing_preferences (org_id, scoring_weights) VALUES (?, ?)`,
+		orgID.Int64(), `{"responsiveness": 0.1, "completion": 0.6, "risk": 0.1, "effectiveness": 0.2}`)
+	assert.NoError(t, svc.AggregateWeeklyPerformance(context.Background()))
+
+	weekly, err = repository.NewRepository(db).FindSnapshotsByUser(ctx, orgID, userID, domain.PeriodTypeWeekly, weekStart, weekStart.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	if !assert.Len(t, weekly, 1) {
+		return
+	}
+	assert.Equal(t, domain.ScoringVersionV2Weighted, weekly[0].ScoringVersion)
+	// 0.1*98 + 0.6*50 + 0.1*90 + 0.2*75 = 63.8
+	assert.Equal(t, 64, weekly[0].Scores.Total)
+	if assert.NotNil(t, weekly[0].Scores.Weights) {
+		assert.Equal(t, 0.6, weekly[0].Scores.Weights.Completion)
+	}
+}
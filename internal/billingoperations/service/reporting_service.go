@@ -11,9 +11,10 @@ import (
 	"github.com/bwmarrin/snowflake"
 	"github.com/railzwaylabs/railzway/internal/billingoperations/domain"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 )
 
-func (s *Service) ListOverdueInvoices(ctx context.Context, limit int) (domain.OverdueInvoicesResponse, error) {
+func (s *Service) ListOverdueInvoices(ctx context.Context, limit int, filter domain.AssignmentFilter) (domain.OverdueInvoicesResponse, error) {
 	orgID, ok := orgcontext.OrgIDFromContext(ctx)
 	if !ok || orgID == 0 {
 		return domain.OverdueInvoicesResponse{}, domain.ErrInvalidOrganization
@@ -28,7 +29,7 @@ func (s *Service) ListOverdueInvoices(ctx context.Context, limit int) (domain.Ov
 	}
 
 	now := s.clock.Now(ctx).UTC()
-	rows, err := s.repo.ListOverdueInvoices(ctx, snowflake.ID(orgID), currency, now, limit)
+	rows, err := s.repo.ListOverdueInvoices(ctx, snowflake.ID(orgID), now, limit, filter)
 	if err != nil {
 		return domain.OverdueInvoicesResponse{}, err
 	}
@@ -40,6 +41,11 @@ func (s *Service) ListOverdueInvoices(ctx context.Context, limit int) (domain.Ov
 			invoiceNumber = row.InvoiceID.String()
 		}
 
+		convertedAmount, converted, err := s.ConvertToBaseCurrency(ctx, snowflake.ID(orgID), row.AmountDue, row.Currency, currency, row.DueAt)
+		if err != nil {
+			return domain.OverdueInvoicesResponse{}, err
+		}
+
 		daysOverdue := int(now.Sub(row.DueAt).Hours() / 24)
 		if daysOverdue < 0 {
 			daysOverdue = 0
@@ -86,16 +92,18 @@ func (s *Service) ListOverdueInvoices(ctx context.Context, limit int) (domain.Ov
 		}
 
 		invoices = append(invoices, domain.OverdueInvoice{
-			InvoiceID:     row.InvoiceID.String(),
-			InvoiceNumber: invoiceNumber,
-			CustomerID:    row.CustomerID.String(),
-			CustomerName:  row.CustomerName,
-			AmountDue:     row.AmountDue,
-			Currency:      currency,
-			DueAt:         row.DueAt,
-			DaysOverdue:   daysOverdue,
-			PublicToken:   decryptToken(s.encKey, row.TokenHash.String),
-			Assignment:    assignmentPtr,
+			InvoiceID:       row.InvoiceID.String(),
+			InvoiceNumber:   invoiceNumber,
+			CustomerID:      row.CustomerID.String(),
+			CustomerName:    row.CustomerName,
+			AmountDue:       row.AmountDue,
+			Currency:        row.Currency,
+			ConvertedAmount: convertedAmount,
+			MissingFXRate:   !converted,
+			DueAt:           row.DueAt,
+			DaysOverdue:     daysOverdue,
+			PublicToken:     decryptToken(s.encKey, row.TokenHash.String),
+			Assignment:      assignmentPtr,
 		})
 	}
 
@@ -203,6 +211,8 @@ func (s *Service) ListOutstandingCustomers(ctx context.Context, limit int) (doma
 			CustomerName:           row.CustomerName,
 			OutstandingBalance:     row.Outstanding,
 			Currency:               currency,
+			ConvertedAmount:        row.ConvertedOutstanding,
+			MissingFXRate:          row.UnconvertedInvoices > 0,
 			OldestOverdueInvoiceID: oldestOverdueInvoiceID,
 			OldestOverdueInvoice:   oldestOverdueInvoiceNumber,
 			OldestOverdueAt:        oldestOverdueAt,
@@ -300,7 +310,7 @@ func (s *Service) ListPaymentIssues(ctx context.Context, limit int) (domain.Paym
 	}, nil
 }
 
-func (s *Service) GetOperations(ctx context.Context, limit int) (domain.BillingOperationsResponse, error) {
+func (s *Service) GetOperations(ctx context.Context, limit int, filter domain.AssignmentFilter) (domain.BillingOperationsResponse, error) {
 	orgID, ok := orgcontext.OrgIDFromContext(ctx)
 	if !ok || orgID == 0 {
 		return domain.BillingOperationsResponse{}, domain.ErrInvalidOrganization
@@ -320,7 +330,7 @@ func (s *Service) GetOperations(ctx context.Context, limit int) (domain.BillingO
 		return domain.BillingOperationsResponse{}, err
 	}
 
-	overdueRows, err := s.repo.ListOverdueInvoices(ctx, snowflake.ID(orgID), currency, now, limit)
+	overdueRows, err := s.repo.ListOverdueInvoices(ctx, snowflake.ID(orgID), now, limit, filter)
 	if err != nil {
 		return domain.BillingOperationsResponse{}, err
 	}
@@ -328,7 +338,7 @@ func (s *Service) GetOperations(ctx context.Context, limit int) (domain.BillingO
 	if err != nil {
 		return domain.BillingOperationsResponse{}, err
 	}
-	queueRows, err := s.repo.ListCollectionQueue(ctx, snowflake.ID(orgID), currency, now, limit)
+	queueRows, err := s.repo.ListCollectionQueue(ctx, snowflake.ID(orgID), currency, now, limit, filter)
 	if err != nil {
 		return domain.BillingOperationsResponse{}, err
 	}
@@ -397,7 +407,7 @@ func (s *Service) GetOperations(ctx context.Context, limit int) (domain.BillingO
 			CustomerID:          row.CustomerID.String(),
 			CustomerName:        row.CustomerName,
 			AmountDue:           row.AmountDue,
-			Currency:            currency,
+			Currency:            row.Currency,
 			DueAt:               &dueAt,
 			DaysOverdue:         daysOverdue,
 			AssignedTo:          assignedToProp.AssignedTo,
@@ -674,6 +684,8 @@ func (s *Service) GetInbox(ctx context.Context, req domain.InboxRequest) (domain
 	limit := req.Limit
 	if limit <= 0 {
 		limit = 25
+	} else if limit > pagination.MaxPageSize {
+		limit = pagination.MaxPageSize
 	}
 
 	currency, err := s.repo.FetchOrgCurrency(ctx, snowflake.ID(orgID))
@@ -696,16 +708,17 @@ func (s *Service) GetInbox(ctx context.Context, req domain.InboxRequest) (domain
 		}
 
 		items = append(items, domain.InboxItem{
-			EntityType:   row.EntityType,
-			EntityID:     row.EntityID,
-			EntityName:   row.EntityName,
-			RiskCategory: row.RiskCategory,
-			RiskScore:    row.RiskScore,
-			AmountDue:    row.AmountDue,
-			Currency:     currency,
-			DaysOverdue:  int(row.DaysOverdue),
-			LastAttempt:  lastAttempt,
-			PublicToken:  decryptToken(s.encKey, row.TokenHash.String),
+			EntityType:      row.EntityType,
+			EntityID:        row.EntityID,
+			EntityName:      row.EntityName,
+			RiskCategory:    row.RiskCategory,
+			RiskScore:       row.RiskScore,
+			AmountDue:       row.AmountDue,
+			Currency:        currency,
+			DaysOverdue:     int(row.DaysOverdue),
+			LastAttempt:     lastAttempt,
+			PublicToken:     decryptToken(s.encKey, row.TokenHash.String),
+			LastEmailStatus: row.LastEmailStatus.String,
 		})
 	}
 
@@ -724,6 +737,8 @@ func (s *Service) GetMyWork(ctx context.Context, userID string, req domain.MyWor
 	limit := req.Limit
 	if limit <= 0 {
 		limit = 25
+	} else if limit > pagination.MaxPageSize {
+		limit = pagination.MaxPageSize
 	}
 
 	currency, err := s.repo.FetchOrgCurrency(ctx, snowflake.ID(orgID))
@@ -781,6 +796,7 @@ func (s *Service) GetMyWork(ctx context.Context, userID string, req domain.MyWor
 			Status:             row.Status,
 			LastActionAt:       timePtr(row.LastActionAt),
 			PublicToken:        decryptToken(s.encKey, row.TokenHash.String),
+			LastEmailStatus:    row.LastEmailStatus.String,
 		})
 	}
 
@@ -799,6 +815,8 @@ func (s *Service) GetRecentlyResolved(ctx context.Context, userID string, req do
 	limit := req.Limit
 	if limit <= 0 {
 		limit = 25
+	} else if limit > pagination.MaxPageSize {
+		limit = pagination.MaxPageSize
 	}
 
 	since := s.clock.Now(ctx).UTC().AddDate(0, 0, -7) // Last 7 days by default
@@ -977,11 +995,11 @@ func (s *Service) GetInvoicePayments(ctx context.Context, invoiceID string) (dom
 	for _, row := range rows {
 		payments = append(payments, domain.PaymentDetail{
 			PaymentID:  row.ProviderPaymentID,
-			Amount:     0, // Amount needs to be extracted from payload or added to row
+			Amount:     row.Amount,
 			Currency:   row.Currency,
 			OccurredAt: row.ReceivedAt,
 			Provider:   row.Provider,
-			Status:     "succeeded", // Simplified
+			Status:     row.Status,
 		})
 	}
 
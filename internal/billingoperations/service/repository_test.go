@@ -11,7 +11,9 @@ import (
 	"github.com/railzwaylabs/railzway/internal/billingoperations/domain"
 	"github.com/railzwaylabs/railzway/internal/billingoperations/repository"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -138,6 +140,66 @@ func TestFinOpsSnapshotRepository(t *testing.T) {
 	})
 }
 
+func TestGetInvoicePayments_AmountsAndStatuses(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	db.Exec(`CREATE TABLE IF NOT EXISTS payment_events (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		provider TEXT NOT NULL,
+		provider_event_id TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		customer_id BIGINT NOT NULL,
+		payload TEXT NOT NULL,
+		received_at TIMESTAMP NOT NULL,
+		processed_at TIMESTAMP
+	)`)
+
+	svc := &Service{
+		db:   db,
+		log:  zaptest.NewLogger(t),
+		repo: repository.NewRepository(db),
+	}
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	invoiceID := node.Generate()
+	paidAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	insert := func(eventID, eventType string, object map[string]any, receivedAt time.Time) {
+		payload := toJSON(map[string]any{"id": eventID, "data": map[string]any{"object": object}})
+		db.Exec(`INSERT INTO payment_events
+			(id, org_id, provider, provider_event_id, event_type, customer_id, payload, received_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			node.Generate().Int64(), orgID.Int64(), "stripe", eventID, eventType, node.Generate().Int64(), string(payload), receivedAt)
+	}
+	insert("evt_paid", paymentdomain.EventTypePaymentSucceeded, map[string]any{
+		"id": "pi_123", "amount": 5000, "currency": "usd",
+		"metadata": map[string]any{"invoice_id": invoiceID.String()},
+	}, paidAt)
+	insert("evt_refund", paymentdomain.EventTypeRefunded, map[string]any{
+		"id": "ch_123", "amount": 5000, "amount_refunded": 2000, "currency": "usd",
+		"metadata": map[string]any{"invoice_id": invoiceID.String()},
+	}, paidAt.Add(time.Hour))
+	insert("evt_other", paymentdomain.EventTypePaymentSucceeded, map[string]any{
+		"id": "pi_other", "amount": 100, "currency": "usd",
+		"metadata": map[string]any{"invoice_id": node.Generate().String()},
+	}, paidAt)
+
+	ctx := orgcontext.WithOrgID(context.Background(), orgID.Int64())
+	resp, err := svc.GetInvoicePayments(ctx, invoiceID.String())
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Payments, 2) {
+		assert.Equal(t, "ch_123", resp.Payments[0].PaymentID)
+		assert.Equal(t, "refunded", resp.Payments[0].Status)
+		assert.Equal(t, int64(2000), resp.Payments[0].Amount)
+		assert.Equal(t, "USD", resp.Payments[0].Currency)
+
+		assert.Equal(t, "pi_123", resp.Payments[1].PaymentID)
+		assert.Equal(t, "succeeded", resp.Payments[1].Status)
+		assert.Equal(t, int64(5000), resp.Payments[1].Amount)
+	}
+}
+
 // We need a dummy helper to create datatypes.JSON from string if we were mocking at struct level,
 // but here we use DB.
 func toJSON(v any) datatypes.JSON {
@@ -10,6 +10,7 @@ import (
 	"github.com/railzwaylabs/railzway/internal/billingoperations/repository"
 	"github.com/railzwaylabs/railzway/internal/clock"
 	"github.com/railzwaylabs/railzway/internal/config"
+	"github.com/railzwaylabs/railzway/internal/providers/email"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -24,6 +25,7 @@ type Params struct {
 	Clock    clock.Clock
 	GenID    *snowflake.Node
 	AuditSvc auditdomain.Service `optional:"true"`
+	Email    email.Provider      `optional:"true"`
 	Cfg      config.Config
 
 	BillingConfig *config.BillingConfigHolder
@@ -36,8 +38,11 @@ type Service struct {
 	clock    clock.Clock
 	genID    *snowflake.Node
 	auditSvc auditdomain.Service
+	email    email.Provider
 	encKey   []byte
 
+	publicURL string
+
 	billingCfg *config.BillingConfigHolder
 }
 
@@ -58,7 +63,9 @@ func NewService(p Params) domain.Service {
 		clock:      p.Clock,
 		genID:      p.GenID,
 		auditSvc:   p.AuditSvc,
+		email:      p.Email,
 		encKey:     key,
 		billingCfg: p.BillingConfig,
+		publicURL:  strings.TrimRight(strings.TrimSpace(p.Cfg.PublicURL), "/"),
 	}
 }
@@ -113,4 +113,37 @@ func TestServiceReadAPI(t *testing.T) {
 			}
 		}
 	})
+	t.Run("GetPerformanceHistory_Paginates", func(t *testing.T) {
+		historyUser := "user_svc_history"
+		for i, periodType := range []string{domain.PeriodTypeDaily, domain.PeriodTypeDaily, domain.PeriodTypeWeekly} {
+			periodStart := start.AddDate(0, 0, -i)
+			db.Exec(`INSERT INTO finops_performance_snapshots 
+				(id, org_id, user_id, period_type, period_start, period_end, scoring_version, metrics, scores, total_score, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				node.Generate().Int64(), orgID.Int64(), historyUser, periodType, periodStart, periodStart.Add(24*time.Hour),
+				domain.ScoringVersionV1EqualWeight, metricsJSON, scoresJSON, 80, now, now)
+		}
+
+		first, err := svc.GetPerformanceHistory(ctx, historyUser, domain.PerformanceHistoryRequest{PeriodType: domain.PeriodTypeDaily, PageSize: 1})
+		assert.NoError(t, err)
+		if assert.Len(t, first.Snapshots, 1) {
+			assert.True(t, first.Snapshots[0].PeriodStart.Equal(start))
+		}
+		assert.True(t, first.HasMore)
+		assert.NotEmpty(t, first.NextPageToken)
+
+		second, err := svc.GetPerformanceHistory(ctx, historyUser, domain.PerformanceHistoryRequest{PeriodType: domain.PeriodTypeDaily, PageSize: 1, PageToken: first.NextPageToken})
+		assert.NoError(t, err)
+		if assert.Len(t, second.Snapshots, 1) {
+			assert.True(t, second.Snapshots[0].PeriodStart.Equal(start.AddDate(0, 0, -1)))
+		}
+		assert.False(t, second.HasMore)
+
+		all, err := svc.GetPerformanceHistory(ctx, historyUser, domain.PerformanceHistoryRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, all.Snapshots, 3)
+
+		_, err = svc.GetPerformanceHistory(ctx, historyUser, domain.PerformanceHistoryRequest{PeriodType: "hourly"})
+		assert.ErrorIs(t, err, domain.ErrInvalidPeriodType)
+	})
 }
@@ -5,98 +5,108 @@ import (
 	"time"
 
 	"github.com/railzwaylabs/railzway/internal/billingoperations/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	"go.uber.org/zap"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+const (
+	breachInitialResponse = "initial_response"
+	breachIdleAction      = "idle_action"
+)
+
+// EvaluateSLAs escalates open assignments that went without an action for
+// longer than their organization's SLA window. A breached assignment gets
+// breached_at and breach_level set, moves to escalated and records an
+// sla_breached action.
 func (s *Service) EvaluateSLAs(ctx context.Context) error {
-	const (
-		initialResponseSLA = 30 * time.Minute
-		idleActionSLA      = 60 * time.Minute
-	)
 	now := s.clock.Now(ctx).UTC()
 
-	var records []domain.BillingAssignmentRecord
 	records, err := s.repo.ListActiveAssignments(ctx)
 	if err != nil {
 		return err
 	}
 
 	for _, rec := range records {
-		isBreached := false
-		breachType := ""
-
-		if rec.Status == domain.AssignmentStatusAssigned {
-			if now.Sub(rec.AssignedAt) > initialResponseSLA {
-				isBreached = true
-				breachType = "initial_response"
-			}
-		}
-
-		if !isBreached && rec.LastActionAt.Valid {
-			if now.Sub(rec.LastActionAt.Time) > idleActionSLA {
-				isBreached = true
-				breachType = "idle_action"
-			}
+		breachType, isBreached := slaBreach(rec, now)
+		if !isBreached {
+			continue
 		}
 
-		if isBreached {
-			err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-				repoTx := s.repo.WithTx(tx)
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			repoTx := s.repo.WithTx(tx)
 
-				if err := repoTx.EscalateAssignment(ctx, rec.OrgID, rec.EntityType, rec.EntityID, breachType, now); err != nil {
-					return err
-				}
+			if err := repoTx.EscalateAssignment(ctx, rec.OrgID, rec.EntityType, rec.EntityID, breachType, now); err != nil {
+				return err
+			}
 
-				actionID := s.genID.Generate()
-				bucket := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+			actionID := s.genID.Generate()
+			bucket := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 
-				metadata := datatypes.JSONMap{
-					"assignment_id": rec.ID.String(),
-					"breach_type":   breachType,
-					"minutes_idle":  0,
-				}
-				if rec.LastActionAt.Valid {
-					metadata["minutes_idle"] = int(now.Sub(rec.LastActionAt.Time).Minutes())
-				} else {
-					metadata["minutes_since_assigned"] = int(now.Sub(rec.AssignedAt).Minutes())
-				}
+			metadata := datatypes.JSONMap{
+				"assignment_id": rec.ID.String(),
+				"breach_type":   breachType,
+				"sla_minutes":   slaWindowMinutes(rec),
+				"minutes_idle":  0,
+			}
+			if rec.LastActionAt.Valid {
+				metadata["minutes_idle"] = int(now.Sub(rec.LastActionAt.Time).Minutes())
+			} else {
+				metadata["minutes_since_assigned"] = int(now.Sub(rec.AssignedAt).Minutes())
+			}
 
-				_, err := repoTx.InsertBillingAction(ctx, domain.BillingActionRecord{
-					ID:           actionID,
-					OrgID:        rec.OrgID,
-					EntityType:   rec.EntityType,
-					EntityID:     rec.EntityID,
-					ActionType:   "sla_breached",
-					ActionBucket: bucket,
-					Metadata:     metadata,
-					ActorType:    "system",
-					ActorID:      "sla_monitor",
-					CreatedAt:    now,
-				})
-				return err
+			_, err := repoTx.InsertBillingAction(ctx, domain.BillingActionRecord{
+				ID:           actionID,
+				OrgID:        rec.OrgID,
+				EntityType:   rec.EntityType,
+				EntityID:     rec.EntityID,
+				ActionType:   "sla_breached",
+				ActionBucket: bucket,
+				Metadata:     metadata,
+				ActorType:    "system",
+				ActorID:      "sla_monitor",
+				CreatedAt:    now,
 			})
+			return err
+		})
 
-			if err != nil {
-				s.log.Error("failed to escalate assignment",
-					zap.String("assignment_id", rec.ID.String()),
-					zap.Error(err))
-				continue
-			}
+		if err != nil {
+			s.log.Error("failed to escalate assignment",
+				zap.String("assignment_id", rec.ID.String()),
+				zap.Error(err))
+			continue
+		}
 
-			if s.auditSvc != nil {
-				targetID := rec.EntityID.String()
-				_ = s.auditSvc.AuditLog(ctx, &rec.OrgID, "system", nil,
-					"billing_operations.assignment.escalated",
-					"billing_operation_assignment",
-					&targetID,
-					map[string]any{
-						"breach_type":   breachType,
-						"assignment_id": rec.ID.String(),
-					})
-			}
+		if s.auditSvc != nil {
+			targetID := rec.EntityID.String()
+			_ = s.auditSvc.AuditLog(ctx, &rec.OrgID, "system", nil,
+				"billing_operations.assignment.escalated",
+				"billing_operation_assignment",
+				&targetID,
+				map[string]any{
+					"breach_type":   breachType,
+					"assignment_id": rec.ID.String(),
+				})
 		}
 	}
 	return nil
 }
+
+// slaBreach reports whether an assignment has gone longer than its SLA window
+// without an action. The window runs from the last recorded action, or from
+// the assignment itself when nothing has been recorded yet.
+func slaBreach(rec domain.ActiveAssignmentRow, now time.Time) (string, bool) {
+	window := time.Duration(slaWindowMinutes(rec)) * time.Minute
+	if rec.LastActionAt.Valid {
+		return breachIdleAction, now.Sub(rec.LastActionAt.Time) > window
+	}
+	return breachInitialResponse, now.Sub(rec.AssignedAt) > window
+}
+
+func slaWindowMinutes(rec domain.ActiveAssignmentRow) int {
+	if rec.SLAMinutes <= 0 {
+		return organizationdomain.DefaultAssignmentSLAMinutes
+	}
+	return rec.SLAMinutes
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/railzwaylabs/railzway/internal/billingoperations/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLABreach(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	row := func(slaMinutes int, assignedAgo time.Duration, lastActionAgo *time.Duration) domain.ActiveAssignmentRow {
+		rec := domain.ActiveAssignmentRow{SLAMinutes: slaMinutes}
+		rec.AssignedAt = now.Add(-assignedAgo)
+		if lastActionAgo != nil {
+			rec.LastActionAt = sql.NullTime{Time: now.Add(-*lastActionAgo), Valid: true}
+		}
+		return rec
+	}
+	minutes := func(n int) *time.Duration {
+		d := time.Duration(n) * time.Minute
+		return &d
+	}
+
+	cases := []struct {
+		name       string
+		rec        domain.ActiveAssignmentRow
+		wantType   string
+		wantBreach bool
+	}{
+		{"within window, no action", row(30, 20*time.Minute, nil), breachInitialResponse, false},
+		{"no action past window", row(30, 31*time.Minute, nil), breachInitialResponse, true},
+		{"recent action", row(30, 5*time.Hour, minutes(10)), breachIdleAction, false},
+		{"idle past window", row(30, 5*time.Hour, minutes(45)), breachIdleAction, true},
+		{"default window", row(0, 59*time.Minute, nil), breachInitialResponse, false},
+		{"default window exceeded", row(0, 61*time.Minute, nil), breachInitialResponse, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			breachType, breached := slaBreach(tc.rec, now)
+			assert.Equal(t, tc.wantBreach, breached)
+			assert.Equal(t, tc.wantType, breachType)
+		})
+	}
+}
@@ -794,47 +794,28 @@ func (s *Service) loadOutstandingBalance(
 	var row outstandingBalanceRow
 	if err := s.db.WithContext(ctx).Raw(
 		`
-		WITH settled AS (
-			SELECT
-				(pe.payload #>> '{data,object,metadata,invoice_id}') AS invoice_id_text,
-				SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE -l.amount END) AS settled_amount
-			FROM ledger_entries le
-			JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-			JOIN ledger_accounts a ON a.id = l.account_id
-			JOIN payment_events pe ON pe.id = le.source_id
-			WHERE le.org_id = ?
-			  AND le.currency = ?
-			  AND le.source_type = ?
-			  AND a.code = ?
-			GROUP BY 1
-		)
 		SELECT
 			COUNT(1) AS invoice_count,
 			COALESCE(
-				SUM(GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0)),
+				SUM(GREATEST(i.total_amount - i.amount_paid, 0)),
 				0
 			) AS outstanding,
 			COALESCE(
 				SUM(
 					CASE
 						WHEN i.due_at IS NOT NULL AND i.due_at < ?
-						THEN GREATEST(i.subtotal_amount - COALESCE(s.settled_amount, 0), 0)
+						THEN GREATEST(i.total_amount - i.amount_paid, 0)
 						ELSE 0
 					END
 				),
 				0
 			) AS overdue
 		FROM invoices i
-		LEFT JOIN settled s ON s.invoice_id_text = i.id::text
 		WHERE i.org_id = ?
 		  AND i.status = 'FINALIZED'
 		  AND i.voided_at IS NULL
 		  AND i.currency = ?
 		`,
-		orgID,
-		currency,
-		string(ledgerdomain.SourceTypePayment),
-		string(ledgerdomain.AccountCodeAccountsReceivable),
 		now,
 		orgID,
 		currency,
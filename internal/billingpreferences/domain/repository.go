@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/bwmarrin/snowflake"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Find(ctx context.Context, db *gorm.DB, orgID snowflake.ID) (*organizationdomain.OrganizationBillingPreferences, error)
+	Save(ctx context.Context, db *gorm.DB, prefs *organizationdomain.OrganizationBillingPreferences) error
+	// FindOrganizationTimezone returns the timezone the organization was
+	// created with, used for preferences saved for the first time.
+	FindOrganizationTimezone(ctx context.Context, db *gorm.DB, orgID snowflake.ID) (string, error)
+}
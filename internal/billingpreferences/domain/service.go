@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// UpdateRequest changes an organization's billing defaults. Nil fields keep
+// their current value.
+type UpdateRequest struct {
+	Currency *string `json:"currency"`
+	// PaymentTermsDays is how many days after finalization invoices are due.
+	PaymentTermsDays *int `json:"payment_terms_days"`
+	// DunningDays is the reminder schedule in days past due.
+	DunningDays  []int   `json:"dunning_days"`
+	RoundingMode *string `json:"rounding_mode"`
+	// CancellationRefund is none, credit or refund: what customers get back
+	// for the unused part of an upfront billed cycle on immediate cancel.
+	CancellationRefund *string `json:"cancellation_refund"`
+	// PastDueGraceDays is how many days a subscription stays past due after
+	// a failed auto-charge before it is canceled.
+	PastDueGraceDays *int `json:"past_due_grace_days"`
+}
+
+type Response struct {
+	OrgID              string    `json:"organization_id"`
+	Currency           string    `json:"currency"`
+	Timezone           string    `json:"timezone"`
+	PaymentTermsDays   int       `json:"payment_terms_days"`
+	DunningDays        []int     `json:"dunning_days"`
+	RoundingMode       string    `json:"rounding_mode"`
+	CancellationRefund string    `json:"cancellation_refund"`
+	PastDueGraceDays   int       `json:"past_due_grace_days"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type Service interface {
+	Get(ctx context.Context) (*Response, error)
+	// Update saves the organization's billing defaults. Services read them
+	// when they bill, so new subscriptions and invoices use the new values
+	// while existing ones keep what they were created with.
+	Update(ctx context.Context, req UpdateRequest) (*Response, error)
+}
+
+var (
+	ErrInvalidOrganization       = errors.New("invalid_organization")
+	ErrInvalidCurrency           = errors.New("invalid_currency")
+	ErrInvalidPaymentTerms       = errors.New("invalid_payment_terms")
+	ErrInvalidDunningDays        = errors.New("invalid_dunning_days")
+	ErrInvalidRoundingMode       = errors.New("invalid_rounding_mode")
+	ErrInvalidCancellationRefund = errors.New("invalid_cancellation_refund")
+	ErrInvalidPastDueGraceDays   = errors.New("invalid_past_due_grace_days")
+	ErrNotFound                  = errors.New("billing_preferences_not_found")
+)
@@ -0,0 +1,12 @@
+package billingpreferences
+
+import (
+	"github.com/railzwaylabs/railzway/internal/billingpreferences/repository"
+	"github.com/railzwaylabs/railzway/internal/billingpreferences/service"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("billingpreferences.service",
+	fx.Provide(repository.Provide),
+	fx.Provide(service.NewService),
+)
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/bwmarrin/snowflake"
+	prefsdomain "github.com/railzwaylabs/railzway/internal/billingpreferences/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"gorm.io/gorm"
+)
+
+type repo struct{}
+
+func Provide() prefsdomain.Repository {
+	return &repo{}
+}
+
+func (r *repo) Find(ctx context.Context, db *gorm.DB, orgID snowflake.ID) (*organizationdomain.OrganizationBillingPreferences, error) {
+	var prefs organizationdomain.OrganizationBillingPreferences
+	err := db.WithContext(ctx).Raw(
+		`SELECT org_id, currency, timezone, dunning_days, assignment_sla_minutes, rounding_mode,
+			payment_terms_days, cancellation_refund, past_due_grace_days, scoring_weights, created_at, updated_at
+		 FROM organization_billing_preferences
+		 WHERE org_id = ?`,
+		orgID,
+	).Scan(&prefs).Error
+	if err != nil {
+		return nil, err
+	}
+	if prefs.OrgID == 0 {
+		return nil, nil
+	}
+	return &prefs, nil
+}
+
+func (r *repo) Save(ctx context.Context, db *gorm.DB, prefs *organizationdomain.OrganizationBillingPreferences) error {
+	return db.WithContext(ctx).Exec(
+		`INSERT INTO organization_billing_preferences (
+			org_id, currency, timezone, dunning_days, assignment_sla_minutes, rounding_mode, payment_terms_days,
+			cancellation_refund, past_due_grace_days, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (org_id)
+		 DO UPDATE SET currency = EXCLUDED.currency,
+		               dunning_days = EXCLUDED.dunning_days,
+		               rounding_mode = EXCLUDED.rounding_mode,
+		               payment_terms_days = EXCLUDED.payment_terms_days,
+		               cancellation_refund = EXCLUDED.cancellation_refund,
+		               past_due_grace_days = EXCLUDED.past_due_grace_days,
+		               updated_at = EXCLUDED.updated_at`,
+		prefs.OrgID,
+		prefs.Currency,
+		prefs.Timezone,
+		prefs.DunningDays,
+		prefs.AssignmentSLAMinutes,
+		prefs.RoundingMode,
+		prefs.PaymentTermsDays,
+		prefs.CancellationRefund,
+		prefs.PastDueGraceDays,
+		prefs.CreatedAt,
+		prefs.UpdatedAt,
+	).Error
+}
+
+func (r *repo) FindOrganizationTimezone(ctx context.Context, db *gorm.DB, orgID snowflake.ID) (string, error) {
+	var timezone string
+	err := db.WithContext(ctx).Raw(
+		`SELECT timezone_name FROM organizations WHERE id = ?`,
+		orgID,
+	).Scan(&timezone).Error
+	return timezone, err
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	auditdomain "github.com/railzwaylabs/railzway/internal/audit/domain"
+	prefsdomain "github.com/railzwaylabs/railzway/internal/billingpreferences/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	referencedomain "github.com/railzwaylabs/railzway/internal/reference/domain"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Params struct {
+	fx.In
+
+	DB       *gorm.DB
+	Log      *zap.Logger
+	Repo     prefsdomain.Repository
+	Ref      referencedomain.Repository
+	AuditSvc auditdomain.Service `optional:"true"`
+}
+
+type Service struct {
+	db       *gorm.DB
+	log      *zap.Logger
+	repo     prefsdomain.Repository
+	ref      referencedomain.Repository
+	auditSvc auditdomain.Service
+}
+
+func NewService(p Params) prefsdomain.Service {
+	return &Service{
+		db:       p.DB,
+		log:      p.Log.Named("billingpreferences.service"),
+		repo:     p.Repo,
+		ref:      p.Ref,
+		auditSvc: p.AuditSvc,
+	}
+}
+
+func (s *Service) Get(ctx context.Context) (*prefsdomain.Response, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, prefsdomain.ErrInvalidOrganization
+	}
+
+	prefs, err := s.repo.Find(ctx, s.db, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		return nil, prefsdomain.ErrNotFound
+	}
+	return toResponse(prefs)
+}
+
+func (s *Service) Update(ctx context.Context, req prefsdomain.UpdateRequest) (*prefsdomain.Response, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, prefsdomain.ErrInvalidOrganization
+	}
+
+	var currency string
+	if req.Currency != nil {
+		currency = strings.ToUpper(strings.TrimSpace(*req.Currency))
+		supported, err := s.currencySupported(ctx, currency)
+		if err != nil {
+			return nil, err
+		}
+		if !supported {
+			return nil, prefsdomain.ErrInvalidCurrency
+		}
+	}
+	if req.PaymentTermsDays != nil {
+		days := *req.PaymentTermsDays
+		if days < 0 || days > organizationdomain.MaxPaymentTermsDays {
+			return nil, prefsdomain.ErrInvalidPaymentTerms
+		}
+	}
+	if req.PastDueGraceDays != nil {
+		days := *req.PastDueGraceDays
+		if days < 0 || days > organizationdomain.MaxPastDueGraceDays {
+			return nil, prefsdomain.ErrInvalidPastDueGraceDays
+		}
+	}
+	var dunningDays []byte
+	if req.DunningDays != nil {
+		days, err := organizationdomain.NormalizeDunningDays(req.DunningDays)
+		if err != nil {
+			return nil, prefsdomain.ErrInvalidDunningDays
+		}
+		dunningDays, err = json.Marshal(days)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var roundingMode organizationdomain.RoundingMode
+	if req.RoundingMode != nil {
+		roundingMode = organizationdomain.RoundingMode(strings.ToLower(strings.TrimSpace(*req.RoundingMode)))
+		if !roundingMode.Valid() {
+			return nil, prefsdomain.ErrInvalidRoundingMode
+		}
+	}
+
+	var cancellationRefund organizationdomain.CancellationRefund
+	if req.CancellationRefund != nil {
+		cancellationRefund = organizationdomain.CancellationRefund(strings.ToLower(strings.TrimSpace(*req.CancellationRefund)))
+		if !cancellationRefund.Valid() {
+			return nil, prefsdomain.ErrInvalidCancellationRefund
+		}
+	}
+
+	now := time.Now().UTC()
+	var prefs *organizationdomain.OrganizationBillingPreferences
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		prefs, err = s.repo.Find(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+		if prefs == nil {
+			// The first save has nothing to fall back on for the currency.
+			if currency == "" {
+				return prefsdomain.ErrInvalidCurrency
+			}
+			prefs, err = s.newPreferences(ctx, tx, orgID, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		if currency != "" {
+			prefs.Currency = currency
+		}
+		if req.PaymentTermsDays != nil {
+			prefs.PaymentTermsDays = *req.PaymentTermsDays
+		}
+		if dunningDays != nil {
+			prefs.DunningDays = dunningDays
+		}
+		if roundingMode != "" {
+			prefs.RoundingMode = roundingMode
+		}
+		if cancellationRefund != "" {
+			prefs.CancellationRefund = cancellationRefund
+		}
+		if req.PastDueGraceDays != nil {
+			prefs.PastDueGraceDays = *req.PastDueGraceDays
+		}
+		prefs.UpdatedAt = now
+		return s.repo.Save(ctx, tx, prefs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := toResponse(prefs)
+	if err != nil {
+		return nil, err
+	}
+	s.emitAudit(ctx, resp)
+	return resp, nil
+}
+
+func (s *Service) newPreferences(ctx context.Context, tx *gorm.DB, orgID snowflake.ID, now time.Time) (*organizationdomain.OrganizationBillingPreferences, error) {
+	timezone, err := s.repo.FindOrganizationTimezone(ctx, tx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(timezone) == "" {
+		timezone = "UTC"
+	}
+	dunningDays, err := json.Marshal(organizationdomain.DefaultDunningDays)
+	if err != nil {
+		return nil, err
+	}
+	return &organizationdomain.OrganizationBillingPreferences{
+		OrgID:                orgID,
+		Timezone:             timezone,
+		DunningDays:          dunningDays,
+		AssignmentSLAMinutes: organizationdomain.DefaultAssignmentSLAMinutes,
+		RoundingMode:         organizationdomain.DefaultRoundingMode,
+		PaymentTermsDays:     organizationdomain.DefaultPaymentTermsDays,
+		CancellationRefund:   organizationdomain.DefaultCancellationRefund,
+		PastDueGraceDays:     organizationdomain.DefaultPastDueGraceDays,
+		CreatedAt:            now,
+	}, nil
+}
+
+func (s *Service) currencySupported(ctx context.Context, code string) (bool, error) {
+	if code == "" || s.ref == nil {
+		return false, nil
+	}
+	currencies, err := s.ref.ListCurrencies(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, currency := range currencies {
+		if currency.Code == code {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) emitAudit(ctx context.Context, resp *prefsdomain.Response) {
+	if s.auditSvc == nil || resp == nil {
+		return
+	}
+	orgID, _ := orgcontext.OrgIDFromContext(ctx)
+	targetID := resp.OrgID
+	_ = s.auditSvc.AuditLog(ctx, &orgID, "", nil, "billing_preferences.updated", "billing_preferences", &targetID, map[string]any{
+		"currency":            resp.Currency,
+		"payment_terms_days":  resp.PaymentTermsDays,
+		"dunning_days":        resp.DunningDays,
+		"rounding_mode":       resp.RoundingMode,
+		"cancellation_refund": resp.CancellationRefund,
+		"past_due_grace_days": resp.PastDueGraceDays,
+	})
+}
+
+func toResponse(prefs *organizationdomain.OrganizationBillingPreferences) (*prefsdomain.Response, error) {
+	var dunningDays []int
+	if len(prefs.DunningDays) > 0 {
+		if err := json.Unmarshal(prefs.DunningDays, &dunningDays); err != nil {
+			return nil, err
+		}
+	}
+	if len(dunningDays) == 0 {
+		dunningDays = append([]int(nil), organizationdomain.DefaultDunningDays...)
+	}
+	roundingMode := prefs.RoundingMode
+	if !roundingMode.Valid() {
+		roundingMode = organizationdomain.DefaultRoundingMode
+	}
+	cancellationRefund := prefs.CancellationRefund
+	if !cancellationRefund.Valid() {
+		cancellationRefund = organizationdomain.DefaultCancellationRefund
+	}
+	return &prefsdomain.Response{
+		OrgID:              prefs.OrgID.String(),
+		Currency:           prefs.Currency,
+		Timezone:           prefs.Timezone,
+		PaymentTermsDays:   prefs.PaymentTermsDays,
+		DunningDays:        dunningDays,
+		RoundingMode:       string(roundingMode),
+		CancellationRefund: string(cancellationRefund),
+		PastDueGraceDays:   prefs.PastDueGraceDays,
+		UpdatedAt:          prefs.UpdatedAt,
+	}, nil
+}
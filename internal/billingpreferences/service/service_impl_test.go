@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	prefsdomain "github.com/railzwaylabs/railzway/internal/billingpreferences/domain"
+	"github.com/railzwaylabs/railzway/internal/billingpreferences/repository"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/reference"
+	referencedomain "github.com/railzwaylabs/railzway/internal/reference/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestBillingPreferences_GetAndUpdate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&organizationdomain.Organization{},
+		&organizationdomain.OrganizationBillingPreferences{},
+		&referencedomain.Currency{},
+	))
+	require.NoError(t, db.Create([]referencedomain.Currency{
+		{Code: "USD", Name: "US Dollar", MinorUnit: 2, IsActive: true},
+		{Code: "EUR", Name: "Euro", MinorUnit: 2, IsActive: true},
+	}).Error)
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	require.NoError(t, db.Create(&organizationdomain.Organization{
+		ID: orgID, Name: "Acme", Slug: "acme", CountryCode: "ID", TimezoneName: "Asia/Jakarta",
+	}).Error)
+
+	svc := NewService(Params{DB: db, Log: zap.NewNop(), Repo: repository.Provide(), Ref: reference.NewRepository(db)})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	str := func(v string) *string { return &v }
+	days := func(v int) *int { return &v }
+
+	_, err = svc.Get(ctx)
+	assert.ErrorIs(t, err, prefsdomain.ErrNotFound)
+
+	// The first save needs a currency and starts from the defaults.
+	_, err = svc.Update(ctx, prefsdomain.UpdateRequest{PaymentTermsDays: days(14)})
+	assert.ErrorIs(t, err, prefsdomain.ErrInvalidCurrency)
+
+	created, err := svc.Update(ctx, prefsdomain.UpdateRequest{Currency: str("usd")})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", created.Currency)
+	assert.Equal(t, "Asia/Jakarta", created.Timezone)
+	assert.Equal(t, organizationdomain.DefaultPaymentTermsDays, created.PaymentTermsDays)
+	assert.Equal(t, organizationdomain.DefaultDunningDays, created.DunningDays)
+	assert.Equal(t, string(organizationdomain.DefaultRoundingMode), created.RoundingMode)
+	assert.Equal(t, string(organizationdomain.DefaultCancellationRefund), created.CancellationRefund)
+	assert.Equal(t, organizationdomain.DefaultPastDueGraceDays, created.PastDueGraceDays)
+
+	updated, err := svc.Update(ctx, prefsdomain.UpdateRequest{
+		Currency:           str("EUR"),
+		PaymentTermsDays:   days(14),
+		DunningDays:        []int{10, 3},
+		RoundingMode:       str("half_even"),
+		CancellationRefund: str("credit"),
+		PastDueGraceDays:   days(3),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", updated.Currency)
+	assert.Equal(t, 14, updated.PaymentTermsDays)
+	assert.Equal(t, []int{3, 10}, updated.DunningDays)
+	assert.Equal(t, "half_even", updated.RoundingMode)
+	assert.Equal(t, "credit", updated.CancellationRefund)
+	assert.Equal(t, 3, updated.PastDueGraceDays)
+
+	// Readers such as subscription creation see the new default currency.
+	var currency string
+	require.NoError(t, db.Raw(`SELECT currency FROM organization_billing_preferences WHERE org_id = ?`, orgID).Scan(&currency).Error)
+	assert.Equal(t, "EUR", currency)
+
+	got, err := svc.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, updated, got)
+
+	_, err = svc.Update(ctx, prefsdomain.UpdateRequest{Currency: str("XYZ")})
+	assert.ErrorIs(t, err, prefsdomain.ErrInvalidCurrency)
+	_, err = svc.Update(ctx, prefsdomain.UpdateRequest{PaymentTermsDays: days(-1)})
+	assert.ErrorIs(t, err, prefsdomain.ErrInvalidPaymentTerms)
+	_, err = svc.Update(ctx, prefsdomain.UpdateRequest{DunningDays: []int{3, 3}})
+	assert.ErrorIs(t, err, prefsdomain.ErrInvalidDunningDays)
+	_, err = svc.Update(ctx, prefsdomain.UpdateRequest{RoundingMode: str("truncate")})
+	assert.ErrorIs(t, err, prefsdomain.ErrInvalidRoundingMode)
+	_, err = svc.Update(ctx, prefsdomain.UpdateRequest{CancellationRefund: str("void")})
+	assert.ErrorIs(t, err, prefsdomain.ErrInvalidCancellationRefund)
+	_, err = svc.Update(ctx, prefsdomain.UpdateRequest{PastDueGraceDays: days(organizationdomain.MaxPastDueGraceDays + 1)})
+	assert.ErrorIs(t, err, prefsdomain.ErrInvalidPastDueGraceDays)
+}
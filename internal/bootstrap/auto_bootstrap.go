@@ -6,15 +6,30 @@ import (
 	"time"
 
 	"github.com/railzwaylabs/railzway/internal/config"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
 	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	"github.com/railzwaylabs/railzway/internal/seed"
+	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+// DefaultOrgParams are the dependencies of EnsureDefaultOrgAndUser. The
+// ledger is optional so processes without it can still bootstrap the org.
+type DefaultOrgParams struct {
+	fx.In
+
+	Cfg      config.Config
+	DB       *gorm.DB
+	OrgState OrgStateService
+	Log      *zap.Logger
+	Accounts ledgerdomain.AccountProvisioner `optional:"true"`
+}
+
 // EnsureDefaultOrgAndUser creates the default organization and admin user when explicitly enabled.
 // This is intended for OSS/dev setups that want an explicit, env-gated bootstrap.
-func EnsureDefaultOrgAndUser(cfg config.Config, db *gorm.DB, orgState OrgStateService, log *zap.Logger) error {
+func EnsureDefaultOrgAndUser(p DefaultOrgParams) error {
+	cfg, db, orgState, log := p.Cfg, p.DB, p.OrgState, p.Log
 	if !cfg.Bootstrap.EnsureDefaultOrgAndUser {
 		return nil
 	}
@@ -38,7 +53,7 @@ func EnsureDefaultOrgAndUser(cfg config.Config, db *gorm.DB, orgState OrgStateSe
 		return err
 	}
 
-	if orgState == nil {
+	if orgState == nil && p.Accounts == nil {
 		return nil
 	}
 
@@ -56,12 +71,20 @@ func EnsureDefaultOrgAndUser(cfg config.Config, db *gorm.DB, orgState OrgStateSe
 		return err
 	}
 
-	now := time.Now().UTC()
-	if err := orgState.Initialize(ctx, org.ID, now); err != nil {
-		return err
+	if p.Accounts != nil {
+		if err := p.Accounts.EnsureAccounts(ctx, org.ID); err != nil {
+			return err
+		}
 	}
-	if err := orgState.Activate(ctx, org.ID, now); err != nil {
-		return err
+
+	if orgState != nil {
+		now := time.Now().UTC()
+		if err := orgState.Initialize(ctx, org.ID, now); err != nil {
+			return err
+		}
+		if err := orgState.Activate(ctx, org.ID, now); err != nil {
+			return err
+		}
 	}
 
 	if log != nil {
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/observability/metrics"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const defaultPriceTTL = 30 * time.Second
+
+// PriceCache stores price and price amount lookups shared across instances.
+// Entries of a price are kept together so a single invalidation drops the
+// price and its amounts in every currency.
+type PriceCache interface {
+	GetPrice(ctx context.Context, orgID, priceID snowflake.ID) (*pricedomain.Response, bool)
+	SetPrice(ctx context.Context, orgID, priceID snowflake.ID, price *pricedomain.Response)
+	GetAmounts(ctx context.Context, orgID, priceID snowflake.ID, currency string) ([]priceamountdomain.Response, bool)
+	SetAmounts(ctx context.Context, orgID, priceID snowflake.ID, currency string, amounts []priceamountdomain.Response)
+	InvalidatePrice(ctx context.Context, orgID, priceID snowflake.ID)
+}
+
+type PriceCacheParams struct {
+	fx.In
+
+	Redis   *redis.Client    `optional:"true"`
+	Metrics *metrics.Metrics `optional:"true"`
+	Log     *zap.Logger
+}
+
+type redisPriceCache struct {
+	client  *redis.Client
+	metrics *metrics.Metrics
+	log     *zap.Logger
+	ttl     time.Duration
+}
+
+// NewPriceCache returns a Redis-backed price cache, or a cache that always
+// misses when no Redis client is configured.
+func NewPriceCache(p PriceCacheParams) PriceCache {
+	if p.Redis == nil {
+		return noopPriceCache{}
+	}
+	return &redisPriceCache{
+		client:  p.Redis,
+		metrics: p.Metrics,
+		log:     p.Log.Named("cache.price"),
+		ttl:     defaultPriceTTL,
+	}
+}
+
+func (c *redisPriceCache) GetPrice(ctx context.Context, orgID, priceID snowflake.ID) (*pricedomain.Response, bool) {
+	var price pricedomain.Response
+	if !c.get(ctx, "price", orgID, priceID, "price", &price) {
+		return nil, false
+	}
+	return &price, true
+}
+
+func (c *redisPriceCache) SetPrice(ctx context.Context, orgID, priceID snowflake.ID, price *pricedomain.Response) {
+	if price == nil {
+		return
+	}
+	c.set(ctx, orgID, priceID, "price", price)
+}
+
+func (c *redisPriceCache) GetAmounts(ctx context.Context, orgID, priceID snowflake.ID, currency string) ([]priceamountdomain.Response, bool) {
+	var amounts []priceamountdomain.Response
+	if !c.get(ctx, "price_amounts", orgID, priceID, amountsField(currency), &amounts) {
+		return nil, false
+	}
+	return amounts, true
+}
+
+func (c *redisPriceCache) SetAmounts(ctx context.Context, orgID, priceID snowflake.ID, currency string, amounts []priceamountdomain.Response) {
+	if amounts == nil {
+		amounts = []priceamountdomain.Response{}
+	}
+	c.set(ctx, orgID, priceID, amountsField(currency), amounts)
+}
+
+func (c *redisPriceCache) InvalidatePrice(ctx context.Context, orgID, priceID snowflake.ID) {
+	if err := c.client.Del(ctx, priceKey(orgID, priceID)).Err(); err != nil {
+		c.log.Warn("failed to invalidate price cache",
+			zap.String("price_id", priceID.String()),
+			zap.Error(err))
+	}
+}
+
+// get reads a field into dest. Redis errors are treated as misses so the
+// caller falls back to the database.
+func (c *redisPriceCache) get(ctx context.Context, kind string, orgID, priceID snowflake.ID, field string, dest any) bool {
+	raw, err := c.client.HGet(ctx, priceKey(orgID, priceID), field).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.log.Warn("failed to read price cache", zap.String("price_id", priceID.String()), zap.Error(err))
+		}
+		c.metrics.RecordPriceCacheLookup(ctx, kind, false)
+		return false
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		c.metrics.RecordPriceCacheLookup(ctx, kind, false)
+		return false
+	}
+	c.metrics.RecordPriceCacheLookup(ctx, kind, true)
+	return true
+}
+
+func (c *redisPriceCache) set(ctx context.Context, orgID, priceID snowflake.ID, field string, value any) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	key := priceKey(orgID, priceID)
+	pipe := c.client.TxPipeline()
+	pipe.HSet(ctx, key, field, raw)
+	pipe.Expire(ctx, key, c.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.log.Warn("failed to write price cache", zap.String("price_id", priceID.String()), zap.Error(err))
+	}
+}
+
+func priceKey(orgID, priceID snowflake.ID) string {
+	return fmt.Sprintf("price_cache:%s:%s", orgID.String(), priceID.String())
+}
+
+// amountsField keys amounts by currency; an empty currency caches the
+// amounts of every currency.
+func amountsField(currency string) string {
+	code := strings.ToUpper(strings.TrimSpace(currency))
+	if code == "" {
+		code = "*"
+	}
+	return "amounts:" + code
+}
+
+type noopPriceCache struct{}
+
+func (noopPriceCache) GetPrice(context.Context, snowflake.ID, snowflake.ID) (*pricedomain.Response, bool) {
+	return nil, false
+}
+
+func (noopPriceCache) SetPrice(context.Context, snowflake.ID, snowflake.ID, *pricedomain.Response) {}
+
+func (noopPriceCache) GetAmounts(context.Context, snowflake.ID, snowflake.ID, string) ([]priceamountdomain.Response, bool) {
+	return nil, false
+}
+
+func (noopPriceCache) SetAmounts(context.Context, snowflake.ID, snowflake.ID, string, []priceamountdomain.Response) {
+}
+
+func (noopPriceCache) InvalidatePrice(context.Context, snowflake.ID, snowflake.ID) {}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPriceCache_InvalidateDropsPriceAndAmounts(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+
+	c := NewPriceCache(PriceCacheParams{
+		Redis: redis.NewClient(&redis.Options{Addr: server.Addr()}),
+		Log:   zap.NewNop(),
+	})
+	ctx := context.Background()
+
+	_, hit := c.GetPrice(ctx, 1, 7)
+	assert.False(t, hit)
+
+	c.SetPrice(ctx, 1, 7, &pricedomain.Response{ID: 7, OrganizationID: 1, Code: "pro", Active: true})
+	c.SetAmounts(ctx, 1, 7, "usd", []priceamountdomain.Response{{ID: 70, PriceID: 7, Currency: "USD", UnitAmountCents: 1200}})
+	c.SetAmounts(ctx, 1, 7, "", nil)
+
+	price, hit := c.GetPrice(ctx, 1, 7)
+	require.True(t, hit)
+	assert.Equal(t, "pro", price.Code)
+
+	amounts, hit := c.GetAmounts(ctx, 1, 7, "USD")
+	require.True(t, hit)
+	require.Len(t, amounts, 1)
+	assert.Equal(t, int64(1200), amounts[0].UnitAmountCents)
+
+	// An empty list is cached too, so a price without amounts is not
+	// re-queried on every lookup.
+	amounts, hit = c.GetAmounts(ctx, 1, 7, "")
+	assert.True(t, hit)
+	assert.Empty(t, amounts)
+
+	_, hit = c.GetPrice(ctx, 2, 7)
+	assert.False(t, hit)
+
+	c.InvalidatePrice(ctx, 1, 7)
+	_, hit = c.GetPrice(ctx, 1, 7)
+	assert.False(t, hit)
+	_, hit = c.GetAmounts(ctx, 1, 7, "USD")
+	assert.False(t, hit)
+
+	c.SetPrice(ctx, 1, 7, &pricedomain.Response{ID: 7})
+	server.FastForward(defaultPriceTTL + time.Second)
+	_, hit = c.GetPrice(ctx, 1, 7)
+	assert.False(t, hit)
+}
+
+func TestPriceCache_WithoutRedisAlwaysMisses(t *testing.T) {
+	c := NewPriceCache(PriceCacheParams{Log: zap.NewNop()})
+	ctx := context.Background()
+
+	c.SetPrice(ctx, 1, 7, &pricedomain.Response{ID: 7})
+	_, hit := c.GetPrice(ctx, 1, 7)
+	assert.False(t, hit)
+}
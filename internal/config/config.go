@@ -38,6 +38,10 @@ type Config struct {
 	StaticDir    string
 	InstanceID   string
 
+	// PublicURL is the base URL of the customer-facing app that serves
+	// hosted invoices; emailed payment links point at it.
+	PublicURL string
+
 	Cloud     CloudConfig
 	Bootstrap BootstrapConfig
 
@@ -57,6 +61,7 @@ type Config struct {
 	Email     EmailConfig
 	Logger    LoggerConfig
 	Privacy   PrivacyConfig
+	Usage     UsageConfig
 	License   LicenseConfig
 	Vault     VaultConfig
 }
@@ -79,6 +84,10 @@ type EmailConfig struct {
 	SMTPUsername string
 	SMTPPassword string
 	SMTPFrom     string
+
+	// WebhookSecret authenticates delivery status webhooks from the email
+	// provider. Empty disables them.
+	WebhookSecret string
 }
 
 type LoggerConfig struct {
@@ -125,12 +134,22 @@ type RateLimitConfig struct {
 	UsageIngestEndpointRate          float64
 	UsageIngestEndpointBurst         int
 	UsageIngestConcurrencyTTLSeconds int
+
+	// Requests per minute allowed for API keys on each rate limit tier.
+	APIKeyFreeRPM int
+	APIKeyProRPM  int
 }
 
 type PrivacyConfig struct {
 	WebhookRetentionDays int
 }
 
+type UsageConfig struct {
+	// Hours after a billing cycle ends during which late usage reopens the
+	// closed cycle for re-rating instead of being rejected.
+	LateArrivalGraceHours int
+}
+
 type BillingConfig struct {
 	AgingBuckets []AgingBucket `mapstructure:"agingBuckets"`
 	RiskLevels   []RiskLevel   `mapstructure:"riskLevels"`
@@ -186,6 +205,7 @@ func Load() Config {
 		PaymentProviderConfigSecret: strings.TrimSpace(getenv("PAYMENT_PROVIDER_CONFIG_SECRET", "")),
 		OTLPEndpoint:                getenv("OTLP_ENDPOINT", "localhost:4317"),
 		StaticDir:                   getenv("STATIC_DIR", "apps/admin/dist"),
+		PublicURL:                   strings.TrimRight(strings.TrimSpace(getenv("PUBLIC_URL", "http://localhost:5173")), "/"),
 		Cloud: CloudConfig{
 			Metrics: CloudMetricsConfig{
 				Enabled:   getenvBool("TELEMETRY_ENABLED", true),
@@ -236,6 +256,8 @@ func Load() Config {
 			UsageIngestEndpointRate:          getenvFloat("USAGE_INGEST_ENDPOINT_RATE", 15),
 			UsageIngestEndpointBurst:         getenvInt("USAGE_INGEST_ENDPOINT_BURST", 30),
 			UsageIngestConcurrencyTTLSeconds: clampInt(getenvInt("USAGE_INGEST_CONCURRENCY_TTL_SECONDS", 3), 2, 5),
+			APIKeyFreeRPM:                    getenvInt("API_KEY_RATE_LIMIT_FREE_RPM", 60),
+			APIKeyProRPM:                     getenvInt("API_KEY_RATE_LIMIT_PRO_RPM", 600),
 		},
 
 		Email: EmailConfig{
@@ -244,6 +266,8 @@ func Load() Config {
 			SMTPUsername: getenv("SMTP_USERNAME", ""),
 			SMTPPassword: getenv("SMTP_PASSWORD", ""),
 			SMTPFrom:     getenv("SMTP_FROM", "no-reply@railzway.test"),
+
+			WebhookSecret: strings.TrimSpace(getenv("EMAIL_WEBHOOK_SECRET", "")),
 		},
 
 		Logger: LoggerConfig{
@@ -254,6 +278,10 @@ func Load() Config {
 			WebhookRetentionDays: getenvInt("WEBHOOK_RETENTION_DAYS", 30),
 		},
 
+		Usage: UsageConfig{
+			LateArrivalGraceHours: getenvInt("USAGE_LATE_ARRIVAL_GRACE_HOURS", 72),
+		},
+
 		InstanceID: loadOrCreateInstanceID(),
 		License: LicenseConfig{
 			PublicKey: strings.TrimSpace(getenv("RAILZWAY_LICENSE_PUBLIC_KEY", "")),
@@ -0,0 +1,141 @@
+// Package domain contains coupons and the discounts they grant subscriptions.
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"gorm.io/datatypes"
+)
+
+// DiscountType is how a coupon reduces the charges of a billing cycle.
+type DiscountType string
+
+const (
+	DiscountTypePercent     DiscountType = "percent"
+	DiscountTypeFixedAmount DiscountType = "fixed_amount"
+)
+
+// Duration is how many billing cycles a discount applies to.
+type Duration string
+
+const (
+	// DurationOnce discounts the first billing cycle only.
+	DurationOnce Duration = "once"
+	// DurationRepeating discounts DurationInCycles billing cycles.
+	DurationRepeating Duration = "repeating"
+	// DurationForever discounts every billing cycle until removed.
+	DurationForever Duration = "forever"
+)
+
+// Coupon is an org-scoped discount definition that can be applied to
+// subscriptions.
+type Coupon struct {
+	ID               snowflake.ID      `gorm:"primaryKey" json:"id"`
+	OrgID            snowflake.ID      `gorm:"column:org_id;not null;index" json:"organization_id"`
+	Code             string            `gorm:"type:text;not null" json:"code"`
+	Name             string            `gorm:"type:text;not null" json:"name"`
+	DiscountType     DiscountType      `gorm:"column:discount_type;type:text;not null" json:"discount_type"`
+	PercentOff       *float64          `gorm:"column:percent_off;type:numeric(5,2)" json:"percent_off,omitempty"`
+	AmountOffCents   *int64            `gorm:"column:amount_off_cents" json:"amount_off_cents,omitempty"`
+	Currency         *string           `gorm:"type:text" json:"currency,omitempty"`
+	Duration         Duration          `gorm:"type:text;not null" json:"duration"`
+	DurationInCycles *int32            `gorm:"column:duration_in_cycles" json:"duration_in_cycles,omitempty"`
+	MaxRedemptions   *int32            `gorm:"column:max_redemptions" json:"max_redemptions,omitempty"`
+	TimesRedeemed    int32             `gorm:"column:times_redeemed;not null;default:0" json:"times_redeemed"`
+	RedeemBy         *time.Time        `gorm:"column:redeem_by" json:"redeem_by,omitempty"`
+	Active           bool              `gorm:"not null;default:true" json:"active"`
+	Metadata         datatypes.JSONMap `gorm:"type:jsonb" json:"metadata,omitempty"`
+	CreatedAt        time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (Coupon) TableName() string { return "coupons" }
+
+func (c *Coupon) Validate() error {
+	if strings.TrimSpace(c.Code) == "" {
+		return ErrInvalidCode
+	}
+	if strings.TrimSpace(c.Name) == "" {
+		return ErrInvalidName
+	}
+	switch c.DiscountType {
+	case DiscountTypePercent:
+		if c.PercentOff == nil || *c.PercentOff <= 0 || *c.PercentOff > 100 {
+			return ErrInvalidPercentOff
+		}
+		if c.AmountOffCents != nil {
+			return ErrInvalidAmountOff
+		}
+	case DiscountTypeFixedAmount:
+		if c.AmountOffCents == nil || *c.AmountOffCents <= 0 {
+			return ErrInvalidAmountOff
+		}
+		if c.PercentOff != nil {
+			return ErrInvalidPercentOff
+		}
+		if c.Currency == nil || *c.Currency == "" {
+			return ErrInvalidCurrency
+		}
+	default:
+		return ErrInvalidDiscountType
+	}
+	switch c.Duration {
+	case DurationOnce, DurationForever:
+		if c.DurationInCycles != nil {
+			return ErrInvalidDurationInCycles
+		}
+	case DurationRepeating:
+		if c.DurationInCycles == nil || *c.DurationInCycles <= 0 {
+			return ErrInvalidDurationInCycles
+		}
+	default:
+		return ErrInvalidDuration
+	}
+	if c.MaxRedemptions != nil && *c.MaxRedemptions <= 0 {
+		return ErrInvalidMaxRedemptions
+	}
+	return nil
+}
+
+// CycleLimit returns how many billing cycles the coupon discounts. ok is
+// false for coupons that last forever.
+func (c *Coupon) CycleLimit() (limit int, ok bool) {
+	switch c.Duration {
+	case DurationOnce:
+		return 1, true
+	case DurationRepeating:
+		if c.DurationInCycles != nil {
+			return int(*c.DurationInCycles), true
+		}
+	}
+	return 0, false
+}
+
+// SubscriptionDiscount binds a coupon to a subscription from StartAt until
+// it is removed.
+type SubscriptionDiscount struct {
+	ID             snowflake.ID `gorm:"primaryKey" json:"id"`
+	OrgID          snowflake.ID `gorm:"column:org_id;not null;index" json:"organization_id"`
+	SubscriptionID snowflake.ID `gorm:"column:subscription_id;not null;index" json:"subscription_id"`
+	CouponID       snowflake.ID `gorm:"column:coupon_id;not null" json:"coupon_id"`
+	StartAt        time.Time    `gorm:"column:start_at;not null" json:"start_at"`
+	EndedAt        *time.Time   `gorm:"column:ended_at" json:"ended_at,omitempty"`
+	CreatedAt      time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (SubscriptionDiscount) TableName() string { return "subscription_discounts" }
+
+// CycleDiscount is a subscription discount that applies to the billing cycle
+// being rated.
+type CycleDiscount struct {
+	DiscountID     snowflake.ID
+	CouponID       snowflake.ID
+	Code           string
+	DiscountType   DiscountType
+	PercentOff     *float64
+	AmountOffCents *int64
+	Currency       *string
+}
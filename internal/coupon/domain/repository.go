@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	InsertCoupon(ctx context.Context, db *gorm.DB, coupon *Coupon) error
+	FindCouponByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*Coupon, error)
+	FindCouponByCode(ctx context.Context, db *gorm.DB, orgID snowflake.ID, code string) (*Coupon, error)
+	ListCoupons(ctx context.Context, db *gorm.DB, orgID snowflake.ID, filter ListCouponsRequest) ([]Coupon, error)
+	// Redeem counts one redemption of the coupon and reports false when its
+	// redemption limit has been reached.
+	Redeem(ctx context.Context, db *gorm.DB, orgID, couponID snowflake.ID, now time.Time) (bool, error)
+
+	InsertDiscount(ctx context.Context, db *gorm.DB, discount *SubscriptionDiscount) error
+	FindDiscountByID(ctx context.Context, db *gorm.DB, orgID, subscriptionID, id snowflake.ID) (*SubscriptionDiscount, error)
+	FindActiveDiscount(ctx context.Context, db *gorm.DB, orgID, subscriptionID, couponID snowflake.ID) (*SubscriptionDiscount, error)
+	EndDiscount(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID, endedAt time.Time) error
+	// ListDiscountsForPeriod returns the discounts of a subscription active at
+	// any point of [start, end).
+	ListDiscountsForPeriod(ctx context.Context, db *gorm.DB, orgID, subscriptionID snowflake.ID, start, end time.Time) ([]SubscriptionDiscount, error)
+	// CountCyclesBetween counts the billing cycles of a subscription that end
+	// after since and start before before.
+	CountCyclesBetween(ctx context.Context, db *gorm.DB, orgID, subscriptionID snowflake.ID, since, before time.Time) (int64, error)
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	CreateCoupon(ctx context.Context, req CreateCouponRequest) (*Coupon, error)
+	ListCoupons(ctx context.Context, req ListCouponsRequest) ([]Coupon, error)
+	ApplyToSubscription(ctx context.Context, req ApplyRequest) (*SubscriptionDiscount, error)
+	RemoveFromSubscription(ctx context.Context, subscriptionID, discountID string) error
+}
+
+// DiscountResolver returns the discounts rating applies to a billing cycle.
+type DiscountResolver interface {
+	ResolveCycleDiscounts(ctx context.Context, db *gorm.DB, orgID, subscriptionID snowflake.ID, periodStart, periodEnd time.Time) ([]CycleDiscount, error)
+}
+
+type CreateCouponRequest struct {
+	Code             string         `json:"code"`
+	Name             string         `json:"name"`
+	DiscountType     DiscountType   `json:"discount_type"`
+	PercentOff       *float64       `json:"percent_off"`
+	AmountOffCents   *int64         `json:"amount_off_cents"`
+	Currency         *string        `json:"currency"`
+	Duration         Duration       `json:"duration"`
+	DurationInCycles *int32         `json:"duration_in_cycles"`
+	MaxRedemptions   *int32         `json:"max_redemptions"`
+	RedeemBy         *time.Time     `json:"redeem_by"`
+	Metadata         map[string]any `json:"metadata"`
+}
+
+type ListCouponsRequest struct {
+	Code   string
+	Active *bool
+}
+
+// ApplyRequest applies a coupon, given by ID or code, to a subscription.
+type ApplyRequest struct {
+	SubscriptionID string
+	CouponID       string
+	CouponCode     string
+}
+
+var (
+	ErrInvalidOrganization     = errors.New("invalid_organization")
+	ErrInvalidID               = errors.New("invalid_id")
+	ErrInvalidSubscription     = errors.New("invalid_subscription")
+	ErrInvalidCode             = errors.New("invalid_code")
+	ErrInvalidName             = errors.New("invalid_name")
+	ErrInvalidDiscountType     = errors.New("invalid_discount_type")
+	ErrInvalidPercentOff       = errors.New("invalid_percent_off")
+	ErrInvalidAmountOff        = errors.New("invalid_amount_off")
+	ErrInvalidCurrency         = errors.New("invalid_currency")
+	ErrInvalidDuration         = errors.New("invalid_duration")
+	ErrInvalidDurationInCycles = errors.New("invalid_duration_in_cycles")
+	ErrInvalidMaxRedemptions   = errors.New("invalid_max_redemptions")
+	ErrInvalidRedeemBy         = errors.New("invalid_redeem_by")
+	ErrCodeAlreadyExists       = errors.New("coupon_code_already_exists")
+	ErrCouponNotFound          = errors.New("coupon_not_found")
+	ErrCouponInactive          = errors.New("coupon_inactive")
+	ErrCouponExpired           = errors.New("coupon_expired")
+	ErrMaxRedemptionsReached   = errors.New("coupon_max_redemptions_reached")
+	ErrCouponAlreadyApplied    = errors.New("coupon_already_applied")
+	ErrSubscriptionNotFound    = errors.New("subscription_not_found")
+	ErrSubscriptionNotActive   = errors.New("subscription_not_active")
+	ErrDiscountNotFound        = errors.New("discount_not_found")
+)
@@ -0,0 +1,13 @@
+package coupon
+
+import (
+	"github.com/railzwaylabs/railzway/internal/coupon/repository"
+	"github.com/railzwaylabs/railzway/internal/coupon/service"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("coupon.service",
+	fx.Provide(repository.Provide),
+	fx.Provide(service.NewResolver),
+	fx.Provide(service.NewService),
+)
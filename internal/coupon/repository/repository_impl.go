@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	coupondomain "github.com/railzwaylabs/railzway/internal/coupon/domain"
+	"gorm.io/gorm"
+)
+
+type repo struct{}
+
+func Provide() coupondomain.Repository {
+	return &repo{}
+}
+
+const couponColumns = `id, org_id, code, name, discount_type, percent_off, amount_off_cents, currency,
+		        duration, duration_in_cycles, max_redemptions, times_redeemed, redeem_by, active,
+		        metadata, created_at, updated_at`
+
+const discountColumns = `id, org_id, subscription_id, coupon_id, start_at, ended_at, created_at, updated_at`
+
+func (r *repo) InsertCoupon(ctx context.Context, db *gorm.DB, coupon *coupondomain.Coupon) error {
+	return db.WithContext(ctx).Exec(
+		`INSERT INTO coupons (
+			id, org_id, code, name, discount_type, percent_off, amount_off_cents, currency,
+			duration, duration_in_cycles, max_redemptions, times_redeemed, redeem_by, active,
+			metadata, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		coupon.ID,
+		coupon.OrgID,
+		coupon.Code,
+		coupon.Name,
+		coupon.DiscountType,
+		coupon.PercentOff,
+		coupon.AmountOffCents,
+		coupon.Currency,
+		coupon.Duration,
+		coupon.DurationInCycles,
+		coupon.MaxRedemptions,
+		coupon.TimesRedeemed,
+		coupon.RedeemBy,
+		coupon.Active,
+		coupon.Metadata,
+		coupon.CreatedAt,
+		coupon.UpdatedAt,
+	).Error
+}
+
+func (r *repo) FindCouponByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*coupondomain.Coupon, error) {
+	var coupon coupondomain.Coupon
+	err := db.WithContext(ctx).Raw(
+		`SELECT `+couponColumns+`
+		 FROM coupons
+		 WHERE org_id = ? AND id = ?`,
+		orgID, id,
+	).Scan(&coupon).Error
+	if err != nil {
+		return nil, err
+	}
+	if coupon.ID == 0 {
+		return nil, nil
+	}
+	return &coupon, nil
+}
+
+func (r *repo) FindCouponByCode(ctx context.Context, db *gorm.DB, orgID snowflake.ID, code string) (*coupondomain.Coupon, error) {
+	var coupon coupondomain.Coupon
+	err := db.WithContext(ctx).Raw(
+		`SELECT `+couponColumns+`
+		 FROM coupons
+		 WHERE org_id = ? AND code = ?`,
+		orgID, code,
+	).Scan(&coupon).Error
+	if err != nil {
+		return nil, err
+	}
+	if coupon.ID == 0 {
+		return nil, nil
+	}
+	return &coupon, nil
+}
+
+func (r *repo) ListCoupons(ctx context.Context, db *gorm.DB, orgID snowflake.ID, filter coupondomain.ListCouponsRequest) ([]coupondomain.Coupon, error) {
+	query := db.WithContext(ctx).
+		Model(&coupondomain.Coupon{}).
+		Where("org_id = ?", orgID)
+	if filter.Code != "" {
+		query = query.Where("code = ?", filter.Code)
+	}
+	if filter.Active != nil {
+		query = query.Where("active = ?", *filter.Active)
+	}
+
+	var coupons []coupondomain.Coupon
+	if err := query.Order("created_at DESC").Order("id DESC").Find(&coupons).Error; err != nil {
+		return nil, err
+	}
+	return coupons, nil
+}
+
+func (r *repo) Redeem(ctx context.Context, db *gorm.DB, orgID, couponID snowflake.ID, now time.Time) (bool, error) {
+	result := db.WithContext(ctx).Exec(
+		`UPDATE coupons
+		 SET times_redeemed = times_redeemed + 1, updated_at = ?
+		 WHERE org_id = ? AND id = ?
+		   AND (max_redemptions IS NULL OR times_redeemed < max_redemptions)`,
+		now, orgID, couponID,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *repo) InsertDiscount(ctx context.Context, db *gorm.DB, discount *coupondomain.SubscriptionDiscount) error {
+	return db.WithContext(ctx).Exec(
+		`INSERT INTO subscription_discounts (
+			id, org_id, subscription_id, coupon_id, start_at, ended_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		discount.ID,
+		discount.OrgID,
+		discount.SubscriptionID,
+		discount.CouponID,
+		discount.StartAt,
+		discount.EndedAt,
+		discount.CreatedAt,
+		discount.UpdatedAt,
+	).Error
+}
+
+func (r *repo) FindDiscountByID(ctx context.Context, db *gorm.DB, orgID, subscriptionID, id snowflake.ID) (*coupondomain.SubscriptionDiscount, error) {
+	var discount coupondomain.SubscriptionDiscount
+	err := db.WithContext(ctx).Raw(
+		`SELECT `+discountColumns+`
+		 FROM subscription_discounts
+		 WHERE org_id = ? AND subscription_id = ? AND id = ?`,
+		orgID, subscriptionID, id,
+	).Scan(&discount).Error
+	if err != nil {
+		return nil, err
+	}
+	if discount.ID == 0 {
+		return nil, nil
+	}
+	return &discount, nil
+}
+
+func (r *repo) FindActiveDiscount(ctx context.Context, db *gorm.DB, orgID, subscriptionID, couponID snowflake.ID) (*coupondomain.SubscriptionDiscount, error) {
+	var discount coupondomain.SubscriptionDiscount
+	err := db.WithContext(ctx).Raw(
+		`SELECT `+discountColumns+`
+		 FROM subscription_discounts
+		 WHERE org_id = ? AND subscription_id = ? AND coupon_id = ? AND ended_at IS NULL`,
+		orgID, subscriptionID, couponID,
+	).Scan(&discount).Error
+	if err != nil {
+		return nil, err
+	}
+	if discount.ID == 0 {
+		return nil, nil
+	}
+	return &discount, nil
+}
+
+func (r *repo) EndDiscount(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID, endedAt time.Time) error {
+	return db.WithContext(ctx).Exec(
+		`UPDATE subscription_discounts
+		 SET ended_at = ?, updated_at = ?
+		 WHERE org_id = ? AND id = ? AND ended_at IS NULL`,
+		endedAt, endedAt, orgID, id,
+	).Error
+}
+
+func (r *repo) ListDiscountsForPeriod(ctx context.Context, db *gorm.DB, orgID, subscriptionID snowflake.ID, start, end time.Time) ([]coupondomain.SubscriptionDiscount, error) {
+	var discounts []coupondomain.SubscriptionDiscount
+	err := db.WithContext(ctx).Raw(
+		`SELECT `+discountColumns+`
+		 FROM subscription_discounts
+		 WHERE org_id = ? AND subscription_id = ?
+		   AND start_at < ?
+		   AND (ended_at IS NULL OR ended_at > ?)
+		 ORDER BY start_at ASC, id ASC`,
+		orgID, subscriptionID, end, start,
+	).Scan(&discounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return discounts, nil
+}
+
+func (r *repo) CountCyclesBetween(ctx context.Context, db *gorm.DB, orgID, subscriptionID snowflake.ID, since, before time.Time) (int64, error) {
+	var count int64
+	err := db.WithContext(ctx).Raw(
+		`SELECT COUNT(1)
+		 FROM billing_cycles
+		 WHERE org_id = ? AND subscription_id = ?
+		   AND period_end > ?
+		   AND period_start < ?`,
+		orgID, subscriptionID, since, before,
+	).Scan(&count).Error
+	return count, err
+}
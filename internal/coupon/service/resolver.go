@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	coupondomain "github.com/railzwaylabs/railzway/internal/coupon/domain"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+type ResolverParams struct {
+	fx.In
+
+	Repo coupondomain.Repository
+}
+
+type Resolver struct {
+	repo coupondomain.Repository
+}
+
+func NewResolver(p ResolverParams) coupondomain.DiscountResolver {
+	return &Resolver{repo: p.Repo}
+}
+
+// ResolveCycleDiscounts returns the discounts active during the cycle that
+// have not used up their duration. A discount's first cycle is the one it was
+// applied in, so a once coupon applied mid-cycle covers that cycle only.
+func (r *Resolver) ResolveCycleDiscounts(
+	ctx context.Context,
+	db *gorm.DB,
+	orgID, subscriptionID snowflake.ID,
+	periodStart, periodEnd time.Time,
+) ([]coupondomain.CycleDiscount, error) {
+	discounts, err := r.repo.ListDiscountsForPeriod(ctx, db, orgID, subscriptionID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]coupondomain.CycleDiscount, 0, len(discounts))
+	for _, discount := range discounts {
+		coupon, err := r.repo.FindCouponByID(ctx, db, orgID, discount.CouponID)
+		if err != nil {
+			return nil, err
+		}
+		if coupon == nil {
+			continue
+		}
+
+		if limit, ok := coupon.CycleLimit(); ok {
+			covered, err := r.repo.CountCyclesBetween(ctx, db, orgID, subscriptionID, discount.StartAt, periodStart)
+			if err != nil {
+				return nil, err
+			}
+			if covered >= int64(limit) {
+				continue
+			}
+		}
+
+		resolved = append(resolved, coupondomain.CycleDiscount{
+			DiscountID:     discount.ID,
+			CouponID:       coupon.ID,
+			Code:           coupon.Code,
+			DiscountType:   coupon.DiscountType,
+			PercentOff:     coupon.PercentOff,
+			AmountOffCents: coupon.AmountOffCents,
+			Currency:       coupon.Currency,
+		})
+	}
+	return resolved, nil
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	coupondomain "github.com/railzwaylabs/railzway/internal/coupon/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type Params struct {
+	fx.In
+
+	DB    *gorm.DB
+	Log   *zap.Logger
+	GenID *snowflake.Node
+	Clock clock.Clock
+	Repo  coupondomain.Repository
+}
+
+type Service struct {
+	db    *gorm.DB
+	log   *zap.Logger
+	genID *snowflake.Node
+	clock clock.Clock
+	repo  coupondomain.Repository
+}
+
+func NewService(p Params) coupondomain.Service {
+	return &Service{
+		db:    p.DB,
+		log:   p.Log.Named("coupon.service"),
+		genID: p.GenID,
+		clock: p.Clock,
+		repo:  p.Repo,
+	}
+}
+
+func (s *Service) CreateCoupon(ctx context.Context, req coupondomain.CreateCouponRequest) (*coupondomain.Coupon, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, coupondomain.ErrInvalidOrganization
+	}
+
+	var currency *string
+	if req.Currency != nil {
+		value := strings.ToUpper(strings.TrimSpace(*req.Currency))
+		currency = &value
+	}
+	metadata := datatypes.JSONMap{}
+	for key, value := range req.Metadata {
+		metadata[key] = value
+	}
+
+	now := s.clock.Now(ctx).UTC()
+	if req.RedeemBy != nil && !req.RedeemBy.After(now) {
+		return nil, coupondomain.ErrInvalidRedeemBy
+	}
+
+	coupon := &coupondomain.Coupon{
+		ID:               s.genID.Generate(),
+		OrgID:            orgID,
+		Code:             normalizeCode(req.Code),
+		Name:             strings.TrimSpace(req.Name),
+		DiscountType:     coupondomain.DiscountType(strings.ToLower(strings.TrimSpace(string(req.DiscountType)))),
+		PercentOff:       req.PercentOff,
+		AmountOffCents:   req.AmountOffCents,
+		Currency:         currency,
+		Duration:         coupondomain.Duration(strings.ToLower(strings.TrimSpace(string(req.Duration)))),
+		DurationInCycles: req.DurationInCycles,
+		MaxRedemptions:   req.MaxRedemptions,
+		RedeemBy:         req.RedeemBy,
+		Active:           true,
+		Metadata:         metadata,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := coupon.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindCouponByCode(ctx, s.db, orgID, coupon.Code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, coupondomain.ErrCodeAlreadyExists
+	}
+
+	if err := s.repo.InsertCoupon(ctx, s.db, coupon); err != nil {
+		return nil, err
+	}
+	return coupon, nil
+}
+
+func (s *Service) ListCoupons(ctx context.Context, req coupondomain.ListCouponsRequest) ([]coupondomain.Coupon, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, coupondomain.ErrInvalidOrganization
+	}
+
+	filter := coupondomain.ListCouponsRequest{Active: req.Active}
+	if code := strings.TrimSpace(req.Code); code != "" {
+		filter.Code = normalizeCode(code)
+	}
+
+	coupons, err := s.repo.ListCoupons(ctx, s.db, orgID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if coupons == nil {
+		coupons = []coupondomain.Coupon{}
+	}
+	return coupons, nil
+}
+
+// ApplyToSubscription starts discounting a subscription with a coupon. Each
+// application counts as one redemption of the coupon.
+func (s *Service) ApplyToSubscription(ctx context.Context, req coupondomain.ApplyRequest) (*coupondomain.SubscriptionDiscount, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, coupondomain.ErrInvalidOrganization
+	}
+	subscriptionID, err := snowflake.ParseString(strings.TrimSpace(req.SubscriptionID))
+	if err != nil {
+		return nil, coupondomain.ErrInvalidSubscription
+	}
+
+	if err := s.ensureSubscriptionDiscountable(ctx, orgID, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	coupon, err := s.resolveCoupon(ctx, orgID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now(ctx).UTC()
+	if !coupon.Active {
+		return nil, coupondomain.ErrCouponInactive
+	}
+	if coupon.RedeemBy != nil && !coupon.RedeemBy.After(now) {
+		return nil, coupondomain.ErrCouponExpired
+	}
+
+	discount := &coupondomain.SubscriptionDiscount{
+		ID:             s.genID.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subscriptionID,
+		CouponID:       coupon.ID,
+		StartAt:        now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.FindActiveDiscount(ctx, tx, orgID, subscriptionID, coupon.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return coupondomain.ErrCouponAlreadyApplied
+		}
+
+		redeemed, err := s.repo.Redeem(ctx, tx, orgID, coupon.ID, now)
+		if err != nil {
+			return err
+		}
+		if !redeemed {
+			return coupondomain.ErrMaxRedemptionsReached
+		}
+
+		return s.repo.InsertDiscount(ctx, tx, discount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return discount, nil
+}
+
+// RemoveFromSubscription stops a discount. Cycles it already covered keep
+// their discount; removing an ended discount is a no-op.
+func (s *Service) RemoveFromSubscription(ctx context.Context, subscriptionID, discountID string) error {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return coupondomain.ErrInvalidOrganization
+	}
+	subID, err := snowflake.ParseString(strings.TrimSpace(subscriptionID))
+	if err != nil {
+		return coupondomain.ErrInvalidSubscription
+	}
+	id, err := snowflake.ParseString(strings.TrimSpace(discountID))
+	if err != nil {
+		return coupondomain.ErrInvalidID
+	}
+
+	discount, err := s.repo.FindDiscountByID(ctx, s.db, orgID, subID, id)
+	if err != nil {
+		return err
+	}
+	if discount == nil {
+		return coupondomain.ErrDiscountNotFound
+	}
+	if discount.EndedAt != nil {
+		return nil
+	}
+
+	return s.repo.EndDiscount(ctx, s.db, orgID, discount.ID, s.clock.Now(ctx).UTC())
+}
+
+func (s *Service) resolveCoupon(ctx context.Context, orgID snowflake.ID, req coupondomain.ApplyRequest) (*coupondomain.Coupon, error) {
+	var (
+		coupon *coupondomain.Coupon
+		err    error
+	)
+	switch {
+	case strings.TrimSpace(req.CouponID) != "":
+		id, parseErr := snowflake.ParseString(strings.TrimSpace(req.CouponID))
+		if parseErr != nil {
+			return nil, coupondomain.ErrInvalidID
+		}
+		coupon, err = s.repo.FindCouponByID(ctx, s.db, orgID, id)
+	case strings.TrimSpace(req.CouponCode) != "":
+		coupon, err = s.repo.FindCouponByCode(ctx, s.db, orgID, normalizeCode(req.CouponCode))
+	default:
+		return nil, coupondomain.ErrInvalidCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	if coupon == nil {
+		return nil, coupondomain.ErrCouponNotFound
+	}
+	return coupon, nil
+}
+
+func (s *Service) ensureSubscriptionDiscountable(ctx context.Context, orgID, subscriptionID snowflake.ID) error {
+	var row struct {
+		ID     snowflake.ID                          `gorm:"column:id"`
+		Status subscriptiondomain.SubscriptionStatus `gorm:"column:status"`
+	}
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT id, status FROM subscriptions WHERE org_id = ? AND id = ?`,
+		orgID, subscriptionID,
+	).Scan(&row).Error; err != nil {
+		return err
+	}
+	if row.ID == 0 {
+		return coupondomain.ErrSubscriptionNotFound
+	}
+	switch row.Status {
+	case subscriptiondomain.SubscriptionStatusCanceled, subscriptiondomain.SubscriptionStatusEnded:
+		return coupondomain.ErrSubscriptionNotActive
+	}
+	return nil
+}
+
+// normalizeCode makes coupon codes case-insensitive.
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
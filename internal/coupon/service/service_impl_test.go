@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	coupondomain "github.com/railzwaylabs/railzway/internal/coupon/domain"
+	"github.com/railzwaylabs/railzway/internal/coupon/repository"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestApplyToSubscription_EnforcesRedemptionsAndDuration(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&billingcycledomain.BillingCycle{},
+		&coupondomain.Coupon{},
+		&coupondomain.SubscriptionDiscount{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(now)
+	repo := repository.Provide()
+	svc := NewService(Params{DB: db, Log: zap.NewNop(), GenID: node, Clock: fakeClock, Repo: repo})
+	resolver := NewResolver(ResolverParams{Repo: repo})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	subscribe := func(status subscriptiondomain.SubscriptionStatus) snowflake.ID {
+		sub := subscriptiondomain.Subscription{
+			ID:               node.Generate(),
+			OrgID:            orgID,
+			CustomerID:       node.Generate(),
+			Status:           status,
+			BillingCycleType: "monthly",
+			StartAt:          now.AddDate(0, -2, 0),
+		}
+		require.NoError(t, db.Create(&sub).Error)
+		return sub.ID
+	}
+
+	percent := 150.0
+	_, err = svc.CreateCoupon(ctx, coupondomain.CreateCouponRequest{
+		Code:         "too-much",
+		Name:         "Too much",
+		DiscountType: coupondomain.DiscountTypePercent,
+		PercentOff:   &percent,
+		Duration:     coupondomain.DurationForever,
+	})
+	assert.ErrorIs(t, err, coupondomain.ErrInvalidPercentOff)
+
+	amountOff := int64(500)
+	oneRedemption := int32(1)
+	coupon, err := svc.CreateCoupon(ctx, coupondomain.CreateCouponRequest{
+		Code:           "welcome",
+		Name:           "Welcome",
+		DiscountType:   coupondomain.DiscountTypeFixedAmount,
+		AmountOffCents: &amountOff,
+		Currency:       ptr("eur"),
+		Duration:       coupondomain.DurationOnce,
+		MaxRedemptions: &oneRedemption,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "WELCOME", coupon.Code)
+	assert.Equal(t, "EUR", *coupon.Currency)
+
+	_, err = svc.CreateCoupon(ctx, coupondomain.CreateCouponRequest{
+		Code:           "Welcome",
+		Name:           "Again",
+		DiscountType:   coupondomain.DiscountTypeFixedAmount,
+		AmountOffCents: &amountOff,
+		Currency:       ptr("EUR"),
+		Duration:       coupondomain.DurationOnce,
+	})
+	assert.ErrorIs(t, err, coupondomain.ErrCodeAlreadyExists)
+
+	_, err = svc.ApplyToSubscription(ctx, coupondomain.ApplyRequest{
+		SubscriptionID: subscribe(subscriptiondomain.SubscriptionStatusCanceled).String(),
+		CouponCode:     "welcome",
+	})
+	assert.ErrorIs(t, err, coupondomain.ErrSubscriptionNotActive)
+
+	subID := subscribe(subscriptiondomain.SubscriptionStatusActive)
+	discount, err := svc.ApplyToSubscription(ctx, coupondomain.ApplyRequest{
+		SubscriptionID: subID.String(),
+		CouponCode:     "welcome",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, coupon.ID, discount.CouponID)
+	assert.True(t, discount.StartAt.Equal(now))
+
+	_, err = svc.ApplyToSubscription(ctx, coupondomain.ApplyRequest{
+		SubscriptionID: subID.String(),
+		CouponID:       coupon.ID.String(),
+	})
+	assert.ErrorIs(t, err, coupondomain.ErrCouponAlreadyApplied)
+
+	_, err = svc.ApplyToSubscription(ctx, coupondomain.ApplyRequest{
+		SubscriptionID: subscribe(subscriptiondomain.SubscriptionStatusActive).String(),
+		CouponCode:     "welcome",
+	})
+	assert.ErrorIs(t, err, coupondomain.ErrMaxRedemptionsReached)
+
+	// The once coupon discounts the cycle it was applied in and no later one.
+	march := now.AddDate(0, 0, -9)
+	for _, start := range []time.Time{march, march.AddDate(0, 1, 0)} {
+		require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			PeriodStart:    start,
+			PeriodEnd:      start.AddDate(0, 1, 0),
+			Status:         billingcycledomain.BillingCycleStatusOpen,
+		}).Error)
+	}
+	discounts, err := resolver.ResolveCycleDiscounts(ctx, db, orgID, subID, march, march.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	require.Len(t, discounts, 1)
+	assert.Equal(t, discount.ID, discounts[0].DiscountID)
+	assert.Equal(t, int64(500), *discounts[0].AmountOffCents)
+
+	discounts, err = resolver.ResolveCycleDiscounts(ctx, db, orgID, subID, march.AddDate(0, 1, 0), march.AddDate(0, 2, 0))
+	require.NoError(t, err)
+	assert.Empty(t, discounts)
+
+	fakeClock.Advance(24 * time.Hour)
+	require.NoError(t, svc.RemoveFromSubscription(ctx, subID.String(), discount.ID.String()))
+	require.NoError(t, svc.RemoveFromSubscription(ctx, subID.String(), discount.ID.String()))
+	assert.ErrorIs(t, svc.RemoveFromSubscription(ctx, subID.String(), node.Generate().String()), coupondomain.ErrDiscountNotFound)
+
+	var stored coupondomain.SubscriptionDiscount
+	require.NoError(t, db.First(&stored, "id = ?", discount.ID).Error)
+	require.NotNil(t, stored.EndedAt)
+	assert.True(t, stored.EndedAt.Equal(now.Add(24*time.Hour)))
+
+	coupons, err := svc.ListCoupons(ctx, coupondomain.ListCouponsRequest{Code: "welcome"})
+	require.NoError(t, err)
+	require.Len(t, coupons, 1)
+	assert.Equal(t, int32(1), coupons[0].TimesRedeemed)
+}
+
+func ptr(value string) *string {
+	return &value
+}
@@ -0,0 +1,68 @@
+// Package currency is the single source of how many decimal places each
+// currency's minor unit has. Amounts are stored in minor units everywhere;
+// convert with ToMinorUnits and ToMajorUnits rather than multiplying by 100.
+package currency
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DefaultDecimals applies to currencies not listed in decimals.
+const DefaultDecimals = 2
+
+// decimals lists currencies whose minor unit is not two digits, per ISO 4217,
+// except IDR which is billed in whole rupiah.
+var decimals = map[string]int{
+	"BIF": 0,
+	"CLP": 0,
+	"DJF": 0,
+	"GNF": 0,
+	"IDR": 0,
+	"ISK": 0,
+	"JPY": 0,
+	"KMF": 0,
+	"KRW": 0,
+	"PYG": 0,
+	"RWF": 0,
+	"UGX": 0,
+	"VND": 0,
+	"VUV": 0,
+	"XAF": 0,
+	"XOF": 0,
+	"XPF": 0,
+	"BHD": 3,
+	"IQD": 3,
+	"JOD": 3,
+	"KWD": 3,
+	"LYD": 3,
+	"OMR": 3,
+	"TND": 3,
+}
+
+// Decimals returns the number of decimal places of code's minor unit. code is
+// case-insensitive.
+func Decimals(code string) int {
+	if d, ok := decimals[strings.ToUpper(strings.TrimSpace(code))]; ok {
+		return d
+	}
+	return DefaultDecimals
+}
+
+// ToMinorUnits converts a major unit amount, such as 19.99 USD, into minor
+// units, rounding to the nearest unit.
+func ToMinorUnits(amount float64, code string) int64 {
+	return int64(math.Round(amount * math.Pow10(Decimals(code))))
+}
+
+// ToMajorUnits converts a minor unit amount into major units.
+func ToMajorUnits(amount int64, code string) float64 {
+	return float64(amount) / math.Pow10(Decimals(code))
+}
+
+// Format renders a minor unit amount with code's decimal places followed by
+// the upper-cased code, such as "19.99 USD" or "1500 JPY".
+func Format(amount int64, code string) string {
+	return fmt.Sprintf("%.*f %s", Decimals(code), ToMajorUnits(amount, code), strings.ToUpper(strings.TrimSpace(code)))
+}
@@ -0,0 +1,42 @@
+package currency
+
+import "testing"
+
+func TestConversions(t *testing.T) {
+	cases := []struct {
+		code     string
+		decimals int
+		major    float64
+		minor    int64
+	}{
+		{code: "JPY", decimals: 0, major: 1500, minor: 1500},
+		{code: "IDR", decimals: 0, major: 150000, minor: 150000},
+		{code: "usd", decimals: 2, major: 19.99, minor: 1999},
+		{code: "BHD", decimals: 3, major: 12.345, minor: 12345},
+		{code: "", decimals: DefaultDecimals, major: 0.29, minor: 29},
+	}
+	for _, tc := range cases {
+		if got := Decimals(tc.code); got != tc.decimals {
+			t.Fatalf("expected %q to have %d decimals, got %d", tc.code, tc.decimals, got)
+		}
+		if got := ToMinorUnits(tc.major, tc.code); got != tc.minor {
+			t.Fatalf("expected %v %s to be %d minor units, got %d", tc.major, tc.code, tc.minor, got)
+		}
+		if got := ToMajorUnits(tc.minor, tc.code); got != tc.major {
+			t.Fatalf("expected %d %s minor units to be %v, got %v", tc.minor, tc.code, tc.major, got)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	cases := map[string]string{
+		"USD": "19.99 USD",
+		"jpy": "1999 JPY",
+		"BHD": "1.999 BHD",
+	}
+	for code, want := range cases {
+		if got := Format(1999, code); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+}
@@ -13,6 +13,12 @@ type Customer struct {
 	Name      string            `gorm:"not null" json:"name"`
 	Email     string            `gorm:"not null" json:"email"`
 	Currency  string            `gorm:"column:currency" json:"currency,omitempty"`
+	Country   string            `gorm:"column:country" json:"country,omitempty"`
+	State     string            `gorm:"column:state" json:"state,omitempty"`
+	TaxID     string            `gorm:"column:tax_id" json:"tax_id,omitempty"`
+	// PaymentTermsDays overrides the organization's net payment terms for
+	// this customer's invoices when set.
+	PaymentTermsDays *int       `gorm:"column:payment_terms_days" json:"payment_terms_days,omitempty"`
 	IdempotencyKey *string      `gorm:"column:idempotency_key" json:"-"`
 	Metadata  datatypes.JSONMap `gorm:"type:jsonb;not null;default:'{}'" json:"metadata,omitempty"`
 	CreatedAt time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
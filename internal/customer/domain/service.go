@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/railzwaylabs/railzway/pkg/db/pagination"
@@ -34,6 +35,13 @@ type ListCustomerResponse struct {
 type CreateCustomerRequest struct {
 	Name  string
 	Email string
+	// Country is an ISO 3166-1 alpha-2 code; State is the region within it.
+	// Together with TaxID they select the tax rules applied to invoices.
+	Country string
+	State   string
+	TaxID   string
+	// PaymentTermsDays overrides the organization's net payment terms.
+	PaymentTermsDays *int
 	IdempotencyKey string
 }
 
@@ -41,16 +49,95 @@ type GetCustomerRequest struct {
 	ID string
 }
 
+type UpdateCurrencyRequest struct {
+	ID       string
+	Currency string
+}
+
+// UpdatePaymentTermsRequest sets the customer's net payment terms. A nil
+// PaymentTermsDays clears the override so the organization's terms apply.
+type UpdatePaymentTermsRequest struct {
+	ID               string
+	PaymentTermsDays *int
+}
+
+// StatementRequest selects the statement window, [From, To). A nil To ends
+// the window now and a nil From starts it 30 days before To.
+type StatementRequest struct {
+	CustomerID string
+	From       *time.Time
+	To         *time.Time
+}
+
+// StatementLine is one movement on the customer's balance. Debits raise what
+// the customer owes (invoiced charges), credits lower it (payments, credit
+// notes). Balance is the running balance after the line.
+type StatementLine struct {
+	OccurredAt    time.Time `json:"occurred_at"`
+	Type          string    `json:"type"`
+	SourceID      string    `json:"source_id"`
+	InvoiceID     *string   `json:"invoice_id,omitempty"`
+	InvoiceNumber *string   `json:"invoice_number,omitempty"`
+	Debit         int64     `json:"debit"`
+	Credit        int64     `json:"credit"`
+	Balance       int64     `json:"balance"`
+}
+
+// Statement is a customer's account activity over a window, sourced from the
+// ledger's accounts receivable.
+type Statement struct {
+	CustomerID     string          `json:"customer_id"`
+	Currency       string          `json:"currency"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	OpeningBalance int64           `json:"opening_balance"`
+	TotalDebits    int64           `json:"total_debits"`
+	TotalCredits   int64           `json:"total_credits"`
+	ClosingBalance int64           `json:"closing_balance"`
+	Lines          []StatementLine `json:"lines"`
+}
+
 type Service interface {
 	Create(context.Context, CreateCustomerRequest) (Customer, error)
 	List(context.Context, ListCustomerRequest) (ListCustomerResponse, error)
 	GetByID(context.Context, GetCustomerRequest) (Customer, error)
+	GetStatement(context.Context, StatementRequest) (Statement, error)
+	// UpdateCurrency changes the customer's billing currency. It is refused
+	// while the customer has subscriptions or unpaid invoices in the old one.
+	UpdateCurrency(context.Context, UpdateCurrencyRequest) (Customer, error)
+	// UpdatePaymentTerms sets or clears the customer's net payment terms,
+	// which apply to invoices finalized afterwards.
+	UpdatePaymentTerms(context.Context, UpdatePaymentTermsRequest) (Customer, error)
 }
 
 var (
 	ErrInvalidOrganization = errors.New("invalid_organization")
 	ErrInvalidName         = errors.New("invalid_name")
 	ErrInvalidEmail        = errors.New("invalid_email")
+	ErrInvalidCountry      = errors.New("invalid_country")
 	ErrInvalidID           = errors.New("invalid_id")
 	ErrNotFound            = errors.New("not_found")
+	ErrInvalidPeriod       = errors.New("invalid_period")
+	ErrCurrencyNotSet      = errors.New("currency_not_set")
+	ErrInvalidCurrency     = errors.New("invalid_currency")
+	ErrInvalidPaymentTerms = errors.New("invalid_payment_terms")
+
+	ErrCurrencyChangeBlocked = errors.New("currency_change_blocked")
 )
+
+// CurrencyChangeBlockedError lists what keeps a customer on its current
+// currency: subscriptions that have not ended and invoices not yet paid.
+type CurrencyChangeBlockedError struct {
+	SubscriptionIDs []string
+	InvoiceIDs      []string
+}
+
+func (e *CurrencyChangeBlockedError) Error() string {
+	return fmt.Sprintf("currency change blocked by %d subscription(s) and %d unpaid invoice(s)",
+		len(e.SubscriptionIDs), len(e.InvoiceIDs))
+}
+
+// Unwrap keeps errors.Is(err, ErrCurrencyChangeBlocked) true.
+func (e *CurrencyChangeBlockedError) Unwrap() error {
+	return ErrCurrencyChangeBlocked
+}
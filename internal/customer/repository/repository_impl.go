@@ -18,13 +18,17 @@ func Provide() domain.Repository {
 
 func (r *repo) Insert(ctx context.Context, db *gorm.DB, customer *domain.Customer) error {
 	return db.WithContext(ctx).Exec(
-		`INSERT INTO customers (id, org_id, name, email, currency, idempotency_key, metadata, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO customers (id, org_id, name, email, currency, country, state, tax_id, payment_terms_days, idempotency_key, metadata, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		customer.ID,
 		customer.OrgID,
 		customer.Name,
 		customer.Email,
 		customer.Currency,
+		customer.Country,
+		customer.State,
+		customer.TaxID,
+		customer.PaymentTermsDays,
 		customer.IdempotencyKey,
 		customer.Metadata,
 		customer.CreatedAt,
@@ -35,7 +39,7 @@ func (r *repo) Insert(ctx context.Context, db *gorm.DB, customer *domain.Custome
 func (r *repo) FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*domain.Customer, error) {
 	var customer domain.Customer
 	err := db.WithContext(ctx).Raw(
-		`SELECT id, org_id, name, email, currency, idempotency_key, metadata, created_at, updated_at
+		`SELECT id, org_id, name, email, currency, country, state, tax_id, payment_terms_days, idempotency_key, metadata, created_at, updated_at
 		 FROM customers WHERE org_id = ? AND id = ?`,
 		orgID,
 		id,
@@ -52,7 +56,7 @@ func (r *repo) FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID
 func (r *repo) FindByIdempotencyKey(ctx context.Context, db *gorm.DB, orgID snowflake.ID, key string) (*domain.Customer, error) {
 	var customer domain.Customer
 	err := db.WithContext(ctx).Raw(
-		`SELECT id, org_id, name, email, currency, idempotency_key, metadata, created_at, updated_at
+		`SELECT id, org_id, name, email, currency, country, state, tax_id, payment_terms_days, idempotency_key, metadata, created_at, updated_at
 		 FROM customers WHERE org_id = ? AND idempotency_key = ? LIMIT 1`,
 		orgID,
 		key,
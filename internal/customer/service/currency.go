@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/customer/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"gorm.io/gorm"
+)
+
+// UpdateCurrency changes the currency new subscriptions of the customer are
+// billed in. Subscriptions keep the currency they were created with, so the
+// change is refused while one has not ended or an invoice is still unpaid;
+// otherwise charges in both currencies would land on the same account.
+func (s *Service) UpdateCurrency(ctx context.Context, req domain.UpdateCurrencyRequest) (domain.Customer, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return domain.Customer{}, domain.ErrInvalidOrganization
+	}
+
+	id, err := s.parseID(req.ID)
+	if err != nil {
+		return domain.Customer{}, err
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if !isCurrencyCode(currency) {
+		return domain.Customer{}, domain.ErrInvalidCurrency
+	}
+
+	var customer *domain.Customer
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		customer, err = s.repo.FindByID(ctx, tx, orgID, id)
+		if err != nil {
+			return err
+		}
+		if customer == nil {
+			return domain.ErrNotFound
+		}
+		if strings.EqualFold(strings.TrimSpace(customer.Currency), currency) {
+			return nil
+		}
+
+		blocked, err := s.currencyChangeBlockers(ctx, tx, orgID, id)
+		if err != nil {
+			return err
+		}
+		if blocked != nil {
+			return blocked
+		}
+
+		now := time.Now().UTC()
+		if err := tx.WithContext(ctx).Exec(
+			`UPDATE customers SET currency = ?, updated_at = ? WHERE org_id = ? AND id = ?`,
+			currency,
+			now,
+			orgID,
+			id,
+		).Error; err != nil {
+			return err
+		}
+		customer.Currency = currency
+		customer.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		return domain.Customer{}, err
+	}
+
+	return *customer, nil
+}
+
+// currencyChangeBlockers returns nil when nothing is billed in the customer's
+// current currency any more.
+func (s *Service) currencyChangeBlockers(ctx context.Context, tx *gorm.DB, orgID, customerID snowflake.ID) (*domain.CurrencyChangeBlockedError, error) {
+	var subscriptionIDs []snowflake.ID
+	if err := tx.WithContext(ctx).Raw(
+		`SELECT id FROM subscriptions
+		 WHERE org_id = ? AND customer_id = ? AND status IN (?, ?, ?, ?)
+		 ORDER BY id ASC`,
+		orgID,
+		customerID,
+		subscriptiondomain.SubscriptionStatusDraft,
+		subscriptiondomain.SubscriptionStatusActive,
+		subscriptiondomain.SubscriptionStatusPastDue,
+		subscriptiondomain.SubscriptionStatusPaused,
+	).Scan(&subscriptionIDs).Error; err != nil {
+		return nil, err
+	}
+
+	var invoiceIDs []snowflake.ID
+	if err := tx.WithContext(ctx).Raw(
+		`SELECT id FROM invoices
+		 WHERE org_id = ? AND customer_id = ? AND status <> ?
+		   AND paid_at IS NULL AND total_amount > amount_paid
+		 ORDER BY id ASC`,
+		orgID,
+		customerID,
+		invoicedomain.InvoiceStatusVoid,
+	).Scan(&invoiceIDs).Error; err != nil {
+		return nil, err
+	}
+
+	if len(subscriptionIDs) == 0 && len(invoiceIDs) == 0 {
+		return nil, nil
+	}
+	blocked := &domain.CurrencyChangeBlockedError{
+		SubscriptionIDs: make([]string, 0, len(subscriptionIDs)),
+		InvoiceIDs:      make([]string, 0, len(invoiceIDs)),
+	}
+	for _, id := range subscriptionIDs {
+		blocked.SubscriptionIDs = append(blocked.SubscriptionIDs, id.String())
+	}
+	for _, id := range invoiceIDs {
+		blocked.InvoiceIDs = append(blocked.InvoiceIDs, id.String())
+	}
+	return blocked, nil
+}
+
+// isCurrencyCode reports whether value looks like an ISO 4217 code.
+func isCurrencyCode(value string) bool {
+	if len(value) != 3 {
+		return false
+	}
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
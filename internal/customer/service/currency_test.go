@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/customer/domain"
+	"github.com/railzwaylabs/railzway/internal/customer/repository"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestUpdateCurrency_BlockedWhileBilling(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Customer{},
+		&subscriptiondomain.Subscription{},
+		&invoicedomain.Invoice{},
+	))
+
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	orgID := node.Generate()
+	now := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	svc := New(Params{DB: db, Log: zap.NewNop(), GenID: node, Repo: repository.Provide()})
+
+	customer := domain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Acme", Email: "billing@acme.test", Currency: "EUR", Metadata: datatypes.JSONMap{}}
+	require.NoError(t, db.Create(&customer).Error)
+
+	sub := subscriptiondomain.Subscription{
+		ID:               node.Generate(),
+		OrgID:            orgID,
+		CustomerID:       customer.ID,
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		CollectionMode:   subscriptiondomain.SubscriptionCollectionModeSendInvoice,
+		StartAt:          now.AddDate(0, -3, 0),
+		BillingCycleType: "MONTHLY",
+	}
+	require.NoError(t, db.Create(&sub).Error)
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		InvoiceNumber:  "INV-1",
+		SubscriptionID: sub.ID,
+		CustomerID:     customer.ID,
+		Status:         invoicedomain.InvoiceStatusFinalized,
+		TotalAmount:    5000,
+		AmountPaid:     2000,
+		Currency:       "EUR",
+		Metadata:       datatypes.JSONMap{},
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+
+	update := func(currency string) (domain.Customer, error) {
+		return svc.UpdateCurrency(ctx, domain.UpdateCurrencyRequest{ID: customer.ID.String(), Currency: currency})
+	}
+
+	_, err = update("usd1")
+	assert.ErrorIs(t, err, domain.ErrInvalidCurrency)
+
+	_, err = update("usd")
+	var blocked *domain.CurrencyChangeBlockedError
+	require.ErrorAs(t, err, &blocked)
+	assert.ErrorIs(t, err, domain.ErrCurrencyChangeBlocked)
+	assert.Equal(t, []string{sub.ID.String()}, blocked.SubscriptionIDs)
+	assert.Equal(t, []string{invoice.ID.String()}, blocked.InvoiceIDs)
+
+	// Setting the currency the customer already has changes nothing.
+	_, err = update("eur")
+	require.NoError(t, err)
+
+	require.NoError(t, db.Model(&subscriptiondomain.Subscription{}).Where("id = ?", sub.ID).
+		Update("status", subscriptiondomain.SubscriptionStatusCanceled).Error)
+	require.NoError(t, db.Model(&invoicedomain.Invoice{}).Where("id = ?", invoice.ID).
+		Updates(map[string]any{"amount_paid": 5000, "paid_at": now}).Error)
+
+	updated, err := update("usd")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", updated.Currency)
+
+	var stored domain.Customer
+	require.NoError(t, db.First(&stored, "id = ?", customer.ID).Error)
+	assert.Equal(t, "USD", stored.Currency)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/railzwaylabs/railzway/internal/customer/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"gorm.io/gorm"
+)
+
+// UpdatePaymentTerms sets how many days after finalization the customer's
+// invoices are due. Invoices already finalized keep the due date they were
+// issued with.
+func (s *Service) UpdatePaymentTerms(ctx context.Context, req domain.UpdatePaymentTermsRequest) (domain.Customer, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return domain.Customer{}, domain.ErrInvalidOrganization
+	}
+
+	id, err := s.parseID(req.ID)
+	if err != nil {
+		return domain.Customer{}, err
+	}
+
+	var days *int
+	if req.PaymentTermsDays != nil {
+		if !validPaymentTermsDays(*req.PaymentTermsDays) {
+			return domain.Customer{}, domain.ErrInvalidPaymentTerms
+		}
+		value := *req.PaymentTermsDays
+		days = &value
+	}
+
+	var customer *domain.Customer
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		customer, err = s.repo.FindByID(ctx, tx, orgID, id)
+		if err != nil {
+			return err
+		}
+		if customer == nil {
+			return domain.ErrNotFound
+		}
+
+		now := time.Now().UTC()
+		if err := tx.WithContext(ctx).Exec(
+			`UPDATE customers SET payment_terms_days = ?, updated_at = ? WHERE org_id = ? AND id = ?`,
+			days,
+			now,
+			orgID,
+			id,
+		).Error; err != nil {
+			return err
+		}
+		customer.PaymentTermsDays = days
+		customer.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		return domain.Customer{}, err
+	}
+
+	return *customer, nil
+}
+
+// validPaymentTermsDays reports whether days is within the net terms an
+// organization may configure: due on finalization up to one year out.
+func validPaymentTermsDays(days int) bool {
+	return days >= 0 && days <= organizationdomain.MaxPaymentTermsDays
+}
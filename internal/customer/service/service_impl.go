@@ -8,6 +8,7 @@ import (
 
 	"github.com/bwmarrin/snowflake"
 	"github.com/railzwaylabs/railzway/internal/customer/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
 	quotadomain "github.com/railzwaylabs/railzway/internal/quota/domain"
 	"github.com/railzwaylabs/railzway/pkg/db/pagination"
@@ -20,28 +21,31 @@ import (
 type Params struct {
 	fx.In
 
-	DB       *gorm.DB
-	Log      *zap.Logger
-	GenID    *snowflake.Node
-	Repo     domain.Repository
-	QuotaSvc quotadomain.Service
+	DB        *gorm.DB
+	Log       *zap.Logger
+	GenID     *snowflake.Node
+	Repo      domain.Repository
+	QuotaSvc  quotadomain.Service
+	LedgerSvc ledgerdomain.Service
 }
 
 type Service struct {
-	db       *gorm.DB
-	log      *zap.Logger
-	genID    *snowflake.Node
-	repo     domain.Repository
-	quotaSvc quotadomain.Service
+	db        *gorm.DB
+	log       *zap.Logger
+	genID     *snowflake.Node
+	repo      domain.Repository
+	quotaSvc  quotadomain.Service
+	ledgerSvc ledgerdomain.Service
 }
 
 func New(p Params) domain.Service {
 	return &Service{
-		db:       p.DB,
-		log:      p.Log.Named("customer.service"),
-		genID:    p.GenID,
-		repo:     p.Repo,
-		quotaSvc: p.QuotaSvc,
+		db:        p.DB,
+		log:       p.Log.Named("customer.service"),
+		genID:     p.GenID,
+		repo:      p.Repo,
+		quotaSvc:  p.QuotaSvc,
+		ledgerSvc: p.LedgerSvc,
 	}
 }
 
@@ -66,6 +70,18 @@ func (s *Service) Create(ctx context.Context, req domain.CreateCustomerRequest)
 		return domain.Customer{}, domain.ErrInvalidEmail
 	}
 
+	country := strings.ToUpper(strings.TrimSpace(req.Country))
+	if country != "" && !isCountryCode(country) {
+		return domain.Customer{}, domain.ErrInvalidCountry
+	}
+	state := strings.ToUpper(strings.TrimSpace(req.State))
+	if state != "" && country == "" {
+		return domain.Customer{}, domain.ErrInvalidCountry
+	}
+	if req.PaymentTermsDays != nil && !validPaymentTermsDays(*req.PaymentTermsDays) {
+		return domain.Customer{}, domain.ErrInvalidPaymentTerms
+	}
+
 	idempotencyKey := strings.TrimSpace(req.IdempotencyKey)
 	if idempotencyKey != "" {
 		existing, err := s.repo.FindByIdempotencyKey(ctx, s.db, orgID, idempotencyKey)
@@ -83,10 +99,17 @@ func (s *Service) Create(ctx context.Context, req domain.CreateCustomerRequest)
 		OrgID:     orgID,
 		Name:      name,
 		Email:     email,
+		Country:   country,
+		State:     state,
+		TaxID:     normalizeTaxID(req.TaxID),
 		Metadata:  datatypes.JSONMap{},
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	if req.PaymentTermsDays != nil {
+		days := *req.PaymentTermsDays
+		customer.PaymentTermsDays = &days
+	}
 	if idempotencyKey != "" {
 		customer.IdempotencyKey = &idempotencyKey
 	}
@@ -107,6 +130,31 @@ func (s *Service) Create(ctx context.Context, req domain.CreateCustomerRequest)
 	return customer, nil
 }
 
+// isCountryCode reports whether value looks like an ISO 3166-1 alpha-2 code.
+func isCountryCode(value string) bool {
+	if len(value) != 2 {
+		return false
+	}
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTaxID uppercases the ID and drops the spaces, dots and dashes
+// customers commonly type into VAT numbers.
+func normalizeTaxID(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '.', '-':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(value)))
+}
+
 func (s *Service) List(ctx context.Context, req domain.ListCustomerRequest) (domain.ListCustomerResponse, error) {
 	orgID, ok := orgcontext.OrgIDFromContext(ctx)
 	if !ok || orgID == 0 {
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/customer/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+)
+
+const defaultStatementWindow = 30 * 24 * time.Hour
+
+type statementInvoice struct {
+	SourceID      snowflake.ID `gorm:"column:source_id"`
+	InvoiceID     snowflake.ID `gorm:"column:invoice_id"`
+	InvoiceNumber string       `gorm:"column:invoice_number"`
+}
+
+// GetStatement returns the customer's account activity in the requested
+// window. Opening and closing balances come from the ledger's accounts
+// receivable, so they match what payments are settled against; invoices and
+// credit notes only label the lines.
+func (s *Service) GetStatement(ctx context.Context, req domain.StatementRequest) (domain.Statement, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return domain.Statement{}, domain.ErrInvalidOrganization
+	}
+
+	id, err := s.parseID(req.CustomerID)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+
+	to := time.Now().UTC()
+	if req.To != nil {
+		to = req.To.UTC()
+	}
+	from := to.Add(-defaultStatementWindow)
+	if req.From != nil {
+		from = req.From.UTC()
+	}
+	if !from.Before(to) {
+		return domain.Statement{}, domain.ErrInvalidPeriod
+	}
+
+	customer, err := s.repo.FindByID(ctx, s.db, orgID, id)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+	if customer == nil {
+		return domain.Statement{}, domain.ErrNotFound
+	}
+
+	currency, err := s.statementCurrency(ctx, orgID, customer)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+
+	opening, err := s.ledgerSvc.CustomerBalance(ctx, orgID, customer.ID, currency, from)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+	entries, err := s.ledgerSvc.ListCustomerEntries(ctx, orgID, customer.ID, currency, from, to)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+	invoices, err := s.loadStatementInvoices(ctx, orgID, entries)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+
+	statement := domain.Statement{
+		CustomerID:     customer.ID.String(),
+		Currency:       currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		Lines:          make([]domain.StatementLine, 0, len(entries)),
+	}
+	balance := opening
+	for _, entry := range entries {
+		balance += entry.Debit - entry.Credit
+		statement.TotalDebits += entry.Debit
+		statement.TotalCredits += entry.Credit
+
+		line := domain.StatementLine{
+			OccurredAt: entry.OccurredAt.UTC(),
+			Type:       statementLineType(entry.SourceType),
+			SourceID:   entry.SourceID.String(),
+			Debit:      entry.Debit,
+			Credit:     entry.Credit,
+			Balance:    balance,
+		}
+		if invoice, ok := invoices[entry.SourceID]; ok {
+			invoiceID := invoice.InvoiceID.String()
+			invoiceNumber := invoice.InvoiceNumber
+			line.InvoiceID = &invoiceID
+			line.InvoiceNumber = &invoiceNumber
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+	statement.ClosingBalance = balance
+
+	return statement, nil
+}
+
+// statementCurrency is the customer's currency, falling back to the
+// organization's billing currency for customers created without one.
+func (s *Service) statementCurrency(ctx context.Context, orgID snowflake.ID, customer *domain.Customer) (string, error) {
+	if currency := strings.ToUpper(strings.TrimSpace(customer.Currency)); currency != "" {
+		return currency, nil
+	}
+
+	var currency string
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT currency FROM organization_billing_preferences WHERE org_id = ?`,
+		orgID,
+	).Scan(&currency).Error; err != nil {
+		return "", err
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return "", domain.ErrCurrencyNotSet
+	}
+	return currency, nil
+}
+
+// loadStatementInvoices maps billing cycle and credit note entries to the
+// invoice they belong to.
+func (s *Service) loadStatementInvoices(ctx context.Context, orgID snowflake.ID, entries []ledgerdomain.CustomerEntry) (map[snowflake.ID]statementInvoice, error) {
+	var cycleIDs, creditNoteIDs []snowflake.ID
+	for _, entry := range entries {
+		switch entry.SourceType {
+		case ledgerdomain.SourceTypeBillingCycle:
+			cycleIDs = append(cycleIDs, entry.SourceID)
+		case ledgerdomain.SourceTypeCreditNote:
+			creditNoteIDs = append(creditNoteIDs, entry.SourceID)
+		}
+	}
+
+	out := make(map[snowflake.ID]statementInvoice)
+	if len(cycleIDs) > 0 {
+		var rows []statementInvoice
+		// Finalization posts against the invoice itself, older entries
+		// against its billing cycle.
+		if err := s.db.WithContext(ctx).Raw(
+			`SELECT billing_cycle_id AS source_id, id AS invoice_id, invoice_number
+			 FROM invoices
+			 WHERE org_id = ? AND billing_cycle_id IN ?
+			 UNION ALL
+			 SELECT id AS source_id, id AS invoice_id, invoice_number
+			 FROM invoices
+			 WHERE org_id = ? AND id IN ?`,
+			orgID,
+			cycleIDs,
+			orgID,
+			cycleIDs,
+		).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			out[row.SourceID] = row
+		}
+	}
+	if len(creditNoteIDs) > 0 {
+		var rows []statementInvoice
+		if err := s.db.WithContext(ctx).Raw(
+			`SELECT cn.id AS source_id, i.id AS invoice_id, i.invoice_number
+			 FROM credit_notes cn
+			 JOIN invoices i ON i.id = cn.invoice_id
+			 WHERE cn.org_id = ? AND cn.id IN ?`,
+			orgID,
+			creditNoteIDs,
+		).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			out[row.SourceID] = row
+		}
+	}
+	return out, nil
+}
+
+func statementLineType(sourceType ledgerdomain.LedgerSourceType) string {
+	switch sourceType {
+	case ledgerdomain.SourceTypeBillingCycle:
+		return "invoice"
+	case ledgerdomain.SourceTypeDisputeHold, ledgerdomain.SourceTypeDisputeWin, ledgerdomain.SourceTypeDisputeLoss:
+		return "dispute"
+	default:
+		return string(sourceType)
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/customer/domain"
+	"github.com/railzwaylabs/railzway/internal/customer/repository"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	ledgerservice "github.com/railzwaylabs/railzway/internal/ledger/service"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
+	disputedomain "github.com/railzwaylabs/railzway/internal/payment/dispute/domain"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestGetStatement_BalancesFromLedger(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Customer{},
+		&subscriptiondomain.Subscription{},
+		&billingcycledomain.BillingCycle{},
+		&invoicedomain.Invoice{},
+		&paymentdomain.EventRecord{},
+		&disputedomain.DisputeRecord{},
+		&creditnotedomain.CreditNote{},
+		&ledgerdomain.LedgerEntry{},
+		&ledgerdomain.LedgerEntryLine{},
+	))
+	require.NoError(t, db.Exec(`CREATE TABLE ledger_accounts (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		code TEXT NOT NULL,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX ux_ledger_entries_source
+		ON ledger_entries (org_id, source_type, source_id)`).Error)
+
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	orgID := node.Generate()
+	now := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	accounts := map[ledgerdomain.LedgerAccountCode]snowflake.ID{}
+	for _, code := range []ledgerdomain.LedgerAccountCode{
+		ledgerdomain.AccountCodeAccountsReceivable,
+		ledgerdomain.AccountCodeCash,
+		ledgerdomain.AccountCodeRevenueFlat,
+	} {
+		accounts[code] = node.Generate()
+		require.NoError(t, db.Exec(
+			`INSERT INTO ledger_accounts (id, org_id, code, name, created_at) VALUES (?, ?, ?, ?, ?)`,
+			accounts[code], orgID, string(code), string(code), now,
+		).Error)
+	}
+
+	customer := domain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Acme", Email: "billing@acme.test", Currency: "EUR", Metadata: datatypes.JSONMap{}}
+	other := domain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Other", Email: "billing@other.test", Currency: "EUR", Metadata: datatypes.JSONMap{}}
+	require.NoError(t, db.Create(&customer).Error)
+	require.NoError(t, db.Create(&other).Error)
+
+	sub := subscriptiondomain.Subscription{
+		ID:               node.Generate(),
+		OrgID:            orgID,
+		CustomerID:       customer.ID,
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		CollectionMode:   subscriptiondomain.SubscriptionCollectionModeSendInvoice,
+		StartAt:          now.AddDate(0, -3, 0),
+		BillingCycleType: "MONTHLY",
+	}
+	require.NoError(t, db.Create(&sub).Error)
+
+	ledger := ledgerservice.NewService(ledgerservice.Params{DB: db, Log: zap.NewNop(), GenID: node})
+	ctx := context.Background()
+	post := func(sourceType ledgerdomain.LedgerSourceType, sourceID snowflake.ID, occurredAt time.Time, debit, credit ledgerdomain.LedgerAccountCode, amount int64) {
+		require.NoError(t, ledger.CreateEntry(ctx, orgID, string(sourceType), sourceID, "EUR", occurredAt, []ledgerdomain.LedgerEntryLine{
+			{AccountID: accounts[debit], Direction: ledgerdomain.LedgerEntryDirectionDebit, Currency: "EUR", Amount: amount},
+			{AccountID: accounts[credit], Direction: ledgerdomain.LedgerEntryDirectionCredit, Currency: "EUR", Amount: amount},
+		}))
+	}
+	bill := func(occurredAt time.Time, invoiceNumber string, amount int64) snowflake.ID {
+		cycle := billingcycledomain.BillingCycle{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: sub.ID,
+			PeriodStart:    occurredAt.AddDate(0, -1, 0),
+			PeriodEnd:      occurredAt,
+			Status:         billingcycledomain.BillingCycleStatusClosed,
+			Metadata:       datatypes.JSONMap{},
+		}
+		require.NoError(t, db.Create(&cycle).Error)
+		invoice := invoicedomain.Invoice{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			InvoiceNumber:  invoiceNumber,
+			BillingCycleID: cycle.ID,
+			SubscriptionID: sub.ID,
+			CustomerID:     customer.ID,
+			Status:         invoicedomain.InvoiceStatusFinalized,
+			TotalAmount:    amount,
+			Currency:       "EUR",
+			Metadata:       datatypes.JSONMap{},
+		}
+		require.NoError(t, db.Create(&invoice).Error)
+		post(ledgerdomain.SourceTypeBillingCycle, cycle.ID, occurredAt, ledgerdomain.AccountCodeAccountsReceivable, ledgerdomain.AccountCodeRevenueFlat, amount)
+		return invoice.ID
+	}
+	pay := func(customerID snowflake.ID, occurredAt time.Time, amount int64) {
+		event := paymentdomain.EventRecord{
+			ID:              node.Generate(),
+			OrgID:           orgID,
+			Provider:        "stripe",
+			ProviderEventID: node.Generate().String(),
+			EventType:       "payment_succeeded",
+			CustomerID:      customerID,
+			Payload:         datatypes.JSON(`{}`),
+			ReceivedAt:      occurredAt,
+		}
+		require.NoError(t, db.Create(&event).Error)
+		post(ledgerdomain.SourceTypePayment, event.ID, occurredAt, ledgerdomain.AccountCodeCash, ledgerdomain.AccountCodeAccountsReceivable, amount)
+	}
+
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	bill(time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), "INV-1", 5000)
+	pay(customer.ID, time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC), 3000)
+	secondInvoice := bill(time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), "INV-2", 2000)
+	note := creditnotedomain.CreditNote{
+		ID:         node.Generate(),
+		OrgID:      orgID,
+		InvoiceID:  secondInvoice,
+		CustomerID: customer.ID,
+		Amount:     500,
+		Currency:   "EUR",
+		Reason:     creditnotedomain.ReasonRefund,
+		IssuedAt:   time.Date(2026, 2, 12, 0, 0, 0, 0, time.UTC),
+		CreatedAt:  now,
+	}
+	require.NoError(t, db.Create(&note).Error)
+	post(ledgerdomain.SourceTypeCreditNote, note.ID, note.IssuedAt, ledgerdomain.AccountCodeRevenueFlat, ledgerdomain.AccountCodeAccountsReceivable, 500)
+	// Another customer's payment and activity after the window are left out.
+	pay(other.ID, time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC), 700)
+	pay(customer.ID, time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), 1000)
+
+	svc := New(Params{DB: db, Log: zap.NewNop(), GenID: node, Repo: repository.Provide(), LedgerSvc: ledger})
+	orgCtx := orgcontext.WithOrgID(ctx, int64(orgID))
+
+	statement, err := svc.GetStatement(orgCtx, domain.StatementRequest{CustomerID: customer.ID.String(), From: &from, To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", statement.Currency)
+	assert.Equal(t, int64(5000), statement.OpeningBalance)
+	assert.Equal(t, int64(2000), statement.TotalDebits)
+	assert.Equal(t, int64(3500), statement.TotalCredits)
+	assert.Equal(t, int64(3500), statement.ClosingBalance)
+
+	require.Len(t, statement.Lines, 3)
+	assert.Equal(t, "payment", statement.Lines[0].Type)
+	assert.Equal(t, int64(3000), statement.Lines[0].Credit)
+	assert.Equal(t, int64(2000), statement.Lines[0].Balance)
+	assert.Equal(t, "invoice", statement.Lines[1].Type)
+	require.NotNil(t, statement.Lines[1].InvoiceNumber)
+	assert.Equal(t, "INV-2", *statement.Lines[1].InvoiceNumber)
+	assert.Equal(t, int64(4000), statement.Lines[1].Balance)
+	assert.Equal(t, "credit_note", statement.Lines[2].Type)
+	require.NotNil(t, statement.Lines[2].InvoiceID)
+	assert.Equal(t, secondInvoice.String(), *statement.Lines[2].InvoiceID)
+	assert.Equal(t, int64(3500), statement.Lines[2].Balance)
+
+	balance, err := ledger.CustomerBalance(ctx, orgID, customer.ID, "eur", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2500), balance)
+
+	_, err = svc.GetStatement(orgCtx, domain.StatementRequest{CustomerID: customer.ID.String(), From: &to, To: &from})
+	assert.ErrorIs(t, err, domain.ErrInvalidPeriod)
+
+	_, err = svc.GetStatement(orgCtx, domain.StatementRequest{CustomerID: node.Generate().String()})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
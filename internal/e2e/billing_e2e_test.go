@@ -204,7 +204,7 @@ func TestE2E_RatingTieredVolume(t *testing.T) {
 	createAdminPriceTier(t, client, orgID, map[string]any{
 		"price_id":          priceID,
 		"tier_mode":         0,
-		"start_quantity":    1.0,
+		"start_quantity":    0.0,
 		"end_quantity":      &tierOneEnd,
 		"unit_amount_cents": &unitTierOne,
 		"unit":              "API_CALL",
@@ -212,7 +212,7 @@ func TestE2E_RatingTieredVolume(t *testing.T) {
 	createAdminPriceTier(t, client, orgID, map[string]any{
 		"price_id":          priceID,
 		"tier_mode":         0,
-		"start_quantity":    101.0,
+		"start_quantity":    100.0,
 		"end_quantity":      nil,
 		"unit_amount_cents": &unitTierTwo,
 		"flat_amount_cents": &flatTierTwo,
@@ -305,7 +305,7 @@ func TestE2E_RatingTieredGraduated(t *testing.T) {
 	createAdminPriceTier(t, client, orgID, map[string]any{
 		"price_id":          priceID,
 		"tier_mode":         0,
-		"start_quantity":    1.0,
+		"start_quantity":    0.0,
 		"end_quantity":      &tierOneEnd,
 		"unit_amount_cents": &unitTierOne,
 		"unit":              "API_CALL",
@@ -313,7 +313,7 @@ func TestE2E_RatingTieredGraduated(t *testing.T) {
 	createAdminPriceTier(t, client, orgID, map[string]any{
 		"price_id":          priceID,
 		"tier_mode":         0,
-		"start_quantity":    101.0,
+		"start_quantity":    100.0,
 		"end_quantity":      &tierTwoEnd,
 		"unit_amount_cents": &unitTierTwo,
 		"unit":              "API_CALL",
@@ -321,7 +321,7 @@ func TestE2E_RatingTieredGraduated(t *testing.T) {
 	createAdminPriceTier(t, client, orgID, map[string]any{
 		"price_id":          priceID,
 		"tier_mode":         0,
-		"start_quantity":    201.0,
+		"start_quantity":    200.0,
 		"end_quantity":      nil,
 		"unit_amount_cents": &unitTierThree,
 		"unit":              "API_CALL",
@@ -834,6 +834,8 @@ func TestE2E_HybridPricing_FlatAndUsage(t *testing.T) {
 
 func createAdminMeter(t *testing.T, client *http.Client, orgID, code string) (string, string) {
 	t.Helper()
+	// Meter codes are limited to lowercase letters, digits and underscores.
+	code = strings.ReplaceAll(code, "-", "_")
 	headers := map[string]string{server.HeaderOrg: orgID}
 	req := map[string]any{
 		"code":             code,
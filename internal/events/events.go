@@ -1,5 +1,7 @@
 package events
 
+import "time"
+
 // Billing event types for snapshot rollups.
 const (
 	EventLedgerEntryCreated = "ledger_entry_created"
@@ -12,6 +14,17 @@ const (
 	EventUsageIngested      = "usage.ingested"
 )
 
+// Subscription renewal event types. A cycle is reported closed once its
+// closing work is done and its invoice, if any, was generated.
+const (
+	EventSubscriptionCycleClosed = "subscription.cycle.closed"
+	EventSubscriptionCycleOpened = "subscription.cycle.opened"
+)
+
+// EventSubscriptionSpendAlert reports that a subscription's cycle-to-date
+// rated amount reached its spend alert threshold.
+const EventSubscriptionSpendAlert = "subscription.spend_alert.triggered"
+
 // LedgerEntryPayload captures the minimal data needed to roll up a ledger entry.
 type LedgerEntryPayload struct {
 	LedgerEntryID string `json:"ledger_entry_id"`
@@ -37,6 +50,60 @@ type UsageIngestedPayload struct {
 	IdempotencyKey     string `json:"idempotency_key,omitempty"`
 }
 
+// SubscriptionCyclePayload describes a billing cycle that opened or closed.
+// RatedTotal, Currency and InvoiceID are only set for closed cycles.
+type SubscriptionCyclePayload struct {
+	SubscriptionID string
+	BillingCycleID string
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	RatedTotal     *int64
+	Currency       string
+	InvoiceID      string
+}
+
+// ToMap converts a payload into an outbox-friendly map.
+func (p SubscriptionCyclePayload) ToMap() map[string]any {
+	payload := map[string]any{
+		"subscription_id":  p.SubscriptionID,
+		"billing_cycle_id": p.BillingCycleID,
+		"period_start":     p.PeriodStart.UTC().Format(time.RFC3339),
+		"period_end":       p.PeriodEnd.UTC().Format(time.RFC3339),
+	}
+	if p.RatedTotal != nil {
+		payload["rated_total"] = *p.RatedTotal
+	}
+	if p.Currency != "" {
+		payload["currency"] = p.Currency
+	}
+	if p.InvoiceID != "" {
+		payload["invoice_id"] = p.InvoiceID
+	}
+	return payload
+}
+
+// SpendAlertPayload describes a spend alert threshold crossed in a cycle.
+type SpendAlertPayload struct {
+	SubscriptionID string
+	CustomerID     string
+	BillingCycleID string
+	ThresholdCents int64
+	AmountCents    int64
+	Currency       string
+}
+
+// ToMap converts a payload into an outbox-friendly map.
+func (p SpendAlertPayload) ToMap() map[string]any {
+	return map[string]any{
+		"subscription_id":  p.SubscriptionID,
+		"customer_id":      p.CustomerID,
+		"billing_cycle_id": p.BillingCycleID,
+		"threshold_cents":  p.ThresholdCents,
+		"amount_cents":     p.AmountCents,
+		"currency":         p.Currency,
+	}
+}
+
 // ToMap converts a payload into an outbox-friendly map.
 func (p UsageIngestedPayload) ToMap() map[string]any {
 	payload := map[string]any{
@@ -4,4 +4,5 @@ import "go.uber.org/fx"
 
 var Module = fx.Module("events.outbox",
 	fx.Provide(NewOutbox),
+	fx.Provide(NewWebhookDispatcher),
 )
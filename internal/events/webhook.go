@@ -0,0 +1,91 @@
+package events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"gorm.io/datatypes"
+)
+
+// Outbound webhook delivery states.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookEventTypeAll subscribes an endpoint to every billing event.
+const WebhookEventTypeAll = "*"
+
+// WebhookSignatureHeader carries the timestamped HMAC-SHA256 signature of an
+// outbound payload, formatted like Stripe-Signature: "t=<unix>,v1=<hex>".
+const WebhookSignatureHeader = "Railzway-Signature"
+
+var (
+	ErrInvalidWebhookURL        = errors.New("invalid_webhook_url")
+	ErrInvalidWebhookEventTypes = errors.New("invalid_webhook_event_types")
+	ErrWebhookEndpointNotFound  = errors.New("webhook_endpoint_not_found")
+)
+
+// WebhookEndpoint is a customer URL subscribed to billing events.
+type WebhookEndpoint struct {
+	ID         snowflake.ID   `gorm:"primaryKey" json:"id"`
+	OrgID      snowflake.ID   `gorm:"not null;index" json:"org_id"`
+	URL        string         `gorm:"type:text;not null" json:"url"`
+	Secret     string         `gorm:"type:text;not null" json:"-"`
+	EventTypes datatypes.JSON `gorm:"type:jsonb;not null;default:'[]'" json:"event_types"`
+	Active     bool           `gorm:"not null;default:true" json:"active"`
+	CreatedAt  time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (WebhookEndpoint) TableName() string { return "webhook_endpoints" }
+
+// WebhookDelivery tracks one billing event queued for one endpoint.
+type WebhookDelivery struct {
+	ID             snowflake.ID   `gorm:"primaryKey" json:"id"`
+	OrgID          snowflake.ID   `gorm:"not null;index" json:"org_id"`
+	EndpointID     snowflake.ID   `gorm:"not null;uniqueIndex:ux_webhook_deliveries_endpoint_event,priority:1" json:"endpoint_id"`
+	EventID        snowflake.ID   `gorm:"not null;uniqueIndex:ux_webhook_deliveries_endpoint_event,priority:2" json:"event_id"`
+	EventType      string         `gorm:"type:text;not null" json:"event_type"`
+	Payload        datatypes.JSON `gorm:"type:jsonb;not null;default:'{}'" json:"payload"`
+	Status         string         `gorm:"type:text;not null;default:'pending'" json:"status"`
+	Attempts       int            `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int           `json:"last_status_code,omitempty"`
+	LastError      *string        `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (WebhookDelivery) TableName() string { return "webhook_deliveries" }
+
+// WebhookDeliveryAttempt is the audit record of a single HTTP request made
+// for a delivery.
+type WebhookDeliveryAttempt struct {
+	ID          snowflake.ID `gorm:"primaryKey" json:"id"`
+	OrgID       snowflake.ID `gorm:"not null" json:"org_id"`
+	DeliveryID  snowflake.ID `gorm:"not null;index" json:"delivery_id"`
+	Attempt     int          `gorm:"not null" json:"attempt"`
+	StatusCode  *int         `json:"status_code,omitempty"`
+	Error       *string      `gorm:"type:text" json:"error,omitempty"`
+	DurationMS  int64        `gorm:"column:duration_ms;not null;default:0" json:"duration_ms"`
+	AttemptedAt time.Time    `gorm:"not null" json:"attempted_at"`
+}
+
+func (WebhookDeliveryAttempt) TableName() string { return "webhook_delivery_attempts" }
+
+// SignWebhookPayload returns the signature header value for payload sent at
+// timestamp. Receivers recompute HMAC-SHA256 over "<timestamp>.<body>" with
+// the endpoint secret and compare it to v1.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(fmt.Sprintf("%d.%s", timestamp, string(payload))))
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
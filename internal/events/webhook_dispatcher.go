@@ -0,0 +1,448 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	WebhookDispatcherConsumerID = "webhook_dispatcher"
+
+	webhookBatchSize      = 50
+	webhookMaxAttempts    = 8
+	webhookBaseBackoff    = 30 * time.Second
+	webhookMaxBackoff     = 6 * time.Hour
+	webhookRequestTimeout = 10 * time.Second
+	webhookMaxErrorLength = 512
+)
+
+// WebhookDispatcher fans billing events out to subscribed endpoints and
+// delivers them with retries.
+type WebhookDispatcher struct {
+	db     *gorm.DB
+	log    *zap.Logger
+	genID  *snowflake.Node
+	clock  clock.Clock
+	client *http.Client
+}
+
+func NewWebhookDispatcher(db *gorm.DB, log *zap.Logger, genID *snowflake.Node, clk clock.Clock) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		db:     db,
+		log:    log.Named("events.webhook"),
+		genID:  genID,
+		clock:  clk,
+		client: &http.Client{Timeout: webhookRequestTimeout},
+	}
+}
+
+// CreateEndpoint subscribes url to eventTypes and generates its signing
+// secret. The secret is only readable on the returned value.
+func (d *WebhookDispatcher) CreateEndpoint(ctx context.Context, orgID snowflake.ID, rawURL string, eventTypes []string) (*WebhookEndpoint, string, error) {
+	if orgID == 0 {
+		return nil, "", errors.New("invalid_org_id")
+	}
+	endpointURL, err := normalizeWebhookURL(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+	types, err := normalizeWebhookEventTypes(eventTypes)
+	if err != nil {
+		return nil, "", err
+	}
+	encodedTypes, err := json.Marshal(types)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := d.clock.Now(ctx).UTC()
+	endpoint := WebhookEndpoint{
+		ID:         d.genID.Generate(),
+		OrgID:      orgID,
+		URL:        endpointURL,
+		Secret:     secret,
+		EventTypes: datatypes.JSON(encodedTypes),
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := d.db.WithContext(ctx).Create(&endpoint).Error; err != nil {
+		return nil, "", err
+	}
+	return &endpoint, secret, nil
+}
+
+// ListEndpoints returns the organization's endpoints, newest first.
+func (d *WebhookDispatcher) ListEndpoints(ctx context.Context, orgID snowflake.ID) ([]WebhookEndpoint, error) {
+	var endpoints []WebhookEndpoint
+	if err := d.db.WithContext(ctx).
+		Where("org_id = ?", orgID).
+		Order("created_at DESC, id DESC").
+		Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// DisableEndpoint stops new deliveries to an endpoint. Pending deliveries are
+// failed on their next attempt.
+func (d *WebhookDispatcher) DisableEndpoint(ctx context.Context, orgID, endpointID snowflake.ID) error {
+	result := d.db.WithContext(ctx).Exec(
+		`UPDATE webhook_endpoints SET active = false, updated_at = ?
+		 WHERE id = ? AND org_id = ?`,
+		d.clock.Now(ctx).UTC(),
+		endpointID,
+		orgID,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookEndpointNotFound
+	}
+	return nil
+}
+
+// ListDeliveryAttempts returns the audit trail of requests made to an
+// endpoint, most recent first.
+func (d *WebhookDispatcher) ListDeliveryAttempts(ctx context.Context, orgID, endpointID snowflake.ID, limit int) ([]WebhookDeliveryAttempt, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	var exists int64
+	if err := d.db.WithContext(ctx).Model(&WebhookEndpoint{}).
+		Where("id = ? AND org_id = ?", endpointID, orgID).
+		Count(&exists).Error; err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, ErrWebhookEndpointNotFound
+	}
+
+	var attempts []WebhookDeliveryAttempt
+	if err := d.db.WithContext(ctx).Raw(
+		`SELECT a.*
+		 FROM webhook_delivery_attempts a
+		 JOIN webhook_deliveries wd ON wd.id = a.delivery_id
+		 WHERE wd.endpoint_id = ? AND a.org_id = ?
+		 ORDER BY a.attempted_at DESC, a.id DESC
+		 LIMIT ?`,
+		endpointID,
+		orgID,
+		limit,
+	).Scan(&attempts).Error; err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// ProcessEvents queues deliveries for billing events published since the last
+// run, then sends every delivery that is due.
+func (d *WebhookDispatcher) ProcessEvents(ctx context.Context) error {
+	if err := d.enqueueEvents(ctx); err != nil {
+		return err
+	}
+	return d.deliverDue(ctx)
+}
+
+func (d *WebhookDispatcher) enqueueEvents(ctx context.Context) error {
+	lastID, err := d.getLastEventID(ctx)
+	if err != nil {
+		return err
+	}
+
+	var rows []struct {
+		ID        snowflake.ID
+		OrgID     snowflake.ID
+		EventType string
+		Payload   datatypes.JSON
+		CreatedAt time.Time
+	}
+	if err := d.db.WithContext(ctx).Raw(`
+		SELECT id, org_id, event_type, payload, created_at
+		FROM billing_events
+		WHERE id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`, lastID, webhookBatchSize).Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	endpointsByOrg := map[snowflake.ID][]WebhookEndpoint{}
+	for _, row := range rows {
+		endpoints, ok := endpointsByOrg[row.OrgID]
+		if !ok {
+			if err := d.db.WithContext(ctx).
+				Where("org_id = ? AND active = ?", row.OrgID, true).
+				Find(&endpoints).Error; err != nil {
+				return err
+			}
+			endpointsByOrg[row.OrgID] = endpoints
+		}
+
+		body, err := json.Marshal(map[string]any{
+			"id":         row.ID.String(),
+			"type":       row.EventType,
+			"org_id":     row.OrgID.String(),
+			"created_at": row.CreatedAt.UTC().Format(time.RFC3339),
+			"data":       row.Payload,
+		})
+		if err != nil {
+			return err
+		}
+
+		now := d.clock.Now(ctx).UTC()
+		for _, endpoint := range endpoints {
+			if !endpointSubscribed(endpoint, row.EventType) {
+				continue
+			}
+			if err := d.db.WithContext(ctx).Exec(
+				`INSERT INTO webhook_deliveries (id, org_id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at)
+				 VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?)
+				 ON CONFLICT (endpoint_id, event_id) DO NOTHING`,
+				d.genID.Generate(),
+				row.OrgID,
+				endpoint.ID,
+				row.ID,
+				row.EventType,
+				datatypes.JSON(body),
+				WebhookDeliveryPending,
+				now,
+				now,
+				now,
+			).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := d.updateLastEventID(ctx, row.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) deliverDue(ctx context.Context) error {
+	var deliveries []WebhookDelivery
+	if err := d.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", WebhookDeliveryPending, d.clock.Now(ctx).UTC()).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(webhookBatchSize).
+		Find(&deliveries).Error; err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		if err := d.deliver(ctx, &deliveries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *WebhookDelivery) error {
+	var endpoint WebhookEndpoint
+	err := d.db.WithContext(ctx).
+		Where("id = ? AND org_id = ?", delivery.EndpointID, delivery.OrgID).
+		First(&endpoint).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	attempt := delivery.Attempts + 1
+	startedAt := d.clock.Now(ctx).UTC()
+	var statusCode *int
+	var deliveryErr error
+	if err != nil || !endpoint.Active {
+		deliveryErr = errors.New("endpoint_disabled")
+	} else {
+		code, sendErr := d.send(ctx, endpoint, delivery, startedAt)
+		if code != 0 {
+			statusCode = &code
+		}
+		deliveryErr = sendErr
+	}
+	finishedAt := d.clock.Now(ctx).UTC()
+
+	record := WebhookDeliveryAttempt{
+		ID:          d.genID.Generate(),
+		OrgID:       delivery.OrgID,
+		DeliveryID:  delivery.ID,
+		Attempt:     attempt,
+		StatusCode:  statusCode,
+		DurationMS:  finishedAt.Sub(startedAt).Milliseconds(),
+		AttemptedAt: startedAt,
+	}
+
+	updates := map[string]any{
+		"attempts":         attempt,
+		"last_status_code": statusCode,
+		"updated_at":       finishedAt,
+	}
+	switch {
+	case deliveryErr == nil:
+		updates["status"] = WebhookDeliverySucceeded
+		updates["delivered_at"] = finishedAt
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = nil
+	case endpoint.ID == 0 || !endpoint.Active || attempt >= webhookMaxAttempts:
+		message := truncateWebhookError(deliveryErr.Error())
+		record.Error = &message
+		updates["status"] = WebhookDeliveryFailed
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = message
+	default:
+		message := truncateWebhookError(deliveryErr.Error())
+		record.Error = &message
+		updates["next_attempt_at"] = finishedAt.Add(webhookBackoff(attempt))
+		updates["last_error"] = message
+	}
+
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+		return tx.Model(&WebhookDelivery{}).
+			Where("id = ?", delivery.ID).
+			Updates(updates).Error
+	})
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, endpoint WebhookEndpoint, delivery *WebhookDelivery, sentAt time.Time) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Railzway-Webhooks/1.0")
+	req.Header.Set("Railzway-Event-Type", delivery.EventType)
+	req.Header.Set("Railzway-Delivery-ID", delivery.ID.String())
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(endpoint.Secret, sentAt.Unix(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected_status_%d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *WebhookDispatcher) getLastEventID(ctx context.Context) (snowflake.ID, error) {
+	var offset struct {
+		LastEventID snowflake.ID
+	}
+	if err := d.db.WithContext(ctx).Raw(
+		"SELECT last_event_id FROM event_consumer_offsets WHERE consumer_id = ?",
+		WebhookDispatcherConsumerID,
+	).Scan(&offset).Error; err != nil {
+		return 0, err
+	}
+	return offset.LastEventID, nil
+}
+
+func (d *WebhookDispatcher) updateLastEventID(ctx context.Context, id snowflake.ID) error {
+	return d.db.WithContext(ctx).Exec(`
+		INSERT INTO event_consumer_offsets (consumer_id, last_event_id, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (consumer_id) DO UPDATE SET last_event_id = EXCLUDED.last_event_id, updated_at = EXCLUDED.updated_at
+	`, WebhookDispatcherConsumerID, id, d.clock.Now(ctx).UTC()).Error
+}
+
+// webhookBackoff doubles the wait after each failed attempt, starting at 30s
+// and capped at six hours.
+func webhookBackoff(attempt int) time.Duration {
+	wait := webhookBaseBackoff
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if wait >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return wait
+}
+
+func endpointSubscribed(endpoint WebhookEndpoint, eventType string) bool {
+	var types []string
+	if err := json.Unmarshal(endpoint.EventTypes, &types); err != nil {
+		return false
+	}
+	for _, value := range types {
+		if value == WebhookEventTypeAll || value == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return "", ErrInvalidWebhookURL
+	}
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return "", ErrInvalidWebhookURL
+	}
+	return raw, nil
+}
+
+func normalizeWebhookEventTypes(values []string) ([]string, error) {
+	seen := map[string]struct{}{}
+	types := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, ErrInvalidWebhookEventTypes
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		types = append(types, value)
+	}
+	if len(types) == 0 {
+		return nil, ErrInvalidWebhookEventTypes
+	}
+	return types, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+func truncateWebhookError(message string) string {
+	if len(message) > webhookMaxErrorLength {
+		return message[:webhookMaxErrorLength]
+	}
+	return message
+}
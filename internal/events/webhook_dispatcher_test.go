@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestWebhookDispatcher_SignsAndRetriesDeliveries(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&WebhookEndpoint{}, &WebhookDelivery{}, &WebhookDeliveryAttempt{}))
+	require.NoError(t, db.Exec(`CREATE TABLE billing_events (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		event_type TEXT NOT NULL,
+		payload TEXT NOT NULL DEFAULT '{}',
+		created_at TIMESTAMP NOT NULL
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE event_consumer_offsets (
+		consumer_id TEXT PRIMARY KEY,
+		last_event_id BIGINT NOT NULL,
+		updated_at TIMESTAMP
+	)`).Error)
+
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFakeClock(now)
+	dispatcher := NewWebhookDispatcher(db, zap.NewNop(), node, clk)
+	ctx := context.Background()
+	orgID := node.Generate()
+
+	var secret string
+	var requests int
+	var signatureValid bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		header := r.Header.Get(WebhookSignatureHeader)
+		parts := strings.SplitN(strings.TrimPrefix(header, "t="), ",", 2)
+		timestamp, _ := strconv.ParseInt(parts[0], 10, 64)
+		signatureValid = header == SignWebhookPayload(secret, timestamp, body)
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	endpoint, secret, err := dispatcher.CreateEndpoint(ctx, orgID, receiver.URL, []string{EventInvoiceFinalized})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "whsec_"))
+
+	_, _, err = dispatcher.CreateEndpoint(ctx, orgID, "ftp://example.com", []string{EventInvoiceFinalized})
+	assert.ErrorIs(t, err, ErrInvalidWebhookURL)
+	_, _, err = dispatcher.CreateEndpoint(ctx, orgID, receiver.URL, nil)
+	assert.ErrorIs(t, err, ErrInvalidWebhookEventTypes)
+
+	for _, eventType := range []string{EventLedgerEntryCreated, EventInvoiceFinalized} {
+		require.NoError(t, db.Exec(
+			`INSERT INTO billing_events (id, org_id, event_type, payload, created_at) VALUES (?, ?, ?, ?, ?)`,
+			node.Generate(), orgID, eventType, `{"invoice_id":"1"}`, now,
+		).Error)
+	}
+
+	// The first attempt fails and is rescheduled after the base backoff.
+	require.NoError(t, dispatcher.ProcessEvents(ctx))
+	var deliveries []WebhookDelivery
+	require.NoError(t, db.Find(&deliveries).Error)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, EventInvoiceFinalized, deliveries[0].EventType)
+	assert.Equal(t, WebhookDeliveryPending, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	require.NotNil(t, deliveries[0].NextAttemptAt)
+	assert.True(t, deliveries[0].NextAttemptAt.Equal(now.Add(webhookBaseBackoff)))
+	assert.True(t, signatureValid)
+
+	// Nothing is retried before the backoff elapses.
+	require.NoError(t, dispatcher.ProcessEvents(ctx))
+	assert.Equal(t, 1, requests)
+
+	clk.Advance(webhookBaseBackoff)
+	require.NoError(t, dispatcher.ProcessEvents(ctx))
+	assert.Equal(t, 2, requests)
+
+	var delivered WebhookDelivery
+	require.NoError(t, db.First(&delivered, "id = ?", deliveries[0].ID).Error)
+	assert.Equal(t, WebhookDeliverySucceeded, delivered.Status)
+	assert.Equal(t, 2, delivered.Attempts)
+	assert.NotNil(t, delivered.DeliveredAt)
+
+	attempts, err := dispatcher.ListDeliveryAttempts(ctx, orgID, endpoint.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	assert.Equal(t, 2, attempts[0].Attempt)
+	require.NotNil(t, attempts[0].StatusCode)
+	assert.Equal(t, http.StatusOK, *attempts[0].StatusCode)
+	require.NotNil(t, attempts[1].Error)
+	assert.Equal(t, "unexpected_status_500", *attempts[1].Error)
+
+	_, err = dispatcher.ListDeliveryAttempts(ctx, node.Generate(), endpoint.ID, 0)
+	assert.ErrorIs(t, err, ErrWebhookEndpointNotFound)
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookBackoff(1))
+	assert.Equal(t, 2*time.Minute, webhookBackoff(3))
+	assert.Equal(t, webhookMaxBackoff, webhookBackoff(20))
+}
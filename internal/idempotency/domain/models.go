@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+)
+
+type Status string
+
+const (
+	// StatusProcessing locks a key while its first request is in flight.
+	StatusProcessing Status = "processing"
+	// StatusCompleted keys carry the response to replay.
+	StatusCompleted Status = "completed"
+)
+
+// Key is the stored outcome of a request sent with an Idempotency-Key.
+type Key struct {
+	ID                  snowflake.ID `gorm:"primaryKey"`
+	OrgID               snowflake.ID `gorm:"not null;uniqueIndex:ux_idempotency_keys_org_endpoint_key,priority:1"`
+	Endpoint            string       `gorm:"type:text;not null;uniqueIndex:ux_idempotency_keys_org_endpoint_key,priority:2"`
+	IdempotencyKey      string       `gorm:"type:text;not null;uniqueIndex:ux_idempotency_keys_org_endpoint_key,priority:3"`
+	RequestHash         string       `gorm:"type:text;not null"`
+	Status              Status       `gorm:"type:text;not null"`
+	ResponseStatus      *int         `gorm:"column:response_status"`
+	ResponseContentType *string      `gorm:"column:response_content_type"`
+	ResponseBody        []byte       `gorm:"column:response_body"`
+	LockedAt            *time.Time   `gorm:"column:locked_at"`
+	ExpiresAt           time.Time    `gorm:"not null;index"`
+	CreatedAt           time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt           time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName sets the database table name.
+func (Key) TableName() string { return "idempotency_keys" }
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+)
+
+const (
+	// TTL is how long a completed response is replayed.
+	TTL = 24 * time.Hour
+	// LockTimeout is how long a request may hold its key before the key is
+	// considered abandoned, e.g. by a crashed server, and can be claimed again.
+	LockTimeout = 5 * time.Minute
+)
+
+type Service interface {
+	// Begin claims the key for a request. It returns the processing key when
+	// the caller should run the request, or the completed key to replay.
+	Begin(ctx context.Context, req BeginRequest) (*Key, error)
+	// Complete stores the response of a claimed key.
+	Complete(ctx context.Context, id snowflake.ID, resp Response) error
+	// Release drops a claimed key without a response so the request can be
+	// retried.
+	Release(ctx context.Context, id snowflake.ID) error
+}
+
+type BeginRequest struct {
+	OrgID       snowflake.ID
+	Endpoint    string
+	Key         string
+	RequestHash string
+}
+
+type Response struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+var (
+	ErrInvalidKey        = errors.New("invalid_idempotency_key")
+	ErrKeyReused         = errors.New("idempotency_key_reused")
+	ErrRequestInProgress = errors.New("idempotency_request_in_progress")
+)
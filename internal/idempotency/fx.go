@@ -0,0 +1,10 @@
+package idempotency
+
+import (
+	"github.com/railzwaylabs/railzway/internal/idempotency/service"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("idempotency.service",
+	fx.Provide(service.New),
+)
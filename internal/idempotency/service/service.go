@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	"github.com/railzwaylabs/railzway/internal/idempotency/domain"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxKeyLength bounds client supplied keys; UUIDs and similar fit easily.
+const maxKeyLength = 255
+
+type Params struct {
+	fx.In
+
+	DB    *gorm.DB
+	Clock clock.Clock
+	GenID *snowflake.Node
+}
+
+type Service struct {
+	db    *gorm.DB
+	clock clock.Clock
+	genID *snowflake.Node
+}
+
+func New(p Params) domain.Service {
+	return &Service{
+		db:    p.DB,
+		clock: p.Clock,
+		genID: p.GenID,
+	}
+}
+
+// Begin claims the key with a processing row. A key another request still
+// holds is in progress; an expired key, or one whose lock timed out, is
+// claimed again. Reusing a live key for a different request is rejected.
+func (s *Service) Begin(ctx context.Context, req domain.BeginRequest) (*domain.Key, error) {
+	key := strings.TrimSpace(req.Key)
+	if req.OrgID == 0 || key == "" || len(key) > maxKeyLength {
+		return nil, domain.ErrInvalidKey
+	}
+
+	now := s.clock.Now(ctx).UTC()
+	claimed := domain.Key{
+		ID:             s.genID.Generate(),
+		OrgID:          req.OrgID,
+		Endpoint:       req.Endpoint,
+		IdempotencyKey: key,
+		RequestHash:    req.RequestHash,
+		Status:         domain.StatusProcessing,
+		LockedAt:       &now,
+		ExpiresAt:      now.Add(domain.TTL),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&claimed)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return &claimed, nil
+	}
+
+	var existing domain.Key
+	err := s.db.WithContext(ctx).
+		Where("org_id = ? AND endpoint = ? AND idempotency_key = ?", req.OrgID, req.Endpoint, key).
+		First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Released between the insert and the read.
+		return nil, domain.ErrRequestInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.ExpiresAt.After(now) {
+		if existing.RequestHash != req.RequestHash {
+			return nil, domain.ErrKeyReused
+		}
+		if existing.Status == domain.StatusCompleted {
+			return &existing, nil
+		}
+		if existing.LockedAt != nil && existing.LockedAt.Add(domain.LockTimeout).After(now) {
+			return nil, domain.ErrRequestInProgress
+		}
+	}
+
+	// The same conditions guard the update so only one request takes the key
+	// over.
+	result = s.db.WithContext(ctx).Model(&domain.Key{}).
+		Where("id = ? AND (expires_at <= ? OR (status = ? AND (locked_at IS NULL OR locked_at <= ?)))",
+			existing.ID, now, domain.StatusProcessing, now.Add(-domain.LockTimeout)).
+		Updates(map[string]any{
+			"request_hash":          req.RequestHash,
+			"status":                domain.StatusProcessing,
+			"response_status":       nil,
+			"response_content_type": nil,
+			"response_body":         nil,
+			"locked_at":             now,
+			"expires_at":            now.Add(domain.TTL),
+			"updated_at":            now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrRequestInProgress
+	}
+
+	existing.RequestHash = req.RequestHash
+	existing.Status = domain.StatusProcessing
+	existing.ResponseStatus = nil
+	existing.ResponseContentType = nil
+	existing.ResponseBody = nil
+	existing.LockedAt = &now
+	existing.ExpiresAt = now.Add(domain.TTL)
+	existing.UpdatedAt = now
+	return &existing, nil
+}
+
+func (s *Service) Complete(ctx context.Context, id snowflake.ID, resp domain.Response) error {
+	now := s.clock.Now(ctx).UTC()
+	return s.db.WithContext(ctx).Model(&domain.Key{}).
+		Where("id = ? AND status = ?", id, domain.StatusProcessing).
+		Updates(map[string]any{
+			"status":                domain.StatusCompleted,
+			"response_status":       resp.Status,
+			"response_content_type": resp.ContentType,
+			"response_body":         resp.Body,
+			"locked_at":             nil,
+			"updated_at":            now,
+		}).Error
+}
+
+func (s *Service) Release(ctx context.Context, id snowflake.ID) error {
+	return s.db.WithContext(ctx).
+		Where("id = ? AND status = ?", id, domain.StatusProcessing).
+		Delete(&domain.Key{}).Error
+}
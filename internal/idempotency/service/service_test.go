@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	"github.com/railzwaylabs/railzway/internal/idempotency/domain"
+	"gorm.io/gorm"
+)
+
+func newTestService(t *testing.T) (*Service, *clock.FakeClock) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.Key{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	node, _ := snowflake.NewNode(1)
+	fake := clock.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	return New(Params{DB: db, Clock: fake, GenID: node}).(*Service), fake
+}
+
+func TestBegin_ReplaysCompletedResponse(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+	req := domain.BeginRequest{OrgID: 1, Endpoint: "POST /api/refunds", Key: "key-1", RequestHash: "hash-a"}
+
+	claimed, err := svc.Begin(ctx, req)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if claimed.Status != domain.StatusProcessing {
+		t.Fatalf("expected a processing key, got %s", claimed.Status)
+	}
+
+	if _, err := svc.Begin(ctx, req); !errors.Is(err, domain.ErrRequestInProgress) {
+		t.Fatalf("expected in progress while locked, got %v", err)
+	}
+
+	body := []byte(`{"data":{"id":"1"}}`)
+	if err := svc.Complete(ctx, claimed.ID, domain.Response{Status: 201, ContentType: "application/json", Body: body}); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	replayed, err := svc.Begin(ctx, req)
+	if err != nil {
+		t.Fatalf("begin after complete: %v", err)
+	}
+	if replayed.Status != domain.StatusCompleted || replayed.ResponseStatus == nil || *replayed.ResponseStatus != 201 || string(replayed.ResponseBody) != string(body) {
+		t.Fatalf("expected the stored response, got %+v", replayed)
+	}
+
+	req.RequestHash = "hash-b"
+	if _, err := svc.Begin(ctx, req); !errors.Is(err, domain.ErrKeyReused) {
+		t.Fatalf("expected key reused for a different request, got %v", err)
+	}
+
+	// The same key on another endpoint or organization is unrelated.
+	if _, err := svc.Begin(ctx, domain.BeginRequest{OrgID: 1, Endpoint: "POST /api/invoices/1/void", Key: "key-1", RequestHash: "hash-b"}); err != nil {
+		t.Fatalf("begin other endpoint: %v", err)
+	}
+	if _, err := svc.Begin(ctx, domain.BeginRequest{OrgID: 2, Endpoint: "POST /api/refunds", Key: "key-1", RequestHash: "hash-b"}); err != nil {
+		t.Fatalf("begin other organization: %v", err)
+	}
+}
+
+func TestBegin_TakesOverStaleAndExpiredKeys(t *testing.T) {
+	svc, fake := newTestService(t)
+	ctx := context.Background()
+	req := domain.BeginRequest{OrgID: 1, Endpoint: "POST /api/refunds", Key: "key-1", RequestHash: "hash-a"}
+
+	first, err := svc.Begin(ctx, req)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	fake.Advance(domain.LockTimeout)
+	second, err := svc.Begin(ctx, req)
+	if err != nil {
+		t.Fatalf("expected a stale lock to be taken over, got %v", err)
+	}
+	if second.ID != first.ID || second.Status != domain.StatusProcessing {
+		t.Fatalf("expected the same key locked again, got %+v", second)
+	}
+	if err := svc.Complete(ctx, second.ID, domain.Response{Status: 200, ContentType: "application/json", Body: []byte(`{}`)}); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	fake.Advance(domain.TTL)
+	req.RequestHash = "hash-b"
+	third, err := svc.Begin(ctx, req)
+	if err != nil {
+		t.Fatalf("expected an expired key to be reusable, got %v", err)
+	}
+	if third.Status != domain.StatusProcessing || third.ResponseBody != nil {
+		t.Fatalf("expected the expired response to be cleared, got %+v", third)
+	}
+}
+
+func TestRelease_AllowsRetry(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+	req := domain.BeginRequest{OrgID: 1, Endpoint: "POST /api/refunds", Key: "key-1", RequestHash: "hash-a"}
+
+	claimed, err := svc.Begin(ctx, req)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err := svc.Release(ctx, claimed.ID); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, err := svc.Begin(ctx, req); err != nil {
+		t.Fatalf("expected a released key to be claimed again, got %v", err)
+	}
+
+	if _, err := svc.Begin(ctx, domain.BeginRequest{OrgID: 1, Endpoint: "POST /api/refunds", Key: " "}); !errors.Is(err, domain.ErrInvalidKey) {
+		t.Fatalf("expected invalid key, got %v", err)
+	}
+}
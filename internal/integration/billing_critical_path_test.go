@@ -8,20 +8,125 @@ import (
 	"github.com/bwmarrin/snowflake"
 	"github.com/glebarez/sqlite"
 	"github.com/railzwaylabs/railzway/internal/clock"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 
 	// Domain imports
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
+	featuredomain "github.com/railzwaylabs/railzway/internal/feature/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	productdomain "github.com/railzwaylabs/railzway/internal/product/domain"
+	productfeaturedomain "github.com/railzwaylabs/railzway/internal/productfeature/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
 
 	// Repositories
-
+	meterrepo "github.com/railzwaylabs/railzway/internal/meter/repository"
+	pricerepo "github.com/railzwaylabs/railzway/internal/price/repository"
+	priceamountrepo "github.com/railzwaylabs/railzway/internal/priceamount/repository"
+	productrepo "github.com/railzwaylabs/railzway/internal/product/repository"
 	subscriptionrepo "github.com/railzwaylabs/railzway/internal/subscription/repository"
+
+	// Services
+	invoiceservice "github.com/railzwaylabs/railzway/internal/invoice/service"
+	ledgerservice "github.com/railzwaylabs/railzway/internal/ledger/service"
+	meterservice "github.com/railzwaylabs/railzway/internal/meter/service"
+	priceservice "github.com/railzwaylabs/railzway/internal/price/service"
+	priceamountservice "github.com/railzwaylabs/railzway/internal/priceamount/service"
+	ratingservice "github.com/railzwaylabs/railzway/internal/rating/service"
+	subscriptionservice "github.com/railzwaylabs/railzway/internal/subscription/service"
 )
 
-func TestBillingCriticalPath(t *testing.T) {
+// inMemoryProductFeatureRepo serves product feature assignments from memory
+// so subscription creation can build entitlements without the features
+// tables.
+type inMemoryProductFeatureRepo struct {
+	features []productfeaturedomain.FeatureAssignment
+}
+
+func (r *inMemoryProductFeatureRepo) ListByProduct(ctx context.Context, db *gorm.DB, orgID, productID snowflake.ID) ([]productfeaturedomain.FeatureAssignment, error) {
+	return r.ListByProducts(ctx, db, orgID, []snowflake.ID{productID})
+}
+
+func (r *inMemoryProductFeatureRepo) ListByProducts(ctx context.Context, db *gorm.DB, orgID snowflake.ID, productIDs []snowflake.ID) ([]productfeaturedomain.FeatureAssignment, error) {
+	var result []productfeaturedomain.FeatureAssignment
+	for _, feature := range r.features {
+		for _, productID := range productIDs {
+			if feature.ProductID == productID {
+				result = append(result, feature)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *inMemoryProductFeatureRepo) Replace(ctx context.Context, db *gorm.DB, productID snowflake.ID, featureIDs []snowflake.ID, now time.Time) error {
+	keep := make(map[snowflake.ID]struct{}, len(featureIDs))
+	for _, id := range featureIDs {
+		keep[id] = struct{}{}
+	}
+	features := r.features[:0]
+	for _, feature := range r.features {
+		if _, ok := keep[feature.FeatureID]; feature.ProductID != productID || ok {
+			features = append(features, feature)
+		}
+	}
+	r.features = features
+	return nil
+}
+
+// unlimitedQuota never rejects; quotas are covered by the quota service tests.
+type unlimitedQuota struct{}
+
+func (unlimitedQuota) CanCreateCustomer(ctx context.Context, orgID snowflake.ID) error {
+	return nil
+}
+
+func (unlimitedQuota) CanCreateSubscription(ctx context.Context, orgID snowflake.ID) error {
+	return nil
+}
+
+func (unlimitedQuota) CanIngestUsage(ctx context.Context, orgID snowflake.ID) error {
+	return nil
+}
+
+func (unlimitedQuota) GetOrgUsage(ctx context.Context, orgID snowflake.ID) (map[string]int64, error) {
+	return map[string]int64{}, nil
+}
+
+// billingHarness wires the catalog, subscription, rating, ledger and invoice
+// services over an in-memory database, with an active subscription billing
+// API calls at $0.05 each.
+type billingHarness struct {
+	db       *gorm.DB
+	node     *snowflake.Node
+	ctx      context.Context
+	orgID    snowflake.ID
+	customer customerdomain.Customer
+	meterID  snowflake.ID
+	subID    snowflake.ID
+
+	meterSvc   meterdomain.Service
+	subSvc     subscriptiondomain.Service
+	ratingSvc  ratingdomain.Service
+	ledgerSvc  ledgerdomain.Service
+	invoiceSvc invoicedomain.Service
+}
+
+// newBillingHarness starts the subscription at cycleStart.
+func newBillingHarness(t *testing.T, cycleStart time.Time) *billingHarness {
 	// 1. Setup Infrastructure
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
 		// Logger: logger.Default.LogMode(logger.Info), // Uncomment for SQL debugging
 	})
 	require.NoError(t, err)
@@ -42,21 +147,31 @@ func TestBillingCriticalPath(t *testing.T) {
 		&subscriptiondomain.Subscription{},
 		&subscriptiondomain.SubscriptionItem{},
 		&subscriptiondomain.SubscriptionEntitlement{},
+		&subscriptiondomain.SubscriptionEvent{},
 		&billingcycledomain.BillingCycle{},
 		&usagedomain.UsageEvent{},
+		&usagedomain.UsageRollup{},
 		&ratingdomain.RatingResult{},
+		&ratingdomain.ThresholdUsage{},
 		&invoicedomain.Invoice{},
 		&invoicedomain.InvoiceItem{},
 		&invoicedomain.InvoiceTaxLine{},
+		&invoicedomain.InvoiceSequence{},
 		&ledgerdomain.LedgerAccount{},
 		&ledgerdomain.LedgerEntry{},
 		&ledgerdomain.LedgerEntryLine{},
+		&organizationdomain.Organization{},
+		&organizationdomain.OrganizationBillingPreferences{},
 	)
 	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS ux_ledger_entries_source
+		ON ledger_entries (org_id, source_type, source_id)`).Error)
 
 	node, _ := snowflake.NewNode(1)
 	logger := zap.NewNop()
-	clk := clock.New() // Real clock
+	// Pin the clock so the subscription, its entitlements and the price
+	// amount all start with the billing cycle.
+	clk := clock.NewFakeClock(cycleStart)
 
 	// 2. Initialize Repositories
 	meterRepo := meterrepo.Provide()
@@ -64,11 +179,7 @@ func TestBillingCriticalPath(t *testing.T) {
 	priceAmountRepo := priceamountrepo.Provide()
 	subRepo := subscriptionrepo.Provide()
 	productRepo := productrepo.Provide()
-	
-	usageRepo := pkg_repository.ProvideStore[usagedomain.UsageEvent](db)
-	// ratingRepo := ratingrepo.NewRepository(db) // Accessed via service usually
-	// invoiceRepo := invoicerepo.ProvideStore(db)
-	billingCycleRepo := pkg_repository.ProvideStore[billingcycledomain.BillingCycle](db)
+	productFeatureRepo := &inMemoryProductFeatureRepo{}
 
 	// 3. Initialize Services
 	meterSvc := meterservice.New(meterservice.Params{
@@ -104,35 +215,22 @@ func TestBillingCriticalPath(t *testing.T) {
 		PaymentMethodSvc:   nil,
 		Pricesvc:           priceSvc,
 		PriceAmountsvc:     priceAmountSvc,
-		ProductFeatureRepo: nil, // Mock if needed
-		QuotaSvc:           nil, // Mock?
-	})
-
-	// Usage Service needs Meter & Subscription Services
-	usageSvc := usageservice.NewService(usageservice.ServiceParam{
-		DB:       db,
-		Log:      logger,
-		GenID:    node,
-		Repo:     usageRepo,
-		MeterSvc: meterSvc,
-		SubSvc:   subSvc,
-		Clock:    clk,
-		// Outbox: optional, nil
+		ProductFeatureRepo: productFeatureRepo,
+		QuotaSvc:           unlimitedQuota{},
 	})
 
 	ratingSvc := ratingservice.NewService(ratingservice.ServiceParam{
 		DB:              db,
 		Log:             logger,
 		GenID:           node,
+		PriceRepo:       priceRepo,
 		PriceAmountRepo: priceAmountRepo,
-		BillingCycleRepo: billingCycleRepo,
 	})
 
 	ledgerSvc := ledgerservice.NewService(ledgerservice.Params{
 		DB:    db,
 		Log:   logger,
 		GenID: node,
-		// No Repo needed
 	})
 
 	invoiceSvc := invoiceservice.NewService(invoiceservice.ServiceParam{
@@ -150,35 +248,110 @@ func TestBillingCriticalPath(t *testing.T) {
 	})
 
 	// 4. Test Scenario Execution
-	ctx := context.Background()
 	orgID := node.Generate()
-	customerID := node.Generate()
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	require.NoError(t, db.Create(&organizationdomain.Organization{
+		ID: orgID, Name: "Acme", Slug: "acme", CountryCode: "US", TimezoneName: "UTC",
+	}).Error)
+	require.NoError(t, db.Create(&invoicedomain.InvoiceSequence{OrgID: orgID, NextNumber: 1, UpdatedAt: cycleStart}).Error)
+
+	customer := customerdomain.Customer{
+		ID:       node.Generate(),
+		OrgID:    orgID,
+		Name:     "Acme",
+		Email:    "billing@acme.test",
+		Currency: "USD",
+		Metadata: datatypes.JSONMap{},
+	}
+	require.NoError(t, db.Create(&customer).Error)
+
+	// 4a. Setup Catalog (Product, Meter, Price, PriceAmount, Features)
+	product := productdomain.Product{
+		ID:     int64(node.Generate()),
+		OrgID:  int64(orgID),
+		Code:   "api",
+		Name:   "API",
+		Active: true,
+	}
+	require.NoError(t, db.Create(&product).Error)
+	productID := snowflake.ID(product.ID)
 
-	// 4a. Setup Catalog (Meter, Price, PriceAmount)
-	meterID, err := createMeter(ctx, meterSvc, orgID, "api_calls", "sum")
+	meterID, err := createMeter(ctx, meterSvc, "api_calls", meterdomain.AggregationSum)
 	require.NoError(t, err)
 
-	productID := node.Generate() // Just ID
-	priceID, err := createPrice(ctx, priceSvc, orgID, productID, "price_api_calls")
+	priceID, err := createPrice(ctx, priceSvc, productID, "price_api_calls")
 	require.NoError(t, err)
 
 	// Create Price Amount: $0.05 per unit
-	err = priceAmountSvc.Create(ctx, priceamountservice.CreateRequest{
-		OrgID:           orgID,
-		PriceID:         priceID,
+	meterIDStr := meterID.String()
+	_, err = priceAmountSvc.Create(ctx, priceamountdomain.CreateRequest{
+		PriceID:         priceID.String(),
+		MeterID:         &meterIDStr,
 		Currency:        "USD",
 		UnitAmountCents: 5, // $0.05
-		MeterID:         &meterID,
-		EffectiveFrom:   time.Now().Add(-24 * time.Hour),
 	})
 	require.NoError(t, err)
 
+	productFeatureRepo.features = append(productFeatureRepo.features, productfeaturedomain.FeatureAssignment{
+		ProductID:   productID,
+		FeatureID:   node.Generate(),
+		Code:        "api_calls",
+		Name:        "API calls",
+		FeatureType: featuredomain.FeatureTypeMetered,
+		MeterID:     &meterID,
+		Active:      true,
+	})
+
 	// 4b. Create Subscription & Cycle
-	subID, err := createSubscription(db, orgID, customerID, priceID, meterID)
+	sub, err := subSvc.Create(ctx, subscriptiondomain.CreateSubscriptionRequest{
+		CustomerID:       customer.ID.String(),
+		CollectionMode:   subscriptiondomain.SubscriptionCollectionModeSendInvoice,
+		BillingCycleType: "monthly",
+		Items:            []subscriptiondomain.CreateSubscriptionItemRequest{{PriceID: priceID.String()}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, subscriptiondomain.SubscriptionStatusDraft, sub.Status)
+	require.Len(t, sub.Items, 1)
+
+	err = subSvc.TransitionSubscription(ctx, sub.ID, subscriptiondomain.SubscriptionStatusActive, "")
+	require.NoError(t, err)
+
+	subID, err := snowflake.ParseString(sub.ID)
 	require.NoError(t, err)
 
-	cycleStart := time.Now().UTC().Add(-24 * time.Hour)
-	cycleEnd := time.Now().UTC().Add(1 * time.Hour) // Ends in future initially?
+	// Entitlements come from the product's features.
+	var entitlements []subscriptiondomain.SubscriptionEntitlement
+	err = db.Where("subscription_id = ?", subID).Find(&entitlements).Error
+	require.NoError(t, err)
+	require.Len(t, entitlements, 1)
+	require.Equal(t, "api_calls", entitlements[0].FeatureCode)
+	require.NotNil(t, entitlements[0].MeterID)
+	require.Equal(t, meterID, *entitlements[0].MeterID)
+
+	return &billingHarness{
+		db:         db,
+		node:       node,
+		ctx:        ctx,
+		orgID:      orgID,
+		customer:   customer,
+		meterID:    meterID,
+		subID:      subID,
+		meterSvc:   meterSvc,
+		subSvc:     subSvc,
+		ratingSvc:  ratingSvc,
+		ledgerSvc:  ledgerSvc,
+		invoiceSvc: invoiceSvc,
+	}
+}
+
+func TestBillingCriticalPath(t *testing.T) {
+	cycleStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := cycleStart.AddDate(0, 1, 0)
+	h := newBillingHarness(t, cycleStart)
+	db, node, ctx, orgID := h.db, h.node, h.ctx, h.orgID
+	customer, meterID, subID := h.customer, h.meterID, h.subID
+	ratingSvc, ledgerSvc, invoiceSvc := h.ratingSvc, h.ledgerSvc, h.invoiceSvc
+
 	// For rating, we usually rate "Closing" cycles.
 	// Let's manually create a cycle that is "Closing" or ready to be rated.
 	cycleID := node.Generate()
@@ -189,38 +362,22 @@ func TestBillingCriticalPath(t *testing.T) {
 		PeriodStart:    cycleStart,
 		PeriodEnd:      cycleEnd,
 		Status:         billingcycledomain.BillingCycleStatusClosing, // Ready for rating
+		Metadata:       datatypes.JSONMap{},
 	}
-	err = db.Create(&cycle).Error
+	err := db.Create(&cycle).Error
 	require.NoError(t, err)
 
 	// 4c. Ingest Usage
 	// 100 units * $0.05 = $5.00 => 500 cents
-	err = usageSvc.Ingest(ctx, usagedomain.CreateIngestRequest{
-		OrgID:              orgID,
-		EventName:          "api_calls", // Meter Slug
-		ExternalCustomerID: "cust_123",  // Need to map?
-		// Usage Service resolves Customer?
-		// UsageService.Ingest -> resolveMeter, resolveSubscription.
-		// It uses ExternalCustomerID if provided but we don't have mapping service wired?
-		// Let's use Subscription ID directly if possible? No, Ingest takes UsageEvent payload.
-		// Let's Look at Ingest implementation again.
-	})
-	// Usage Ingest usually requires `meter_code` and `customer_id`.
-	// We didn't create a customer mapping.
-	// SHORTCUT: Insert UsageEvent directly into DB to bypass Ingest lookup complexity for now?
-	// OR: Fix the setup to be correct.
-	// Usage Service needs `customer_id` (Snowflake) OR `external_customer_id`.
-	// If `external`, it looks up Customer.
-	// Let's Insert a UsageEvent manually to ensure precise state for Rating.
-
-	// Insert Usage Event: 100 Units
+	// Ingest resolves the customer and meter from the event payload; insert
+	// an enriched event directly to keep the rating input precise.
 	uEvt := usagedomain.UsageEvent{
 		ID:             node.Generate(),
 		OrgID:          orgID,
 		MeterID:        meterID,
 		SubscriptionID: subID,
 		Value:          100,
-		RecordedAt:     time.Now().Add(-1 * time.Hour),
+		RecordedAt:     cycleStart.AddDate(0, 0, 10),
 		Status:         usagedomain.UsageStatusEnriched,
 	}
 	err = db.Create(&uEvt).Error
@@ -237,11 +394,11 @@ func TestBillingCriticalPath(t *testing.T) {
 	require.Len(t, results, 1)
 	require.Equal(t, int64(500), results[0].Amount) // 100 * 5 = 500
 	require.Equal(t, "USD", results[0].Currency)
+	require.Equal(t, "api_calls", results[0].FeatureCode)
 
 	// 4e. Scheduler Step: Create Ledger Entry (Simulated)
-	// We need to create Ledger Accounts first
-	mustEnsureAccount(t, db, orgID, ledgerdomain.AccountCodeRevenueUsage, "Usage Revenue")
-	mustEnsureAccount(t, db, orgID, ledgerdomain.AccountCodeAccountsReceivable, "Accounts Receivable")
+	// The org's chart of accounts is provisioned by the ledger itself
+	require.NoError(t, ledgerSvc.EnsureAccounts(ctx, orgID))
 
 	// Construct Ledger Logic (Simplified from Scheduler)
 	lines := []ledgerdomain.LedgerEntryLine{
@@ -258,14 +415,7 @@ func TestBillingCriticalPath(t *testing.T) {
 			Amount:    500,
 		},
 	}
-	_, err = ledgerSvc.CreateEntry(ctx, ledgerdomain.CreateEntryRequest{
-		OrgID:      orgID,
-		SourceType: ledgerdomain.SourceTypeBillingCycle,
-		SourceID:   cycleID,
-		Currency:   "USD",
-		OccurredAt: cycleEnd,
-		Lines:      lines,
-	})
+	err = ledgerSvc.CreateEntry(ctx, orgID, string(ledgerdomain.SourceTypeBillingCycle), cycleID, "USD", cycleEnd, lines)
 	require.NoError(t, err)
 
 	// Update Cycle to Closed (Prerequisite for Invoice?)
@@ -282,38 +432,48 @@ func TestBillingCriticalPath(t *testing.T) {
 	require.Equal(t, invoicedomain.InvoiceStatusDraft, inv.Status)
 	require.Equal(t, int64(500), inv.SubtotalAmount)
 	require.Equal(t, "USD", inv.Currency)
+	require.Equal(t, customer.ID, inv.CustomerID)
 
 	// Verify Line Items
-	require.Len(t, inv.Items, 1)
-	require.Equal(t, int64(500), inv.Items[0].Amount)
-	require.Equal(t, float64(100), inv.Items[0].Quantity)
+	var items []invoicedomain.InvoiceItem
+	err = db.Where("invoice_id = ?", inv.ID).Find(&items).Error
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, int64(500), items[0].Amount)
+	require.Equal(t, float64(100), items[0].Quantity)
 }
 
 // -----------------------------------------------------------------------------
 // Helpers
 // -----------------------------------------------------------------------------
 
-func createMeter(ctx context.Context, svc meterdomain.Service, orgID snowflake.ID, slug, aggregator string) (snowflake.ID, error) {
-	m, err := svc.Create(ctx, meterdomain.CreateMeterRequest{
-		OrgID:      orgID,
-		Slug:       slug,
-		Aggregator: aggregator,
-		Name:       slug,
+func createMeter(ctx context.Context, svc meterdomain.Service, code, aggregation string) (snowflake.ID, error) {
+	m, err := svc.Create(ctx, meterdomain.CreateRequest{
+		Code:        code,
+		Name:        code,
+		Aggregation: aggregation,
+		Unit:        "call",
 	})
 	if err != nil {
 		return 0, err
 	}
-	return m.ID, nil
+	return snowflake.ParseString(m.ID)
 }
 
-func createPrice(ctx context.Context, svc pricedomain.Service, orgID, productID snowflake.ID, code string) (snowflake.ID, error) {
-	p, err := svc.Create(ctx, pricedomain.CreatePriceRequest{
-		OrgID:       orgID,
-		ProductID:   productID,
-		Code:        code,
-		Name:        code,
-		BillingMode: "METERED", // or FLAT
-		Interval:    "MONTH",
+func createPrice(ctx context.Context, svc pricedomain.Service, productID snowflake.ID, code string) (snowflake.ID, error) {
+	billingUnit := pricedomain.API_CALL
+	aggregateUsage := pricedomain.SUM
+	p, err := svc.Create(ctx, pricedomain.CreateRequest{
+		ProductID:            productID.String(),
+		Code:                 code,
+		Name:                 code,
+		PricingModel:         pricedomain.PerUnit,
+		BillingMode:          pricedomain.Metered,
+		BillingInterval:      pricedomain.Month,
+		BillingIntervalCount: 1,
+		AggregateUsage:       &aggregateUsage,
+		BillingUnit:          &billingUnit,
+		TaxBehavior:          pricedomain.Exclusive,
 	})
 	if err != nil {
 		return 0, err
@@ -321,65 +481,6 @@ func createPrice(ctx context.Context, svc pricedomain.Service, orgID, productID
 	return p.ID, nil
 }
 
-func createSubscription(db *gorm.DB, orgID, custID, priceID, meterID snowflake.ID) (snowflake.ID, error) {
-	node, _ := snowflake.NewNode(1)
-	subID := node.Generate()
-
-	// Subscription
-	sub := subscriptiondomain.Subscription{
-		ID:         subID,
-		OrgID:      orgID,
-		CustomerID: custID,
-		Status:     subscriptiondomain.SubscriptionStatusActive,
-	}
-	if err := db.Create(&sub).Error; err != nil {
-		return 0, err
-	}
-
-	// Entitlement
-	ent := subscriptiondomain.SubscriptionEntitlement{
-		ID:             node.Generate(),
-		OrgID:          orgID,
-		SubscriptionID: subID,
-		FeatureCode:    "api_calls", // Needs to match Rating logic?
-		// Rating uses Feature Code from... where?
-		// Subscription Item -> Price.
-		// Rating logic:
-		// 1. List Sub Items.
-		// 2. Map to Entitlements?
-		// Wait, Rating logic iterates Items.
-		// We need Subscription Item.
-	}
-	// Actually Rating iterates SubscriptionItems.
-	item := subscriptiondomain.SubscriptionItem{
-		ID:             node.Generate(),
-		OrgID:          orgID,
-		SubscriptionID: subID,
-		PriceID:        priceID,
-		MeterID:        &meterID,
-		Quantity:       1, // Multiplier?
-	}
-	if err := db.Create(&item).Error; err != nil {
-		return 0, err
-	}
-
-	// We also need Entitlement for Invoice Description lookup (as per snapshot_test logic)
-	ent.FeatureCode = "feature_snapshot" // Just a dummy?
-	// Wait, recent fix in Invoice service (Step 512):
-	// "Strict Join Rule: WAS Must match entitlement. Now: Optional."
-	// But Rating Result puts FeatureCode.
-	// Rating Result FeatureCode comes from... Price?
-
-	return subID, nil
-}
-
-func mustEnsureAccount(t *testing.T, db *gorm.DB, orgID snowflake.ID, code ledgerdomain.LedgerAccountCode, name string) {
-	// Basic insert
-	err := db.Exec("INSERT INTO ledger_accounts (id, org_id, code, name, created_at) VALUES (?, ?, ?, ?, ?)",
-		snowflake.ID(time.Now().UnixNano()), orgID, code, name, time.Now()).Error
-	require.NoError(t, err)
-}
-
 func mustGetAccountID(t *testing.T, db *gorm.DB, orgID snowflake.ID, code ledgerdomain.LedgerAccountCode) snowflake.ID {
 	var id snowflake.ID
 	err := db.Raw("SELECT id FROM ledger_accounts WHERE org_id = ? AND code = ?", orgID, code).Scan(&id).Error
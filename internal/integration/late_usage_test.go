@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/railzwaylabs/railzway/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	usageservice "github.com/railzwaylabs/railzway/internal/usage/service"
+)
+
+// TestLateUsageBilledOnlyBeforeInvoicing follows late usage through ingest,
+// re-rating and invoicing. Usage arriving after the cycle closed but before
+// it was invoiced lands on the cycle's invoice through an adjustment of the
+// cycle's accrual; once the cycle is invoiced, late usage is rejected instead
+// of being re-rated onto an invoice that has already gone out.
+func TestLateUsageBilledOnlyBeforeInvoicing(t *testing.T) {
+	now := time.Now().UTC()
+	cycleStart := now.AddDate(0, 0, -30).Truncate(time.Hour)
+	cycleEnd := now.Add(-time.Hour).Truncate(time.Hour)
+	h := newBillingHarness(t, cycleStart)
+	require.NoError(t, h.ledgerSvc.EnsureAccounts(h.ctx, h.orgID))
+	require.NoError(t, h.db.Exec(`CREATE UNIQUE INDEX uidx_usage_idempotency_key
+		ON usage_events (org_id, idempotency_key)`).Error)
+
+	usageSvc := usageservice.NewService(usageservice.ServiceParam{
+		DB:       h.db,
+		Log:      zap.NewNop(),
+		GenID:    h.node,
+		MeterSvc: h.meterSvc,
+		SubSvc:   h.subSvc,
+		QuotaSvc: unlimitedQuota{},
+		Cfg:      config.Config{Usage: config.UsageConfig{LateArrivalGraceHours: 72}},
+	})
+
+	// The snapshot worker stamps the meter and subscription on accepted
+	// events before rating reads them.
+	enrich := func(event *usagedomain.UsageEvent) {
+		require.NoError(t, h.db.Model(&usagedomain.UsageEvent{}).Where("id = ?", event.ID).Updates(map[string]any{
+			"status":          usagedomain.UsageStatusEnriched,
+			"meter_id":        h.meterID,
+			"subscription_id": h.subID,
+		}).Error)
+	}
+	ingest := func(key string, value float64) (*usagedomain.UsageEvent, error) {
+		return usageSvc.Ingest(h.ctx, usagedomain.CreateIngestRequest{
+			CustomerID:     h.customer.ID.String(),
+			MeterCode:      "api_calls",
+			Value:          value,
+			RecordedAt:     cycleStart.AddDate(0, 0, 10),
+			IdempotencyKey: key,
+		})
+	}
+	cycleStatus := func(cycleID any) billingcycledomain.BillingCycleStatus {
+		var cycle billingcycledomain.BillingCycle
+		require.NoError(t, h.db.First(&cycle, "id = ?", cycleID).Error)
+		return cycle.Status
+	}
+	// rateAndClose mirrors the scheduler's rating and close steps: the first
+	// close accrues the rated revenue, a close after re-rating accrues the
+	// difference as an adjustment for the cycle.
+	var accrued int64
+	rateAndClose := func(cycle billingcycledomain.BillingCycle) {
+		require.NoError(t, h.ratingSvc.RunRating(h.ctx, cycle.ID.String()))
+
+		var rated int64
+		require.NoError(t, h.db.Model(&ratingdomain.RatingResult{}).Where("billing_cycle_id = ?", cycle.ID).
+			Select("COALESCE(SUM(amount), 0)").Scan(&rated).Error)
+		sourceType := ledgerdomain.SourceTypeBillingCycle
+		if accrued > 0 {
+			sourceType = ledgerdomain.SourceTypeAdjustment
+		}
+		if delta := rated - accrued; delta > 0 {
+			require.NoError(t, h.ledgerSvc.CreateEntry(h.ctx, h.orgID, string(sourceType), cycle.ID, "USD", cycle.PeriodEnd, []ledgerdomain.LedgerEntryLine{
+				{AccountID: mustGetAccountID(t, h.db, h.orgID, ledgerdomain.AccountCodeRevenueUsage), Direction: ledgerdomain.LedgerEntryDirectionCredit, Currency: "USD", Amount: delta},
+				{AccountID: mustGetAccountID(t, h.db, h.orgID, ledgerdomain.AccountCodeAccountsReceivable), Direction: ledgerdomain.LedgerEntryDirectionDebit, Currency: "USD", Amount: delta},
+			}))
+			accrued = rated
+		}
+
+		closedAt := time.Now().UTC()
+		require.NoError(t, h.db.Model(&billingcycledomain.BillingCycle{}).Where("id = ?", cycle.ID).Updates(map[string]any{
+			"status":              billingcycledomain.BillingCycleStatusClosed,
+			"rating_completed_at": closedAt,
+			"closed_at":           closedAt,
+		}).Error)
+	}
+
+	cycle := billingcycledomain.BillingCycle{
+		ID:             h.node.Generate(),
+		OrgID:          h.orgID,
+		SubscriptionID: h.subID,
+		PeriodStart:    cycleStart,
+		PeriodEnd:      cycleEnd,
+		Status:         billingcycledomain.BillingCycleStatusClosing,
+		Metadata:       datatypes.JSONMap{},
+	}
+	require.NoError(t, h.db.Create(&cycle).Error)
+
+	onTime, err := ingest("on-time", 100)
+	require.NoError(t, err)
+	enrich(onTime)
+	rateAndClose(cycle)
+
+	// Late usage for the closed, uninvoiced cycle reopens it for rating.
+	late, err := ingest("late", 20)
+	require.NoError(t, err)
+	require.Equal(t, billingcycledomain.BillingCycleStatusClosing, cycleStatus(cycle.ID))
+	enrich(late)
+	rateAndClose(cycle)
+
+	// The cycle's accrual has been adjusted once; further late usage is
+	// turned away rather than re-rated without reaching the ledger.
+	_, err = ingest("late-again", 10)
+	require.ErrorIs(t, err, usagedomain.ErrUsageWindowClosed)
+	require.Equal(t, billingcycledomain.BillingCycleStatusClosed, cycleStatus(cycle.ID))
+
+	var results []ratingdomain.RatingResult
+	require.NoError(t, h.db.Where("billing_cycle_id = ?", cycle.ID).Find(&results).Error)
+	require.Len(t, results, 1)
+	require.Equal(t, int64(600), results[0].Amount)
+
+	inv, err := h.invoiceSvc.GenerateInvoice(h.ctx, cycle.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, int64(600), inv.SubtotalAmount)
+	// The scheduler marks the cycle invoiced once its invoice exists.
+	require.NoError(t, h.db.Model(&billingcycledomain.BillingCycle{}).Where("id = ?", cycle.ID).
+		Update("invoiced_at", time.Now().UTC()).Error)
+
+	// Past invoicing, late usage is rejected within the grace window too and
+	// the invoiced cycle stays closed.
+	_, err = ingest("after-invoice", 50)
+	require.ErrorIs(t, err, usagedomain.ErrUsageWindowClosed)
+	require.Equal(t, billingcycledomain.BillingCycleStatusClosed, cycleStatus(cycle.ID))
+
+	var stored int64
+	require.NoError(t, h.db.Model(&usagedomain.UsageEvent{}).Where("idempotency_key = ?", "after-invoice").Count(&stored).Error)
+	require.Zero(t, stored)
+
+	var invoice invoicedomain.Invoice
+	require.NoError(t, h.db.First(&invoice, "id = ?", inv.ID).Error)
+	require.Equal(t, int64(600), invoice.SubtotalAmount)
+}
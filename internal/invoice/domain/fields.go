@@ -0,0 +1,54 @@
+package domain
+
+import "strings"
+
+// ListFields maps the columns an invoice list can be narrowed to with
+// the fields parameter to their keys in the response.
+var ListFields = map[string]string{
+	"id":               "ID",
+	"invoice_number":   "InvoiceNumber",
+	"billing_cycle_id": "BillingCycleID",
+	"billing_phase":    "BillingPhase",
+	"subscription_id":  "SubscriptionID",
+	"customer_id":      "CustomerID",
+	"status":           "Status",
+	"subtotal_amount":  "SubtotalAmount",
+	"tax_rate":         "TaxRate",
+	"tax_amount":       "TaxAmount",
+	"total_amount":     "TotalAmount",
+	"amount_paid":      "AmountPaid",
+	"currency":         "Currency",
+	"period_start":     "PeriodStart",
+	"period_end":       "PeriodEnd",
+	"issued_at":        "IssuedAt",
+	"due_at":           "DueAt",
+	"paid_at":          "PaidAt",
+	"finalized_at":     "FinalizedAt",
+	"voided_at":        "VoidedAt",
+	"rendered_pdf_url": "RenderedPDFURL",
+	"metadata":         "Metadata",
+	"created_at":       "CreatedAt",
+	"updated_at":       "UpdatedAt",
+}
+
+// ListColumns returns the columns to load for the requested fields. The id
+// and created_at columns are always loaded since list pages are cursored on
+// them; nil means every column.
+func ListColumns(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	columns := []string{"id", "created_at"}
+	seen := map[string]bool{"id": true, "created_at": true}
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if _, ok := ListFields[field]; !ok {
+			return nil, ErrInvalidFields
+		}
+		if !seen[field] {
+			seen[field] = true
+			columns = append(columns, field)
+		}
+	}
+	return columns, nil
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+)
+
+// InvoicePDFStatus tracks the generation of an invoice PDF.
+type InvoicePDFStatus string
+
+const (
+	InvoicePDFStatusPending    InvoicePDFStatus = "PENDING"
+	InvoicePDFStatusProcessing InvoicePDFStatus = "PROCESSING"
+	InvoicePDFStatusReady      InvoicePDFStatus = "READY"
+	InvoicePDFStatusFailed     InvoicePDFStatus = "FAILED"
+)
+
+// InvoicePDF holds the generated PDF of an invoice. There is at most one per
+// invoice; a failed generation is retried in place.
+type InvoicePDF struct {
+	ID                snowflake.ID     `gorm:"primaryKey"`
+	OrgID             snowflake.ID     `gorm:"not null;uniqueIndex:ux_invoice_pdfs_invoice,priority:1"`
+	InvoiceID         snowflake.ID     `gorm:"not null;uniqueIndex:ux_invoice_pdfs_invoice,priority:2"`
+	Status            InvoicePDFStatus `gorm:"type:text;not null"`
+	InvoiceTemplateID *snowflake.ID    `gorm:"column:invoice_template_id"`
+	Content           []byte           `gorm:"type:bytea"`
+	Error             *string          `gorm:"type:text"`
+	CreatedAt         time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	StartedAt         *time.Time       `gorm:""`
+	CompletedAt       *time.Time       `gorm:""`
+	UpdatedAt         time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName sets the database table name.
+func (InvoicePDF) TableName() string { return "invoice_pdfs" }
+
+// InvoicePDFResponse reports the state of an invoice PDF. Content and
+// Filename are only set when the file is ready.
+type InvoicePDFResponse struct {
+	InvoiceID   string           `json:"invoice_id"`
+	Status      InvoicePDFStatus `json:"status"`
+	ObjectRef   *string          `json:"object_ref,omitempty"`
+	Error       *string          `json:"error,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+
+	Filename string `json:"-"`
+	Content  []byte `json:"-"`
+}
@@ -16,7 +16,11 @@ type InvoiceTaxLine struct {
 	TaxMode   string       `gorm:"type:text;not null"`
 	TaxRate   float64      `gorm:"not null"`
 	Amount    int64        `gorm:"not null"` // Tax amount in cents
-	CreatedAt time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	// Jurisdiction is the region the rule applied to, e.g. "DE" or "US-CA".
+	Jurisdiction  *string   `gorm:"type:text"`
+	TaxableAmount int64     `gorm:"not null;default:0"`
+	Note          *string   `gorm:"type:text"`
+	CreatedAt     time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
 }
 
 // TableName sets the database table name.
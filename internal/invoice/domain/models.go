@@ -19,39 +19,41 @@ const (
 
 // Invoice represents a generated invoice.
 type Invoice struct {
-	ID                snowflake.ID      `gorm:"primaryKey"`
-	OrgID             snowflake.ID      `gorm:"not null;index;uniqueIndex:ux_invoice_number_org,priority:1"`
-	InvoiceSeq        *int64            `gorm:"uniqueIndex:ux_invoice_number_org,priority:2"`
-	InvoiceNumber     string            `gorm:"not null;index;"`
-	BillingCycleID    snowflake.ID      `gorm:"not null;index;uniqueIndex:ux_invoice_billing_cycle"`
-	SubscriptionID    snowflake.ID      `gorm:"not null;index"`
-	CustomerID        snowflake.ID      `gorm:"not null;index"`
-	InvoiceTemplateID *snowflake.ID     `gorm:"column:invoice_template_id;index"`
-	Status            InvoiceStatus     `gorm:"type:text;not null;default:'DRAFT'"`
-	SubtotalAmount    int64             `gorm:"not null;default:0"`
-	TaxRate           *float64          `gorm:"column:tax_rate"`
-	TaxCode           *string           `gorm:"column:tax_code"`
-	TaxAmount         int64             `gorm:"not null;default:0"`
-	TotalAmount       int64             `gorm:"not null;default:0"`
-	Currency          string            `gorm:"type:text;not null"`
-	PeriodStart       *time.Time        `gorm:""`
-	PeriodEnd         *time.Time        `gorm:""`
-	IssuedAt          *time.Time        `gorm:""`
-	DueAt             *time.Time        `gorm:""`
-	PaidAt            *time.Time        `gorm:"column:paid_at"`
-	FinalizedAt       *time.Time        `gorm:""`
-	VoidedAt          *time.Time        `gorm:""`
-	RenderedHTML      *string           `gorm:"column:rendered_html;type:text"`
-	RenderedPDFURL    *string           `gorm:"column:rendered_pdf_url;type:text"`
-	Metadata          datatypes.JSONMap `gorm:"type:jsonb;not null;default:'{}'"`
-	CreatedAt         time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP"`
-	UpdatedAt         time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP"`
-	
+	ID                     snowflake.ID      `gorm:"primaryKey"`
+	OrgID                  snowflake.ID      `gorm:"not null;index;uniqueIndex:ux_invoice_number_org,priority:1"`
+	InvoiceSeq             *int64            `gorm:"uniqueIndex:ux_invoice_number_org,priority:2"`
+	InvoiceNumber          string            `gorm:"not null;index;"`
+	BillingCycleID         snowflake.ID      `gorm:"not null;index;uniqueIndex:ux_invoice_billing_cycle,priority:1,where:billing_phase <> 'threshold' AND billing_phase <> 'one_off'"`
+	BillingPhase           string            `gorm:"type:text;not null;default:'arrears';uniqueIndex:ux_invoice_billing_cycle,priority:2"`
+	SubscriptionID         snowflake.ID      `gorm:"not null;index"`
+	CustomerID             snowflake.ID      `gorm:"not null;index"`
+	InvoiceTemplateID      *snowflake.ID     `gorm:"column:invoice_template_id;index"`
+	InvoiceTemplateVersion *int              `gorm:"column:invoice_template_version"`
+	Status                 InvoiceStatus     `gorm:"type:text;not null;default:'DRAFT'"`
+	SubtotalAmount         int64             `gorm:"not null;default:0"`
+	TaxRate                *float64          `gorm:"column:tax_rate"`
+	TaxCode                *string           `gorm:"column:tax_code"`
+	TaxAmount              int64             `gorm:"not null;default:0"`
+	TotalAmount            int64             `gorm:"not null;default:0"`
+	AmountPaid             int64             `gorm:"not null;default:0"`
+	Currency               string            `gorm:"type:text;not null"`
+	PeriodStart            *time.Time        `gorm:""`
+	PeriodEnd              *time.Time        `gorm:""`
+	IssuedAt               *time.Time        `gorm:""`
+	DueAt                  *time.Time        `gorm:""`
+	PaidAt                 *time.Time        `gorm:"column:paid_at"`
+	FinalizedAt            *time.Time        `gorm:""`
+	VoidedAt               *time.Time        `gorm:""`
+	RenderedHTML           *string           `gorm:"column:rendered_html;type:text"`
+	RenderedPDFURL         *string           `gorm:"column:rendered_pdf_url;type:text"`
+	Metadata               datatypes.JSONMap `gorm:"type:jsonb;not null;default:'{}'"`
+	CreatedAt              time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt              time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
 	// Items is populated for API responses, not persisted
 	Items []InvoiceItem `gorm:"-" json:"items,omitempty"`
 }
 
-
 // TableName sets the database table name.
 func (Invoice) TableName() string { return "invoices" }
 
@@ -21,6 +21,8 @@ type ListInvoiceRequest struct {
 	FinalizedTo   *time.Time
 	TotalMin      *int64
 	TotalMax      *int64
+	// Fields narrows the loaded columns to those listed in ListFields.
+	Fields []string
 
 	PageToken string
 	PageSize  int
@@ -31,6 +33,35 @@ type ListInvoiceResponse struct {
 	Invoices []Invoice `json:"invoices"`
 }
 
+// CreateOneTimeChargeRequest bills a customer once, outside any
+// subscription. Currency defaults to the customer's billing currency.
+type CreateOneTimeChargeRequest struct {
+	CustomerID string
+	Currency   string
+	Items      []OneTimeChargeItem
+}
+
+// OneTimeChargeItem is a line of a one-time charge. Quantity defaults to 1
+// and UnitAmount is in the currency's minor unit.
+type OneTimeChargeItem struct {
+	Description string
+	Quantity    float64
+	UnitAmount  int64
+}
+
+// ChargeInvoiceResponse is the outcome of charging an invoice on demand, as
+// recorded in the invoice's auto-charge metadata. Error fields are set only
+// when the charge failed.
+type ChargeInvoiceResponse struct {
+	Invoice           Invoice `json:"invoice"`
+	Provider          string  `json:"provider,omitempty"`
+	Status            string  `json:"status"`
+	PaymentIntentID   string  `json:"payment_intent_id,omitempty"`
+	ProviderInvoiceID string  `json:"provider_invoice_id,omitempty"`
+	ErrorCode         string  `json:"error_code,omitempty"`
+	ErrorMessage      string  `json:"error_message,omitempty"`
+}
+
 type RenderInvoiceResponse struct {
 	InvoiceTemplateID *string `json:"invoice_template_id,omitempty"`
 	RenderedHTML      string  `json:"rendered_html"`
@@ -43,23 +74,68 @@ type Service interface {
 	GetByID(ctx context.Context, id string) (Invoice, error)
 	RenderInvoice(ctx context.Context, invoiceID string) (RenderInvoiceResponse, error)
 	GenerateInvoice(ctx context.Context, billingCycleID string) (*Invoice, error)
-	FinalizeInvoice(ctx context.Context, invoiceID string) error
+	// GenerateAdvanceInvoice bills the advance charges of an open billing cycle.
+	GenerateAdvanceInvoice(ctx context.Context, billingCycleID string) (*Invoice, error)
+	// GenerateThresholdInvoice bills the threshold charges of an open billing
+	// cycle that no earlier threshold invoice billed.
+	GenerateThresholdInvoice(ctx context.Context, billingCycleID string) (*Invoice, error)
+	// CreateOneTimeCharge invoices a one-time charge with no subscription or
+	// billing cycle and finalizes it, so the customer's default payment
+	// method is charged when there is one.
+	CreateOneTimeCharge(ctx context.Context, req CreateOneTimeChargeRequest) (*Invoice, error)
+	// FinalizeInvoice finalizes a draft invoice and returns it. Finalizing an
+	// invoice that is already finalized returns it unchanged, without posting
+	// it to the ledger or charging it again.
+	FinalizeInvoice(ctx context.Context, invoiceID string) (*Invoice, error)
+	// VoidInvoice voids a finalized invoice nothing was paid or credited
+	// against and reverses its ledger posting. Paid invoices need a credit
+	// note instead.
 	VoidInvoice(ctx context.Context, invoiceID string, reason string) error
+	// CreditCanceledSubscription gives the customer of an immediately
+	// canceled subscription back the unused part of the cycle billed
+	// upfront, as the organization's cancellation refund preference asks.
+	CreditCanceledSubscription(ctx context.Context, subscriptionID string) error
+	// RetryFailedAutoCharges re-attempts failed auto-charges whose backoff has
+	// elapsed and returns how many invoices were retried.
+	RetryFailedAutoCharges(ctx context.Context, maxRetries int, limit int) (int, error)
+	// ChargeInvoice charges the remaining balance of an unpaid invoice of a
+	// charge_automatically subscription now, waiting for the provider instead
+	// of charging in the background.
+	ChargeInvoice(ctx context.Context, invoiceID string) (ChargeInvoiceResponse, error)
+	// RequestInvoicePDF queues PDF generation for a finalized invoice. An
+	// invoice whose PDF is queued or ready is left as is.
+	RequestInvoicePDF(ctx context.Context, invoiceID string) (InvoicePDFResponse, error)
+	// GetInvoicePDF returns the invoice PDF, with Content set once it is ready.
+	GetInvoicePDF(ctx context.Context, invoiceID string) (InvoicePDFResponse, error)
+	// GenerateQueuedInvoicePDFs renders up to limit queued PDFs and returns
+	// how many were attempted.
+	GenerateQueuedInvoicePDFs(ctx context.Context, limit int) (int, error)
 }
 
 var (
 	ErrInvalidOrganization     = errors.New("invalid_organization")
 	ErrInvalidBillingCycle     = errors.New("invalid_billing_cycle")
+	ErrInvalidSubscription     = errors.New("invalid_subscription")
+	ErrInvalidCustomer         = errors.New("invalid_customer")
+	ErrInvalidCurrency         = errors.New("invalid_currency")
+	ErrInvalidChargeItem       = errors.New("invalid_charge_item")
 	ErrBillingCycleNotFound    = errors.New("billing_cycle_not_found")
 	ErrBillingCycleNotClosed   = errors.New("billing_cycle_not_closed")
+	ErrBillingCycleNotOpen     = errors.New("billing_cycle_not_open")
 	ErrMissingLedgerEntry      = errors.New("missing_ledger_entry")
 	ErrMissingRatingResults    = errors.New("missing_rating_results")
 	ErrCurrencyMismatch        = errors.New("currency_mismatch")
 	ErrInvalidInvoiceID        = errors.New("invalid_invoice_id")
+	ErrInvalidFields           = errors.New("invalid_fields")
 	ErrInvalidSubtotal         = errors.New("invalid_subtotal_amount")
 	ErrInvoiceNotFound         = errors.New("invoice_not_found")
 	ErrInvoiceNotDraft         = errors.New("invoice_not_draft")
 	ErrInvoiceNotFinalized     = errors.New("invoice_not_finalized")
 	ErrInvoiceTemplateNotFound = errors.New("invoice_template_not_found")
 	ErrInvoiceRenderMissing    = errors.New("invoice_render_missing")
+	ErrInvoicePaid             = errors.New("invoice_paid")
+	ErrInvoiceCredited         = errors.New("invoice_credited")
+	ErrInvoiceNotChargeable    = errors.New("invoice_not_chargeable")
+	ErrInvoicePDFNotFound      = errors.New("invoice_pdf_not_found")
+	ErrInvoicePDFFailed        = errors.New("invoice_pdf_failed")
 )
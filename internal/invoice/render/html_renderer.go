@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/railzwaylabs/railzway/internal/currency"
 )
 
 const invoiceHTMLTemplate = `<!doctype html>
@@ -289,13 +291,12 @@ func (r *HTMLRenderer) RenderHTML(input RenderInput) (string, error) {
 	return buf.String(), nil
 }
 
-func formatMoney(amount int64, currency string) string {
-	currency = strings.ToUpper(strings.TrimSpace(currency))
-	if currency == "" {
-		currency = "USD"
+func formatMoney(amount int64, code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		code = "USD"
 	}
-	value := float64(amount) / 100.0
-	return fmt.Sprintf("%s %.2f", currency, value)
+	return fmt.Sprintf("%s %.*f", code, currency.Decimals(code), currency.ToMajorUnits(amount, code))
 }
 
 func formatDate(value *time.Time) string {
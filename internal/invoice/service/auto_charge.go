@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"math"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -13,7 +12,10 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/currency"
 	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	obsmetrics "github.com/railzwaylabs/railzway/internal/observability/metrics"
+	stripeadapter "github.com/railzwaylabs/railzway/internal/payment/adapters/stripe"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
 	"go.uber.org/zap"
@@ -36,13 +38,19 @@ type stripeAutoChargeError struct {
 type stripeAutoChargeClient struct {
 	apiKey    string
 	accountID string
+	baseURL   string
 	client    *http.Client
 }
 
-func newStripeAutoChargeClient(apiKey string, accountID string) *stripeAutoChargeClient {
+func newStripeAutoChargeClient(apiKey string, accountID string, baseURL string) *stripeAutoChargeClient {
+	baseURL = strings.TrimSpace(baseURL)
+	if baseURL == "" {
+		baseURL = "https://api.stripe.com"
+	}
 	return &stripeAutoChargeClient{
 		apiKey:    strings.TrimSpace(apiKey),
 		accountID: strings.TrimSpace(accountID),
+		baseURL:   baseURL,
 		client:    &http.Client{Timeout: 12 * time.Second},
 	}
 }
@@ -123,12 +131,13 @@ func (c *stripeAutoChargeClient) createAndConfirmPaymentIntent(
 	amount int64,
 	paymentMethodID string,
 	customerProviderID string,
+	idempotencyKey string,
 ) (stripeAutoChargeIntent, error) {
 	if invoice == nil {
 		return stripeAutoChargeIntent{}, paymentdomain.ErrInvalidConfig
 	}
 	values := url.Values{}
-	values.Set("amount", strconv.FormatInt(amount, 10))
+	values.Set("amount", strconv.FormatInt(stripeadapter.ToStripeAmount(amount, invoice.Currency), 10))
 	values.Set("currency", strings.ToLower(invoice.Currency))
 	values.Set("payment_method", strings.TrimSpace(paymentMethodID))
 	values.Set("confirm", "true")
@@ -141,7 +150,7 @@ func (c *stripeAutoChargeClient) createAndConfirmPaymentIntent(
 		values.Set("customer", strings.TrimSpace(customerProviderID))
 	}
 
-	return c.doRequest(ctx, http.MethodPost, "/v1/payment_intents", values, "auto_charge:"+invoice.ID.String())
+	return c.doRequest(ctx, http.MethodPost, "/v1/payment_intents", values, idempotencyKey)
 }
 
 func (c *stripeAutoChargeClient) doRequest(
@@ -155,7 +164,7 @@ func (c *stripeAutoChargeClient) doRequest(
 		return stripeAutoChargeIntent{}, paymentdomain.ErrInvalidConfig
 	}
 	bodyReader := strings.NewReader(values.Encode())
-	req, err := http.NewRequestWithContext(ctx, method, "https://api.stripe.com"+path, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
 	if err != nil {
 		return stripeAutoChargeIntent{}, err
 	}
@@ -218,34 +227,38 @@ func (s *Service) autoChargeInvoice(ctx context.Context, invoice *invoicedomain.
 	if invoice == nil {
 		return nil
 	}
-	if invoice.TotalAmount <= 0 || invoice.PaidAt != nil {
+	if invoiceAmountDue(invoice) <= 0 || invoice.PaidAt != nil {
 		return nil
 	}
 	if s.paymentMethodSvc == nil || s.paymentProviderSvc == nil {
 		return nil
 	}
 
-	mode, err := s.loadSubscriptionCollectionMode(ctx, invoice.OrgID, invoice.SubscriptionID)
-	if err != nil {
-		return err
-	}
-	if mode != subscriptiondomain.SubscriptionCollectionModeChargeAutomatically {
-		return nil
+	// One-time charges have no subscription and are charged whenever the
+	// customer has a default payment method; without one they wait for a
+	// manual payment instead of failing.
+	oneOff := invoice.SubscriptionID == 0
+	if !oneOff {
+		mode, err := s.loadSubscriptionCollectionMode(ctx, invoice.OrgID, invoice.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		if mode != subscriptiondomain.SubscriptionCollectionModeChargeAutomatically {
+			return nil
+		}
 	}
 
 	pm, err := s.paymentMethodSvc.GetDefaultPaymentMethod(ctx, invoice.CustomerID)
-	if err != nil {
-		if errors.Is(err, paymentdomain.ErrPaymentMethodNotFound) {
+	if errors.Is(err, paymentdomain.ErrPaymentMethodNotFound) || (err == nil && pm == nil) {
+		if !oneOff {
 			s.recordAutoChargeFailure(ctx, invoice, "", "missing_payment_method", "")
-			return nil
 		}
+		return nil
+	}
+	if err != nil {
 		s.recordAutoChargeFailure(ctx, invoice, "", "payment_method_error", err.Error())
 		return err
 	}
-	if pm == nil {
-		s.recordAutoChargeFailure(ctx, invoice, "", "missing_payment_method", "")
-		return nil
-	}
 
 	provider := strings.ToLower(strings.TrimSpace(pm.Provider))
 	if provider == "" {
@@ -296,9 +309,11 @@ func (s *Service) autoChargeStripe(
 
 	customerProviderID := s.loadCustomerProviderID(ctx, invoice.CustomerID)
 	accountID := readConfigString(config, "stripe_account_id")
-	client := newStripeAutoChargeClient(secret, accountID)
+	client := newStripeAutoChargeClient(secret, accountID, readConfigString(config, "base_url"))
 
-	intent, err := client.createAndConfirmPaymentIntent(ctx, invoice, invoice.TotalAmount, paymentMethodID, customerProviderID)
+	started := time.Now()
+	intent, err := client.createAndConfirmPaymentIntent(ctx, invoice, invoiceAmountDue(invoice), paymentMethodID, customerProviderID, autoChargeIdempotencyKey(invoice))
+	obsmetrics.Billing().ObserveAutoChargeRequest(s.loadOrgTier(ctx, invoice.OrgID), "stripe", time.Since(started), err)
 	if err != nil {
 		s.recordAutoChargeFailure(ctx, invoice, "stripe", "charge_failed", err.Error())
 		return err
@@ -315,6 +330,7 @@ func (s *Service) autoChargeStripe(
 	if accountID != "" {
 		updates["stripe_account_id"] = accountID
 	}
+	obsmetrics.Billing().IncAutoCharge(s.loadOrgTier(ctx, invoice.OrgID), "stripe", "")
 	return s.mergeInvoiceMetadata(ctx, invoice.OrgID, invoice.ID, updates)
 }
 
@@ -330,7 +346,7 @@ func (s *Service) autoChargeXendit(
 		return err
 	}
 
-	amountMajor := amountToMajor(invoice.TotalAmount, invoice.Currency)
+	amountMajor := currency.ToMajorUnits(invoiceAmountDue(invoice), invoice.Currency)
 	if amountMajor <= 0 {
 		return nil
 	}
@@ -338,7 +354,9 @@ func (s *Service) autoChargeXendit(
 	externalID := buildXenditExternalID(invoice.CustomerID, invoice.ID)
 	client := newXenditAutoChargeClient(apiKey, readConfigString(config, "base_url"))
 
+	started := time.Now()
 	resp, err := client.createInvoice(ctx, invoice, amountMajor, externalID)
+	obsmetrics.Billing().ObserveAutoChargeRequest(s.loadOrgTier(ctx, invoice.OrgID), "xendit", time.Since(started), err)
 	if err != nil {
 		s.recordAutoChargeFailure(ctx, invoice, "xendit", "charge_failed", err.Error())
 		return err
@@ -352,9 +370,32 @@ func (s *Service) autoChargeXendit(
 		"auto_charge_external_id":  externalID,
 		"payment_provider":         "xendit",
 	}
+	obsmetrics.Billing().IncAutoCharge(s.loadOrgTier(ctx, invoice.OrgID), "xendit", "")
 	return s.mergeInvoiceMetadata(ctx, invoice.OrgID, invoice.ID, updates)
 }
 
+// autoChargeIdempotencyKey identifies a charge attempt on invoice to the
+// provider. Retries and manual charges are counted in auto_charge_retry_count
+// and auto_charge_manual_count before they charge, so each one is sent as a
+// new request instead of replaying an earlier failed attempt.
+func autoChargeIdempotencyKey(invoice *invoicedomain.Invoice) string {
+	key := "auto_charge:" + invoice.ID.String()
+	if retry := metadataInt(invoice.Metadata["auto_charge_retry_count"]); retry > 0 {
+		key += ":retry:" + strconv.Itoa(retry)
+	}
+	if manual := metadataInt(invoice.Metadata["auto_charge_manual_count"]); manual > 0 {
+		key += ":manual:" + strconv.Itoa(manual)
+	}
+	return key
+}
+
+// invoiceAmountDue is what is left to collect on invoice after any partial
+// payments and the credit notes issued against it.
+func invoiceAmountDue(invoice *invoicedomain.Invoice) int64 {
+	credited := int64(metadataInt(invoice.Metadata["amount_credited"]))
+	return invoice.TotalAmount - invoice.AmountPaid - credited
+}
+
 func (s *Service) recordAutoChargeFailure(
 	ctx context.Context,
 	invoice *invoicedomain.Invoice,
@@ -365,6 +406,7 @@ func (s *Service) recordAutoChargeFailure(
 	if invoice == nil {
 		return
 	}
+	obsmetrics.Billing().IncAutoCharge(s.loadOrgTier(ctx, invoice.OrgID), provider, reason)
 	updates := map[string]any{
 		"auto_charge_attempted_at": time.Now().UTC().Format(time.RFC3339),
 		"auto_charge_status":       "failed",
@@ -460,19 +502,6 @@ func readConfigString(config map[string]any, keys ...string) string {
 	return ""
 }
 
-func amountToMajor(amount int64, currency string) float64 {
-	c := strings.ToUpper(strings.TrimSpace(currency))
-	decimals, ok := currencyDecimals[c]
-	if !ok {
-		decimals = 2
-	}
-	divisor := math.Pow10(decimals)
-	if divisor == 0 {
-		return float64(amount)
-	}
-	return float64(amount) / divisor
-}
-
 func buildXenditExternalID(customerID snowflake.ID, invoiceID snowflake.ID) string {
 	if customerID == 0 {
 		return ""
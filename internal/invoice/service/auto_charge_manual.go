@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+// ChargeInvoice re-runs auto-charge on a finalized, unpaid invoice of a
+// charge_automatically subscription, typically after a transient provider
+// failure. Partially paid invoices are charged the remaining balance. Unlike
+// the charge made at finalization it waits for the provider and reports the
+// outcome recorded on the invoice, failed charges included.
+func (s *Service) ChargeInvoice(ctx context.Context, invoiceID string) (invoicedomain.ChargeInvoiceResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return invoicedomain.ChargeInvoiceResponse{}, invoicedomain.ErrInvalidOrganization
+	}
+	id, err := parseID(strings.TrimSpace(invoiceID))
+	if err != nil {
+		return invoicedomain.ChargeInvoiceResponse{}, invoicedomain.ErrInvalidInvoiceID
+	}
+
+	invoice, err := s.invoicerepo.FindOne(ctx, &invoicedomain.Invoice{ID: id, OrgID: orgID})
+	if err != nil {
+		return invoicedomain.ChargeInvoiceResponse{}, err
+	}
+	if invoice == nil {
+		return invoicedomain.ChargeInvoiceResponse{}, invoicedomain.ErrInvoiceNotFound
+	}
+	if s.orgGate != nil {
+		if err := s.orgGate.MustBeActive(ctx, invoice.OrgID); err != nil {
+			return invoicedomain.ChargeInvoiceResponse{}, err
+		}
+	}
+	if invoice.Status != invoicedomain.InvoiceStatusFinalized {
+		return invoicedomain.ChargeInvoiceResponse{}, invoicedomain.ErrInvoiceNotFinalized
+	}
+	if invoice.PaidAt != nil || (invoice.TotalAmount > 0 && invoiceAmountDue(invoice) <= 0) {
+		return invoicedomain.ChargeInvoiceResponse{}, invoicedomain.ErrInvoicePaid
+	}
+	if invoice.TotalAmount <= 0 || invoice.SubscriptionID == 0 {
+		return invoicedomain.ChargeInvoiceResponse{}, invoicedomain.ErrInvoiceNotChargeable
+	}
+	if s.paymentMethodSvc == nil || s.paymentProviderSvc == nil {
+		return invoicedomain.ChargeInvoiceResponse{}, invoicedomain.ErrInvoiceNotChargeable
+	}
+	mode, err := s.loadSubscriptionCollectionMode(ctx, invoice.OrgID, invoice.SubscriptionID)
+	if err != nil {
+		return invoicedomain.ChargeInvoiceResponse{}, err
+	}
+	if mode != subscriptiondomain.SubscriptionCollectionModeChargeAutomatically {
+		return invoicedomain.ChargeInvoiceResponse{}, invoicedomain.ErrInvoiceNotChargeable
+	}
+
+	// Count the attempt first so it gets its own provider idempotency key.
+	manualCount := metadataInt(invoice.Metadata["auto_charge_manual_count"]) + 1
+	if err := s.mergeInvoiceMetadata(ctx, invoice.OrgID, invoice.ID, map[string]any{
+		"auto_charge_manual_count": manualCount,
+	}); err != nil {
+		return invoicedomain.ChargeInvoiceResponse{}, err
+	}
+	if invoice.Metadata == nil {
+		invoice.Metadata = datatypes.JSONMap{}
+	}
+	invoice.Metadata["auto_charge_manual_count"] = manualCount
+
+	chargeErr := s.autoChargeInvoice(ctx, invoice)
+
+	charged, err := s.GetByID(ctx, invoice.ID.String())
+	if err != nil {
+		return invoicedomain.ChargeInvoiceResponse{}, err
+	}
+	resp := chargeInvoiceResponse(charged)
+	if chargeErr != nil {
+		// Provider failures are recorded on the invoice and reported as a
+		// failed charge; anything else left no outcome to report.
+		if resp.Status != "failed" {
+			return invoicedomain.ChargeInvoiceResponse{}, chargeErr
+		}
+		s.log.Warn("manual auto-charge failed", zap.Error(chargeErr), zap.String("invoice_id", invoice.ID.String()))
+	}
+
+	metadata := map[string]any{
+		"charge_status": resp.Status,
+	}
+	if resp.Provider != "" {
+		metadata["provider"] = resp.Provider
+	}
+	if resp.ErrorCode != "" {
+		metadata["error_code"] = resp.ErrorCode
+	}
+	s.emitAudit(ctx, "invoice.charge", invoice, metadata)
+	return resp, nil
+}
+
+// chargeInvoiceResponse reads the last auto-charge outcome from the invoice
+// metadata. Error details of an earlier failure are dropped once a later
+// attempt got past the provider.
+func chargeInvoiceResponse(invoice invoicedomain.Invoice) invoicedomain.ChargeInvoiceResponse {
+	metadata := map[string]any(invoice.Metadata)
+	resp := invoicedomain.ChargeInvoiceResponse{
+		Invoice:  invoice,
+		Provider: readConfigString(metadata, "auto_charge_provider"),
+		Status:   readConfigString(metadata, "auto_charge_status"),
+	}
+	if resp.Status == "failed" {
+		resp.ErrorCode = readConfigString(metadata, "auto_charge_error_code")
+		resp.ErrorMessage = readConfigString(metadata, "auto_charge_error_message")
+		return resp
+	}
+	switch resp.Provider {
+	case "stripe":
+		resp.PaymentIntentID = readConfigString(metadata, "auto_charge_payment_intent_id")
+	case "xendit":
+		resp.ProviderInvoiceID = readConfigString(metadata, "auto_charge_invoice_id")
+	}
+	return resp
+}
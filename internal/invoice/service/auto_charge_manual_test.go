@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	paymentproviderdomain "github.com/railzwaylabs/railzway/internal/providers/payment/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type xenditPaymentMethodSvc struct {
+	paymentdomain.PaymentMethodService
+}
+
+func (xenditPaymentMethodSvc) GetDefaultPaymentMethod(ctx context.Context, customerID snowflake.ID) (*paymentdomain.PaymentMethod, error) {
+	return &paymentdomain.PaymentMethod{CustomerID: customerID, Provider: "xendit"}, nil
+}
+
+type staticProviderConfigSvc struct {
+	paymentproviderdomain.Service
+	config string
+}
+
+func (s staticProviderConfigSvc) GetActiveProviderConfig(ctx context.Context, orgID snowflake.ID, provider string) (*paymentproviderdomain.ProviderConfig, error) {
+	return &paymentproviderdomain.ProviderConfig{OrgID: int64(orgID), Provider: provider, Config: datatypes.JSON(s.config), IsActive: true}, nil
+}
+
+func TestChargeInvoice(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&invoicedomain.Invoice{}, &invoicedomain.InvoiceItem{}))
+	require.NoError(t, db.Exec(`CREATE TABLE subscriptions (id BIGINT PRIMARY KEY, org_id BIGINT, collection_mode TEXT)`).Error)
+
+	providerUp := false
+	var charged float64
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Amount float64 `json:"amount"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		charged = payload.Amount
+		if !providerUp {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"xinv_1","status":"PENDING"}`))
+	}))
+	defer provider.Close()
+
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(ServiceParam{DB: db, Log: zap.NewNop(), GenID: node}).(*Service)
+	svc.paymentMethodSvc = xenditPaymentMethodSvc{}
+	svc.paymentProviderSvc = staticProviderConfigSvc{config: `{"api_key":"xnd_test","base_url":"` + provider.URL + `"}`}
+
+	orgID := node.Generate()
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	newInvoice := func(mode subscriptiondomain.SubscriptionCollectionMode, amountPaid int64) invoicedomain.Invoice {
+		subscriptionID := node.Generate()
+		require.NoError(t, db.Exec(`INSERT INTO subscriptions (id, org_id, collection_mode) VALUES (?, ?, ?)`, subscriptionID, orgID, mode).Error)
+		now := time.Now().UTC()
+		invoice := invoicedomain.Invoice{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			BillingCycleID: node.Generate(),
+			SubscriptionID: subscriptionID,
+			CustomerID:     node.Generate(),
+			InvoiceNumber:  node.Generate().String(),
+			Currency:       "IDR",
+			Status:         invoicedomain.InvoiceStatusFinalized,
+			SubtotalAmount: 150000,
+			TotalAmount:    150000,
+			Metadata:       datatypes.JSONMap{},
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		invoice.AmountPaid = amountPaid
+		if amountPaid == invoice.TotalAmount {
+			invoice.PaidAt = &now
+		}
+		require.NoError(t, db.Create(&invoice).Error)
+		return invoice
+	}
+
+	invoice := newInvoice(subscriptiondomain.SubscriptionCollectionModeChargeAutomatically, 0)
+
+	// A provider outage is reported as a failed charge, not an error.
+	resp, err := svc.ChargeInvoice(ctx, invoice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "xendit", resp.Provider)
+	assert.Equal(t, "failed", resp.Status)
+	assert.Equal(t, "charge_failed", resp.ErrorCode)
+	assert.Equal(t, "failed", resp.Invoice.Metadata["auto_charge_status"])
+
+	providerUp = true
+	resp, err = svc.ChargeInvoice(ctx, invoice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "PENDING", resp.Status)
+	assert.Equal(t, "xinv_1", resp.ProviderInvoiceID)
+	assert.Empty(t, resp.ErrorCode)
+	assert.Equal(t, "xinv_1", resp.Invoice.Metadata["auto_charge_invoice_id"])
+	assert.Equal(t, float64(150000), charged)
+
+	// A partially paid invoice is charged what is left.
+	resp, err = svc.ChargeInvoice(ctx, newInvoice(subscriptiondomain.SubscriptionCollectionModeChargeAutomatically, 50000).ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "PENDING", resp.Status)
+	assert.Equal(t, float64(100000), charged)
+
+	// Credit notes reduce the charge like payments do; a fully credited
+	// invoice has nothing left to charge.
+	credited := newInvoice(subscriptiondomain.SubscriptionCollectionModeChargeAutomatically, 50000)
+	require.NoError(t, svc.mergeInvoiceMetadata(ctx, orgID, credited.ID, map[string]any{"amount_credited": 30000}))
+	resp, err = svc.ChargeInvoice(ctx, credited.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "PENDING", resp.Status)
+	assert.Equal(t, float64(70000), charged)
+
+	credited = newInvoice(subscriptiondomain.SubscriptionCollectionModeChargeAutomatically, 50000)
+	require.NoError(t, svc.mergeInvoiceMetadata(ctx, orgID, credited.ID, map[string]any{"amount_credited": 100000}))
+	_, err = svc.ChargeInvoice(ctx, credited.ID.String())
+	assert.ErrorIs(t, err, invoicedomain.ErrInvoicePaid)
+
+	_, err = svc.ChargeInvoice(ctx, newInvoice(subscriptiondomain.SubscriptionCollectionModeSendInvoice, 0).ID.String())
+	assert.ErrorIs(t, err, invoicedomain.ErrInvoiceNotChargeable)
+
+	_, err = svc.ChargeInvoice(ctx, newInvoice(subscriptiondomain.SubscriptionCollectionModeChargeAutomatically, 150000).ID.String())
+	assert.ErrorIs(t, err, invoicedomain.ErrInvoicePaid)
+
+	_, err = svc.ChargeInvoice(orgcontext.WithOrgID(context.Background(), int64(node.Generate())), invoice.ID.String())
+	assert.ErrorIs(t, err, invoicedomain.ErrInvoiceNotFound)
+}
+
+// TestChargeInvoiceSendsNewIdempotencyKeys verifies that each manual charge
+// reaches Stripe as a new request rather than replaying a declined earlier
+// attempt under its idempotency key.
+func TestChargeInvoiceSendsNewIdempotencyKeys(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&invoicedomain.Invoice{}, &invoicedomain.InvoiceItem{}))
+	require.NoError(t, db.Exec(`CREATE TABLE subscriptions (id BIGINT PRIMARY KEY, org_id BIGINT, collection_mode TEXT)`).Error)
+
+	var keys []string
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"error":{"message":"card_declined"}}`))
+	}))
+	defer provider.Close()
+
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(ServiceParam{DB: db, Log: zap.NewNop(), GenID: node}).(*Service)
+	svc.paymentMethodSvc = stripePaymentMethodSvc{}
+	svc.paymentProviderSvc = staticProviderConfigSvc{config: `{"api_key":"sk_test","base_url":"` + provider.URL + `"}`}
+
+	orgID := node.Generate()
+	subscriptionID := node.Generate()
+	require.NoError(t, db.Exec(`INSERT INTO subscriptions (id, org_id, collection_mode) VALUES (?, ?, ?)`,
+		subscriptionID, orgID, subscriptiondomain.SubscriptionCollectionModeChargeAutomatically).Error)
+	now := time.Now().UTC()
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		BillingCycleID: node.Generate(),
+		SubscriptionID: subscriptionID,
+		CustomerID:     node.Generate(),
+		InvoiceNumber:  "INV-1",
+		Currency:       "USD",
+		Status:         invoicedomain.InvoiceStatusFinalized,
+		SubtotalAmount: 1000,
+		TotalAmount:    1000,
+		Metadata:       datatypes.JSONMap{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	for i := 0; i < 2; i++ {
+		resp, err := svc.ChargeInvoice(ctx, invoice.ID.String())
+		require.NoError(t, err)
+		require.Equal(t, "failed", resp.Status)
+	}
+
+	require.Equal(t, []string{
+		"auto_charge:" + invoice.ID.String() + ":manual:1",
+		"auto_charge:" + invoice.ID.String() + ":manual:2",
+	}, keys)
+}
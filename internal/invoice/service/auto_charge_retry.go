@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+// autoChargeRetryBackoff is the wait after the last attempt before each retry.
+// Retries past the end of the schedule reuse the last delay.
+var autoChargeRetryBackoff = []time.Duration{
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+}
+
+// RetryFailedAutoCharges re-attempts auto-charge on finalized, unpaid invoices
+// whose last attempt failed. Each invoice is retried at most maxRetries times,
+// spaced by autoChargeRetryBackoff from auto_charge_attempted_at. It returns
+// the number of invoices retried, at most limit.
+func (s *Service) RetryFailedAutoCharges(ctx context.Context, maxRetries int, limit int) (int, error) {
+	if maxRetries <= 0 || limit <= 0 {
+		return 0, nil
+	}
+	if s.paymentMethodSvc == nil || s.paymentProviderSvc == nil {
+		return 0, nil
+	}
+
+	invoices, err := s.loadAutoChargeRetryCandidates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	retried := 0
+	for i := range invoices {
+		if retried >= limit {
+			break
+		}
+		invoice := &invoices[i]
+		retryCount, due := autoChargeRetryDue(invoice.Metadata, maxRetries, now)
+		if !due {
+			continue
+		}
+
+		// Stamp the attempt first so a crash mid-charge still counts it.
+		if err := s.mergeInvoiceMetadata(ctx, invoice.OrgID, invoice.ID, map[string]any{
+			"auto_charge_retry_count": retryCount + 1,
+		}); err != nil {
+			return retried, err
+		}
+		if invoice.Metadata == nil {
+			invoice.Metadata = datatypes.JSONMap{}
+		}
+		invoice.Metadata["auto_charge_retry_count"] = retryCount + 1
+		retried++
+
+		if err := s.autoChargeInvoice(ctx, invoice); err != nil {
+			s.log.Warn("auto-charge retry failed",
+				zap.Error(err),
+				zap.String("invoice_id", invoice.ID.String()),
+				zap.Int("retry_count", retryCount+1),
+			)
+		}
+	}
+
+	return retried, nil
+}
+
+// loadAutoChargeRetryCandidates returns the invoices whose last auto-charge
+// failed and that are still owed. Paid, voided and fully credited invoices are
+// never retried.
+func (s *Service) loadAutoChargeRetryCandidates(ctx context.Context) ([]invoicedomain.Invoice, error) {
+	var invoices []invoicedomain.Invoice
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT id, org_id, invoice_number, billing_cycle_id, subscription_id, customer_id,
+		        status, total_amount, amount_paid, currency, paid_at, voided_at, metadata
+		 FROM invoices
+		 WHERE status = ? AND paid_at IS NULL AND voided_at IS NULL AND total_amount > amount_paid
+		 AND metadata->>'auto_charge_status' = ?
+		 ORDER BY updated_at ASC, id ASC`,
+		invoicedomain.InvoiceStatusFinalized,
+		"failed",
+	).Scan(&invoices).Error; err != nil {
+		return nil, err
+	}
+
+	owed := invoices[:0]
+	for i := range invoices {
+		if invoiceAmountDue(&invoices[i]) > 0 {
+			owed = append(owed, invoices[i])
+		}
+	}
+	return owed, nil
+}
+
+// autoChargeRetryDue reports the retries already made and whether the next one
+// is due at now.
+func autoChargeRetryDue(metadata datatypes.JSONMap, maxRetries int, now time.Time) (int, bool) {
+	retryCount := metadataInt(metadata["auto_charge_retry_count"])
+	if retryCount >= maxRetries {
+		return retryCount, false
+	}
+
+	raw, _ := metadata["auto_charge_attempted_at"].(string)
+	attemptedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(raw))
+	if err != nil {
+		// Without a last attempt there is nothing to back off from.
+		return retryCount, true
+	}
+
+	idx := retryCount
+	if idx >= len(autoChargeRetryBackoff) {
+		idx = len(autoChargeRetryBackoff) - 1
+	}
+	return retryCount, !now.Before(attemptedAt.Add(autoChargeRetryBackoff[idx]))
+}
+
+func metadataInt(value any) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return int(n)
+	case string:
+		n, _ := strconv.Atoi(strings.TrimSpace(v))
+		return n
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestAutoChargeRetryDue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	attempted := func(ago time.Duration) string {
+		return now.Add(-ago).Format(time.RFC3339)
+	}
+
+	cases := []struct {
+		name     string
+		metadata datatypes.JSONMap
+		count    int
+		due      bool
+	}{
+		{"first retry waits an hour", datatypes.JSONMap{"auto_charge_attempted_at": attempted(30 * time.Minute)}, 0, false},
+		{"first retry after an hour", datatypes.JSONMap{"auto_charge_attempted_at": attempted(time.Hour)}, 0, true},
+		{"second retry waits six hours", datatypes.JSONMap{"auto_charge_attempted_at": attempted(5 * time.Hour), "auto_charge_retry_count": float64(1)}, 1, false},
+		{"third retry after a day", datatypes.JSONMap{"auto_charge_attempted_at": attempted(24 * time.Hour), "auto_charge_retry_count": float64(2)}, 2, true},
+		{"max retries reached", datatypes.JSONMap{"auto_charge_attempted_at": attempted(72 * time.Hour), "auto_charge_retry_count": float64(3)}, 3, false},
+		{"missing attempt", datatypes.JSONMap{}, 0, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			count, due := autoChargeRetryDue(tc.metadata, 3, now)
+			require.Equal(t, tc.count, count)
+			require.Equal(t, tc.due, due)
+		})
+	}
+}
+
+func TestLoadAutoChargeRetryCandidatesSkipsSettledInvoices(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&invoicedomain.Invoice{}))
+
+	node, _ := snowflake.NewNode(1)
+	now := time.Now().UTC()
+	create := func(number string, status invoicedomain.InvoiceStatus, chargeStatus string, paidAt, voidedAt *time.Time) snowflake.ID {
+		inv := invoicedomain.Invoice{
+			ID:             node.Generate(),
+			OrgID:          node.Generate(),
+			BillingCycleID: node.Generate(),
+			SubscriptionID: node.Generate(),
+			CustomerID:     node.Generate(),
+			InvoiceNumber:  number,
+			Currency:       "USD",
+			Status:         status,
+			SubtotalAmount: 1000,
+			TotalAmount:    1000,
+			PaidAt:         paidAt,
+			VoidedAt:       voidedAt,
+			Metadata:       datatypes.JSONMap{"auto_charge_status": chargeStatus},
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		require.NoError(t, db.Create(&inv).Error)
+		return inv.ID
+	}
+
+	failedID := create("INV-1", invoicedomain.InvoiceStatusFinalized, "failed", nil, nil)
+	create("INV-2", invoicedomain.InvoiceStatusFinalized, "failed", &now, nil)
+	create("INV-3", invoicedomain.InvoiceStatusVoid, "failed", nil, &now)
+	create("INV-4", invoicedomain.InvoiceStatusFinalized, "succeeded", nil, nil)
+	creditedID := create("INV-5", invoicedomain.InvoiceStatusFinalized, "failed", nil, nil)
+	require.NoError(t, db.Model(&invoicedomain.Invoice{}).Where("id = ?", creditedID).
+		Updates(map[string]any{
+			"amount_paid": 400,
+			"metadata":    datatypes.JSONMap{"auto_charge_status": "failed", "amount_credited": float64(600)},
+		}).Error)
+
+	svc := &Service{db: db, log: zap.NewNop()}
+	invoices, err := svc.loadAutoChargeRetryCandidates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, invoices, 1)
+	require.Equal(t, failedID, invoices[0].ID)
+	require.Equal(t, "failed", invoices[0].Metadata["auto_charge_status"])
+}
+
+type stripePaymentMethodSvc struct {
+	paymentdomain.PaymentMethodService
+}
+
+func (stripePaymentMethodSvc) GetDefaultPaymentMethod(ctx context.Context, customerID snowflake.ID) (*paymentdomain.PaymentMethod, error) {
+	return &paymentdomain.PaymentMethod{CustomerID: customerID, Provider: "stripe", ProviderPaymentMethodID: "pm_1"}, nil
+}
+
+// TestRetryFailedAutoChargesSendsNewIdempotencyKeys verifies that each retry
+// reaches Stripe as a new request rather than replaying the declined first
+// attempt under its idempotency key.
+func TestRetryFailedAutoChargesSendsNewIdempotencyKeys(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&invoicedomain.Invoice{}))
+
+	var keys []string
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"error":{"message":"card_declined"}}`))
+	}))
+	defer provider.Close()
+
+	svc := &Service{
+		db:                 db,
+		log:                zap.NewNop(),
+		paymentMethodSvc:   stripePaymentMethodSvc{},
+		paymentProviderSvc: staticProviderConfigSvc{config: `{"api_key":"sk_test","base_url":"` + provider.URL + `"}`},
+	}
+
+	node, _ := snowflake.NewNode(1)
+	now := time.Now().UTC()
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          node.Generate(),
+		CustomerID:     node.Generate(),
+		InvoiceNumber:  "INV-1",
+		Currency:       "USD",
+		Status:         invoicedomain.InvoiceStatusFinalized,
+		SubtotalAmount: 1000,
+		TotalAmount:    1000,
+		Metadata:       datatypes.JSONMap{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+	ctx := context.Background()
+
+	require.Error(t, svc.autoChargeInvoice(ctx, &invoice))
+	for i := 0; i < 2; i++ {
+		// Move the failed attempt past its backoff.
+		require.NoError(t, svc.mergeInvoiceMetadata(ctx, invoice.OrgID, invoice.ID, map[string]any{
+			"auto_charge_attempted_at": now.Add(-48 * time.Hour).Format(time.RFC3339),
+		}))
+		retried, err := svc.RetryFailedAutoCharges(ctx, 3, 10)
+		require.NoError(t, err)
+		require.Equal(t, 1, retried)
+	}
+
+	require.Equal(t, []string{
+		"auto_charge:" + invoice.ID.String(),
+		"auto_charge:" + invoice.ID.String() + ":retry:1",
+		"auto_charge:" + invoice.ID.String() + ":retry:2",
+	}, keys)
+}
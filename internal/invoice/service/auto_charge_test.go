@@ -2,6 +2,12 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,9 +30,95 @@ func TestBuildXenditExternalID(t *testing.T) {
 	require.Equal(t, "", buildXenditExternalID(0, invoiceID))
 }
 
-func TestAmountToMajor(t *testing.T) {
-	require.Equal(t, 123.45, amountToMajor(12345, "USD"))
-	require.Equal(t, 12345.0, amountToMajor(12345, "IDR"))
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestStripeAutoChargeSendsStripeMinorUnits(t *testing.T) {
+	var sent url.Values
+	client := newStripeAutoChargeClient("sk_test", "", "")
+	client.client = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		sent, _ = url.ParseQuery(string(body))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"id":"pi_1","status":"succeeded"}`)),
+			Header:     http.Header{},
+		}, nil
+	})}
+
+	node, _ := snowflake.NewNode(1)
+	cases := []struct {
+		currency string
+		amount   int64
+		want     string
+	}{
+		{currency: "JPY", amount: 1500, want: "1500"},
+		{currency: "USD", amount: 1999, want: "1999"},
+		// We store whole rupiah but Stripe counts IDR in hundredths.
+		{currency: "IDR", amount: 150000, want: "15000000"},
+	}
+	for _, tc := range cases {
+		invoice := &invoicedomain.Invoice{ID: node.Generate(), Currency: tc.currency}
+		_, err := client.createAndConfirmPaymentIntent(context.Background(), invoice, tc.amount, "pm_1", "", "key")
+		require.NoError(t, err)
+		require.Equal(t, tc.want, sent.Get("amount"), tc.currency)
+	}
+}
+
+func TestAutoChargeCollectsRemainingBalance(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&invoicedomain.Invoice{}))
+
+	var charged []float64
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Amount float64 `json:"amount"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		charged = append(charged, payload.Amount)
+		_, _ = w.Write([]byte(`{"id":"xinv_1","status":"PENDING"}`))
+	}))
+	defer provider.Close()
+
+	svc := &Service{
+		db:                 db,
+		log:                zap.NewNop(),
+		paymentMethodSvc:   xenditPaymentMethodSvc{},
+		paymentProviderSvc: staticProviderConfigSvc{config: `{"api_key":"xnd_test","base_url":"` + provider.URL + `"}`},
+	}
+
+	node, _ := snowflake.NewNode(1)
+	now := time.Now().UTC()
+	newInvoice := func(amountPaid int64) *invoicedomain.Invoice {
+		invoice := invoicedomain.Invoice{
+			ID:             node.Generate(),
+			OrgID:          node.Generate(),
+			BillingCycleID: node.Generate(),
+			CustomerID:     node.Generate(),
+			InvoiceNumber:  node.Generate().String(),
+			Currency:       "USD",
+			Status:         invoicedomain.InvoiceStatusFinalized,
+			SubtotalAmount: 10000,
+			TotalAmount:    10000,
+			AmountPaid:     amountPaid,
+			Metadata:       datatypes.JSONMap{},
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		require.NoError(t, db.Create(&invoice).Error)
+		return &invoice
+	}
+
+	require.NoError(t, svc.autoChargeInvoice(context.Background(), newInvoice(4000)))
+	require.Equal(t, []float64{60}, charged)
+
+	// Installments already cover the total, so there is nothing to charge.
+	require.NoError(t, svc.autoChargeInvoice(context.Background(), newInvoice(10000)))
+	require.Len(t, charged, 1)
 }
 
 func TestMergeInvoiceMetadataPreservesExisting(t *testing.T) {
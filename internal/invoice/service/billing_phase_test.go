@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TestBillingPhase_OpeningAndClosingInvoiceItems verifies that the opening
+// invoice only carries advance charges and the closing invoice only carries
+// arrears usage for the same billing cycle.
+func TestBillingPhase_OpeningAndClosingInvoiceItems(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&ratingdomain.RatingResult{},
+		&invoicedomain.Invoice{},
+		&invoicedomain.InvoiceItem{},
+		&invoicedomain.SubscriptionEntitlement{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(ServiceParam{DB: db, Log: zap.NewNop(), GenID: node}).(*Service)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	meterID := node.Generate()
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	flatRating := ratingdomain.RatingResult{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		BillingCycleID: cycleID,
+		BillingPhase:   string(billingcycledomain.BillingPhaseAdvance),
+		PriceID:        node.Generate(),
+		Source:         "flat_rate",
+		Quantity:       1,
+		UnitPrice:      5000,
+		Amount:         5000,
+		Currency:       "USD",
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Checksum:       "checksum_advance",
+		CreatedAt:      start,
+	}
+	usageRating := ratingdomain.RatingResult{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		BillingCycleID: cycleID,
+		BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
+		PriceID:        node.Generate(),
+		MeterID:        &meterID,
+		Source:         "usage_events",
+		Quantity:       42,
+		UnitPrice:      10,
+		Amount:         420,
+		Currency:       "USD",
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Checksum:       "checksum_arrears",
+		CreatedAt:      end,
+	}
+	require.NoError(t, db.Create(&flatRating).Error)
+	require.NoError(t, db.Create(&usageRating).Error)
+
+	cycle := billingCycleRow{
+		ID:             cycleID,
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Status:         billingcycledomain.BillingCycleStatusOpen,
+	}
+
+	subtotal, currency, err := svc.sumRatingForPhase(context.Background(), db, cycleID, billingcycledomain.BillingPhaseAdvance)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5000), subtotal)
+	assert.Equal(t, "USD", currency)
+
+	openingID := node.Generate()
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return svc.listInvoiceItemPartsFromRating(context.Background(), tx, cycle, openingID, "USD", billingcycledomain.BillingPhaseAdvance)
+	}))
+
+	closingID := node.Generate()
+	cycle.Status = billingcycledomain.BillingCycleStatusClosed
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return svc.listInvoiceItemPartsFromRating(context.Background(), tx, cycle, closingID, "USD", billingcycledomain.BillingPhaseArrears)
+	}))
+
+	var openingItems []invoicedomain.InvoiceItem
+	require.NoError(t, db.Where("invoice_id = ?", openingID).Find(&openingItems).Error)
+	require.Len(t, openingItems, 1)
+	assert.Equal(t, flatRating.ID, *openingItems[0].RatingResultID)
+	assert.Equal(t, invoicedomain.InvoiceItemLineTypeSubscription, openingItems[0].LineType)
+	assert.Equal(t, int64(5000), openingItems[0].Amount)
+
+	var closingItems []invoicedomain.InvoiceItem
+	require.NoError(t, db.Where("invoice_id = ?", closingID).Find(&closingItems).Error)
+	require.Len(t, closingItems, 1)
+	assert.Equal(t, usageRating.ID, *closingItems[0].RatingResultID)
+	assert.Equal(t, invoicedomain.InvoiceItemLineTypeUsage, closingItems[0].LineType)
+	assert.Equal(t, int64(420), closingItems[0].Amount)
+
+	_, _, err = svc.sumRatingForPhase(context.Background(), db, node.Generate(), billingcycledomain.BillingPhaseAdvance)
+	assert.ErrorIs(t, err, invoicedomain.ErrMissingRatingResults)
+}
+
+// TestBillingPhase_ThresholdInvoicesBillOnlyNewResults verifies that each
+// threshold invoice of a cycle bills the threshold results written since the
+// previous one.
+func TestBillingPhase_ThresholdInvoicesBillOnlyNewResults(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&ratingdomain.RatingResult{},
+		&invoicedomain.Invoice{},
+		&invoicedomain.InvoiceItem{},
+		&invoicedomain.SubscriptionEntitlement{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(ServiceParam{DB: db, Log: zap.NewNop(), GenID: node}).(*Service)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	meterID := node.Generate()
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	cycle := billingCycleRow{
+		ID:             cycleID,
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Status:         billingcycledomain.BillingCycleStatusOpen,
+	}
+
+	bill := func(amount int64, billedAt time.Time) snowflake.ID {
+		require.NoError(t, db.Create(&ratingdomain.RatingResult{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			BillingCycleID: cycleID,
+			BillingPhase:   string(billingcycledomain.BillingPhaseThreshold),
+			PriceID:        node.Generate(),
+			MeterID:        &meterID,
+			Source:         ratingdomain.RatingSourceThreshold,
+			Quantity:       float64(amount) / 10,
+			UnitPrice:      10,
+			Amount:         amount,
+			Currency:       "USD",
+			PeriodStart:    start,
+			PeriodEnd:      billedAt,
+			Checksum:       "checksum_threshold_" + billedAt.Format(time.RFC3339),
+			CreatedAt:      billedAt,
+		}).Error)
+
+		subtotal, _, err := svc.sumRatingForPhase(context.Background(), db, cycleID, billingcycledomain.BillingPhaseThreshold)
+		require.NoError(t, err)
+		assert.Equal(t, amount, subtotal)
+
+		invoiceID := node.Generate()
+		require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+			inserted, err := svc.insertInvoice(context.Background(), tx, invoicedomain.Invoice{
+				ID:             invoiceID,
+				OrgID:          orgID,
+				InvoiceNumber:  invoiceID.String(),
+				BillingCycleID: cycleID,
+				BillingPhase:   string(billingcycledomain.BillingPhaseThreshold),
+				SubscriptionID: subID,
+				CustomerID:     node.Generate(),
+				Status:         invoicedomain.InvoiceStatusDraft,
+				SubtotalAmount: subtotal,
+				Currency:       "USD",
+				CreatedAt:      billedAt,
+				UpdatedAt:      billedAt,
+			})
+			require.NoError(t, err)
+			assert.True(t, inserted)
+			return svc.listInvoiceItemPartsFromRating(context.Background(), tx, cycle, invoiceID, "USD", billingcycledomain.BillingPhaseThreshold)
+		}))
+		return invoiceID
+	}
+
+	first := bill(1500, start.Add(5*24*time.Hour))
+	second := bill(1200, start.Add(9*24*time.Hour))
+
+	for _, tc := range []struct {
+		invoiceID snowflake.ID
+		amount    int64
+	}{{first, 1500}, {second, 1200}} {
+		var items []invoicedomain.InvoiceItem
+		require.NoError(t, db.Where("invoice_id = ?", tc.invoiceID).Find(&items).Error)
+		require.Len(t, items, 1)
+		assert.Equal(t, tc.amount, items[0].Amount)
+		assert.Equal(t, invoicedomain.InvoiceItemLineTypeUsage, items[0].LineType)
+	}
+
+	_, _, err = svc.sumRatingForPhase(context.Background(), db, cycleID, billingcycledomain.BillingPhaseThreshold)
+	assert.ErrorIs(t, err, invoicedomain.ErrMissingRatingResults)
+}
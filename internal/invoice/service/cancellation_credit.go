@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+// cancellationRefundReason tags the provider refunds of unused upfront
+// periods so a repeated cancellation does not refund twice.
+const cancellationRefundReason = "subscription_canceled"
+
+type upfrontInvoiceRow struct {
+	ID          snowflake.ID      `gorm:"column:id"`
+	Currency    string            `gorm:"column:currency"`
+	TotalAmount int64             `gorm:"column:total_amount"`
+	PaidAt      *time.Time        `gorm:"column:paid_at"`
+	Metadata    datatypes.JSONMap `gorm:"column:metadata"`
+	PeriodStart time.Time         `gorm:"column:period_start"`
+	PeriodEnd   time.Time         `gorm:"column:period_end"`
+}
+
+// CreditCanceledSubscription credits the unused part of the advance invoice
+// of the cycle a subscription was canceled in. With the refund preference a
+// paid invoice is refunded through its payment provider instead, and the
+// provider's refund webhook issues the credit note; when no refundable
+// payment is found, or the provider rejects the refund, the amount is
+// credited on the invoice.
+func (s *Service) CreditCanceledSubscription(ctx context.Context, subscriptionID string) error {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return invoicedomain.ErrInvalidOrganization
+	}
+	id, err := parseID(strings.TrimSpace(subscriptionID))
+	if err != nil {
+		return invoicedomain.ErrInvalidSubscription
+	}
+
+	mode, err := s.loadCancellationRefund(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if mode == organizationdomain.CancellationRefundNone || s.creditNoteSvc == nil {
+		return nil
+	}
+
+	var subscription struct {
+		Status     subscriptiondomain.SubscriptionStatus `gorm:"column:status"`
+		CanceledAt *time.Time                            `gorm:"column:canceled_at"`
+	}
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT status, canceled_at FROM subscriptions WHERE org_id = ? AND id = ?`,
+		orgID,
+		id,
+	).Scan(&subscription).Error; err != nil {
+		return err
+	}
+	if subscription.Status != subscriptiondomain.SubscriptionStatusCanceled || subscription.CanceledAt == nil {
+		return nil
+	}
+	canceledAt := subscription.CanceledAt.UTC()
+
+	var invoices []upfrontInvoiceRow
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT i.id, i.currency, i.total_amount, i.paid_at, i.metadata, bc.period_start, bc.period_end
+		 FROM invoices i
+		 JOIN billing_cycles bc ON bc.id = i.billing_cycle_id
+		 WHERE i.org_id = ? AND i.subscription_id = ?
+		   AND i.billing_phase = ? AND i.status = ?
+		   AND bc.period_start <= ? AND bc.period_end > ?
+		 ORDER BY bc.period_start DESC
+		 LIMIT 1`,
+		orgID,
+		id,
+		billingcycledomain.BillingPhaseAdvance,
+		invoicedomain.InvoiceStatusFinalized,
+		canceledAt,
+		canceledAt,
+	).Scan(&invoices).Error; err != nil {
+		return err
+	}
+	if len(invoices) == 0 {
+		return nil
+	}
+	invoice := invoices[0]
+
+	factor := billingcycledomain.ProrationFactor(canceledAt, invoice.PeriodEnd, invoice.PeriodEnd.Sub(invoice.PeriodStart).Seconds())
+	unused := int64(math.Round(float64(invoice.TotalAmount) * factor))
+	if unused <= 0 {
+		return nil
+	}
+
+	if mode == organizationdomain.CancellationRefundRefund && invoice.PaidAt != nil && s.paymentSvc != nil {
+		refunded, err := s.refundUnusedPeriod(ctx, orgID, invoice, unused)
+		if err != nil {
+			s.log.Warn("cancellation refund failed, crediting invoice instead",
+				zap.String("invoice_id", invoice.ID.String()),
+				zap.String("subscription_id", id.String()),
+				zap.Error(err),
+			)
+		} else if refunded {
+			return nil
+		}
+	}
+
+	note, err := s.creditNoteSvc.CreateForCancellation(ctx, creditnotedomain.CreateForCancellationRequest{
+		OrgID:     orgID,
+		InvoiceID: invoice.ID,
+		Amount:    unused,
+		Currency:  invoice.Currency,
+		IssuedAt:  canceledAt,
+	})
+	if err != nil {
+		return err
+	}
+	if note != nil {
+		s.log.Info("credited unused period of canceled subscription",
+			zap.String("invoice_id", invoice.ID.String()),
+			zap.String("credit_note_id", note.ID.String()),
+			zap.Int64("amount", note.Amount),
+		)
+	}
+	return nil
+}
+
+// refundUnusedPeriod refunds amount of the payment that settled invoice and
+// reports whether a refund was requested now or by an earlier cancellation.
+func (s *Service) refundUnusedPeriod(ctx context.Context, orgID snowflake.ID, invoice upfrontInvoiceRow, amount int64) (bool, error) {
+	providerEventID, _ := invoice.Metadata["last_payment_provider_event_id"].(string)
+	providerEventID = strings.TrimSpace(providerEventID)
+	if providerEventID == "" {
+		return false, nil
+	}
+
+	var payments []struct {
+		ID snowflake.ID `gorm:"column:id"`
+	}
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT id FROM payment_events
+		 WHERE org_id = ? AND provider_event_id = ? AND event_type = ?
+		 LIMIT 1`,
+		orgID,
+		providerEventID,
+		paymentdomain.EventTypePaymentSucceeded,
+	).Scan(&payments).Error; err != nil {
+		return false, err
+	}
+	if len(payments) == 0 {
+		return false, nil
+	}
+	paymentID := payments[0].ID
+
+	var requested int64
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT COUNT(1) FROM payment_refunds
+		 WHERE org_id = ? AND payment_event_id = ? AND reason = ? AND status <> ?`,
+		orgID,
+		paymentID,
+		cancellationRefundReason,
+		paymentdomain.RefundStatusFailed,
+	).Scan(&requested).Error; err != nil {
+		return false, err
+	}
+	if requested > 0 {
+		return true, nil
+	}
+
+	refund, err := s.paymentSvc.RefundPayment(ctx, paymentdomain.RefundPaymentRequest{
+		PaymentID: paymentID.String(),
+		Amount:    &amount,
+		Reason:    cancellationRefundReason,
+	})
+	if err != nil {
+		return false, err
+	}
+	s.log.Info("refunded unused period of canceled subscription",
+		zap.String("invoice_id", invoice.ID.String()),
+		zap.String("refund_id", refund.ID.String()),
+		zap.Int64("amount", refund.Amount),
+	)
+	return true, nil
+}
+
+func (s *Service) loadCancellationRefund(ctx context.Context, orgID snowflake.ID) (organizationdomain.CancellationRefund, error) {
+	var rows []struct {
+		CancellationRefund organizationdomain.CancellationRefund `gorm:"column:cancellation_refund"`
+	}
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT cancellation_refund FROM organization_billing_preferences WHERE org_id = ? LIMIT 1`,
+		orgID,
+	).Scan(&rows).Error; err != nil {
+		return "", err
+	}
+	if len(rows) == 0 || !rows[0].CancellationRefund.Valid() {
+		return organizationdomain.DefaultCancellationRefund, nil
+	}
+	return rows[0].CancellationRefund, nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// cancellationCreditRecorder records cancellation credits; other credit
+// note calls are not expected.
+type cancellationCreditRecorder struct {
+	creditnotedomain.Service
+	requests []creditnotedomain.CreateForCancellationRequest
+}
+
+func (r *cancellationCreditRecorder) CreateForCancellation(ctx context.Context, req creditnotedomain.CreateForCancellationRequest) (*creditnotedomain.CreditNote, error) {
+	r.requests = append(r.requests, req)
+	return &creditnotedomain.CreditNote{ID: 1, InvoiceID: req.InvoiceID, Amount: req.Amount, Currency: req.Currency}, nil
+}
+
+// refundRecorder records refunds; other payment calls are not expected.
+type refundRecorder struct {
+	paymentdomain.Service
+	requests []paymentdomain.RefundPaymentRequest
+}
+
+func (r *refundRecorder) RefundPayment(ctx context.Context, req paymentdomain.RefundPaymentRequest) (*paymentdomain.Refund, error) {
+	r.requests = append(r.requests, req)
+	return &paymentdomain.Refund{ID: 1, Amount: *req.Amount}, nil
+}
+
+func TestCreditCanceledSubscription_CreditsOrRefundsUnusedPeriod(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&organizationdomain.OrganizationBillingPreferences{},
+		&subscriptiondomain.Subscription{},
+		&billingcycledomain.BillingCycle{},
+		&invoicedomain.Invoice{},
+		&paymentdomain.EventRecord{},
+		&paymentdomain.Refund{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	credits := &cancellationCreditRecorder{}
+	refunds := &refundRecorder{}
+	svc := NewService(ServiceParam{
+		DB:            db,
+		Log:           zap.NewNop(),
+		GenID:         node,
+		CreditNoteSvc: credits,
+		PaymentSvc:    refunds,
+	}).(*Service)
+
+	orgID := node.Generate()
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	periodStart := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	canceledAt := time.Date(2026, 4, 21, 0, 0, 0, 0, time.UTC)
+
+	subscription := subscriptiondomain.Subscription{
+		ID:               node.Generate(),
+		OrgID:            orgID,
+		CustomerID:       node.Generate(),
+		Status:           subscriptiondomain.SubscriptionStatusCanceled,
+		CollectionMode:   subscriptiondomain.SubscriptionCollectionModeChargeAutomatically,
+		StartAt:          periodStart,
+		CanceledAt:       &canceledAt,
+		BillingCycleType: "MONTHLY",
+		Metadata:         datatypes.JSONMap{},
+	}
+	require.NoError(t, db.Create(&subscription).Error)
+	cycle := billingcycledomain.BillingCycle{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subscription.ID,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		Status:         billingcycledomain.BillingCycleStatusOpen,
+		Metadata:       datatypes.JSONMap{},
+	}
+	require.NoError(t, db.Create(&cycle).Error)
+	paidAt := periodStart
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		InvoiceNumber:  "INV-1",
+		BillingCycleID: cycle.ID,
+		BillingPhase:   string(billingcycledomain.BillingPhaseAdvance),
+		SubscriptionID: subscription.ID,
+		CustomerID:     subscription.CustomerID,
+		Status:         invoicedomain.InvoiceStatusFinalized,
+		SubtotalAmount: 3000,
+		TotalAmount:    3000,
+		Currency:       "USD",
+		PaidAt:         &paidAt,
+		Metadata:       datatypes.JSONMap{"last_payment_provider_event_id": "evt_paid"},
+		CreatedAt:      periodStart,
+		UpdatedAt:      periodStart,
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+
+	// Without a preference nothing is credited.
+	require.NoError(t, svc.CreditCanceledSubscription(ctx, subscription.ID.String()))
+	assert.Empty(t, credits.requests)
+
+	require.NoError(t, db.Create(&organizationdomain.OrganizationBillingPreferences{
+		OrgID:    orgID,
+		Currency: "USD",
+		Timezone: "UTC",
+	}).Error)
+	setMode := func(mode organizationdomain.CancellationRefund) {
+		require.NoError(t, db.Model(&organizationdomain.OrganizationBillingPreferences{}).
+			Where("org_id = ?", orgID).
+			Update("cancellation_refund", mode).Error)
+	}
+
+	// Ten of thirty days were unused.
+	setMode(organizationdomain.CancellationRefundCredit)
+	require.NoError(t, svc.CreditCanceledSubscription(ctx, subscription.ID.String()))
+	require.Len(t, credits.requests, 1)
+	assert.Equal(t, invoice.ID, credits.requests[0].InvoiceID)
+	assert.Equal(t, int64(1000), credits.requests[0].Amount)
+	assert.Equal(t, canceledAt, credits.requests[0].IssuedAt)
+	assert.Empty(t, refunds.requests)
+
+	// Refunds fall back to a credit while the settling payment is unknown.
+	setMode(organizationdomain.CancellationRefundRefund)
+	require.NoError(t, svc.CreditCanceledSubscription(ctx, subscription.ID.String()))
+	assert.Len(t, credits.requests, 2)
+	assert.Empty(t, refunds.requests)
+
+	payment := paymentdomain.EventRecord{
+		ID:              node.Generate(),
+		OrgID:           orgID,
+		Provider:        "stripe",
+		ProviderEventID: "evt_paid",
+		EventType:       paymentdomain.EventTypePaymentSucceeded,
+		CustomerID:      subscription.CustomerID,
+		Payload:         datatypes.JSON(`{}`),
+		ReceivedAt:      periodStart,
+	}
+	require.NoError(t, db.Create(&payment).Error)
+	require.NoError(t, svc.CreditCanceledSubscription(ctx, subscription.ID.String()))
+	require.Len(t, refunds.requests, 1)
+	assert.Equal(t, payment.ID.String(), refunds.requests[0].PaymentID)
+	assert.Equal(t, int64(1000), *refunds.requests[0].Amount)
+	assert.Equal(t, cancellationRefundReason, refunds.requests[0].Reason)
+	assert.Len(t, credits.requests, 2)
+
+	// A refund already requested for the cancellation is not requested again.
+	reason := cancellationRefundReason
+	require.NoError(t, db.Create(&paymentdomain.Refund{
+		ID:                node.Generate(),
+		OrgID:             orgID,
+		PaymentEventID:    payment.ID,
+		CustomerID:        subscription.CustomerID,
+		Provider:          "stripe",
+		ProviderPaymentID: "pi_1",
+		Amount:            1000,
+		Currency:          "USD",
+		Reason:            &reason,
+		Status:            paymentdomain.RefundStatusRequested,
+		CreatedAt:         canceledAt,
+		UpdatedAt:         canceledAt,
+	}).Error)
+	require.NoError(t, svc.CreditCanceledSubscription(ctx, subscription.ID.String()))
+	assert.Len(t, refunds.requests, 1)
+	assert.Len(t, credits.requests, 2)
+}
@@ -19,7 +19,7 @@ import (
 // Double-entry logic:
 //
 //	Debit:  Accounts Receivable (asset increases)
-//	Credit: Revenue (income increases)
+//	Credit: Revenue (income increases, subtotal net of inclusive tax)
 //	Credit: Tax Payable (liability increases, if tax > 0)
 //
 // Idempotency: The ledger service has ON CONFLICT DO NOTHING, so re-posting
@@ -66,7 +66,7 @@ func (s *Service) postInvoiceToLedger(ctx context.Context, tx *gorm.DB, invoice
 			AccountID: revenueAccount.ID,
 			Direction: ledgerdomain.LedgerEntryDirectionCredit,
 			Currency:  invoice.Currency,
-			Amount:    invoice.SubtotalAmount, // Revenue = Subtotal (before tax)
+			Amount:    invoice.TotalAmount - invoice.TaxAmount, // Revenue = Subtotal - inclusive tax
 		},
 	}
 
@@ -110,9 +110,100 @@ func (s *Service) postInvoiceToLedger(ctx context.Context, tx *gorm.DB, invoice
 	return s.postLedgerEntryDirect(ctx, tx, invoice, lines)
 }
 
+// ensureLedgerAccounts provisions the org's chart of accounts so the invoice
+// can be posted when it is finalized. A failure is only logged: the invoice is
+// already committed, and finalization reports the missing accounts itself.
+func (s *Service) ensureLedgerAccounts(ctx context.Context, orgID snowflake.ID) {
+	if s.ledgerSvc == nil {
+		return
+	}
+	if err := s.ledgerSvc.EnsureAccounts(ctx, orgID); err != nil {
+		s.log.Warn("failed to provision ledger accounts",
+			zap.String("org_id", orgID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
 // postLedgerEntryDirect posts ledger entries directly within the current transaction.
 // This ensures atomicity with invoice finalization.
 func (s *Service) postLedgerEntryDirect(ctx context.Context, tx *gorm.DB, invoice *invoicedomain.Invoice, lines []ledgerdomain.LedgerEntryLine) error {
+	entryID, err := s.insertLedgerEntry(ctx, tx, invoice.OrgID, ledgerdomain.SourceTypeBillingCycle, invoice.ID, invoice.Currency, invoice.FinalizedAt.UTC(), lines)
+	if err != nil {
+		return err
+	}
+
+	// If no entry was inserted, it already exists (idempotency)
+	if entryID == 0 {
+		s.log.Info("ledger entry already exists for invoice",
+			zap.String("invoice_id", invoice.ID.String()),
+			zap.String("org_id", invoice.OrgID.String()),
+		)
+		return nil
+	}
+
+	s.log.Info("posted invoice to ledger",
+		zap.String("invoice_id", invoice.ID.String()),
+		zap.String("ledger_entry_id", entryID.String()),
+		zap.Int64("total_amount", invoice.TotalAmount),
+	)
+
+	return nil
+}
+
+// reverseInvoiceLedger posts a compensating adjustment for the entry the
+// invoice posted at finalization, flipping every line so receivables, revenue
+// and tax return to where they were. Invoices finalized without a posting
+// have nothing to reverse.
+func (s *Service) reverseInvoiceLedger(ctx context.Context, tx *gorm.DB, invoice *invoicedomain.Invoice, occurredAt time.Time) error {
+	// Finalization posts under the invoice id, not the billing cycle's.
+	entry, err := s.loadLedgerEntryForCycle(ctx, tx, invoice.OrgID, invoice.ID)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return nil
+	}
+
+	postedLines, err := s.listLedgerEntryLines(ctx, tx, entry.ID)
+	if err != nil {
+		return err
+	}
+
+	lines := make([]ledgerdomain.LedgerEntryLine, 0, len(postedLines))
+	for _, line := range postedLines {
+		direction := ledgerdomain.LedgerEntryDirectionDebit
+		if line.Direction == ledgerdomain.LedgerEntryDirectionDebit {
+			direction = ledgerdomain.LedgerEntryDirectionCredit
+		}
+		lines = append(lines, ledgerdomain.LedgerEntryLine{
+			AccountID: line.AccountID,
+			Direction: direction,
+			Currency:  entry.Currency,
+			Amount:    line.Amount,
+		})
+	}
+	if err := ledgerdomain.ValidateBalanced(lines); err != nil {
+		return fmt.Errorf("ledger reversal not balanced: %w", err)
+	}
+
+	entryID, err := s.insertLedgerEntry(ctx, tx, invoice.OrgID, ledgerdomain.SourceTypeAdjustment, invoice.ID, entry.Currency, occurredAt, lines)
+	if err != nil {
+		return err
+	}
+	if entryID != 0 {
+		s.log.Info("reversed invoice ledger entry",
+			zap.String("invoice_id", invoice.ID.String()),
+			zap.String("ledger_entry_id", entryID.String()),
+			zap.String("reversed_entry_id", entry.ID.String()),
+		)
+	}
+	return nil
+}
+
+// insertLedgerEntry writes an entry and its lines within tx. It returns a
+// zero id when an entry for the source already exists.
+func (s *Service) insertLedgerEntry(ctx context.Context, tx *gorm.DB, orgID snowflake.ID, sourceType ledgerdomain.LedgerSourceType, sourceID snowflake.ID, currency string, occurredAt time.Time, lines []ledgerdomain.LedgerEntryLine) (snowflake.ID, error) {
 	entryID := s.genID.Generate()
 	now := time.Now().UTC()
 
@@ -123,24 +214,18 @@ func (s *Service) postLedgerEntryDirect(ctx context.Context, tx *gorm.DB, invoic
 		) VALUES (?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT (org_id, source_type, source_id) DO NOTHING`,
 		entryID,
-		invoice.OrgID,
-		string(ledgerdomain.SourceTypeBillingCycle),
-		invoice.ID,
-		invoice.Currency,
-		invoice.FinalizedAt.UTC(),
+		orgID,
+		string(sourceType),
+		sourceID,
+		currency,
+		occurredAt,
 		now,
 	)
 	if result.Error != nil {
-		return fmt.Errorf("failed to insert ledger entry: %w", result.Error)
+		return 0, fmt.Errorf("failed to insert ledger entry: %w", result.Error)
 	}
-
-	// If no rows affected, entry already exists (idempotency)
 	if result.RowsAffected == 0 {
-		s.log.Info("ledger entry already exists for invoice",
-			zap.String("invoice_id", invoice.ID.String()),
-			zap.String("org_id", invoice.OrgID.String()),
-		)
-		return nil
+		return 0, nil
 	}
 
 	// Insert ledger entry lines
@@ -157,17 +242,11 @@ func (s *Service) postLedgerEntryDirect(ctx context.Context, tx *gorm.DB, invoic
 			line.Amount,
 			now,
 		).Error; err != nil {
-			return fmt.Errorf("failed to insert ledger entry line: %w", err)
+			return 0, fmt.Errorf("failed to insert ledger entry line: %w", err)
 		}
 	}
 
-	s.log.Info("posted invoice to ledger",
-		zap.String("invoice_id", invoice.ID.String()),
-		zap.String("ledger_entry_id", entryID.String()),
-		zap.Int64("total_amount", invoice.TotalAmount),
-	)
-
-	return nil
+	return entryID, nil
 }
 
 // loadLedgerAccounts loads ledger accounts by code for the given organization.
@@ -7,14 +7,32 @@ import (
 
 	"github.com/bwmarrin/snowflake"
 	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
+	customerrepository "github.com/railzwaylabs/railzway/internal/customer/repository"
+	customerservice "github.com/railzwaylabs/railzway/internal/customer/service"
 	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
 	"github.com/railzwaylabs/railzway/internal/invoice/render"
+	templatedomain "github.com/railzwaylabs/railzway/internal/invoicetemplate/domain"
+	templaterepository "github.com/railzwaylabs/railzway/internal/invoicetemplate/repository"
 	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	ledgerservice "github.com/railzwaylabs/railzway/internal/ledger/service"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
+	disputedomain "github.com/railzwaylabs/railzway/internal/payment/dispute/domain"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	"github.com/railzwaylabs/railzway/internal/providers/email"
+	"github.com/railzwaylabs/railzway/internal/providers/pdf"
 	publicinvoicedomain "github.com/railzwaylabs/railzway/internal/publicinvoice/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
 	taxdomain "github.com/railzwaylabs/railzway/internal/tax/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -31,6 +49,14 @@ func (m *mockTaxResolver) ResolveForInvoice(ctx context.Context, orgID, customer
 	return args.Get(0).(*taxdomain.TaxDefinition), args.Error(1)
 }
 
+func (m *mockTaxResolver) CalculateForInvoice(ctx context.Context, req taxdomain.InvoiceTaxRequest) (*taxdomain.InvoiceTaxResult, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*taxdomain.InvoiceTaxResult), args.Error(1)
+}
+
 type mockRenderer struct {
 	mock.Mock
 }
@@ -58,6 +84,18 @@ func (m *mockLedgerSvc) CreateEntry(ctx context.Context, orgID snowflake.ID, sou
 	return args.Error(0)
 }
 
+func (m *mockLedgerSvc) CustomerBalance(ctx context.Context, orgID, customerID snowflake.ID, currency string, asOf time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockLedgerSvc) ListCustomerEntries(ctx context.Context, orgID, customerID snowflake.ID, currency string, from, to time.Time) ([]ledgerdomain.CustomerEntry, error) {
+	return nil, nil
+}
+
+func (m *mockLedgerSvc) EnsureAccounts(ctx context.Context, orgID snowflake.ID) error {
+	return nil
+}
+
 func TestPostInvoiceToLedger_CorrectPostings(t *testing.T) {
 	db, _ := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
 
@@ -247,9 +285,13 @@ func TestFinalizeInvoice_Idempotency_NoOp(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Call FinalizeInvoice
-	// Expectation: Return nil (Success), NO side effects.
-	err = svc.FinalizeInvoice(context.Background(), invoiceID.String())
+	// Expectation: Return the existing invoice, NO side effects.
+	finalized, err := svc.FinalizeInvoice(context.Background(), invoiceID.String())
 	assert.NoError(t, err)
+	if assert.NotNil(t, finalized) {
+		assert.Equal(t, invoiceID, finalized.ID)
+		assert.Equal(t, invoicedomain.InvoiceStatusFinalized, finalized.Status)
+	}
 
 	// Verify status didn't change (still Finalized)
 	var reloaded invoicedomain.Invoice
@@ -260,3 +302,194 @@ func TestFinalizeInvoice_Idempotency_NoOp(t *testing.T) {
 
 func float64Ptr(f float64) *float64  { return &f }
 func timePtr(t time.Time) *time.Time { return &t }
+
+func TestFinalizeInvoice_AppearsOnCustomerStatement(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&customerdomain.Customer{},
+		&subscriptiondomain.Subscription{},
+		&billingcycledomain.BillingCycle{},
+		&invoicedomain.Invoice{},
+		&invoicedomain.InvoiceItem{},
+		&templatedomain.InvoiceTemplate{},
+		&paymentdomain.EventRecord{},
+		&disputedomain.DisputeRecord{},
+		&creditnotedomain.CreditNote{},
+		&ledgerdomain.LedgerEntry{},
+		&ledgerdomain.LedgerEntryLine{},
+		&ledgerdomain.LedgerAccount{},
+	))
+	require.NoError(t, db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS ux_ledger_entries_source ON ledger_entries(org_id, source_type, source_id)").Error)
+	require.NoError(t, db.Exec("DROP INDEX IF EXISTS ux_ledger_accounts_org_type").Error)
+
+	renderer := new(mockRenderer)
+	renderer.On("RenderHTML", mock.Anything).Return("<html></html>", nil)
+	tokens := new(mockPublicTokenSvc)
+	tokens.On("EnsureForInvoice", mock.Anything, mock.Anything).Return(publicinvoicedomain.PublicInvoiceToken{}, nil)
+
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(ServiceParam{
+		DB:             db,
+		Log:            zap.NewNop(),
+		GenID:          node,
+		TemplateRepo:   templaterepository.Provide(),
+		Renderer:       renderer,
+		PublicTokenSvc: tokens,
+		EmailProvider:  &email.NoOpProvider{},
+		PDFProvider:    &pdf.NoOpProvider{},
+	})
+
+	orgID := node.Generate()
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	require.NoError(t, db.Create(&ledgerdomain.LedgerAccount{ID: node.Generate(), OrgID: orgID, Code: ledgerdomain.AccountCodeAccountsReceivable, Name: "AR", Type: ledgerdomain.Assets}).Error)
+	require.NoError(t, db.Create(&ledgerdomain.LedgerAccount{ID: node.Generate(), OrgID: orgID, Code: ledgerdomain.AccountCodeRevenueUsage, Name: "Revenue", Type: ledgerdomain.Income}).Error)
+	require.NoError(t, db.Create(&templatedomain.InvoiceTemplate{
+		ID: node.Generate(), OrgID: orgID, Name: "Default", IsDefault: true, Currency: "EUR",
+	}).Error)
+
+	terms := 30
+	customer := customerdomain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Acme", Email: "billing@acme.test", Currency: "EUR", PaymentTermsDays: &terms, Metadata: datatypes.JSONMap{}}
+	other := customerdomain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Other", Email: "billing@other.test", Currency: "EUR", PaymentTermsDays: &terms, Metadata: datatypes.JSONMap{}}
+	require.NoError(t, db.Create(&customer).Error)
+	require.NoError(t, db.Create(&other).Error)
+
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		InvoiceNumber:  "INV-7",
+		BillingCycleID: node.Generate(),
+		SubscriptionID: node.Generate(),
+		CustomerID:     customer.ID,
+		Status:         invoicedomain.InvoiceStatusDraft,
+		SubtotalAmount: 4200,
+		TotalAmount:    4200,
+		Currency:       "EUR",
+		Metadata:       datatypes.JSONMap{},
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+
+	_, err = svc.FinalizeInvoice(ctx, invoice.ID.String())
+	require.NoError(t, err)
+
+	ledger := ledgerservice.NewService(ledgerservice.Params{DB: db, Log: zap.NewNop(), GenID: node})
+	customers := customerservice.New(customerservice.Params{DB: db, Log: zap.NewNop(), GenID: node, Repo: customerrepository.Provide(), LedgerSvc: ledger})
+	from := time.Now().UTC().Add(-time.Hour)
+	to := time.Now().UTC().Add(time.Hour)
+
+	statement, err := customers.GetStatement(ctx, customerdomain.StatementRequest{CustomerID: customer.ID.String(), From: &from, To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4200), statement.TotalDebits)
+	assert.Equal(t, int64(4200), statement.ClosingBalance)
+	require.Len(t, statement.Lines, 1)
+	assert.Equal(t, "invoice", statement.Lines[0].Type)
+	require.NotNil(t, statement.Lines[0].InvoiceNumber)
+	assert.Equal(t, "INV-7", *statement.Lines[0].InvoiceNumber)
+
+	// The invoice stays off other customers' statements.
+	statement, err = customers.GetStatement(ctx, customerdomain.StatementRequest{CustomerID: other.ID.String(), From: &from, To: &to})
+	require.NoError(t, err)
+	assert.Empty(t, statement.Lines)
+	assert.Zero(t, statement.ClosingBalance)
+}
+
+// TestFinalizeInvoice_MixedInclusiveAndExclusiveTax verifies that only
+// exclusive tax is added to the total, while revenue is posted net of the
+// tax included in the subtotal.
+func TestFinalizeInvoice_MixedInclusiveAndExclusiveTax(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&customerdomain.Customer{},
+		&invoicedomain.Invoice{},
+		&invoicedomain.InvoiceItem{},
+		&invoicedomain.InvoiceTaxLine{},
+		&ratingdomain.RatingResult{},
+		&pricedomain.Price{},
+		&templatedomain.InvoiceTemplate{},
+		&ledgerdomain.LedgerEntry{},
+		&ledgerdomain.LedgerEntryLine{},
+		&ledgerdomain.LedgerAccount{},
+	))
+	require.NoError(t, db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS ux_ledger_entries_source ON ledger_entries(org_id, source_type, source_id)").Error)
+	require.NoError(t, db.Exec("DROP INDEX IF EXISTS ux_ledger_accounts_org_type").Error)
+
+	renderer := new(mockRenderer)
+	renderer.On("RenderHTML", mock.Anything).Return("<html></html>", nil)
+	tokens := new(mockPublicTokenSvc)
+	tokens.On("EnsureForInvoice", mock.Anything, mock.Anything).Return(publicinvoicedomain.PublicInvoiceToken{}, nil)
+	// A 1200 line priced with 20% VAT included next to a 1000 line taxed
+	// 10% on top.
+	taxResolver := new(mockTaxResolver)
+	taxResolver.On("CalculateForInvoice", mock.Anything, mock.Anything).Return(&taxdomain.InvoiceTaxResult{Lines: []taxdomain.TaxLine{
+		{Code: "VAT", Name: "VAT", Mode: taxdomain.TaxModeInclusive, Rate: 0.2, TaxableAmount: 1000, Amount: 200},
+		{Code: "GST", Name: "GST", Mode: taxdomain.TaxModeExclusive, Rate: 0.1, TaxableAmount: 1000, Amount: 100},
+	}}, nil)
+
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(ServiceParam{
+		DB:             db,
+		Log:            zap.NewNop(),
+		GenID:          node,
+		TemplateRepo:   templaterepository.Provide(),
+		Renderer:       renderer,
+		PublicTokenSvc: tokens,
+		TaxResolver:    taxResolver,
+		EmailProvider:  &email.NoOpProvider{},
+		PDFProvider:    &pdf.NoOpProvider{},
+	})
+
+	orgID := node.Generate()
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	accounts := map[ledgerdomain.LedgerAccountCode]snowflake.ID{}
+	for code, typ := range map[ledgerdomain.LedgerAccountCode]ledgerdomain.LedgerAccountType{
+		ledgerdomain.AccountCodeAccountsReceivable: ledgerdomain.Assets,
+		ledgerdomain.AccountCodeRevenueUsage:       ledgerdomain.Income,
+		ledgerdomain.AccountCodeTaxPayable:         ledgerdomain.Liability,
+	} {
+		accounts[code] = node.Generate()
+		require.NoError(t, db.Create(&ledgerdomain.LedgerAccount{ID: accounts[code], OrgID: orgID, Code: code, Name: string(code), Type: typ}).Error)
+	}
+	require.NoError(t, db.Create(&templatedomain.InvoiceTemplate{
+		ID: node.Generate(), OrgID: orgID, Name: "Default", IsDefault: true, Currency: "EUR",
+	}).Error)
+
+	terms := 30
+	customer := customerdomain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Acme", Email: "billing@acme.test", Currency: "EUR", PaymentTermsDays: &terms, Metadata: datatypes.JSONMap{}}
+	require.NoError(t, db.Create(&customer).Error)
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		InvoiceNumber:  "INV-8",
+		BillingCycleID: node.Generate(),
+		SubscriptionID: node.Generate(),
+		CustomerID:     customer.ID,
+		Status:         invoicedomain.InvoiceStatusDraft,
+		SubtotalAmount: 2200,
+		TotalAmount:    2200,
+		Currency:       "EUR",
+		Metadata:       datatypes.JSONMap{},
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+
+	finalized, err := svc.FinalizeInvoice(ctx, invoice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2200), finalized.SubtotalAmount)
+	assert.Equal(t, int64(300), finalized.TaxAmount)
+	assert.Equal(t, int64(2300), finalized.TotalAmount)
+
+	var lines []ledgerdomain.LedgerEntryLine
+	require.NoError(t, db.Joins("JOIN ledger_entries le ON le.id = ledger_entry_lines.ledger_entry_id").
+		Where("le.source_id = ?", invoice.ID).Find(&lines).Error)
+	posted := map[snowflake.ID]int64{}
+	for _, line := range lines {
+		amount := line.Amount
+		if line.Direction == ledgerdomain.LedgerEntryDirectionDebit {
+			amount = -amount
+		}
+		posted[line.AccountID] += amount
+	}
+	assert.Equal(t, int64(-2300), posted[accounts[ledgerdomain.AccountCodeAccountsReceivable]])
+	assert.Equal(t, int64(2000), posted[accounts[ledgerdomain.AccountCodeRevenueUsage]])
+	assert.Equal(t, int64(300), posted[accounts[ledgerdomain.AccountCodeTaxPayable]])
+}
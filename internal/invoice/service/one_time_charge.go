@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	invoiceformat "github.com/railzwaylabs/railzway/internal/invoice/format"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"gorm.io/gorm"
+)
+
+// CreateOneTimeCharge invoices the charge as a one_off invoice with no
+// billing cycle or subscription, then finalizes it. Finalization posts it to
+// the ledger and charges the customer's default payment method, if any.
+func (s *Service) CreateOneTimeCharge(ctx context.Context, req invoicedomain.CreateOneTimeChargeRequest) (*invoicedomain.Invoice, error) {
+	invoice, err := s.createOneTimeChargeDraft(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ensureLedgerAccounts(ctx, invoice.OrgID)
+	s.emitAudit(ctx, "invoice.generate", invoice, map[string]any{
+		"billing_phase": invoice.BillingPhase,
+	})
+
+	return s.FinalizeInvoice(ctx, invoice.ID.String())
+}
+
+func (s *Service) createOneTimeChargeDraft(ctx context.Context, req invoicedomain.CreateOneTimeChargeRequest) (*invoicedomain.Invoice, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, invoicedomain.ErrInvalidOrganization
+	}
+	customerID, err := parseID(strings.TrimSpace(req.CustomerID))
+	if err != nil {
+		return nil, invoicedomain.ErrInvalidCustomer
+	}
+
+	items, subtotal, err := oneTimeChargeItems(req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	var createdInvoice *invoicedomain.Invoice
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if s.orgGate != nil {
+			if err := s.orgGate.MustBeActive(ctx, orgID); err != nil {
+				return err
+			}
+		}
+
+		var customers []struct {
+			ID       snowflake.ID `gorm:"column:id"`
+			Currency string       `gorm:"column:currency"`
+		}
+		if err := tx.WithContext(ctx).Raw(
+			`SELECT id, currency FROM customers WHERE org_id = ? AND id = ?`,
+			orgID,
+			customerID,
+		).Scan(&customers).Error; err != nil {
+			return err
+		}
+		if len(customers) == 0 {
+			return customerdomain.ErrNotFound
+		}
+
+		currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+		customerCurrency := strings.ToUpper(strings.TrimSpace(customers[0].Currency))
+		switch {
+		case currency == "":
+			currency = customerCurrency
+		case customerCurrency != "" && currency != customerCurrency:
+			return invoicedomain.ErrCurrencyMismatch
+		}
+		if currency == "" {
+			return invoicedomain.ErrInvalidCurrency
+		}
+
+		if err := s.lockOrganization(ctx, tx, orgID); err != nil {
+			return err
+		}
+		invoiceNumber, err := s.nextInvoiceNumber(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		displayNumber, err := invoiceformat.FormatInvoiceNumber(invoiceformat.DefaultInvoiceNumberTemplate, now, invoiceNumber)
+		if err != nil {
+			return err
+		}
+		invoice := invoicedomain.Invoice{
+			ID:             s.genID.Generate(),
+			OrgID:          orgID,
+			InvoiceSeq:     &invoiceNumber,
+			InvoiceNumber:  displayNumber,
+			BillingPhase:   string(billingcycledomain.BillingPhaseOneOff),
+			CustomerID:     customerID,
+			Status:         invoicedomain.InvoiceStatusDraft,
+			SubtotalAmount: subtotal,
+			Currency:       currency,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if _, err := s.insertInvoice(ctx, tx, invoice); err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			item.ID = s.genID.Generate()
+			item.OrgID = invoice.OrgID
+			item.InvoiceID = invoice.ID
+			item.CreatedAt = now
+			if err := s.insertInvoiceItem(ctx, tx, item); err != nil {
+				return err
+			}
+			invoice.Items = append(invoice.Items, item)
+		}
+		createdInvoice = &invoice
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return createdInvoice, nil
+}
+
+// oneTimeChargeItems validates the charge lines and returns them as one_off
+// invoice items with their subtotal.
+func oneTimeChargeItems(lines []invoicedomain.OneTimeChargeItem) ([]invoicedomain.InvoiceItem, int64, error) {
+	if len(lines) == 0 {
+		return nil, 0, invoicedomain.ErrInvalidChargeItem
+	}
+
+	items := make([]invoicedomain.InvoiceItem, 0, len(lines))
+	var subtotal int64
+	for _, line := range lines {
+		description := strings.TrimSpace(line.Description)
+		quantity := line.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		if description == "" || quantity < 0 || math.IsNaN(quantity) || math.IsInf(quantity, 0) || line.UnitAmount <= 0 {
+			return nil, 0, invoicedomain.ErrInvalidChargeItem
+		}
+
+		amount := int64(math.Round(quantity * float64(line.UnitAmount)))
+		if amount <= 0 {
+			return nil, 0, invoicedomain.ErrInvalidChargeItem
+		}
+		subtotal += amount
+		items = append(items, invoicedomain.InvoiceItem{
+			LineType:    invoicedomain.InvoiceItemLineTypeOneOff,
+			Description: description,
+			Quantity:    quantity,
+			UnitPrice:   line.UnitAmount,
+			Amount:      amount,
+		})
+	}
+	return items, subtotal, nil
+}
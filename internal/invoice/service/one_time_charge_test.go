@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	paymentproviderdomain "github.com/railzwaylabs/railzway/internal/providers/payment/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type missingPaymentMethodSvc struct {
+	paymentdomain.PaymentMethodService
+}
+
+func (missingPaymentMethodSvc) GetDefaultPaymentMethod(ctx context.Context, customerID snowflake.ID) (*paymentdomain.PaymentMethod, error) {
+	return nil, paymentdomain.ErrPaymentMethodNotFound
+}
+
+func TestCreateOneTimeChargeDraft(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&invoicedomain.Invoice{}, &invoicedomain.InvoiceItem{}, &invoicedomain.InvoiceSequence{}))
+	require.NoError(t, db.Exec(`CREATE TABLE organizations (id BIGINT PRIMARY KEY)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE customers (id BIGINT PRIMARY KEY, org_id BIGINT, currency TEXT)`).Error)
+
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(ServiceParam{DB: db, Log: zap.NewNop(), GenID: node}).(*Service)
+
+	orgID := node.Generate()
+	customerID := node.Generate()
+	require.NoError(t, db.Exec(`INSERT INTO organizations (id) VALUES (?)`, orgID).Error)
+	require.NoError(t, db.Exec(`INSERT INTO customers (id, org_id, currency) VALUES (?, ?, ?)`, customerID, orgID, "USD").Error)
+	require.NoError(t, db.Create(&invoicedomain.InvoiceSequence{OrgID: orgID, NextNumber: 1, UpdatedAt: time.Now().UTC()}).Error)
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	req := invoicedomain.CreateOneTimeChargeRequest{
+		CustomerID: customerID.String(),
+		Items: []invoicedomain.OneTimeChargeItem{
+			{Description: "Setup fee", UnitAmount: 5000},
+			{Description: "Data migration hours", Quantity: 2.5, UnitAmount: 1000},
+		},
+	}
+	first, err := svc.createOneTimeChargeDraft(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, string(billingcycledomain.BillingPhaseOneOff), first.BillingPhase)
+	assert.Zero(t, first.BillingCycleID)
+	assert.Zero(t, first.SubscriptionID)
+	assert.Equal(t, "USD", first.Currency)
+	assert.Equal(t, int64(7500), first.SubtotalAmount)
+
+	var items []invoicedomain.InvoiceItem
+	require.NoError(t, db.Where("invoice_id = ?", first.ID).Order("id").Find(&items).Error)
+	require.Len(t, items, 2)
+	assert.Equal(t, invoicedomain.InvoiceItemLineTypeOneOff, items[0].LineType)
+	assert.Equal(t, float64(1), items[0].Quantity)
+	assert.Equal(t, int64(2500), items[1].Amount)
+
+	// A customer can be charged any number of times.
+	second, err := svc.createOneTimeChargeDraft(ctx, req)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, second.ID)
+	var count int64
+	require.NoError(t, db.Model(&invoicedomain.Invoice{}).Where("customer_id = ?", customerID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	req.Currency = "eur"
+	_, err = svc.createOneTimeChargeDraft(ctx, req)
+	assert.ErrorIs(t, err, invoicedomain.ErrCurrencyMismatch)
+
+	req.Currency = ""
+	req.CustomerID = node.Generate().String()
+	_, err = svc.createOneTimeChargeDraft(ctx, req)
+	assert.ErrorIs(t, err, customerdomain.ErrNotFound)
+
+	req.CustomerID = customerID.String()
+	req.Items = []invoicedomain.OneTimeChargeItem{{Description: "Refund", UnitAmount: -100}}
+	_, err = svc.createOneTimeChargeDraft(ctx, req)
+	assert.ErrorIs(t, err, invoicedomain.ErrInvalidChargeItem)
+}
+
+func TestAutoChargeOneOffInvoiceWithoutPaymentMethod(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&invoicedomain.Invoice{}))
+
+	node, _ := snowflake.NewNode(1)
+	now := time.Now().UTC()
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          node.Generate(),
+		BillingPhase:   string(billingcycledomain.BillingPhaseOneOff),
+		CustomerID:     node.Generate(),
+		InvoiceNumber:  "INV-1",
+		Currency:       "USD",
+		Status:         invoicedomain.InvoiceStatusFinalized,
+		SubtotalAmount: 1000,
+		TotalAmount:    1000,
+		Metadata:       datatypes.JSONMap{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+
+	svc := &Service{
+		db:                 db,
+		log:                zap.NewNop(),
+		paymentMethodSvc:   missingPaymentMethodSvc{},
+		paymentProviderSvc: struct{ paymentproviderdomain.Service }{},
+	}
+	require.NoError(t, svc.autoChargeInvoice(context.Background(), &invoice))
+
+	// Without a default payment method the invoice waits for payment rather
+	// than being recorded as a failed auto-charge.
+	var metadata datatypes.JSONMap
+	require.NoError(t, db.Raw("SELECT metadata FROM invoices WHERE id = ?", invoice.ID).Scan(&metadata).Error)
+	assert.Empty(t, metadata)
+}
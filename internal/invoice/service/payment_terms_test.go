@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestLoadPaymentTermsDays_CustomerOverridesOrganization(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&customerdomain.Customer{}, &organizationdomain.OrganizationBillingPreferences{}))
+
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(ServiceParam{DB: db, Log: zap.NewNop(), GenID: node}).(*Service)
+	ctx := context.Background()
+
+	orgID := node.Generate()
+	net60 := 60
+	withTerms := customerdomain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Acme", Email: "ap@acme.test", PaymentTermsDays: &net60, Metadata: datatypes.JSONMap{}}
+	withoutTerms := customerdomain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Globex", Email: "ap@globex.test", Metadata: datatypes.JSONMap{}}
+	require.NoError(t, db.Create(&withTerms).Error)
+	require.NoError(t, db.Create(&withoutTerms).Error)
+
+	// Without preferences the default terms apply.
+	days, err := svc.loadPaymentTermsDays(ctx, db, orgID, withoutTerms.ID)
+	require.NoError(t, err)
+	assert.Equal(t, organizationdomain.DefaultPaymentTermsDays, days)
+
+	require.NoError(t, db.Create(&organizationdomain.OrganizationBillingPreferences{
+		OrgID:            orgID,
+		Currency:         "USD",
+		Timezone:         "UTC",
+		PaymentTermsDays: 15,
+	}).Error)
+
+	days, err = svc.loadPaymentTermsDays(ctx, db, orgID, withoutTerms.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 15, days)
+
+	days, err = svc.loadPaymentTermsDays(ctx, db, orgID, withTerms.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 60, days)
+}
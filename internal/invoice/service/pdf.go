@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	templatedomain "github.com/railzwaylabs/railzway/internal/invoicetemplate/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/providers/pdf"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// invoicePDFStaleAfter is how long a PDF may stay PROCESSING before another
+// run assumes the worker died and picks it up again.
+const invoicePDFStaleAfter = 10 * time.Minute
+
+var errEmptyInvoicePDF = errors.New("empty_invoice_pdf")
+
+// RequestInvoicePDF queues PDF generation for a finalized invoice. Drafts
+// still change, so only invoices that have been finalized can be rendered.
+func (s *Service) RequestInvoicePDF(ctx context.Context, invoiceID string) (invoicedomain.InvoicePDFResponse, error) {
+	invoice, err := s.loadInvoiceForPDF(ctx, invoiceID)
+	if err != nil {
+		return invoicedomain.InvoicePDFResponse{}, err
+	}
+	if invoice.Status == invoicedomain.InvoiceStatusDraft {
+		return invoicedomain.InvoicePDFResponse{}, invoicedomain.ErrInvoiceNotFinalized
+	}
+
+	now := time.Now().UTC()
+	if err := s.db.WithContext(ctx).Exec(
+		`INSERT INTO invoice_pdfs (id, org_id, invoice_id, status, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (org_id, invoice_id) DO NOTHING`,
+		s.genID.Generate(),
+		invoice.OrgID,
+		invoice.ID,
+		invoicedomain.InvoicePDFStatusPending,
+		now,
+		now,
+	).Error; err != nil {
+		return invoicedomain.InvoicePDFResponse{}, err
+	}
+
+	// A failed generation is queued again; anything else is left alone.
+	if err := s.db.WithContext(ctx).Exec(
+		`UPDATE invoice_pdfs
+		 SET status = ?, error = NULL, started_at = NULL, completed_at = NULL, updated_at = ?
+		 WHERE org_id = ? AND invoice_id = ? AND status = ?`,
+		invoicedomain.InvoicePDFStatusPending,
+		now,
+		invoice.OrgID,
+		invoice.ID,
+		invoicedomain.InvoicePDFStatusFailed,
+	).Error; err != nil {
+		return invoicedomain.InvoicePDFResponse{}, err
+	}
+
+	record, err := s.findInvoicePDF(ctx, invoice.OrgID, invoice.ID, false)
+	if err != nil {
+		return invoicedomain.InvoicePDFResponse{}, err
+	}
+	return invoicePDFResponse(invoice, record), nil
+}
+
+// GetInvoicePDF returns the invoice PDF. Content is only set once the file is
+// ready; callers report the status otherwise.
+func (s *Service) GetInvoicePDF(ctx context.Context, invoiceID string) (invoicedomain.InvoicePDFResponse, error) {
+	invoice, err := s.loadInvoiceForPDF(ctx, invoiceID)
+	if err != nil {
+		return invoicedomain.InvoicePDFResponse{}, err
+	}
+
+	record, err := s.findInvoicePDF(ctx, invoice.OrgID, invoice.ID, true)
+	if err != nil {
+		return invoicedomain.InvoicePDFResponse{}, err
+	}
+	if record.Status == invoicedomain.InvoicePDFStatusFailed {
+		return invoicedomain.InvoicePDFResponse{}, invoicedomain.ErrInvoicePDFFailed
+	}
+
+	resp := invoicePDFResponse(invoice, record)
+	if record.Status == invoicedomain.InvoicePDFStatusReady {
+		resp.Filename = invoicePDFFilename(invoice)
+		resp.Content = record.Content
+	}
+	return resp, nil
+}
+
+// GenerateQueuedInvoicePDFs renders queued invoice PDFs, oldest first, along
+// with any left PROCESSING by a worker that stopped. A failure is recorded on
+// the PDF and does not stop the rest of the batch.
+func (s *Service) GenerateQueuedInvoicePDFs(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	staleBefore := time.Now().UTC().Add(-invoicePDFStaleAfter)
+	var records []invoicedomain.InvoicePDF
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT id, org_id, invoice_id, status, started_at
+		 FROM invoice_pdfs
+		 WHERE status = ? OR (status = ? AND started_at < ?)
+		 ORDER BY created_at ASC, id ASC
+		 LIMIT ?`,
+		invoicedomain.InvoicePDFStatusPending,
+		invoicedomain.InvoicePDFStatusProcessing,
+		staleBefore,
+		limit,
+	).Scan(&records).Error; err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	for i := range records {
+		if err := ctx.Err(); err != nil {
+			return generated, err
+		}
+		claimed, err := s.claimInvoicePDF(ctx, &records[i], staleBefore)
+		if err != nil {
+			return generated, err
+		}
+		if !claimed {
+			continue
+		}
+		generated++
+
+		if err := s.generateInvoicePDF(ctx, &records[i]); err != nil {
+			s.log.Warn("failed to generate invoice PDF",
+				zap.Error(err),
+				zap.String("invoice_id", records[i].InvoiceID.String()),
+			)
+			if markErr := s.failInvoicePDF(ctx, records[i].ID, err); markErr != nil {
+				return generated, markErr
+			}
+		}
+	}
+	return generated, nil
+}
+
+func (s *Service) claimInvoicePDF(ctx context.Context, record *invoicedomain.InvoicePDF, staleBefore time.Time) (bool, error) {
+	now := time.Now().UTC()
+	result := s.db.WithContext(ctx).Exec(
+		`UPDATE invoice_pdfs
+		 SET status = ?, started_at = ?, updated_at = ?
+		 WHERE id = ? AND (status = ? OR (status = ? AND started_at < ?))`,
+		invoicedomain.InvoicePDFStatusProcessing,
+		now,
+		now,
+		record.ID,
+		invoicedomain.InvoicePDFStatusPending,
+		invoicedomain.InvoicePDFStatusProcessing,
+		staleBefore,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *Service) generateInvoicePDF(ctx context.Context, record *invoicedomain.InvoicePDF) error {
+	invoice, err := s.invoicerepo.FindOne(ctx, &invoicedomain.Invoice{ID: record.InvoiceID, OrgID: record.OrgID})
+	if err != nil {
+		return err
+	}
+	if invoice == nil {
+		return invoicedomain.ErrInvoiceNotFound
+	}
+
+	content, tmpl, err := s.renderInvoicePDF(ctx, s.db, invoice)
+	if err != nil {
+		return err
+	}
+	var templateID *snowflake.ID
+	if tmpl != nil {
+		templateID = &tmpl.ID
+	}
+
+	now := time.Now().UTC()
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.WithContext(ctx).Exec(
+			`UPDATE invoice_pdfs
+			 SET status = ?, content = ?, invoice_template_id = ?, error = NULL, completed_at = ?, updated_at = ?
+			 WHERE id = ?`,
+			invoicedomain.InvoicePDFStatusReady,
+			content,
+			templateID,
+			now,
+			now,
+			record.ID,
+		).Error; err != nil {
+			return err
+		}
+		return tx.WithContext(ctx).Exec(
+			`UPDATE invoices SET rendered_pdf_url = ?, updated_at = ? WHERE org_id = ? AND id = ?`,
+			invoicePDFObjectRef(record.ID),
+			now,
+			record.OrgID,
+			record.InvoiceID,
+		).Error
+	})
+}
+
+func (s *Service) failInvoicePDF(ctx context.Context, id snowflake.ID, cause error) error {
+	now := time.Now().UTC()
+	return s.db.WithContext(ctx).Exec(
+		`UPDATE invoice_pdfs
+		 SET status = ?, error = ?, completed_at = ?, updated_at = ?
+		 WHERE id = ?`,
+		invoicedomain.InvoicePDFStatusFailed,
+		cause.Error(),
+		now,
+		now,
+		id,
+	).Error
+}
+
+// renderInvoicePDF renders the invoice with the template version it was
+// finalized with.
+func (s *Service) renderInvoicePDF(ctx context.Context, db *gorm.DB, invoice *invoicedomain.Invoice) ([]byte, *templatedomain.InvoiceTemplate, error) {
+	if s.pdfProvider == nil {
+		return nil, nil, errors.New("pdf_provider_not_configured")
+	}
+
+	tmpl, err := s.resolveTemplate(ctx, db, invoice.OrgID, invoice.InvoiceTemplateID, invoice.InvoiceTemplateVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := s.listInvoiceItems(ctx, db, invoice.OrgID, invoice.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	customer, err := s.loadCustomer(ctx, db, invoice.OrgID, invoice.CustomerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgName := templateValue(map[string]any(tmpl.Header), "company_name")
+	if orgName == "" {
+		if err := db.WithContext(ctx).Raw(
+			`SELECT name FROM organizations WHERE id = ?`,
+			invoice.OrgID,
+		).Scan(&orgName).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+
+	reader, err := s.pdfProvider.GenerateInvoice(ctx, buildInvoicePDFData(tmpl, invoice, customer, items, orgName))
+	if err != nil {
+		return nil, nil, err
+	}
+	if reader == nil {
+		return nil, nil, errEmptyInvoicePDF
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(content) == 0 {
+		return nil, nil, errEmptyInvoicePDF
+	}
+	return content, tmpl, nil
+}
+
+func (s *Service) loadInvoiceForPDF(ctx context.Context, invoiceID string) (*invoicedomain.Invoice, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, invoicedomain.ErrInvalidOrganization
+	}
+
+	id, err := parseID(strings.TrimSpace(invoiceID))
+	if err != nil {
+		return nil, invoicedomain.ErrInvalidInvoiceID
+	}
+
+	invoice, err := s.invoicerepo.FindOne(ctx, &invoicedomain.Invoice{ID: id, OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	if invoice == nil {
+		return nil, invoicedomain.ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+func (s *Service) findInvoicePDF(ctx context.Context, orgID, invoiceID snowflake.ID, withContent bool) (*invoicedomain.InvoicePDF, error) {
+	columns := "id, org_id, invoice_id, status, invoice_template_id, error, created_at, started_at, completed_at, updated_at"
+	if withContent {
+		columns += ", content"
+	}
+
+	var records []invoicedomain.InvoicePDF
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT `+columns+`
+		 FROM invoice_pdfs
+		 WHERE org_id = ? AND invoice_id = ?`,
+		orgID,
+		invoiceID,
+	).Scan(&records).Error; err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, invoicedomain.ErrInvoicePDFNotFound
+	}
+	return &records[0], nil
+}
+
+func invoicePDFResponse(invoice *invoicedomain.Invoice, record *invoicedomain.InvoicePDF) invoicedomain.InvoicePDFResponse {
+	resp := invoicedomain.InvoicePDFResponse{
+		InvoiceID:   invoice.ID.String(),
+		Status:      record.Status,
+		Error:       record.Error,
+		CreatedAt:   record.CreatedAt,
+		CompletedAt: record.CompletedAt,
+	}
+	if record.Status == invoicedomain.InvoicePDFStatusReady {
+		ref := invoicePDFObjectRef(record.ID)
+		resp.ObjectRef = &ref
+	}
+	return resp
+}
+
+// invoicePDFObjectRef is the reference stored in invoices.rendered_pdf_url.
+func invoicePDFObjectRef(id snowflake.ID) string {
+	return "invoice_pdfs/" + id.String()
+}
+
+func invoicePDFFilename(invoice *invoicedomain.Invoice) string {
+	number := strings.TrimSpace(invoice.InvoiceNumber)
+	if number == "" {
+		number = invoice.ID.String()
+	}
+	return fmt.Sprintf("invoice-%s.pdf", number)
+}
+
+func buildInvoicePDFData(tmpl *templatedomain.InvoiceTemplate, invoice *invoicedomain.Invoice, customer *customerRow, items []invoicedomain.InvoiceItem, orgName string) pdf.InvoiceData {
+	view := buildInvoiceView(invoice)
+	number := strings.TrimSpace(invoice.InvoiceNumber)
+	if number == "" {
+		number = view.Number
+	}
+
+	data := pdf.InvoiceData{
+		OrgName:       orgName,
+		InvoiceNumber: number,
+		IssueDate:     formatPDFDate(invoice.IssuedAt),
+		DueDate:       formatPDFDate(invoice.DueAt),
+		BillToName:    customer.Name,
+		BillToEmail:   customer.Email,
+		Subtotal:      formatMoney(invoice.SubtotalAmount, invoice.Currency),
+		Total:         formatMoney(invoice.TotalAmount, invoice.Currency),
+		TotalDue:      formatMoney(invoice.TotalAmount-invoice.AmountPaid, invoice.Currency),
+		AmountDue:     formatMoney(invoice.TotalAmount-invoice.AmountPaid, invoice.Currency),
+	}
+	if invoice.PeriodStart != nil && invoice.PeriodEnd != nil {
+		data.ServicePeriod = formatPDFDate(invoice.PeriodStart) + " – " + formatPDFDate(invoice.PeriodEnd)
+	}
+	if tmpl.ShowShipTo {
+		data.ShipToName = customer.Name
+	}
+	if tmpl.ShowPaymentDetails {
+		data.BankDetails = templateValue(map[string]any(tmpl.Footer), "notes")
+	}
+
+	data.Items = make([]pdf.InvoiceItem, 0, len(items))
+	for _, item := range items {
+		data.Items = append(data.Items, pdf.InvoiceItem{
+			Description: item.Description,
+			Qty:         int(math.Round(item.Quantity)),
+			UnitPrice:   formatMoney(item.UnitPrice, invoice.Currency),
+			Amount:      formatMoney(item.Amount, invoice.Currency),
+		})
+	}
+	return data
+}
+
+func formatPDFDate(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format("January 2, 2006")
+}
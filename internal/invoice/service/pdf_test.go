@@ -0,0 +1,152 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	templatedomain "github.com/railzwaylabs/railzway/internal/invoicetemplate/domain"
+	templaterepository "github.com/railzwaylabs/railzway/internal/invoicetemplate/repository"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/providers/pdf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type pdfProviderStub struct {
+	err  error
+	data []pdf.InvoiceData
+}
+
+func (p *pdfProviderStub) GenerateInvoice(ctx context.Context, data interface{}) (io.Reader, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	invoice := data.(pdf.InvoiceData)
+	p.data = append(p.data, invoice)
+	return bytes.NewReader([]byte("%PDF " + invoice.InvoiceNumber)), nil
+}
+
+func (p *pdfProviderStub) GenerateReceipt(ctx context.Context, data interface{}) (io.Reader, error) {
+	return nil, nil
+}
+
+func TestInvoicePDF_QueuesRendersAndRetries(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&customerdomain.Customer{},
+		&invoicedomain.Invoice{},
+		&invoicedomain.InvoiceItem{},
+		&invoicedomain.InvoicePDF{},
+		&templatedomain.InvoiceTemplate{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	provider := &pdfProviderStub{err: errors.New("renderer down")}
+	svc := NewService(ServiceParam{
+		DB:           db,
+		Log:          zap.NewNop(),
+		GenID:        node,
+		TemplateRepo: templaterepository.Provide(),
+		PDFProvider:  provider,
+	})
+
+	orgID := node.Generate()
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	now := time.Now().UTC()
+
+	customer := customerdomain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Acme", Email: "billing@acme.test", Currency: "USD", Metadata: datatypes.JSONMap{}}
+	require.NoError(t, db.Create(&customer).Error)
+	require.NoError(t, db.Create(&templatedomain.InvoiceTemplate{
+		ID: node.Generate(), OrgID: orgID, Name: "Default", IsDefault: true, Currency: "USD",
+		Header: datatypes.JSONMap{"company_name": "Railzway Inc"},
+	}).Error)
+
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		InvoiceNumber:  "INV-42",
+		BillingCycleID: node.Generate(),
+		SubscriptionID: node.Generate(),
+		CustomerID:     customer.ID,
+		Status:         invoicedomain.InvoiceStatusDraft,
+		SubtotalAmount: 1500,
+		TotalAmount:    1500,
+		Currency:       "USD",
+		IssuedAt:       &now,
+		Metadata:       datatypes.JSONMap{},
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+	require.NoError(t, db.Create(&invoicedomain.InvoiceItem{
+		ID: node.Generate(), OrgID: orgID, InvoiceID: invoice.ID,
+		Description: "Pro plan", Quantity: 1, UnitPrice: 1500, Amount: 1500, Metadata: datatypes.JSONMap{},
+	}).Error)
+
+	_, err = svc.RequestInvoicePDF(ctx, invoice.ID.String())
+	assert.ErrorIs(t, err, invoicedomain.ErrInvoiceNotFinalized)
+	_, err = svc.GetInvoicePDF(ctx, invoice.ID.String())
+	assert.ErrorIs(t, err, invoicedomain.ErrInvoicePDFNotFound)
+
+	require.NoError(t, db.Model(&invoicedomain.Invoice{}).Where("id = ?", invoice.ID).
+		Update("status", invoicedomain.InvoiceStatusFinalized).Error)
+
+	queued, err := svc.RequestInvoicePDF(ctx, invoice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, invoicedomain.InvoicePDFStatusPending, queued.Status)
+	pending, err := svc.GetInvoicePDF(ctx, invoice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, invoicedomain.InvoicePDFStatusPending, pending.Status)
+	assert.Empty(t, pending.Content)
+
+	// A failed render is recorded and queued again on the next request.
+	generated, err := svc.GenerateQueuedInvoicePDFs(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, generated)
+	_, err = svc.GetInvoicePDF(ctx, invoice.ID.String())
+	assert.ErrorIs(t, err, invoicedomain.ErrInvoicePDFFailed)
+
+	provider.err = nil
+	requeued, err := svc.RequestInvoicePDF(ctx, invoice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, invoicedomain.InvoicePDFStatusPending, requeued.Status)
+
+	generated, err = svc.GenerateQueuedInvoicePDFs(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, generated)
+	require.Len(t, provider.data, 1)
+	assert.Equal(t, "Railzway Inc", provider.data[0].OrgName)
+	assert.Equal(t, "Acme", provider.data[0].BillToName)
+	require.Len(t, provider.data[0].Items, 1)
+	assert.Equal(t, "USD 15.00", provider.data[0].Items[0].Amount)
+
+	ready, err := svc.GetInvoicePDF(ctx, invoice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, invoicedomain.InvoicePDFStatusReady, ready.Status)
+	assert.Equal(t, "%PDF INV-42", string(ready.Content))
+	assert.Equal(t, "invoice-INV-42.pdf", ready.Filename)
+	require.NotNil(t, ready.ObjectRef)
+
+	var stored invoicedomain.Invoice
+	require.NoError(t, db.First(&stored, "id = ?", invoice.ID).Error)
+	require.NotNil(t, stored.RenderedPDFURL)
+	assert.Equal(t, *ready.ObjectRef, *stored.RenderedPDFURL)
+
+	// A ready PDF is not rendered again.
+	again, err := svc.RequestInvoicePDF(ctx, invoice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, invoicedomain.InvoicePDFStatusReady, again.Status)
+	generated, err = svc.GenerateQueuedInvoicePDFs(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Zero(t, generated)
+}
@@ -76,7 +76,7 @@ func (s *Service) renderInvoiceHTML(ctx context.Context, db *gorm.DB, invoice *i
 		return "", nil, errors.New("renderer_not_configured")
 	}
 
-	tmpl, err := s.resolveTemplate(ctx, db, invoice.OrgID, invoice.InvoiceTemplateID)
+	tmpl, err := s.resolveTemplate(ctx, db, invoice.OrgID, invoice.InvoiceTemplateID, invoice.InvoiceTemplateVersion)
 	if err != nil {
 		return "", nil, err
 	}
@@ -105,7 +105,10 @@ func (s *Service) renderInvoiceHTML(ctx context.Context, db *gorm.DB, invoice *i
 	return html, tmpl, nil
 }
 
-func (s *Service) resolveTemplate(ctx context.Context, db *gorm.DB, orgID snowflake.ID, templateID *snowflake.ID) (*templatedomain.InvoiceTemplate, error) {
+// resolveTemplate returns the invoice's template, or the org default when it
+// has none. A finalized invoice records the template version it was issued
+// with; that version's content is used instead of the current one.
+func (s *Service) resolveTemplate(ctx context.Context, db *gorm.DB, orgID snowflake.ID, templateID *snowflake.ID, version *int) (*templatedomain.InvoiceTemplate, error) {
 	if s.templateRepo == nil {
 		return nil, invoicedomain.ErrInvoiceTemplateNotFound
 	}
@@ -117,6 +120,24 @@ func (s *Service) resolveTemplate(ctx context.Context, db *gorm.DB, orgID snowfl
 		if item == nil {
 			return nil, invoicedomain.ErrInvoiceTemplateNotFound
 		}
+		if version == nil || *version == item.Version {
+			return item, nil
+		}
+
+		snapshot, err := s.templateRepo.FindVersion(ctx, db, orgID, *templateID, *version)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot == nil {
+			return nil, invoicedomain.ErrInvoiceTemplateNotFound
+		}
+		item.Name = snapshot.Name
+		item.Locale = snapshot.Locale
+		item.Currency = snapshot.Currency
+		item.Header = snapshot.Header
+		item.Footer = snapshot.Footer
+		item.Style = snapshot.Style
+		item.Version = snapshot.Version
 		return item, nil
 	}
 
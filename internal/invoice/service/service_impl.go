@@ -7,14 +7,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"math"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
 	auditdomain "github.com/railzwaylabs/railzway/internal/audit/domain"
+	auditcontext "github.com/railzwaylabs/railzway/internal/auditcontext"
 	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	billingoperationsdomain "github.com/railzwaylabs/railzway/internal/billingoperations/domain"
 	"github.com/railzwaylabs/railzway/internal/bootstrap"
+	"github.com/railzwaylabs/railzway/internal/currency"
 	"github.com/railzwaylabs/railzway/internal/events"
 	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
 	invoiceformat "github.com/railzwaylabs/railzway/internal/invoice/format"
@@ -22,7 +24,10 @@ import (
 	templatedomain "github.com/railzwaylabs/railzway/internal/invoicetemplate/domain"
 	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
 	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	obsmetrics "github.com/railzwaylabs/railzway/internal/observability/metrics"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
 	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
@@ -32,12 +37,12 @@ import (
 	publicinvoicedomain "github.com/railzwaylabs/railzway/internal/publicinvoice/domain"
 	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
 	taxdomain "github.com/railzwaylabs/railzway/internal/tax/domain"
-	taxservice "github.com/railzwaylabs/railzway/internal/tax/service"
 	"github.com/railzwaylabs/railzway/pkg/db/option"
 	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 	"github.com/railzwaylabs/railzway/pkg/repository"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -116,6 +121,9 @@ type ServiceParam struct {
 	OrgGate            bootstrap.OrgGate                  `optional:"true"`
 	PaymentMethodSvc   paymentdomain.PaymentMethodService `optional:"true"`
 	PaymentProviderSvc paymentproviderdomain.Service      `optional:"true"`
+	BillingOpsSvc      billingoperationsdomain.Service    `optional:"true"`
+	PaymentSvc         paymentdomain.Service              `optional:"true"`
+	CreditNoteSvc      creditnotedomain.Service           `optional:"true"`
 }
 
 type Service struct {
@@ -136,6 +144,9 @@ type Service struct {
 	orgGate            bootstrap.OrgGate
 	paymentMethodSvc   paymentdomain.PaymentMethodService
 	paymentProviderSvc paymentproviderdomain.Service
+	billingOpsSvc      billingoperationsdomain.Service
+	paymentSvc         paymentdomain.Service
+	creditNoteSvc      creditnotedomain.Service
 }
 
 func NewService(p ServiceParam) invoicedomain.Service {
@@ -157,6 +168,9 @@ func NewService(p ServiceParam) invoicedomain.Service {
 		orgGate:            p.OrgGate,
 		paymentMethodSvc:   p.PaymentMethodSvc,
 		paymentProviderSvc: p.PaymentProviderSvc,
+		billingOpsSvc:      p.BillingOpsSvc,
+		paymentSvc:         p.PaymentSvc,
+		creditNoteSvc:      p.CreditNoteSvc,
 	}
 }
 
@@ -177,14 +191,13 @@ func (s *Service) List(ctx context.Context, req invoicedomain.ListInvoiceRequest
 		filter.InvoiceNumber = *req.InvoiceNumber
 	}
 
-	// Pagination defaults
-	limit := req.PageSize
-	if limit <= 0 {
-		limit = 100
-	} else if limit > 250 {
-		limit = 250
+	columns, err := invoicedomain.ListColumns(req.Fields)
+	if err != nil {
+		return invoicedomain.ListInvoiceResponse{}, err
 	}
 
+	limit := pagination.ClampPageSize(req.PageSize)
+
 	options := []option.QueryOption{
 		option.ApplyPagination(pagination.Pagination{
 			PageToken: req.PageToken,
@@ -196,6 +209,9 @@ func (s *Service) List(ctx context.Context, req invoicedomain.ListInvoiceRequest
 			Allow:   map[string]bool{"created_at": true},
 		}),
 	}
+	if columns != nil {
+		options = append(options, option.WithSelect(columns))
+	}
 	if req.CreatedFrom != nil {
 		options = append(options, option.ApplyOperator(option.Condition{
 			Field:    "created_at",
@@ -319,13 +335,20 @@ func (s *Service) GetByID(ctx context.Context, id string) (invoicedomain.Invoice
 	return *item, nil
 }
 
-func (s *Service) GenerateInvoice(ctx context.Context, billingCycleID string) (*invoicedomain.Invoice, error) {
+func (s *Service) GenerateInvoice(ctx context.Context, billingCycleID string) (_ *invoicedomain.Invoice, err error) {
+	start := time.Now()
+	var orgID snowflake.ID
+	var createdInvoice *invoicedomain.Invoice
+	var itemCount int
+	defer func() {
+		obsmetrics.Billing().ObserveInvoiceGeneration(s.loadOrgTier(ctx, orgID), time.Since(start), itemCount, createdInvoice != nil, err)
+	}()
+
 	cycleID, err := parseID(strings.TrimSpace(billingCycleID))
 	if err != nil {
 		return nil, invoicedomain.ErrInvalidBillingCycle
 	}
 
-	var createdInvoice *invoicedomain.Invoice
 	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		cycle, err := s.loadBillingCycleForUpdate(ctx, tx, cycleID)
 		if err != nil {
@@ -334,6 +357,7 @@ func (s *Service) GenerateInvoice(ctx context.Context, billingCycleID string) (*
 		if cycle == nil {
 			return invoicedomain.ErrBillingCycleNotFound
 		}
+		orgID = cycle.OrgID
 		if cycle.Status != billingcycledomain.BillingCycleStatusClosed {
 			return invoicedomain.ErrBillingCycleNotClosed
 		}
@@ -346,7 +370,7 @@ func (s *Service) GenerateInvoice(ctx context.Context, billingCycleID string) (*
 			}
 		}
 
-		existingID, err := s.findInvoiceByBillingCycle(ctx, tx, cycle.ID)
+		existingID, err := s.findInvoiceByBillingCycle(ctx, tx, cycle.ID, billingcycledomain.BillingPhaseArrears)
 		if err != nil {
 			return err
 		}
@@ -358,7 +382,7 @@ func (s *Service) GenerateInvoice(ctx context.Context, billingCycleID string) (*
 			return err
 		}
 
-		rating, err := s.loadRating(ctx, tx, cycle.ID)
+		rating, err := s.loadRating(ctx, tx, cycle.ID, billingcycledomain.BillingPhaseArrears)
 		if err != nil {
 			return err
 		}
@@ -403,6 +427,29 @@ func (s *Service) GenerateInvoice(ctx context.Context, billingCycleID string) (*
 			return invoicedomain.ErrMissingLedgerEntry
 		}
 
+		// Late usage re-rated after the accrual was posted is accrued as an
+		// adjustment entry for the cycle.
+		adjustment, err := s.loadLedgerEntry(ctx, tx, cycle.OrgID, ledgerdomain.SourceTypeAdjustment, cycle.ID)
+		if err != nil {
+			return err
+		}
+		if adjustment != nil {
+			adjustmentLines, err := s.listLedgerEntryLines(ctx, tx, adjustment.ID)
+			if err != nil {
+				return err
+			}
+			for _, line := range adjustmentLines {
+				if line.AccountCode == string(ledgerdomain.AccountCodeAccountsReceivable) {
+					continue
+				}
+				if line.Direction == ledgerdomain.LedgerEntryDirectionCredit {
+					subtotal += line.Amount
+				} else {
+					subtotal -= line.Amount
+				}
+			}
+		}
+
 		invoiceNumber, err := s.nextInvoiceNumber(ctx, tx, cycle.OrgID)
 		if err != nil {
 			return err
@@ -420,6 +467,7 @@ func (s *Service) GenerateInvoice(ctx context.Context, billingCycleID string) (*
 			InvoiceSeq:     &invoiceNumber,
 			InvoiceNumber:  displayNumber,
 			BillingCycleID: cycle.ID,
+			BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
 			SubscriptionID: cycle.SubscriptionID,
 			CustomerID:     subscription.CustomerID,
 			Status:         invoicedomain.InvoiceStatusDraft,
@@ -439,29 +487,244 @@ func (s *Service) GenerateInvoice(ctx context.Context, billingCycleID string) (*
 		}
 		createdInvoice = &invoice
 
-		if err := s.listInvoiceItemPartsFromRating(ctx, tx, *cycle, invoiceID, invoice.Currency); err != nil {
+		if err := s.listInvoiceItemPartsFromRating(ctx, tx, *cycle, invoiceID, invoice.Currency, billingcycledomain.BillingPhaseArrears); err != nil {
 			return err
 		}
 
+		var items int64
+		if err := tx.WithContext(ctx).Model(&invoicedomain.InvoiceItem{}).
+			Where("org_id = ? AND invoice_id = ?", cycle.OrgID, invoiceID).
+			Count(&items).Error; err != nil {
+			return err
+		}
+		itemCount = int(items)
+
 		return nil
 	})
 	if err != nil {
+		createdInvoice = nil
 		return nil, err
 	}
 
 	if createdInvoice != nil {
+		s.ensureLedgerAccounts(ctx, createdInvoice.OrgID)
 		s.emitAudit(ctx, "invoice.generate", createdInvoice, nil)
 	}
 
 	return createdInvoice, nil
 }
 
+// GenerateAdvanceInvoice creates the opening invoice of a billing cycle from
+// its advance rating results. Unlike arrears invoices, the subtotal is taken
+// from the rating results directly; revenue is posted to the ledger when the
+// invoice is finalized.
+func (s *Service) GenerateAdvanceInvoice(ctx context.Context, billingCycleID string) (*invoicedomain.Invoice, error) {
+	cycleID, err := parseID(strings.TrimSpace(billingCycleID))
+	if err != nil {
+		return nil, invoicedomain.ErrInvalidBillingCycle
+	}
+
+	var createdInvoice *invoicedomain.Invoice
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cycle, err := s.loadBillingCycleForUpdate(ctx, tx, cycleID)
+		if err != nil {
+			return err
+		}
+		if cycle == nil {
+			return invoicedomain.ErrBillingCycleNotFound
+		}
+		if cycle.Status != billingcycledomain.BillingCycleStatusOpen {
+			return invoicedomain.ErrBillingCycleNotOpen
+		}
+		if !cycle.PeriodEnd.After(cycle.PeriodStart) {
+			return invoicedomain.ErrInvalidBillingCycle
+		}
+		if s.orgGate != nil {
+			if err := s.orgGate.MustBeActive(ctx, cycle.OrgID); err != nil {
+				return err
+			}
+		}
+
+		existingID, err := s.findInvoiceByBillingCycle(ctx, tx, cycle.ID, billingcycledomain.BillingPhaseAdvance)
+		if err != nil {
+			return err
+		}
+		if existingID != 0 {
+			return nil
+		}
+
+		if err := s.lockOrganization(ctx, tx, cycle.OrgID); err != nil {
+			return err
+		}
+
+		subtotal, currency, err := s.sumRatingForPhase(ctx, tx, cycle.ID, billingcycledomain.BillingPhaseAdvance)
+		if err != nil {
+			return err
+		}
+
+		subscription, err := s.loadSubscription(ctx, tx, cycle.OrgID, cycle.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		if subscription == nil || subscription.CustomerID == 0 {
+			return invoicedomain.ErrInvalidBillingCycle
+		}
+
+		invoiceNumber, err := s.nextInvoiceNumber(ctx, tx, cycle.OrgID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		displayNumber, err := invoiceformat.FormatInvoiceNumber(invoiceformat.DefaultInvoiceNumberTemplate, now, invoiceNumber)
+		if err != nil {
+			return err
+		}
+		invoiceID := s.genID.Generate()
+		invoice := invoicedomain.Invoice{
+			ID:             invoiceID,
+			OrgID:          cycle.OrgID,
+			InvoiceSeq:     &invoiceNumber,
+			InvoiceNumber:  displayNumber,
+			BillingCycleID: cycle.ID,
+			BillingPhase:   string(billingcycledomain.BillingPhaseAdvance),
+			SubscriptionID: cycle.SubscriptionID,
+			CustomerID:     subscription.CustomerID,
+			Status:         invoicedomain.InvoiceStatusDraft,
+			SubtotalAmount: subtotal,
+			Currency:       currency,
+			PeriodStart:    &cycle.PeriodStart,
+			PeriodEnd:      &cycle.PeriodEnd,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		inserted, err := s.insertInvoice(ctx, tx, invoice)
+		if err != nil {
+			return err
+		}
+		if !inserted {
+			return nil
+		}
+		createdInvoice = &invoice
+
+		return s.listInvoiceItemPartsFromRating(ctx, tx, *cycle, invoiceID, invoice.Currency, billingcycledomain.BillingPhaseAdvance)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if createdInvoice != nil {
+		s.ensureLedgerAccounts(ctx, createdInvoice.OrgID)
+		s.emitAudit(ctx, "invoice.generate", createdInvoice, map[string]any{
+			"billing_phase": createdInvoice.BillingPhase,
+		})
+	}
+
+	return createdInvoice, nil
+}
+
+// GenerateThresholdInvoice creates an interim invoice for an open billing
+// cycle from the threshold rating results no earlier threshold invoice
+// billed. A cycle can get any number of threshold invoices; like advance
+// invoices, revenue is posted to the ledger when the invoice is finalized.
+func (s *Service) GenerateThresholdInvoice(ctx context.Context, billingCycleID string) (*invoicedomain.Invoice, error) {
+	cycleID, err := parseID(strings.TrimSpace(billingCycleID))
+	if err != nil {
+		return nil, invoicedomain.ErrInvalidBillingCycle
+	}
+
+	var createdInvoice *invoicedomain.Invoice
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cycle, err := s.loadBillingCycleForUpdate(ctx, tx, cycleID)
+		if err != nil {
+			return err
+		}
+		if cycle == nil {
+			return invoicedomain.ErrBillingCycleNotFound
+		}
+		if cycle.Status != billingcycledomain.BillingCycleStatusOpen {
+			return invoicedomain.ErrBillingCycleNotOpen
+		}
+		if !cycle.PeriodEnd.After(cycle.PeriodStart) {
+			return invoicedomain.ErrInvalidBillingCycle
+		}
+		if s.orgGate != nil {
+			if err := s.orgGate.MustBeActive(ctx, cycle.OrgID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.lockOrganization(ctx, tx, cycle.OrgID); err != nil {
+			return err
+		}
+
+		subtotal, currency, err := s.sumRatingForPhase(ctx, tx, cycle.ID, billingcycledomain.BillingPhaseThreshold)
+		if err != nil {
+			return err
+		}
+
+		subscription, err := s.loadSubscription(ctx, tx, cycle.OrgID, cycle.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		if subscription == nil || subscription.CustomerID == 0 {
+			return invoicedomain.ErrInvalidBillingCycle
+		}
+
+		invoiceNumber, err := s.nextInvoiceNumber(ctx, tx, cycle.OrgID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		displayNumber, err := invoiceformat.FormatInvoiceNumber(invoiceformat.DefaultInvoiceNumberTemplate, now, invoiceNumber)
+		if err != nil {
+			return err
+		}
+		invoiceID := s.genID.Generate()
+		invoice := invoicedomain.Invoice{
+			ID:             invoiceID,
+			OrgID:          cycle.OrgID,
+			InvoiceSeq:     &invoiceNumber,
+			InvoiceNumber:  displayNumber,
+			BillingCycleID: cycle.ID,
+			BillingPhase:   string(billingcycledomain.BillingPhaseThreshold),
+			SubscriptionID: cycle.SubscriptionID,
+			CustomerID:     subscription.CustomerID,
+			Status:         invoicedomain.InvoiceStatusDraft,
+			SubtotalAmount: subtotal,
+			Currency:       currency,
+			PeriodStart:    &cycle.PeriodStart,
+			PeriodEnd:      &cycle.PeriodEnd,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if _, err := s.insertInvoice(ctx, tx, invoice); err != nil {
+			return err
+		}
+		createdInvoice = &invoice
+
+		return s.listInvoiceItemPartsFromRating(ctx, tx, *cycle, invoiceID, invoice.Currency, billingcycledomain.BillingPhaseThreshold)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.ensureLedgerAccounts(ctx, createdInvoice.OrgID)
+	s.emitAudit(ctx, "invoice.generate", createdInvoice, map[string]any{
+		"billing_phase": createdInvoice.BillingPhase,
+	})
+
+	return createdInvoice, nil
+}
+
 func (s *Service) listInvoiceItemPartsFromRating(
 	ctx context.Context,
 	tx *gorm.DB,
 	cycle billingCycleRow,
 	invoiceID snowflake.ID,
 	expectedCurrency string,
+	phase billingcycledomain.BillingPhase,
 ) error {
 
 	// 1. Load active entitlements for the cycle
@@ -490,7 +753,7 @@ func (s *Service) listInvoiceItemPartsFromRating(
 		Source      string
 	}
 
-	if err := tx.WithContext(ctx).
+	query := tx.WithContext(ctx).
 		Table("rating_results").
 		Select(`
 		id,
@@ -504,8 +767,11 @@ func (s *Service) listInvoiceItemPartsFromRating(
 		currency,
 		source
 	`).
-		Where("billing_cycle_id = ?", cycle.ID).
-		Scan(&rows).Error; err != nil {
+		Where("billing_cycle_id = ? AND billing_phase = ?", cycle.ID, phase)
+	if phase == billingcycledomain.BillingPhaseThreshold {
+		query = query.Where(unbilledRatingResultClause)
+	}
+	if err := query.Scan(&rows).Error; err != nil {
 		return err
 	}
 
@@ -530,6 +796,18 @@ func (s *Service) listInvoiceItemPartsFromRating(
 		} else if r.MeterID == 0 {
 			description = "Subscription"
 		}
+		if r.Source == ratingdomain.RatingSourceMinimumCommitment {
+			description = "Minimum commitment"
+		}
+		if r.Source == ratingdomain.RatingSourceDiscount {
+			description = "Discount (" + r.FeatureCode + ")"
+		}
+		if r.Source == ratingdomain.RatingSourceIncluded {
+			description += " (included)"
+		}
+		if r.Source == ratingdomain.RatingSourceThreshold && r.Amount < 0 {
+			description += " (billed at threshold)"
+		}
 
 		invoiceItem := invoicedomain.InvoiceItem{
 			ID:             s.genID.Generate(),
@@ -549,6 +827,15 @@ func (s *Service) listInvoiceItemPartsFromRating(
 		if r.MeterID == 0 {
 			invoiceItem.LineType = invoicedomain.InvoiceItemLineTypeSubscription
 		}
+		if r.Source == ratingdomain.RatingSourceProration && r.Amount < 0 {
+			invoiceItem.LineType = invoicedomain.InvoiceItemLineTypeCredit
+		}
+		if r.Source == ratingdomain.RatingSourceDiscount {
+			invoiceItem.LineType = invoicedomain.InvoiceItemLineTypeCredit
+		}
+		if r.Source == ratingdomain.RatingSourceThreshold && r.Amount < 0 {
+			invoiceItem.LineType = invoicedomain.InvoiceItemLineTypeCredit
+		}
 
 		// Enrich description (e.g. usage dates, rate)
 		part := invoiceItemPart{
@@ -668,12 +955,7 @@ func (s *Service) formatInvoiceItemDescription(
 		if p.RateAmount >= 0 {
 			// High precision rate for description
 			c := strings.ToUpper(p.Currency)
-			decimals, ok := currencyDecimals[c]
-			if !ok {
-				decimals = 2
-			}
-			divisor := math.Pow(10, float64(decimals))
-			val := float64(p.RateAmount) / divisor
+			val := currency.ToMajorUnits(p.RateAmount, c)
 
 			rate := fmt.Sprintf(
 				"%s %.6f / %s",
@@ -702,13 +984,14 @@ func (s *Service) formatInvoiceItemDescription(
 	return base + "\n" + period
 }
 
-func (s *Service) FinalizeInvoice(ctx context.Context, invoiceID string) error {
+func (s *Service) FinalizeInvoice(ctx context.Context, invoiceID string) (*invoicedomain.Invoice, error) {
 	id, err := parseID(strings.TrimSpace(invoiceID))
 	if err != nil {
-		return invoicedomain.ErrInvalidInvoiceID
+		return nil, invoicedomain.ErrInvalidInvoiceID
 	}
 
 	var finalizedInvoice *invoicedomain.Invoice
+	var existingInvoice *invoicedomain.Invoice
 	var publicToken publicinvoicedomain.PublicInvoiceToken
 	var renderedChecksum string
 	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -726,6 +1009,7 @@ func (s *Service) FinalizeInvoice(ctx context.Context, invoiceID string) error {
 		}
 		if invoice.Status == invoicedomain.InvoiceStatusFinalized {
 			s.log.Info("invoice already finalized, skipping", zap.String("invoice_id", invoiceID))
+			existingInvoice = invoice
 			return nil
 		}
 		if invoice.Status != invoicedomain.InvoiceStatusDraft {
@@ -736,46 +1020,67 @@ func (s *Service) FinalizeInvoice(ctx context.Context, invoiceID string) error {
 		}
 
 		// Tax is resolved and frozen at finalize-time.
-		taxDef, err := s.taxResolver.ResolveForInvoice(ctx, invoice.OrgID, invoice.CustomerID)
-		if err != nil {
-			return err
+		var taxResult *taxdomain.InvoiceTaxResult
+		if s.taxResolver != nil {
+			taxableLines, err := s.loadTaxableLines(ctx, tx, invoice)
+			if err != nil {
+				return err
+			}
+			taxResult, err = s.taxResolver.CalculateForInvoice(ctx, taxdomain.InvoiceTaxRequest{
+				OrgID:      invoice.OrgID,
+				CustomerID: invoice.CustomerID,
+				Lines:      taxableLines,
+			})
+			if err != nil {
+				return err
+			}
 		}
 		invoice.TaxRate = nil
 		invoice.TaxCode = nil
 		invoice.TaxAmount = 0
+		// Inclusive tax is already part of the subtotal; only exclusive tax
+		// is added to the total.
+		var exclusiveTax int64
 
+		paymentTermsDays, err := s.loadPaymentTermsDays(ctx, tx, invoice.OrgID, invoice.CustomerID)
+		if err != nil {
+			return err
+		}
 		now := time.Now().UTC()
-		dueAt := now.AddDate(0, 0, 30)
-
-		if taxDef != nil {
-			switch taxDef.TaxMode {
-			case taxdomain.TaxModeExclusive:
-				invoice.TaxAmount = taxservice.ComputeTaxExclusive(invoice.SubtotalAmount, taxDef.Rate)
-			case taxdomain.TaxModeInclusive:
-				invoice.TaxAmount = taxservice.ComputeTaxInclusive(invoice.SubtotalAmount, taxDef.Rate)
-			default:
-				invoice.TaxAmount = 0
-			}
-			invoice.TaxRate = taxDef.Rate
-			invoice.TaxCode = &taxDef.Code
-
-			// SNAPSHOT: Create InvoiceTaxLine
-			taxLine := invoicedomain.InvoiceTaxLine{
-				ID:        s.genID.Generate(),
-				OrgID:     invoice.OrgID,
-				InvoiceID: invoice.ID,
-				TaxCode:   &taxDef.Code,
-				TaxName:   taxDef.Name,
-				TaxMode:   string(taxDef.TaxMode),
-				TaxRate:   *taxDef.Rate,
-				Amount:    invoice.TaxAmount,
-				CreatedAt: now,
-			}
-			if err := tx.WithContext(ctx).Create(&taxLine).Error; err != nil {
-				return err
+		dueAt := now.AddDate(0, 0, paymentTermsDays)
+
+		if taxResult != nil && len(taxResult.Lines) > 0 {
+			invoice.TaxAmount = taxResult.TotalAmount()
+			exclusiveTax = taxResult.ExclusiveAmount()
+			// Every line comes from the same definition, so the first one
+			// carries the invoice-level code and rate.
+			invoice.TaxRate = &taxResult.Lines[0].Rate
+			invoice.TaxCode = &taxResult.Lines[0].Code
+
+			// SNAPSHOT: Create InvoiceTaxLine per jurisdiction and tax mode
+			for _, line := range taxResult.Lines {
+				taxLine := invoicedomain.InvoiceTaxLine{
+					ID:            s.genID.Generate(),
+					OrgID:         invoice.OrgID,
+					InvoiceID:     invoice.ID,
+					TaxCode:       &line.Code,
+					TaxName:       line.Name,
+					TaxMode:       string(line.Mode),
+					TaxRate:       line.Rate,
+					Amount:        line.Amount,
+					TaxableAmount: line.TaxableAmount,
+					Note:          line.Note,
+					CreatedAt:     now,
+				}
+				if line.Jurisdiction != "" {
+					taxLine.Jurisdiction = &line.Jurisdiction
+				}
+				if err := tx.WithContext(ctx).Create(&taxLine).Error; err != nil {
+					return err
+				}
 			}
 		}
-		invoice.TotalAmount = invoice.SubtotalAmount + invoice.TaxAmount
+		invoice.TotalAmount = invoice.SubtotalAmount + exclusiveTax
 
 		// Snapshot rendered output at finalization so future template edits never change history.
 		invoice.Status = invoicedomain.InvoiceStatusFinalized
@@ -785,6 +1090,7 @@ func (s *Service) FinalizeInvoice(ctx context.Context, invoiceID string) error {
 		}
 		if tmpl != nil {
 			invoice.InvoiceTemplateID = &tmpl.ID
+			invoice.InvoiceTemplateVersion = &tmpl.Version
 		}
 		invoice.RenderedHTML = &renderedHTML
 		checksum := sha256.Sum256([]byte(renderedHTML))
@@ -794,15 +1100,18 @@ func (s *Service) FinalizeInvoice(ctx context.Context, invoiceID string) error {
 		invoice.IssuedAt = &now
 		invoice.FinalizedAt = &now
 
-		if err := tx.WithContext(ctx).Exec(
+		// The status guard backs up the row lock: a finalization that lost a
+		// race rolls back instead of posting the invoice a second time.
+		result := tx.WithContext(ctx).Exec(
 			`UPDATE invoices
-			 SET status = ?, finalized_at = ?, issued_at = ?, due_at = ?, invoice_template_id = ?, rendered_html = ?, rendered_pdf_url = ?, tax_rate = ?, tax_code = ?, tax_amount = ?, total_amount = ?, updated_at = ?
-			 WHERE id = ?`,
+			 SET status = ?, finalized_at = ?, issued_at = ?, due_at = ?, invoice_template_id = ?, invoice_template_version = ?, rendered_html = ?, rendered_pdf_url = ?, tax_rate = ?, tax_code = ?, tax_amount = ?, total_amount = ?, updated_at = ?
+			 WHERE id = ? AND status = ?`,
 			invoice.Status,
 			invoice.FinalizedAt,
 			invoice.IssuedAt,
 			invoice.DueAt,
 			invoice.InvoiceTemplateID,
+			invoice.InvoiceTemplateVersion,
 			invoice.RenderedHTML,
 			invoice.RenderedPDFURL,
 			invoice.TaxRate,
@@ -811,8 +1120,13 @@ func (s *Service) FinalizeInvoice(ctx context.Context, invoiceID string) error {
 			invoice.TotalAmount,
 			now,
 			id,
-		).Error; err != nil {
-			return err
+			invoicedomain.InvoiceStatusDraft,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errInvoiceFinalizedConcurrently
 		}
 		finalizedInvoice = invoice
 
@@ -843,8 +1157,14 @@ func (s *Service) FinalizeInvoice(ctx context.Context, invoiceID string) error {
 
 		return nil
 	})
+	if errors.Is(err, errInvoiceFinalizedConcurrently) {
+		return s.loadFinalizedInvoice(ctx, id)
+	}
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if existingInvoice != nil {
+		return existingInvoice, nil
 	}
 	if finalizedInvoice != nil {
 		metadata := map[string]any{
@@ -855,6 +1175,9 @@ func (s *Service) FinalizeInvoice(ctx context.Context, invoiceID string) error {
 		}
 		if finalizedInvoice.InvoiceTemplateID != nil {
 			metadata["invoice_template_id"] = finalizedInvoice.InvoiceTemplateID.String()
+			if finalizedInvoice.InvoiceTemplateVersion != nil {
+				metadata["invoice_template_version"] = *finalizedInvoice.InvoiceTemplateVersion
+			}
 		}
 		s.emitAudit(ctx, "invoice.finalize", finalizedInvoice, metadata)
 
@@ -872,7 +1195,25 @@ func (s *Service) FinalizeInvoice(ctx context.Context, invoiceID string) error {
 
 		s.triggerAutoCharge(finalizedInvoice)
 	}
-	return nil
+	return finalizedInvoice, nil
+}
+
+// errInvoiceFinalizedConcurrently rolls back a finalization another caller
+// completed first.
+var errInvoiceFinalizedConcurrently = errors.New("invoice finalized concurrently")
+
+func (s *Service) loadFinalizedInvoice(ctx context.Context, id snowflake.ID) (*invoicedomain.Invoice, error) {
+	var invoice invoicedomain.Invoice
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&invoice).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invoicedomain.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	if invoice.Status != invoicedomain.InvoiceStatusFinalized {
+		return nil, invoicedomain.ErrInvoiceNotDraft
+	}
+	return &invoice, nil
 }
 
 func (s *Service) VoidInvoice(ctx context.Context, invoiceID string, reason string) error {
@@ -898,6 +1239,14 @@ func (s *Service) VoidInvoice(ctx context.Context, invoiceID string, reason stri
 		if invoice.Status != invoicedomain.InvoiceStatusFinalized {
 			return invoicedomain.ErrInvoiceNotFinalized
 		}
+		if invoice.AmountPaid > 0 || invoice.PaidAt != nil {
+			return invoicedomain.ErrInvoicePaid
+		}
+		// The reversal undoes the whole finalize posting, which would count
+		// the credit notes already issued against the invoice twice.
+		if metadataInt(invoice.Metadata["amount_credited"]) > 0 {
+			return invoicedomain.ErrInvoiceCredited
+		}
 
 		now := time.Now().UTC()
 		if err := tx.WithContext(ctx).Exec(
@@ -913,6 +1262,10 @@ func (s *Service) VoidInvoice(ctx context.Context, invoiceID string, reason stri
 		}
 		voidedInvoice = invoice
 
+		if err := s.reverseInvoiceLedger(ctx, tx, invoice, now); err != nil {
+			return err
+		}
+
 		if s.outbox != nil {
 			if err := s.outbox.PublishTx(ctx, tx, events.Event{
 				OrgID: invoice.OrgID,
@@ -940,10 +1293,36 @@ func (s *Service) VoidInvoice(ctx context.Context, invoiceID string, reason stri
 			metadata["reason"] = reason
 		}
 		s.emitAudit(ctx, "invoice.void", voidedInvoice, metadata)
+		s.releaseVoidedAssignment(ctx, voidedInvoice)
 	}
 	return nil
 }
 
+// releaseVoidedAssignment frees the billing operations assignment on a voided
+// invoice, since there is nothing left to collect. The void has already
+// committed, so a failure is only logged.
+func (s *Service) releaseVoidedAssignment(ctx context.Context, invoice *invoicedomain.Invoice) {
+	if s.billingOpsSvc == nil {
+		return
+	}
+	releasedBy := "system"
+	if _, actorID := auditcontext.ActorFromContext(ctx); strings.TrimSpace(actorID) != "" {
+		releasedBy = strings.TrimSpace(actorID)
+	}
+	ctx = orgcontext.WithOrgID(ctx, int64(invoice.OrgID))
+	if err := s.billingOpsSvc.ReleaseAssignment(ctx, billingoperationsdomain.ReleaseAssignmentRequest{
+		EntityType: billingoperationsdomain.EntityTypeInvoice,
+		EntityID:   invoice.ID.String(),
+		Reason:     "invoice_voided",
+		ReleasedBy: releasedBy,
+	}); err != nil {
+		s.log.Warn("failed to release assignment on voided invoice",
+			zap.String("invoice_id", invoice.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
 func (s *Service) emitAudit(ctx context.Context, action string, invoice *invoicedomain.Invoice, extra map[string]any) {
 	if s.auditSvc == nil || invoice == nil {
 		return
@@ -979,13 +1358,68 @@ func (s *Service) emitAudit(ctx context.Context, action string, invoice *invoice
 	_ = s.auditSvc.AuditLog(ctx, &orgID, "", nil, action, "invoice", &targetID, metadata)
 }
 
+// loadPaymentTermsDays returns how many days after finalization the
+// customer's invoices are due: the customer's own net terms when set,
+// otherwise the organization's.
+func (s *Service) loadPaymentTermsDays(ctx context.Context, tx *gorm.DB, orgID, customerID snowflake.ID) (int, error) {
+	var customerTerms []struct {
+		PaymentTermsDays *int `gorm:"column:payment_terms_days"`
+	}
+	if err := tx.WithContext(ctx).Raw(
+		`SELECT payment_terms_days FROM customers WHERE org_id = ? AND id = ? LIMIT 1`,
+		orgID,
+		customerID,
+	).Scan(&customerTerms).Error; err != nil {
+		return 0, err
+	}
+	if len(customerTerms) > 0 && customerTerms[0].PaymentTermsDays != nil && *customerTerms[0].PaymentTermsDays >= 0 {
+		return *customerTerms[0].PaymentTermsDays, nil
+	}
+
+	var rows []struct {
+		PaymentTermsDays int `gorm:"column:payment_terms_days"`
+	}
+	if err := tx.WithContext(ctx).Raw(
+		`SELECT payment_terms_days FROM organization_billing_preferences WHERE org_id = ? LIMIT 1`,
+		orgID,
+	).Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 || rows[0].PaymentTermsDays < 0 {
+		return organizationdomain.DefaultPaymentTermsDays, nil
+	}
+	return rows[0].PaymentTermsDays, nil
+}
+
+// loadOrgTier returns the organization's tier for labeling metrics. It is
+// read outside any invoice transaction and a failed lookup only loses the
+// label.
+func (s *Service) loadOrgTier(ctx context.Context, orgID snowflake.ID) string {
+	if orgID == 0 {
+		return ""
+	}
+	var row struct {
+		Metadata datatypes.JSONMap `gorm:"column:metadata"`
+	}
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT metadata FROM organizations WHERE id = ? LIMIT 1`,
+		orgID,
+	).Scan(&row).Error; err != nil {
+		return ""
+	}
+	return organizationdomain.TierFromMetadata(row.Metadata)
+}
+
 func (s *Service) loadBillingCycleForUpdate(ctx context.Context, tx *gorm.DB, id snowflake.ID) (*billingCycleRow, error) {
 	var cycle billingCycleRow
-	err := tx.WithContext(ctx).Raw(
-		`SELECT id, org_id, subscription_id, period_start, period_end, status
+	query := `SELECT id, org_id, subscription_id, period_start, period_end, status
 		 FROM billing_cycles
-		 WHERE id = ?
-		 FOR UPDATE SKIP LOCKED`,
+		 WHERE id = ?`
+	if tx.Dialector.Name() != "sqlite" {
+		query += " FOR UPDATE SKIP LOCKED"
+	}
+	err := tx.WithContext(ctx).Raw(
+		query,
 		id,
 	).Scan(&cycle).Error
 	if err != nil {
@@ -1015,14 +1449,20 @@ func (s *Service) loadSubscription(ctx context.Context, tx *gorm.DB, orgID, subs
 	return &sub, nil
 }
 
-func (s *Service) findInvoiceByBillingCycle(ctx context.Context, tx *gorm.DB, billingCycleID snowflake.ID) (snowflake.ID, error) {
+func (s *Service) findInvoiceByBillingCycle(
+	ctx context.Context,
+	tx *gorm.DB,
+	billingCycleID snowflake.ID,
+	phase billingcycledomain.BillingPhase,
+) (snowflake.ID, error) {
 	var invoiceID snowflake.ID
 	err := tx.WithContext(ctx).Raw(
 		`SELECT id
 		 FROM invoices
-		 WHERE billing_cycle_id = ?
+		 WHERE billing_cycle_id = ? AND billing_phase = ?
 		 LIMIT 1`,
 		billingCycleID,
+		phase,
 	).Scan(&invoiceID).Error
 	if err != nil {
 		return 0, err
@@ -1031,6 +1471,10 @@ func (s *Service) findInvoiceByBillingCycle(ctx context.Context, tx *gorm.DB, bi
 }
 
 func (s *Service) loadLedgerEntryForCycle(ctx context.Context, tx *gorm.DB, orgID, billingCycleID snowflake.ID) (*ledgerEntryRow, error) {
+	return s.loadLedgerEntry(ctx, tx, orgID, ledgerdomain.SourceTypeBillingCycle, billingCycleID)
+}
+
+func (s *Service) loadLedgerEntry(ctx context.Context, tx *gorm.DB, orgID snowflake.ID, sourceType ledgerdomain.LedgerSourceType, sourceID snowflake.ID) (*ledgerEntryRow, error) {
 	var entry ledgerEntryRow
 	err := tx.WithContext(ctx).Raw(
 		`SELECT id, org_id, currency, occurred_at
@@ -1038,8 +1482,8 @@ func (s *Service) loadLedgerEntryForCycle(ctx context.Context, tx *gorm.DB, orgI
 		 WHERE org_id = ? AND source_type = ? AND source_id = ?
 		 LIMIT 1`,
 		orgID,
-		ledgerdomain.SourceTypeBillingCycle,
-		billingCycleID,
+		sourceType,
+		sourceID,
 	).Scan(&entry).Error
 	if err != nil {
 		return nil, err
@@ -1069,11 +1513,14 @@ func (s *Service) listLedgerEntryLines(ctx context.Context, tx *gorm.DB, ledgerE
 
 func (s *Service) lockOrganization(ctx context.Context, tx *gorm.DB, orgID snowflake.ID) error {
 	var id snowflake.ID
-	err := tx.WithContext(ctx).Raw(
-		`SELECT id
+	query := `SELECT id
 		 FROM organizations
-		 WHERE id = ?
-		 FOR UPDATE`,
+		 WHERE id = ?`
+	if tx.Dialector.Name() != "sqlite" {
+		query += " FOR UPDATE"
+	}
+	err := tx.WithContext(ctx).Raw(
+		query,
 		orgID,
 	).Scan(&id).Error
 	if err != nil {
@@ -1095,22 +1542,22 @@ func (s *Service) nextInvoiceNumber(
 	err := tx.WithContext(ctx).Raw(`
 		UPDATE invoice_sequences
 		SET next_number = next_number + 1,
-		    updated_at = now()
+		    updated_at = ?
 		WHERE org_id = ?
 		RETURNING next_number - 1
-	`, orgID).Scan(&next).Error
+	`, time.Now().UTC(), orgID).Scan(&next).Error
 
 	return next, err
 }
 
-func (s *Service) loadRating(ctx context.Context, tx *gorm.DB, cycleID snowflake.ID) (*ratingdomain.RatingResult, error) {
+func (s *Service) loadRating(ctx context.Context, tx *gorm.DB, cycleID snowflake.ID, phase billingcycledomain.BillingPhase) (*ratingdomain.RatingResult, error) {
 	var rating ratingdomain.RatingResult
 	err := tx.WithContext(ctx).Raw(
 		`SELECT id, org_id, subscription_id, meter_id, price_id,
 		quantity, unit_price, amount, currency, period_start, period_end
 		FROM rating_results
-		WHERE billing_cycle_id = ?
-		`, cycleID,
+		WHERE billing_cycle_id = ? AND billing_phase = ?
+		`, cycleID, phase,
 	).Scan(&rating).Error
 	if err != nil {
 		return nil, err
@@ -1119,6 +1566,46 @@ func (s *Service) loadRating(ctx context.Context, tx *gorm.DB, cycleID snowflake
 	return &rating, nil
 }
 
+// unbilledRatingResultClause keeps the rating results no invoice item was
+// created from. A cycle has several threshold invoices, each billing the
+// threshold results written since the previous one.
+const unbilledRatingResultClause = `NOT EXISTS (
+	SELECT 1 FROM invoice_items ii WHERE ii.rating_result_id = rating_results.id
+)`
+
+// sumRatingForPhase totals the rating results of one billing phase and
+// enforces a single currency across them. For the threshold phase only the
+// results not invoiced yet are totalled.
+func (s *Service) sumRatingForPhase(
+	ctx context.Context,
+	tx *gorm.DB,
+	cycleID snowflake.ID,
+	phase billingcycledomain.BillingPhase,
+) (int64, string, error) {
+	var rows []struct {
+		Currency string
+		Total    int64
+	}
+	query := `SELECT currency, COALESCE(SUM(amount), 0) AS total
+		 FROM rating_results
+		 WHERE billing_cycle_id = ? AND billing_phase = ?`
+	if phase == billingcycledomain.BillingPhaseThreshold {
+		query += " AND " + unbilledRatingResultClause
+	}
+	query += " GROUP BY currency"
+	if err := tx.WithContext(ctx).Raw(query, cycleID, phase).Scan(&rows).Error; err != nil {
+		return 0, "", err
+	}
+	switch len(rows) {
+	case 0:
+		return 0, "", invoicedomain.ErrMissingRatingResults
+	case 1:
+		return rows[0].Total, rows[0].Currency, nil
+	default:
+		return 0, "", invoicedomain.ErrCurrencyMismatch
+	}
+}
+
 func (s *Service) loadMeter(
 	ctx context.Context,
 	tx *gorm.DB,
@@ -1205,16 +1692,17 @@ func (s *Service) loadPriceAmount(
 func (s *Service) insertInvoice(ctx context.Context, tx *gorm.DB, invoice invoicedomain.Invoice) (bool, error) {
 	result := tx.WithContext(ctx).Exec(
 		`INSERT INTO invoices (
-			id, org_id, invoice_seq, invoice_number, billing_cycle_id, subscription_id, customer_id,
+			id, org_id, invoice_seq, invoice_number, billing_cycle_id, billing_phase, subscription_id, customer_id,
 			invoice_template_id, status, subtotal_amount, total_amount, currency, period_start, period_end,
 			issued_at, due_at, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT (billing_cycle_id) DO NOTHING`,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (billing_cycle_id, billing_phase) WHERE billing_phase <> 'threshold' AND billing_phase <> 'one_off' DO NOTHING`,
 		invoice.ID,
 		invoice.OrgID,
 		invoice.InvoiceSeq,
 		invoice.InvoiceNumber,
 		invoice.BillingCycleID,
+		invoice.BillingPhase,
 		invoice.SubscriptionID,
 		invoice.CustomerID,
 		invoice.InvoiceTemplateID,
@@ -1242,8 +1730,8 @@ func (s *Service) insertInvoiceItem(ctx context.Context, tx *gorm.DB, item invoi
 	return tx.WithContext(ctx).Exec(
 		`INSERT INTO invoice_items (
 			id, org_id, invoice_id, rating_result_id,
-			description, quantity, unit_price, amount, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			description, quantity, unit_price, amount, line_type, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		item.ID,
 		item.OrgID,
 		item.InvoiceID,
@@ -1252,16 +1740,51 @@ func (s *Service) insertInvoiceItem(ctx context.Context, tx *gorm.DB, item invoi
 		item.Quantity,
 		item.UnitPrice,
 		item.Amount,
+		item.LineType,
 		item.CreatedAt,
 	).Error
 }
 
+// loadTaxableLines returns the invoice items with the tax behavior of the
+// price they were rated from. Items without a price, such as discounts,
+// carry no behavior. An invoice without items is taxed on its subtotal.
+func (s *Service) loadTaxableLines(ctx context.Context, tx *gorm.DB, invoice *invoicedomain.Invoice) ([]taxdomain.TaxableLine, error) {
+	var rows []struct {
+		Amount      int64
+		TaxBehavior *string
+	}
+	if err := tx.WithContext(ctx).Raw(
+		`SELECT ii.amount, p.tax_behavior
+		 FROM invoice_items ii
+		 LEFT JOIN rating_results rr ON rr.id = ii.rating_result_id
+		 LEFT JOIN prices p ON p.id = rr.price_id
+		 WHERE ii.invoice_id = ?
+		 ORDER BY ii.id ASC`,
+		invoice.ID,
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []taxdomain.TaxableLine{{Amount: invoice.SubtotalAmount}}, nil
+	}
+
+	lines := make([]taxdomain.TaxableLine, 0, len(rows))
+	for _, row := range rows {
+		line := taxdomain.TaxableLine{Amount: row.Amount}
+		if row.TaxBehavior != nil {
+			line.TaxBehavior = pricedomain.TaxBehavior(*row.TaxBehavior)
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
 func (s *Service) loadInvoiceForUpdate(ctx context.Context, tx *gorm.DB, id snowflake.ID) (*invoicedomain.Invoice, error) {
 	var invoice invoicedomain.Invoice
 	query := `SELECT id, org_id, invoice_number, billing_cycle_id, subscription_id, customer_id,
-		        invoice_template_id, status, subtotal_amount, tax_rate, tax_code, tax_amount, total_amount, currency, period_start, period_end,
-		        issued_at, due_at, finalized_at, voided_at, rendered_html, rendered_pdf_url,
-		        created_at, updated_at
+		        invoice_template_id, invoice_template_version, status, subtotal_amount, tax_rate, tax_code, tax_amount, total_amount, amount_paid, currency, period_start, period_end,
+		        issued_at, due_at, paid_at, finalized_at, voided_at, rendered_html, rendered_pdf_url,
+		        metadata, created_at, updated_at
 		 FROM invoices
 		 WHERE id = ?`
 
@@ -1312,25 +1835,9 @@ func formatQty(v float64) string {
 	return fmt.Sprintf("%.2f", v)
 }
 
-var currencyDecimals = map[string]int{
-	"USD": 2,
-	"EUR": 2,
-	"SGD": 2,
-	"CNY": 2,
-	"IDR": 0,
-	"PHP": 2,
-	"THB": 2,
-	"MYR": 2,
-	"VND": 0,
-}
-
-func formatMoney(amount int64, currency string) string {
-	c := strings.ToUpper(currency)
-	decimals, ok := currencyDecimals[c]
-	if !ok {
-		// fallback: assume 2 decimals
-		decimals = 2
-	}
+func formatMoney(amount int64, code string) string {
+	c := strings.ToUpper(code)
+	decimals := currency.Decimals(c)
 
 	sign := ""
 	if amount < 0 {
@@ -1395,8 +1902,8 @@ func (s *Service) sendInvoiceNotification(ctx context.Context, invoice *invoiced
 		InvoiceNumber: invoice.ID.String(),
 		IssueDate:     invoice.IssuedAt.Format("January 2, 2006"),
 		DueDate:       invoice.DueAt.Format("January 2, 2006"),
-		TotalDue:      fmt.Sprintf("$%.2f", float64(invoice.TotalAmount)/100.0), // TODO: Currency formatting
-		Total:         fmt.Sprintf("$%.2f", float64(invoice.TotalAmount)/100.0),
+		TotalDue:      formatMoney(invoice.TotalAmount, invoice.Currency),
+		Total:         formatMoney(invoice.TotalAmount, invoice.Currency),
 		OrgName:       org.Name,
 		// Populate other fields as needed
 	}
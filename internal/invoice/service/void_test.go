@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingoperationsdomain "github.com/railzwaylabs/railzway/internal/billingoperations/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// releaseRecorder records assignment releases; other billing operations
+// calls are not expected.
+type releaseRecorder struct {
+	billingoperationsdomain.Service
+	released []billingoperationsdomain.ReleaseAssignmentRequest
+}
+
+func (r *releaseRecorder) ReleaseAssignment(ctx context.Context, req billingoperationsdomain.ReleaseAssignmentRequest) error {
+	r.released = append(r.released, req)
+	return nil
+}
+
+func TestVoidInvoice_ReversesLedgerAndRejectsPaid(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&invoicedomain.Invoice{},
+		&ledgerdomain.LedgerEntry{},
+		&ledgerdomain.LedgerEntryLine{},
+		&ledgerdomain.LedgerAccount{},
+	))
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS ux_ledger_entries_source ON ledger_entries(org_id, source_type, source_id)")
+	db.Exec("DROP INDEX IF EXISTS ux_ledger_accounts_org_type")
+
+	node, _ := snowflake.NewNode(1)
+	releaser := &releaseRecorder{}
+	svc := NewService(ServiceParam{
+		DB:            db,
+		Log:           zap.NewNop(),
+		GenID:         node,
+		BillingOpsSvc: releaser,
+	}).(*Service)
+
+	orgID := node.Generate()
+	accounts := map[ledgerdomain.LedgerAccountCode]ledgerdomain.LedgerAccountType{
+		ledgerdomain.AccountCodeAccountsReceivable: ledgerdomain.Assets,
+		ledgerdomain.AccountCodeRevenueUsage:       ledgerdomain.Income,
+		ledgerdomain.AccountCodeTaxPayable:         ledgerdomain.Liability,
+	}
+	for code, accountType := range accounts {
+		require.NoError(t, db.Create(&ledgerdomain.LedgerAccount{ID: node.Generate(), OrgID: orgID, Code: code, Name: string(code), Type: accountType}).Error)
+	}
+
+	finalize := func(amountPaid int64) snowflake.ID {
+		finalizedAt := time.Now().UTC().Add(-time.Hour)
+		invoice := &invoicedomain.Invoice{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			InvoiceNumber:  "INV-" + node.Generate().String(),
+			BillingCycleID: node.Generate(),
+			SubscriptionID: node.Generate(),
+			CustomerID:     node.Generate(),
+			Status:         invoicedomain.InvoiceStatusFinalized,
+			SubtotalAmount: 10000,
+			TaxAmount:      2000,
+			TotalAmount:    12000,
+			AmountPaid:     amountPaid,
+			Currency:       "USD",
+			FinalizedAt:    &finalizedAt,
+			Metadata:       datatypes.JSONMap{},
+		}
+		require.NoError(t, db.Create(invoice).Error)
+		require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+			return svc.postInvoiceToLedger(context.Background(), tx, invoice)
+		}))
+		return invoice.ID
+	}
+
+	ctx := context.Background()
+	invoiceID := finalize(0)
+	require.NoError(t, svc.VoidInvoice(ctx, invoiceID.String(), "issued twice"))
+
+	var status invoicedomain.InvoiceStatus
+	require.NoError(t, db.Raw(`SELECT status FROM invoices WHERE id = ?`, invoiceID).Scan(&status).Error)
+	assert.Equal(t, invoicedomain.InvoiceStatusVoid, status)
+
+	// The reversal nets every account the invoice touched back to zero.
+	type accountBalance struct {
+		AccountID snowflake.ID
+		Balance   int64
+	}
+	var balances []accountBalance
+	require.NoError(t, db.Raw(
+		`SELECT l.account_id, SUM(CASE l.direction WHEN 'debit' THEN l.amount ELSE -l.amount END) AS balance
+		 FROM ledger_entry_lines l
+		 JOIN ledger_entries le ON le.id = l.ledger_entry_id
+		 WHERE le.org_id = ? AND le.source_id = ?
+		 GROUP BY l.account_id`,
+		orgID, invoiceID,
+	).Scan(&balances).Error)
+	require.Len(t, balances, 3)
+	for _, balance := range balances {
+		assert.Zero(t, balance.Balance)
+	}
+
+	var reversals int64
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM ledger_entries WHERE source_type = ? AND source_id = ?`, ledgerdomain.SourceTypeAdjustment, invoiceID).Scan(&reversals).Error)
+	assert.Equal(t, int64(1), reversals)
+
+	require.Len(t, releaser.released, 1)
+	assert.Equal(t, billingoperationsdomain.EntityTypeInvoice, releaser.released[0].EntityType)
+	assert.Equal(t, invoiceID.String(), releaser.released[0].EntityID)
+	assert.Equal(t, "system", releaser.released[0].ReleasedBy)
+
+	assert.ErrorIs(t, svc.VoidInvoice(ctx, invoiceID.String(), ""), invoicedomain.ErrInvoiceNotFinalized)
+
+	paidID := finalize(5000)
+	assert.ErrorIs(t, svc.VoidInvoice(ctx, paidID.String(), ""), invoicedomain.ErrInvoicePaid)
+	require.NoError(t, db.Raw(`SELECT status FROM invoices WHERE id = ?`, paidID).Scan(&status).Error)
+	assert.Equal(t, invoicedomain.InvoiceStatusFinalized, status)
+	assert.Len(t, releaser.released, 1)
+
+	// A credit note already reversed part of the posting, so voiding the
+	// invoice would reverse that part twice.
+	creditedID := finalize(0)
+	require.NoError(t, svc.mergeInvoiceMetadata(ctx, orgID, creditedID, map[string]any{"amount_credited": 3000}))
+	assert.ErrorIs(t, svc.VoidInvoice(ctx, creditedID.String(), ""), invoicedomain.ErrInvoiceCredited)
+	require.NoError(t, db.Raw(`SELECT status FROM invoices WHERE id = ?`, creditedID).Scan(&status).Error)
+	assert.Equal(t, invoicedomain.InvoiceStatusFinalized, status)
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM ledger_entries WHERE source_type = ? AND source_id = ?`, ledgerdomain.SourceTypeAdjustment, creditedID).Scan(&reversals).Error)
+	assert.Zero(t, reversals)
+	assert.Len(t, releaser.released, 1)
+}
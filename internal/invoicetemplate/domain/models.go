@@ -29,3 +29,23 @@ type InvoiceTemplate struct {
 
 // TableName sets the database table name.
 func (InvoiceTemplate) TableName() string { return "invoice_templates" }
+
+// InvoiceTemplateVersion is a saved state of an invoice template. A new
+// version is stored every time the template content changes.
+type InvoiceTemplateVersion struct {
+	ID                snowflake.ID      `gorm:"primaryKey"`
+	OrgID             snowflake.ID      `gorm:"not null;index"`
+	InvoiceTemplateID snowflake.ID      `gorm:"not null;uniqueIndex:ux_invoice_template_versions_version,priority:1"`
+	Version           int               `gorm:"not null;uniqueIndex:ux_invoice_template_versions_version,priority:2"`
+	Name              string            `gorm:"type:text;not null"`
+	Locale            string            `gorm:"type:text;not null"`
+	Currency          string            `gorm:"type:text;not null"`
+	Header            datatypes.JSONMap `gorm:"type:jsonb"`
+	Footer            datatypes.JSONMap `gorm:"type:jsonb"`
+	Style             datatypes.JSONMap `gorm:"type:jsonb"`
+	RestoredFrom      *int              `gorm:""`
+	CreatedAt         time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName sets the database table name.
+func (InvoiceTemplateVersion) TableName() string { return "invoice_template_versions" }
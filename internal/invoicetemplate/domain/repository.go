@@ -13,4 +13,8 @@ type Repository interface {
 	FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*InvoiceTemplate, error)
 	FindDefault(ctx context.Context, db *gorm.DB, orgID snowflake.ID) (*InvoiceTemplate, error)
 	List(ctx context.Context, db *gorm.DB, orgID snowflake.ID, filter ListRequest) ([]InvoiceTemplate, error)
+
+	InsertVersion(ctx context.Context, db *gorm.DB, version *InvoiceTemplateVersion) error
+	FindVersion(ctx context.Context, db *gorm.DB, orgID, templateID snowflake.ID, version int) (*InvoiceTemplateVersion, error)
+	ListVersions(ctx context.Context, db *gorm.DB, orgID, templateID snowflake.ID) ([]InvoiceTemplateVersion, error)
 }
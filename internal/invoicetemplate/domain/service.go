@@ -44,16 +44,36 @@ type Response struct {
 	Header    map[string]any `json:"header"`
 	Footer    map[string]any `json:"footer"`
 	Style     map[string]any `json:"style"`
+	Version   int            `json:"version"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 }
 
+type VersionResponse struct {
+	TemplateID   string         `json:"invoice_template_id"`
+	Version      int            `json:"version"`
+	Name         string         `json:"name"`
+	Locale       string         `json:"locale"`
+	Currency     string         `json:"currency"`
+	Header       map[string]any `json:"header"`
+	Footer       map[string]any `json:"footer"`
+	Style        map[string]any `json:"style"`
+	RestoredFrom *int           `json:"restored_from,omitempty"`
+	IsCurrent    bool           `json:"is_current"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
 type Service interface {
 	Create(ctx context.Context, req CreateRequest) (*Response, error)
 	List(ctx context.Context, req ListRequest) ([]Response, error)
 	GetByID(ctx context.Context, id string) (*Response, error)
 	Update(ctx context.Context, req UpdateRequest) (*Response, error)
 	SetDefault(ctx context.Context, id string) (*Response, error)
+	// ListVersions returns the saved versions of a template, newest first.
+	ListVersions(ctx context.Context, id string) ([]VersionResponse, error)
+	// RestoreVersion makes the content of an earlier version current again
+	// by saving it as a new version.
+	RestoreVersion(ctx context.Context, id string, version int) (*Response, error)
 }
 
 func ParseID(raw string) (snowflake.ID, error) {
@@ -66,5 +86,7 @@ var (
 	ErrInvalidName         = errors.New("invalid_name")
 	ErrInvalidCurrency     = errors.New("invalid_currency")
 	ErrInvalidLocale       = errors.New("invalid_locale")
+	ErrInvalidVersion      = errors.New("invalid_version")
 	ErrNotFound            = errors.New("not_found")
+	ErrVersionNotFound     = errors.New("version_not_found")
 )
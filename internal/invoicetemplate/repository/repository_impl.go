@@ -17,8 +17,8 @@ func Provide() templatedomain.Repository {
 func (r *repo) Insert(ctx context.Context, db *gorm.DB, tmpl *templatedomain.InvoiceTemplate) error {
 	return db.WithContext(ctx).Exec(
 		`INSERT INTO invoice_templates (
-			id, org_id, name, is_default, locale, currency, header, footer, style, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, org_id, name, is_default, locale, currency, header, footer, style, version, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		tmpl.ID,
 		tmpl.OrgID,
 		tmpl.Name,
@@ -28,6 +28,7 @@ func (r *repo) Insert(ctx context.Context, db *gorm.DB, tmpl *templatedomain.Inv
 		tmpl.Header,
 		tmpl.Footer,
 		tmpl.Style,
+		tmpl.Version,
 		tmpl.CreatedAt,
 		tmpl.UpdatedAt,
 	).Error
@@ -36,7 +37,7 @@ func (r *repo) Insert(ctx context.Context, db *gorm.DB, tmpl *templatedomain.Inv
 func (r *repo) Update(ctx context.Context, db *gorm.DB, tmpl *templatedomain.InvoiceTemplate) error {
 	return db.WithContext(ctx).Exec(
 		`UPDATE invoice_templates
-		 SET name = ?, is_default = ?, locale = ?, currency = ?, header = ?, footer = ?, style = ?, updated_at = ?
+		 SET name = ?, is_default = ?, locale = ?, currency = ?, header = ?, footer = ?, style = ?, version = ?, updated_at = ?
 		 WHERE org_id = ? AND id = ?`,
 		tmpl.Name,
 		tmpl.IsDefault,
@@ -45,6 +46,7 @@ func (r *repo) Update(ctx context.Context, db *gorm.DB, tmpl *templatedomain.Inv
 		tmpl.Header,
 		tmpl.Footer,
 		tmpl.Style,
+		tmpl.Version,
 		tmpl.UpdatedAt,
 		tmpl.OrgID,
 		tmpl.ID,
@@ -54,7 +56,7 @@ func (r *repo) Update(ctx context.Context, db *gorm.DB, tmpl *templatedomain.Inv
 func (r *repo) FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*templatedomain.InvoiceTemplate, error) {
 	var tmpl templatedomain.InvoiceTemplate
 	err := db.WithContext(ctx).Raw(
-		`SELECT id, org_id, name, is_default, locale, currency, header, footer, style, created_at, updated_at
+		`SELECT id, org_id, name, is_default, locale, currency, header, footer, style, version, created_at, updated_at
 		 FROM invoice_templates
 		 WHERE org_id = ? AND id = ?`,
 		orgID,
@@ -72,7 +74,7 @@ func (r *repo) FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID
 func (r *repo) FindDefault(ctx context.Context, db *gorm.DB, orgID snowflake.ID) (*templatedomain.InvoiceTemplate, error) {
 	var tmpl templatedomain.InvoiceTemplate
 	err := db.WithContext(ctx).Raw(
-		`SELECT id, org_id, name, is_default, locale, currency, header, footer, style, created_at, updated_at
+		`SELECT id, org_id, name, is_default, locale, currency, header, footer, style, version, created_at, updated_at
 		 FROM invoice_templates
 		 WHERE org_id = ? AND is_default = TRUE
 		 LIMIT 1`,
@@ -105,3 +107,58 @@ func (r *repo) List(ctx context.Context, db *gorm.DB, orgID snowflake.ID, filter
 	}
 	return items, nil
 }
+
+func (r *repo) InsertVersion(ctx context.Context, db *gorm.DB, version *templatedomain.InvoiceTemplateVersion) error {
+	return db.WithContext(ctx).Exec(
+		`INSERT INTO invoice_template_versions (
+			id, org_id, invoice_template_id, version, name, locale, currency, header, footer, style, restored_from, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		version.ID,
+		version.OrgID,
+		version.InvoiceTemplateID,
+		version.Version,
+		version.Name,
+		version.Locale,
+		version.Currency,
+		version.Header,
+		version.Footer,
+		version.Style,
+		version.RestoredFrom,
+		version.CreatedAt,
+	).Error
+}
+
+func (r *repo) FindVersion(ctx context.Context, db *gorm.DB, orgID, templateID snowflake.ID, version int) (*templatedomain.InvoiceTemplateVersion, error) {
+	var item templatedomain.InvoiceTemplateVersion
+	err := db.WithContext(ctx).Raw(
+		`SELECT id, org_id, invoice_template_id, version, name, locale, currency, header, footer, style, restored_from, created_at
+		 FROM invoice_template_versions
+		 WHERE org_id = ? AND invoice_template_id = ? AND version = ?`,
+		orgID,
+		templateID,
+		version,
+	).Scan(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	if item.ID == 0 {
+		return nil, nil
+	}
+	return &item, nil
+}
+
+func (r *repo) ListVersions(ctx context.Context, db *gorm.DB, orgID, templateID snowflake.ID) ([]templatedomain.InvoiceTemplateVersion, error) {
+	var items []templatedomain.InvoiceTemplateVersion
+	err := db.WithContext(ctx).Raw(
+		`SELECT id, org_id, invoice_template_id, version, name, locale, currency, header, footer, style, restored_from, created_at
+		 FROM invoice_template_versions
+		 WHERE org_id = ? AND invoice_template_id = ?
+		 ORDER BY version DESC`,
+		orgID,
+		templateID,
+	).Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -75,6 +75,7 @@ func (s *Service) Create(ctx context.Context, req templatedomain.CreateRequest)
 		Header:    normalizeMap(req.Header),
 		Footer:    normalizeMap(req.Footer),
 		Style:     normalizeMap(req.Style),
+		Version:   1,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -85,7 +86,10 @@ func (s *Service) Create(ctx context.Context, req templatedomain.CreateRequest)
 				return err
 			}
 		}
-		return s.repo.Insert(ctx, tx, tmpl)
+		if err := s.repo.Insert(ctx, tx, tmpl); err != nil {
+			return err
+		}
+		return s.saveVersion(ctx, tx, tmpl, nil)
 	})
 	if err != nil {
 		return nil, err
@@ -151,18 +155,41 @@ func (s *Service) Update(ctx context.Context, req templatedomain.UpdateRequest)
 		return nil, templatedomain.ErrInvalidID
 	}
 
-	item, err := s.repo.FindByID(ctx, s.db, orgID, templateID)
+	var item *templatedomain.InvoiceTemplate
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		item, err = s.repo.FindByID(ctx, tx, orgID, templateID)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return templatedomain.ErrNotFound
+		}
+		if err := applyUpdate(item, req); err != nil {
+			return err
+		}
+
+		// Every edit is a new version so invoices finalized earlier keep
+		// rendering with the content they were issued with.
+		item.Version++
+		item.UpdatedAt = time.Now().UTC()
+		if err := s.repo.Update(ctx, tx, item); err != nil {
+			return err
+		}
+		return s.saveVersion(ctx, tx, item, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if item == nil {
-		return nil, templatedomain.ErrNotFound
-	}
 
+	s.emitAudit(ctx, "invoice_template.updated", item, map[string]any{"version": item.Version})
+	return s.toResponse(item), nil
+}
+
+func applyUpdate(item *templatedomain.InvoiceTemplate, req templatedomain.UpdateRequest) error {
 	if req.Name != nil {
 		name := strings.TrimSpace(*req.Name)
 		if name == "" {
-			return nil, templatedomain.ErrInvalidName
+			return templatedomain.ErrInvalidName
 		}
 		item.Name = name
 	}
@@ -170,7 +197,7 @@ func (s *Service) Update(ctx context.Context, req templatedomain.UpdateRequest)
 	if req.Currency != nil {
 		currency := strings.ToUpper(strings.TrimSpace(*req.Currency))
 		if currency == "" {
-			return nil, templatedomain.ErrInvalidCurrency
+			return templatedomain.ErrInvalidCurrency
 		}
 		item.Currency = currency
 	}
@@ -178,7 +205,7 @@ func (s *Service) Update(ctx context.Context, req templatedomain.UpdateRequest)
 	if req.Locale != nil {
 		locale := strings.TrimSpace(*req.Locale)
 		if locale == "" {
-			return nil, templatedomain.ErrInvalidLocale
+			return templatedomain.ErrInvalidLocale
 		}
 		item.Locale = locale
 	}
@@ -194,14 +221,7 @@ func (s *Service) Update(ctx context.Context, req templatedomain.UpdateRequest)
 	if req.Style != nil {
 		item.Style = normalizeMap(req.Style)
 	}
-
-	item.UpdatedAt = time.Now().UTC()
-	if err := s.repo.Update(ctx, s.db, item); err != nil {
-		return nil, err
-	}
-
-	s.emitAudit(ctx, "invoice_template.updated", item, nil)
-	return s.toResponse(item), nil
+	return nil
 }
 
 func (s *Service) SetDefault(ctx context.Context, id string) (*templatedomain.Response, error) {
@@ -240,6 +260,120 @@ func (s *Service) SetDefault(ctx context.Context, id string) (*templatedomain.Re
 	return s.toResponse(item), nil
 }
 
+func (s *Service) ListVersions(ctx context.Context, id string) ([]templatedomain.VersionResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, templatedomain.ErrInvalidOrganization
+	}
+
+	templateID, err := templatedomain.ParseID(id)
+	if err != nil {
+		return nil, templatedomain.ErrInvalidID
+	}
+
+	item, err := s.repo.FindByID(ctx, s.db, orgID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, templatedomain.ErrNotFound
+	}
+
+	versions, err := s.repo.ListVersions(ctx, s.db, orgID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]templatedomain.VersionResponse, 0, len(versions))
+	for i := range versions {
+		resp = append(resp, toVersionResponse(&versions[i], item.Version))
+	}
+	return resp, nil
+}
+
+func (s *Service) RestoreVersion(ctx context.Context, id string, version int) (*templatedomain.Response, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, templatedomain.ErrInvalidOrganization
+	}
+
+	templateID, err := templatedomain.ParseID(id)
+	if err != nil {
+		return nil, templatedomain.ErrInvalidID
+	}
+	if version <= 0 {
+		return nil, templatedomain.ErrInvalidVersion
+	}
+
+	var item *templatedomain.InvoiceTemplate
+	restored := false
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		item, err = s.repo.FindByID(ctx, tx, orgID, templateID)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return templatedomain.ErrNotFound
+		}
+		if item.Version == version {
+			return nil
+		}
+
+		snapshot, err := s.repo.FindVersion(ctx, tx, orgID, templateID, version)
+		if err != nil {
+			return err
+		}
+		if snapshot == nil {
+			return templatedomain.ErrVersionNotFound
+		}
+
+		// Restoring appends a version instead of rewinding, so the versions
+		// invoices point at are never reused for different content.
+		item.Name = snapshot.Name
+		item.Locale = snapshot.Locale
+		item.Currency = snapshot.Currency
+		item.Header = snapshot.Header
+		item.Footer = snapshot.Footer
+		item.Style = snapshot.Style
+		item.Version++
+		item.UpdatedAt = time.Now().UTC()
+		if err := s.repo.Update(ctx, tx, item); err != nil {
+			return err
+		}
+		restored = true
+		return s.saveVersion(ctx, tx, item, &version)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if restored {
+		s.emitAudit(ctx, "invoice_template.version_restored", item, map[string]any{
+			"version":       item.Version,
+			"restored_from": version,
+		})
+	}
+	return s.toResponse(item), nil
+}
+
+// saveVersion stores the current content of tmpl as tmpl.Version.
+func (s *Service) saveVersion(ctx context.Context, tx *gorm.DB, tmpl *templatedomain.InvoiceTemplate, restoredFrom *int) error {
+	return s.repo.InsertVersion(ctx, tx, &templatedomain.InvoiceTemplateVersion{
+		ID:                s.genID.Generate(),
+		OrgID:             tmpl.OrgID,
+		InvoiceTemplateID: tmpl.ID,
+		Version:           tmpl.Version,
+		Name:              tmpl.Name,
+		Locale:            tmpl.Locale,
+		Currency:          tmpl.Currency,
+		Header:            tmpl.Header,
+		Footer:            tmpl.Footer,
+		Style:             tmpl.Style,
+		RestoredFrom:      restoredFrom,
+		CreatedAt:         tmpl.UpdatedAt,
+	})
+}
+
 func (s *Service) unsetDefault(ctx context.Context, tx *gorm.DB, orgID snowflake.ID, now time.Time) error {
 	return tx.WithContext(ctx).Exec(
 		`UPDATE invoice_templates
@@ -286,11 +420,28 @@ func (s *Service) toResponse(tmpl *templatedomain.InvoiceTemplate) *templatedoma
 		Header:    map[string]any(tmpl.Header),
 		Footer:    map[string]any(tmpl.Footer),
 		Style:     map[string]any(tmpl.Style),
+		Version:   tmpl.Version,
 		CreatedAt: tmpl.CreatedAt,
 		UpdatedAt: tmpl.UpdatedAt,
 	}
 }
 
+func toVersionResponse(version *templatedomain.InvoiceTemplateVersion, current int) templatedomain.VersionResponse {
+	return templatedomain.VersionResponse{
+		TemplateID:   version.InvoiceTemplateID.String(),
+		Version:      version.Version,
+		Name:         version.Name,
+		Locale:       version.Locale,
+		Currency:     version.Currency,
+		Header:       map[string]any(version.Header),
+		Footer:       map[string]any(version.Footer),
+		Style:        map[string]any(version.Style),
+		RestoredFrom: version.RestoredFrom,
+		IsCurrent:    version.Version == current,
+		CreatedAt:    version.CreatedAt,
+	}
+}
+
 func normalizeMap(input map[string]any) datatypes.JSONMap {
 	if input == nil {
 		return datatypes.JSONMap{}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	templatedomain "github.com/railzwaylabs/railzway/internal/invoicetemplate/domain"
+	"github.com/railzwaylabs/railzway/internal/invoicetemplate/repository"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestTemplateVersions_UpdateAndRestore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&templatedomain.InvoiceTemplate{}, &templatedomain.InvoiceTemplateVersion{}))
+
+	node, _ := snowflake.NewNode(1)
+	repo := repository.Provide()
+	svc := NewService(Params{DB: db, Log: zap.NewNop(), GenID: node, Repo: repo})
+	orgID := node.Generate()
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	created, err := svc.Create(ctx, templatedomain.CreateRequest{
+		Name:     "Default",
+		Currency: "usd",
+		Header:   map[string]any{"company_name": "Acme"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created.Version)
+
+	renamed := "Acme Corp"
+	updated, err := svc.Update(ctx, templatedomain.UpdateRequest{
+		ID:     created.ID,
+		Header: map[string]any{"company_name": renamed},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+
+	versions, err := svc.ListVersions(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+	assert.True(t, versions[0].IsCurrent)
+	assert.Equal(t, "Acme", versions[1].Header["company_name"])
+	assert.False(t, versions[1].IsCurrent)
+
+	// Restoring appends a version with the old content.
+	restored, err := svc.RestoreVersion(ctx, created.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, restored.Version)
+	assert.Equal(t, "Acme", restored.Header["company_name"])
+
+	templateID, _ := templatedomain.ParseID(created.ID)
+	snapshot, err := repo.FindVersion(ctx, db, orgID, templateID, 3)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	require.NotNil(t, snapshot.RestoredFrom)
+	assert.Equal(t, 1, *snapshot.RestoredFrom)
+
+	// Version 2 still holds the content invoices finalized with it used.
+	snapshot, err = repo.FindVersion(ctx, db, orgID, templateID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, renamed, snapshot.Header["company_name"])
+
+	_, err = svc.RestoreVersion(ctx, created.ID, 9)
+	assert.ErrorIs(t, err, templatedomain.ErrVersionNotFound)
+	_, err = svc.RestoreVersion(ctx, created.ID, 0)
+	assert.ErrorIs(t, err, templatedomain.ErrInvalidVersion)
+}
@@ -35,6 +35,7 @@ const (
 	SourceTypeCreditGrant LedgerSourceType = "credit_grant" // promo / goodwill credit
 	SourceTypeCreditUse   LedgerSourceType = "credit_use"   // credit applied to invoice
 	SourceTypeRefund      LedgerSourceType = "refund"       // money returned to customer
+	SourceTypeCreditNote  LedgerSourceType = "credit_note"  // invoice amount reversed
 
 	// ======================
 	// Disputes (economic impact only)
@@ -77,12 +78,36 @@ const (
 	Equity    LedgerAccountType = "equity"
 )
 
+// StandardAccount describes an account every organization is provisioned with.
+type StandardAccount struct {
+	Code LedgerAccountCode
+	Type LedgerAccountType
+	Name string
+}
+
+// StandardAccounts is the default chart of accounts. Billing, payments and
+// disputes post against these codes, so each org needs all of them.
+var StandardAccounts = []StandardAccount{
+	{AccountCodeAccountsReceivable, Assets, "Accounts Receivable"},
+	{AccountCodeCash, Assets, "Cash / Bank"},
+
+	{AccountCodeRevenueUsage, Income, "Usage Revenue"},
+	{AccountCodeRevenueFlat, Income, "Subscription Revenue"},
+
+	{AccountCodeTaxPayable, Liability, "Tax Payable"},
+	{AccountCodeCreditBalance, Liability, "Customer Credit Balance"},
+	{AccountCodeRefundLiab, Liability, "Refund Liability"},
+
+	{AccountCodePaymentFeeExpense, Expense, "Payment Gateway Fees"},
+	{AccountCodeAdjustment, Expense, "Billing Adjustment"},
+}
+
 // LedgerAccount defines a chart-of-accounts entry.
 type LedgerAccount struct {
 	ID        snowflake.ID      `gorm:"primaryKey"`
 	OrgID     snowflake.ID      `gorm:"not null;index;uniqueIndex:ux_ledger_accounts_org_code,priority:1"`
-	Code      LedgerAccountCode `gorm:"code:text;not null;uniqueIndex:ux_ledger_accounts_org_code,priority:2"`
-	Type      LedgerAccountType `gorm:"type:text;not null;uniqueIndex:ux_ledger_accounts_org_type"`
+	Code      LedgerAccountCode `gorm:"type:text;not null;uniqueIndex:ux_ledger_accounts_org_code,priority:2"`
+	Type      LedgerAccountType `gorm:"type:text;not null"`
 	Name      string            `gorm:"type:text;not null"`
 	CreatedAt time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP"`
 }
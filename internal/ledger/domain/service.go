@@ -19,6 +19,35 @@ type LedgerService interface {
 		occurredAt time.Time,
 		lines []LedgerEntryLine,
 	) error
+
+	// CustomerBalance returns what the customer owes in currency: the accounts
+	// receivable balance of its entries that occurred before asOf. A zero asOf
+	// covers every entry.
+	CustomerBalance(ctx context.Context, orgID, customerID snowflake.ID, currency string, asOf time.Time) (int64, error)
+
+	// ListCustomerEntries returns the customer's accounts receivable movements
+	// in currency that occurred in [from, to), oldest first.
+	ListCustomerEntries(ctx context.Context, orgID, customerID snowflake.ID, currency string, from, to time.Time) ([]CustomerEntry, error)
+
+	AccountProvisioner
+}
+
+// AccountProvisioner creates the standard chart of accounts for an org.
+// EnsureAccounts is idempotent; accounts that already exist are left as is.
+type AccountProvisioner interface {
+	EnsureAccounts(ctx context.Context, orgID snowflake.ID) error
+}
+
+// CustomerEntry is a ledger entry's effect on a customer's accounts
+// receivable. Debits raise what the customer owes, credits lower it.
+type CustomerEntry struct {
+	EntryID    snowflake.ID     `gorm:"column:entry_id"`
+	SourceType LedgerSourceType `gorm:"column:source_type"`
+	SourceID   snowflake.ID     `gorm:"column:source_id"`
+	Currency   string           `gorm:"column:currency"`
+	OccurredAt time.Time        `gorm:"column:occurred_at"`
+	Debit      int64            `gorm:"column:debit"`
+	Credit     int64            `gorm:"column:credit"`
 }
 
 // Service is the package alias for LedgerService.
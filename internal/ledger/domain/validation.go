@@ -1,29 +1,35 @@
 package domain
 
+import "strings"
+
 // ValidateBalanced ensures ledger lines sum to a balanced double-entry posting.
+// Debits and credits must match within each currency; a surplus in one
+// currency never offsets a shortfall in another.
 func ValidateBalanced(lines []LedgerEntryLine) error {
 	if len(lines) < 2 {
 		return ErrInvalidEntryLines
 	}
 
-	var debitTotal int64
-	var creditTotal int64
+	balances := make(map[string]int64)
 	for _, line := range lines {
 		if line.Amount < 0 {
 			return ErrInvalidLineAmount
 		}
+		currency := strings.ToUpper(strings.TrimSpace(line.Currency))
 		switch line.Direction {
 		case LedgerEntryDirectionDebit:
-			debitTotal += line.Amount
+			balances[currency] += line.Amount
 		case LedgerEntryDirectionCredit:
-			creditTotal += line.Amount
+			balances[currency] -= line.Amount
 		default:
 			return ErrInvalidLineDirection
 		}
 	}
 
-	if debitTotal != creditTotal {
-		return ErrUnbalancedEntry
+	for _, balance := range balances {
+		if balance != 0 {
+			return ErrUnbalancedEntry
+		}
 	}
 	return nil
 }
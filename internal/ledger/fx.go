@@ -1,10 +1,12 @@
 package ledger
 
 import (
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
 	"github.com/railzwaylabs/railzway/internal/ledger/service"
 	"go.uber.org/fx"
 )
 
 var Module = fx.Module("ledger.service",
 	fx.Provide(service.NewService),
+	fx.Provide(func(svc ledgerdomain.Service) ledgerdomain.AccountProvisioner { return svc }),
 )
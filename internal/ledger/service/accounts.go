@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bwmarrin/snowflake"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	"go.uber.org/zap"
+)
+
+// EnsureAccounts creates any of the standard accounts the org is missing.
+// Orgs already provisioned by this process are skipped without touching the
+// database, so callers on the billing path can invoke it on every run.
+func (s *Service) EnsureAccounts(ctx context.Context, orgID snowflake.ID) error {
+	if orgID == 0 {
+		return ledgerdomain.ErrInvalidOrganization
+	}
+	if _, ok := s.provisioned.Load(orgID); ok {
+		return nil
+	}
+
+	created := int64(0)
+	for _, account := range ledgerdomain.StandardAccounts {
+		result := s.db.WithContext(ctx).Exec(
+			`INSERT INTO ledger_accounts (id, org_id, code, type, name)
+			 VALUES (?, ?, ?, ?, ?)
+			 ON CONFLICT (org_id, code) DO NOTHING`,
+			s.genID.Generate(),
+			orgID,
+			string(account.Code),
+			string(account.Type),
+			account.Name,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		created += result.RowsAffected
+	}
+
+	if created > 0 {
+		s.log.Info("provisioned ledger accounts",
+			zap.String("org_id", orgID.String()),
+			zap.Int64("created", created),
+		)
+	}
+	s.provisioned.Store(orgID, struct{}{})
+	return nil
+}
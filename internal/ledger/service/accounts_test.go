@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestEnsureAccounts_ProvisionsStandardChartOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&ledgerdomain.LedgerAccount{}))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	ctx := context.Background()
+	newService := func() ledgerdomain.Service {
+		return NewService(Params{DB: db, Log: zap.NewNop(), GenID: node})
+	}
+
+	// An account created ad hoc before provisioning keeps its row.
+	existingID := node.Generate()
+	require.NoError(t, db.Create(&ledgerdomain.LedgerAccount{
+		ID: existingID, OrgID: orgID, Code: ledgerdomain.AccountCodeCash, Type: ledgerdomain.Assets, Name: "Bank",
+	}).Error)
+
+	require.NoError(t, newService().EnsureAccounts(ctx, orgID))
+	// A second process starts without the in-memory record.
+	require.NoError(t, newService().EnsureAccounts(ctx, orgID))
+
+	var accounts []ledgerdomain.LedgerAccount
+	require.NoError(t, db.Where("org_id = ?", orgID).Order("code").Find(&accounts).Error)
+	require.Len(t, accounts, len(ledgerdomain.StandardAccounts))
+	for _, account := range accounts {
+		if account.Code == ledgerdomain.AccountCodeCash {
+			assert.Equal(t, existingID, account.ID)
+			assert.Equal(t, "Bank", account.Name)
+		}
+	}
+
+	assert.ErrorIs(t, newService().EnsureAccounts(ctx, 0), ledgerdomain.ErrInvalidOrganization)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+)
+
+// customerEntryScope resolves the customer behind each ledger entry from its
+// source and keeps the accounts receivable lines of the given customer.
+// Billing and adjustment entries are scoped through the subscription of the
+// billing cycle or through the invoice, as finalization posts them against
+// the invoice; payments, refunds and fees through the payment event, disputes
+// through the dispute and credit notes through the credit note.
+const customerEntryScope = `
+		FROM ledger_entries le
+		JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
+		JOIN ledger_accounts a ON a.id = l.account_id
+		LEFT JOIN billing_cycles bc
+			ON bc.id = le.source_id
+		   AND le.source_type IN (?, ?)
+		LEFT JOIN subscriptions s
+			ON s.id = bc.subscription_id
+		LEFT JOIN invoices inv
+			ON inv.id = le.source_id
+		   AND le.source_type IN (?, ?)
+		LEFT JOIN payment_events pe
+			ON pe.id = le.source_id
+		   AND le.source_type IN (?, ?, ?)
+		LEFT JOIN payment_disputes pd
+			ON pd.id = le.source_id
+		   AND le.source_type IN (?, ?, ?)
+		LEFT JOIN credit_notes cn
+			ON cn.id = le.source_id
+		   AND le.source_type = ?
+		WHERE le.org_id = ?
+		  AND a.code = ?
+		  AND le.currency = ?
+		  AND (
+			   (le.source_type IN (?, ?) AND COALESCE(s.customer_id, inv.customer_id) = ?)
+			OR (le.source_type IN (?, ?, ?) AND pe.customer_id = ?)
+			OR (le.source_type IN (?, ?, ?) AND pd.customer_id = ?)
+			OR (le.source_type = ? AND cn.customer_id = ?)
+		  )`
+
+func customerEntryScopeArgs(orgID, customerID snowflake.ID, currency string) []any {
+	return []any{
+		// joins
+		ledgerdomain.SourceTypeBillingCycle,
+		ledgerdomain.SourceTypeAdjustment,
+		ledgerdomain.SourceTypeBillingCycle,
+		ledgerdomain.SourceTypeAdjustment,
+		ledgerdomain.SourceTypePayment,
+		ledgerdomain.SourceTypePaymentFee,
+		ledgerdomain.SourceTypeRefund,
+		ledgerdomain.SourceTypeDisputeHold,
+		ledgerdomain.SourceTypeDisputeWin,
+		ledgerdomain.SourceTypeDisputeLoss,
+		ledgerdomain.SourceTypeCreditNote,
+
+		// filters
+		orgID,
+		ledgerdomain.AccountCodeAccountsReceivable,
+		currency,
+
+		// customer resolution
+		ledgerdomain.SourceTypeBillingCycle,
+		ledgerdomain.SourceTypeAdjustment,
+		customerID,
+		ledgerdomain.SourceTypePayment,
+		ledgerdomain.SourceTypePaymentFee,
+		ledgerdomain.SourceTypeRefund,
+		customerID,
+		ledgerdomain.SourceTypeDisputeHold,
+		ledgerdomain.SourceTypeDisputeWin,
+		ledgerdomain.SourceTypeDisputeLoss,
+		customerID,
+		ledgerdomain.SourceTypeCreditNote,
+		customerID,
+	}
+}
+
+func (s *Service) CustomerBalance(
+	ctx context.Context,
+	orgID snowflake.ID,
+	customerID snowflake.ID,
+	currency string,
+	asOf time.Time,
+) (int64, error) {
+	if orgID == 0 {
+		return 0, ledgerdomain.ErrInvalidOrganization
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return 0, ledgerdomain.ErrInvalidCurrency
+	}
+
+	query := `
+		SELECT COALESCE(
+			SUM(CASE l.direction WHEN 'debit' THEN l.amount ELSE -l.amount END),
+			0
+		) AS balance` + customerEntryScope
+	args := customerEntryScopeArgs(orgID, customerID, currency)
+	if !asOf.IsZero() {
+		query += `
+		  AND le.occurred_at < ?`
+		args = append(args, asOf.UTC())
+	}
+
+	var balance int64
+	if err := s.db.WithContext(ctx).Raw(query, args...).Scan(&balance).Error; err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+func (s *Service) ListCustomerEntries(
+	ctx context.Context,
+	orgID snowflake.ID,
+	customerID snowflake.ID,
+	currency string,
+	from, to time.Time,
+) ([]ledgerdomain.CustomerEntry, error) {
+	if orgID == 0 {
+		return nil, ledgerdomain.ErrInvalidOrganization
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return nil, ledgerdomain.ErrInvalidCurrency
+	}
+
+	query := `
+		SELECT le.id AS entry_id, le.source_type, le.source_id, le.currency, le.occurred_at,
+		       SUM(CASE l.direction WHEN 'debit' THEN l.amount ELSE 0 END) AS debit,
+		       SUM(CASE l.direction WHEN 'credit' THEN l.amount ELSE 0 END) AS credit` + customerEntryScope + `
+		  AND le.occurred_at >= ?
+		  AND le.occurred_at < ?
+		GROUP BY le.id, le.source_type, le.source_id, le.currency, le.occurred_at
+		ORDER BY le.occurred_at ASC, le.id ASC`
+	args := append(customerEntryScopeArgs(orgID, customerID, currency), from.UTC(), to.UTC())
+
+	var entries []ledgerdomain.CustomerEntry
+	if err := s.db.WithContext(ctx).Raw(query, args...).Scan(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
@@ -36,6 +37,9 @@ type Service struct {
 	outbox     *events.Outbox
 	obsMetrics *obsmetrics.Metrics
 	orgGate    bootstrap.OrgGate
+
+	// provisioned records orgs whose standard accounts are known to exist.
+	provisioned sync.Map
 }
 
 func NewService(p Params) ledgerdomain.Service {
@@ -100,10 +104,15 @@ func (s *Service) CreateEntry(
 		if line.Amount < 0 {
 			return ledgerdomain.ErrInvalidLineAmount
 		}
+		// Lines without a currency are posted in the entry's currency.
+		lineCurrency := strings.TrimSpace(line.Currency)
+		if lineCurrency == "" {
+			lineCurrency = currency
+		}
 		normalized = append(normalized, ledgerdomain.LedgerEntryLine{
 			AccountID: line.AccountID,
 			Direction: direction,
-			Currency:  line.Currency,
+			Currency:  lineCurrency,
 			Amount:    line.Amount,
 		})
 	}
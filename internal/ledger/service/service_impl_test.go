@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCreateEntry_RejectsUnbalancedLines(t *testing.T) {
+	node, _ := snowflake.NewNode(1)
+	svc := NewService(Params{Log: zap.NewNop(), GenID: node})
+	ctx := context.Background()
+	arID, revenueID := node.Generate(), node.Generate()
+
+	create := func(lines ...ledgerdomain.LedgerEntryLine) error {
+		return svc.CreateEntry(ctx, node.Generate(), string(ledgerdomain.SourceTypeAdjustment), node.Generate(), "USD", time.Now(), lines)
+	}
+
+	err := create(
+		ledgerdomain.LedgerEntryLine{AccountID: arID, Direction: ledgerdomain.LedgerEntryDirectionDebit, Currency: "USD", Amount: 500},
+		ledgerdomain.LedgerEntryLine{AccountID: revenueID, Direction: ledgerdomain.LedgerEntryDirectionCredit, Currency: "USD", Amount: 499},
+	)
+	assert.ErrorIs(t, err, ledgerdomain.ErrUnbalancedEntry)
+
+	// Totals match, but each currency is off by the full amount.
+	err = create(
+		ledgerdomain.LedgerEntryLine{AccountID: arID, Direction: ledgerdomain.LedgerEntryDirectionDebit, Currency: "USD", Amount: 500},
+		ledgerdomain.LedgerEntryLine{AccountID: revenueID, Direction: ledgerdomain.LedgerEntryDirectionCredit, Currency: "EUR", Amount: 500},
+	)
+	assert.ErrorIs(t, err, ledgerdomain.ErrUnbalancedEntry)
+
+	// A line without a currency is counted in the entry's currency.
+	err = create(
+		ledgerdomain.LedgerEntryLine{AccountID: arID, Direction: ledgerdomain.LedgerEntryDirectionDebit, Amount: 500},
+		ledgerdomain.LedgerEntryLine{AccountID: revenueID, Direction: ledgerdomain.LedgerEntryDirectionCredit, Currency: "EUR", Amount: 500},
+	)
+	assert.ErrorIs(t, err, ledgerdomain.ErrUnbalancedEntry)
+}
+
+func TestValidateBalanced_PerCurrency(t *testing.T) {
+	lines := []ledgerdomain.LedgerEntryLine{
+		{Direction: ledgerdomain.LedgerEntryDirectionDebit, Currency: "USD", Amount: 500},
+		{Direction: ledgerdomain.LedgerEntryDirectionCredit, Currency: "usd", Amount: 300},
+		{Direction: ledgerdomain.LedgerEntryDirectionCredit, Currency: "USD", Amount: 200},
+		{Direction: ledgerdomain.LedgerEntryDirectionDebit, Currency: "EUR", Amount: 70},
+		{Direction: ledgerdomain.LedgerEntryDirectionCredit, Currency: "EUR", Amount: 70},
+	}
+	assert.NoError(t, ledgerdomain.ValidateBalanced(lines))
+
+	lines[4].Amount = 69
+	assert.ErrorIs(t, ledgerdomain.ValidateBalanced(lines), ledgerdomain.ErrUnbalancedEntry)
+}
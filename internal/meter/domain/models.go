@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
@@ -14,11 +16,78 @@ type Meter struct {
 	Name        string       `json:"name" gorm:"type:text;not null"`
 	Aggregation string       `json:"aggregation" gorm:"type:text;not null"`
 	Unit        string       `json:"unit" gorm:"type:text;not null"`
+	ResetInterval string     `json:"reset_interval" gorm:"type:text;not null;default:'BILLING_CYCLE'"`
 	Active      bool         `json:"active" gorm:"not null;default:true"`
 	IdempotencyKey *string   `json:"-" gorm:"column:idempotency_key"`
+	ArchivedAt  *time.Time   `json:"archived_at,omitempty" gorm:"column:archived_at"`
 	CreatedAt   time.Time    `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
 	UpdatedAt   time.Time    `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
 }
 
 // TableName sets the database table name.
 func (Meter) TableName() string { return "meters" }
+
+// Archived reports whether the meter was archived. Archived meters keep
+// resolving for historical rating but cannot be bound to new items.
+func (m Meter) Archived() bool { return m.ArchivedAt != nil }
+
+// codePattern is the shape of a meter code once normalized.
+var codePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// NormalizeCode returns the canonical form of a meter code. Codes are stored
+// and looked up lowercased, so "API_Calls" and "api_calls" name one meter.
+func NormalizeCode(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// ValidCode reports whether a normalized code may name a new meter.
+func ValidCode(code string) bool {
+	return codePattern.MatchString(code)
+}
+
+// Aggregations decide how the usage of a meter is rolled up over a rating
+// window.
+const (
+	AggregationSum         = "SUM"
+	AggregationMax         = "MAX"
+	AggregationLast        = "LAST"
+	AggregationCount       = "COUNT"
+	AggregationUniqueCount = "UNIQUE_COUNT"
+)
+
+// NormalizeAggregation returns the canonical form of value and whether it is
+// a supported aggregation.
+func NormalizeAggregation(value string) (string, bool) {
+	aggregation := strings.ToUpper(strings.TrimSpace(value))
+	switch aggregation {
+	case AggregationSum,
+		AggregationMax,
+		AggregationLast,
+		AggregationCount,
+		AggregationUniqueCount:
+		return aggregation, true
+	default:
+		return "", false
+	}
+}
+
+// Reset intervals decide when the included allowance of a subscription item
+// on the meter starts over.
+const (
+	ResetIntervalBillingCycle = "BILLING_CYCLE"
+	ResetIntervalNever        = "NEVER"
+)
+
+// NormalizeResetInterval returns the canonical form of value and whether it
+// is a supported reset interval. An empty value resets every billing cycle.
+func NormalizeResetInterval(value string) (string, bool) {
+	interval := strings.ToUpper(strings.TrimSpace(value))
+	switch interval {
+	case "":
+		return ResetIntervalBillingCycle, true
+	case ResetIntervalBillingCycle, ResetIntervalNever:
+		return interval, true
+	default:
+		return "", false
+	}
+}
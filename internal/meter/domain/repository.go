@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/bwmarrin/snowflake"
 	"github.com/railzwaylabs/railzway/pkg/db/pagination"
@@ -11,7 +12,9 @@ import (
 type Repository interface {
 	Insert(ctx context.Context, db *gorm.DB, meter *Meter) error
 	Update(ctx context.Context, db *gorm.DB, meter *Meter) error
-	Delete(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) error
+	Archive(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID, at time.Time) error
+	FindByIDForUpdate(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*Meter, error)
+	ListReferencingSubscriptionIDs(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) ([]snowflake.ID, error)
 	FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*Meter, error)
 	FindByCode(ctx context.Context, db *gorm.DB, orgID snowflake.ID, code string) (*Meter, error)
 	FindByIdempotencyKey(ctx context.Context, db *gorm.DB, orgID snowflake.ID, key string) (*Meter, error)
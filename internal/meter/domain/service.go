@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
@@ -38,6 +39,7 @@ type CreateRequest struct {
 	Name        string `json:"name"`
 	Aggregation string `json:"aggregation_type"`
 	Unit        string `json:"unit"`
+	ResetInterval string `json:"reset_interval"`
 	Active      *bool  `json:"active"`
 	IdempotencyKey string `json:"-"`
 }
@@ -47,19 +49,22 @@ type UpdateRequest struct {
 	Name        *string `json:"name,omitempty"`
 	Aggregation *string `json:"aggregation_type,omitempty"`
 	Unit        *string `json:"unit,omitempty"`
+	ResetInterval *string `json:"reset_interval,omitempty"`
 	Active      *bool   `json:"active,omitempty"`
 }
 
 type Response struct {
-	ID             string    `json:"id"`
-	OrganizationID string    `json:"organization_id"`
-	Code           string    `json:"code"`
-	Name           string    `json:"name"`
-	Aggregation    string    `json:"aggregation"`
-	Unit           string    `json:"unit"`
-	Active         bool      `json:"active"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"organization_id"`
+	Code           string     `json:"code"`
+	Name           string     `json:"name"`
+	Aggregation    string     `json:"aggregation"`
+	Unit           string     `json:"unit"`
+	ResetInterval  string     `json:"reset_interval"`
+	Active         bool       `json:"active"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 var (
@@ -70,8 +75,28 @@ var (
 	ErrInvalidAggregation  = errors.New("invalid_aggregation_type")
 	ErrInvalidUnit         = errors.New("invalid_unit")
 	ErrInvalidID           = errors.New("invalid_id")
+
+	ErrInvalidResetInterval = errors.New("invalid_reset_interval")
+
+	ErrMeterInUse    = errors.New("meter_in_use")
+	ErrMeterArchived = errors.New("meter_archived")
 )
 
+// MeterInUseError lists the subscriptions whose items still reference a meter
+// that was asked to be archived.
+type MeterInUseError struct {
+	SubscriptionIDs []string
+}
+
+func (e *MeterInUseError) Error() string {
+	return fmt.Sprintf("meter is used by %d subscription(s)", len(e.SubscriptionIDs))
+}
+
+// Unwrap keeps errors.Is(err, ErrMeterInUse) true.
+func (e *MeterInUseError) Unwrap() error {
+	return ErrMeterInUse
+}
+
 func ParseID(value string) (snowflake.ID, error) {
 	return snowflake.ParseString(value)
 }
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/bwmarrin/snowflake"
 	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
 	"github.com/railzwaylabs/railzway/pkg/db/option"
 	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 	"gorm.io/gorm"
@@ -18,14 +20,15 @@ func Provide() meterdomain.Repository {
 
 func (r *repo) Insert(ctx context.Context, db *gorm.DB, m *meterdomain.Meter) error {
 	return db.WithContext(ctx).Exec(
-		`INSERT INTO meters (id, org_id, code, name, aggregation, unit, active, idempotency_key, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO meters (id, org_id, code, name, aggregation, unit, reset_interval, active, idempotency_key, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		m.ID,
 		m.OrgID,
 		m.Code,
 		m.Name,
 		m.Aggregation,
 		m.Unit,
+		m.ResetInterval,
 		m.Active,
 		m.IdempotencyKey,
 		m.CreatedAt,
@@ -36,11 +39,12 @@ func (r *repo) Insert(ctx context.Context, db *gorm.DB, m *meterdomain.Meter) er
 func (r *repo) Update(ctx context.Context, db *gorm.DB, m *meterdomain.Meter) error {
 	return db.WithContext(ctx).Exec(
 		`UPDATE meters
-		 SET name = ?, aggregation = ?, unit = ?, active = ?, updated_at = ?
+		 SET name = ?, aggregation = ?, unit = ?, reset_interval = ?, active = ?, updated_at = ?
 		 WHERE org_id = ? AND id = ?`,
 		m.Name,
 		m.Aggregation,
 		m.Unit,
+		m.ResetInterval,
 		m.Active,
 		m.UpdatedAt,
 		m.OrgID,
@@ -48,18 +52,62 @@ func (r *repo) Update(ctx context.Context, db *gorm.DB, m *meterdomain.Meter) er
 	).Error
 }
 
-func (r *repo) Delete(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) error {
+func (r *repo) Archive(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID, at time.Time) error {
 	return db.WithContext(ctx).Exec(
-		`DELETE FROM meters WHERE org_id = ? AND id = ?`,
+		`UPDATE meters
+		 SET active = FALSE, archived_at = ?, updated_at = ?
+		 WHERE org_id = ? AND id = ? AND archived_at IS NULL`,
+		at,
+		at,
 		orgID,
 		id,
 	).Error
 }
 
+func (r *repo) FindByIDForUpdate(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*meterdomain.Meter, error) {
+	var meter meterdomain.Meter
+	query := `SELECT id, org_id, code, name, aggregation, unit, reset_interval, active, idempotency_key, archived_at, created_at, updated_at
+		 FROM meters WHERE org_id = ? AND id = ?`
+	if db.Dialector.Name() != "sqlite" {
+		query += " FOR UPDATE"
+	}
+	err := db.WithContext(ctx).Raw(query, orgID, id).Scan(&meter).Error
+	if err != nil {
+		return nil, err
+	}
+	if meter.ID == 0 {
+		return nil, nil
+	}
+	return &meter, nil
+}
+
+// ListReferencingSubscriptionIDs returns the subscriptions that have not
+// ended and still carry an item on the meter.
+func (r *repo) ListReferencingSubscriptionIDs(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) ([]snowflake.ID, error) {
+	var ids []snowflake.ID
+	err := db.WithContext(ctx).Raw(
+		`SELECT DISTINCT s.id
+		 FROM subscription_items si
+		 JOIN subscriptions s ON s.id = si.subscription_id AND s.org_id = si.org_id
+		 WHERE si.org_id = ? AND si.meter_id = ? AND s.status IN (?, ?, ?, ?)
+		 ORDER BY s.id ASC`,
+		orgID,
+		id,
+		subscriptiondomain.SubscriptionStatusDraft,
+		subscriptiondomain.SubscriptionStatusActive,
+		subscriptiondomain.SubscriptionStatusPastDue,
+		subscriptiondomain.SubscriptionStatusPaused,
+	).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 func (r *repo) FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*meterdomain.Meter, error) {
 	var meter meterdomain.Meter
 	err := db.WithContext(ctx).Raw(
-		`SELECT id, org_id, code, name, aggregation, unit, active, idempotency_key, created_at, updated_at
+		`SELECT id, org_id, code, name, aggregation, unit, reset_interval, active, idempotency_key, archived_at, created_at, updated_at
 		 FROM meters WHERE org_id = ? AND id = ?`,
 		orgID,
 		id,
@@ -76,7 +124,7 @@ func (r *repo) FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID
 func (r *repo) FindByCode(ctx context.Context, db *gorm.DB, orgID snowflake.ID, code string) (*meterdomain.Meter, error) {
 	var meter meterdomain.Meter
 	err := db.WithContext(ctx).Raw(
-		`SELECT id, org_id, code, name, aggregation, unit, active, idempotency_key, created_at, updated_at
+		`SELECT id, org_id, code, name, aggregation, unit, reset_interval, active, idempotency_key, archived_at, created_at, updated_at
 		 FROM meters WHERE org_id = ? AND code = ?`,
 		orgID,
 		code,
@@ -93,7 +141,7 @@ func (r *repo) FindByCode(ctx context.Context, db *gorm.DB, orgID snowflake.ID,
 func (r *repo) FindByIdempotencyKey(ctx context.Context, db *gorm.DB, orgID snowflake.ID, key string) (*meterdomain.Meter, error) {
 	var meter meterdomain.Meter
 	err := db.WithContext(ctx).Raw(
-		`SELECT id, org_id, code, name, aggregation, unit, active, idempotency_key, created_at, updated_at
+		`SELECT id, org_id, code, name, aggregation, unit, reset_interval, active, idempotency_key, archived_at, created_at, updated_at
 		 FROM meters WHERE org_id = ? AND idempotency_key = ? LIMIT 1`,
 		orgID,
 		key,
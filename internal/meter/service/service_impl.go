@@ -57,8 +57,8 @@ func (s *Service) Create(ctx context.Context, req meterdomain.CreateRequest) (*m
 		}
 	}
 
-	code := strings.TrimSpace(req.Code)
-	if code == "" {
+	code := meterdomain.NormalizeCode(req.Code)
+	if !meterdomain.ValidCode(code) {
 		return nil, meterdomain.ErrInvalidCode
 	}
 
@@ -67,9 +67,9 @@ func (s *Service) Create(ctx context.Context, req meterdomain.CreateRequest) (*m
 		return nil, meterdomain.ErrInvalidName
 	}
 
-	aggregation := strings.TrimSpace(req.Aggregation)
-	if aggregation == "" {
-		return nil, meterdomain.ErrInvalidAggregation
+	aggregation, err := s.parseAggregation(req.Aggregation)
+	if err != nil {
+		return nil, err
 	}
 
 	unit := strings.TrimSpace(req.Unit)
@@ -77,6 +77,11 @@ func (s *Service) Create(ctx context.Context, req meterdomain.CreateRequest) (*m
 		return nil, meterdomain.ErrInvalidUnit
 	}
 
+	resetInterval, ok := meterdomain.NormalizeResetInterval(req.ResetInterval)
+	if !ok {
+		return nil, meterdomain.ErrInvalidResetInterval
+	}
+
 	active := true
 	if req.Active != nil {
 		active = *req.Active
@@ -90,6 +95,7 @@ func (s *Service) Create(ctx context.Context, req meterdomain.CreateRequest) (*m
 		Name:        name,
 		Aggregation: aggregation,
 		Unit:        unit,
+		ResetInterval: resetInterval,
 		Active:      active,
 		CreatedAt:   now,
 		UpdatedAt:   now,
@@ -122,7 +128,7 @@ func (s *Service) List(ctx context.Context, req meterdomain.ListRequest) (meterd
 
 	filter := meterdomain.ListRequest{
 		Name:    strings.TrimSpace(req.Name),
-		Code:    strings.TrimSpace(req.Code),
+		Code:    meterdomain.NormalizeCode(req.Code),
 		Active:  req.Active,
 		SortBy:  strings.TrimSpace(req.SortBy),
 		OrderBy: strings.TrimSpace(req.OrderBy),
@@ -206,9 +212,9 @@ func (s *Service) Update(ctx context.Context, req meterdomain.UpdateRequest) (*m
 	}
 
 	if req.Aggregation != nil {
-		aggregation := strings.TrimSpace(*req.Aggregation)
-		if aggregation == "" {
-			return nil, meterdomain.ErrInvalidAggregation
+		aggregation, err := s.parseAggregation(*req.Aggregation)
+		if err != nil {
+			return nil, err
 		}
 		item.Aggregation = aggregation
 	}
@@ -221,7 +227,18 @@ func (s *Service) Update(ctx context.Context, req meterdomain.UpdateRequest) (*m
 		item.Unit = unit
 	}
 
+	if req.ResetInterval != nil {
+		resetInterval, ok := meterdomain.NormalizeResetInterval(*req.ResetInterval)
+		if !ok {
+			return nil, meterdomain.ErrInvalidResetInterval
+		}
+		item.ResetInterval = resetInterval
+	}
+
 	if req.Active != nil {
+		if *req.Active && item.Archived() {
+			return nil, meterdomain.ErrMeterArchived
+		}
 		item.Active = *req.Active
 	}
 
@@ -233,6 +250,9 @@ func (s *Service) Update(ctx context.Context, req meterdomain.UpdateRequest) (*m
 	return s.toResponse(item), nil
 }
 
+// Delete archives the meter. Meters still referenced by items of
+// subscriptions that have not ended cannot be archived; archiving an archived
+// meter is a no-op.
 func (s *Service) Delete(ctx context.Context, id string) error {
 	orgID, ok := orgcontext.OrgIDFromContext(ctx)
 	if !ok || orgID == 0 {
@@ -244,15 +264,32 @@ func (s *Service) Delete(ctx context.Context, id string) error {
 		return meterdomain.ErrInvalidID
 	}
 
-	item, err := s.repo.FindByID(ctx, s.db, orgID, meterID)
-	if err != nil {
-		return err
-	}
-	if item == nil {
-		return meterdomain.ErrMeterNotFound
-	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		item, err := s.repo.FindByIDForUpdate(ctx, tx, orgID, meterID)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return meterdomain.ErrMeterNotFound
+		}
+		if item.Archived() {
+			return nil
+		}
+
+		subscriptionIDs, err := s.repo.ListReferencingSubscriptionIDs(ctx, tx, orgID, meterID)
+		if err != nil {
+			return err
+		}
+		if len(subscriptionIDs) > 0 {
+			inUse := &meterdomain.MeterInUseError{SubscriptionIDs: make([]string, 0, len(subscriptionIDs))}
+			for _, subscriptionID := range subscriptionIDs {
+				inUse.SubscriptionIDs = append(inUse.SubscriptionIDs, subscriptionID.String())
+			}
+			return inUse
+		}
 
-	return s.repo.Delete(ctx, s.db, orgID, meterID)
+		return s.repo.Archive(ctx, tx, orgID, meterID, time.Now().UTC())
+	})
 }
 
 func (s *Service) GetByCode(ctx context.Context, code string) (*meterdomain.Response, error) {
@@ -261,6 +298,11 @@ func (s *Service) GetByCode(ctx context.Context, code string) (*meterdomain.Resp
 		return nil, meterdomain.ErrInvalidOrganization
 	}
 
+	code = meterdomain.NormalizeCode(code)
+	if code == "" {
+		return nil, meterdomain.ErrInvalidCode
+	}
+
 	item, err := s.repo.FindByCode(ctx, s.db, orgID, code)
 	if err != nil {
 		return nil, err
@@ -294,6 +336,23 @@ func (s *Service) GetByID(ctx context.Context, id string) (*meterdomain.Response
 	return s.toResponse(item), nil
 }
 
+// parseAggregation normalizes a meter aggregation. Unsupported aggregations
+// fall back to sum so existing integrations keep ingesting.
+func (s *Service) parseAggregation(value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", meterdomain.ErrInvalidAggregation
+	}
+	aggregation, ok := meterdomain.NormalizeAggregation(trimmed)
+	if !ok {
+		s.log.Warn("unsupported meter aggregation, defaulting to sum",
+			zap.String("aggregation", trimmed),
+		)
+		return meterdomain.AggregationSum, nil
+	}
+	return aggregation, nil
+}
+
 func (s *Service) toResponse(m *meterdomain.Meter) *meterdomain.Response {
 	return &meterdomain.Response{
 		ID:             m.ID.String(),
@@ -302,7 +361,9 @@ func (s *Service) toResponse(m *meterdomain.Meter) *meterdomain.Response {
 		Name:           m.Name,
 		Aggregation:    m.Aggregation,
 		Unit:           m.Unit,
+		ResetInterval:  m.ResetInterval,
 		Active:         m.Active,
+		ArchivedAt:     m.ArchivedAt,
 		CreatedAt:      m.CreatedAt,
 		UpdatedAt:      m.UpdatedAt,
 	}
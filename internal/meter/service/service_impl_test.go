@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	"github.com/railzwaylabs/railzway/internal/meter/repository"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestDelete_ArchivesUnusedMetersOnly(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&meterdomain.Meter{},
+		&subscriptiondomain.Subscription{},
+		&subscriptiondomain.SubscriptionItem{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	node, _ := snowflake.NewNode(1)
+	svc := New(Params{DB: db, Log: zap.NewNop(), GenID: node, Repo: repository.Provide()})
+	orgID := node.Generate()
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	meter, err := svc.Create(ctx, meterdomain.CreateRequest{Code: "api_calls", Name: "API calls", Aggregation: "SUM", Unit: "call"})
+	if err != nil {
+		t.Fatalf("create meter: %v", err)
+	}
+	meterID, _ := snowflake.ParseString(meter.ID)
+
+	now := time.Now().UTC()
+	subscribe := func(status subscriptiondomain.SubscriptionStatus) snowflake.ID {
+		sub := subscriptiondomain.Subscription{
+			ID:               node.Generate(),
+			OrgID:            orgID,
+			CustomerID:       node.Generate(),
+			Status:           status,
+			CollectionMode:   subscriptiondomain.SubscriptionCollectionModeSendInvoice,
+			BillingCycleType: "monthly",
+			StartAt:          now,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		if err := db.Create(&sub).Error; err != nil {
+			t.Fatalf("create subscription: %v", err)
+		}
+		item := subscriptiondomain.SubscriptionItem{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: sub.ID,
+			PriceID:        node.Generate(),
+			MeterID:        &meterID,
+			BillingMode:    "METERED",
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := db.Create(&item).Error; err != nil {
+			t.Fatalf("create subscription item: %v", err)
+		}
+		return sub.ID
+	}
+	active := subscribe(subscriptiondomain.SubscriptionStatusActive)
+	subscribe(subscriptiondomain.SubscriptionStatusEnded)
+
+	err = svc.Delete(ctx, meter.ID)
+	var inUse *meterdomain.MeterInUseError
+	if !errors.As(err, &inUse) || !errors.Is(err, meterdomain.ErrMeterInUse) {
+		t.Fatalf("expected meter in use error, got %v", err)
+	}
+	if len(inUse.SubscriptionIDs) != 1 || inUse.SubscriptionIDs[0] != active.String() {
+		t.Fatalf("expected only the active subscription to block, got %v", inUse.SubscriptionIDs)
+	}
+
+	if err := db.Model(&subscriptiondomain.Subscription{}).Where("id = ?", active).Update("status", subscriptiondomain.SubscriptionStatusEnded).Error; err != nil {
+		t.Fatalf("end subscription: %v", err)
+	}
+	if err := svc.Delete(ctx, meter.ID); err != nil {
+		t.Fatalf("archive meter: %v", err)
+	}
+
+	// Archived meters stay readable for historical rating.
+	archived, err := svc.GetByID(ctx, meter.ID)
+	if err != nil {
+		t.Fatalf("get archived meter: %v", err)
+	}
+	if archived.ArchivedAt == nil || archived.Active {
+		t.Fatalf("expected archived inactive meter, got %+v", archived)
+	}
+	if err := svc.Delete(ctx, meter.ID); err != nil {
+		t.Fatalf("expected archiving twice to be a no-op, got %v", err)
+	}
+
+	reactivate := true
+	if _, err := svc.Update(ctx, meterdomain.UpdateRequest{ID: meter.ID, Active: &reactivate}); !errors.Is(err, meterdomain.ErrMeterArchived) {
+		t.Fatalf("expected archived meter to stay inactive, got %v", err)
+	}
+}
+
+func TestCreate_NormalizesCodeCase(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&meterdomain.Meter{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	node, _ := snowflake.NewNode(1)
+	svc := New(Params{DB: db, Log: zap.NewNop(), GenID: node, Repo: repository.Provide()})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(node.Generate()))
+
+	meter, err := svc.Create(ctx, meterdomain.CreateRequest{Code: " API_Calls ", Name: "API calls", Aggregation: "SUM", Unit: "call"})
+	if err != nil {
+		t.Fatalf("create meter: %v", err)
+	}
+	if meter.Code != "api_calls" {
+		t.Fatalf("expected code api_calls, got %q", meter.Code)
+	}
+
+	found, err := svc.GetByCode(ctx, "Api_Calls")
+	if err != nil {
+		t.Fatalf("get by code: %v", err)
+	}
+	if found.ID != meter.ID {
+		t.Fatalf("expected meter %s, got %s", meter.ID, found.ID)
+	}
+
+	for _, code := range []string{"", "api-calls", "api calls", "api.calls"} {
+		_, err := svc.Create(ctx, meterdomain.CreateRequest{Code: code, Name: "API calls", Aggregation: "SUM", Unit: "call"})
+		if !errors.Is(err, meterdomain.ErrInvalidCode) {
+			t.Fatalf("code %q: expected ErrInvalidCode, got %v", code, err)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+UPDATE subscription_items
+SET usage_behavior = 'arrears'
+WHERE usage_behavior IS NULL OR usage_behavior = '';
+
+ALTER TABLE rating_results
+    ADD COLUMN IF NOT EXISTS billing_phase TEXT NOT NULL DEFAULT 'arrears';
+
+CREATE INDEX IF NOT EXISTS idx_rating_results_cycle_phase ON rating_results(billing_cycle_id, billing_phase);
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS billing_phase TEXT NOT NULL DEFAULT 'arrears';
+
+DROP INDEX IF EXISTS ux_invoice_billing_cycle;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_invoice_billing_cycle ON invoices(billing_cycle_id, billing_phase);
+
+ALTER TABLE billing_cycles
+    ADD COLUMN IF NOT EXISTS advance_invoiced_at TIMESTAMPTZ;
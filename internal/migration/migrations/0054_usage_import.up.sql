@@ -0,0 +1,6 @@
+ALTER TABLE usage_events
+    ADD COLUMN IF NOT EXISTS import_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_usage_events_import_id
+    ON usage_events (org_id, import_id)
+    WHERE import_id IS NOT NULL;
@@ -0,0 +1,4 @@
+INSERT INTO payment_provider_catalog (provider, display_name, description, supports_webhook, supports_refund)
+VALUES
+  ('paypal', 'PayPal', 'PayPal checkout with wallet and card payments.', TRUE, TRUE)
+ON CONFLICT (provider) DO NOTHING;
@@ -0,0 +1,10 @@
+ALTER TABLE subscription_items
+    ALTER COLUMN quantity TYPE INTEGER USING quantity::integer;
+
+UPDATE subscription_items
+SET quantity = 1
+WHERE quantity IS NULL OR quantity < 1;
+
+ALTER TABLE subscription_items
+    ALTER COLUMN quantity SET DEFAULT 1,
+    ALTER COLUMN quantity SET NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE organization_billing_preferences
+    ADD COLUMN IF NOT EXISTS dunning_days JSONB NOT NULL DEFAULT '[1, 7, 14]'::jsonb;
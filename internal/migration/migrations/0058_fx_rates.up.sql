@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS fx_rates (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    effective_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_fx_rates_pair_effective_at
+    ON fx_rates (org_id, from_currency, to_currency, effective_at);
@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS minimum_commitment_cents BIGINT
+    CHECK (minimum_commitment_cents IS NULL OR minimum_commitment_cents >= 0);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS credit_notes (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    invoice_id BIGINT NOT NULL,
+    customer_id BIGINT NOT NULL,
+    payment_event_id BIGINT,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_notes_org_invoice
+    ON credit_notes (org_id, invoice_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_credit_notes_payment_event
+    ON credit_notes (payment_event_id)
+    WHERE payment_event_id IS NOT NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS processed_webhook_events (
+    org_id BIGINT NOT NULL,
+    provider TEXT NOT NULL,
+    provider_event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    processed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (org_id, provider, provider_event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_webhook_events_processed_at
+    ON processed_webhook_events (processed_at);
@@ -0,0 +1,2 @@
+ALTER TABLE organization_billing_preferences
+    ADD COLUMN IF NOT EXISTS assignment_sla_minutes INTEGER NOT NULL DEFAULT 60;
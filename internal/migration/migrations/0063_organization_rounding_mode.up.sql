@@ -0,0 +1,2 @@
+ALTER TABLE organization_billing_preferences
+    ADD COLUMN IF NOT EXISTS rounding_mode TEXT NOT NULL DEFAULT 'half_up';
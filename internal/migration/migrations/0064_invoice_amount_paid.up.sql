@@ -0,0 +1,7 @@
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS amount_paid BIGINT NOT NULL DEFAULT 0;
+
+UPDATE invoices
+SET amount_paid = (metadata->>'amount_paid')::bigint
+WHERE metadata ? 'amount_paid'
+  AND (metadata->>'amount_paid') ~ '^[0-9]+$';
@@ -0,0 +1,6 @@
+ALTER TABLE usage_events
+    ADD COLUMN IF NOT EXISTS dimensions JSONB;
+
+ALTER TABLE subscription_items
+    ADD COLUMN IF NOT EXISTS dimension_key TEXT,
+    ADD COLUMN IF NOT EXISTS dimension_value TEXT;
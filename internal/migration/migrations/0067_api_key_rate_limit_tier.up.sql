@@ -0,0 +1,2 @@
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS rate_limit_tier TEXT NOT NULL DEFAULT 'free';
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS coupons (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    discount_type TEXT NOT NULL,
+    percent_off NUMERIC(5,2),
+    amount_off_cents BIGINT,
+    currency TEXT,
+    duration TEXT NOT NULL,
+    duration_in_cycles INT,
+    max_redemptions INT,
+    times_redeemed INT NOT NULL DEFAULT 0,
+    redeem_by TIMESTAMPTZ,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_coupons_org_code ON coupons (org_id, code);
+
+CREATE TABLE IF NOT EXISTS subscription_discounts (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    coupon_id BIGINT NOT NULL REFERENCES coupons (id),
+    start_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_discounts_subscription
+    ON subscription_discounts (org_id, subscription_id);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_subscription_discounts_active_coupon
+    ON subscription_discounts (subscription_id, coupon_id)
+    WHERE ended_at IS NULL;
@@ -0,0 +1,21 @@
+-- Customer location and tax ID drive which tax rule applies at finalization.
+ALTER TABLE customers
+    ADD COLUMN IF NOT EXISTS country TEXT,
+    ADD COLUMN IF NOT EXISTS state TEXT,
+    ADD COLUMN IF NOT EXISTS tax_id TEXT;
+
+-- A tax definition with a country (and optionally a state) only applies to
+-- customers in that jurisdiction. Definitions without a country stay the
+-- organization-wide fallback.
+ALTER TABLE tax_definitions
+    ADD COLUMN IF NOT EXISTS country TEXT,
+    ADD COLUMN IF NOT EXISTS state TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_tax_definitions_jurisdiction
+    ON tax_definitions(org_id, country, state)
+    WHERE is_enabled = true;
+
+ALTER TABLE invoice_tax_lines
+    ADD COLUMN IF NOT EXISTS jurisdiction TEXT,
+    ADD COLUMN IF NOT EXISTS taxable_amount BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS note TEXT;
@@ -0,0 +1,8 @@
+-- BILLING_CYCLE allowances start over every cycle; NEVER allowances are
+-- consumed once over the life of the subscription item.
+ALTER TABLE meters
+    ADD COLUMN IF NOT EXISTS reset_interval TEXT NOT NULL DEFAULT 'BILLING_CYCLE';
+
+-- Units of the meter included with the item before its price applies.
+ALTER TABLE subscription_items
+    ADD COLUMN IF NOT EXISTS included_quantity DOUBLE PRECISION;
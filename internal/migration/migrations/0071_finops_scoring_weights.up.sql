@@ -0,0 +1,3 @@
+-- NULL keeps the equal weighting FinOps scores started with.
+ALTER TABLE organization_billing_preferences
+    ADD COLUMN IF NOT EXISTS scoring_weights JSONB;
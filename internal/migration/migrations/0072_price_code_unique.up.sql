@@ -0,0 +1,5 @@
+-- Codes identify prices within an organization, so lookups by code must be
+-- unambiguous. This replaces 0037_add_unique_constraint_prices.sql, which was
+-- never applied because it lacked the .up.sql suffix. products already has
+-- ux_products_org_code from 0002.
+CREATE UNIQUE INDEX IF NOT EXISTS ux_prices_org_code ON prices(org_id, code);
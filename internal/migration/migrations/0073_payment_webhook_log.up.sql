@@ -0,0 +1,18 @@
+-- Raw payloads of verified payment webhooks, kept so events that failed
+-- downstream can be replayed without a provider resend.
+CREATE TABLE IF NOT EXISTS payment_webhook_log (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    provider TEXT NOT NULL,
+    provider_event_id TEXT,
+    event_type TEXT,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL,
+    last_error TEXT,
+    attempts INT NOT NULL DEFAULT 1,
+    received_at TIMESTAMPTZ NOT NULL,
+    processed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_webhook_log_org_received
+    ON payment_webhook_log (org_id, received_at DESC);
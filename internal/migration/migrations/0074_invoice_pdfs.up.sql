@@ -0,0 +1,19 @@
+-- Generated invoice PDFs. A row is queued by POST /invoices/{id}/pdf and
+-- filled in by the invoice_pdf scheduler job; the invoice keeps a reference
+-- to it in rendered_pdf_url once the file is ready.
+CREATE TABLE IF NOT EXISTS invoice_pdfs (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    invoice_id BIGINT NOT NULL,
+    status TEXT NOT NULL,
+    invoice_template_id BIGINT,
+    content BYTEA,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_invoice_pdfs_invoice ON invoice_pdfs (org_id, invoice_id);
+CREATE INDEX IF NOT EXISTS idx_invoice_pdfs_status ON invoice_pdfs (status, created_at);
@@ -0,0 +1,33 @@
+-- Every saved state of an invoice template. invoice_templates.version points
+-- at the latest row; invoices record the version they were finalized with so
+-- later edits never change how they render.
+CREATE TABLE IF NOT EXISTS invoice_template_versions (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    invoice_template_id BIGINT NOT NULL,
+    version INT NOT NULL,
+    name TEXT NOT NULL,
+    locale TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    header JSONB,
+    footer JSONB,
+    style JSONB,
+    restored_from INT,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_invoice_template_versions_version
+    ON invoice_template_versions (invoice_template_id, version);
+CREATE INDEX IF NOT EXISTS idx_invoice_template_versions_org
+    ON invoice_template_versions (org_id, invoice_template_id);
+
+-- Templates edited before versioning only have their current state.
+INSERT INTO invoice_template_versions (
+    id, org_id, invoice_template_id, version, name, locale, currency, header, footer, style, created_at
+)
+SELECT id, org_id, id, version, name, locale, currency, header, footer, style, updated_at
+FROM invoice_templates
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS invoice_template_version INT;
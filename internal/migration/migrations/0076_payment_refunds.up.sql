@@ -0,0 +1,22 @@
+-- Refunds requested through the API. A row records the intent; the ledger
+-- entry is still written when the provider's refund webhook arrives.
+CREATE TABLE IF NOT EXISTS payment_refunds (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    payment_event_id BIGINT NOT NULL,
+    customer_id BIGINT NOT NULL,
+    invoice_id BIGINT,
+    provider TEXT NOT NULL,
+    provider_payment_id TEXT NOT NULL,
+    provider_refund_id TEXT,
+    amount BIGINT NOT NULL,
+    currency TEXT NOT NULL,
+    reason TEXT,
+    status TEXT NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_payment
+    ON payment_refunds (org_id, payment_event_id);
@@ -0,0 +1,2 @@
+ALTER TABLE organization_billing_preferences
+    ADD COLUMN IF NOT EXISTS payment_terms_days INTEGER NOT NULL DEFAULT 30;
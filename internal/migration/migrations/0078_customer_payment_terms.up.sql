@@ -0,0 +1,2 @@
+ALTER TABLE customers
+    ADD COLUMN IF NOT EXISTS payment_terms_days INTEGER;
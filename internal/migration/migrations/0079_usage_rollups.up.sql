@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS usage_rollups (
+    org_id BIGINT NOT NULL,
+    subscription_id BIGINT NOT NULL,
+    meter_id BIGINT NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    sum_value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    event_count BIGINT NOT NULL DEFAULT 0,
+    last_value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_recorded_at TIMESTAMPTZ NOT NULL,
+    last_event_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_id, subscription_id, meter_id, bucket_start)
+);
+
+ALTER TABLE usage_events
+    ADD COLUMN IF NOT EXISTS rolled_up_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_usage_events_rollup_pending
+    ON usage_events (recorded_at)
+    WHERE status = 'enriched' AND rolled_up_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE meters
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
@@ -0,0 +1,29 @@
+-- Outbound emails whose delivery we track. Provider webhooks report
+-- delivery, bounce and complaint events against provider_message_id.
+CREATE TABLE IF NOT EXISTS email_logs (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    provider TEXT NOT NULL,
+    provider_message_id TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    template TEXT,
+    billing_action_id BIGINT,
+    status TEXT NOT NULL,
+    status_reason TEXT,
+    status_updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_email_logs_provider_message_id
+    ON email_logs (provider_message_id);
+
+CREATE INDEX IF NOT EXISTS idx_email_logs_billing_action
+    ON email_logs (billing_action_id)
+    WHERE billing_action_id IS NOT NULL;
+
+-- Latest delivery status of the email a follow-up action sent, so work
+-- queues can show it without joining email_logs.
+ALTER TABLE billing_operation_actions
+    ADD COLUMN IF NOT EXISTS email_status TEXT,
+    ADD COLUMN IF NOT EXISTS email_status_at TIMESTAMPTZ;
@@ -0,0 +1,26 @@
+-- Usage already billed by threshold invoices, per item and cycle. The
+-- closing rating deducts it so the arrears invoice bills the remainder.
+CREATE TABLE IF NOT EXISTS billing_threshold_usages (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    billing_cycle_id BIGINT NOT NULL REFERENCES billing_cycles(id) ON DELETE CASCADE,
+    subscription_item_id BIGINT NOT NULL,
+    billed_quantity NUMERIC NOT NULL DEFAULT 0,
+    billed_amount BIGINT NOT NULL DEFAULT 0,
+    currency TEXT NOT NULL,
+    billed_through TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_billing_threshold_usage
+    ON billing_threshold_usages(billing_cycle_id, subscription_item_id);
+CREATE INDEX IF NOT EXISTS idx_billing_threshold_usages_org_id
+    ON billing_threshold_usages(org_id);
+
+-- A cycle gets one advance and one arrears invoice but any number of
+-- threshold invoices.
+DROP INDEX IF EXISTS ux_invoice_billing_cycle;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_invoice_billing_cycle
+    ON invoices(billing_cycle_id, billing_phase)
+    WHERE billing_phase <> 'threshold';
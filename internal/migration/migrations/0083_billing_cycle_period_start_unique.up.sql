@@ -0,0 +1,40 @@
+-- A subscription has at most one cycle per period start, whatever the
+-- period end, so replicas generating cycles concurrently cannot open two.
+--
+-- Cycles already duplicated that way are collapsed first, or the index
+-- cannot be built. The cycle kept is the one that got furthest: invoiced,
+-- then rated, then the oldest. Rating results and stats of the dropped
+-- cycles are derived data and go with them; change requests move to the
+-- kept cycle. Duplicates that were invoiced themselves are left for manual
+-- review and fail the index build.
+CREATE TEMPORARY TABLE billing_cycle_duplicates AS
+SELECT id, kept_id
+FROM (
+    SELECT bc.id,
+           FIRST_VALUE(bc.id) OVER w AS kept_id,
+           ROW_NUMBER() OVER w AS rn
+    FROM billing_cycles bc
+    WINDOW w AS (
+        PARTITION BY bc.subscription_id, bc.period_start
+        ORDER BY EXISTS (SELECT 1 FROM invoices i WHERE i.billing_cycle_id = bc.id) DESC,
+                 bc.rating_completed_at IS NOT NULL DESC,
+                 bc.id
+    )
+) ranked
+WHERE rn > 1
+  AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.billing_cycle_id = ranked.id);
+
+UPDATE billing_change_requests bcr
+SET billing_cycle_id = d.kept_id
+FROM billing_cycle_duplicates d
+WHERE bcr.billing_cycle_id = d.id;
+
+DELETE FROM rating_results WHERE billing_cycle_id IN (SELECT id FROM billing_cycle_duplicates);
+DELETE FROM billing_cycle_stats WHERE billing_cycle_id IN (SELECT id FROM billing_cycle_duplicates);
+DELETE FROM billing_cycles WHERE id IN (SELECT id FROM billing_cycle_duplicates);
+
+DROP TABLE billing_cycle_duplicates;
+
+DROP INDEX IF EXISTS ux_billing_cycle_period;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_billing_cycle_period
+    ON billing_cycles(subscription_id, period_start);
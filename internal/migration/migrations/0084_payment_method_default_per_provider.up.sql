@@ -0,0 +1,6 @@
+-- A customer keeps one default payment method per provider rather than one
+-- overall.
+DROP INDEX IF EXISTS idx_one_default_pm_per_customer;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_one_default_pm_per_customer_provider
+    ON customer_payment_methods(customer_id, provider)
+    WHERE is_default = true;
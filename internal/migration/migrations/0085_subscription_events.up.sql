@@ -0,0 +1,16 @@
+-- Timeline of subscription status changes with the reason and the actor
+-- that triggered each one. Rows are only ever appended.
+CREATE TABLE IF NOT EXISTS subscription_events (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reason TEXT,
+    actor_type TEXT NOT NULL,
+    actor_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_events_subscription
+    ON subscription_events (org_id, subscription_id, created_at, id);
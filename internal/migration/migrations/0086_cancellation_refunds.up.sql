@@ -0,0 +1,9 @@
+-- Organizations opt in to crediting or refunding the unused part of an
+-- upfront billed cycle when a subscription is canceled immediately.
+ALTER TABLE organization_billing_preferences
+    ADD COLUMN IF NOT EXISTS cancellation_refund TEXT NOT NULL DEFAULT 'none';
+
+-- An invoice is credited for an early cancellation at most once.
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_credit_notes_cancellation
+    ON credit_notes (invoice_id)
+    WHERE reason = 'cancellation';
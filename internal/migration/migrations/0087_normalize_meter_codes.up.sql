@@ -0,0 +1,22 @@
+-- Meter codes are matched lowercased. Lowercase the existing codes unless that
+-- would collide with another meter of the same organization; colliding meters
+-- keep their code until an operator renames or archives one of them.
+UPDATE meters
+SET code = LOWER(code),
+    updated_at = NOW()
+WHERE code <> LOWER(code)
+  AND NOT EXISTS (
+      SELECT 1
+      FROM meters other
+      WHERE other.org_id = meters.org_id
+        AND other.id <> meters.id
+        AND LOWER(other.code) = LOWER(meters.code)
+  );
+
+-- Subscription items snapshot the code of their meter.
+UPDATE subscription_items si
+SET meter_code = m.code
+FROM meters m
+WHERE si.meter_id = m.id
+  AND si.meter_code IS DISTINCT FROM m.code
+  AND LOWER(si.meter_code) = m.code;
@@ -0,0 +1,20 @@
+-- Spend alert threshold per subscription and the alerts already sent, one
+-- per cycle and threshold so a cycle is not alerted twice for the same
+-- amount.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS spend_alert_threshold_cents BIGINT
+    CHECK (spend_alert_threshold_cents IS NULL OR spend_alert_threshold_cents > 0);
+
+CREATE TABLE IF NOT EXISTS subscription_spend_alerts (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    billing_cycle_id BIGINT NOT NULL REFERENCES billing_cycles(id) ON DELETE CASCADE,
+    threshold_cents BIGINT NOT NULL,
+    amount_cents BIGINT NOT NULL,
+    currency TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_subscription_spend_alerts_cycle_threshold
+    ON subscription_spend_alerts (billing_cycle_id, threshold_cents);
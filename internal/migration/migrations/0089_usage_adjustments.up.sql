@@ -0,0 +1,4 @@
+-- Usage events are either usage or adjustments. Adjustments correct earlier
+-- usage of a sum meter and may carry a negative value.
+ALTER TABLE usage_events
+    ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'usage';
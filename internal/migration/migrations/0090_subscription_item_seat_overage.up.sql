@@ -0,0 +1,5 @@
+-- Licensed items may count their active seats with a meter and bill the
+-- seats above the licensed quantity at a per-unit overage price.
+ALTER TABLE subscription_items
+    ADD COLUMN IF NOT EXISTS seat_meter_id BIGINT,
+    ADD COLUMN IF NOT EXISTS overage_price_id BIGINT;
@@ -0,0 +1,24 @@
+-- Responses of mutating API requests, stored per organization, endpoint and
+-- Idempotency-Key so a repeated request replays the first response instead
+-- of running again. A key is locked while its first request is in flight.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    endpoint TEXT NOT NULL,
+    idempotency_key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('processing', 'completed')),
+    response_status INT,
+    response_content_type TEXT,
+    response_body BYTEA,
+    locked_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_idempotency_keys_org_endpoint_key
+    ON idempotency_keys (org_id, endpoint, idempotency_key);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at
+    ON idempotency_keys (expires_at);
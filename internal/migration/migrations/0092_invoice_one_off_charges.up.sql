@@ -0,0 +1,6 @@
+-- One-time charges are invoiced outside any billing cycle, with a zero
+-- billing_cycle_id, so a customer can have any number of them.
+DROP INDEX IF EXISTS ux_invoice_billing_cycle;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_invoice_billing_cycle
+    ON invoices(billing_cycle_id, billing_phase)
+    WHERE billing_phase <> 'threshold' AND billing_phase <> 'one_off';
@@ -0,0 +1,7 @@
+-- Rated usage is still rolled up: closing a cycle can rate events before the
+-- rollup worker reaches them.
+DROP INDEX IF EXISTS idx_usage_events_rollup_pending;
+
+CREATE INDEX IF NOT EXISTS idx_usage_events_rollup_pending
+    ON usage_events (recorded_at)
+    WHERE status IN ('enriched', 'rated') AND rolled_up_at IS NULL;
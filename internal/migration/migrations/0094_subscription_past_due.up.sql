@@ -0,0 +1,7 @@
+-- Subscriptions whose auto-charge failed go past due and are canceled if
+-- still unpaid when the organization's grace period ends.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS past_due_at TIMESTAMPTZ;
+
+ALTER TABLE organization_billing_preferences
+    ADD COLUMN IF NOT EXISTS past_due_grace_days INTEGER NOT NULL DEFAULT 7;
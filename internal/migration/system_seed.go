@@ -95,6 +95,7 @@ func seedCurrencies(ctx context.Context, tx *sql.Tx) error {
 
 func seedBillingCycleTypes(ctx context.Context, tx *sql.Tx) error {
 	seeds := []namedSeed{
+		{Code: "yearly", Name: "Yearly"},
 		{Code: "monthly", Name: "Monthly"},
 		{Code: "weekly", Name: "Weekly"},
 	}
@@ -22,6 +22,7 @@ var Module = fx.Module("observability",
 	),
 	fx.Invoke(ensureTracingProvider),
 	fx.Invoke(ensureSchedulerMetrics),
+	fx.Invoke(ensureBillingMetrics),
 )
 
 func ensureTracingProvider(_ *sdktrace.TracerProvider) {}
@@ -64,3 +65,7 @@ func provideMetricsConfig(cfg Config) metrics.Config {
 func ensureSchedulerMetrics(cfg metrics.Config) {
 	metrics.SchedulerWithConfig(cfg)
 }
+
+func ensureBillingMetrics(cfg metrics.Config) {
+	metrics.BillingWithConfig(cfg)
+}
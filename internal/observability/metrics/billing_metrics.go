@@ -0,0 +1,282 @@
+package metrics
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	BillingResultSuccess = "success"
+	BillingResultFailure = "failure"
+	BillingResultSkipped = "skipped"
+
+	BillingReasonNone = "none"
+)
+
+// BillingMetrics instruments the billing pipeline: rating runs, invoice
+// generation and automatic charges. Every series is labeled by org tier so
+// large tenants can be alerted on separately.
+type BillingMetrics struct {
+	ratingDuration     *prometheus.HistogramVec
+	ratingItems        *prometheus.HistogramVec
+	ratingRuns         *prometheus.CounterVec
+	invoiceDuration    *prometheus.HistogramVec
+	invoiceItems       *prometheus.HistogramVec
+	invoiceGenerations *prometheus.CounterVec
+	autoChargeDuration *prometheus.HistogramVec
+	autoChargeAttempts *prometheus.CounterVec
+}
+
+var (
+	billingMetricsOnce sync.Once
+	billingMetrics     *BillingMetrics
+)
+
+func Billing() *BillingMetrics {
+	return BillingWithConfig(Config{})
+}
+
+func BillingWithConfig(cfg Config) *BillingMetrics {
+	billingMetricsOnce.Do(func() {
+		billingMetrics = newBillingMetrics(prometheus.DefaultRegisterer, cfg)
+	})
+	return billingMetrics
+}
+
+func ResetBillingMetricsForTest() {
+	billingMetricsOnce = sync.Once{}
+	billingMetrics = nil
+}
+
+func newBillingMetrics(registerer prometheus.Registerer, cfg Config) *BillingMetrics {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	serviceName := strings.TrimSpace(cfg.ServiceName)
+	if serviceName == "" {
+		serviceName = "railzway"
+	}
+	environment := strings.TrimSpace(cfg.Environment)
+	if environment == "" {
+		environment = "unknown"
+	}
+
+	constLabels := prometheus.Labels{
+		"service": serviceName,
+		"env":     environment,
+	}
+
+	durationBuckets := []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+	itemBuckets := []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000}
+
+	ratingDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "railzway_rating_duration_seconds",
+			Help:        "Duration of rating runs for a billing cycle.",
+			Buckets:     durationBuckets,
+			ConstLabels: constLabels,
+		},
+		[]string{"org_tier", "result"},
+	)
+
+	ratingItems := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "railzway_rating_items",
+			Help:        "Number of subscription items rated per billing cycle.",
+			Buckets:     itemBuckets,
+			ConstLabels: constLabels,
+		},
+		[]string{"org_tier"},
+	)
+
+	ratingRuns := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "railzway_rating_runs_total",
+			Help:        "Total rating runs by result and failure reason.",
+			ConstLabels: constLabels,
+		},
+		[]string{"org_tier", "result", "reason"},
+	)
+
+	invoiceDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "railzway_invoice_generation_duration_seconds",
+			Help:        "Duration of invoice generation for a closed billing cycle.",
+			Buckets:     durationBuckets,
+			ConstLabels: constLabels,
+		},
+		[]string{"org_tier", "result"},
+	)
+
+	invoiceItems := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "railzway_invoice_items",
+			Help:        "Number of items on generated invoices.",
+			Buckets:     itemBuckets,
+			ConstLabels: constLabels,
+		},
+		[]string{"org_tier"},
+	)
+
+	invoiceGenerations := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "railzway_invoice_generations_total",
+			Help:        "Total invoice generation attempts by result and failure reason.",
+			ConstLabels: constLabels,
+		},
+		[]string{"org_tier", "result", "reason"},
+	)
+
+	autoChargeDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "railzway_auto_charge_duration_seconds",
+			Help:        "Duration of charge requests to the payment provider for automatic invoice charges.",
+			Buckets:     durationBuckets,
+			ConstLabels: constLabels,
+		},
+		[]string{"org_tier", "provider", "result"},
+	)
+
+	autoChargeAttempts := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "railzway_auto_charge_attempts_total",
+			Help:        "Total automatic invoice charges by provider, result and failure reason.",
+			ConstLabels: constLabels,
+		},
+		[]string{"org_tier", "provider", "result", "reason"},
+	)
+
+	registerer.MustRegister(
+		ratingDuration,
+		ratingItems,
+		ratingRuns,
+		invoiceDuration,
+		invoiceItems,
+		invoiceGenerations,
+		autoChargeDuration,
+		autoChargeAttempts,
+	)
+
+	return &BillingMetrics{
+		ratingDuration:     ratingDuration,
+		ratingItems:        ratingItems,
+		ratingRuns:         ratingRuns,
+		invoiceDuration:    invoiceDuration,
+		invoiceItems:       invoiceItems,
+		invoiceGenerations: invoiceGenerations,
+		autoChargeDuration: autoChargeDuration,
+		autoChargeAttempts: autoChargeAttempts,
+	}
+}
+
+// ObserveRating records a rating run. items is the number of subscription
+// items considered; it is only observed for successful runs.
+func (m *BillingMetrics) ObserveRating(orgTier string, duration time.Duration, items int, err error) {
+	if m == nil {
+		return
+	}
+	orgTier = normalizeOrgTier(orgTier)
+	result, reason := billingOutcome(err)
+	m.ratingDuration.WithLabelValues(orgTier, result).Observe(duration.Seconds())
+	m.ratingRuns.WithLabelValues(orgTier, result, reason).Inc()
+	if err == nil {
+		m.ratingItems.WithLabelValues(orgTier).Observe(float64(items))
+	}
+}
+
+// ObserveInvoiceGeneration records an invoice generation attempt. items is
+// the number of invoice items written; it is only observed when an invoice
+// was created.
+func (m *BillingMetrics) ObserveInvoiceGeneration(orgTier string, duration time.Duration, items int, created bool, err error) {
+	if m == nil {
+		return
+	}
+	orgTier = normalizeOrgTier(orgTier)
+	result, reason := billingOutcome(err)
+	if err == nil && !created {
+		result = BillingResultSkipped
+	}
+	m.invoiceDuration.WithLabelValues(orgTier, result).Observe(duration.Seconds())
+	m.invoiceGenerations.WithLabelValues(orgTier, result, reason).Inc()
+	if created {
+		m.invoiceItems.WithLabelValues(orgTier).Observe(float64(items))
+	}
+}
+
+// ObserveAutoChargeRequest records the duration of a charge request to the
+// payment provider.
+func (m *BillingMetrics) ObserveAutoChargeRequest(orgTier, provider string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	result, _ := billingOutcome(err)
+	m.autoChargeDuration.WithLabelValues(normalizeOrgTier(orgTier), normalizeProvider(provider), result).Observe(duration.Seconds())
+}
+
+// IncAutoCharge counts an automatic charge attempt. reason is empty on
+// success and one of the auto-charge error codes otherwise.
+func (m *BillingMetrics) IncAutoCharge(orgTier, provider, reason string) {
+	if m == nil {
+		return
+	}
+	result := BillingResultSuccess
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = BillingReasonNone
+	} else {
+		result = BillingResultFailure
+	}
+	m.autoChargeAttempts.WithLabelValues(normalizeOrgTier(orgTier), normalizeProvider(provider), result, reason).Inc()
+}
+
+func billingOutcome(err error) (string, string) {
+	if err == nil {
+		return BillingResultSuccess, BillingReasonNone
+	}
+	return BillingResultFailure, ClassifyBillingReason(err)
+}
+
+var billingReasonPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// ClassifyBillingReason maps billing errors to low-cardinality reasons. Domain
+// errors carry snake_case codes, which are used as is; anything else falls
+// back to the scheduler job classification.
+func ClassifyBillingReason(err error) string {
+	if err == nil {
+		return BillingReasonNone
+	}
+	root := err
+	for {
+		next := errors.Unwrap(root)
+		if next == nil {
+			break
+		}
+		root = next
+	}
+	if code := root.Error(); billingReasonPattern.MatchString(code) {
+		return code
+	}
+	return classifySchedulerJobReason(err)
+}
+
+func normalizeOrgTier(tier string) string {
+	tier = strings.ToLower(strings.TrimSpace(tier))
+	if tier == "" {
+		return "unknown"
+	}
+	return tier
+}
+
+func normalizeProvider(provider string) string {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "" {
+		return "unknown"
+	}
+	return provider
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestClassifyBillingReason(t *testing.T) {
+	errMissingPrice := errors.New("missing_price_amount")
+
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "domain", err: errMissingPrice, want: "missing_price_amount"},
+		{name: "wrapped_domain", err: fmt.Errorf("rating failed for item 42: %w", errMissingPrice), want: "missing_price_amount"},
+		{name: "deadline", err: context.DeadlineExceeded, want: SchedulerJobReasonDeadlineExceeded},
+		{name: "unknown", err: errors.New("connection reset by peer"), want: SchedulerJobReasonUnknown},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ClassifyBillingReason(tc.err); got != tc.want {
+				t.Fatalf("expected reason %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestObserveRatingLabelsByTierAndReason(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := newBillingMetrics(registry, Config{
+		ServiceName: "railzway",
+		Environment: "test",
+	})
+
+	metrics.ObserveRating("Enterprise", 2*time.Second, 120, nil)
+	metrics.ObserveRating("", time.Second, 3, errors.New("missing_meter"))
+
+	if got := testutil.ToFloat64(metrics.ratingRuns.WithLabelValues("enterprise", BillingResultSuccess, BillingReasonNone)); got != 1 {
+		t.Fatalf("expected 1 successful run, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.ratingRuns.WithLabelValues("unknown", BillingResultFailure, "missing_meter")); got != 1 {
+		t.Fatalf("expected 1 failed run, got %v", got)
+	}
+	if got := testutil.CollectAndCount(metrics.ratingItems); got != 1 {
+		t.Fatalf("expected item counts only for successful runs, got %d series", got)
+	}
+}
+
+func TestInvoiceGenerationSkippedWhenNothingCreated(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := newBillingMetrics(registry, Config{})
+
+	metrics.ObserveInvoiceGeneration("standard", time.Millisecond, 0, false, nil)
+
+	if got := testutil.ToFloat64(metrics.invoiceGenerations.WithLabelValues("standard", BillingResultSkipped, BillingReasonNone)); got != 1 {
+		t.Fatalf("expected 1 skipped generation, got %v", got)
+	}
+}
+
+func TestIncAutoCharge(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := newBillingMetrics(registry, Config{})
+
+	metrics.IncAutoCharge("standard", "Stripe", "")
+	metrics.IncAutoCharge("standard", "", "missing_payment_method")
+
+	if got := testutil.ToFloat64(metrics.autoChargeAttempts.WithLabelValues("standard", "stripe", BillingResultSuccess, BillingReasonNone)); got != 1 {
+		t.Fatalf("expected 1 successful charge, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.autoChargeAttempts.WithLabelValues("standard", "unknown", BillingResultFailure, "missing_payment_method")); got != 1 {
+		t.Fatalf("expected 1 failed charge, got %v", got)
+	}
+}
@@ -33,6 +33,7 @@ type Metrics struct {
 	ledgerEntries    metric.Int64Counter
 	rateLimitAllowed metric.Int64Counter
 	rateLimitDenied  metric.Int64Counter
+	priceCacheLookup metric.Int64Counter
 }
 
 // NewProvider configures and registers the meter provider.
@@ -101,6 +102,10 @@ func New(cfg Config, provider metric.MeterProvider) (*Metrics, error) {
 	if err != nil {
 		return nil, err
 	}
+	priceCacheLookup, err := meter.Int64Counter("railzway_price_cache_lookups_total")
+	if err != nil {
+		return nil, err
+	}
 
 	return &Metrics{
 		usageIngest:      usageIngest,
@@ -108,6 +113,7 @@ func New(cfg Config, provider metric.MeterProvider) (*Metrics, error) {
 		ledgerEntries:    ledgerEntries,
 		rateLimitAllowed: rateLimitAllowed,
 		rateLimitDenied:  rateLimitDenied,
+		priceCacheLookup: priceCacheLookup,
 	}, nil
 }
 
@@ -166,6 +172,22 @@ func (m *Metrics) RecordRateLimitDenied(ctx context.Context, orgID, endpoint, re
 	m.rateLimitDenied.Add(ctx, 1, metric.WithAttributes(attrs...))
 }
 
+// RecordPriceCacheLookup counts price cache lookups by entry kind and outcome.
+func (m *Metrics) RecordPriceCacheLookup(ctx context.Context, kind string, hit bool) {
+	if m == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	attrs := FilterAttributes(
+		attribute.String("cache", strings.TrimSpace(kind)),
+		attribute.String("result", result),
+	)
+	m.priceCacheLookup.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
 func newExporter(protocol, endpoint string) (sdkmetric.Exporter, error) {
 	protocol = strings.ToLower(strings.TrimSpace(protocol))
 	switch protocol {
@@ -196,6 +218,8 @@ var allowedLabelKeys = map[attribute.Key]struct{}{
 	"event_type":  {},
 	"source_type": {},
 	"reason":      {},
+	"cache":       {},
+	"result":      {},
 }
 
 // FilterAttributes strips disallowed labels to keep metrics low-cardinality.
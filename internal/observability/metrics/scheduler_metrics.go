@@ -45,6 +45,8 @@ const (
 	CycleStageRating           = "rating"
 	CycleStageCloseAfterRating = "close_after_rating"
 	CycleStageInvoice          = "invoice"
+	CycleStageAdvanceInvoice   = "advance_invoice"
+	CycleStageThresholdInvoice = "threshold_invoice"
 	CycleStageRecoveryRating   = "recovery_rating"
 	CycleStageRecoveryClose    = "recovery_close"
 	CycleStageRecoveryInvoice  = "recovery_invoice"
@@ -59,6 +61,7 @@ const (
 	LockResourceBillingCyclesForWork = "billing_cycles_for_work"
 	LockResourceOpenCycle            = "billing_cycles_open_cycle"
 	LockResourceBillingCycleByID     = "billing_cycle_by_id"
+	LockResourceSubscriptionCycles   = "subscription_cycles"
 )
 
 // SchedulerMetrics captures billing scheduler health signals for Cloud SLOs.
@@ -243,6 +246,7 @@ func newSchedulerMetrics(registerer prometheus.Registerer, cfg Config) *Schedule
 		LockResourceBillingCyclesForWork: dbLockWait.WithLabelValues(LockResourceBillingCyclesForWork),
 		LockResourceOpenCycle:            dbLockWait.WithLabelValues(LockResourceOpenCycle),
 		LockResourceBillingCycleByID:     dbLockWait.WithLabelValues(LockResourceBillingCycleByID),
+		LockResourceSubscriptionCycles:   dbLockWait.WithLabelValues(LockResourceSubscriptionCycles),
 	}
 
 	cycleErrorCounts := map[string]map[string]prometheus.Counter{}
@@ -257,6 +261,8 @@ func newSchedulerMetrics(registerer prometheus.Registerer, cfg Config) *Schedule
 		CycleStageRating,
 		CycleStageCloseAfterRating,
 		CycleStageInvoice,
+		CycleStageAdvanceInvoice,
+		CycleStageThresholdInvoice,
 		CycleStageRecoveryRating,
 		CycleStageRecoveryClose,
 		CycleStageRecoveryInvoice,
@@ -2,6 +2,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
@@ -25,6 +26,29 @@ type Organization struct {
 // TableName sets the database table name.
 func (Organization) TableName() string { return "organizations" }
 
+// TierMetadataKey is the organization metadata key holding the org's tier.
+// Operational metrics are labeled by tier.
+const TierMetadataKey = "tier"
+
+// DefaultTier is the tier of organizations without one in their metadata.
+const DefaultTier = "standard"
+
+// Tier returns the organization's tier from its metadata.
+func (o Organization) Tier() string {
+	return TierFromMetadata(o.Metadata)
+}
+
+// TierFromMetadata reads the tier from organization metadata, falling back to
+// DefaultTier.
+func TierFromMetadata(metadata map[string]any) string {
+	if value, ok := metadata[TierMetadataKey].(string); ok {
+		if tier := strings.ToLower(strings.TrimSpace(value)); tier != "" {
+			return tier
+		}
+	}
+	return DefaultTier
+}
+
 // OrganizationUser represents membership of a user in an organization.
 type OrganizationMember struct {
 	ID        snowflake.ID `gorm:"primaryKey" json:"id"`
@@ -53,11 +77,18 @@ func (OrganizationInvite) TableName() string { return "organization_invites" }
 
 // OrganizationBillingPreferences stores billing defaults for an organization.
 type OrganizationBillingPreferences struct {
-	OrgID     snowflake.ID `gorm:"primaryKey" json:"org_id"`
-	Currency  string       `gorm:"type:text;not null" json:"currency"`
-	Timezone  string       `gorm:"type:text;not null" json:"timezone"`
-	CreatedAt time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	OrgID                snowflake.ID       `gorm:"primaryKey" json:"org_id"`
+	Currency             string             `gorm:"type:text;not null" json:"currency"`
+	Timezone             string             `gorm:"type:text;not null" json:"timezone"`
+	DunningDays          datatypes.JSON     `gorm:"type:jsonb;not null;default:'[1, 7, 14]'" json:"dunning_days"`
+	AssignmentSLAMinutes int                `gorm:"not null;default:60" json:"assignment_sla_minutes"`
+	RoundingMode         RoundingMode       `gorm:"type:text;not null;default:'half_up'" json:"rounding_mode"`
+	PaymentTermsDays     int                `gorm:"not null;default:30" json:"payment_terms_days"`
+	CancellationRefund   CancellationRefund `gorm:"type:text;not null;default:'none'" json:"cancellation_refund"`
+	PastDueGraceDays     int                `gorm:"not null;default:7" json:"past_due_grace_days"`
+	ScoringWeights       datatypes.JSON     `gorm:"type:jsonb" json:"scoring_weights,omitempty"`
+	CreatedAt            time.Time          `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt            time.Time          `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
 // TableName sets the database table name.
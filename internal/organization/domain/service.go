@@ -3,6 +3,8 @@ package domain
 import (
 	"context"
 	"errors"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
@@ -57,6 +59,146 @@ type InviteRequest struct {
 type BillingPreferencesRequest struct {
 	Currency string
 	Timezone string
+	// DunningDays overrides the reminder schedule; nil keeps the current one.
+	DunningDays []int
+	// AssignmentSLAMinutes overrides how long an assignment may go without an
+	// action before it is escalated; nil keeps the current window.
+	AssignmentSLAMinutes *int
+	// RoundingMode overrides how rated amounts are rounded to whole cents;
+	// nil keeps the current mode.
+	RoundingMode *RoundingMode
+	// ScoringWeights overrides how FinOps performance dimensions are weighted
+	// in the total score; nil keeps the current weights.
+	ScoringWeights *ScoringWeights
+}
+
+// DefaultDunningDays is the reminder schedule, in days past due, used until an
+// organization configures its own.
+var DefaultDunningDays = []int{1, 7, 14}
+
+// MaxDunningDays bounds the number of reminders in a dunning schedule.
+const MaxDunningDays = 10
+
+// NormalizeDunningDays validates a reminder schedule and returns it sorted.
+// Offsets are whole days past due, at most a year out, without repeats.
+func NormalizeDunningDays(days []int) ([]int, error) {
+	if len(days) == 0 || len(days) > MaxDunningDays {
+		return nil, ErrInvalidDunningDays
+	}
+	out := append([]int(nil), days...)
+	sort.Ints(out)
+	for i, day := range out {
+		if day < 1 || day > 365 {
+			return nil, ErrInvalidDunningDays
+		}
+		if i > 0 && out[i-1] == day {
+			return nil, ErrInvalidDunningDays
+		}
+	}
+	return out, nil
+}
+
+// DefaultPaymentTermsDays is how many days after finalization an invoice is
+// due until an organization configures its own terms.
+const DefaultPaymentTermsDays = 30
+
+// MaxPaymentTermsDays bounds net payment terms to one year.
+const MaxPaymentTermsDays = 365
+
+// DefaultPastDueGraceDays is how long a past due subscription keeps billing
+// before it is canceled, until an organization configures its own period.
+// It leaves room for every auto-charge retry.
+const DefaultPastDueGraceDays = 7
+
+// MaxPastDueGraceDays bounds the past due grace period.
+const MaxPastDueGraceDays = 90
+
+// DefaultAssignmentSLAMinutes is the assignment SLA window used until an
+// organization configures its own.
+const DefaultAssignmentSLAMinutes = 60
+
+// MinAssignmentSLAMinutes and MaxAssignmentSLAMinutes bound the assignment
+// SLA window: five minutes to one week.
+const (
+	MinAssignmentSLAMinutes = 5
+	MaxAssignmentSLAMinutes = 7 * 24 * 60
+)
+
+// RoundingMode selects how fractional cents are rounded when usage and
+// prorated charges are rated.
+type RoundingMode string
+
+const (
+	// RoundingModeHalfUp rounds halves up to the next cent.
+	RoundingModeHalfUp RoundingMode = "half_up"
+	// RoundingModeHalfEven rounds halves to the nearest even cent (banker's
+	// rounding).
+	RoundingModeHalfEven RoundingMode = "half_even"
+	RoundingModeFloor    RoundingMode = "floor"
+	RoundingModeCeil     RoundingMode = "ceil"
+)
+
+// DefaultRoundingMode is the rounding mode used until an organization
+// configures its own.
+const DefaultRoundingMode = RoundingModeHalfUp
+
+// Valid reports whether m is a supported rounding mode.
+func (m RoundingMode) Valid() bool {
+	switch m {
+	case RoundingModeHalfUp, RoundingModeHalfEven, RoundingModeFloor, RoundingModeCeil:
+		return true
+	default:
+		return false
+	}
+}
+
+// CancellationRefund selects what a customer gets back for the unused part
+// of a cycle billed upfront when their subscription is canceled immediately.
+type CancellationRefund string
+
+const (
+	// CancellationRefundNone leaves the upfront invoice as billed.
+	CancellationRefundNone CancellationRefund = "none"
+	// CancellationRefundCredit issues a credit note for the unused amount.
+	CancellationRefundCredit CancellationRefund = "credit"
+	// CancellationRefundRefund refunds the unused amount through the payment
+	// provider when the invoice was paid, and credits it otherwise.
+	CancellationRefundRefund CancellationRefund = "refund"
+)
+
+// DefaultCancellationRefund keeps cancellations from crediting anything
+// until an organization opts in.
+const DefaultCancellationRefund = CancellationRefundNone
+
+// Valid reports whether m is a supported cancellation refund mode.
+func (m CancellationRefund) Valid() bool {
+	switch m {
+	case CancellationRefundNone, CancellationRefundCredit, CancellationRefundRefund:
+		return true
+	default:
+		return false
+	}
+}
+
+// ScoringWeights weights the FinOps performance dimensions in a member's
+// total score. The weights are non-negative and sum to 1.
+type ScoringWeights struct {
+	Responsiveness float64 `json:"responsiveness"`
+	Completion     float64 `json:"completion"`
+	Risk           float64 `json:"risk"`
+	Effectiveness  float64 `json:"effectiveness"`
+}
+
+// Valid reports whether the weights are non-negative and sum to 1.
+func (w ScoringWeights) Valid() bool {
+	sum := 0.0
+	for _, weight := range []float64{w.Responsiveness, w.Completion, w.Risk, w.Effectiveness} {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return false
+		}
+		sum += weight
+	}
+	return math.Abs(sum-1) <= 1e-6
 }
 
 type OrganizationResponse struct {
@@ -75,13 +217,17 @@ type OrganizationListResponseItem struct {
 }
 
 var (
-	ErrInvalidName         = errors.New("invalid_name")
-	ErrInvalidCountry      = errors.New("invalid_country")
-	ErrInvalidTimezone     = errors.New("invalid_timezone")
-	ErrInvalidCurrency     = errors.New("invalid_currency")
-	ErrInvalidUser         = errors.New("invalid_user")
-	ErrInvalidOrganization = errors.New("invalid_organization")
-	ErrInvalidEmail        = errors.New("invalid_email")
-	ErrInvalidRole         = errors.New("invalid_role")
-	ErrForbidden           = errors.New("forbidden")
+	ErrInvalidName           = errors.New("invalid_name")
+	ErrInvalidCountry        = errors.New("invalid_country")
+	ErrInvalidTimezone       = errors.New("invalid_timezone")
+	ErrInvalidCurrency       = errors.New("invalid_currency")
+	ErrInvalidDunningDays    = errors.New("invalid_dunning_days")
+	ErrInvalidSLAWindow      = errors.New("invalid_sla_window")
+	ErrInvalidRoundingMode   = errors.New("invalid_rounding_mode")
+	ErrInvalidScoringWeights = errors.New("invalid_scoring_weights")
+	ErrInvalidUser           = errors.New("invalid_user")
+	ErrInvalidOrganization   = errors.New("invalid_organization")
+	ErrInvalidEmail          = errors.New("invalid_email")
+	ErrInvalidRole           = errors.New("invalid_role")
+	ErrForbidden             = errors.New("forbidden")
 )
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/bwmarrin/snowflake"
@@ -91,18 +92,52 @@ func (r *repository) CreateInvites(ctx context.Context, invites []domain.Organiz
 }
 
 func (r *repository) UpsertBillingPreferences(ctx context.Context, prefs domain.OrganizationBillingPreferences) error {
+	// An empty schedule keeps the stored one; new rows start from the default.
+	overrideDunning := len(prefs.DunningDays) > 0
+	dunningDays := prefs.DunningDays
+	if !overrideDunning {
+		encoded, err := json.Marshal(domain.DefaultDunningDays)
+		if err != nil {
+			return err
+		}
+		dunningDays = encoded
+	}
+	// Likewise a zero SLA window keeps the stored one.
+	overrideSLA := prefs.AssignmentSLAMinutes > 0
+	slaMinutes := prefs.AssignmentSLAMinutes
+	if !overrideSLA {
+		slaMinutes = domain.DefaultAssignmentSLAMinutes
+	}
+	overrideWeights := len(prefs.ScoringWeights) > 0
+	overrideRounding := prefs.RoundingMode != ""
+	roundingMode := prefs.RoundingMode
+	if !overrideRounding {
+		roundingMode = domain.DefaultRoundingMode
+	}
 	return r.db.WithContext(ctx).Exec(
-		`INSERT INTO organization_billing_preferences (org_id, currency, timezone, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?)
+		`INSERT INTO organization_billing_preferences (org_id, currency, timezone, dunning_days, assignment_sla_minutes, rounding_mode, scoring_weights, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT (org_id)
 		 DO UPDATE SET currency = EXCLUDED.currency,
 		               timezone = EXCLUDED.timezone,
+		               dunning_days = CASE WHEN ? THEN EXCLUDED.dunning_days ELSE organization_billing_preferences.dunning_days END,
+		               assignment_sla_minutes = CASE WHEN ? THEN EXCLUDED.assignment_sla_minutes ELSE organization_billing_preferences.assignment_sla_minutes END,
+		               rounding_mode = CASE WHEN ? THEN EXCLUDED.rounding_mode ELSE organization_billing_preferences.rounding_mode END,
+		               scoring_weights = CASE WHEN ? THEN EXCLUDED.scoring_weights ELSE organization_billing_preferences.scoring_weights END,
 		               updated_at = EXCLUDED.updated_at`,
 		prefs.OrgID,
 		prefs.Currency,
 		prefs.Timezone,
+		dunningDays,
+		slaMinutes,
+		roundingMode,
+		prefs.ScoringWeights,
 		prefs.CreatedAt,
 		prefs.UpdatedAt,
+		overrideDunning,
+		overrideSLA,
+		overrideRounding,
+		overrideWeights,
 	).Error
 }
 
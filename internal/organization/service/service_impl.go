@@ -16,6 +16,7 @@ import (
 	"github.com/railzwaylabs/railzway/internal/providers/email"
 	referencedomain "github.com/railzwaylabs/railzway/internal/reference/domain"
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -474,13 +475,56 @@ func (s *service) SetBillingPreferences(ctx context.Context, userID snowflake.ID
 		return domain.ErrInvalidTimezone
 	}
 
+	var dunningDays datatypes.JSON
+	if req.DunningDays != nil {
+		days, err := domain.NormalizeDunningDays(req.DunningDays)
+		if err != nil {
+			return err
+		}
+		dunningDays, err = json.Marshal(days)
+		if err != nil {
+			return err
+		}
+	}
+
+	var slaMinutes int
+	if req.AssignmentSLAMinutes != nil {
+		slaMinutes = *req.AssignmentSLAMinutes
+		if slaMinutes < domain.MinAssignmentSLAMinutes || slaMinutes > domain.MaxAssignmentSLAMinutes {
+			return domain.ErrInvalidSLAWindow
+		}
+	}
+
+	var roundingMode domain.RoundingMode
+	if req.RoundingMode != nil {
+		roundingMode = domain.RoundingMode(strings.ToLower(strings.TrimSpace(string(*req.RoundingMode))))
+		if !roundingMode.Valid() {
+			return domain.ErrInvalidRoundingMode
+		}
+	}
+
+	var scoringWeights datatypes.JSON
+	if req.ScoringWeights != nil {
+		if !req.ScoringWeights.Valid() {
+			return domain.ErrInvalidScoringWeights
+		}
+		scoringWeights, err = json.Marshal(req.ScoringWeights)
+		if err != nil {
+			return err
+		}
+	}
+
 	now := time.Now().UTC()
 	return s.repo.UpsertBillingPreferences(ctx, domain.OrganizationBillingPreferences{
-		OrgID:     org.ID,
-		Currency:  currency,
-		Timezone:  timezone,
-		CreatedAt: now,
-		UpdatedAt: now,
+		OrgID:                org.ID,
+		Currency:             currency,
+		Timezone:             timezone,
+		DunningDays:          dunningDays,
+		AssignmentSLAMinutes: slaMinutes,
+		RoundingMode:         roundingMode,
+		ScoringWeights:       scoringWeights,
+		CreatedAt:            now,
+		UpdatedAt:            now,
 	})
 }
 
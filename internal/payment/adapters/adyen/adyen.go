@@ -270,6 +270,11 @@ func (a *Adapter) RetrieveCheckoutSession(ctx context.Context, providerSessionID
 	return nil, paymentdomain.ErrInvalidProvider
 }
 
+// Refund (stub - not implemented for Adyen yet)
+func (a *Adapter) Refund(ctx context.Context, input paymentdomain.RefundRequest) (*paymentdomain.ProviderRefund, error) {
+	return nil, paymentdomain.ErrInvalidProvider
+}
+
 
 func readString(config map[string]any, key string) (string, bool) {
 	val, ok := config[key]
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/currency"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 )
 
@@ -147,15 +148,15 @@ func (a *Adapter) Parse(ctx context.Context, payload []byte) (*paymentdomain.Pay
 	// Amount extraction (simplified, assuming <amount>10.00</amount>)
 	amountStr := extractXMLTag(sXml, "amount")
 	amountFloat, _ := strconv.ParseFloat(amountStr, 64)
-	amount := int64(amountFloat * 100) // Convert standard units to cents (minor units)
 
 	// Currency
-	currency := "USD" // Default if not found
+	currencyCode := "USD" // Default if not found
 	// Braintree often configured per merchant account, but let's try to find it
 	// <currency-iso-code>USD</currency-iso-code>
 	if foundCurr := extractXMLTag(sXml, "currency-iso-code"); foundCurr != "" {
-		currency = foundCurr
+		currencyCode = foundCurr
 	}
+	amount := currency.ToMinorUnits(amountFloat, currencyCode)
 	
 	// Timestamp defaults to now as Braintree XML is heavy to parse actual event time without struct
 	occurredAt := time.Now().UTC()
@@ -169,7 +170,7 @@ func (a *Adapter) Parse(ctx context.Context, payload []byte) (*paymentdomain.Pay
 		OrgID:               a.orgID,
 		CustomerID:          customerID,
 		Amount:              amount,
-		Currency:            strings.ToUpper(currency),
+		Currency:            strings.ToUpper(currencyCode),
 		OccurredAt:          occurredAt,
 		RawPayload:          payload,
 	}, nil
@@ -221,6 +222,11 @@ func (a *Adapter) RetrieveCheckoutSession(ctx context.Context, providerSessionID
 	return nil, paymentdomain.ErrInvalidProvider
 }
 
+// Refund (stub - not implemented for Braintree yet)
+func (a *Adapter) Refund(ctx context.Context, input paymentdomain.RefundRequest) (*paymentdomain.ProviderRefund, error) {
+	return nil, paymentdomain.ErrInvalidProvider
+}
+
 func readString(config map[string]any, key string) (string, bool) {
 	val, ok := config[key]
 	if !ok {
@@ -0,0 +1,515 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/currency"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+)
+
+const (
+	liveBaseURL    = "https://api-m.paypal.com"
+	sandboxBaseURL = "https://api-m.sandbox.paypal.com"
+
+	// orderApprovalWindow is how long PayPal keeps an unapproved order payable.
+	orderApprovalWindow = 3 * time.Hour
+)
+
+// Factory creates PayPal adapters
+type Factory struct{}
+
+func NewFactory() *Factory {
+	return &Factory{}
+}
+
+func (f *Factory) Provider() string {
+	return "paypal"
+}
+
+func (f *Factory) NewAdapter(cfg paymentdomain.AdapterConfig) (paymentdomain.PaymentAdapter, error) {
+	clientID, ok := readString(cfg.Config, "client_id")
+	if !ok || strings.TrimSpace(clientID) == "" {
+		return nil, paymentdomain.ErrInvalidConfig
+	}
+
+	clientSecret, ok := readString(cfg.Config, "client_secret")
+	if !ok || strings.TrimSpace(clientSecret) == "" {
+		return nil, paymentdomain.ErrInvalidConfig
+	}
+
+	webhookID, ok := readString(cfg.Config, "webhook_id")
+	if !ok || strings.TrimSpace(webhookID) == "" {
+		return nil, paymentdomain.ErrInvalidConfig
+	}
+
+	baseURL := liveBaseURL
+	if environment, ok := readString(cfg.Config, "environment"); ok {
+		switch strings.ToLower(strings.TrimSpace(environment)) {
+		case "", "live":
+		case "sandbox":
+			baseURL = sandboxBaseURL
+		default:
+			return nil, paymentdomain.ErrInvalidConfig
+		}
+	}
+
+	return &Adapter{
+		orgID:        cfg.OrgID,
+		clientID:     strings.TrimSpace(clientID),
+		clientSecret: strings.TrimSpace(clientSecret),
+		webhookID:    strings.TrimSpace(webhookID),
+		baseURL:      baseURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Adapter implements PaymentAdapter for PayPal
+type Adapter struct {
+	orgID        snowflake.ID
+	clientID     string
+	clientSecret string
+	webhookID    string
+	baseURL      string
+	client       *http.Client
+}
+
+// Verify checks the webhook transmission headers against PayPal's
+// verify-webhook-signature API.
+// Reference: https://developer.paypal.com/api/rest/webhooks/rest/#verify-webhook-signature
+func (a *Adapter) Verify(ctx context.Context, payload []byte, headers http.Header) error {
+	transmission := map[string]string{
+		"auth_algo":         strings.TrimSpace(headers.Get("Paypal-Auth-Algo")),
+		"cert_url":          strings.TrimSpace(headers.Get("Paypal-Cert-Url")),
+		"transmission_id":   strings.TrimSpace(headers.Get("Paypal-Transmission-Id")),
+		"transmission_sig":  strings.TrimSpace(headers.Get("Paypal-Transmission-Sig")),
+		"transmission_time": strings.TrimSpace(headers.Get("Paypal-Transmission-Time")),
+	}
+	for _, value := range transmission {
+		if value == "" {
+			return paymentdomain.ErrInvalidSignature
+		}
+	}
+
+	if !json.Valid(payload) {
+		return paymentdomain.ErrInvalidPayload
+	}
+
+	reqBody := map[string]any{
+		"webhook_id":    a.webhookID,
+		"webhook_event": json.RawMessage(payload),
+	}
+	for key, value := range transmission {
+		reqBody[key] = value
+	}
+
+	var result struct {
+		VerificationStatus string `json:"verification_status"`
+	}
+	if err := a.doJSON(ctx, http.MethodPost, "/v1/notifications/verify-webhook-signature", reqBody, &result); err != nil {
+		return err
+	}
+
+	if result.VerificationStatus != "SUCCESS" {
+		return paymentdomain.ErrInvalidSignature
+	}
+	return nil
+}
+
+// Parse parses PayPal webhook payload
+func (a *Adapter) Parse(ctx context.Context, payload []byte) (*paymentdomain.PaymentEvent, error) {
+	var event paypalEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, paymentdomain.ErrInvalidPayload
+	}
+
+	if strings.TrimSpace(event.ID) == "" {
+		return nil, paymentdomain.ErrInvalidEvent
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(event.EventType)) {
+	case "PAYMENT.CAPTURE.COMPLETED":
+		return a.parseCapture(event, paymentdomain.EventTypePaymentSucceeded, payload)
+	case "PAYMENT.CAPTURE.DENIED":
+		return a.parseCapture(event, paymentdomain.EventTypePaymentFailed, payload)
+	default:
+		return nil, paymentdomain.ErrEventIgnored
+	}
+}
+
+// AttachPaymentMethod (stub - PayPal vaulting is not supported yet)
+func (a *Adapter) AttachPaymentMethod(ctx context.Context, customerProviderID, token string) (*paymentdomain.PaymentMethodDetails, error) {
+	return nil, paymentdomain.ErrInvalidProvider
+}
+
+// DetachPaymentMethod (stub - PayPal vaulting is not supported yet)
+func (a *Adapter) DetachPaymentMethod(ctx context.Context, paymentMethodID string) error {
+	return paymentdomain.ErrInvalidProvider
+}
+
+// GetPaymentMethod (stub - PayPal vaulting is not supported yet)
+func (a *Adapter) GetPaymentMethod(ctx context.Context, paymentMethodID string) (*paymentdomain.PaymentMethodDetails, error) {
+	return nil, paymentdomain.ErrInvalidProvider
+}
+
+// ListPaymentMethods (stub - PayPal vaulting is not supported yet)
+func (a *Adapter) ListPaymentMethods(ctx context.Context, customerProviderID string) ([]*paymentdomain.PaymentMethodDetails, error) {
+	return nil, paymentdomain.ErrInvalidProvider
+}
+
+// CreateCheckoutSession creates a PayPal order the customer approves on PayPal.
+// The internal customer and invoice IDs travel in the purchase unit custom_id
+// so capture webhooks can be correlated.
+func (a *Adapter) CreateCheckoutSession(ctx context.Context, input paymentdomain.CheckoutSessionInput) (*paymentdomain.ProviderCheckoutSession, error) {
+	currencyCode := strings.ToUpper(strings.TrimSpace(input.Currency))
+	if currencyCode == "" {
+		return nil, paymentdomain.ErrInvalidCurrency
+	}
+	if input.Amount <= 0 {
+		return nil, paymentdomain.ErrInvalidAmount
+	}
+
+	purchaseUnit := map[string]any{
+		"amount": map[string]any{
+			"currency_code": currencyCode,
+			"value":         formatAmount(input.Amount, currencyCode),
+		},
+		"description": "Payment", // Generic description
+	}
+	if customID := buildCustomID(input); customID != "" {
+		purchaseUnit["custom_id"] = customID
+	}
+	if input.ClientReferenceID != "" {
+		purchaseUnit["reference_id"] = input.ClientReferenceID
+	}
+
+	reqBody := map[string]any{
+		"intent":         "CAPTURE",
+		"purchase_units": []any{purchaseUnit},
+		"application_context": map[string]any{
+			"return_url":  input.SuccessURL,
+			"cancel_url":  input.CancelURL,
+			"user_action": "PAY_NOW",
+		},
+	}
+
+	// Call PayPal API: POST /v2/checkout/orders
+	var order paypalOrder
+	if err := a.doJSON(ctx, http.MethodPost, "/v2/checkout/orders", reqBody, &order); err != nil {
+		return nil, err
+	}
+
+	return a.toCheckoutSession(order, time.Now().UTC().Add(orderApprovalWindow)), nil
+}
+
+// RetrieveCheckoutSession retrieves an order from PayPal
+func (a *Adapter) RetrieveCheckoutSession(ctx context.Context, providerSessionID string) (*paymentdomain.ProviderCheckoutSession, error) {
+	providerSessionID = strings.TrimSpace(providerSessionID)
+	if providerSessionID == "" {
+		return nil, paymentdomain.ErrInvalidCheckoutSession
+	}
+
+	// Call PayPal API: GET /v2/checkout/orders/{id}
+	var order paypalOrder
+	if err := a.doJSON(ctx, http.MethodGet, "/v2/checkout/orders/"+url.PathEscape(providerSessionID), nil, &order); err != nil {
+		return nil, err
+	}
+
+	createdAt, _ := time.Parse(time.RFC3339, order.CreateTime)
+	if createdAt.IsZero() {
+		createdAt = time.Now().UTC()
+	}
+	return a.toCheckoutSession(order, createdAt.Add(orderApprovalWindow)), nil
+}
+
+// Refund (stub - PayPal refund webhooks are not handled yet)
+func (a *Adapter) Refund(ctx context.Context, input paymentdomain.RefundRequest) (*paymentdomain.ProviderRefund, error) {
+	return nil, paymentdomain.ErrInvalidProvider
+}
+
+func (a *Adapter) toCheckoutSession(order paypalOrder, expiresAt time.Time) *paymentdomain.ProviderCheckoutSession {
+	status := paymentdomain.CheckoutSessionStatusOpen
+	switch strings.ToUpper(order.Status) {
+	case "COMPLETED":
+		status = paymentdomain.CheckoutSessionStatusComplete
+	case "VOIDED":
+		status = paymentdomain.CheckoutSessionStatusExpired
+	}
+
+	var approveURL string
+	for _, link := range order.Links {
+		if link.Rel == "approve" || link.Rel == "payer-action" {
+			approveURL = link.Href
+			break
+		}
+	}
+
+	var captureID string
+	for _, unit := range order.PurchaseUnits {
+		if len(unit.Payments.Captures) > 0 {
+			captureID = unit.Payments.Captures[0].ID
+			break
+		}
+	}
+
+	return &paymentdomain.ProviderCheckoutSession{
+		ID:              order.ID,
+		Provider:        "paypal",
+		URL:             approveURL,
+		Status:          status,
+		ExpiresAt:       expiresAt,
+		PaymentIntentID: captureID,
+	}
+}
+
+// paypalEvent represents a PayPal webhook event
+type paypalEvent struct {
+	ID           string          `json:"id"`
+	EventType    string          `json:"event_type"`
+	CreateTime   string          `json:"create_time"`
+	ResourceType string          `json:"resource_type"`
+	Resource     json.RawMessage `json:"resource"`
+}
+
+type paypalAmount struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+type paypalCapture struct {
+	ID                string       `json:"id"`
+	Status            string       `json:"status"`
+	Amount            paypalAmount `json:"amount"`
+	CustomID          string       `json:"custom_id"`
+	CreateTime        string       `json:"create_time"`
+	SupplementaryData struct {
+		RelatedIDs struct {
+			OrderID string `json:"order_id"`
+		} `json:"related_ids"`
+	} `json:"supplementary_data"`
+}
+
+type paypalOrder struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	CreateTime    string `json:"create_time"`
+	PurchaseUnits []struct {
+		CustomID string `json:"custom_id"`
+		Payments struct {
+			Captures []paypalCapture `json:"captures"`
+		} `json:"payments"`
+	} `json:"purchase_units"`
+	Links []struct {
+		Href string `json:"href"`
+		Rel  string `json:"rel"`
+	} `json:"links"`
+}
+
+func (a *Adapter) parseCapture(event paypalEvent, eventType string, payload []byte) (*paymentdomain.PaymentEvent, error) {
+	var capture paypalCapture
+	if err := json.Unmarshal(event.Resource, &capture); err != nil {
+		return nil, paymentdomain.ErrInvalidPayload
+	}
+
+	customerID, invoiceID, err := parseCustomID(capture.CustomID)
+	if err != nil {
+		return nil, paymentdomain.ErrInvalidCustomer
+	}
+
+	currencyCode := strings.ToUpper(strings.TrimSpace(capture.Amount.CurrencyCode))
+	if currencyCode == "" {
+		return nil, paymentdomain.ErrInvalidCurrency
+	}
+	amount, err := parseAmount(capture.Amount.Value, currencyCode)
+	if err != nil {
+		return nil, paymentdomain.ErrInvalidAmount
+	}
+
+	// Checkout sessions are keyed by the order, so the order ID is the
+	// payment reference. Captures made outside the orders flow fall back to
+	// the capture ID.
+	providerPaymentID := strings.TrimSpace(capture.SupplementaryData.RelatedIDs.OrderID)
+	if providerPaymentID == "" {
+		providerPaymentID = capture.ID
+	}
+	if providerPaymentID == "" {
+		return nil, paymentdomain.ErrInvalidEvent
+	}
+
+	occurredAt, _ := time.Parse(time.RFC3339, event.CreateTime)
+	if occurredAt.IsZero() {
+		occurredAt, _ = time.Parse(time.RFC3339, capture.CreateTime)
+	}
+	if occurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+
+	return &paymentdomain.PaymentEvent{
+		Provider:            "paypal",
+		ProviderEventID:     event.ID,
+		ProviderPaymentID:   providerPaymentID,
+		ProviderPaymentType: "paypal_order",
+		Type:                eventType,
+		OrgID:               a.orgID,
+		CustomerID:          customerID,
+		Amount:              amount,
+		Currency:            currencyCode,
+		OccurredAt:          occurredAt.UTC(),
+		RawPayload:          payload,
+		InvoiceID:           invoiceID,
+	}, nil
+}
+
+// doJSON sends an authenticated request to the PayPal REST API and decodes the
+// JSON response into out.
+func (a *Adapter) doJSON(ctx context.Context, method, path string, body any, out any) error {
+	token, err := a.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("paypal api error: %d body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// accessToken exchanges the client credentials for an OAuth access token.
+func (a *Adapter) accessToken(ctx context.Context) (string, error) {
+	if a.clientID == "" || a.clientSecret == "" {
+		return "", errors.New("paypal client credentials not configured")
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "client_credentials")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/v1/oauth2/token", strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(a.clientID, a.clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("paypal auth error: %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("paypal auth error: empty access token")
+	}
+	return token.AccessToken, nil
+}
+
+// buildCustomID encodes the internal IDs as "customer_{customerID}_invoice_{invoiceID}".
+func buildCustomID(input paymentdomain.CheckoutSessionInput) string {
+	if input.CustomerID == 0 {
+		return ""
+	}
+	customID := "customer_" + input.CustomerID.String()
+	if invoiceID := strings.TrimSpace(input.Metadata["invoice_id"]); invoiceID != "" {
+		customID += "_invoice_" + invoiceID
+	}
+	return customID
+}
+
+// parseCustomID extracts customer_id and invoice_id from custom_id
+// Format: "customer_{customerID}_invoice_{invoiceID}"
+func parseCustomID(customID string) (snowflake.ID, *snowflake.ID, error) {
+	parts := strings.Split(strings.TrimSpace(customID), "_")
+
+	var customerIDStr, invoiceIDStr string
+	for i := 0; i+1 < len(parts); i++ {
+		switch parts[i] {
+		case "customer":
+			customerIDStr = parts[i+1]
+		case "invoice":
+			invoiceIDStr = parts[i+1]
+		}
+	}
+
+	if customerIDStr == "" {
+		return 0, nil, errors.New("customer_id not found in custom_id")
+	}
+	customerID, err := snowflake.ParseString(customerIDStr)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid customer_id: %w", err)
+	}
+
+	if invoiceIDStr == "" {
+		return customerID, nil, nil
+	}
+	invoiceID, err := snowflake.ParseString(invoiceIDStr)
+	if err != nil {
+		return customerID, nil, nil // Invoice ID is optional
+	}
+	return customerID, &invoiceID, nil
+}
+
+// formatAmount renders minor units as the decimal string PayPal expects.
+func formatAmount(amount int64, code string) string {
+	return strconv.FormatFloat(currency.ToMajorUnits(amount, code), 'f', currency.Decimals(code), 64)
+}
+
+// parseAmount converts a PayPal decimal amount into minor units.
+func parseAmount(value, code string) (int64, error) {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || parsed < 0 {
+		return 0, paymentdomain.ErrInvalidAmount
+	}
+	return currency.ToMinorUnits(parsed, code), nil
+}
+
+func readString(config map[string]any, key string) (string, bool) {
+	value, ok := config[key]
+	if !ok {
+		return "", false
+	}
+	str, ok := value.(string)
+	return str, ok
+}
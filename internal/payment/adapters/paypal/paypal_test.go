@@ -0,0 +1,223 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bwmarrin/snowflake"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+)
+
+func newTestAdapter(t *testing.T, handler http.HandlerFunc) *Adapter {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "client" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token"})
+	})
+	mux.HandleFunc("/", handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return &Adapter{
+		orgID:        1,
+		clientID:     "client",
+		clientSecret: "secret",
+		webhookID:    "WH-1",
+		baseURL:      server.URL,
+		client:       server.Client(),
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte(`{"id":"WH-EVT-1","event_type":"PAYMENT.CAPTURE.COMPLETED","resource":{}}`)
+	status := "SUCCESS"
+
+	adapter := newTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/notifications/verify-webhook-signature" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if body["webhook_id"] != "WH-1" || body["transmission_sig"] != "sig" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"verification_status": status})
+	})
+
+	headers := http.Header{}
+	headers.Set("PAYPAL-AUTH-ALGO", "SHA256withRSA")
+	headers.Set("PAYPAL-CERT-URL", "https://api.paypal.com/v1/notifications/certs/CERT")
+	headers.Set("PAYPAL-TRANSMISSION-ID", "tx-1")
+	headers.Set("PAYPAL-TRANSMISSION-SIG", "sig")
+	headers.Set("PAYPAL-TRANSMISSION-TIME", "2026-01-01T00:00:00Z")
+
+	if err := adapter.Verify(context.Background(), payload, headers); err != nil {
+		t.Fatalf("expected valid signature, got error: %v", err)
+	}
+
+	status = "FAILURE"
+	if err := adapter.Verify(context.Background(), payload, headers); !errors.Is(err, paymentdomain.ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature error, got %v", err)
+	}
+
+	headers.Del("PAYPAL-TRANSMISSION-SIG")
+	if err := adapter.Verify(context.Background(), payload, headers); !errors.Is(err, paymentdomain.ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature error for missing header, got %v", err)
+	}
+}
+
+func TestParseCaptureEvents(t *testing.T) {
+	node, err := snowflake.NewNode(1)
+	if err != nil {
+		t.Fatalf("new node: %v", err)
+	}
+	customerID := node.Generate()
+	invoiceID := node.Generate()
+
+	tests := []struct {
+		eventType string
+		wantType  string
+	}{
+		{eventType: "PAYMENT.CAPTURE.COMPLETED", wantType: paymentdomain.EventTypePaymentSucceeded},
+		{eventType: "PAYMENT.CAPTURE.DENIED", wantType: paymentdomain.EventTypePaymentFailed},
+	}
+
+	adapter := &Adapter{orgID: 1}
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			payload, _ := json.Marshal(map[string]any{
+				"id":          "WH-EVT-1",
+				"event_type":  tt.eventType,
+				"create_time": "2026-01-01T00:00:00Z",
+				"resource": map[string]any{
+					"id":        "CAPTURE-1",
+					"custom_id": "customer_" + customerID.String() + "_invoice_" + invoiceID.String(),
+					"amount":    map[string]any{"currency_code": "USD", "value": "25.10"},
+					"supplementary_data": map[string]any{
+						"related_ids": map[string]any{"order_id": "ORDER-1"},
+					},
+				},
+			})
+
+			event, err := adapter.Parse(context.Background(), payload)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if event.Type != tt.wantType {
+				t.Fatalf("expected type %s, got %s", tt.wantType, event.Type)
+			}
+			if event.ProviderPaymentID != "ORDER-1" {
+				t.Fatalf("expected order id as provider payment id, got %s", event.ProviderPaymentID)
+			}
+			if event.CustomerID != customerID {
+				t.Fatalf("expected customer %s, got %s", customerID, event.CustomerID)
+			}
+			if event.InvoiceID == nil || *event.InvoiceID != invoiceID {
+				t.Fatalf("expected invoice %s, got %v", invoiceID, event.InvoiceID)
+			}
+			if event.Amount != 2510 || event.Currency != "USD" {
+				t.Fatalf("expected 2510 USD, got %d %s", event.Amount, event.Currency)
+			}
+		})
+	}
+
+	if _, err := adapter.Parse(context.Background(), []byte(`{"id":"WH-EVT-2","event_type":"CHECKOUT.ORDER.APPROVED"}`)); !errors.Is(err, paymentdomain.ErrEventIgnored) {
+		t.Fatalf("expected ignored event, got %v", err)
+	}
+}
+
+func TestCreateCheckoutSession(t *testing.T) {
+	customerID := snowflake.ID(42)
+	var order map[string]any
+
+	adapter := newTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/checkout/orders" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&order)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":     "ORDER-1",
+			"status": "CREATED",
+			"links": []map[string]any{
+				{"href": "https://www.paypal.com/checkoutnow?token=ORDER-1", "rel": "approve"},
+			},
+		})
+	})
+
+	session, err := adapter.CreateCheckoutSession(context.Background(), paymentdomain.CheckoutSessionInput{
+		CustomerID: customerID,
+		Currency:   "usd",
+		Amount:     1999,
+		SuccessURL: "https://example.com/success",
+		CancelURL:  "https://example.com/cancel",
+		Metadata:   map[string]string{"invoice_id": "77"},
+	})
+	if err != nil {
+		t.Fatalf("create checkout session: %v", err)
+	}
+	if session.ID != "ORDER-1" || session.Provider != "paypal" {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+	if session.URL != "https://www.paypal.com/checkoutnow?token=ORDER-1" {
+		t.Fatalf("expected approve url, got %s", session.URL)
+	}
+	if session.Status != paymentdomain.CheckoutSessionStatusOpen {
+		t.Fatalf("expected open session, got %s", session.Status)
+	}
+
+	units, _ := order["purchase_units"].([]any)
+	if len(units) != 1 {
+		t.Fatalf("expected one purchase unit, got %v", order["purchase_units"])
+	}
+	unit := units[0].(map[string]any)
+	if unit["custom_id"] != "customer_42_invoice_77" {
+		t.Fatalf("unexpected custom_id: %v", unit["custom_id"])
+	}
+	amount := unit["amount"].(map[string]any)
+	if amount["value"] != "19.99" || amount["currency_code"] != "USD" {
+		t.Fatalf("unexpected amount: %v", amount)
+	}
+}
+
+func TestAmountsUseCurrencyMinorUnits(t *testing.T) {
+	cases := []struct {
+		currency string
+		amount   int64
+		value    string
+	}{
+		{currency: "USD", amount: 1999, value: "19.99"},
+		{currency: "JPY", amount: 1500, value: "1500"},
+		{currency: "BHD", amount: 12345, value: "12.345"},
+	}
+	for _, tc := range cases {
+		if got := formatAmount(tc.amount, tc.currency); got != tc.value {
+			t.Fatalf("expected %d %s to format as %q, got %q", tc.amount, tc.currency, tc.value, got)
+		}
+		got, err := parseAmount(tc.value, tc.currency)
+		if err != nil {
+			t.Fatalf("parse %s %s: %v", tc.value, tc.currency, err)
+		}
+		if got != tc.amount {
+			t.Fatalf("expected %s %s to parse as %d, got %d", tc.value, tc.currency, tc.amount, got)
+		}
+	}
+}
@@ -9,17 +9,25 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/currency"
 	disputedomain "github.com/railzwaylabs/railzway/internal/payment/dispute/domain"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	"go.uber.org/zap"
 )
 
+// DefaultWebhookTolerance is how old a webhook signature timestamp may be
+// before the event is rejected as a replay, matching Stripe's libraries.
+const DefaultWebhookTolerance = 5 * time.Minute
+
 type Factory struct{}
 
 func NewFactory() *Factory {
@@ -46,17 +54,66 @@ func (f *Factory) NewAdapter(cfg paymentdomain.AdapterConfig) (paymentdomain.Pay
 		apiKey = "" // API key is optional for webhook-only usage
 	}
 
+	// Platforms on Stripe Connect route calls to a connected account.
+	accountID, _ := readString(cfg.Config, "stripe_account_id")
+
+	tolerance := DefaultWebhookTolerance
+	if seconds, ok := readNumber(cfg.Config, "webhook_tolerance_seconds"); ok {
+		if seconds <= 0 {
+			return nil, paymentdomain.ErrInvalidConfig
+		}
+		tolerance = time.Duration(seconds * float64(time.Second))
+	}
+
+	log := cfg.Log
+	if log == nil {
+		log = zap.NewNop()
+	}
+
 	return &Adapter{
-		orgID:         cfg.OrgID,
-		webhookSecret: secret,
-		apiKey:        strings.TrimSpace(apiKey),
+		orgID:            cfg.OrgID,
+		webhookSecret:    secret,
+		webhookTolerance: tolerance,
+		apiKey:           strings.TrimSpace(apiKey),
+		accountID:        strings.TrimSpace(accountID),
+		log:              log.Named("stripe"),
 	}, nil
 }
 
 type Adapter struct {
 	orgID         snowflake.ID
 	webhookSecret string
-	apiKey        string
+	// webhookTolerance bounds the age of a signature's timestamp; zero means
+	// DefaultWebhookTolerance.
+	webhookTolerance time.Duration
+	apiKey           string
+	// accountID is the Stripe Connect account API calls act on; empty means
+	// the platform account itself.
+	accountID string
+	log       *zap.Logger
+}
+
+// authorize sets the headers every Stripe API request carries.
+func (a *Adapter) authorize(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	if a.accountID != "" {
+		req.Header.Set("Stripe-Account", a.accountID)
+	}
+}
+
+func (a *Adapter) logger() *zap.Logger {
+	if a.log == nil {
+		return zap.NewNop()
+	}
+	return a.log
+}
+
+func (a *Adapter) eventFields(event stripeEvent, fields ...zap.Field) []zap.Field {
+	return append([]zap.Field{
+		zap.String("event_id", event.ID),
+		zap.String("event_type", event.Type),
+		zap.String("org_id", a.orgID.String()),
+	}, fields...)
 }
 
 func (a *Adapter) Verify(ctx context.Context, payload []byte, headers http.Header) error {
@@ -70,6 +127,20 @@ func (a *Adapter) Verify(ctx context.Context, payload []byte, headers http.Heade
 		return paymentdomain.ErrInvalidSignature
 	}
 
+	// The timestamp is part of the signed payload, so an old one cannot be
+	// swapped for a fresh one; rejecting it stops replays of captured events.
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return paymentdomain.ErrInvalidSignature
+	}
+	tolerance := a.webhookTolerance
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	if time.Since(time.Unix(signedAt, 0)) > tolerance {
+		return paymentdomain.ErrInvalidSignature
+	}
+
 	signedPayload := fmt.Sprintf("%s.%s", timestamp, string(payload))
 	mac := hmac.New(sha256.New, []byte(a.webhookSecret))
 	_, _ = mac.Write([]byte(signedPayload))
@@ -93,8 +164,7 @@ func (a *Adapter) Parse(ctx context.Context, payload []byte) (*paymentdomain.Pay
 		return nil, paymentdomain.ErrInvalidEvent
 	}
 
-	// Log event type for debugging
-	fmt.Printf("[STRIPE] Processing event: type=%s, id=%s\n", event.Type, event.ID)
+	a.logger().Debug("processing stripe event", a.eventFields(event)...)
 
 	switch strings.TrimSpace(event.Type) {
 	case "payment_intent.succeeded":
@@ -108,7 +178,7 @@ func (a *Adapter) Parse(ctx context.Context, payload []byte) (*paymentdomain.Pay
 	case "charge.refunded":
 		return a.parseCharge(event, payload, paymentdomain.EventTypeRefunded)
 	default:
-		fmt.Printf("[STRIPE] Unhandled event type: %s (event_id=%s)\n", event.Type, event.ID)
+		a.logger().Debug("unhandled stripe event", a.eventFields(event)...)
 		return nil, paymentdomain.ErrEventIgnored
 	}
 }
@@ -144,7 +214,7 @@ func (a *Adapter) ParseDispute(ctx context.Context, payload []byte) (*disputedom
 		return nil, paymentdomain.ErrInvalidEvent
 	}
 
-	customerID, _, err := parseMetadataIDs(dispute.Metadata)
+	customerID, _, err := a.parseMetadataIDs(event, dispute.Metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -157,7 +227,7 @@ func (a *Adapter) ParseDispute(ctx context.Context, payload []byte) (*disputedom
 		Type:              disputeType,
 		OrgID:             a.orgID,
 		CustomerID:        customerID,
-		Amount:            dispute.Amount,
+		Amount:            fromStripeAmount(dispute.Amount, dispute.Currency),
 		Currency:          strings.ToUpper(strings.TrimSpace(dispute.Currency)),
 		Reason:            strings.TrimSpace(dispute.Reason),
 		OccurredAt:        occurredAt,
@@ -226,7 +296,7 @@ func (a *Adapter) parsePaymentIntent(event stripeEvent, payload []byte) (*paymen
 	if amount <= 0 {
 		amount = intent.Amount
 	}
-	customerID, invoiceID, err := parseMetadataIDs(intent.Metadata)
+	customerID, invoiceID, err := a.parseMetadataIDs(event, intent.Metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -240,7 +310,7 @@ func (a *Adapter) parsePaymentIntent(event stripeEvent, payload []byte) (*paymen
 		Type:                paymentdomain.EventTypePaymentSucceeded,
 		OrgID:               a.orgID,
 		CustomerID:          customerID,
-		Amount:              amount,
+		Amount:              fromStripeAmount(amount, intent.Currency),
 		Currency:            strings.ToUpper(strings.TrimSpace(intent.Currency)),
 		OccurredAt:          occurredAt,
 		RawPayload:          payload,
@@ -254,7 +324,7 @@ func (a *Adapter) parsePaymentIntentFailed(event stripeEvent, payload []byte) (*
 		return nil, paymentdomain.ErrInvalidPayload
 	}
 
-	customerID, invoiceID, err := parseMetadataIDs(intent.Metadata)
+	customerID, invoiceID, err := a.parseMetadataIDs(event, intent.Metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -268,7 +338,7 @@ func (a *Adapter) parsePaymentIntentFailed(event stripeEvent, payload []byte) (*
 		Type:                paymentdomain.EventTypePaymentFailed,
 		OrgID:               a.orgID,
 		CustomerID:          customerID,
-		Amount:              intent.Amount,
+		Amount:              fromStripeAmount(intent.Amount, intent.Currency),
 		Currency:            strings.ToUpper(strings.TrimSpace(intent.Currency)),
 		OccurredAt:          occurredAt,
 		RawPayload:          payload,
@@ -286,7 +356,7 @@ func (a *Adapter) parseCharge(event stripeEvent, payload []byte, eventType strin
 	if eventType == paymentdomain.EventTypeRefunded && charge.AmountRefunded > 0 {
 		amount = charge.AmountRefunded
 	}
-	customerID, invoiceID, err := parseMetadataIDs(charge.Metadata)
+	customerID, invoiceID, err := a.parseMetadataIDs(event, charge.Metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -300,7 +370,7 @@ func (a *Adapter) parseCharge(event stripeEvent, payload []byte, eventType strin
 		Type:                eventType,
 		OrgID:               a.orgID,
 		CustomerID:          customerID,
-		Amount:              amount,
+		Amount:              fromStripeAmount(amount, charge.Currency),
 		Currency:            strings.ToUpper(strings.TrimSpace(charge.Currency)),
 		OccurredAt:          occurredAt,
 		RawPayload:          payload,
@@ -325,13 +395,17 @@ func (a *Adapter) parseCheckoutSessionCompleted(event stripeEvent, payload []byt
 	if customerIDStr != "" {
 		customerID, err = snowflake.ParseString(customerIDStr)
 		if err != nil {
-			fmt.Printf("[STRIPE] Invalid customer_id format in checkout session: %s\n", customerIDStr)
+			a.logger().Warn("invalid customer_id in checkout session", a.eventFields(event,
+				zap.String("customer_id", customerIDStr),
+				zap.String("session_id", session.ID),
+			)...)
 			return nil, paymentdomain.ErrInvalidCustomer
 		}
 	} else {
-		// Log session details for debugging
-		fmt.Printf("[STRIPE] Missing customer_id in checkout session: client_reference_id=%s, metadata=%+v, session_id=%s\n",
-			session.ClientReferenceID, session.Metadata, session.ID)
+		a.logger().Warn("missing customer_id in checkout session", a.eventFields(event,
+			zap.String("session_id", session.ID),
+			zap.Strings("metadata_keys", metadataKeys(session.Metadata)),
+		)...)
 		return nil, paymentdomain.ErrInvalidCustomer
 	}
 
@@ -345,7 +419,7 @@ func (a *Adapter) parseCheckoutSessionCompleted(event stripeEvent, payload []byt
 		Type:                paymentdomain.EventTypeCheckoutSessionCompleted,
 		OrgID:               a.orgID,
 		CustomerID:          customerID,
-		Amount:              session.AmountTotal,
+		Amount:              fromStripeAmount(session.AmountTotal, session.Currency),
 		Currency:            strings.ToUpper(strings.TrimSpace(session.Currency)),
 		OccurredAt:          occurredAt,
 		RawPayload:          payload,
@@ -392,16 +466,19 @@ func timestamp(primary int64, fallback int64) time.Time {
 	return time.Unix(value, 0).UTC()
 }
 
-func parseMetadataIDs(metadata map[string]any) (snowflake.ID, *snowflake.ID, error) {
+func (a *Adapter) parseMetadataIDs(event stripeEvent, metadata map[string]any) (snowflake.ID, *snowflake.ID, error) {
 	customerRaw := readMetadataValue(metadata, "customer_id")
 	if customerRaw == "" {
-		// Log metadata for debugging
-		fmt.Printf("[STRIPE] Missing customer_id in metadata: %+v\n", metadata)
+		a.logger().Warn("missing customer_id in stripe metadata", a.eventFields(event,
+			zap.Strings("metadata_keys", metadataKeys(metadata)),
+		)...)
 		return 0, nil, paymentdomain.ErrInvalidCustomer
 	}
 	customerID, err := snowflake.ParseString(customerRaw)
 	if err != nil {
-		fmt.Printf("[STRIPE] Invalid customer_id format: %s\n", customerRaw)
+		a.logger().Warn("invalid customer_id in stripe metadata", a.eventFields(event,
+			zap.String("customer_id", customerRaw),
+		)...)
 		return 0, nil, paymentdomain.ErrInvalidCustomer
 	}
 
@@ -416,6 +493,16 @@ func parseMetadataIDs(metadata map[string]any) (snowflake.ID, *snowflake.ID, err
 	return customerID, &invoiceID, nil
 }
 
+// metadataKeys lists metadata keys without their values for logging.
+func metadataKeys(metadata map[string]any) []string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func readMetadataValue(metadata map[string]any, key string) string {
 	if metadata == nil {
 		return ""
@@ -459,7 +546,7 @@ func (a *Adapter) AttachPaymentMethod(ctx context.Context, customerProviderID, t
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	a.authorize(req)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	client := &http.Client{Timeout: 10 * time.Second}
@@ -505,7 +592,7 @@ func (a *Adapter) retrievePaymentMethod(ctx context.Context, id string) (*paymen
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	a.authorize(req)
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -542,7 +629,7 @@ func (a *Adapter) DetachPaymentMethod(ctx context.Context, paymentMethodID strin
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	a.authorize(req)
 
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
@@ -573,7 +660,7 @@ func (a *Adapter) CreateCheckoutSession(ctx context.Context, input paymentdomain
 	data.Set("cancel_url", input.CancelURL)
 	data.Set("line_items[0][price_data][currency]", strings.ToLower(input.Currency))
 	data.Set("line_items[0][price_data][product_data][name]", "Payment") // Generic name for now
-	data.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(input.Amount, 10))
+	data.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(ToStripeAmount(input.Amount, input.Currency), 10))
 	data.Set("line_items[0][quantity]", "1")
 	data.Set("payment_intent_data[setup_future_usage]", "off_session")
 
@@ -607,7 +694,7 @@ func (a *Adapter) CreateCheckoutSession(ctx context.Context, input paymentdomain
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	a.authorize(req)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	client := &http.Client{Timeout: 10 * time.Second}
@@ -662,7 +749,7 @@ func (a *Adapter) RetrieveCheckoutSession(ctx context.Context, providerSessionID
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	a.authorize(req)
 
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
@@ -729,7 +816,7 @@ func (a *Adapter) GetPaymentMethod(ctx context.Context, paymentMethodID string)
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	a.authorize(req)
 
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
@@ -770,7 +857,7 @@ func (a *Adapter) ListPaymentMethods(ctx context.Context, customerProviderID str
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	a.authorize(req)
 
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
@@ -819,6 +906,121 @@ type stripePaymentMethodList struct {
 	Data []stripePaymentMethod `json:"data"`
 }
 
+// Refund refunds a payment intent or charge. Stripe reports the result with
+// a charge.refunded webhook, which posts the refund to the ledger.
+func (a *Adapter) Refund(ctx context.Context, input paymentdomain.RefundRequest) (*paymentdomain.ProviderRefund, error) {
+	if a.apiKey == "" {
+		return nil, errors.New("stripe api key not configured")
+	}
+
+	// Call Stripe API: POST /v1/refunds
+	endpoint := "https://api.stripe.com/v1/refunds"
+
+	data := url.Values{}
+	switch input.ProviderPaymentType {
+	case "payment_intent":
+		data.Set("payment_intent", input.ProviderPaymentID)
+	case "charge":
+		data.Set("charge", input.ProviderPaymentID)
+	default:
+		return nil, paymentdomain.ErrInvalidPayment
+	}
+	data.Set("amount", strconv.FormatInt(ToStripeAmount(input.Amount, input.Currency), 10))
+	// Stripe only accepts a fixed set of reasons, so ours travels as metadata.
+	if input.Reason != "" {
+		data.Set("metadata[reason]", input.Reason)
+	}
+	if input.CustomerID != 0 {
+		data.Set("metadata[customer_id]", input.CustomerID.String())
+	}
+	if input.InvoiceID != nil && *input.InvoiceID != 0 {
+		data.Set("metadata[invoice_id]", input.InvoiceID.String())
+	}
+	data.Set("metadata[refund_id]", input.ReferenceID)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	a.authorize(req)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Idempotency-Key", input.ReferenceID)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("stripe api error: %d body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var refund struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&refund); err != nil {
+		return nil, err
+	}
+
+	return &paymentdomain.ProviderRefund{
+		ID:     refund.ID,
+		Status: refund.Status,
+	}, nil
+}
+
+// stripeDecimals lists currencies Stripe expresses with a different number of
+// decimals than we store. Stripe treats IDR as a two-decimal currency.
+var stripeDecimals = map[string]int{
+	"IDR": 2,
+}
+
+// ToStripeAmount converts an amount in our minor units of code into the
+// minor units Stripe expects.
+func ToStripeAmount(amount int64, code string) int64 {
+	return int64(math.Round(currency.ToMajorUnits(amount, code) * math.Pow10(stripeDecimalsFor(code))))
+}
+
+// fromStripeAmount converts an amount in Stripe's minor units of code into
+// ours.
+func fromStripeAmount(amount int64, code string) int64 {
+	return currency.ToMinorUnits(float64(amount)/math.Pow10(stripeDecimalsFor(code)), code)
+}
+
+func stripeDecimalsFor(code string) int {
+	if decimals, ok := stripeDecimals[strings.ToUpper(strings.TrimSpace(code))]; ok {
+		return decimals
+	}
+	return currency.Decimals(code)
+}
+
+func readNumber(config map[string]any, key string) (float64, bool) {
+	value, ok := config[key]
+	if !ok {
+		return 0, false
+	}
+	switch cast := value.(type) {
+	case float64:
+		return cast, true
+	case int:
+		return float64(cast), true
+	case int64:
+		return float64(cast), true
+	case json.Number:
+		parsed, err := cast.Float64()
+		return parsed, err == nil
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(cast), 64)
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
+
 func readString(config map[string]any, key string) (string, bool) {
 	value, ok := config[key]
 	if !ok {
@@ -13,6 +13,8 @@ import (
 
 	"github.com/bwmarrin/snowflake"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestVerifySignature(t *testing.T) {
@@ -35,6 +37,43 @@ func TestVerifySignature(t *testing.T) {
 	}
 }
 
+func TestVerifySignatureRejectsStaleTimestamp(t *testing.T) {
+	secret := "whsec_test"
+	payload := []byte(`{"id":"evt_123","type":"charge.succeeded","data":{"object":{}}}`)
+	reqHeader := http.Header{}
+
+	adapter := &Adapter{orgID: 1, webhookSecret: secret}
+	stale := time.Now().Add(-DefaultWebhookTolerance - time.Minute).Unix()
+	reqHeader.Set("Stripe-Signature", buildStripeSignatureHeader(secret, payload, stale))
+	if err := adapter.Verify(context.Background(), payload, reqHeader); err != paymentdomain.ErrInvalidSignature {
+		t.Fatalf("expected stale signature to be rejected, got %v", err)
+	}
+
+	factory := NewFactory()
+	configured, err := factory.NewAdapter(paymentdomain.AdapterConfig{
+		OrgID: 1,
+		Config: map[string]any{
+			"webhook_secret":            secret,
+			"webhook_tolerance_seconds": float64(600),
+		},
+	})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	if err := configured.Verify(context.Background(), payload, reqHeader); err != nil {
+		t.Fatalf("expected signature within configured tolerance, got %v", err)
+	}
+
+	// Any one of several v1 signatures may match, as during secret rotation.
+	fresh := time.Now().Unix()
+	valid := buildStripeSignatureHeader(secret, payload, fresh)
+	other := buildStripeSignatureHeader("whsec_old", payload, fresh)
+	reqHeader.Set("Stripe-Signature", other+","+valid[len(fmt.Sprintf("t=%d,", fresh)):])
+	if err := adapter.Verify(context.Background(), payload, reqHeader); err != nil {
+		t.Fatalf("expected one matching v1 signature to pass, got %v", err)
+	}
+}
+
 func TestParsePaymentEvent(t *testing.T) {
 	node, err := snowflake.NewNode(1)
 	if err != nil {
@@ -121,6 +160,93 @@ func TestParsePaymentEvent(t *testing.T) {
 	}
 }
 
+func TestParseLogsMissingCustomer(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	adapter, err := NewFactory().NewAdapter(paymentdomain.AdapterConfig{
+		OrgID:  7,
+		Config: map[string]any{"webhook_secret": "whsec_test"},
+		Log:    zap.New(core),
+	})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+
+	payload := []byte(`{"id":"evt_missing","type":"payment_intent.succeeded","data":{"object":{"id":"pi_1","metadata":{"invoice_id":"1"}}}}`)
+	if _, err := adapter.Parse(context.Background(), payload); err != paymentdomain.ErrInvalidCustomer {
+		t.Fatalf("expected invalid customer error, got %v", err)
+	}
+
+	warnings := logs.FilterLevelExact(zap.WarnLevel).All()
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(warnings))
+	}
+	fields := warnings[0].ContextMap()
+	if fields["event_id"] != "evt_missing" || fields["event_type"] != "payment_intent.succeeded" || fields["org_id"] != "7" {
+		t.Fatalf("unexpected log fields: %v", fields)
+	}
+
+	// Adapters built without a logger must not panic.
+	noLog, err := NewFactory().NewAdapter(paymentdomain.AdapterConfig{
+		Config: map[string]any{"webhook_secret": "whsec_test"},
+	})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	if _, err := noLog.Parse(context.Background(), payload); err != paymentdomain.ErrInvalidCustomer {
+		t.Fatalf("expected invalid customer error, got %v", err)
+	}
+}
+
+func TestAuthorizeSetsConnectedAccount(t *testing.T) {
+	adapter, err := NewFactory().NewAdapter(paymentdomain.AdapterConfig{
+		Config: map[string]any{
+			"webhook_secret":    "whsec_test",
+			"api_key":           "sk_test",
+			"stripe_account_id": " acct_123 ",
+		},
+	})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.stripe.com/v1/checkout/sessions", nil)
+	adapter.(*Adapter).authorize(req)
+	if got := req.Header.Get("Authorization"); got != "Bearer sk_test" {
+		t.Fatalf("unexpected authorization header %q", got)
+	}
+	if got := req.Header.Get("Stripe-Account"); got != "acct_123" {
+		t.Fatalf("expected connected account header, got %q", got)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, "https://api.stripe.com/v1/checkout/sessions", nil)
+	(&Adapter{apiKey: "sk_test"}).authorize(req)
+	if _, ok := req.Header["Stripe-Account"]; ok {
+		t.Fatal("expected no connected account header for the platform account")
+	}
+}
+
+func TestStripeAmountsUseStripeMinorUnits(t *testing.T) {
+	cases := []struct {
+		currency string
+		amount   int64
+		want     int64
+	}{
+		{currency: "USD", amount: 1999, want: 1999},
+		{currency: "JPY", amount: 1500, want: 1500},
+		{currency: "BHD", amount: 12345, want: 12345},
+		// We store whole rupiah but Stripe counts IDR in hundredths.
+		{currency: "idr", amount: 150000, want: 15000000},
+	}
+	for _, tc := range cases {
+		if got := ToStripeAmount(tc.amount, tc.currency); got != tc.want {
+			t.Fatalf("expected %d %s to be %d at Stripe, got %d", tc.amount, tc.currency, tc.want, got)
+		}
+		if got := fromStripeAmount(tc.want, tc.currency); got != tc.amount {
+			t.Fatalf("expected Stripe %d %s to be %d, got %d", tc.want, tc.currency, tc.amount, got)
+		}
+	}
+}
+
 func buildStripeSignatureHeader(secret string, payload []byte, timestamp int64) string {
 	signedPayload := fmt.Sprintf("%d.%s", timestamp, string(payload))
 	mac := hmac.New(sha256.New, []byte(secret))
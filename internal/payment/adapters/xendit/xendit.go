@@ -5,14 +5,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/currency"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 )
 
+const defaultBaseURL = "https://api.xendit.co"
+
 // Factory creates Xendit adapters
 type Factory struct{}
 
@@ -39,6 +44,7 @@ func (f *Factory) NewAdapter(cfg paymentdomain.AdapterConfig) (paymentdomain.Pay
 		orgID:         cfg.OrgID,
 		webhookSecret: webhookSecret,
 		apiKey:        apiKey,
+		baseURL:       defaultBaseURL,
 	}, nil
 }
 
@@ -47,6 +53,7 @@ type Adapter struct {
 	orgID         snowflake.ID
 	webhookSecret string
 	apiKey        string
+	baseURL       string
 }
 
 // Verify verifies Xendit webhook signature
@@ -71,6 +78,11 @@ func (a *Adapter) Parse(ctx context.Context, payload []byte) (*paymentdomain.Pay
 		return nil, paymentdomain.ErrInvalidPayload
 	}
 
+	// Refund callbacks carry the refund in data and have no top-level id.
+	if strings.EqualFold(strings.TrimSpace(event.Event), "refund.succeeded") {
+		return a.parseRefundSucceeded(event, payload)
+	}
+
 	if strings.TrimSpace(event.ID) == "" {
 		return nil, paymentdomain.ErrInvalidEvent
 	}
@@ -88,15 +100,36 @@ func (a *Adapter) Parse(ctx context.Context, payload []byte) (*paymentdomain.Pay
 
 // AttachPaymentMethod creates a multi-use token in Xendit
 func (a *Adapter) AttachPaymentMethod(ctx context.Context, customerProviderID, token string) (*paymentdomain.PaymentMethodDetails, error) {
-	// TODO: Implement Xendit multi-use token creation
-	// POST /credit_card_tokens with is_multiple_use: true
-	// For now, return placeholder
-	return &paymentdomain.PaymentMethodDetails{
-		ID:    token,
-		Type:  "card",
-		Last4: "****", // Will be populated from Xendit API
-		Brand: "unknown",
-	}, nil
+	if a.apiKey == "" {
+		return nil, errors.New("xendit api key not configured")
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, paymentdomain.ErrInvalidPaymentMethod
+	}
+
+	// Call Xendit API: POST /credit_card_tokens
+	reqBody := map[string]interface{}{
+		"token_id":        token,
+		"is_multiple_use": true,
+	}
+	if customerID := strings.TrimSpace(customerProviderID); customerID != "" {
+		reqBody["customer_id"] = customerID
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.endpoint("/credit_card_tokens"), strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(a.apiKey, "")
+	req.Header.Set("Content-Type", "application/json")
+
+	return a.doCardTokenRequest(req)
 }
 
 // DetachPaymentMethod removes a payment method
@@ -108,9 +141,55 @@ func (a *Adapter) DetachPaymentMethod(ctx context.Context, paymentMethodID strin
 
 // GetPaymentMethod retrieves payment method details
 func (a *Adapter) GetPaymentMethod(ctx context.Context, paymentMethodID string) (*paymentdomain.PaymentMethodDetails, error) {
-	// TODO: Implement Xendit token retrieval
-	// GET /credit_card_tokens/{token_id}
-	return nil, errors.New("not implemented")
+	if a.apiKey == "" {
+		return nil, errors.New("xendit api key not configured")
+	}
+	paymentMethodID = strings.TrimSpace(paymentMethodID)
+	if paymentMethodID == "" {
+		return nil, paymentdomain.ErrInvalidPaymentMethod
+	}
+
+	// Call Xendit API: GET /credit_card_tokens/{id}
+	url := a.endpoint("/credit_card_tokens/" + paymentMethodID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(a.apiKey, "")
+
+	return a.doCardTokenRequest(req)
+}
+
+// doCardTokenRequest sends a credit card token request and maps the token to
+// payment method details. Unknown tokens map to ErrPaymentMethodNotFound and
+// rejected or failed tokens to ErrInvalidPaymentMethod.
+func (a *Adapter) doCardTokenRequest(req *http.Request) (*paymentdomain.PaymentMethodDetails, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, paymentdomain.ErrPaymentMethodNotFound
+	case resp.StatusCode == http.StatusBadRequest:
+		return nil, paymentdomain.ErrInvalidPaymentMethod
+	case resp.StatusCode != http.StatusOK:
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("xendit api error: %d body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var token xenditCardToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(token.ID) == "" || strings.EqualFold(token.Status, "FAILED") {
+		return nil, paymentdomain.ErrInvalidPaymentMethod
+	}
+
+	return token.details(), nil
 }
 
 // CreateCheckoutSession creates a new checkout session (invoice)
@@ -120,12 +199,12 @@ func (a *Adapter) CreateCheckoutSession(ctx context.Context, input paymentdomain
 	}
 
 	// Call Xendit API: POST /v2/invoices
-	url := "https://api.xendit.co/v2/invoices"
+	url := a.endpoint("/v2/invoices")
 
 	// Create request body
 	reqBody := map[string]interface{}{
 		"external_id":          fmt.Sprintf("checkout-%s-%d", input.CustomerID, time.Now().UnixNano()),
-		"amount":               input.Amount,
+		"amount":               currency.ToMajorUnits(input.Amount, input.Currency),
 		"currency":             input.Currency,
 		"success_redirect_url": input.SuccessURL,
 		"failure_redirect_url": input.CancelURL,
@@ -199,7 +278,7 @@ func (a *Adapter) RetrieveCheckoutSession(ctx context.Context, providerSessionID
 	}
 
 	// Call Xendit API: GET /v2/invoices/{id}
-	url := fmt.Sprintf("https://api.xendit.co/v2/invoices/%s", providerSessionID)
+	url := a.endpoint("/v2/invoices/" + providerSessionID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
@@ -246,6 +325,76 @@ func (a *Adapter) RetrieveCheckoutSession(ctx context.Context, providerSessionID
 	}, nil
 }
 
+// Refund refunds a paid invoice. The reference ID uses the external_id
+// format so the refund.succeeded callback maps back to the customer.
+func (a *Adapter) Refund(ctx context.Context, input paymentdomain.RefundRequest) (*paymentdomain.ProviderRefund, error) {
+	if a.apiKey == "" {
+		return nil, errors.New("xendit api key not configured")
+	}
+	if strings.TrimSpace(input.ProviderPaymentID) == "" || input.CustomerID == 0 {
+		return nil, paymentdomain.ErrInvalidPayment
+	}
+
+	// Call Xendit API: POST /refunds
+	url := a.endpoint("/refunds")
+
+	referenceID := "customer_" + input.CustomerID.String()
+	if input.InvoiceID != nil && *input.InvoiceID != 0 {
+		referenceID += "_invoice_" + input.InvoiceID.String()
+	}
+	referenceID += "_refund_" + input.ReferenceID
+
+	reqBody := map[string]interface{}{
+		"invoice_id":   input.ProviderPaymentID,
+		"reference_id": referenceID,
+		"amount":       currency.ToMajorUnits(input.Amount, input.Currency),
+		"currency":     input.Currency,
+		"reason":       "REQUESTED_BY_CUSTOMER",
+	}
+	if input.Reason != "" {
+		reqBody["metadata"] = map[string]string{"reason": input.Reason}
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(a.apiKey, "")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-key", input.ReferenceID)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("xendit api error: %d body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var refund struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&refund); err != nil {
+		return nil, err
+	}
+
+	return &paymentdomain.ProviderRefund{
+		ID:     refund.ID,
+		Status: refund.Status,
+	}, nil
+}
+
 // ListPaymentMethods lists customer payment methods
 func (a *Adapter) ListPaymentMethods(ctx context.Context, customerProviderID string) ([]*paymentdomain.PaymentMethodDetails, error) {
 	// Xendit doesn't have a native list API for tokens
@@ -253,6 +402,45 @@ func (a *Adapter) ListPaymentMethods(ctx context.Context, customerProviderID str
 	return []*paymentdomain.PaymentMethodDetails{}, nil
 }
 
+func (a *Adapter) endpoint(path string) string {
+	baseURL := strings.TrimRight(a.baseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return baseURL + path
+}
+
+// xenditCardToken represents a Xendit credit card token
+type xenditCardToken struct {
+	ID                  string `json:"id"`
+	Status              string `json:"status"`
+	MaskedCardNumber    string `json:"masked_card_number"`
+	CardExpirationMonth string `json:"card_expiration_month"`
+	CardExpirationYear  string `json:"card_expiration_year"`
+	CardInfo            struct {
+		Brand string `json:"brand"`
+		Type  string `json:"type"`
+	} `json:"card_info"`
+}
+
+func (t xenditCardToken) details() *paymentdomain.PaymentMethodDetails {
+	last4 := strings.TrimSpace(t.MaskedCardNumber)
+	if len(last4) > 4 {
+		last4 = last4[len(last4)-4:]
+	}
+	expMonth, _ := strconv.Atoi(strings.TrimSpace(t.CardExpirationMonth))
+	expYear, _ := strconv.Atoi(strings.TrimSpace(t.CardExpirationYear))
+
+	return &paymentdomain.PaymentMethodDetails{
+		ID:       t.ID,
+		Type:     "card",
+		Last4:    last4,
+		Brand:    strings.ToLower(strings.TrimSpace(t.CardInfo.Brand)),
+		ExpMonth: expMonth,
+		ExpYear:  expYear,
+	}
+}
+
 // xenditEvent represents a Xendit webhook event
 type xenditEvent struct {
 	ID         string          `json:"id"`
@@ -273,8 +461,7 @@ func (a *Adapter) parsePaymentSucceeded(event xenditEvent, payload []byte) (*pay
 		return nil, paymentdomain.ErrInvalidCustomer
 	}
 
-	// Convert amount (Xendit uses float, we use int64 cents)
-	amount := int64(event.Amount * 100)
+	amount := currency.ToMinorUnits(event.Amount, event.Currency)
 
 	// Parse timestamp
 	occurredAt, _ := time.Parse(time.RFC3339, event.Created)
@@ -304,7 +491,7 @@ func (a *Adapter) parsePaymentFailed(event xenditEvent, payload []byte) (*paymen
 		return nil, paymentdomain.ErrInvalidCustomer
 	}
 
-	amount := int64(event.Amount * 100)
+	amount := currency.ToMinorUnits(event.Amount, event.Currency)
 	occurredAt, _ := time.Parse(time.RFC3339, event.Created)
 	if occurredAt.IsZero() {
 		occurredAt = time.Now().UTC()
@@ -326,6 +513,47 @@ func (a *Adapter) parsePaymentFailed(event xenditEvent, payload []byte) (*paymen
 	}, nil
 }
 
+func (a *Adapter) parseRefundSucceeded(event xenditEvent, payload []byte) (*paymentdomain.PaymentEvent, error) {
+	var refund struct {
+		ID          string  `json:"id"`
+		InvoiceID   string  `json:"invoice_id"`
+		ReferenceID string  `json:"reference_id"`
+		Amount      float64 `json:"amount"`
+		Currency    string  `json:"currency"`
+	}
+	if err := json.Unmarshal(event.Data, &refund); err != nil {
+		return nil, paymentdomain.ErrInvalidPayload
+	}
+	if strings.TrimSpace(refund.ID) == "" {
+		return nil, paymentdomain.ErrInvalidEvent
+	}
+
+	customerID, invoiceID, err := parseExternalID(refund.ReferenceID)
+	if err != nil {
+		return nil, paymentdomain.ErrInvalidCustomer
+	}
+
+	occurredAt, _ := time.Parse(time.RFC3339, event.Created)
+	if occurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+
+	return &paymentdomain.PaymentEvent{
+		Provider:            "xendit",
+		ProviderEventID:     refund.ID,
+		ProviderPaymentID:   refund.InvoiceID,
+		ProviderPaymentType: "xendit_payment",
+		Type:                paymentdomain.EventTypeRefunded,
+		OrgID:               a.orgID,
+		CustomerID:          customerID,
+		Amount:              currency.ToMinorUnits(refund.Amount, refund.Currency),
+		Currency:            strings.ToUpper(strings.TrimSpace(refund.Currency)),
+		OccurredAt:          occurredAt,
+		RawPayload:          payload,
+		InvoiceID:           invoiceID,
+	}, nil
+}
+
 // parseExternalID extracts customer_id and invoice_id from external_id
 // Format: "customer_{customerID}_invoice_{invoiceID}"
 func parseExternalID(externalID string) (snowflake.ID, *snowflake.ID, error) {
@@ -0,0 +1,105 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+)
+
+func TestCardTokenization(t *testing.T) {
+	var attachBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user != "xnd_test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		token := `{"id":"tok_multi","status":"VERIFIED","masked_card_number":"400000XXXXXX1091","card_expiration_month":"12","card_expiration_year":"2030","card_info":{"brand":"VISA","type":"CREDIT"}}`
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/credit_card_tokens":
+			_ = json.NewDecoder(r.Body).Decode(&attachBody)
+			_, _ = w.Write([]byte(token))
+		case r.Method == http.MethodGet && r.URL.Path == "/credit_card_tokens/tok_multi":
+			_, _ = w.Write([]byte(token))
+		case r.Method == http.MethodGet && r.URL.Path == "/credit_card_tokens/tok_failed":
+			_, _ = w.Write([]byte(`{"id":"tok_failed","status":"FAILED"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	adapter := &Adapter{orgID: 1, apiKey: "xnd_test", baseURL: server.URL}
+	ctx := context.Background()
+
+	method, err := adapter.AttachPaymentMethod(ctx, "cust_123", "tok_single")
+	if err != nil {
+		t.Fatalf("expected attach to succeed, got %v", err)
+	}
+	if attachBody["is_multiple_use"] != true || attachBody["token_id"] != "tok_single" || attachBody["customer_id"] != "cust_123" {
+		t.Fatalf("unexpected attach body: %v", attachBody)
+	}
+	expected := paymentdomain.PaymentMethodDetails{ID: "tok_multi", Type: "card", Last4: "1091", Brand: "visa", ExpMonth: 12, ExpYear: 2030}
+	if *method != expected {
+		t.Fatalf("expected %+v, got %+v", expected, *method)
+	}
+
+	method, err = adapter.GetPaymentMethod(ctx, "tok_multi")
+	if err != nil {
+		t.Fatalf("expected get to succeed, got %v", err)
+	}
+	if *method != expected {
+		t.Fatalf("expected %+v, got %+v", expected, *method)
+	}
+
+	if _, err := adapter.GetPaymentMethod(ctx, "tok_missing"); !errors.Is(err, paymentdomain.ErrPaymentMethodNotFound) {
+		t.Fatalf("expected payment method not found, got %v", err)
+	}
+	if _, err := adapter.GetPaymentMethod(ctx, "tok_failed"); !errors.Is(err, paymentdomain.ErrInvalidPaymentMethod) {
+		t.Fatalf("expected invalid payment method, got %v", err)
+	}
+	if _, err := adapter.AttachPaymentMethod(ctx, "cust_123", " "); !errors.Is(err, paymentdomain.ErrInvalidPaymentMethod) {
+		t.Fatalf("expected invalid payment method for empty token, got %v", err)
+	}
+}
+
+func TestPaymentAmountsUseCurrencyMinorUnits(t *testing.T) {
+	adapter := &Adapter{orgID: 1}
+	cases := []struct {
+		currency string
+		amount   float64
+		want     int64
+	}{
+		// IDR has no minor unit, so Xendit's amount is already the stored one.
+		{currency: "IDR", amount: 150000, want: 150000},
+		// 19.99 * 100 is 1998.999... in float64 and used to truncate to 1998.
+		{currency: "USD", amount: 19.99, want: 1999},
+		{currency: "usd", amount: 0.29, want: 29},
+	}
+	for _, tc := range cases {
+		event := xenditEvent{
+			ID:         "evt_1",
+			ExternalID: "customer_123_invoice_456",
+			Amount:     tc.amount,
+			Currency:   tc.currency,
+		}
+		succeeded, err := adapter.parsePaymentSucceeded(event, nil)
+		if err != nil {
+			t.Fatalf("parse %s payment: %v", tc.currency, err)
+		}
+		if succeeded.Amount != tc.want {
+			t.Fatalf("expected %s %v to be %d minor units, got %d", tc.currency, tc.amount, tc.want, succeeded.Amount)
+		}
+		failed, err := adapter.parsePaymentFailed(event, nil)
+		if err != nil {
+			t.Fatalf("parse %s failure: %v", tc.currency, err)
+		}
+		if failed.Amount != tc.want {
+			t.Fatalf("expected failed %s %v to be %d minor units, got %d", tc.currency, tc.amount, tc.want, failed.Amount)
+		}
+	}
+}
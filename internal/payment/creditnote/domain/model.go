@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+)
+
+const (
+	ReasonRefund = "refund"
+	// ReasonCancellation credits the unused part of an upfront billed cycle
+	// of a subscription canceled before the cycle ended.
+	ReasonCancellation = "cancellation"
+)
+
+// CreditNote reduces the amount owed on an invoice. Credit notes are
+// immutable; a correction is another credit note.
+type CreditNote struct {
+	ID             snowflake.ID  `gorm:"primaryKey"`
+	OrgID          snowflake.ID  `gorm:"not null;index"`
+	InvoiceID      snowflake.ID  `gorm:"not null;index"`
+	CustomerID     snowflake.ID  `gorm:"not null"`
+	PaymentEventID *snowflake.ID `gorm:"column:payment_event_id"`
+	Amount         int64         `gorm:"not null"`
+	Currency       string        `gorm:"type:text;not null"`
+	Reason         string        `gorm:"type:text;not null"`
+	IssuedAt       time.Time     `gorm:"not null"`
+	CreatedAt      time.Time     `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (CreditNote) TableName() string { return "credit_notes" }
+
+type Response struct {
+	ID             string    `json:"id"`
+	InvoiceID      string    `json:"invoice_id"`
+	CustomerID     string    `json:"customer_id"`
+	PaymentEventID *string   `json:"payment_event_id,omitempty"`
+	Amount         int64     `json:"amount"`
+	Currency       string    `json:"currency"`
+	Reason         string    `json:"reason"`
+	IssuedAt       time.Time `json:"issued_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/bwmarrin/snowflake"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	InsertCreditNote(ctx context.Context, db *gorm.DB, record *CreditNote) (bool, error)
+	FindByPaymentEvent(ctx context.Context, db *gorm.DB, orgID snowflake.ID, paymentEventID snowflake.ID) (*CreditNote, error)
+	FindByInvoiceReason(ctx context.Context, db *gorm.DB, orgID snowflake.ID, invoiceID snowflake.ID, reason string) (*CreditNote, error)
+	ListByInvoice(ctx context.Context, db *gorm.DB, orgID snowflake.ID, invoiceID snowflake.ID) ([]CreditNote, error)
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+)
+
+type Service interface {
+	CreateFromRefund(ctx context.Context, req CreateFromRefundRequest) (*CreditNote, error)
+	CreateForCancellation(ctx context.Context, req CreateForCancellationRequest) (*CreditNote, error)
+	ListByInvoice(ctx context.Context, invoiceID string) ([]Response, error)
+}
+
+// CreateFromRefundRequest describes a processed refund payment event.
+type CreateFromRefundRequest struct {
+	OrgID          snowflake.ID
+	InvoiceID      snowflake.ID
+	CustomerID     snowflake.ID
+	PaymentEventID snowflake.ID
+	Amount         int64
+	Currency       string
+	OccurredAt     time.Time
+}
+
+// CreateForCancellationRequest credits the unused part of an invoice billed
+// upfront for a subscription canceled before the invoiced period ended.
+type CreateForCancellationRequest struct {
+	OrgID     snowflake.ID
+	InvoiceID snowflake.ID
+	Amount    int64
+	Currency  string
+	IssuedAt  time.Time
+}
+
+var (
+	ErrInvalidOrganization = errors.New("invalid_organization")
+	ErrInvalidAmount       = errors.New("invalid_amount")
+	ErrInvalidCurrency     = errors.New("invalid_currency")
+	ErrInvalidPaymentEvent = errors.New("invalid_payment_event")
+)
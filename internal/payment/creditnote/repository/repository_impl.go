@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/bwmarrin/snowflake"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
+	"gorm.io/gorm"
+)
+
+type repo struct{}
+
+func Provide() creditnotedomain.Repository {
+	return &repo{}
+}
+
+func (r *repo) InsertCreditNote(ctx context.Context, db *gorm.DB, record *creditnotedomain.CreditNote) (bool, error) {
+	res := db.WithContext(ctx).Exec(
+		`INSERT INTO credit_notes (
+			id, org_id, invoice_id, customer_id, payment_event_id,
+			amount, currency, reason, issued_at, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (payment_event_id) WHERE payment_event_id IS NOT NULL DO NOTHING`,
+		record.ID,
+		record.OrgID,
+		record.InvoiceID,
+		record.CustomerID,
+		record.PaymentEventID,
+		record.Amount,
+		record.Currency,
+		record.Reason,
+		record.IssuedAt,
+		record.CreatedAt,
+	)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *repo) FindByPaymentEvent(ctx context.Context, db *gorm.DB, orgID snowflake.ID, paymentEventID snowflake.ID) (*creditnotedomain.CreditNote, error) {
+	var record creditnotedomain.CreditNote
+	err := db.WithContext(ctx).Raw(
+		`SELECT id, org_id, invoice_id, customer_id, payment_event_id,
+			amount, currency, reason, issued_at, created_at
+		 FROM credit_notes
+		 WHERE org_id = ? AND payment_event_id = ?
+		 LIMIT 1`,
+		orgID,
+		paymentEventID,
+	).Scan(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	if record.ID == 0 {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (r *repo) FindByInvoiceReason(ctx context.Context, db *gorm.DB, orgID snowflake.ID, invoiceID snowflake.ID, reason string) (*creditnotedomain.CreditNote, error) {
+	var record creditnotedomain.CreditNote
+	err := db.WithContext(ctx).Raw(
+		`SELECT id, org_id, invoice_id, customer_id, payment_event_id,
+			amount, currency, reason, issued_at, created_at
+		 FROM credit_notes
+		 WHERE org_id = ? AND invoice_id = ? AND reason = ?
+		 ORDER BY issued_at ASC, id ASC
+		 LIMIT 1`,
+		orgID,
+		invoiceID,
+		reason,
+	).Scan(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	if record.ID == 0 {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (r *repo) ListByInvoice(ctx context.Context, db *gorm.DB, orgID snowflake.ID, invoiceID snowflake.ID) ([]creditnotedomain.CreditNote, error) {
+	var records []creditnotedomain.CreditNote
+	err := db.WithContext(ctx).Raw(
+		`SELECT id, org_id, invoice_id, customer_id, payment_event_id,
+			amount, currency, reason, issued_at, created_at
+		 FROM credit_notes
+		 WHERE org_id = ? AND invoice_id = ?
+		 ORDER BY issued_at ASC, id ASC`,
+		orgID,
+		invoiceID,
+	).Scan(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
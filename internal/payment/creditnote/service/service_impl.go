@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type Params struct {
+	fx.In
+
+	DB        *gorm.DB
+	Log       *zap.Logger
+	GenID     *snowflake.Node
+	LedgerSvc ledgerdomain.Service
+	Repo      creditnotedomain.Repository
+}
+
+type Service struct {
+	db        *gorm.DB
+	log       *zap.Logger
+	genID     *snowflake.Node
+	ledgerSvc ledgerdomain.Service
+	repo      creditnotedomain.Repository
+}
+
+func NewService(p Params) creditnotedomain.Service {
+	return &Service{
+		db:        p.DB,
+		log:       p.Log.Named("payment.creditnote"),
+		genID:     p.GenID,
+		ledgerSvc: p.LedgerSvc,
+		repo:      p.Repo,
+	}
+}
+
+type invoiceRow struct {
+	ID          snowflake.ID      `gorm:"column:id"`
+	CustomerID  snowflake.ID      `gorm:"column:customer_id"`
+	Currency    string            `gorm:"column:currency"`
+	TotalAmount int64             `gorm:"column:total_amount"`
+	Metadata    datatypes.JSONMap `gorm:"column:metadata"`
+}
+
+// CreateFromRefund issues a credit note for a refund against an invoice and
+// reverses the credited revenue and tax in the ledger (debit revenue and tax
+// payable, credit accounts receivable). The credit is capped at what is left to credit on
+// the invoice, so it returns nil when the invoice is already fully credited.
+// Replaying the same payment event returns the credit note issued first.
+func (s *Service) CreateFromRefund(ctx context.Context, req creditnotedomain.CreateFromRefundRequest) (*creditnotedomain.CreditNote, error) {
+	if req.OrgID == 0 {
+		return nil, creditnotedomain.ErrInvalidOrganization
+	}
+	if req.InvoiceID == 0 {
+		return nil, invoicedomain.ErrInvalidInvoiceID
+	}
+	if req.PaymentEventID == 0 {
+		return nil, creditnotedomain.ErrInvalidPaymentEvent
+	}
+	if req.Amount <= 0 {
+		return nil, creditnotedomain.ErrInvalidAmount
+	}
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency == "" {
+		return nil, creditnotedomain.ErrInvalidCurrency
+	}
+	issuedAt := req.OccurredAt.UTC()
+	if req.OccurredAt.IsZero() {
+		issuedAt = time.Now().UTC()
+	}
+
+	var note *creditnotedomain.CreditNote
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.FindByPaymentEvent(ctx, tx, req.OrgID, req.PaymentEventID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			note = existing
+			return nil
+		}
+
+		invoice, err := s.loadInvoiceForUpdate(ctx, tx, req.OrgID, req.InvoiceID)
+		if err != nil {
+			return err
+		}
+		if invoice == nil {
+			return invoicedomain.ErrInvoiceNotFound
+		}
+		if !strings.EqualFold(invoice.Currency, currency) {
+			return invoicedomain.ErrCurrencyMismatch
+		}
+
+		credited := readMetadataAmount(invoice.Metadata, "amount_credited")
+		amount := req.Amount
+		if remaining := invoice.TotalAmount - credited; amount > remaining {
+			amount = remaining
+		}
+		if amount <= 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		paymentEventID := req.PaymentEventID
+		record := creditnotedomain.CreditNote{
+			ID:             s.genID.Generate(),
+			OrgID:          req.OrgID,
+			InvoiceID:      invoice.ID,
+			CustomerID:     invoice.CustomerID,
+			PaymentEventID: &paymentEventID,
+			Amount:         amount,
+			Currency:       currency,
+			Reason:         creditnotedomain.ReasonRefund,
+			IssuedAt:       issuedAt,
+			CreatedAt:      now,
+		}
+		inserted, err := s.repo.InsertCreditNote(ctx, tx, &record)
+		if err != nil {
+			return err
+		}
+		if !inserted {
+			note, err = s.repo.FindByPaymentEvent(ctx, tx, req.OrgID, req.PaymentEventID)
+			return err
+		}
+
+		if err := s.markCredited(ctx, tx, req.OrgID, invoice, credited+amount, now); err != nil {
+			return err
+		}
+
+		note = &record
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		s.log.Info("invoice already fully credited, refund not credited",
+			zap.String("invoice_id", req.InvoiceID.String()),
+			zap.String("payment_event_id", req.PaymentEventID.String()),
+		)
+		return nil, nil
+	}
+
+	// The ledger entry is keyed by the credit note, so posting again on a
+	// replayed event is a no-op.
+	if err := s.postLedgerEntry(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// CreateForCancellation issues the credit note for the unused part of an
+// invoice billed upfront when its subscription is canceled early, and
+// reverses the credited revenue in the ledger like CreateFromRefund. An
+// invoice gets at most one such credit note; calling again returns it.
+func (s *Service) CreateForCancellation(ctx context.Context, req creditnotedomain.CreateForCancellationRequest) (*creditnotedomain.CreditNote, error) {
+	if req.OrgID == 0 {
+		return nil, creditnotedomain.ErrInvalidOrganization
+	}
+	if req.InvoiceID == 0 {
+		return nil, invoicedomain.ErrInvalidInvoiceID
+	}
+	if req.Amount <= 0 {
+		return nil, creditnotedomain.ErrInvalidAmount
+	}
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency == "" {
+		return nil, creditnotedomain.ErrInvalidCurrency
+	}
+	issuedAt := req.IssuedAt.UTC()
+	if req.IssuedAt.IsZero() {
+		issuedAt = time.Now().UTC()
+	}
+
+	var note *creditnotedomain.CreditNote
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The invoice row lock serializes cancellations of the same
+		// invoice, so the lookup below cannot miss a concurrent insert.
+		invoice, err := s.loadInvoiceForUpdate(ctx, tx, req.OrgID, req.InvoiceID)
+		if err != nil {
+			return err
+		}
+		if invoice == nil {
+			return invoicedomain.ErrInvoiceNotFound
+		}
+
+		existing, err := s.repo.FindByInvoiceReason(ctx, tx, req.OrgID, invoice.ID, creditnotedomain.ReasonCancellation)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			note = existing
+			return nil
+		}
+		if !strings.EqualFold(invoice.Currency, currency) {
+			return invoicedomain.ErrCurrencyMismatch
+		}
+
+		credited := readMetadataAmount(invoice.Metadata, "amount_credited")
+		amount := req.Amount
+		if remaining := invoice.TotalAmount - credited; amount > remaining {
+			amount = remaining
+		}
+		if amount <= 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		record := creditnotedomain.CreditNote{
+			ID:         s.genID.Generate(),
+			OrgID:      req.OrgID,
+			InvoiceID:  invoice.ID,
+			CustomerID: invoice.CustomerID,
+			Amount:     amount,
+			Currency:   currency,
+			Reason:     creditnotedomain.ReasonCancellation,
+			IssuedAt:   issuedAt,
+			CreatedAt:  now,
+		}
+		if _, err := s.repo.InsertCreditNote(ctx, tx, &record); err != nil {
+			return err
+		}
+		if err := s.markCredited(ctx, tx, req.OrgID, invoice, credited+amount, now); err != nil {
+			return err
+		}
+
+		note = &record
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		s.log.Info("invoice already fully credited, cancellation not credited",
+			zap.String("invoice_id", req.InvoiceID.String()),
+		)
+		return nil, nil
+	}
+
+	if err := s.postLedgerEntry(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// ListByInvoice returns the credit notes issued against an invoice of the
+// current organization, oldest first.
+func (s *Service) ListByInvoice(ctx context.Context, invoiceID string) ([]creditnotedomain.Response, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, creditnotedomain.ErrInvalidOrganization
+	}
+	id, err := snowflake.ParseString(strings.TrimSpace(invoiceID))
+	if err != nil {
+		return nil, invoicedomain.ErrInvalidInvoiceID
+	}
+
+	var exists int64
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT COUNT(1) FROM invoices WHERE id = ? AND org_id = ?`,
+		id,
+		orgID,
+	).Scan(&exists).Error; err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, invoicedomain.ErrInvoiceNotFound
+	}
+
+	records, err := s.repo.ListByInvoice(ctx, s.db, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]creditnotedomain.Response, 0, len(records))
+	for _, record := range records {
+		resp = append(resp, toResponse(record))
+	}
+	return resp, nil
+}
+
+func (s *Service) loadInvoiceForUpdate(ctx context.Context, tx *gorm.DB, orgID snowflake.ID, invoiceID snowflake.ID) (*invoiceRow, error) {
+	query := `SELECT id, customer_id, currency, total_amount, metadata
+	 FROM invoices
+	 WHERE id = ? AND org_id = ?`
+	if tx.Dialector.Name() != "sqlite" {
+		query += " FOR UPDATE"
+	}
+	var row invoiceRow
+	if err := tx.WithContext(ctx).Raw(query, invoiceID, orgID).Scan(&row).Error; err != nil {
+		return nil, err
+	}
+	if row.ID == 0 {
+		return nil, nil
+	}
+	return &row, nil
+}
+
+func (s *Service) markCredited(ctx context.Context, tx *gorm.DB, orgID snowflake.ID, invoice *invoiceRow, credited int64, now time.Time) error {
+	if invoice.Metadata == nil {
+		invoice.Metadata = datatypes.JSONMap{}
+	}
+	invoice.Metadata["amount_credited"] = credited
+	return tx.WithContext(ctx).Exec(
+		`UPDATE invoices
+		 SET metadata = ?, updated_at = ?
+		 WHERE id = ? AND org_id = ?`,
+		invoice.Metadata,
+		now,
+		invoice.ID,
+		orgID,
+	).Error
+}
+
+// postLedgerEntry reverses the credited part of the invoice's posting. The
+// credit is split between revenue and tax payable in the proportion the
+// invoice total was posted to them.
+func (s *Service) postLedgerEntry(ctx context.Context, note *creditnotedomain.CreditNote) error {
+	now := time.Now().UTC()
+	revenueID, err := s.ensureLedgerAccount(ctx, note.OrgID, ledgerdomain.AccountCodeRevenueUsage, now)
+	if err != nil {
+		return err
+	}
+	receivableID, err := s.ensureLedgerAccount(ctx, note.OrgID, ledgerdomain.AccountCodeAccountsReceivable, now)
+	if err != nil {
+		return err
+	}
+
+	var totals struct {
+		TaxAmount   int64 `gorm:"column:tax_amount"`
+		TotalAmount int64 `gorm:"column:total_amount"`
+	}
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT tax_amount, total_amount
+		 FROM invoices
+		 WHERE id = ? AND org_id = ?`,
+		note.InvoiceID,
+		note.OrgID,
+	).Scan(&totals).Error; err != nil {
+		return err
+	}
+	var tax int64
+	if totals.TaxAmount > 0 && totals.TotalAmount > 0 {
+		tax = note.Amount * totals.TaxAmount / totals.TotalAmount
+	}
+
+	lines := []ledgerdomain.LedgerEntryLine{
+		{AccountID: revenueID, Direction: ledgerdomain.LedgerEntryDirectionDebit, Currency: note.Currency, Amount: note.Amount - tax},
+	}
+	if tax > 0 {
+		taxID, err := s.ensureLedgerAccount(ctx, note.OrgID, ledgerdomain.AccountCodeTaxPayable, now)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, ledgerdomain.LedgerEntryLine{AccountID: taxID, Direction: ledgerdomain.LedgerEntryDirectionDebit, Currency: note.Currency, Amount: tax})
+	}
+	lines = append(lines, ledgerdomain.LedgerEntryLine{AccountID: receivableID, Direction: ledgerdomain.LedgerEntryDirectionCredit, Currency: note.Currency, Amount: note.Amount})
+	return s.ledgerSvc.CreateEntry(
+		ctx,
+		note.OrgID,
+		string(ledgerdomain.SourceTypeCreditNote),
+		note.ID,
+		note.Currency,
+		note.IssuedAt,
+		lines,
+	)
+}
+
+func (s *Service) ensureLedgerAccount(ctx context.Context, orgID snowflake.ID, code ledgerdomain.LedgerAccountCode, now time.Time) (snowflake.ID, error) {
+	if err := s.db.WithContext(ctx).Exec(
+		`INSERT INTO ledger_accounts (id, org_id, code, name, created_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT (org_id, code) DO NOTHING`,
+		s.genID.Generate(),
+		orgID,
+		string(code),
+		string(code),
+		now,
+	).Error; err != nil {
+		return 0, err
+	}
+
+	var accountID snowflake.ID
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT id
+		 FROM ledger_accounts
+		 WHERE org_id = ? AND code = ?`,
+		orgID,
+		string(code),
+	).Scan(&accountID).Error; err != nil {
+		return 0, err
+	}
+	if accountID == 0 {
+		return 0, errors.New("ledger_account_not_found")
+	}
+	return accountID, nil
+}
+
+func toResponse(record creditnotedomain.CreditNote) creditnotedomain.Response {
+	resp := creditnotedomain.Response{
+		ID:         record.ID.String(),
+		InvoiceID:  record.InvoiceID.String(),
+		CustomerID: record.CustomerID.String(),
+		Amount:     record.Amount,
+		Currency:   record.Currency,
+		Reason:     record.Reason,
+		IssuedAt:   record.IssuedAt,
+		CreatedAt:  record.CreatedAt,
+	}
+	if record.PaymentEventID != nil {
+		id := record.PaymentEventID.String()
+		resp.PaymentEventID = &id
+	}
+	return resp
+}
+
+func readMetadataAmount(metadata datatypes.JSONMap, key string) int64 {
+	switch typed := metadata[key].(type) {
+	case float64:
+		return int64(typed)
+	case int64:
+		return typed
+	case int:
+		return int64(typed)
+	case json.Number:
+		parsed, _ := typed.Int64()
+		return parsed
+	case string:
+		parsed, _ := strconv.ParseInt(strings.TrimSpace(typed), 10, 64)
+		return parsed
+	default:
+		return 0
+	}
+}
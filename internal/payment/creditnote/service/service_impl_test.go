@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
+	"github.com/railzwaylabs/railzway/internal/payment/creditnote/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type ledgerEntry struct {
+	sourceType string
+	sourceID   snowflake.ID
+	currency   string
+	lines      []ledgerdomain.LedgerEntryLine
+}
+
+type recordingLedger struct {
+	entries map[snowflake.ID]ledgerEntry
+}
+
+func (l *recordingLedger) CreateEntry(
+	ctx context.Context,
+	orgID snowflake.ID,
+	sourceType string,
+	sourceID snowflake.ID,
+	currency string,
+	occurredAt time.Time,
+	lines []ledgerdomain.LedgerEntryLine,
+) error {
+	if _, ok := l.entries[sourceID]; !ok {
+		l.entries[sourceID] = ledgerEntry{sourceType: sourceType, sourceID: sourceID, currency: currency, lines: lines}
+	}
+	return nil
+}
+
+func (l *recordingLedger) CustomerBalance(ctx context.Context, orgID, customerID snowflake.ID, currency string, asOf time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (l *recordingLedger) ListCustomerEntries(ctx context.Context, orgID, customerID snowflake.ID, currency string, from, to time.Time) ([]ledgerdomain.CustomerEntry, error) {
+	return nil, nil
+}
+
+func (l *recordingLedger) EnsureAccounts(ctx context.Context, orgID snowflake.ID) error {
+	return nil
+}
+
+func TestCreateFromRefund_CreditsInvoiceAndReversesRevenue(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&invoicedomain.Invoice{}, &creditnotedomain.CreditNote{}))
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX uidx_credit_notes_payment_event
+		ON credit_notes (payment_event_id) WHERE payment_event_id IS NOT NULL`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE ledger_accounts (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		code TEXT NOT NULL,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX ux_ledger_accounts_org_code ON ledger_accounts (org_id, code)`).Error)
+
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	orgID := node.Generate()
+	customerID := node.Generate()
+	now := time.Now().UTC()
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		InvoiceNumber:  "INV-1",
+		BillingCycleID: node.Generate(),
+		SubscriptionID: node.Generate(),
+		CustomerID:     customerID,
+		Status:         invoicedomain.InvoiceStatusFinalized,
+		SubtotalAmount: 5000,
+		TotalAmount:    5000,
+		Currency:       "EUR",
+		PaidAt:         &now,
+		Metadata:       datatypes.JSONMap{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+
+	ledger := &recordingLedger{entries: map[snowflake.ID]ledgerEntry{}}
+	svc := NewService(Params{
+		DB:        db,
+		Log:       zap.NewNop(),
+		GenID:     node,
+		LedgerSvc: ledger,
+		Repo:      repository.Provide(),
+	})
+	ctx := context.Background()
+
+	refund := func(paymentEventID snowflake.ID, amount int64) (*creditnotedomain.CreditNote, error) {
+		return svc.CreateFromRefund(ctx, creditnotedomain.CreateFromRefundRequest{
+			OrgID:          orgID,
+			InvoiceID:      invoice.ID,
+			CustomerID:     customerID,
+			PaymentEventID: paymentEventID,
+			Amount:         amount,
+			Currency:       "eur",
+			OccurredAt:     now,
+		})
+	}
+	credited := func() int64 {
+		var stored invoicedomain.Invoice
+		require.NoError(t, db.First(&stored, "id = ?", invoice.ID).Error)
+		return readMetadataAmount(stored.Metadata, "amount_credited")
+	}
+
+	firstEvent := node.Generate()
+	note, err := refund(firstEvent, 2000)
+	require.NoError(t, err)
+	require.NotNil(t, note)
+	assert.Equal(t, int64(2000), note.Amount)
+	assert.Equal(t, "EUR", note.Currency)
+	assert.Equal(t, creditnotedomain.ReasonRefund, note.Reason)
+	assert.Equal(t, int64(2000), credited())
+
+	entry, ok := ledger.entries[note.ID]
+	require.True(t, ok)
+	assert.Equal(t, string(ledgerdomain.SourceTypeCreditNote), entry.sourceType)
+	require.Len(t, entry.lines, 2)
+	assert.Equal(t, ledgerdomain.LedgerEntryDirectionDebit, entry.lines[0].Direction)
+	assert.Equal(t, ledgerdomain.LedgerEntryDirectionCredit, entry.lines[1].Direction)
+	assert.Equal(t, int64(2000), entry.lines[0].Amount)
+	assert.Equal(t, int64(2000), entry.lines[1].Amount)
+
+	// A replayed refund event returns the credit note issued first.
+	replayed, err := refund(firstEvent, 2000)
+	require.NoError(t, err)
+	require.NotNil(t, replayed)
+	assert.Equal(t, note.ID, replayed.ID)
+	assert.Equal(t, int64(2000), credited())
+
+	// Credits never exceed the invoice total.
+	capped, err := refund(node.Generate(), 4000)
+	require.NoError(t, err)
+	require.NotNil(t, capped)
+	assert.Equal(t, int64(3000), capped.Amount)
+	assert.Equal(t, int64(5000), credited())
+
+	none, err := refund(node.Generate(), 100)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	_, err = svc.CreateFromRefund(ctx, creditnotedomain.CreateFromRefundRequest{
+		OrgID:          orgID,
+		InvoiceID:      invoice.ID,
+		PaymentEventID: node.Generate(),
+		Amount:         100,
+		Currency:       "USD",
+	})
+	assert.ErrorIs(t, err, invoicedomain.ErrCurrencyMismatch)
+
+	orgCtx := orgcontext.WithOrgID(ctx, int64(orgID))
+	notes, err := svc.ListByInvoice(orgCtx, invoice.ID.String())
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	assert.Equal(t, note.ID.String(), notes[0].ID)
+	require.NotNil(t, notes[0].PaymentEventID)
+	assert.Equal(t, firstEvent.String(), *notes[0].PaymentEventID)
+
+	_, err = svc.ListByInvoice(orgCtx, node.Generate().String())
+	assert.ErrorIs(t, err, invoicedomain.ErrInvoiceNotFound)
+}
+
+func TestCreateForCancellation_CreditsUnusedPeriodOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&invoicedomain.Invoice{}, &creditnotedomain.CreditNote{}))
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX uidx_credit_notes_payment_event
+		ON credit_notes (payment_event_id) WHERE payment_event_id IS NOT NULL`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE ledger_accounts (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		code TEXT NOT NULL,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX ux_ledger_accounts_org_code ON ledger_accounts (org_id, code)`).Error)
+
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	orgID := node.Generate()
+	now := time.Now().UTC()
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		InvoiceNumber:  "INV-1",
+		BillingCycleID: node.Generate(),
+		SubscriptionID: node.Generate(),
+		CustomerID:     node.Generate(),
+		Status:         invoicedomain.InvoiceStatusFinalized,
+		SubtotalAmount: 3000,
+		TotalAmount:    3000,
+		Currency:       "USD",
+		Metadata:       datatypes.JSONMap{"amount_credited": 1000},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+
+	ledger := &recordingLedger{entries: map[snowflake.ID]ledgerEntry{}}
+	svc := NewService(Params{
+		DB:        db,
+		Log:       zap.NewNop(),
+		GenID:     node,
+		LedgerSvc: ledger,
+		Repo:      repository.Provide(),
+	})
+	ctx := context.Background()
+	cancel := func(amount int64) (*creditnotedomain.CreditNote, error) {
+		return svc.CreateForCancellation(ctx, creditnotedomain.CreateForCancellationRequest{
+			OrgID:     orgID,
+			InvoiceID: invoice.ID,
+			Amount:    amount,
+			Currency:  "usd",
+			IssuedAt:  now,
+		})
+	}
+
+	// The unused amount is capped at what is left to credit.
+	note, err := cancel(2500)
+	require.NoError(t, err)
+	require.NotNil(t, note)
+	assert.Equal(t, int64(2000), note.Amount)
+	assert.Equal(t, creditnotedomain.ReasonCancellation, note.Reason)
+	assert.Nil(t, note.PaymentEventID)
+	_, ok := ledger.entries[note.ID]
+	assert.True(t, ok)
+
+	// Canceling again returns the credit note issued first.
+	again, err := cancel(2500)
+	require.NoError(t, err)
+	require.NotNil(t, again)
+	assert.Equal(t, note.ID, again.ID)
+
+	var stored invoicedomain.Invoice
+	require.NoError(t, db.First(&stored, "id = ?", invoice.ID).Error)
+	assert.Equal(t, int64(3000), readMetadataAmount(stored.Metadata, "amount_credited"))
+
+	var count int64
+	require.NoError(t, db.Model(&creditnotedomain.CreditNote{}).Where("invoice_id = ?", invoice.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestCreateFromRefund_ReversesTaxInProportion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&invoicedomain.Invoice{}, &creditnotedomain.CreditNote{}))
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX uidx_credit_notes_payment_event
+		ON credit_notes (payment_event_id) WHERE payment_event_id IS NOT NULL`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE ledger_accounts (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		code TEXT NOT NULL,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX ux_ledger_accounts_org_code ON ledger_accounts (org_id, code)`).Error)
+
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	orgID := node.Generate()
+	now := time.Now().UTC()
+	invoice := invoicedomain.Invoice{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		InvoiceNumber:  "INV-1",
+		BillingCycleID: node.Generate(),
+		SubscriptionID: node.Generate(),
+		CustomerID:     node.Generate(),
+		Status:         invoicedomain.InvoiceStatusFinalized,
+		SubtotalAmount: 10000,
+		TaxAmount:      2000,
+		TotalAmount:    12000,
+		Currency:       "USD",
+		PaidAt:         &now,
+		Metadata:       datatypes.JSONMap{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	require.NoError(t, db.Create(&invoice).Error)
+
+	ledger := &recordingLedger{entries: map[snowflake.ID]ledgerEntry{}}
+	svc := NewService(Params{
+		DB:        db,
+		Log:       zap.NewNop(),
+		GenID:     node,
+		LedgerSvc: ledger,
+		Repo:      repository.Provide(),
+	})
+
+	note, err := svc.CreateFromRefund(context.Background(), creditnotedomain.CreateFromRefundRequest{
+		OrgID:          orgID,
+		InvoiceID:      invoice.ID,
+		CustomerID:     invoice.CustomerID,
+		PaymentEventID: node.Generate(),
+		Amount:         6000,
+		Currency:       "USD",
+		OccurredAt:     now,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, note)
+
+	// Half the invoice is refunded, so half its tax is no longer payable.
+	accountID := func(code ledgerdomain.LedgerAccountCode) snowflake.ID {
+		var id snowflake.ID
+		require.NoError(t, db.Raw(`SELECT id FROM ledger_accounts WHERE org_id = ? AND code = ?`, orgID, string(code)).Scan(&id).Error)
+		return id
+	}
+	entry, ok := ledger.entries[note.ID]
+	require.True(t, ok)
+	require.Len(t, entry.lines, 3)
+	assert.Equal(t, accountID(ledgerdomain.AccountCodeRevenueUsage), entry.lines[0].AccountID)
+	assert.Equal(t, ledgerdomain.LedgerEntryDirectionDebit, entry.lines[0].Direction)
+	assert.Equal(t, int64(5000), entry.lines[0].Amount)
+	assert.Equal(t, accountID(ledgerdomain.AccountCodeTaxPayable), entry.lines[1].AccountID)
+	assert.Equal(t, ledgerdomain.LedgerEntryDirectionDebit, entry.lines[1].Direction)
+	assert.Equal(t, int64(1000), entry.lines[1].Amount)
+	assert.Equal(t, accountID(ledgerdomain.AccountCodeAccountsReceivable), entry.lines[2].AccountID)
+	assert.Equal(t, ledgerdomain.LedgerEntryDirectionCredit, entry.lines[2].Direction)
+	assert.Equal(t, int64(6000), entry.lines[2].Amount)
+}
@@ -5,13 +5,14 @@ import (
 	"net/http"
 
 	"github.com/bwmarrin/snowflake"
+	"go.uber.org/zap"
 )
 
 type PaymentAdapter interface {
 	// Webhook handling
 	Verify(ctx context.Context, payload []byte, headers http.Header) error
 	Parse(ctx context.Context, payload []byte) (*PaymentEvent, error)
-	
+
 	// Payment method management
 	AttachPaymentMethod(ctx context.Context, customerProviderID, token string) (*PaymentMethodDetails, error)
 	DetachPaymentMethod(ctx context.Context, paymentMethodID string) error
@@ -21,12 +22,17 @@ type PaymentAdapter interface {
 	// Checkout Session
 	CreateCheckoutSession(ctx context.Context, input CheckoutSessionInput) (*ProviderCheckoutSession, error)
 	RetrieveCheckoutSession(ctx context.Context, providerSessionID string) (*ProviderCheckoutSession, error)
+
+	// Refunds
+	Refund(ctx context.Context, req RefundRequest) (*ProviderRefund, error)
 }
 
 type AdapterConfig struct {
 	OrgID    snowflake.ID
 	Provider string
 	Config   map[string]any
+	// Log is optional; adapters fall back to a no-op logger when nil.
+	Log *zap.Logger
 }
 
 type AdapterFactory interface {
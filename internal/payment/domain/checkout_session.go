@@ -107,4 +107,7 @@ type CheckoutSessionRepository interface {
 	FindByID(ctx context.Context, db *gorm.DB, id snowflake.ID) (*CheckoutSession, error)
 	FindByProviderSessionID(ctx context.Context, db *gorm.DB, provider, providerSessionID string) (*CheckoutSession, error)
 	FindByAnyProviderSessionID(ctx context.Context, db *gorm.DB, providerSessionID string) (*CheckoutSession, error)
+	// AttachSubscription writes the subscription created for a session in a
+	// single update so the column and metadata never disagree.
+	AttachSubscription(ctx context.Context, db *gorm.DB, session *CheckoutSession, subscriptionID string) error
 }
@@ -21,6 +21,42 @@ type EventRecord struct {
 
 func (EventRecord) TableName() string { return "payment_events" }
 
+// ProcessedWebhookEvent marks a provider event as handled so redeliveries are
+// dropped before they reach the ledger. Rows expire after a retention window.
+type ProcessedWebhookEvent struct {
+	OrgID           snowflake.ID `gorm:"primaryKey"`
+	Provider        string       `gorm:"primaryKey;type:text"`
+	ProviderEventID string       `gorm:"primaryKey;type:text"`
+	EventType       string       `gorm:"type:text;not null"`
+	ProcessedAt     time.Time    `gorm:"not null;index"`
+}
+
+func (ProcessedWebhookEvent) TableName() string { return "processed_webhook_events" }
+
+const (
+	WebhookLogStatusProcessed = "processed"
+	WebhookLogStatusFailed    = "failed"
+)
+
+// WebhookLog keeps the raw payload of a verified webhook and the outcome of
+// applying it, so an event that failed downstream can be replayed without
+// asking the provider to resend it.
+type WebhookLog struct {
+	ID              snowflake.ID   `json:"id" gorm:"primaryKey"`
+	OrgID           snowflake.ID   `json:"org_id" gorm:"not null;index"`
+	Provider        string         `json:"provider" gorm:"type:text;not null"`
+	ProviderEventID string         `json:"provider_event_id" gorm:"type:text"`
+	EventType       string         `json:"event_type" gorm:"type:text"`
+	Payload         datatypes.JSON `json:"-" gorm:"type:jsonb;not null"`
+	Status          string         `json:"status" gorm:"type:text;not null"`
+	LastError       *string        `json:"last_error,omitempty" gorm:"type:text"`
+	Attempts        int            `json:"attempts" gorm:"not null;default:1"`
+	ReceivedAt      time.Time      `json:"received_at" gorm:"not null"`
+	ProcessedAt     *time.Time     `json:"processed_at,omitempty"`
+}
+
+func (WebhookLog) TableName() string { return "payment_webhook_log" }
+
 const (
 	EventTypePaymentSucceeded         = "payment_succeeded"
 	EventTypePaymentFailed            = "payment_failed"
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+)
+
+const (
+	// RefundStatusRequested means the provider accepted the refund. Ledger
+	// effects follow with the provider's refund webhook.
+	RefundStatusRequested = "requested"
+	RefundStatusFailed    = "failed"
+)
+
+// Refund records a refund initiated against a settled payment.
+type Refund struct {
+	ID                snowflake.ID  `json:"id" gorm:"primaryKey"`
+	OrgID             snowflake.ID  `json:"org_id" gorm:"not null;index"`
+	PaymentEventID    snowflake.ID  `json:"payment_id" gorm:"not null;index"`
+	CustomerID        snowflake.ID  `json:"customer_id" gorm:"not null"`
+	InvoiceID         *snowflake.ID `json:"invoice_id,omitempty"`
+	Provider          string        `json:"provider" gorm:"type:text;not null"`
+	ProviderPaymentID string        `json:"provider_payment_id" gorm:"type:text;not null"`
+	ProviderRefundID  *string       `json:"provider_refund_id,omitempty" gorm:"type:text"`
+	Amount            int64         `json:"amount" gorm:"not null"`
+	Currency          string        `json:"currency" gorm:"type:text;not null"`
+	Reason            *string       `json:"reason,omitempty" gorm:"type:text"`
+	Status            string        `json:"status" gorm:"type:text;not null"`
+	LastError         *string       `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedAt         time.Time     `json:"created_at" gorm:"not null"`
+	UpdatedAt         time.Time     `json:"updated_at" gorm:"not null"`
+}
+
+func (Refund) TableName() string { return "payment_refunds" }
+
+// RefundPaymentRequest refunds a payment in full, or partially when Amount
+// is set.
+type RefundPaymentRequest struct {
+	PaymentID string `json:"-"`
+	Amount    *int64 `json:"amount,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// RefundRequest is what adapters send to the provider. ReferenceID is the
+// refund's own ID and doubles as the idempotency key.
+type RefundRequest struct {
+	ProviderPaymentID   string
+	ProviderPaymentType string
+	ReferenceID         string
+	CustomerID          snowflake.ID
+	InvoiceID           *snowflake.ID
+	Amount              int64
+	Currency            string
+	Reason              string
+}
+
+// ProviderRefund is the provider's view of a refund it accepted.
+type ProviderRefund struct {
+	ID     string
+	Status string
+}
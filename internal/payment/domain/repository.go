@@ -12,4 +12,13 @@ type Repository interface {
 	FindEvent(ctx context.Context, db *gorm.DB, provider string, providerEventID string) (*EventRecord, error)
 	InsertEvent(ctx context.Context, db *gorm.DB, event *EventRecord) (bool, error)
 	MarkProcessed(ctx context.Context, db *gorm.DB, id snowflake.ID, processedAt time.Time) error
+	IsWebhookEventProcessed(ctx context.Context, db *gorm.DB, orgID snowflake.ID, provider string, providerEventID string) (bool, error)
+	MarkWebhookEventProcessed(ctx context.Context, db *gorm.DB, event *ProcessedWebhookEvent) error
+	InsertWebhookLog(ctx context.Context, db *gorm.DB, entry *WebhookLog) error
+	FindWebhookLog(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*WebhookLog, error)
+	UpdateWebhookLogOutcome(ctx context.Context, db *gorm.DB, entry *WebhookLog) error
+	FindEventByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*EventRecord, error)
+	SumRefunds(ctx context.Context, db *gorm.DB, orgID, paymentEventID snowflake.ID) (int64, error)
+	InsertRefund(ctx context.Context, db *gorm.DB, refund *Refund) error
+	UpdateRefundOutcome(ctx context.Context, db *gorm.DB, refund *Refund) error
 }
@@ -10,6 +10,8 @@ import (
 
 type Service interface {
 	IngestWebhook(ctx context.Context, provider string, payload []byte, headers http.Header) error
+	ReplayWebhook(ctx context.Context, id string) (*WebhookLog, error)
+	RefundPayment(ctx context.Context, req RefundPaymentRequest) (*Refund, error)
 }
 
 type CheckoutService interface {
@@ -36,4 +38,9 @@ var (
 	ErrEventAlreadyProcessed = errors.New("event_already_processed")
 	ErrPaymentMethodNotFound = errors.New("payment_method_not_found")
 	ErrInvalidPaymentMethod  = errors.New("invalid_payment_method")
+	ErrInvalidWebhookLog     = errors.New("invalid_webhook_log")
+	ErrWebhookLogNotFound    = errors.New("webhook_log_not_found")
+	ErrInvalidPayment        = errors.New("invalid_payment")
+	ErrPaymentNotFound       = errors.New("payment_not_found")
+	ErrRefundExceedsPayment  = errors.New("refund_exceeds_payment")
 )
@@ -4,8 +4,11 @@ import (
 	"github.com/railzwaylabs/railzway/internal/payment/adapters"
 	"github.com/railzwaylabs/railzway/internal/payment/adapters/adyen"
 	"github.com/railzwaylabs/railzway/internal/payment/adapters/braintree"
+	"github.com/railzwaylabs/railzway/internal/payment/adapters/paypal"
 	"github.com/railzwaylabs/railzway/internal/payment/adapters/stripe"
 	"github.com/railzwaylabs/railzway/internal/payment/adapters/xendit"
+	creditnoterepo "github.com/railzwaylabs/railzway/internal/payment/creditnote/repository"
+	creditnoteservice "github.com/railzwaylabs/railzway/internal/payment/creditnote/service"
 	disputerepo "github.com/railzwaylabs/railzway/internal/payment/dispute/repository"
 	disputeservice "github.com/railzwaylabs/railzway/internal/payment/dispute/service"
 	"github.com/railzwaylabs/railzway/internal/payment/repository"
@@ -17,6 +20,7 @@ import (
 var Module = fx.Module("payment.service",
 	fx.Provide(repository.Provide),
 	fx.Provide(disputerepo.Provide),
+	fx.Provide(creditnoterepo.Provide),
 	fx.Provide(repository.NewCheckoutSessionRepository),
 	fx.Provide(func() *adapters.Registry {
 		return adapters.NewRegistry(
@@ -24,10 +28,12 @@ var Module = fx.Module("payment.service",
 			adyen.NewFactory(),
 			braintree.NewFactory(),
 			xendit.NewFactory(),
+			paypal.NewFactory(),
 		)
 	}),
 	fx.Provide(paymentservice.NewService),
 	fx.Provide(disputeservice.NewService),
+	fx.Provide(creditnoteservice.NewService),
 	fx.Provide(webhook.NewService),
 	fx.Provide(paymentservice.NewPaymentMethodService),
 	fx.Provide(paymentservice.NewPaymentMethodConfigService),
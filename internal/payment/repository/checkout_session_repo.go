@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/bwmarrin/snowflake"
 	"github.com/railzwaylabs/railzway/internal/payment/domain"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -78,3 +80,31 @@ func (r *checkoutSessionRepo) FindByAnyProviderSessionID(ctx context.Context, db
 	}
 	return &session, nil
 }
+
+func (r *checkoutSessionRepo) AttachSubscription(ctx context.Context, db *gorm.DB, session *domain.CheckoutSession, subscriptionID string) error {
+	if db == nil {
+		db = r.db
+	}
+	metadata := datatypes.JSONMap{}
+	for key, value := range session.Metadata {
+		metadata[key] = value
+	}
+	metadata["subscription_id"] = subscriptionID
+	now := time.Now().UTC()
+
+	if err := db.WithContext(ctx).
+		Model(&domain.CheckoutSession{}).
+		Where("id = ?", session.ID).
+		Updates(map[string]any{
+			"subscription_id": subscriptionID,
+			"metadata":        metadata,
+			"updated_at":      now,
+		}).Error; err != nil {
+		return err
+	}
+
+	session.SubscriptionID = subscriptionID
+	session.Metadata = metadata
+	session.UpdatedAt = now
+	return nil
+}
@@ -67,3 +67,162 @@ func (r *repo) MarkProcessed(ctx context.Context, db *gorm.DB, id snowflake.ID,
 		id,
 	).Error
 }
+
+func (r *repo) IsWebhookEventProcessed(ctx context.Context, db *gorm.DB, orgID snowflake.ID, provider string, providerEventID string) (bool, error) {
+	var count int64
+	err := db.WithContext(ctx).Raw(
+		`SELECT COUNT(1)
+		 FROM processed_webhook_events
+		 WHERE org_id = ? AND provider = ? AND provider_event_id = ?`,
+		orgID,
+		provider,
+		providerEventID,
+	).Scan(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *repo) MarkWebhookEventProcessed(ctx context.Context, db *gorm.DB, event *domain.ProcessedWebhookEvent) error {
+	return db.WithContext(ctx).Exec(
+		`INSERT INTO processed_webhook_events (
+			org_id, provider, provider_event_id, event_type, processed_at
+		) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (org_id, provider, provider_event_id) DO NOTHING`,
+		event.OrgID,
+		event.Provider,
+		event.ProviderEventID,
+		event.EventType,
+		event.ProcessedAt,
+	).Error
+}
+
+func (r *repo) InsertWebhookLog(ctx context.Context, db *gorm.DB, entry *domain.WebhookLog) error {
+	return db.WithContext(ctx).Exec(
+		`INSERT INTO payment_webhook_log (
+			id, org_id, provider, provider_event_id, event_type, payload,
+			status, last_error, attempts, received_at, processed_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.ID,
+		entry.OrgID,
+		entry.Provider,
+		entry.ProviderEventID,
+		entry.EventType,
+		entry.Payload,
+		entry.Status,
+		entry.LastError,
+		entry.Attempts,
+		entry.ReceivedAt,
+		entry.ProcessedAt,
+	).Error
+}
+
+func (r *repo) FindWebhookLog(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*domain.WebhookLog, error) {
+	var entry domain.WebhookLog
+	err := db.WithContext(ctx).Raw(
+		`SELECT id, org_id, provider, provider_event_id, event_type, payload,
+			status, last_error, attempts, received_at, processed_at
+		 FROM payment_webhook_log
+		 WHERE org_id = ? AND id = ?`,
+		orgID,
+		id,
+	).Scan(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	if entry.ID == 0 {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (r *repo) UpdateWebhookLogOutcome(ctx context.Context, db *gorm.DB, entry *domain.WebhookLog) error {
+	return db.WithContext(ctx).Exec(
+		`UPDATE payment_webhook_log
+		 SET status = ?, last_error = ?, attempts = ?, processed_at = ?
+		 WHERE org_id = ? AND id = ?`,
+		entry.Status,
+		entry.LastError,
+		entry.Attempts,
+		entry.ProcessedAt,
+		entry.OrgID,
+		entry.ID,
+	).Error
+}
+
+func (r *repo) FindEventByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*domain.EventRecord, error) {
+	var item domain.EventRecord
+	err := db.WithContext(ctx).Raw(
+		`SELECT id, org_id, provider, provider_event_id, event_type, customer_id,
+			payload, received_at, processed_at
+		 FROM payment_events
+		 WHERE org_id = ? AND id = ?
+		 LIMIT 1`,
+		orgID,
+		id,
+	).Scan(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	if item.ID == 0 {
+		return nil, nil
+	}
+	return &item, nil
+}
+
+// SumRefunds totals the refunds of a payment that have not failed.
+func (r *repo) SumRefunds(ctx context.Context, db *gorm.DB, orgID, paymentEventID snowflake.ID) (int64, error) {
+	var total int64
+	err := db.WithContext(ctx).Raw(
+		`SELECT COALESCE(SUM(amount), 0)
+		 FROM payment_refunds
+		 WHERE org_id = ? AND payment_event_id = ? AND status <> ?`,
+		orgID,
+		paymentEventID,
+		domain.RefundStatusFailed,
+	).Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *repo) InsertRefund(ctx context.Context, db *gorm.DB, refund *domain.Refund) error {
+	return db.WithContext(ctx).Exec(
+		`INSERT INTO payment_refunds (
+			id, org_id, payment_event_id, customer_id, invoice_id, provider,
+			provider_payment_id, provider_refund_id, amount, currency, reason,
+			status, last_error, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		refund.ID,
+		refund.OrgID,
+		refund.PaymentEventID,
+		refund.CustomerID,
+		refund.InvoiceID,
+		refund.Provider,
+		refund.ProviderPaymentID,
+		refund.ProviderRefundID,
+		refund.Amount,
+		refund.Currency,
+		refund.Reason,
+		refund.Status,
+		refund.LastError,
+		refund.CreatedAt,
+		refund.UpdatedAt,
+	).Error
+}
+
+func (r *repo) UpdateRefundOutcome(ctx context.Context, db *gorm.DB, refund *domain.Refund) error {
+	return db.WithContext(ctx).Exec(
+		`UPDATE payment_refunds
+		 SET provider_refund_id = ?, status = ?, last_error = ?, updated_at = ?
+		 WHERE org_id = ? AND id = ?`,
+		refund.ProviderRefundID,
+		refund.Status,
+		refund.LastError,
+		refund.UpdatedAt,
+		refund.OrgID,
+		refund.ID,
+	).Error
+}
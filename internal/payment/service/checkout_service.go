@@ -11,6 +11,7 @@ import (
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
 	"github.com/railzwaylabs/railzway/internal/payment/adapters"
 	"github.com/railzwaylabs/railzway/internal/payment/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
 	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
 	providerservice "github.com/railzwaylabs/railzway/internal/providers/payment/domain"
 	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
@@ -26,6 +27,7 @@ type CheckoutServiceParams struct {
 	ProviderService      providerservice.Service
 	PaymentMethodService domain.PaymentMethodService
 	SubscriptionService  subscriptiondomain.Service
+	PriceService         pricedomain.Service
 	PriceAmountService   priceamountdomain.Service
 	Repo                 domain.CheckoutSessionRepository
 	GenID                *snowflake.Node
@@ -38,6 +40,7 @@ type CheckoutServiceImpl struct {
 	providerService      providerservice.Service
 	paymentMethodService domain.PaymentMethodService
 	subscriptionService  subscriptiondomain.Service
+	priceService         pricedomain.Service
 	priceAmountService   priceamountdomain.Service
 	repo                 domain.CheckoutSessionRepository
 	genID                *snowflake.Node
@@ -51,6 +54,7 @@ func NewCheckoutService(p CheckoutServiceParams) domain.CheckoutService {
 		providerService:      p.ProviderService,
 		paymentMethodService: p.PaymentMethodService,
 		subscriptionService:  p.SubscriptionService,
+		priceService:         p.PriceService,
 		priceAmountService:   p.PriceAmountService,
 		repo:                 p.Repo,
 		genID:                p.GenID,
@@ -162,6 +166,7 @@ func (s *CheckoutServiceImpl) CreateSession(ctx context.Context, input domain.Ch
 		OrgID:    orgID,
 		Provider: input.Provider,
 		Config:   configMap,
+		Log:      s.logger,
 	})
 	if err != nil {
 		return nil, err
@@ -376,6 +381,9 @@ func (s *CheckoutServiceImpl) CompleteSession(ctx context.Context, provider, pro
 	if err != nil {
 		return nil, err
 	}
+	if session == nil {
+		return nil, domain.ErrCheckoutSessionNotFound
+	}
 
 	// 2. Idempotency Check - if already completed, return existing
 	if session.Status == domain.CheckoutSessionStatusComplete {
@@ -399,6 +407,7 @@ func (s *CheckoutServiceImpl) CompleteSession(ctx context.Context, provider, pro
 		OrgID:    session.OrgID,
 		Provider: provider,
 		Config:   configMap,
+		Log:      s.logger,
 	})
 	if err != nil {
 		return nil, err
@@ -414,91 +423,148 @@ func (s *CheckoutServiceImpl) CompleteSession(ctx context.Context, provider, pro
 		// No need to manually attach payment method here
 	}
 
-	// c. Update Status
+	// b. Resolve the subscription plan before completing, so an unresolvable
+	// billing interval fails the completion instead of defaulting.
+	items, billingCycleType, err := s.resolveCheckoutPlan(ctx, session)
+	if err != nil {
+		s.logger.Error("failed to resolve billing cycle for checkout session",
+			zap.Error(err),
+			zap.String("session_id", session.ID.String()))
+		return nil, err
+	}
+
+	// c. Create Subscription via Service. Creation is keyed on the session,
+	// so a completion retried after a failure below resolves to the same
+	// subscription instead of creating another one.
+	subscriptionID := ""
+	if session.CustomerID != nil && len(items) > 0 {
+		customerID := session.CustomerID.String()
+		s.logger.Info("creating subscription from checkout session",
+			zap.String("customer_id", customerID),
+			zap.Int("item_count", len(items)),
+			zap.String("billing_cycle_type", billingCycleType),
+			zap.String("session_id", session.ID.String()))
+
+		req := subscriptiondomain.CreateSubscriptionRequest{
+			CustomerID:       customerID,
+			BillingCycleType: billingCycleType,
+			CollectionMode:   subscriptiondomain.SubscriptionCollectionModeChargeAutomatically,
+			Items:            items,
+			// Tie the subscription to the session so concurrent or repeated
+			// completions resolve to the same subscription.
+			IdempotencyKey: session.ID.String(),
+		}
+
+		subResp, err := s.subscriptionService.Create(ctx, req)
+		if err != nil {
+			s.logger.Error("failed to create subscription",
+				zap.Error(err),
+				zap.String("customer_id", customerID),
+				zap.String("session_id", session.ID.String()))
+			return nil, err
+		}
+
+		// d. Transition to Active, unless a previous attempt already did.
+		if subResp.Status == subscriptiondomain.SubscriptionStatusDraft {
+			if err := s.subscriptionService.TransitionSubscription(ctx, subResp.ID, subscriptiondomain.SubscriptionStatusActive, "checkout_session"); err != nil {
+				s.logger.Error("failed to transition subscription to active",
+					zap.Error(err),
+					zap.String("subscription_id", subResp.ID))
+				return nil, err
+			}
+		}
+		subscriptionID = subResp.ID
+	} else if session.CustomerID != nil {
+		s.logger.Warn("no line items or price_id in session, skipping subscription creation",
+			zap.String("session_id", session.ID.String()))
+	}
+
+	// e. Complete the session and link its subscription together, so a
+	// session is never complete without the subscription it paid for.
 	session.Status = domain.CheckoutSessionStatusComplete
 	session.PaymentStatus = domain.PaymentStatusPaid
 	now := time.Now().UTC()
 	session.CompletedAt = &now
 
-	if err := s.repo.Update(ctx, nil, session); err != nil {
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.Update(ctx, tx, session); err != nil {
+			return err
+		}
+		if subscriptionID == "" {
+			return nil
+		}
+		return s.repo.AttachSubscription(ctx, tx, session, subscriptionID)
+	}); err != nil {
+		s.logger.Error("failed to complete checkout session",
+			zap.Error(err),
+			zap.String("session_id", session.ID.String()))
 		return nil, err
 	}
 
-	// d. Create Subscription via Service
-	if session.CustomerID != nil {
-		customerID := session.CustomerID.String()
+	return session, nil
+}
+
+// resolveCheckoutPlan builds the subscription items for a session from its
+// stored line items, falling back to the legacy price_id metadata, and the
+// billing cycle type shared by their prices. Sessions without a customer or
+// price create no subscription and resolve to no items.
+func (s *CheckoutServiceImpl) resolveCheckoutPlan(ctx context.Context, session *domain.CheckoutSession) ([]subscriptiondomain.CreateSubscriptionItemRequest, string, error) {
+	if session.CustomerID == nil {
+		return nil, "", nil
+	}
 
-		// Parse Line Items or Metadata
-		priceID := ""
+	var items []subscriptiondomain.CreateSubscriptionItemRequest
+	if len(session.LineItems) > 0 {
+		lineItems, err := s.GetLineItems(ctx, session.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, item := range lineItems {
+			items = append(items, subscriptiondomain.CreateSubscriptionItemRequest{
+				PriceID:  item.PriceID,
+				Quantity: int32(item.Quantity),
+			})
+		}
+	}
+
+	if len(items) == 0 {
 		if val, ok := session.Metadata["price_id"]; ok {
-			if priceIDStr, ok := val.(string); ok {
-				priceID = priceIDStr
-			} else {
+			if priceIDStr, ok := val.(string); ok && strings.TrimSpace(priceIDStr) != "" {
+				items = append(items, subscriptiondomain.CreateSubscriptionItemRequest{
+					PriceID:  strings.TrimSpace(priceIDStr),
+					Quantity: 1,
+				})
+			} else if !ok {
 				s.logger.Warn("price_id in metadata is not a string",
 					zap.String("session_id", session.ID.String()),
 					zap.Any("price_id_value", val))
 			}
 		}
+	}
+	if len(items) == 0 {
+		return nil, "", nil
+	}
 
-		if priceID != "" {
-			s.logger.Info("creating subscription from checkout session",
-				zap.String("customer_id", customerID),
-				zap.String("price_id", priceID),
-				zap.String("session_id", session.ID.String()))
-
-			req := subscriptiondomain.CreateSubscriptionRequest{
-				CustomerID:       customerID,
-				BillingCycleType: "monthly", // Default to monthly for checkout sessions
-				CollectionMode:   subscriptiondomain.SubscriptionCollectionModeChargeAutomatically,
-				Items: []subscriptiondomain.CreateSubscriptionItemRequest{
-					{PriceID: priceID, Quantity: 1},
-				},
-			}
+	billingCycleType := ""
+	for _, item := range items {
+		price, err := s.priceService.Get(ctx, item.PriceID)
+		if err != nil {
+			return nil, "", err
+		}
+		if price == nil {
+			return nil, "", pricedomain.ErrNotFound
+		}
 
-			subResp, err := s.subscriptionService.Create(ctx, req)
-			if err != nil {
-				s.logger.Error("failed to create subscription",
-					zap.Error(err),
-					zap.String("customer_id", customerID),
-					zap.String("price_id", priceID),
-					zap.String("session_id", session.ID.String()))
-				// Don't fail the entire checkout completion, but log prominently
-				// The session is still marked as complete, subscription can be retried
-			} else {
-				s.logger.Info("subscription created successfully",
-					zap.String("subscription_id", subResp.ID),
-					zap.String("customer_id", customerID))
-
-				// e. Transition to Active
-				err = s.subscriptionService.TransitionSubscription(ctx, subResp.ID, subscriptiondomain.SubscriptionStatusActive, "checkout_session")
-				if err != nil {
-					s.logger.Error("failed to transition subscription to active",
-						zap.Error(err),
-						zap.String("subscription_id", subResp.ID))
-				} else {
-					s.logger.Info("subscription transitioned to active",
-						zap.String("subscription_id", subResp.ID))
-				}
-
-				// Save subscription ID to metadata and DTO
-				if session.Metadata == nil {
-					session.Metadata = make(map[string]any)
-				}
-				session.Metadata["subscription_id"] = subResp.ID
-				session.SubscriptionID = subResp.ID
-
-				// Update session with new metadata
-				if err := s.repo.Update(ctx, nil, session); err != nil {
-					s.logger.Error("failed to update session metadata with subscription_id", zap.Error(err))
-				}
-			}
-		} else {
-			s.logger.Warn("no price_id in session metadata, skipping subscription creation",
-				zap.String("session_id", session.ID.String()))
+		cycleType, err := subscriptiondomain.BillingCycleTypeForInterval(price.BillingInterval)
+		if err != nil {
+			return nil, "", err
 		}
+		if billingCycleType != "" && cycleType != billingCycleType {
+			return nil, "", subscriptiondomain.ErrInvalidBillingCycleType
+		}
+		billingCycleType = cycleType
 	}
-
-	return session, nil
+	return items, billingCycleType, nil
 }
 
 // VerifyAndComplete verifies a checkout session and completes it if payment succeeded
@@ -576,6 +642,7 @@ func (s *CheckoutServiceImpl) VerifyAndComplete(ctx context.Context, sessionID s
 		OrgID:    session.OrgID,
 		Provider: session.Provider,
 		Config:   configMap,
+		Log:      s.logger,
 	})
 	if err != nil {
 		return nil, err
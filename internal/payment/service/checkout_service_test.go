@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/payment/adapters"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	"github.com/railzwaylabs/railzway/internal/payment/repository"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type fakeCheckoutAdapter struct {
+	paymentdomain.PaymentAdapter
+}
+
+func (fakeCheckoutAdapter) RetrieveCheckoutSession(ctx context.Context, providerSessionID string) (*paymentdomain.ProviderCheckoutSession, error) {
+	return &paymentdomain.ProviderCheckoutSession{ID: providerSessionID, Status: paymentdomain.CheckoutSessionStatusComplete}, nil
+}
+
+type fakeCheckoutAdapterFactory struct{}
+
+func (fakeCheckoutAdapterFactory) Provider() string { return "stripe" }
+
+func (fakeCheckoutAdapterFactory) NewAdapter(cfg paymentdomain.AdapterConfig) (paymentdomain.PaymentAdapter, error) {
+	return fakeCheckoutAdapter{}, nil
+}
+
+type fakeCheckoutSubscriptions struct {
+	subscriptiondomain.Service
+	genID     *snowflake.Node
+	createErr error
+	requests  []subscriptiondomain.CreateSubscriptionRequest
+	created   map[string]subscriptiondomain.CreateSubscriptionResponse
+	activated []string
+}
+
+func (f *fakeCheckoutSubscriptions) Create(ctx context.Context, req subscriptiondomain.CreateSubscriptionRequest) (subscriptiondomain.CreateSubscriptionResponse, error) {
+	f.requests = append(f.requests, req)
+	if f.createErr != nil {
+		err := f.createErr
+		f.createErr = nil
+		return subscriptiondomain.CreateSubscriptionResponse{}, err
+	}
+	if existing, ok := f.created[req.IdempotencyKey]; ok {
+		return existing, nil
+	}
+	resp := subscriptiondomain.CreateSubscriptionResponse{
+		ID:         f.genID.Generate().String(),
+		CustomerID: req.CustomerID,
+		Status:     subscriptiondomain.SubscriptionStatusDraft,
+	}
+	f.created[req.IdempotencyKey] = resp
+	return resp, nil
+}
+
+func (f *fakeCheckoutSubscriptions) TransitionSubscription(ctx context.Context, subscriptionID string, targetStatus subscriptiondomain.SubscriptionStatus, reason subscriptiondomain.TransitionReason) error {
+	f.activated = append(f.activated, subscriptionID)
+	for key, resp := range f.created {
+		if resp.ID == subscriptionID {
+			resp.Status = targetStatus
+			f.created[key] = resp
+		}
+	}
+	return nil
+}
+
+type fakeCheckoutPrices struct {
+	pricedomain.Service
+	intervals map[string]pricedomain.BillingInterval
+}
+
+func (f fakeCheckoutPrices) Get(ctx context.Context, id string) (*pricedomain.Response, error) {
+	interval, ok := f.intervals[id]
+	if !ok {
+		return nil, pricedomain.ErrNotFound
+	}
+	return &pricedomain.Response{BillingInterval: interval}, nil
+}
+
+type fakeCheckoutPriceAmounts struct {
+	priceamountdomain.Service
+}
+
+func (fakeCheckoutPriceAmounts) List(ctx context.Context, req priceamountdomain.ListPriceAmountRequest) (priceamountdomain.ListPriceAmountResponse, error) {
+	return priceamountdomain.ListPriceAmountResponse{
+		Amounts: []priceamountdomain.Response{{Currency: "USD", UnitAmountCents: 1000}},
+	}, nil
+}
+
+type checkoutTestEnv struct {
+	db    *gorm.DB
+	node  *snowflake.Node
+	ctx   context.Context
+	orgID snowflake.ID
+	svc   *CheckoutServiceImpl
+	subs  *fakeCheckoutSubscriptions
+}
+
+func newCheckoutTestEnv(t *testing.T, intervals map[string]pricedomain.BillingInterval) *checkoutTestEnv {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&paymentdomain.CheckoutSession{}))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	subs := &fakeCheckoutSubscriptions{genID: node, created: map[string]subscriptiondomain.CreateSubscriptionResponse{}}
+	svc := NewCheckoutService(CheckoutServiceParams{
+		Registry:            adapters.NewRegistry(fakeCheckoutAdapterFactory{}),
+		ProviderService:     &fakeProviderConfigs{},
+		SubscriptionService: subs,
+		PriceService:        fakeCheckoutPrices{intervals: intervals},
+		PriceAmountService:  fakeCheckoutPriceAmounts{},
+		Repo:                repository.NewCheckoutSessionRepository(db),
+		GenID:               node,
+		Logger:              zap.NewNop(),
+		DB:                  db,
+	}).(*CheckoutServiceImpl)
+
+	return &checkoutTestEnv{
+		db:    db,
+		node:  node,
+		ctx:   orgcontext.WithOrgID(context.Background(), int64(orgID)),
+		orgID: orgID,
+		svc:   svc,
+		subs:  subs,
+	}
+}
+
+func (e *checkoutTestEnv) insertSession(t *testing.T, lineItems string, metadata datatypes.JSONMap) *paymentdomain.CheckoutSession {
+	customerID := e.node.Generate()
+	now := time.Now().UTC()
+	if metadata == nil {
+		metadata = datatypes.JSONMap{}
+	}
+	session := &paymentdomain.CheckoutSession{
+		ID:                e.node.Generate(),
+		OrgID:             e.orgID,
+		CustomerID:        &customerID,
+		Provider:          "stripe",
+		Status:            paymentdomain.CheckoutSessionStatusOpen,
+		PaymentStatus:     paymentdomain.PaymentStatusUnpaid,
+		Currency:          "USD",
+		ProviderSessionID: "cs_" + e.node.Generate().String(),
+		Metadata:          metadata,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if lineItems != "" {
+		session.LineItems = datatypes.JSON(lineItems)
+	}
+	require.NoError(t, e.db.Create(session).Error)
+	return session
+}
+
+func (e *checkoutTestEnv) reload(t *testing.T, id snowflake.ID) paymentdomain.CheckoutSession {
+	var session paymentdomain.CheckoutSession
+	require.NoError(t, e.db.First(&session, id).Error)
+	return session
+}
+
+func TestCompleteSession_RetriedUntilSubscriptionLinked(t *testing.T) {
+	priceID := "1001"
+	env := newCheckoutTestEnv(t, map[string]pricedomain.BillingInterval{priceID: pricedomain.Month})
+	session := env.insertSession(t, "", datatypes.JSONMap{"price_id": priceID})
+
+	// A failed subscription create fails the completion and leaves the
+	// session open for the redelivered webhook.
+	env.subs.createErr = errors.New("subscriptions unavailable")
+	_, err := env.svc.CompleteSession(env.ctx, "stripe", session.ProviderSessionID)
+	require.Error(t, err)
+	stored := env.reload(t, session.ID)
+	assert.Equal(t, paymentdomain.CheckoutSessionStatusOpen, stored.Status)
+	assert.Empty(t, stored.SubscriptionID)
+
+	// A failed link rolls back the status update made before it.
+	failLink := true
+	require.NoError(t, env.db.Callback().Update().Before("gorm:update").Register("test:fail_link", func(tx *gorm.DB) {
+		if _, linking := tx.Statement.Dest.(map[string]any); linking && failLink {
+			_ = tx.AddError(errors.New("database unavailable"))
+		}
+	}))
+	_, err = env.svc.CompleteSession(env.ctx, "stripe", session.ProviderSessionID)
+	require.Error(t, err)
+	stored = env.reload(t, session.ID)
+	assert.Equal(t, paymentdomain.CheckoutSessionStatusOpen, stored.Status)
+	assert.Empty(t, stored.SubscriptionID)
+	require.Len(t, env.subs.created, 1)
+
+	failLink = false
+	completed, err := env.svc.CompleteSession(env.ctx, "stripe", session.ProviderSessionID)
+	require.NoError(t, err)
+	subscriptionID := env.subs.created[session.ID.String()].ID
+	assert.Equal(t, subscriptionID, completed.SubscriptionID)
+
+	// A duplicate completion webhook returns the linked session as is.
+	duplicate, err := env.svc.CompleteSession(env.ctx, "stripe", session.ProviderSessionID)
+	require.NoError(t, err)
+	assert.Equal(t, subscriptionID, duplicate.SubscriptionID)
+
+	stored = env.reload(t, session.ID)
+	assert.Equal(t, paymentdomain.CheckoutSessionStatusComplete, stored.Status)
+	assert.Equal(t, paymentdomain.PaymentStatusPaid, stored.PaymentStatus)
+	assert.Equal(t, subscriptionID, stored.SubscriptionID)
+	assert.Equal(t, subscriptionID, stored.Metadata["subscription_id"])
+	assert.Len(t, env.subs.requests, 3)
+	assert.Len(t, env.subs.created, 1)
+	assert.Equal(t, []string{subscriptionID}, env.subs.activated)
+}
+
+func TestCompleteSession_BillingCycleFromPriceInterval(t *testing.T) {
+	cases := []struct {
+		name      string
+		priceID   string
+		wantCycle string
+		wantErr   error
+	}{
+		{name: "monthly", priceID: "1001", wantCycle: "monthly"},
+		{name: "yearly", priceID: "1002", wantCycle: "yearly"},
+		{name: "unknown_price", priceID: "9999", wantErr: pricedomain.ErrNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newCheckoutTestEnv(t, map[string]pricedomain.BillingInterval{
+				"1001": pricedomain.Month,
+				"1002": pricedomain.Year,
+			})
+			session := env.insertSession(t, "", datatypes.JSONMap{"price_id": tc.priceID})
+
+			completed, err := env.svc.CompleteSession(env.ctx, "stripe", session.ProviderSessionID)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				// An unresolvable plan never defaults to a cycle type: no
+				// subscription is created and the session stays open.
+				assert.Empty(t, env.subs.requests)
+				assert.Equal(t, paymentdomain.CheckoutSessionStatusOpen, env.reload(t, session.ID).Status)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, env.subs.requests, 1)
+			req := env.subs.requests[0]
+			assert.Equal(t, tc.wantCycle, req.BillingCycleType)
+			assert.Equal(t, []subscriptiondomain.CreateSubscriptionItemRequest{{PriceID: tc.priceID, Quantity: 1}}, req.Items)
+			assert.Equal(t, session.ID.String(), req.IdempotencyKey)
+			assert.NotEmpty(t, completed.SubscriptionID)
+		})
+	}
+}
+
+func TestCompleteSession_SubscriptionItemsFromLineItems(t *testing.T) {
+	intervals := map[string]pricedomain.BillingInterval{
+		"1001": pricedomain.Month,
+		"1002": pricedomain.Month,
+		"1003": pricedomain.Year,
+	}
+
+	t.Run("all_line_items", func(t *testing.T) {
+		env := newCheckoutTestEnv(t, intervals)
+		// Stored line items take precedence over the legacy metadata price.
+		session := env.insertSession(t,
+			`[{"price_id":"1001","quantity":2},{"price_id":"1002","quantity":5}]`,
+			datatypes.JSONMap{"price_id": "1003"},
+		)
+
+		_, err := env.svc.CompleteSession(env.ctx, "stripe", session.ProviderSessionID)
+		require.NoError(t, err)
+		require.Len(t, env.subs.requests, 1)
+		assert.Equal(t, "monthly", env.subs.requests[0].BillingCycleType)
+		assert.Equal(t, []subscriptiondomain.CreateSubscriptionItemRequest{
+			{PriceID: "1001", Quantity: 2},
+			{PriceID: "1002", Quantity: 5},
+		}, env.subs.requests[0].Items)
+	})
+
+	t.Run("metadata_price_fallback", func(t *testing.T) {
+		env := newCheckoutTestEnv(t, intervals)
+		session := env.insertSession(t, `[]`, datatypes.JSONMap{"price_id": " 1003 "})
+
+		_, err := env.svc.CompleteSession(env.ctx, "stripe", session.ProviderSessionID)
+		require.NoError(t, err)
+		require.Len(t, env.subs.requests, 1)
+		assert.Equal(t, "yearly", env.subs.requests[0].BillingCycleType)
+		assert.Equal(t, []subscriptiondomain.CreateSubscriptionItemRequest{
+			{PriceID: "1003", Quantity: 1},
+		}, env.subs.requests[0].Items)
+	})
+
+	t.Run("mixed_intervals", func(t *testing.T) {
+		env := newCheckoutTestEnv(t, intervals)
+		session := env.insertSession(t,
+			`[{"price_id":"1001","quantity":1},{"price_id":"1003","quantity":1}]`,
+			nil,
+		)
+
+		_, err := env.svc.CompleteSession(env.ctx, "stripe", session.ProviderSessionID)
+		require.ErrorIs(t, err, subscriptiondomain.ErrInvalidBillingCycleType)
+		assert.Empty(t, env.subs.requests)
+		assert.Equal(t, paymentdomain.CheckoutSessionStatusOpen, env.reload(t, session.ID).Status)
+	})
+}
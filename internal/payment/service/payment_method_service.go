@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
 	"github.com/railzwaylabs/railzway/internal/payment/adapters"
 	"github.com/railzwaylabs/railzway/internal/payment/domain"
 	providerservice "github.com/railzwaylabs/railzway/internal/providers/payment/domain"
@@ -40,33 +41,13 @@ func (s *PaymentMethodServiceImpl) AttachPaymentMethod(
 	provider, token string,
 ) (*domain.PaymentMethod, error) {
 	// 1. Get customer and their provider customer ID
-	var customer struct {
-		OrgID              snowflake.ID
-		ProviderCustomerID string
-	}
-	if err := s.db.Table("customers").
-		Select("org_id, provider_customer_id").
-		Where("id = ?", customerID).
-		First(&customer).Error; err != nil {
-		return nil, err
-	}
-
-	// 2. Get provider config and create adapter
-	providerConfig, err := s.providerService.GetActiveProviderConfig(ctx, customer.OrgID, provider)
+	customer, err := s.loadCustomer(ctx, s.db, customerID)
 	if err != nil {
 		return nil, err
 	}
 
-	var configMap map[string]any
-	if err := json.Unmarshal(providerConfig.Config, &configMap); err != nil {
-		return nil, err
-	}
-
-	adapter, err := s.adapterRegistry.NewAdapter(provider, domain.AdapterConfig{
-		OrgID:    customer.OrgID,
-		Provider: provider,
-		Config:   configMap,
-	})
+	// 2. Get provider config and create adapter
+	adapter, err := s.newAdapter(ctx, customer.OrgID, provider)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +58,9 @@ func (s *PaymentMethodServiceImpl) AttachPaymentMethod(
 		return nil, err
 	}
 
-	// 4. Save to database
+	// 4. Save to database. The first method of a provider becomes its
+	// default.
+	now := time.Now().UTC()
 	pm := &domain.PaymentMethod{
 		ID:                      s.idGen.Generate(),
 		CustomerID:              customerID,
@@ -88,100 +71,108 @@ func (s *PaymentMethodServiceImpl) AttachPaymentMethod(
 		Brand:                   pmDetails.Brand,
 		ExpMonth:                pmDetails.ExpMonth,
 		ExpYear:                 pmDetails.ExpYear,
-		IsDefault:               false,
-		CreatedAt:               time.Now().UTC(),
-		UpdatedAt:               time.Now().UTC(),
+		CreatedAt:               now,
+		UpdatedAt:               now,
 	}
 
-	if err := s.db.Create(pm).Error; err != nil {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var defaults int64
+		if err := tx.Model(&domain.PaymentMethod{}).
+			Where("customer_id = ? AND provider = ? AND is_default = true", customerID, provider).
+			Count(&defaults).Error; err != nil {
+			return err
+		}
+		pm.IsDefault = defaults == 0
+		return tx.Create(pm).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// 5. If this is the first payment method, make it default
-	var count int64
-	s.db.Model(&domain.PaymentMethod{}).Where("customer_id = ?", customerID).Count(&count)
-	if count == 1 {
-		pm.IsDefault = true
-		s.db.Save(pm)
-	}
-
 	return pm, nil
 }
 
+// ListPaymentMethods returns the customer's payment methods, defaults first.
+// Card details are refreshed from each provider that can list them; stored
+// details are returned as they are when the provider cannot be reached.
 func (s *PaymentMethodServiceImpl) ListPaymentMethods(
 	ctx context.Context,
 	customerID snowflake.ID,
 ) ([]*domain.PaymentMethod, error) {
+	customer, err := s.loadCustomer(ctx, s.db, customerID)
+	if err != nil {
+		return nil, err
+	}
+
 	var pms []*domain.PaymentMethod
-	if err := s.db.Where("customer_id = ?", customerID).
+	if err := s.db.WithContext(ctx).Where("customer_id = ?", customerID).
 		Order("is_default DESC, created_at DESC").
 		Find(&pms).Error; err != nil {
 		return nil, err
 	}
+
+	byProvider := make(map[string][]*domain.PaymentMethod)
+	for _, pm := range pms {
+		byProvider[pm.Provider] = append(byProvider[pm.Provider], pm)
+	}
+	for provider, stored := range byProvider {
+		s.refreshPaymentMethods(ctx, customer, provider, stored)
+	}
+
 	return pms, nil
 }
 
+// SetDefaultPaymentMethod makes a payment method the default of its
+// provider, replacing the customer's previous default for that provider.
 func (s *PaymentMethodServiceImpl) SetDefaultPaymentMethod(
 	ctx context.Context,
 	customerID, paymentMethodID snowflake.ID,
 ) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	if _, err := s.loadCustomer(ctx, s.db, customerID); err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Verify ownership
-		var pm domain.PaymentMethod
-		if err := tx.Where("id = ? AND customer_id = ?", paymentMethodID, customerID).
-			First(&pm).Error; err != nil {
+		pm, err := s.findPaymentMethod(tx, customerID, paymentMethodID)
+		if err != nil {
 			return err
 		}
 
-		// Unset current default
-		tx.Model(&domain.PaymentMethod{}).
-			Where("customer_id = ? AND is_default = true", customerID).
-			Update("is_default", false)
+		// Unset the provider's current default
+		now := time.Now().UTC()
+		if err := tx.Model(&domain.PaymentMethod{}).
+			Where("customer_id = ? AND provider = ? AND is_default = true AND id <> ?", customerID, pm.Provider, pm.ID).
+			Updates(map[string]any{"is_default": false, "updated_at": now}).Error; err != nil {
+			return err
+		}
 
 		// Set new default
 		pm.IsDefault = true
-		pm.UpdatedAt = time.Now().UTC()
-		return tx.Save(&pm).Error
+		pm.UpdatedAt = now
+		return tx.Save(pm).Error
 	})
 }
 
+// DetachPaymentMethod detaches a payment method at its provider and deletes
+// it. When it was the provider's default, the customer's newest remaining
+// method of that provider takes its place.
 func (s *PaymentMethodServiceImpl) DetachPaymentMethod(
 	ctx context.Context,
 	customerID, paymentMethodID snowflake.ID,
 ) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		var pm domain.PaymentMethod
-		if err := tx.Where("id = ? AND customer_id = ?", paymentMethodID, customerID).
-			First(&pm).Error; err != nil {
-			return err
-		}
-
-		// Get provider config
-		var customer struct {
-			OrgID snowflake.ID
-		}
-		if err := tx.Table("customers").
-			Select("org_id").
-			Where("id = ?", customerID).
-			First(&customer).Error; err != nil {
-			return err
-		}
-
-		providerConfig, err := s.providerService.GetActiveProviderConfig(ctx, customer.OrgID, pm.Provider)
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		customer, err := s.loadCustomer(ctx, tx, customerID)
 		if err != nil {
 			return err
 		}
 
-		var configMap map[string]any
-		if err := json.Unmarshal(providerConfig.Config, &configMap); err != nil {
+		pm, err := s.findPaymentMethod(tx, customerID, paymentMethodID)
+		if err != nil {
 			return err
 		}
 
-		adapter, err := s.adapterRegistry.NewAdapter(pm.Provider, domain.AdapterConfig{
-			OrgID:    customer.OrgID,
-			Provider: pm.Provider,
-			Config:   configMap,
-		})
+		adapter, err := s.newAdapter(ctx, customer.OrgID, pm.Provider)
 		if err != nil {
 			return err
 		}
@@ -192,16 +183,38 @@ func (s *PaymentMethodServiceImpl) DetachPaymentMethod(
 		}
 
 		// Delete from database
-		return tx.Delete(&pm).Error
+		if err := tx.Delete(pm).Error; err != nil {
+			return err
+		}
+		if !pm.IsDefault {
+			return nil
+		}
+
+		var next domain.PaymentMethod
+		err = tx.Where("customer_id = ? AND provider = ?", customerID, pm.Provider).
+			Order("created_at DESC, id DESC").
+			First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		next.IsDefault = true
+		next.UpdatedAt = time.Now().UTC()
+		return tx.Save(&next).Error
 	})
 }
 
+// GetDefaultPaymentMethod returns the default the customer chose most
+// recently when they have one per provider.
 func (s *PaymentMethodServiceImpl) GetDefaultPaymentMethod(
 	ctx context.Context,
 	customerID snowflake.ID,
 ) (*domain.PaymentMethod, error) {
 	var pm domain.PaymentMethod
-	if err := s.db.Where("customer_id = ? AND is_default = true", customerID).
+	if err := s.db.WithContext(ctx).Where("customer_id = ? AND is_default = true", customerID).
+		Order("updated_at DESC").
 		First(&pm).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrPaymentMethodNotFound
@@ -210,3 +223,106 @@ func (s *PaymentMethodServiceImpl) GetDefaultPaymentMethod(
 	}
 	return &pm, nil
 }
+
+type paymentMethodCustomer struct {
+	ID                 snowflake.ID
+	OrgID              snowflake.ID
+	ProviderCustomerID string
+}
+
+// loadCustomer loads the customer, rejecting one outside the caller's
+// organization.
+func (s *PaymentMethodServiceImpl) loadCustomer(ctx context.Context, db *gorm.DB, customerID snowflake.ID) (*paymentMethodCustomer, error) {
+	var customer paymentMethodCustomer
+	if err := db.WithContext(ctx).Table("customers").
+		Select("id, org_id, provider_customer_id").
+		Where("id = ?", customerID).
+		First(&customer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInvalidCustomer
+		}
+		return nil, err
+	}
+	if orgID, ok := orgcontext.OrgIDFromContext(ctx); ok && orgID != 0 && snowflake.ID(orgID) != customer.OrgID {
+		return nil, domain.ErrInvalidCustomer
+	}
+	return &customer, nil
+}
+
+func (s *PaymentMethodServiceImpl) findPaymentMethod(tx *gorm.DB, customerID, paymentMethodID snowflake.ID) (*domain.PaymentMethod, error) {
+	var pm domain.PaymentMethod
+	if err := tx.Where("id = ? AND customer_id = ?", paymentMethodID, customerID).
+		First(&pm).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPaymentMethodNotFound
+		}
+		return nil, err
+	}
+	return &pm, nil
+}
+
+func (s *PaymentMethodServiceImpl) newAdapter(ctx context.Context, orgID snowflake.ID, provider string) (domain.PaymentAdapter, error) {
+	providerConfig, err := s.providerService.GetActiveProviderConfig(ctx, orgID, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var configMap map[string]any
+	if err := json.Unmarshal(providerConfig.Config, &configMap); err != nil {
+		return nil, err
+	}
+
+	return s.adapterRegistry.NewAdapter(provider, domain.AdapterConfig{
+		OrgID:    orgID,
+		Provider: provider,
+		Config:   configMap,
+	})
+}
+
+// refreshPaymentMethods updates the stored details of a provider's methods
+// with what the provider lists now, e.g. a card's new expiry after the
+// network updated it. Methods the provider does not list are left alone, as
+// not every provider lists every method type.
+func (s *PaymentMethodServiceImpl) refreshPaymentMethods(ctx context.Context, customer *paymentMethodCustomer, provider string, stored []*domain.PaymentMethod) {
+	if customer.ProviderCustomerID == "" || s.providerService == nil || s.adapterRegistry == nil {
+		return
+	}
+	adapter, err := s.newAdapter(ctx, customer.OrgID, provider)
+	if err != nil {
+		return
+	}
+	listed, err := adapter.ListPaymentMethods(ctx, customer.ProviderCustomerID)
+	if err != nil {
+		return
+	}
+
+	details := make(map[string]*domain.PaymentMethodDetails, len(listed))
+	for _, item := range listed {
+		if item != nil {
+			details[item.ID] = item
+		}
+	}
+	for _, pm := range stored {
+		item, ok := details[pm.ProviderPaymentMethodID]
+		if !ok {
+			continue
+		}
+		if item.Last4 == pm.Last4 && item.Brand == pm.Brand && item.ExpMonth == pm.ExpMonth && item.ExpYear == pm.ExpYear {
+			continue
+		}
+		pm.Last4 = item.Last4
+		pm.Brand = item.Brand
+		pm.ExpMonth = item.ExpMonth
+		pm.ExpYear = item.ExpYear
+		// updated_at tracks changes the customer made, which
+		// GetDefaultPaymentMethod orders by, so a refresh leaves it alone.
+		_ = s.db.WithContext(ctx).Model(&domain.PaymentMethod{}).
+			Where("id = ?", pm.ID).
+			UpdateColumns(map[string]any{
+				"last4":     pm.Last4,
+				"brand":     pm.Brand,
+				"exp_month": pm.ExpMonth,
+				"exp_year":  pm.ExpYear,
+			}).Error
+	}
+}
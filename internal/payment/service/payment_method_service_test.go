@@ -4,8 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/bwmarrin/snowflake"
 	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/payment/adapters"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	providerservice "github.com/railzwaylabs/railzway/internal/providers/payment/domain"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
@@ -20,3 +25,110 @@ func TestGetDefaultPaymentMethod_NotFound(t *testing.T) {
 	_, err = svc.GetDefaultPaymentMethod(context.Background(), 123)
 	require.ErrorIs(t, err, paymentdomain.ErrPaymentMethodNotFound)
 }
+
+type fakeMethodAdapter struct {
+	paymentdomain.PaymentAdapter
+	listed   []*paymentdomain.PaymentMethodDetails
+	detached []string
+}
+
+func (a *fakeMethodAdapter) AttachPaymentMethod(ctx context.Context, customerProviderID, token string) (*paymentdomain.PaymentMethodDetails, error) {
+	return &paymentdomain.PaymentMethodDetails{ID: token, Type: "card", Last4: "4242", Brand: "visa", ExpMonth: 1, ExpYear: 2030}, nil
+}
+
+func (a *fakeMethodAdapter) DetachPaymentMethod(ctx context.Context, paymentMethodID string) error {
+	a.detached = append(a.detached, paymentMethodID)
+	return nil
+}
+
+func (a *fakeMethodAdapter) ListPaymentMethods(ctx context.Context, customerProviderID string) ([]*paymentdomain.PaymentMethodDetails, error) {
+	return a.listed, nil
+}
+
+type fakeMethodAdapterFactory struct {
+	provider string
+	adapter  *fakeMethodAdapter
+}
+
+func (f *fakeMethodAdapterFactory) Provider() string { return f.provider }
+
+func (f *fakeMethodAdapterFactory) NewAdapter(cfg paymentdomain.AdapterConfig) (paymentdomain.PaymentAdapter, error) {
+	return f.adapter, nil
+}
+
+type fakeProviderConfigs struct {
+	providerservice.Service
+}
+
+func (f *fakeProviderConfigs) GetActiveProviderConfig(ctx context.Context, orgID snowflake.ID, provider string) (*providerservice.ProviderConfig, error) {
+	return &providerservice.ProviderConfig{OrgID: int64(orgID), Provider: provider, Config: []byte(`{}`), IsActive: true}, nil
+}
+
+func TestPaymentMethodDefaults_OnePerProvider(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&paymentdomain.PaymentMethod{}))
+	require.NoError(t, db.Exec(`CREATE TABLE customers (id INTEGER PRIMARY KEY, org_id INTEGER, provider_customer_id TEXT)`).Error)
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX idx_one_default_pm_per_customer_provider
+		ON customer_payment_methods(customer_id, provider) WHERE is_default = true`).Error)
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	customerID := node.Generate()
+	require.NoError(t, db.Exec(`INSERT INTO customers (id, org_id, provider_customer_id) VALUES (?, ?, ?)`, customerID, orgID, "cus_123").Error)
+
+	stripe := &fakeMethodAdapter{}
+	xendit := &fakeMethodAdapter{}
+	svc := NewPaymentMethodService(db, node, adapters.NewRegistry(
+		&fakeMethodAdapterFactory{provider: "stripe", adapter: stripe},
+		&fakeMethodAdapterFactory{provider: "xendit", adapter: xendit},
+	), &fakeProviderConfigs{})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	first, err := svc.AttachPaymentMethod(ctx, customerID, "stripe", "pm_1")
+	require.NoError(t, err)
+	second, err := svc.AttachPaymentMethod(ctx, customerID, "stripe", "pm_2")
+	require.NoError(t, err)
+	wallet, err := svc.AttachPaymentMethod(ctx, customerID, "xendit", "tok_1")
+	require.NoError(t, err)
+	assert.True(t, first.IsDefault)
+	assert.False(t, second.IsDefault)
+	assert.True(t, wallet.IsDefault, "the first method of each provider becomes its default")
+
+	require.NoError(t, svc.SetDefaultPaymentMethod(ctx, customerID, second.ID))
+	defaults := func() map[string]snowflake.ID {
+		var rows []paymentdomain.PaymentMethod
+		require.NoError(t, db.Where("customer_id = ? AND is_default = true", customerID).Find(&rows).Error)
+		out := make(map[string]snowflake.ID, len(rows))
+		for _, row := range rows {
+			out[row.Provider] = row.ID
+		}
+		return out
+	}
+	assert.Equal(t, map[string]snowflake.ID{"stripe": second.ID, "xendit": wallet.ID}, defaults())
+
+	chosen, err := svc.GetDefaultPaymentMethod(context.Background(), customerID)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, chosen.ID, "the most recently chosen default is charged")
+
+	// Listing refreshes card details the provider reports.
+	stripe.listed = []*paymentdomain.PaymentMethodDetails{{ID: "pm_2", Type: "card", Last4: "4242", Brand: "visa", ExpMonth: 12, ExpYear: 2032}}
+	listed, err := svc.ListPaymentMethods(ctx, customerID)
+	require.NoError(t, err)
+	require.Len(t, listed, 3)
+	var refreshed paymentdomain.PaymentMethod
+	require.NoError(t, db.First(&refreshed, "id = ?", second.ID).Error)
+	assert.Equal(t, 12, refreshed.ExpMonth)
+	assert.Equal(t, 2032, refreshed.ExpYear)
+
+	require.NoError(t, svc.DetachPaymentMethod(ctx, customerID, second.ID))
+	assert.Equal(t, []string{"pm_2"}, stripe.detached)
+	assert.Equal(t, map[string]snowflake.ID{"stripe": first.ID, "xendit": wallet.ID}, defaults())
+
+	err = svc.SetDefaultPaymentMethod(ctx, customerID, second.ID)
+	assert.ErrorIs(t, err, paymentdomain.ErrPaymentMethodNotFound)
+
+	otherOrg := orgcontext.WithOrgID(context.Background(), int64(node.Generate()))
+	_, err = svc.ListPaymentMethods(otherOrg, customerID)
+	assert.ErrorIs(t, err, paymentdomain.ErrInvalidCustomer)
+}
@@ -12,6 +12,7 @@ import (
 	auditdomain "github.com/railzwaylabs/railzway/internal/audit/domain"
 	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
 	obsmetrics "github.com/railzwaylabs/railzway/internal/observability/metrics"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -22,34 +23,37 @@ import (
 type Params struct {
 	fx.In
 
-	DB         *gorm.DB
-	Log        *zap.Logger
-	GenID      *snowflake.Node
-	LedgerSvc  ledgerdomain.Service
-	AuditSvc   auditdomain.Service
-	Repo       paymentdomain.Repository
-	ObsMetrics *obsmetrics.Metrics `optional:"true"`
+	DB            *gorm.DB
+	Log           *zap.Logger
+	GenID         *snowflake.Node
+	LedgerSvc     ledgerdomain.Service
+	AuditSvc      auditdomain.Service
+	Repo          paymentdomain.Repository
+	CreditNoteSvc creditnotedomain.Service `optional:"true"`
+	ObsMetrics    *obsmetrics.Metrics      `optional:"true"`
 }
 
 type Service struct {
-	db         *gorm.DB
-	log        *zap.Logger
-	genID      *snowflake.Node
-	ledgerSvc  ledgerdomain.Service
-	auditSvc   auditdomain.Service
-	repo       paymentdomain.Repository
-	obsMetrics *obsmetrics.Metrics
+	db            *gorm.DB
+	log           *zap.Logger
+	genID         *snowflake.Node
+	ledgerSvc     ledgerdomain.Service
+	auditSvc      auditdomain.Service
+	repo          paymentdomain.Repository
+	creditNoteSvc creditnotedomain.Service
+	obsMetrics    *obsmetrics.Metrics
 }
 
 func NewService(p Params) *Service {
 	return &Service{
-		db:         p.DB,
-		log:        p.Log.Named("payment.service"),
-		genID:      p.GenID,
-		ledgerSvc:  p.LedgerSvc,
-		auditSvc:   p.AuditSvc,
-		repo:       p.Repo,
-		obsMetrics: p.ObsMetrics,
+		db:            p.DB,
+		log:           p.Log.Named("payment.service"),
+		genID:         p.GenID,
+		ledgerSvc:     p.LedgerSvc,
+		auditSvc:      p.AuditSvc,
+		repo:          p.Repo,
+		creditNoteSvc: p.CreditNoteSvc,
+		obsMetrics:    p.ObsMetrics,
 	}
 }
 
@@ -258,17 +262,37 @@ func (s *Service) settleRefund(
 		return err
 	}
 
+	extra := map[string]any{}
+	if s.creditNoteSvc != nil && event.InvoiceID != nil && *event.InvoiceID != 0 {
+		note, err := s.creditNoteSvc.CreateFromRefund(ctx, creditnotedomain.CreateFromRefundRequest{
+			OrgID:          stored.OrgID,
+			InvoiceID:      *event.InvoiceID,
+			CustomerID:     event.CustomerID,
+			PaymentEventID: stored.ID,
+			Amount:         event.Amount,
+			Currency:       event.Currency,
+			OccurredAt:     event.OccurredAt,
+		})
+		if err != nil {
+			return err
+		}
+		if note != nil {
+			extra["credit_note_id"] = note.ID.String()
+		}
+	}
+
 	balance, err := s.customerBalance(ctx, stored.OrgID, event.CustomerID, event.Currency)
 	if err != nil {
 		return err
 	}
+	extra["balance"] = balance
 
 	return s.writeAuditLog(
 		ctx,
 		"payment.refunded",
 		stored,
 		event,
-		map[string]any{"balance": balance},
+		extra,
 	)
 }
 
@@ -383,17 +407,21 @@ func (s *Service) updateInvoiceSettlement(ctx context.Context, orgID snowflake.I
 
 	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var row struct {
-			ID             snowflake.ID      `gorm:"column:id"`
-			OrgID          snowflake.ID      `gorm:"column:org_id"`
-			SubtotalAmount int64             `gorm:"column:subtotal_amount"`
-			PaidAt         *time.Time        `gorm:"column:paid_at"`
-			Metadata       datatypes.JSONMap `gorm:"column:metadata"`
+			ID          snowflake.ID      `gorm:"column:id"`
+			OrgID       snowflake.ID      `gorm:"column:org_id"`
+			TotalAmount int64             `gorm:"column:total_amount"`
+			AmountPaid  int64             `gorm:"column:amount_paid"`
+			PaidAt      *time.Time        `gorm:"column:paid_at"`
+			Metadata    datatypes.JSONMap `gorm:"column:metadata"`
 		}
-		if err := tx.WithContext(ctx).Raw(
-			`SELECT id, org_id, subtotal_amount, paid_at, metadata
+		query := `SELECT id, org_id, total_amount, amount_paid, paid_at, metadata
 			 FROM invoices
-			 WHERE id = ? AND org_id = ?
-			 FOR UPDATE`,
+			 WHERE id = ? AND org_id = ?`
+		if tx.Dialector.Name() != "sqlite" {
+			query += " FOR UPDATE"
+		}
+		if err := tx.WithContext(ctx).Raw(
+			query,
 			*event.InvoiceID,
 			orgID,
 		).Scan(&row).Error; err != nil {
@@ -406,15 +434,7 @@ func (s *Service) updateInvoiceSettlement(ctx context.Context, orgID snowflake.I
 			return nil
 		}
 
-		paid := readMetadataAmount(row.Metadata, "amount_paid")
-		if isRefund {
-			paid -= event.Amount
-		} else {
-			paid += event.Amount
-		}
-		if paid < 0 {
-			paid = 0
-		}
+		paid := applySettledAmount(row.AmountPaid, event.Amount, isRefund)
 		if row.Metadata == nil {
 			row.Metadata = datatypes.JSONMap{}
 		}
@@ -426,7 +446,7 @@ func (s *Service) updateInvoiceSettlement(ctx context.Context, orgID snowflake.I
 
 		now := time.Now().UTC()
 		paidAt := row.PaidAt
-		if row.SubtotalAmount > 0 && paid >= row.SubtotalAmount {
+		if invoiceFullyPaid(row.TotalAmount, paid) {
 			if paidAt == nil {
 				paidAt = &now
 			}
@@ -434,8 +454,9 @@ func (s *Service) updateInvoiceSettlement(ctx context.Context, orgID snowflake.I
 
 		if err := tx.WithContext(ctx).Exec(
 			`UPDATE invoices
-			 SET metadata = ?, paid_at = ?, updated_at = ?
+			 SET amount_paid = ?, metadata = ?, paid_at = ?, updated_at = ?
 			 WHERE id = ? AND org_id = ?`,
+			paid,
 			row.Metadata,
 			paidAt,
 			now,
@@ -511,6 +532,26 @@ func (s *Service) invoiceAlreadyPaid(ctx context.Context, orgID snowflake.ID, in
 	return row.PaidAt != nil, nil
 }
 
+// applySettledAmount returns the invoice's cumulative amount paid after a
+// payment or refund of amount, never dropping below zero.
+func applySettledAmount(paid int64, amount int64, isRefund bool) int64 {
+	if isRefund {
+		paid -= amount
+	} else {
+		paid += amount
+	}
+	if paid < 0 {
+		return 0
+	}
+	return paid
+}
+
+// invoiceFullyPaid reports whether installments have covered the invoice
+// total. Zero-amount invoices are never settled by payments.
+func invoiceFullyPaid(total int64, paid int64) bool {
+	return total > 0 && paid >= total
+}
+
 func applyPaymentMetadata(metadata datatypes.JSONMap, event *paymentdomain.PaymentEvent) {
 	if metadata == nil || event == nil {
 		return
@@ -578,82 +619,7 @@ func (s *Service) customerBalance(
 		return 0, paymentdomain.ErrInvalidCurrency
 	}
 
-	var balance int64
-
-	err := s.db.WithContext(ctx).Raw(
-		`
-		SELECT COALESCE(
-			SUM(CASE l.direction WHEN 'debit' THEN l.amount ELSE -l.amount END),
-			0
-		) AS balance
-		FROM ledger_entries le
-		JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
-		JOIN ledger_accounts a ON a.id = l.account_id
-
-		-- billing & adjustment → subscription scoped
-		LEFT JOIN billing_cycles bc
-			ON bc.id = le.source_id
-		   AND le.source_type IN (?, ?)
-		LEFT JOIN subscriptions s
-			ON s.id = bc.subscription_id
-
-		-- payment / refund / fee → payment scoped
-		LEFT JOIN payment_events pe
-			ON pe.id = le.source_id
-		   AND le.source_type IN (?, ?, ?)
-
-		-- disputes → dispute scoped
-		LEFT JOIN payment_disputes pd
-			ON pd.id = le.source_id
-		   AND le.source_type IN (?, ?, ?)
-
-		WHERE le.org_id = ?
-		  AND a.code = ?
-		  AND le.currency = ?
-		  AND (
-			   (le.source_type IN (?, ?) AND s.customer_id = ?)
-			OR (le.source_type IN (?, ?, ?) AND pe.customer_id = ?)
-			OR (le.source_type IN (?, ?, ?) AND pd.customer_id = ?)
-		  )
-		`,
-		// joins
-		ledgerdomain.SourceTypeBillingCycle,
-		ledgerdomain.SourceTypeAdjustment,
-
-		ledgerdomain.SourceTypePayment,
-		ledgerdomain.SourceTypePaymentFee,
-		ledgerdomain.SourceTypeRefund,
-
-		ledgerdomain.SourceTypeDisputeHold,
-		ledgerdomain.SourceTypeDisputeWin,
-		ledgerdomain.SourceTypeDisputeLoss,
-
-		// filters
-		orgID,
-		ledgerdomain.AccountCodeAccountsReceivable,
-		currency,
-
-		// customer resolution
-		ledgerdomain.SourceTypeBillingCycle,
-		ledgerdomain.SourceTypeAdjustment,
-		customerID,
-
-		ledgerdomain.SourceTypePayment,
-		ledgerdomain.SourceTypePaymentFee,
-		ledgerdomain.SourceTypeRefund,
-		customerID,
-
-		ledgerdomain.SourceTypeDisputeHold,
-		ledgerdomain.SourceTypeDisputeWin,
-		ledgerdomain.SourceTypeDisputeLoss,
-		customerID,
-	).Scan(&balance).Error
-
-	if err != nil {
-		return 0, err
-	}
-
-	return balance, nil
+	return s.ledgerSvc.CustomerBalance(ctx, orgID, customerID, currency, time.Time{})
 }
 
 func (s *Service) writeAuditLog(ctx context.Context, action string, stored *paymentdomain.EventRecord, event *paymentdomain.PaymentEvent, extra map[string]any) error {
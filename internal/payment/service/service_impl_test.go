@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -90,6 +91,7 @@ func TestIngestWebhookCreatesLedgerEntry(t *testing.T) {
 		Log:        zap.NewNop(),
 		PaymentSvc: paymentSvc,
 		DisputeSvc: disputeSvc,
+		Repo:       paymentrepo.Provide(),
 		Adapters:   adapterRegistry,
 		Cfg:        config.Config{PaymentProviderConfigSecret: configSecret},
 	})
@@ -150,6 +152,15 @@ func TestIngestWebhookCreatesLedgerEntry(t *testing.T) {
 	if processedAt == "" {
 		t.Fatalf("expected processed_at to be set")
 	}
+	assertCount(t, db, "SELECT COUNT(1) FROM processed_webhook_events", 1)
+
+	// A redelivery is dropped before it reaches the ledger.
+	err = webhookSvc.IngestWebhook(ctx, "stripe", payload, reqHeader)
+	if !errors.Is(err, paymentdomain.ErrEventAlreadyProcessed) {
+		t.Fatalf("expected redelivery to be rejected as processed, got %v", err)
+	}
+	assertCount(t, db, "SELECT COUNT(1) FROM payment_events", 1)
+	assertCount(t, db, "SELECT COUNT(1) FROM ledger_entries", 1)
 }
 
 func TestIngestWebhookCreatesDisputeLedgerEntry(t *testing.T) {
@@ -247,6 +258,84 @@ func TestIngestWebhookCreatesDisputeLedgerEntry(t *testing.T) {
 	}
 }
 
+func TestProcessEventSettlesInvoiceInInstallments(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+
+	node, err := snowflake.NewNode(10)
+	if err != nil {
+		t.Fatalf("new node: %v", err)
+	}
+
+	auditSvc := noopAuditService{}
+	paymentSvc := paymentservice.NewService(paymentservice.Params{
+		DB:    db,
+		Log:   zap.NewNop(),
+		GenID: node,
+		LedgerSvc: ledgerservice.NewService(ledgerservice.Params{
+			DB:       db,
+			Log:      zap.NewNop(),
+			GenID:    node,
+			AuditSvc: auditSvc,
+		}),
+		AuditSvc: auditSvc,
+		Repo:     paymentrepo.Provide(),
+	})
+
+	orgID := node.Generate()
+	customerID := node.Generate()
+	invoiceID := node.Generate()
+	if err := seedCustomer(db, orgID, customerID); err != nil {
+		t.Fatalf("seed customer: %v", err)
+	}
+	if err := db.Exec(
+		"INSERT INTO invoices (id, org_id, customer_id, total_amount) VALUES (?, ?, ?, ?)",
+		invoiceID, orgID, customerID, 10000,
+	).Error; err != nil {
+		t.Fatalf("seed invoice: %v", err)
+	}
+
+	pay := func(providerEventID string, amount int64) {
+		t.Helper()
+		if err := paymentSvc.ProcessEvent(ctx, &paymentdomain.PaymentEvent{
+			Provider:        "stripe",
+			ProviderEventID: providerEventID,
+			Type:            paymentdomain.EventTypePaymentSucceeded,
+			OrgID:           orgID,
+			CustomerID:      customerID,
+			Amount:          amount,
+			Currency:        "USD",
+			OccurredAt:      time.Now().UTC(),
+			InvoiceID:       &invoiceID,
+		}, []byte(`{}`)); err != nil {
+			t.Fatalf("process %s: %v", providerEventID, err)
+		}
+	}
+	var invoice struct {
+		AmountPaid int64      `gorm:"column:amount_paid"`
+		PaidAt     *time.Time `gorm:"column:paid_at"`
+	}
+	load := func() {
+		t.Helper()
+		if err := db.Raw("SELECT amount_paid, paid_at FROM invoices WHERE id = ?", invoiceID).Scan(&invoice).Error; err != nil {
+			t.Fatalf("load invoice: %v", err)
+		}
+	}
+
+	pay("evt_installment_1", 4000)
+	load()
+	if invoice.AmountPaid != 4000 || invoice.PaidAt != nil {
+		t.Fatalf("expected a partially paid invoice, got amount_paid=%d paid_at=%v", invoice.AmountPaid, invoice.PaidAt)
+	}
+
+	pay("evt_installment_2", 6000)
+	load()
+	if invoice.AmountPaid != 10000 || invoice.PaidAt == nil {
+		t.Fatalf("expected the invoice to be paid, got amount_paid=%d paid_at=%v", invoice.AmountPaid, invoice.PaidAt)
+	}
+	assertCount(t, db, "SELECT COUNT(1) FROM ledger_entries", 2)
+}
+
 func setupTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 
@@ -317,6 +406,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 			ledger_entry_id BIGINT NOT NULL,
 			account_id BIGINT NOT NULL,
 			direction TEXT NOT NULL,
+			currency TEXT NOT NULL,
 			amount BIGINT NOT NULL,
 			created_at TIMESTAMPTZ NOT NULL
 		)`,
@@ -348,6 +438,30 @@ func setupTestDB(t *testing.T) *gorm.DB {
 			org_id BIGINT NOT NULL,
 			subscription_id BIGINT NOT NULL
 		)`,
+		`CREATE TABLE processed_webhook_events (
+			org_id BIGINT NOT NULL,
+			provider TEXT NOT NULL,
+			provider_event_id TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			processed_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (org_id, provider, provider_event_id)
+		)`,
+		`CREATE TABLE credit_notes (
+			id BIGINT PRIMARY KEY,
+			org_id BIGINT NOT NULL,
+			invoice_id BIGINT NOT NULL,
+			customer_id BIGINT NOT NULL
+		)`,
+		`CREATE TABLE invoices (
+			id BIGINT PRIMARY KEY,
+			org_id BIGINT NOT NULL,
+			customer_id BIGINT NOT NULL,
+			total_amount BIGINT NOT NULL,
+			amount_paid BIGINT NOT NULL DEFAULT 0,
+			paid_at TIMESTAMP,
+			metadata TEXT NOT NULL DEFAULT '{}',
+			updated_at TIMESTAMPTZ
+		)`,
 	}
 
 	for _, stmt := range schema {
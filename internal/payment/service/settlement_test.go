@@ -0,0 +1,30 @@
+package service
+
+import "testing"
+
+func TestInstallmentsSettleInvoiceAtTotal(t *testing.T) {
+	const total = int64(10000)
+
+	paid := applySettledAmount(0, 4000, false)
+	if paid != 4000 {
+		t.Fatalf("expected 4000 paid, got %d", paid)
+	}
+	if invoiceFullyPaid(total, paid) {
+		t.Fatalf("expected invoice to stay open after the first installment")
+	}
+
+	paid = applySettledAmount(paid, 6000, false)
+	if paid != total {
+		t.Fatalf("expected %d paid, got %d", total, paid)
+	}
+	if !invoiceFullyPaid(total, paid) {
+		t.Fatalf("expected invoice to be paid once installments reach the total")
+	}
+
+	if got := applySettledAmount(paid, 12000, true); got != 0 {
+		t.Fatalf("expected refunds to clamp at zero, got %d", got)
+	}
+	if invoiceFullyPaid(0, 0) {
+		t.Fatalf("expected zero-amount invoices not to be settled by payments")
+	}
+}
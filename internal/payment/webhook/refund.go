@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RefundPayment asks the provider to refund a settled payment, in full or in
+// part. The refund is recorded before the provider is called so concurrent
+// requests cannot refund more than was charged. Ledger entries and credit
+// notes are not written here: they follow from the provider's refund webhook
+// like refunds issued from the provider dashboard.
+func (s *Service) RefundPayment(ctx context.Context, req paymentdomain.RefundPaymentRequest) (*paymentdomain.Refund, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, paymentdomain.ErrInvalidOrganization
+	}
+	paymentID, err := snowflake.ParseString(strings.TrimSpace(req.PaymentID))
+	if err != nil || paymentID == 0 {
+		return nil, paymentdomain.ErrInvalidPayment
+	}
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, paymentdomain.ErrInvalidAmount
+	}
+	if s.repo == nil {
+		return nil, paymentdomain.ErrPaymentNotFound
+	}
+
+	stored, err := s.repo.FindEventByID(ctx, s.db, orgID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.EventType != paymentdomain.EventTypePaymentSucceeded {
+		return nil, paymentdomain.ErrPaymentNotFound
+	}
+
+	if s.adapters == nil || !s.adapters.ProviderExists(stored.Provider) {
+		return nil, paymentdomain.ErrProviderNotFound
+	}
+	cfg, err := s.orgConfig(ctx, stored.Provider, orgID)
+	if err != nil {
+		return nil, err
+	}
+	adapter, err := s.newAdapter(stored.Provider, *cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stored payload is the provider's own record of the charge, so it
+	// is the amount partial refunds are checked against.
+	payment, _, err := parseEvent(ctx, adapter, stored.Provider, orgID, stored.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil || payment.Type != paymentdomain.EventTypePaymentSucceeded {
+		return nil, paymentdomain.ErrPaymentNotFound
+	}
+
+	now := time.Now().UTC()
+	refund := &paymentdomain.Refund{
+		ID:                s.genID.Generate(),
+		OrgID:             orgID,
+		PaymentEventID:    stored.ID,
+		CustomerID:        payment.CustomerID,
+		InvoiceID:         payment.InvoiceID,
+		Provider:          stored.Provider,
+		ProviderPaymentID: payment.ProviderPaymentID,
+		Currency:          strings.ToUpper(strings.TrimSpace(payment.Currency)),
+		Status:            paymentdomain.RefundStatusRequested,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		refund.Reason = &reason
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPayment(ctx, tx, orgID, stored.ID); err != nil {
+			return err
+		}
+		refunded, err := s.repo.SumRefunds(ctx, tx, orgID, stored.ID)
+		if err != nil {
+			return err
+		}
+		remaining := payment.Amount - refunded
+		refund.Amount = remaining
+		if req.Amount != nil {
+			refund.Amount = *req.Amount
+		}
+		if remaining <= 0 || refund.Amount > remaining {
+			return paymentdomain.ErrRefundExceedsPayment
+		}
+		return s.repo.InsertRefund(ctx, tx, refund)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reason := ""
+	if refund.Reason != nil {
+		reason = *refund.Reason
+	}
+	result, err := adapter.Refund(ctx, paymentdomain.RefundRequest{
+		ProviderPaymentID:   payment.ProviderPaymentID,
+		ProviderPaymentType: payment.ProviderPaymentType,
+		ReferenceID:         refund.ID.String(),
+		CustomerID:          refund.CustomerID,
+		InvoiceID:           refund.InvoiceID,
+		Amount:              refund.Amount,
+		Currency:            refund.Currency,
+		Reason:              reason,
+	})
+
+	refund.UpdatedAt = time.Now().UTC()
+	if err != nil {
+		message := err.Error()
+		refund.Status = paymentdomain.RefundStatusFailed
+		refund.LastError = &message
+	} else if result != nil && strings.TrimSpace(result.ID) != "" {
+		providerRefundID := strings.TrimSpace(result.ID)
+		refund.ProviderRefundID = &providerRefundID
+	}
+	if updateErr := s.repo.UpdateRefundOutcome(ctx, s.db, refund); updateErr != nil {
+		s.log.Warn("failed to record refund outcome",
+			zap.String("provider", refund.Provider),
+			zap.String("refund_id", refund.ID.String()),
+			zap.Error(updateErr))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// lockPayment serializes refunds of one payment.
+func lockPayment(ctx context.Context, tx *gorm.DB, orgID, paymentID snowflake.ID) error {
+	if tx.Dialector.Name() == "sqlite" {
+		return nil
+	}
+	var id snowflake.ID
+	return tx.WithContext(ctx).Raw(
+		`SELECT id FROM payment_events WHERE org_id = ? AND id = ? FOR UPDATE`,
+		orgID,
+		paymentID,
+	).Scan(&id).Error
+}
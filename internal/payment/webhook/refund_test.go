@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/payment/adapters"
+	"github.com/railzwaylabs/railzway/internal/payment/domain"
+	"github.com/railzwaylabs/railzway/internal/payment/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type plainVault struct{}
+
+func (plainVault) Encrypt(plaintext []byte) ([]byte, error) { return plaintext, nil }
+func (plainVault) Decrypt(data []byte) ([]byte, error)      { return data, nil }
+
+type refundAdapter struct {
+	domain.PaymentAdapter
+	payment  domain.PaymentEvent
+	err      error
+	requests []domain.RefundRequest
+}
+
+func (a *refundAdapter) Provider() string { return "fake" }
+
+func (a *refundAdapter) NewAdapter(domain.AdapterConfig) (domain.PaymentAdapter, error) {
+	return a, nil
+}
+
+func (a *refundAdapter) Parse(ctx context.Context, payload []byte) (*domain.PaymentEvent, error) {
+	event := a.payment
+	return &event, nil
+}
+
+func (a *refundAdapter) Refund(ctx context.Context, req domain.RefundRequest) (*domain.ProviderRefund, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	a.requests = append(a.requests, req)
+	return &domain.ProviderRefund{ID: "re_" + req.ReferenceID}, nil
+}
+
+func TestRefundPayment_ValidatesAgainstCharge(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.EventRecord{}, &domain.Refund{}))
+	require.NoError(t, db.Exec(`CREATE TABLE payment_provider_configs (
+		org_id BIGINT NOT NULL,
+		provider TEXT NOT NULL,
+		config TEXT NOT NULL,
+		is_active BOOLEAN NOT NULL
+	)`).Error)
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	customerID := node.Generate()
+	invoiceID := node.Generate()
+	require.NoError(t, db.Exec(
+		`INSERT INTO payment_provider_configs (org_id, provider, config, is_active) VALUES (?, ?, ?, TRUE)`,
+		orgID, "fake", `{"secret_key":"sk_test"}`,
+	).Error)
+
+	adapter := &refundAdapter{payment: domain.PaymentEvent{
+		ProviderPaymentID:   "pi_123",
+		ProviderPaymentType: "payment_intent",
+		Type:                domain.EventTypePaymentSucceeded,
+		CustomerID:          customerID,
+		Amount:              5000,
+		Currency:            "usd",
+		InvoiceID:           &invoiceID,
+	}}
+	repo := repository.Provide()
+	svc := NewService(Params{
+		DB:       db,
+		Log:      zap.NewNop(),
+		GenID:    node,
+		Repo:     repo,
+		Adapters: adapters.NewRegistry(adapter),
+		Vault:    plainVault{},
+	})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	storeEvent := func(eventType string) snowflake.ID {
+		record := domain.EventRecord{
+			ID:              node.Generate(),
+			OrgID:           orgID,
+			Provider:        "fake",
+			ProviderEventID: node.Generate().String(),
+			EventType:       eventType,
+			CustomerID:      customerID,
+			Payload:         datatypes.JSON(`{}`),
+			ReceivedAt:      time.Now().UTC(),
+		}
+		require.NoError(t, db.Create(&record).Error)
+		return record.ID
+	}
+	paymentID := storeEvent(domain.EventTypePaymentSucceeded)
+	refund := func(amount *int64) (*domain.Refund, error) {
+		return svc.RefundPayment(ctx, domain.RefundPaymentRequest{PaymentID: paymentID.String(), Amount: amount, Reason: "duplicate"})
+	}
+	amount := func(v int64) *int64 { return &v }
+
+	partial, err := refund(amount(2000))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2000), partial.Amount)
+	assert.Equal(t, "USD", partial.Currency)
+	assert.Equal(t, domain.RefundStatusRequested, partial.Status)
+	require.NotNil(t, partial.ProviderRefundID)
+	assert.Equal(t, "re_"+partial.ID.String(), *partial.ProviderRefundID)
+	require.Len(t, adapter.requests, 1)
+	assert.Equal(t, "pi_123", adapter.requests[0].ProviderPaymentID)
+	assert.Equal(t, invoiceID, *adapter.requests[0].InvoiceID)
+
+	_, err = refund(amount(3001))
+	assert.ErrorIs(t, err, domain.ErrRefundExceedsPayment)
+
+	// Provider failures are recorded but do not hold back the balance.
+	adapter.err = errors.New("card_declined")
+	_, err = refund(amount(1000))
+	require.Error(t, err)
+	var failed domain.Refund
+	require.NoError(t, db.Where("status = ?", domain.RefundStatusFailed).First(&failed).Error)
+	require.NotNil(t, failed.LastError)
+	assert.Equal(t, "card_declined", *failed.LastError)
+
+	// Without an amount the remaining balance is refunded.
+	adapter.err = nil
+	rest, err := refund(nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3000), rest.Amount)
+
+	_, err = refund(nil)
+	assert.ErrorIs(t, err, domain.ErrRefundExceedsPayment)
+	_, err = refund(amount(0))
+	assert.ErrorIs(t, err, domain.ErrInvalidAmount)
+
+	_, err = svc.RefundPayment(ctx, domain.RefundPaymentRequest{PaymentID: storeEvent(domain.EventTypePaymentFailed).String()})
+	assert.ErrorIs(t, err, domain.ErrPaymentNotFound)
+	_, err = svc.RefundPayment(ctx, domain.RefundPaymentRequest{PaymentID: "not-an-id"})
+	assert.ErrorIs(t, err, domain.ErrInvalidPayment)
+}
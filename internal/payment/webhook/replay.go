@@ -0,0 +1,175 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	disputedomain "github.com/railzwaylabs/railzway/internal/payment/dispute/domain"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+// ReplayWebhook re-runs parsing and dispatch for a logged webhook. An event
+// the dedup store already records as processed is returned untouched.
+func (s *Service) ReplayWebhook(ctx context.Context, id string) (*paymentdomain.WebhookLog, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, paymentdomain.ErrInvalidOrganization
+	}
+	logID, err := snowflake.ParseString(strings.TrimSpace(id))
+	if err != nil {
+		return nil, paymentdomain.ErrInvalidWebhookLog
+	}
+	if s.repo == nil {
+		return nil, paymentdomain.ErrWebhookLogNotFound
+	}
+
+	entry, err := s.repo.FindWebhookLog(ctx, s.db, orgID, logID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, paymentdomain.ErrWebhookLogNotFound
+	}
+
+	if entry.ProviderEventID != "" {
+		done, err := s.isProcessed(ctx, &paymentdomain.ProcessedWebhookEvent{
+			OrgID:           entry.OrgID,
+			Provider:        entry.Provider,
+			ProviderEventID: entry.ProviderEventID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return entry, nil
+		}
+	}
+
+	if s.adapters == nil || !s.adapters.ProviderExists(entry.Provider) {
+		return nil, paymentdomain.ErrProviderNotFound
+	}
+	cfg, err := s.orgConfig(ctx, entry.Provider, entry.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	adapter, err := s.newAdapter(entry.Provider, *cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The payload was verified when it arrived. Signatures are not checked
+	// again because provider signing timestamps expire long before a replay.
+	paymentEvent, disputeEvent, err := parseEvent(ctx, adapter, entry.Provider, entry.OrgID, entry.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	processed := processedEventFor(paymentEvent, disputeEvent)
+	err = s.dispatchEvent(ctx, entry.Provider, entry.Payload, paymentEvent, disputeEvent)
+	if errors.Is(err, paymentdomain.ErrEventAlreadyProcessed) {
+		err = nil
+	}
+	if processed != nil && err == nil {
+		if markErr := s.markProcessed(ctx, processed); markErr != nil {
+			s.log.Warn("failed to record processed webhook event",
+				zap.String("provider", entry.Provider),
+				zap.String("provider_event_id", processed.ProviderEventID),
+				zap.Error(markErr))
+		}
+	}
+
+	entry.Attempts++
+	applyWebhookOutcome(entry, err, time.Now().UTC())
+	if updateErr := s.repo.UpdateWebhookLogOutcome(ctx, s.db, entry); updateErr != nil {
+		return nil, updateErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// logWebhook records a verified webhook and the outcome of dispatching it.
+// Logging failures are reported but never fail the webhook itself.
+func (s *Service) logWebhook(
+	ctx context.Context,
+	provider string,
+	payload []byte,
+	paymentEvent *paymentdomain.PaymentEvent,
+	disputeEvent *disputedomain.DisputeEvent,
+	dispatchErr error,
+) {
+	if s.repo == nil || s.genID == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	entry := &paymentdomain.WebhookLog{
+		Provider:   provider,
+		Payload:    datatypes.JSON(payload),
+		Attempts:   1,
+		ReceivedAt: now,
+	}
+	switch {
+	case disputeEvent != nil:
+		entry.OrgID = disputeEvent.OrgID
+		entry.ProviderEventID = strings.TrimSpace(disputeEvent.ProviderEventID)
+		entry.EventType = disputeEvent.Type
+	case paymentEvent != nil:
+		entry.OrgID = paymentEvent.OrgID
+		entry.ProviderEventID = strings.TrimSpace(paymentEvent.ProviderEventID)
+		entry.EventType = paymentEvent.Type
+	}
+	if entry.OrgID == 0 {
+		return
+	}
+	entry.ID = s.genID.Generate()
+	if errors.Is(dispatchErr, paymentdomain.ErrEventAlreadyProcessed) {
+		dispatchErr = nil
+	}
+	applyWebhookOutcome(entry, dispatchErr, now)
+
+	if err := s.repo.InsertWebhookLog(ctx, s.db, entry); err != nil {
+		s.log.Warn("failed to log payment webhook",
+			zap.String("provider", provider),
+			zap.String("provider_event_id", entry.ProviderEventID),
+			zap.Error(err))
+	}
+}
+
+func applyWebhookOutcome(entry *paymentdomain.WebhookLog, err error, now time.Time) {
+	if err != nil {
+		message := err.Error()
+		entry.Status = paymentdomain.WebhookLogStatusFailed
+		entry.LastError = &message
+		return
+	}
+	entry.Status = paymentdomain.WebhookLogStatusProcessed
+	entry.LastError = nil
+	entry.ProcessedAt = &now
+}
+
+func (s *Service) orgConfig(ctx context.Context, provider string, orgID snowflake.ID) (*providerConfigRow, error) {
+	var rows []providerConfigRow
+	err := s.db.WithContext(ctx).Raw(
+		`SELECT org_id, config
+		 FROM payment_provider_configs
+		 WHERE provider = ? AND org_id = ? AND is_active = TRUE
+		 LIMIT 1`,
+		provider,
+		orgID,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, paymentdomain.ErrProviderNotFound
+	}
+	return &rows[0], nil
+}
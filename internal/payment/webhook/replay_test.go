@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/payment/domain"
+	"github.com/railzwaylabs/railzway/internal/payment/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestReplayWebhook_SkipsEventsAlreadyProcessed(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.WebhookLog{}, &domain.ProcessedWebhookEvent{}))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	repo := repository.Provide()
+	svc := NewService(Params{DB: db, Log: zap.NewNop(), GenID: node, Repo: repo})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	logEntry := func(eventID string) *domain.WebhookLog {
+		message := "subscription_create_failed"
+		entry := &domain.WebhookLog{
+			ID:              node.Generate(),
+			OrgID:           orgID,
+			Provider:        "stripe",
+			ProviderEventID: eventID,
+			EventType:       domain.EventTypeCheckoutSessionCompleted,
+			Payload:         datatypes.JSON(`{"id":"` + eventID + `"}`),
+			Status:          domain.WebhookLogStatusFailed,
+			LastError:       &message,
+			Attempts:        1,
+			ReceivedAt:      time.Now().UTC(),
+		}
+		require.NoError(t, repo.InsertWebhookLog(context.Background(), db, entry))
+		return entry
+	}
+
+	done := logEntry("evt_done")
+	require.NoError(t, repo.MarkWebhookEventProcessed(context.Background(), db, &domain.ProcessedWebhookEvent{
+		OrgID:           orgID,
+		Provider:        "stripe",
+		ProviderEventID: "evt_done",
+		EventType:       domain.EventTypeCheckoutSessionCompleted,
+		ProcessedAt:     time.Now().UTC(),
+	}))
+
+	got, err := svc.ReplayWebhook(ctx, done.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, done.ID, got.ID)
+	assert.Equal(t, 1, got.Attempts)
+
+	// Unprocessed events go back through the pipeline, which needs the
+	// provider adapter.
+	pending := logEntry("evt_pending")
+	_, err = svc.ReplayWebhook(ctx, pending.ID.String())
+	assert.ErrorIs(t, err, domain.ErrProviderNotFound)
+
+	otherOrg := orgcontext.WithOrgID(context.Background(), int64(node.Generate()))
+	_, err = svc.ReplayWebhook(otherOrg, done.ID.String())
+	assert.ErrorIs(t, err, domain.ErrWebhookLogNotFound)
+
+	_, err = svc.ReplayWebhook(ctx, "not-an-id")
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhookLog)
+}
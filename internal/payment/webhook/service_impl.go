@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/snowflake"
 	"github.com/railzwaylabs/railzway/internal/config"
@@ -28,9 +29,11 @@ type Params struct {
 
 	DB          *gorm.DB
 	Log         *zap.Logger
+	GenID       *snowflake.Node
 	PaymentSvc  *paymentservice.Service
 	CheckoutSvc domain.CheckoutService
 	DisputeSvc  *disputeservice.Service
+	Repo        paymentdomain.Repository `optional:"true"`
 	Adapters    *adapters.Registry
 	Cfg         config.Config
 	Vault       vault.Provider
@@ -39,9 +42,11 @@ type Params struct {
 type Service struct {
 	db          *gorm.DB
 	log         *zap.Logger
+	genID       *snowflake.Node
 	paymentSvc  *paymentservice.Service
 	checkoutSvc domain.CheckoutService
 	disputeSvc  *disputeservice.Service
+	repo        paymentdomain.Repository
 	adapters    *adapters.Registry
 	vault       vault.Provider
 }
@@ -55,9 +60,11 @@ func NewService(p Params) paymentdomain.Service {
 	return &Service{
 		db:          p.DB,
 		log:         p.Log.Named("payment.webhook"),
+		genID:       p.GenID,
 		paymentSvc:  p.PaymentSvc,
 		checkoutSvc: p.CheckoutSvc,
 		disputeSvc:  p.DisputeSvc,
+		repo:        p.Repo,
 		adapters:    p.Adapters,
 		vault:       p.Vault,
 	}
@@ -107,6 +114,41 @@ func (s *Service) IngestWebhook(ctx context.Context, provider string, payload []
 		return err
 	}
 
+	processed := processedEventFor(paymentEvent, disputeEvent)
+	if processed != nil {
+		done, err := s.isProcessed(ctx, processed)
+		if err != nil {
+			return err
+		}
+		if done {
+			s.log.Info("webhook event already processed",
+				zap.String("provider", provider),
+				zap.String("provider_event_id", processed.ProviderEventID))
+			return paymentdomain.ErrEventAlreadyProcessed
+		}
+	}
+
+	err = s.dispatchEvent(ctx, provider, payload, paymentEvent, disputeEvent)
+	if processed != nil && (err == nil || errors.Is(err, paymentdomain.ErrEventAlreadyProcessed)) {
+		if markErr := s.markProcessed(ctx, processed); markErr != nil {
+			// The event was applied; payment_events still rejects a replay.
+			s.log.Warn("failed to record processed webhook event",
+				zap.String("provider", provider),
+				zap.String("provider_event_id", processed.ProviderEventID),
+				zap.Error(markErr))
+		}
+	}
+	s.logWebhook(ctx, provider, payload, paymentEvent, disputeEvent, err)
+	return err
+}
+
+func (s *Service) dispatchEvent(
+	ctx context.Context,
+	provider string,
+	payload []byte,
+	paymentEvent *paymentdomain.PaymentEvent,
+	disputeEvent *disputedomain.DisputeEvent,
+) error {
 	if disputeEvent != nil {
 		if s.disputeSvc == nil {
 			return errors.New("dispute_service_unavailable")
@@ -138,15 +180,13 @@ func (s *Service) IngestWebhook(ctx context.Context, provider string, payload []
 			// Use ProviderPaymentID as session ID (mapped in adapters)
 			_, err := s.checkoutSvc.CompleteSession(ctx, provider, paymentEvent.ProviderPaymentID)
 			if err != nil && !errors.Is(err, paymentdomain.ErrCheckoutSessionNotFound) {
-				// Log error but don't fail the webhook processing itself,
-				// as the main payment processing might still need to succeed?
-				// Actually if session completion fails (e.g. DB error), we might accept it to be safe
-				// or retry.
-				// For now log error.
+				// Fail the webhook so the provider redelivers it; the event is
+				// only marked processed once the session is complete.
 				s.log.Error("failed to complete checkout session",
 					zap.String("provider", provider),
 					zap.String("provider_session_id", paymentEvent.ProviderPaymentID),
 					zap.Error(err))
+				return err
 			}
 		}
 	}
@@ -154,6 +194,49 @@ func (s *Service) IngestWebhook(ctx context.Context, provider string, payload []
 	return s.paymentSvc.ProcessEvent(ctx, paymentEvent, masked)
 }
 
+// processedEventFor returns the deduplication key of a parsed event, or nil
+// when the event carries no provider event ID.
+func processedEventFor(paymentEvent *paymentdomain.PaymentEvent, disputeEvent *disputedomain.DisputeEvent) *paymentdomain.ProcessedWebhookEvent {
+	var record paymentdomain.ProcessedWebhookEvent
+	switch {
+	case disputeEvent != nil:
+		record = paymentdomain.ProcessedWebhookEvent{
+			OrgID:           disputeEvent.OrgID,
+			Provider:        disputeEvent.Provider,
+			ProviderEventID: strings.TrimSpace(disputeEvent.ProviderEventID),
+			EventType:       disputeEvent.Type,
+		}
+	case paymentEvent != nil:
+		record = paymentdomain.ProcessedWebhookEvent{
+			OrgID:           paymentEvent.OrgID,
+			Provider:        paymentEvent.Provider,
+			ProviderEventID: strings.TrimSpace(paymentEvent.ProviderEventID),
+			EventType:       paymentEvent.Type,
+		}
+	default:
+		return nil
+	}
+	if record.OrgID == 0 || record.ProviderEventID == "" {
+		return nil
+	}
+	return &record
+}
+
+func (s *Service) isProcessed(ctx context.Context, event *paymentdomain.ProcessedWebhookEvent) (bool, error) {
+	if s.repo == nil {
+		return false, nil
+	}
+	return s.repo.IsWebhookEventProcessed(ctx, s.db, event.OrgID, event.Provider, event.ProviderEventID)
+}
+
+func (s *Service) markProcessed(ctx context.Context, event *paymentdomain.ProcessedWebhookEvent) error {
+	if s.repo == nil {
+		return nil
+	}
+	event.ProcessedAt = time.Now().UTC()
+	return s.repo.MarkWebhookEventProcessed(ctx, s.db, event)
+}
+
 func (s *Service) listActiveConfigs(ctx context.Context, provider string) ([]providerConfigRow, error) {
 	var rows []providerConfigRow
 	err := s.db.WithContext(ctx).Raw(
@@ -177,7 +260,7 @@ func (s *Service) matchAdapter(
 ) (paymentdomain.PaymentAdapter, *paymentdomain.PaymentEvent, *disputedomain.DisputeEvent, error) {
 	var configErr error
 	for _, cfg := range configs {
-		decrypted, err := s.decryptConfig(cfg.Config)
+		adapter, err := s.newAdapter(provider, cfg)
 		if err != nil {
 			if errors.Is(err, paymentproviderdomain.ErrEncryptionKeyMissing) {
 				return nil, nil, nil, err
@@ -186,16 +269,6 @@ func (s *Service) matchAdapter(
 			continue
 		}
 
-		adapter, err := s.adapters.NewAdapter(provider, paymentdomain.AdapterConfig{
-			OrgID:    cfg.OrgID,
-			Provider: provider,
-			Config:   decrypted,
-		})
-		if err != nil {
-			configErr = err
-			continue
-		}
-
 		if err := adapter.Verify(ctx, payload, headers); err != nil {
 			if errors.Is(err, paymentdomain.ErrInvalidSignature) {
 				continue
@@ -203,28 +276,14 @@ func (s *Service) matchAdapter(
 			return nil, nil, nil, err
 		}
 
-		if disputeAdapter, ok := adapter.(disputedomain.DisputeAdapter); ok {
-			disputeEvent, err := disputeAdapter.ParseDispute(ctx, payload)
-			if err == nil {
-				disputeEvent.Provider = provider
-				disputeEvent.OrgID = cfg.OrgID
-				return adapter, nil, disputeEvent, nil
-			}
-			if !errors.Is(err, paymentdomain.ErrEventIgnored) {
-				return nil, nil, nil, err
-			}
-		}
-
-		paymentEvent, err := adapter.Parse(ctx, payload)
+		paymentEvent, disputeEvent, err := parseEvent(ctx, adapter, provider, cfg.OrgID, payload)
 		if err != nil {
 			if errors.Is(err, paymentdomain.ErrEventIgnored) {
 				return adapter, nil, nil, err
 			}
 			return nil, nil, nil, err
 		}
-		paymentEvent.Provider = provider
-		paymentEvent.OrgID = cfg.OrgID
-		return adapter, paymentEvent, nil, nil
+		return adapter, paymentEvent, disputeEvent, nil
 	}
 
 	if configErr != nil {
@@ -233,6 +292,49 @@ func (s *Service) matchAdapter(
 	return nil, nil, nil, paymentdomain.ErrInvalidSignature
 }
 
+func (s *Service) newAdapter(provider string, cfg providerConfigRow) (paymentdomain.PaymentAdapter, error) {
+	decrypted, err := s.decryptConfig(cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+	return s.adapters.NewAdapter(provider, paymentdomain.AdapterConfig{
+		OrgID:    cfg.OrgID,
+		Provider: provider,
+		Config:   decrypted,
+		Log:      s.log,
+	})
+}
+
+// parseEvent parses a verified payload, preferring the dispute form when the
+// adapter supports disputes.
+func parseEvent(
+	ctx context.Context,
+	adapter paymentdomain.PaymentAdapter,
+	provider string,
+	orgID snowflake.ID,
+	payload []byte,
+) (*paymentdomain.PaymentEvent, *disputedomain.DisputeEvent, error) {
+	if disputeAdapter, ok := adapter.(disputedomain.DisputeAdapter); ok {
+		disputeEvent, err := disputeAdapter.ParseDispute(ctx, payload)
+		if err == nil {
+			disputeEvent.Provider = provider
+			disputeEvent.OrgID = orgID
+			return nil, disputeEvent, nil
+		}
+		if !errors.Is(err, paymentdomain.ErrEventIgnored) {
+			return nil, nil, err
+		}
+	}
+
+	paymentEvent, err := adapter.Parse(ctx, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	paymentEvent.Provider = provider
+	paymentEvent.OrgID = orgID
+	return paymentEvent, nil, nil
+}
+
 func (s *Service) decryptConfig(encrypted datatypes.JSON) (map[string]any, error) {
 	if s.vault == nil {
 		return nil, paymentproviderdomain.ErrEncryptionKeyMissing
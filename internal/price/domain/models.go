@@ -73,9 +73,9 @@ var (
 
 type Price struct {
 	ID                   snowflake.ID      `json:"id" gorm:"primaryKey"`
-	OrgID                snowflake.ID      `json:"organization_id" gorm:"column:org_id;not null;index"`
+	OrgID                snowflake.ID      `json:"organization_id" gorm:"column:org_id;not null;index;uniqueIndex:ux_prices_org_code,priority:1"`
 	ProductID            snowflake.ID      `json:"product_id" gorm:"column:product_id;not null;index"`
-	Code                 string            `json:"code" gorm:"type:text;not null"`
+	Code                 string            `json:"code" gorm:"type:text;not null;uniqueIndex:ux_prices_org_code,priority:2"`
 	Name                 string            `json:"name,omitempty" gorm:"type:text"`
 	Description          string            `json:"description,omitempty" gorm:"type:text"`
 	IdempotencyKey       *string           `json:"-" gorm:"column:idempotency_key"`
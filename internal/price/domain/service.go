@@ -85,4 +85,5 @@ var (
 	ErrInvalidVersion              = errors.New("invalid_version")
 	ErrInvalidID                   = errors.New("invalid_id")
 	ErrNotFound                    = errors.New("not_found")
+	ErrDuplicateCode               = errors.New("duplicate_code")
 )
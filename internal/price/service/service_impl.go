@@ -10,6 +10,7 @@ import (
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
 	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
 	productdomain "github.com/railzwaylabs/railzway/internal/product/domain"
+	"github.com/railzwaylabs/railzway/pkg/db"
 	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -114,6 +115,9 @@ func (s *Service) Create(ctx context.Context, req pricedomain.CreateRequest) (*p
 				return s.toResponse(existing), nil
 			}
 		}
+		if db.IsDuplicateKeyErr(err) {
+			return nil, pricedomain.ErrDuplicateCode
+		}
 		return nil, err
 	}
 
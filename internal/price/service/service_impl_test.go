@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	"github.com/railzwaylabs/railzway/internal/price/repository"
+	productdomain "github.com/railzwaylabs/railzway/internal/product/domain"
+	productrepository "github.com/railzwaylabs/railzway/internal/product/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestCreate_RejectsDuplicateCodeWithinOrg(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&productdomain.Product{}, &pricedomain.Price{}))
+
+	node, _ := snowflake.NewNode(1)
+	svc := New(Params{
+		DB:          db,
+		Log:         zap.NewNop(),
+		GenID:       node,
+		Repo:        repository.Provide(),
+		ProductRepo: productrepository.Provide(),
+	})
+
+	create := func(orgID snowflake.ID) error {
+		productID := node.Generate()
+		require.NoError(t, db.Create(&productdomain.Product{
+			ID:     productID.Int64(),
+			OrgID:  orgID.Int64(),
+			Code:   "hobby_" + productID.String(),
+			Name:   "Hobby",
+			Active: true,
+		}).Error)
+
+		ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+		_, err := svc.Create(ctx, pricedomain.CreateRequest{
+			ProductID:            productID.String(),
+			Code:                 "hobby",
+			PricingModel:         pricedomain.Flat,
+			BillingMode:          pricedomain.Licensed,
+			BillingInterval:      pricedomain.Month,
+			BillingIntervalCount: 1,
+			TaxBehavior:          pricedomain.Exclusive,
+		})
+		return err
+	}
+
+	orgA := node.Generate()
+	require.NoError(t, create(orgA))
+	assert.ErrorIs(t, create(orgA), pricedomain.ErrDuplicateCode)
+
+	require.NoError(t, create(node.Generate()))
+}
@@ -31,6 +31,7 @@ type Service interface {
 	Create(ctx context.Context, req CreateRequest) (*Response, error)
 	List(ctx context.Context, req ListPriceAmountRequest) (ListPriceAmountResponse, error)
 	Get(ctx context.Context, req GetPriceAmountByID) (*Response, error)
+	Schedule(ctx context.Context, req ScheduleRequest) (*Response, error)
 }
 
 type CreateRequest struct {
@@ -46,6 +47,20 @@ type CreateRequest struct {
 	IdempotencyKey     string         `json:"-"`
 }
 
+// ScheduleRequest schedules a future amount for a price. The amount active
+// today is closed at EffectiveFrom.
+type ScheduleRequest struct {
+	PriceID            string         `json:"-"`
+	MeterID            *string        `json:"meter_id,omitempty"`
+	Currency           string         `json:"currency"`
+	UnitAmountCents    int64          `json:"unit_amount_cents"`
+	MinimumAmountCents *int64         `json:"minimum_amount_cents,omitempty"`
+	MaximumAmountCents *int64         `json:"maximum_amount_cents,omitempty"`
+	EffectiveFrom      time.Time      `json:"effective_from"`
+	Metadata           map[string]any `json:"metadata,omitempty"`
+	IdempotencyKey     string         `json:"-"`
+}
+
 type Response struct {
 	ID                 snowflake.ID  `json:"id"`
 	OrganizationID     snowflake.ID  `json:"organization_id"`
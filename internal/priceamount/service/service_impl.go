@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/cache"
 	"github.com/railzwaylabs/railzway/internal/clock"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
 	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
@@ -28,6 +29,7 @@ type Params struct {
 	Clock     clock.Clock
 	Repo      priceamountdomain.Repository
 	PriceRepo pricedomain.Repository
+	Cache     cache.PriceCache `optional:"true"`
 }
 
 type Service struct {
@@ -37,6 +39,7 @@ type Service struct {
 	clock     clock.Clock
 	repo      priceamountdomain.Repository
 	priceRepo pricedomain.Repository
+	cache     cache.PriceCache
 }
 
 func New(p Params) priceamountdomain.Service {
@@ -47,6 +50,7 @@ func New(p Params) priceamountdomain.Service {
 		clock:     p.Clock,
 		repo:      p.Repo,
 		priceRepo: p.PriceRepo,
+		cache:     p.Cache,
 	}
 }
 
@@ -246,9 +250,102 @@ func (s *Service) Create(ctx context.Context, req priceamountdomain.CreateReques
 		return nil, err
 	}
 
+	// Versioning may have closed the active amount, so every cached amount
+	// of the price is dropped.
+	if s.cache != nil {
+		s.cache.InvalidatePrice(ctx, orgID, priceID)
+	}
+
 	return s.toResponse(ctx, entity), nil
 }
 
+// Schedule inserts an amount that takes over from the currently active one
+// at a future EffectiveFrom. The active amount is closed at that instant, so
+// the price stays covered without overlapping windows. Schedules that would
+// leave time uncovered, or that compete with an amount already scheduled,
+// are rejected.
+func (s *Service) Schedule(ctx context.Context, req priceamountdomain.ScheduleRequest) (*priceamountdomain.Response, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, priceamountdomain.ErrInvalidOrganization
+	}
+
+	createReq := priceamountdomain.CreateRequest{
+		PriceID:            req.PriceID,
+		MeterID:            req.MeterID,
+		Currency:           req.Currency,
+		UnitAmountCents:    req.UnitAmountCents,
+		MinimumAmountCents: req.MinimumAmountCents,
+		MaximumAmountCents: req.MaximumAmountCents,
+		Metadata:           req.Metadata,
+		IdempotencyKey:     req.IdempotencyKey,
+	}
+	priceID, _, currency, err := s.parseAmountIdentifiers(createReq)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAmountValues(createReq); err != nil {
+		return nil, err
+	}
+
+	now := normalizeToMinutePrecision(s.clock.Now(ctx))
+	effectiveFrom := normalizeToMinutePrecision(req.EffectiveFrom)
+	if !effectiveFrom.After(now) {
+		return nil, priceamountdomain.ErrInvalidEffectiveFrom
+	}
+
+	// A replayed request is answered by Create before the active amount,
+	// which the first request already closed, is looked at.
+	if key := strings.TrimSpace(req.IdempotencyKey); key != "" {
+		existing, err := s.repo.FindByIdempotencyKey(ctx, s.db, orgID, key)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return s.toResponse(ctx, existing), nil
+		}
+	}
+
+	price, err := s.ensurePriceExists(ctx, s.db, orgID, priceID)
+	if err != nil {
+		return nil, err
+	}
+	if price == nil {
+		return nil, priceamountdomain.ErrInvalidPrice
+	}
+
+	latest, err := s.repo.FindLatestByPriceAndCurrency(ctx, s.db, orgID, priceID, currency)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, priceamountdomain.ErrEffectiveGap
+	}
+	current, err := s.repo.FindEffectiveAt(ctx, s.db, orgID, priceID, latest.MeterID, currency, now)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, priceamountdomain.ErrEffectiveGap
+	}
+	upcoming, err := s.repo.FindUpcoming(ctx, s.db, orgID, priceID, latest.MeterID, currency)
+	if err != nil {
+		return nil, err
+	}
+	if upcoming != nil {
+		return nil, priceamountdomain.ErrUpcomingAlreadyExists
+	}
+	if current.EffectiveTo != nil {
+		if current.EffectiveTo.Before(effectiveFrom) {
+			return nil, priceamountdomain.ErrEffectiveGap
+		}
+		return nil, priceamountdomain.ErrUpcomingAlreadyExists
+	}
+
+	createReq.EffectiveFrom = &effectiveFrom
+	return s.Create(ctx, createReq)
+}
+
 // resolvePricingDimension determines the immutable pricing dimension for this request.
 // Returns: (meterID, isVersioning, error)
 //
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	pricerepository "github.com/railzwaylabs/railzway/internal/price/repository"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	"github.com/railzwaylabs/railzway/internal/priceamount/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestSchedule_ClosesCurrentAmount(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&pricedomain.Price{}, &priceamountdomain.PriceAmount{}))
+
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	orgID := node.Generate()
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	svc := New(Params{
+		DB:        db,
+		Log:       zap.NewNop(),
+		GenID:     node,
+		Clock:     clock.NewFakeClock(now),
+		Repo:      repository.Provide(),
+		PriceRepo: pricerepository.Provide(),
+	})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	seed := func(effectiveTo *time.Time) (snowflake.ID, snowflake.ID) {
+		price := pricedomain.Price{
+			ID:              node.Generate(),
+			OrgID:           orgID,
+			ProductID:       node.Generate(),
+			Code:            "price_" + node.Generate().String(),
+			PricingModel:    pricedomain.Flat,
+			BillingMode:     pricedomain.Licensed,
+			BillingInterval: pricedomain.Month,
+			TaxBehavior:     pricedomain.Exclusive,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		require.NoError(t, db.Create(&price).Error)
+		amount := priceamountdomain.PriceAmount{
+			ID:              node.Generate(),
+			OrgID:           orgID,
+			PriceID:         price.ID,
+			Currency:        "USD",
+			UnitAmountCents: 1000,
+			EffectiveFrom:   now.AddDate(0, -1, 0),
+			EffectiveTo:     effectiveTo,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		require.NoError(t, db.Create(&amount).Error)
+		return price.ID, amount.ID
+	}
+	schedule := func(priceID snowflake.ID, effectiveFrom time.Time) (*priceamountdomain.Response, error) {
+		return svc.Schedule(ctx, priceamountdomain.ScheduleRequest{
+			PriceID:         priceID.String(),
+			Currency:        "usd",
+			UnitAmountCents: 1500,
+			EffectiveFrom:   effectiveFrom,
+		})
+	}
+
+	priceID, currentID := seed(nil)
+	startsAt := now.AddDate(0, 0, 7)
+	resp, err := schedule(priceID, startsAt.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), resp.UnitAmountCents)
+	assert.True(t, resp.EffectiveFrom.Equal(startsAt))
+	assert.Nil(t, resp.EffectiveTo)
+
+	var current priceamountdomain.PriceAmount
+	require.NoError(t, db.First(&current, "id = ?", currentID).Error)
+	require.NotNil(t, current.EffectiveTo)
+	assert.True(t, current.EffectiveTo.Equal(startsAt))
+
+	// The active amount now ends at the scheduled one.
+	_, err = schedule(priceID, startsAt.AddDate(0, 0, 7))
+	assert.ErrorIs(t, err, priceamountdomain.ErrUpcomingAlreadyExists)
+
+	_, err = schedule(priceID, now.Add(-time.Hour))
+	assert.ErrorIs(t, err, priceamountdomain.ErrInvalidEffectiveFrom)
+
+	// Coverage would lapse between the end of the active amount and the
+	// scheduled start.
+	endsAt := now.AddDate(0, 0, 3)
+	gapPriceID, _ := seed(&endsAt)
+	_, err = schedule(gapPriceID, startsAt)
+	assert.ErrorIs(t, err, priceamountdomain.ErrEffectiveGap)
+
+	_, err = schedule(node.Generate(), startsAt)
+	assert.ErrorIs(t, err, priceamountdomain.ErrInvalidPrice)
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "fmt"
+
+// Tier CSV columns, shared by import and export. The header row is required;
+// column order is free.
+const (
+	CSVColumnStartQuantity   = "start_quantity"
+	CSVColumnEndQuantity     = "end_quantity"
+	CSVColumnUnitAmountCents = "unit_amount_cents"
+	CSVColumnFlatAmountCents = "flat_amount_cents"
+)
+
+// CSVColumns lists the tier columns in export order.
+var CSVColumns = []string{
+	CSVColumnStartQuantity,
+	CSVColumnEndQuantity,
+	CSVColumnUnitAmountCents,
+	CSVColumnFlatAmountCents,
+}
+
+// ImportRequest replaces the tiers of a price with those in a CSV file.
+type ImportRequest struct {
+	PriceID string
+	// Unit of the imported tiers. Empty keeps the unit of the current tiers.
+	Unit string
+}
+
+// ImportRowError rejects an import because of a malformed row.
+type ImportRowError struct {
+	Line int
+	Err  error
+}
+
+func (e *ImportRowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *ImportRowError) Unwrap() error { return e.Err }
@@ -24,3 +24,27 @@ type PriceTier struct {
 }
 
 func (PriceTier) TableName() string { return "price_tiers" }
+
+// TiersContiguous reports whether sorted tiers cover the range from 0 without
+// gaps or overlaps: each tier starts where the previous one ends and only the
+// last may be open-ended.
+func TiersContiguous(sorted []PriceTier) bool {
+	if len(sorted) == 0 || sorted[0].StartQuantity != 0 {
+		return false
+	}
+	for i, tier := range sorted {
+		if tier.EndQuantity == nil {
+			if i != len(sorted)-1 {
+				return false
+			}
+			continue
+		}
+		if *tier.EndQuantity <= tier.StartQuantity {
+			return false
+		}
+		if i+1 < len(sorted) && sorted[i+1].StartQuantity != *tier.EndQuantity {
+			return false
+		}
+	}
+	return true
+}
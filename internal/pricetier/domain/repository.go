@@ -13,4 +13,6 @@ type Repository interface {
 	FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*PriceTier, error)
 	FindByIdempotencyKey(ctx context.Context, db *gorm.DB, orgID snowflake.ID, key string) (*PriceTier, error)
 	List(ctx context.Context, db *gorm.DB, orgID snowflake.ID, page pagination.Pagination) ([]*PriceTier, error)
+	ListByPrice(ctx context.Context, db *gorm.DB, orgID, priceID snowflake.ID) ([]*PriceTier, error)
+	DeleteByPrice(ctx context.Context, db *gorm.DB, orgID, priceID snowflake.ID) error
 }
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"io"
 	"time"
 
 	"github.com/railzwaylabs/railzway/pkg/db/pagination"
@@ -12,6 +13,11 @@ type Service interface {
 	Create(ctx context.Context, req CreateRequest) (*Response, error)
 	List(ctx context.Context, req ListRequest) (ListResponse, error)
 	Get(ctx context.Context, id string) (*Response, error)
+	// ImportCSV validates every row and replaces the price's tiers in one
+	// transaction; any malformed row rejects the whole file.
+	ImportCSV(ctx context.Context, req ImportRequest, r io.Reader) ([]Response, error)
+	// ExportCSV writes the price's tiers in the format ImportCSV accepts.
+	ExportCSV(ctx context.Context, priceID string, w io.Writer) error
 }
 
 type ListRequest struct {
@@ -61,4 +67,6 @@ var (
 	ErrInvalidUnit         = errors.New("invalid_unit")
 	ErrInvalidID           = errors.New("invalid_id")
 	ErrNotFound            = errors.New("not_found")
+	ErrInvalidImportFile   = errors.New("invalid_import_file")
+	ErrTiersNotContiguous  = errors.New("tiers_not_contiguous")
 )
@@ -93,3 +93,27 @@ func (r *repo) List(ctx context.Context, db *gorm.DB, orgID snowflake.ID, page p
 	}
 	return items, nil
 }
+
+func (r *repo) ListByPrice(ctx context.Context, db *gorm.DB, orgID, priceID snowflake.ID) ([]*pricetierdomain.PriceTier, error) {
+	var items []*pricetierdomain.PriceTier
+	err := db.WithContext(ctx).Raw(
+		`SELECT id, org_id, price_id, tier_mode, start_quantity, end_quantity, unit_amount_cents,
+		 flat_amount_cents, unit, idempotency_key, metadata, created_at, updated_at
+		 FROM price_tiers WHERE org_id = ? AND price_id = ?
+		 ORDER BY start_quantity ASC`,
+		orgID,
+		priceID,
+	).Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *repo) DeleteByPrice(ctx context.Context, db *gorm.DB, orgID, priceID snowflake.ID) error {
+	return db.WithContext(ctx).Exec(
+		`DELETE FROM price_tiers WHERE org_id = ? AND price_id = ?`,
+		orgID,
+		priceID,
+	).Error
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricetierdomain "github.com/railzwaylabs/railzway/internal/pricetier/domain"
+	"gorm.io/gorm"
+)
+
+// ImportCSV replaces the tiers of a price with the rows of a CSV file. Every
+// row is validated and the tiers must be contiguous before anything is
+// written; the old tiers are then swapped out in a single transaction.
+func (s *Service) ImportCSV(ctx context.Context, req pricetierdomain.ImportRequest, r io.Reader) ([]pricetierdomain.Response, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, pricetierdomain.ErrInvalidOrganization
+	}
+	if r == nil {
+		return nil, pricetierdomain.ErrInvalidImportFile
+	}
+
+	priceID, err := parseID(req.PriceID)
+	if err != nil {
+		return nil, pricetierdomain.ErrInvalidPrice
+	}
+	if err := s.ensurePriceExists(ctx, orgID, priceID); err != nil {
+		return nil, err
+	}
+
+	// Tier mode and unit are not part of the file; they carry over from the
+	// tiers being replaced.
+	existing, err := s.repo.ListByPrice(ctx, s.db, orgID, priceID)
+	if err != nil {
+		return nil, err
+	}
+	unit := strings.TrimSpace(req.Unit)
+	var tierMode int16
+	if len(existing) > 0 && existing[0] != nil {
+		if unit == "" {
+			unit = existing[0].Unit
+		}
+		tierMode = existing[0].TierMode
+	}
+	if unit == "" {
+		return nil, pricetierdomain.ErrInvalidUnit
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, pricetierdomain.ErrInvalidImportFile
+	}
+	columns, err := parseTierCSVHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	tiers := make([]pricetierdomain.PriceTier, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, &pricetierdomain.ImportRowError{Line: parseErr.Line, Err: pricetierdomain.ErrInvalidImportFile}
+			}
+			return nil, pricetierdomain.ErrInvalidImportFile
+		}
+		line, _ := reader.FieldPos(0)
+
+		tierReq, err := parseTierCSVRow(columns, record)
+		if err == nil {
+			tierReq.TierMode = tierMode
+			err = validateTierValues(tierReq)
+		}
+		if err != nil {
+			return nil, &pricetierdomain.ImportRowError{Line: line, Err: err}
+		}
+
+		tiers = append(tiers, pricetierdomain.PriceTier{
+			ID:              s.genID.Generate(),
+			OrgID:           orgID,
+			PriceID:         priceID,
+			TierMode:        tierMode,
+			StartQuantity:   tierReq.StartQuantity,
+			EndQuantity:     tierReq.EndQuantity,
+			UnitAmountCents: tierReq.UnitAmountCents,
+			FlatAmountCents: tierReq.FlatAmountCents,
+			Unit:            unit,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		})
+	}
+	if len(tiers) == 0 {
+		return nil, pricetierdomain.ErrInvalidImportFile
+	}
+
+	sort.SliceStable(tiers, func(i, j int) bool { return tiers[i].StartQuantity < tiers[j].StartQuantity })
+	if !pricetierdomain.TiersContiguous(tiers) {
+		return nil, pricetierdomain.ErrTiersNotContiguous
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.DeleteByPrice(ctx, tx, orgID, priceID); err != nil {
+			return err
+		}
+		for i := range tiers {
+			if err := s.repo.Insert(ctx, tx, &tiers[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]pricetierdomain.Response, 0, len(tiers))
+	for i := range tiers {
+		resp = append(resp, *s.toResponse(&tiers[i]))
+	}
+	return resp, nil
+}
+
+// ExportCSV writes the tiers of a price, lowest first, with the header row
+// ImportCSV expects.
+func (s *Service) ExportCSV(ctx context.Context, priceID string, w io.Writer) error {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return pricetierdomain.ErrInvalidOrganization
+	}
+
+	id, err := parseID(priceID)
+	if err != nil {
+		return pricetierdomain.ErrInvalidPrice
+	}
+	if err := s.ensurePriceExists(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	tiers, err := s.repo.ListByPrice(ctx, s.db, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(pricetierdomain.CSVColumns); err != nil {
+		return err
+	}
+	for _, tier := range tiers {
+		if tier == nil {
+			continue
+		}
+		row := []string{formatQuantity(tier.StartQuantity), "", "", ""}
+		if tier.EndQuantity != nil {
+			row[1] = formatQuantity(*tier.EndQuantity)
+		}
+		if tier.UnitAmountCents != nil {
+			row[2] = strconv.FormatInt(*tier.UnitAmountCents, 10)
+		}
+		if tier.FlatAmountCents != nil {
+			row[3] = strconv.FormatInt(*tier.FlatAmountCents, 10)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func parseTierCSVHeader(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if name == "" {
+			continue
+		}
+		if _, exists := columns[name]; exists {
+			return nil, pricetierdomain.ErrInvalidImportFile
+		}
+		columns[name] = i
+	}
+	for _, name := range pricetierdomain.CSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, pricetierdomain.ErrInvalidImportFile
+		}
+	}
+	return columns, nil
+}
+
+// parseTierCSVRow reads a data row into a tier request. Empty end, unit and
+// flat cells leave the value unset; start_quantity is required.
+func parseTierCSVRow(columns map[string]int, record []string) (pricetierdomain.CreateRequest, error) {
+	field := func(name string) string {
+		idx := columns[name]
+		if idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	var req pricetierdomain.CreateRequest
+	start, err := strconv.ParseFloat(field(pricetierdomain.CSVColumnStartQuantity), 64)
+	if err != nil {
+		return req, pricetierdomain.ErrInvalidStartQty
+	}
+	req.StartQuantity = start
+
+	if raw := field(pricetierdomain.CSVColumnEndQuantity); raw != "" {
+		end, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return req, pricetierdomain.ErrInvalidEndQty
+		}
+		req.EndQuantity = &end
+	}
+	if raw := field(pricetierdomain.CSVColumnUnitAmountCents); raw != "" {
+		amount, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return req, pricetierdomain.ErrInvalidUnitAmount
+		}
+		req.UnitAmountCents = &amount
+	}
+	if raw := field(pricetierdomain.CSVColumnFlatAmountCents); raw != "" {
+		amount, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return req, pricetierdomain.ErrInvalidFlatAmount
+		}
+		req.FlatAmountCents = &amount
+	}
+	return req, nil
+}
+
+func formatQuantity(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
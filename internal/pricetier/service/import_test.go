@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	pricerepository "github.com/railzwaylabs/railzway/internal/price/repository"
+	pricetierdomain "github.com/railzwaylabs/railzway/internal/pricetier/domain"
+	"github.com/railzwaylabs/railzway/internal/pricetier/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestImportCSV_ReplacesTiersAndRoundTrips(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&pricedomain.Price{}, &pricetierdomain.PriceTier{}))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	priceID := node.Generate()
+	require.NoError(t, db.Create(&pricedomain.Price{
+		ID:           priceID,
+		OrgID:        orgID,
+		ProductID:    node.Generate(),
+		Code:         "api",
+		PricingModel: pricedomain.TieredGraduated,
+		Active:       true,
+	}).Error)
+
+	svc := New(Params{DB: db, Log: zap.NewNop(), GenID: node, Repo: repository.Provide(), PriceRepo: pricerepository.Provide()})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	stale := int64(999)
+	_, err = svc.Create(ctx, pricetierdomain.CreateRequest{PriceID: priceID.String(), StartQuantity: 0, UnitAmountCents: &stale, Unit: "call"})
+	require.NoError(t, err)
+
+	importCSV := func(body string) ([]pricetierdomain.Response, error) {
+		return svc.ImportCSV(ctx, pricetierdomain.ImportRequest{PriceID: priceID.String()}, strings.NewReader(body))
+	}
+
+	_, err = importCSV("start_quantity,end_quantity,unit_amount_cents,flat_amount_cents\n0,100,10,\n100,abc,5,\n")
+	var rowErr *pricetierdomain.ImportRowError
+	require.ErrorAs(t, err, &rowErr)
+	assert.Equal(t, 3, rowErr.Line)
+	assert.ErrorIs(t, err, pricetierdomain.ErrInvalidEndQty)
+
+	_, err = importCSV("start_quantity,end_quantity,unit_amount_cents,flat_amount_cents\n0,100,10,\n150,,5,\n")
+	assert.ErrorIs(t, err, pricetierdomain.ErrTiersNotContiguous)
+
+	// Rejected files leave the existing tier in place.
+	var count int64
+	require.NoError(t, db.Model(&pricetierdomain.PriceTier{}).Where("price_id = ?", priceID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	csv := "start_quantity,end_quantity,unit_amount_cents,flat_amount_cents\n100,1000.5,5,\n0,100,10,500\n1000.5,,2,\n"
+	tiers, err := importCSV(csv)
+	require.NoError(t, err)
+	require.Len(t, tiers, 3)
+	assert.Equal(t, "call", tiers[0].Unit)
+	assert.Equal(t, int64(500), *tiers[0].FlatAmountCents)
+	assert.Nil(t, tiers[2].EndQuantity)
+
+	var exported bytes.Buffer
+	require.NoError(t, svc.ExportCSV(ctx, priceID.String(), &exported))
+	assert.Equal(t, "start_quantity,end_quantity,unit_amount_cents,flat_amount_cents\n0,100,10,500\n100,1000.5,5,\n1000.5,,2,\n", exported.String())
+
+	_, err = importCSV(exported.String())
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&pricetierdomain.PriceTier{}).Where("price_id = ?", priceID).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+
+	err = svc.ExportCSV(ctx, node.Generate().String(), &exported)
+	assert.ErrorIs(t, err, pricetierdomain.ErrInvalidPrice)
+}
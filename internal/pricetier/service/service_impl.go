@@ -246,7 +246,7 @@ func validateTierValues(req pricetierdomain.CreateRequest) error {
 		return pricetierdomain.ErrInvalidTierMode
 	}
 
-	if req.StartQuantity <= 0 {
+	if req.StartQuantity < 0 {
 		return pricetierdomain.ErrInvalidStartQty
 	}
 
@@ -8,8 +8,8 @@ import (
 
 type Product struct {
 	ID          int64             `json:"id" gorm:"primaryKey"`
-	OrgID       int64             `json:"organization_id" gorm:"column:org_id;not null;index:ux_products_org_code,priority:1"`
-	Code        string            `json:"code" gorm:"type:text;not null;index:ux_products_org_code,priority:2"`
+	OrgID       int64             `json:"organization_id" gorm:"column:org_id;not null;uniqueIndex:ux_products_org_code,priority:1"`
+	Code        string            `json:"code" gorm:"type:text;not null;uniqueIndex:ux_products_org_code,priority:2"`
 	Name        string            `json:"name" gorm:"type:text;not null"`
 	Description *string           `json:"description,omitempty" gorm:"type:text"`
 	Active      bool              `json:"active" gorm:"not null;default:true"`
@@ -65,4 +65,5 @@ var (
 	ErrInvalidName         = errors.New("invalid_name")
 	ErrNotFound            = errors.New("not_found")
 	ErrInvalidID           = errors.New("invalid_id")
+	ErrDuplicateCode       = errors.New("duplicate_code")
 )
@@ -9,6 +9,7 @@ import (
 	"github.com/bwmarrin/snowflake"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
 	"github.com/railzwaylabs/railzway/internal/product/domain"
+	"github.com/railzwaylabs/railzway/pkg/db"
 	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -173,6 +174,9 @@ func (s *Service) Create(ctx context.Context, req domain.CreateRequest) (*domain
 				return &resp, nil
 			}
 		}
+		if db.IsDuplicateKeyErr(err) {
+			return nil, domain.ErrDuplicateCode
+		}
 		return nil, err
 	}
 	resp := s.toResponse(p)
@@ -0,0 +1,134 @@
+package email
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Delivery statuses tracked for sent emails.
+const (
+	DeliveryStatusSent       = "sent"
+	DeliveryStatusDelivered  = "delivered"
+	DeliveryStatusBounced    = "bounced"
+	DeliveryStatusComplained = "complained"
+	DeliveryStatusFailed     = "failed"
+)
+
+var (
+	ErrUnsupportedDeliveryProvider = errors.New("unsupported_email_delivery_provider")
+	ErrInvalidDeliveryPayload      = errors.New("invalid_email_delivery_payload")
+)
+
+// DeliveryEvent is a delivery status reported by an email provider for a
+// message sent with a known Message-ID.
+type DeliveryEvent struct {
+	MessageID  string
+	Status     string
+	Reason     string
+	OccurredAt time.Time
+}
+
+// ParseDeliveryEvents normalizes a delivery webhook payload. "generic" takes
+// one or a list of {message_id, status, reason, occurred_at} objects using the
+// statuses above; "sendgrid" takes SendGrid's event webhook batch. Events that
+// do not change delivery status, such as opens, are dropped.
+func ParseDeliveryEvents(provider string, payload []byte) ([]DeliveryEvent, error) {
+	switch strings.ToLower(strings.TrimSpace(provider)) {
+	case "generic":
+		return parseGenericDeliveryEvents(payload)
+	case "sendgrid":
+		return parseSendGridDeliveryEvents(payload)
+	default:
+		return nil, ErrUnsupportedDeliveryProvider
+	}
+}
+
+func parseGenericDeliveryEvents(payload []byte) ([]DeliveryEvent, error) {
+	type genericEvent struct {
+		MessageID  string    `json:"message_id"`
+		Status     string    `json:"status"`
+		Reason     string    `json:"reason"`
+		OccurredAt time.Time `json:"occurred_at"`
+	}
+
+	var raw []genericEvent
+	trimmed := bytes.TrimSpace(payload)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var single genericEvent
+		if err := json.Unmarshal(trimmed, &single); err != nil {
+			return nil, ErrInvalidDeliveryPayload
+		}
+		raw = append(raw, single)
+	} else if err := json.Unmarshal(trimmed, &raw); err != nil {
+		return nil, ErrInvalidDeliveryPayload
+	}
+
+	events := make([]DeliveryEvent, 0, len(raw))
+	for _, item := range raw {
+		status := strings.ToLower(strings.TrimSpace(item.Status))
+		switch status {
+		case DeliveryStatusDelivered, DeliveryStatusBounced, DeliveryStatusComplained, DeliveryStatusFailed:
+		default:
+			continue
+		}
+		events = append(events, DeliveryEvent{
+			MessageID:  normalizeMessageID(item.MessageID),
+			Status:     status,
+			Reason:     strings.TrimSpace(item.Reason),
+			OccurredAt: item.OccurredAt,
+		})
+	}
+	return events, nil
+}
+
+func parseSendGridDeliveryEvents(payload []byte) ([]DeliveryEvent, error) {
+	var raw []struct {
+		Event     string `json:"event"`
+		SMTPID    string `json:"smtp-id"`
+		Reason    string `json:"reason"`
+		Response  string `json:"response"`
+		Timestamp int64  `json:"timestamp"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, ErrInvalidDeliveryPayload
+	}
+
+	events := make([]DeliveryEvent, 0, len(raw))
+	for _, item := range raw {
+		var status string
+		switch item.Event {
+		case "delivered":
+			status = DeliveryStatusDelivered
+		case "bounce":
+			status = DeliveryStatusBounced
+		case "dropped":
+			status = DeliveryStatusFailed
+		case "spamreport":
+			status = DeliveryStatusComplained
+		default:
+			continue
+		}
+		reason := strings.TrimSpace(item.Reason)
+		if reason == "" {
+			reason = strings.TrimSpace(item.Response)
+		}
+		var occurredAt time.Time
+		if item.Timestamp > 0 {
+			occurredAt = time.Unix(item.Timestamp, 0).UTC()
+		}
+		events = append(events, DeliveryEvent{
+			MessageID:  normalizeMessageID(item.SMTPID),
+			Status:     status,
+			Reason:     reason,
+			OccurredAt: occurredAt,
+		})
+	}
+	return events, nil
+}
+
+func normalizeMessageID(value string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "<"), ">")
+}
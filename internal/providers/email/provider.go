@@ -1,6 +1,9 @@
 package email
 
-import "context"
+import (
+	"context"
+	"strings"
+)
 
 type Attachment struct {
 	Filename string
@@ -15,6 +18,7 @@ type EmailMessage struct {
 	Subject     string
 	HTMLBody    string // For Send()
 	Attachments []Attachment
+	MessageID   string // Optional, sent as the Message-ID header so delivery events can be matched
 }
 
 type Provider interface {
@@ -31,3 +35,9 @@ func (p *NoOpProvider) Send(ctx context.Context, msg EmailMessage) error {
 func (p *NoOpProvider) SendTemplate(ctx context.Context, msg EmailMessage, templateName string, data interface{}) error {
 	return nil
 }
+
+// NewMessageID returns a Message-ID, without angle brackets, built from a
+// value unique to the sender such as a snowflake ID.
+func NewMessageID(unique string) string {
+	return strings.TrimSpace(unique) + "@railzway"
+}
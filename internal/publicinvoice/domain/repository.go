@@ -26,6 +26,7 @@ type InvoiceRecord struct {
 	SubtotalAmount int64             `gorm:"column:subtotal_amount"`
 	TaxAmount      int64             `gorm:"column:tax_amount"`
 	TotalAmount    int64             `gorm:"column:total_amount"`
+	AmountPaid     int64             `gorm:"column:amount_paid"`
 	Currency       string            `gorm:"column:currency"`
 	IssuedAt       *time.Time        `gorm:"column:issued_at"`
 	DueAt          *time.Time        `gorm:"column:due_at"`
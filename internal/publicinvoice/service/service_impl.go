@@ -523,8 +523,7 @@ func (s *Service) buildPublicInvoiceView(
 		// Ledgers are the source of truth for partial payments (credits)
 		amountPaid = settledAmount
 	} else {
-		// Fallback for non-ledger systems (should be deprecated)
-		amountPaid = readMetadataAmount(row.Metadata, "amount_paid")
+		amountPaid = row.AmountPaid
 	}
 
 	// Safety clamping
@@ -535,7 +534,8 @@ func (s *Service) buildPublicInvoiceView(
 		amountPaid = totalAmount
 	}
 
-	amountDue := totalAmount - amountPaid
+	// Credit notes reduce what is owed without counting as a payment.
+	amountDue := totalAmount - amountPaid - readMetadataAmount(row.Metadata, "amount_credited")
 	if amountDue < 0 {
 		amountDue = 0
 	}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apikeydomain "github.com/railzwaylabs/railzway/internal/apikey/domain"
+	"github.com/railzwaylabs/railzway/internal/config"
+	redis "github.com/redis/go-redis/v9"
+)
+
+const keyAPIKeyRate = "api:key:%s"
+
+// APIKeyLimiter enforces a per API key request rate chosen by the key's
+// rate limit tier. Each key gets a token bucket holding a minute of requests
+// that refills continuously.
+type APIKeyLimiter struct {
+	enabled bool
+
+	bucket *TokenBucket
+	tiers  map[string]int
+}
+
+func NewAPIKeyLimiter(cfg config.Config, client *redis.Client) (*APIKeyLimiter, error) {
+	limitCfg := cfg.RateLimit
+	if !limitCfg.Enabled {
+		return nil, nil
+	}
+	if limitCfg.APIKeyFreeRPM <= 0 || limitCfg.APIKeyProRPM <= 0 {
+		return nil, errors.New("api key rate limit must be positive")
+	}
+	if client == nil {
+		return nil, errors.New("redis client is required for rate limiter")
+	}
+
+	return &APIKeyLimiter{
+		enabled: true,
+		bucket:  NewTokenBucket(client),
+		tiers: map[string]int{
+			apikeydomain.RateLimitTierFree: limitCfg.APIKeyFreeRPM,
+			apikeydomain.RateLimitTierPro:  limitCfg.APIKeyProRPM,
+		},
+	}, nil
+}
+
+func (l *APIKeyLimiter) Enabled() bool {
+	return l != nil && l.enabled
+}
+
+// LimitPerMinute returns the requests per minute allowed for tier. Unknown
+// tiers get the free limit.
+func (l *APIKeyLimiter) LimitPerMinute(tier string) int {
+	if l == nil {
+		return 0
+	}
+	if limit, ok := l.tiers[strings.ToLower(strings.TrimSpace(tier))]; ok {
+		return limit
+	}
+	return l.tiers[apikeydomain.RateLimitTierFree]
+}
+
+func (l *APIKeyLimiter) Allow(ctx context.Context, apiKeyID, tier string) (*RateLimitResult, error) {
+	if !l.Enabled() {
+		return &RateLimitResult{Allowed: true}, nil
+	}
+	limit := l.LimitPerMinute(tier)
+	return l.bucket.Allow(ctx, fmt.Sprintf(keyAPIKeyRate, strings.TrimSpace(apiKeyID)), float64(limit)/60, limit)
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	apikeydomain "github.com/railzwaylabs/railzway/internal/apikey/domain"
+	"github.com/railzwaylabs/railzway/internal/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAPIKeyLimiter(t *testing.T) *APIKeyLimiter {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	limiter, err := NewAPIKeyLimiter(config.Config{RateLimit: config.RateLimitConfig{
+		Enabled:       true,
+		APIKeyFreeRPM: 2,
+		APIKeyProRPM:  4,
+	}}, redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	require.NoError(t, err)
+	return limiter
+}
+
+func TestAPIKeyLimiter_LimitsEachKeyByTier(t *testing.T) {
+	limiter := newTestAPIKeyLimiter(t)
+	ctx := context.Background()
+
+	allowed := func(apiKeyID, tier string) int {
+		count := 0
+		for i := 0; i < 6; i++ {
+			result, err := limiter.Allow(ctx, apiKeyID, tier)
+			require.NoError(t, err)
+			assert.Equal(t, limiter.LimitPerMinute(tier), result.Limit)
+			if result.Allowed {
+				count++
+			}
+		}
+		return count
+	}
+
+	assert.Equal(t, 2, allowed("1", apikeydomain.RateLimitTierFree))
+	assert.Equal(t, 4, allowed("2", apikeydomain.RateLimitTierPro))
+	// Unknown tiers fall back to the free limit.
+	assert.Equal(t, 2, allowed("3", "enterprise"))
+
+	// Keys are limited independently of each other.
+	result, err := limiter.Allow(ctx, "4", apikeydomain.RateLimitTierFree)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+}
+
+func TestAPIKeyLimiter_LimitPerMinute(t *testing.T) {
+	limiter := newTestAPIKeyLimiter(t)
+
+	assert.Equal(t, 2, limiter.LimitPerMinute(apikeydomain.RateLimitTierFree))
+	assert.Equal(t, 4, limiter.LimitPerMinute(" PRO "))
+	assert.Equal(t, 2, limiter.LimitPerMinute(""))
+}
+
+func TestNewAPIKeyLimiter_Config(t *testing.T) {
+	limiter, err := NewAPIKeyLimiter(config.Config{}, nil)
+	require.NoError(t, err)
+	assert.False(t, limiter.Enabled())
+	result, err := limiter.Allow(context.Background(), "1", apikeydomain.RateLimitTierFree)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	_, err = NewAPIKeyLimiter(config.Config{RateLimit: config.RateLimitConfig{
+		Enabled:       true,
+		APIKeyFreeRPM: 0,
+		APIKeyProRPM:  10,
+	}}, redis.NewClient(&redis.Options{}))
+	assert.Error(t, err)
+
+	_, err = NewAPIKeyLimiter(config.Config{RateLimit: config.RateLimitConfig{
+		Enabled:       true,
+		APIKeyFreeRPM: 5,
+		APIKeyProRPM:  10,
+	}}, nil)
+	assert.Error(t, err)
+}
@@ -4,4 +4,5 @@ import "go.uber.org/fx"
 
 var Module = fx.Module("rate.limit",
 	fx.Provide(NewUsageIngestLimiter),
+	fx.Provide(NewAPIKeyLimiter),
 )
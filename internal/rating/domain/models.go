@@ -8,12 +8,38 @@ import (
 	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
 )
 
+// RatingSourceProration marks rating results written for mid-cycle
+// subscription item changes rather than by a rating run.
+const RatingSourceProration = "proration"
+
+// RatingSourceMinimumCommitment marks the true-up that brings a cycle up to
+// the subscription's minimum commitment.
+const RatingSourceMinimumCommitment = "minimum_commitment"
+
+// RatingSourceDiscount marks the negative rows that apply a subscription's
+// coupon discounts to a cycle.
+const RatingSourceDiscount = "discount"
+
+// RatingSourceIncluded marks the zero-priced usage covered by a subscription
+// item's included allowance.
+const RatingSourceIncluded = "included"
+
+// RatingSourceThreshold marks usage billed mid-cycle because an item crossed
+// its billing threshold, and the arrears rows that deduct it again when the
+// cycle closes.
+const RatingSourceThreshold = "threshold"
+
+// RatingSourceSeatOverage marks the seats a licensed item's seat meter
+// counted above the licensed quantity, billed at the item's overage price.
+const RatingSourceSeatOverage = "seat_overage"
+
 // RatingResult captures the priced usage output for a billing cycle.
 type RatingResult struct {
 	ID             snowflake.ID  `gorm:"primaryKey"`
 	OrgID          snowflake.ID  `gorm:"not null;index"`
 	SubscriptionID snowflake.ID  `gorm:"not null;index"`
 	BillingCycleID snowflake.ID  `gorm:"not null;index"`
+	BillingPhase   string        `gorm:"type:text;not null;default:'arrears'"`
 	PriceID        snowflake.ID  `gorm:"not null"`
 	FeatureCode    string        `gorm:"type:text"`
 	MeterID        *snowflake.ID `gorm:"index"`
@@ -31,6 +57,24 @@ type RatingResult struct {
 // TableName sets the database table name.
 func (RatingResult) TableName() string { return "rating_results" }
 
+// ThresholdUsage tracks how much of a subscription item's usage in a cycle was
+// already billed by threshold invoices.
+type ThresholdUsage struct {
+	ID                 snowflake.ID `gorm:"primaryKey"`
+	OrgID              snowflake.ID `gorm:"not null;index"`
+	BillingCycleID     snowflake.ID `gorm:"not null;uniqueIndex:ux_billing_threshold_usage,priority:1"`
+	SubscriptionItemID snowflake.ID `gorm:"not null;uniqueIndex:ux_billing_threshold_usage,priority:2"`
+	BilledQuantity     float64      `gorm:"not null;default:0"`
+	BilledAmount       int64        `gorm:"not null;default:0"`
+	Currency           string       `gorm:"type:text;not null"`
+	BilledThrough      time.Time    `gorm:"not null"`
+	CreatedAt          time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt          time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName sets the database table name.
+func (ThresholdUsage) TableName() string { return "billing_threshold_usages" }
+
 type BillingCycleRow struct {
 	ID             snowflake.ID
 	OrgID          snowflake.ID
@@ -46,4 +90,33 @@ type SubscriptionItemRow struct {
 	SubscriptionID snowflake.ID
 	PriceID        snowflake.ID
 	MeterID        *snowflake.ID
+	Quantity       int32
+	UsageBehavior  *string
+	// IncludedQuantity is the usage rated at zero before the price applies.
+	IncludedQuantity *float64
+	// BillingThreshold is the usage quantity that triggers a mid-cycle
+	// invoice.
+	BillingThreshold *float64
+	DimensionKey     *string
+	DimensionValue   *string
+	// SeatMeterID counts the active seats of a licensed item; seats above
+	// Quantity are billed at OveragePriceID.
+	SeatMeterID    *snowflake.ID
+	OveragePriceID *snowflake.ID
+}
+
+// DimensionFilter restricts usage aggregation to events tagged with
+// dimensions[Key] = Value.
+type DimensionFilter struct {
+	Key   string
+	Value string
+}
+
+// Dimension returns the item's usage dimension binding, or nil when the item
+// rates all usage of its meter.
+func (r SubscriptionItemRow) Dimension() *DimensionFilter {
+	if r.DimensionKey == nil || r.DimensionValue == nil || *r.DimensionKey == "" {
+		return nil
+	}
+	return &DimensionFilter{Key: *r.DimensionKey, Value: *r.DimensionValue}
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
 	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
 )
 
@@ -13,7 +14,16 @@ type Repository interface {
 	GetSubscription(ctx context.Context, orgID, subID snowflake.ID) (*subscriptiondomain.Subscription, error)
 	ListSubscriptionItems(ctx context.Context, orgID, subID snowflake.ID) ([]SubscriptionItemRow, error)
 	ListEntitlements(ctx context.Context, orgID, subID snowflake.ID, start, end time.Time) ([]subscriptiondomain.SubscriptionEntitlement, error)
-	AggregateUsage(ctx context.Context, orgID, subID, meterID snowflake.ID, start, end time.Time) (float64, error)
-	DeleteRatingResults(ctx context.Context, cycleID snowflake.ID) error
-	InsertRatingResult(ctx context.Context, result RatingResult) error
+	AggregateUsage(ctx context.Context, orgID, subID, meterID snowflake.ID, start, end time.Time, dimension *DimensionFilter) (float64, error)
+	MarkUsageRated(ctx context.Context, orgID, subID snowflake.ID, start, end, at time.Time) error
+	MeterResetInterval(ctx context.Context, orgID, meterID snowflake.ID) (string, error)
+	DeleteRatingResultsExcept(ctx context.Context, cycleID snowflake.ID, phase billingcycledomain.BillingPhase, checksums []string, sources ...string) error
+	UpsertRatingResult(ctx context.Context, result RatingResult) error
+	ListRatingResults(ctx context.Context, cycleID snowflake.ID, phase billingcycledomain.BillingPhase) ([]RatingResult, error)
+	SumRatingAmounts(ctx context.Context, cycleID snowflake.ID, currency string) (int64, error)
+	SumPhaseCharges(ctx context.Context, cycleID snowflake.ID, phase billingcycledomain.BillingPhase, currency string) (int64, error)
+	SumAmountByChecksum(ctx context.Context, checksum string) (int64, error)
+	FindThresholdUsageForUpdate(ctx context.Context, cycleID, itemID snowflake.ID) (*ThresholdUsage, error)
+	ListThresholdUsages(ctx context.Context, cycleID snowflake.ID) ([]ThresholdUsage, error)
+	UpsertThresholdUsage(ctx context.Context, usage ThresholdUsage) error
 }
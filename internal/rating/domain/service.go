@@ -7,12 +7,22 @@ import (
 
 type Service interface {
 	RunRating(context.Context, string) error
+	// RunAdvanceRating rates items billed in advance for an open billing cycle.
+	RunAdvanceRating(context.Context, string) error
+	// RunThresholdRating bills the usage of an open billing cycle's items
+	// that crossed their billing threshold since it was last billed.
+	RunThresholdRating(context.Context, string) error
+	// DryRunRating computes the arrears rating of a billing cycle in any
+	// status without persisting the results.
+	DryRunRating(context.Context, string) ([]RatingResult, error)
 }
 
 var (
+	ErrInvalidOrganization    = errors.New("invalid_organization")
 	ErrInvalidBillingCycle    = errors.New("invalid_billing_cycle")
 	ErrBillingCycleNotFound   = errors.New("billing_cycle_not_found")
 	ErrBillingCycleNotClosing = errors.New("billing_cycle_not_closing")
+	ErrBillingCycleNotOpen    = errors.New("billing_cycle_not_open")
 	ErrMissingUsage           = errors.New("missing_usage")
 	ErrMissingPriceAmount     = errors.New("missing_price_amount")
 	ErrMissingPriceTier       = errors.New("missing_price_tier")
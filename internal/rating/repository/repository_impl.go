@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
 	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
 	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
 	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
@@ -53,7 +55,9 @@ func (r *repository) GetSubscription(ctx context.Context, orgID, subID snowflake
 func (r *repository) ListSubscriptionItems(ctx context.Context, orgID, subID snowflake.ID) ([]ratingdomain.SubscriptionItemRow, error) {
 	var items []ratingdomain.SubscriptionItemRow
 	err := r.db.WithContext(ctx).Raw(
-		`SELECT id, org_id, subscription_id, price_id, meter_id
+		`SELECT id, org_id, subscription_id, price_id, meter_id, COALESCE(quantity, 1) AS quantity, usage_behavior,
+		        dimension_key, dimension_value, included_quantity, billing_threshold,
+		        seat_meter_id, overage_price_id
 		 FROM subscription_items
 		 WHERE org_id = ? AND subscription_id = ?`,
 		orgID,
@@ -73,39 +77,214 @@ func (r *repository) ListEntitlements(ctx context.Context, orgID, subID snowflak
 	return rows, err
 }
 
-func (r *repository) AggregateUsage(ctx context.Context, orgID, subID, meterID snowflake.ID, start, end time.Time) (float64, error) {
+// AggregateUsage rolls up the enriched and rated usage of a meter in [start, end) with
+// the meter's aggregation. Meters with an unknown aggregation are summed. A
+// non-nil dimension only counts events tagged with that dimension value.
+// Adjustment events are summed with the usage they correct, and a window
+// never aggregates below zero.
+//
+// Whole hours inside the window are read from usage_rollups; raw events are
+// only scanned for the partial hours at either end and for events the rollup
+// worker has not reached yet. Rollups carry no dimensions and cannot count
+// distinct values, so those aggregations always scan raw events.
+func (r *repository) AggregateUsage(ctx context.Context, orgID, subID, meterID snowflake.ID, start, end time.Time, dimension *ratingdomain.DimensionFilter) (float64, error) {
+	aggregation, err := r.meterAggregation(ctx, orgID, meterID)
+	if err != nil {
+		return 0, err
+	}
+
+	hoursStart := usagedomain.RollupBucket(start)
+	if hoursStart.Before(start) {
+		hoursStart = hoursStart.Add(time.Hour)
+	}
+	hoursEnd := usagedomain.RollupBucket(end)
+	if dimension != nil || aggregation == meterdomain.AggregationUniqueCount || !hoursStart.Before(hoursEnd) {
+		return r.aggregateRawUsage(ctx, aggregation, orgID, subID, meterID, start, end, dimension)
+	}
+
+	// Both sources are read in one statement so an event the worker rolls
+	// up meanwhile is counted exactly once.
+	raw := `FROM usage_events
+		 WHERE org_id = ? AND subscription_id = ? AND meter_id = ?
+		 AND recorded_at >= ? AND recorded_at < ? AND status IN (?, ?)
+		 AND (recorded_at < ? OR recorded_at >= ? OR rolled_up_at IS NULL)`
+	rolled := `FROM usage_rollups
+		 WHERE org_id = ? AND subscription_id = ? AND meter_id = ?
+		 AND bucket_start >= ? AND bucket_start < ?`
+	args := []any{
+		orgID, subID, meterID, start, end, usagedomain.UsageStatusEnriched, usagedomain.UsageStatusRated, hoursStart, hoursEnd,
+		orgID, subID, meterID, hoursStart, hoursEnd,
+	}
+
+	var query string
+	switch aggregation {
+	case meterdomain.AggregationMax:
+		query = `SELECT COALESCE(MAX(value), 0) FROM (
+			SELECT value ` + raw + `
+			UNION ALL
+			SELECT max_value AS value ` + rolled + `
+		) usage`
+	case meterdomain.AggregationCount:
+		query = `SELECT COALESCE(SUM(event_count), 0) FROM (
+			SELECT COUNT(1) AS event_count ` + raw + `
+			UNION ALL
+			SELECT event_count ` + rolled + `
+		) usage`
+	case meterdomain.AggregationLast:
+		query = `SELECT value FROM (
+			SELECT value, recorded_at, id ` + raw + `
+			UNION ALL
+			SELECT last_value AS value, last_recorded_at AS recorded_at, last_event_id AS id ` + rolled + `
+		) usage
+		ORDER BY recorded_at DESC, id DESC LIMIT 1`
+	default:
+		query = `SELECT COALESCE(SUM(value), 0) FROM (
+			SELECT value ` + raw + `
+			UNION ALL
+			SELECT sum_value AS value ` + rolled + `
+		) usage`
+	}
+
 	var quantity float64
-	err := r.db.WithContext(ctx).Raw(
-		`SELECT COALESCE(SUM(value), 0)
-		 FROM usage_events
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&quantity).Error; err != nil {
+		return 0, err
+	}
+	return floorUsage(quantity), nil
+}
+
+func (r *repository) aggregateRawUsage(ctx context.Context, aggregation string, orgID, subID, meterID snowflake.ID, start, end time.Time, dimension *ratingdomain.DimensionFilter) (float64, error) {
+	filter := `FROM usage_events
 		 WHERE org_id = ? AND subscription_id = ? AND meter_id = ?
+		 AND recorded_at >= ? AND recorded_at < ? AND status IN (?, ?)`
+	args := []any{orgID, subID, meterID, start, end, usagedomain.UsageStatusEnriched, usagedomain.UsageStatusRated}
+	if dimension != nil {
+		filter += ` AND dimensions->>? = ?`
+		args = append(args, dimension.Key, dimension.Value)
+	}
+	var query string
+	switch aggregation {
+	case meterdomain.AggregationMax:
+		query = `SELECT COALESCE(MAX(value), 0) ` + filter
+	case meterdomain.AggregationCount:
+		query = `SELECT COUNT(1) ` + filter
+	case meterdomain.AggregationUniqueCount:
+		query = `SELECT COUNT(DISTINCT value) ` + filter
+	case meterdomain.AggregationLast:
+		query = `SELECT value ` + filter + ` ORDER BY recorded_at DESC, id DESC LIMIT 1`
+	default:
+		query = `SELECT COALESCE(SUM(value), 0) ` + filter
+	}
+
+	var quantity float64
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&quantity).Error; err != nil {
+		return 0, err
+	}
+	return floorUsage(quantity), nil
+}
+
+// MarkUsageRated moves the enriched usage of the subscription recorded in
+// [start, end) to rated.
+func (r *repository) MarkUsageRated(ctx context.Context, orgID, subID snowflake.ID, start, end, at time.Time) error {
+	return r.db.WithContext(ctx).Exec(
+		`UPDATE usage_events
+		 SET status = ?, updated_at = ?
+		 WHERE org_id = ? AND subscription_id = ?
 		 AND recorded_at >= ? AND recorded_at < ? AND status = ?`,
+		usagedomain.UsageStatusRated,
+		at,
 		orgID,
 		subID,
-		meterID,
 		start,
 		end,
 		usagedomain.UsageStatusEnriched,
-	).Scan(&quantity).Error
-	return quantity, err
+	).Error
 }
 
-func (r *repository) DeleteRatingResults(ctx context.Context, cycleID snowflake.ID) error {
-	return r.db.WithContext(ctx).Where("billing_cycle_id = ?", cycleID).Delete(&ratingdomain.RatingResult{}).Error
+// floorUsage keeps a window whose adjustments outweigh its usage from
+// rating below zero.
+func floorUsage(quantity float64) float64 {
+	if quantity < 0 {
+		return 0
+	}
+	return quantity
+}
+
+// MeterResetInterval returns when allowances on the meter start over,
+// defaulting to every billing cycle.
+func (r *repository) MeterResetInterval(ctx context.Context, orgID, meterID snowflake.ID) (string, error) {
+	var raw string
+	if err := r.db.WithContext(ctx).Raw(
+		`SELECT reset_interval FROM meters WHERE org_id = ? AND id = ?`,
+		orgID,
+		meterID,
+	).Scan(&raw).Error; err != nil {
+		return "", err
+	}
+	interval, ok := meterdomain.NormalizeResetInterval(raw)
+	if !ok {
+		return meterdomain.ResetIntervalBillingCycle, nil
+	}
+	return interval, nil
 }
 
-func (r *repository) InsertRatingResult(ctx context.Context, result ratingdomain.RatingResult) error {
+func (r *repository) meterAggregation(ctx context.Context, orgID, meterID snowflake.ID) (string, error) {
+	var raw string
+	if err := r.db.WithContext(ctx).Raw(
+		`SELECT aggregation FROM meters WHERE org_id = ? AND id = ?`,
+		orgID,
+		meterID,
+	).Scan(&raw).Error; err != nil {
+		return "", err
+	}
+	aggregation, ok := meterdomain.NormalizeAggregation(raw)
+	if !ok {
+		return meterdomain.AggregationSum, nil
+	}
+	return aggregation, nil
+}
+
+// DeleteRatingResultsExcept removes the results of a cycle phase whose
+// checksum is not in checksums and whose source is not in sources.
+// Proration rows are written by subscription changes, not by rating, so they
+// are always kept.
+func (r *repository) DeleteRatingResultsExcept(ctx context.Context, cycleID snowflake.ID, phase billingcycledomain.BillingPhase, checksums []string, sources ...string) error {
+	stmt := r.db.WithContext(ctx).
+		Where("billing_cycle_id = ? AND billing_phase = ? AND source <> ?", cycleID, phase, ratingdomain.RatingSourceProration)
+	if len(checksums) > 0 {
+		stmt = stmt.Where("checksum NOT IN ?", checksums)
+	}
+	if len(sources) > 0 {
+		stmt = stmt.Where("source NOT IN ?", sources)
+	}
+	return stmt.Delete(&ratingdomain.RatingResult{}).Error
+}
+
+// UpsertRatingResult writes a result keyed by its checksum. An existing row
+// is only touched when the rated values changed, so it keeps its id and
+// created_at across re-ratings.
+func (r *repository) UpsertRatingResult(ctx context.Context, result ratingdomain.RatingResult) error {
 	return r.db.WithContext(ctx).Exec(
 		`INSERT INTO rating_results (
-			id, org_id, subscription_id, billing_cycle_id, meter_id, price_id, feature_code,
+			id, org_id, subscription_id, billing_cycle_id, billing_phase, meter_id, price_id, feature_code,
 			quantity, unit_price, amount, currency, period_start, period_end,
 			source, checksum, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT (checksum) DO NOTHING`,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (checksum) DO UPDATE SET
+			quantity = excluded.quantity,
+			unit_price = excluded.unit_price,
+			amount = excluded.amount,
+			currency = excluded.currency,
+			source = excluded.source
+		WHERE rating_results.quantity <> excluded.quantity
+			OR rating_results.unit_price <> excluded.unit_price
+			OR rating_results.amount <> excluded.amount
+			OR rating_results.currency <> excluded.currency
+			OR rating_results.source <> excluded.source`,
 		result.ID,
 		result.OrgID,
 		result.SubscriptionID,
 		result.BillingCycleID,
+		result.BillingPhase,
 		result.MeterID,
 		result.PriceID,
 		result.FeatureCode,
@@ -120,3 +299,111 @@ func (r *repository) InsertRatingResult(ctx context.Context, result ratingdomain
 		result.CreatedAt,
 	).Error
 }
+
+// ListRatingResults returns the rating results of a cycle in one phase, in
+// the order they were written.
+func (r *repository) ListRatingResults(ctx context.Context, cycleID snowflake.ID, phase billingcycledomain.BillingPhase) ([]ratingdomain.RatingResult, error) {
+	var results []ratingdomain.RatingResult
+	err := r.db.WithContext(ctx).
+		Where("billing_cycle_id = ? AND billing_phase = ?", cycleID, phase).
+		Order("created_at ASC, id ASC").
+		Find(&results).Error
+	return results, err
+}
+
+// SumRatingAmounts totals the charges of a cycle across both billing phases,
+// leaving out any minimum commitment true-up and discounts.
+func (r *repository) SumRatingAmounts(ctx context.Context, cycleID snowflake.ID, currency string) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Raw(
+		`SELECT COALESCE(SUM(amount), 0)
+		 FROM rating_results
+		 WHERE billing_cycle_id = ? AND currency = ? AND source NOT IN (?, ?)`,
+		cycleID,
+		currency,
+		ratingdomain.RatingSourceMinimumCommitment,
+		ratingdomain.RatingSourceDiscount,
+	).Scan(&total).Error
+	return total, err
+}
+
+// SumPhaseCharges totals what a cycle bills in one phase before discounts.
+func (r *repository) SumPhaseCharges(ctx context.Context, cycleID snowflake.ID, phase billingcycledomain.BillingPhase, currency string) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Raw(
+		`SELECT COALESCE(SUM(amount), 0)
+		 FROM rating_results
+		 WHERE billing_cycle_id = ? AND billing_phase = ? AND currency = ? AND source <> ?`,
+		cycleID,
+		phase,
+		currency,
+		ratingdomain.RatingSourceDiscount,
+	).Scan(&total).Error
+	return total, err
+}
+
+func (r *repository) SumAmountByChecksum(ctx context.Context, checksum string) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Raw(
+		`SELECT COALESCE(SUM(amount), 0)
+		 FROM rating_results
+		 WHERE checksum = ?`,
+		checksum,
+	).Scan(&total).Error
+	return total, err
+}
+
+// FindThresholdUsageForUpdate returns what threshold invoices already billed
+// for an item in a cycle, locking the row, or nil when nothing was billed.
+func (r *repository) FindThresholdUsageForUpdate(ctx context.Context, cycleID, itemID snowflake.ID) (*ratingdomain.ThresholdUsage, error) {
+	query := `SELECT id, org_id, billing_cycle_id, subscription_item_id, billed_quantity, billed_amount,
+		        currency, billed_through, created_at, updated_at
+		 FROM billing_threshold_usages
+		 WHERE billing_cycle_id = ? AND subscription_item_id = ?`
+	if r.db.Dialector.Name() != "sqlite" {
+		query += " FOR UPDATE"
+	}
+	var usage ratingdomain.ThresholdUsage
+	if err := r.db.WithContext(ctx).Raw(query, cycleID, itemID).Scan(&usage).Error; err != nil {
+		return nil, err
+	}
+	if usage.ID == 0 {
+		return nil, nil
+	}
+	return &usage, nil
+}
+
+func (r *repository) ListThresholdUsages(ctx context.Context, cycleID snowflake.ID) ([]ratingdomain.ThresholdUsage, error) {
+	var usages []ratingdomain.ThresholdUsage
+	err := r.db.WithContext(ctx).
+		Where("billing_cycle_id = ?", cycleID).
+		Order("id ASC").
+		Find(&usages).Error
+	return usages, err
+}
+
+// UpsertThresholdUsage records the cumulative usage billed for an item in a
+// cycle, keyed by cycle and item.
+func (r *repository) UpsertThresholdUsage(ctx context.Context, usage ratingdomain.ThresholdUsage) error {
+	return r.db.WithContext(ctx).Exec(
+		`INSERT INTO billing_threshold_usages (
+			id, org_id, billing_cycle_id, subscription_item_id, billed_quantity, billed_amount,
+			currency, billed_through, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (billing_cycle_id, subscription_item_id) DO UPDATE SET
+			billed_quantity = excluded.billed_quantity,
+			billed_amount = excluded.billed_amount,
+			billed_through = excluded.billed_through,
+			updated_at = excluded.updated_at`,
+		usage.ID,
+		usage.OrgID,
+		usage.BillingCycleID,
+		usage.SubscriptionItemID,
+		usage.BilledQuantity,
+		usage.BilledAmount,
+		usage.Currency,
+		usage.BilledThrough,
+		usage.CreatedAt,
+		usage.UpdatedAt,
+	).Error
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"github.com/railzwaylabs/railzway/internal/rating/repository"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// TestAggregateUsage_MeterAggregations rolls the same usage up with every
+// supported aggregation.
+func TestAggregateUsage_MeterAggregations(t *testing.T) {
+	db, _, node := setupProrationTest(t)
+	repo := repository.NewRepository(db)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	windowStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	windowEnd := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := func(aggregation string) snowflake.ID {
+		meter := meterdomain.Meter{
+			ID:          node.Generate(),
+			OrgID:       orgID,
+			Code:        "meter_" + aggregation + "_" + node.Generate().String(),
+			Name:        aggregation,
+			Aggregation: aggregation,
+			Unit:        "unit",
+			Active:      true,
+		}
+		require.NoError(t, db.Create(&meter).Error)
+
+		events := []struct {
+			value float64
+			day   int
+		}{{4, 3}, {9, 10}, {4, 12}, {2, 20}}
+		for _, event := range events {
+			require.NoError(t, db.Create(&usagedomain.UsageEvent{
+				ID:             node.Generate(),
+				OrgID:          orgID,
+				SubscriptionID: subID,
+				MeterID:        meter.ID,
+				Value:          event.value,
+				RecordedAt:     time.Date(2026, 1, event.day, 0, 0, 0, 0, time.UTC),
+				Status:         usagedomain.UsageStatusEnriched,
+			}).Error)
+		}
+		// Outside the window.
+		require.NoError(t, db.Create(&usagedomain.UsageEvent{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			MeterID:        meter.ID,
+			Value:          50,
+			RecordedAt:     windowEnd,
+			Status:         usagedomain.UsageStatusEnriched,
+		}).Error)
+		return meter.ID
+	}
+
+	cases := []struct {
+		aggregation string
+		expected    float64
+	}{
+		{meterdomain.AggregationSum, 19},
+		{meterdomain.AggregationMax, 9},
+		{meterdomain.AggregationLast, 2},
+		{meterdomain.AggregationCount, 4},
+		{meterdomain.AggregationUniqueCount, 3},
+		// Unknown aggregations are summed.
+		{"AVG", 19},
+	}
+	for _, tc := range cases {
+		t.Run(tc.aggregation, func(t *testing.T) {
+			meterID := seed(tc.aggregation)
+			qty, err := repo.AggregateUsage(context.Background(), orgID, subID, meterID, windowStart, windowEnd, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, qty)
+		})
+	}
+
+	// Last reports zero when the window has no usage.
+	meterID := seed(meterdomain.AggregationLast)
+	qty, err := repo.AggregateUsage(context.Background(), orgID, subID, meterID, windowEnd.Add(time.Hour), windowEnd.Add(2*time.Hour), nil)
+	require.NoError(t, err)
+	assert.Zero(t, qty)
+}
+
+// TestAggregateUsage_DimensionFilter rates the same meter separately per
+// region for items bound to a dimension.
+func TestAggregateUsage_DimensionFilter(t *testing.T) {
+	db, _, node := setupProrationTest(t)
+	repo := repository.NewRepository(db)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	windowStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	windowEnd := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	meter := meterdomain.Meter{
+		ID:          node.Generate(),
+		OrgID:       orgID,
+		Code:        "meter_dimensions_" + node.Generate().String(),
+		Name:        "compute",
+		Aggregation: meterdomain.AggregationSum,
+		Unit:        "hour",
+		Active:      true,
+	}
+	require.NoError(t, db.Create(&meter).Error)
+
+	events := []struct {
+		value      float64
+		dimensions datatypes.JSONMap
+	}{
+		{3, datatypes.JSONMap{"region": "us"}},
+		{5, datatypes.JSONMap{"region": "eu", "instance_type": "large"}},
+		{7, datatypes.JSONMap{"region": "us"}},
+		{11, nil},
+	}
+	for i, event := range events {
+		require.NoError(t, db.Create(&usagedomain.UsageEvent{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			MeterID:        meter.ID,
+			Value:          event.value,
+			RecordedAt:     time.Date(2026, 1, i+2, 0, 0, 0, 0, time.UTC),
+			Status:         usagedomain.UsageStatusEnriched,
+			Dimensions:     event.dimensions,
+		}).Error)
+	}
+
+	us := &ratingdomain.DimensionFilter{Key: "region", Value: "us"}
+	eu := &ratingdomain.DimensionFilter{Key: "region", Value: "eu"}
+
+	qty, err := repo.AggregateUsage(context.Background(), orgID, subID, meter.ID, windowStart, windowEnd, us)
+	require.NoError(t, err)
+	assert.Equal(t, float64(10), qty)
+
+	qty, err = repo.AggregateUsage(context.Background(), orgID, subID, meter.ID, windowStart, windowEnd, eu)
+	require.NoError(t, err)
+	assert.Equal(t, float64(5), qty)
+
+	qty, err = repo.AggregateUsage(context.Background(), orgID, subID, meter.ID, windowStart, windowEnd, nil)
+	require.NoError(t, err)
+	assert.Equal(t, float64(26), qty)
+
+	cycleID := node.Generate()
+	priceID := node.Generate()
+	usChecksum := buildRatingChecksum(cycleID, subID, priceID, &meter.ID, us, "", windowStart, windowEnd)
+	euChecksum := buildRatingChecksum(cycleID, subID, priceID, &meter.ID, eu, "", windowStart, windowEnd)
+	allChecksum := buildRatingChecksum(cycleID, subID, priceID, &meter.ID, nil, "", windowStart, windowEnd)
+	assert.NotEqual(t, usChecksum, euChecksum)
+	assert.NotEqual(t, usChecksum, allChecksum)
+}
+
+// TestAggregateUsage_AdjustmentsNetOut nets negative adjustments against the
+// usage they correct without rating a window below zero.
+func TestAggregateUsage_AdjustmentsNetOut(t *testing.T) {
+	db, _, node := setupProrationTest(t)
+	repo := repository.NewRepository(db)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	meter := meterdomain.Meter{
+		ID:          node.Generate(),
+		OrgID:       orgID,
+		Code:        "meter_adjustments_" + node.Generate().String(),
+		Name:        "api_calls",
+		Aggregation: meterdomain.AggregationSum,
+		Unit:        "call",
+		Active:      true,
+	}
+	require.NoError(t, db.Create(&meter).Error)
+
+	events := []struct {
+		value     float64
+		usageType string
+		day       int
+	}{
+		{10, usagedomain.UsageTypeUsage, 3},
+		{-4, usagedomain.UsageTypeAdjustment, 5},
+		{-3, usagedomain.UsageTypeAdjustment, 10},
+	}
+	for _, event := range events {
+		require.NoError(t, db.Create(&usagedomain.UsageEvent{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			MeterID:        meter.ID,
+			Value:          event.value,
+			Type:           event.usageType,
+			RecordedAt:     time.Date(2026, 1, event.day, 0, 0, 0, 0, time.UTC),
+			Status:         usagedomain.UsageStatusEnriched,
+		}).Error)
+	}
+
+	qty, err := repo.AggregateUsage(context.Background(), orgID, subID, meter.ID,
+		time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), nil)
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), qty)
+
+	// A window holding only a correction rates nothing rather than a credit.
+	qty, err = repo.AggregateUsage(context.Background(), orgID, subID, meter.ID,
+		time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC), nil)
+	require.NoError(t, err)
+	assert.Zero(t, qty)
+}
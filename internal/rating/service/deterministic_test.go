@@ -8,6 +8,8 @@ import (
 	"github.com/bwmarrin/snowflake"
 	"github.com/glebarez/sqlite"
 	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
 	pricerepository "github.com/railzwaylabs/railzway/internal/price/repository"
 	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
@@ -28,18 +30,20 @@ func TestRating_Deterministic_Idempotency(t *testing.T) {
 	// Migrate
 	err = db.AutoMigrate(
 		&ratingdomain.RatingResult{},
+		&ratingdomain.ThresholdUsage{},
 		// &usagedomain.UsageEvent{}, // The service queries usage_events table
 		&subscriptiondomain.SubscriptionItem{},
 		&subscriptiondomain.Subscription{},
 		&subscriptiondomain.SubscriptionEntitlement{},
 		&billingcycledomain.BillingCycle{},
 		&pricedomain.Price{},
+		&organizationdomain.OrganizationBillingPreferences{},
 		// PriceAmount table not strictly needed if we stub repo, but good for consistency
 	)
 	assert.NoError(t, err)
 
 	// Usage table needs manual creation or migrate
-	err = db.AutoMigrate(&usagedomain.UsageEvent{})
+	err = db.AutoMigrate(&usagedomain.UsageEvent{}, &usagedomain.UsageRollup{}, &meterdomain.Meter{})
 	assert.NoError(t, err)
 
 	node, _ := snowflake.NewNode(1)
@@ -83,11 +87,12 @@ func TestRating_Deterministic_Idempotency(t *testing.T) {
 
 	// Price (Allowed Snapshot)
 	db.Create(&pricedomain.Price{
-		ID:        priceID,
-		OrgID:     orgID,
-		ProductID: productID,
-		Code:      "price_123",
-		Active:    true,
+		ID:           priceID,
+		OrgID:        orgID,
+		ProductID:    productID,
+		Code:         "price_123",
+		Active:       true,
+		PricingModel: pricedomain.PerUnit,
 	})
 
 	// Price Amount (via stub)
@@ -166,8 +171,7 @@ func TestRating_Deterministic_Idempotency(t *testing.T) {
 	firstRunID := results[0].ID
 
 	// 4. Run Rating (Second Time - Idempotency)
-	// Should produce SAME content. ID CAN Change due to DELETE-INSERT.
-	// But check Deterministic outputs.
+	// An unchanged cycle keeps its rows as they are.
 	err = svc.RunRating(context.Background(), cycleID.String())
 	assert.NoError(t, err)
 
@@ -176,9 +180,36 @@ func TestRating_Deterministic_Idempotency(t *testing.T) {
 	assert.Len(t, results2, 1)
 	assert.Equal(t, float64(15.0), results2[0].Quantity)
 	assert.Equal(t, featureCode, results2[0].FeatureCode)
+	assert.Equal(t, firstRunID, results2[0].ID, "unchanged result should keep its row")
 
-	// Assert ID changed (proving we did a full replacements)
-	assert.NotEqual(t, firstRunID, results2[0].ID, "ID should change due to Delete-Insert")
+	// 5. Late usage updates the line in place and stale lines are removed.
+	db.Create(&usagedomain.UsageEvent{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		MeterID:        meterID,
+		SubscriptionID: subID,
+		Value:          5.0,
+		RecordedAt:     start.Add(3 * time.Hour),
+		Status:         usagedomain.UsageStatusEnriched,
+	})
+	stale := ratingdomain.RatingResult{
+		ID: node.Generate(), OrgID: orgID, SubscriptionID: subID, BillingCycleID: cycleID,
+		BillingPhase: string(billingcycledomain.BillingPhaseArrears), PriceID: node.Generate(),
+		Quantity: 1, UnitPrice: 100, Amount: 100, Currency: currency,
+		PeriodStart: start, PeriodEnd: end, Source: "usage_events", Checksum: "removed-item",
+	}
+	assert.NoError(t, db.Create(&stale).Error)
+
+	err = svc.RunRating(context.Background(), cycleID.String())
+	assert.NoError(t, err)
+
+	var results3 []ratingdomain.RatingResult
+	db.Where("billing_cycle_id = ?", cycleID).Find(&results3)
+	if assert.Len(t, results3, 1) {
+		assert.Equal(t, firstRunID, results3[0].ID)
+		assert.Equal(t, float64(20.0), results3[0].Quantity)
+		assert.Equal(t, int64(2000), results3[0].Amount)
+	}
 }
 
 // Stub
@@ -196,7 +227,10 @@ func (s *priceAmountStub) FindOne(ctx context.Context, db *gorm.DB, amount *pric
 func (s *priceAmountStub) FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*priceamountdomain.PriceAmount, error) {
 	return nil, nil
 }
-func (s *priceAmountStub) List(ctx context.Context, db *gorm.DB, f priceamountdomain.PriceAmount, opts ...option.QueryOption) ([]priceamountdomain.PriceAmount, error) {
+func (s *priceAmountStub) FindByIdempotencyKey(ctx context.Context, db *gorm.DB, orgID snowflake.ID, key string) (*priceamountdomain.PriceAmount, error) {
+	return nil, nil
+}
+func (s *priceAmountStub) List(ctx context.Context, db *gorm.DB, f priceamountdomain.PriceAmount, opts ...option.QueryOption) ([]*priceamountdomain.PriceAmount, error) {
 	return nil, nil
 }
 func (s *priceAmountStub) Update(ctx context.Context, db *gorm.DB, amount *priceamountdomain.PriceAmount) (*priceamountdomain.PriceAmount, error) {
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	coupondomain "github.com/railzwaylabs/railzway/internal/coupon/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"github.com/railzwaylabs/railzway/internal/rating/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// applyDiscounts writes a negative rating row per coupon discount active in
+// the cycle. Discounts apply in order to what the phase bills after earlier
+// discounts, so the phase never goes below zero. A fixed amount is spread
+// across the phases of a cycle: the arrears phase only takes what the advance
+// phase left.
+func (s *Service) applyDiscounts(
+	ctx context.Context,
+	tx *gorm.DB,
+	w *ratingWriter,
+	cycle *ratingdomain.BillingCycleRow,
+	phase billingcycledomain.BillingPhase,
+	currency string,
+	rounding organizationdomain.RoundingMode,
+	now time.Time,
+) error {
+	if s.discounts == nil {
+		return nil
+	}
+	discounts, err := s.discounts.ResolveCycleDiscounts(ctx, tx, cycle.OrgID, cycle.SubscriptionID, cycle.PeriodStart, cycle.PeriodEnd)
+	if err != nil {
+		return err
+	}
+	if len(discounts) == 0 {
+		return nil
+	}
+
+	repoTx := repository.NewRepository(tx)
+	remaining, err := repoTx.SumPhaseCharges(ctx, cycle.ID, phase, currency)
+	if err != nil {
+		return err
+	}
+
+	for _, discount := range discounts {
+		if remaining <= 0 {
+			return nil
+		}
+
+		var amount int64
+		switch discount.DiscountType {
+		case coupondomain.DiscountTypePercent:
+			if discount.PercentOff == nil {
+				continue
+			}
+			amount = roundRatingAmount(float64(remaining)*(*discount.PercentOff)/100, rounding)
+		case coupondomain.DiscountTypeFixedAmount:
+			if discount.AmountOffCents == nil {
+				continue
+			}
+			if discount.Currency == nil || !strings.EqualFold(*discount.Currency, currency) {
+				s.log.Warn("skipping discount in another currency",
+					zap.String("billing_cycle_id", cycle.ID.String()),
+					zap.String("coupon_code", discount.Code),
+					zap.String("currency", currency),
+				)
+				continue
+			}
+			amount = *discount.AmountOffCents
+			if phase == billingcycledomain.BillingPhaseArrears {
+				applied, err := repoTx.SumAmountByChecksum(ctx, buildDiscountChecksum(cycle.ID, discount.DiscountID, billingcycledomain.BillingPhaseAdvance))
+				if err != nil {
+					return err
+				}
+				amount += applied
+			}
+		default:
+			continue
+		}
+
+		if amount > remaining {
+			amount = remaining
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		if err := w.write(ctx, ratingdomain.RatingResult{
+			ID:             s.genID.Generate(),
+			OrgID:          cycle.OrgID,
+			SubscriptionID: cycle.SubscriptionID,
+			BillingCycleID: cycle.ID,
+			BillingPhase:   string(phase),
+			FeatureCode:    discount.Code,
+			Source:         ratingdomain.RatingSourceDiscount,
+			Quantity:       1,
+			UnitPrice:      -amount,
+			Amount:         -amount,
+			Currency:       currency,
+			PeriodStart:    cycle.PeriodStart,
+			PeriodEnd:      cycle.PeriodEnd,
+			Checksum:       buildDiscountChecksum(cycle.ID, discount.DiscountID, phase),
+			CreatedAt:      now,
+		}); err != nil {
+			return err
+		}
+		remaining -= amount
+	}
+	return nil
+}
+
+func buildDiscountChecksum(cycleID, discountID snowflake.ID, phase billingcycledomain.BillingPhase) string {
+	payload := fmt.Sprintf("discount|%s|%s|%s", cycleID.String(), discountID.String(), phase)
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
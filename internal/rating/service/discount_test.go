@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	coupondomain "github.com/railzwaylabs/railzway/internal/coupon/domain"
+	couponrepository "github.com/railzwaylabs/railzway/internal/coupon/repository"
+	couponservice "github.com/railzwaylabs/railzway/internal/coupon/service"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiscounts_AppliedInOrderAfterCharges verifies that active discounts
+// become negative rating rows, that each applies to what earlier ones left,
+// and that a coupon past its duration is skipped.
+func TestDiscounts_AppliedInOrderAfterCharges(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+	require.NoError(t, db.AutoMigrate(&coupondomain.Coupon{}, &coupondomain.SubscriptionDiscount{}))
+	svc.(*Service).discounts = couponservice.NewResolver(couponservice.ResolverParams{Repo: couponrepository.Provide()})
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	productID := node.Generate()
+	priceID := node.Generate()
+
+	cycleStart := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	previousStart := cycleStart.AddDate(0, -1, 0)
+
+	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+	seedProrationData(t, db, node, priceAmountStub, orgID, subID, cycleID, productID, priceID, cycleStart, cycleEnd, previousStart, nil, 10000)
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PeriodStart:    previousStart,
+		PeriodEnd:      cycleStart,
+		Status:         billingcycledomain.BillingCycleStatusClosed,
+	}).Error)
+
+	twenty := 20.0
+	fifteenHundred := int64(1500)
+	twoCycles := int32(2)
+	usd := "USD"
+	coupons := []coupondomain.Coupon{
+		{Code: "PCT20", DiscountType: coupondomain.DiscountTypePercent, PercentOff: &twenty, Duration: coupondomain.DurationForever},
+		{Code: "OFF15", DiscountType: coupondomain.DiscountTypeFixedAmount, AmountOffCents: &fifteenHundred, Currency: &usd, Duration: coupondomain.DurationRepeating, DurationInCycles: &twoCycles},
+		{Code: "FIRST", DiscountType: coupondomain.DiscountTypeFixedAmount, AmountOffCents: &fifteenHundred, Currency: &usd, Duration: coupondomain.DurationOnce},
+	}
+	starts := []time.Time{cycleStart, previousStart, previousStart}
+	for i := range coupons {
+		coupons[i].ID = node.Generate()
+		coupons[i].OrgID = orgID
+		coupons[i].Name = coupons[i].Code
+		coupons[i].Active = true
+		require.NoError(t, db.Create(&coupons[i]).Error)
+		require.NoError(t, db.Create(&coupondomain.SubscriptionDiscount{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			CouponID:       coupons[i].ID,
+			StartAt:        starts[i],
+		}).Error)
+	}
+
+	ctx := context.Background()
+	require.NoError(t, svc.RunRating(ctx, cycleID.String()))
+	// Re-rating replaces the discount rows instead of adding more.
+	require.NoError(t, svc.RunRating(ctx, cycleID.String()))
+
+	var discounts []ratingdomain.RatingResult
+	require.NoError(t, db.Where("billing_cycle_id = ? AND source = ?", cycleID, ratingdomain.RatingSourceDiscount).
+		Order("amount ASC").Find(&discounts).Error)
+	require.Len(t, discounts, 2)
+	// OFF15 started first and takes 1500; PCT20 takes 20% of the 8500 left.
+	assert.Equal(t, int64(-1700), discounts[0].Amount)
+	assert.Equal(t, "PCT20", discounts[0].FeatureCode)
+	assert.Equal(t, int64(-1500), discounts[1].Amount)
+	assert.Equal(t, "OFF15", discounts[1].FeatureCode)
+	assert.Equal(t, string(billingcycledomain.BillingPhaseArrears), discounts[0].BillingPhase)
+
+	var total int64
+	require.NoError(t, db.Raw(`SELECT COALESCE(SUM(amount), 0) FROM rating_results WHERE billing_cycle_id = ?`, cycleID).Scan(&total).Error)
+	assert.Equal(t, int64(6800), total)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"github.com/railzwaylabs/railzway/internal/rating/repository"
+	"gorm.io/gorm"
+)
+
+// errDryRunRollback aborts the dry run transaction once the results are read.
+var errDryRunRollback = errors.New("rating_dry_run_rollback")
+
+// DryRunRating runs the arrears rating of a billing cycle inside a
+// transaction that is always rolled back, so the preview goes through exactly
+// the code RunRating does while leaving the stored results untouched. Unlike
+// RunRating it accepts a cycle in any status.
+func (s *Service) DryRunRating(ctx context.Context, billingCycleID string) ([]ratingdomain.RatingResult, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return nil, ratingdomain.ErrInvalidOrganization
+	}
+
+	cycle, err := s.loadBillingCycle(ctx, billingCycleID)
+	if err != nil {
+		return nil, err
+	}
+	if cycle.OrgID != orgID {
+		return nil, ratingdomain.ErrBillingCycleNotFound
+	}
+
+	subscription, items, err := s.loadSubscriptionItems(ctx, cycle)
+	if err != nil {
+		return nil, err
+	}
+
+	phase := billingcycledomain.BillingPhaseArrears
+	var results []ratingdomain.RatingResult
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.rateCycle(ctx, tx, cycle, subscription, items, phase); err != nil {
+			return err
+		}
+		results, err = repository.NewRepository(tx).ListRatingResults(ctx, cycle.ID, phase)
+		if err != nil {
+			return err
+		}
+		return errDryRunRollback
+	})
+	if !errors.Is(err, errDryRunRollback) {
+		return nil, err
+	}
+	if results == nil {
+		results = []ratingdomain.RatingResult{}
+	}
+	return results, nil
+}
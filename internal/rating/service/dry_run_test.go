@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDryRunRating_PreviewsWithoutWriting verifies that a preview of an open
+// cycle returns what the closing run later stores, and that it neither
+// writes its own rows nor drops the ones already stored.
+func TestDryRunRating_PreviewsWithoutWriting(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	productID := node.Generate()
+	priceID := node.Generate()
+
+	cycleStart := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	subStart := time.Date(2026, 9, 11, 0, 0, 0, 0, time.UTC)
+
+	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+	seedProrationData(t, db, node, priceAmountStub, orgID, subID, cycleID, productID, priceID, cycleStart, cycleEnd, subStart, nil, 3000)
+	setCycleStatus := func(status billingcycledomain.BillingCycleStatus) {
+		require.NoError(t, db.Model(&billingcycledomain.BillingCycle{}).Where("id = ?", cycleID).Update("status", status).Error)
+	}
+	setCycleStatus(billingcycledomain.BillingCycleStatusOpen)
+
+	stale := ratingdomain.RatingResult{
+		ID: node.Generate(), OrgID: orgID, SubscriptionID: subID, BillingCycleID: cycleID,
+		BillingPhase: string(billingcycledomain.BillingPhaseArrears), PriceID: priceID,
+		Quantity: 1, UnitPrice: 1, Amount: 1, Currency: "USD",
+		PeriodStart: cycleStart, PeriodEnd: cycleEnd, Source: "usage_events", Checksum: "stale-" + cycleID.String(),
+	}
+	require.NoError(t, db.Create(&stale).Error)
+
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	preview, err := svc.DryRunRating(ctx, cycleID.String())
+	require.NoError(t, err)
+	require.Len(t, preview, 1)
+	assert.Equal(t, priceID, preview[0].PriceID)
+	assert.NotEqual(t, stale.Checksum, preview[0].Checksum)
+
+	var stored []ratingdomain.RatingResult
+	require.NoError(t, db.Where("billing_cycle_id = ?", cycleID).Find(&stored).Error)
+	require.Len(t, stored, 1)
+	assert.Equal(t, stale.ID, stored[0].ID)
+
+	_, err = svc.DryRunRating(orgcontext.WithOrgID(context.Background(), int64(node.Generate())), cycleID.String())
+	assert.ErrorIs(t, err, ratingdomain.ErrBillingCycleNotFound)
+
+	setCycleStatus(billingcycledomain.BillingCycleStatusClosing)
+	require.NoError(t, svc.RunRating(context.Background(), cycleID.String()))
+	stored = nil
+	require.NoError(t, db.Where("billing_cycle_id = ?", cycleID).Find(&stored).Error)
+	require.Len(t, stored, 1)
+	assert.Equal(t, preview[0].Amount, stored[0].Amount)
+	assert.Equal(t, preview[0].Quantity, stored[0].Quantity)
+	assert.Equal(t, preview[0].Checksum, stored[0].Checksum)
+}
@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
 )
 
 func resolveEffectiveWindow(
@@ -43,19 +46,20 @@ func resolveEffectiveWindow(
 	return start, end, true
 }
 
-func calculateProrationFactor(start, end time.Time, cycleDurationSeconds float64) float64 {
-	if cycleDurationSeconds <= 0 {
-		return 0
+// pausedUsageEnd stops counting usage at the moment a paused subscription
+// was paused. It reports false when the subscription was paused for the
+// whole window. Subscriptions that were resumed are rated normally.
+func pausedUsageEnd(subscription *subscriptiondomain.Subscription, start, end time.Time) (time.Time, bool) {
+	if subscription.Status != subscriptiondomain.SubscriptionStatusPaused || subscription.PausedAt == nil {
+		return end, true
 	}
-	activeSeconds := end.Sub(start).Seconds()
-	factor := activeSeconds / cycleDurationSeconds
-	if factor > 1.0 {
-		return 1.0
+	if subscription.PausedAt.Before(end) {
+		end = *subscription.PausedAt
 	}
-	if factor < 0.0 {
-		return 0.0
+	if !end.After(start) {
+		return time.Time{}, false
 	}
-	return factor
+	return end, true
 }
 
 func buildRatingChecksum(
@@ -63,6 +67,7 @@ func buildRatingChecksum(
 	subscriptionID snowflake.ID,
 	priceID snowflake.ID,
 	meterID *snowflake.ID,
+	dimension *ratingdomain.DimensionFilter,
 	featureCode string,
 	periodStart, periodEnd time.Time,
 ) string {
@@ -70,6 +75,11 @@ func buildRatingChecksum(
 	if meterID != nil && *meterID != 0 {
 		meterPart = meterID.String()
 	}
+	// Items bound to a dimension rate a slice of the meter, so the same
+	// price and meter can appear once per dimension value.
+	if dimension != nil {
+		meterPart += "|" + dimension.Key + "=" + dimension.Value
+	}
 
 	payload := fmt.Sprintf(
 		"%s|%s|%s|%s|%s|%s|%s",
@@ -85,6 +95,17 @@ func buildRatingChecksum(
 	return hex.EncodeToString(sum[:])
 }
 
-func roundRatingAmount(raw float64) int64 {
-	return int64(math.Floor(raw + 0.5))
+// roundRatingAmount rounds a rated amount to whole cents with the
+// organization's rounding mode. Unknown modes round half up.
+func roundRatingAmount(raw float64, mode organizationdomain.RoundingMode) int64 {
+	switch mode {
+	case organizationdomain.RoundingModeHalfEven:
+		return int64(math.RoundToEven(raw))
+	case organizationdomain.RoundingModeFloor:
+		return int64(math.Floor(raw))
+	case organizationdomain.RoundingModeCeil:
+		return int64(math.Ceil(raw))
+	default:
+		return int64(math.Floor(raw + 0.5))
+	}
 }
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIncludedAllowance_RatedAtZeroBeforePrice verifies that the included
+// quantity of a metered item is rated at zero and only the remainder is
+// priced, and that an allowance on a never-resetting meter carries what
+// earlier cycles used.
+func TestIncludedAllowance_RatedAtZeroBeforePrice(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+
+	subStart := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	cycleStart := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	rate := func(resetInterval string, earlierUsage, cycleUsage float64) (included, priced ratingdomain.RatingResult) {
+		orgID := node.Generate()
+		subID := node.Generate()
+		cycleID := node.Generate()
+		productID := node.Generate()
+		priceID := node.Generate()
+		meterID := node.Generate()
+
+		require.NoError(t, db.Create(&meterdomain.Meter{
+			ID:            meterID,
+			OrgID:         orgID,
+			Code:          "api_calls_" + meterID.String(),
+			Name:          "API calls",
+			Aggregation:   "SUM",
+			Unit:          "call",
+			ResetInterval: resetInterval,
+			Active:        true,
+		}).Error)
+		require.NoError(t, db.Create(&pricedomain.Price{
+			ID:           priceID,
+			OrgID:        orgID,
+			ProductID:    productID,
+			Code:         "api_" + priceID.String(),
+			PricingModel: pricedomain.PerUnit,
+			Active:       true,
+		}).Error)
+		priceAmountStub.Amounts[priceID.String()] = priceamountdomain.PriceAmount{
+			PriceID:         priceID,
+			UnitAmountCents: 100,
+			Currency:        "USD",
+		}
+
+		currency := "USD"
+		require.NoError(t, db.Create(&subscriptiondomain.Subscription{
+			ID:              subID,
+			OrgID:           orgID,
+			CustomerID:      node.Generate(),
+			Status:          subscriptiondomain.SubscriptionStatusActive,
+			StartAt:         subStart,
+			DefaultCurrency: &currency,
+		}).Error)
+		allowance := 1000.0
+		require.NoError(t, db.Create(&subscriptiondomain.SubscriptionItem{
+			ID:               node.Generate(),
+			OrgID:            orgID,
+			SubscriptionID:   subID,
+			PriceID:          priceID,
+			MeterID:          &meterID,
+			Quantity:         1,
+			BillingMode:      "METERED",
+			IncludedQuantity: &allowance,
+		}).Error)
+		require.NoError(t, db.Create(&subscriptiondomain.SubscriptionEntitlement{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			ProductID:      productID,
+			FeatureCode:    "api_calls",
+			MeterID:        &meterID,
+			EffectiveFrom:  subStart,
+		}).Error)
+		require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+			ID:             cycleID,
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			PeriodStart:    cycleStart,
+			PeriodEnd:      cycleEnd,
+			Status:         billingcycledomain.BillingCycleStatusClosing,
+		}).Error)
+
+		record := func(value float64, at time.Time) {
+			require.NoError(t, db.Create(&usagedomain.UsageEvent{
+				ID:             node.Generate(),
+				OrgID:          orgID,
+				MeterID:        meterID,
+				SubscriptionID: subID,
+				Value:          value,
+				RecordedAt:     at,
+				Status:         usagedomain.UsageStatusEnriched,
+			}).Error)
+		}
+		record(earlierUsage, subStart.Add(24*time.Hour))
+		record(cycleUsage, cycleStart.Add(24*time.Hour))
+
+		require.NoError(t, svc.RunRating(context.Background(), cycleID.String()))
+
+		var results []ratingdomain.RatingResult
+		require.NoError(t, db.Where("billing_cycle_id = ?", cycleID).Order("amount ASC").Find(&results).Error)
+		require.Len(t, results, 2)
+		return results[0], results[1]
+	}
+
+	included, priced := rate(meterdomain.ResetIntervalBillingCycle, 800, 1500)
+	assert.Equal(t, ratingdomain.RatingSourceIncluded, included.Source)
+	assert.Equal(t, 1000.0, included.Quantity)
+	assert.Zero(t, included.Amount)
+	assert.Equal(t, 500.0, priced.Quantity)
+	assert.Equal(t, int64(50000), priced.Amount)
+
+	// 800 of the allowance went to the earlier cycle, leaving 200.
+	included, priced = rate(meterdomain.ResetIntervalNever, 800, 300)
+	assert.Equal(t, ratingdomain.RatingSourceIncluded, included.Source)
+	assert.Equal(t, 200.0, included.Quantity)
+	assert.Equal(t, 100.0, priced.Quantity)
+	assert.Equal(t, int64(10000), priced.Amount)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"github.com/railzwaylabs/railzway/internal/rating/repository"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"gorm.io/gorm"
+)
+
+// applyMinimumCommitment tops a closing cycle up to the subscription's minimum
+// commitment. The commitment is prorated to the part of the cycle the
+// subscription was active and compared with every charge of the cycle in the
+// subscription currency, advance and proration rows included.
+func (s *Service) applyMinimumCommitment(
+	ctx context.Context,
+	tx *gorm.DB,
+	w *ratingWriter,
+	cycle *ratingdomain.BillingCycleRow,
+	subscription *subscriptiondomain.Subscription,
+	currency string,
+	cycleDuration float64,
+	rounding organizationdomain.RoundingMode,
+	now time.Time,
+) error {
+	if subscription.MinimumCommitmentCents == nil || *subscription.MinimumCommitmentCents <= 0 {
+		return nil
+	}
+
+	start, end, active := resolveEffectiveWindow(
+		cycle.PeriodStart, cycle.PeriodEnd,
+		subscription.StartAt, subscription.EndedAt, subscription.CanceledAt,
+		time.Time{}, nil,
+	)
+	if !active {
+		return nil
+	}
+	prorationFactor := billingcycledomain.ProrationFactor(start, end, cycleDuration)
+	commitment := roundRatingAmount(float64(*subscription.MinimumCommitmentCents)*prorationFactor, rounding)
+
+	repoTx := repository.NewRepository(tx)
+	total, err := repoTx.SumRatingAmounts(ctx, cycle.ID, currency)
+	if err != nil {
+		return err
+	}
+	shortfall := commitment - total
+	if shortfall <= 0 {
+		return nil
+	}
+
+	return w.write(ctx, ratingdomain.RatingResult{
+		ID:             s.genID.Generate(),
+		OrgID:          cycle.OrgID,
+		SubscriptionID: cycle.SubscriptionID,
+		BillingCycleID: cycle.ID,
+		BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
+		Source:         ratingdomain.RatingSourceMinimumCommitment,
+		Quantity:       1,
+		UnitPrice:      shortfall,
+		Amount:         shortfall,
+		Currency:       currency,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Checksum:       buildMinimumCommitmentChecksum(cycle.ID, start, end),
+		CreatedAt:      now,
+	})
+}
+
+func buildMinimumCommitmentChecksum(cycleID snowflake.ID, start, end time.Time) string {
+	payload := fmt.Sprintf(
+		"minimum_commitment|%s|%s|%s",
+		cycleID.String(),
+		start.UTC().Format(time.RFC3339Nano),
+		end.UTC().Format(time.RFC3339Nano),
+	)
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
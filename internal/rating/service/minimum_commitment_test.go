@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMinimumCommitment_ProratedTrueUp verifies that a subscription starting
+// halfway through a cycle is topped up to half of its commitment.
+func TestMinimumCommitment_ProratedTrueUp(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	productID := node.Generate()
+	priceID := node.Generate()
+	meterID := node.Generate()
+
+	cycleStart := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	subStart := time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID:             cycleID,
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PeriodStart:    cycleStart,
+		PeriodEnd:      cycleEnd,
+		Status:         billingcycledomain.BillingCycleStatusClosing,
+	}).Error)
+
+	currency := "EUR"
+	commitment := int64(10000)
+	require.NoError(t, db.Create(&subscriptiondomain.Subscription{
+		ID:                     subID,
+		OrgID:                  orgID,
+		CustomerID:             node.Generate(),
+		Status:                 subscriptiondomain.SubscriptionStatusActive,
+		StartAt:                subStart,
+		DefaultCurrency:        &currency,
+		MinimumCommitmentCents: &commitment,
+	}).Error)
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionItem{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PriceID:        priceID,
+		MeterID:        &meterID,
+		Quantity:       1,
+		BillingMode:    "METERED",
+	}).Error)
+	require.NoError(t, db.Create(&pricedomain.Price{
+		ID:           priceID,
+		OrgID:        orgID,
+		ProductID:    productID,
+		Code:         "api_calls_eur",
+		PricingModel: pricedomain.PerUnit,
+		BillingMode:  pricedomain.Metered,
+		Active:       true,
+	}).Error)
+
+	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+	priceAmountStub.Amounts[priceID.String()] = priceamountdomain.PriceAmount{
+		PriceID:         priceID,
+		MeterID:         &meterID,
+		UnitAmountCents: 10,
+		Currency:        currency,
+	}
+
+	require.NoError(t, db.Create(&usagedomain.UsageEvent{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		MeterID:        meterID,
+		SubscriptionID: subID,
+		Value:          42,
+		RecordedAt:     subStart.Add(48 * time.Hour),
+		Status:         usagedomain.UsageStatusEnriched,
+	}).Error)
+
+	ctx := context.Background()
+	require.NoError(t, svc.RunRating(ctx, cycleID.String()))
+	// Re-rating replaces the true-up instead of adding another one.
+	require.NoError(t, svc.RunRating(ctx, cycleID.String()))
+
+	var trueUps []ratingdomain.RatingResult
+	require.NoError(t, db.Where("billing_cycle_id = ? AND source = ?", cycleID, ratingdomain.RatingSourceMinimumCommitment).Find(&trueUps).Error)
+	require.Len(t, trueUps, 1)
+	// Half the cycle commits 5000; usage covered 420 of it.
+	assert.Equal(t, int64(4580), trueUps[0].Amount)
+	assert.Equal(t, currency, trueUps[0].Currency)
+	assert.Equal(t, string(billingcycledomain.BillingPhaseArrears), trueUps[0].BillingPhase)
+	assert.True(t, trueUps[0].PeriodStart.Equal(subStart))
+	assert.True(t, trueUps[0].PeriodEnd.Equal(cycleEnd))
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/bwmarrin/snowflake"
 	"github.com/glebarez/sqlite"
 	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
 	pricerepository "github.com/railzwaylabs/railzway/internal/price/repository"
 	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
@@ -87,6 +89,39 @@ func TestProration_MidCycleSubscriptionStart(t *testing.T) {
 	assert.Equal(t, firstChecksum, results2[0].Checksum)
 }
 
+// TestProration_LicensedQuantityMultipliesUnitAmount verifies that seat counts
+// beyond the old int8 range multiply the prorated flat amount.
+func TestProration_LicensedQuantityMultipliesUnitAmount(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	productID := node.Generate()
+	priceID := node.Generate()
+
+	cycleStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	subStart := time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)
+
+	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+	seedProrationData(t, db, node, priceAmountStub, orgID, subID, cycleID, productID, priceID, cycleStart, cycleEnd, subStart, nil, 1000)
+	require.NoError(t, db.Model(&subscriptiondomain.SubscriptionItem{}).
+		Where("subscription_id = ?", subID).
+		Update("quantity", 250).Error)
+
+	require.NoError(t, svc.RunRating(context.Background(), cycleID.String()))
+
+	var results []ratingdomain.RatingResult
+	db.Where("billing_cycle_id = ?", cycleID).Find(&results)
+	require.Len(t, results, 1)
+
+	expectedQuantity := 250 * 16.0 / 31.0
+	assert.InDelta(t, expectedQuantity, results[0].Quantity, 0.0001)
+	assert.Equal(t, int64(1000), results[0].UnitPrice)
+	assert.InDelta(t, int64(1000*expectedQuantity), results[0].Amount, 1)
+}
+
 // TestProration_MidCycleSubscriptionEnd validates PRORATION RULE 1:
 // Subscription ends mid-cycle
 func TestProration_MidCycleSubscriptionEnd(t *testing.T) {
@@ -183,18 +218,20 @@ func TestProration_PlanChangeMidCycle(t *testing.T) {
 
 	// Prices
 	db.Create(&pricedomain.Price{
-		ID:        priceA,
-		OrgID:     orgID,
-		ProductID: productA,
-		Code:      "price_a",
-		Active:    true,
+		ID:           priceA,
+		OrgID:        orgID,
+		ProductID:    productA,
+		Code:         "price_a",
+		Active:       true,
+		PricingModel: pricedomain.Flat,
 	})
 	db.Create(&pricedomain.Price{
-		ID:        priceB,
-		OrgID:     orgID,
-		ProductID: productB,
-		Code:      "price_b",
-		Active:    true,
+		ID:           priceB,
+		OrgID:        orgID,
+		ProductID:    productB,
+		Code:         "price_b",
+		Active:       true,
+		PricingModel: pricedomain.Flat,
 	})
 
 	// Price amounts
@@ -312,10 +349,11 @@ func TestProration_MeteredUsageWithWindow(t *testing.T) {
 	})
 
 	db.Create(&pricedomain.Price{
-		ID:        priceID,
-		OrgID:     orgID,
-		ProductID: productID,
-		Active:    true,
+		ID:           priceID,
+		OrgID:        orgID,
+		ProductID:    productID,
+		Active:       true,
+		PricingModel: pricedomain.PerUnit,
 	})
 
 	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
@@ -369,6 +407,100 @@ func TestProration_MeteredUsageWithWindow(t *testing.T) {
 	assert.Equal(t, cycleEnd, result.PeriodEnd)
 }
 
+// TestProration_MeteredUsageStopsAtPause validates that a paused
+// subscription is only rated for usage recorded before it was paused, and
+// that metered items paused for the whole cycle are skipped.
+func TestProration_MeteredUsageStopsAtPause(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+
+	cycleStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := func(pausedAt time.Time) snowflake.ID {
+		orgID := node.Generate()
+		subID := node.Generate()
+		cycleID := node.Generate()
+		productID := node.Generate()
+		priceID := node.Generate()
+		meterID := node.Generate()
+
+		db.Create(&billingcycledomain.BillingCycle{
+			ID:             cycleID,
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			PeriodStart:    cycleStart,
+			PeriodEnd:      cycleEnd,
+			Status:         billingcycledomain.BillingCycleStatusClosing,
+		})
+
+		currency := "USD"
+		db.Create(&subscriptiondomain.Subscription{
+			ID:              subID,
+			OrgID:           orgID,
+			CustomerID:      node.Generate(),
+			Status:          subscriptiondomain.SubscriptionStatusPaused,
+			StartAt:         time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+			PausedAt:        &pausedAt,
+			DefaultCurrency: &currency,
+		})
+		db.Create(&subscriptiondomain.SubscriptionItem{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			PriceID:        priceID,
+			MeterID:        &meterID,
+			BillingMode:    "METERED",
+		})
+		db.Create(&pricedomain.Price{
+			ID:           priceID,
+			OrgID:        orgID,
+			ProductID:    productID,
+			Active:       true,
+			PricingModel: pricedomain.PerUnit,
+		})
+
+		priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+		priceAmountStub.Amounts[priceID.String()] = priceamountdomain.PriceAmount{
+			PriceID:         priceID,
+			UnitAmountCents: 100,
+			Currency:        "USD",
+		}
+
+		for day, value := range map[int]float64{5: 3.0, 25: 7.0} {
+			db.Create(&usagedomain.UsageEvent{
+				ID:             node.Generate(),
+				OrgID:          orgID,
+				MeterID:        meterID,
+				SubscriptionID: subID,
+				Value:          value,
+				RecordedAt:     time.Date(2026, 1, day, 0, 0, 0, 0, time.UTC),
+				Status:         usagedomain.UsageStatusEnriched,
+			})
+		}
+		return cycleID
+	}
+
+	// Paused mid-cycle: only usage up to the pause is rated.
+	pausedAt := time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)
+	cycleID := seed(pausedAt)
+	require.NoError(t, svc.RunRating(context.Background(), cycleID.String()))
+
+	var results []ratingdomain.RatingResult
+	db.Where("billing_cycle_id = ?", cycleID).Find(&results)
+	require.Len(t, results, 1)
+	assert.Equal(t, 3.0, results[0].Quantity)
+	assert.Equal(t, cycleStart, results[0].PeriodStart)
+	assert.Equal(t, pausedAt, results[0].PeriodEnd)
+
+	// Paused before the cycle started: the metered item is not rated.
+	cycleID = seed(time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, svc.RunRating(context.Background(), cycleID.String()))
+
+	results = nil
+	db.Where("billing_cycle_id = ?", cycleID).Find(&results)
+	assert.Empty(t, results)
+}
+
 // Helper functions
 
 func setupProrationTest(t *testing.T) (*gorm.DB, ratingdomain.Service, *snowflake.Node) {
@@ -377,12 +509,16 @@ func setupProrationTest(t *testing.T) (*gorm.DB, ratingdomain.Service, *snowflak
 
 	err = db.AutoMigrate(
 		&ratingdomain.RatingResult{},
+		&ratingdomain.ThresholdUsage{},
 		&subscriptiondomain.SubscriptionItem{},
 		&subscriptiondomain.Subscription{},
 		&subscriptiondomain.SubscriptionEntitlement{},
 		&billingcycledomain.BillingCycle{},
 		&pricedomain.Price{},
+		&organizationdomain.OrganizationBillingPreferences{},
 		&usagedomain.UsageEvent{},
+		&usagedomain.UsageRollup{},
+		&meterdomain.Meter{},
 	)
 	require.NoError(t, err)
 
@@ -450,11 +586,12 @@ func seedProrationData(
 	})
 
 	db.Create(&pricedomain.Price{
-		ID:        priceID,
-		OrgID:     orgID,
-		ProductID: productID,
-		Code:      "test_price",
-		Active:    true,
+		ID:           priceID,
+		OrgID:        orgID,
+		ProductID:    productID,
+		Code:         "test_price",
+		Active:       true,
+		PricingModel: pricedomain.Flat,
 	})
 
 	// Configure price amount stub
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunRating_MarksCycleUsageRated verifies that closing rating moves the
+// cycle's enriched usage to rated, leaves usage outside the cycle alone, and
+// still counts rated usage when the cycle is rated again.
+func TestRunRating_MarksCycleUsageRated(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	productID := node.Generate()
+	priceID := node.Generate()
+	meterID := node.Generate()
+	cycleStart := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, db.Create(&meterdomain.Meter{
+		ID:          meterID,
+		OrgID:       orgID,
+		Code:        "api_calls_" + meterID.String(),
+		Name:        "API calls",
+		Aggregation: "SUM",
+		Unit:        "call",
+		Active:      true,
+	}).Error)
+	require.NoError(t, db.Create(&pricedomain.Price{
+		ID:           priceID,
+		OrgID:        orgID,
+		ProductID:    productID,
+		Code:         "api_" + priceID.String(),
+		PricingModel: pricedomain.PerUnit,
+		Active:       true,
+	}).Error)
+	priceAmountStub.Amounts[priceID.String()] = priceamountdomain.PriceAmount{
+		PriceID:         priceID,
+		UnitAmountCents: 100,
+		Currency:        "USD",
+	}
+	currency := "USD"
+	require.NoError(t, db.Create(&subscriptiondomain.Subscription{
+		ID:              subID,
+		OrgID:           orgID,
+		CustomerID:      node.Generate(),
+		Status:          subscriptiondomain.SubscriptionStatusActive,
+		StartAt:         cycleStart,
+		DefaultCurrency: &currency,
+	}).Error)
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionItem{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PriceID:        priceID,
+		MeterID:        &meterID,
+		Quantity:       1,
+		BillingMode:    "METERED",
+	}).Error)
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionEntitlement{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		ProductID:      productID,
+		FeatureCode:    "api_calls",
+		MeterID:        &meterID,
+		EffectiveFrom:  cycleStart,
+	}).Error)
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID:             cycleID,
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PeriodStart:    cycleStart,
+		PeriodEnd:      cycleEnd,
+		Status:         billingcycledomain.BillingCycleStatusClosing,
+	}).Error)
+
+	record := func(value float64, at time.Time) usagedomain.UsageEvent {
+		event := usagedomain.UsageEvent{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			MeterID:        meterID,
+			SubscriptionID: subID,
+			Value:          value,
+			RecordedAt:     at,
+			Status:         usagedomain.UsageStatusEnriched,
+		}
+		require.NoError(t, db.Create(&event).Error)
+		return event
+	}
+	inCycle := record(30, cycleStart.Add(24*time.Hour))
+	nextCycle := record(50, cycleEnd.Add(24*time.Hour))
+
+	status := func(event usagedomain.UsageEvent) string {
+		var stored usagedomain.UsageEvent
+		require.NoError(t, db.First(&stored, "id = ?", event.ID).Error)
+		return stored.Status
+	}
+	amount := func() int64 {
+		var results []ratingdomain.RatingResult
+		require.NoError(t, db.Where("billing_cycle_id = ?", cycleID).Find(&results).Error)
+		require.Len(t, results, 1)
+		return results[0].Amount
+	}
+
+	require.NoError(t, svc.RunRating(context.Background(), cycleID.String()))
+	assert.Equal(t, usagedomain.UsageStatusRated, status(inCycle))
+	assert.Equal(t, usagedomain.UsageStatusEnriched, status(nextCycle))
+	assert.Equal(t, int64(3000), amount())
+
+	// Usage arriving late for the cycle is rated with the rest.
+	late := record(10, cycleStart.Add(48*time.Hour))
+	require.NoError(t, svc.RunRating(context.Background(), cycleID.String()))
+	assert.Equal(t, usagedomain.UsageStatusRated, status(late))
+	assert.Equal(t, int64(4000), amount())
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+)
+
+// ratingWriter writes the results of one rating run. Results are upserted by
+// checksum and remembered, so the run can afterwards remove the results it no
+// longer produces. Re-rating an unchanged cycle therefore writes nothing.
+type ratingWriter struct {
+	repo      ratingdomain.Repository
+	checksums []string
+}
+
+func newRatingWriter(repo ratingdomain.Repository) *ratingWriter {
+	return &ratingWriter{repo: repo}
+}
+
+func (w *ratingWriter) write(ctx context.Context, result ratingdomain.RatingResult) error {
+	if err := w.repo.UpsertRatingResult(ctx, result); err != nil {
+		return err
+	}
+	w.checksums = append(w.checksums, result.Checksum)
+	return nil
+}
+
+// prune deletes the phase's results this run has not written, leaving rows
+// of the given sources alone.
+func (w *ratingWriter) prune(ctx context.Context, cycleID snowflake.ID, phase billingcycledomain.BillingPhase, sources ...string) error {
+	return w.repo.DeleteRatingResultsExcept(ctx, cycleID, phase, w.checksums, sources...)
+}
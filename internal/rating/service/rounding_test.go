@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	pricetierdomain "github.com/railzwaylabs/railzway/internal/pricetier/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestRoundRatingAmount_Modes(t *testing.T) {
+	cases := []struct {
+		raw      float64
+		mode     organizationdomain.RoundingMode
+		expected int64
+	}{
+		{12.5, organizationdomain.RoundingModeHalfUp, 13},
+		{12.5, organizationdomain.RoundingModeHalfEven, 12},
+		{13.5, organizationdomain.RoundingModeHalfEven, 14},
+		{12.5, organizationdomain.RoundingModeFloor, 12},
+		{12.1, organizationdomain.RoundingModeCeil, 13},
+		{12.4, organizationdomain.RoundingModeHalfEven, 12},
+		{12.6, organizationdomain.RoundingModeHalfEven, 13},
+		// Unknown modes round half up.
+		{12.5, organizationdomain.RoundingMode(""), 13},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, roundRatingAmount(tc.raw, tc.mode), "%v %s", tc.raw, tc.mode)
+	}
+}
+
+func TestTieredGraduatedAmount_RoundingMode(t *testing.T) {
+	tiers := []pricetierdomain.PriceTier{
+		{StartQuantity: 0, EndQuantity: floatPtr(10), UnitAmountCents: int64Ptr(5)},
+		{StartQuantity: 10, EndQuantity: nil, UnitAmountCents: int64Ptr(1)},
+	}
+
+	// 10*5 + 2.5*1 = 52.5
+	halfUp, _, err := calculateTieredGraduatedAmount(12.5, tiers, organizationdomain.RoundingModeHalfUp)
+	require.NoError(t, err)
+	halfEven, _, err := calculateTieredGraduatedAmount(12.5, tiers, organizationdomain.RoundingModeHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, int64(53), halfUp)
+	assert.Equal(t, int64(52), halfEven)
+}
+
+// TestRunRating_UsesOrgRoundingMode rates the same half-cent usage charge
+// for an organization on banker's rounding and one on the default.
+func TestRunRating_UsesOrgRoundingMode(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+
+	cycleStart := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	currency := "USD"
+
+	rate := func(mode *organizationdomain.RoundingMode) int64 {
+		orgID := node.Generate()
+		subID := node.Generate()
+		cycleID := node.Generate()
+		priceID := node.Generate()
+		meterID := node.Generate()
+
+		if mode != nil {
+			require.NoError(t, db.Create(&organizationdomain.OrganizationBillingPreferences{
+				OrgID:        orgID,
+				Currency:     currency,
+				Timezone:     "UTC",
+				DunningDays:  datatypes.JSON(`[1, 7, 14]`),
+				RoundingMode: *mode,
+			}).Error)
+		}
+		require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+			ID:             cycleID,
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			PeriodStart:    cycleStart,
+			PeriodEnd:      cycleEnd,
+			Status:         billingcycledomain.BillingCycleStatusClosing,
+		}).Error)
+		require.NoError(t, db.Create(&subscriptiondomain.Subscription{
+			ID:              subID,
+			OrgID:           orgID,
+			CustomerID:      node.Generate(),
+			Status:          subscriptiondomain.SubscriptionStatusActive,
+			StartAt:         cycleStart,
+			DefaultCurrency: &currency,
+		}).Error)
+		require.NoError(t, db.Create(&subscriptiondomain.SubscriptionItem{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			PriceID:        priceID,
+			MeterID:        &meterID,
+			Quantity:       1,
+			BillingMode:    "METERED",
+		}).Error)
+		require.NoError(t, db.Create(&pricedomain.Price{
+			ID:           priceID,
+			OrgID:        orgID,
+			ProductID:    node.Generate(),
+			Code:         "api_calls_" + priceID.String(),
+			PricingModel: pricedomain.PerUnit,
+			BillingMode:  pricedomain.Metered,
+			Active:       true,
+		}).Error)
+		priceAmountStub.Amounts[priceID.String()] = priceamountdomain.PriceAmount{
+			PriceID:         priceID,
+			MeterID:         &meterID,
+			UnitAmountCents: 5,
+			Currency:        currency,
+		}
+		require.NoError(t, db.Create(&usagedomain.UsageEvent{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			MeterID:        meterID,
+			SubscriptionID: subID,
+			Value:          2.5,
+			RecordedAt:     cycleStart.Add(48 * time.Hour),
+			Status:         usagedomain.UsageStatusEnriched,
+		}).Error)
+
+		require.NoError(t, svc.RunRating(context.Background(), cycleID.String()))
+
+		var results []ratingdomain.RatingResult
+		require.NoError(t, db.Where("billing_cycle_id = ?", cycleID).Find(&results).Error)
+		require.Len(t, results, 1)
+		return results[0].Amount
+	}
+
+	halfEven := organizationdomain.RoundingModeHalfEven
+	// 2.5 units at 5 cents is 12.5 cents.
+	assert.Equal(t, int64(12), rate(&halfEven))
+	assert.Equal(t, int64(13), rate(nil))
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"github.com/railzwaylabs/railzway/internal/rating/repository"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"gorm.io/gorm"
+)
+
+// applySeatOverage bills the seats a licensed item's seat meter counted in
+// the cycle above the item's quantity, at the item's per-unit overage price.
+// Seats are counted once over the whole window, so the meter's aggregation
+// decides what a seat is, e.g. UNIQUE_COUNT of active user ids. Items
+// without an overage price only report their overage.
+func (s *Service) applySeatOverage(
+	ctx context.Context,
+	tx *gorm.DB,
+	w *ratingWriter,
+	cycle *ratingdomain.BillingCycleRow,
+	subscription *subscriptiondomain.Subscription,
+	items []ratingdomain.SubscriptionItemRow,
+	entitlements []subscriptiondomain.SubscriptionEntitlement,
+	currency string,
+	rounding organizationdomain.RoundingMode,
+	now time.Time,
+) error {
+	repoTx := repository.NewRepository(tx)
+	for _, item := range items {
+		if item.SeatMeterID == nil || item.OveragePriceID == nil {
+			continue
+		}
+
+		featureCode, ent, err := s.resolveEntitlementWithWindow(ctx, tx, item, entitlements)
+		if err != nil {
+			return err
+		}
+		start, end, active := resolveEffectiveWindow(
+			cycle.PeriodStart, cycle.PeriodEnd,
+			subscription.StartAt, subscription.EndedAt, subscription.CanceledAt,
+			getEntEffectiveFrom(ent), getEntEffectiveTo(ent),
+		)
+		if !active {
+			continue
+		}
+		end, active = pausedUsageEnd(subscription, start, end)
+		if !active {
+			continue
+		}
+
+		seats, err := repoTx.AggregateUsage(ctx, cycle.OrgID, cycle.SubscriptionID, *item.SeatMeterID, start, end, nil)
+		if err != nil {
+			return err
+		}
+		overage := subscriptiondomain.SeatOverage(seats, item.Quantity)
+		if overage <= 0 {
+			continue
+		}
+
+		priceAmount, err := s.resolvePriceAmountAt(ctx, tx, cycle.OrgID, *item.OveragePriceID, item.SeatMeterID, currency, start)
+		if err != nil {
+			return err
+		}
+		if priceAmount == nil {
+			return ratingdomain.ErrMissingPriceAmount
+		}
+
+		if err := w.write(ctx, ratingdomain.RatingResult{
+			ID:             s.genID.Generate(),
+			OrgID:          cycle.OrgID,
+			SubscriptionID: cycle.SubscriptionID,
+			BillingCycleID: cycle.ID,
+			BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
+			MeterID:        item.SeatMeterID,
+			PriceID:        *item.OveragePriceID,
+			FeatureCode:    featureCode,
+			Quantity:       overage,
+			UnitPrice:      priceAmount.UnitAmountCents,
+			Amount:         roundRatingAmount(overage*float64(priceAmount.UnitAmountCents), rounding),
+			Currency:       currency,
+			PeriodStart:    start,
+			PeriodEnd:      end,
+			Source:         ratingdomain.RatingSourceSeatOverage,
+			Checksum:       buildRatingChecksum(cycle.ID, cycle.SubscriptionID, *item.OveragePriceID, item.SeatMeterID, nil, ratingdomain.RatingSourceSeatOverage+"|"+featureCode, start, end),
+			CreatedAt:      now,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSeatOverage_BillsSeatsAboveLicensedQuantity verifies that the distinct
+// users a licensed item's seat meter counted above the item's quantity are
+// billed at the overage price.
+func TestSeatOverage_BillsSeatsAboveLicensedQuantity(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	productID := node.Generate()
+	seatPriceID := node.Generate()
+	overagePriceID := node.Generate()
+	meterID := node.Generate()
+	cycleStart := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, db.Create(&meterdomain.Meter{
+		ID:            meterID,
+		OrgID:         orgID,
+		Code:          "active_users_" + meterID.String(),
+		Name:          "Active users",
+		Aggregation:   meterdomain.AggregationUniqueCount,
+		Unit:          "user",
+		ResetInterval: meterdomain.ResetIntervalBillingCycle,
+		Active:        true,
+	}).Error)
+	for _, price := range []pricedomain.Price{
+		{ID: seatPriceID, OrgID: orgID, ProductID: productID, Code: "seats_" + seatPriceID.String(), PricingModel: pricedomain.Flat, Active: true},
+		{ID: overagePriceID, OrgID: orgID, ProductID: productID, Code: "overage_" + overagePriceID.String(), PricingModel: pricedomain.PerUnit, Active: true},
+	} {
+		require.NoError(t, db.Create(&price).Error)
+	}
+	priceAmountStub.Amounts[seatPriceID.String()] = priceamountdomain.PriceAmount{
+		PriceID:         seatPriceID,
+		UnitAmountCents: 1000,
+		Currency:        "USD",
+	}
+	priceAmountStub.Amounts[overagePriceID.String()] = priceamountdomain.PriceAmount{
+		PriceID:         overagePriceID,
+		UnitAmountCents: 300,
+		Currency:        "USD",
+	}
+
+	currency := "USD"
+	require.NoError(t, db.Create(&subscriptiondomain.Subscription{
+		ID:              subID,
+		OrgID:           orgID,
+		CustomerID:      node.Generate(),
+		Status:          subscriptiondomain.SubscriptionStatusActive,
+		StartAt:         cycleStart,
+		DefaultCurrency: &currency,
+	}).Error)
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionItem{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PriceID:        seatPriceID,
+		Quantity:       5,
+		BillingMode:    "LICENSED",
+		SeatMeterID:    &meterID,
+		OveragePriceID: &overagePriceID,
+	}).Error)
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID:             cycleID,
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PeriodStart:    cycleStart,
+		PeriodEnd:      cycleEnd,
+		Status:         billingcycledomain.BillingCycleStatusClosing,
+	}).Error)
+
+	// Seven distinct users, two of them active more than once.
+	for i, user := range []float64{1, 2, 3, 4, 5, 6, 7, 1, 2} {
+		require.NoError(t, db.Create(&usagedomain.UsageEvent{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			MeterID:        meterID,
+			SubscriptionID: subID,
+			Value:          user,
+			RecordedAt:     cycleStart.Add(time.Duration(i+1) * time.Hour),
+			Status:         usagedomain.UsageStatusEnriched,
+		}).Error)
+	}
+
+	require.NoError(t, svc.RunRating(context.Background(), cycleID.String()))
+
+	var overage []ratingdomain.RatingResult
+	require.NoError(t, db.Where("billing_cycle_id = ? AND source = ?", cycleID, ratingdomain.RatingSourceSeatOverage).Find(&overage).Error)
+	require.Len(t, overage, 1)
+	assert.Equal(t, overagePriceID, overage[0].PriceID)
+	require.NotNil(t, overage[0].MeterID)
+	assert.Equal(t, meterID, *overage[0].MeterID)
+	assert.Equal(t, 2.0, overage[0].Quantity)
+	assert.Equal(t, int64(600), overage[0].Amount)
+	assert.Equal(t, string(billingcycledomain.BillingPhaseArrears), overage[0].BillingPhase)
+}
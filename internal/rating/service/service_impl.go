@@ -11,6 +11,11 @@ import (
 	"github.com/bwmarrin/snowflake"
 	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
 	"github.com/railzwaylabs/railzway/internal/bootstrap"
+	coupondomain "github.com/railzwaylabs/railzway/internal/coupon/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	obsmetrics "github.com/railzwaylabs/railzway/internal/observability/metrics"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
 	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
 	pricetierdomain "github.com/railzwaylabs/railzway/internal/pricetier/domain"
@@ -19,6 +24,7 @@ import (
 	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -31,6 +37,8 @@ type Service struct {
 	priceRepo       pricedomain.Repository
 	priceAmountRepo priceamountdomain.Repository
 	orgGate         bootstrap.OrgGate
+	discounts       coupondomain.DiscountResolver
+	accounts        ledgerdomain.AccountProvisioner
 }
 
 const defaultCurrency = "USD"
@@ -43,7 +51,9 @@ type ServiceParam struct {
 	GenID           *snowflake.Node
 	PriceRepo       pricedomain.Repository
 	PriceAmountRepo priceamountdomain.Repository
-	OrgGate         bootstrap.OrgGate `optional:"true"`
+	OrgGate         bootstrap.OrgGate               `optional:"true"`
+	Discounts       coupondomain.DiscountResolver   `optional:"true"`
+	Accounts        ledgerdomain.AccountProvisioner `optional:"true"`
 }
 
 func NewService(p ServiceParam) ratingdomain.Service {
@@ -56,149 +66,282 @@ func NewService(p ServiceParam) ratingdomain.Service {
 		priceRepo:       p.PriceRepo,
 		priceAmountRepo: p.PriceAmountRepo,
 		orgGate:         p.OrgGate,
+		discounts:       p.Discounts,
+		accounts:        p.Accounts,
 	}
 }
 
+// RunRating rates the arrears charges of a closing billing cycle. Flat items
+// billed in advance are skipped; they are rated by RunAdvanceRating when the
+// cycle opens.
 func (s *Service) RunRating(ctx context.Context, billingCycleID string) error {
-	cycleID, err := parseID(billingCycleID)
-	if err != nil {
-		return ratingdomain.ErrInvalidBillingCycle
-	}
+	return s.runRating(ctx, billingCycleID, billingcycledomain.BillingPhaseArrears)
+}
 
-	cycle, err := s.repo.GetBillingCycle(ctx, cycleID)
+// RunAdvanceRating rates flat items billed in advance for an open billing cycle.
+func (s *Service) RunAdvanceRating(ctx context.Context, billingCycleID string) error {
+	return s.runRating(ctx, billingCycleID, billingcycledomain.BillingPhaseAdvance)
+}
+
+func (s *Service) runRating(ctx context.Context, billingCycleID string, phase billingcycledomain.BillingPhase) (err error) {
+	start := time.Now()
+	var orgID snowflake.ID
+	var itemCount int
+	defer func() {
+		obsmetrics.Billing().ObserveRating(s.loadOrgTier(ctx, orgID), time.Since(start), itemCount, err)
+	}()
+
+	cycle, err := s.loadBillingCycle(ctx, billingCycleID)
 	if err != nil {
 		return err
 	}
-	if cycle == nil {
-		return ratingdomain.ErrBillingCycleNotFound
-	}
-	if cycle.Status != billingcycledomain.BillingCycleStatusClosing {
-		return ratingdomain.ErrBillingCycleNotClosing
+	orgID = cycle.OrgID
+	switch phase {
+	case billingcycledomain.BillingPhaseAdvance:
+		if cycle.Status != billingcycledomain.BillingCycleStatusOpen {
+			return ratingdomain.ErrBillingCycleNotOpen
+		}
+	default:
+		if cycle.Status != billingcycledomain.BillingCycleStatusClosing {
+			return ratingdomain.ErrBillingCycleNotClosing
+		}
 	}
 	if s.orgGate != nil {
 		if err := s.orgGate.MustBeActive(ctx, cycle.OrgID); err != nil {
 			return err
 		}
 	}
+	// Orgs created outside the bootstrap seed get their chart of accounts on
+	// their first rating run, before anything is invoiced and posted.
+	if s.accounts != nil {
+		if err := s.accounts.EnsureAccounts(ctx, cycle.OrgID); err != nil {
+			return err
+		}
+	}
 
-	subscription, err := s.repo.GetSubscription(ctx, cycle.OrgID, cycle.SubscriptionID)
+	subscription, items, err := s.loadSubscriptionItems(ctx, cycle)
 	if err != nil {
 		return err
 	}
+	itemCount = len(items)
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.rateCycle(ctx, tx, cycle, subscription, items, phase)
+	})
+}
+
+func (s *Service) loadBillingCycle(ctx context.Context, billingCycleID string) (*ratingdomain.BillingCycleRow, error) {
+	cycleID, err := parseID(billingCycleID)
+	if err != nil {
+		return nil, ratingdomain.ErrInvalidBillingCycle
+	}
+
+	cycle, err := s.repo.GetBillingCycle(ctx, cycleID)
+	if err != nil {
+		return nil, err
+	}
+	if cycle == nil {
+		return nil, ratingdomain.ErrBillingCycleNotFound
+	}
+	return cycle, nil
+}
+
+func (s *Service) loadSubscriptionItems(ctx context.Context, cycle *ratingdomain.BillingCycleRow) (*subscriptiondomain.Subscription, []ratingdomain.SubscriptionItemRow, error) {
+	subscription, err := s.repo.GetSubscription(ctx, cycle.OrgID, cycle.SubscriptionID)
+	if err != nil {
+		return nil, nil, err
+	}
 	if subscription == nil {
-		return ratingdomain.ErrSubscriptionNotFound
+		return nil, nil, ratingdomain.ErrSubscriptionNotFound
 	}
 
 	items, err := s.repo.ListSubscriptionItems(ctx, cycle.OrgID, cycle.SubscriptionID)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	if len(items) == 0 {
-		return ratingdomain.ErrNoSubscriptionItems
+		return nil, nil, ratingdomain.ErrNoSubscriptionItems
 	}
+	return subscription, items, nil
+}
 
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		repoTx := repository.NewRepository(tx)
+// rateCycle rates the cycle's phase within tx. Results are upserted by
+// checksum and the ones the cycle no longer produces are removed, so lines
+// that did not change keep their rows.
+func (s *Service) rateCycle(
+	ctx context.Context,
+	tx *gorm.DB,
+	cycle *ratingdomain.BillingCycleRow,
+	subscription *subscriptiondomain.Subscription,
+	items []ratingdomain.SubscriptionItemRow,
+	phase billingcycledomain.BillingPhase,
+) error {
+	repoTx := repository.NewRepository(tx)
+	writer := newRatingWriter(repoTx)
 
-		if err := repoTx.DeleteRatingResults(ctx, cycle.ID); err != nil {
-			return err
-		}
+	entitlements, err := repoTx.ListEntitlements(ctx, cycle.OrgID, cycle.SubscriptionID, cycle.PeriodStart, cycle.PeriodEnd)
+	if err != nil {
+		return err
+	}
 
-		entitlements, err := repoTx.ListEntitlements(ctx, cycle.OrgID, cycle.SubscriptionID, cycle.PeriodStart, cycle.PeriodEnd)
+	currency, err := s.resolveSubscriptionCurrency(ctx, tx, subscription)
+	if err != nil {
+		return err
+	}
+
+	rounding, err := s.loadRoundingMode(ctx, tx, cycle.OrgID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	cycleDuration := cycle.PeriodEnd.Sub(cycle.PeriodStart).Seconds()
+
+	for _, item := range items {
+		price, err := s.priceRepo.FindByID(ctx, tx, cycle.OrgID, item.PriceID)
 		if err != nil {
 			return err
 		}
+		if price == nil {
+			return ratingdomain.ErrMissingPriceAmount
+		}
+		if itemBillingPhase(item, price.PricingModel) != phase {
+			continue
+		}
 
-		currency, err := s.resolveSubscriptionCurrency(ctx, tx, subscription)
+		featureCode, ent, err := s.resolveEntitlementWithWindow(ctx, tx, item, entitlements)
 		if err != nil {
-			return err
+			return fmt.Errorf("rating failed for item %s: %w", item.ID, err)
 		}
 
-		now := time.Now().UTC()
-		cycleDuration := cycle.PeriodEnd.Sub(cycle.PeriodStart).Seconds()
+		start, end, active := resolveEffectiveWindow(
+			cycle.PeriodStart, cycle.PeriodEnd,
+			subscription.StartAt, subscription.EndedAt, subscription.CanceledAt,
+			getEntEffectiveFrom(ent), getEntEffectiveTo(ent),
+		)
 
-		for _, item := range items {
-			price, err := s.priceRepo.FindByID(ctx, tx, cycle.OrgID, item.PriceID)
-			if err != nil {
-				return err
-			}
-			if price == nil {
-				return ratingdomain.ErrMissingPriceAmount
-			}
-
-			featureCode, ent, err := s.resolveEntitlementWithWindow(ctx, tx, item, entitlements)
-			if err != nil {
-				return fmt.Errorf("rating failed for item %s: %w", item.ID, err)
-			}
+		if !active {
+			continue
+		}
 
-			start, end, active := resolveEffectiveWindow(
-				cycle.PeriodStart, cycle.PeriodEnd,
-				subscription.StartAt, subscription.EndedAt, subscription.CanceledAt,
-				getEntEffectiveFrom(ent), getEntEffectiveTo(ent),
-			)
+		prorationFactor := billingcycledomain.ProrationFactor(start, end, cycleDuration)
 
-			if !active {
-				continue
+		if price.PricingModel == pricedomain.Flat {
+			if err := s.rateFlatItem(ctx, tx, writer, cycle, item, phase, featureCode, start, end, prorationFactor, currency, rounding, now); err != nil {
+				return err
 			}
+			continue
+		}
 
-			prorationFactor := calculateProrationFactor(start, end, cycleDuration)
+		if item.MeterID == nil {
+			return ratingdomain.ErrMissingMeter
+		}
 
-			if price.PricingModel == pricedomain.Flat {
-				if err := s.rateFlatItem(ctx, tx, cycle, item, featureCode, start, end, prorationFactor, currency, now); err != nil {
-					return err
-				}
-				continue
-			}
+		end, active = pausedUsageEnd(subscription, start, end)
+		if !active {
+			continue
+		}
 
-			if item.MeterID == nil {
-				return ratingdomain.ErrMissingMeter
-			}
+		windows, err := s.buildPriceWindows(ctx, tx, cycle.OrgID, item.PriceID, item.MeterID, currency, start, end)
+		if err != nil {
+			return err
+		}
+
+		allowance, err := s.includedAllowance(ctx, repoTx, cycle, subscription, item, start)
+		if err != nil {
+			return err
+		}
 
-			windows, err := s.buildPriceWindows(ctx, tx, cycle.OrgID, item.PriceID, item.MeterID, currency, start, end)
+		for _, window := range windows {
+			qty, err := repoTx.AggregateUsage(ctx, cycle.OrgID, cycle.SubscriptionID, *item.MeterID, window.Start, window.End, item.Dimension())
 			if err != nil {
 				return err
 			}
 
-			for _, window := range windows {
-				qty, err := repoTx.AggregateUsage(ctx, cycle.OrgID, cycle.SubscriptionID, *item.MeterID, window.Start, window.End)
-				if err != nil {
+			// The allowance covers the earliest usage of the cycle; only
+			// the remainder is priced.
+			if included := math.Min(allowance, qty); included > 0 {
+				if err := s.insertIncludedUsage(ctx, tx, writer, cycle, item, window, included, featureCode, currency, now); err != nil {
 					return err
 				}
+				allowance -= included
+				qty -= included
+			}
 
-				switch price.PricingModel {
-				case pricedomain.PerUnit:
-					if err := s.insertRatingWindow(ctx, tx, cycle, item, window, qty, "usage_events", featureCode, currency, now); err != nil {
-						return err
-					}
-				case pricedomain.TieredVolume, pricedomain.TieredGraduated:
-					tiers, err := s.listPriceTiers(ctx, tx, cycle.OrgID, item.PriceID)
-					if err != nil {
-						return err
-					}
-					if len(tiers) == 0 {
-						return ratingdomain.ErrMissingPriceTier
-					}
-					var amount int64
-					var unitPrice int64
-					if price.PricingModel == pricedomain.TieredVolume {
-						amount, unitPrice, err = calculateTieredVolumeAmount(qty, tiers)
-					} else {
-						amount, unitPrice, err = calculateTieredGraduatedAmount(qty, tiers)
-					}
-					if err != nil {
-						return err
-					}
-					if err := s.insertTieredRating(ctx, tx, cycle, item, window, qty, unitPrice, amount, currency, price.PricingModel, featureCode, now); err != nil {
-						return err
-					}
-				default:
-					return pricedomain.ErrUnsupportedPricingModel
+			switch price.PricingModel {
+			case pricedomain.PerUnit:
+				if err := s.insertRatingWindow(ctx, tx, writer, cycle, item, window, qty, "usage_events", featureCode, currency, rounding, now); err != nil {
+					return err
+				}
+			case pricedomain.TieredVolume, pricedomain.TieredGraduated:
+				tiers, err := s.listPriceTiers(ctx, tx, cycle.OrgID, item.PriceID)
+				if err != nil {
+					return err
+				}
+				if len(tiers) == 0 {
+					return ratingdomain.ErrMissingPriceTier
 				}
+				var amount int64
+				var unitPrice int64
+				if price.PricingModel == pricedomain.TieredVolume {
+					amount, unitPrice, err = calculateTieredVolumeAmount(qty, tiers, rounding)
+				} else {
+					amount, unitPrice, err = calculateTieredGraduatedAmount(qty, tiers, rounding)
+				}
+				if err != nil {
+					return err
+				}
+				if err := s.insertTieredRating(ctx, tx, writer, cycle, item, window, qty, unitPrice, amount, currency, price.PricingModel, featureCode, now); err != nil {
+					return err
+				}
+			default:
+				return pricedomain.ErrUnsupportedPricingModel
 			}
 		}
+	}
 
-		return nil
-	})
+	if phase == billingcycledomain.BillingPhaseArrears {
+		if err := s.applyThresholdCredits(ctx, tx, writer, cycle, items, entitlements, now); err != nil {
+			return err
+		}
+		if err := s.applySeatOverage(ctx, tx, writer, cycle, subscription, items, entitlements, currency, rounding, now); err != nil {
+			return err
+		}
+	}
+
+	// Drop stale charges before the true-up and discounts sum what is left.
+	if err := writer.prune(ctx, cycle.ID, phase, ratingdomain.RatingSourceMinimumCommitment, ratingdomain.RatingSourceDiscount); err != nil {
+		return err
+	}
+
+	// The true-up needs every charge of the cycle, so it runs with the
+	// closing arrears rating.
+	if phase == billingcycledomain.BillingPhaseArrears {
+		if err := s.applyMinimumCommitment(ctx, tx, writer, cycle, subscription, currency, cycleDuration, rounding, now); err != nil {
+			return err
+		}
+		// The cycle's usage is billed now; usage arriving later for a
+		// reopened cycle stays enriched until the cycle is rated again.
+		if err := repoTx.MarkUsageRated(ctx, cycle.OrgID, cycle.SubscriptionID, cycle.PeriodStart, cycle.PeriodEnd, now); err != nil {
+			return err
+		}
+	}
+	if err := s.applyDiscounts(ctx, tx, writer, cycle, phase, currency, rounding, now); err != nil {
+		return err
+	}
+	return writer.prune(ctx, cycle.ID, phase)
+}
+
+// itemBillingPhase reports when an item is billed. Only flat items can be
+// billed in advance; metered items always bill in arrears.
+func itemBillingPhase(item ratingdomain.SubscriptionItemRow, pricingModel pricedomain.PricingModel) billingcycledomain.BillingPhase {
+	if pricingModel != pricedomain.Flat || item.UsageBehavior == nil {
+		return billingcycledomain.BillingPhaseArrears
+	}
+	if strings.EqualFold(strings.TrimSpace(*item.UsageBehavior), subscriptiondomain.UsageBehaviorAdvance) {
+		return billingcycledomain.BillingPhaseAdvance
+	}
+	return billingcycledomain.BillingPhaseArrears
 }
 
 func getEntEffectiveFrom(ent *subscriptiondomain.SubscriptionEntitlement) time.Time {
@@ -248,12 +391,15 @@ func (s *Service) resolveEntitlementWithWindow(
 func (s *Service) rateFlatItem(
 	ctx context.Context,
 	tx *gorm.DB,
+	w *ratingWriter,
 	cycle *ratingdomain.BillingCycleRow,
 	item ratingdomain.SubscriptionItemRow,
+	phase billingcycledomain.BillingPhase,
 	featureCode string,
 	periodStart, periodEnd time.Time,
 	prorationFactor float64,
 	currency string,
+	rounding organizationdomain.RoundingMode,
 	now time.Time,
 ) error {
 	priceAmount, err := s.resolvePriceAmountAt(ctx, tx, cycle.OrgID, item.PriceID, nil, currency, periodStart)
@@ -264,22 +410,30 @@ func (s *Service) rateFlatItem(
 		return ratingdomain.ErrMissingPriceAmount
 	}
 
+	// Licensed quantities multiply the unit amount; the stored quantity is the
+	// prorated seat count so that unit price times quantity matches the amount.
+	quantity := float64(item.Quantity)
+	if quantity <= 0 {
+		quantity = 1
+	}
+	ratedQuantity := quantity * prorationFactor
+
 	baseAmount := float64(priceAmount.UnitAmountCents)
-	finalAmount := roundRatingAmount(baseAmount * prorationFactor)
+	finalAmount := roundRatingAmount(baseAmount*ratedQuantity, rounding)
 
-	checksum := buildRatingChecksum(cycle.ID, cycle.SubscriptionID, item.PriceID, item.MeterID, featureCode, periodStart, periodEnd)
+	checksum := buildRatingChecksum(cycle.ID, cycle.SubscriptionID, item.PriceID, item.MeterID, item.Dimension(), featureCode, periodStart, periodEnd)
 
-	repoTx := repository.NewRepository(tx)
-	return repoTx.InsertRatingResult(ctx, ratingdomain.RatingResult{
+	return w.write(ctx, ratingdomain.RatingResult{
 		ID:             s.genID.Generate(),
 		OrgID:          cycle.OrgID,
 		SubscriptionID: cycle.SubscriptionID,
 		BillingCycleID: cycle.ID,
+		BillingPhase:   string(phase),
 		PriceID:        item.PriceID,
 		FeatureCode:    featureCode,
 		MeterID:        item.MeterID,
 		Source:         "flat_rate",
-		Quantity:       prorationFactor,
+		Quantity:       ratedQuantity,
 		UnitPrice:      priceAmount.UnitAmountCents,
 		Amount:         finalAmount,
 		Currency:       currency,
@@ -360,6 +514,7 @@ func (s *Service) resolvePriceAmountAt(
 func (s *Service) insertRatingWindow(
 	ctx context.Context,
 	tx *gorm.DB,
+	w *ratingWriter,
 	cycle *ratingdomain.BillingCycleRow,
 	item ratingdomain.SubscriptionItemRow,
 	window priceWindow,
@@ -367,6 +522,7 @@ func (s *Service) insertRatingWindow(
 	source string,
 	featureCode string,
 	currency string,
+	rounding organizationdomain.RoundingMode,
 	now time.Time,
 ) error {
 	if quantity < 0 {
@@ -374,7 +530,7 @@ func (s *Service) insertRatingWindow(
 	}
 
 	unitPrice := window.Amount.UnitAmountCents
-	amount := roundRatingAmount(quantity * float64(unitPrice))
+	amount := roundRatingAmount(quantity*float64(unitPrice), rounding)
 
 	if window.Amount.MinimumAmountCents != nil && *window.Amount.MinimumAmountCents > 0 {
 		if amount < *window.Amount.MinimumAmountCents {
@@ -387,14 +543,14 @@ func (s *Service) insertRatingWindow(
 		}
 	}
 
-	checksum := buildRatingChecksum(cycle.ID, cycle.SubscriptionID, item.PriceID, item.MeterID, featureCode, window.Start, window.End)
+	checksum := buildRatingChecksum(cycle.ID, cycle.SubscriptionID, item.PriceID, item.MeterID, item.Dimension(), featureCode, window.Start, window.End)
 
-	repoTx := repository.NewRepository(tx)
-	return repoTx.InsertRatingResult(ctx, ratingdomain.RatingResult{
+	return w.write(ctx, ratingdomain.RatingResult{
 		ID:             s.genID.Generate(),
 		OrgID:          cycle.OrgID,
 		SubscriptionID: cycle.SubscriptionID,
 		BillingCycleID: cycle.ID,
+		BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
 		MeterID:        item.MeterID,
 		PriceID:        item.PriceID,
 		FeatureCode:    featureCode,
@@ -410,6 +566,75 @@ func (s *Service) insertRatingWindow(
 	})
 }
 
+// includedAllowance returns how much of the item's included quantity is left
+// for usage from usageStart. Allowances on meters that never reset are
+// reduced by the usage rated since the subscription started, so they only
+// suit additive aggregations such as SUM and COUNT.
+func (s *Service) includedAllowance(
+	ctx context.Context,
+	repoTx ratingdomain.Repository,
+	cycle *ratingdomain.BillingCycleRow,
+	subscription *subscriptiondomain.Subscription,
+	item ratingdomain.SubscriptionItemRow,
+	usageStart time.Time,
+) (float64, error) {
+	if item.IncludedQuantity == nil || *item.IncludedQuantity <= 0 || item.MeterID == nil {
+		return 0, nil
+	}
+	allowance := *item.IncludedQuantity
+
+	interval, err := repoTx.MeterResetInterval(ctx, cycle.OrgID, *item.MeterID)
+	if err != nil {
+		return 0, err
+	}
+	if interval != meterdomain.ResetIntervalNever || !usageStart.After(subscription.StartAt) {
+		return allowance, nil
+	}
+
+	used, err := repoTx.AggregateUsage(ctx, cycle.OrgID, cycle.SubscriptionID, *item.MeterID, subscription.StartAt, usageStart, item.Dimension())
+	if err != nil {
+		return 0, err
+	}
+	return math.Max(0, allowance-used), nil
+}
+
+// insertIncludedUsage records the usage covered by the allowance at a zero
+// price so the invoice shows what was included.
+func (s *Service) insertIncludedUsage(
+	ctx context.Context,
+	tx *gorm.DB,
+	w *ratingWriter,
+	cycle *ratingdomain.BillingCycleRow,
+	item ratingdomain.SubscriptionItemRow,
+	window priceWindow,
+	quantity float64,
+	featureCode string,
+	currency string,
+	now time.Time,
+) error {
+	checksum := buildRatingChecksum(cycle.ID, cycle.SubscriptionID, item.PriceID, item.MeterID, item.Dimension(), ratingdomain.RatingSourceIncluded+"|"+featureCode, window.Start, window.End)
+
+	return w.write(ctx, ratingdomain.RatingResult{
+		ID:             s.genID.Generate(),
+		OrgID:          cycle.OrgID,
+		SubscriptionID: cycle.SubscriptionID,
+		BillingCycleID: cycle.ID,
+		BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
+		MeterID:        item.MeterID,
+		PriceID:        item.PriceID,
+		FeatureCode:    featureCode,
+		Quantity:       quantity,
+		UnitPrice:      0,
+		Amount:         0,
+		Currency:       currency,
+		PeriodStart:    window.Start,
+		PeriodEnd:      window.End,
+		Source:         ratingdomain.RatingSourceIncluded,
+		Checksum:       checksum,
+		CreatedAt:      now,
+	})
+}
+
 type priceWindow struct {
 	Start  time.Time
 	End    time.Time
@@ -474,6 +699,44 @@ func (s *Service) loadOrgCurrency(ctx context.Context, tx *gorm.DB, orgID snowfl
 	return strings.ToUpper(strings.TrimSpace(row.Currency)), nil
 }
 
+// loadRoundingMode returns the organization's rounding mode, falling back
+// to the default when none is configured.
+func (s *Service) loadRoundingMode(ctx context.Context, tx *gorm.DB, orgID snowflake.ID) (organizationdomain.RoundingMode, error) {
+	var row struct {
+		RoundingMode string `gorm:"column:rounding_mode"`
+	}
+	if err := tx.WithContext(ctx).Raw(
+		`SELECT rounding_mode FROM organization_billing_preferences WHERE org_id = ? LIMIT 1`,
+		orgID,
+	).Scan(&row).Error; err != nil {
+		return "", err
+	}
+	mode := organizationdomain.RoundingMode(strings.ToLower(strings.TrimSpace(row.RoundingMode)))
+	if !mode.Valid() {
+		return organizationdomain.DefaultRoundingMode, nil
+	}
+	return mode, nil
+}
+
+// loadOrgTier returns the organization's tier for labeling metrics. It is
+// read outside the rating transaction and a failed lookup only loses the
+// label.
+func (s *Service) loadOrgTier(ctx context.Context, orgID snowflake.ID) string {
+	if orgID == 0 {
+		return ""
+	}
+	var row struct {
+		Metadata datatypes.JSONMap `gorm:"column:metadata"`
+	}
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT metadata FROM organizations WHERE id = ? LIMIT 1`,
+		orgID,
+	).Scan(&row).Error; err != nil {
+		return ""
+	}
+	return organizationdomain.TierFromMetadata(row.Metadata)
+}
+
 func (s *Service) listPriceTiers(ctx context.Context, tx *gorm.DB, orgID, priceID snowflake.ID) ([]pricetierdomain.PriceTier, error) {
 	var tiers []pricetierdomain.PriceTier
 	if err := tx.WithContext(ctx).Raw(
@@ -492,6 +755,7 @@ func (s *Service) listPriceTiers(ctx context.Context, tx *gorm.DB, orgID, priceI
 func (s *Service) insertTieredRating(
 	ctx context.Context,
 	tx *gorm.DB,
+	w *ratingWriter,
 	cycle *ratingdomain.BillingCycleRow,
 	item ratingdomain.SubscriptionItemRow,
 	window priceWindow,
@@ -511,14 +775,14 @@ func (s *Service) insertTieredRating(
 		source = "tiered_graduated"
 	}
 
-	checksum := buildRatingChecksum(cycle.ID, cycle.SubscriptionID, item.PriceID, item.MeterID, featureCode, window.Start, window.End)
+	checksum := buildRatingChecksum(cycle.ID, cycle.SubscriptionID, item.PriceID, item.MeterID, item.Dimension(), featureCode, window.Start, window.End)
 
-	repoTx := repository.NewRepository(tx)
-	return repoTx.InsertRatingResult(ctx, ratingdomain.RatingResult{
+	return w.write(ctx, ratingdomain.RatingResult{
 		ID:             s.genID.Generate(),
 		OrgID:          cycle.OrgID,
 		SubscriptionID: cycle.SubscriptionID,
 		BillingCycleID: cycle.ID,
+		BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
 		MeterID:        item.MeterID,
 		PriceID:        item.PriceID,
 		FeatureCode:    featureCode,
@@ -534,23 +798,25 @@ func (s *Service) insertTieredRating(
 	})
 }
 
-func calculateTieredVolumeAmount(quantity float64, tiers []pricetierdomain.PriceTier) (int64, int64, error) {
+func calculateTieredVolumeAmount(quantity float64, tiers []pricetierdomain.PriceTier, rounding organizationdomain.RoundingMode) (int64, int64, error) {
 	if quantity <= 0 {
 		return 0, 0, nil
 	}
 	sorted := append([]pricetierdomain.PriceTier(nil), tiers...)
 	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartQuantity < sorted[j].StartQuantity })
+	if !pricetierdomain.TiersContiguous(sorted) {
+		return 0, 0, ratingdomain.ErrMissingPriceTier
+	}
 
+	// Tiers cover (start, end], so a quantity on a boundary prices at the
+	// lower tier; the open-ended last tier takes everything above it.
 	var matched *pricetierdomain.PriceTier
 	for i := range sorted {
 		tier := sorted[i]
-		if quantity < tier.StartQuantity {
-			continue
-		}
-		if tier.EndQuantity != nil && quantity > *tier.EndQuantity {
-			continue
+		if quantity > tier.StartQuantity && (tier.EndQuantity == nil || quantity <= *tier.EndQuantity) {
+			matched = &tier
+			break
 		}
-		matched = &tier
 	}
 	if matched == nil {
 		return 0, 0, ratingdomain.ErrMissingPriceTier
@@ -558,7 +824,7 @@ func calculateTieredVolumeAmount(quantity float64, tiers []pricetierdomain.Price
 
 	var amount int64
 	if matched.UnitAmountCents != nil {
-		amount += roundRatingAmount(quantity * float64(*matched.UnitAmountCents))
+		amount += roundRatingAmount(quantity*float64(*matched.UnitAmountCents), rounding)
 	}
 	if matched.FlatAmountCents != nil {
 		amount += *matched.FlatAmountCents
@@ -566,12 +832,12 @@ func calculateTieredVolumeAmount(quantity float64, tiers []pricetierdomain.Price
 
 	unitPrice := int64(0)
 	if quantity > 0 {
-		unitPrice = roundRatingAmount(float64(amount) / quantity)
+		unitPrice = roundRatingAmount(float64(amount)/quantity, rounding)
 	}
 	return amount, unitPrice, nil
 }
 
-func calculateTieredGraduatedAmount(quantity float64, tiers []pricetierdomain.PriceTier) (int64, int64, error) {
+func calculateTieredGraduatedAmount(quantity float64, tiers []pricetierdomain.PriceTier, rounding organizationdomain.RoundingMode) (int64, int64, error) {
 	if quantity <= 0 {
 		return 0, 0, nil
 	}
@@ -591,7 +857,7 @@ func calculateTieredGraduatedAmount(quantity float64, tiers []pricetierdomain.Pr
 		}
 		matched = true
 		if tier.UnitAmountCents != nil {
-			amount += roundRatingAmount(tierQty * float64(*tier.UnitAmountCents))
+			amount += roundRatingAmount(tierQty*float64(*tier.UnitAmountCents), rounding)
 		}
 		if tier.FlatAmountCents != nil {
 			amount += *tier.FlatAmountCents
@@ -604,23 +870,23 @@ func calculateTieredGraduatedAmount(quantity float64, tiers []pricetierdomain.Pr
 
 	unitPrice := int64(0)
 	if quantity > 0 {
-		unitPrice = roundRatingAmount(float64(amount) / quantity)
+		unitPrice = roundRatingAmount(float64(amount)/quantity, rounding)
 	}
 	return amount, unitPrice, nil
 }
 
+// tierQuantity returns the part of total that falls in the tier (start, end].
+// Tier bounds are boundaries rather than unit numbers, so fractional
+// quantities split exactly: 0-100 and 100-200 put 150.5 as 100 and 50.5.
 func tierQuantity(total float64, start float64, end *float64) float64 {
-	if total < start {
+	if total <= start {
 		return 0
 	}
 	upper := total
 	if end != nil && *end < upper {
 		upper = *end
 	}
-	if upper < start {
-		return 0
-	}
-	return math.Max(0, upper-start+1)
+	return math.Max(0, upper-start)
 }
 
 func appendEffectiveBoundaries(
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"github.com/railzwaylabs/railzway/internal/rating/repository"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"gorm.io/gorm"
+)
+
+// ratedUsage is the billable usage of one item from the start of a cycle.
+type ratedUsage struct {
+	Quantity    float64
+	Amount      int64
+	Currency    string
+	FeatureCode string
+}
+
+// RunThresholdRating bills the usage of an open cycle's metered items once it
+// reaches their billing threshold. Usage is rated from the start of the cycle
+// up to now exactly like the closing rating does; whatever earlier threshold
+// runs did not bill yet is written as a threshold phase result when it is at
+// least the item's threshold. Usage covered by an included allowance does not
+// count toward the threshold.
+func (s *Service) RunThresholdRating(ctx context.Context, billingCycleID string) error {
+	cycle, err := s.loadBillingCycle(ctx, billingCycleID)
+	if err != nil {
+		return err
+	}
+	if cycle.Status != billingcycledomain.BillingCycleStatusOpen {
+		return ratingdomain.ErrBillingCycleNotOpen
+	}
+	if s.orgGate != nil {
+		if err := s.orgGate.MustBeActive(ctx, cycle.OrgID); err != nil {
+			return err
+		}
+	}
+	if s.accounts != nil {
+		if err := s.accounts.EnsureAccounts(ctx, cycle.OrgID); err != nil {
+			return err
+		}
+	}
+
+	subscription, items, err := s.loadSubscriptionItems(ctx, cycle)
+	if err != nil {
+		return err
+	}
+
+	asOf := time.Now().UTC()
+	if asOf.After(cycle.PeriodEnd) {
+		asOf = cycle.PeriodEnd
+	}
+	if !asOf.After(cycle.PeriodStart) {
+		return nil
+	}
+
+	for _, item := range items {
+		if item.MeterID == nil || item.BillingThreshold == nil || *item.BillingThreshold <= 0 {
+			continue
+		}
+		usage, err := s.rateUsageToDate(ctx, cycle, subscription, item, asOf)
+		if err != nil {
+			return fmt.Errorf("threshold rating failed for item %s: %w", item.ID, err)
+		}
+		if usage == nil {
+			continue
+		}
+		if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return s.billThresholdUsage(ctx, tx, cycle, item, *usage, asOf)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rateUsageToDate rates one item over the cycle up to asOf in a transaction
+// that is rolled back, the way DryRunRating does, and totals its billable
+// usage. It returns nil when the item has no billable usage yet.
+func (s *Service) rateUsageToDate(
+	ctx context.Context,
+	cycle *ratingdomain.BillingCycleRow,
+	subscription *subscriptiondomain.Subscription,
+	item ratingdomain.SubscriptionItemRow,
+	asOf time.Time,
+) (*ratedUsage, error) {
+	toDate := *cycle
+	toDate.PeriodEnd = asOf
+
+	phase := billingcycledomain.BillingPhaseArrears
+	var usage *ratedUsage
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.rateCycle(ctx, tx, &toDate, subscription, []ratingdomain.SubscriptionItemRow{item}, phase); err != nil {
+			return err
+		}
+		results, err := repository.NewRepository(tx).ListRatingResults(ctx, cycle.ID, phase)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			if result.MeterID == nil || !isThresholdUsageSource(result.Source) {
+				continue
+			}
+			if usage == nil {
+				usage = &ratedUsage{Currency: result.Currency, FeatureCode: result.FeatureCode}
+			}
+			usage.Quantity += result.Quantity
+			usage.Amount += result.Amount
+		}
+		return errDryRunRollback
+	})
+	if !errors.Is(err, errDryRunRollback) {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// isThresholdUsageSource reports whether rows of a source are priced usage
+// that counts toward a billing threshold.
+func isThresholdUsageSource(source string) bool {
+	switch source {
+	case ratingdomain.RatingSourceProration,
+		ratingdomain.RatingSourceMinimumCommitment,
+		ratingdomain.RatingSourceDiscount,
+		ratingdomain.RatingSourceIncluded,
+		ratingdomain.RatingSourceThreshold:
+		return false
+	default:
+		return true
+	}
+}
+
+// billThresholdUsage writes the usage not billed yet as a threshold phase
+// result when it reaches the item's threshold, and moves the item's billed
+// totals up to the usage rated so far.
+func (s *Service) billThresholdUsage(
+	ctx context.Context,
+	tx *gorm.DB,
+	cycle *ratingdomain.BillingCycleRow,
+	item ratingdomain.SubscriptionItemRow,
+	usage ratedUsage,
+	asOf time.Time,
+) error {
+	repoTx := repository.NewRepository(tx)
+	billed, err := repoTx.FindThresholdUsageForUpdate(ctx, cycle.ID, item.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	record := ratingdomain.ThresholdUsage{
+		ID:                 s.genID.Generate(),
+		OrgID:              cycle.OrgID,
+		BillingCycleID:     cycle.ID,
+		SubscriptionItemID: item.ID,
+		Currency:           usage.Currency,
+		BilledThrough:      cycle.PeriodStart,
+		CreatedAt:          now,
+	}
+	if billed != nil {
+		record = *billed
+	}
+
+	quantity := usage.Quantity - record.BilledQuantity
+	amount := usage.Amount - record.BilledAmount
+	if quantity < *item.BillingThreshold || amount <= 0 {
+		return nil
+	}
+
+	periodStart := record.BilledThrough
+	if err := repoTx.UpsertRatingResult(ctx, ratingdomain.RatingResult{
+		ID:             s.genID.Generate(),
+		OrgID:          cycle.OrgID,
+		SubscriptionID: cycle.SubscriptionID,
+		BillingCycleID: cycle.ID,
+		BillingPhase:   string(billingcycledomain.BillingPhaseThreshold),
+		PriceID:        item.PriceID,
+		FeatureCode:    usage.FeatureCode,
+		MeterID:        item.MeterID,
+		Source:         ratingdomain.RatingSourceThreshold,
+		Quantity:       quantity,
+		UnitPrice:      int64(math.Round(float64(amount) / quantity)),
+		Amount:         amount,
+		Currency:       usage.Currency,
+		PeriodStart:    periodStart,
+		PeriodEnd:      asOf,
+		Checksum:       buildRatingChecksum(cycle.ID, cycle.SubscriptionID, item.PriceID, item.MeterID, item.Dimension(), ratingdomain.RatingSourceThreshold+"|"+usage.FeatureCode, periodStart, asOf),
+		CreatedAt:      now,
+	}); err != nil {
+		return err
+	}
+
+	record.BilledQuantity = usage.Quantity
+	record.BilledAmount = usage.Amount
+	record.BilledThrough = asOf
+	record.UpdatedAt = now
+	return repoTx.UpsertThresholdUsage(ctx, record)
+}
+
+// applyThresholdCredits deducts what threshold invoices already billed from
+// the closing rating, which rates the whole cycle's usage again. Items that
+// left the subscription are not rated at close, so their billed usage stands
+// and gets no credit.
+func (s *Service) applyThresholdCredits(
+	ctx context.Context,
+	tx *gorm.DB,
+	w *ratingWriter,
+	cycle *ratingdomain.BillingCycleRow,
+	items []ratingdomain.SubscriptionItemRow,
+	entitlements []subscriptiondomain.SubscriptionEntitlement,
+	now time.Time,
+) error {
+	usages, err := repository.NewRepository(tx).ListThresholdUsages(ctx, cycle.ID)
+	if err != nil {
+		return err
+	}
+	if len(usages) == 0 {
+		return nil
+	}
+
+	itemsByID := make(map[snowflake.ID]ratingdomain.SubscriptionItemRow, len(items))
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+
+	for _, usage := range usages {
+		item, ok := itemsByID[usage.SubscriptionItemID]
+		if !ok || usage.BilledAmount == 0 || usage.BilledQuantity == 0 {
+			continue
+		}
+		featureCode, _, err := s.resolveEntitlementWithWindow(ctx, tx, item, entitlements)
+		if err != nil {
+			return err
+		}
+
+		if err := w.write(ctx, ratingdomain.RatingResult{
+			ID:             s.genID.Generate(),
+			OrgID:          cycle.OrgID,
+			SubscriptionID: cycle.SubscriptionID,
+			BillingCycleID: cycle.ID,
+			BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
+			PriceID:        item.PriceID,
+			FeatureCode:    featureCode,
+			MeterID:        item.MeterID,
+			Source:         ratingdomain.RatingSourceThreshold,
+			Quantity:       -usage.BilledQuantity,
+			UnitPrice:      int64(math.Round(float64(usage.BilledAmount) / usage.BilledQuantity)),
+			Amount:         -usage.BilledAmount,
+			Currency:       usage.Currency,
+			PeriodStart:    cycle.PeriodStart,
+			PeriodEnd:      usage.BilledThrough,
+			Checksum:       buildThresholdCreditChecksum(cycle.ID, item.ID),
+			CreatedAt:      now,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildThresholdCreditChecksum(cycleID, itemID snowflake.ID) string {
+	payload := fmt.Sprintf("threshold_credit|%s|%s", cycleID.String(), itemID.String())
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
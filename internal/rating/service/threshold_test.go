@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestThresholdRating_BillsCrossedUsageAndCreditsItAtClose verifies that usage
+// is billed mid-cycle each time it grows by the item's threshold, and that the
+// closing rating only bills what the threshold results did not.
+func TestThresholdRating_BillsCrossedUsageAndCreditsItAtClose(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	productID := node.Generate()
+	priceID := node.Generate()
+	meterID := node.Generate()
+	now := time.Now().UTC()
+	cycleStart := now.Add(-10 * 24 * time.Hour)
+	cycleEnd := now.Add(20 * 24 * time.Hour)
+
+	require.NoError(t, db.Create(&meterdomain.Meter{
+		ID:          meterID,
+		OrgID:       orgID,
+		Code:        "api_calls_" + meterID.String(),
+		Name:        "API calls",
+		Aggregation: "SUM",
+		Unit:        "call",
+		Active:      true,
+	}).Error)
+	require.NoError(t, db.Create(&pricedomain.Price{
+		ID:           priceID,
+		OrgID:        orgID,
+		ProductID:    productID,
+		Code:         "api_" + priceID.String(),
+		PricingModel: pricedomain.PerUnit,
+		Active:       true,
+	}).Error)
+	priceAmountStub.Amounts[priceID.String()] = priceamountdomain.PriceAmount{
+		PriceID:         priceID,
+		UnitAmountCents: 100,
+		Currency:        "USD",
+	}
+
+	currency := "USD"
+	require.NoError(t, db.Create(&subscriptiondomain.Subscription{
+		ID:              subID,
+		OrgID:           orgID,
+		CustomerID:      node.Generate(),
+		Status:          subscriptiondomain.SubscriptionStatusActive,
+		StartAt:         cycleStart,
+		DefaultCurrency: &currency,
+	}).Error)
+	threshold := 100.0
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionItem{
+		ID:               node.Generate(),
+		OrgID:            orgID,
+		SubscriptionID:   subID,
+		PriceID:          priceID,
+		MeterID:          &meterID,
+		Quantity:         1,
+		BillingMode:      "METERED",
+		BillingThreshold: &threshold,
+	}).Error)
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID:             cycleID,
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PeriodStart:    cycleStart,
+		PeriodEnd:      cycleEnd,
+		Status:         billingcycledomain.BillingCycleStatusOpen,
+	}).Error)
+
+	record := func(value float64, daysAgo int) {
+		require.NoError(t, db.Create(&usagedomain.UsageEvent{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			MeterID:        meterID,
+			SubscriptionID: subID,
+			Value:          value,
+			RecordedAt:     now.Add(-time.Duration(daysAgo) * 24 * time.Hour),
+			Status:         usagedomain.UsageStatusEnriched,
+		}).Error)
+	}
+	thresholdResults := func() []ratingdomain.RatingResult {
+		var results []ratingdomain.RatingResult
+		require.NoError(t, db.Where("billing_cycle_id = ? AND billing_phase = ?", cycleID, billingcycledomain.BillingPhaseThreshold).
+			Order("created_at ASC").Find(&results).Error)
+		return results
+	}
+	ctx := context.Background()
+
+	record(60, 9)
+	require.NoError(t, svc.RunThresholdRating(ctx, cycleID.String()))
+	assert.Empty(t, thresholdResults())
+
+	record(80, 8)
+	require.NoError(t, svc.RunThresholdRating(ctx, cycleID.String()))
+	results := thresholdResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, ratingdomain.RatingSourceThreshold, results[0].Source)
+	assert.Equal(t, 140.0, results[0].Quantity)
+	assert.Equal(t, int64(14000), results[0].Amount)
+
+	// Nothing new was used, and 50 more calls stay under the threshold.
+	require.NoError(t, svc.RunThresholdRating(ctx, cycleID.String()))
+	record(50, 7)
+	require.NoError(t, svc.RunThresholdRating(ctx, cycleID.String()))
+	assert.Len(t, thresholdResults(), 1)
+
+	record(70, 6)
+	require.NoError(t, svc.RunThresholdRating(ctx, cycleID.String()))
+	results = thresholdResults()
+	require.Len(t, results, 2)
+	assert.Equal(t, 120.0, results[1].Quantity)
+	assert.Equal(t, int64(12000), results[1].Amount)
+
+	record(30, 5)
+	require.NoError(t, db.Model(&billingcycledomain.BillingCycle{}).Where("id = ?", cycleID).
+		Update("status", billingcycledomain.BillingCycleStatusClosing).Error)
+	require.NoError(t, svc.RunRating(ctx, cycleID.String()))
+
+	var arrears []ratingdomain.RatingResult
+	require.NoError(t, db.Where("billing_cycle_id = ? AND billing_phase = ?", cycleID, billingcycledomain.BillingPhaseArrears).
+		Order("amount ASC").Find(&arrears).Error)
+	require.Len(t, arrears, 2)
+	assert.Equal(t, ratingdomain.RatingSourceThreshold, arrears[0].Source)
+	assert.Equal(t, -260.0, arrears[0].Quantity)
+	assert.Equal(t, int64(-26000), arrears[0].Amount)
+	assert.Equal(t, 290.0, arrears[1].Quantity)
+	assert.Equal(t, int64(29000), arrears[1].Amount)
+
+	err := svc.RunThresholdRating(ctx, cycleID.String())
+	assert.ErrorIs(t, err, ratingdomain.ErrBillingCycleNotOpen)
+}
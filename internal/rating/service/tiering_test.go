@@ -3,7 +3,9 @@ package service
 import (
 	"testing"
 
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	pricetierdomain "github.com/railzwaylabs/railzway/internal/pricetier/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -11,49 +13,166 @@ import (
 func TestTieredVolumeAmount(t *testing.T) {
 	tiers := []pricetierdomain.PriceTier{
 		{
-			StartQuantity:   1,
+			StartQuantity:   0,
 			EndQuantity:     floatPtr(100),
 			UnitAmountCents: int64Ptr(10),
 		},
 		{
-			StartQuantity:   101,
+			StartQuantity:   100,
 			EndQuantity:     nil,
 			UnitAmountCents: int64Ptr(8),
 			FlatAmountCents: int64Ptr(100),
 		},
 	}
 
-	amount, unitPrice, err := calculateTieredVolumeAmount(150, tiers)
+	amount, unitPrice, err := calculateTieredVolumeAmount(150, tiers, organizationdomain.RoundingModeHalfUp)
 	require.NoError(t, err)
 	assert.Equal(t, int64(1300), amount) // 150*8 + 100 flat
 	assert.Equal(t, int64(9), unitPrice) // round(1300/150) = 9
 }
 
+func TestTieredVolumeAmount_Boundaries(t *testing.T) {
+	tiers := []pricetierdomain.PriceTier{
+		{
+			StartQuantity:   0,
+			EndQuantity:     floatPtr(100),
+			UnitAmountCents: int64Ptr(10),
+		},
+		{
+			StartQuantity:   100,
+			EndQuantity:     floatPtr(200),
+			UnitAmountCents: int64Ptr(8),
+		},
+		{
+			StartQuantity:   200,
+			EndQuantity:     nil,
+			UnitAmountCents: int64Ptr(6),
+		},
+	}
+
+	cases := []struct {
+		name       string
+		quantity   float64
+		wantAmount int64
+	}{
+		{"inside first tier", 50, 500},
+		{"on first boundary", 100, 1000},
+		{"just past first boundary", 100.5, 804},
+		{"on second boundary", 200, 1600},
+		{"open-ended last tier", 10000, 60000},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, _, err := calculateTieredVolumeAmount(tc.quantity, tiers, organizationdomain.RoundingModeHalfUp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantAmount, amount)
+		})
+	}
+}
+
+func TestTieredVolumeAmount_RejectsNonContiguousTiers(t *testing.T) {
+	cases := []struct {
+		name  string
+		tiers []pricetierdomain.PriceTier
+	}{
+		{"does not start at zero", []pricetierdomain.PriceTier{
+			{StartQuantity: 1, EndQuantity: floatPtr(100), UnitAmountCents: int64Ptr(10)},
+			{StartQuantity: 100, UnitAmountCents: int64Ptr(8)},
+		}},
+		{"gap between tiers", []pricetierdomain.PriceTier{
+			{StartQuantity: 0, EndQuantity: floatPtr(100), UnitAmountCents: int64Ptr(10)},
+			{StartQuantity: 150, UnitAmountCents: int64Ptr(8)},
+		}},
+		{"overlapping tiers", []pricetierdomain.PriceTier{
+			{StartQuantity: 0, EndQuantity: floatPtr(100), UnitAmountCents: int64Ptr(10)},
+			{StartQuantity: 50, UnitAmountCents: int64Ptr(8)},
+		}},
+		{"open-ended tier before the last", []pricetierdomain.PriceTier{
+			{StartQuantity: 0, UnitAmountCents: int64Ptr(10)},
+			{StartQuantity: 100, EndQuantity: floatPtr(200), UnitAmountCents: int64Ptr(8)},
+		}},
+		{"bounded last tier below quantity", []pricetierdomain.PriceTier{
+			{StartQuantity: 0, EndQuantity: floatPtr(100), UnitAmountCents: int64Ptr(10)},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := calculateTieredVolumeAmount(150, tc.tiers, organizationdomain.RoundingModeHalfUp)
+			assert.ErrorIs(t, err, ratingdomain.ErrMissingPriceTier)
+		})
+	}
+}
+
 func TestTieredGraduatedAmount(t *testing.T) {
 	tiers := []pricetierdomain.PriceTier{
 		{
-			StartQuantity:   1,
+			StartQuantity:   0,
 			EndQuantity:     floatPtr(100),
 			UnitAmountCents: int64Ptr(10),
 		},
 		{
-			StartQuantity:   101,
+			StartQuantity:   100,
 			EndQuantity:     floatPtr(200),
 			UnitAmountCents: int64Ptr(8),
 		},
 		{
-			StartQuantity:   201,
+			StartQuantity:   200,
 			EndQuantity:     nil,
 			UnitAmountCents: int64Ptr(6),
 		},
 	}
 
-	amount, unitPrice, err := calculateTieredGraduatedAmount(250, tiers)
+	amount, unitPrice, err := calculateTieredGraduatedAmount(250, tiers, organizationdomain.RoundingModeHalfUp)
 	require.NoError(t, err)
 	assert.Equal(t, int64(2100), amount) // 100*10 + 100*8 + 50*6
 	assert.Equal(t, int64(8), unitPrice) // round(2100/250) = 8
 }
 
+func TestTieredGraduatedAmount_FractionalQuantities(t *testing.T) {
+	// Per-GB pricing: 0-10 GB at 100, 10-50 GB at 80, above 50 GB at 50 plus
+	// a 200 flat fee once the last tier is reached.
+	tiers := []pricetierdomain.PriceTier{
+		{
+			StartQuantity:   0,
+			EndQuantity:     floatPtr(10),
+			UnitAmountCents: int64Ptr(100),
+		},
+		{
+			StartQuantity:   10,
+			EndQuantity:     floatPtr(50),
+			UnitAmountCents: int64Ptr(80),
+		},
+		{
+			StartQuantity:   50,
+			EndQuantity:     nil,
+			UnitAmountCents: int64Ptr(50),
+			FlatAmountCents: int64Ptr(200),
+		},
+	}
+
+	cases := []struct {
+		name       string
+		quantity   float64
+		wantAmount int64
+	}{
+		{"fraction of first tier", 0.5, 50},
+		{"first tier upper bound", 10, 1000},
+		{"fraction into second tier", 10.5, 1040},                 // 10*100 + 0.5*80
+		{"second tier upper bound", 50, 4200},                     // 10*100 + 40*80
+		{"fraction into third tier", 50.25, 4413},                 // 4200 + round(0.25*50) + 200
+		{"spans all three tiers", 72.75, 5538},                    // 4200 + round(22.75*50) + 200
+		{"sub-unit past the last boundary", 50.001, 4400},         // 4200 + round(0.05) + 200
+		{"large fractional quantity", 1000.5, 4200 + 47525 + 200}, // 950.5*50
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, _, err := calculateTieredGraduatedAmount(tc.quantity, tiers, organizationdomain.RoundingModeHalfUp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantAmount, amount)
+		})
+	}
+}
+
 func floatPtr(v float64) *float64 {
 	return &v
 }
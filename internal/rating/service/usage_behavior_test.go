@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUsageBehavior_AdvanceFlatAtOpenUsageAtClose verifies that a flat item
+// configured with the advance usage behavior is rated when the cycle opens,
+// while metered usage is rated in arrears when the cycle closes.
+func TestUsageBehavior_AdvanceFlatAtOpenUsageAtClose(t *testing.T) {
+	db, svc, node := setupProrationTest(t)
+
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	flatProductID := node.Generate()
+	flatPriceID := node.Generate()
+	usageProductID := node.Generate()
+	usagePriceID := node.Generate()
+	meterID := node.Generate()
+
+	cycleStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	cycleEnd := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID:             cycleID,
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PeriodStart:    cycleStart,
+		PeriodEnd:      cycleEnd,
+		Status:         billingcycledomain.BillingCycleStatusOpen,
+	}).Error)
+
+	currency := "USD"
+	require.NoError(t, db.Create(&subscriptiondomain.Subscription{
+		ID:              subID,
+		OrgID:           orgID,
+		CustomerID:      node.Generate(),
+		Status:          subscriptiondomain.SubscriptionStatusActive,
+		StartAt:         cycleStart,
+		DefaultCurrency: &currency,
+	}).Error)
+
+	advance := subscriptiondomain.UsageBehaviorAdvance
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionItem{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PriceID:        flatPriceID,
+		Quantity:       1,
+		BillingMode:    "LICENSED",
+		UsageBehavior:  &advance,
+	}).Error)
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionItem{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PriceID:        usagePriceID,
+		MeterID:        &meterID,
+		Quantity:       1,
+		BillingMode:    "METERED",
+	}).Error)
+
+	require.NoError(t, db.Create(&pricedomain.Price{
+		ID:           flatPriceID,
+		OrgID:        orgID,
+		ProductID:    flatProductID,
+		Code:         "platform_fee",
+		PricingModel: pricedomain.Flat,
+		BillingMode:  pricedomain.Licensed,
+		Active:       true,
+	}).Error)
+	require.NoError(t, db.Create(&pricedomain.Price{
+		ID:           usagePriceID,
+		OrgID:        orgID,
+		ProductID:    usageProductID,
+		Code:         "api_calls",
+		PricingModel: pricedomain.PerUnit,
+		BillingMode:  pricedomain.Metered,
+		Active:       true,
+	}).Error)
+
+	priceAmountStub := svc.(*Service).priceAmountRepo.(*priceAmountStub)
+	priceAmountStub.Amounts[flatPriceID.String()] = priceamountdomain.PriceAmount{
+		PriceID:         flatPriceID,
+		UnitAmountCents: 5000,
+		Currency:        "USD",
+	}
+	priceAmountStub.Amounts[usagePriceID.String()] = priceamountdomain.PriceAmount{
+		PriceID:         usagePriceID,
+		MeterID:         &meterID,
+		UnitAmountCents: 10,
+		Currency:        "USD",
+	}
+
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionEntitlement{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		ProductID:      flatProductID,
+		FeatureCode:    "platform",
+		EffectiveFrom:  cycleStart,
+	}).Error)
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionEntitlement{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		ProductID:      usageProductID,
+		FeatureCode:    "api_calls",
+		MeterID:        &meterID,
+		EffectiveFrom:  cycleStart,
+	}).Error)
+
+	require.NoError(t, db.Create(&usagedomain.UsageEvent{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		MeterID:        meterID,
+		SubscriptionID: subID,
+		Value:          42,
+		RecordedAt:     cycleStart.Add(48 * time.Hour),
+		Status:         usagedomain.UsageStatusEnriched,
+	}).Error)
+
+	ctx := context.Background()
+
+	// Arrears rating is not allowed while the cycle is open.
+	err := svc.RunRating(ctx, cycleID.String())
+	assert.ErrorIs(t, err, ratingdomain.ErrBillingCycleNotClosing)
+
+	// Opening run: only the advance flat charge is rated.
+	require.NoError(t, svc.RunAdvanceRating(ctx, cycleID.String()))
+
+	var opening []ratingdomain.RatingResult
+	require.NoError(t, db.Where("billing_cycle_id = ?", cycleID).Find(&opening).Error)
+	require.Len(t, opening, 1)
+	assert.Equal(t, string(billingcycledomain.BillingPhaseAdvance), opening[0].BillingPhase)
+	assert.Equal(t, flatPriceID, opening[0].PriceID)
+	assert.Equal(t, "flat_rate", opening[0].Source)
+	assert.Equal(t, int64(5000), opening[0].Amount)
+
+	// Re-running the opening phase is idempotent.
+	require.NoError(t, svc.RunAdvanceRating(ctx, cycleID.String()))
+	var rerun []ratingdomain.RatingResult
+	require.NoError(t, db.Where("billing_cycle_id = ?", cycleID).Find(&rerun).Error)
+	require.Len(t, rerun, 1)
+
+	// Closing run: usage is rated in arrears and the advance row is kept.
+	require.NoError(t, db.Model(&billingcycledomain.BillingCycle{}).
+		Where("id = ?", cycleID).
+		Update("status", billingcycledomain.BillingCycleStatusClosing).Error)
+
+	err = svc.RunAdvanceRating(ctx, cycleID.String())
+	assert.ErrorIs(t, err, ratingdomain.ErrBillingCycleNotOpen)
+
+	require.NoError(t, svc.RunRating(ctx, cycleID.String()))
+
+	var closing []ratingdomain.RatingResult
+	require.NoError(t, db.Where("billing_cycle_id = ? AND billing_phase = ?", cycleID, billingcycledomain.BillingPhaseArrears).Find(&closing).Error)
+	require.Len(t, closing, 1)
+	assert.Equal(t, usagePriceID, closing[0].PriceID)
+	assert.Equal(t, float64(42), closing[0].Quantity)
+	assert.Equal(t, int64(420), closing[0].Amount)
+
+	var advanceRows []ratingdomain.RatingResult
+	require.NoError(t, db.Where("billing_cycle_id = ? AND billing_phase = ?", cycleID, billingcycledomain.BillingPhaseAdvance).Find(&advanceRows).Error)
+	require.Len(t, advanceRows, 1)
+	assert.Equal(t, opening[0].Checksum, advanceRows[0].Checksum)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+)
+
+// ReportRequest selects the window to reconcile. Ledger entries are taken by
+// occurred_at and invoices by finalized_at, both in [From, To).
+type ReportRequest struct {
+	From time.Time
+	To   time.Time
+}
+
+// Report compares the accounts receivable posted for invoices against the
+// finalized invoice totals of each customer. Only customers that do not
+// balance are listed.
+type Report struct {
+	From          time.Time             `json:"from"`
+	To            time.Time             `json:"to"`
+	Balanced      bool                  `json:"balanced"`
+	LedgerTotal   int64                 `json:"ledger_total"`
+	InvoiceTotal  int64                 `json:"invoice_total"`
+	Discrepancies []CustomerDiscrepancy `json:"discrepancies"`
+}
+
+// CustomerDiscrepancy holds the totals of one customer and currency together
+// with the entries and invoices that explain the difference.
+type CustomerDiscrepancy struct {
+	CustomerID    snowflake.ID `json:"customer_id"`
+	Currency      string       `json:"currency"`
+	LedgerAmount  int64        `json:"ledger_amount"`
+	InvoiceAmount int64        `json:"invoice_amount"`
+	// Difference is LedgerAmount - InvoiceAmount.
+	Difference int64         `json:"difference"`
+	Entries    []EntryItem   `json:"entries"`
+	Invoices   []InvoiceItem `json:"invoices"`
+}
+
+// EntryItem is an invoice posting that has no matching finalized invoice in
+// the window, or whose amount differs from the invoice it belongs to.
+type EntryItem struct {
+	EntryID    snowflake.ID  `json:"entry_id"`
+	SourceID   snowflake.ID  `json:"source_id"`
+	InvoiceID  *snowflake.ID `json:"invoice_id,omitempty"`
+	Amount     int64         `json:"amount"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+// InvoiceItem is a finalized invoice without a matching posting in the
+// window, or whose total differs from what was posted for it.
+type InvoiceItem struct {
+	InvoiceID     snowflake.ID `json:"invoice_id"`
+	InvoiceNumber string       `json:"invoice_number"`
+	TotalAmount   int64        `json:"total_amount"`
+	PostedAmount  int64        `json:"posted_amount"`
+	FinalizedAt   time.Time    `json:"finalized_at"`
+}
+
+// Service reconciles the ledger against invoices.
+type Service interface {
+	GetReport(ctx context.Context, req ReportRequest) (Report, error)
+}
+
+var (
+	ErrInvalidOrganization = errors.New("invalid_organization")
+	ErrInvalidRange        = errors.New("invalid_range")
+)
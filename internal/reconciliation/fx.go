@@ -0,0 +1,10 @@
+package reconciliation
+
+import (
+	"github.com/railzwaylabs/railzway/internal/reconciliation/service"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("reconciliation.service",
+	fx.Provide(service.NewService),
+)
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	reconciliationdomain "github.com/railzwaylabs/railzway/internal/reconciliation/domain"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Params struct {
+	fx.In
+
+	DB  *gorm.DB
+	Log *zap.Logger
+}
+
+type Service struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewService(p Params) reconciliationdomain.Service {
+	return &Service{
+		db:  p.DB,
+		log: p.Log.Named("reconciliation.service"),
+	}
+}
+
+// postingRow is the accounts receivable effect of one invoice posting.
+// Postings are keyed on the invoice by the invoice service and on the billing
+// cycle by the scheduler, so both are resolved.
+type postingRow struct {
+	EntryID    snowflake.ID  `gorm:"column:entry_id"`
+	SourceID   snowflake.ID  `gorm:"column:source_id"`
+	InvoiceID  *snowflake.ID `gorm:"column:invoice_id"`
+	CustomerID *snowflake.ID `gorm:"column:customer_id"`
+	Currency   string        `gorm:"column:currency"`
+	OccurredAt time.Time     `gorm:"column:occurred_at"`
+	Amount     int64         `gorm:"column:amount"`
+}
+
+type invoiceRow struct {
+	ID             snowflake.ID `gorm:"column:id"`
+	InvoiceNumber  string       `gorm:"column:invoice_number"`
+	BillingCycleID snowflake.ID `gorm:"column:billing_cycle_id"`
+	CustomerID     snowflake.ID `gorm:"column:customer_id"`
+	Currency       string       `gorm:"column:currency"`
+	TotalAmount    int64        `gorm:"column:total_amount"`
+	FinalizedAt    time.Time    `gorm:"column:finalized_at"`
+}
+
+type reconciliationKey struct {
+	customerID snowflake.ID
+	currency   string
+}
+
+// GetReport matches every invoice posting in the window to its finalized
+// invoice and reports, per customer and currency, the totals that differ and
+// the postings and invoices responsible.
+func (s *Service) GetReport(ctx context.Context, req reconciliationdomain.ReportRequest) (reconciliationdomain.Report, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return reconciliationdomain.Report{}, reconciliationdomain.ErrInvalidOrganization
+	}
+	from := req.From.UTC()
+	to := req.To.UTC()
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return reconciliationdomain.Report{}, reconciliationdomain.ErrInvalidRange
+	}
+
+	postings, err := s.listPostings(ctx, orgID, from, to)
+	if err != nil {
+		return reconciliationdomain.Report{}, err
+	}
+	invoices, err := s.listInvoices(ctx, orgID, from, to)
+	if err != nil {
+		return reconciliationdomain.Report{}, err
+	}
+
+	byID := make(map[snowflake.ID]int, len(invoices))
+	byCycle := make(map[snowflake.ID][]int, len(invoices))
+	for i, invoice := range invoices {
+		byID[invoice.ID] = i
+		byCycle[invoice.BillingCycleID] = append(byCycle[invoice.BillingCycleID], i)
+	}
+
+	posted := make([]int64, len(invoices))
+	matched := make([]bool, len(invoices))
+	postingInvoice := make([]int, len(postings))
+	for p, posting := range postings {
+		postingInvoice[p] = -1
+		if posting.InvoiceID != nil {
+			if i, ok := byID[*posting.InvoiceID]; ok {
+				postingInvoice[p] = i
+			}
+		} else {
+			for _, i := range byCycle[posting.SourceID] {
+				if !matched[i] {
+					postingInvoice[p] = i
+					break
+				}
+			}
+		}
+		if i := postingInvoice[p]; i >= 0 {
+			matched[i] = true
+			posted[i] += posting.Amount
+		}
+	}
+
+	report := reconciliationdomain.Report{
+		From:          from,
+		To:            to,
+		Discrepancies: []reconciliationdomain.CustomerDiscrepancy{},
+	}
+	groups := make(map[reconciliationKey]*reconciliationdomain.CustomerDiscrepancy)
+	group := func(customerID snowflake.ID, currency string) *reconciliationdomain.CustomerDiscrepancy {
+		key := reconciliationKey{customerID: customerID, currency: currency}
+		if g, ok := groups[key]; ok {
+			return g
+		}
+		g := &reconciliationdomain.CustomerDiscrepancy{
+			CustomerID: customerID,
+			Currency:   currency,
+			Entries:    []reconciliationdomain.EntryItem{},
+			Invoices:   []reconciliationdomain.InvoiceItem{},
+		}
+		groups[key] = g
+		return g
+	}
+
+	for p, posting := range postings {
+		var customerID snowflake.ID
+		if i := postingInvoice[p]; i >= 0 {
+			customerID = invoices[i].CustomerID
+		} else if posting.CustomerID != nil {
+			customerID = *posting.CustomerID
+		}
+		g := group(customerID, posting.Currency)
+		g.LedgerAmount += posting.Amount
+		report.LedgerTotal += posting.Amount
+
+		i := postingInvoice[p]
+		if i >= 0 && posted[i] == invoices[i].TotalAmount {
+			continue
+		}
+		item := reconciliationdomain.EntryItem{
+			EntryID:    posting.EntryID,
+			SourceID:   posting.SourceID,
+			InvoiceID:  posting.InvoiceID,
+			Amount:     posting.Amount,
+			OccurredAt: posting.OccurredAt,
+		}
+		if i >= 0 {
+			invoiceID := invoices[i].ID
+			item.InvoiceID = &invoiceID
+		}
+		g.Entries = append(g.Entries, item)
+	}
+
+	for i, invoice := range invoices {
+		g := group(invoice.CustomerID, invoice.Currency)
+		g.InvoiceAmount += invoice.TotalAmount
+		report.InvoiceTotal += invoice.TotalAmount
+		if matched[i] && posted[i] == invoice.TotalAmount {
+			continue
+		}
+		g.Invoices = append(g.Invoices, reconciliationdomain.InvoiceItem{
+			InvoiceID:     invoice.ID,
+			InvoiceNumber: invoice.InvoiceNumber,
+			TotalAmount:   invoice.TotalAmount,
+			PostedAmount:  posted[i],
+			FinalizedAt:   invoice.FinalizedAt,
+		})
+	}
+
+	for _, g := range groups {
+		g.Difference = g.LedgerAmount - g.InvoiceAmount
+		if g.Difference == 0 && len(g.Entries) == 0 && len(g.Invoices) == 0 {
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, *g)
+	}
+	sort.Slice(report.Discrepancies, func(a, b int) bool {
+		if report.Discrepancies[a].CustomerID != report.Discrepancies[b].CustomerID {
+			return report.Discrepancies[a].CustomerID < report.Discrepancies[b].CustomerID
+		}
+		return report.Discrepancies[a].Currency < report.Discrepancies[b].Currency
+	})
+	report.Balanced = len(report.Discrepancies) == 0
+
+	return report, nil
+}
+
+func (s *Service) listPostings(ctx context.Context, orgID snowflake.ID, from, to time.Time) ([]postingRow, error) {
+	var rows []postingRow
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT le.id AS entry_id, le.source_id, le.currency, le.occurred_at,
+		        i.id AS invoice_id,
+		        COALESCE(i.customer_id, s.customer_id) AS customer_id,
+		        SUM(CASE l.direction WHEN 'debit' THEN l.amount ELSE -l.amount END) AS amount
+		 FROM ledger_entries le
+		 JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
+		 JOIN ledger_accounts a ON a.id = l.account_id
+		 LEFT JOIN invoices i ON i.org_id = le.org_id AND i.id = le.source_id
+		 LEFT JOIN billing_cycles bc ON bc.org_id = le.org_id AND bc.id = le.source_id
+		 LEFT JOIN subscriptions s ON s.id = bc.subscription_id
+		 WHERE le.org_id = ?
+		   AND le.source_type = ?
+		   AND a.code = ?
+		   AND le.occurred_at >= ? AND le.occurred_at < ?
+		 GROUP BY le.id, le.source_id, le.currency, le.occurred_at, i.id, i.customer_id, s.customer_id
+		 ORDER BY le.occurred_at ASC, le.id ASC`,
+		orgID,
+		ledgerdomain.SourceTypeBillingCycle,
+		ledgerdomain.AccountCodeAccountsReceivable,
+		from,
+		to,
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *Service) listInvoices(ctx context.Context, orgID snowflake.ID, from, to time.Time) ([]invoiceRow, error) {
+	var rows []invoiceRow
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT id, invoice_number, billing_cycle_id, customer_id, currency, total_amount, finalized_at
+		 FROM invoices
+		 WHERE org_id = ?
+		   AND status = ?
+		   AND finalized_at >= ? AND finalized_at < ?
+		 ORDER BY finalized_at ASC, id ASC`,
+		orgID,
+		invoicedomain.InvoiceStatusFinalized,
+		from,
+		to,
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	ledgerservice "github.com/railzwaylabs/railzway/internal/ledger/service"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	reconciliationdomain "github.com/railzwaylabs/railzway/internal/reconciliation/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestGetReport_ReportsUnbalancedCustomers(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&billingcycledomain.BillingCycle{},
+		&invoicedomain.Invoice{},
+		&ledgerdomain.LedgerEntry{},
+		&ledgerdomain.LedgerEntryLine{},
+	))
+	require.NoError(t, db.Exec(`CREATE TABLE ledger_accounts (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		code TEXT NOT NULL,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX ux_ledger_entries_source
+		ON ledger_entries (org_id, source_type, source_id)`).Error)
+
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	orgID := node.Generate()
+	now := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	accounts := map[ledgerdomain.LedgerAccountCode]snowflake.ID{}
+	for _, code := range []ledgerdomain.LedgerAccountCode{
+		ledgerdomain.AccountCodeAccountsReceivable,
+		ledgerdomain.AccountCodeRevenueFlat,
+	} {
+		accounts[code] = node.Generate()
+		require.NoError(t, db.Exec(
+			`INSERT INTO ledger_accounts (id, org_id, code, name, created_at) VALUES (?, ?, ?, ?, ?)`,
+			accounts[code], orgID, string(code), string(code), now,
+		).Error)
+	}
+
+	ledger := ledgerservice.NewService(ledgerservice.Params{DB: db, Log: zap.NewNop(), GenID: node})
+	ctx := context.Background()
+	post := func(sourceID snowflake.ID, occurredAt time.Time, amount int64) {
+		require.NoError(t, ledger.CreateEntry(ctx, orgID, string(ledgerdomain.SourceTypeBillingCycle), sourceID, "EUR", occurredAt, []ledgerdomain.LedgerEntryLine{
+			{AccountID: accounts[ledgerdomain.AccountCodeAccountsReceivable], Direction: ledgerdomain.LedgerEntryDirectionDebit, Currency: "EUR", Amount: amount},
+			{AccountID: accounts[ledgerdomain.AccountCodeRevenueFlat], Direction: ledgerdomain.LedgerEntryDirectionCredit, Currency: "EUR", Amount: amount},
+		}))
+	}
+	subscribe := func(customerID snowflake.ID) snowflake.ID {
+		sub := subscriptiondomain.Subscription{
+			ID:               node.Generate(),
+			OrgID:            orgID,
+			CustomerID:       customerID,
+			Status:           subscriptiondomain.SubscriptionStatusActive,
+			CollectionMode:   subscriptiondomain.SubscriptionCollectionModeSendInvoice,
+			StartAt:          now.AddDate(0, -3, 0),
+			BillingCycleType: "monthly",
+		}
+		require.NoError(t, db.Create(&sub).Error)
+		return sub.ID
+	}
+	cycle := func(subscriptionID snowflake.ID, periodEnd time.Time) snowflake.ID {
+		bc := billingcycledomain.BillingCycle{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subscriptionID,
+			PeriodStart:    periodEnd.AddDate(0, -1, 0),
+			PeriodEnd:      periodEnd,
+			Status:         billingcycledomain.BillingCycleStatusClosed,
+			Metadata:       datatypes.JSONMap{},
+		}
+		require.NoError(t, db.Create(&bc).Error)
+		return bc.ID
+	}
+	invoice := func(subscriptionID, customerID, cycleID snowflake.ID, number string, total int64, finalizedAt time.Time) snowflake.ID {
+		inv := invoicedomain.Invoice{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			InvoiceNumber:  number,
+			BillingCycleID: cycleID,
+			SubscriptionID: subscriptionID,
+			CustomerID:     customerID,
+			Status:         invoicedomain.InvoiceStatusFinalized,
+			TotalAmount:    total,
+			Currency:       "EUR",
+			FinalizedAt:    &finalizedAt,
+			Metadata:       datatypes.JSONMap{},
+		}
+		require.NoError(t, db.Create(&inv).Error)
+		return inv.ID
+	}
+
+	balanced := node.Generate()
+	short := node.Generate()
+	unposted := node.Generate()
+	balancedSub := subscribe(balanced)
+	shortSub := subscribe(short)
+	unpostedSub := subscribe(unposted)
+
+	// Posted against the billing cycle, as the scheduler does.
+	balancedCycle := cycle(balancedSub, now.AddDate(0, 0, -10))
+	invoice(balancedSub, balanced, balancedCycle, "INV-1", 1000, now.AddDate(0, 0, -10))
+	post(balancedCycle, now.AddDate(0, 0, -10), 1000)
+
+	// Posted against the invoice for less than its total.
+	shortCycle := cycle(shortSub, now.AddDate(0, 0, -5))
+	shortInvoice := invoice(shortSub, short, shortCycle, "INV-2", 500, now.AddDate(0, 0, -5))
+	post(shortInvoice, now.AddDate(0, 0, -5), 450)
+
+	// One finalized invoice never posted and one posting without an invoice.
+	unpostedCycle := cycle(unpostedSub, now.AddDate(0, 0, -3))
+	unpostedInvoice := invoice(unpostedSub, unposted, unpostedCycle, "INV-3", 700, now.AddDate(0, 0, -3))
+	orphanCycle := cycle(unpostedSub, now.AddDate(0, 0, -2))
+	post(orphanCycle, now.AddDate(0, 0, -2), 300)
+
+	// Outside the window.
+	laterCycle := cycle(balancedSub, now.AddDate(0, 1, 0))
+	post(laterCycle, now.AddDate(0, 1, 0), 999)
+
+	svc := NewService(Params{DB: db, Log: zap.NewNop()})
+	orgCtx := orgcontext.WithOrgID(ctx, int64(orgID))
+
+	_, err = svc.GetReport(orgCtx, reconciliationdomain.ReportRequest{From: now, To: now})
+	assert.ErrorIs(t, err, reconciliationdomain.ErrInvalidRange)
+
+	report, err := svc.GetReport(orgCtx, reconciliationdomain.ReportRequest{
+		From: now.AddDate(0, 0, -30),
+		To:   now,
+	})
+	require.NoError(t, err)
+	assert.False(t, report.Balanced)
+	assert.Equal(t, int64(1750), report.LedgerTotal)
+	assert.Equal(t, int64(2200), report.InvoiceTotal)
+	require.Len(t, report.Discrepancies, 2)
+
+	byCustomer := map[snowflake.ID]reconciliationdomain.CustomerDiscrepancy{}
+	for _, d := range report.Discrepancies {
+		byCustomer[d.CustomerID] = d
+	}
+	assert.NotContains(t, byCustomer, balanced)
+
+	shortReport := byCustomer[short]
+	assert.Equal(t, int64(-50), shortReport.Difference)
+	require.Len(t, shortReport.Entries, 1)
+	require.NotNil(t, shortReport.Entries[0].InvoiceID)
+	assert.Equal(t, shortInvoice, *shortReport.Entries[0].InvoiceID)
+	require.Len(t, shortReport.Invoices, 1)
+	assert.Equal(t, int64(450), shortReport.Invoices[0].PostedAmount)
+
+	unpostedReport := byCustomer[unposted]
+	assert.Equal(t, int64(300), unpostedReport.LedgerAmount)
+	assert.Equal(t, int64(700), unpostedReport.InvoiceAmount)
+	require.Len(t, unpostedReport.Entries, 1)
+	assert.Equal(t, orphanCycle, unpostedReport.Entries[0].SourceID)
+	assert.Nil(t, unpostedReport.Entries[0].InvoiceID)
+	require.Len(t, unpostedReport.Invoices, 1)
+	assert.Equal(t, unpostedInvoice, unpostedReport.Invoices[0].InvoiceID)
+	assert.Equal(t, int64(0), unpostedReport.Invoices[0].PostedAmount)
+}
@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/authorization"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	obsmetrics "github.com/railzwaylabs/railzway/internal/observability/metrics"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/zap"
+)
+
+// AdvanceBillingJob bills items configured with the advance usage behavior
+// when their billing cycle opens. Metered items and arrears items are still
+// billed by the close -> rating -> invoice pipeline.
+func (s *Scheduler) AdvanceBillingJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "advance_invoice", s.cfg.MaxInvoiceBatchSize)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	now := s.clock.Now(ctx)
+	var jobErr error
+
+	cycles, err := s.fetchBillingCyclesForWork(ctx,
+		`status = ? AND advance_invoiced_at IS NULL
+		 AND EXISTS (
+			 SELECT 1 FROM subscription_items si
+			 WHERE si.subscription_id = billing_cycles.subscription_id
+			   AND si.usage_behavior = ?
+		 )`,
+		[]any{billingcycledomain.BillingCycleStatusOpen, subscriptiondomain.UsageBehaviorAdvance},
+		s.cfg.MaxInvoiceBatchSize,
+	)
+	if err != nil {
+		s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "advance_invoice", 0, err)
+		return err
+	}
+
+	for _, cycle := range cycles {
+		s.logCycleClaimed(ctx, "advance_invoice", cycle)
+		if err := s.ensureOrgActive(ctx, cycle.OrgID); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.org.inactive", "advance_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+		if err := s.authorizeSystem(ctx, cycle.OrgID, authorization.ObjectBillingCycle, authorization.ActionBillingCycleRate); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.authorize.failed", "advance_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+		if err := s.authorizeSystem(ctx, cycle.OrgID, authorization.ObjectInvoice, authorization.ActionInvoiceGenerate); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.authorize.failed", "advance_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+
+		cycleCtx := s.withAuditContext(ctx, cycle.SubscriptionID.String(), cycle.ID.String())
+		if err := s.ratingSvc.RunAdvanceRating(cycleCtx, cycle.ID.String()); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "advance_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			_ = s.recordCycleErrorWithMetrics(ctx, cycle.ID, obsmetrics.CycleStageAdvanceInvoice, err)
+			continue
+		}
+
+		// ErrMissingRatingResults means there is nothing to bill up front (e.g. the
+		// advance item is not active yet); the cycle is still marked so it is not
+		// picked up again.
+		invoice, err := s.invoiceSvc.GenerateAdvanceInvoice(cycleCtx, cycle.ID.String())
+		if err != nil && !errors.Is(err, invoicedomain.ErrMissingRatingResults) {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "invoice.generate.failed", "advance_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			_ = s.recordCycleErrorWithMetrics(ctx, cycle.ID, obsmetrics.CycleStageAdvanceInvoice, err)
+			continue
+		}
+
+		if err := s.markCycleAdvanceInvoiced(ctx, cycle.ID, now); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "advance_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			_ = s.recordCycleErrorWithMetrics(ctx, cycle.ID, obsmetrics.CycleStageAdvanceInvoice, err)
+			continue
+		}
+		run.AddProcessed(1)
+
+		if invoice == nil {
+			continue
+		}
+		s.logInvoiceGenerated(ctx, cycle, invoice.ID)
+
+		if !s.cfg.FinalizeInvoices || invoice.Status != invoicedomain.InvoiceStatusDraft {
+			continue
+		}
+		if err := s.authorizeSystem(ctx, cycle.OrgID, authorization.ObjectInvoice, authorization.ActionInvoiceFinalize); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.authorize.failed", "advance_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("invoice_id", idString(invoice.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+		if _, err := s.invoiceSvc.FinalizeInvoice(cycleCtx, invoice.ID.String()); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "invoice.finalize.failed", "advance_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("invoice_id", idString(invoice.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			_ = s.recordCycleErrorWithMetrics(ctx, cycle.ID, obsmetrics.CycleStageAdvanceInvoice, err)
+			continue
+		}
+		s.logInvoiceFinalized(ctx, cycle, invoice.ID)
+	}
+
+	return jobErr
+}
+
+func (s *Scheduler) markCycleAdvanceInvoiced(ctx context.Context, cycleID snowflake.ID, now time.Time) error {
+	return s.db.WithContext(ctx).Exec(
+		`UPDATE billing_cycles
+		 SET advance_invoiced_at = COALESCE(advance_invoiced_at, ?),
+		     last_error = NULL,
+		     last_error_at = NULL,
+		     updated_at = ?
+		 WHERE id = ?`,
+		now,
+		now,
+		cycleID,
+	).Error
+}
+
+func (s *Scheduler) hasRatingResultsForPhase(ctx context.Context, cycleID snowflake.ID, phase billingcycledomain.BillingPhase) (bool, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT COUNT(1)
+		 FROM rating_results
+		 WHERE billing_cycle_id = ? AND billing_phase = ?`,
+		cycleID,
+		phase,
+	).Scan(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// AutoChargeRetryJob retries failed auto-charges on unpaid invoices. Backoff
+// and the stop conditions live in the invoice service.
+func (s *Scheduler) AutoChargeRetryJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "auto_charge_retry", s.cfg.BatchSize)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	if s.cfg.MaxAutoChargeRetries <= 0 {
+		return nil
+	}
+
+	retried, err := s.invoiceSvc.RetryFailedAutoCharges(ctx, s.cfg.MaxAutoChargeRetries, s.cfg.BatchSize)
+	if err != nil {
+		s.logSchedulerError(ctx, run, "invoice.auto_charge.retry.failed", "auto_charge_retry", 0, err)
+		return err
+	}
+	if retried > 0 {
+		s.log.Info("auto-charge retries attempted", zap.Int("retried", retried))
+	}
+	run.AddProcessed(retried)
+
+	return nil
+}
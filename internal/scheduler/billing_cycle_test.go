@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"gorm.io/gorm"
+)
+
+func TestNextPeriodEndYearlySpansLeapDay(t *testing.T) {
+	start := time.Date(2027, 7, 1, 0, 0, 0, 0, time.UTC)
+	end, err := nextPeriodEnd(start, "YEARLY")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := time.Date(2028, 7, 1, 0, 0, 0, 0, time.UTC); !end.Equal(want) {
+		t.Fatalf("expected period end %v, got %v", want, end)
+	}
+
+	// The cycle holds 29 February 2028, so a subscription active for the
+	// last 183 days is billed 183/366 of the price, not 183/365.
+	cycleDuration := end.Sub(start).Seconds()
+	if days := cycleDuration / 86400; days != 366 {
+		t.Fatalf("expected 366 day cycle, got %v", days)
+	}
+	factor := billingcycledomain.ProrationFactor(end.AddDate(0, 0, -183), end, cycleDuration)
+	if math.Abs(factor-183.0/366.0) > 1e-9 {
+		t.Fatalf("expected proration factor %v, got %v", 183.0/366.0, factor)
+	}
+}
+
+func TestInsertCycleSkipsPeriodAlreadyOpened(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&billingcycledomain.BillingCycle{}); err != nil {
+		t.Fatalf("migrate billing_cycles: %v", err)
+	}
+	if err := db.Exec(`
+		CREATE TABLE billing_cycle_stats (
+			billing_cycle_id INTEGER PRIMARY KEY,
+			org_id INTEGER,
+			period_start DATETIME,
+			status TEXT,
+			total_revenue REAL,
+			invoice_count INTEGER,
+			updated_at DATETIME
+		)
+	`).Error; err != nil {
+		t.Fatalf("create billing_cycle_stats table: %v", err)
+	}
+
+	node, _ := snowflake.NewNode(1)
+	s := &Scheduler{db: db}
+	ctx := context.Background()
+	orgID := node.Generate()
+	subID := node.Generate()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// A second replica that computed the same period start, even with a
+	// different period end, must not open another cycle.
+	inserted, err := s.insertCycle(ctx, db, node.Generate(), orgID, subID, start, start.AddDate(0, 1, 0), start)
+	if err != nil || !inserted {
+		t.Fatalf("expected first cycle to be inserted, got inserted=%v err=%v", inserted, err)
+	}
+	inserted, err = s.insertCycle(ctx, db, node.Generate(), orgID, subID, start, start.AddDate(0, 0, 7), start)
+	if err != nil {
+		t.Fatalf("expected duplicate period to be skipped, got %v", err)
+	}
+	if inserted {
+		t.Fatal("expected duplicate period not to be inserted")
+	}
+
+	var cycles, stats int64
+	db.Model(&billingcycledomain.BillingCycle{}).Where("subscription_id = ?", subID).Count(&cycles)
+	db.Raw("SELECT COUNT(1) FROM billing_cycle_stats").Scan(&stats)
+	if cycles != 1 || stats != 1 {
+		t.Fatalf("expected one cycle and one stats row, got %d and %d", cycles, stats)
+	}
+}
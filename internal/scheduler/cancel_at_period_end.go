@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/railzwaylabs/railzway/internal/authorization"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/zap"
+)
+
+// CancelAtPeriodEndJob cancels subscriptions whose scheduled cancellation
+// time has passed. end_canceled_subs ends them once their last cycle closes.
+func (s *Scheduler) CancelAtPeriodEndJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "cancel_at_period_end", s.cfg.BatchSize)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	now := s.clock.Now(ctx)
+	var subscriptions []WorkSubscription
+	if err := applyTestClockScope(ctx, s.db).WithContext(ctx).Raw(
+		`SELECT id, org_id, status, activated_at, billing_cycle_type
+		 FROM subscriptions
+		 WHERE status IN (?, ?, ?)
+		   AND cancel_at_period_end = TRUE
+		   AND cancel_at <= ?
+		 ORDER BY id
+		 LIMIT ?`,
+		subscriptiondomain.SubscriptionStatusActive,
+		subscriptiondomain.SubscriptionStatusPastDue,
+		subscriptiondomain.SubscriptionStatusPaused,
+		now,
+		s.cfg.BatchSize,
+	).Scan(&subscriptions).Error; err != nil {
+		s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "cancel_at_period_end", 0, err)
+		return err
+	}
+
+	var jobErr error
+	for _, subscription := range subscriptions {
+		if ctx.Err() != nil {
+			jobErr = errors.Join(jobErr, ctx.Err())
+			break
+		}
+
+		if err := s.ensureOrgActive(ctx, subscription.OrgID); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.org.inactive", "cancel_at_period_end", subscription.OrgID, err,
+				zap.String("subscription_id", idString(subscription.ID)),
+			)
+			continue
+		}
+
+		if err := s.authorizeSystem(ctx, subscription.OrgID, authorization.ObjectSubscription, authorization.ActionSubscriptionCancel); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.authorize.failed", "cancel_at_period_end", subscription.OrgID, err,
+				zap.String("subscription_id", idString(subscription.ID)),
+			)
+			continue
+		}
+
+		ctxWithOrg := orgcontext.WithOrgID(ctx, int64(subscription.OrgID))
+		ctxWithAudit := s.withAuditContext(ctxWithOrg, subscription.ID.String(), "")
+		if err := s.subscriptionSvc.TransitionSubscription(ctxWithAudit, subscription.ID.String(), subscriptiondomain.SubscriptionStatusCanceled, subscriptiondomain.TransitionReason("scheduler")); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "cancel_at_period_end", subscription.OrgID, err,
+				zap.String("subscription_id", idString(subscription.ID)),
+			)
+			continue
+		}
+		run.AddProcessed(1)
+
+		s.emitAuditEvent(ctxWithAudit, auditEvent{
+			OrgID:          subscription.OrgID,
+			Action:         "subscription.cancel",
+			TargetType:     "subscription",
+			TargetID:       subscription.ID.String(),
+			SubscriptionID: subscription.ID.String(),
+			Metadata: map[string]any{
+				"reason":    "scheduler",
+				"cancel_at": subscriptiondomain.CancelAtPeriodEnd,
+			},
+		})
+	}
+
+	return jobErr
+}
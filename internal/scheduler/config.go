@@ -2,21 +2,24 @@ package scheduler
 
 import (
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // Config controls scheduler intervals and batch sizes.
 type Config struct {
-	RunInterval          time.Duration
-	BatchSize            int
-	RecoveryThreshold    time.Duration
-	FinalizeInvoices     bool
-	MaxCloseBatchSize    int
-	MaxRatingBatchSize   int
-	MaxInvoiceBatchSize  int
-	WebhookRetentionDays int
-	EnabledJobs          []string
+	RunInterval                   time.Duration
+	BatchSize                     int
+	RecoveryThreshold             time.Duration
+	FinalizeInvoices              bool
+	MaxCloseBatchSize             int
+	MaxRatingBatchSize            int
+	MaxInvoiceBatchSize           int
+	WebhookRetentionDays          int
+	ProcessedWebhookRetentionDays int
+	MaxAutoChargeRetries          int
+	EnabledJobs                   []string
 }
 
 func ProvideConfig() Config {
@@ -27,19 +30,31 @@ func ProvideConfig() Config {
 			cfg.EnabledJobs[i] = strings.TrimSpace(cfg.EnabledJobs[i])
 		}
 	}
+	if raw := strings.TrimSpace(os.Getenv("PROCESSED_WEBHOOK_RETENTION_DAYS")); raw != "" {
+		if days, err := strconv.Atoi(raw); err == nil && days >= 0 {
+			cfg.ProcessedWebhookRetentionDays = days
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("AUTO_CHARGE_MAX_RETRIES")); raw != "" {
+		if retries, err := strconv.Atoi(raw); err == nil && retries >= 0 {
+			cfg.MaxAutoChargeRetries = retries
+		}
+	}
 	return cfg
 }
 
 func DefaultConfig() Config {
 	return Config{
-		RunInterval:          time.Minute,
-		BatchSize:            50,
-		RecoveryThreshold:    15 * time.Minute,
-		FinalizeInvoices:     true,
-		MaxCloseBatchSize:    50,
-		MaxRatingBatchSize:   25,
-		MaxInvoiceBatchSize:  25,
-		WebhookRetentionDays: 30,
+		RunInterval:                   time.Minute,
+		BatchSize:                     50,
+		RecoveryThreshold:             15 * time.Minute,
+		FinalizeInvoices:              true,
+		MaxCloseBatchSize:             50,
+		MaxRatingBatchSize:            25,
+		MaxInvoiceBatchSize:           25,
+		WebhookRetentionDays:          30,
+		ProcessedWebhookRetentionDays: 90,
+		MaxAutoChargeRetries:          3,
 	}
 }
 
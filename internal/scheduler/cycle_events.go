@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/events"
+	"gorm.io/gorm"
+)
+
+// publishCycleOpened records a subscription.cycle.opened event in tx.
+func (s *Scheduler) publishCycleOpened(ctx context.Context, tx *gorm.DB, cycleID, orgID, subscriptionID snowflake.ID, periodStart, periodEnd time.Time) error {
+	if s.outbox == nil {
+		return nil
+	}
+	payload := events.SubscriptionCyclePayload{
+		SubscriptionID: subscriptionID.String(),
+		BillingCycleID: cycleID.String(),
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+	}
+	return s.outbox.PublishTx(ctx, tx, events.Event{
+		OrgID:     orgID,
+		Type:      events.EventSubscriptionCycleOpened,
+		Payload:   payload.ToMap(),
+		DedupeKey: events.EventSubscriptionCycleOpened + ":" + cycleID.String(),
+	})
+}
+
+// publishCycleClosed records a subscription.cycle.closed event in tx with
+// the cycle's rated total and the invoice generated at close, if any.
+func (s *Scheduler) publishCycleClosed(ctx context.Context, tx *gorm.DB, cycle *WorkBillingCycle) error {
+	if s.outbox == nil {
+		return nil
+	}
+
+	var rated struct {
+		Total    int64  `gorm:"column:total"`
+		Currency string `gorm:"column:currency"`
+	}
+	if err := tx.WithContext(ctx).Raw(
+		`SELECT COALESCE(SUM(amount), 0) AS total, COALESCE(MAX(currency), '') AS currency
+		 FROM rating_results
+		 WHERE billing_cycle_id = ?`,
+		cycle.ID,
+	).Scan(&rated).Error; err != nil {
+		return err
+	}
+
+	var invoiceIDs []snowflake.ID
+	if err := tx.WithContext(ctx).Raw(
+		`SELECT id FROM invoices
+		 WHERE org_id = ? AND billing_cycle_id = ? AND billing_phase = ?
+		 LIMIT 1`,
+		cycle.OrgID,
+		cycle.ID,
+		billingcycledomain.BillingPhaseArrears,
+	).Scan(&invoiceIDs).Error; err != nil {
+		return err
+	}
+
+	payload := events.SubscriptionCyclePayload{
+		SubscriptionID: cycle.SubscriptionID.String(),
+		BillingCycleID: cycle.ID.String(),
+		PeriodStart:    cycle.PeriodStart,
+		PeriodEnd:      cycle.PeriodEnd,
+		RatedTotal:     &rated.Total,
+		Currency:       rated.Currency,
+	}
+	if len(invoiceIDs) > 0 {
+		payload.InvoiceID = invoiceIDs[0].String()
+	}
+	return s.outbox.PublishTx(ctx, tx, events.Event{
+		OrgID:     cycle.OrgID,
+		Type:      events.EventSubscriptionCycleClosed,
+		Payload:   payload.ToMap(),
+		DedupeKey: events.EventSubscriptionCycleClosed + ":" + cycle.ID.String(),
+	})
+}
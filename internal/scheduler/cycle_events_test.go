@@ -0,0 +1,128 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/events"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestCycleEvents_OpenedAndClosedCarryPeriodAndTotals(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&billingcycledomain.BillingCycle{}, &ratingdomain.RatingResult{}, &invoicedomain.Invoice{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE billing_cycle_stats (
+			billing_cycle_id INTEGER PRIMARY KEY,
+			org_id INTEGER,
+			period_start DATETIME,
+			status TEXT,
+			total_revenue REAL,
+			invoice_count INTEGER,
+			updated_at DATETIME
+		)`,
+		`CREATE TABLE billing_events (
+			id BIGINT PRIMARY KEY,
+			org_id BIGINT NOT NULL,
+			event_type TEXT NOT NULL,
+			payload TEXT NOT NULL DEFAULT '{}',
+			dedupe_key TEXT,
+			published BOOLEAN NOT NULL DEFAULT false,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE UNIQUE INDEX ux_billing_event_dedupe ON billing_events(org_id, dedupe_key)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("create table: %v", err)
+		}
+	}
+
+	node, _ := snowflake.NewNode(1)
+	s := &Scheduler{db: db, outbox: events.NewOutbox(db, node)}
+	ctx := context.Background()
+	orgID := node.Generate()
+	subID := node.Generate()
+	cycleID := node.Generate()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	if _, err := s.insertCycle(ctx, db, cycleID, orgID, subID, start, end, start); err != nil {
+		t.Fatalf("insert cycle: %v", err)
+	}
+
+	for _, amount := range []int64{1500, 2500} {
+		if err := db.Create(&ratingdomain.RatingResult{
+			ID: node.Generate(), OrgID: orgID, SubscriptionID: subID, BillingCycleID: cycleID,
+			BillingPhase: string(billingcycledomain.BillingPhaseArrears), PriceID: node.Generate(),
+			Quantity: 1, UnitPrice: amount, Amount: amount, Currency: "USD",
+			PeriodStart: start, PeriodEnd: end, Source: "test", Checksum: node.Generate().String(),
+		}).Error; err != nil {
+			t.Fatalf("create rating result: %v", err)
+		}
+	}
+	invoice := invoicedomain.Invoice{
+		ID: node.Generate(), OrgID: orgID, InvoiceNumber: "INV-1", BillingCycleID: cycleID,
+		BillingPhase: string(billingcycledomain.BillingPhaseArrears), SubscriptionID: subID,
+		CustomerID: node.Generate(), Status: invoicedomain.InvoiceStatusDraft, TotalAmount: 4000,
+		Currency: "USD", Metadata: datatypes.JSONMap{}, CreatedAt: end, UpdatedAt: end,
+	}
+	if err := db.Create(&invoice).Error; err != nil {
+		t.Fatalf("create invoice: %v", err)
+	}
+
+	cycle := &WorkBillingCycle{ID: cycleID, OrgID: orgID, SubscriptionID: subID, PeriodStart: start, PeriodEnd: end}
+	for i := 0; i < 2; i++ {
+		if err := s.publishCycleClosed(ctx, db, cycle); err != nil {
+			t.Fatalf("publish cycle closed: %v", err)
+		}
+	}
+
+	var rows []struct {
+		EventType string
+		Payload   string
+	}
+	if err := db.Raw(`SELECT event_type, payload FROM billing_events ORDER BY id`).Scan(&rows).Error; err != nil {
+		t.Fatalf("load events: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected one opened and one closed event, got %d", len(rows))
+	}
+
+	payloads := map[string]map[string]any{}
+	for _, row := range rows {
+		var payload map[string]any
+		if err := json.Unmarshal([]byte(row.Payload), &payload); err != nil {
+			t.Fatalf("decode payload: %v", err)
+		}
+		payloads[row.EventType] = payload
+	}
+
+	opened := payloads[events.EventSubscriptionCycleOpened]
+	if opened["billing_cycle_id"] != cycleID.String() || opened["period_start"] != "2026-01-01T00:00:00Z" || opened["period_end"] != "2026-02-01T00:00:00Z" {
+		t.Fatalf("unexpected opened payload %v", opened)
+	}
+	if _, ok := opened["rated_total"]; ok {
+		t.Fatalf("opened payload should not carry a rated total: %v", opened)
+	}
+
+	closed := payloads[events.EventSubscriptionCycleClosed]
+	if closed["subscription_id"] != subID.String() || closed["invoice_id"] != invoice.ID.String() {
+		t.Fatalf("unexpected closed payload %v", closed)
+	}
+	if closed["rated_total"] != float64(4000) || closed["currency"] != "USD" {
+		t.Fatalf("expected rated total 4000 USD, got %v", closed)
+	}
+}
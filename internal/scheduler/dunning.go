@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// DunningJob emails reminders for overdue invoices nobody has claimed. The
+// schedule of reminder days is set per organization.
+func (s *Scheduler) DunningJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "dunning", s.cfg.BatchSize)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	sent, err := s.billingOperationsSvc.SendDunningReminders(ctx, s.cfg.BatchSize)
+	if err != nil {
+		s.logSchedulerError(ctx, run, "dunning.send.failed", "dunning", 0, err)
+		return err
+	}
+	if sent > 0 {
+		s.log.Info("dunning reminders sent", zap.Int("sent", sent))
+	}
+	run.AddProcessed(sent)
+
+	return nil
+}
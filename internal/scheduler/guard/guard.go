@@ -18,7 +18,7 @@ var (
 )
 
 func EnsureSubscriptionCanOpenBillingCycle(status subscriptiondomain.SubscriptionStatus, activatedAt *time.Time, cycleType string) error {
-	if status != subscriptiondomain.SubscriptionStatusActive {
+	if !status.Billable() {
 		return ErrSubscriptionNotActive
 	}
 	if activatedAt == nil {
@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// InvoicePDFJob renders the invoice PDFs queued through the API.
+func (s *Scheduler) InvoicePDFJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "invoice_pdf", s.cfg.BatchSize)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	generated, err := s.invoiceSvc.GenerateQueuedInvoicePDFs(ctx, s.cfg.BatchSize)
+	if err != nil {
+		s.logSchedulerError(ctx, run, "invoice.pdf.generate.failed", "invoice_pdf", 0, err)
+		return err
+	}
+	if generated > 0 {
+		s.log.Info("invoice PDFs generated", zap.Int("generated", generated))
+	}
+	run.AddProcessed(generated)
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
 	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
 	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
 )
@@ -42,6 +43,12 @@ func (s *Scheduler) getLedgerAccountID(
 	return id, nil
 }
 
+// ensureLedgerEntryForCycle accrues the cycle's rated revenue against
+// accounts receivable. A cycle re-rated after its accrual was posted, because
+// late usage reopened it, gets the difference posted as an adjustment entry
+// for the cycle instead, which the cycle's invoice adds to the accrual. Only
+// one adjustment is posted per cycle; late usage no longer reopens a cycle
+// once it has one.
 func (s *Scheduler) ensureLedgerEntryForCycle(
 	ctx context.Context,
 	cycle WorkBillingCycle,
@@ -108,6 +115,14 @@ func (s *Scheduler) ensureLedgerEntryForCycle(
 		return err
 	}
 
+	posted, err := s.postedCycleBalances(ctx, cycle.OrgID, cycle.ID)
+	if err != nil {
+		return err
+	}
+	if len(posted) > 0 {
+		return s.adjustLedgerEntryForCycle(ctx, cycle, currency, arID, lines, posted)
+	}
+
 	lines = append(lines, ledgerdomain.LedgerEntryLine{
 		AccountID: arID,
 		Direction: ledgerdomain.LedgerEntryDirectionDebit,
@@ -126,6 +141,116 @@ func (s *Scheduler) ensureLedgerEntryForCycle(
 	)
 }
 
+// adjustLedgerEntryForCycle posts the difference between the re-rated revenue
+// lines and the revenue already posted for the cycle. Nothing is posted when
+// the re-rating did not change the revenue.
+func (s *Scheduler) adjustLedgerEntryForCycle(
+	ctx context.Context,
+	cycle WorkBillingCycle,
+	currency string,
+	arID snowflake.ID,
+	revenue []ledgerdomain.LedgerEntryLine,
+	posted map[snowflake.ID]int64,
+) error {
+	rated := make(map[snowflake.ID]int64, len(revenue))
+	for _, line := range revenue {
+		rated[line.AccountID] += line.Amount
+	}
+	for accountID := range posted {
+		if accountID != arID {
+			if _, ok := rated[accountID]; !ok {
+				rated[accountID] = 0
+			}
+		}
+	}
+
+	var (
+		lines []ledgerdomain.LedgerEntryLine
+		net   int64
+	)
+	for accountID, amount := range rated {
+		delta := amount - posted[accountID]
+		if delta == 0 {
+			continue
+		}
+		net += delta
+		lines = append(lines, adjustmentLine(accountID, currency, delta))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	if net != 0 {
+		// Receivable moves opposite to revenue.
+		lines = append(lines, adjustmentLine(arID, currency, -net))
+	}
+
+	return s.ledgerSvc.CreateEntry(
+		ctx,
+		cycle.OrgID,
+		string(ledgerdomain.SourceTypeAdjustment),
+		cycle.ID,
+		currency,
+		cycle.PeriodEnd,
+		lines,
+	)
+}
+
+// adjustmentLine credits a positive revenue delta and debits a negative one.
+func adjustmentLine(accountID snowflake.ID, currency string, delta int64) ledgerdomain.LedgerEntryLine {
+	line := ledgerdomain.LedgerEntryLine{
+		AccountID: accountID,
+		Direction: ledgerdomain.LedgerEntryDirectionCredit,
+		Currency:  currency,
+		Amount:    delta,
+	}
+	if delta < 0 {
+		line.Direction = ledgerdomain.LedgerEntryDirectionDebit
+		line.Amount = -delta
+	}
+	return line
+}
+
+// postedCycleBalances sums the cycle's accrual and adjustment entries per
+// account, credits positive.
+func (s *Scheduler) postedCycleBalances(
+	ctx context.Context,
+	orgID snowflake.ID,
+	billingCycleID snowflake.ID,
+) (map[snowflake.ID]int64, error) {
+
+	var rows []struct {
+		AccountID snowflake.ID
+		Balance   int64
+	}
+	err := s.db.WithContext(ctx).Raw(
+		`
+		SELECT
+			l.account_id,
+			SUM(CASE WHEN l.direction = ? THEN l.amount ELSE -l.amount END) AS balance
+		FROM ledger_entries le
+		JOIN ledger_entry_lines l ON l.ledger_entry_id = le.id
+		WHERE le.org_id = ?
+		  AND le.source_id = ?
+		  AND le.source_type IN (?, ?)
+		GROUP BY l.account_id
+		`,
+		ledgerdomain.LedgerEntryDirectionCredit,
+		orgID,
+		billingCycleID,
+		ledgerdomain.SourceTypeBillingCycle,
+		ledgerdomain.SourceTypeAdjustment,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[snowflake.ID]int64, len(rows))
+	for _, row := range rows {
+		balances[row.AccountID] = row.Balance
+	}
+	return balances, nil
+}
+
 func (s *Scheduler) summarizeRatingResults(
 	ctx context.Context,
 	orgID snowflake.ID,
@@ -146,10 +271,12 @@ func (s *Scheduler) summarizeRatingResults(
 		FROM rating_results
 		WHERE org_id = ?
 		  AND billing_cycle_id = ?
+		  AND billing_phase = ?
 		GROUP BY kind, currency
 		`,
 		orgID,
 		billingCycleID,
+		billingcycledomain.BillingPhaseArrears,
 	).Scan(&rows).Error
 	if err != nil {
 		return nil, err
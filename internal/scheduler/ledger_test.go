@@ -0,0 +1,150 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	ledgerservice "github.com/railzwaylabs/railzway/internal/ledger/service"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestEnsureLedgerEntryForCycleAdjustsReRatedCycle(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&ledgerdomain.LedgerAccount{},
+		&ledgerdomain.LedgerEntry{},
+		&ledgerdomain.LedgerEntryLine{},
+		&ratingdomain.RatingResult{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := db.Exec(`CREATE UNIQUE INDEX ux_ledger_entries_source
+		ON ledger_entries (org_id, source_type, source_id)`).Error; err != nil {
+		t.Fatalf("create ledger source index: %v", err)
+	}
+
+	node, _ := snowflake.NewNode(1)
+	ledgerSvc := ledgerservice.NewService(ledgerservice.Params{DB: db, Log: zap.NewNop(), GenID: node})
+	s := &Scheduler{db: db, genID: node, ledgerSvc: ledgerSvc}
+	ctx := context.Background()
+	orgID := node.Generate()
+	if err := ledgerSvc.EnsureAccounts(ctx, orgID); err != nil {
+		t.Fatalf("ensure accounts: %v", err)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cycle := WorkBillingCycle{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: node.Generate(),
+		PeriodStart:    start,
+		PeriodEnd:      start.AddDate(0, 1, 0),
+	}
+	meterID := node.Generate()
+	rate := func(usage, flat int64) {
+		if err := db.Where("billing_cycle_id = ?", cycle.ID).Delete(&ratingdomain.RatingResult{}).Error; err != nil {
+			t.Fatalf("clear rating results: %v", err)
+		}
+		for i, amount := range []int64{usage, flat} {
+			result := ratingdomain.RatingResult{
+				ID:             node.Generate(),
+				OrgID:          orgID,
+				SubscriptionID: cycle.SubscriptionID,
+				BillingCycleID: cycle.ID,
+				BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
+				PriceID:        node.Generate(),
+				Amount:         amount,
+				Currency:       "USD",
+				PeriodStart:    cycle.PeriodStart,
+				PeriodEnd:      cycle.PeriodEnd,
+				Source:         "flat",
+				Checksum:       node.Generate().String(),
+			}
+			if i == 0 {
+				result.MeterID = &meterID
+				result.Source = "usage"
+			}
+			if err := db.Create(&result).Error; err != nil {
+				t.Fatalf("create rating result: %v", err)
+			}
+		}
+	}
+	entries := func(sourceType ledgerdomain.LedgerSourceType) int64 {
+		var count int64
+		if err := db.Model(&ledgerdomain.LedgerEntry{}).
+			Where("source_type = ? AND source_id = ?", sourceType, cycle.ID).
+			Count(&count).Error; err != nil {
+			t.Fatalf("count ledger entries: %v", err)
+		}
+		return count
+	}
+	balance := func(code ledgerdomain.LedgerAccountCode) int64 {
+		balances, err := s.postedCycleBalances(ctx, orgID, cycle.ID)
+		if err != nil {
+			t.Fatalf("posted balances: %v", err)
+		}
+		id, err := s.getLedgerAccountID(ctx, orgID, code)
+		if err != nil {
+			t.Fatalf("account %s: %v", code, err)
+		}
+		return balances[id]
+	}
+
+	rate(500, 1000)
+	if err := s.ensureLedgerEntryForCycle(ctx, cycle); err != nil {
+		t.Fatalf("accrue cycle: %v", err)
+	}
+	if got := entries(ledgerdomain.SourceTypeBillingCycle); got != 1 {
+		t.Fatalf("expected one accrual entry, got %d", got)
+	}
+
+	// Late usage re-rated the cycle: only the usage revenue moved.
+	rate(600, 1000)
+	if err := s.ensureLedgerEntryForCycle(ctx, cycle); err != nil {
+		t.Fatalf("adjust cycle: %v", err)
+	}
+	if got := entries(ledgerdomain.SourceTypeAdjustment); got != 1 {
+		t.Fatalf("expected one adjustment entry, got %d", got)
+	}
+	var lines int64
+	if err := db.Model(&ledgerdomain.LedgerEntryLine{}).
+		Joins("JOIN ledger_entries le ON le.id = ledger_entry_lines.ledger_entry_id").
+		Where("le.source_type = ? AND le.source_id = ?", ledgerdomain.SourceTypeAdjustment, cycle.ID).
+		Count(&lines).Error; err != nil {
+		t.Fatalf("count adjustment lines: %v", err)
+	}
+	if lines != 2 {
+		t.Fatalf("expected usage revenue and receivable adjustment lines, got %d", lines)
+	}
+	if got := balance(ledgerdomain.AccountCodeRevenueUsage); got != 600 {
+		t.Fatalf("expected usage revenue 600, got %d", got)
+	}
+	if got := balance(ledgerdomain.AccountCodeRevenueFlat); got != 1000 {
+		t.Fatalf("expected flat revenue 1000, got %d", got)
+	}
+	if got := balance(ledgerdomain.AccountCodeAccountsReceivable); got != -1600 {
+		t.Fatalf("expected receivable 1600, got %d", -got)
+	}
+
+	// A retried close finds nothing left to post.
+	if err := s.ensureLedgerEntryForCycle(ctx, cycle); err != nil {
+		t.Fatalf("retry close: %v", err)
+	}
+	var total int64
+	if err := db.Model(&ledgerdomain.LedgerEntry{}).Count(&total).Error; err != nil {
+		t.Fatalf("count ledger entries: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected no further entries, got %d", total)
+	}
+}
@@ -124,7 +124,8 @@ func (s *Scheduler) fetchSubscriptionsNeedingCycle(ctx context.Context, tx *gorm
 	err := applyTestClockScope(ctx, tx).WithContext(ctx).Raw(
 		`SELECT s.id, s.org_id, s.status, s.activated_at, s.billing_cycle_type
 		 FROM subscriptions s
-		 WHERE s.status = ?
+		 WHERE s.status IN (?, ?)
+		   AND s.cancel_at_period_end = FALSE
 		   AND NOT EXISTS (
 			   SELECT 1 FROM billing_cycles bc 
 			   WHERE bc.subscription_id = s.id 
@@ -134,6 +135,7 @@ func (s *Scheduler) fetchSubscriptionsNeedingCycle(ctx context.Context, tx *gorm
 		 LIMIT ?
 		 FOR UPDATE SKIP LOCKED`,
 		subscriptiondomain.SubscriptionStatusActive,
+		subscriptiondomain.SubscriptionStatusPastDue,
 		billingcycledomain.BillingCycleStatusOpen,
 		limit,
 	).Scan(&subscriptions).Error
@@ -185,6 +187,20 @@ func (s *Scheduler) findOpenCycle(
 	}
 }
 
+// lockSubscriptionCycles serializes cycle generation for a subscription
+// across scheduler replicas until tx ends. Replicas that claimed the same
+// subscription wait here and then see the cycle the first one opened.
+func (s *Scheduler) lockSubscriptionCycles(ctx context.Context, tx *gorm.DB, subscriptionID snowflake.ID) error {
+	if tx.Dialector.Name() == "sqlite" {
+		return nil
+	}
+	schedMetrics := obsmetrics.Scheduler()
+	lockStart := time.Now()
+	err := tx.WithContext(ctx).Exec(`SELECT pg_advisory_xact_lock(?)`, int64(subscriptionID)).Error
+	schedMetrics.ObserveDBLockWait(obsmetrics.LockResourceSubscriptionCycles, time.Since(lockStart))
+	return err
+}
+
 func (s *Scheduler) findLastCycle(ctx context.Context, tx *gorm.DB, orgID, subscriptionID snowflake.ID) (*WorkBillingCycle, error) {
 	var cycle WorkBillingCycle
 	err := tx.WithContext(ctx).Raw(
@@ -207,7 +223,9 @@ func (s *Scheduler) findLastCycle(ctx context.Context, tx *gorm.DB, orgID, subsc
 	return &cycle, nil
 }
 
-func (s *Scheduler) insertCycle(ctx context.Context, tx *gorm.DB, cycleID, orgID, subscriptionID snowflake.ID, periodStart, periodEnd, now time.Time) error {
+// insertCycle opens a cycle and reports whether it was created. A cycle that
+// already exists for the period, or another open cycle, leaves it untouched.
+func (s *Scheduler) insertCycle(ctx context.Context, tx *gorm.DB, cycleID, orgID, subscriptionID snowflake.ID, periodStart, periodEnd, now time.Time) (bool, error) {
 	openedAt := now
 	
 	testClockID, _ := testclockctx.TestClockIDFromContext(ctx)
@@ -216,11 +234,12 @@ func (s *Scheduler) insertCycle(ctx context.Context, tx *gorm.DB, cycleID, orgID
 		testClockIDPtr = &testClockID
 	}
 
-	if err := tx.WithContext(ctx).Exec(
+	result := tx.WithContext(ctx).Exec(
 		`INSERT INTO billing_cycles (
 			id, org_id, test_clock_id, subscription_id, period_start, period_end, status,
 			opened_at, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`,
 		cycleID,
 		orgID,
 		testClockIDPtr,
@@ -231,10 +250,17 @@ func (s *Scheduler) insertCycle(ctx context.Context, tx *gorm.DB, cycleID, orgID
 		openedAt,
 		now,
 		now,
-	).Error; err != nil {
-		return err
+	)
+	if result.Error != nil {
+		return false, result.Error
 	}
-	return s.upsertBillingCycleStats(ctx, tx, cycleID, orgID, periodStart, billingcycledomain.BillingCycleStatusOpen, now)
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	if err := s.upsertBillingCycleStats(ctx, tx, cycleID, orgID, periodStart, billingcycledomain.BillingCycleStatusOpen, now); err != nil {
+		return false, err
+	}
+	return true, s.publishCycleOpened(ctx, tx, cycleID, orgID, subscriptionID, periodStart, periodEnd)
 }
 
 func (s *Scheduler) lockCycleForUpdate(
@@ -388,7 +414,7 @@ func (s *Scheduler) markCycleInvoiced(ctx context.Context, cycleID snowflake.ID,
 		if cycle == nil || cycle.Status != billingcycledomain.BillingCycleStatusClosed {
 			return nil
 		}
-		return tx.WithContext(ctx).Exec(
+		if err := tx.WithContext(ctx).Exec(
 			`UPDATE billing_cycles
 			 SET invoiced_at = COALESCE(invoiced_at, ?),
 			     last_error = NULL,
@@ -399,7 +425,15 @@ func (s *Scheduler) markCycleInvoiced(ctx context.Context, cycleID snowflake.ID,
 			now,
 			cycleID,
 			billingcycledomain.BillingCycleStatusClosed,
-		).Error
+		).Error; err != nil {
+			return err
+		}
+		// The cycle's closing work ends here, so renewals are reported once
+		// its invoice exists.
+		if cycle.InvoicedAt != nil {
+			return nil
+		}
+		return s.publishCycleClosed(ctx, tx, cycle)
 	})
 }
 
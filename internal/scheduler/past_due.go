@@ -0,0 +1,201 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/authorization"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/zap"
+)
+
+// unpaidFailedChargeClause matches subscriptions with a finalized invoice that
+// is still owed after its last auto-charge attempt failed.
+const unpaidFailedChargeClause = `EXISTS (
+	SELECT 1 FROM invoices i
+	WHERE i.subscription_id = s.id
+	  AND i.status = ?
+	  AND i.paid_at IS NULL
+	  AND i.voided_at IS NULL
+	  AND i.total_amount > 0
+	  AND i.metadata->>'auto_charge_status' = 'failed'
+)`
+
+// PastDueSubscriptionsJob moves active subscriptions with a failed auto-charge
+// to past_due, back to active once nothing is owed, and cancels those still
+// unpaid when the organization's grace period ends. Auto-charge retries keep
+// running throughout the grace period.
+func (s *Scheduler) PastDueSubscriptionsJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "past_due_subscriptions", s.cfg.BatchSize)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	var jobErr error
+	transition := func(subscription WorkSubscription, target subscriptiondomain.SubscriptionStatus, action string, reason string) {
+		if err := s.transitionPastDue(ctx, subscription, target, action, reason); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.subscription.past_due.failed", "past_due_subscriptions", subscription.OrgID, err,
+				zap.String("subscription_id", idString(subscription.ID)),
+				zap.String("target_status", string(target)),
+			)
+			return
+		}
+		run.AddProcessed(1)
+	}
+
+	var failed []WorkSubscription
+	if err := applyTestClockScope(ctx, s.db).WithContext(ctx).Raw(
+		`SELECT s.id, s.org_id, s.status, s.activated_at, s.billing_cycle_type
+		 FROM subscriptions s
+		 WHERE s.status = ?
+		   AND `+unpaidFailedChargeClause+`
+		 ORDER BY s.id
+		 LIMIT ?`,
+		subscriptiondomain.SubscriptionStatusActive,
+		invoicedomain.InvoiceStatusFinalized,
+		s.cfg.BatchSize,
+	).Scan(&failed).Error; err != nil {
+		s.logSchedulerError(ctx, run, "scheduler.subscription.past_due.failed", "past_due_subscriptions", 0, err)
+		return err
+	}
+	for _, subscription := range failed {
+		if ctx.Err() != nil {
+			return errors.Join(jobErr, ctx.Err())
+		}
+		transition(subscription, subscriptiondomain.SubscriptionStatusPastDue, "subscription.past_due", "auto_charge_failed")
+	}
+
+	var recovered []WorkSubscription
+	if err := applyTestClockScope(ctx, s.db).WithContext(ctx).Raw(
+		`SELECT s.id, s.org_id, s.status, s.activated_at, s.billing_cycle_type
+		 FROM subscriptions s
+		 WHERE s.status = ?
+		   AND NOT `+unpaidFailedChargeClause+`
+		 ORDER BY s.id
+		 LIMIT ?`,
+		subscriptiondomain.SubscriptionStatusPastDue,
+		invoicedomain.InvoiceStatusFinalized,
+		s.cfg.BatchSize,
+	).Scan(&recovered).Error; err != nil {
+		s.logSchedulerError(ctx, run, "scheduler.subscription.past_due.failed", "past_due_subscriptions", 0, err)
+		return errors.Join(jobErr, err)
+	}
+	for _, subscription := range recovered {
+		if ctx.Err() != nil {
+			return errors.Join(jobErr, ctx.Err())
+		}
+		transition(subscription, subscriptiondomain.SubscriptionStatusActive, "subscription.activate", "payment_recovered")
+	}
+
+	expired, err := s.fetchPastDueGraceEnded(ctx, s.clock.Now(ctx))
+	if err != nil {
+		s.logSchedulerError(ctx, run, "scheduler.subscription.past_due.failed", "past_due_subscriptions", 0, err)
+		return errors.Join(jobErr, err)
+	}
+	for _, subscription := range expired {
+		if ctx.Err() != nil {
+			return errors.Join(jobErr, ctx.Err())
+		}
+		transition(subscription, subscriptiondomain.SubscriptionStatusCanceled, "subscription.cancel", "past_due_grace_ended")
+	}
+
+	return jobErr
+}
+
+// fetchPastDueGraceEnded returns past due subscriptions still owing a failed
+// auto-charge whose organization's grace period has ended by now. Grace
+// periods differ per organization, so the cutoff is resolved per org.
+func (s *Scheduler) fetchPastDueGraceEnded(ctx context.Context, now time.Time) ([]WorkSubscription, error) {
+	var orgs []struct {
+		OrgID     snowflake.ID `gorm:"column:org_id"`
+		GraceDays *int         `gorm:"column:past_due_grace_days"`
+	}
+	if err := applyTestClockScope(ctx, s.db).WithContext(ctx).Raw(
+		`SELECT DISTINCT s.org_id, p.past_due_grace_days
+		 FROM subscriptions s
+		 LEFT JOIN organization_billing_preferences p ON p.org_id = s.org_id
+		 WHERE s.status = ?
+		 ORDER BY s.org_id`,
+		subscriptiondomain.SubscriptionStatusPastDue,
+	).Scan(&orgs).Error; err != nil {
+		return nil, err
+	}
+
+	var subscriptions []WorkSubscription
+	for _, org := range orgs {
+		remaining := s.cfg.BatchSize - len(subscriptions)
+		if remaining <= 0 {
+			break
+		}
+		graceDays := organizationdomain.DefaultPastDueGraceDays
+		if org.GraceDays != nil {
+			graceDays = *org.GraceDays
+		}
+
+		var batch []WorkSubscription
+		if err := applyTestClockScope(ctx, s.db).WithContext(ctx).Raw(
+			`SELECT s.id, s.org_id, s.status, s.activated_at, s.billing_cycle_type
+			 FROM subscriptions s
+			 WHERE s.org_id = ?
+			   AND s.status = ?
+			   AND s.past_due_at <= ?
+			   AND `+unpaidFailedChargeClause+`
+			 ORDER BY s.id
+			 LIMIT ?`,
+			org.OrgID,
+			subscriptiondomain.SubscriptionStatusPastDue,
+			now.AddDate(0, 0, -graceDays),
+			invoicedomain.InvoiceStatusFinalized,
+			remaining,
+		).Scan(&batch).Error; err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, batch...)
+	}
+	return subscriptions, nil
+}
+
+func (s *Scheduler) transitionPastDue(
+	ctx context.Context,
+	subscription WorkSubscription,
+	target subscriptiondomain.SubscriptionStatus,
+	action string,
+	reason string,
+) error {
+	if err := s.ensureOrgActive(ctx, subscription.OrgID); err != nil {
+		return err
+	}
+
+	authzAction := authorization.ActionSubscriptionActivate
+	if target == subscriptiondomain.SubscriptionStatusCanceled {
+		authzAction = authorization.ActionSubscriptionCancel
+	}
+	if err := s.authorizeSystem(ctx, subscription.OrgID, authorization.ObjectSubscription, authzAction); err != nil {
+		return err
+	}
+
+	ctxWithOrg := orgcontext.WithOrgID(ctx, int64(subscription.OrgID))
+	ctxWithAudit := s.withAuditContext(ctxWithOrg, subscription.ID.String(), "")
+	if err := s.subscriptionSvc.TransitionSubscription(ctxWithAudit, subscription.ID.String(), target, subscriptiondomain.TransitionReason(reason)); err != nil {
+		return err
+	}
+
+	s.emitAuditEvent(ctxWithAudit, auditEvent{
+		OrgID:          subscription.OrgID,
+		Action:         action,
+		TargetType:     "subscription",
+		TargetID:       subscription.ID.String(),
+		SubscriptionID: subscription.ID.String(),
+		Metadata: map[string]any{
+			"reason": reason,
+		},
+	})
+	return nil
+}
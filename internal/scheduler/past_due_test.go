@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// transitionRecorder applies status transitions straight to the database so
+// the job sees its own changes between runs.
+type transitionRecorder struct {
+	subscriptiondomain.Service
+	db      *gorm.DB
+	clock   clock.Clock
+	reasons map[string]subscriptiondomain.TransitionReason
+}
+
+func (r *transitionRecorder) TransitionSubscription(ctx context.Context, id string, status subscriptiondomain.SubscriptionStatus, reason subscriptiondomain.TransitionReason) error {
+	r.reasons[id] = reason
+	var pastDueAt *time.Time
+	if status == subscriptiondomain.SubscriptionStatusPastDue {
+		now := r.clock.Now(ctx)
+		pastDueAt = &now
+	}
+	return r.db.Exec(`UPDATE subscriptions SET status = ?, past_due_at = ? WHERE id = ?`, status, pastDueAt, id).Error
+}
+
+func TestPastDueSubscriptionsJob(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&subscriptiondomain.Subscription{}, &invoicedomain.Invoice{}, &organizationdomain.OrganizationBillingPreferences{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	node, _ := snowflake.NewNode(1)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(start)
+	recorder := &transitionRecorder{db: db, clock: fakeClock, reasons: map[string]subscriptiondomain.TransitionReason{}}
+	s := &Scheduler{
+		db:              db,
+		log:             zap.NewNop(),
+		genID:           node,
+		clock:           fakeClock,
+		cfg:             Config{BatchSize: 10},
+		subscriptionSvc: recorder,
+		authzSvc:        &mockAuthzSvc{},
+	}
+	ctx := context.Background()
+
+	orgID := node.Generate()
+	if err := db.Create(&organizationdomain.OrganizationBillingPreferences{
+		OrgID:            orgID,
+		Currency:         "USD",
+		Timezone:         "UTC",
+		DunningDays:      datatypes.JSON(`[1, 7, 14]`),
+		PastDueGraceDays: 3,
+	}).Error; err != nil {
+		t.Fatalf("create preferences: %v", err)
+	}
+
+	newSubscription := func() subscriptiondomain.Subscription {
+		subscription := subscriptiondomain.Subscription{
+			ID:               node.Generate(),
+			OrgID:            orgID,
+			CustomerID:       node.Generate(),
+			Status:           subscriptiondomain.SubscriptionStatusActive,
+			CollectionMode:   subscriptiondomain.SubscriptionCollectionModeChargeAutomatically,
+			StartAt:          start,
+			BillingCycleType: "MONTHLY",
+			Metadata:         datatypes.JSONMap{},
+		}
+		if err := db.Create(&subscription).Error; err != nil {
+			t.Fatalf("create subscription: %v", err)
+		}
+		return subscription
+	}
+	newInvoice := func(subscriptionID snowflake.ID, chargeStatus string) invoicedomain.Invoice {
+		invoice := invoicedomain.Invoice{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			BillingCycleID: node.Generate(),
+			SubscriptionID: subscriptionID,
+			CustomerID:     node.Generate(),
+			InvoiceNumber:  node.Generate().String(),
+			Currency:       "USD",
+			Status:         invoicedomain.InvoiceStatusFinalized,
+			SubtotalAmount: 1000,
+			TotalAmount:    1000,
+			Metadata:       datatypes.JSONMap{"auto_charge_status": chargeStatus},
+			CreatedAt:      start,
+			UpdatedAt:      start,
+		}
+		if err := db.Create(&invoice).Error; err != nil {
+			t.Fatalf("create invoice: %v", err)
+		}
+		return invoice
+	}
+	status := func(subscription subscriptiondomain.Subscription) subscriptiondomain.SubscriptionStatus {
+		var current subscriptiondomain.SubscriptionStatus
+		if err := db.Raw(`SELECT status FROM subscriptions WHERE id = ?`, subscription.ID).Scan(&current).Error; err != nil {
+			t.Fatalf("load status: %v", err)
+		}
+		return current
+	}
+	run := func(advance time.Duration) {
+		fakeClock.Advance(advance)
+		if err := s.PastDueSubscriptionsJob(ctx); err != nil {
+			t.Fatalf("past due job: %v", err)
+		}
+	}
+
+	healthy := newSubscription()
+	newInvoice(healthy.ID, "succeeded")
+	recovering := newSubscription()
+	recoveringInvoice := newInvoice(recovering.ID, "failed")
+	unpaid := newSubscription()
+	newInvoice(unpaid.ID, "failed")
+
+	run(0)
+	if got := status(healthy); got != subscriptiondomain.SubscriptionStatusActive {
+		t.Fatalf("expected subscription with a successful charge to stay active, got %s", got)
+	}
+	for _, subscription := range []subscriptiondomain.Subscription{recovering, unpaid} {
+		if got := status(subscription); got != subscriptiondomain.SubscriptionStatusPastDue {
+			t.Fatalf("expected failed auto-charge to mark subscription past due, got %s", got)
+		}
+		if reason := recorder.reasons[subscription.ID.String()]; reason != "auto_charge_failed" {
+			t.Fatalf("unexpected transition reason %q", reason)
+		}
+	}
+
+	// A retry succeeds during the grace period.
+	if err := db.Model(&invoicedomain.Invoice{}).Where("id = ?", recoveringInvoice.ID).
+		Update("paid_at", start.Add(time.Hour)).Error; err != nil {
+		t.Fatalf("pay invoice: %v", err)
+	}
+	run(2 * time.Hour)
+	if got := status(recovering); got != subscriptiondomain.SubscriptionStatusActive {
+		t.Fatalf("expected paid subscription to become active, got %s", got)
+	}
+	if got := status(unpaid); got != subscriptiondomain.SubscriptionStatusPastDue {
+		t.Fatalf("expected unpaid subscription to stay past due within the grace period, got %s", got)
+	}
+
+	run(3*24*time.Hour - 2*time.Hour)
+	if got := status(unpaid); got != subscriptiondomain.SubscriptionStatusCanceled {
+		t.Fatalf("expected unpaid subscription to be canceled at grace end, got %s", got)
+	}
+	if reason := recorder.reasons[unpaid.ID.String()]; reason != "past_due_grace_ended" {
+		t.Fatalf("unexpected transition reason %q", reason)
+	}
+}
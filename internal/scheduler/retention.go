@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 
+	idempotencydomain "github.com/railzwaylabs/railzway/internal/idempotency/domain"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 	"go.uber.org/zap"
 )
@@ -39,3 +40,59 @@ func (s *Scheduler) ResizeWebhookLogsJob(ctx context.Context) error {
 
 	return nil
 }
+
+// CleanupProcessedWebhookEventsJob expires webhook deduplication records.
+// Providers stop redelivering long before the retention window ends.
+func (s *Scheduler) CleanupProcessedWebhookEventsJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "cleanup_processed_webhook_events", 1)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	retentionDays := s.cfg.ProcessedWebhookRetentionDays
+	if retentionDays <= 0 {
+		s.log.Info("processed webhook retention disabled or invalid", zap.Int("days", retentionDays))
+		return nil
+	}
+
+	cutoff := s.clock.Now(ctx).AddDate(0, 0, -retentionDays)
+	result := s.db.WithContext(ctx).Delete(&paymentdomain.ProcessedWebhookEvent{}, "processed_at < ?", cutoff)
+	if result.Error != nil {
+		s.logSchedulerError(ctx, run, "scheduler.cleanup.failed", "cleanup_processed_webhook_events", 0, result.Error)
+		return result.Error
+	}
+
+	deleted := int(result.RowsAffected)
+	if deleted > 0 {
+		s.log.Info("cleanup processed webhook events completed", zap.Int("deleted", deleted))
+	}
+	run.AddProcessed(deleted)
+
+	return nil
+}
+
+// CleanupExpiredIdempotencyKeysJob deletes idempotency keys past their TTL;
+// an expired key no longer replays its response.
+func (s *Scheduler) CleanupExpiredIdempotencyKeysJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "cleanup_idempotency_keys", 1)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	now := s.clock.Now(ctx)
+	result := s.db.WithContext(ctx).Delete(&idempotencydomain.Key{}, "expires_at < ?", now)
+	if result.Error != nil {
+		s.logSchedulerError(ctx, run, "scheduler.cleanup.failed", "cleanup_idempotency_keys", 0, result.Error)
+		return result.Error
+	}
+
+	deleted := int(result.RowsAffected)
+	if deleted > 0 {
+		s.log.Info("cleanup idempotency keys completed", zap.Int("deleted", deleted))
+	}
+	run.AddProcessed(deleted)
+
+	return nil
+}
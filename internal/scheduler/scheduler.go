@@ -17,11 +17,13 @@ import (
 	"github.com/railzwaylabs/railzway/internal/bootstrap"
 	"github.com/railzwaylabs/railzway/internal/clock"
 	"github.com/railzwaylabs/railzway/internal/cloudmetrics"
+	"github.com/railzwaylabs/railzway/internal/events"
 	integrationsvc "github.com/railzwaylabs/railzway/internal/integration/service"
 	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
 	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
 	obsmetrics "github.com/railzwaylabs/railzway/internal/observability/metrics"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/providers/email"
 	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
 	"github.com/railzwaylabs/railzway/internal/scheduler/guard"
 	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
@@ -52,6 +54,9 @@ type Params struct {
 	CloudMetrics         *cloudmetrics.CloudMetrics `optional:"true"`
 	OrgGate              bootstrap.OrgGate          `optional:"true"`
 	IntegrationDispatcher *integrationsvc.Dispatcher `optional:"true"`
+	WebhookDispatcher     *events.WebhookDispatcher  `optional:"true"`
+	Outbox                *events.Outbox             `optional:"true"`
+	Email                 email.Provider             `optional:"true"`
 }
 
 type Scheduler struct {
@@ -71,6 +76,9 @@ type Scheduler struct {
 	cloudMetrics         *cloudmetrics.CloudMetrics
 	orgGate              bootstrap.OrgGate
 	integrationDispatcher *integrationsvc.Dispatcher
+	webhookDispatcher     *events.WebhookDispatcher
+	outbox                *events.Outbox
+	email                 email.Provider
 }
 
 type auditEvent struct {
@@ -105,6 +113,9 @@ func New(p Params) (*Scheduler, error) {
 		cloudMetrics:         p.CloudMetrics,
 		orgGate:              p.OrgGate,
 		integrationDispatcher: p.IntegrationDispatcher,
+		webhookDispatcher:     p.WebhookDispatcher,
+		outbox:                p.Outbox,
+		email:                 p.Email,
 	}, nil
 }
 
@@ -172,9 +183,21 @@ func (s *Scheduler) RunOnce(parent context.Context) error {
 		Enabled bool
 		Run     func(context.Context) error
 	}{
+		{"start_scheduled_subs", s.isJobEnabled("start_scheduled_subs"), func(ctx context.Context) error {
+			return s.runJob(ctx, "start_scheduled_subs", s.cfg.BatchSize, 30*time.Second, s.StartScheduledSubscriptionsJob)
+		}},
 		{"ensure_cycles", s.isJobEnabled("ensure_cycles"), func(ctx context.Context) error {
 			return s.runJob(ctx, "ensure_cycles", s.cfg.BatchSize, 30*time.Second, s.EnsureBillingCyclesJob)
 		}},
+		{"advance_invoice", s.isJobEnabled("advance_invoice"), func(ctx context.Context) error {
+			return s.runJob(ctx, "advance_invoice", s.cfg.MaxInvoiceBatchSize, 30*time.Second, s.AdvanceBillingJob)
+		}},
+		{"threshold_invoice", s.isJobEnabled("threshold_invoice"), func(ctx context.Context) error {
+			return s.runJob(ctx, "threshold_invoice", s.cfg.MaxInvoiceBatchSize, 30*time.Second, s.ThresholdBillingJob)
+		}},
+		{"spend_alert", s.isJobEnabled("spend_alert"), func(ctx context.Context) error {
+			return s.runJob(ctx, "spend_alert", s.cfg.BatchSize, 30*time.Second, s.SpendAlertJob)
+		}},
 		{"close_cycles", s.isJobEnabled("close_cycles"), func(ctx context.Context) error {
 			return s.runJob(ctx, "close_cycles", s.cfg.MaxCloseBatchSize, 30*time.Second, s.CloseCyclesJob)
 		}},
@@ -213,24 +236,54 @@ func (s *Scheduler) RunOnce(parent context.Context) error {
 		Enabled bool
 		Run     func(context.Context) error
 	}{
+		{"cancel_at_period_end", s.isJobEnabled("cancel_at_period_end"), func(ctx context.Context) error {
+			return s.runJob(ctx, "cancel_at_period_end", s.cfg.BatchSize, 30*time.Second, s.CancelAtPeriodEndJob)
+		}},
 		{"end_canceled_subs", s.isJobEnabled("end_canceled_subs"), func(ctx context.Context) error {
 			return s.runJob(ctx, "end_canceled_subs", s.cfg.BatchSize, 30*time.Second, s.EndCanceledSubscriptionsJob)
 		}},
 		{"recovery_sweep", s.isJobEnabled("recovery_sweep"), func(ctx context.Context) error {
 			return s.runJob(ctx, "recovery_sweep", maxInt(s.cfg.MaxRatingBatchSize, s.cfg.MaxCloseBatchSize, s.cfg.MaxInvoiceBatchSize), 30*time.Second, s.RecoverySweepJob)
 		}},
+		{"auto_charge_retry", s.isJobEnabled("auto_charge_retry"), func(ctx context.Context) error {
+			return s.runJob(ctx, "auto_charge_retry", s.cfg.BatchSize, 5*time.Minute, s.AutoChargeRetryJob)
+		}},
+		{"past_due_subscriptions", s.isJobEnabled("past_due_subscriptions"), func(ctx context.Context) error {
+			return s.runJob(ctx, "past_due_subscriptions", s.cfg.BatchSize, 5*time.Minute, s.PastDueSubscriptionsJob)
+		}},
+		{"invoice_pdf", s.isJobEnabled("invoice_pdf"), func(ctx context.Context) error {
+			return s.runJob(ctx, "invoice_pdf", s.cfg.BatchSize, 5*time.Minute, s.InvoicePDFJob)
+		}},
+		{"dunning", s.isJobEnabled("dunning"), func(ctx context.Context) error {
+			return s.runJob(ctx, "dunning", s.cfg.BatchSize, 5*time.Minute, s.DunningJob)
+		}},
 		{"sla_evaluation", s.isJobEnabled("sla_evaluation"), func(ctx context.Context) error {
 			return s.runJob(ctx, "sla_evaluation", s.cfg.BatchSize, 30*time.Second, s.SLAEvaluationJob)
 		}},
 		{"finops_scoring", s.isJobEnabled("finops_scoring"), func(ctx context.Context) error {
 			return s.runJob(ctx, "finops_scoring", 1, 24*time.Hour, s.FinOpsScoringJob)
 		}},
+		{"finops_scoring_weekly", s.isJobEnabled("finops_scoring_weekly"), func(ctx context.Context) error {
+			return s.runJob(ctx, "finops_scoring_weekly", 1, 24*time.Hour, s.FinOpsWeeklyScoringJob)
+		}},
+		{"finops_scoring_monthly", s.isJobEnabled("finops_scoring_monthly"), func(ctx context.Context) error {
+			return s.runJob(ctx, "finops_scoring_monthly", 1, 24*time.Hour, s.FinOpsMonthlyScoringJob)
+		}},
 		{"cleanup_webhook_logs", s.isJobEnabled("cleanup_webhook_logs"), func(ctx context.Context) error {
 			return s.runJob(ctx, "cleanup_webhook_logs", 1, 24*time.Hour, s.ResizeWebhookLogsJob)
 		}},
+		{"cleanup_processed_webhook_events", s.isJobEnabled("cleanup_processed_webhook_events"), func(ctx context.Context) error {
+			return s.runJob(ctx, "cleanup_processed_webhook_events", 1, 24*time.Hour, s.CleanupProcessedWebhookEventsJob)
+		}},
+		{"cleanup_idempotency_keys", s.isJobEnabled("cleanup_idempotency_keys"), func(ctx context.Context) error {
+			return s.runJob(ctx, "cleanup_idempotency_keys", 1, time.Hour, s.CleanupExpiredIdempotencyKeysJob)
+		}},
 		{"notification_dispatcher", s.isJobEnabled("notification_dispatcher") && s.integrationDispatcher != nil, func(ctx context.Context) error {
 			return s.runJob(ctx, "notification_dispatcher", s.cfg.BatchSize, 30*time.Second, s.integrationDispatcher.ProcessEvents)
 		}},
+		{"webhook_delivery", s.isJobEnabled("webhook_delivery") && s.webhookDispatcher != nil, func(ctx context.Context) error {
+			return s.runJob(ctx, "webhook_delivery", s.cfg.BatchSize, 2*time.Minute, s.webhookDispatcher.ProcessEvents)
+		}},
 	}
 
 	for _, job := range otherJobs {
@@ -560,7 +613,43 @@ func (s *Scheduler) CloseAfterRatingJob(ctx context.Context) error {
 				continue
 			}
 
+			// Cycles whose items are all billed in advance have nothing left to
+			// post or invoice at close.
+			hasArrears, err := s.hasRatingResultsForPhase(ctx, cycle.ID, billingcycledomain.BillingPhaseArrears)
+			if err != nil {
+				jobErr = errors.Join(jobErr, err)
+				s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "close_after_rating", cycle.OrgID, err,
+					zap.String("cycle_id", idString(cycle.ID)),
+					zap.String("subscription_id", idString(cycle.SubscriptionID)),
+				)
+				_ = s.recordCycleErrorWithMetrics(ctx, cycle.ID, obsmetrics.CycleStageCloseAfterRating, err)
+				continue
+			}
+
 			cycleCtx := s.withAuditContext(ctx, cycle.SubscriptionID.String(), cycle.ID.String())
+			if !hasArrears {
+				if _, err := s.markCycleClosed(ctx, cycle.ID, now); err != nil {
+					jobErr = errors.Join(jobErr, err)
+					s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "close_after_rating", cycle.OrgID, err,
+						zap.String("cycle_id", idString(cycle.ID)),
+						zap.String("subscription_id", idString(cycle.SubscriptionID)),
+					)
+					_ = s.recordCycleErrorWithMetrics(ctx, cycle.ID, obsmetrics.CycleStageCloseAfterRating, err)
+					continue
+				}
+				if err := s.markCycleInvoiced(ctx, cycle.ID, now); err != nil {
+					jobErr = errors.Join(jobErr, err)
+					s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "close_after_rating", cycle.OrgID, err,
+						zap.String("cycle_id", idString(cycle.ID)),
+						zap.String("subscription_id", idString(cycle.SubscriptionID)),
+					)
+					_ = s.recordCycleErrorWithMetrics(ctx, cycle.ID, obsmetrics.CycleStageCloseAfterRating, err)
+					continue
+				}
+				run.AddProcessed(1)
+				continue
+			}
+
 			if err := s.ensureLedgerEntryForCycle(cycleCtx, cycle); err != nil {
 				jobErr = errors.Join(jobErr, err)
 				s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "close_after_rating", cycle.OrgID, err,
@@ -703,7 +792,7 @@ func (s *Scheduler) InvoiceJob(ctx context.Context) error {
 					)
 					continue
 				}
-				if err := s.invoiceSvc.FinalizeInvoice(cycleCtx, invoice.ID.String()); err != nil {
+				if _, err := s.invoiceSvc.FinalizeInvoice(cycleCtx, invoice.ID.String()); err != nil {
 					jobErr = errors.Join(jobErr, err)
 					s.logSchedulerError(ctx, run, "invoice.finalize.failed", "invoice", cycle.OrgID, err,
 						zap.String("cycle_id", idString(cycle.ID)),
@@ -938,6 +1027,10 @@ func (s *Scheduler) ensureSubscriptionCycle(ctx context.Context, tx *gorm.DB, su
 		return err
 	}
 
+	if err := s.lockSubscriptionCycles(ctx, tx, subscription.ID); err != nil {
+		return err
+	}
+
 	openCycle, openCount, err := s.findOpenCycle(ctx, tx, subscription.OrgID, subscription.ID)
 	if err != nil {
 		return err
@@ -995,9 +1088,17 @@ func (s *Scheduler) ensureSubscriptionCycle(ctx context.Context, tx *gorm.DB, su
 	}
 
 	cycleID := s.genID.Generate()
-	if err := s.insertCycle(ctx, tx, cycleID, subscription.OrgID, subscription.ID, periodStart, periodEnd, now); err != nil {
+	inserted, err := s.insertCycle(ctx, tx, cycleID, subscription.OrgID, subscription.ID, periodStart, periodEnd, now)
+	if err != nil {
 		return err
 	}
+	if !inserted {
+		s.logger(s.withLogContext(ctx, subscription.OrgID)).Info("billing cycle already opened",
+			zap.String("subscription_id", idString(subscription.ID)),
+			zap.Time("period_start", periodStart),
+		)
+		return nil
+	}
 	*events = append(*events, auditEvent{
 		OrgID:          subscription.OrgID,
 		Action:         "billing_cycle.opened",
@@ -1015,6 +1116,8 @@ func (s *Scheduler) ensureSubscriptionCycle(ctx context.Context, tx *gorm.DB, su
 
 func nextPeriodEnd(start time.Time, cycleType string) (time.Time, error) {
 	switch strings.ToLower(strings.TrimSpace(cycleType)) {
+	case "yearly":
+		return start.AddDate(1, 0, 0), nil
 	case "monthly":
 		return start.AddDate(0, 1, 0), nil
 	case "weekly":
@@ -1108,6 +1211,48 @@ func (s *Scheduler) FinOpsScoringJob(ctx context.Context) error {
 	return nil
 }
 
+// FinOpsWeeklyScoringJob rolls up the previous week on Mondays (UTC), once
+// the daily job has scored Sunday.
+func (s *Scheduler) FinOpsWeeklyScoringJob(ctx context.Context) error {
+	if s.clock.Now(ctx).UTC().Weekday() != time.Monday {
+		return nil
+	}
+
+	ctx, run, owner := s.ensureJobRun(ctx, "finops_scoring_weekly", 1)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	if err := s.billingOperationsSvc.AggregateWeeklyPerformance(ctx); err != nil {
+		s.logSchedulerError(ctx, run, "finops.scoring.weekly.failed", "finops_scoring_weekly", 0, err)
+		return err
+	}
+
+	return nil
+}
+
+// FinOpsMonthlyScoringJob rolls up the previous month on the first of the
+// month (UTC).
+func (s *Scheduler) FinOpsMonthlyScoringJob(ctx context.Context) error {
+	if s.clock.Now(ctx).UTC().Day() != 1 {
+		return nil
+	}
+
+	ctx, run, owner := s.ensureJobRun(ctx, "finops_scoring_monthly", 1)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	if err := s.billingOperationsSvc.AggregateMonthlyPerformance(ctx); err != nil {
+		s.logSchedulerError(ctx, run, "finops.scoring.monthly.failed", "finops_scoring_monthly", 0, err)
+		return err
+	}
+
+	return nil
+}
+
 // TriggerSimulationStep runs the deterministic simulation pipeline for a specific Test Clock.
 // It executes key billing jobs synchronously using the simulated time from the context.
 func (s *Scheduler) TriggerSimulationStep(ctx context.Context, testClockID snowflake.ID, simulatedTime time.Time) error {
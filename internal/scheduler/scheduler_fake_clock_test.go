@@ -16,6 +16,7 @@ import (
 	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
 	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
 	obsmetrics "github.com/railzwaylabs/railzway/internal/observability/metrics"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
 	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -35,6 +36,18 @@ func (m *mockRatingSvc) RunRating(ctx context.Context, cycleID string) error {
 	`, cycleID, 2010735548360036353, nil, "USD", 100.0).Error
 }
 
+func (m *mockRatingSvc) RunAdvanceRating(ctx context.Context, cycleID string) error {
+	return nil
+}
+
+func (m *mockRatingSvc) RunThresholdRating(ctx context.Context, cycleID string) error {
+	return nil
+}
+
+func (m *mockRatingSvc) DryRunRating(ctx context.Context, cycleID string) ([]ratingdomain.RatingResult, error) {
+	return nil, nil
+}
+
 type mockInvoiceSvc struct {
 	genFunc func(ctx context.Context, cycleID string) (*invoicedomain.Invoice, error)
 	finFunc func(ctx context.Context, invoiceID string) error
@@ -55,15 +68,42 @@ func (m *mockInvoiceSvc) GenerateInvoice(ctx context.Context, billingCycleID str
 	}
 	return nil, nil
 }
-func (m *mockInvoiceSvc) FinalizeInvoice(ctx context.Context, invoiceID string) error {
+func (m *mockInvoiceSvc) GenerateAdvanceInvoice(ctx context.Context, billingCycleID string) (*invoicedomain.Invoice, error) {
+	return nil, nil
+}
+func (m *mockInvoiceSvc) GenerateThresholdInvoice(ctx context.Context, billingCycleID string) (*invoicedomain.Invoice, error) {
+	return nil, invoicedomain.ErrMissingRatingResults
+}
+func (m *mockInvoiceSvc) CreateOneTimeCharge(ctx context.Context, req invoicedomain.CreateOneTimeChargeRequest) (*invoicedomain.Invoice, error) {
+	return nil, nil
+}
+func (m *mockInvoiceSvc) FinalizeInvoice(ctx context.Context, invoiceID string) (*invoicedomain.Invoice, error) {
 	if m.finFunc != nil {
-		return m.finFunc(ctx, invoiceID)
+		return nil, m.finFunc(ctx, invoiceID)
 	}
-	return nil
+	return nil, nil
 }
 func (m *mockInvoiceSvc) VoidInvoice(ctx context.Context, invoiceID string, reason string) error {
 	return nil
 }
+func (m *mockInvoiceSvc) CreditCanceledSubscription(ctx context.Context, subscriptionID string) error {
+	return nil
+}
+func (m *mockInvoiceSvc) RetryFailedAutoCharges(ctx context.Context, maxRetries int, limit int) (int, error) {
+	return 0, nil
+}
+func (m *mockInvoiceSvc) ChargeInvoice(ctx context.Context, invoiceID string) (invoicedomain.ChargeInvoiceResponse, error) {
+	return invoicedomain.ChargeInvoiceResponse{}, nil
+}
+func (m *mockInvoiceSvc) RequestInvoicePDF(ctx context.Context, invoiceID string) (invoicedomain.InvoicePDFResponse, error) {
+	return invoicedomain.InvoicePDFResponse{}, nil
+}
+func (m *mockInvoiceSvc) GetInvoicePDF(ctx context.Context, invoiceID string) (invoicedomain.InvoicePDFResponse, error) {
+	return invoicedomain.InvoicePDFResponse{}, nil
+}
+func (m *mockInvoiceSvc) GenerateQueuedInvoicePDFs(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
 
 type mockLedgerSvc struct{}
 
@@ -71,6 +111,18 @@ func (m *mockLedgerSvc) CreateEntry(ctx context.Context, orgID snowflake.ID, sou
 	return nil
 }
 
+func (m *mockLedgerSvc) CustomerBalance(ctx context.Context, orgID, customerID snowflake.ID, currency string, asOf time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockLedgerSvc) ListCustomerEntries(ctx context.Context, orgID, customerID snowflake.ID, currency string, from, to time.Time) ([]ledgerdomain.CustomerEntry, error) {
+	return nil, nil
+}
+
+func (m *mockLedgerSvc) EnsureAccounts(ctx context.Context, orgID snowflake.ID) error {
+	return nil
+}
+
 type mockSubscriptionSvc struct{}
 
 func (m *mockSubscriptionSvc) List(context.Context, subscriptiondomain.ListSubscriptionRequest) (subscriptiondomain.ListSubscriptionResponse, error) {
@@ -97,12 +149,44 @@ func (m *mockSubscriptionSvc) GetSubscriptionItem(context.Context, subscriptiond
 func (m *mockSubscriptionSvc) TransitionSubscription(ctx context.Context, id string, status subscriptiondomain.SubscriptionStatus, reason subscriptiondomain.TransitionReason) error {
 	return nil
 }
+func (m *mockSubscriptionSvc) CancelSubscription(ctx context.Context, req subscriptiondomain.CancelSubscriptionRequest) error {
+	return nil
+}
+func (m *mockSubscriptionSvc) ListBillingCycles(ctx context.Context, req subscriptiondomain.ListBillingCyclesRequest) (subscriptiondomain.ListBillingCyclesResponse, error) {
+	return subscriptiondomain.ListBillingCyclesResponse{}, nil
+}
+func (m *mockSubscriptionSvc) ListEvents(ctx context.Context, req subscriptiondomain.ListSubscriptionEventsRequest) (subscriptiondomain.ListSubscriptionEventsResponse, error) {
+	return subscriptiondomain.ListSubscriptionEventsResponse{}, nil
+}
+
+func (m *mockSubscriptionSvc) UpdateMetadata(ctx context.Context, req subscriptiondomain.UpdateMetadataRequest) (subscriptiondomain.Subscription, error) {
+	return subscriptiondomain.Subscription{}, nil
+}
+
+func (m *mockSubscriptionSvc) CheckEntitlement(ctx context.Context, req subscriptiondomain.CheckEntitlementRequest) (subscriptiondomain.EntitlementCheckResponse, error) {
+	return subscriptiondomain.EntitlementCheckResponse{}, nil
+}
+func (m *mockSubscriptionSvc) GetSeatUsage(ctx context.Context, subscriptionID string) (subscriptiondomain.SeatUsageResponse, error) {
+	return subscriptiondomain.SeatUsageResponse{}, nil
+}
+func (m *mockSubscriptionSvc) ListItems(ctx context.Context, req subscriptiondomain.ListSubscriptionItemsRequest) (subscriptiondomain.ListSubscriptionItemsResponse, error) {
+	return subscriptiondomain.ListSubscriptionItemsResponse{}, nil
+}
+func (m *mockSubscriptionSvc) UpdateItemQuantity(ctx context.Context, req subscriptiondomain.UpdateItemQuantityRequest) (subscriptiondomain.UpdateItemQuantityResponse, error) {
+	return subscriptiondomain.UpdateItemQuantityResponse{}, nil
+}
 func (m *mockSubscriptionSvc) ValidateUsageEntitlement(ctx context.Context, subscriptionID, meterID snowflake.ID, at time.Time) error {
 	return nil
 }
 func (m *mockSubscriptionSvc) ChangePlan(ctx context.Context, req subscriptiondomain.ChangePlanRequest) error {
 	return nil
 }
+func (m *mockSubscriptionSvc) PreviewProration(ctx context.Context, req subscriptiondomain.PreviewProrationRequest) (subscriptiondomain.PreviewProrationResponse, error) {
+	return subscriptiondomain.PreviewProrationResponse{}, nil
+}
+func (m *mockSubscriptionSvc) GetEntitlementHistory(context.Context, subscriptiondomain.GetEntitlementHistoryRequest) (subscriptiondomain.EntitlementHistoryResponse, error) {
+	return subscriptiondomain.EntitlementHistoryResponse{}, nil
+}
 
 type mockAuditSvc struct{}
 
@@ -122,7 +206,7 @@ func (m *mockAuthzSvc) Authorize(ctx context.Context, subject, domain, object, a
 
 type mockBillingOpsSvc struct{}
 
-func (m *mockBillingOpsSvc) ListOverdueInvoices(ctx context.Context, limit int) (billingopsdomain.OverdueInvoicesResponse, error) {
+func (m *mockBillingOpsSvc) ListOverdueInvoices(ctx context.Context, limit int, filter billingopsdomain.AssignmentFilter) (billingopsdomain.OverdueInvoicesResponse, error) {
 	return billingopsdomain.OverdueInvoicesResponse{}, nil
 }
 func (m *mockBillingOpsSvc) ListOutstandingCustomers(ctx context.Context, limit int) (billingopsdomain.OutstandingCustomersResponse, error) {
@@ -131,7 +215,7 @@ func (m *mockBillingOpsSvc) ListOutstandingCustomers(ctx context.Context, limit
 func (m *mockBillingOpsSvc) ListPaymentIssues(ctx context.Context, limit int) (billingopsdomain.PaymentIssuesResponse, error) {
 	return billingopsdomain.PaymentIssuesResponse{}, nil
 }
-func (m *mockBillingOpsSvc) GetOperations(ctx context.Context, limit int) (billingopsdomain.BillingOperationsResponse, error) {
+func (m *mockBillingOpsSvc) GetOperations(ctx context.Context, limit int, filter billingopsdomain.AssignmentFilter) (billingopsdomain.BillingOperationsResponse, error) {
 	return billingopsdomain.BillingOperationsResponse{}, nil
 }
 func (m *mockBillingOpsSvc) RecordAction(ctx context.Context, req billingopsdomain.RecordActionRequest) (billingopsdomain.RecordActionResponse, error) {
@@ -143,21 +227,36 @@ func (m *mockBillingOpsSvc) ClaimAssignment(ctx context.Context, req billingopsd
 func (m *mockBillingOpsSvc) ReleaseAssignment(ctx context.Context, req billingopsdomain.ReleaseAssignmentRequest) error {
 	return nil
 }
+func (m *mockBillingOpsSvc) BulkClaimAssignments(ctx context.Context, reqs []billingopsdomain.ClaimAssignmentRequest) (billingopsdomain.BulkAssignmentResponse, error) {
+	return billingopsdomain.BulkAssignmentResponse{}, nil
+}
+func (m *mockBillingOpsSvc) BulkReleaseAssignments(ctx context.Context, reqs []billingopsdomain.ReleaseAssignmentRequest) (billingopsdomain.BulkAssignmentResponse, error) {
+	return billingopsdomain.BulkAssignmentResponse{}, nil
+}
 func (m *mockBillingOpsSvc) ResolveAssignment(ctx context.Context, req billingopsdomain.ResolveAssignmentRequest) error {
 	return nil
 }
+func (m *mockBillingOpsSvc) GetAssignmentDetail(ctx context.Context, entityType, entityID string) (billingopsdomain.AssignmentDetailResponse, error) {
+	return billingopsdomain.AssignmentDetailResponse{}, nil
+}
 func (m *mockBillingOpsSvc) EvaluateSLAs(ctx context.Context) error {
 	return nil
 }
 func (m *mockBillingOpsSvc) CalculatePerformance(ctx context.Context, userID string, start, end time.Time) (billingopsdomain.FinOpsScoreSnapshot, error) {
 	return billingopsdomain.FinOpsScoreSnapshot{}, nil
 }
-func (m *mockBillingOpsSvc) GetPerformanceHistory(ctx context.Context, userID string, limit int) ([]billingopsdomain.FinOpsScoreSnapshot, error) {
-	return nil, nil
+func (m *mockBillingOpsSvc) GetPerformanceHistory(ctx context.Context, userID string, req billingopsdomain.PerformanceHistoryRequest) (billingopsdomain.PerformanceHistoryResponse, error) {
+	return billingopsdomain.PerformanceHistoryResponse{}, nil
 }
 func (m *mockBillingOpsSvc) AggregateDailyPerformance(ctx context.Context) error {
 	return nil
 }
+func (m *mockBillingOpsSvc) AggregateWeeklyPerformance(ctx context.Context) error {
+	return nil
+}
+func (m *mockBillingOpsSvc) AggregateMonthlyPerformance(ctx context.Context) error {
+	return nil
+}
 func (m *mockBillingOpsSvc) GetMyPerformance(ctx context.Context, userID string, req billingopsdomain.GetPerformanceRequest) (*billingopsdomain.PerformanceResponse, error) {
 	return nil, nil
 }
@@ -182,6 +281,12 @@ func (m *mockBillingOpsSvc) GetExposureAnalysis(ctx context.Context, req billing
 func (m *mockBillingOpsSvc) RecordFollowUp(ctx context.Context, req billingopsdomain.RecordFollowUpRequest) error {
 	return nil
 }
+func (m *mockBillingOpsSvc) SendDunningReminders(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
+func (m *mockBillingOpsSvc) UpdateEmailDeliveryStatus(ctx context.Context, update billingopsdomain.EmailDeliveryUpdate) error {
+	return nil
+}
 func (m *mockBillingOpsSvc) GetInvoicePayments(ctx context.Context, invoiceID string) (billingopsdomain.InvoicePaymentsResponse, error) {
 	return billingopsdomain.InvoicePaymentsResponse{}, nil
 }
@@ -226,13 +331,53 @@ func TestScheduler_RunOnce_FakeClock_30Days(t *testing.T) {
 		CREATE TABLE subscriptions (
 			id INTEGER PRIMARY KEY,
 			org_id INTEGER,
+			customer_id INTEGER,
 			status TEXT,
+			collection_mode TEXT,
+			start_at DATETIME,
 			activated_at DATETIME,
-			billing_cycle_type TEXT
+			billing_cycle_type TEXT,
+			cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+			cancel_at DATETIME,
+			spend_alert_threshold_cents INTEGER,
+			past_due_at DATETIME,
+			created_at DATETIME
 		)
 	`).Error; err != nil {
 		t.Fatalf("create subscriptions table: %v", err)
 	}
+	// subscription_spend_alerts (for spend alert lookups)
+	if err := db.Exec(`
+		CREATE TABLE subscription_spend_alerts (
+			id INTEGER PRIMARY KEY,
+			billing_cycle_id INTEGER,
+			threshold_cents INTEGER
+		)
+	`).Error; err != nil {
+		t.Fatalf("create subscription_spend_alerts table: %v", err)
+	}
+	// customer_payment_methods (for scheduled starts)
+	if err := db.Exec(`
+		CREATE TABLE customer_payment_methods (
+			id INTEGER PRIMARY KEY,
+			customer_id INTEGER,
+			is_default BOOLEAN NOT NULL DEFAULT FALSE
+		)
+	`).Error; err != nil {
+		t.Fatalf("create customer_payment_methods table: %v", err)
+	}
+	// subscription_items (for advance and threshold billing lookups)
+	if err := db.Exec(`
+		CREATE TABLE subscription_items (
+			id INTEGER PRIMARY KEY,
+			subscription_id INTEGER,
+			meter_id INTEGER,
+			usage_behavior TEXT,
+			billing_threshold NUMERIC
+		)
+	`).Error; err != nil {
+		t.Fatalf("create subscription_items table: %v", err)
+	}
 	// billing_cycles table
 	if err := db.Exec(`
 		CREATE TABLE billing_cycles (
@@ -246,6 +391,7 @@ func TestScheduler_RunOnce_FakeClock_30Days(t *testing.T) {
 			opened_at DATETIME,
 			closing_started_at DATETIME,
 			rating_completed_at DATETIME,
+			advance_invoiced_at DATETIME,
 			invoiced_at DATETIME,
 			invoice_finalized_at DATETIME,
 			closed_at DATETIME,
@@ -262,18 +408,33 @@ func TestScheduler_RunOnce_FakeClock_30Days(t *testing.T) {
 		CREATE TABLE invoices (
 			id INTEGER PRIMARY KEY,
 			billing_cycle_id INTEGER,
+			subscription_id INTEGER,
 			status TEXT,
-			finalized_at DATETIME
+			total_amount INTEGER,
+			metadata TEXT,
+			finalized_at DATETIME,
+			paid_at DATETIME,
+			voided_at DATETIME
 		)
 	`).Error; err != nil {
 		t.Fatalf("create invoices table: %v", err)
 	}
+	// organization_billing_preferences (for past due grace periods)
+	if err := db.Exec(`
+		CREATE TABLE organization_billing_preferences (
+			org_id INTEGER PRIMARY KEY,
+			past_due_grace_days INTEGER NOT NULL DEFAULT 7
+		)
+	`).Error; err != nil {
+		t.Fatalf("create organization_billing_preferences table: %v", err)
+	}
 	// rating_results (for hasRatingResults check)
 	if err := db.Exec(`
 		CREATE TABLE rating_results (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			billing_cycle_id INTEGER,
 			org_id INTEGER,
+			billing_phase TEXT DEFAULT 'arrears',
 			meter_id TEXT,
 			currency TEXT,
 			amount REAL
@@ -309,6 +470,23 @@ func TestScheduler_RunOnce_FakeClock_30Days(t *testing.T) {
 	`).Error; err != nil {
 		t.Fatalf("create ledger_accounts table: %v", err)
 	}
+	// ledger_entries and ledger_entry_lines
+	if err := db.AutoMigrate(&ledgerdomain.LedgerEntry{}, &ledgerdomain.LedgerEntryLine{}); err != nil {
+		t.Fatalf("create ledger entry tables: %v", err)
+	}
+
+	// idempotency_keys
+	if err := db.Exec(`
+		CREATE TABLE idempotency_keys (
+			id INTEGER PRIMARY KEY,
+			org_id INTEGER,
+			endpoint TEXT,
+			idempotency_key TEXT,
+			expires_at DATETIME
+		)
+	`).Error; err != nil {
+		t.Fatalf("create idempotency_keys table: %v", err)
+	}
 
 	// 2. Setup Dependencies
 	node, _ := snowflake.NewNode(1)
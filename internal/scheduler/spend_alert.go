@@ -0,0 +1,254 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/authorization"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/currency"
+	"github.com/railzwaylabs/railzway/internal/events"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"github.com/railzwaylabs/railzway/internal/providers/email"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const spendAlertEmailTemplate = "spend_alert"
+
+type spendAlertTarget struct {
+	ThresholdCents int64        `gorm:"column:threshold_cents"`
+	CustomerID     snowflake.ID `gorm:"column:customer_id"`
+	CustomerEmail  string       `gorm:"column:customer_email"`
+	OrgName        string       `gorm:"column:org_name"`
+	SupportEmail   string       `gorm:"column:support_email"`
+}
+
+// SpendAlertJob warns customers whose open cycle's rated amount reached the
+// subscription's spend alert threshold. Each cycle alerts once per threshold:
+// the alert is recorded and published as a subscription.spend_alert.triggered
+// event, and the customer is emailed when an email provider is configured.
+func (s *Scheduler) SpendAlertJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "spend_alert", s.cfg.BatchSize)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	var jobErr error
+
+	cycles, err := s.fetchBillingCyclesForWork(ctx,
+		`status = ?
+		 AND EXISTS (
+			 SELECT 1 FROM subscriptions sub
+			 WHERE sub.id = billing_cycles.subscription_id
+			   AND sub.spend_alert_threshold_cents > 0
+			   AND NOT EXISTS (
+				   SELECT 1 FROM subscription_spend_alerts ssa
+				   WHERE ssa.billing_cycle_id = billing_cycles.id
+				     AND ssa.threshold_cents = sub.spend_alert_threshold_cents
+			   )
+		 )`,
+		[]any{billingcycledomain.BillingCycleStatusOpen},
+		s.cfg.BatchSize,
+	)
+	if err != nil {
+		s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "spend_alert", 0, err)
+		return err
+	}
+
+	for _, cycle := range cycles {
+		if err := s.ensureOrgActive(ctx, cycle.OrgID); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.org.inactive", "spend_alert", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+		if err := s.authorizeSystem(ctx, cycle.OrgID, authorization.ObjectBillingCycle, authorization.ActionBillingCycleRate); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.authorize.failed", "spend_alert", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+
+		cycleCtx := s.withAuditContext(ctx, cycle.SubscriptionID.String(), cycle.ID.String())
+		alerted, err := s.evaluateSpendAlert(cycleCtx, cycle)
+		if err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "spend_alert.evaluate.failed", "spend_alert", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+		if alerted {
+			run.AddProcessed(1)
+		}
+	}
+
+	return jobErr
+}
+
+// evaluateSpendAlert records and publishes a spend alert for cycle when its
+// cycle-to-date rated amount reached the subscription's threshold, and
+// reports whether it did. The amount is what the cycle billed in advance or
+// by threshold so far plus a dry run of its closing rating; the minimum
+// commitment true-up is not spend and is left out.
+func (s *Scheduler) evaluateSpendAlert(ctx context.Context, cycle WorkBillingCycle) (bool, error) {
+	var targets []spendAlertTarget
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT sub.spend_alert_threshold_cents AS threshold_cents, sub.customer_id,
+		        COALESCE(c.email, '') AS customer_email,
+		        COALESCE(o.name, '') AS org_name,
+		        COALESCE(o.support_email, '') AS support_email
+		 FROM subscriptions sub
+		 LEFT JOIN customers c ON c.id = sub.customer_id
+		 LEFT JOIN organizations o ON o.id = sub.org_id
+		 WHERE sub.org_id = ? AND sub.id = ? AND sub.spend_alert_threshold_cents > 0`,
+		cycle.OrgID,
+		cycle.SubscriptionID,
+	).Scan(&targets).Error; err != nil {
+		return false, err
+	}
+	if len(targets) == 0 {
+		return false, nil
+	}
+	target := targets[0]
+
+	var billed struct {
+		Total    int64  `gorm:"column:total"`
+		Currency string `gorm:"column:currency"`
+	}
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT COALESCE(SUM(amount), 0) AS total, COALESCE(MAX(currency), '') AS currency
+		 FROM rating_results
+		 WHERE billing_cycle_id = ? AND billing_phase IN (?, ?)`,
+		cycle.ID,
+		billingcycledomain.BillingPhaseAdvance,
+		billingcycledomain.BillingPhaseThreshold,
+	).Scan(&billed).Error; err != nil {
+		return false, err
+	}
+
+	results, err := s.ratingSvc.DryRunRating(orgcontext.WithOrgID(ctx, int64(cycle.OrgID)), cycle.ID.String())
+	if err != nil {
+		return false, err
+	}
+	amount, currency := billed.Total, billed.Currency
+	for _, result := range results {
+		if result.Source == ratingdomain.RatingSourceMinimumCommitment {
+			continue
+		}
+		amount += result.Amount
+		if currency == "" {
+			currency = result.Currency
+		}
+	}
+	if amount < target.ThresholdCents {
+		return false, nil
+	}
+
+	alert := subscriptiondomain.SpendAlert{
+		ID:             s.genID.Generate(),
+		OrgID:          cycle.OrgID,
+		SubscriptionID: cycle.SubscriptionID,
+		BillingCycleID: cycle.ID,
+		ThresholdCents: target.ThresholdCents,
+		AmountCents:    amount,
+		Currency:       currency,
+		CreatedAt:      s.clock.Now(ctx).UTC(),
+	}
+	recorded := false
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&alert)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		recorded = true
+		if s.outbox == nil {
+			return nil
+		}
+		payload := events.SpendAlertPayload{
+			SubscriptionID: cycle.SubscriptionID.String(),
+			CustomerID:     target.CustomerID.String(),
+			BillingCycleID: cycle.ID.String(),
+			ThresholdCents: alert.ThresholdCents,
+			AmountCents:    alert.AmountCents,
+			Currency:       alert.Currency,
+		}
+		return s.outbox.PublishTx(ctx, tx, events.Event{
+			OrgID:     cycle.OrgID,
+			Type:      events.EventSubscriptionSpendAlert,
+			Payload:   payload.ToMap(),
+			DedupeKey: fmt.Sprintf("%s:%s:%d", events.EventSubscriptionSpendAlert, cycle.ID.String(), alert.ThresholdCents),
+		})
+	}); err != nil {
+		return false, err
+	}
+	if !recorded {
+		return false, nil
+	}
+
+	s.log.Info("spend alert triggered",
+		zap.String("subscription_id", cycle.SubscriptionID.String()),
+		zap.String("cycle_id", cycle.ID.String()),
+		zap.Int64("threshold_cents", alert.ThresholdCents),
+		zap.Int64("amount_cents", alert.AmountCents),
+	)
+
+	// The alert is already recorded, so a failed email is not retried.
+	if err := s.sendSpendAlertEmail(ctx, cycle, target, alert); err != nil {
+		s.log.Warn("failed to send spend alert email",
+			zap.Error(err),
+			zap.String("subscription_id", cycle.SubscriptionID.String()),
+			zap.String("cycle_id", cycle.ID.String()),
+		)
+	}
+	return true, nil
+}
+
+func (s *Scheduler) sendSpendAlertEmail(ctx context.Context, cycle WorkBillingCycle, target spendAlertTarget, alert subscriptiondomain.SpendAlert) error {
+	if s.email == nil || strings.TrimSpace(target.CustomerEmail) == "" {
+		return nil
+	}
+	supportEmail := strings.TrimSpace(target.SupportEmail)
+	if supportEmail == "" {
+		supportEmail = "support@railzway.com"
+	}
+
+	data := struct {
+		OrgName         string
+		Amount          string
+		Threshold       string
+		PeriodEnd       string
+		OrgContactEmail string
+	}{
+		OrgName:         target.OrgName,
+		Amount:          currency.Format(alert.AmountCents, alert.Currency),
+		Threshold:       currency.Format(alert.ThresholdCents, alert.Currency),
+		PeriodEnd:       cycle.PeriodEnd.UTC().Format("January 2, 2006"),
+		OrgContactEmail: supportEmail,
+	}
+
+	msg := email.EmailMessage{
+		To:         []string{target.CustomerEmail},
+		SenderName: target.OrgName,
+		ReplyTo:    supportEmail,
+		Subject:    fmt.Sprintf("Your %s usage has reached %s", target.OrgName, data.Threshold),
+		MessageID:  email.NewMessageID(alert.ID.String()),
+	}
+	return s.email.SendTemplate(ctx, msg, spendAlertEmailTemplate, data)
+}
@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	"github.com/railzwaylabs/railzway/internal/events"
+	"github.com/railzwaylabs/railzway/internal/providers/email"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// dryRunRatingStub returns fixed dry run results; other rating calls are
+// not expected.
+type dryRunRatingStub struct {
+	ratingdomain.Service
+	results []ratingdomain.RatingResult
+}
+
+func (r *dryRunRatingStub) DryRunRating(ctx context.Context, cycleID string) ([]ratingdomain.RatingResult, error) {
+	return r.results, nil
+}
+
+type emailRecorder struct {
+	email.NoOpProvider
+	templates []string
+	to        []string
+}
+
+func (r *emailRecorder) SendTemplate(ctx context.Context, msg email.EmailMessage, templateName string, data interface{}) error {
+	r.templates = append(r.templates, templateName)
+	r.to = append(r.to, msg.To...)
+	return nil
+}
+
+func TestEvaluateSpendAlert_FiresOncePerCycleThreshold(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&subscriptiondomain.Subscription{}, &subscriptiondomain.SpendAlert{}, &ratingdomain.RatingResult{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE customers (id BIGINT PRIMARY KEY, email TEXT)`,
+		`CREATE TABLE organizations (id BIGINT PRIMARY KEY, name TEXT, support_email TEXT)`,
+		`CREATE TABLE billing_events (
+			id BIGINT PRIMARY KEY,
+			org_id BIGINT NOT NULL,
+			event_type TEXT NOT NULL,
+			payload TEXT NOT NULL DEFAULT '{}',
+			dedupe_key TEXT,
+			published BOOLEAN NOT NULL DEFAULT false,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE UNIQUE INDEX ux_billing_event_dedupe ON billing_events(org_id, dedupe_key)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("create table: %v", err)
+		}
+	}
+
+	node, _ := snowflake.NewNode(1)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	rating := &dryRunRatingStub{}
+	mailer := &emailRecorder{}
+	s := &Scheduler{
+		db:        db,
+		log:       zap.NewNop(),
+		genID:     node,
+		clock:     clock.NewFakeClock(start.AddDate(0, 0, 10)),
+		ratingSvc: rating,
+		outbox:    events.NewOutbox(db, node),
+		email:     mailer,
+	}
+	ctx := context.Background()
+
+	orgID := node.Generate()
+	customerID := node.Generate()
+	threshold := int64(5000)
+	subscription := subscriptiondomain.Subscription{
+		ID:                       node.Generate(),
+		OrgID:                    orgID,
+		CustomerID:               customerID,
+		Status:                   subscriptiondomain.SubscriptionStatusActive,
+		CollectionMode:           subscriptiondomain.SubscriptionCollectionModeChargeAutomatically,
+		StartAt:                  start,
+		BillingCycleType:         "MONTHLY",
+		SpendAlertThresholdCents: &threshold,
+		Metadata:                 datatypes.JSONMap{},
+	}
+	if err := db.Create(&subscription).Error; err != nil {
+		t.Fatalf("create subscription: %v", err)
+	}
+	if err := db.Exec(`INSERT INTO customers (id, email) VALUES (?, ?)`, customerID, "billing@example.com").Error; err != nil {
+		t.Fatalf("create customer: %v", err)
+	}
+	if err := db.Exec(`INSERT INTO organizations (id, name) VALUES (?, ?)`, orgID, "Acme").Error; err != nil {
+		t.Fatalf("create organization: %v", err)
+	}
+	cycle := WorkBillingCycle{ID: node.Generate(), OrgID: orgID, SubscriptionID: subscription.ID, PeriodStart: start, PeriodEnd: end}
+
+	// Usage billed by threshold so far plus the dry run stays below 50.00,
+	// and the minimum commitment true-up does not count.
+	if err := db.Create(&ratingdomain.RatingResult{
+		ID: node.Generate(), OrgID: orgID, SubscriptionID: subscription.ID, BillingCycleID: cycle.ID,
+		BillingPhase: string(billingcycledomain.BillingPhaseThreshold), PriceID: node.Generate(),
+		Quantity: 1, UnitPrice: 2000, Amount: 2000, Currency: "USD",
+		PeriodStart: start, PeriodEnd: end, Source: ratingdomain.RatingSourceThreshold, Checksum: node.Generate().String(),
+	}).Error; err != nil {
+		t.Fatalf("create rating result: %v", err)
+	}
+	rating.results = []ratingdomain.RatingResult{
+		{Amount: 2500, Currency: "USD", Source: "usage"},
+		{Amount: 10000, Currency: "USD", Source: ratingdomain.RatingSourceMinimumCommitment},
+	}
+	alerted, err := s.evaluateSpendAlert(ctx, cycle)
+	if err != nil {
+		t.Fatalf("evaluate spend alert: %v", err)
+	}
+	if alerted {
+		t.Fatalf("expected no alert below the threshold")
+	}
+
+	rating.results = []ratingdomain.RatingResult{{Amount: 3500, Currency: "USD", Source: "usage"}}
+	for i := 0; i < 2; i++ {
+		alerted, err = s.evaluateSpendAlert(ctx, cycle)
+		if err != nil {
+			t.Fatalf("evaluate spend alert: %v", err)
+		}
+		if alerted != (i == 0) {
+			t.Fatalf("run %d: expected alerted=%v, got %v", i, i == 0, alerted)
+		}
+	}
+
+	var alerts []subscriptiondomain.SpendAlert
+	if err := db.Find(&alerts).Error; err != nil {
+		t.Fatalf("load alerts: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].AmountCents != 5500 || alerts[0].ThresholdCents != threshold {
+		t.Fatalf("expected one alert of 5500 at threshold %d, got %+v", threshold, alerts)
+	}
+
+	var published []string
+	if err := db.Raw(`SELECT event_type FROM billing_events`).Scan(&published).Error; err != nil {
+		t.Fatalf("load events: %v", err)
+	}
+	if len(published) != 1 || published[0] != events.EventSubscriptionSpendAlert {
+		t.Fatalf("expected one spend alert event, got %v", published)
+	}
+	if len(mailer.templates) != 1 || mailer.templates[0] != spendAlertEmailTemplate || mailer.to[0] != "billing@example.com" {
+		t.Fatalf("expected one spend alert email to the customer, got %v %v", mailer.templates, mailer.to)
+	}
+
+	// Raising the threshold arms a new alert in the same cycle.
+	if err := db.Model(&subscriptiondomain.Subscription{}).Where("id = ?", subscription.ID).
+		Update("spend_alert_threshold_cents", 6000).Error; err != nil {
+		t.Fatalf("update threshold: %v", err)
+	}
+	rating.results = []ratingdomain.RatingResult{{Amount: 4500, Currency: "USD", Source: "usage"}}
+	alerted, err = s.evaluateSpendAlert(ctx, cycle)
+	if err != nil {
+		t.Fatalf("evaluate spend alert: %v", err)
+	}
+	if !alerted {
+		t.Fatalf("expected an alert for the raised threshold")
+	}
+}
@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/authorization"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"go.uber.org/zap"
+)
+
+// StartScheduledSubscriptionsJob activates draft subscriptions created with a
+// future start date once that date has passed. Subscriptions charged
+// automatically wait in draft until their customer has a default payment
+// method. ensure_cycles opens their first cycle at the start date.
+func (s *Scheduler) StartScheduledSubscriptionsJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "start_scheduled_subs", s.cfg.BatchSize)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	now := s.clock.Now(ctx)
+	var subscriptions []struct {
+		ID      snowflake.ID
+		OrgID   snowflake.ID
+		StartAt time.Time
+	}
+	if err := applyTestClockScope(ctx, s.db).WithContext(ctx).Raw(
+		`SELECT s.id, s.org_id, s.start_at
+		 FROM subscriptions s
+		 WHERE s.status = ?
+		   AND s.start_at > s.created_at
+		   AND s.start_at <= ?
+		   AND (s.collection_mode <> ? OR EXISTS (
+			   SELECT 1 FROM customer_payment_methods pm
+			   WHERE pm.customer_id = s.customer_id
+				 AND pm.is_default = TRUE
+		   ))
+		 ORDER BY s.start_at, s.id
+		 LIMIT ?`,
+		subscriptiondomain.SubscriptionStatusDraft,
+		now,
+		subscriptiondomain.SubscriptionCollectionModeChargeAutomatically,
+		s.cfg.BatchSize,
+	).Scan(&subscriptions).Error; err != nil {
+		s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "start_scheduled_subs", 0, err)
+		return err
+	}
+
+	var jobErr error
+	for _, subscription := range subscriptions {
+		if ctx.Err() != nil {
+			jobErr = errors.Join(jobErr, ctx.Err())
+			break
+		}
+
+		if err := s.ensureOrgActive(ctx, subscription.OrgID); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.org.inactive", "start_scheduled_subs", subscription.OrgID, err,
+				zap.String("subscription_id", idString(subscription.ID)),
+			)
+			continue
+		}
+
+		if err := s.authorizeSystem(ctx, subscription.OrgID, authorization.ObjectSubscription, authorization.ActionSubscriptionActivate); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.authorize.failed", "start_scheduled_subs", subscription.OrgID, err,
+				zap.String("subscription_id", idString(subscription.ID)),
+			)
+			continue
+		}
+
+		ctxWithOrg := orgcontext.WithOrgID(ctx, int64(subscription.OrgID))
+		ctxWithAudit := s.withAuditContext(ctxWithOrg, subscription.ID.String(), "")
+		if err := s.subscriptionSvc.TransitionSubscription(ctxWithAudit, subscription.ID.String(), subscriptiondomain.SubscriptionStatusActive, subscriptiondomain.TransitionReason("scheduler")); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "start_scheduled_subs", subscription.OrgID, err,
+				zap.String("subscription_id", idString(subscription.ID)),
+			)
+			continue
+		}
+		run.AddProcessed(1)
+
+		s.emitAuditEvent(ctxWithAudit, auditEvent{
+			OrgID:          subscription.OrgID,
+			Action:         "subscription.activate",
+			TargetType:     "subscription",
+			TargetID:       subscription.ID.String(),
+			SubscriptionID: subscription.ID.String(),
+			Metadata: map[string]any{
+				"reason":   "scheduler",
+				"start_at": subscription.StartAt.UTC().Format(time.RFC3339),
+			},
+		})
+	}
+
+	return jobErr
+}
@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/railzwaylabs/railzway/internal/authorization"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	obsmetrics "github.com/railzwaylabs/railzway/internal/observability/metrics"
+	"go.uber.org/zap"
+)
+
+// ThresholdBillingJob invoices the usage of open cycles whose metered items
+// crossed their billing threshold, instead of waiting for the cycle to close.
+// The closing invoice then only bills the remainder.
+func (s *Scheduler) ThresholdBillingJob(ctx context.Context) error {
+	ctx, run, owner := s.ensureJobRun(ctx, "threshold_invoice", s.cfg.MaxInvoiceBatchSize)
+	if owner {
+		s.logJobStart(ctx, run)
+		defer s.logJobFinish(ctx, run)
+	}
+
+	var jobErr error
+
+	cycles, err := s.fetchBillingCyclesForWork(ctx,
+		`status = ?
+		 AND EXISTS (
+			 SELECT 1 FROM subscription_items si
+			 WHERE si.subscription_id = billing_cycles.subscription_id
+			   AND si.meter_id IS NOT NULL
+			   AND si.billing_threshold > 0
+		 )`,
+		[]any{billingcycledomain.BillingCycleStatusOpen},
+		s.cfg.MaxInvoiceBatchSize,
+	)
+	if err != nil {
+		s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "threshold_invoice", 0, err)
+		return err
+	}
+
+	for _, cycle := range cycles {
+		s.logCycleClaimed(ctx, "threshold_invoice", cycle)
+		if err := s.ensureOrgActive(ctx, cycle.OrgID); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.org.inactive", "threshold_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+		if err := s.authorizeSystem(ctx, cycle.OrgID, authorization.ObjectBillingCycle, authorization.ActionBillingCycleRate); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.authorize.failed", "threshold_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+		if err := s.authorizeSystem(ctx, cycle.OrgID, authorization.ObjectInvoice, authorization.ActionInvoiceGenerate); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.authorize.failed", "threshold_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+
+		cycleCtx := s.withAuditContext(ctx, cycle.SubscriptionID.String(), cycle.ID.String())
+		if err := s.ratingSvc.RunThresholdRating(cycleCtx, cycle.ID.String()); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.cycle.process.failed", "threshold_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			_ = s.recordCycleErrorWithMetrics(ctx, cycle.ID, obsmetrics.CycleStageThresholdInvoice, err)
+			continue
+		}
+
+		// ErrMissingRatingResults means no item reached its threshold since
+		// the last threshold invoice.
+		invoice, err := s.invoiceSvc.GenerateThresholdInvoice(cycleCtx, cycle.ID.String())
+		if errors.Is(err, invoicedomain.ErrMissingRatingResults) {
+			continue
+		}
+		if err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "invoice.generate.failed", "threshold_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			_ = s.recordCycleErrorWithMetrics(ctx, cycle.ID, obsmetrics.CycleStageThresholdInvoice, err)
+			continue
+		}
+		run.AddProcessed(1)
+		s.logInvoiceGenerated(ctx, cycle, invoice.ID)
+
+		if !s.cfg.FinalizeInvoices || invoice.Status != invoicedomain.InvoiceStatusDraft {
+			continue
+		}
+		if err := s.authorizeSystem(ctx, cycle.OrgID, authorization.ObjectInvoice, authorization.ActionInvoiceFinalize); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "scheduler.authorize.failed", "threshold_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("invoice_id", idString(invoice.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			continue
+		}
+		if _, err := s.invoiceSvc.FinalizeInvoice(cycleCtx, invoice.ID.String()); err != nil {
+			jobErr = errors.Join(jobErr, err)
+			s.logSchedulerError(ctx, run, "invoice.finalize.failed", "threshold_invoice", cycle.OrgID, err,
+				zap.String("cycle_id", idString(cycle.ID)),
+				zap.String("invoice_id", idString(invoice.ID)),
+				zap.String("subscription_id", idString(cycle.SubscriptionID)),
+			)
+			_ = s.recordCycleErrorWithMetrics(ctx, cycle.ID, obsmetrics.CycleStageThresholdInvoice, err)
+			continue
+		}
+		s.logInvoiceFinalized(ctx, cycle, invoice.ID)
+	}
+
+	return jobErr
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/railzwaylabs/railzway/internal/auth/password"
 	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
 	invoicetemplatedomain "github.com/railzwaylabs/railzway/internal/invoicetemplate/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
 	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	"gorm.io/gorm"
 )
@@ -357,28 +358,7 @@ func ensureInvoiceTemplateTx(ctx context.Context, tx *gorm.DB, node *snowflake.N
 }
 
 func ensureLedgerAccounts(ctx context.Context, db *gorm.DB, node *snowflake.Node, orgID snowflake.ID) error {
-	type account struct {
-		Code string
-		Type string
-		Name string
-	}
-
-	accounts := []account{
-		{"accounts_receivable", "asset", "Accounts Receivable"},
-		{"cash", "asset", "Cash / Bank"},
-
-		{"revenue_usage", "income", "Usage Revenue"},
-		{"revenue_flat", "income", "Subscription Revenue"},
-
-		{"tax_payable", "liability", "Tax Payable"},
-		{"credit_balance", "liability", "Customer Credit Balance"},
-		{"refund_liability", "liability", "Refund Liability"},
-
-		{"payment_fee_expense", "expense", "Payment Gateway Fees"},
-		{"adjustment", "expense", "Billing Adjustment"},
-	}
-
-	for _, a := range accounts {
+	for _, a := range ledgerdomain.StandardAccounts {
 		err := db.WithContext(ctx).
 			Exec(`
 				INSERT INTO ledger_accounts (id, org_id, code, type, name)
@@ -387,8 +367,8 @@ func ensureLedgerAccounts(ctx context.Context, db *gorm.DB, node *snowflake.Node
 			`,
 				node.Generate(),
 				orgID,
-				a.Code,
-				a.Type,
+				string(a.Code),
+				string(a.Type),
 				a.Name,
 			).Error
 
@@ -55,10 +55,11 @@ func (s *Server) APIKeyRequired() gin.HandlerFunc {
 			OrgID   snowflake.ID   `gorm:"column:org_id"`
 			KeyHash string         `gorm:"column:key_hash"`
 			Scopes  pq.StringArray `gorm:"column:scopes;type:text[]"`
+			Tier    string         `gorm:"column:rate_limit_tier"`
 		}
 
 		if err := s.db.WithContext(c.Request.Context()).Raw(
-			`SELECT id, org_id, key_hash, scopes
+			`SELECT id, org_id, key_hash, scopes, rate_limit_tier
 			 FROM api_keys
 			 WHERE key_hash = ?
 			   AND is_active = true
@@ -90,6 +91,10 @@ func (s *Server) APIKeyRequired() gin.HandlerFunc {
 			}
 		}
 
+		if !s.allowAPIKeyRequest(c, record.ID, record.Tier) {
+			return
+		}
+
 		ctx := c.Request.Context()
 		scopes := make([]string, 0, len(record.Scopes))
 		scopes = append(scopes, record.Scopes...)
@@ -97,13 +102,14 @@ func (s *Server) APIKeyRequired() gin.HandlerFunc {
 		ctx = context.WithValue(ctx, contextOrgIDKey, int64(record.OrgID))
 		ctx = context.WithValue(ctx, contextAPIKeyIDKey, int64(record.ID))
 		ctx = context.WithValue(ctx, contextAPIKeyScopesKey, scopes)
+		ctx = context.WithValue(ctx, contextAPIKeyTierKey, record.Tier)
 		ctx = orgcontext.WithOrgID(ctx, int64(record.OrgID))
 		ctx = auditcontext.WithActor(ctx, string(auditdomain.ActorTypeAPIKey), record.ID.String())
 		ctx = obscontext.WithActor(ctx, string(auditdomain.ActorTypeAPIKey), record.ID.String())
 		ctx = obscontext.WithOrgID(ctx, record.OrgID.String())
 
 		c.Request = c.Request.WithContext(ctx)
-		c.Next()
+		s.serveIdempotent(c)
 	}
 }
 
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	apikeydomain "github.com/railzwaylabs/railzway/internal/apikey/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+)
+
+type apiKeyMeResponse struct {
+	APIKeyID      string   `json:"api_key_id"`
+	OrgID         string   `json:"org_id"`
+	Scopes        []string `json:"scopes"`
+	RateLimitTier string   `json:"rate_limit_tier"`
+}
+
+// @Summary      Get Calling API Key
+// @Description  Get the calling API key's organization, granted scopes and rate limit tier
+// @Tags         api_keys
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {object}  DataResponse
+// @Router       /me [get]
+func (s *Server) GetAPIKeyMe(c *gin.Context) {
+	ctx := c.Request.Context()
+	apiKeyID, ok := apiKeyIDFromContext(ctx)
+	if !ok {
+		AbortWithError(c, ErrUnauthorized)
+		return
+	}
+	orgID, _ := orgcontext.OrgIDFromContext(ctx)
+
+	scopes := apiKeyScopesFromContext(ctx)
+	if scopes == nil {
+		scopes = []string{}
+	}
+	tier, _ := ctx.Value(contextAPIKeyTierKey).(string)
+	if tier == "" {
+		tier = apikeydomain.RateLimitTierFree
+	}
+
+	respondData(c, apiKeyMeResponse{
+		APIKeyID:      apiKeyID.String(),
+		OrgID:         orgID.String(),
+		Scopes:        scopes,
+		RateLimitTier: tier,
+	})
+}
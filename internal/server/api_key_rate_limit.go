@@ -0,0 +1,84 @@
+package server
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/gin-gonic/gin"
+	apikeydomain "github.com/railzwaylabs/railzway/internal/apikey/domain"
+	"github.com/railzwaylabs/railzway/internal/observability/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	contextAPIKeyTierKey      = "api_key_rate_limit_tier"
+	contextAPIKeyRemainingKey = "api_key_rate_limit_remaining"
+)
+
+type apiKeyRateLimitResponse struct {
+	Enabled        bool   `json:"enabled"`
+	Tier           string `json:"tier"`
+	LimitPerMinute int    `json:"limit_per_minute"`
+	Remaining      *int   `json:"remaining,omitempty"`
+}
+
+// allowAPIKeyRequest charges the request against the API key's tier bucket.
+// It aborts with 429 and returns false once the key is out of tokens.
+func (s *Server) allowAPIKeyRequest(c *gin.Context, apiKeyID snowflake.ID, tier string) bool {
+	if s.apiKeyRateLimiter == nil || !s.apiKeyRateLimiter.Enabled() {
+		return true
+	}
+
+	ctx := c.Request.Context()
+	result, err := s.apiKeyRateLimiter.Allow(ctx, apiKeyID.String(), tier)
+	if err != nil {
+		logger.FromContext(ctx).Warn("api key rate limit check failed", zap.Error(err))
+		AbortWithError(c, ErrServiceUnavailable)
+		return false
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Set(contextAPIKeyRemainingKey, result.Remaining)
+
+	if !result.Allowed {
+		retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		AbortWithError(c, ErrRateLimited)
+		return false
+	}
+	return true
+}
+
+// @Summary      Get API Key Rate Limit
+// @Description  Get the rate limit tier and per-minute limit of the calling API key
+// @Tags         api_keys
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {object}  DataResponse
+// @Router       /me/rate-limit [get]
+func (s *Server) GetAPIKeyRateLimit(c *gin.Context) {
+	tier, _ := c.Request.Context().Value(contextAPIKeyTierKey).(string)
+	if tier == "" {
+		tier = apikeydomain.RateLimitTierFree
+	}
+
+	resp := apiKeyRateLimitResponse{
+		Enabled: s.apiKeyRateLimiter.Enabled(),
+		Tier:    tier,
+	}
+	if resp.Enabled {
+		resp.LimitPerMinute = s.apiKeyRateLimiter.LimitPerMinute(tier)
+		if remaining, ok := c.Get(contextAPIKeyRemainingKey); ok {
+			if value, ok := remaining.(int); ok {
+				resp.Remaining = &value
+			}
+		}
+	}
+	respondData(c, resp)
+}
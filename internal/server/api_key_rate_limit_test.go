@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bwmarrin/snowflake"
+	"github.com/gin-gonic/gin"
+	apikeydomain "github.com/railzwaylabs/railzway/internal/apikey/domain"
+	"github.com/railzwaylabs/railzway/internal/config"
+	"github.com/railzwaylabs/railzway/internal/ratelimit"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAPIKeyRateLimitRouter serves GET /me/rate-limit behind a stand-in for
+// APIKeyRequired that charges the key's bucket and stores its tier the same
+// way.
+func newAPIKeyRateLimitRouter(srv *Server, apiKeyID snowflake.ID, tier string) *gin.Engine {
+	r := gin.New()
+	r.Use(ErrorHandlingMiddleware())
+	r.Use(func(c *gin.Context) {
+		if !srv.allowAPIKeyRequest(c, apiKeyID, tier) {
+			return
+		}
+		ctx := context.WithValue(c.Request.Context(), contextAPIKeyTierKey, tier)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	r.GET("/me/rate-limit", srv.GetAPIKeyRateLimit)
+	return r
+}
+
+func newTestAPIKeyRateLimiter(t *testing.T) (*ratelimit.APIKeyLimiter, *miniredis.Miniredis) {
+	redisServer, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(redisServer.Close)
+
+	limiter, err := ratelimit.NewAPIKeyLimiter(config.Config{RateLimit: config.RateLimitConfig{
+		Enabled:       true,
+		APIKeyFreeRPM: 2,
+		APIKeyProRPM:  3,
+	}}, redis.NewClient(&redis.Options{Addr: redisServer.Addr()}))
+	require.NoError(t, err)
+	return limiter, redisServer
+}
+
+func getAPIKeyRateLimit(r *gin.Engine) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me/rate-limit", nil))
+	return rec
+}
+
+func TestGetAPIKeyRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter, _ := newTestAPIKeyRateLimiter(t)
+	r := newAPIKeyRateLimitRouter(&Server{apiKeyRateLimiter: limiter}, 42, apikeydomain.RateLimitTierPro)
+
+	rec := getAPIKeyRateLimit(r)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "3", rec.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Remaining"))
+
+	var body struct {
+		Data apiKeyRateLimitResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.True(t, body.Data.Enabled)
+	assert.Equal(t, apikeydomain.RateLimitTierPro, body.Data.Tier)
+	assert.Equal(t, 3, body.Data.LimitPerMinute)
+	require.NotNil(t, body.Data.Remaining)
+	assert.Equal(t, 2, *body.Data.Remaining)
+
+	getAPIKeyRateLimit(r)
+	getAPIKeyRateLimit(r)
+	rec = getAPIKeyRateLimit(r)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+}
+
+func TestGetAPIKeyRateLimit_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := newAPIKeyRateLimitRouter(&Server{}, 42, "")
+
+	rec := getAPIKeyRateLimit(r)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, false, body.Data["enabled"])
+	assert.Equal(t, apikeydomain.RateLimitTierFree, body.Data["tier"])
+	assert.EqualValues(t, 0, body.Data["limit_per_minute"])
+	assert.NotContains(t, body.Data, "remaining")
+}
+
+// TestAllowAPIKeyRequest_FailsClosedOnRedisError verifies that a limiter
+// that cannot reach Redis rejects the request rather than letting it through
+// unmetered.
+func TestAllowAPIKeyRequest_FailsClosedOnRedisError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter, redisServer := newTestAPIKeyRateLimiter(t)
+	r := newAPIKeyRateLimitRouter(&Server{apiKeyRateLimiter: limiter}, 42, apikeydomain.RateLimitTierFree)
+
+	redisServer.Close()
+
+	rec := getAPIKeyRateLimit(r)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, rec.Body.String())
+	assert.Empty(t, rec.Header().Get("X-RateLimit-Remaining"))
+}
@@ -57,18 +57,12 @@ func (s *Server) authorizeOrgActionWithContext(c *gin.Context, object string, ac
 		// API Keys MUST have scopes. If no scopes, or scope mismatch -> Forbidden.
 		// We do NOT fall through to RBAC (system role) for API keys anymore.
 		requiredScope := authscope.FromAuthz(object, action)
-		fmt.Printf("[DEBUG] APIKey Auth: object=%s action=%s requiredScope=%s scopes=%v\n", object, action, requiredScope, actor.Scopes)
-
-		if len(actor.Scopes) == 0 {
+		if requiredScope == "" {
 			return ErrForbidden
 		}
-
-		hasScope := authscope.Has(actor.Scopes, requiredScope)
-		fmt.Printf("[DEBUG] APIKey Auth: HasScope=%v\n", hasScope)
-		if !hasScope {
-			return ErrForbidden
+		if !authscope.Has(actor.Scopes, requiredScope) {
+			return newInsufficientScopeError(requiredScope)
 		}
-
 		return nil
 	case ActorUser:
 		if s.authzSvc == nil {
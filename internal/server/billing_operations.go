@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/railzwaylabs/railzway/internal/auditcontext"
 	billingoperationsdomain "github.com/railzwaylabs/railzway/internal/billingoperations/domain"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 )
 
 type billingOperationsActionRequest struct {
@@ -31,6 +32,14 @@ type billingOperationsReleaseRequest struct {
 	ReleasedBy string `json:"released_by"`
 }
 
+type billingOperationsBulkAssignmentRequest struct {
+	Assignments []billingOperationsAssignmentRequest `json:"assignments"`
+}
+
+type billingOperationsBulkReleaseRequest struct {
+	Assignments []billingOperationsReleaseRequest `json:"assignments"`
+}
+
 func (s *Server) GetBillingOperationsOverdueInvoices(c *gin.Context) {
 	if s.billingOperationsSvc == nil {
 		AbortWithError(c, ErrServiceUnavailable)
@@ -42,8 +51,13 @@ func (s *Server) GetBillingOperationsOverdueInvoices(c *gin.Context) {
 		AbortWithError(c, err)
 		return
 	}
+	filter, err := parseBillingOperationsAssignmentFilter(c)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
 
-	resp, err := s.billingOperationsSvc.ListOverdueInvoices(c.Request.Context(), limit)
+	resp, err := s.billingOperationsSvc.ListOverdueInvoices(c.Request.Context(), limit, filter)
 	if err != nil {
 		AbortWithError(c, err)
 		return
@@ -105,8 +119,13 @@ func (s *Server) GetBillingOperations(c *gin.Context) {
 		AbortWithError(c, err)
 		return
 	}
+	filter, err := parseBillingOperationsAssignmentFilter(c)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
 
-	resp, err := s.billingOperationsSvc.GetOperations(c.Request.Context(), limit)
+	resp, err := s.billingOperationsSvc.GetOperations(c.Request.Context(), limit, filter)
 	if err != nil {
 		AbortWithError(c, err)
 		return
@@ -168,6 +187,70 @@ func (s *Server) PostBillingOperationsAssignment(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// POST /admin/billing-operations/bulk-claim
+func (s *Server) BulkClaimBillingOperationsAssignments(c *gin.Context) {
+	if s.billingOperationsSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	var req billingOperationsBulkAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	claims := make([]billingoperationsdomain.ClaimAssignmentRequest, 0, len(req.Assignments))
+	for _, item := range req.Assignments {
+		claims = append(claims, billingoperationsdomain.ClaimAssignmentRequest{
+			EntityType:           strings.TrimSpace(item.EntityType),
+			EntityID:             strings.TrimSpace(item.EntityID),
+			AssignedTo:           strings.TrimSpace(item.AssignedTo),
+			AssignmentTTLMinutes: item.AssignmentTTLMinutes,
+		})
+	}
+
+	resp, err := s.billingOperationsSvc.BulkClaimAssignments(c.Request.Context(), claims)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// POST /admin/billing-operations/bulk-release
+func (s *Server) BulkReleaseBillingOperationsAssignments(c *gin.Context) {
+	if s.billingOperationsSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	var req billingOperationsBulkReleaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	releases := make([]billingoperationsdomain.ReleaseAssignmentRequest, 0, len(req.Assignments))
+	for _, item := range req.Assignments {
+		releases = append(releases, billingoperationsdomain.ReleaseAssignmentRequest{
+			EntityType: strings.TrimSpace(item.EntityType),
+			EntityID:   strings.TrimSpace(item.EntityID),
+			Reason:     strings.TrimSpace(item.Reason),
+			ReleasedBy: strings.TrimSpace(item.ReleasedBy),
+		})
+	}
+
+	resp, err := s.billingOperationsSvc.BulkReleaseAssignments(c.Request.Context(), releases)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // func- [x] Backend: Claim & Release with Audit <!-- id: 11 -->
 // - [x] Implement Release Assignment (DELETE) Endpoint <!-- id: 7 -->
 // - [/] Verify design and integration <!-- id: 6 -->
@@ -241,12 +324,41 @@ func parseBillingOperationsLimit(c *gin.Context) (int, error) {
 		return 0, newValidationError("limit", "invalid_limit", "limit must be positive")
 	}
 	limit := int(*limitValue)
-	if limit > 200 {
-		limit = 200
+	if limit > pagination.MaxPageSize {
+		limit = pagination.MaxPageSize
 	}
 	return limit, nil
 }
 
+// parseBillingOperationsAssignmentFilter reads the assigned (true, false or
+// any) and assigned_to query parameters. assigned_to=me is the caller.
+func parseBillingOperationsAssignmentFilter(c *gin.Context) (billingoperationsdomain.AssignmentFilter, error) {
+	var filter billingoperationsdomain.AssignmentFilter
+	switch strings.ToLower(strings.TrimSpace(c.Query("assigned"))) {
+	case "", "any":
+	case "true":
+		assigned := true
+		filter.Assigned = &assigned
+	case "false":
+		assigned := false
+		filter.Assigned = &assigned
+	default:
+		return filter, newValidationError("assigned", "invalid_assigned", "assigned must be true, false or any")
+	}
+
+	filter.AssignedTo = strings.TrimSpace(c.Query("assigned_to"))
+	if strings.EqualFold(filter.AssignedTo, "me") {
+		_, filter.AssignedTo = auditcontext.ActorFromContext(c.Request.Context())
+		if filter.AssignedTo == "" {
+			return filter, newValidationError("assigned_to", "invalid_assigned_to", "assigned_to=me requires a user session")
+		}
+	}
+	if filter.AssignedTo != "" && filter.Assigned != nil && !*filter.Assigned {
+		return filter, newValidationError("assigned_to", "invalid_assigned_to", "assigned_to cannot be combined with assigned=false")
+	}
+	return filter, nil
+}
+
 // GET /finops/performance/me
 func (s *Server) GetBillingOperationsPerformanceMe(c *gin.Context) {
 	if s.billingOperationsSvc == nil {
@@ -275,6 +387,34 @@ func (s *Server) GetBillingOperationsPerformanceMe(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GET /billing-operations/performance/history
+func (s *Server) GetBillingOperationsPerformanceHistory(c *gin.Context) {
+	if s.billingOperationsSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	_, userID := auditcontext.ActorFromContext(c.Request.Context())
+	if userID == "" {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	var req billingoperationsdomain.PerformanceHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	resp, err := s.billingOperationsSvc.GetPerformanceHistory(c.Request.Context(), userID, req)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondList(c, resp.Snapshots, &resp.PageInfo)
+}
+
 // GET /finops/performance/team
 func (s *Server) GetBillingOperationsPerformanceTeam(c *gin.Context) {
 	if s.billingOperationsSvc == nil {
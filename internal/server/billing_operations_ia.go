@@ -146,3 +146,19 @@ func (s *Server) GetBillingOperationsInvoicePayments(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// GET /admin/billing-operations/assignments/:entity_type/:entity_id
+func (s *Server) GetBillingOperationsAssignment(c *gin.Context) {
+	if s.billingOperationsSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	resp, err := s.billingOperationsSvc.GetAssignmentDetail(c.Request.Context(), c.Param("entity_type"), c.Param("entity_id"))
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
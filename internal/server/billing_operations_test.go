@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/railzwaylabs/railzway/internal/auditcontext"
+	billingoperationsdomain "github.com/railzwaylabs/railzway/internal/billingoperations/domain"
+)
+
+func TestParseBillingOperationsAssignmentFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	parse := func(ctx context.Context, query string) (billingoperationsdomain.AssignmentFilter, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/billing/operations/overdue-invoices?"+query, nil).WithContext(ctx)
+		return parseBillingOperationsAssignmentFilter(c)
+	}
+	agent := auditcontext.WithActor(context.Background(), "user", "user_1")
+
+	for _, query := range []string{"", "assigned=any"} {
+		filter, err := parse(agent, query)
+		if err != nil {
+			t.Fatalf("parse %q: %v", query, err)
+		}
+		if filter.Assigned != nil || filter.AssignedTo != "" {
+			t.Fatalf("expected %q to match every item, got %+v", query, filter)
+		}
+	}
+
+	filter, err := parse(agent, "assigned=false")
+	if err != nil {
+		t.Fatalf("parse unassigned: %v", err)
+	}
+	if filter.Assigned == nil || *filter.Assigned {
+		t.Fatalf("expected unassigned filter, got %+v", filter)
+	}
+
+	filter, err = parse(agent, "assigned=true&assigned_to=me")
+	if err != nil {
+		t.Fatalf("parse own work: %v", err)
+	}
+	if filter.Assigned == nil || !*filter.Assigned || filter.AssignedTo != "user_1" {
+		t.Fatalf("expected items assigned to the caller, got %+v", filter)
+	}
+
+	for _, tc := range []struct {
+		query string
+		ctx   context.Context
+	}{
+		{"assigned=maybe", agent},
+		{"assigned=false&assigned_to=user_2", agent},
+		{"assigned_to=me", context.Background()},
+	} {
+		if _, err := parse(tc.ctx, tc.query); err == nil {
+			t.Fatalf("expected %q to be rejected", tc.query)
+		}
+	}
+}
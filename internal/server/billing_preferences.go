@@ -0,0 +1,64 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	billingprefsdomain "github.com/railzwaylabs/railzway/internal/billingpreferences/domain"
+)
+
+// @Summary      Get Billing Preferences
+// @Description  Get the organization's billing defaults: currency, payment terms, dunning schedule, rounding mode and cancellation refunds
+// @Tags         organization
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {object}  DataResponse
+// @Router       /organization/billing-preferences [get]
+func (s *Server) GetBillingPreferences(c *gin.Context) {
+	resp, err := s.billingPreferencesSvc.Get(c.Request.Context())
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
+// @Summary      Update Billing Preferences
+// @Description  Update the organization's billing defaults; omitted fields keep their value. New subscriptions and invoices use the updated defaults
+// @Tags         organization
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request  body      billingprefsdomain.UpdateRequest  true  "Update Billing Preferences Request"
+// @Success      200  {object}  DataResponse
+// @Router       /organization/billing-preferences [put]
+func (s *Server) UpdateBillingPreferences(c *gin.Context) {
+	var req billingprefsdomain.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	resp, err := s.billingPreferencesSvc.Update(c.Request.Context(), req)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
+func isBillingPreferencesValidationError(err error) bool {
+	switch err {
+	case billingprefsdomain.ErrInvalidOrganization,
+		billingprefsdomain.ErrInvalidCurrency,
+		billingprefsdomain.ErrInvalidPaymentTerms,
+		billingprefsdomain.ErrInvalidDunningDays,
+		billingprefsdomain.ErrInvalidRoundingMode,
+		billingprefsdomain.ErrInvalidCancellationRefund,
+		billingprefsdomain.ErrInvalidPastDueGraceDays:
+		return true
+	default:
+		return false
+	}
+}
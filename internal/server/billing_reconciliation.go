@@ -0,0 +1,41 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	reconciliationdomain "github.com/railzwaylabs/railzway/internal/reconciliation/domain"
+)
+
+func (s *Server) GetBillingReconciliation(c *gin.Context) {
+	if s.reconciliationSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	from, err := parseOptionalTime(c.Query("from"), false)
+	if err != nil || from == nil {
+		AbortWithError(c, newValidationError("from", "invalid_time", "from is required and must be a valid time"))
+		return
+	}
+	to, err := parseOptionalTime(c.Query("to"), true)
+	if err != nil || to == nil {
+		AbortWithError(c, newValidationError("to", "invalid_time", "to is required and must be a valid time"))
+		return
+	}
+	if !from.Before(*to) {
+		AbortWithError(c, newValidationError("range", "invalid_range", "from must be before to"))
+		return
+	}
+
+	resp, err := s.reconciliationSvc.GetReport(c.Request.Context(), reconciliationdomain.ReportRequest{
+		From: *from,
+		To:   *to,
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
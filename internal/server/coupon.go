@@ -0,0 +1,222 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	coupondomain "github.com/railzwaylabs/railzway/internal/coupon/domain"
+)
+
+type createCouponRequest struct {
+	Code             string                    `json:"code"`
+	Name             string                    `json:"name"`
+	DiscountType     coupondomain.DiscountType `json:"discount_type"`
+	PercentOff       *float64                  `json:"percent_off"`
+	AmountOffCents   *int64                    `json:"amount_off_cents"`
+	Currency         *string                   `json:"currency"`
+	Duration         coupondomain.Duration     `json:"duration"`
+	DurationInCycles *int32                    `json:"duration_in_cycles"`
+	MaxRedemptions   *int32                    `json:"max_redemptions"`
+	RedeemBy         *time.Time                `json:"redeem_by"`
+	Metadata         map[string]any            `json:"metadata"`
+}
+
+type applySubscriptionDiscountRequest struct {
+	CouponID   string `json:"coupon_id"`
+	CouponCode string `json:"coupon_code"`
+}
+
+// @Summary      Create Coupon
+// @Description  Create a percent or fixed amount coupon that can be applied to subscriptions
+// @Tags         coupons
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request body createCouponRequest true "Create Coupon Request"
+// @Success      200  {object}  DataResponse
+// @Router       /coupons [post]
+func (s *Server) CreateCoupon(c *gin.Context) {
+	if s.couponSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	var req createCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	resp, err := s.couponSvc.CreateCoupon(c.Request.Context(), coupondomain.CreateCouponRequest{
+		Code:             req.Code,
+		Name:             req.Name,
+		DiscountType:     req.DiscountType,
+		PercentOff:       req.PercentOff,
+		AmountOffCents:   req.AmountOffCents,
+		Currency:         req.Currency,
+		Duration:         req.Duration,
+		DurationInCycles: req.DurationInCycles,
+		MaxRedemptions:   req.MaxRedemptions,
+		RedeemBy:         req.RedeemBy,
+		Metadata:         req.Metadata,
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	if s.auditSvc != nil {
+		targetID := resp.ID.String()
+		_ = s.auditSvc.AuditLog(c.Request.Context(), nil, "", nil, "coupon.create", "coupon", &targetID, map[string]any{
+			"coupon_id":     resp.ID,
+			"code":          resp.Code,
+			"discount_type": resp.DiscountType,
+			"duration":      resp.Duration,
+		})
+	}
+
+	respondData(c, resp)
+}
+
+// @Summary      List Coupons
+// @Description  List the organization's coupons, newest first
+// @Tags         coupons
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        code    query  string  false  "Coupon code"
+// @Param        active  query  bool    false  "Active filter"
+// @Success      200  {object}  ListResponse
+// @Router       /coupons [get]
+func (s *Server) ListCoupons(c *gin.Context) {
+	if s.couponSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	req := coupondomain.ListCouponsRequest{Code: strings.TrimSpace(c.Query("code"))}
+	if raw := strings.TrimSpace(c.Query("active")); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			AbortWithError(c, newValidationError("active", "invalid_active", "invalid active"))
+			return
+		}
+		req.Active = &active
+	}
+
+	resp, err := s.couponSvc.ListCoupons(c.Request.Context(), req)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondList(c, resp, nil)
+}
+
+// @Summary      Apply Subscription Discount
+// @Description  Apply a coupon, by ID or code, to a subscription. The discount applies from the current billing cycle
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path  string                            true  "Subscription ID"
+// @Param        request  body  applySubscriptionDiscountRequest  true  "Apply Subscription Discount Request"
+// @Success      200  {object}  DataResponse
+// @Router       /subscriptions/{id}/discounts [post]
+func (s *Server) ApplySubscriptionDiscount(c *gin.Context) {
+	if s.couponSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	var req applySubscriptionDiscountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	resp, err := s.couponSvc.ApplyToSubscription(c.Request.Context(), coupondomain.ApplyRequest{
+		SubscriptionID: id,
+		CouponID:       req.CouponID,
+		CouponCode:     req.CouponCode,
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	if s.auditSvc != nil {
+		targetID := id
+		_ = s.auditSvc.AuditLog(c.Request.Context(), nil, "", nil, "subscription.discount_apply", "subscription", &targetID, map[string]any{
+			"subscription_id": id,
+			"discount_id":     resp.ID,
+			"coupon_id":       resp.CouponID,
+		})
+	}
+
+	respondData(c, resp)
+}
+
+// @Summary      Remove Subscription Discount
+// @Description  Stop a subscription discount. Billing cycles it already covered keep their discount
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id           path  string  true  "Subscription ID"
+// @Param        discount_id  path  string  true  "Discount ID"
+// @Success      204
+// @Router       /subscriptions/{id}/discounts/{discount_id} [delete]
+func (s *Server) RemoveSubscriptionDiscount(c *gin.Context) {
+	if s.couponSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	discountID := strings.TrimSpace(c.Param("discount_id"))
+	if err := s.couponSvc.RemoveFromSubscription(c.Request.Context(), id, discountID); err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	if s.auditSvc != nil {
+		targetID := id
+		_ = s.auditSvc.AuditLog(c.Request.Context(), nil, "", nil, "subscription.discount_remove", "subscription", &targetID, map[string]any{
+			"subscription_id": id,
+			"discount_id":     discountID,
+		})
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func isCouponValidationError(err error) bool {
+	switch {
+	case errors.Is(err, coupondomain.ErrInvalidOrganization),
+		errors.Is(err, coupondomain.ErrInvalidID),
+		errors.Is(err, coupondomain.ErrInvalidSubscription),
+		errors.Is(err, coupondomain.ErrInvalidCode),
+		errors.Is(err, coupondomain.ErrInvalidName),
+		errors.Is(err, coupondomain.ErrInvalidDiscountType),
+		errors.Is(err, coupondomain.ErrInvalidPercentOff),
+		errors.Is(err, coupondomain.ErrInvalidAmountOff),
+		errors.Is(err, coupondomain.ErrInvalidCurrency),
+		errors.Is(err, coupondomain.ErrInvalidDuration),
+		errors.Is(err, coupondomain.ErrInvalidDurationInCycles),
+		errors.Is(err, coupondomain.ErrInvalidMaxRedemptions),
+		errors.Is(err, coupondomain.ErrInvalidRedeemBy),
+		errors.Is(err, coupondomain.ErrCouponInactive),
+		errors.Is(err, coupondomain.ErrCouponExpired),
+		errors.Is(err, coupondomain.ErrMaxRedemptionsReached),
+		errors.Is(err, coupondomain.ErrSubscriptionNotActive):
+		return true
+	default:
+		return false
+	}
+}
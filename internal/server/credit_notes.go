@@ -0,0 +1,38 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      List Invoice Credit Notes
+// @Description  List credit notes issued against an invoice
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Invoice ID"
+// @Success      200  {object}  ListResponse
+// @Router       /invoices/{id}/credit-notes [get]
+func (s *Server) ListInvoiceCreditNotes(c *gin.Context) {
+	if s.creditNoteSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	items, err := s.creditNoteSvc.ListByInvoice(c.Request.Context(), id)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondList(c, items, nil)
+}
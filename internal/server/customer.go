@@ -9,8 +9,12 @@ import (
 )
 
 type createCustomerRequest struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	Country          string `json:"country"`
+	State            string `json:"state"`
+	TaxID            string `json:"tax_id"`
+	PaymentTermsDays *int   `json:"payment_terms_days"`
 }
 
 // @Summary      Create Customer
@@ -31,9 +35,13 @@ func (s *Server) CreateCustomer(c *gin.Context) {
 	}
 
 	resp, err := s.customerSvc.Create(c.Request.Context(), customerdomain.CreateCustomerRequest{
-		Name:           strings.TrimSpace(req.Name),
-		Email:          strings.TrimSpace(req.Email),
-		IdempotencyKey: idempotencyKeyFromHeader(c),
+		Name:             strings.TrimSpace(req.Name),
+		Email:            strings.TrimSpace(req.Email),
+		Country:          req.Country,
+		State:            req.State,
+		TaxID:            req.TaxID,
+		PaymentTermsDays: req.PaymentTermsDays,
+		IdempotencyKey:   idempotencyKeyFromHeader(c),
 	})
 	if err != nil {
 		AbortWithError(c, err)
@@ -132,12 +140,122 @@ func (s *Server) GetCustomerByID(c *gin.Context) {
 	respondData(c, resp)
 }
 
+// @Summary      Get Customer Statement
+// @Description  Get the customer's balance and account activity between from and to, in the customer's currency
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id    path      string  true   "Customer ID"
+// @Param        from  query     string  false  "From (defaults to 30 days before to)"
+// @Param        to    query     string  false  "To (defaults to now)"
+// @Success      200  {object}  DataResponse
+// @Router       /customers/{id}/statement [get]
+func (s *Server) GetCustomerStatement(c *gin.Context) {
+	from, err := parseOptionalTime(c.Query("from"), false)
+	if err != nil {
+		AbortWithError(c, newValidationError("from", "invalid_from", "invalid from"))
+		return
+	}
+
+	to, err := parseOptionalTime(c.Query("to"), true)
+	if err != nil {
+		AbortWithError(c, newValidationError("to", "invalid_to", "invalid to"))
+		return
+	}
+
+	resp, err := s.customerSvc.GetStatement(c.Request.Context(), customerdomain.StatementRequest{
+		CustomerID: strings.TrimSpace(c.Param("id")),
+		From:       from,
+		To:         to,
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
+type updateCustomerCurrencyRequest struct {
+	Currency string `json:"currency"`
+}
+
+// @Summary      Update Customer Currency
+// @Description  Change the customer's billing currency; refused while the customer has subscriptions that have not ended or unpaid invoices
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                         true  "Customer ID"
+// @Param        request  body      updateCustomerCurrencyRequest  true  "Update Customer Currency Request"
+// @Success      200  {object}  DataResponse
+// @Router       /customers/{id}/currency [patch]
+func (s *Server) UpdateCustomerCurrency(c *gin.Context) {
+	var req updateCustomerCurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	resp, err := s.customerSvc.UpdateCurrency(c.Request.Context(), customerdomain.UpdateCurrencyRequest{
+		ID:       strings.TrimSpace(c.Param("id")),
+		Currency: req.Currency,
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
+type updateCustomerPaymentTermsRequest struct {
+	// PaymentTermsDays is how many days after finalization the customer's
+	// invoices are due; null falls back to the organization's terms.
+	PaymentTermsDays *int `json:"payment_terms_days"`
+}
+
+// @Summary      Update Customer Payment Terms
+// @Description  Set the customer's net payment terms, overriding the organization's for invoices finalized afterwards; null clears the override
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                             true  "Customer ID"
+// @Param        request  body      updateCustomerPaymentTermsRequest  true  "Update Customer Payment Terms Request"
+// @Success      200  {object}  DataResponse
+// @Router       /customers/{id}/payment-terms [patch]
+func (s *Server) UpdateCustomerPaymentTerms(c *gin.Context) {
+	var req updateCustomerPaymentTermsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	resp, err := s.customerSvc.UpdatePaymentTerms(c.Request.Context(), customerdomain.UpdatePaymentTermsRequest{
+		ID:               strings.TrimSpace(c.Param("id")),
+		PaymentTermsDays: req.PaymentTermsDays,
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
 func isCustomerValidationError(err error) bool {
 	switch err {
 	case customerdomain.ErrInvalidOrganization,
 		customerdomain.ErrInvalidName,
 		customerdomain.ErrInvalidEmail,
-		customerdomain.ErrInvalidID:
+		customerdomain.ErrInvalidCountry,
+		customerdomain.ErrInvalidID,
+		customerdomain.ErrInvalidPeriod,
+		customerdomain.ErrCurrencyNotSet,
+		customerdomain.ErrInvalidCurrency,
+		customerdomain.ErrInvalidPaymentTerms:
 		return true
 	default:
 		return false
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	billingoperationsdomain "github.com/railzwaylabs/railzway/internal/billingoperations/domain"
+	"github.com/railzwaylabs/railzway/internal/providers/email"
+)
+
+// HandleEmailDeliveryWebhook applies delivery, bounce and complaint events
+// from the email provider to the follow-up actions that sent those emails.
+// The provider path segment selects the payload format.
+func (s *Server) HandleEmailDeliveryWebhook(c *gin.Context) {
+	if s.billingOperationsSvc == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+
+	secret := s.cfg.Email.WebhookSecret
+	token := strings.TrimSpace(c.GetHeader("X-Webhook-Token"))
+	if token == "" {
+		token = strings.TrimSpace(c.Query("token"))
+	}
+	if secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		AbortWithError(c, ErrUnauthorized)
+		return
+	}
+
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	events, err := email.ParseDeliveryEvents(c.Param("provider"), payload)
+	switch {
+	case errors.Is(err, email.ErrUnsupportedDeliveryProvider):
+		AbortWithError(c, ErrNotFound)
+		return
+	case err != nil:
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	for _, event := range events {
+		err := s.billingOperationsSvc.UpdateEmailDeliveryStatus(c.Request.Context(), billingoperationsdomain.EmailDeliveryUpdate{
+			MessageID:  event.MessageID,
+			Status:     event.Status,
+			Reason:     event.Reason,
+			OccurredAt: event.OccurredAt,
+		})
+		// Providers report on every email they relay, including ones
+		// Railzway does not track.
+		if errors.Is(err, billingoperationsdomain.ErrEmailLogNotFound) {
+			continue
+		}
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -14,12 +14,17 @@ import (
 	billingdashboarddomain "github.com/railzwaylabs/railzway/internal/billingdashboard/domain"
 	billingoperationsdomain "github.com/railzwaylabs/railzway/internal/billingoperations/domain"
 	billingoverviewdomain "github.com/railzwaylabs/railzway/internal/billingoverview/domain"
+	billingprefsdomain "github.com/railzwaylabs/railzway/internal/billingpreferences/domain"
+	coupondomain "github.com/railzwaylabs/railzway/internal/coupon/domain"
 	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
+	"github.com/railzwaylabs/railzway/internal/events"
 	featuredomain "github.com/railzwaylabs/railzway/internal/feature/domain"
+	idempotencydomain "github.com/railzwaylabs/railzway/internal/idempotency/domain"
 	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
 	invoicetemplatedomain "github.com/railzwaylabs/railzway/internal/invoicetemplate/domain"
 	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
 	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
 	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
@@ -29,6 +34,7 @@ import (
 	paymentproviderdomain "github.com/railzwaylabs/railzway/internal/providers/payment/domain"
 	quotadomain "github.com/railzwaylabs/railzway/internal/quota/domain"
 	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	reconciliationdomain "github.com/railzwaylabs/railzway/internal/reconciliation/domain"
 	signupdomain "github.com/railzwaylabs/railzway/internal/signup/domain"
 	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
 	taxdomain "github.com/railzwaylabs/railzway/internal/tax/domain"
@@ -54,6 +60,18 @@ type errorPayload struct {
 	Type    string            `json:"type"`
 	Message string            `json:"message"`
 	Errors  []ValidationError `json:"errors,omitempty"`
+
+	// AvailableCurrencies lists what a price is priced in when the requested
+	// currency has no amount.
+	AvailableCurrencies []string `json:"available_currencies,omitempty"`
+
+	// BlockingSubscriptions and BlockingInvoices list what keeps a customer
+	// from changing currency or a meter from being archived.
+	BlockingSubscriptions []string `json:"blocking_subscriptions,omitempty"`
+	BlockingInvoices      []string `json:"blocking_invoices,omitempty"`
+
+	// RequiredScope names the scope an API key lacked.
+	RequiredScope string `json:"required_scope,omitempty"`
 }
 
 type errorResponse struct {
@@ -73,6 +91,20 @@ var (
 	ErrInvoiceUnavailable = errors.New("invoice_unavailable")
 )
 
+// InsufficientScopeError rejects an API key that lacks the scope a route
+// requires.
+type InsufficientScopeError struct {
+	RequiredScope authscope.Scope
+}
+
+func (e *InsufficientScopeError) Error() string {
+	return "insufficient_scope"
+}
+
+func newInsufficientScopeError(required authscope.Scope) error {
+	return &InsufficientScopeError{RequiredScope: required}
+}
+
 func ErrorHandlingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
@@ -81,15 +113,24 @@ func ErrorHandlingMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		lastErr := c.Errors.Last()
-		if lastErr == nil {
-			return
-		}
+		writeLastError(c)
+	}
+}
+
+// writeLastError renders the last recorded error as the JSON error response.
+func writeLastError(c *gin.Context) {
+	if c.Writer.Written() {
+		return
+	}
 
-		status, payload := mapError(lastErr.Err)
-		c.Header("Content-Type", "application/json")
-		c.AbortWithStatusJSON(status, errorResponse{Error: payload})
+	lastErr := c.Errors.Last()
+	if lastErr == nil {
+		return
 	}
+
+	status, payload := mapError(lastErr.Err)
+	c.Header("Content-Type", "application/json")
+	c.AbortWithStatusJSON(status, errorResponse{Error: payload})
 }
 
 func AbortWithError(c *gin.Context, err error) {
@@ -132,6 +173,87 @@ func mapError(err error) (int, errorPayload) {
 		}
 	}
 
+	var itemErr *subscriptiondomain.ItemError
+	if errors.As(err, &itemErr) {
+		payload := errorPayload{
+			Type:    "validation_error",
+			Message: "validation error",
+			Errors: []ValidationError{
+				{
+					Field:   itemErr.Field(),
+					Code:    validationErrorCode(itemErr.Err),
+					Message: itemErr.Error(),
+				},
+			},
+		}
+		var currencyErr *subscriptiondomain.CurrencyNotPricedError
+		if errors.As(itemErr.Err, &currencyErr) {
+			payload.Errors[0].Code = "currency_not_priced"
+			payload.AvailableCurrencies = currencyErr.AvailableCurrencies
+		}
+		return http.StatusUnprocessableEntity, payload
+	}
+
+	var currencyErr *subscriptiondomain.CurrencyNotPricedError
+	if errors.As(err, &currencyErr) {
+		return http.StatusUnprocessableEntity, errorPayload{
+			Type:    "validation_error",
+			Message: "validation error",
+			Errors: []ValidationError{
+				{
+					Field:   "currency",
+					Code:    "currency_not_priced",
+					Message: currencyErr.Error(),
+				},
+			},
+			AvailableCurrencies: currencyErr.AvailableCurrencies,
+		}
+	}
+
+	var scopeErr *InsufficientScopeError
+	if errors.As(err, &scopeErr) {
+		return http.StatusForbidden, errorPayload{
+			Type:          "insufficient_scope",
+			Message:       "api key is missing the " + string(scopeErr.RequiredScope) + " scope",
+			RequiredScope: string(scopeErr.RequiredScope),
+		}
+	}
+
+	var blockedErr *customerdomain.CurrencyChangeBlockedError
+	if errors.As(err, &blockedErr) {
+		return http.StatusConflict, errorPayload{
+			Type:                  "conflict",
+			Message:               blockedErr.Error(),
+			BlockingSubscriptions: blockedErr.SubscriptionIDs,
+			BlockingInvoices:      blockedErr.InvoiceIDs,
+		}
+	}
+
+	var meterInUseErr *meterdomain.MeterInUseError
+	if errors.As(err, &meterInUseErr) {
+		return http.StatusConflict, errorPayload{
+			Type:                  "conflict",
+			Message:               meterInUseErr.Error(),
+			BlockingSubscriptions: meterInUseErr.SubscriptionIDs,
+		}
+	}
+
+	var tierRowErr *pricetierdomain.ImportRowError
+	if errors.As(err, &tierRowErr) {
+		code := validationErrorCode(tierRowErr.Err)
+		return http.StatusBadRequest, errorPayload{
+			Type:    "validation_error",
+			Message: "validation error",
+			Errors: []ValidationError{
+				{
+					Field:   "file",
+					Code:    code,
+					Message: tierRowErr.Error(),
+				},
+			},
+		}
+	}
+
 	if isValidationError(err) {
 		code := validationErrorCode(err)
 		return http.StatusBadRequest, errorPayload{
@@ -175,13 +297,28 @@ func mapError(err error) (int, errorPayload) {
 			Type:    "quota_exceeded",
 			Message: "quota exceeded",
 		}
+	case errors.Is(err, idempotencydomain.ErrKeyReused):
+		return http.StatusUnprocessableEntity, errorPayload{
+			Type:    "idempotency_error",
+			Message: "idempotency key was already used with a different request",
+		}
+	case errors.Is(err, idempotencydomain.ErrRequestInProgress):
+		return http.StatusConflict, errorPayload{
+			Type:    "conflict",
+			Message: "a request with this idempotency key is in progress",
+		}
 	case errors.Is(err, organizationdomain.ErrForbidden):
 		return http.StatusForbidden, errorPayload{
 			Type:    "forbidden",
 			Message: "forbidden",
 		}
 	case errors.Is(err, ErrConflict),
-		errors.Is(err, authdomain.ErrUserExists):
+		errors.Is(err, authdomain.ErrUserExists),
+		errors.Is(err, coupondomain.ErrCodeAlreadyExists),
+		errors.Is(err, coupondomain.ErrCouponAlreadyApplied),
+		errors.Is(err, subscriptiondomain.ErrConcurrentUpdate),
+		errors.Is(err, productdomain.ErrDuplicateCode),
+		errors.Is(err, pricedomain.ErrDuplicateCode):
 		return http.StatusConflict, errorPayload{
 			Type:    "conflict",
 			Message: "conflict",
@@ -247,13 +384,16 @@ func asValidationErrors(err error) *ValidationErrors {
 func isValidationError(err error) bool {
 	switch {
 	case errors.Is(err, ErrInvalidRequest),
-		errors.Is(err, signupdomain.ErrInvalidRequest):
+		errors.Is(err, signupdomain.ErrInvalidRequest),
+		errors.Is(err, idempotencydomain.ErrInvalidKey):
 		return true
 	case isOrganizationValidationError(err),
 		isCustomerValidationError(err),
 		isBillingDashboardValidationError(err),
 		isBillingOperationsValidationError(err),
 		isBillingOverviewValidationError(err),
+		isBillingPreferencesValidationError(err),
+		isReconciliationValidationError(err),
 		isInvoiceValidationError(err),
 		isInvoiceTemplateValidationError(err),
 		isRatingValidationError(err),
@@ -272,7 +412,10 @@ func isValidationError(err error) bool {
 		isAuthorizationValidationError(err),
 		isPaymentProviderValidationError(err),
 		isTaxValidationError(err),
+		isCreditNoteValidationError(err),
 		isProductFeatureValidationError(err),
+		isWebhookEndpointValidationError(err),
+		isCouponValidationError(err),
 		isScopeValidationError(err):
 		return true
 	default:
@@ -289,9 +432,18 @@ func isUsageValidationError(err error) bool {
 		usagedomain.ErrInvalidMeter,
 		usagedomain.ErrInvalidMeterCode,
 		usagedomain.ErrInvalidValue,
+		usagedomain.ErrInvalidUsageType,
+		usagedomain.ErrAdjustmentNotSupported,
 		usagedomain.ErrInvalidRecordedAt,
 		usagedomain.ErrInvalidIdempotencyKey,
-		usagedomain.ErrFeatureNotEntitled:
+		usagedomain.ErrFeatureNotEntitled,
+		usagedomain.ErrInvalidImportFile,
+		usagedomain.ErrInvalidImportID,
+		usagedomain.ErrInvalidBackfillWindow,
+		usagedomain.ErrInvalidBatchSize,
+		usagedomain.ErrSubscriptionPaused,
+		usagedomain.ErrInvalidDimensions,
+		usagedomain.ErrUsageWindowClosed:
 		return true
 	default:
 		return false
@@ -342,7 +494,9 @@ func isBillingOperationsValidationError(err error) bool {
 		billingoperationsdomain.ErrInvalidActionType,
 		billingoperationsdomain.ErrInvalidAssignee,
 		billingoperationsdomain.ErrInvalidIdempotencyKey,
-		billingoperationsdomain.ErrInvalidAssignmentTTL:
+		billingoperationsdomain.ErrInvalidAssignmentTTL,
+		billingoperationsdomain.ErrInvalidPeriodType,
+		billingoperationsdomain.ErrInvalidBulkSize:
 		return true
 	default:
 		return false
@@ -358,6 +512,16 @@ func isBillingOverviewValidationError(err error) bool {
 	}
 }
 
+func isReconciliationValidationError(err error) bool {
+	switch err {
+	case reconciliationdomain.ErrInvalidOrganization,
+		reconciliationdomain.ErrInvalidRange:
+		return true
+	default:
+		return false
+	}
+}
+
 func isTaxValidationError(err error) bool {
 	switch err {
 	case taxdomain.ErrInvalidOrganization,
@@ -365,7 +529,30 @@ func isTaxValidationError(err error) bool {
 		taxdomain.ErrInvalidID,
 		taxdomain.ErrInvalidTaxCode,
 		taxdomain.ErrInvalidTaxMode,
-		taxdomain.ErrInvalidTaxRate:
+		taxdomain.ErrInvalidTaxRate,
+		taxdomain.ErrInvalidCountry:
+		return true
+	default:
+		return false
+	}
+}
+
+func isCreditNoteValidationError(err error) bool {
+	switch err {
+	case creditnotedomain.ErrInvalidOrganization,
+		creditnotedomain.ErrInvalidAmount,
+		creditnotedomain.ErrInvalidCurrency,
+		creditnotedomain.ErrInvalidPaymentEvent:
+		return true
+	default:
+		return false
+	}
+}
+
+func isWebhookEndpointValidationError(err error) bool {
+	switch err {
+	case events.ErrInvalidWebhookURL,
+		events.ErrInvalidWebhookEventTypes:
 		return true
 	default:
 		return false
@@ -377,6 +564,8 @@ func isNotFoundError(err error) bool {
 	case errors.Is(err, ErrNotFound),
 		errors.Is(err, customerdomain.ErrNotFound),
 		errors.Is(err, invoicetemplatedomain.ErrNotFound),
+		errors.Is(err, billingprefsdomain.ErrNotFound),
+		errors.Is(err, invoicetemplatedomain.ErrVersionNotFound),
 		errors.Is(err, invoicedomain.ErrInvoiceTemplateNotFound),
 		errors.Is(err, productdomain.ErrNotFound),
 		errors.Is(err, productfeaturedomain.ErrProductNotFound),
@@ -390,12 +579,22 @@ func isNotFoundError(err error) bool {
 		errors.Is(err, pricetierdomain.ErrNotFound),
 		errors.Is(err, invoicedomain.ErrBillingCycleNotFound),
 		errors.Is(err, invoicedomain.ErrInvoiceNotFound),
+		errors.Is(err, invoicedomain.ErrInvoicePDFNotFound),
 		errors.Is(err, ratingdomain.ErrBillingCycleNotFound),
+		errors.Is(err, ratingdomain.ErrSubscriptionNotFound),
 		errors.Is(err, subscriptiondomain.ErrSubscriptionNotFound),
 		errors.Is(err, subscriptiondomain.ErrSubscriptionItemNotFound),
 		errors.Is(err, paymentdomain.ErrProviderNotFound),
+		errors.Is(err, paymentdomain.ErrWebhookLogNotFound),
+		errors.Is(err, paymentdomain.ErrPaymentNotFound),
+		errors.Is(err, paymentdomain.ErrPaymentMethodNotFound),
 		errors.Is(err, paymentproviderdomain.ErrNotFound),
 		errors.Is(err, taxdomain.ErrNotFound),
+		errors.Is(err, events.ErrWebhookEndpointNotFound),
+		errors.Is(err, coupondomain.ErrCouponNotFound),
+		errors.Is(err, coupondomain.ErrDiscountNotFound),
+		errors.Is(err, coupondomain.ErrSubscriptionNotFound),
+		errors.Is(err, billingoperationsdomain.ErrAssignmentNotFound),
 		errors.Is(err, gorm.ErrRecordNotFound):
 		return true
 	default:
@@ -407,13 +606,22 @@ func isInvoiceValidationError(err error) bool {
 	switch err {
 	case invoicedomain.ErrInvalidOrganization,
 		invoicedomain.ErrInvalidBillingCycle,
+		invoicedomain.ErrInvalidCustomer,
+		invoicedomain.ErrInvalidCurrency,
+		invoicedomain.ErrInvalidChargeItem,
 		invoicedomain.ErrBillingCycleNotClosed,
+		invoicedomain.ErrBillingCycleNotOpen,
 		invoicedomain.ErrMissingLedgerEntry,
 		invoicedomain.ErrMissingRatingResults,
 		invoicedomain.ErrCurrencyMismatch,
 		invoicedomain.ErrInvalidInvoiceID,
+		invoicedomain.ErrInvalidFields,
 		invoicedomain.ErrInvoiceNotDraft,
-		invoicedomain.ErrInvoiceNotFinalized:
+		invoicedomain.ErrInvoiceNotFinalized,
+		invoicedomain.ErrInvoicePaid,
+		invoicedomain.ErrInvoiceCredited,
+		invoicedomain.ErrInvoiceNotChargeable,
+		invoicedomain.ErrInvoicePDFFailed:
 		return true
 	default:
 		return false
@@ -426,7 +634,8 @@ func isInvoiceTemplateValidationError(err error) bool {
 		invoicetemplatedomain.ErrInvalidID,
 		invoicetemplatedomain.ErrInvalidName,
 		invoicetemplatedomain.ErrInvalidCurrency,
-		invoicetemplatedomain.ErrInvalidLocale:
+		invoicetemplatedomain.ErrInvalidLocale,
+		invoicetemplatedomain.ErrInvalidVersion:
 		return true
 	default:
 		return false
@@ -437,7 +646,8 @@ func isAPIKeyValidationError(err error) bool {
 	switch err {
 	case apikeydomain.ErrInvalidOrganization,
 		apikeydomain.ErrInvalidName,
-		apikeydomain.ErrInvalidKeyID:
+		apikeydomain.ErrInvalidKeyID,
+		apikeydomain.ErrInvalidRateLimitTier:
 		return true
 	default:
 		return false
@@ -487,7 +697,10 @@ func isPaymentValidationError(err error) bool {
 		paymentdomain.ErrInvalidEvent,
 		paymentdomain.ErrInvalidCustomer,
 		paymentdomain.ErrInvalidAmount,
-		paymentdomain.ErrInvalidCurrency:
+		paymentdomain.ErrInvalidCurrency,
+		paymentdomain.ErrInvalidWebhookLog,
+		paymentdomain.ErrInvalidPayment,
+		paymentdomain.ErrRefundExceedsPayment:
 		return true
 	default:
 		return false
@@ -505,12 +718,16 @@ func isScopeValidationError(err error) bool {
 
 func isRatingValidationError(err error) bool {
 	switch err {
-	case ratingdomain.ErrInvalidBillingCycle,
+	case ratingdomain.ErrInvalidOrganization,
+		ratingdomain.ErrInvalidBillingCycle,
 		ratingdomain.ErrBillingCycleNotClosing,
+		ratingdomain.ErrBillingCycleNotOpen,
 		ratingdomain.ErrMissingUsage,
 		ratingdomain.ErrMissingPriceAmount,
+		ratingdomain.ErrMissingPriceTier,
 		ratingdomain.ErrMissingMeter,
-		ratingdomain.ErrInvalidQuantity:
+		ratingdomain.ErrInvalidQuantity,
+		ratingdomain.ErrNoSubscriptionItems:
 		return true
 	default:
 		return false
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessCheckTimeout bounds each dependency probe so a hung dependency
+// fails readiness instead of stalling the probe.
+const readinessCheckTimeout = 2 * time.Second
+
+const (
+	dependencyStatusUp   = "up"
+	dependencyStatusDown = "down"
+)
+
+// DependencyCheck is the outcome of probing one dependency.
+type DependencyCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// GetLiveness reports that the process is serving requests. It checks no
+// dependencies, so a database outage never gets the instance restarted.
+func (s *Server) GetLiveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// GetReadiness probes the database, Redis and the schema gate, and answers
+// 503 with the status of each when any of them fails.
+func (s *Server) GetReadiness(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	checks := map[string]DependencyCheck{
+		"database": runDependencyCheck(ctx, s.pingDatabase),
+		"redis":    runDependencyCheck(ctx, s.pingRedis),
+		"schema":   runDependencyCheck(ctx, s.checkSchemaGate),
+	}
+
+	status := "ready"
+	code := http.StatusOK
+	for _, check := range checks {
+		if check.Status != dependencyStatusUp {
+			status = "not_ready"
+			code = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	c.JSON(code, gin.H{
+		"status": status,
+		"checks": checks,
+	})
+}
+
+func runDependencyCheck(ctx context.Context, check func(context.Context) error) DependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	if err := check(ctx); err != nil {
+		return DependencyCheck{Status: dependencyStatusDown, Error: err.Error()}
+	}
+	return DependencyCheck{Status: dependencyStatusUp}
+}
+
+func (s *Server) pingDatabase(ctx context.Context) error {
+	if s.db == nil {
+		return errors.New("database not configured")
+	}
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func (s *Server) pingRedis(ctx context.Context) error {
+	if s.redis == nil {
+		return errors.New("redis not configured")
+	}
+	return s.redis.Ping(ctx).Err()
+}
+
+func (s *Server) checkSchemaGate(ctx context.Context) error {
+	if s.schemaGate == nil {
+		return errors.New("schema gate not configured")
+	}
+	return s.schemaGate.MustBeActive(ctx)
+}
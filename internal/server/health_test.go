@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type schemaGateStub struct {
+	err error
+}
+
+func (g schemaGateStub) MustBeActive(context.Context) error { return g.err }
+
+func TestReadiness_ReportsEachDependency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+
+	srv := &Server{db: db, schemaGate: schemaGateStub{}}
+	router := gin.New()
+	router.GET("/healthz", srv.GetLiveness)
+	router.GET("/readyz", srv.GetReadiness)
+
+	get := func(path string) (int, map[string]any) {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		return resp.Code, body
+	}
+
+	code, _ := get("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+
+	// Redis is not configured, so readiness fails on it alone.
+	code, body := get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not_ready", body["status"])
+	checks := body["checks"].(map[string]any)
+	assert.Equal(t, dependencyStatusUp, checks["database"].(map[string]any)["status"])
+	assert.Equal(t, dependencyStatusUp, checks["schema"].(map[string]any)["status"])
+	assert.Equal(t, dependencyStatusDown, checks["redis"].(map[string]any)["status"])
+
+	srv.schemaGate = schemaGateStub{err: errors.New("schema version mismatch")}
+	_, body = get("/readyz")
+	schema := body["checks"].(map[string]any)["schema"].(map[string]any)
+	assert.Equal(t, dependencyStatusDown, schema["status"])
+	assert.Equal(t, "schema version mismatch", schema["error"])
+}
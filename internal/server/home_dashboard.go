@@ -7,7 +7,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	auditdomain "github.com/railzwaylabs/railzway/internal/audit/domain"
+	billingoperationsdomain "github.com/railzwaylabs/railzway/internal/billingoperations/domain"
 	billingoverviewdomain "github.com/railzwaylabs/railzway/internal/billingoverview/domain"
+	"github.com/railzwaylabs/railzway/internal/currency"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
 	"github.com/railzwaylabs/railzway/pkg/db/pagination"
 )
@@ -69,10 +71,10 @@ func (s *Server) GetHomeDashboard(c *gin.Context) {
 	previousRevenue := 0.0
 	if err == nil {
 		if revenue.Total != nil {
-			currentRevenue = float64(*revenue.Total) / 100.0 // Cents to Dollars
+			currentRevenue = currency.ToMajorUnits(*revenue.Total, revenue.Currency)
 		}
 		if revenue.Previous != nil {
-			previousRevenue = float64(*revenue.Previous) / 100.0
+			previousRevenue = currency.ToMajorUnits(*revenue.Previous, revenue.Currency)
 		}
 	}
 
@@ -116,7 +118,7 @@ func (s *Server) GetHomeDashboard(c *gin.Context) {
 
 	// 3. Alerts: Check for Overdue Invoices
 	opsCtx := c.Request.Context()
-	overdueInvoices, _ := s.billingOperationsSvc.ListOverdueInvoices(opsCtx, 5)
+	overdueInvoices, _ := s.billingOperationsSvc.ListOverdueInvoices(opsCtx, 5, billingoperationsdomain.AssignmentFilter{})
 
 	alerts := []AlertBase{}
 
@@ -1,11 +1,144 @@
 package server
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
 	"strings"
 
+	"github.com/bwmarrin/snowflake"
 	"github.com/gin-gonic/gin"
+	idempotencydomain "github.com/railzwaylabs/railzway/internal/idempotency/domain"
+	"github.com/railzwaylabs/railzway/internal/observability/logger"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"go.uber.org/zap"
 )
 
+const headerIdempotentReplayed = "Idempotent-Replayed"
+
 func idempotencyKeyFromHeader(c *gin.Context) string {
 	return strings.TrimSpace(c.GetHeader("Idempotency-Key"))
 }
+
+// Idempotency replays the stored response of a POST repeated with the same
+// Idempotency-Key. It must run after the organization is resolved.
+func (s *Server) Idempotency() gin.HandlerFunc {
+	return s.serveIdempotent
+}
+
+// serveIdempotent runs the rest of the chain at most once per organization,
+// endpoint and Idempotency-Key. The first request locks the key and its
+// response is stored; repeats get that response back, or a conflict while
+// the first is still running. Server, auth and rate limit errors are not
+// stored so the request can be retried.
+func (s *Server) serveIdempotent(c *gin.Context) {
+	key := idempotencyKeyFromHeader(c)
+	if s.idempotencySvc == nil || c.Request.Method != http.MethodPost || key == "" {
+		c.Next()
+		return
+	}
+	orgID, ok := orgcontext.OrgIDFromContext(c.Request.Context())
+	if !ok || orgID == 0 {
+		c.Next()
+		return
+	}
+
+	var body []byte
+	if c.Request.Body != nil {
+		read, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			AbortWithError(c, invalidRequestError())
+			return
+		}
+		body = read
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	hash := sha256.Sum256(append([]byte(c.Request.URL.RawQuery+"\n"), body...))
+
+	ctx := c.Request.Context()
+	record, err := s.idempotencySvc.Begin(ctx, idempotencydomain.BeginRequest{
+		OrgID:       snowflake.ID(orgID),
+		Endpoint:    c.Request.Method + " " + c.Request.URL.Path,
+		Key:         key,
+		RequestHash: hex.EncodeToString(hash[:]),
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	if record.Status == idempotencydomain.StatusCompleted {
+		status := http.StatusOK
+		if record.ResponseStatus != nil {
+			status = *record.ResponseStatus
+		}
+		contentType := "application/json"
+		if record.ResponseContentType != nil && *record.ResponseContentType != "" {
+			contentType = *record.ResponseContentType
+		}
+		c.Header(headerIdempotentReplayed, "true")
+		c.Data(status, contentType, record.ResponseBody)
+		c.Abort()
+		return
+	}
+
+	writer := &capturingWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			s.releaseIdempotencyKey(c, record.ID)
+			panic(recovered)
+		}
+	}()
+
+	c.Next()
+
+	// Errors are rendered here rather than by ErrorHandlingMiddleware so
+	// they are stored with the key.
+	writeLastError(c)
+
+	status := writer.Status()
+	if !storableIdempotentStatus(status) {
+		s.releaseIdempotencyKey(c, record.ID)
+		return
+	}
+	if err := s.idempotencySvc.Complete(ctx, record.ID, idempotencydomain.Response{
+		Status:      status,
+		ContentType: writer.Header().Get("Content-Type"),
+		Body:        writer.body.Bytes(),
+	}); err != nil {
+		logger.FromContext(ctx).Warn("failed to store idempotent response", zap.Error(err), zap.String("idempotency_key", key))
+	}
+}
+
+func (s *Server) releaseIdempotencyKey(c *gin.Context, id snowflake.ID) {
+	if err := s.idempotencySvc.Release(c.Request.Context(), id); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to release idempotency key", zap.Error(err))
+	}
+}
+
+func storableIdempotentStatus(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+// capturingWriter keeps a copy of the response body.
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	idempotencydomain "github.com/railzwaylabs/railzway/internal/idempotency/domain"
+	idempotencyservice "github.com/railzwaylabs/railzway/internal/idempotency/service"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	"gorm.io/gorm"
+)
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&idempotencydomain.Key{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	node, _ := snowflake.NewNode(1)
+	srv := &Server{idempotencySvc: idempotencyservice.New(idempotencyservice.Params{
+		DB:    db,
+		Clock: clock.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+		GenID: node,
+	})}
+
+	refunds, failures := 0, 0
+	r := gin.New()
+	r.Use(ErrorHandlingMiddleware())
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(orgcontext.WithOrgID(c.Request.Context(), 1))
+		c.Next()
+	})
+	r.Use(srv.Idempotency())
+	r.POST("/refunds", func(c *gin.Context) {
+		refunds++
+		c.JSON(http.StatusCreated, gin.H{"data": gin.H{"refund": refunds}})
+	})
+	r.POST("/fail", func(c *gin.Context) {
+		failures++
+		AbortWithError(c, ErrInternal)
+	})
+
+	send := func(path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := send("/refunds", "key-1", `{"amount":100}`)
+	second := send("/refunds", "key-1", `{"amount":100}`)
+	if refunds != 1 {
+		t.Fatalf("expected the handler to run once, ran %d times", refunds)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Fatalf("expected the first response replayed, got %d %s", second.Code, second.Body.String())
+	}
+	if second.Header().Get(headerIdempotentReplayed) != "true" {
+		t.Fatalf("expected the replay to be marked")
+	}
+
+	if rec := send("/refunds", "key-1", `{"amount":200}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a reused key, got %d", rec.Code)
+	}
+
+	// Server errors release the key so the request can be retried.
+	send("/fail", "key-2", `{}`)
+	if rec := send("/fail", "key-2", `{}`); rec.Code != http.StatusInternalServerError || failures != 2 {
+		t.Fatalf("expected the failed request to run again, got %d after %d runs", rec.Code, failures)
+	}
+}
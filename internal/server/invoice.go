@@ -27,6 +27,7 @@ import (
 // @Param        finalized_to     query     string  false  "Finalized To"
 // @Param        total_min        query     int     false  "Total Min"
 // @Param        total_max        query     int     false  "Total Max"
+// @Param        fields           query     string  false  "Comma separated columns to return, e.g. id,status,total_amount"
 // @Param        page_token       query     string  false  "Page Token"
 // @Param        page_size        query     int     false  "Page Size"
 // @Success      200  {object}  ListResponse
@@ -44,6 +45,7 @@ func (s *Server) ListInvoices(c *gin.Context) {
 		FinalizedTo   string `form:"finalized_to"`
 		TotalMin      string `form:"total_min"`
 		TotalMax      string `form:"total_max"`
+		Fields        string `form:"fields"`
 
 		PageToken string `form:"page_token"`
 		PageSize  int    `form:"page_size"`
@@ -113,6 +115,7 @@ func (s *Server) ListInvoices(c *gin.Context) {
 		return
 	}
 
+	fields := parseFields(query.Fields)
 	resp, err := s.invoiceSvc.List(c.Request.Context(), invoicedomain.ListInvoiceRequest{
 		Status:        status,
 		InvoiceNumber: &query.InvoiceNumber,
@@ -125,6 +128,7 @@ func (s *Server) ListInvoices(c *gin.Context) {
 		FinalizedTo:   finalizedTo,
 		TotalMin:      totalMin,
 		TotalMax:      totalMax,
+		Fields:        fields,
 		PageToken:     query.PageToken,
 		PageSize:      query.PageSize,
 	})
@@ -133,7 +137,7 @@ func (s *Server) ListInvoices(c *gin.Context) {
 		return
 	}
 
-	respondList(c, resp.Invoices, &resp.PageInfo)
+	respondFieldList(c, resp.Invoices, fields, invoicedomain.ListFields, &resp.PageInfo)
 }
 
 // @Summary      Get Invoice
@@ -161,6 +165,116 @@ func (s *Server) GetInvoiceByID(c *gin.Context) {
 	respondData(c, item)
 }
 
+type voidInvoiceRequest struct {
+	Reason string `json:"reason"`
+}
+
+// VoidInvoice voids a finalized, unpaid invoice with no credit notes. Paid
+// invoices are corrected with a credit note instead.
+func (s *Server) VoidInvoice(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	var req voidInvoiceRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			AbortWithError(c, invalidRequestError())
+			return
+		}
+	}
+
+	if err := s.invoiceSvc.VoidInvoice(c.Request.Context(), id, req.Reason); err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	item, err := s.invoiceSvc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, item)
+}
+
+// @Summary      Charge Invoice
+// @Description  Re-run auto-charge on an unpaid invoice of a charge_automatically subscription and wait for the provider. A partially paid invoice is charged the remaining balance. A failed charge is reported in the response and recorded on the invoice
+// @Tags         invoices
+// @Produce      json
+// @Param        id   path      string  true  "Invoice ID"
+// @Success      200  {object}  invoicedomain.ChargeInvoiceResponse
+// @Router       /invoices/{id}/charge [post]
+func (s *Server) ChargeInvoice(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	resp, err := s.invoiceSvc.ChargeInvoice(c.Request.Context(), id)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
+type createOneTimeChargeRequest struct {
+	// Currency defaults to the customer's billing currency.
+	Currency string                     `json:"currency"`
+	Items    []oneTimeChargeItemRequest `json:"items"`
+}
+
+type oneTimeChargeItemRequest struct {
+	Description string `json:"description"`
+	// Quantity defaults to 1.
+	Quantity   float64 `json:"quantity"`
+	UnitAmount int64   `json:"unit_amount"`
+}
+
+// @Summary      Create One-Time Charge
+// @Description  Invoice a customer for one-time charges outside any subscription. The invoice is finalized and charged to the customer's default payment method when there is one
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                      true  "Customer ID"
+// @Param        request  body      createOneTimeChargeRequest  true  "Create One-Time Charge Request"
+// @Success      201  {object}  DataResponse
+// @Router       /customers/{id}/charges [post]
+func (s *Server) CreateOneTimeCharge(c *gin.Context) {
+	var req createOneTimeChargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	items := make([]invoicedomain.OneTimeChargeItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, invoicedomain.OneTimeChargeItem{
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitAmount:  item.UnitAmount,
+		})
+	}
+
+	invoice, err := s.invoiceSvc.CreateOneTimeCharge(c.Request.Context(), invoicedomain.CreateOneTimeChargeRequest{
+		CustomerID: strings.TrimSpace(c.Param("id")),
+		Currency:   req.Currency,
+		Items:      items,
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": invoice})
+}
+
 // @Summary      Render Invoice
 // @Description  Render invoice PDF/HTML
 // @Tags         invoices
@@ -186,6 +300,61 @@ func (s *Server) RenderInvoice(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
+// @Summary      Generate Invoice PDF
+// @Description  Queue PDF generation for a finalized invoice; poll GET /invoices/{id}/pdf for the file
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Invoice ID"
+// @Success      202  {object}  DataResponse
+// @Router       /invoices/{id}/pdf [post]
+func (s *Server) GenerateInvoicePDF(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	resp, err := s.invoiceSvc.RequestInvoicePDF(c.Request.Context(), id)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": resp})
+}
+
+// @Summary      Download Invoice PDF
+// @Description  Download the invoice PDF; responds 202 with the generation status while the file is not ready yet
+// @Tags         invoices
+// @Produce      application/pdf
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Invoice ID"
+// @Success      200  {file}    file
+// @Success      202  {object}  DataResponse
+// @Router       /invoices/{id}/pdf [get]
+func (s *Server) DownloadInvoicePDF(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	resp, err := s.invoiceSvc.GetInvoicePDF(c.Request.Context(), id)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+	if resp.Status != invoicedomain.InvoicePDFStatusReady {
+		c.JSON(http.StatusAccepted, gin.H{"data": resp})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=\""+resp.Filename+"\"")
+	c.Data(http.StatusOK, "application/pdf", resp.Content)
+}
+
 func parseInvoiceStatus(value string) (*invoicedomain.InvoiceStatus, error) {
 	status := strings.TrimSpace(value)
 	if status == "" {
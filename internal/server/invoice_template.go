@@ -2,6 +2,7 @@ package server
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -82,3 +83,33 @@ func (s *Server) SetDefaultInvoiceTemplate(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
+
+func (s *Server) ListInvoiceTemplateVersions(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+
+	resp, err := s.invoiceTemplateSvc.ListVersions(c.Request.Context(), id)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+func (s *Server) RestoreInvoiceTemplateVersion(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+
+	version, err := strconv.Atoi(strings.TrimSpace(c.Param("version")))
+	if err != nil {
+		AbortWithError(c, templatedomain.ErrInvalidVersion)
+		return
+	}
+
+	resp, err := s.invoiceTemplateSvc.RestoreVersion(c.Request.Context(), id, version)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
@@ -16,6 +16,9 @@ type createMeterRequest struct {
 	Unit            string  `json:"unit"`
 	Description     *string `json:"description"`
 	Active          *bool   `json:"active"`
+	// BILLING_CYCLE (default) or NEVER: whether included allowances on the
+	// meter start over each billing cycle.
+	ResetInterval string `json:"reset_interval"`
 }
 
 type updateMeterRequest struct {
@@ -23,10 +26,13 @@ type updateMeterRequest struct {
 	AggregationType *string `json:"aggregation_type,omitempty"`
 	Unit            *string `json:"unit,omitempty"`
 	Active          *bool   `json:"active,omitempty"`
+	// BILLING_CYCLE (default) or NEVER: whether included allowances on the
+	// meter start over each billing cycle.
+	ResetInterval *string `json:"reset_interval,omitempty"`
 }
 
 // @Summary      Create Meter
-// @Description  Create a new meter
+// @Description  Create a new meter. Codes are stored lowercased and may only contain letters, digits and underscores
 // @Tags         meters
 // @Accept       json
 // @Produce      json
@@ -46,6 +52,7 @@ func (s *Server) CreateMeter(c *gin.Context) {
 		Name:           strings.TrimSpace(req.Name),
 		Aggregation:    strings.TrimSpace(req.AggregationType),
 		Unit:           strings.TrimSpace(req.Unit),
+		ResetInterval:  strings.TrimSpace(req.ResetInterval),
 		Active:         req.Active,
 		IdempotencyKey: idempotencyKeyFromHeader(c),
 	})
@@ -146,11 +153,12 @@ func (s *Server) UpdateMeter(c *gin.Context) {
 	}
 
 	resp, err := s.meterSvc.Update(c.Request.Context(), meterdomain.UpdateRequest{
-		ID:          id,
-		Name:        trimStringPtr(req.Name),
-		Aggregation: trimStringPtr(req.AggregationType),
-		Unit:        trimStringPtr(req.Unit),
-		Active:      req.Active,
+		ID:            id,
+		Name:          trimStringPtr(req.Name),
+		Aggregation:   trimStringPtr(req.AggregationType),
+		Unit:          trimStringPtr(req.Unit),
+		ResetInterval: trimStringPtr(req.ResetInterval),
+		Active:        req.Active,
 	})
 	if err != nil {
 		AbortWithError(c, err)
@@ -160,8 +168,8 @@ func (s *Server) UpdateMeter(c *gin.Context) {
 	respondData(c, resp)
 }
 
-// @Summary      Delete Meter
-// @Description  Delete meter
+// @Summary      Archive Meter
+// @Description  Archive a meter. Meters still used by subscriptions that have not ended cannot be archived.
 // @Tags         meters
 // @Accept       json
 // @Produce      json
@@ -186,7 +194,9 @@ func isMeterValidationError(err error) bool {
 		meterdomain.ErrInvalidName,
 		meterdomain.ErrInvalidAggregation,
 		meterdomain.ErrInvalidUnit,
-		meterdomain.ErrInvalidID:
+		meterdomain.ErrInvalidResetInterval,
+		meterdomain.ErrInvalidID,
+		meterdomain.ErrMeterArchived:
 		return true
 	default:
 		return false
@@ -114,6 +114,10 @@ func isOrganizationValidationError(err error) bool {
 		organizationdomain.ErrInvalidCountry,
 		organizationdomain.ErrInvalidTimezone,
 		organizationdomain.ErrInvalidCurrency,
+		organizationdomain.ErrInvalidDunningDays,
+		organizationdomain.ErrInvalidSLAWindow,
+		organizationdomain.ErrInvalidRoundingMode,
+		organizationdomain.ErrInvalidScoringWeights,
 		organizationdomain.ErrInvalidUser,
 		organizationdomain.ErrInvalidEmail,
 		organizationdomain.ErrInvalidRole:
@@ -19,8 +19,12 @@ type inviteMemberRequest struct {
 }
 
 type billingPreferencesRequest struct {
-	Currency string `json:"currency"`
-	Timezone string `json:"timezone"`
+	Currency             string                             `json:"currency"`
+	Timezone             string                             `json:"timezone"`
+	DunningDays          []int                              `json:"dunning_days,omitempty"`
+	AssignmentSLAMinutes *int                               `json:"assignment_sla_minutes,omitempty"`
+	RoundingMode         *organizationdomain.RoundingMode   `json:"rounding_mode,omitempty"`
+	ScoringWeights       *organizationdomain.ScoringWeights `json:"scoring_weights,omitempty"`
 }
 
 func (s *Server) InviteOrganizationMembers(c *gin.Context) {
@@ -83,8 +87,12 @@ func (s *Server) SetOrganizationBillingPreferences(c *gin.Context) {
 	}
 
 	if err := s.organizationSvc.SetBillingPreferences(c.Request.Context(), userID, orgID, organizationdomain.BillingPreferencesRequest{
-		Currency: req.Currency,
-		Timezone: req.Timezone,
+		Currency:             req.Currency,
+		Timezone:             req.Timezone,
+		DunningDays:          req.DunningDays,
+		AssignmentSLAMinutes: req.AssignmentSLAMinutes,
+		RoundingMode:         req.RoundingMode,
+		ScoringWeights:       req.ScoringWeights,
 	}); err != nil {
 		AbortWithError(c, err)
 		return
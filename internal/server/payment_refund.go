@@ -0,0 +1,37 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
+)
+
+// @Summary      Refund Payment
+// @Description  Refund a settled payment through its provider, in full or partially when amount is set; the ledger is updated when the provider confirms the refund
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                              true  "Payment ID"
+// @Param        request  body      paymentdomain.RefundPaymentRequest  false "Refund Payment Request"
+// @Success      200  {object}  DataResponse
+// @Router       /payments/{id}/refund [post]
+func (s *Server) RefundPayment(c *gin.Context) {
+	var req paymentdomain.RefundPaymentRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			AbortWithError(c, invalidRequestError())
+			return
+		}
+	}
+	req.PaymentID = strings.TrimSpace(c.Param("id"))
+
+	refund, err := s.paymentSvc.RefundPayment(c.Request.Context(), req)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, refund)
+}
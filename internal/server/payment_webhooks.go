@@ -33,3 +33,13 @@ func (s *Server) HandlePaymentWebhook(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
+
+func (s *Server) ReplayPaymentWebhook(c *gin.Context) {
+	entry, err := s.paymentSvc.ReplayWebhook(c.Request.Context(), strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, entry)
+}
@@ -51,6 +51,46 @@ func (s *Server) CreatePriceAmount(c *gin.Context) {
 	respondData(c, resp)
 }
 
+// @Summary      Schedule Price Amount
+// @Description  Schedule a future amount for a price. The currently active amount ends when the scheduled one takes effect.
+// @Tags         price_amounts
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id               path    string  true   "Price ID"
+// @Param        Idempotency-Key  header  string  false  "Idempotency Key"
+// @Param        request body priceamountdomain.ScheduleRequest true "Schedule Price Amount Request"
+// @Success      200  {object}  DataResponse
+// @Router       /prices/{id}/amounts/schedule [post]
+func (s *Server) SchedulePriceAmount(c *gin.Context) {
+	var req priceamountdomain.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+	req.PriceID = strings.TrimSpace(c.Param("id"))
+	req.IdempotencyKey = idempotencyKeyFromHeader(c)
+
+	resp, err := s.priceAmountSvc.Schedule(c.Request.Context(), req)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	if s.auditSvc != nil {
+		targetID := resp.ID.String()
+		_ = s.auditSvc.AuditLog(c.Request.Context(), nil, "", nil, "price_amount.schedule", "price_amount", &targetID, map[string]any{
+			"price_amount_id":   resp.ID,
+			"price_id":          resp.PriceID,
+			"currency":          resp.Currency,
+			"unit_amount_cents": resp.UnitAmountCents,
+			"effective_from":    resp.EffectiveFrom,
+		})
+	}
+
+	respondData(c, resp)
+}
+
 // @Summary      List Price Amounts
 // @Description  List available price amounts
 // @Tags         price_amounts
@@ -118,6 +158,7 @@ func isPriceAmountValidationError(err error) bool {
 		priceamountdomain.ErrInvalidEffectiveTo,
 		priceamountdomain.ErrEffectiveOverlap,
 		priceamountdomain.ErrEffectiveGap,
+		priceamountdomain.ErrUpcomingAlreadyExists,
 		priceamountdomain.ErrInvalidID:
 		return true
 	default:
@@ -65,3 +65,15 @@ func parseOptionalTime(value string, endOfDay bool) (*time.Time, error) {
 	}
 	return nil, errors.New("invalid_time")
 }
+
+// parseFields splits a comma separated fields parameter.
+func parseFields(value string) []string {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -3,8 +3,11 @@ package server
 import (
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/bwmarrin/snowflake"
 	"github.com/gin-gonic/gin"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
 )
 
 func (s *Server) RunRatingJob(c *gin.Context) {
@@ -29,3 +32,75 @@ func (s *Server) RunRatingJob(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
+
+type ratingPreviewLine struct {
+	PriceID     string    `json:"price_id"`
+	FeatureCode string    `json:"feature_code,omitempty"`
+	MeterID     *string   `json:"meter_id,omitempty"`
+	Quantity    float64   `json:"quantity"`
+	UnitPrice   int64     `json:"unit_price"`
+	Amount      int64     `json:"amount"`
+	Currency    string    `json:"currency"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Source      string    `json:"source"`
+}
+
+type ratingPreviewResponse struct {
+	BillingCycleID string              `json:"billing_cycle_id"`
+	TotalAmount    int64               `json:"total_amount"`
+	Results        []ratingPreviewLine `json:"results"`
+}
+
+// @Summary      Preview Billing Cycle Rating
+// @Description  Compute the arrears rating of a billing cycle in any status without storing the results
+// @Tags         billing_cycles
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Billing Cycle ID"
+// @Success      200  {object}  DataResponse
+// @Router       /billing-cycles/{id}/rating-preview [get]
+func (s *Server) PreviewBillingCycleRating(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	results, err := s.ratingSvc.DryRunRating(c.Request.Context(), id)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": toRatingPreviewResponse(id, results)})
+}
+
+func toRatingPreviewResponse(billingCycleID string, results []ratingdomain.RatingResult) ratingPreviewResponse {
+	resp := ratingPreviewResponse{
+		BillingCycleID: billingCycleID,
+		Results:        make([]ratingPreviewLine, 0, len(results)),
+	}
+	for _, result := range results {
+		var meterID *string
+		if result.MeterID != nil {
+			value := result.MeterID.String()
+			meterID = &value
+		}
+		resp.Results = append(resp.Results, ratingPreviewLine{
+			PriceID:     result.PriceID.String(),
+			FeatureCode: result.FeatureCode,
+			MeterID:     meterID,
+			Quantity:    result.Quantity,
+			UnitPrice:   result.UnitPrice,
+			Amount:      result.Amount,
+			Currency:    result.Currency,
+			PeriodStart: result.PeriodStart,
+			PeriodEnd:   result.PeriodEnd,
+			Source:      result.Source,
+		})
+		resp.TotalAmount += result.Amount
+	}
+	return resp
+}
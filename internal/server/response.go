@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -18,3 +19,35 @@ func respondList(c *gin.Context, data any, pageInfo *pagination.PageInfo) {
 	}
 	c.JSON(http.StatusOK, gin.H{"data": data, "page_info": pageInfo})
 }
+
+// respondFieldList responds with each item narrowed to the response keys of
+// the requested fields. Without fields the items are sent whole.
+func respondFieldList[T any](c *gin.Context, items []T, fields []string, keys map[string]string, pageInfo *pagination.PageInfo) {
+	if len(fields) == 0 {
+		respondList(c, items, pageInfo)
+		return
+	}
+
+	projected := make([]map[string]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(encoded, &values); err != nil {
+			AbortWithError(c, err)
+			return
+		}
+		selected := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			key := keys[field]
+			if value, ok := values[key]; ok {
+				selected[key] = value
+			}
+		}
+		projected = append(projected, selected)
+	}
+	respondList(c, projected, pageInfo)
+}
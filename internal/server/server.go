@@ -29,14 +29,20 @@ import (
 	billingoperationsdomain "github.com/railzwaylabs/railzway/internal/billingoperations/domain"
 	"github.com/railzwaylabs/railzway/internal/billingoverview"
 	billingoverviewdomain "github.com/railzwaylabs/railzway/internal/billingoverview/domain"
+	"github.com/railzwaylabs/railzway/internal/billingpreferences"
+	billingprefsdomain "github.com/railzwaylabs/railzway/internal/billingpreferences/domain"
 	"github.com/railzwaylabs/railzway/internal/bootstrap"
 	"github.com/railzwaylabs/railzway/internal/cloudmetrics"
 	"github.com/railzwaylabs/railzway/internal/config"
+	"github.com/railzwaylabs/railzway/internal/coupon"
+	coupondomain "github.com/railzwaylabs/railzway/internal/coupon/domain"
 	"github.com/railzwaylabs/railzway/internal/customer"
 	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
 	"github.com/railzwaylabs/railzway/internal/events"
 	"github.com/railzwaylabs/railzway/internal/feature"
 	featuredomain "github.com/railzwaylabs/railzway/internal/feature/domain"
+	"github.com/railzwaylabs/railzway/internal/idempotency"
+	idempotencydomain "github.com/railzwaylabs/railzway/internal/idempotency/domain"
 	"github.com/railzwaylabs/railzway/internal/integration"
 	integrationdomain "github.com/railzwaylabs/railzway/internal/integration/domain"
 	"github.com/railzwaylabs/railzway/internal/invoice"
@@ -54,6 +60,7 @@ import (
 	"github.com/railzwaylabs/railzway/internal/organization"
 	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
 	"github.com/railzwaylabs/railzway/internal/payment"
+	creditnotedomain "github.com/railzwaylabs/railzway/internal/payment/creditnote/domain"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 	"github.com/railzwaylabs/railzway/internal/price"
 	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
@@ -76,6 +83,8 @@ import (
 	"github.com/railzwaylabs/railzway/internal/ratelimit"
 	"github.com/railzwaylabs/railzway/internal/rating"
 	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	"github.com/railzwaylabs/railzway/internal/reconciliation"
+	reconciliationdomain "github.com/railzwaylabs/railzway/internal/reconciliation/domain"
 	"github.com/railzwaylabs/railzway/internal/reference"
 	referencedomain "github.com/railzwaylabs/railzway/internal/reference/domain"
 	"github.com/railzwaylabs/railzway/internal/scheduler"
@@ -89,6 +98,7 @@ import (
 	"github.com/railzwaylabs/railzway/internal/usage"
 	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
 	"github.com/railzwaylabs/railzway/internal/usage/liveevents"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/fx"
 	"gorm.io/gorm"
 )
@@ -111,8 +121,12 @@ var Module = fx.Module("http.server",
 	email.Module,
 	pdf.Module,
 	billingoverview.Module,
+	reconciliation.Module,
+	coupon.Module,
+	idempotency.Module,
 	invoice.Module,
 	invoicetemplate.Module,
+	billingpreferences.Module,
 	ledger.Module,
 	meter.Module,
 	organization.Module,
@@ -216,6 +230,9 @@ type Server struct {
 	billingDashboardSvc         billingdashboarddomain.Service
 	billingOperationsSvc        billingoperationsdomain.Service
 	billingOverviewSvc          billingoverviewdomain.Service
+	reconciliationSvc           reconciliationdomain.Service
+	couponSvc                   coupondomain.Service
+	idempotencySvc              idempotencydomain.Service
 	billingRollup               *billingrollup.Service
 	invoiceSvc                  invoicedomain.Service
 	meterSvc                    meterdomain.Service
@@ -228,8 +245,10 @@ type Server struct {
 	productFeatureSvc           productfeaturedomain.Service
 	featureSvc                  featuredomain.Service
 	paymentSvc                  paymentdomain.Service
+	creditNoteSvc               creditnotedomain.Service
 	paymentProviderSvc          paymentproviderdomain.Service
 	invoiceTemplateSvc          invoicetemplatedomain.Service
+	billingPreferencesSvc       billingprefsdomain.Service
 	refrepo                     referencedomain.Repository
 	signupsvc                   signupdomain.Service
 	ratingSvc                   ratingdomain.Service
@@ -239,6 +258,7 @@ type Server struct {
 	liveMeterEvents             *liveevents.Hub
 	obsMetrics                  *obsmetrics.Metrics
 	usageLimiter                *ratelimit.UsageIngestLimiter
+	apiKeyRateLimiter           *ratelimit.APIKeyLimiter
 	publicInvoiceSvc            publicinvoicedomain.Service
 	publicInvoiceLimiter        *rateLimiter
 	publicPaymentIntentLimiter  *rateLimiter
@@ -248,11 +268,13 @@ type Server struct {
 	paymentMethodConfigSvc      paymentdomain.PaymentMethodConfigService
 	checkoutSvc                 paymentdomain.CheckoutService
 	integrationSvc              integrationdomain.Service
+	webhookDispatcher           *events.WebhookDispatcher
 	testClockSvc                testclockdomain.Service
 
 	licenseSvc *license.Service
 	scheduler  *scheduler.Scheduler `optional:"true"`
 	schemaGate bootstrap.SchemaGate
+	redis      *redis.Client
 }
 
 type ServerParams struct {
@@ -272,6 +294,9 @@ type ServerParams struct {
 	BillingDashboardSvc    billingdashboarddomain.Service  `optional:"true"`
 	BillingOperationsSvc   billingoperationsdomain.Service `optional:"true"`
 	BillingOverviewSvc     billingoverviewdomain.Service   `optional:"true"`
+	ReconciliationSvc      reconciliationdomain.Service    `optional:"true"`
+	CouponSvc              coupondomain.Service            `optional:"true"`
+	IdempotencySvc         idempotencydomain.Service       `optional:"true"`
 	BillingRollup          *billingrollup.Service          `optional:"true"`
 	InvoiceSvc             invoicedomain.Service           `optional:"true"`
 	MeterSvc               meterdomain.Service             `optional:"true"`
@@ -284,8 +309,10 @@ type ServerParams struct {
 	ProductFeatureSvc      productfeaturedomain.Service    `optional:"true"`
 	FeatureSvc             featuredomain.Service           `optional:"true"`
 	PaymentSvc             paymentdomain.Service           `optional:"true"`
+	CreditNoteSvc          creditnotedomain.Service        `optional:"true"`
 	PaymentProviderSvc     paymentproviderdomain.Service   `optional:"true"`
 	InvoiceTemplateSvc     invoicetemplatedomain.Service   `optional:"true"`
+	BillingPreferencesSvc  billingprefsdomain.Service      `optional:"true"`
 	Refrepo                referencedomain.Repository      `optional:"true"`
 	RatingSvc              ratingdomain.Service            `optional:"true"`
 	SubscriptionSvc        subscriptiondomain.Service      `optional:"true"`
@@ -295,15 +322,18 @@ type ServerParams struct {
 	PublicInvoiceSvc       publicinvoicedomain.Service     `optional:"true"`
 	ObsMetrics             *obsmetrics.Metrics             `optional:"true"`
 	UsageLimiter           *ratelimit.UsageIngestLimiter   `optional:"true"`
+	APIKeyRateLimiter      *ratelimit.APIKeyLimiter        `optional:"true"`
 	PaymentMethodSvc       paymentdomain.PaymentMethodService
 	PaymentMethodConfigSvc paymentdomain.PaymentMethodConfigService
 	CheckoutSvc            paymentdomain.CheckoutService
 	IntegrationSvc         integrationdomain.Service `optional:"true"`
+	WebhookDispatcher      *events.WebhookDispatcher `optional:"true"`
 	TestClockSvc           testclockdomain.Service
 
 	LicenseSvc *license.Service
 	Scheduler  *scheduler.Scheduler `optional:"true"`
 	SchemaGate bootstrap.SchemaGate `optional:"true"`
+	Redis      *redis.Client        `optional:"true"`
 }
 
 func NewServer(p ServerParams) *Server {
@@ -323,6 +353,9 @@ func NewServer(p ServerParams) *Server {
 		billingDashboardSvc:         p.BillingDashboardSvc,
 		billingOperationsSvc:        p.BillingOperationsSvc,
 		billingOverviewSvc:          p.BillingOverviewSvc,
+		reconciliationSvc:           p.ReconciliationSvc,
+		couponSvc:                   p.CouponSvc,
+		idempotencySvc:              p.IdempotencySvc,
 		billingRollup:               p.BillingRollup,
 		invoiceSvc:                  p.InvoiceSvc,
 		meterSvc:                    p.MeterSvc,
@@ -335,8 +368,10 @@ func NewServer(p ServerParams) *Server {
 		productFeatureSvc:           p.ProductFeatureSvc,
 		featureSvc:                  p.FeatureSvc,
 		paymentSvc:                  p.PaymentSvc,
+		creditNoteSvc:               p.CreditNoteSvc,
 		paymentProviderSvc:          p.PaymentProviderSvc,
 		invoiceTemplateSvc:          p.InvoiceTemplateSvc,
+		billingPreferencesSvc:       p.BillingPreferencesSvc,
 		refrepo:                     p.Refrepo,
 		ratingSvc:                   p.RatingSvc,
 		subscriptionSvc:             p.SubscriptionSvc,
@@ -345,6 +380,7 @@ func NewServer(p ServerParams) *Server {
 		liveMeterEvents:             p.LiveMeterEvents,
 		obsMetrics:                  p.ObsMetrics,
 		usageLimiter:                p.UsageLimiter,
+		apiKeyRateLimiter:           p.APIKeyRateLimiter,
 		publicInvoiceSvc:            p.PublicInvoiceSvc,
 		publicInvoiceLimiter:        newRateLimiter(30, time.Minute),
 		publicPaymentIntentLimiter:  newRateLimiter(5, time.Minute),
@@ -354,10 +390,12 @@ func NewServer(p ServerParams) *Server {
 		paymentMethodConfigSvc:      p.PaymentMethodConfigSvc,
 		checkoutSvc:                 p.CheckoutSvc,
 		integrationSvc:              p.IntegrationSvc,
+		webhookDispatcher:           p.WebhookDispatcher,
 		testClockSvc:                p.TestClockSvc,
 		licenseSvc:                  p.LicenseSvc,
 		scheduler:                   p.Scheduler,
 		schemaGate:                  p.SchemaGate,
+		redis:                       p.Redis,
 	}
 
 	return svc
@@ -409,6 +447,12 @@ func (s *Server) RegisterAPIRoutes() {
 	api.GET("/countries", s.APIKeyRequired(), s.ListCountries)
 	api.GET("/timezones", s.APIKeyRequired(), s.ListTimezones)
 	api.GET("/currencies", s.APIKeyRequired(), s.ListCurrencies)
+	api.GET("/me", s.APIKeyRequired(), s.GetAPIKeyMe)
+	api.GET("/me/rate-limit", s.APIKeyRequired(), s.GetAPIKeyRateLimit)
+
+	// -------- Organization --------
+	api.GET("/organization/billing-preferences", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectOrganization, authorization.ActionOrganizationView), s.GetBillingPreferences)
+	api.PUT("/organization/billing-preferences", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectOrganization, authorization.ActionOrganizationUpdate), s.UpdateBillingPreferences)
 
 	// -------- Meters --------
 	api.GET("/meters", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectMeter, authorization.ActionMeterView), s.ListMeters)
@@ -435,6 +479,9 @@ func (s *Server) RegisterAPIRoutes() {
 	api.GET("/prices", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectPrice, authorization.ActionPriceView), s.ListPrices)
 	api.POST("/prices", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectPrice, authorization.ActionPriceCreate), s.CreatePrice)
 	api.GET("/prices/:id", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectPrice, authorization.ActionPriceView), s.GetPriceByID)
+	api.POST("/prices/:id/amounts/schedule", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectPriceAmount, authorization.ActionPriceAmountCreate), s.SchedulePriceAmount)
+	api.POST("/prices/:id/tiers/import", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectPriceTier, authorization.ActionPriceTierCreate), s.ImportPriceTiers)
+	api.GET("/prices/:id/tiers/export", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectPriceTier, authorization.ActionPriceTierView), s.ExportPriceTiers)
 
 	// -------- Price Amounts --------
 	api.GET("/price_amounts", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectPriceAmount, authorization.ActionPriceAmountView), s.ListPriceAmounts)
@@ -446,26 +493,51 @@ func (s *Server) RegisterAPIRoutes() {
 	api.POST("/price_tiers", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectPriceTier, authorization.ActionPriceTierCreate), s.CreatePriceTier)
 	api.GET("/price_tiers/:id", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectPriceTier, authorization.ActionPriceTierView), s.GetPriceTierByID)
 
+	// -------- Coupons --------
+	api.GET("/coupons", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCoupon, authorization.ActionCouponView), s.ListCoupons)
+	api.POST("/coupons", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCoupon, authorization.ActionCouponCreate), s.CreateCoupon)
+
 	// -------- Subscriptions --------
 	// Shared handlers, different gates: API keys use scopes, admin uses RBAC.
 	api.GET("/subscriptions", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionView), s.ListSubscriptions)
 	api.POST("/subscriptions", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionCreate), s.CreateSubscription)
 	api.GET("/subscriptions/:id", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionView), s.GetSubscriptionByID)
+	api.GET("/subscriptions/:id/billing-cycles", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionView), s.ListSubscriptionBillingCycles)
 	api.GET("/subscriptions/:id/entitlements", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionView), s.ListSubscriptionEntitlements)
+	api.GET("/subscriptions/:id/entitlements/history", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionView), s.GetSubscriptionEntitlementHistory)
+	api.GET("/subscriptions/:id/events", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionView), s.ListSubscriptionEvents)
+	api.PATCH("/subscriptions/:id/metadata", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionUpdate), s.UpdateSubscriptionMetadata)
+	api.GET("/subscriptions/:id/items", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionView), s.ListSubscriptionItems)
+	api.GET("/subscriptions/:id/seat-usage", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionView), s.GetSubscriptionSeatUsage)
 	api.PUT("/subscriptions/:id/items", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionUpdate), s.ReplaceSubscriptionItems)
+	api.POST("/subscriptions/:id/items/:item_id/quantity", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionUpdate), s.UpdateSubscriptionItemQuantity)
+	api.POST("/subscriptions/:id/preview-proration", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionView), s.PreviewSubscriptionProration)
 	api.POST("/subscriptions/:id/activate", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionActivate), s.ActivateSubscription)
 	api.POST("/subscriptions/:id/pause", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionPause), s.PauseSubscription)
 	api.POST("/subscriptions/:id/resume", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionResume), s.ResumeSubscription)
 	api.POST("/subscriptions/:id/cancel", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionCancel), s.CancelSubscription)
+	api.POST("/subscriptions/:id/discounts", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionUpdate), s.ApplySubscriptionDiscount)
+	api.DELETE("/subscriptions/:id/discounts/:discount_id", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionUpdate), s.RemoveSubscriptionDiscount)
+
+	// -------- Billing Cycles --------
+	api.GET("/billing-cycles/:id/rating-preview", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectBillingCycle, authorization.ActionBillingCycleRate), s.PreviewBillingCycleRating)
 
 	// -------- Invoices --------
 	api.GET("/invoices", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectInvoice, authorization.ActionInvoiceView), s.ListInvoices)
 	api.GET("/invoices/:id", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectInvoice, authorization.ActionInvoiceView), s.GetInvoiceByID)
+	api.GET("/invoices/:id/credit-notes", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectInvoice, authorization.ActionInvoiceView), s.ListInvoiceCreditNotes)
+	api.POST("/invoices/:id/pdf", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectInvoice, authorization.ActionInvoiceView), s.GenerateInvoicePDF)
+	api.GET("/invoices/:id/pdf", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectInvoice, authorization.ActionInvoiceView), s.DownloadInvoicePDF)
 
 	// -------- Customers --------
 	api.GET("/customers", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCustomer, authorization.ActionCustomerView), s.ListCustomers)
 	api.POST("/customers", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCustomer, authorization.ActionCustomerCreate), s.CreateCustomer)
 	api.GET("/customers/:id", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCustomer, authorization.ActionCustomerView), s.GetCustomerByID)
+	api.PATCH("/customers/:id/currency", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCustomer, authorization.ActionCustomerUpdate), s.UpdateCustomerCurrency)
+	api.PATCH("/customers/:id/payment-terms", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCustomer, authorization.ActionCustomerUpdate), s.UpdateCustomerPaymentTerms)
+	api.POST("/customers/:id/charges", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectInvoice, authorization.ActionInvoiceFinalize), s.CreateOneTimeCharge)
+	api.GET("/customers/:id/statement", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCustomer, authorization.ActionCustomerView), s.GetCustomerStatement)
+	api.GET("/customers/:id/entitlements/:feature_code", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCustomer, authorization.ActionCustomerView), s.CheckCustomerEntitlement)
 
 	// -------- Features --------
 	api.GET("/features", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectProduct, authorization.ActionProductView), s.ListFeatures) // Features are parts of products
@@ -476,6 +548,12 @@ func (s *Server) RegisterAPIRoutes() {
 	// -------- Payment Webhooks --------
 	api.POST("/payments/webhooks/:provider", s.HandlePaymentWebhook)
 
+	// -------- Email Delivery Webhooks --------
+	api.POST("/emails/webhooks/:provider", s.HandleEmailDeliveryWebhook)
+
+	// -------- Payments --------
+	api.POST("/payments/:id/refund", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectInvoice, authorization.ActionInvoiceUpdate), s.RefundPayment)
+
 	// -------- Payment Methods (Customer) --------
 	api.GET("/payment-methods/available", s.APIKeyRequired(), s.ListAvailablePaymentMethods) // Public lookup
 	api.GET("/customers/:id/payment-methods", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCustomer, authorization.ActionCustomerView), s.ListCustomerPaymentMethods)
@@ -484,22 +562,24 @@ func (s *Server) RegisterAPIRoutes() {
 	api.POST("/customers/:id/payment-methods/:pm_id/default", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCustomer, authorization.ActionCustomerUpdate), s.SetDefaultPaymentMethod)
 
 	// -------- Checkout Sessions --------
-	api.POST("/checkout/sessions", s.APIKeyRequired(), s.CreateCheckoutSession)
-	api.GET("/checkout/sessions/:session_id/verify", s.APIKeyRequired(), s.VerifyCheckoutSession)
+	api.POST("/checkout/sessions", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCheckout, authorization.ActionCheckoutCreate), s.CreateCheckoutSession)
+	api.GET("/checkout/sessions/:session_id/verify", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectCheckout, authorization.ActionCheckoutCreate), s.VerifyCheckoutSession)
 
 	// -------- Test Clocks (Public for Simulation) --------
 	log.Println("DEBUG: Registering public test clock routes")
-	api.POST("/test-clocks", s.APIKeyRequired(), func(c *gin.Context) {
+	api.POST("/test-clocks", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectTestClock, authorization.ActionTestClockManage), func(c *gin.Context) {
 		log.Println("DEBUG: POST /api/test-clocks hit")
 		s.CreateTestClock(c)
 	})
-	api.GET("/test-clocks", s.APIKeyRequired(), s.ListTestClocks)
-	api.GET("/test-clocks/:id", s.APIKeyRequired(), s.GetTestClock)
-	api.POST("/test-clocks/:id/advance", s.APIKeyRequired(), s.AdvanceTestClock)
+	api.GET("/test-clocks", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectTestClock, authorization.ActionTestClockManage), s.ListTestClocks)
+	api.GET("/test-clocks/:id", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectTestClock, authorization.ActionTestClockManage), s.GetTestClock)
+	api.POST("/test-clocks/:id/advance", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectTestClock, authorization.ActionTestClockManage), s.AdvanceTestClock)
 
 	api.POST("/usage", s.APIKeyRequired(), s.UsageIngestRateLimit(), s.authorizeOrgAction(authorization.ObjectUsage, authorization.ActionUsageIngest), s.IngestUsage)
+	api.POST("/usage/batch", s.APIKeyRequired(), s.UsageIngestRateLimit(), s.authorizeOrgAction(authorization.ObjectUsage, authorization.ActionUsageIngest), s.BatchIngestUsage)
 	api.GET("/usage", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectUsage, authorization.ActionUsageView), s.ListUsage)
 	api.GET("/usage/summary", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectUsage, authorization.ActionUsageView), s.GetUsageSummary)
+	api.POST("/usage/import", s.APIKeyRequired(), s.authorizeOrgAction(authorization.ObjectUsage, authorization.ActionUsageIngest), s.ImportUsage)
 
 	if s.cfg.Environment != "production" {
 		api.POST("/test/cleanup", s.TestCleanup)
@@ -520,6 +600,7 @@ func (s *Server) RegisterAdminRoutes() {
 	// --- global middlewares ---
 	admin.Use(s.WebAuthRequired())
 	admin.Use(s.OrgContext())
+	admin.Use(s.Idempotency())
 
 	// Home / Dashboard
 	admin.GET("/home", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.GetHomeDashboard)
@@ -565,6 +646,9 @@ func (s *Server) RegisterAdminRoutes() {
 	admin.GET("/prices", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ListPrices)
 	admin.POST("/prices", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.CreatePrice)
 	admin.GET("/prices/:id", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.GetPriceByID)
+	admin.POST("/prices/:id/amounts/schedule", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.SchedulePriceAmount)
+	admin.POST("/prices/:id/tiers/import", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ImportPriceTiers)
+	admin.GET("/prices/:id/tiers/export", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ExportPriceTiers)
 
 	// -------- Price Amounts --------
 	admin.GET("/price_amounts", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ListPriceAmounts)
@@ -576,16 +660,33 @@ func (s *Server) RegisterAdminRoutes() {
 	admin.POST("/price_tiers", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.CreatePriceTier)
 	admin.GET("/price_tiers/:id", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.GetPriceTierByID)
 
+	// -------- Coupons --------
+	admin.GET("/coupons", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ListCoupons)
+	admin.POST("/coupons", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.CreateCoupon)
+
 	// -------- Subscriptions --------
 	admin.GET("/subscriptions", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.ListSubscriptions)
 	admin.POST("/subscriptions", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.CreateSubscription)
 	admin.GET("/subscriptions/:id", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.GetSubscriptionByID)
+	admin.GET("/subscriptions/:id/billing-cycles", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.ListSubscriptionBillingCycles)
 	admin.GET("/subscriptions/:id/entitlements", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.ListSubscriptionEntitlements)
+	admin.GET("/subscriptions/:id/entitlements/history", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.GetSubscriptionEntitlementHistory)
+	admin.GET("/subscriptions/:id/events", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.ListSubscriptionEvents)
+	admin.PATCH("/subscriptions/:id/metadata", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.UpdateSubscriptionMetadata)
+	admin.GET("/subscriptions/:id/items", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.ListSubscriptionItems)
+	admin.GET("/subscriptions/:id/seat-usage", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.GetSubscriptionSeatUsage)
 	admin.PUT("/subscriptions/:id/items", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ReplaceSubscriptionItems)
+	admin.POST("/subscriptions/:id/items/:item_id/quantity", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.UpdateSubscriptionItemQuantity)
+	admin.POST("/subscriptions/:id/preview-proration", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.PreviewSubscriptionProration)
 	admin.POST("/subscriptions/:id/activate", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionActivate), s.ActivateSubscription)
 	admin.POST("/subscriptions/:id/pause", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionPause), s.PauseSubscription)
 	admin.POST("/subscriptions/:id/resume", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionResume), s.ResumeSubscription)
 	admin.POST("/subscriptions/:id/cancel", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.authorizeOrgAction(authorization.ObjectSubscription, authorization.ActionSubscriptionCancel), s.CancelSubscription)
+	admin.POST("/subscriptions/:id/discounts", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ApplySubscriptionDiscount)
+	admin.DELETE("/subscriptions/:id/discounts/:discount_id", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.RemoveSubscriptionDiscount)
+
+	// -------- Billing Cycles --------
+	admin.GET("/billing-cycles/:id/rating-preview", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.PreviewBillingCycleRating)
 
 	// -------- Usage --------
 	admin.GET("/usage", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.ListUsage)
@@ -595,6 +696,11 @@ func (s *Server) RegisterAdminRoutes() {
 	admin.GET("/invoices/:id", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.GetInvoiceByID)
 	admin.GET("/invoices/:id/render", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.RenderInvoice)
 	admin.GET("/invoices/:id/explanation", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.ExplainInvoice)
+	admin.GET("/invoices/:id/credit-notes", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.ListInvoiceCreditNotes)
+	admin.POST("/invoices/:id/pdf", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.GenerateInvoicePDF)
+	admin.GET("/invoices/:id/pdf", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.DownloadInvoicePDF)
+	admin.POST("/invoices/:id/void", s.RequireRole(organizationdomain.RoleOwner), s.authorizeOrgAction(authorization.ObjectInvoice, authorization.ActionInvoiceVoid), s.VoidInvoice)
+	admin.POST("/invoices/:id/charge", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.authorizeOrgAction(authorization.ObjectInvoice, authorization.ActionInvoiceCharge), s.ChargeInvoice)
 
 	// -------- Billing Dashboard --------
 	admin.GET("/billing/customers", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.authorizeOrgAction(authorization.ObjectBillingDashboard, authorization.ActionBillingDashboardView), s.ListBillingCustomers)
@@ -613,6 +719,7 @@ func (s *Server) RegisterAdminRoutes() {
 	admin.GET("/billing/overview/outstanding", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.authorizeOrgAction(authorization.ObjectBillingOverview, authorization.ActionBillingOverviewView), s.GetBillingOverviewOutstandingBalance)
 	admin.GET("/billing/overview/collection-rate", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.authorizeOrgAction(authorization.ObjectBillingOverview, authorization.ActionBillingOverviewView), s.GetBillingOverviewCollectionRate)
 	admin.GET("/billing/overview/subscribers", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.authorizeOrgAction(authorization.ObjectBillingOverview, authorization.ActionBillingOverviewView), s.GetBillingOverviewSubscribers)
+	admin.GET("/billing/reconciliation", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.authorizeOrgAction(authorization.ObjectBillingOverview, authorization.ActionBillingOverviewView), s.GetBillingReconciliation)
 
 	// -------- Billing Change Requests (Approval Workflow) --------
 	admin.GET("/billing/change-requests", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ListBillingChangeRequests)
@@ -629,8 +736,10 @@ func (s *Server) RegisterAdminRoutes() {
 	admin.GET("/billing-operations/inbox", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.GetBillingOperationsInbox)
 	admin.GET("/billing-operations/my-work", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.GetBillingOperationsMyWork)
 	admin.GET("/billing-operations/recently-resolved", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.GetBillingOperationsRecentlyResolved)
+	admin.GET("/billing-operations/performance/history", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.GetBillingOperationsPerformanceHistory)
 	admin.GET("/billing-operations/team", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.RequireCapability("sso"), s.GetBillingOperationsTeamView)
 	admin.GET("/billing-operations/invoices/:id/payments", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.GetBillingOperationsInvoicePayments)
+	admin.GET("/billing-operations/assignments/:entity_type/:entity_id", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.GetBillingOperationsAssignment)
 
 	admin.GET("/organizations/:id/members", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.ListOrganizationMembers)
 	admin.GET("/organizations/:id/readiness", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.GetOrganizationReadiness)
@@ -638,17 +747,27 @@ func (s *Server) RegisterAdminRoutes() {
 	// -------- Billing Operations Actions --------
 	admin.POST("/billing-operations/claim", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.PostBillingOperationsAssignment)
 	admin.POST("/billing-operations/release", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.ReleaseBillingOperationsAssignment)
+	admin.POST("/billing-operations/bulk-claim", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.BulkClaimBillingOperationsAssignments)
+	admin.POST("/billing-operations/bulk-release", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.BulkReleaseBillingOperationsAssignments)
 	admin.POST("/billing-operations/resolve", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.ResolveBillingOperationsAssignment)
 	admin.POST("/billing-operations/record-follow-up", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleMember, organizationdomain.RoleFinOps), s.RecordBillingOperationsFollowUp)
 
 	admin.POST("/internal/rebuild-billing-snapshots", s.RequireRole(organizationdomain.RoleOwner), s.RebuildBillingSnapshots)
 
 	// -------- Invoice Templates --------
+	admin.GET("/organization/billing-preferences", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.GetBillingPreferences)
+	admin.PUT("/organization/billing-preferences", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.UpdateBillingPreferences)
 	admin.GET("/invoice-templates", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ListInvoiceTemplates)
 	admin.POST("/invoice-templates", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.CreateInvoiceTemplate)
 	admin.GET("/invoice-templates/:id", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.GetInvoiceTemplateByID)
 	admin.PATCH("/invoice-templates/:id", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.UpdateInvoiceTemplate)
 	admin.POST("/invoice-templates/:id/set-default", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.SetDefaultInvoiceTemplate)
+	admin.GET("/invoice-templates/:id/versions", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ListInvoiceTemplateVersions)
+	admin.POST("/invoice-templates/:id/versions/:version/restore", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.RestoreInvoiceTemplateVersion)
+
+	// -------- Payment Webhooks --------
+	admin.POST("/payments/webhooks/:id/replay", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.authorizeOrgAction(authorization.ObjectPaymentProvider, authorization.ActionPaymentProviderManage), s.ReplayPaymentWebhook)
+	admin.POST("/payments/:id/refund", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.RefundPayment)
 
 	// -------- Checkout Options (Legacy Payment Methods Config) --------
 	admin.GET("/payment-method-configs", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.authorizeOrgAction(authorization.ObjectPaymentProvider, authorization.ActionPaymentProviderManage), s.ListPaymentMethodConfigs)
@@ -660,6 +779,11 @@ func (s *Server) RegisterAdminRoutes() {
 	admin.GET("/customers", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.ListCustomers)
 	admin.POST("/customers", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.CreateCustomer)
 	admin.GET("/customers/:id", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.GetCustomerByID)
+	admin.PATCH("/customers/:id/currency", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.UpdateCustomerCurrency)
+	admin.PATCH("/customers/:id/payment-terms", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.UpdateCustomerPaymentTerms)
+	admin.POST("/customers/:id/charges", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.authorizeOrgAction(authorization.ObjectInvoice, authorization.ActionInvoiceFinalize), s.CreateOneTimeCharge)
+	admin.GET("/customers/:id/statement", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.GetCustomerStatement)
+	admin.GET("/customers/:id/entitlements/:feature_code", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin, organizationdomain.RoleFinOps), s.CheckCustomerEntitlement)
 
 	admin.GET("/audit-logs", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.authorizeOrgAction(authorization.ObjectAuditLog, authorization.ActionAuditLogView), s.ListAuditLogs)
 	admin.GET("/audit-logs/export", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ExportAuditLogs)
@@ -687,6 +811,15 @@ func (s *Server) RegisterAdminRoutes() {
 		integrations.POST("/connect", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ConnectIntegration)
 		integrations.POST("/:id/disconnect", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.DisconnectIntegration)
 	}
+
+	// -------- Outbound Webhooks --------
+	webhookEndpoints := admin.Group("/webhook-endpoints")
+	{
+		webhookEndpoints.GET("", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ListWebhookEndpoints)
+		webhookEndpoints.POST("", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.CreateWebhookEndpoint)
+		webhookEndpoints.POST("/:id/disable", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.DisableWebhookEndpoint)
+		webhookEndpoints.GET("/:id/attempts", s.RequireRole(organizationdomain.RoleOwner, organizationdomain.RoleAdmin), s.ListWebhookDeliveryAttempts)
+	}
 }
 
 func (s *Server) GetSSOConfig(c *gin.Context) {
@@ -2,8 +2,10 @@ package server
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/snowflake"
 	"github.com/gin-gonic/gin"
@@ -12,18 +14,23 @@ import (
 )
 
 type createSubscriptionItemRequest struct {
-	PriceID  string  `json:"price_id"`
-	MeterID  *string `json:"meter_id,omitempty"`
-	Quantity int8    `json:"quantity,omitempty"`
+	PriceID           string  `json:"price_id"`
+	MeterID           *string `json:"meter_id,omitempty"`
+	Quantity          int32   `json:"quantity,omitempty"`
+	UsageBehavior     string  `json:"usage_behavior,omitempty"`
+	ProrationBehavior string  `json:"proration_behavior,omitempty"`
 }
 
 type createSubscriptionRequest struct {
-	CustomerID       string                                        `json:"customer_id"`
-	CollectionMode   subscriptiondomain.SubscriptionCollectionMode `json:"collection_mode"`
-	BillingCycleType string                                        `json:"billing_cycle_type"`
-	Items            []createSubscriptionItemRequest               `json:"items"`
-	TrialDays        *int                                          `json:"trial_days,omitempty"`
-	Metadata         map[string]any                                `json:"metadata,omitempty"`
+	CustomerID               string                                        `json:"customer_id"`
+	CollectionMode           subscriptiondomain.SubscriptionCollectionMode `json:"collection_mode"`
+	BillingCycleType         string                                        `json:"billing_cycle_type"`
+	Items                    []createSubscriptionItemRequest               `json:"items"`
+	TrialDays                *int                                          `json:"trial_days,omitempty"`
+	MinimumCommitmentCents   *int64                                        `json:"minimum_commitment_cents,omitempty"`
+	SpendAlertThresholdCents *int64                                        `json:"spend_alert_threshold_cents,omitempty"`
+	StartAt                  *time.Time                                    `json:"start_at,omitempty"`
+	Metadata                 map[string]any                                `json:"metadata,omitempty"`
 }
 
 // @Summary      Create Subscription
@@ -49,12 +56,15 @@ func (s *Server) CreateSubscription(c *gin.Context) {
 	}
 
 	resp, err := s.subscriptionSvc.Create(c.Request.Context(), subscriptiondomain.CreateSubscriptionRequest{
-		CustomerID:       strings.TrimSpace(req.CustomerID),
-		CollectionMode:   req.CollectionMode,
-		BillingCycleType: strings.TrimSpace(req.BillingCycleType),
-		Items:            normalizeSubscriptionItems(req.Items),
-		Metadata:         req.Metadata,
-		IdempotencyKey:   idempotencyKeyFromHeader(c),
+		CustomerID:               strings.TrimSpace(req.CustomerID),
+		CollectionMode:           req.CollectionMode,
+		BillingCycleType:         strings.TrimSpace(req.BillingCycleType),
+		Items:                    normalizeSubscriptionItems(req.Items),
+		MinimumCommitmentCents:   req.MinimumCommitmentCents,
+		SpendAlertThresholdCents: req.SpendAlertThresholdCents,
+		StartAt:                  req.StartAt,
+		Metadata:                 req.Metadata,
+		IdempotencyKey:           idempotencyKeyFromHeader(c),
 	})
 	if err != nil {
 		AbortWithError(c, err)
@@ -124,6 +134,56 @@ func (s *Server) ReplaceSubscriptionItems(c *gin.Context) {
 	respondData(c, resp)
 }
 
+type previewProrationRequest struct {
+	Items             []createSubscriptionItemRequest `json:"items,omitempty"`
+	NewPriceID        string                          `json:"new_price_id,omitempty"`
+	NewProductID      string                          `json:"new_product_id,omitempty"`
+	ProrationBehavior string                          `json:"proration_behavior,omitempty"`
+}
+
+// @Summary      Preview Subscription Proration
+// @Description  Preview the prorated credits and charges that replacing the items or changing the plan of a subscription would add to the current billing cycle. Nothing is persisted.
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                   true  "Subscription ID"
+// @Param        request  body      previewProrationRequest  true  "Preview Proration Request"
+// @Success      200  {object}  DataResponse
+// @Router       /subscriptions/{id}/preview-proration [post]
+func (s *Server) PreviewSubscriptionProration(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	var req previewProrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	if err := rejectSubscriptionMeterID(req.Items); err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	resp, err := s.subscriptionSvc.PreviewProration(c.Request.Context(), subscriptiondomain.PreviewProrationRequest{
+		SubscriptionID:    id,
+		Items:             normalizeSubscriptionItems(req.Items),
+		NewPriceID:        strings.TrimSpace(req.NewPriceID),
+		NewProductID:      strings.TrimSpace(req.NewProductID),
+		ProrationBehavior: strings.TrimSpace(req.ProrationBehavior),
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
 // @Summary      List Subscriptions
 // @Description  List available subscriptions
 // @Tags         subscriptions
@@ -134,6 +194,7 @@ func (s *Server) ReplaceSubscriptionItems(c *gin.Context) {
 // @Param        customer_id   query     string  false  "Customer ID"
 // @Param        created_from  query     string  false  "Created From"
 // @Param        created_to    query     string  false  "Created To"
+// @Param        fields        query     string  false  "Comma separated columns to return, e.g. id,status,customer_id"
 // @Param        page_token    query     string  false  "Page Token"
 // @Param        page_size     query     int     false  "Page Size"
 // @Success      200  {object}  ListResponse
@@ -145,6 +206,7 @@ func (s *Server) ListSubscriptions(c *gin.Context) {
 		CustomerID  string `form:"customer_id"`
 		CreatedFrom string `form:"created_from"`
 		CreatedTo   string `form:"created_to"`
+		Fields      string `form:"fields"`
 	}
 	if err := c.ShouldBindQuery(&query); err != nil {
 		AbortWithError(c, invalidRequestError())
@@ -163,6 +225,7 @@ func (s *Server) ListSubscriptions(c *gin.Context) {
 		return
 	}
 
+	fields := parseFields(query.Fields)
 	resp, err := s.subscriptionSvc.List(c.Request.Context(), subscriptiondomain.ListSubscriptionRequest{
 		Status:      strings.TrimSpace(query.Status),
 		CustomerID:  strings.TrimSpace(query.CustomerID),
@@ -170,13 +233,14 @@ func (s *Server) ListSubscriptions(c *gin.Context) {
 		PageSize:    int32(query.PageSize),
 		CreatedFrom: createdFrom,
 		CreatedTo:   createdTo,
+		Fields:      fields,
 	})
 	if err != nil {
 		AbortWithError(c, err)
 		return
 	}
 
-	respondList(c, resp.Subscriptions, &resp.PageInfo)
+	respondFieldList(c, resp.Subscriptions, fields, subscriptiondomain.ListFields, &resp.PageInfo)
 }
 
 // @Summary      Get Subscription
@@ -252,21 +316,377 @@ func (s *Server) ListSubscriptionEntitlements(c *gin.Context) {
 	respondList(c, resp.Entitlements, &resp.PageInfo)
 }
 
-// @Summary      Cancel Subscription
-// @Description  Cancel a subscription
+// @Summary      Check Customer Entitlement
+// @Description  Check whether a customer's active subscription grants a feature, with the remaining included quota for metered features
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id            path     string  true   "Customer ID"
+// @Param        feature_code  path     string  true   "Feature Code"
+// @Param        at            query    string  false  "At (RFC3339 or YYYY-MM-DD, defaults to now)"
+// @Success      200  {object}  DataResponse
+// @Router       /customers/{id}/entitlements/{feature_code} [get]
+func (s *Server) CheckCustomerEntitlement(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	at, err := parseOptionalTime(c.Query("at"), false)
+	if err != nil {
+		AbortWithError(c, newValidationError("at", "invalid_at", "invalid at"))
+		return
+	}
+
+	resp, err := s.subscriptionSvc.CheckEntitlement(c.Request.Context(), subscriptiondomain.CheckEntitlementRequest{
+		CustomerID:  id,
+		FeatureCode: c.Param("feature_code"),
+		At:          at,
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
+// @Summary      Get Subscription Entitlement History
+// @Description  List the effective windows of a feature entitlement with the event that opened and closed each window
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id            path     string  true   "Subscription ID"
+// @Param        feature_code  query    string  true   "Feature Code"
+// @Param        page_token    query    string  false  "Page Token"
+// @Param        page_size     query    int     false  "Page Size"
+// @Success      200  {object}  ListResponse
+// @Router       /subscriptions/{id}/entitlements/history [get]
+func (s *Server) GetSubscriptionEntitlementHistory(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	var query struct {
+		pagination.Pagination
+		FeatureCode string `form:"feature_code"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	featureCode := strings.TrimSpace(query.FeatureCode)
+	if featureCode == "" {
+		AbortWithError(c, newValidationError("feature_code", "required", "feature_code is required"))
+		return
+	}
+
+	resp, err := s.subscriptionSvc.GetEntitlementHistory(c.Request.Context(), subscriptiondomain.GetEntitlementHistoryRequest{
+		SubscriptionID: id,
+		FeatureCode:    featureCode,
+		PageToken:      query.PageToken,
+		PageSize:       int32(query.PageSize),
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondList(c, resp.History, &resp.PageInfo)
+}
+
+// @Summary      List Subscription Events
+// @Description  List the status changes of a subscription oldest first, with the reason and the actor that triggered each one
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id          path     string  true   "Subscription ID"
+// @Param        page_token  query    string  false  "Page Token"
+// @Param        page_size   query    int     false  "Page Size"
+// @Success      200  {object}  ListResponse
+// @Router       /subscriptions/{id}/events [get]
+func (s *Server) ListSubscriptionEvents(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	var query pagination.Pagination
+	if err := c.ShouldBindQuery(&query); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	resp, err := s.subscriptionSvc.ListEvents(c.Request.Context(), subscriptiondomain.ListSubscriptionEventsRequest{
+		SubscriptionID: id,
+		PageToken:      query.PageToken,
+		PageSize:       int32(query.PageSize),
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondList(c, resp.Events, &resp.PageInfo)
+}
+
+type cancelSubscriptionRequest struct {
+	// Either immediate (default) or period_end.
+	CancelAt string `json:"cancel_at"`
+}
+
+// @Summary      List Subscription Billing Cycles
+// @Description  List the billing cycles of a subscription with the rated amount of each cycle
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id          path     string  true   "Subscription ID"
+// @Param        status      query    string  false  "Cycle Status (OPEN, CLOSING, CLOSED)"
+// @Param        page_token  query    string  false  "Page Token"
+// @Param        page_size   query    int     false  "Page Size"
+// @Success      200  {object}  ListResponse
+// @Router       /subscriptions/{id}/billing-cycles [get]
+func (s *Server) ListSubscriptionBillingCycles(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	var query struct {
+		pagination.Pagination
+		Status string `form:"status"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	resp, err := s.subscriptionSvc.ListBillingCycles(c.Request.Context(), subscriptiondomain.ListBillingCyclesRequest{
+		SubscriptionID: id,
+		Status:         query.Status,
+		PageToken:      query.PageToken,
+		PageSize:       int32(query.PageSize),
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondList(c, resp.BillingCycles, &resp.PageInfo)
+}
+
+// @Summary      List Subscription Items
+// @Description  List the items of a subscription with the unit amount each price currently bills in the subscription currency
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id          path     string  true   "Subscription ID"
+// @Param        page_token  query    string  false  "Page Token"
+// @Param        page_size   query    int     false  "Page Size"
+// @Success      200  {object}  ListResponse
+// @Router       /subscriptions/{id}/items [get]
+func (s *Server) ListSubscriptionItems(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	var query struct {
+		pagination.Pagination
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	resp, err := s.subscriptionSvc.ListItems(c.Request.Context(), subscriptiondomain.ListSubscriptionItemsRequest{
+		SubscriptionID: id,
+		PageToken:      query.PageToken,
+		PageSize:       int32(query.PageSize),
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondList(c, resp.Items, &resp.PageInfo)
+}
+
+// @Summary      Get Subscription Seat Usage
+// @Description  Compare the seats counted by each licensed item's seat meter in the current billing cycle with the licensed quantity and flag overages
 // @Tags         subscriptions
 // @Accept       json
 // @Produce      json
 // @Security     ApiKeyAuth
 // @Param        id   path      string  true  "Subscription ID"
+// @Success      200  {object}  DataResponse
+// @Router       /subscriptions/{id}/seat-usage [get]
+func (s *Server) GetSubscriptionSeatUsage(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	resp, err := s.subscriptionSvc.GetSeatUsage(c.Request.Context(), id)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+type updateSubscriptionMetadataRequest struct {
+	Metadata map[string]any `json:"metadata"`
+}
+
+// @Summary      Update Subscription Metadata
+// @Description  Merge keys into subscription metadata; a null value removes the key
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                             true  "Subscription ID"
+// @Param        request  body      updateSubscriptionMetadataRequest  true  "Update Subscription Metadata Request"
+// @Success      200  {object}  DataResponse
+// @Router       /subscriptions/{id}/metadata [patch]
+func (s *Server) UpdateSubscriptionMetadata(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	var req updateSubscriptionMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	item, err := s.subscriptionSvc.UpdateMetadata(c.Request.Context(), subscriptiondomain.UpdateMetadataRequest{
+		SubscriptionID: id,
+		Metadata:       req.Metadata,
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, item)
+}
+
+type updateSubscriptionItemQuantityRequest struct {
+	Quantity int32 `json:"quantity"`
+}
+
+// @Summary      Update Subscription Item Quantity
+// @Description  Change the seat count of a licensed item and prorate the change over the open billing cycle
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                                 true  "Subscription ID"
+// @Param        item_id  path      string                                 true  "Subscription Item ID"
+// @Param        request  body      updateSubscriptionItemQuantityRequest  true  "Update Subscription Item Quantity Request"
+// @Success      200  {object}  DataResponse
+// @Router       /subscriptions/{id}/items/{item_id}/quantity [post]
+func (s *Server) UpdateSubscriptionItemQuantity(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+	itemID := strings.TrimSpace(c.Param("item_id"))
+	if _, err := snowflake.ParseString(itemID); err != nil {
+		AbortWithError(c, newValidationError("item_id", "invalid_id", "invalid id"))
+		return
+	}
+
+	var req updateSubscriptionItemQuantityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	resp, err := s.subscriptionSvc.UpdateItemQuantity(c.Request.Context(), subscriptiondomain.UpdateItemQuantityRequest{
+		SubscriptionID: id,
+		ItemID:         itemID,
+		Quantity:       req.Quantity,
+	})
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
+// @Summary      Cancel Subscription
+// @Description  Cancel a subscription immediately, or at the end of the current billing cycle with cancel_at=period_end. Immediate cancellations credit or refund the unused upfront period when the organization opted in
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                     true   "Subscription ID"
+// @Param        request  body      cancelSubscriptionRequest  false  "Cancel Subscription Request"
 // @Success      204
 // @Router       /subscriptions/{id}/cancel [post]
 func (s *Server) CancelSubscription(c *gin.Context) {
-	s.transitionSubscription(
-		c,
-		subscriptiondomain.SubscriptionStatusCanceled,
-		"subscription.cancel",
-	)
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := snowflake.ParseString(id); err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	var req cancelSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+	cancelAt := strings.TrimSpace(req.CancelAt)
+	if cancelAt == "" {
+		cancelAt = subscriptiondomain.CancelAtImmediate
+	}
+
+	if err := s.subscriptionSvc.CancelSubscription(c.Request.Context(), subscriptiondomain.CancelSubscriptionRequest{
+		SubscriptionID: id,
+		CancelAt:       cancelAt,
+	}); err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	// Crediting is idempotent, so a failure here is safe to retry by
+	// canceling again.
+	if cancelAt == subscriptiondomain.CancelAtImmediate && s.invoiceSvc != nil {
+		if err := s.invoiceSvc.CreditCanceledSubscription(c.Request.Context(), id); err != nil {
+			AbortWithError(c, err)
+			return
+		}
+	}
+
+	if s.auditSvc != nil {
+		targetID := id
+		_ = s.auditSvc.AuditLog(c.Request.Context(), nil, "", nil, "subscription.cancel", "subscription", &targetID, map[string]any{
+			"subscription_id": id,
+			"cancel_at":       cancelAt,
+		})
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
 // @Summary      Activate Subscription
@@ -331,7 +751,7 @@ func (s *Server) transitionSubscription(c *gin.Context, target subscriptiondomai
 		c.Request.Context(),
 		id,
 		target,
-		"",
+		subscriptiondomain.TransitionReason(auditAction),
 	); err != nil {
 		AbortWithError(c, err)
 		return
@@ -356,8 +776,10 @@ func normalizeSubscriptionItems(items []createSubscriptionItemRequest) []subscri
 	normalized := make([]subscriptiondomain.CreateSubscriptionItemRequest, 0, len(items))
 	for _, item := range items {
 		normalized = append(normalized, subscriptiondomain.CreateSubscriptionItemRequest{
-			PriceID:  strings.TrimSpace(item.PriceID),
-			Quantity: item.Quantity,
+			PriceID:           strings.TrimSpace(item.PriceID),
+			Quantity:          item.Quantity,
+			UsageBehavior:     strings.TrimSpace(item.UsageBehavior),
+			ProrationBehavior: strings.TrimSpace(item.ProrationBehavior),
 		})
 	}
 	return normalized
@@ -383,9 +805,12 @@ func isSubscriptionValidationError(err error) bool {
 		errors.Is(err, subscriptiondomain.ErrInvalidSubscription),
 		errors.Is(err, subscriptiondomain.ErrInvalidMeterID),
 		errors.Is(err, subscriptiondomain.ErrInvalidMeterCode),
+		errors.Is(err, subscriptiondomain.ErrMeterArchived),
 		errors.Is(err, subscriptiondomain.ErrInvalidStatus),
 		errors.Is(err, subscriptiondomain.ErrInvalidTargetStatus),
 		errors.Is(err, subscriptiondomain.ErrInvalidTransition),
+		errors.Is(err, subscriptiondomain.ErrInvalidCancelAt),
+		errors.Is(err, subscriptiondomain.ErrNoOpenBillingCycle),
 		errors.Is(err, subscriptiondomain.ErrMissingSubscriptionItems),
 		errors.Is(err, subscriptiondomain.ErrMissingPricing),
 		errors.Is(err, subscriptiondomain.ErrMissingCustomer),
@@ -395,13 +820,26 @@ func isSubscriptionValidationError(err error) bool {
 		errors.Is(err, subscriptiondomain.ErrInvalidBillingCycleType),
 		errors.Is(err, subscriptiondomain.ErrInvalidCurrency),
 		errors.Is(err, subscriptiondomain.ErrInvalidStartAt),
+		errors.Is(err, subscriptiondomain.ErrStartAtNotReached),
 		errors.Is(err, subscriptiondomain.ErrInvalidPeriod),
 		errors.Is(err, subscriptiondomain.ErrInvalidItems),
 		errors.Is(err, subscriptiondomain.ErrInvalidQuantity),
+		errors.Is(err, subscriptiondomain.ErrInvalidMinimumCommitment),
+		errors.Is(err, subscriptiondomain.ErrInvalidSpendThreshold),
+		errors.Is(err, subscriptiondomain.ErrInvalidUsageBehavior),
+		errors.Is(err, subscriptiondomain.ErrInvalidProrationBehavior),
+		errors.Is(err, subscriptiondomain.ErrInvalidDimensionFilter),
+		errors.Is(err, subscriptiondomain.ErrInvalidIncludedQuantity),
+		errors.Is(err, subscriptiondomain.ErrInvalidSeatMeter),
+		errors.Is(err, subscriptiondomain.ErrInvalidOveragePrice),
+		errors.Is(err, subscriptiondomain.ErrInvalidFields),
 		errors.Is(err, subscriptiondomain.ErrInvalidPrice),
 		errors.Is(err, subscriptiondomain.ErrInvalidProduct),
 		errors.Is(err, subscriptiondomain.ErrMultipleFlatPrices),
-		errors.Is(err, subscriptiondomain.ErrMissingEntitlements):
+		errors.Is(err, subscriptiondomain.ErrMissingEntitlements),
+		errors.Is(err, subscriptiondomain.ErrInvalidFeatureCode),
+		errors.Is(err, subscriptiondomain.ErrInvalidMetadata),
+		errors.Is(err, subscriptiondomain.ErrQuantityChangeNotAllowed):
 		return true
 	default:
 		return false
@@ -10,6 +10,8 @@ import (
 
 func (s *Server) RegisterSystemRoutes() {
 	s.engine.GET("/ready", s.GetSystemReadiness)
+	s.engine.GET("/healthz", s.GetLiveness)
+	s.engine.GET("/readyz", s.GetReadiness)
 }
 
 // GetSystemReadiness exposes a system-level readiness endpoint for control-plane orchestration.
@@ -13,6 +13,8 @@ type createTaxDefinitionRequest struct {
 	Name        string   `json:"name"`
 	TaxMode     string   `json:"tax_mode"`
 	Rate        *float64 `json:"rate"`
+	Country     *string  `json:"country"`
+	State       *string  `json:"state"`
 	Description *string  `json:"description"`
 	IsEnabled   *bool    `json:"is_enabled"`
 }
@@ -21,6 +23,8 @@ type updateTaxDefinitionRequest struct {
 	Name        *string  `json:"name,omitempty"`
 	TaxMode     *string  `json:"tax_mode,omitempty"`
 	Rate        *float64 `json:"rate,omitempty"`
+	Country     *string  `json:"country,omitempty"`
+	State       *string  `json:"state,omitempty"`
 	Description *string  `json:"description,omitempty"`
 }
 
@@ -36,6 +40,8 @@ func (s *Server) CreateTaxDefinition(c *gin.Context) {
 		Name:        strings.TrimSpace(req.Name),
 		TaxMode:     taxdomain.TaxMode(strings.TrimSpace(req.TaxMode)),
 		Rate:        req.Rate,
+		Country:     trimTaxString(req.Country),
+		State:       trimTaxString(req.State),
 		Description: trimTaxString(req.Description),
 		IsEnabled:   req.IsEnabled,
 	})
@@ -111,6 +117,8 @@ func (s *Server) UpdateTaxDefinition(c *gin.Context) {
 		Name:        trimTaxString(req.Name),
 		TaxMode:     taxMode,
 		Rate:        req.Rate,
+		Country:     trimTaxString(req.Country),
+		State:       trimTaxString(req.State),
 		Description: trimTaxString(req.Description),
 	})
 	if err != nil {
@@ -1,6 +1,9 @@
 package server
 
 import (
+	"bytes"
+	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -89,6 +92,59 @@ func (s *Server) CreatePriceTier(c *gin.Context) {
 	respondData(c, resp)
 }
 
+// @Summary      Import Price Tiers
+// @Description  Replace the tiers of a price with a CSV file of start_quantity, end_quantity, unit_amount_cents and flat_amount_cents. Tiers must be contiguous from 0; a malformed row rejects the whole file.
+// @Tags         price_tiers
+// @Accept       text/csv
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id    path      string  true   "Price ID"
+// @Param        file  formData  file    false  "CSV file (start_quantity, end_quantity, unit_amount_cents, flat_amount_cents)"
+// @Param        unit  query     string  false  "Tier unit; defaults to the unit of the current tiers"
+// @Success      200  {object}  DataResponse
+// @Router       /prices/{id}/tiers/import [post]
+func (s *Server) ImportPriceTiers(c *gin.Context) {
+	body, err := openCSVUpload(c, pricetierdomain.ErrInvalidImportFile)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+	defer body.Close()
+
+	resp, err := s.priceTierSvc.ImportCSV(c.Request.Context(), pricetierdomain.ImportRequest{
+		PriceID: strings.TrimSpace(c.Param("id")),
+		Unit:    strings.TrimSpace(c.Query("unit")),
+	}, body)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
+// @Summary      Export Price Tiers
+// @Description  Download the tiers of a price as CSV, in the format accepted by the import endpoint
+// @Tags         price_tiers
+// @Produce      text/csv
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Price ID"
+// @Success      200  {string}  string
+// @Router       /prices/{id}/tiers/export [get]
+func (s *Server) ExportPriceTiers(c *gin.Context) {
+	priceID := strings.TrimSpace(c.Param("id"))
+
+	var buf bytes.Buffer
+	if err := s.priceTierSvc.ExportCSV(c.Request.Context(), priceID, &buf); err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"price_%s_tiers.csv\"", priceID))
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
 func isPriceTierValidationError(err error) bool {
 	switch err {
 	case pricetierdomain.ErrInvalidOrganization,
@@ -99,7 +155,9 @@ func isPriceTierValidationError(err error) bool {
 		pricetierdomain.ErrInvalidUnitAmount,
 		pricetierdomain.ErrInvalidFlatAmount,
 		pricetierdomain.ErrInvalidUnit,
-		pricetierdomain.ErrInvalidID:
+		pricetierdomain.ErrInvalidID,
+		pricetierdomain.ErrInvalidImportFile,
+		pricetierdomain.ErrTiersNotContiguous:
 		return true
 	default:
 		return false
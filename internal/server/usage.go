@@ -1,6 +1,10 @@
 package server
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,11 +22,14 @@ type usageEventResponse struct {
 	MeterID            string            `json:"meter_id,omitempty"`
 	MeterCode          string            `json:"meter_code"`
 	Value              float64           `json:"value"`
+	Type               string            `json:"type"`
 	RecordedAt         time.Time         `json:"recorded_at"`
 	Status             string            `json:"status"`
 	Error              *string           `json:"error,omitempty"`
 	IdempotencyKey     string            `json:"idempotency_key,omitempty"`
+	ImportID           string            `json:"import_id,omitempty"`
 	Metadata           datatypes.JSONMap `json:"metadata,omitempty"`
+	Dimensions         datatypes.JSONMap `json:"dimensions,omitempty"`
 	CreatedAt          time.Time         `json:"created_at"`
 }
 
@@ -32,6 +39,7 @@ type usageEventResponse struct {
 // @Accept       json
 // @Produce      json
 // @Security     ApiKeyAuth
+// @Param        Idempotency-Key  header  string  false  "Idempotency Key"
 // @Param        request body usagedomain.CreateIngestRequest true "Ingest Usage Request"
 // @Success      200  {object}  DataResponse
 // @Router       /usage [post]
@@ -42,6 +50,10 @@ func (s *Server) IngestUsage(c *gin.Context) {
 		AbortWithError(c, invalidRequestError())
 		return
 	}
+	// The body key wins; the header covers clients that only set it there.
+	if strings.TrimSpace(req.IdempotencyKey) == "" {
+		req.IdempotencyKey = idempotencyKeyFromHeader(c)
+	}
 	if meterCode := strings.TrimSpace(req.MeterCode); meterCode != "" {
 		c.Set("meter_code", meterCode)
 	}
@@ -55,6 +67,35 @@ func (s *Server) IngestUsage(c *gin.Context) {
 	respondData(c, usage)
 }
 
+type batchIngestUsageRequest struct {
+	Events []usagedomain.CreateIngestRequest `json:"events"`
+}
+
+// @Summary      Batch Ingest Usage
+// @Description  Ingest up to 500 usage events at once. Every event is validated on its own and reported as ingested, duplicate or rejected.
+// @Tags         usage
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request body batchIngestUsageRequest true "Batch Ingest Usage Request"
+// @Success      200  {object}  usagedomain.BatchIngestResponse
+// @Router       /usage/batch [post]
+func (s *Server) BatchIngestUsage(c *gin.Context) {
+	var req batchIngestUsageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	resp, err := s.usagesvc.BatchIngest(c.Request.Context(), req.Events)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+
+	respondData(c, resp)
+}
+
 // @Summary      List Usage
 // @Description  List usage events
 // @Tags         usage
@@ -66,6 +107,7 @@ func (s *Server) IngestUsage(c *gin.Context) {
 // @Param        meter_id         query     string  false  "Meter ID"
 // @Param        meter_code       query     string  false  "Meter Code"
 // @Param        status           query     string  false  "Usage Status"
+// @Param        import_id        query     string  false  "CSV Import ID"
 // @Param        recorded_from    query     string  false  "Recorded From (RFC3339 or YYYY-MM-DD)"
 // @Param        recorded_to      query     string  false  "Recorded To (RFC3339 or YYYY-MM-DD)"
 // @Param        page_token       query     string  false  "Page Token"
@@ -80,6 +122,7 @@ func (s *Server) ListUsage(c *gin.Context) {
 		MeterID        string `form:"meter_id"`
 		MeterCode      string `form:"meter_code"`
 		Status         string `form:"status"`
+		ImportID       string `form:"import_id"`
 		RecordedFrom   string `form:"recorded_from"`
 		RecordedTo     string `form:"recorded_to"`
 	}
@@ -116,6 +159,7 @@ func (s *Server) ListUsage(c *gin.Context) {
 		MeterID:        strings.TrimSpace(query.MeterID),
 		MeterCode:      strings.TrimSpace(query.MeterCode),
 		Status:         status,
+		ImportID:       strings.TrimSpace(query.ImportID),
 		PageToken:      query.PageToken,
 		PageSize:       int32(query.PageSize),
 		RecordedFrom:   recordedFrom,
@@ -180,15 +224,104 @@ func (s *Server) GetUsageSummary(c *gin.Context) {
 	respondData(c, summary)
 }
 
+// @Summary      Import Usage
+// @Description  Bulk import historical usage from a CSV file. The response is streamed as newline-delimited JSON: one result per data row followed by a summary line.
+// @Tags         usage
+// @Accept       text/csv
+// @Accept       multipart/form-data
+// @Produce      application/x-ndjson
+// @Security     ApiKeyAuth
+// @Param        file           formData  file  false  "CSV file (meter_code, customer_external_id, value, recorded_at, idempotency_key)"
+// @Param        backfill_days  query     int   false  "Maximum age of recorded_at in days"
+// @Success      200  {object}  usagedomain.ImportRowResult
+// @Router       /usage/import [post]
+func (s *Server) ImportUsage(c *gin.Context) {
+	req := usagedomain.ImportUsageRequest{}
+	if raw := strings.TrimSpace(c.Query("backfill_days")); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days <= 0 {
+			AbortWithError(c, newValidationError("backfill_days", "invalid_backfill_days", "backfill_days must be a positive integer"))
+			return
+		}
+		req.BackfillWindow = time.Duration(days) * 24 * time.Hour
+	}
+
+	body, err := openCSVUpload(c, usagedomain.ErrInvalidImportFile)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+	defer body.Close()
+
+	writer := c.Writer
+	flusher, _ := writer.(http.Flusher)
+	encoder := json.NewEncoder(writer)
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		writer.Header().Set("Content-Type", "application/x-ndjson")
+		writer.Header().Set("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+	}
+
+	summary, err := s.usagesvc.ImportCSV(c.Request.Context(), req, body, func(row usagedomain.ImportRowResult) error {
+		start()
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if !started {
+			AbortWithError(c, err)
+			return
+		}
+		// Headers are already sent; report the failure in-band.
+		_ = encoder.Encode(gin.H{"error": err.Error(), "summary": summary})
+		return
+	}
+
+	start()
+	_ = encoder.Encode(gin.H{"summary": summary})
+}
+
+// openCSVUpload accepts either a multipart "file" field or a raw text/csv
+// request body. invalidFile is returned when neither can be read.
+func openCSVUpload(c *gin.Context, invalidFile error) (io.ReadCloser, error) {
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, newValidationError("file", "required", "file is required")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, invalidFile
+		}
+		return file, nil
+	}
+	if c.Request.Body == nil {
+		return nil, invalidFile
+	}
+	return c.Request.Body, nil
+}
+
 func toUsageEventResponse(item usagedomain.UsageEvent) usageEventResponse {
 	resp := usageEventResponse{
 		MeterCode:      item.MeterCode,
 		Value:          item.Value,
+		Type:           item.Type,
 		RecordedAt:     item.RecordedAt,
 		Status:         item.Status,
 		Error:          item.Error,
 		IdempotencyKey: item.IdempotencyKey,
 		Metadata:       item.Metadata,
+		Dimensions:     item.Dimensions,
 		CreatedAt:      item.CreatedAt,
 	}
 
@@ -198,6 +331,9 @@ func toUsageEventResponse(item usagedomain.UsageEvent) usageEventResponse {
 	if item.CustomerID != 0 {
 		resp.CustomerID = item.CustomerID.String()
 	}
+	if item.ImportID != nil && *item.ImportID != 0 {
+		resp.ImportID = item.ImportID.String()
+	}
 	if item.SubscriptionID != 0 {
 		resp.SubscriptionID = item.SubscriptionID.String()
 	}
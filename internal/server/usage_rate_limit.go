@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
 	"github.com/railzwaylabs/railzway/internal/observability/logger"
 	obsmetrics "github.com/railzwaylabs/railzway/internal/observability/metrics"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
@@ -164,7 +165,7 @@ func readUsageIngestKey(c *gin.Context) (string, string, error) {
 		return "", "", nil
 	}
 
-	return strings.TrimSpace(payload.CustomerID), strings.TrimSpace(payload.MeterCode), nil
+	return strings.TrimSpace(payload.CustomerID), meterdomain.NormalizeCode(payload.MeterCode), nil
 }
 
 func normalizeRateLimitEndpoint(c *gin.Context) string {
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/gin-gonic/gin"
+	"github.com/railzwaylabs/railzway/internal/events"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+)
+
+type createWebhookEndpointRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+type createWebhookEndpointResponse struct {
+	events.WebhookEndpoint
+	// Secret is only returned when the endpoint is created.
+	Secret string `json:"secret"`
+}
+
+func (s *Server) ListWebhookEndpoints(c *gin.Context) {
+	if s.webhookDispatcher == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+	orgID, ok := orgcontext.OrgIDFromContext(c.Request.Context())
+	if !ok {
+		AbortWithError(c, ErrUnauthorized)
+		return
+	}
+
+	endpoints, err := s.webhookDispatcher.ListEndpoints(c.Request.Context(), orgID)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+	respondList(c, endpoints, nil)
+}
+
+func (s *Server) CreateWebhookEndpoint(c *gin.Context) {
+	if s.webhookDispatcher == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+	orgID, ok := orgcontext.OrgIDFromContext(c.Request.Context())
+	if !ok {
+		AbortWithError(c, ErrUnauthorized)
+		return
+	}
+
+	var req createWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithError(c, invalidRequestError())
+		return
+	}
+
+	endpoint, secret, err := s.webhookDispatcher.CreateEndpoint(c.Request.Context(), orgID, req.URL, req.EventTypes)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+	respondData(c, createWebhookEndpointResponse{WebhookEndpoint: *endpoint, Secret: secret})
+}
+
+func (s *Server) DisableWebhookEndpoint(c *gin.Context) {
+	if s.webhookDispatcher == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+	orgID, ok := orgcontext.OrgIDFromContext(c.Request.Context())
+	if !ok {
+		AbortWithError(c, ErrUnauthorized)
+		return
+	}
+	endpointID, err := snowflake.ParseString(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+
+	if err := s.webhookDispatcher.DisableEndpoint(c.Request.Context(), orgID, endpointID); err != nil {
+		AbortWithError(c, err)
+		return
+	}
+	respondData(c, gin.H{"id": endpointID.String(), "active": false})
+}
+
+func (s *Server) ListWebhookDeliveryAttempts(c *gin.Context) {
+	if s.webhookDispatcher == nil {
+		AbortWithError(c, ErrServiceUnavailable)
+		return
+	}
+	orgID, ok := orgcontext.OrgIDFromContext(c.Request.Context())
+	if !ok {
+		AbortWithError(c, ErrUnauthorized)
+		return
+	}
+	endpointID, err := snowflake.ParseString(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		AbortWithError(c, newValidationError("id", "invalid_id", "invalid id"))
+		return
+	}
+	limit := 0
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			AbortWithError(c, newValidationError("limit", "invalid_limit", "invalid limit"))
+			return
+		}
+	}
+
+	attempts, err := s.webhookDispatcher.ListDeliveryAttempts(c.Request.Context(), orgID, endpointID, limit)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+	respondList(c, attempts, nil)
+}
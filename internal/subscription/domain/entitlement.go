@@ -21,3 +21,12 @@ type SubscriptionEntitlement struct {
 }
 
 func (SubscriptionEntitlement) TableName() string { return "subscription_entitlements" }
+
+// EntitlementTrigger identifies the subscription event that opened or closed
+// an entitlement window.
+type EntitlementTrigger string
+
+const (
+	EntitlementTriggerCreate       EntitlementTrigger = "create"
+	EntitlementTriggerReplaceItems EntitlementTrigger = "replace_items"
+)
@@ -0,0 +1,55 @@
+package domain
+
+import "strings"
+
+// ListFields maps the columns a subscription list can be narrowed to with
+// the fields parameter to their keys in the response.
+var ListFields = map[string]string{
+	"id":                          "ID",
+	"customer_id":                 "CustomerID",
+	"status":                      "Status",
+	"collection_mode":             "CollectionMode",
+	"start_at":                    "StartAt",
+	"end_at":                      "EndAt",
+	"trial_starts_at":             "TrialStartsAt",
+	"trial_ends_at":               "TrialEndsAt",
+	"cancel_at":                   "CancelAt",
+	"cancel_at_period_end":        "CancelAtPeriodEnd",
+	"canceled_at":                 "CanceledAt",
+	"activated_at":                "ActivatedAt",
+	"paused_at":                   "PausedAt",
+	"resumed_at":                  "ResumedAt",
+	"ended_at":                    "EndedAt",
+	"past_due_at":                 "PastDueAt",
+	"billing_anchor_day":          "BillingAnchorDay",
+	"billing_cycle_type":          "BillingCycleType",
+	"default_payment_term_days":   "DefaultPaymentTermDays",
+	"default_currency":            "DefaultCurrency",
+	"minimum_commitment_cents":    "MinimumCommitmentCents",
+	"spend_alert_threshold_cents": "SpendAlertThresholdCents",
+	"metadata":                    "Metadata",
+	"created_at":                  "CreatedAt",
+	"updated_at":                  "UpdatedAt",
+}
+
+// ListColumns returns the columns to load for the requested fields. The id
+// and created_at columns are always loaded since list pages are cursored on
+// them; nil means every column.
+func ListColumns(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	columns := []string{"id", "created_at"}
+	seen := map[string]bool{"id": true, "created_at": true}
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if _, ok := ListFields[field]; !ok {
+			return nil, ErrInvalidFields
+		}
+		if !seen[field] {
+			seen[field] = true
+			columns = append(columns, field)
+		}
+	}
+	return columns, nil
+}
@@ -2,9 +2,12 @@
 package domain
 
 import (
+	"math"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
 	"gorm.io/datatypes"
 )
 
@@ -17,6 +20,33 @@ const (
 	SubscriptionStatusPaused   SubscriptionStatus = "PAUSED"
 	SubscriptionStatusCanceled SubscriptionStatus = "CANCELED"
 	SubscriptionStatusEnded    SubscriptionStatus = "ENDED"
+	// SubscriptionStatusPastDue marks an active subscription whose
+	// auto-charge failed. It keeps billing through the organization's grace
+	// period and is canceled if still unpaid when the grace period ends.
+	SubscriptionStatusPastDue SubscriptionStatus = "PAST_DUE"
+)
+
+// Billable reports whether subscriptions in the status keep opening and
+// billing cycles.
+func (s SubscriptionStatus) Billable() bool {
+	return s == SubscriptionStatusActive || s == SubscriptionStatusPastDue
+}
+
+// Usage behaviors control when a subscription item is billed.
+const (
+	// UsageBehaviorArrears bills the item at the end of the billing cycle.
+	UsageBehaviorArrears = "arrears"
+	// UsageBehaviorAdvance bills a flat or licensed item at the start of the billing cycle.
+	UsageBehaviorAdvance = "advance"
+)
+
+// Proration behaviors control whether item replacements mid-cycle are prorated.
+const (
+	// ProrationBehaviorNone leaves mid-cycle item changes to the regular rating run.
+	ProrationBehaviorNone = "none"
+	// ProrationBehaviorCreateProrations charges or credits a flat item for the
+	// part of the cycle it was added or removed in.
+	ProrationBehaviorCreateProrations = "create_prorations"
 )
 
 type SubscriptionCollectionMode string
@@ -44,16 +74,21 @@ type Subscription struct {
 	PausedAt               *time.Time                 `gorm:"column:paused_at"`
 	ResumedAt              *time.Time                 `gorm:"column:resumed_at"`
 	EndedAt                *time.Time                 `gorm:"column:ended_at"`
+	PastDueAt              *time.Time                 `gorm:"column:past_due_at"`
 	PlanChangedAt          *time.Time                 `gorm:"column:plan_changed_at"`
 	BillingAnchorDay       *int16                     `gorm:"type:smallint"`
 	BillingCycleType       string                     `gorm:"type:text;not null"`
 	DefaultPaymentTermDays *int                       `gorm:""`
 	DefaultCurrency        *string                    `gorm:"type:text"`
 	DefaultTaxBehavior     *string                    `gorm:"type:text"`
-	IdempotencyKey         *string                    `gorm:"column:idempotency_key"`
-	Metadata               datatypes.JSONMap          `gorm:"type:jsonb"`
-	CreatedAt              time.Time                  `gorm:"not null;default:CURRENT_TIMESTAMP"`
-	UpdatedAt              time.Time                  `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	MinimumCommitmentCents *int64                     `gorm:"column:minimum_commitment_cents"`
+	// SpendAlertThresholdCents is the cycle-to-date rated amount at which the
+	// customer is warned about the upcoming bill, once per cycle.
+	SpendAlertThresholdCents *int64            `gorm:"column:spend_alert_threshold_cents"`
+	IdempotencyKey           *string           `gorm:"column:idempotency_key"`
+	Metadata                 datatypes.JSONMap `gorm:"type:jsonb"`
+	CreatedAt                time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt                time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP"`
 }
 
 // TableName sets the database table name.
@@ -82,11 +117,16 @@ type SubscriptionItem struct {
 	PriceCode         *string           `gorm:"type:text"`
 	MeterID           *snowflake.ID     `gorm:"index"`
 	MeterCode         *string           `gorm:"type:text"`
-	Quantity          int8              `gorm:"column:quantity"`
+	Quantity          int32             `gorm:"column:quantity"`
 	BillingMode       string            `gorm:"type:text;not null"`
 	UsageBehavior     *string           `gorm:"type:text"`
 	BillingThreshold  *float64          `gorm:""`
 	ProrationBehavior *string           `gorm:"type:text"`
+	DimensionKey      *string           `gorm:"type:text"`
+	DimensionValue    *string           `gorm:"type:text"`
+	IncludedQuantity  *float64          `gorm:""`
+	SeatMeterID       *snowflake.ID     `gorm:""`
+	OveragePriceID    *snowflake.ID     `gorm:""`
 	NextPeriodStart   *time.Time        `gorm:""`
 	NextPeriodEnd     *time.Time        `gorm:""`
 	Metadata          datatypes.JSONMap `gorm:"type:jsonb"`
@@ -96,3 +136,59 @@ type SubscriptionItem struct {
 
 // TableName sets the database table name.
 func (SubscriptionItem) TableName() string { return "subscription_items" }
+
+// SeatOverage returns how many of the used seats exceed the licensed
+// quantity. Partial seats count as whole ones.
+func SeatOverage(used float64, licensed int32) float64 {
+	return math.Max(0, math.Ceil(used)-float64(licensed))
+}
+
+// SubscriptionEvent records one status change of a subscription and who
+// triggered it. Rows are only ever appended.
+type SubscriptionEvent struct {
+	ID             snowflake.ID       `gorm:"primaryKey"`
+	OrgID          snowflake.ID       `gorm:"not null;index"`
+	SubscriptionID snowflake.ID       `gorm:"not null;index"`
+	FromStatus     SubscriptionStatus `gorm:"type:text;not null"`
+	ToStatus       SubscriptionStatus `gorm:"type:text;not null"`
+	Reason         *string            `gorm:"type:text"`
+	ActorType      string             `gorm:"type:text;not null"`
+	ActorID        *string            `gorm:"type:text"`
+	CreatedAt      time.Time          `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName sets the database table name.
+func (SubscriptionEvent) TableName() string { return "subscription_events" }
+
+// SpendAlert records a spend alert threshold a billing cycle crossed, so
+// the alert is sent once per cycle and threshold.
+type SpendAlert struct {
+	ID             snowflake.ID `gorm:"primaryKey"`
+	OrgID          snowflake.ID `gorm:"not null;index"`
+	SubscriptionID snowflake.ID `gorm:"not null;index"`
+	BillingCycleID snowflake.ID `gorm:"not null;uniqueIndex:ux_subscription_spend_alerts_cycle_threshold,priority:1"`
+	ThresholdCents int64        `gorm:"not null;uniqueIndex:ux_subscription_spend_alerts_cycle_threshold,priority:2"`
+	AmountCents    int64        `gorm:"not null"`
+	Currency       string       `gorm:"type:text;not null"`
+	CreatedAt      time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName sets the database table name.
+func (SpendAlert) TableName() string { return "subscription_spend_alerts" }
+
+// BillingCycleTypeForInterval maps a price billing interval to the
+// subscription billing cycle type that bills it.
+func BillingCycleTypeForInterval(interval pricedomain.BillingInterval) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(string(interval))) {
+	case string(pricedomain.Day):
+		return "daily", nil
+	case string(pricedomain.Week):
+		return "weekly", nil
+	case string(pricedomain.Month):
+		return "monthly", nil
+	case string(pricedomain.Year):
+		return "yearly", nil
+	default:
+		return "", ErrInvalidBillingCycleType
+	}
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
@@ -16,6 +17,8 @@ type ListSubscriptionRequest struct {
 	PageSize    int32
 	CreatedFrom *time.Time
 	CreatedTo   *time.Time
+	// Fields narrows the loaded columns to those listed in ListFields.
+	Fields []string
 }
 
 type ListSubscriptionResponse struct {
@@ -48,19 +51,170 @@ type ListEntitlementsResponse struct {
 	Entitlements []EntitlementResponse `json:"entitlements"`
 }
 
+// CheckEntitlementRequest asks whether a customer is entitled to a feature
+// at At, or now when At is nil.
+type CheckEntitlementRequest struct {
+	CustomerID  string
+	FeatureCode string
+	At          *time.Time
+}
+
+// EntitlementQuota is the included allowance of a metered item bound to the
+// feature's meter and how much of it the current period has used.
+type EntitlementQuota struct {
+	SubscriptionItemID snowflake.ID `json:"subscription_item_id"`
+	DimensionKey       *string      `json:"dimension_key,omitempty"`
+	DimensionValue     *string      `json:"dimension_value,omitempty"`
+	PeriodStart        time.Time    `json:"period_start"`
+	IncludedQuantity   float64      `json:"included_quantity"`
+	UsedQuantity       float64      `json:"used_quantity"`
+	RemainingQuantity  float64      `json:"remaining_quantity"`
+}
+
+type EntitlementCheckResponse struct {
+	CustomerID         snowflake.ID        `json:"customer_id"`
+	FeatureCode        string              `json:"feature_code"`
+	Entitled           bool                `json:"entitled"`
+	At                 time.Time           `json:"at"`
+	SubscriptionID     *snowflake.ID       `json:"subscription_id,omitempty"`
+	SubscriptionStatus *SubscriptionStatus `json:"subscription_status,omitempty"`
+	FeatureType        string              `json:"feature_type,omitempty"`
+	MeterID            *snowflake.ID       `json:"meter_id,omitempty"`
+	Quotas             []EntitlementQuota  `json:"quotas,omitempty"`
+}
+
+// SeatUsage compares the active seats counted by a licensed item's seat
+// meter in the current period with the item's licensed quantity.
+type SeatUsage struct {
+	SubscriptionItemID snowflake.ID  `json:"subscription_item_id"`
+	SeatMeterID        snowflake.ID  `json:"seat_meter_id"`
+	OveragePriceID     *snowflake.ID `json:"overage_price_id,omitempty"`
+	PeriodStart        time.Time     `json:"period_start"`
+	LicensedQuantity   int32         `json:"licensed_quantity"`
+	UsedQuantity       float64       `json:"used_quantity"`
+	OverageQuantity    float64       `json:"overage_quantity"`
+	Overage            bool          `json:"overage"`
+}
+
+type SeatUsageResponse struct {
+	SubscriptionID snowflake.ID `json:"subscription_id"`
+	At             time.Time    `json:"at"`
+	Items          []SeatUsage  `json:"items"`
+}
+
+type GetEntitlementHistoryRequest struct {
+	SubscriptionID string
+	FeatureCode    string
+	PageToken      string
+	PageSize       int32
+}
+
+// EntitlementHistoryEntry is one effective window of a feature entitlement
+// together with the events that opened and closed it.
+type EntitlementHistoryEntry struct {
+	EntitlementID  snowflake.ID        `json:"entitlement_id"`
+	SubscriptionID snowflake.ID        `json:"subscription_id"`
+	ProductID      snowflake.ID        `json:"product_id"`
+	FeatureCode    string              `json:"feature_code"`
+	FeatureName    string              `json:"feature_name"`
+	FeatureType    string              `json:"feature_type"`
+	MeterID        *snowflake.ID       `json:"meter_id,omitempty"`
+	EffectiveFrom  time.Time           `json:"effective_from"`
+	EffectiveTo    *time.Time          `json:"effective_to,omitempty"`
+	Trigger        EntitlementTrigger  `json:"trigger"`
+	AuditLogID     *snowflake.ID       `json:"audit_log_id,omitempty"`
+	ClosedBy       *EntitlementTrigger `json:"closed_by,omitempty"`
+}
+
+type EntitlementHistoryResponse struct {
+	pagination.PageInfo
+	History []EntitlementHistoryEntry `json:"history"`
+}
+
+type ListSubscriptionEventsRequest struct {
+	SubscriptionID string
+	PageToken      string
+	PageSize       int32
+}
+
+// SubscriptionEventResponse is one status change in a subscription's
+// lifecycle timeline.
+type SubscriptionEventResponse struct {
+	ID             snowflake.ID       `json:"id"`
+	SubscriptionID snowflake.ID       `json:"subscription_id"`
+	FromStatus     SubscriptionStatus `json:"from_status"`
+	ToStatus       SubscriptionStatus `json:"to_status"`
+	Reason         *string            `json:"reason,omitempty"`
+	ActorType      string             `json:"actor_type"`
+	ActorID        *string            `json:"actor_id,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type ListSubscriptionEventsResponse struct {
+	pagination.PageInfo
+	Events []SubscriptionEventResponse `json:"events"`
+}
+
+type ListBillingCyclesRequest struct {
+	SubscriptionID string
+	// Status optionally restricts the list to OPEN, CLOSING or CLOSED cycles.
+	Status    string
+	PageToken string
+	PageSize  int32
+}
+
+// BillingCycleSummary is one billing cycle of a subscription with the total
+// of the rating results recorded against it.
+type BillingCycleSummary struct {
+	ID          snowflake.ID `json:"id"`
+	PeriodStart time.Time    `json:"period_start"`
+	PeriodEnd   time.Time    `json:"period_end"`
+	Status      string       `json:"status"`
+	RatedAmount int64        `json:"rated_amount"`
+}
+
+type ListBillingCyclesResponse struct {
+	pagination.PageInfo
+	BillingCycles []BillingCycleSummary `json:"billing_cycles"`
+}
+
+// UpdateMetadataRequest merges Metadata into a subscription's metadata. A
+// nil value removes its key.
+type UpdateMetadataRequest struct {
+	SubscriptionID string
+	Metadata       map[string]any
+}
+
 type CreateSubscriptionItemRequest struct {
-	PriceID  string `json:"price_id"`
-	Quantity int8   `json:"quantity,omitempty"`
+	PriceID           string `json:"price_id"`
+	Quantity          int32  `json:"quantity,omitempty"`
+	UsageBehavior     string `json:"usage_behavior,omitempty"`
+	ProrationBehavior string `json:"proration_behavior,omitempty"`
+	// DimensionKey and DimensionValue restrict a metered item to usage events
+	// tagged with that dimension, e.g. region=us.
+	DimensionKey   string `json:"dimension_key,omitempty"`
+	DimensionValue string `json:"dimension_value,omitempty"`
+	// IncludedQuantity is the allowance of a metered item rated at zero
+	// before its price applies, e.g. 1000 included API calls per cycle.
+	IncludedQuantity *float64 `json:"included_quantity,omitempty"`
+	// SeatMeterID counts the active seats of a licensed item, e.g. distinct
+	// active users. Seats above the item's quantity are overage, billed at
+	// the per-unit OveragePriceID when one is set.
+	SeatMeterID    string `json:"seat_meter_id,omitempty"`
+	OveragePriceID string `json:"overage_price_id,omitempty"`
 }
 
 type CreateSubscriptionRequest struct {
-	CustomerID       string                          `json:"customer_id"`
-	CollectionMode   SubscriptionCollectionMode      `json:"collection_mode"`
-	BillingCycleType string                          `json:"billing_cycle_type"`
-	Items            []CreateSubscriptionItemRequest `json:"items"`
-	TrialDays        *int                            `json:"trial_days,omitempty"`
-	Metadata         map[string]any                  `json:"metadata,omitempty"`
-	IdempotencyKey   string                          `json:"-"`
+	CustomerID               string                          `json:"customer_id"`
+	CollectionMode           SubscriptionCollectionMode      `json:"collection_mode"`
+	BillingCycleType         string                          `json:"billing_cycle_type"`
+	Items                    []CreateSubscriptionItemRequest `json:"items"`
+	TrialDays                *int                            `json:"trial_days,omitempty"`
+	MinimumCommitmentCents   *int64                          `json:"minimum_commitment_cents,omitempty"`
+	SpendAlertThresholdCents *int64                          `json:"spend_alert_threshold_cents,omitempty"`
+	StartAt                  *time.Time                      `json:"start_at,omitempty"`
+	Metadata                 map[string]any                  `json:"metadata,omitempty"`
+	IdempotencyKey           string                          `json:"-"`
 }
 
 type ReplaceSubscriptionItemsRequest struct {
@@ -68,6 +222,27 @@ type ReplaceSubscriptionItemsRequest struct {
 	Items          []CreateSubscriptionItemRequest `json:"items"`
 }
 
+type ListSubscriptionItemsRequest struct {
+	SubscriptionID string
+	PageToken      string
+	PageSize       int32
+}
+
+// SubscriptionItemSummary is a subscription item with the unit amount its
+// price currently bills in the subscription currency. UnitAmountCents is nil
+// when the price has no amount in effect in that currency.
+type SubscriptionItemSummary struct {
+	CreateSubscriptionItemResponse
+	Currency        string    `json:"currency"`
+	UnitAmountCents *int64    `json:"unit_amount_cents,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type ListSubscriptionItemsResponse struct {
+	pagination.PageInfo
+	Items []SubscriptionItemSummary `json:"items"`
+}
+
 type GetActiveByCustomerIDRequest struct {
 	CustomerID string
 }
@@ -80,6 +255,20 @@ type GetSubscriptionItemRequest struct {
 
 type TransitionReason string
 
+// Cancellation timings accepted by CancelSubscription.
+const (
+	CancelAtImmediate = "immediate"
+	CancelAtPeriodEnd = "period_end"
+)
+
+// CancelSubscriptionRequest cancels a subscription now or schedules the
+// cancellation for the end of its current billing cycle.
+type CancelSubscriptionRequest struct {
+	SubscriptionID string
+	// CancelAt is CancelAtImmediate (the default) or CancelAtPeriodEnd.
+	CancelAt string
+}
+
 //go:generate mockgen -source=service.go -destination=./mocks/mock_service.go -package=mocks
 type Service interface {
 	List(context.Context, ListSubscriptionRequest) (ListSubscriptionResponse, error)
@@ -89,14 +278,78 @@ type Service interface {
 	GetByID(context.Context, string) (Subscription, error)
 	GetActiveByCustomerID(context.Context, GetActiveByCustomerIDRequest) (Subscription, error)
 	GetSubscriptionItem(context.Context, GetSubscriptionItemRequest) (SubscriptionItem, error)
+	ListItems(context.Context, ListSubscriptionItemsRequest) (ListSubscriptionItemsResponse, error)
 	TransitionSubscription(ctx context.Context, subscriptionID string, targetStatus SubscriptionStatus, reason TransitionReason) error
+	CancelSubscription(ctx context.Context, req CancelSubscriptionRequest) error
 	ValidateUsageEntitlement(ctx context.Context, subscriptionID, meterID snowflake.ID, at time.Time) error
 	ChangePlan(ctx context.Context, req ChangePlanRequest) error
+	PreviewProration(ctx context.Context, req PreviewProrationRequest) (PreviewProrationResponse, error)
+	GetEntitlementHistory(context.Context, GetEntitlementHistoryRequest) (EntitlementHistoryResponse, error)
+	ListBillingCycles(context.Context, ListBillingCyclesRequest) (ListBillingCyclesResponse, error)
+	ListEvents(context.Context, ListSubscriptionEventsRequest) (ListSubscriptionEventsResponse, error)
+	UpdateMetadata(context.Context, UpdateMetadataRequest) (Subscription, error)
+	CheckEntitlement(context.Context, CheckEntitlementRequest) (EntitlementCheckResponse, error)
+	GetSeatUsage(ctx context.Context, subscriptionID string) (SeatUsageResponse, error)
+	UpdateItemQuantity(context.Context, UpdateItemQuantityRequest) (UpdateItemQuantityResponse, error)
 }
 
 type ChangePlanRequest struct {
 	SubscriptionID string
-	NewProductID   string
+	// NewPriceID is the target price. When empty, the default price of
+	// NewProductID is used.
+	NewPriceID        string
+	NewProductID      string
+	ProrationBehavior string
+}
+
+// PreviewProrationRequest describes a pending change to preview. Items
+// previews a ReplaceItems call; otherwise NewPriceID or NewProductID
+// previews a ChangePlan call.
+type PreviewProrationRequest struct {
+	SubscriptionID    string
+	Items             []CreateSubscriptionItemRequest
+	NewPriceID        string
+	NewProductID      string
+	ProrationBehavior string
+}
+
+// ProrationLine is a credit (negative amount) or charge the change would add
+// to the current billing cycle.
+type ProrationLine struct {
+	PriceID     string    `json:"price_id"`
+	Quantity    float64   `json:"quantity"`
+	UnitPrice   int64     `json:"unit_price"`
+	Amount      int64     `json:"amount"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+type PreviewProrationResponse struct {
+	SubscriptionID string          `json:"subscription_id"`
+	BillingCycleID *string         `json:"billing_cycle_id,omitempty"`
+	Currency       string          `json:"currency"`
+	Credits        []ProrationLine `json:"credits"`
+	Charges        []ProrationLine `json:"charges"`
+	NetAmount      int64           `json:"net_amount"`
+	ProratedAt     time.Time       `json:"prorated_at"`
+}
+
+// UpdateItemQuantityRequest changes the seat count of a licensed item.
+type UpdateItemQuantityRequest struct {
+	SubscriptionID string
+	ItemID         string
+	Quantity       int32
+}
+
+// UpdateItemQuantityResponse carries the updated item and the prorations the
+// seat change added to the open billing cycle.
+type UpdateItemQuantityResponse struct {
+	SubscriptionID   string                         `json:"subscription_id"`
+	Item             CreateSubscriptionItemResponse `json:"item"`
+	PreviousQuantity int32                          `json:"previous_quantity"`
+	BillingCycleID   *string                        `json:"billing_cycle_id,omitempty"`
+	Prorations       []ProrationLine                `json:"prorations"`
+	NetAmount        int64                          `json:"net_amount"`
 }
 
 type CreateSubscriptionItemResponse struct {
@@ -105,11 +358,16 @@ type CreateSubscriptionItemResponse struct {
 	PriceCode         *string  `json:"price_code,omitempty"`
 	MeterID           *string  `json:"meter_id,omitempty"`
 	MeterCode         *string  `json:"meter_code,omitempty"`
-	Quantity          int8     `json:"quantity"`
+	Quantity          int32    `json:"quantity"`
 	BillingMode       string   `json:"billing_mode"`
 	UsageBehavior     *string  `json:"usage_behavior,omitempty"`
 	BillingThreshold  *float64 `json:"billing_threshold,omitempty"`
 	ProrationBehavior *string  `json:"proration_behavior,omitempty"`
+	DimensionKey      *string  `json:"dimension_key,omitempty"`
+	DimensionValue    *string  `json:"dimension_value,omitempty"`
+	IncludedQuantity  *float64 `json:"included_quantity,omitempty"`
+	SeatMeterID       *string  `json:"seat_meter_id,omitempty"`
+	OveragePriceID    *string  `json:"overage_price_id,omitempty"`
 }
 
 type CreateSubscriptionResponse struct {
@@ -131,9 +389,12 @@ var (
 	ErrInvalidMeterID            = errors.New("invalid_meter_id")
 	ErrUnsupportedPricingModel   = errors.New("unsupported_pricing_model")
 	ErrInvalidMeterCode          = errors.New("invalid_meter_code")
+	ErrMeterArchived             = errors.New("meter_archived")
 	ErrInvalidStatus             = errors.New("invalid_status")
 	ErrInvalidTargetStatus       = errors.New("invalid_target_status")
 	ErrInvalidTransition         = errors.New("invalid_transition")
+	ErrInvalidCancelAt           = errors.New("invalid_cancel_at")
+	ErrNoOpenBillingCycle        = errors.New("no_open_billing_cycle")
 	ErrMissingSubscriptionItems  = errors.New("missing_subscription_items")
 	ErrMissingPricing            = errors.New("missing_pricing")
 	ErrMissingCustomer           = errors.New("missing_customer")
@@ -144,9 +405,19 @@ var (
 	ErrInvalidBillingCycleType   = errors.New("invalid_billing_cycle_type")
 	ErrInvalidCurrency           = errors.New("invalid_currency")
 	ErrInvalidStartAt            = errors.New("invalid_start_at")
+	ErrStartAtNotReached         = errors.New("start_at_not_reached")
 	ErrInvalidPeriod             = errors.New("invalid_period")
 	ErrInvalidItems              = errors.New("invalid_items")
 	ErrInvalidQuantity           = errors.New("invalid_quantity")
+	ErrInvalidMinimumCommitment  = errors.New("invalid_minimum_commitment")
+	ErrInvalidSpendThreshold     = errors.New("invalid_spend_alert_threshold")
+	ErrInvalidUsageBehavior      = errors.New("invalid_usage_behavior")
+	ErrInvalidProrationBehavior  = errors.New("invalid_proration_behavior")
+	ErrInvalidDimensionFilter    = errors.New("invalid_dimension_filter")
+	ErrInvalidIncludedQuantity   = errors.New("invalid_included_quantity")
+	ErrInvalidFields             = errors.New("invalid_fields")
+	ErrInvalidSeatMeter          = errors.New("invalid_seat_meter")
+	ErrInvalidOveragePrice       = errors.New("invalid_overage_price")
 	ErrInvalidPrice              = errors.New("invalid_price")
 	ErrInvalidProduct            = errors.New("invalid_product")
 	ErrMultipleFlatPrices        = errors.New("multiple_flat_prices_not_allowed")
@@ -155,4 +426,46 @@ var (
 	ErrFeatureNotEntitled        = errors.New("feature_not_entitled")
 	ErrInvalidSubscriptionStatus = errors.New("invalid_subscription_status")
 	ErrMissingPaymentMethod      = errors.New("missing_payment_method")
+	ErrInvalidFeatureCode        = errors.New("invalid_feature_code")
+	ErrInvalidMetadata           = errors.New("invalid_metadata")
+	ErrConcurrentUpdate          = errors.New("concurrent_update")
+	ErrQuantityChangeNotAllowed  = errors.New("quantity_change_not_allowed")
 )
+
+// CurrencyNotPricedError reports that a price has no amount in the
+// subscription's currency and lists the currencies it is priced in.
+type CurrencyNotPricedError struct {
+	PriceID             string
+	Currency            string
+	AvailableCurrencies []string
+}
+
+func (e *CurrencyNotPricedError) Error() string {
+	return fmt.Sprintf("price %s has no amount in %s", e.PriceID, e.Currency)
+}
+
+// Unwrap keeps errors.Is(err, ErrMissingPricing) true for existing callers.
+func (e *CurrencyNotPricedError) Unwrap() error {
+	return ErrMissingPricing
+}
+
+// ItemError points a subscription item validation error at the requested
+// item that caused it.
+type ItemError struct {
+	Index   int
+	PriceID string
+	Err     error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Field(), e.PriceID, e.Err)
+}
+
+// Field names the offending request field, e.g. items[2].price_id.
+func (e *ItemError) Field() string {
+	return fmt.Sprintf("items[%d].price_id", e.Index)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
@@ -60,9 +60,10 @@ func (r *repo) InsertItems(ctx context.Context, db *gorm.DB, items []subscriptio
 		if err := db.WithContext(ctx).Exec(
 			`INSERT INTO subscription_items (
 				id, org_id, subscription_id, price_id, price_code, meter_id, meter_code, quantity,
-				billing_mode, usage_behavior, billing_threshold, proration_behavior, next_period_start,
-				next_period_end, metadata, created_at, updated_at
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				billing_mode, usage_behavior, billing_threshold, proration_behavior, dimension_key,
+				dimension_value, included_quantity, seat_meter_id, overage_price_id,
+				next_period_start, next_period_end, metadata, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			item.ID,
 			item.OrgID,
 			item.SubscriptionID,
@@ -75,6 +76,11 @@ func (r *repo) InsertItems(ctx context.Context, db *gorm.DB, items []subscriptio
 			item.UsageBehavior,
 			item.BillingThreshold,
 			item.ProrationBehavior,
+			item.DimensionKey,
+			item.DimensionValue,
+			item.IncludedQuantity,
+			item.SeatMeterID,
+			item.OveragePriceID,
 			item.NextPeriodStart,
 			item.NextPeriodEnd,
 			item.Metadata,
@@ -133,7 +139,7 @@ func (r *repo) FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID
 	var subscription subscriptiondomain.Subscription
 	err := db.WithContext(ctx).Raw(
 		`SELECT id, org_id, customer_id, status, collection_mode, start_at, end_at, cancel_at,
-		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at,
+		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at, past_due_at,
 		 billing_anchor_day, billing_cycle_type, default_payment_term_days, default_currency,
 		 default_tax_behavior, idempotency_key, metadata, created_at, updated_at
 		 FROM subscriptions WHERE org_id = ? AND id = ?`,
@@ -151,12 +157,16 @@ func (r *repo) FindByID(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID
 
 func (r *repo) FindByIDForUpdate(ctx context.Context, db *gorm.DB, orgID, id snowflake.ID) (*subscriptiondomain.Subscription, error) {
 	var subscription subscriptiondomain.Subscription
-	err := db.WithContext(ctx).Raw(
-		`SELECT id, org_id, customer_id, status, collection_mode, start_at, end_at, cancel_at,
-		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at,
+	query := `SELECT id, org_id, customer_id, status, collection_mode, start_at, end_at, cancel_at,
+		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at, past_due_at,
 		 billing_anchor_day, billing_cycle_type, default_payment_term_days, default_currency,
 		 default_tax_behavior, idempotency_key, metadata, created_at, updated_at
-		 FROM subscriptions WHERE org_id = ? AND id = ? FOR UPDATE`,
+		 FROM subscriptions WHERE org_id = ? AND id = ?`
+	if db.Dialector.Name() != "sqlite" {
+		query += " FOR UPDATE"
+	}
+	err := db.WithContext(ctx).Raw(
+		query,
 		orgID,
 		id,
 	).Scan(&subscription).Error
@@ -173,7 +183,7 @@ func (r *repo) FindByIdempotencyKey(ctx context.Context, db *gorm.DB, orgID snow
 	var subscription subscriptiondomain.Subscription
 	err := db.WithContext(ctx).Raw(
 		`SELECT id, org_id, customer_id, status, collection_mode, start_at, end_at, cancel_at,
-		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at,
+		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at, past_due_at,
 		 billing_anchor_day, billing_cycle_type, default_payment_term_days, default_currency,
 		 default_tax_behavior, idempotency_key, metadata, created_at, updated_at
 		 FROM subscriptions WHERE org_id = ? AND idempotency_key = ? LIMIT 1`,
@@ -193,8 +203,9 @@ func (r *repo) ListItemsBySubscriptionID(ctx context.Context, db *gorm.DB, orgID
 	var items []subscriptiondomain.SubscriptionItem
 	err := db.WithContext(ctx).Raw(
 		`SELECT id, org_id, subscription_id, price_id, price_code, meter_id, meter_code, quantity,
-		 billing_mode, usage_behavior, billing_threshold, proration_behavior, next_period_start,
-		 next_period_end, metadata, created_at, updated_at
+		 billing_mode, usage_behavior, billing_threshold, proration_behavior, dimension_key,
+		 dimension_value, included_quantity, seat_meter_id, overage_price_id,
+		 next_period_start, next_period_end, metadata, created_at, updated_at
 		 FROM subscription_items WHERE org_id = ? AND subscription_id = ? ORDER BY created_at ASC`,
 		orgID,
 		subscriptionID,
@@ -233,7 +244,7 @@ func (r *repo) List(ctx context.Context, db *gorm.DB, orgID snowflake.ID) ([]sub
 	var subscriptions []subscriptiondomain.Subscription
 	err := db.WithContext(ctx).Raw(
 		`SELECT id, org_id, customer_id, status, collection_mode, start_at, end_at, cancel_at,
-		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at,
+		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at, past_due_at,
 		 billing_anchor_day, billing_cycle_type, default_payment_term_days, default_currency,
 		 default_tax_behavior, idempotency_key, metadata, created_at, updated_at
 		 FROM subscriptions WHERE org_id = ? ORDER BY created_at ASC`,
@@ -249,7 +260,7 @@ func (r *repo) FindActiveByCustomerID(ctx context.Context, db *gorm.DB, orgID, c
 	var subscription subscriptiondomain.Subscription
 	err := db.WithContext(ctx).Raw(
 		`SELECT id, org_id, customer_id, status, collection_mode, start_at, end_at, cancel_at,
-		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at,
+		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at, past_due_at,
 		 billing_anchor_day, billing_cycle_type, default_payment_term_days, default_currency,
 		 default_tax_behavior, metadata, created_at, updated_at
 		 FROM subscriptions
@@ -273,7 +284,7 @@ func (r *repo) FindActiveByCustomerIDAt(ctx context.Context, db *gorm.DB, orgID,
 	var subscription subscriptiondomain.Subscription
 	err := db.WithContext(ctx).Raw(
 		`SELECT id, org_id, customer_id, status, collection_mode, start_at, end_at, cancel_at,
-		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at,
+		 cancel_at_period_end, canceled_at, activated_at, paused_at, resumed_at, ended_at, past_due_at,
 		 billing_anchor_day, billing_cycle_type, default_payment_term_days, default_currency,
 		 default_tax_behavior, metadata, created_at, updated_at
 		 FROM subscriptions
@@ -311,8 +322,9 @@ func (r *repo) FindSubscriptionItemByMeterCode(ctx context.Context, db *gorm.DB,
 	var item subscriptiondomain.SubscriptionItem
 	err := db.WithContext(ctx).Raw(
 		`SELECT id, org_id, subscription_id, price_id, price_code, meter_id, meter_code, quantity,
-		 billing_mode, usage_behavior, billing_threshold, proration_behavior, next_period_start,
-		 next_period_end, metadata, created_at, updated_at
+		 billing_mode, usage_behavior, billing_threshold, proration_behavior, dimension_key,
+		 dimension_value, included_quantity, seat_meter_id, overage_price_id,
+		 next_period_start, next_period_end, metadata, created_at, updated_at
 		 FROM subscription_items
 		 WHERE org_id = ? AND subscription_id = ? AND meter_code = ?
 		 LIMIT 1`,
@@ -333,8 +345,9 @@ func (r *repo) FindSubscriptionItemByMeterID(ctx context.Context, db *gorm.DB, o
 	var item subscriptiondomain.SubscriptionItem
 	err := db.WithContext(ctx).Raw(
 		`SELECT id, org_id, subscription_id, price_id, price_code, meter_id, meter_code, quantity,
-		 billing_mode, usage_behavior, billing_threshold, proration_behavior, next_period_start,
-		 next_period_end, metadata, created_at, updated_at
+		 billing_mode, usage_behavior, billing_threshold, proration_behavior, dimension_key,
+		 dimension_value, included_quantity, seat_meter_id, overage_price_id,
+		 next_period_start, next_period_end, metadata, created_at, updated_at
 		 FROM subscription_items
 		 WHERE org_id = ? AND subscription_id = ? AND meter_id = ?
 		 LIMIT 1`,
@@ -355,8 +368,9 @@ func (r *repo) FindSubscriptionItemByMeterIDAt(ctx context.Context, db *gorm.DB,
 	var item subscriptiondomain.SubscriptionItem
 	err := db.WithContext(ctx).Raw(
 		`SELECT id, org_id, subscription_id, price_id, price_code, meter_id, meter_code, quantity,
-		 billing_mode, usage_behavior, billing_threshold, proration_behavior, next_period_start,
-		 next_period_end, metadata, created_at, updated_at
+		 billing_mode, usage_behavior, billing_threshold, proration_behavior, dimension_key,
+		 dimension_value, included_quantity, seat_meter_id, overage_price_id,
+		 next_period_start, next_period_end, metadata, created_at, updated_at
 		 FROM subscription_items
 		 WHERE org_id = ? AND subscription_id = ? AND meter_id = ?
 		   AND (next_period_start IS NULL OR next_period_start <= ?)
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
+	"go.uber.org/zap"
+)
+
+// ListBillingCycles returns the billing cycles of a subscription, newest
+// first, each with the sum of its rating results.
+func (s *Service) ListBillingCycles(ctx context.Context, req subscriptiondomain.ListBillingCyclesRequest) (subscriptiondomain.ListBillingCyclesResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return subscriptiondomain.ListBillingCyclesResponse{}, subscriptiondomain.ErrInvalidOrganization
+	}
+
+	subscriptionID, err := s.parseID(req.SubscriptionID, subscriptiondomain.ErrInvalidSubscription)
+	if err != nil {
+		return subscriptiondomain.ListBillingCyclesResponse{}, err
+	}
+
+	status, err := parseBillingCycleStatusFilter(req.Status)
+	if err != nil {
+		return subscriptiondomain.ListBillingCyclesResponse{}, err
+	}
+
+	subscription, err := s.repo.FindByID(ctx, s.db, orgID, subscriptionID)
+	if err != nil {
+		return subscriptiondomain.ListBillingCyclesResponse{}, err
+	}
+	if subscription == nil {
+		return subscriptiondomain.ListBillingCyclesResponse{}, subscriptiondomain.ErrSubscriptionNotFound
+	}
+
+	pageSize := pagination.ClampPageSize(req.PageSize)
+
+	stmt := s.db.WithContext(ctx).Table("billing_cycles bc").
+		Select(`bc.id, bc.period_start, bc.period_end, bc.status, COALESCE(SUM(rr.amount), 0) AS rated_amount`).
+		Joins("LEFT JOIN rating_results rr ON rr.org_id = bc.org_id AND rr.billing_cycle_id = bc.id").
+		Where("bc.org_id = ? AND bc.subscription_id = ?", orgID, subscriptionID)
+	if status != nil {
+		stmt = stmt.Where("bc.status = ?", *status)
+	}
+
+	// Cycles are listed newest first, so the cursor moves backwards on
+	// (period_start, id).
+	if req.PageToken != "" {
+		cursor, err := pagination.DecodeCursor(req.PageToken)
+		if err == nil {
+			start, startErr := time.Parse(time.RFC3339Nano, cursor.CreatedAt)
+			id, idErr := snowflake.ParseString(cursor.ID)
+			if startErr == nil && idErr == nil {
+				stmt = stmt.Where("(bc.period_start < ? OR (bc.period_start = ? AND bc.id < ?))", start, start, id)
+			}
+		} else {
+			s.log.Warn("failed to decode billing cycle cursor", zap.String("cursor", req.PageToken), zap.Error(err))
+		}
+	}
+	stmt = stmt.Limit(int(pageSize) + 1)
+
+	var items []*subscriptiondomain.BillingCycleSummary
+	if err := stmt.Group("bc.id, bc.period_start, bc.period_end, bc.status").
+		Order("bc.period_start DESC, bc.id DESC").
+		Scan(&items).Error; err != nil {
+		return subscriptiondomain.ListBillingCyclesResponse{}, err
+	}
+
+	pageInfo := pagination.BuildCursorPageInfo(items, pageSize, func(item *subscriptiondomain.BillingCycleSummary) string {
+		token, err := pagination.EncodeCursor(pagination.Cursor{
+			ID:        item.ID.String(),
+			CreatedAt: item.PeriodStart.UTC().Format(time.RFC3339Nano),
+		})
+		if err != nil {
+			return ""
+		}
+		return token
+	})
+	if pageInfo != nil && pageInfo.HasMore && len(items) > int(pageSize) {
+		items = items[:pageSize]
+	}
+
+	cycles := make([]subscriptiondomain.BillingCycleSummary, 0, len(items))
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		cycles = append(cycles, *item)
+	}
+
+	resp := subscriptiondomain.ListBillingCyclesResponse{
+		BillingCycles: cycles,
+	}
+	if pageInfo != nil {
+		resp.PageInfo = *pageInfo
+	}
+
+	return resp, nil
+}
+
+func parseBillingCycleStatusFilter(value string) (*billingcycledomain.BillingCycleStatus, error) {
+	status := billingcycledomain.BillingCycleStatus(strings.ToUpper(strings.TrimSpace(value)))
+	switch status {
+	case "":
+		return nil, nil
+	case billingcycledomain.BillingCycleStatusOpen,
+		billingcycledomain.BillingCycleStatusClosing,
+		billingcycledomain.BillingCycleStatusClosed:
+		return &status, nil
+	default:
+		return nil, subscriptiondomain.ErrInvalidStatus
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestListBillingCycles_SumsRatingAndPaginates(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&billingcycledomain.BillingCycle{},
+		&ratingdomain.RatingResult{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	subID := node.Generate()
+	repo := &mockRepository{subscriptions: make(map[string]*subscriptiondomain.Subscription)}
+
+	svc := NewService(ServiceParam{
+		DB:               db,
+		Log:              zap.NewNop(),
+		GenID:            node,
+		Clock:            &mockClock{},
+		Repo:             repo,
+		Pricesvc:         &mockPriceService{},
+		PriceAmountsvc:   &mockPriceAmountService{},
+		PaymentMethodSvc: &mockPaymentMethodService{},
+	})
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+		ID:               subID,
+		OrgID:            orgID,
+		CustomerID:       node.Generate(),
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		BillingCycleType: "monthly",
+		StartAt:          start,
+		CreatedAt:        start,
+		UpdatedAt:        start,
+	}))
+
+	statuses := []billingcycledomain.BillingCycleStatus{
+		billingcycledomain.BillingCycleStatusClosed,
+		billingcycledomain.BillingCycleStatusClosing,
+		billingcycledomain.BillingCycleStatusOpen,
+	}
+	cycleIDs := make([]snowflake.ID, 0, len(statuses))
+	for i, status := range statuses {
+		cycleID := node.Generate()
+		cycleIDs = append(cycleIDs, cycleID)
+		periodStart := start.AddDate(0, i, 0)
+		require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+			ID:             cycleID,
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			PeriodStart:    periodStart,
+			PeriodEnd:      periodStart.AddDate(0, 1, 0),
+			Status:         status,
+			CreatedAt:      periodStart,
+			UpdatedAt:      periodStart,
+		}).Error)
+	}
+	for i, amount := range []int64{1000, 250} {
+		require.NoError(t, db.Create(&ratingdomain.RatingResult{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			BillingCycleID: cycleIDs[0],
+			PriceID:        node.Generate(),
+			Quantity:       1,
+			UnitPrice:      amount,
+			Amount:         amount,
+			Currency:       "USD",
+			PeriodStart:    start,
+			PeriodEnd:      start.AddDate(0, 1, 0),
+			Source:         "test",
+			Checksum:       "checksum-" + string(rune('a'+i)),
+		}).Error)
+	}
+
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	page, err := svc.ListBillingCycles(ctx, subscriptiondomain.ListBillingCyclesRequest{
+		SubscriptionID: subID.String(),
+		PageSize:       2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.BillingCycles, 2)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, 2, page.PageSize)
+	assert.Equal(t, cycleIDs[2], page.BillingCycles[0].ID)
+	assert.Equal(t, cycleIDs[1], page.BillingCycles[1].ID)
+	assert.Equal(t, int64(0), page.BillingCycles[0].RatedAmount)
+
+	page, err = svc.ListBillingCycles(ctx, subscriptiondomain.ListBillingCyclesRequest{
+		SubscriptionID: subID.String(),
+		PageSize:       2,
+		PageToken:      page.NextPageToken,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.BillingCycles, 1)
+	assert.False(t, page.HasMore)
+	assert.Equal(t, cycleIDs[0], page.BillingCycles[0].ID)
+	assert.Equal(t, int64(1250), page.BillingCycles[0].RatedAmount)
+
+	closing, err := svc.ListBillingCycles(ctx, subscriptiondomain.ListBillingCyclesRequest{
+		SubscriptionID: subID.String(),
+		Status:         "closing",
+	})
+	require.NoError(t, err)
+	require.Len(t, closing.BillingCycles, 1)
+	assert.Equal(t, string(billingcycledomain.BillingCycleStatusClosing), closing.BillingCycles[0].Status)
+	assert.Equal(t, pagination.DefaultPageSize, closing.PageSize)
+
+	all, err := svc.ListBillingCycles(ctx, subscriptiondomain.ListBillingCyclesRequest{
+		SubscriptionID: subID.String(),
+		PageSize:       100000,
+	})
+	require.NoError(t, err)
+	require.Len(t, all.BillingCycles, 3)
+	assert.Equal(t, pagination.MaxPageSize, all.PageSize)
+
+	_, err = svc.ListBillingCycles(ctx, subscriptiondomain.ListBillingCyclesRequest{
+		SubscriptionID: subID.String(),
+		Status:         "archived",
+	})
+	assert.ErrorIs(t, err, subscriptiondomain.ErrInvalidStatus)
+}
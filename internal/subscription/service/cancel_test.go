@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TestCancelSubscription_AtPeriodEnd schedules a cancellation for the end of
+// the open cycle and then withdraws it by re-activating the subscription.
+func TestCancelSubscription_AtPeriodEnd(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&subscriptiondomain.SubscriptionEvent{},
+		&billingcycledomain.BillingCycle{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	subID := node.Generate()
+	repo := &mockRepository{subscriptions: make(map[string]*subscriptiondomain.Subscription)}
+
+	svc := NewService(ServiceParam{
+		DB:               db,
+		Log:              zap.NewNop(),
+		GenID:            node,
+		Clock:            &mockClock{},
+		Repo:             repo,
+		Pricesvc:         &mockPriceService{},
+		PriceAmountsvc:   &mockPriceAmountService{},
+		PaymentMethodSvc: &mockPaymentMethodService{},
+	})
+
+	now := time.Now().UTC()
+	periodStart := now.Add(-10 * 24 * time.Hour)
+	periodEnd := now.Add(20 * 24 * time.Hour).Truncate(time.Second)
+	require.NoError(t, repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+		ID:               subID,
+		OrgID:            orgID,
+		CustomerID:       node.Generate(),
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		BillingCycleType: "monthly",
+		StartAt:          periodStart,
+		CreatedAt:        periodStart,
+		UpdatedAt:        periodStart,
+	}))
+
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	cancel := subscriptiondomain.CancelSubscriptionRequest{
+		SubscriptionID: subID.String(),
+		CancelAt:       subscriptiondomain.CancelAtPeriodEnd,
+	}
+
+	// Without an open cycle there is no period end to cancel at.
+	assert.ErrorIs(t, svc.CancelSubscription(ctx, cancel), subscriptiondomain.ErrNoOpenBillingCycle)
+
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		Status:         billingcycledomain.BillingCycleStatusOpen,
+		CreatedAt:      periodStart,
+		UpdatedAt:      periodStart,
+	}).Error)
+
+	assert.ErrorIs(t, svc.CancelSubscription(ctx, subscriptiondomain.CancelSubscriptionRequest{
+		SubscriptionID: subID.String(),
+		CancelAt:       "tomorrow",
+	}), subscriptiondomain.ErrInvalidCancelAt)
+
+	require.NoError(t, svc.CancelSubscription(ctx, cancel))
+
+	var stored subscriptiondomain.Subscription
+	require.NoError(t, db.First(&stored, "id = ?", subID).Error)
+	assert.Equal(t, subscriptiondomain.SubscriptionStatusActive, stored.Status)
+	assert.True(t, stored.CancelAtPeriodEnd)
+	require.NotNil(t, stored.CancelAt)
+	assert.True(t, stored.CancelAt.Equal(periodEnd))
+	assert.Nil(t, stored.CanceledAt)
+
+	// Re-activating before the scheduled cancel clears it.
+	require.NoError(t, svc.TransitionSubscription(ctx, subID.String(), subscriptiondomain.SubscriptionStatusActive, ""))
+	stored = subscriptiondomain.Subscription{}
+	require.NoError(t, db.First(&stored, "id = ?", subID).Error)
+	assert.False(t, stored.CancelAtPeriodEnd)
+	assert.Nil(t, stored.CancelAt)
+
+	// Immediate cancellation transitions straight away.
+	require.NoError(t, svc.CancelSubscription(ctx, subscriptiondomain.CancelSubscriptionRequest{
+		SubscriptionID: subID.String(),
+		CancelAt:       subscriptiondomain.CancelAtImmediate,
+	}))
+	require.NoError(t, db.First(&stored, "id = ?", subID).Error)
+	assert.Equal(t, subscriptiondomain.SubscriptionStatusCanceled, stored.Status)
+	assert.NotNil(t, stored.CanceledAt)
+}
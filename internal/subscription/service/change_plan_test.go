@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
 	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
@@ -35,8 +37,8 @@ func (m *mockPriceService) Get(ctx context.Context, id string) (*pricedomain.Res
 	}
 	return nil, nil
 }
-func (m *mockPriceService) List(ctx context.Context, opts pricedomain.ListOptions) ([]pricedomain.Response, error) {
-	return m.prices, nil
+func (m *mockPriceService) List(ctx context.Context, opts pricedomain.ListOptions) (pricedomain.ListResponse, error) {
+	return pricedomain.ListResponse{Prices: m.prices}, nil
 }
 
 type mockProductFeatureRepo struct {
@@ -101,7 +103,9 @@ func (m *mockRepository) FindByIdempotencyKey(ctx context.Context, db *gorm.DB,
 	return nil, nil
 }
 func (m *mockRepository) ListItemsBySubscriptionID(ctx context.Context, db *gorm.DB, orgID, subscriptionID snowflake.ID) ([]subscriptiondomain.SubscriptionItem, error) {
-	return nil, nil
+	var items []subscriptiondomain.SubscriptionItem
+	err := db.Where("org_id = ? AND subscription_id = ?", orgID, subscriptionID).Find(&items).Error
+	return items, err
 }
 func (m *mockRepository) ListEntitlements(ctx context.Context, db *gorm.DB, subscriptionID snowflake.ID, activeAt *time.Time, page pagination.Pagination) ([]*subscriptiondomain.SubscriptionEntitlement, error) {
 	return nil, nil
@@ -141,10 +145,18 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&subscriptiondomain.Subscription{},
 		&subscriptiondomain.SubscriptionItem{},
 		&subscriptiondomain.SubscriptionEntitlement{},
+		&billingcycledomain.BillingCycle{},
 	)
 	if err != nil {
 		t.Fatalf("failed to migrate database: %v", err)
 	}
+	if err := db.Exec(`CREATE TABLE products (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		active BOOLEAN NOT NULL
+	)`).Error; err != nil {
+		t.Fatalf("failed to create products table: %v", err)
+	}
 	return db
 }
 
@@ -232,6 +244,7 @@ func TestChangePlan(t *testing.T) {
 		UpdatedAt:        now,
 	}
 	repo.Insert(context.Background(), db, sub)
+	db.Exec(`INSERT INTO products (id, org_id, active) VALUES (?, ?, true), (?, ?, true)`, oldProductID, orgID, newProductID, orgID)
 
 	// Create old entitlement - WE MUST INSERT into DB manually because repo.InsertEntitlements does (via mock)
 	// but direct usage sets effectiveFrom.
@@ -299,9 +312,102 @@ func TestChangePlan(t *testing.T) {
 	}
 }
 
+func TestChangePlan_TargetPrice(t *testing.T) {
+	db := setupTestDB(t)
+	node, _ := snowflake.NewNode(1)
+	repo := &mockRepository{
+		subscriptions: make(map[string]*subscriptiondomain.Subscription),
+	}
+
+	orgID := node.Generate()
+	productID := node.Generate()
+	inactiveProductID := node.Generate()
+	oldPriceID := node.Generate()
+	newPriceID := node.Generate()
+	dailyPriceID := node.Generate()
+	inactivePriceID := node.Generate()
+
+	priceSvc := &mockPriceService{
+		prices: []pricedomain.Response{
+			{ID: oldPriceID, OrganizationID: orgID, ProductID: productID, BillingInterval: pricedomain.Month, Active: true, PricingModel: pricedomain.Flat, BillingMode: pricedomain.Licensed},
+			{ID: newPriceID, OrganizationID: orgID, ProductID: productID, BillingInterval: pricedomain.Month, Active: true, PricingModel: pricedomain.Flat, BillingMode: pricedomain.Licensed},
+			{ID: dailyPriceID, OrganizationID: orgID, ProductID: productID, BillingInterval: pricedomain.Day, Active: true, PricingModel: pricedomain.Flat, BillingMode: pricedomain.Licensed},
+			{ID: inactivePriceID, OrganizationID: orgID, ProductID: inactiveProductID, BillingInterval: pricedomain.Month, Active: true, PricingModel: pricedomain.Flat, BillingMode: pricedomain.Licensed},
+		},
+	}
+
+	svc := NewService(ServiceParam{
+		DB:                 db,
+		Log:                zap.NewNop(),
+		GenID:              node,
+		Clock:              &mockClock{},
+		Repo:               repo,
+		Pricesvc:           priceSvc,
+		ProductFeatureRepo: &mockProductFeatureRepo{},
+		PriceAmountsvc:     &mockPriceAmountService{},
+		PaymentMethodSvc:   &mockPaymentMethodService{},
+	})
+
+	subID := node.Generate()
+	now := time.Now().UTC()
+	currency := "USD"
+	repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+		ID:               subID,
+		OrgID:            orgID,
+		CustomerID:       node.Generate(),
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		BillingCycleType: "monthly",
+		DefaultCurrency:  &currency,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	})
+	db.Exec(`INSERT INTO products (id, org_id, active) VALUES (?, ?, true), (?, ?, false)`, productID, orgID, inactiveProductID, orgID)
+	db.Create(&subscriptiondomain.SubscriptionItem{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: subID,
+		PriceID:        oldPriceID,
+		Quantity:       1,
+		BillingMode:    string(pricedomain.Licensed),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	err := svc.ChangePlan(ctx, subscriptiondomain.ChangePlanRequest{SubscriptionID: subID.String(), NewPriceID: dailyPriceID.String()})
+	if !errors.Is(err, subscriptiondomain.ErrInvalidBillingCycleType) {
+		t.Fatalf("expected ErrInvalidBillingCycleType, got %v", err)
+	}
+
+	err = svc.ChangePlan(ctx, subscriptiondomain.ChangePlanRequest{SubscriptionID: subID.String(), NewPriceID: inactivePriceID.String()})
+	if !errors.Is(err, subscriptiondomain.ErrInvalidProduct) {
+		t.Fatalf("expected ErrInvalidProduct, got %v", err)
+	}
+
+	if err := svc.ChangePlan(ctx, subscriptiondomain.ChangePlanRequest{SubscriptionID: subID.String(), NewPriceID: newPriceID.String()}); err != nil {
+		t.Fatalf("ChangePlan failed: %v", err)
+	}
+
+	var items []subscriptiondomain.SubscriptionItem
+	db.Where("subscription_id = ?", subID).Find(&items)
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(items))
+	}
+	if items[0].PriceID != newPriceID {
+		t.Errorf("Expected item for price %s, got %s", newPriceID, items[0].PriceID)
+	}
+	if items[0].BillingMode != string(pricedomain.Licensed) {
+		t.Errorf("Expected billing mode snapshot %s, got %s", pricedomain.Licensed, items[0].BillingMode)
+	}
+	if items[0].ProrationBehavior == nil || *items[0].ProrationBehavior != subscriptiondomain.ProrationBehaviorCreateProrations {
+		t.Error("Flat plan changes should prorate by default")
+	}
+}
+
 type mockClock struct{}
 
-func (m *mockClock) Now() time.Time { return time.Now().UTC() }
+func (m *mockClock) Now(ctx context.Context) time.Time { return time.Now().UTC() }
 
 // Mock PriceAmountService (minimal)
 type mockPriceAmountService struct{}
@@ -324,6 +430,9 @@ func (m *mockPriceAmountService) List(ctx context.Context, req priceamountdomain
 func (m *mockPriceAmountService) Get(ctx context.Context, req priceamountdomain.GetPriceAmountByID) (*priceamountdomain.Response, error) {
 	return nil, nil
 }
+func (m *mockPriceAmountService) Schedule(ctx context.Context, req priceamountdomain.ScheduleRequest) (*priceamountdomain.Response, error) {
+	return nil, nil
+}
 
 type mockPaymentMethodService struct{}
 
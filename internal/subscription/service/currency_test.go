@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+)
+
+// currencyPriceAmounts serves a fixed set of amounts, filtered by currency.
+type currencyPriceAmounts struct {
+	mockPriceAmountService
+	amounts []priceamountdomain.Response
+}
+
+func (m *currencyPriceAmounts) List(ctx context.Context, req priceamountdomain.ListPriceAmountRequest) (priceamountdomain.ListPriceAmountResponse, error) {
+	var out []priceamountdomain.Response
+	for _, amount := range m.amounts {
+		if req.Currency == "" || amount.Currency == req.Currency {
+			out = append(out, amount)
+		}
+	}
+	return priceamountdomain.ListPriceAmountResponse{Amounts: out}, nil
+}
+
+func TestMissingPricingError_ListsAvailableCurrencies(t *testing.T) {
+	amounts := &currencyPriceAmounts{amounts: []priceamountdomain.Response{
+		{ID: 1, PriceID: 7, Currency: "USD"},
+		{ID: 2, PriceID: 7, Currency: "IDR"},
+		{ID: 3, PriceID: 7, Currency: "USD"},
+	}}
+	svc := &Service{clock: &mockClock{}, priceamountsvc: amounts}
+
+	err := svc.missingPricingError(context.Background(), "7", "EUR")
+	var currencyErr *subscriptiondomain.CurrencyNotPricedError
+	if !errors.As(err, &currencyErr) {
+		t.Fatalf("expected CurrencyNotPricedError, got %v", err)
+	}
+	if currencyErr.Currency != "EUR" || currencyErr.PriceID != "7" {
+		t.Errorf("unexpected error fields: %+v", currencyErr)
+	}
+	if got := currencyErr.AvailableCurrencies; len(got) != 2 || got[0] != "IDR" || got[1] != "USD" {
+		t.Errorf("expected [IDR USD], got %v", got)
+	}
+	if !errors.Is(err, subscriptiondomain.ErrMissingPricing) {
+		t.Error("CurrencyNotPricedError should still match ErrMissingPricing")
+	}
+
+	// A price with no amounts at all is plainly missing pricing.
+	amounts.amounts = nil
+	err = svc.missingPricingError(context.Background(), "7", "EUR")
+	if err != subscriptiondomain.ErrMissingPricing {
+		t.Errorf("expected ErrMissingPricing, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	ratingrepository "github.com/railzwaylabs/railzway/internal/rating/repository"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+)
+
+// CheckEntitlement reports whether the customer's subscription grants a
+// feature at the requested time. A customer without a subscription in effect
+// at that time is simply not entitled. For metered features the response also carries the
+// remaining included allowance of each item bound to the feature's meter.
+func (s *Service) CheckEntitlement(ctx context.Context, req subscriptiondomain.CheckEntitlementRequest) (subscriptiondomain.EntitlementCheckResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return subscriptiondomain.EntitlementCheckResponse{}, subscriptiondomain.ErrInvalidOrganization
+	}
+
+	customerID, err := s.parseID(req.CustomerID, subscriptiondomain.ErrInvalidCustomer)
+	if err != nil {
+		return subscriptiondomain.EntitlementCheckResponse{}, err
+	}
+	featureCode := strings.TrimSpace(req.FeatureCode)
+	if featureCode == "" {
+		return subscriptiondomain.EntitlementCheckResponse{}, subscriptiondomain.ErrInvalidFeatureCode
+	}
+
+	at := s.clock.Now(ctx).UTC()
+	if req.At != nil {
+		at = req.At.UTC()
+	}
+
+	resp := subscriptiondomain.EntitlementCheckResponse{
+		CustomerID:  customerID,
+		FeatureCode: featureCode,
+		At:          at,
+	}
+
+	// The subscription is resolved as of at, so paused, ended and canceled
+	// windows grant nothing.
+	subscription, err := s.repo.FindActiveByCustomerIDAt(ctx, s.db, orgID, customerID, at)
+	if err != nil {
+		return subscriptiondomain.EntitlementCheckResponse{}, err
+	}
+	if subscription == nil {
+		return resp, nil
+	}
+	resp.SubscriptionID = &subscription.ID
+	resp.SubscriptionStatus = &subscription.Status
+
+	entitlement, err := s.findEntitlementByFeature(ctx, subscription.ID, featureCode, at)
+	if err != nil {
+		return subscriptiondomain.EntitlementCheckResponse{}, err
+	}
+	if entitlement == nil {
+		return resp, nil
+	}
+	resp.Entitled = true
+	resp.FeatureType = entitlement.FeatureType
+	resp.MeterID = entitlement.MeterID
+
+	if entitlement.MeterID != nil {
+		quotas, err := s.entitlementQuotas(ctx, subscription, *entitlement.MeterID, at)
+		if err != nil {
+			return subscriptiondomain.EntitlementCheckResponse{}, err
+		}
+		resp.Quotas = quotas
+	}
+
+	return resp, nil
+}
+
+func (s *Service) findEntitlementByFeature(ctx context.Context, subscriptionID snowflake.ID, featureCode string, at time.Time) (*subscriptiondomain.SubscriptionEntitlement, error) {
+	var entitlement subscriptiondomain.SubscriptionEntitlement
+	err := s.db.WithContext(ctx).Raw(
+		`SELECT id, subscription_id, product_id, feature_code, feature_name, feature_type, meter_id,
+		 effective_from, effective_to, created_at
+		 FROM subscription_entitlements
+		 WHERE subscription_id = ? AND feature_code = ?
+		   AND effective_from <= ?
+		   AND (effective_to IS NULL OR effective_to > ?)
+		 ORDER BY effective_from DESC
+		 LIMIT 1`,
+		subscriptionID,
+		featureCode,
+		at,
+		at,
+	).Scan(&entitlement).Error
+	if err != nil {
+		return nil, err
+	}
+	if entitlement.ID == 0 {
+		return nil, nil
+	}
+	return &entitlement, nil
+}
+
+// entitlementQuotas measures usage of the meter against each item's included
+// allowance. Usage counts from the start of the billing cycle containing at,
+// or from the subscription start for meters that never reset.
+func (s *Service) entitlementQuotas(ctx context.Context, subscription *subscriptiondomain.Subscription, meterID snowflake.ID, at time.Time) ([]subscriptiondomain.EntitlementQuota, error) {
+	var items []subscriptiondomain.SubscriptionItem
+	err := s.db.WithContext(ctx).Raw(
+		`SELECT id, dimension_key, dimension_value, included_quantity
+		 FROM subscription_items
+		 WHERE org_id = ? AND subscription_id = ? AND meter_id = ? AND included_quantity IS NOT NULL
+		 ORDER BY id ASC`,
+		subscription.OrgID,
+		subscription.ID,
+		meterID,
+	).Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	ratingRepo := ratingrepository.NewRepository(s.db)
+	interval, err := ratingRepo.MeterResetInterval(ctx, subscription.OrgID, meterID)
+	if err != nil {
+		return nil, err
+	}
+
+	periodStart := subscription.StartAt
+	if interval != meterdomain.ResetIntervalNever {
+		cycleStart, err := s.billingCycleStartAt(ctx, subscription, at)
+		if err != nil {
+			return nil, err
+		}
+		if cycleStart != nil {
+			periodStart = *cycleStart
+		}
+	}
+
+	quotas := make([]subscriptiondomain.EntitlementQuota, 0, len(items))
+	for _, item := range items {
+		if item.IncludedQuantity == nil {
+			continue
+		}
+		var dimension *ratingdomain.DimensionFilter
+		if item.DimensionKey != nil && item.DimensionValue != nil && *item.DimensionKey != "" {
+			dimension = &ratingdomain.DimensionFilter{Key: *item.DimensionKey, Value: *item.DimensionValue}
+		}
+
+		used := 0.0
+		if at.After(periodStart) {
+			used, err = ratingRepo.AggregateUsage(ctx, subscription.OrgID, subscription.ID, meterID, periodStart, at, dimension)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		quotas = append(quotas, subscriptiondomain.EntitlementQuota{
+			SubscriptionItemID: item.ID,
+			DimensionKey:       item.DimensionKey,
+			DimensionValue:     item.DimensionValue,
+			PeriodStart:        periodStart,
+			IncludedQuantity:   *item.IncludedQuantity,
+			UsedQuantity:       used,
+			RemainingQuantity:  math.Max(0, *item.IncludedQuantity-used),
+		})
+	}
+	return quotas, nil
+}
+
+func (s *Service) billingCycleStartAt(ctx context.Context, subscription *subscriptiondomain.Subscription, at time.Time) (*time.Time, error) {
+	var rows []struct {
+		PeriodStart time.Time
+	}
+	err := s.db.WithContext(ctx).Raw(
+		`SELECT period_start
+		 FROM billing_cycles
+		 WHERE org_id = ? AND subscription_id = ? AND period_start <= ? AND period_end > ?
+		 ORDER BY period_start DESC
+		 LIMIT 1`,
+		subscription.OrgID,
+		subscription.ID,
+		at,
+		at,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0].PeriodStart, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	subscriptionrepository "github.com/railzwaylabs/railzway/internal/subscription/repository"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestCheckEntitlement_ReportsFeatureAndRemainingQuota(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&subscriptiondomain.SubscriptionItem{},
+		&subscriptiondomain.SubscriptionEntitlement{},
+		&billingcycledomain.BillingCycle{},
+		&meterdomain.Meter{},
+		&usagedomain.UsageEvent{},
+		&usagedomain.UsageRollup{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	customerID := node.Generate()
+	subID := node.Generate()
+	meterID := node.Generate()
+	repo := subscriptionrepository.Provide()
+
+	svc := NewService(ServiceParam{
+		DB:               db,
+		Log:              zap.NewNop(),
+		GenID:            node,
+		Clock:            &mockClock{},
+		Repo:             repo,
+		Pricesvc:         &mockPriceService{},
+		PriceAmountsvc:   &mockPriceAmountService{},
+		PaymentMethodSvc: &mockPaymentMethodService{},
+	})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cycleStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := cycleStart.Add(10 * 24 * time.Hour)
+	included := 150.0
+
+	require.NoError(t, repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+		ID:               subID,
+		OrgID:            orgID,
+		CustomerID:       customerID,
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		BillingCycleType: "monthly",
+		StartAt:          start,
+		CreatedAt:        start,
+		UpdatedAt:        start,
+	}))
+	require.NoError(t, db.Create(&meterdomain.Meter{
+		ID: meterID, OrgID: orgID, Code: "api_calls", Name: "API Calls",
+		Aggregation: "SUM", Unit: "call", ResetInterval: meterdomain.ResetIntervalBillingCycle, Active: true,
+	}).Error)
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionItem{
+		ID: node.Generate(), OrgID: orgID, SubscriptionID: subID, PriceID: node.Generate(),
+		MeterID: &meterID, BillingMode: "METERED", IncludedQuantity: &included,
+	}).Error)
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID: node.Generate(), OrgID: orgID, SubscriptionID: subID,
+		PeriodStart: cycleStart, PeriodEnd: cycleStart.AddDate(0, 1, 0),
+		Status: billingcycledomain.BillingCycleStatusOpen,
+	}).Error)
+	require.NoError(t, db.Create([]subscriptiondomain.SubscriptionEntitlement{
+		{ID: node.Generate(), OrgID: orgID, SubscriptionID: subID, FeatureCode: "api", FeatureType: "metered", MeterID: &meterID, EffectiveFrom: start, CreatedAt: start},
+		{ID: node.Generate(), OrgID: orgID, SubscriptionID: subID, FeatureCode: "sso", FeatureType: "boolean", EffectiveFrom: at.Add(time.Hour), CreatedAt: start},
+	}).Error)
+
+	// Usage from the previous cycle does not count against this one.
+	for i, recordedAt := range []time.Time{cycleStart.Add(-time.Hour), cycleStart.Add(time.Hour), cycleStart.Add(2 * time.Hour)} {
+		require.NoError(t, db.Create(&usagedomain.UsageEvent{
+			ID: node.Generate(), OrgID: orgID, CustomerID: customerID, SubscriptionID: subID, MeterID: meterID,
+			MeterCode: "api_calls", Value: float64(20 * (i + 1)), RecordedAt: recordedAt, Status: usagedomain.UsageStatusEnriched,
+		}).Error)
+	}
+
+	check := func(feature string) subscriptiondomain.EntitlementCheckResponse {
+		resp, err := svc.CheckEntitlement(ctx, subscriptiondomain.CheckEntitlementRequest{
+			CustomerID:  customerID.String(),
+			FeatureCode: feature,
+			At:          &at,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	api := check("api")
+	assert.True(t, api.Entitled)
+	require.NotNil(t, api.MeterID)
+	assert.Equal(t, meterID, *api.MeterID)
+	require.Len(t, api.Quotas, 1)
+	assert.Equal(t, cycleStart, api.Quotas[0].PeriodStart.UTC())
+	assert.Equal(t, 100.0, api.Quotas[0].UsedQuantity)
+	assert.Equal(t, 50.0, api.Quotas[0].RemainingQuantity)
+
+	// Not yet effective at the requested time.
+	sso := check("sso")
+	assert.False(t, sso.Entitled)
+	assert.NotNil(t, sso.SubscriptionID)
+
+	resp, err := svc.CheckEntitlement(ctx, subscriptiondomain.CheckEntitlementRequest{
+		CustomerID:  node.Generate().String(),
+		FeatureCode: "api",
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.Entitled)
+	assert.Nil(t, resp.SubscriptionID)
+
+	_, err = svc.CheckEntitlement(ctx, subscriptiondomain.CheckEntitlementRequest{CustomerID: customerID.String()})
+	assert.ErrorIs(t, err, subscriptiondomain.ErrInvalidFeatureCode)
+}
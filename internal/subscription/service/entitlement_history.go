@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	auditdomain "github.com/railzwaylabs/railzway/internal/audit/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
+	"go.uber.org/zap"
+)
+
+// entitlementAuditActions maps subscription audit actions to the entitlement
+// trigger they record.
+var entitlementAuditActions = map[string]subscriptiondomain.EntitlementTrigger{
+	"subscription.create":        subscriptiondomain.EntitlementTriggerCreate,
+	"subscription.items.replace": subscriptiondomain.EntitlementTriggerReplaceItems,
+}
+
+// entitlementBatch is a set of entitlement rows written by one subscription
+// change. Every row of a batch shares the same effective_from.
+type entitlementBatch struct {
+	at         time.Time
+	trigger    subscriptiondomain.EntitlementTrigger
+	auditLogID *snowflake.ID
+}
+
+// GetEntitlementHistory returns the effective windows of a single feature on a
+// subscription in chronological order, annotated with the event that opened
+// and, when closed by another change, the event that closed each window.
+func (s *Service) GetEntitlementHistory(ctx context.Context, req subscriptiondomain.GetEntitlementHistoryRequest) (subscriptiondomain.EntitlementHistoryResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return subscriptiondomain.EntitlementHistoryResponse{}, subscriptiondomain.ErrInvalidOrganization
+	}
+
+	subscriptionID, err := s.parseID(req.SubscriptionID, subscriptiondomain.ErrInvalidSubscription)
+	if err != nil {
+		return subscriptiondomain.EntitlementHistoryResponse{}, err
+	}
+
+	featureCode := strings.TrimSpace(req.FeatureCode)
+	if featureCode == "" {
+		return subscriptiondomain.EntitlementHistoryResponse{}, subscriptiondomain.ErrInvalidFeatureCode
+	}
+
+	subscription, err := s.repo.FindByID(ctx, s.db, orgID, subscriptionID)
+	if err != nil {
+		return subscriptiondomain.EntitlementHistoryResponse{}, err
+	}
+	if subscription == nil {
+		return subscriptiondomain.EntitlementHistoryResponse{}, subscriptiondomain.ErrSubscriptionNotFound
+	}
+
+	pageSize := pagination.ClampPageSize(req.PageSize)
+
+	stmt := s.db.WithContext(ctx).Model(&subscriptiondomain.SubscriptionEntitlement{}).
+		Where("org_id = ? AND subscription_id = ? AND feature_code = ?", orgID, subscriptionID, featureCode)
+
+	// History is read oldest first, so the cursor moves forward on
+	// (effective_from, id) rather than backwards on created_at.
+	if req.PageToken != "" {
+		cursor, err := pagination.DecodeCursor(req.PageToken)
+		if err == nil {
+			from, fromErr := time.Parse(time.RFC3339Nano, cursor.CreatedAt)
+			id, idErr := snowflake.ParseString(cursor.ID)
+			if fromErr == nil && idErr == nil {
+				stmt = stmt.Where("(effective_from > ? OR (effective_from = ? AND id > ?))", from, from, id)
+			}
+		} else {
+			s.log.Warn("failed to decode entitlement history cursor", zap.String("cursor", req.PageToken), zap.Error(err))
+		}
+	}
+	stmt = stmt.Limit(int(pageSize) + 1)
+
+	var items []*subscriptiondomain.SubscriptionEntitlement
+	if err := stmt.Order("effective_from ASC, id ASC").Find(&items).Error; err != nil {
+		return subscriptiondomain.EntitlementHistoryResponse{}, err
+	}
+
+	pageInfo := pagination.BuildCursorPageInfo(items, pageSize, func(item *subscriptiondomain.SubscriptionEntitlement) string {
+		token, err := pagination.EncodeCursor(pagination.Cursor{
+			ID:        item.ID.String(),
+			CreatedAt: item.EffectiveFrom.UTC().Format(time.RFC3339Nano),
+		})
+		if err != nil {
+			return ""
+		}
+		return token
+	})
+	if pageInfo != nil && pageInfo.HasMore && len(items) > int(pageSize) {
+		items = items[:pageSize]
+	}
+
+	batches, err := s.loadEntitlementBatches(ctx, orgID, subscriptionID)
+	if err != nil {
+		return subscriptiondomain.EntitlementHistoryResponse{}, err
+	}
+
+	history := make([]subscriptiondomain.EntitlementHistoryEntry, 0, len(items))
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		entry := subscriptiondomain.EntitlementHistoryEntry{
+			EntitlementID:  item.ID,
+			SubscriptionID: item.SubscriptionID,
+			ProductID:      item.ProductID,
+			FeatureCode:    item.FeatureCode,
+			FeatureName:    item.FeatureName,
+			FeatureType:    item.FeatureType,
+			MeterID:        item.MeterID,
+			EffectiveFrom:  item.EffectiveFrom,
+			EffectiveTo:    item.EffectiveTo,
+			Trigger:        subscriptiondomain.EntitlementTriggerReplaceItems,
+		}
+		if batch, ok := findEntitlementBatch(batches, item.EffectiveFrom); ok {
+			entry.Trigger = batch.trigger
+			entry.AuditLogID = batch.auditLogID
+		}
+		if item.EffectiveTo != nil {
+			if batch, ok := findEntitlementBatch(batches, *item.EffectiveTo); ok {
+				closedBy := batch.trigger
+				entry.ClosedBy = &closedBy
+			}
+		}
+		history = append(history, entry)
+	}
+
+	resp := subscriptiondomain.EntitlementHistoryResponse{
+		History: history,
+	}
+	if pageInfo != nil {
+		resp.PageInfo = *pageInfo
+	}
+
+	return resp, nil
+}
+
+// loadEntitlementBatches groups every entitlement row of the subscription by
+// the change that wrote it. The first batch is the subscription create and
+// later ones default to item replacement; matching audit entries refine the
+// trigger and link the audit record.
+func (s *Service) loadEntitlementBatches(ctx context.Context, orgID, subscriptionID snowflake.ID) ([]entitlementBatch, error) {
+	var rows []subscriptiondomain.SubscriptionEntitlement
+	if err := s.db.WithContext(ctx).Model(&subscriptiondomain.SubscriptionEntitlement{}).
+		Select("effective_from").
+		Where("org_id = ? AND subscription_id = ?", orgID, subscriptionID).
+		Order("effective_from ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	batches := make([]entitlementBatch, 0)
+	for _, row := range rows {
+		if n := len(batches); n > 0 && batches[n-1].at.Equal(row.EffectiveFrom) {
+			continue
+		}
+		trigger := subscriptiondomain.EntitlementTriggerReplaceItems
+		if len(batches) == 0 {
+			trigger = subscriptiondomain.EntitlementTriggerCreate
+		}
+		batches = append(batches, entitlementBatch{at: row.EffectiveFrom, trigger: trigger})
+	}
+	if len(batches) == 0 {
+		return batches, nil
+	}
+
+	actions := make([]string, 0, len(entitlementAuditActions))
+	for action := range entitlementAuditActions {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	var logs []auditdomain.AuditLog
+	if err := s.db.WithContext(ctx).
+		Where("org_id = ? AND target_type = ? AND target_id = ? AND action IN ?", orgID, "subscription", subscriptionID.String(), actions).
+		Order("created_at ASC, id ASC").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	// Entitlements are written inside the change transaction and the audit
+	// entry right after it commits, so a batch owns the first audit entry
+	// recorded between it and the next batch.
+	next := 0
+	for i := range batches {
+		for next < len(logs) && logs[next].CreatedAt.Before(batches[i].at) {
+			next++
+		}
+		if next >= len(logs) {
+			break
+		}
+		if i+1 < len(batches) && !logs[next].CreatedAt.Before(batches[i+1].at) {
+			continue
+		}
+		auditLogID := logs[next].ID
+		batches[i].trigger = entitlementAuditActions[logs[next].Action]
+		batches[i].auditLogID = &auditLogID
+		next++
+	}
+
+	return batches, nil
+}
+
+func findEntitlementBatch(batches []entitlementBatch, at time.Time) (entitlementBatch, bool) {
+	idx := sort.Search(len(batches), func(i int) bool {
+		return !batches[i].at.Before(at)
+	})
+	if idx < len(batches) && batches[idx].at.Equal(at) {
+		return batches[idx], true
+	}
+	return entitlementBatch{}, false
+}
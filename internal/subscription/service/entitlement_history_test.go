@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	auditdomain "github.com/railzwaylabs/railzway/internal/audit/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	productfeaturedomain "github.com/railzwaylabs/railzway/internal/productfeature/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TestGetEntitlementHistory_ReplaceItemsClosesAndReopens verifies the history
+// of a feature that is revoked by one item replacement and granted again by a
+// later one.
+func TestGetEntitlementHistory_ReplaceItemsClosesAndReopens(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&subscriptiondomain.SubscriptionItem{},
+		&subscriptiondomain.SubscriptionEntitlement{},
+		&auditdomain.AuditLog{},
+	))
+	require.NoError(t, db.Exec(`CREATE TABLE products (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		active BOOLEAN NOT NULL
+	)`).Error)
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	subID := node.Generate()
+	proProductID := node.Generate()
+	basicProductID := node.Generate()
+	proPriceID := node.Generate()
+	basicPriceID := node.Generate()
+
+	require.NoError(t, db.Exec(
+		`INSERT INTO products (id, org_id, active) VALUES (?, ?, true), (?, ?, true)`,
+		proProductID, orgID, basicProductID, orgID,
+	).Error)
+
+	priceSvc := &mockPriceService{prices: []pricedomain.Response{
+		{ID: proPriceID, OrganizationID: orgID, ProductID: proProductID, BillingInterval: pricedomain.Month, Active: true, PricingModel: pricedomain.Flat, BillingMode: pricedomain.Licensed},
+		{ID: basicPriceID, OrganizationID: orgID, ProductID: basicProductID, BillingInterval: pricedomain.Month, Active: true, PricingModel: pricedomain.Flat, BillingMode: pricedomain.Licensed},
+	}}
+	featureRepo := &mockProductFeatureRepo{features: []productfeaturedomain.FeatureAssignment{
+		{FeatureID: node.Generate(), ProductID: proProductID, Code: "sso", Name: "SSO", FeatureType: "boolean", Active: true},
+		{FeatureID: node.Generate(), ProductID: proProductID, Code: "api", Name: "API", FeatureType: "boolean", Active: true},
+		{FeatureID: node.Generate(), ProductID: basicProductID, Code: "api", Name: "API", FeatureType: "boolean", Active: true},
+	}}
+	repo := &mockRepository{subscriptions: make(map[string]*subscriptiondomain.Subscription)}
+
+	svc := NewService(ServiceParam{
+		DB:                 db,
+		Log:                zap.NewNop(),
+		GenID:              node,
+		Clock:              &mockClock{},
+		Repo:               repo,
+		Pricesvc:           priceSvc,
+		PriceAmountsvc:     &mockPriceAmountService{},
+		ProductFeatureRepo: featureRepo,
+		PaymentMethodSvc:   &mockPaymentMethodService{},
+	})
+
+	createdAt := time.Now().UTC().Add(-48 * time.Hour)
+	currency := "USD"
+	require.NoError(t, repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+		ID:               subID,
+		OrgID:            orgID,
+		CustomerID:       node.Generate(),
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		BillingCycleType: "monthly",
+		DefaultCurrency:  &currency,
+		StartAt:          createdAt,
+		CreatedAt:        createdAt,
+		UpdatedAt:        createdAt,
+	}))
+	for _, code := range []string{"sso", "api"} {
+		require.NoError(t, db.Create(&subscriptiondomain.SubscriptionEntitlement{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			ProductID:      proProductID,
+			FeatureCode:    code,
+			FeatureType:    "boolean",
+			EffectiveFrom:  createdAt,
+			CreatedAt:      createdAt,
+		}).Error)
+	}
+
+	target := subID.String()
+	writeAudit := func(action string, at time.Time) snowflake.ID {
+		entry := auditdomain.AuditLog{
+			ID:         node.Generate(),
+			OrgID:      &orgID,
+			ActorType:  string(auditdomain.ActorTypeAPIKey),
+			Action:     action,
+			TargetType: "subscription",
+			TargetID:   &target,
+			CreatedAt:  at,
+		}
+		require.NoError(t, db.Create(&entry).Error)
+		return entry.ID
+	}
+	createAuditID := writeAudit("subscription.create", createdAt.Add(time.Millisecond))
+
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	replace := func(priceID snowflake.ID, otherActions ...string) snowflake.ID {
+		_, err := svc.ReplaceItems(ctx, subscriptiondomain.ReplaceSubscriptionItemsRequest{
+			SubscriptionID: subID.String(),
+			Items:          []subscriptiondomain.CreateSubscriptionItemRequest{{PriceID: priceID.String()}},
+		})
+		require.NoError(t, err)
+		for _, action := range otherActions {
+			writeAudit(action, time.Now().UTC())
+		}
+		return writeAudit("subscription.items.replace", time.Now().UTC())
+	}
+
+	// Downgrade drops sso, upgrade grants it again. Audit actions that write
+	// no entitlements are skipped when matching a change to its audit entry.
+	downgradeAuditID := replace(basicPriceID, "subscription.update", "subscription.entitlements.override")
+	upgradeAuditID := replace(proPriceID)
+
+	resp, err := svc.GetEntitlementHistory(ctx, subscriptiondomain.GetEntitlementHistoryRequest{
+		SubscriptionID: subID.String(),
+		FeatureCode:    "sso",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.History, 2)
+
+	granted := resp.History[0]
+	assert.Equal(t, subscriptiondomain.EntitlementTriggerCreate, granted.Trigger)
+	require.NotNil(t, granted.AuditLogID)
+	assert.Equal(t, createAuditID, *granted.AuditLogID)
+	assert.True(t, granted.EffectiveFrom.Equal(createdAt))
+	require.NotNil(t, granted.EffectiveTo)
+	require.NotNil(t, granted.ClosedBy)
+	assert.Equal(t, subscriptiondomain.EntitlementTriggerReplaceItems, *granted.ClosedBy)
+
+	regranted := resp.History[1]
+	assert.Equal(t, subscriptiondomain.EntitlementTriggerReplaceItems, regranted.Trigger)
+	require.NotNil(t, regranted.AuditLogID)
+	assert.Equal(t, upgradeAuditID, *regranted.AuditLogID)
+	assert.True(t, regranted.EffectiveFrom.After(*granted.EffectiveTo))
+	assert.Nil(t, regranted.EffectiveTo)
+	assert.Nil(t, regranted.ClosedBy)
+
+	// api survives both replacements as three contiguous windows, paged one
+	// window at a time.
+	var windows []subscriptiondomain.EntitlementHistoryEntry
+	pageToken := ""
+	for i := 0; i < 3; i++ {
+		page, err := svc.GetEntitlementHistory(ctx, subscriptiondomain.GetEntitlementHistoryRequest{
+			SubscriptionID: subID.String(),
+			FeatureCode:    "api",
+			PageToken:      pageToken,
+			PageSize:       1,
+		})
+		require.NoError(t, err)
+		require.Len(t, page.History, 1)
+		windows = append(windows, page.History[0])
+		pageToken = page.NextPageToken
+		assert.Equal(t, i < 2, page.HasMore)
+	}
+	assert.Equal(t, subscriptiondomain.EntitlementTriggerCreate, windows[0].Trigger)
+	assert.Equal(t, subscriptiondomain.EntitlementTriggerReplaceItems, windows[1].Trigger)
+	require.NotNil(t, windows[1].AuditLogID)
+	assert.Equal(t, downgradeAuditID, *windows[1].AuditLogID)
+	assert.Equal(t, subscriptiondomain.EntitlementTriggerReplaceItems, windows[2].Trigger)
+	assert.Equal(t, basicProductID, windows[1].ProductID)
+	assert.True(t, windows[0].EffectiveTo.Equal(windows[1].EffectiveFrom))
+	assert.True(t, windows[1].EffectiveTo.Equal(windows[2].EffectiveFrom))
+	assert.Nil(t, windows[2].EffectiveTo)
+
+	_, err = svc.GetEntitlementHistory(ctx, subscriptiondomain.GetEntitlementHistoryRequest{
+		SubscriptionID: subID.String(),
+	})
+	assert.ErrorIs(t, err, subscriptiondomain.ErrInvalidFeatureCode)
+}
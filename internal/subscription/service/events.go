@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	auditdomain "github.com/railzwaylabs/railzway/internal/audit/domain"
+	"github.com/railzwaylabs/railzway/internal/auditcontext"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// recordEvent appends the status change of subscription to its lifecycle
+// timeline. The actor comes from the audit context and falls back to the
+// system, matching how audit entries resolve it.
+func (s *Service) recordEvent(
+	ctx context.Context,
+	tx *gorm.DB,
+	subscription *subscriptiondomain.Subscription,
+	fromStatus subscriptiondomain.SubscriptionStatus,
+	reason subscriptiondomain.TransitionReason,
+	at time.Time,
+) error {
+	actorType, actorID := auditcontext.ActorFromContext(ctx)
+	if actorType == "" {
+		actorType = string(auditdomain.ActorTypeSystem)
+	}
+
+	return tx.WithContext(ctx).Exec(
+		`INSERT INTO subscription_events (
+			id, org_id, subscription_id, from_status, to_status, reason, actor_type, actor_id, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.genID.Generate(),
+		subscription.OrgID,
+		subscription.ID,
+		fromStatus,
+		subscription.Status,
+		optionalString(string(reason)),
+		actorType,
+		optionalString(actorID),
+		at,
+	).Error
+}
+
+// ListEvents returns the status changes of a subscription oldest first.
+// Pages move forward on (created_at, id).
+func (s *Service) ListEvents(ctx context.Context, req subscriptiondomain.ListSubscriptionEventsRequest) (subscriptiondomain.ListSubscriptionEventsResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return subscriptiondomain.ListSubscriptionEventsResponse{}, subscriptiondomain.ErrInvalidOrganization
+	}
+
+	subscriptionID, err := s.parseID(req.SubscriptionID, subscriptiondomain.ErrInvalidSubscription)
+	if err != nil {
+		return subscriptiondomain.ListSubscriptionEventsResponse{}, err
+	}
+
+	subscription, err := s.repo.FindByID(ctx, s.db, orgID, subscriptionID)
+	if err != nil {
+		return subscriptiondomain.ListSubscriptionEventsResponse{}, err
+	}
+	if subscription == nil {
+		return subscriptiondomain.ListSubscriptionEventsResponse{}, subscriptiondomain.ErrSubscriptionNotFound
+	}
+
+	pageSize := pagination.ClampPageSize(req.PageSize)
+
+	stmt := s.db.WithContext(ctx).Model(&subscriptiondomain.SubscriptionEvent{}).
+		Where("org_id = ? AND subscription_id = ?", orgID, subscriptionID)
+	if req.PageToken != "" {
+		cursor, err := pagination.DecodeCursor(req.PageToken)
+		if err == nil {
+			createdAt, createdErr := time.Parse(time.RFC3339Nano, cursor.CreatedAt)
+			id, idErr := snowflake.ParseString(cursor.ID)
+			if createdErr == nil && idErr == nil {
+				stmt = stmt.Where("(created_at > ? OR (created_at = ? AND id > ?))", createdAt, createdAt, id)
+			}
+		} else {
+			s.log.Warn("failed to decode subscription event cursor", zap.String("cursor", req.PageToken), zap.Error(err))
+		}
+	}
+	stmt = stmt.Limit(int(pageSize) + 1)
+
+	var events []*subscriptiondomain.SubscriptionEvent
+	if err := stmt.Order("created_at ASC, id ASC").Find(&events).Error; err != nil {
+		return subscriptiondomain.ListSubscriptionEventsResponse{}, err
+	}
+
+	pageInfo := pagination.BuildCursorPageInfo(events, pageSize, func(event *subscriptiondomain.SubscriptionEvent) string {
+		token, err := pagination.EncodeCursor(pagination.Cursor{
+			ID:        event.ID.String(),
+			CreatedAt: event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		})
+		if err != nil {
+			return ""
+		}
+		return token
+	})
+	if pageInfo != nil && pageInfo.HasMore && len(events) > int(pageSize) {
+		events = events[:pageSize]
+	}
+
+	items := make([]subscriptiondomain.SubscriptionEventResponse, 0, len(events))
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		items = append(items, subscriptiondomain.SubscriptionEventResponse{
+			ID:             event.ID,
+			SubscriptionID: event.SubscriptionID,
+			FromStatus:     event.FromStatus,
+			ToStatus:       event.ToStatus,
+			Reason:         event.Reason,
+			ActorType:      event.ActorType,
+			ActorID:        event.ActorID,
+			CreatedAt:      event.CreatedAt,
+		})
+	}
+
+	resp := subscriptiondomain.ListSubscriptionEventsResponse{
+		Events: items,
+	}
+	if pageInfo != nil {
+		resp.PageInfo = *pageInfo
+	}
+
+	return resp, nil
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	auditdomain "github.com/railzwaylabs/railzway/internal/audit/domain"
+	"github.com/railzwaylabs/railzway/internal/auditcontext"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TestListEvents_RecordsStatusChangesWithActor pauses, resumes and cancels a
+// subscription and reads the timeline back across pages.
+func TestListEvents_RecordsStatusChangesWithActor(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&subscriptiondomain.SubscriptionEvent{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	subID := node.Generate()
+	repo := &mockRepository{subscriptions: make(map[string]*subscriptiondomain.Subscription)}
+
+	svc := NewService(ServiceParam{
+		DB:               db,
+		Log:              zap.NewNop(),
+		GenID:            node,
+		Clock:            &mockClock{},
+		Repo:             repo,
+		Pricesvc:         &mockPriceService{},
+		PriceAmountsvc:   &mockPriceAmountService{},
+		PaymentMethodSvc: &mockPaymentMethodService{},
+	})
+
+	startAt := time.Now().UTC().Add(-24 * time.Hour)
+	require.NoError(t, repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+		ID:               subID,
+		OrgID:            orgID,
+		CustomerID:       node.Generate(),
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		BillingCycleType: "monthly",
+		StartAt:          startAt,
+		CreatedAt:        startAt,
+		UpdatedAt:        startAt,
+	}))
+
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	apiKeyCtx := auditcontext.WithActor(ctx, string(auditdomain.ActorTypeAPIKey), "key_1")
+	userCtx := auditcontext.WithActor(ctx, string(auditdomain.ActorTypeUser), "user_1")
+
+	require.NoError(t, svc.TransitionSubscription(apiKeyCtx, subID.String(), subscriptiondomain.SubscriptionStatusPaused, "subscription.pause"))
+	// Pausing twice changes nothing and records nothing.
+	require.NoError(t, svc.TransitionSubscription(apiKeyCtx, subID.String(), subscriptiondomain.SubscriptionStatusPaused, "subscription.pause"))
+	require.NoError(t, svc.TransitionSubscription(ctx, subID.String(), subscriptiondomain.SubscriptionStatusActive, ""))
+	require.NoError(t, svc.CancelSubscription(userCtx, subscriptiondomain.CancelSubscriptionRequest{
+		SubscriptionID: subID.String(),
+		CancelAt:       subscriptiondomain.CancelAtImmediate,
+	}))
+
+	first, err := svc.ListEvents(ctx, subscriptiondomain.ListSubscriptionEventsRequest{
+		SubscriptionID: subID.String(),
+		PageSize:       2,
+	})
+	require.NoError(t, err)
+	require.Len(t, first.Events, 2)
+	require.True(t, first.HasMore)
+
+	pause := first.Events[0]
+	assert.Equal(t, subscriptiondomain.SubscriptionStatusActive, pause.FromStatus)
+	assert.Equal(t, subscriptiondomain.SubscriptionStatusPaused, pause.ToStatus)
+	require.NotNil(t, pause.Reason)
+	assert.Equal(t, "subscription.pause", *pause.Reason)
+	assert.Equal(t, string(auditdomain.ActorTypeAPIKey), pause.ActorType)
+	require.NotNil(t, pause.ActorID)
+	assert.Equal(t, "key_1", *pause.ActorID)
+
+	resume := first.Events[1]
+	assert.Equal(t, subscriptiondomain.SubscriptionStatusPaused, resume.FromStatus)
+	assert.Equal(t, subscriptiondomain.SubscriptionStatusActive, resume.ToStatus)
+	assert.Nil(t, resume.Reason)
+	assert.Equal(t, string(auditdomain.ActorTypeSystem), resume.ActorType)
+	assert.Nil(t, resume.ActorID)
+
+	second, err := svc.ListEvents(ctx, subscriptiondomain.ListSubscriptionEventsRequest{
+		SubscriptionID: subID.String(),
+		PageToken:      first.NextPageToken,
+		PageSize:       2,
+	})
+	require.NoError(t, err)
+	require.Len(t, second.Events, 1)
+	assert.False(t, second.HasMore)
+
+	cancel := second.Events[0]
+	assert.Equal(t, subscriptiondomain.SubscriptionStatusCanceled, cancel.ToStatus)
+	require.NotNil(t, cancel.Reason)
+	assert.Equal(t, "subscription.cancel", *cancel.Reason)
+	assert.Equal(t, string(auditdomain.ActorTypeUser), cancel.ActorType)
+
+	_, err = svc.ListEvents(ctx, subscriptiondomain.ListSubscriptionEventsRequest{SubscriptionID: node.Generate().String()})
+	assert.ErrorIs(t, err, subscriptiondomain.ErrSubscriptionNotFound)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSubscriptionItems_PointsErrorsAtOffendingItem(t *testing.T) {
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	flat := func(interval pricedomain.BillingInterval) pricedomain.Response {
+		return pricedomain.Response{
+			ID:              node.Generate(),
+			OrganizationID:  orgID,
+			ProductID:       node.Generate(),
+			PricingModel:    pricedomain.Flat,
+			BillingMode:     pricedomain.Licensed,
+			BillingInterval: interval,
+			Active:          true,
+		}
+	}
+	monthly, otherMonthly := flat(pricedomain.Month), flat(pricedomain.Month)
+	weekly := flat(pricedomain.Week)
+	weekly.PricingModel = pricedomain.PerUnit
+	weekly.BillingMode = pricedomain.Metered
+
+	svc := &Service{
+		genID:          node,
+		clock:          &mockClock{},
+		pricesvc:       &mockPriceService{prices: []pricedomain.Response{monthly, otherMonthly, weekly}},
+		priceamountsvc: &currencyPriceAmounts{amounts: []priceamountdomain.Response{{ID: 1, Currency: "USD"}}},
+	}
+	build := func(currency string, prices ...pricedomain.Response) *subscriptiondomain.ItemError {
+		items := make([]subscriptiondomain.CreateSubscriptionItemRequest, 0, len(prices))
+		for _, price := range prices {
+			items = append(items, subscriptiondomain.CreateSubscriptionItemRequest{PriceID: price.ID.String(), Quantity: 1})
+		}
+		_, _, err := svc.buildSubscriptionItems(context.Background(), orgID, node.Generate(), items, "monthly", currency, time.Now().UTC())
+		var itemErr *subscriptiondomain.ItemError
+		require.True(t, errors.As(err, &itemErr), "expected ItemError, got %v", err)
+		return itemErr
+	}
+
+	itemErr := build("USD", monthly, weekly)
+	assert.Equal(t, 1, itemErr.Index)
+	assert.Equal(t, weekly.ID.String(), itemErr.PriceID)
+	assert.Equal(t, "items[1].price_id", itemErr.Field())
+	assert.ErrorIs(t, itemErr, subscriptiondomain.ErrInvalidBillingCycleType)
+
+	itemErr = build("USD", monthly, otherMonthly)
+	assert.Equal(t, 1, itemErr.Index)
+	assert.ErrorIs(t, itemErr, subscriptiondomain.ErrMultipleFlatPrices)
+
+	itemErr = build("EUR", monthly)
+	assert.Equal(t, 0, itemErr.Index)
+	assert.ErrorIs(t, itemErr, subscriptiondomain.ErrMissingPricing)
+	var currencyErr *subscriptiondomain.CurrencyNotPricedError
+	assert.True(t, errors.As(itemErr, &currencyErr))
+}
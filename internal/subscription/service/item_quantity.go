@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"gorm.io/gorm"
+)
+
+// UpdateItemQuantity changes the seat count of a licensed item in place.
+// Items that opted into create_prorations get a rating result for the seats
+// added or removed in the open billing cycle; metered items are rejected
+// because their quantity is not a seat count.
+func (s *Service) UpdateItemQuantity(ctx context.Context, req subscriptiondomain.UpdateItemQuantityRequest) (subscriptiondomain.UpdateItemQuantityResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return subscriptiondomain.UpdateItemQuantityResponse{}, subscriptiondomain.ErrInvalidOrganization
+	}
+	subscriptionID, err := s.parseID(req.SubscriptionID, subscriptiondomain.ErrInvalidSubscription)
+	if err != nil {
+		return subscriptiondomain.UpdateItemQuantityResponse{}, err
+	}
+	itemID, err := s.parseID(req.ItemID, subscriptiondomain.ErrInvalidItems)
+	if err != nil {
+		return subscriptiondomain.UpdateItemQuantityResponse{}, err
+	}
+	if req.Quantity < 1 {
+		return subscriptiondomain.UpdateItemQuantityResponse{}, subscriptiondomain.ErrInvalidQuantity
+	}
+
+	now := s.clock.Now(ctx).UTC()
+	var (
+		item     subscriptiondomain.SubscriptionItem
+		previous int32
+		result   *ratingdomain.RatingResult
+	)
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		subscription, err := s.repo.FindByIDForUpdate(ctx, tx, orgID, subscriptionID)
+		if err != nil {
+			return err
+		}
+		if subscription == nil {
+			return subscriptiondomain.ErrSubscriptionNotFound
+		}
+		if subscription.Status != subscriptiondomain.SubscriptionStatusActive {
+			return subscriptiondomain.ErrInvalidStatus
+		}
+
+		items, err := s.repo.ListItemsBySubscriptionID(ctx, tx, orgID, subscriptionID)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, candidate := range items {
+			if candidate.ID == itemID {
+				item, found = candidate, true
+				break
+			}
+		}
+		if !found {
+			return subscriptiondomain.ErrSubscriptionItemNotFound
+		}
+		if item.MeterID != nil || item.BillingMode != string(pricedomain.Licensed) {
+			return subscriptiondomain.ErrQuantityChangeNotAllowed
+		}
+
+		previous = normalizeSubscriptionQuantity(item.Quantity)
+		if previous == req.Quantity {
+			return nil
+		}
+
+		if isProratedItem(item) {
+			currency, err := s.resolveSubscriptionCurrency(ctx, tx, orgID, subscription.CustomerID, subscription.DefaultCurrency)
+			if err != nil {
+				return err
+			}
+			result, err = s.buildQuantityProration(ctx, tx, subscription, item, req.Quantity-previous, currency, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.WithContext(ctx).Exec(
+			`UPDATE subscription_items SET quantity = ?, updated_at = ? WHERE org_id = ? AND id = ?`,
+			req.Quantity,
+			now,
+			orgID,
+			item.ID,
+		).Error; err != nil {
+			return err
+		}
+		if result != nil {
+			if err := tx.WithContext(ctx).Create(result).Error; err != nil {
+				return err
+			}
+		}
+		return tx.WithContext(ctx).Exec(
+			`UPDATE subscriptions SET updated_at = ? WHERE org_id = ? AND id = ?`,
+			now,
+			orgID,
+			subscriptionID,
+		).Error
+	}); err != nil {
+		return subscriptiondomain.UpdateItemQuantityResponse{}, err
+	}
+
+	item.Quantity = req.Quantity
+	resp := subscriptiondomain.UpdateItemQuantityResponse{
+		SubscriptionID:   subscriptionID.String(),
+		Item:             toItemResponse(item),
+		PreviousQuantity: previous,
+		Prorations:       []subscriptiondomain.ProrationLine{},
+	}
+	if result != nil {
+		cycleID := result.BillingCycleID.String()
+		resp.BillingCycleID = &cycleID
+		resp.Prorations = append(resp.Prorations, subscriptiondomain.ProrationLine{
+			PriceID:     result.PriceID.String(),
+			Quantity:    result.Quantity,
+			UnitPrice:   result.UnitPrice,
+			Amount:      result.Amount,
+			PeriodStart: result.PeriodStart,
+			PeriodEnd:   result.PeriodEnd,
+		})
+		resp.NetAmount = result.Amount
+	}
+	return resp, nil
+}
+
+// buildQuantityProration prices delta seats of a licensed item for the part
+// of the open billing cycle the regular run bills at the wrong quantity:
+//   - once the advance invoice went out, it billed the old count, so the
+//     change is charged or credited for [at, cycle end)
+//   - otherwise the next run bills the new count for the whole cycle, so the
+//     time before the change is corrected for [cycle start, at)
+func (s *Service) buildQuantityProration(
+	ctx context.Context,
+	tx *gorm.DB,
+	subscription *subscriptiondomain.Subscription,
+	item subscriptiondomain.SubscriptionItem,
+	delta int32,
+	currency string,
+	at time.Time,
+) (*ratingdomain.RatingResult, error) {
+	cycle, err := s.loadProrationCycle(ctx, tx, subscription.OrgID, subscription.ID, at)
+	if err != nil {
+		return nil, err
+	}
+	if cycle == nil {
+		return nil, nil
+	}
+
+	start, end, sign := at, cycle.PeriodEnd, int64(1)
+	if cycle.AdvanceInvoicedAt == nil || !isAdvanceItem(item) {
+		start, end, sign = cycle.PeriodStart, at, -1
+		if subscription.StartAt.After(start) {
+			start = subscription.StartAt
+		}
+		if item.CreatedAt.After(start) {
+			start = item.CreatedAt
+		}
+	}
+	if !end.After(start) {
+		return nil, nil
+	}
+
+	priceAmounts, err := s.loadPriceAmount(ctx, item.PriceID.String(), currency)
+	if err != nil {
+		return nil, err
+	}
+	if len(priceAmounts) == 0 {
+		return nil, subscriptiondomain.ErrMissingPricing
+	}
+
+	unitPrice := priceAmounts[0].UnitAmountCents
+	cycleDuration := cycle.PeriodEnd.Sub(cycle.PeriodStart).Seconds()
+	seats := math.Abs(float64(delta))
+	quantity := seats * billingcycledomain.ProrationFactor(start, end, cycleDuration)
+	if delta < 0 {
+		sign = -sign
+	}
+
+	return &ratingdomain.RatingResult{
+		ID:             s.genID.Generate(),
+		OrgID:          subscription.OrgID,
+		SubscriptionID: subscription.ID,
+		BillingCycleID: cycle.ID,
+		BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
+		PriceID:        item.PriceID,
+		Quantity:       quantity,
+		UnitPrice:      unitPrice,
+		Amount:         sign * int64(math.Floor(float64(unitPrice)*quantity+0.5)),
+		Currency:       currency,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Source:         ratingdomain.RatingSourceProration,
+		Checksum:       buildQuantityProrationChecksum(cycle.ID.String(), item.ID.String(), delta, at),
+		CreatedAt:      at,
+	}, nil
+}
+
+func buildQuantityProrationChecksum(cycleID, itemID string, delta int32, at time.Time) string {
+	payload := fmt.Sprintf(
+		"proration_quantity|%s|%s|%d|%s",
+		cycleID,
+		itemID,
+		delta,
+		at.UTC().Format(time.RFC3339Nano),
+	)
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TestUpdateItemQuantity_ProratesSeatChanges changes the seats of an item
+// billed in advance with two thirds of the cycle left, so each change is
+// prorated over the rest of the cycle.
+func TestUpdateItemQuantity_ProratesSeatChanges(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&subscriptiondomain.SubscriptionItem{},
+		&billingcycledomain.BillingCycle{},
+		&ratingdomain.RatingResult{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	subID := node.Generate()
+	repo := &mockRepository{subscriptions: make(map[string]*subscriptiondomain.Subscription)}
+	svc := NewService(ServiceParam{
+		DB:               db,
+		Log:              zap.NewNop(),
+		GenID:            node,
+		Clock:            &mockClock{},
+		Repo:             repo,
+		Pricesvc:         &mockPriceService{},
+		PriceAmountsvc:   &mockPriceAmountService{},
+		PaymentMethodSvc: &mockPaymentMethodService{},
+	})
+
+	now := time.Now().UTC()
+	periodStart := now.Add(-10 * 24 * time.Hour)
+	periodEnd := now.Add(20 * 24 * time.Hour)
+	currency := "USD"
+	require.NoError(t, repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+		ID:               subID,
+		OrgID:            orgID,
+		CustomerID:       node.Generate(),
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		BillingCycleType: "monthly",
+		DefaultCurrency:  &currency,
+		StartAt:          periodStart,
+		CreatedAt:        periodStart,
+		UpdatedAt:        periodStart,
+	}))
+
+	advance := subscriptiondomain.UsageBehaviorAdvance
+	prorate := subscriptiondomain.ProrationBehaviorCreateProrations
+	seatItemID := node.Generate()
+	meterID := node.Generate()
+	meteredItemID := node.Generate()
+	require.NoError(t, db.Create([]subscriptiondomain.SubscriptionItem{
+		{
+			ID: seatItemID, OrgID: orgID, SubscriptionID: subID, PriceID: node.Generate(), Quantity: 2,
+			BillingMode: string(pricedomain.Licensed), UsageBehavior: &advance, ProrationBehavior: &prorate,
+			CreatedAt: periodStart, UpdatedAt: periodStart,
+		},
+		{
+			ID: meteredItemID, OrgID: orgID, SubscriptionID: subID, PriceID: node.Generate(), MeterID: &meterID,
+			BillingMode: string(pricedomain.Metered), CreatedAt: periodStart, UpdatedAt: periodStart,
+		},
+	}).Error)
+	cycleID := node.Generate()
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID:                cycleID,
+		OrgID:             orgID,
+		SubscriptionID:    subID,
+		PeriodStart:       periodStart,
+		PeriodEnd:         periodEnd,
+		Status:            billingcycledomain.BillingCycleStatusOpen,
+		AdvanceInvoicedAt: &periodStart,
+		CreatedAt:         periodStart,
+		UpdatedAt:         periodStart,
+	}).Error)
+
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	update := func(itemID snowflake.ID, quantity int32) (subscriptiondomain.UpdateItemQuantityResponse, error) {
+		return svc.UpdateItemQuantity(ctx, subscriptiondomain.UpdateItemQuantityRequest{
+			SubscriptionID: subID.String(),
+			ItemID:         itemID.String(),
+			Quantity:       quantity,
+		})
+	}
+
+	// Three added seats for two thirds of the cycle at 1000 per seat.
+	resp, err := update(seatItemID, 5)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), resp.PreviousQuantity)
+	assert.Equal(t, int32(5), resp.Item.Quantity)
+	require.NotNil(t, resp.BillingCycleID)
+	assert.Equal(t, cycleID.String(), *resp.BillingCycleID)
+	require.Len(t, resp.Prorations, 1)
+	assert.Equal(t, int64(2000), resp.NetAmount)
+
+	// Four removed seats are credited for the same remaining time.
+	resp, err = update(seatItemID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-2667), resp.NetAmount)
+
+	var stored subscriptiondomain.SubscriptionItem
+	require.NoError(t, db.First(&stored, "id = ?", seatItemID).Error)
+	assert.Equal(t, int32(1), stored.Quantity)
+
+	var prorations int64
+	require.NoError(t, db.Model(&ratingdomain.RatingResult{}).
+		Where("billing_cycle_id = ? AND source = ?", cycleID, ratingdomain.RatingSourceProration).
+		Count(&prorations).Error)
+	assert.Equal(t, int64(2), prorations)
+
+	// An unchanged count writes nothing.
+	resp, err = update(seatItemID, 1)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Prorations)
+
+	_, err = update(meteredItemID, 3)
+	assert.ErrorIs(t, err, subscriptiondomain.ErrQuantityChangeNotAllowed)
+
+	_, err = update(seatItemID, 0)
+	assert.ErrorIs(t, err, subscriptiondomain.ErrInvalidQuantity)
+
+	_, err = update(node.Generate(), 2)
+	assert.ErrorIs(t, err, subscriptiondomain.ErrSubscriptionItemNotFound)
+}
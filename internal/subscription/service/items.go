@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	priceamount "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/railzwaylabs/railzway/pkg/db/pagination"
+	"go.uber.org/zap"
+)
+
+// ListItems returns the items of a subscription in the order they were
+// added, each with the unit amount its price bills now in the subscription
+// currency. Pages move forward on (created_at, id).
+func (s *Service) ListItems(ctx context.Context, req subscriptiondomain.ListSubscriptionItemsRequest) (subscriptiondomain.ListSubscriptionItemsResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return subscriptiondomain.ListSubscriptionItemsResponse{}, subscriptiondomain.ErrInvalidOrganization
+	}
+
+	subscriptionID, err := s.parseID(req.SubscriptionID, subscriptiondomain.ErrInvalidSubscription)
+	if err != nil {
+		return subscriptiondomain.ListSubscriptionItemsResponse{}, err
+	}
+
+	subscription, err := s.repo.FindByID(ctx, s.db, orgID, subscriptionID)
+	if err != nil {
+		return subscriptiondomain.ListSubscriptionItemsResponse{}, err
+	}
+	if subscription == nil {
+		return subscriptiondomain.ListSubscriptionItemsResponse{}, subscriptiondomain.ErrSubscriptionNotFound
+	}
+
+	pageSize := pagination.ClampPageSize(req.PageSize)
+
+	items, err := s.repo.ListItemsBySubscriptionID(ctx, s.db, orgID, subscriptionID)
+	if err != nil {
+		return subscriptiondomain.ListSubscriptionItemsResponse{}, err
+	}
+
+	if req.PageToken != "" {
+		cursor, err := pagination.DecodeCursor(req.PageToken)
+		if err == nil {
+			createdAt, createdErr := time.Parse(time.RFC3339Nano, cursor.CreatedAt)
+			id, idErr := snowflake.ParseString(cursor.ID)
+			if createdErr == nil && idErr == nil {
+				remaining := items[:0]
+				for _, item := range items {
+					if item.CreatedAt.After(createdAt) || (item.CreatedAt.Equal(createdAt) && item.ID > id) {
+						remaining = append(remaining, item)
+					}
+				}
+				items = remaining
+			}
+		} else {
+			s.log.Warn("failed to decode subscription item cursor", zap.String("cursor", req.PageToken), zap.Error(err))
+		}
+	}
+
+	page := make([]*subscriptiondomain.SubscriptionItem, 0, len(items))
+	for i := range items {
+		page = append(page, &items[i])
+	}
+
+	pageInfo := pagination.BuildCursorPageInfo(page, pageSize, func(item *subscriptiondomain.SubscriptionItem) string {
+		token, err := pagination.EncodeCursor(pagination.Cursor{
+			ID:        item.ID.String(),
+			CreatedAt: item.CreatedAt.UTC().Format(time.RFC3339Nano),
+		})
+		if err != nil {
+			return ""
+		}
+		return token
+	})
+	if pageInfo != nil && pageInfo.HasMore && len(page) > int(pageSize) {
+		page = page[:pageSize]
+	}
+
+	currency, err := s.resolveSubscriptionCurrency(ctx, s.db, orgID, subscription.CustomerID, subscription.DefaultCurrency)
+	if err != nil {
+		return subscriptiondomain.ListSubscriptionItemsResponse{}, err
+	}
+
+	summaries := make([]subscriptiondomain.SubscriptionItemSummary, 0, len(page))
+	for _, item := range page {
+		amounts, err := s.loadPriceAmount(ctx, item.PriceID.String(), currency)
+		if err != nil {
+			return subscriptiondomain.ListSubscriptionItemsResponse{}, err
+		}
+		summary := subscriptiondomain.SubscriptionItemSummary{
+			CreateSubscriptionItemResponse: toItemResponse(*item),
+			Currency:                       currency,
+			CreatedAt:                      item.CreatedAt,
+		}
+		if amount := currentPriceAmount(amounts, item.MeterID, s.clock.Now(ctx)); amount != nil {
+			unitAmount := amount.UnitAmountCents
+			summary.UnitAmountCents = &unitAmount
+		}
+		summaries = append(summaries, summary)
+	}
+
+	resp := subscriptiondomain.ListSubscriptionItemsResponse{
+		Items: summaries,
+	}
+	if pageInfo != nil {
+		resp.PageInfo = *pageInfo
+	}
+
+	return resp, nil
+}
+
+// currentPriceAmount picks the amount in effect at now, preferring one bound
+// to the item's meter over the price's default amount.
+func currentPriceAmount(amounts []priceamount.Response, meterID *snowflake.ID, now time.Time) *priceamount.Response {
+	var current *priceamount.Response
+	for i := range amounts {
+		amount := &amounts[i]
+		if amount.EffectiveFrom.After(now) || amount.RevokedAt != nil {
+			continue
+		}
+		if amount.EffectiveTo != nil && !amount.EffectiveTo.After(now) {
+			continue
+		}
+		if amount.MeterID != nil && (meterID == nil || *amount.MeterID != *meterID) {
+			continue
+		}
+		if current == nil ||
+			(amount.MeterID != nil && current.MeterID == nil) ||
+			((amount.MeterID == nil) == (current.MeterID == nil) && amount.EffectiveFrom.After(current.EffectiveFrom)) {
+			current = amount
+		}
+	}
+	return current
+}
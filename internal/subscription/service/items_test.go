@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+)
+
+func TestCurrentPriceAmount_PrefersMeterAndLatestEffective(t *testing.T) {
+	now := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	meterID := snowflake.ID(10)
+	otherMeterID := snowflake.ID(11)
+	expired := now.Add(-time.Hour)
+	revoked := now.Add(-2 * time.Hour)
+
+	amounts := []priceamountdomain.Response{
+		{ID: 1, UnitAmountCents: 100, EffectiveFrom: now.AddDate(0, -2, 0)},
+		{ID: 2, UnitAmountCents: 200, EffectiveFrom: now.AddDate(0, -1, 0)},
+		{ID: 3, UnitAmountCents: 300, EffectiveFrom: now.AddDate(0, 0, 1)},
+		{ID: 4, UnitAmountCents: 400, EffectiveFrom: now.AddDate(0, -1, 0), EffectiveTo: &expired},
+		{ID: 5, UnitAmountCents: 500, EffectiveFrom: now.AddDate(0, -1, 0), RevokedAt: &revoked},
+		{ID: 6, UnitAmountCents: 600, EffectiveFrom: now.AddDate(0, -3, 0), MeterID: &otherMeterID},
+	}
+
+	current := currentPriceAmount(amounts, nil, now)
+	if current == nil || current.ID != 2 {
+		t.Fatalf("expected latest default amount 2, got %+v", current)
+	}
+
+	current = currentPriceAmount(amounts, &meterID, now)
+	if current == nil || current.ID != 2 {
+		t.Fatalf("expected default amount 2 without a meter amount, got %+v", current)
+	}
+
+	amounts = append(amounts, priceamountdomain.Response{ID: 7, UnitAmountCents: 700, EffectiveFrom: now.AddDate(0, -3, 0), MeterID: &meterID})
+	current = currentPriceAmount(amounts, &meterID, now)
+	if current == nil || current.ID != 7 {
+		t.Fatalf("expected meter amount 7, got %+v", current)
+	}
+
+	if current := currentPriceAmount(amounts[2:5], nil, now); current != nil {
+		t.Fatalf("expected no amount in effect, got %+v", current)
+	}
+}
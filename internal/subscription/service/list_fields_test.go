@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	subscriptionrepository "github.com/railzwaylabs/railzway/internal/subscription/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestList_LoadsOnlyRequestedFields(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&subscriptiondomain.Subscription{}))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	repo := subscriptionrepository.Provide()
+	svc := NewService(ServiceParam{
+		DB:               db,
+		Log:              zap.NewNop(),
+		GenID:            node,
+		Clock:            &mockClock{},
+		Repo:             repo,
+		Pricesvc:         &mockPriceService{},
+		PriceAmountsvc:   &mockPriceAmountService{},
+		PaymentMethodSvc: &mockPaymentMethodService{},
+	})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	currency := "USD"
+	for i := 0; i < 2; i++ {
+		require.NoError(t, repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+			ID:               node.Generate(),
+			OrgID:            orgID,
+			CustomerID:       node.Generate(),
+			Status:           subscriptiondomain.SubscriptionStatusActive,
+			CollectionMode:   subscriptiondomain.SubscriptionCollectionModeChargeAutomatically,
+			BillingCycleType: "monthly",
+			DefaultCurrency:  &currency,
+			StartAt:          start,
+			CreatedAt:        start.Add(time.Duration(i) * time.Hour),
+			UpdatedAt:        start,
+		}))
+	}
+
+	resp, err := svc.List(ctx, subscriptiondomain.ListSubscriptionRequest{Fields: []string{"Status", " customer_id "}})
+	require.NoError(t, err)
+	require.Len(t, resp.Subscriptions, 2)
+	for _, subscription := range resp.Subscriptions {
+		assert.NotZero(t, subscription.ID)
+		assert.NotZero(t, subscription.CustomerID)
+		assert.Equal(t, subscriptiondomain.SubscriptionStatusActive, subscription.Status)
+		assert.Nil(t, subscription.DefaultCurrency)
+		assert.Empty(t, subscription.BillingCycleType)
+	}
+
+	_, err = svc.List(ctx, subscriptiondomain.ListSubscriptionRequest{Fields: []string{"status", "id; DROP TABLE subscriptions"}})
+	assert.ErrorIs(t, err, subscriptiondomain.ErrInvalidFields)
+}
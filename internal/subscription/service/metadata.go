@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"gorm.io/datatypes"
+)
+
+// UpdateMetadata merges the requested keys into a subscription's metadata;
+// keys with a nil value are removed. The write only applies if updated_at is
+// unchanged since the row was read, so a concurrent writer surfaces as
+// ErrConcurrentUpdate instead of silently losing keys.
+func (s *Service) UpdateMetadata(ctx context.Context, req subscriptiondomain.UpdateMetadataRequest) (subscriptiondomain.Subscription, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return subscriptiondomain.Subscription{}, subscriptiondomain.ErrInvalidOrganization
+	}
+
+	subscriptionID, err := s.parseID(req.SubscriptionID, subscriptiondomain.ErrInvalidSubscription)
+	if err != nil {
+		return subscriptiondomain.Subscription{}, err
+	}
+	if len(req.Metadata) == 0 {
+		return subscriptiondomain.Subscription{}, subscriptiondomain.ErrInvalidMetadata
+	}
+	for key := range req.Metadata {
+		if strings.TrimSpace(key) == "" {
+			return subscriptiondomain.Subscription{}, subscriptiondomain.ErrInvalidMetadata
+		}
+	}
+
+	subscription, err := s.repo.FindByID(ctx, s.db, orgID, subscriptionID)
+	if err != nil {
+		return subscriptiondomain.Subscription{}, err
+	}
+	if subscription == nil {
+		return subscriptiondomain.Subscription{}, subscriptiondomain.ErrSubscriptionNotFound
+	}
+
+	if err := s.writeMetadata(ctx, subscription, req.Metadata); err != nil {
+		return subscriptiondomain.Subscription{}, err
+	}
+	return *subscription, nil
+}
+
+// writeMetadata applies updates on top of subscription.Metadata and stores
+// the result, conditional on the updated_at the caller read. On success the
+// subscription is updated in place.
+func (s *Service) writeMetadata(ctx context.Context, subscription *subscriptiondomain.Subscription, updates map[string]any) error {
+	metadata := datatypes.JSONMap{}
+	for key, value := range subscription.Metadata {
+		metadata[key] = value
+	}
+	for key, value := range updates {
+		if value == nil {
+			delete(metadata, key)
+			continue
+		}
+		metadata[key] = value
+	}
+
+	// The new timestamp must differ from the one being matched, otherwise a
+	// second writer in the same instant would pass the check too.
+	now := s.clock.Now(ctx).UTC()
+	if !now.After(subscription.UpdatedAt) {
+		now = subscription.UpdatedAt.Add(time.Microsecond)
+	}
+
+	result := s.db.WithContext(ctx).Exec(
+		`UPDATE subscriptions SET metadata = ?, updated_at = ? WHERE org_id = ? AND id = ? AND updated_at = ?`,
+		metadata,
+		now,
+		subscription.OrgID,
+		subscription.ID,
+		subscription.UpdatedAt,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return subscriptiondomain.ErrConcurrentUpdate
+	}
+
+	subscription.Metadata = metadata
+	subscription.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	subscriptionrepository "github.com/railzwaylabs/railzway/internal/subscription/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestUpdateMetadata_MergesAndDetectsConcurrentWriters(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&subscriptiondomain.Subscription{}))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	subID := node.Generate()
+	repo := subscriptionrepository.Provide()
+
+	svc := NewService(ServiceParam{
+		DB:               db,
+		Log:              zap.NewNop(),
+		GenID:            node,
+		Clock:            &mockClock{},
+		Repo:             repo,
+		Pricesvc:         &mockPriceService{},
+		PriceAmountsvc:   &mockPriceAmountService{},
+		PaymentMethodSvc: &mockPaymentMethodService{},
+	})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+		ID:               subID,
+		OrgID:            orgID,
+		CustomerID:       node.Generate(),
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		BillingCycleType: "monthly",
+		StartAt:          start,
+		Metadata:         datatypes.JSONMap{"crm_id": "A-1", "tier": "gold"},
+		CreatedAt:        start,
+		UpdatedAt:        start,
+	}))
+
+	stale, err := repo.FindByID(ctx, db, orgID, subID)
+	require.NoError(t, err)
+
+	updated, err := svc.UpdateMetadata(ctx, subscriptiondomain.UpdateMetadataRequest{
+		SubscriptionID: subID.String(),
+		Metadata:       map[string]any{"tier": nil, "region": "eu"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, datatypes.JSONMap{"crm_id": "A-1", "region": "eu"}, updated.Metadata)
+
+	stored, err := repo.FindByID(ctx, db, orgID, subID)
+	require.NoError(t, err)
+	assert.Equal(t, datatypes.JSONMap{"crm_id": "A-1", "region": "eu"}, stored.Metadata)
+	assert.True(t, stored.UpdatedAt.After(start))
+
+	// A writer that read the row before the update above loses the race.
+	err = svc.(*Service).writeMetadata(ctx, stale, map[string]any{"tier": "silver"})
+	assert.ErrorIs(t, err, subscriptiondomain.ErrConcurrentUpdate)
+
+	_, err = svc.UpdateMetadata(ctx, subscriptiondomain.UpdateMetadataRequest{SubscriptionID: subID.String()})
+	assert.ErrorIs(t, err, subscriptiondomain.ErrInvalidMetadata)
+
+	_, err = svc.UpdateMetadata(ctx, subscriptiondomain.UpdateMetadataRequest{
+		SubscriptionID: node.Generate().String(),
+		Metadata:       map[string]any{"region": "us"},
+	})
+	assert.ErrorIs(t, err, subscriptiondomain.ErrSubscriptionNotFound)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// TestMeteredOnlySubscription_Lifecycle activates a pay as you go
+// subscription that has no flat base price and walks it through pause,
+// resume and cancel.
+func TestMeteredOnlySubscription_Lifecycle(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&subscriptiondomain.SubscriptionItem{},
+		&subscriptiondomain.SubscriptionEvent{},
+		&pricedomain.Price{},
+		&customerdomain.Customer{},
+	))
+	require.NoError(t, db.Exec(`CREATE TABLE meters (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		code TEXT NOT NULL,
+		archived_at DATETIME
+	)`).Error)
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	meterID := node.Generate()
+	require.NoError(t, db.Exec(`INSERT INTO meters (id, org_id, code) VALUES (?, ?, ?)`, meterID, orgID, "api_calls").Error)
+
+	metered := func(model pricedomain.PricingModel) pricedomain.Price {
+		return pricedomain.Price{
+			ID:              node.Generate(),
+			OrgID:           orgID,
+			ProductID:       node.Generate(),
+			Code:            node.Generate().String(),
+			PricingModel:    model,
+			BillingMode:     pricedomain.Metered,
+			BillingInterval: pricedomain.Month,
+			Active:          true,
+		}
+	}
+	apiCalls, storage := metered(pricedomain.PerUnit), metered(pricedomain.PerUnit)
+	require.NoError(t, db.Create([]pricedomain.Price{apiCalls, storage}).Error)
+
+	toResponse := func(price pricedomain.Price) pricedomain.Response {
+		return pricedomain.Response{
+			ID:              price.ID,
+			OrganizationID:  price.OrgID,
+			ProductID:       price.ProductID,
+			PricingModel:    price.PricingModel,
+			BillingMode:     price.BillingMode,
+			BillingInterval: price.BillingInterval,
+			Active:          true,
+		}
+	}
+
+	customer := customerdomain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Acme", Email: "billing@acme.test", Currency: "USD", Metadata: datatypes.JSONMap{}}
+	require.NoError(t, db.Create(&customer).Error)
+
+	repo := &mockRepository{subscriptions: make(map[string]*subscriptiondomain.Subscription)}
+	svc := NewService(ServiceParam{
+		DB:       db,
+		Log:      zap.NewNop(),
+		GenID:    node,
+		Clock:    &mockClock{},
+		Repo:     repo,
+		Pricesvc: &mockPriceService{prices: []pricedomain.Response{toResponse(apiCalls), toResponse(storage)}},
+		PriceAmountsvc: &currencyPriceAmounts{amounts: []priceamountdomain.Response{
+			{ID: 1, PriceID: apiCalls.ID, MeterID: &meterID, Currency: "USD", UnitAmountCents: 2},
+		}},
+		PaymentMethodSvc: &mockPaymentMethodService{},
+	}).(*Service)
+
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	now := time.Now().UTC()
+	newDraft := func() *subscriptiondomain.Subscription {
+		sub := &subscriptiondomain.Subscription{
+			ID:               node.Generate(),
+			OrgID:            orgID,
+			CustomerID:       customer.ID,
+			Status:           subscriptiondomain.SubscriptionStatusDraft,
+			BillingCycleType: "monthly",
+			StartAt:          now,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		require.NoError(t, repo.Insert(ctx, db, sub))
+		return sub
+	}
+
+	sub := newDraft()
+	items, _, err := svc.buildSubscriptionItems(ctx, orgID, sub.ID, []subscriptiondomain.CreateSubscriptionItemRequest{
+		{PriceID: apiCalls.ID.String()},
+		{PriceID: apiCalls.ID.String(), DimensionKey: "region", DimensionValue: "eu"},
+	}, "monthly", "USD", now)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	for _, item := range items {
+		require.NotNil(t, item.MeterID)
+		assert.Equal(t, meterID, *item.MeterID)
+	}
+	require.NoError(t, repo.InsertItems(ctx, db, items))
+
+	transition := func(id snowflake.ID, status subscriptiondomain.SubscriptionStatus) error {
+		return svc.TransitionSubscription(ctx, id.String(), status, "")
+	}
+	require.NoError(t, transition(sub.ID, subscriptiondomain.SubscriptionStatusActive))
+	require.NoError(t, transition(sub.ID, subscriptiondomain.SubscriptionStatusPaused))
+	require.NoError(t, transition(sub.ID, subscriptiondomain.SubscriptionStatusActive))
+	require.NoError(t, transition(sub.ID, subscriptiondomain.SubscriptionStatusCanceled))
+
+	var stored subscriptiondomain.Subscription
+	require.NoError(t, db.First(&stored, "id = ?", sub.ID).Error)
+	assert.Equal(t, subscriptiondomain.SubscriptionStatusCanceled, stored.Status)
+	assert.NotNil(t, stored.ActivatedAt)
+	assert.NotNil(t, stored.ResumedAt)
+
+	// A usage priced item without a meter could never be rated.
+	unmetered := newDraft()
+	require.NoError(t, repo.InsertItems(ctx, db, []subscriptiondomain.SubscriptionItem{{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		SubscriptionID: unmetered.ID,
+		PriceID:        storage.ID,
+		Quantity:       1,
+		BillingMode:    string(pricedomain.Metered),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}}))
+	assert.ErrorIs(t, transition(unmetered.ID, subscriptiondomain.SubscriptionStatusActive), subscriptiondomain.ErrInvalidMeterID)
+
+	// Archived meters cannot be bound to new items.
+	require.NoError(t, db.Exec(`UPDATE meters SET archived_at = ? WHERE id = ?`, now, meterID).Error)
+	_, _, err = svc.buildSubscriptionItems(ctx, orgID, newDraft().ID, []subscriptiondomain.CreateSubscriptionItemRequest{
+		{PriceID: apiCalls.ID.String()},
+	}, "monthly", "USD", now)
+	assert.ErrorIs(t, err, subscriptiondomain.ErrMeterArchived)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamount "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+)
+
+// getPrice loads a price through the shared price cache.
+func (s *Service) getPrice(ctx context.Context, priceID string) (*pricedomain.Response, error) {
+	orgID, id, ok := s.priceCacheKey(ctx, priceID)
+	if !ok {
+		return s.pricesvc.Get(ctx, priceID)
+	}
+	if cached, hit := s.priceCache.GetPrice(ctx, orgID, id); hit {
+		return cached, nil
+	}
+
+	loaded, err := s.pricesvc.Get(ctx, priceID)
+	if err != nil {
+		return nil, err
+	}
+	s.priceCache.SetPrice(ctx, orgID, id, loaded)
+	return loaded, nil
+}
+
+// listPriceAmounts returns every amount of the price in currency, scheduled
+// ones included, so a cached list stays valid as amounts take effect.
+func (s *Service) listPriceAmounts(ctx context.Context, priceID string, currency string) ([]priceamount.Response, error) {
+	orgID, id, ok := s.priceCacheKey(ctx, priceID)
+	if ok {
+		if cached, hit := s.priceCache.GetAmounts(ctx, orgID, id, currency); hit {
+			return cached, nil
+		}
+	}
+
+	resp, err := s.priceamountsvc.List(ctx, priceamount.ListPriceAmountRequest{
+		PriceID:  priceID,
+		Currency: currency,
+		PageSize: -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		s.priceCache.SetAmounts(ctx, orgID, id, currency, resp.Amounts)
+	}
+	return resp.Amounts, nil
+}
+
+func (s *Service) priceCacheKey(ctx context.Context, priceID string) (snowflake.ID, snowflake.ID, bool) {
+	if s.priceCache == nil {
+		return 0, 0, false
+	}
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return 0, 0, false
+	}
+	id, err := snowflake.ParseString(strings.TrimSpace(priceID))
+	if err != nil {
+		return 0, 0, false
+	}
+	return orgID, id, true
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"gorm.io/gorm"
+)
+
+// prorationCycle is the open billing cycle an item replacement falls into.
+type prorationCycle struct {
+	ID                snowflake.ID
+	PeriodStart       time.Time
+	PeriodEnd         time.Time
+	AdvanceInvoicedAt *time.Time
+}
+
+// buildReplaceProrations prices the flat items that an item replacement adds
+// or removes in the middle of an open billing cycle. Rows land in the arrears
+// phase so they are invoiced when the cycle closes:
+//   - a removed item already billed in advance is credited for [at, cycle end)
+//   - a removed item not billed yet is charged for the time it was active,
+//     since the closing rating run only sees current items
+//   - an added advance item is charged for [at, cycle end) once the advance
+//     invoice went out; otherwise the regular rating run covers it
+//
+// Only items with the create_prorations behavior are prorated.
+func (s *Service) buildReplaceProrations(
+	ctx context.Context,
+	tx *gorm.DB,
+	subscription *subscriptiondomain.Subscription,
+	current []subscriptiondomain.SubscriptionItem,
+	next []subscriptiondomain.SubscriptionItem,
+	currency string,
+	at time.Time,
+) ([]ratingdomain.RatingResult, error) {
+	currentPrices := make(map[snowflake.ID]struct{}, len(current))
+	for _, item := range current {
+		currentPrices[item.PriceID] = struct{}{}
+	}
+	nextPrices := make(map[snowflake.ID]struct{}, len(next))
+	for _, item := range next {
+		nextPrices[item.PriceID] = struct{}{}
+	}
+
+	var removed, added []subscriptiondomain.SubscriptionItem
+	for _, item := range current {
+		if _, ok := nextPrices[item.PriceID]; !ok && isProratedItem(item) {
+			removed = append(removed, item)
+		}
+	}
+	for _, item := range next {
+		if _, ok := currentPrices[item.PriceID]; !ok && isProratedItem(item) && isAdvanceItem(item) {
+			added = append(added, item)
+		}
+	}
+	if len(removed) == 0 && len(added) == 0 {
+		return nil, nil
+	}
+
+	cycle, err := s.loadProrationCycle(ctx, tx, subscription.OrgID, subscription.ID, at)
+	if err != nil {
+		return nil, err
+	}
+	if cycle == nil {
+		return nil, nil
+	}
+	cycleDuration := cycle.PeriodEnd.Sub(cycle.PeriodStart).Seconds()
+	advanceBilled := cycle.AdvanceInvoicedAt != nil
+
+	results := make([]ratingdomain.RatingResult, 0, len(removed)+len(added))
+	appendResult := func(item subscriptiondomain.SubscriptionItem, start, end time.Time, sign int64) error {
+		if !end.After(start) {
+			return nil
+		}
+		priceAmounts, err := s.loadPriceAmount(ctx, item.PriceID.String(), currency)
+		if err != nil {
+			return err
+		}
+		if len(priceAmounts) == 0 {
+			return subscriptiondomain.ErrMissingPricing
+		}
+
+		unitPrice := priceAmounts[0].UnitAmountCents
+		quantity := float64(normalizeSubscriptionQuantity(item.Quantity)) *
+			billingcycledomain.ProrationFactor(start, end, cycleDuration)
+		results = append(results, ratingdomain.RatingResult{
+			ID:             s.genID.Generate(),
+			OrgID:          subscription.OrgID,
+			SubscriptionID: subscription.ID,
+			BillingCycleID: cycle.ID,
+			BillingPhase:   string(billingcycledomain.BillingPhaseArrears),
+			PriceID:        item.PriceID,
+			Quantity:       quantity,
+			UnitPrice:      unitPrice,
+			Amount:         sign * int64(math.Floor(float64(unitPrice)*quantity+0.5)),
+			Currency:       currency,
+			PeriodStart:    start,
+			PeriodEnd:      end,
+			Source:         ratingdomain.RatingSourceProration,
+			Checksum:       buildProrationChecksum(cycle.ID, item.ID, start, end),
+			CreatedAt:      at,
+		})
+		return nil
+	}
+
+	for _, item := range removed {
+		if advanceBilled && isAdvanceItem(item) {
+			if err := appendResult(item, at, cycle.PeriodEnd, -1); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		start := cycle.PeriodStart
+		if subscription.StartAt.After(start) {
+			start = subscription.StartAt
+		}
+		if item.CreatedAt.After(start) {
+			start = item.CreatedAt
+		}
+		if err := appendResult(item, start, at, 1); err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range added {
+		if !advanceBilled {
+			break
+		}
+		if err := appendResult(item, at, cycle.PeriodEnd, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// PreviewProration prices the prorations a ReplaceItems or ChangePlan call
+// would add to the open billing cycle right now, without persisting
+// anything.
+func (s *Service) PreviewProration(ctx context.Context, req subscriptiondomain.PreviewProrationRequest) (subscriptiondomain.PreviewProrationResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return subscriptiondomain.PreviewProrationResponse{}, subscriptiondomain.ErrInvalidOrganization
+	}
+	subscriptionID, err := s.parseID(req.SubscriptionID, subscriptiondomain.ErrInvalidSubscription)
+	if err != nil {
+		return subscriptiondomain.PreviewProrationResponse{}, err
+	}
+	planChange := strings.TrimSpace(req.NewPriceID) != "" || strings.TrimSpace(req.NewProductID) != ""
+	if len(req.Items) == 0 && !planChange {
+		return subscriptiondomain.PreviewProrationResponse{}, subscriptiondomain.ErrInvalidItems
+	}
+	if len(req.Items) > 0 && planChange {
+		return subscriptiondomain.PreviewProrationResponse{}, subscriptiondomain.ErrInvalidItems
+	}
+
+	subscription, err := s.repo.FindByID(ctx, s.db, orgID, subscriptionID)
+	if err != nil {
+		return subscriptiondomain.PreviewProrationResponse{}, err
+	}
+	if subscription == nil {
+		return subscriptiondomain.PreviewProrationResponse{}, subscriptiondomain.ErrSubscriptionNotFound
+	}
+	if subscription.Status != subscriptiondomain.SubscriptionStatusActive {
+		return subscriptiondomain.PreviewProrationResponse{}, subscriptiondomain.ErrInvalidStatus
+	}
+
+	now := s.clock.Now(ctx).UTC()
+	currency, err := s.resolveSubscriptionCurrency(ctx, s.db, orgID, subscription.CustomerID, subscription.DefaultCurrency)
+	if err != nil {
+		return subscriptiondomain.PreviewProrationResponse{}, err
+	}
+
+	var current, next []subscriptiondomain.SubscriptionItem
+	if planChange {
+		next, current, _, err = s.buildPlanChangeItems(ctx, s.db, subscription, subscriptiondomain.ChangePlanRequest{
+			SubscriptionID:    req.SubscriptionID,
+			NewPriceID:        req.NewPriceID,
+			NewProductID:      req.NewProductID,
+			ProrationBehavior: req.ProrationBehavior,
+		}, currency, now)
+		if err != nil {
+			return subscriptiondomain.PreviewProrationResponse{}, err
+		}
+	} else {
+		var productIDs []snowflake.ID
+		next, productIDs, err = s.buildSubscriptionItems(ctx, orgID, subscriptionID, req.Items, subscription.BillingCycleType, currency, now)
+		if err != nil {
+			return subscriptiondomain.PreviewProrationResponse{}, err
+		}
+		if err := s.ensureProductsActive(ctx, s.db, orgID, productIDs); err != nil {
+			return subscriptiondomain.PreviewProrationResponse{}, err
+		}
+		current, err = s.repo.ListItemsBySubscriptionID(ctx, s.db, orgID, subscriptionID)
+		if err != nil {
+			return subscriptiondomain.PreviewProrationResponse{}, err
+		}
+	}
+
+	prorations, err := s.buildReplaceProrations(ctx, s.db, subscription, current, next, currency, now)
+	if err != nil {
+		return subscriptiondomain.PreviewProrationResponse{}, err
+	}
+
+	resp := subscriptiondomain.PreviewProrationResponse{
+		SubscriptionID: subscriptionID.String(),
+		Currency:       currency,
+		Credits:        []subscriptiondomain.ProrationLine{},
+		Charges:        []subscriptiondomain.ProrationLine{},
+		ProratedAt:     now,
+	}
+	for _, result := range prorations {
+		if resp.BillingCycleID == nil {
+			cycleID := result.BillingCycleID.String()
+			resp.BillingCycleID = &cycleID
+		}
+		line := subscriptiondomain.ProrationLine{
+			PriceID:     result.PriceID.String(),
+			Quantity:    result.Quantity,
+			UnitPrice:   result.UnitPrice,
+			Amount:      result.Amount,
+			PeriodStart: result.PeriodStart,
+			PeriodEnd:   result.PeriodEnd,
+		}
+		if result.Amount < 0 {
+			resp.Credits = append(resp.Credits, line)
+		} else {
+			resp.Charges = append(resp.Charges, line)
+		}
+		resp.NetAmount += result.Amount
+	}
+	return resp, nil
+}
+
+func (s *Service) loadProrationCycle(ctx context.Context, tx *gorm.DB, orgID, subscriptionID snowflake.ID, at time.Time) (*prorationCycle, error) {
+	var cycles []prorationCycle
+	if err := tx.WithContext(ctx).Raw(
+		`SELECT id, period_start, period_end, advance_invoiced_at
+		 FROM billing_cycles
+		 WHERE org_id = ? AND subscription_id = ? AND status = ?
+		 AND period_start <= ? AND period_end > ?
+		 ORDER BY period_start DESC
+		 LIMIT 1`,
+		orgID,
+		subscriptionID,
+		billingcycledomain.BillingCycleStatusOpen,
+		at,
+		at,
+	).Scan(&cycles).Error; err != nil {
+		return nil, err
+	}
+	if len(cycles) == 0 {
+		return nil, nil
+	}
+	return &cycles[0], nil
+}
+
+// isProratedItem reports whether a flat licensed item opted into proration.
+func isProratedItem(item subscriptiondomain.SubscriptionItem) bool {
+	if item.MeterID != nil || item.BillingMode != string(pricedomain.Licensed) {
+		return false
+	}
+	return item.ProrationBehavior != nil && *item.ProrationBehavior == subscriptiondomain.ProrationBehaviorCreateProrations
+}
+
+func isAdvanceItem(item subscriptiondomain.SubscriptionItem) bool {
+	return item.UsageBehavior != nil && *item.UsageBehavior == subscriptiondomain.UsageBehaviorAdvance
+}
+
+func buildProrationChecksum(cycleID, itemID snowflake.ID, start, end time.Time) string {
+	payload := fmt.Sprintf(
+		"proration|%s|%s|%s|%s",
+		cycleID.String(),
+		itemID.String(),
+		start.UTC().Format(time.RFC3339Nano),
+		end.UTC().Format(time.RFC3339Nano),
+	)
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	ratingdomain "github.com/railzwaylabs/railzway/internal/rating/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TestReplaceItems_ProratesAdvanceFlatItems replaces an advance-billed flat
+// item two thirds of the way before the cycle ends and expects a credit for
+// the old item and a charge for the new one.
+func TestReplaceItems_ProratesAdvanceFlatItems(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&subscriptiondomain.SubscriptionItem{},
+		&subscriptiondomain.SubscriptionEntitlement{},
+		&billingcycledomain.BillingCycle{},
+		&ratingdomain.RatingResult{},
+	))
+	require.NoError(t, db.Exec(`CREATE TABLE products (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		active BOOLEAN NOT NULL
+	)`).Error)
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	subID := node.Generate()
+	productID := node.Generate()
+	oldPriceID := node.Generate()
+	newPriceID := node.Generate()
+	meteredPriceID := node.Generate()
+
+	require.NoError(t, db.Exec(`INSERT INTO products (id, org_id, active) VALUES (?, ?, true)`, productID, orgID).Error)
+
+	priceSvc := &mockPriceService{prices: []pricedomain.Response{
+		{ID: oldPriceID, OrganizationID: orgID, ProductID: productID, BillingInterval: pricedomain.Month, Active: true, PricingModel: pricedomain.Flat, BillingMode: pricedomain.Licensed},
+		{ID: newPriceID, OrganizationID: orgID, ProductID: productID, BillingInterval: pricedomain.Month, Active: true, PricingModel: pricedomain.Flat, BillingMode: pricedomain.Licensed},
+		{ID: meteredPriceID, OrganizationID: orgID, ProductID: productID, BillingInterval: pricedomain.Month, Active: true, PricingModel: pricedomain.PerUnit, BillingMode: pricedomain.Metered},
+	}}
+	repo := &mockRepository{subscriptions: make(map[string]*subscriptiondomain.Subscription)}
+
+	svc := NewService(ServiceParam{
+		DB:                 db,
+		Log:                zap.NewNop(),
+		GenID:              node,
+		Clock:              &mockClock{},
+		Repo:               repo,
+		Pricesvc:           priceSvc,
+		PriceAmountsvc:     &mockPriceAmountService{},
+		ProductFeatureRepo: &mockProductFeatureRepo{},
+		PaymentMethodSvc:   &mockPaymentMethodService{},
+	})
+
+	now := time.Now().UTC()
+	periodStart := now.Add(-10 * 24 * time.Hour)
+	periodEnd := now.Add(20 * 24 * time.Hour)
+	currency := "USD"
+	require.NoError(t, repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+		ID:               subID,
+		OrgID:            orgID,
+		CustomerID:       node.Generate(),
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		BillingCycleType: "monthly",
+		DefaultCurrency:  &currency,
+		StartAt:          periodStart,
+		CreatedAt:        periodStart,
+		UpdatedAt:        periodStart,
+	}))
+
+	advance := subscriptiondomain.UsageBehaviorAdvance
+	prorate := subscriptiondomain.ProrationBehaviorCreateProrations
+	oldItemID := node.Generate()
+	require.NoError(t, db.Create(&subscriptiondomain.SubscriptionItem{
+		ID:                oldItemID,
+		OrgID:             orgID,
+		SubscriptionID:    subID,
+		PriceID:           oldPriceID,
+		Quantity:          1,
+		BillingMode:       string(pricedomain.Licensed),
+		UsageBehavior:     &advance,
+		ProrationBehavior: &prorate,
+		CreatedAt:         periodStart,
+		UpdatedAt:         periodStart,
+	}).Error)
+
+	cycleID := node.Generate()
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID:                cycleID,
+		OrgID:             orgID,
+		SubscriptionID:    subID,
+		PeriodStart:       periodStart,
+		PeriodEnd:         periodEnd,
+		Status:            billingcycledomain.BillingCycleStatusOpen,
+		AdvanceInvoicedAt: &periodStart,
+		CreatedAt:         periodStart,
+		UpdatedAt:         periodStart,
+	}).Error)
+
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	_, err = svc.ReplaceItems(ctx, subscriptiondomain.ReplaceSubscriptionItemsRequest{
+		SubscriptionID: subID.String(),
+		Items: []subscriptiondomain.CreateSubscriptionItemRequest{{
+			PriceID:           meteredPriceID.String(),
+			ProrationBehavior: subscriptiondomain.ProrationBehaviorCreateProrations,
+		}},
+	})
+	assert.ErrorIs(t, err, subscriptiondomain.ErrInvalidProrationBehavior)
+
+	newItems := []subscriptiondomain.CreateSubscriptionItemRequest{{
+		PriceID:           newPriceID.String(),
+		UsageBehavior:     subscriptiondomain.UsageBehaviorAdvance,
+		ProrationBehavior: subscriptiondomain.ProrationBehaviorCreateProrations,
+	}}
+
+	// The preview prices the same lines without writing them.
+	preview, err := svc.PreviewProration(ctx, subscriptiondomain.PreviewProrationRequest{
+		SubscriptionID: subID.String(),
+		Items:          newItems,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, preview.BillingCycleID)
+	assert.Equal(t, cycleID.String(), *preview.BillingCycleID)
+	assert.Equal(t, "USD", preview.Currency)
+	require.Len(t, preview.Credits, 1)
+	require.Len(t, preview.Charges, 1)
+	assert.Equal(t, oldPriceID.String(), preview.Credits[0].PriceID)
+	assert.Equal(t, int64(-667), preview.Credits[0].Amount)
+	assert.Equal(t, newPriceID.String(), preview.Charges[0].PriceID)
+	assert.Equal(t, int64(667), preview.Charges[0].Amount)
+	assert.Equal(t, int64(0), preview.NetAmount)
+
+	var previewed int64
+	require.NoError(t, db.Model(&ratingdomain.RatingResult{}).Where("billing_cycle_id = ?", cycleID).Count(&previewed).Error)
+	assert.Zero(t, previewed)
+
+	_, err = svc.PreviewProration(ctx, subscriptiondomain.PreviewProrationRequest{
+		SubscriptionID: subID.String(),
+		Items:          newItems,
+		NewPriceID:     newPriceID.String(),
+	})
+	assert.ErrorIs(t, err, subscriptiondomain.ErrInvalidItems)
+
+	_, err = svc.ReplaceItems(ctx, subscriptiondomain.ReplaceSubscriptionItemsRequest{
+		SubscriptionID: subID.String(),
+		Items:          newItems,
+	})
+	require.NoError(t, err)
+
+	var results []ratingdomain.RatingResult
+	require.NoError(t, db.Where("billing_cycle_id = ?", cycleID).Order("amount ASC").Find(&results).Error)
+	require.Len(t, results, 2)
+
+	credit := results[0]
+	assert.Equal(t, oldPriceID, credit.PriceID)
+	assert.Equal(t, int64(-667), credit.Amount)
+	assert.Equal(t, ratingdomain.RatingSourceProration, credit.Source)
+	assert.Equal(t, string(billingcycledomain.BillingPhaseArrears), credit.BillingPhase)
+	assert.True(t, credit.PeriodEnd.Equal(periodEnd))
+
+	charge := results[1]
+	assert.Equal(t, newPriceID, charge.PriceID)
+	assert.Equal(t, int64(667), charge.Amount)
+	assert.Equal(t, ratingdomain.RatingSourceProration, charge.Source)
+	assert.True(t, charge.PeriodStart.Equal(credit.PeriodStart))
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	ratingrepository "github.com/railzwaylabs/railzway/internal/rating/repository"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+)
+
+// GetSeatUsage compares the seats counted by each licensed item's seat meter
+// in the current billing cycle with the item's licensed quantity. Before the
+// first cycle opens seats count from the subscription start.
+func (s *Service) GetSeatUsage(ctx context.Context, subscriptionID string) (subscriptiondomain.SeatUsageResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return subscriptiondomain.SeatUsageResponse{}, subscriptiondomain.ErrInvalidOrganization
+	}
+
+	id, err := s.parseID(subscriptionID, subscriptiondomain.ErrInvalidSubscription)
+	if err != nil {
+		return subscriptiondomain.SeatUsageResponse{}, err
+	}
+
+	subscription, err := s.repo.FindByID(ctx, s.db, orgID, id)
+	if err != nil {
+		return subscriptiondomain.SeatUsageResponse{}, err
+	}
+	if subscription == nil {
+		return subscriptiondomain.SeatUsageResponse{}, subscriptiondomain.ErrSubscriptionNotFound
+	}
+
+	at := s.clock.Now(ctx).UTC()
+	resp := subscriptiondomain.SeatUsageResponse{
+		SubscriptionID: subscription.ID,
+		At:             at,
+		Items:          []subscriptiondomain.SeatUsage{},
+	}
+
+	items, err := s.repo.ListItemsBySubscriptionID(ctx, s.db, orgID, subscription.ID)
+	if err != nil {
+		return subscriptiondomain.SeatUsageResponse{}, err
+	}
+
+	var periodStart *time.Time
+	ratingRepo := ratingrepository.NewRepository(s.db)
+	for _, item := range items {
+		if item.SeatMeterID == nil {
+			continue
+		}
+		if periodStart == nil {
+			periodStart, err = s.billingCycleStartAt(ctx, subscription, at)
+			if err != nil {
+				return subscriptiondomain.SeatUsageResponse{}, err
+			}
+			if periodStart == nil {
+				periodStart = &subscription.StartAt
+			}
+		}
+
+		used := 0.0
+		if at.After(*periodStart) {
+			used, err = ratingRepo.AggregateUsage(ctx, orgID, subscription.ID, *item.SeatMeterID, *periodStart, at, nil)
+			if err != nil {
+				return subscriptiondomain.SeatUsageResponse{}, err
+			}
+		}
+
+		overage := subscriptiondomain.SeatOverage(used, item.Quantity)
+		resp.Items = append(resp.Items, subscriptiondomain.SeatUsage{
+			SubscriptionItemID: item.ID,
+			SeatMeterID:        *item.SeatMeterID,
+			OveragePriceID:     item.OveragePriceID,
+			PeriodStart:        *periodStart,
+			LicensedQuantity:   item.Quantity,
+			UsedQuantity:       used,
+			OverageQuantity:    overage,
+			Overage:            overage > 0,
+		})
+	}
+
+	return resp, nil
+}
+
+// normalizeSeatOverage validates the optional seat meter and overage price
+// of an item. Seats are only counted for flat licensed prices, and the
+// overage is billed per seat in the subscription currency.
+func (s *Service) normalizeSeatOverage(
+	ctx context.Context,
+	orgID snowflake.ID,
+	price *pricedomain.Response,
+	item subscriptiondomain.CreateSubscriptionItemRequest,
+	currency string,
+	priceCache map[string]*pricedomain.Response,
+) (*snowflake.ID, *snowflake.ID, error) {
+	seatMeter := strings.TrimSpace(item.SeatMeterID)
+	overagePrice := strings.TrimSpace(item.OveragePriceID)
+	if seatMeter == "" {
+		if overagePrice != "" {
+			return nil, nil, subscriptiondomain.ErrInvalidOveragePrice
+		}
+		return nil, nil, nil
+	}
+
+	if price.PricingModel != pricedomain.Flat || price.BillingMode != pricedomain.Licensed {
+		return nil, nil, subscriptiondomain.ErrInvalidSeatMeter
+	}
+	seatMeterID, err := s.parseID(seatMeter, subscriptiondomain.ErrInvalidSeatMeter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var meters []struct {
+		ArchivedAt *time.Time `gorm:"column:archived_at"`
+	}
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT archived_at FROM meters WHERE org_id = ? AND id = ? LIMIT 1`,
+		orgID,
+		seatMeterID,
+	).Scan(&meters).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(meters) == 0 || meters[0].ArchivedAt != nil {
+		return nil, nil, subscriptiondomain.ErrInvalidSeatMeter
+	}
+
+	if overagePrice == "" {
+		return &seatMeterID, nil, nil
+	}
+
+	overage, err := s.loadPrice(ctx, overagePrice, priceCache)
+	if err != nil {
+		if errors.Is(err, pricedomain.ErrNotFound) || errors.Is(err, pricedomain.ErrInvalidID) || errors.Is(err, subscriptiondomain.ErrInvalidPrice) {
+			return nil, nil, subscriptiondomain.ErrInvalidOveragePrice
+		}
+		return nil, nil, err
+	}
+	if overage == nil || overage.PricingModel != pricedomain.PerUnit {
+		return nil, nil, subscriptiondomain.ErrInvalidOveragePrice
+	}
+	amounts, err := s.loadPriceAmount(ctx, overage.ID.String(), currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(amounts) == 0 {
+		return nil, nil, subscriptiondomain.ErrInvalidOveragePrice
+	}
+
+	overagePriceID := overage.ID
+	return &seatMeterID, &overagePriceID, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	subscriptionrepository "github.com/railzwaylabs/railzway/internal/subscription/repository"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestGetSeatUsage_FlagsSeatsAboveLicensedQuantity(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&subscriptiondomain.SubscriptionItem{},
+		&billingcycledomain.BillingCycle{},
+		&meterdomain.Meter{},
+		&usagedomain.UsageEvent{},
+		&usagedomain.UsageRollup{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	customerID := node.Generate()
+	subID := node.Generate()
+	meterID := node.Generate()
+	overagePriceID := node.Generate()
+	repo := subscriptionrepository.Provide()
+
+	svc := NewService(ServiceParam{
+		DB:               db,
+		Log:              zap.NewNop(),
+		GenID:            node,
+		Clock:            &mockClock{},
+		Repo:             repo,
+		Pricesvc:         &mockPriceService{},
+		PriceAmountsvc:   &mockPriceAmountService{},
+		PaymentMethodSvc: &mockPaymentMethodService{},
+	})
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+
+	cycleStart := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Hour)
+	require.NoError(t, repo.Insert(context.Background(), db, &subscriptiondomain.Subscription{
+		ID:               subID,
+		OrgID:            orgID,
+		CustomerID:       customerID,
+		Status:           subscriptiondomain.SubscriptionStatusActive,
+		BillingCycleType: "monthly",
+		StartAt:          cycleStart.AddDate(0, -1, 0),
+		CreatedAt:        cycleStart,
+		UpdatedAt:        cycleStart,
+	}))
+	require.NoError(t, db.Create(&meterdomain.Meter{
+		ID: meterID, OrgID: orgID, Code: "active_users", Name: "Active Users",
+		Aggregation: meterdomain.AggregationUniqueCount, Unit: "user", ResetInterval: meterdomain.ResetIntervalBillingCycle, Active: true,
+	}).Error)
+	require.NoError(t, db.Create([]subscriptiondomain.SubscriptionItem{
+		{ID: node.Generate(), OrgID: orgID, SubscriptionID: subID, PriceID: node.Generate(), Quantity: 3,
+			BillingMode: "LICENSED", SeatMeterID: &meterID, OveragePriceID: &overagePriceID, CreatedAt: cycleStart},
+		{ID: node.Generate(), OrgID: orgID, SubscriptionID: subID, PriceID: node.Generate(), Quantity: 1,
+			BillingMode: "LICENSED", CreatedAt: cycleStart.Add(time.Second)},
+	}).Error)
+	require.NoError(t, db.Create(&billingcycledomain.BillingCycle{
+		ID: node.Generate(), OrgID: orgID, SubscriptionID: subID,
+		PeriodStart: cycleStart, PeriodEnd: cycleStart.AddDate(0, 1, 0),
+		Status: billingcycledomain.BillingCycleStatusOpen,
+	}).Error)
+
+	// Users active in the previous cycle do not count against this one.
+	for i, user := range []float64{9, 1, 2, 3, 4, 4} {
+		recordedAt := cycleStart.Add(time.Duration(i) * time.Hour)
+		if i == 0 {
+			recordedAt = cycleStart.Add(-time.Hour)
+		}
+		require.NoError(t, db.Create(&usagedomain.UsageEvent{
+			ID: node.Generate(), OrgID: orgID, CustomerID: customerID, SubscriptionID: subID, MeterID: meterID,
+			MeterCode: "active_users", Value: user, RecordedAt: recordedAt, Status: usagedomain.UsageStatusEnriched,
+		}).Error)
+	}
+
+	resp, err := svc.GetSeatUsage(ctx, subID.String())
+	require.NoError(t, err)
+	assert.Equal(t, subID, resp.SubscriptionID)
+	require.Len(t, resp.Items, 1)
+	seats := resp.Items[0]
+	assert.Equal(t, meterID, seats.SeatMeterID)
+	require.NotNil(t, seats.OveragePriceID)
+	assert.Equal(t, overagePriceID, *seats.OveragePriceID)
+	assert.Equal(t, cycleStart, seats.PeriodStart.UTC())
+	assert.Equal(t, int32(3), seats.LicensedQuantity)
+	assert.Equal(t, 4.0, seats.UsedQuantity)
+	assert.Equal(t, 1.0, seats.OverageQuantity)
+	assert.True(t, seats.Overage)
+
+	_, err = svc.GetSeatUsage(ctx, node.Generate().String())
+	assert.ErrorIs(t, err, subscriptiondomain.ErrSubscriptionNotFound)
+}
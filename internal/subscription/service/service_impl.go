@@ -3,13 +3,17 @@ package service
 import (
 	"context"
 	"errors"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
 	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/cache"
 	"github.com/railzwaylabs/railzway/internal/clock"
 	invoicedomain "github.com/railzwaylabs/railzway/internal/invoice/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
 	paymentdomain "github.com/railzwaylabs/railzway/internal/payment/domain"
 	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
@@ -38,6 +42,7 @@ type Service struct {
 
 	pricesvc           pricedomain.Service
 	priceamountsvc     priceamount.Service
+	priceCache         cache.PriceCache
 	productFeatureRepo productfeaturedomain.Repository
 	quotaSvc           quotadomain.Service
 	paymentMethodSvc   paymentdomain.PaymentMethodService
@@ -54,6 +59,7 @@ type ServiceParam struct {
 
 	Pricesvc           pricedomain.Service
 	PriceAmountsvc     priceamount.Service
+	PriceCache         cache.PriceCache `optional:"true"`
 	ProductFeatureRepo productfeaturedomain.Repository
 	QuotaSvc           quotadomain.Service
 	PaymentMethodSvc   paymentdomain.PaymentMethodService
@@ -74,6 +80,7 @@ func NewService(p ServiceParam) subscriptiondomain.Service {
 
 		pricesvc:           p.Pricesvc,
 		priceamountsvc:     p.PriceAmountsvc,
+		priceCache:         p.PriceCache,
 		productFeatureRepo: p.ProductFeatureRepo,
 		quotaSvc:           p.QuotaSvc,
 		paymentMethodSvc:   p.PaymentMethodSvc,
@@ -92,8 +99,12 @@ func (s *Service) GetActiveByCustomerID(ctx context.Context, req subscriptiondom
 		return subscriptiondomain.Subscription{}, err
 	}
 
+	// Paused subscriptions are returned too so callers can tell a paused
+	// customer from one without a subscription.
 	statuses := []subscriptiondomain.SubscriptionStatus{
 		subscriptiondomain.SubscriptionStatusActive,
+		subscriptiondomain.SubscriptionStatusPastDue,
+		subscriptiondomain.SubscriptionStatusPaused,
 	}
 
 	item, err := s.repo.FindActiveByCustomerID(ctx, s.db, orgID, customerID, statuses)
@@ -137,7 +148,7 @@ func (s *Service) GetSubscriptionItem(ctx context.Context, req subscriptiondomai
 		return *item, nil
 	}
 
-	meterCode := strings.TrimSpace(req.MeterCode)
+	meterCode := meterdomain.NormalizeCode(req.MeterCode)
 	if meterCode == "" {
 		return subscriptiondomain.SubscriptionItem{}, subscriptiondomain.ErrInvalidMeterCode
 	}
@@ -179,11 +190,13 @@ func (s *Service) List(ctx context.Context, req subscriptiondomain.ListSubscript
 		filter.CustomerID = customerID
 	}
 
-	pageSize := req.PageSize
-	if pageSize <= 0 {
-		pageSize = 50
+	columns, err := subscriptiondomain.ListColumns(req.Fields)
+	if err != nil {
+		return subscriptiondomain.ListSubscriptionResponse{}, err
 	}
 
+	pageSize := pagination.ClampPageSize(req.PageSize)
+
 	options := []option.QueryOption{
 		option.ApplyPagination(pagination.Pagination{
 			PageToken: req.PageToken,
@@ -191,6 +204,9 @@ func (s *Service) List(ctx context.Context, req subscriptiondomain.ListSubscript
 		}),
 		option.WithSortBy(option.WithQuerySortBy("created_at", "desc", map[string]bool{"created_at": true})),
 	}
+	if columns != nil {
+		options = append(options, option.WithSelect(columns))
+	}
 
 	if req.CreatedFrom != nil {
 		options = append(options, option.ApplyOperator(option.Condition{
@@ -263,12 +279,7 @@ func (s *Service) ListEntitlements(ctx context.Context, req subscriptiondomain.L
 		return subscriptiondomain.ListEntitlementsResponse{}, subscriptiondomain.ErrSubscriptionNotFound
 	}
 
-	pageSize := req.PageSize
-	if pageSize < 0 {
-		pageSize = 0
-	} else if pageSize == 0 {
-		pageSize = 50
-	}
+	pageSize := pagination.ClampPageSize(req.PageSize)
 
 	items, err := s.repo.ListEntitlements(ctx, s.db, subscriptionID, req.EffectiveAt, pagination.Pagination{
 		PageToken: req.PageToken,
@@ -278,21 +289,18 @@ func (s *Service) ListEntitlements(ctx context.Context, req subscriptiondomain.L
 		return subscriptiondomain.ListEntitlementsResponse{}, err
 	}
 
-	var pageInfo *pagination.PageInfo
-	if pageSize > 0 {
-		pageInfo = pagination.BuildCursorPageInfo(items, pageSize, func(item *subscriptiondomain.SubscriptionEntitlement) string {
-			token, err := pagination.EncodeCursor(pagination.Cursor{
-				ID:        item.ID.String(),
-				CreatedAt: item.CreatedAt.Format(time.RFC3339),
-			})
-			if err != nil {
-				return ""
-			}
-			return token
+	pageInfo := pagination.BuildCursorPageInfo(items, pageSize, func(item *subscriptiondomain.SubscriptionEntitlement) string {
+		token, err := pagination.EncodeCursor(pagination.Cursor{
+			ID:        item.ID.String(),
+			CreatedAt: item.CreatedAt.Format(time.RFC3339),
 		})
-		if pageInfo != nil && pageInfo.HasMore && len(items) > int(pageSize) {
-			items = items[:pageSize]
+		if err != nil {
+			return ""
 		}
+		return token
+	})
+	if pageInfo != nil && pageInfo.HasMore && len(items) > int(pageSize) {
+		items = items[:pageSize]
 	}
 
 	entitlements := make([]subscriptiondomain.EntitlementResponse, 0, len(items))
@@ -369,7 +377,24 @@ func (s *Service) Create(ctx context.Context, req subscriptiondomain.CreateSubsc
 		return subscriptiondomain.CreateSubscriptionResponse{}, err
 	}
 
+	if req.MinimumCommitmentCents != nil && *req.MinimumCommitmentCents < 0 {
+		return subscriptiondomain.CreateSubscriptionResponse{}, subscriptiondomain.ErrInvalidMinimumCommitment
+	}
+	if req.SpendAlertThresholdCents != nil && *req.SpendAlertThresholdCents < 0 {
+		return subscriptiondomain.CreateSubscriptionResponse{}, subscriptiondomain.ErrInvalidSpendThreshold
+	}
+
 	now := s.clock.Now(ctx)
+	// A future StartAt keeps the subscription in draft until the scheduler
+	// activates it; its first billing cycle then begins at StartAt.
+	startAt := now
+	if req.StartAt != nil {
+		if req.StartAt.Before(now) {
+			return subscriptiondomain.CreateSubscriptionResponse{}, subscriptiondomain.ErrInvalidStartAt
+		}
+		startAt = req.StartAt.UTC()
+	}
+
 	currency, err := s.resolveSubscriptionCurrency(ctx, s.db, orgID, customerID, nil)
 	if err != nil {
 		return subscriptiondomain.CreateSubscriptionResponse{}, err
@@ -380,12 +405,20 @@ func (s *Service) Create(ctx context.Context, req subscriptiondomain.CreateSubsc
 		CustomerID:       customerID,
 		Status:           subscriptiondomain.SubscriptionStatusDraft,
 		CollectionMode:   collectionMode,
-		StartAt:          now,
+		StartAt:          startAt,
 		BillingCycleType: billingCycleType,
 		DefaultCurrency:  &currency,
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
+	if req.MinimumCommitmentCents != nil && *req.MinimumCommitmentCents > 0 {
+		commitment := *req.MinimumCommitmentCents
+		subscription.MinimumCommitmentCents = &commitment
+	}
+	if req.SpendAlertThresholdCents != nil && *req.SpendAlertThresholdCents > 0 {
+		threshold := *req.SpendAlertThresholdCents
+		subscription.SpendAlertThresholdCents = &threshold
+	}
 	if idempotencyKey != "" {
 		subscription.IdempotencyKey = &idempotencyKey
 	}
@@ -407,6 +440,9 @@ func (s *Service) Create(ctx context.Context, req subscriptiondomain.CreateSubsc
 		if err != nil {
 			return err
 		}
+		for i := range entitlements {
+			entitlements[i].EffectiveFrom = startAt
+		}
 
 		if err := s.repo.Insert(ctx, tx, &subscription); err != nil {
 			return err
@@ -490,9 +526,23 @@ func (s *Service) ReplaceItems(ctx context.Context, req subscriptiondomain.Repla
 			return err
 		}
 
+		currentItems, err := s.repo.ListItemsBySubscriptionID(ctx, tx, orgID, subscriptionID)
+		if err != nil {
+			return err
+		}
+		prorations, err := s.buildReplaceProrations(ctx, tx, subscription, currentItems, subscriptionItems, currency, now)
+		if err != nil {
+			return err
+		}
+
 		if err := s.repo.ReplaceItems(ctx, tx, orgID, subscriptionID, subscriptionItems); err != nil {
 			return err
 		}
+		if len(prorations) > 0 {
+			if err := tx.WithContext(ctx).Create(&prorations).Error; err != nil {
+				return err
+			}
+		}
 		if len(entitlements) > 0 {
 			if err := s.repo.InsertEntitlements(ctx, tx, entitlements); err != nil {
 				return err
@@ -548,8 +598,6 @@ func (s *Service) TransitionSubscription(
 		return subscriptiondomain.ErrInvalidOrganization
 	}
 
-	_ = reason
-
 	id, err := s.parseID(subscriptionID, subscriptiondomain.ErrInvalidSubscription)
 	if err != nil {
 		return err
@@ -568,7 +616,15 @@ func (s *Service) TransitionSubscription(
 			return subscriptiondomain.ErrSubscriptionNotFound
 		}
 
+		now := time.Now().UTC()
 		if subscription.Status == targetStatus {
+			// Re-activating an active subscription withdraws a pending
+			// cancel-at-period-end.
+			if targetStatus == subscriptiondomain.SubscriptionStatusActive && subscription.CancelAtPeriodEnd {
+				clearScheduledCancel(subscription)
+				subscription.UpdatedAt = now
+				return s.updateLifecycle(ctx, tx, subscription)
+			}
 			return nil
 		}
 
@@ -576,20 +632,29 @@ func (s *Service) TransitionSubscription(
 			return subscriptiondomain.ErrInvalidTransition
 		}
 
-		now := time.Now().UTC()
 		switch targetStatus {
 		case subscriptiondomain.SubscriptionStatusActive:
+			clearScheduledCancel(subscription)
 			if subscription.Status == subscriptiondomain.SubscriptionStatusDraft {
 				if err := s.validateActivation(ctx, tx, subscription); err != nil {
 					return err
 				}
 				if subscription.ActivatedAt == nil {
-					subscription.ActivatedAt = &now
+					activatedAt := now
+					// Scheduled subscriptions are anchored at StartAt so the
+					// first billing cycle does not drift with scheduler lag.
+					if isScheduledStart(subscription) {
+						activatedAt = subscription.StartAt
+					}
+					subscription.ActivatedAt = &activatedAt
 				}
 			}
 			if subscription.Status == subscriptiondomain.SubscriptionStatusPaused {
 				subscription.ResumedAt = &now
 			}
+			subscription.PastDueAt = nil
+		case subscriptiondomain.SubscriptionStatusPastDue:
+			subscription.PastDueAt = &now
 		case subscriptiondomain.SubscriptionStatusPaused:
 			subscription.PausedAt = &now
 		case subscriptiondomain.SubscriptionStatusCanceled:
@@ -603,10 +668,14 @@ func (s *Service) TransitionSubscription(
 			return subscriptiondomain.ErrInvalidTargetStatus
 		}
 
+		fromStatus := subscription.Status
 		subscription.Status = targetStatus
 		subscription.UpdatedAt = now
 
-		return s.updateLifecycle(ctx, tx, subscription)
+		if err := s.updateLifecycle(ctx, tx, subscription); err != nil {
+			return err
+		}
+		return s.recordEvent(ctx, tx, subscription, fromStatus, reason, now)
 	})
 }
 
@@ -623,6 +692,10 @@ func (s *Service) validateActivation(ctx context.Context, tx *gorm.DB, subscript
 		return subscriptiondomain.ErrInvalidBillingCycleType
 	}
 
+	if isScheduledStart(subscription) && subscription.StartAt.After(s.clock.Now(ctx)) {
+		return subscriptiondomain.ErrStartAtNotReached
+	}
+
 	itemCount, err := s.countSubscriptionItems(ctx, tx, subscription.OrgID, subscription.ID)
 	if err != nil {
 		return err
@@ -631,14 +704,9 @@ func (s *Service) validateActivation(ctx context.Context, tx *gorm.DB, subscript
 		return subscriptiondomain.ErrMissingSubscriptionItems
 	}
 
-	// meterCount, err := s.countSubscriptionItemsWithMeter(ctx, tx, subscription.OrgID, subscription.ID)
-	// if err != nil {
-	// 	return err
-	// }
-	// if meterCount == 0 {
-	// 	return subscriptiondomain.ErrInvalidMeterID
-	// }
-
+	// A flat base price is optional: a subscription made of metered items
+	// only is billed purely on usage. Every usage priced item needs a meter
+	// though, otherwise it can never be rated.
 	pricedCount, err := s.countSubscriptionItemsWithPrice(ctx, tx, subscription.OrgID, subscription.ID)
 	if err != nil {
 		return err
@@ -647,6 +715,14 @@ func (s *Service) validateActivation(ctx context.Context, tx *gorm.DB, subscript
 		return subscriptiondomain.ErrMissingPricing
 	}
 
+	unmetered, err := s.countUsageItemsWithoutMeter(ctx, tx, subscription.OrgID, subscription.ID)
+	if err != nil {
+		return err
+	}
+	if unmetered > 0 {
+		return subscriptiondomain.ErrInvalidMeterID
+	}
+
 	hasCustomer, err := s.hasCustomer(ctx, tx, subscription.OrgID, subscription.CustomerID)
 	if err != nil {
 		return err
@@ -658,6 +734,12 @@ func (s *Service) validateActivation(ctx context.Context, tx *gorm.DB, subscript
 	return nil
 }
 
+// isScheduledStart reports whether the subscription was created with a
+// StartAt later than its creation time.
+func isScheduledStart(subscription *subscriptiondomain.Subscription) bool {
+	return subscription.StartAt.After(subscription.CreatedAt)
+}
+
 func (s *Service) validateEnd(ctx context.Context, tx *gorm.DB, subscription *subscriptiondomain.Subscription) error {
 	openCycles, err := s.countOpenBillingCycles(ctx, tx, subscription.OrgID, subscription.ID)
 	if err != nil {
@@ -681,7 +763,8 @@ func (s *Service) validateEnd(ctx context.Context, tx *gorm.DB, subscription *su
 func (s *Service) updateLifecycle(ctx context.Context, tx *gorm.DB, subscription *subscriptiondomain.Subscription) error {
 	return tx.WithContext(ctx).Exec(
 		`UPDATE subscriptions
-		 SET status = ?, activated_at = ?, paused_at = ?, resumed_at = ?, canceled_at = ?, ended_at = ?, updated_at = ?
+		 SET status = ?, activated_at = ?, paused_at = ?, resumed_at = ?, canceled_at = ?, ended_at = ?,
+		     past_due_at = ?, cancel_at = ?, cancel_at_period_end = ?, updated_at = ?
 		 WHERE org_id = ? AND id = ?`,
 		subscription.Status,
 		subscription.ActivatedAt,
@@ -689,6 +772,9 @@ func (s *Service) updateLifecycle(ctx context.Context, tx *gorm.DB, subscription
 		subscription.ResumedAt,
 		subscription.CanceledAt,
 		subscription.EndedAt,
+		subscription.PastDueAt,
+		subscription.CancelAt,
+		subscription.CancelAtPeriodEnd,
 		subscription.UpdatedAt,
 		subscription.OrgID,
 		subscription.ID,
@@ -707,6 +793,8 @@ func (s *Service) countSubscriptionItems(ctx context.Context, tx *gorm.DB, orgID
 	return count, nil
 }
 
+// countSubscriptionItemsWithPrice counts the items backed by an existing
+// price, whatever its pricing model or billing mode.
 func (s *Service) countSubscriptionItemsWithPrice(ctx context.Context, tx *gorm.DB, orgID, subscriptionID snowflake.ID) (int64, error) {
 	var count int64
 	if err := tx.WithContext(ctx).Raw(
@@ -752,14 +840,19 @@ func (s *Service) closeActiveEntitlements(ctx context.Context, tx *gorm.DB, subs
 	return nil
 }
 
-func (s *Service) countSubscriptionItemsWithMeter(ctx context.Context, tx *gorm.DB, orgID, subscriptionID snowflake.ID) (int64, error) {
+// countUsageItemsWithoutMeter counts the items whose price is not flat but
+// that are not bound to a meter.
+func (s *Service) countUsageItemsWithoutMeter(ctx context.Context, tx *gorm.DB, orgID, subscriptionID snowflake.ID) (int64, error) {
 	var count int64
 	if err := tx.WithContext(ctx).Raw(
 		`SELECT COUNT(1)
-		 FROM subscription_items
-		 WHERE org_id = ? AND subscription_id = ?`,
+		 FROM subscription_items si
+		 JOIN prices p ON p.id = si.price_id AND p.org_id = si.org_id
+		 WHERE si.org_id = ? AND si.subscription_id = ?
+		   AND p.pricing_model <> ? AND si.meter_id IS NULL`,
 		orgID,
 		subscriptionID,
+		pricedomain.Flat,
 	).Scan(&count).Error; err != nil {
 		return 0, err
 	}
@@ -835,7 +928,8 @@ func isValidStatus(status subscriptiondomain.SubscriptionStatus) bool {
 		subscriptiondomain.SubscriptionStatusActive,
 		subscriptiondomain.SubscriptionStatusPaused,
 		subscriptiondomain.SubscriptionStatusCanceled,
-		subscriptiondomain.SubscriptionStatusEnded:
+		subscriptiondomain.SubscriptionStatusEnded,
+		subscriptiondomain.SubscriptionStatusPastDue:
 		return true
 	default:
 		return false
@@ -847,7 +941,11 @@ func isTransitionAllowed(current, target subscriptiondomain.SubscriptionStatus)
 	case subscriptiondomain.SubscriptionStatusDraft:
 		return target == subscriptiondomain.SubscriptionStatusActive
 	case subscriptiondomain.SubscriptionStatusActive:
-		return target == subscriptiondomain.SubscriptionStatusPaused || target == subscriptiondomain.SubscriptionStatusCanceled
+		return target == subscriptiondomain.SubscriptionStatusPaused ||
+			target == subscriptiondomain.SubscriptionStatusCanceled ||
+			target == subscriptiondomain.SubscriptionStatusPastDue
+	case subscriptiondomain.SubscriptionStatusPastDue:
+		return target == subscriptiondomain.SubscriptionStatusActive || target == subscriptiondomain.SubscriptionStatusCanceled
 	case subscriptiondomain.SubscriptionStatusPaused:
 		return target == subscriptiondomain.SubscriptionStatusActive || target == subscriptiondomain.SubscriptionStatusCanceled
 	case subscriptiondomain.SubscriptionStatusCanceled:
@@ -869,7 +967,8 @@ func parseStatusFilter(value string) (*subscriptiondomain.SubscriptionStatus, er
 		subscriptiondomain.SubscriptionStatusActive,
 		subscriptiondomain.SubscriptionStatusCanceled,
 		subscriptiondomain.SubscriptionStatusPaused,
-		subscriptiondomain.SubscriptionStatusEnded:
+		subscriptiondomain.SubscriptionStatusEnded,
+		subscriptiondomain.SubscriptionStatusPastDue:
 		parsed := subscriptiondomain.SubscriptionStatus(status)
 		return &parsed, nil
 	default:
@@ -914,6 +1013,8 @@ func parseCollectionMode(value string) (subscriptiondomain.SubscriptionCollectio
 func normalizeBillingCycleType(value string) (string, error) {
 	cycle := strings.ToUpper(strings.TrimSpace(value))
 	switch cycle {
+	case "YEARLY":
+		return "yearly", nil
 	case "MONTHLY":
 		return "monthly", nil
 	case "WEEKLY":
@@ -949,7 +1050,7 @@ func (s *Service) buildSubscriptionItems(
 		return nil, nil, subscriptiondomain.ErrInvalidCurrency
 	}
 
-	for _, item := range items {
+	for i, item := range items {
 		price, err := s.loadPrice(ctx, item.PriceID, priceCache)
 		if err != nil {
 			return nil, nil, err
@@ -960,7 +1061,7 @@ func (s *Service) buildSubscriptionItems(
 		}
 
 		if err := validateSubscriptionPricingModel(price, &flatCount); err != nil {
-			return nil, nil, err
+			return nil, nil, itemError(i, item.PriceID, err)
 		}
 
 		quantity := normalizeSubscriptionQuantity(item.Quantity)
@@ -968,17 +1069,27 @@ func (s *Service) buildSubscriptionItems(
 			return nil, nil, err
 		}
 
-		parsedPriceID, err := s.parseID(price.ID.String(), subscriptiondomain.ErrInvalidPrice)
+		usageBehavior, err := normalizeUsageBehavior(price, item.UsageBehavior)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		cycleType, err := billingCycleTypeForInterval(price.BillingInterval)
+		prorationBehavior, err := normalizeProrationBehavior(price, item.ProrationBehavior)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		parsedPriceID, err := s.parseID(price.ID.String(), subscriptiondomain.ErrInvalidPrice)
 		if err != nil {
 			return nil, nil, err
 		}
+
+		cycleType, err := subscriptiondomain.BillingCycleTypeForInterval(price.BillingInterval)
+		if err != nil {
+			return nil, nil, itemError(i, item.PriceID, err)
+		}
 		if cycleType != expectedCycleType {
-			return nil, nil, subscriptiondomain.ErrInvalidBillingCycleType
+			return nil, nil, itemError(i, item.PriceID, subscriptiondomain.ErrInvalidBillingCycleType)
 		}
 
 		var (
@@ -990,14 +1101,15 @@ func (s *Service) buildSubscriptionItems(
 			return nil, nil, err
 		}
 		if len(priceAmounts) == 0 {
-			return nil, nil, subscriptiondomain.ErrMissingPricing
+			return nil, nil, itemError(i, item.PriceID, s.missingPricingError(ctx, price.ID.String(), currency))
 		}
 
 		if price.PricingModel != pricedomain.Flat {
 			if priceAmounts[0].MeterID == nil {
 				return nil, nil, subscriptiondomain.ErrInvalidMeterID
 			}
-			meterID, meterCode, err = s.resolvePriceMeter(
+			var archived bool
+			meterID, meterCode, archived, err = s.resolvePriceMeter(
 				ctx,
 				orgID,
 				parsedPriceID,
@@ -1006,6 +1118,24 @@ func (s *Service) buildSubscriptionItems(
 			if err != nil {
 				return nil, nil, err
 			}
+			if archived {
+				return nil, nil, itemError(i, item.PriceID, subscriptiondomain.ErrMeterArchived)
+			}
+		}
+
+		dimensionKey, dimensionValue, err := normalizeDimensionFilter(meterID, item.DimensionKey, item.DimensionValue)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		includedQuantity, err := normalizeIncludedQuantity(meterID, item.IncludedQuantity)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		seatMeterID, overagePriceID, err := s.normalizeSeatOverage(ctx, orgID, price, item, currency, priceCache)
+		if err != nil {
+			return nil, nil, err
 		}
 
 		if price.PricingModel == pricedomain.TieredVolume || price.PricingModel == pricedomain.TieredGraduated {
@@ -1014,7 +1144,7 @@ func (s *Service) buildSubscriptionItems(
 				return nil, nil, err
 			}
 			if !hasTiers {
-				return nil, nil, subscriptiondomain.ErrMissingPricing
+				return nil, nil, itemError(i, item.PriceID, subscriptiondomain.ErrMissingPricing)
 			}
 		}
 
@@ -1025,18 +1155,25 @@ func (s *Service) buildSubscriptionItems(
 		}
 
 		subscriptionItems = append(subscriptionItems, subscriptiondomain.SubscriptionItem{
-			ID:               s.genID.Generate(),
-			OrgID:            orgID,
-			SubscriptionID:   subscriptionID,
-			PriceID:          parsedPriceID,
-			PriceCode:        priceCodePtr, // snapshot
-			MeterID:          meterID,
-			MeterCode:        meterCode, // snapshot
-			Quantity:         quantity,
-			BillingMode:      string(price.BillingMode), // snapshot
-			BillingThreshold: price.BillingThreshold,    // snapshot
-			CreatedAt:        now,
-			UpdatedAt:        now,
+			ID:                s.genID.Generate(),
+			OrgID:             orgID,
+			SubscriptionID:    subscriptionID,
+			PriceID:           parsedPriceID,
+			PriceCode:         priceCodePtr, // snapshot
+			MeterID:           meterID,
+			MeterCode:         meterCode, // snapshot
+			Quantity:          quantity,
+			BillingMode:       string(price.BillingMode), // snapshot
+			BillingThreshold:  price.BillingThreshold,    // snapshot
+			UsageBehavior:     &usageBehavior,
+			ProrationBehavior: &prorationBehavior,
+			DimensionKey:      dimensionKey,
+			DimensionValue:    dimensionValue,
+			IncludedQuantity:  includedQuantity,
+			SeatMeterID:       seatMeterID,
+			OveragePriceID:    overagePriceID,
+			CreatedAt:         now,
+			UpdatedAt:         now,
 		})
 
 		if _, ok := seenProducts[price.ProductID]; !ok {
@@ -1048,6 +1185,23 @@ func (s *Service) buildSubscriptionItems(
 	return subscriptionItems, productIDs, nil
 }
 
+// itemError ties the item-level validation failures callers need to fix in
+// the request to the offending item. Other errors pass through unchanged.
+func itemError(index int, priceID string, err error) error {
+	switch {
+	case errors.Is(err, subscriptiondomain.ErrInvalidBillingCycleType),
+		errors.Is(err, subscriptiondomain.ErrMultipleFlatPrices),
+		errors.Is(err, subscriptiondomain.ErrMissingPricing):
+		return &subscriptiondomain.ItemError{
+			Index:   index,
+			PriceID: strings.TrimSpace(priceID),
+			Err:     err,
+		}
+	default:
+		return err
+	}
+}
+
 func (s *Service) loadPrice(
 	ctx context.Context,
 	priceID string,
@@ -1062,7 +1216,7 @@ func (s *Service) loadPrice(
 		return cached, nil
 	}
 
-	loaded, err := s.pricesvc.Get(ctx, trimmed)
+	loaded, err := s.getPrice(ctx, trimmed)
 	if err != nil {
 		return nil, err
 	}
@@ -1138,16 +1292,50 @@ func (s *Service) buildSubscriptionEntitlements(
 
 func (s *Service) loadPriceAmount(ctx context.Context, priceID string, currency string) ([]priceamount.Response, error) {
 	now := s.clock.Now(ctx)
-	resp, err := s.priceamountsvc.List(ctx, priceamount.ListPriceAmountRequest{
-		PriceID:       priceID,
-		Currency:      currency,
-		EffectiveFrom: &now,
-		PageSize:      -1,
-	})
+	amounts, err := s.listPriceAmounts(ctx, priceID, currency)
 	if err != nil {
 		return nil, err
 	}
-	return resp.Amounts, nil
+
+	effective := make([]priceamount.Response, 0, len(amounts))
+	for _, amount := range amounts {
+		if amount.EffectiveFrom.After(now) {
+			continue
+		}
+		effective = append(effective, amount)
+	}
+	return effective, nil
+}
+
+// missingPricingError explains a price with no amount in currency. When the
+// price is priced in other currencies they are listed so the caller can fix
+// the customer's currency; otherwise the price has no pricing at all.
+func (s *Service) missingPricingError(ctx context.Context, priceID string, currency string) error {
+	amounts, err := s.loadPriceAmount(ctx, priceID, "")
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(amounts))
+	available := make([]string, 0, len(amounts))
+	for _, amount := range amounts {
+		code := strings.ToUpper(strings.TrimSpace(amount.Currency))
+		if _, ok := seen[code]; ok || code == "" {
+			continue
+		}
+		seen[code] = struct{}{}
+		available = append(available, code)
+	}
+	if len(available) == 0 {
+		return subscriptiondomain.ErrMissingPricing
+	}
+	sort.Strings(available)
+
+	return &subscriptiondomain.CurrencyNotPricedError{
+		PriceID:             priceID,
+		Currency:            currency,
+		AvailableCurrencies: available,
+	}
 }
 
 func (s *Service) resolveSubscriptionCurrency(ctx context.Context, tx *gorm.DB, orgID, customerID snowflake.ID, explicit *string) (string, error) {
@@ -1234,14 +1422,14 @@ func validateSubscriptionPricingModel(price *pricedomain.Response, flatCount *in
 	}
 }
 
-func normalizeSubscriptionQuantity(quantity int8) int8 {
+func normalizeSubscriptionQuantity(quantity int32) int32 {
 	if quantity <= 0 {
 		return 1
 	}
 	return quantity
 }
 
-func validateSubscriptionBillingMode(price *pricedomain.Response, quantity int8) error {
+func validateSubscriptionBillingMode(price *pricedomain.Response, quantity int32) error {
 	switch price.BillingMode {
 	case pricedomain.Licensed:
 		if quantity < 1 {
@@ -1255,32 +1443,74 @@ func validateSubscriptionBillingMode(price *pricedomain.Response, quantity int8)
 	}
 }
 
-func (s *Service) toCreateResponse(subscription *subscriptiondomain.Subscription, items []subscriptiondomain.SubscriptionItem) subscriptiondomain.CreateSubscriptionResponse {
-	respItems := make([]subscriptiondomain.CreateSubscriptionItemResponse, 0, len(items))
-	for _, item := range items {
-		var meterID *string
-		if item.MeterID != nil {
-			value := item.MeterID.String()
-			meterID = &value
+// normalizeUsageBehavior defaults items to arrears billing. Only flat prices
+// may be billed in advance since metered usage is unknown at cycle start.
+func normalizeUsageBehavior(price *pricedomain.Response, value string) (string, error) {
+	behavior := strings.ToLower(strings.TrimSpace(value))
+	switch behavior {
+	case "", subscriptiondomain.UsageBehaviorArrears:
+		return subscriptiondomain.UsageBehaviorArrears, nil
+	case subscriptiondomain.UsageBehaviorAdvance:
+		if price.PricingModel != pricedomain.Flat {
+			return "", subscriptiondomain.ErrInvalidUsageBehavior
 		}
-		var meterCode *string
-		if item.MeterCode != nil && strings.TrimSpace(*item.MeterCode) != "" {
-			value := strings.TrimSpace(*item.MeterCode)
-			meterCode = &value
+		return subscriptiondomain.UsageBehaviorAdvance, nil
+	default:
+		return "", subscriptiondomain.ErrInvalidUsageBehavior
+	}
+}
+
+// normalizeDimensionFilter validates an optional usage dimension binding. Key
+// and value must be set together, and only metered items can be filtered.
+func normalizeDimensionFilter(meterID *snowflake.ID, key, value string) (*string, *string, error) {
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	if key == "" && value == "" {
+		return nil, nil, nil
+	}
+	if key == "" || value == "" || meterID == nil {
+		return nil, nil, subscriptiondomain.ErrInvalidDimensionFilter
+	}
+	return &key, &value, nil
+}
+
+// normalizeIncludedQuantity validates an optional included allowance. Only
+// metered items have usage to include, and a zero allowance is dropped.
+func normalizeIncludedQuantity(meterID *snowflake.ID, value *float64) (*float64, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if *value < 0 || math.IsNaN(*value) || math.IsInf(*value, 0) || meterID == nil {
+		return nil, subscriptiondomain.ErrInvalidIncludedQuantity
+	}
+	if *value == 0 {
+		return nil, nil
+	}
+	included := *value
+	return &included, nil
+}
+
+// normalizeProrationBehavior defaults items to no proration. Only flat
+// licensed prices may be prorated since metered usage is rated as it happens.
+func normalizeProrationBehavior(price *pricedomain.Response, value string) (string, error) {
+	behavior := strings.ToLower(strings.TrimSpace(value))
+	switch behavior {
+	case "", subscriptiondomain.ProrationBehaviorNone:
+		return subscriptiondomain.ProrationBehaviorNone, nil
+	case subscriptiondomain.ProrationBehaviorCreateProrations:
+		if price.PricingModel != pricedomain.Flat || price.BillingMode != pricedomain.Licensed {
+			return "", subscriptiondomain.ErrInvalidProrationBehavior
 		}
+		return subscriptiondomain.ProrationBehaviorCreateProrations, nil
+	default:
+		return "", subscriptiondomain.ErrInvalidProrationBehavior
+	}
+}
 
-		respItems = append(respItems, subscriptiondomain.CreateSubscriptionItemResponse{
-			ID:                item.ID.String(),
-			PriceID:           item.PriceID.String(),
-			PriceCode:         item.PriceCode,
-			MeterID:           meterID,
-			MeterCode:         meterCode,
-			Quantity:          item.Quantity,
-			BillingMode:       item.BillingMode,
-			UsageBehavior:     item.UsageBehavior,
-			BillingThreshold:  item.BillingThreshold,
-			ProrationBehavior: item.ProrationBehavior,
-		})
+func (s *Service) toCreateResponse(subscription *subscriptiondomain.Subscription, items []subscriptiondomain.SubscriptionItem) subscriptiondomain.CreateSubscriptionResponse {
+	respItems := make([]subscriptiondomain.CreateSubscriptionItemResponse, 0, len(items))
+	for _, item := range items {
+		respItems = append(respItems, toItemResponse(item))
 	}
 
 	var metadata map[string]any
@@ -1300,55 +1530,88 @@ func (s *Service) toCreateResponse(subscription *subscriptiondomain.Subscription
 	}
 }
 
+func toItemResponse(item subscriptiondomain.SubscriptionItem) subscriptiondomain.CreateSubscriptionItemResponse {
+	var meterID *string
+	if item.MeterID != nil {
+		value := item.MeterID.String()
+		meterID = &value
+	}
+	var meterCode *string
+	if item.MeterCode != nil && strings.TrimSpace(*item.MeterCode) != "" {
+		value := strings.TrimSpace(*item.MeterCode)
+		meterCode = &value
+	}
+
+	var seatMeterID *string
+	if item.SeatMeterID != nil {
+		value := item.SeatMeterID.String()
+		seatMeterID = &value
+	}
+	var overagePriceID *string
+	if item.OveragePriceID != nil {
+		value := item.OveragePriceID.String()
+		overagePriceID = &value
+	}
+
+	return subscriptiondomain.CreateSubscriptionItemResponse{
+		ID:                item.ID.String(),
+		PriceID:           item.PriceID.String(),
+		PriceCode:         item.PriceCode,
+		MeterID:           meterID,
+		MeterCode:         meterCode,
+		Quantity:          item.Quantity,
+		BillingMode:       item.BillingMode,
+		UsageBehavior:     item.UsageBehavior,
+		BillingThreshold:  item.BillingThreshold,
+		ProrationBehavior: item.ProrationBehavior,
+		DimensionKey:      item.DimensionKey,
+		DimensionValue:    item.DimensionValue,
+		IncludedQuantity:  item.IncludedQuantity,
+		SeatMeterID:       seatMeterID,
+		OveragePriceID:    overagePriceID,
+	}
+}
+
+// resolvePriceMeter resolves the meter a usage price is billed on. Archived
+// meters still resolve so historical items keep rating; callers binding new
+// items must reject them.
 func (s *Service) resolvePriceMeter(
 	ctx context.Context,
 	orgID, priceID snowflake.ID,
 	meterID *snowflake.ID,
-) (*snowflake.ID, *string, error) {
+) (*snowflake.ID, *string, bool, error) {
 
 	// Case 1: Flat price → no meter
 	if meterID == nil {
-		return nil, nil, nil
+		return nil, nil, false, nil
 	}
 
 	var row struct {
-		MeterID   snowflake.ID `gorm:"column:meter_id"`
-		MeterCode string       `gorm:"column:code"`
+		MeterID    snowflake.ID `gorm:"column:meter_id"`
+		MeterCode  string       `gorm:"column:code"`
+		ArchivedAt *time.Time   `gorm:"column:archived_at"`
 	}
 
 	if err := s.db.WithContext(ctx).Raw(
-		`SELECT m.id AS meter_id, m.code
+		`SELECT m.id AS meter_id, m.code, m.archived_at
 		 FROM meters m
 		 WHERE m.org_id = ? AND m.id = ?
 		 LIMIT 1`,
 		orgID,
 		*meterID,
 	).Scan(&row).Error; err != nil {
-		return nil, nil, err
+		return nil, nil, false, err
 	}
 
 	if row.MeterID == 0 {
-		return nil, nil, subscriptiondomain.ErrInvalidMeterID
+		return nil, nil, false, subscriptiondomain.ErrInvalidMeterID
 	}
 
 	meterCode := strings.TrimSpace(row.MeterCode)
 	if meterCode == "" {
-		return nil, nil, subscriptiondomain.ErrInvalidMeterCode
+		return nil, nil, false, subscriptiondomain.ErrInvalidMeterCode
 	}
 
 	resolvedMeterID := row.MeterID
-	return &resolvedMeterID, &meterCode, nil
-}
-
-func billingCycleTypeForInterval(interval pricedomain.BillingInterval) (string, error) {
-	switch strings.ToUpper(strings.TrimSpace(string(interval))) {
-	case string(pricedomain.Day):
-		return "daily", nil
-	case string(pricedomain.Week):
-		return "weekly", nil
-	case string(pricedomain.Month):
-		return "monthly", nil
-	default:
-		return "", subscriptiondomain.ErrInvalidBillingCycleType
-	}
+	return &resolvedMeterID, &meterCode, row.ArchivedAt != nil, nil
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
@@ -11,6 +12,69 @@ import (
 	"gorm.io/gorm"
 )
 
+// CancelSubscription cancels a subscription immediately or, for
+// CancelAtPeriodEnd, flags it to be canceled when its open billing cycle
+// ends. The subscription stays active until the scheduler performs the
+// transition at CancelAt.
+func (s *Service) CancelSubscription(ctx context.Context, req subscriptiondomain.CancelSubscriptionRequest) error {
+	switch strings.TrimSpace(req.CancelAt) {
+	case "", subscriptiondomain.CancelAtImmediate:
+		return s.TransitionSubscription(ctx, req.SubscriptionID, subscriptiondomain.SubscriptionStatusCanceled, subscriptiondomain.TransitionReason("subscription.cancel"))
+	case subscriptiondomain.CancelAtPeriodEnd:
+	default:
+		return subscriptiondomain.ErrInvalidCancelAt
+	}
+
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return subscriptiondomain.ErrInvalidOrganization
+	}
+	id, err := s.parseID(req.SubscriptionID, subscriptiondomain.ErrInvalidSubscription)
+	if err != nil {
+		return err
+	}
+
+	now := s.clock.Now(ctx).UTC()
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		subscription, err := s.repo.FindByIDForUpdate(ctx, tx, orgID, id)
+		if err != nil {
+			return err
+		}
+		if subscription == nil {
+			return subscriptiondomain.ErrSubscriptionNotFound
+		}
+		if !isTransitionAllowed(subscription.Status, subscriptiondomain.SubscriptionStatusCanceled) {
+			return subscriptiondomain.ErrInvalidTransition
+		}
+
+		cycle, err := s.loadProrationCycle(ctx, tx, orgID, id, now)
+		if err != nil {
+			return err
+		}
+		if cycle == nil {
+			return subscriptiondomain.ErrNoOpenBillingCycle
+		}
+
+		cancelAt := cycle.PeriodEnd.UTC()
+		subscription.CancelAt = &cancelAt
+		subscription.CancelAtPeriodEnd = true
+		subscription.UpdatedAt = now
+
+		return s.updateLifecycle(ctx, tx, subscription)
+	})
+}
+
+// clearScheduledCancel withdraws a pending cancel-at-period-end.
+func clearScheduledCancel(subscription *subscriptiondomain.Subscription) {
+	subscription.CancelAt = nil
+	subscription.CancelAtPeriodEnd = false
+}
+
+// ChangePlan moves a subscription onto a new price. The current items are
+// replaced by a single item for the target price, entitlements are rebuilt
+// from the target product and flat licensed items are prorated for the rest
+// of the open billing cycle unless the request opts out.
 func (s *Service) ChangePlan(ctx context.Context, req subscriptiondomain.ChangePlanRequest) error {
 	orgID, ok := orgcontext.OrgIDFromContext(ctx)
 	if !ok {
@@ -20,14 +84,6 @@ func (s *Service) ChangePlan(ctx context.Context, req subscriptiondomain.ChangeP
 	if err != nil {
 		return subscriptiondomain.ErrInvalidSubscription
 	}
-	newProductID, err := snowflake.ParseString(req.NewProductID)
-	if err != nil {
-		return subscriptiondomain.ErrInvalidProduct
-	}
-
-	if !isValidStatus(subscriptiondomain.SubscriptionStatusActive) {
-		return subscriptiondomain.ErrInvalidSubscriptionStatus
-	}
 
 	now := s.clock.Now(ctx).UTC()
 
@@ -44,44 +100,29 @@ func (s *Service) ChangePlan(ctx context.Context, req subscriptiondomain.ChangeP
 			return subscriptiondomain.ErrInvalidSubscriptionStatus
 		}
 
-		// 2. Resolve Price for New Product
-		// We need to find the default active price for the product.
-		newPrice, err := s.resolveProductPrice(ctx, orgID, newProductID)
+		// 2. Build the new item and line up the items it replaces
+		currency, err := s.resolveSubscriptionCurrency(ctx, s.db, orgID, sub.CustomerID, sub.DefaultCurrency)
 		if err != nil {
 			return err
 		}
-
-		// 3. Build New Items and Entitlements
-		// Re-calculate based on new product features and price
-		cycleType, err := billingCycleTypeForInterval(newPrice.BillingInterval)
+		subscriptionItems, currentItems, productID, err := s.buildPlanChangeItems(ctx, tx, sub, req, currency, now)
 		if err != nil {
 			return err
 		}
 
-		// Construct request item (assuming quantity 1 for plan change for offered product)
-		itemReqs := []subscriptiondomain.CreateSubscriptionItemRequest{
-			{
-				PriceID:  newPrice.ID.String(),
-				Quantity: 1,
-			},
-		}
-
-		// buildSubscriptionItems does not take tx, uses service db/cache, which is safe for read-only static data (prices/meters)
-		currency, err := s.resolveSubscriptionCurrency(ctx, s.db, orgID, sub.CustomerID, sub.DefaultCurrency)
-		if err != nil {
-			return err
-		}
-		subscriptionItems, _, err := s.buildSubscriptionItems(ctx, orgID, subscriptionID, itemReqs, cycleType, currency, now)
+		// 3. Build Entitlements
+		entitlements, err := s.buildSubscriptionEntitlements(ctx, tx, orgID, subscriptionID, []snowflake.ID{productID}, now)
 		if err != nil {
 			return err
 		}
 
-		entitlements, err := s.buildSubscriptionEntitlements(ctx, tx, orgID, subscriptionID, []snowflake.ID{newProductID}, now)
+		// 4. Prorate the items being replaced
+		prorations, err := s.buildReplaceProrations(ctx, tx, sub, currentItems, subscriptionItems, currency, now)
 		if err != nil {
 			return err
 		}
 
-		// 4. Update Database
+		// 5. Update Database
 
 		// Close old entitlements
 		if err := s.closeActiveEntitlements(ctx, tx, subscriptionID, now); err != nil {
@@ -94,23 +135,24 @@ func (s *Service) ChangePlan(ctx context.Context, req subscriptiondomain.ChangeP
 		}
 
 		// Insert new entitlements
-		if err := s.repo.InsertEntitlements(ctx, tx, entitlements); err != nil {
-			return err
+		if len(entitlements) > 0 {
+			if err := s.repo.InsertEntitlements(ctx, tx, entitlements); err != nil {
+				return err
+			}
 		}
 
-		// Update subscription
-		newCycleType, err := billingCycleTypeForInterval(newPrice.BillingInterval)
-		if err != nil {
-			return err
+		if len(prorations) > 0 {
+			if err := tx.WithContext(ctx).Create(&prorations).Error; err != nil {
+				return err
+			}
 		}
 
-		// Optimization: Only update if changed, but PlanChangedAt must strictly update
+		// PlanChangedAt must strictly update
 		if err := tx.Exec(
 			`UPDATE subscriptions
-             SET plan_changed_at = ?, billing_cycle_type = ?, updated_at = ?
+             SET plan_changed_at = ?, updated_at = ?
              WHERE org_id = ? AND id = ?`,
 			now,
-			newCycleType,
 			now,
 			orgID,
 			subscriptionID,
@@ -122,6 +164,94 @@ func (s *Service) ChangePlan(ctx context.Context, req subscriptiondomain.ChangeP
 	})
 }
 
+// buildPlanChangeItems returns the single item a plan change puts on the
+// subscription, the product it grants and the current items it replaces.
+// The plan change decides proration for both sides, so the current items
+// follow the behavior of the new one.
+func (s *Service) buildPlanChangeItems(
+	ctx context.Context,
+	tx *gorm.DB,
+	sub *subscriptiondomain.Subscription,
+	req subscriptiondomain.ChangePlanRequest,
+	currency string,
+	now time.Time,
+) ([]subscriptiondomain.SubscriptionItem, []subscriptiondomain.SubscriptionItem, snowflake.ID, error) {
+	// Resolve the target price and make sure its product is sellable
+	newPrice, err := s.resolveChangePlanPrice(ctx, sub.OrgID, req)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if err := s.ensureProductsActive(ctx, tx, sub.OrgID, []snowflake.ID{newPrice.ProductID}); err != nil {
+		return nil, nil, 0, err
+	}
+
+	// The subscription keeps its billing cycle, so buildSubscriptionItems
+	// rejects prices billed on a different interval.
+	prorationBehavior := strings.TrimSpace(req.ProrationBehavior)
+	if prorationBehavior == "" && newPrice.PricingModel == pricedomain.Flat && newPrice.BillingMode == pricedomain.Licensed {
+		prorationBehavior = subscriptiondomain.ProrationBehaviorCreateProrations
+	}
+	itemReqs := []subscriptiondomain.CreateSubscriptionItemRequest{
+		{
+			PriceID:           newPrice.ID.String(),
+			Quantity:          1,
+			ProrationBehavior: prorationBehavior,
+		},
+	}
+
+	// buildSubscriptionItems does not take tx, uses service db/cache, which is safe for read-only static data (prices/meters)
+	nextItems, _, err := s.buildSubscriptionItems(ctx, sub.OrgID, sub.ID, itemReqs, sub.BillingCycleType, currency, now)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	behavior := *nextItems[0].ProrationBehavior
+	currentItems, err := s.repo.ListItemsBySubscriptionID(ctx, tx, sub.OrgID, sub.ID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	for i := range currentItems {
+		currentItems[i].ProrationBehavior = &behavior
+	}
+	return nextItems, currentItems, newPrice.ProductID, nil
+}
+
+// resolveChangePlanPrice returns the target price of a plan change. An
+// explicit price wins; otherwise the default price of the product is used.
+func (s *Service) resolveChangePlanPrice(ctx context.Context, orgID snowflake.ID, req subscriptiondomain.ChangePlanRequest) (*pricedomain.Response, error) {
+	var productID snowflake.ID
+	if raw := strings.TrimSpace(req.NewProductID); raw != "" {
+		parsed, err := snowflake.ParseString(raw)
+		if err != nil {
+			return nil, subscriptiondomain.ErrInvalidProduct
+		}
+		productID = parsed
+	}
+
+	raw := strings.TrimSpace(req.NewPriceID)
+	if raw == "" {
+		if productID == 0 {
+			return nil, subscriptiondomain.ErrInvalidPrice
+		}
+		return s.resolveProductPrice(ctx, orgID, productID)
+	}
+
+	if _, err := snowflake.ParseString(raw); err != nil {
+		return nil, subscriptiondomain.ErrInvalidPrice
+	}
+	price, err := s.getPrice(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+	if price == nil || price.OrganizationID != orgID || !price.Active || price.RetiredAt != nil {
+		return nil, subscriptiondomain.ErrInvalidPrice
+	}
+	if productID != 0 && price.ProductID != productID {
+		return nil, subscriptiondomain.ErrInvalidPrice
+	}
+	return price, nil
+}
+
 func (s *Service) resolveProductPrice(ctx context.Context, orgID, productID snowflake.ID) (*pricedomain.Response, error) {
 	allPrices, err := s.pricesvc.List(ctx, pricedomain.ListOptions{
 		ProductID: productID.String(),
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	priceamountdomain "github.com/railzwaylabs/railzway/internal/priceamount/domain"
+	quotadomain "github.com/railzwaylabs/railzway/internal/quota/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type fixedClock struct{ now time.Time }
+
+func (c *fixedClock) Now(ctx context.Context) time.Time { return c.now }
+
+type allowAllQuota struct{ quotadomain.Service }
+
+func (allowAllQuota) CanCreateSubscription(ctx context.Context, orgID snowflake.ID) error { return nil }
+
+// TestCreate_FutureStartAt schedules a subscription a day ahead and expects it
+// to stay in draft until then, with its billing anchor at the start date.
+func TestCreate_FutureStartAt(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&subscriptiondomain.Subscription{},
+		&subscriptiondomain.SubscriptionItem{},
+		&subscriptiondomain.SubscriptionEntitlement{},
+		&subscriptiondomain.SubscriptionEvent{},
+		&pricedomain.Price{},
+		&customerdomain.Customer{},
+	))
+	require.NoError(t, db.Exec(`CREATE TABLE products (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		active BOOLEAN NOT NULL
+	)`).Error)
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	productID := node.Generate()
+	priceID := node.Generate()
+	require.NoError(t, db.Exec(`INSERT INTO products (id, org_id, active) VALUES (?, ?, true)`, productID, orgID).Error)
+	price := pricedomain.Price{
+		ID:              priceID,
+		OrgID:           orgID,
+		ProductID:       productID,
+		Code:            "pro_monthly",
+		PricingModel:    pricedomain.Flat,
+		BillingMode:     pricedomain.Licensed,
+		BillingInterval: pricedomain.Month,
+		Active:          true,
+	}
+	require.NoError(t, db.Create(&price).Error)
+
+	customer := customerdomain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Acme", Email: "billing@acme.test", Currency: "USD", Metadata: datatypes.JSONMap{}}
+	require.NoError(t, db.Create(&customer).Error)
+
+	clock := &fixedClock{now: time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)}
+	repo := &mockRepository{subscriptions: make(map[string]*subscriptiondomain.Subscription)}
+	svc := NewService(ServiceParam{
+		DB:    db,
+		Log:   zap.NewNop(),
+		GenID: node,
+		Clock: clock,
+		Repo:  repo,
+		Pricesvc: &mockPriceService{prices: []pricedomain.Response{{
+			ID:              priceID,
+			OrganizationID:  orgID,
+			ProductID:       productID,
+			PricingModel:    pricedomain.Flat,
+			BillingMode:     pricedomain.Licensed,
+			BillingInterval: pricedomain.Month,
+			Active:          true,
+		}}},
+		PriceAmountsvc: &currencyPriceAmounts{amounts: []priceamountdomain.Response{
+			{ID: 1, PriceID: priceID, Currency: "USD", UnitAmountCents: 4900},
+		}},
+		ProductFeatureRepo: &mockProductFeatureRepo{},
+		QuotaSvc:           allowAllQuota{},
+		PaymentMethodSvc:   &mockPaymentMethodService{},
+	}).(*Service)
+
+	ctx := orgcontext.WithOrgID(context.Background(), int64(orgID))
+	create := func(startAt time.Time) (subscriptiondomain.CreateSubscriptionResponse, error) {
+		return svc.Create(ctx, subscriptiondomain.CreateSubscriptionRequest{
+			CustomerID:       customer.ID.String(),
+			CollectionMode:   subscriptiondomain.SubscriptionCollectionModeSendInvoice,
+			BillingCycleType: "monthly",
+			Items:            []subscriptiondomain.CreateSubscriptionItemRequest{{PriceID: priceID.String()}},
+			StartAt:          &startAt,
+		})
+	}
+
+	_, err = create(clock.now.Add(-time.Hour))
+	assert.ErrorIs(t, err, subscriptiondomain.ErrInvalidStartAt)
+
+	startAt := clock.now.Add(24 * time.Hour)
+	resp, err := create(startAt)
+	require.NoError(t, err)
+	assert.Equal(t, subscriptiondomain.SubscriptionStatusDraft, resp.Status)
+	assert.True(t, resp.StartAt.Equal(startAt))
+
+	err = svc.TransitionSubscription(ctx, resp.ID, subscriptiondomain.SubscriptionStatusActive, "")
+	assert.ErrorIs(t, err, subscriptiondomain.ErrStartAtNotReached)
+
+	// The scheduler picks the subscription up a few minutes late; the first
+	// cycle must still start at StartAt.
+	clock.now = startAt.Add(3 * time.Minute)
+	require.NoError(t, svc.TransitionSubscription(ctx, resp.ID, subscriptiondomain.SubscriptionStatusActive, ""))
+
+	var stored subscriptiondomain.Subscription
+	require.NoError(t, db.First(&stored, "id = ?", resp.ID).Error)
+	assert.Equal(t, subscriptiondomain.SubscriptionStatusActive, stored.Status)
+	require.NotNil(t, stored.ActivatedAt)
+	assert.True(t, stored.ActivatedAt.Equal(startAt))
+}
@@ -10,4 +10,5 @@ var (
 	ErrInvalidTaxCode      = errors.New("invalid_tax_code")
 	ErrInvalidTaxMode      = errors.New("invalid_tax_mode")
 	ErrInvalidTaxRate      = errors.New("invalid_tax_rate")
+	ErrInvalidCountry      = errors.New("invalid_country")
 )
@@ -51,6 +51,12 @@ type TaxDefinition struct {
 	TaxMode TaxMode  `gorm:"column:tax_mode;type:text;not null"`
 	Rate    *float64 `gorm:"type:numeric(6,4)"` // fraction (e.g. 0.2000 for 20%), nil if dynamic/placeholder
 
+	// Country (ISO 3166-1 alpha-2) and State scope the definition to customers
+	// in that jurisdiction. A definition without a country is the org-wide
+	// fallback.
+	Country *string `gorm:"type:text"`
+	State   *string `gorm:"type:text"`
+
 	Description *string `gorm:"type:text"`
 
 	IsEnabled bool `gorm:"column:is_enabled;not null;default:true"`
@@ -61,6 +67,18 @@ type TaxDefinition struct {
 
 func (TaxDefinition) TableName() string { return "tax_definitions" }
 
+// Jurisdiction returns the region the definition applies to, e.g. "US-CA",
+// "DE", or "" for the org-wide fallback.
+func (t *TaxDefinition) Jurisdiction() string {
+	if t.Country == nil {
+		return ""
+	}
+	if t.State == nil {
+		return *t.Country
+	}
+	return *t.Country + "-" + *t.State
+}
+
 func (t *TaxDefinition) Validate() error {
 	if t.Code == "" {
 		return ErrInvalidTaxCode
@@ -71,5 +89,11 @@ func (t *TaxDefinition) Validate() error {
 	if t.Rate != nil && *t.Rate < 0 {
 		return ErrInvalidTaxRate
 	}
+	if t.Country != nil && len(*t.Country) != 2 {
+		return ErrInvalidCountry
+	}
+	if t.State != nil && t.Country == nil {
+		return ErrInvalidCountry
+	}
 	return nil
 }
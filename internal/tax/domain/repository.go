@@ -8,6 +8,8 @@ import (
 
 type Repository interface {
 	GetActiveTaxDefinition(ctx context.Context, orgID snowflake.ID) (*TaxDefinition, error)
+	ListActiveTaxDefinitions(ctx context.Context, orgID snowflake.ID) ([]TaxDefinition, error)
+	FindCustomerTaxProfile(ctx context.Context, orgID, customerID snowflake.ID) (*CustomerTaxProfile, error)
 	Create(ctx context.Context, def *TaxDefinition) error
 	FindByID(ctx context.Context, orgID, id snowflake.ID) (*TaxDefinition, error)
 	List(ctx context.Context, orgID snowflake.ID, filter ListRequest) ([]TaxDefinition, error)
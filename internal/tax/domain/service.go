@@ -5,11 +5,86 @@ import (
 	"time"
 
 	"github.com/bwmarrin/snowflake"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
 )
 
 // TaxResolver returns the active tax definition for an invoice context.
 type TaxResolver interface {
 	ResolveForInvoice(ctx context.Context, orgID, customerID snowflake.ID) (*TaxDefinition, error)
+	// CalculateForInvoice applies the tax rules of the customer's
+	// jurisdiction to the invoice lines.
+	CalculateForInvoice(ctx context.Context, req InvoiceTaxRequest) (*InvoiceTaxResult, error)
+}
+
+// InvoiceTaxRequest carries what tax calculation needs from an invoice.
+type InvoiceTaxRequest struct {
+	OrgID      snowflake.ID
+	CustomerID snowflake.ID
+	Lines      []TaxableLine
+}
+
+// TaxableLine is an invoice line amount with the tax behavior of the price it
+// was rated from. An empty or inline behavior follows the tax mode of the
+// matched definition.
+type TaxableLine struct {
+	Amount      int64
+	TaxBehavior pricedomain.TaxBehavior
+}
+
+// TaxLine is the tax owed in one jurisdiction for the lines sharing a tax mode.
+type TaxLine struct {
+	Code          string
+	Name          string
+	Mode          TaxMode
+	Rate          float64
+	Jurisdiction  string
+	TaxableAmount int64
+	Amount        int64
+	Note          *string
+}
+
+// InvoiceTaxResult is the tax computed for an invoice. ReverseCharge marks an
+// invoice on which the customer accounts for VAT: it carries a zero tax line
+// with the reverse-charge note.
+type InvoiceTaxResult struct {
+	Lines         []TaxLine
+	ReverseCharge bool
+}
+
+// TotalAmount returns the tax across all lines.
+func (r *InvoiceTaxResult) TotalAmount() int64 {
+	if r == nil {
+		return 0
+	}
+	var total int64
+	for _, line := range r.Lines {
+		total += line.Amount
+	}
+	return total
+}
+
+// ExclusiveAmount returns the tax of the exclusive lines, the part added on
+// top of the subtotal. Inclusive tax is already part of the line amounts.
+func (r *InvoiceTaxResult) ExclusiveAmount() int64 {
+	if r == nil {
+		return 0
+	}
+	var total int64
+	for _, line := range r.Lines {
+		if line.Mode == TaxModeExclusive {
+			total += line.Amount
+		}
+	}
+	return total
+}
+
+// CustomerTaxProfile is the customer and seller location used to pick tax
+// rules.
+type CustomerTaxProfile struct {
+	Country       string
+	State         string
+	TaxID         string
+	SellerCountry string
 }
 
 type Service interface {
@@ -32,6 +107,8 @@ type CreateRequest struct {
 	Name        string   `json:"name"`
 	TaxMode     TaxMode  `json:"tax_mode"`
 	Rate        *float64 `json:"rate"`
+	Country     *string  `json:"country"`
+	State       *string  `json:"state"`
 	Description *string  `json:"description"`
 	IsEnabled   *bool    `json:"is_enabled"`
 }
@@ -41,6 +118,8 @@ type UpdateRequest struct {
 	Name        *string  `json:"name,omitempty"`
 	TaxMode     *TaxMode `json:"tax_mode,omitempty"`
 	Rate        *float64 `json:"rate,omitempty"`
+	Country     *string  `json:"country,omitempty"`
+	State       *string  `json:"state,omitempty"`
 	Description *string  `json:"description,omitempty"`
 }
 
@@ -51,6 +130,8 @@ type Response struct {
 	Name           string    `json:"name"`
 	TaxMode        TaxMode   `json:"tax_mode"`
 	Rate           *float64  `json:"rate,omitempty"`
+	Country        *string   `json:"country,omitempty"`
+	State          *string   `json:"state,omitempty"`
 	Description    *string   `json:"description,omitempty"`
 	IsEnabled      bool      `json:"is_enabled"`
 	CreatedAt      time.Time `json:"created_at"`
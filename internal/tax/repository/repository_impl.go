@@ -20,9 +20,9 @@ func NewRepository(db *gorm.DB) taxdomain.Repository {
 func (r *repository) GetActiveTaxDefinition(ctx context.Context, orgID snowflake.ID) (*taxdomain.TaxDefinition, error) {
 	var def taxdomain.TaxDefinition
 	err := r.db.WithContext(ctx).Raw(
-		`SELECT id, org_id, name, code, tax_mode, rate, description, is_enabled, created_at, updated_at
+		`SELECT id, org_id, name, code, tax_mode, rate, country, state, description, is_enabled, created_at, updated_at
 		 FROM tax_definitions
-		 WHERE org_id = ? AND is_enabled = true
+		 WHERE org_id = ? AND is_enabled = true AND country IS NULL
 		 ORDER BY id ASC
 		 LIMIT 1`,
 		orgID,
@@ -36,17 +36,64 @@ func (r *repository) GetActiveTaxDefinition(ctx context.Context, orgID snowflake
 	return &def, nil
 }
 
+func (r *repository) ListActiveTaxDefinitions(ctx context.Context, orgID snowflake.ID) ([]taxdomain.TaxDefinition, error) {
+	var defs []taxdomain.TaxDefinition
+	err := r.db.WithContext(ctx).Raw(
+		`SELECT id, org_id, name, code, tax_mode, rate, country, state, description, is_enabled, created_at, updated_at
+		 FROM tax_definitions
+		 WHERE org_id = ? AND is_enabled = true
+		 ORDER BY id ASC`,
+		orgID,
+	).Scan(&defs).Error
+	if err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+func (r *repository) FindCustomerTaxProfile(ctx context.Context, orgID, customerID snowflake.ID) (*taxdomain.CustomerTaxProfile, error) {
+	var row struct {
+		ID            snowflake.ID
+		Country       *string
+		State         *string
+		TaxID         *string
+		SellerCountry *string
+	}
+	err := r.db.WithContext(ctx).Raw(
+		`SELECT c.id, c.country, c.state, c.tax_id, o.country_code AS seller_country
+		 FROM customers c
+		 LEFT JOIN organizations o ON o.id = c.org_id
+		 WHERE c.org_id = ? AND c.id = ?`,
+		orgID,
+		customerID,
+	).Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	if row.ID == 0 {
+		return nil, nil
+	}
+	return &taxdomain.CustomerTaxProfile{
+		Country:       derefString(row.Country),
+		State:         derefString(row.State),
+		TaxID:         derefString(row.TaxID),
+		SellerCountry: derefString(row.SellerCountry),
+	}, nil
+}
+
 func (r *repository) Create(ctx context.Context, def *taxdomain.TaxDefinition) error {
 	return r.db.WithContext(ctx).Exec(
 		`INSERT INTO tax_definitions (
-			id, org_id, name, code, tax_mode, rate, description, is_enabled, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, org_id, name, code, tax_mode, rate, country, state, description, is_enabled, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		def.ID,
 		def.OrgID,
 		def.Name,
 		def.Code,
 		def.TaxMode,
 		def.Rate,
+		def.Country,
+		def.State,
 		def.Description,
 		def.IsEnabled,
 		def.CreatedAt,
@@ -57,7 +104,7 @@ func (r *repository) Create(ctx context.Context, def *taxdomain.TaxDefinition) e
 func (r *repository) FindByID(ctx context.Context, orgID, id snowflake.ID) (*taxdomain.TaxDefinition, error) {
 	var def taxdomain.TaxDefinition
 	err := r.db.WithContext(ctx).Raw(
-		`SELECT id, org_id, name, code, tax_mode, rate, description, is_enabled, created_at, updated_at
+		`SELECT id, org_id, name, code, tax_mode, rate, country, state, description, is_enabled, created_at, updated_at
 		 FROM tax_definitions
 		 WHERE org_id = ? AND id = ?`,
 		orgID,
@@ -103,11 +150,13 @@ func (r *repository) List(ctx context.Context, orgID snowflake.ID, filter taxdom
 func (r *repository) Update(ctx context.Context, def *taxdomain.TaxDefinition) error {
 	return r.db.WithContext(ctx).Exec(
 		`UPDATE tax_definitions
-		 SET name = ?, tax_mode = ?, rate = ?, description = ?, is_enabled = ?, updated_at = ?
+		 SET name = ?, tax_mode = ?, rate = ?, country = ?, state = ?, description = ?, is_enabled = ?, updated_at = ?
 		 WHERE org_id = ? AND id = ?`,
 		def.Name,
 		def.TaxMode,
 		def.Rate,
+		def.Country,
+		def.State,
 		def.Description,
 		def.IsEnabled,
 		def.UpdatedAt,
@@ -115,3 +164,10 @@ func (r *repository) Update(ctx context.Context, def *taxdomain.TaxDefinition) e
 		def.ID,
 	).Error
 }
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
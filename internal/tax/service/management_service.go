@@ -83,6 +83,11 @@ func (s *Service) Create(ctx context.Context, req taxdomain.CreateRequest) (*tax
 		descriptionPtr = &description
 	}
 
+	country, state, err := normalizeJurisdiction(req.Country, req.State)
+	if err != nil {
+		return nil, err
+	}
+
 	isEnabled := true
 	if req.IsEnabled != nil {
 		isEnabled = *req.IsEnabled
@@ -96,6 +101,8 @@ func (s *Service) Create(ctx context.Context, req taxdomain.CreateRequest) (*tax
 		Code:        code,
 		TaxMode:     normalizeTaxMode(req.TaxMode),
 		Rate:        req.Rate,
+		Country:     country,
+		State:       state,
 		Description: descriptionPtr,
 		IsEnabled:   isEnabled,
 		CreatedAt:   now,
@@ -145,6 +152,19 @@ func (s *Service) Update(ctx context.Context, req taxdomain.UpdateRequest) (*tax
 	if req.Rate != nil {
 		item.Rate = req.Rate
 	}
+	if req.Country != nil || req.State != nil {
+		country, state := req.Country, req.State
+		if country == nil {
+			country = item.Country
+		}
+		if state == nil {
+			state = item.State
+		}
+		item.Country, item.State, err = normalizeJurisdiction(country, state)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if req.Description != nil {
 		description := strings.TrimSpace(*req.Description)
 		if description == "" {
@@ -204,6 +224,8 @@ func toResponse(def *taxdomain.TaxDefinition) taxdomain.Response {
 		Name:           def.Name,
 		TaxMode:        def.TaxMode,
 		Rate:           def.Rate,
+		Country:        def.Country,
+		State:          def.State,
 		Description:    def.Description,
 		IsEnabled:      def.IsEnabled,
 		CreatedAt:      def.CreatedAt,
@@ -215,6 +237,26 @@ func normalizeTaxMode(value taxdomain.TaxMode) taxdomain.TaxMode {
 	return taxdomain.TaxMode(strings.ToLower(strings.TrimSpace(string(value))))
 }
 
+// normalizeJurisdiction uppercases the country and state and clears blank
+// values, so an empty country turns a definition back into the fallback.
+func normalizeJurisdiction(country, state *string) (*string, *string, error) {
+	c := strings.ToUpper(strings.TrimSpace(ptrToString(country)))
+	st := strings.ToUpper(strings.TrimSpace(ptrToString(state)))
+	if c == "" {
+		if st != "" {
+			return nil, nil, taxdomain.ErrInvalidCountry
+		}
+		return nil, nil, nil
+	}
+	if len(c) != 2 {
+		return nil, nil, taxdomain.ErrInvalidCountry
+	}
+	if st == "" {
+		return &c, nil, nil
+	}
+	return &c, &st, nil
+}
+
 func ptrToString(value *string) string {
 	if value == nil {
 		return ""
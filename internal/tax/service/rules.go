@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	taxdomain "github.com/railzwaylabs/railzway/internal/tax/domain"
+)
+
+// euMemberStates maps EU member states to the prefix of their VAT IDs.
+// Greece issues VAT IDs prefixed EL rather than its ISO code.
+var euMemberStates = map[string]string{
+	"AT": "AT", "BE": "BE", "BG": "BG", "CY": "CY", "CZ": "CZ", "DE": "DE",
+	"DK": "DK", "EE": "EE", "ES": "ES", "FI": "FI", "FR": "FR", "GR": "EL",
+	"HR": "HR", "HU": "HU", "IE": "IE", "IT": "IT", "LT": "LT", "LU": "LU",
+	"LV": "LV", "MT": "MT", "NL": "NL", "PL": "PL", "PT": "PT", "RO": "RO",
+	"SE": "SE", "SI": "SI", "SK": "SK",
+}
+
+var vatIDPattern = regexp.MustCompile(`^[A-Z]{2}[0-9A-Z]{2,12}$`)
+
+// CalculateForInvoice matches the customer's jurisdiction to the org's tax
+// definitions and taxes the invoice lines. Lines priced inclusive or
+// exclusive keep that behavior; the rest follow the definition's mode.
+//
+// An EU business customer with a well-formed VAT ID, buying from a seller
+// outside its country, is reverse charged: the rule's VAT becomes a zero tax
+// line with a note, since the customer accounts for it.
+func (r *resolver) CalculateForInvoice(ctx context.Context, req taxdomain.InvoiceTaxRequest) (*taxdomain.InvoiceTaxResult, error) {
+	profile, err := r.repo.FindCustomerTaxProfile(ctx, req.OrgID, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = &taxdomain.CustomerTaxProfile{}
+	}
+
+	defs, err := r.repo.ListActiveTaxDefinitions(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	def := matchDefinition(defs, profile.Country, profile.State)
+	if def == nil || def.Rate == nil || *def.Rate <= 0 {
+		return &taxdomain.InvoiceTaxResult{}, nil
+	}
+
+	jurisdiction := def.Jurisdiction()
+	if jurisdiction == "" {
+		jurisdiction = profile.Country
+	}
+
+	if isReverseCharge(profile) {
+		var taxable int64
+		for _, line := range req.Lines {
+			taxable += line.Amount
+		}
+		note := fmt.Sprintf("Reverse charge: VAT to be accounted for by the recipient (VAT ID %s)", profile.TaxID)
+		return &taxdomain.InvoiceTaxResult{
+			Lines: []taxdomain.TaxLine{{
+				Code:          def.Code,
+				Name:          def.Name,
+				Mode:          taxdomain.TaxModeExclusive,
+				Rate:          0,
+				Jurisdiction:  profile.Country,
+				TaxableAmount: taxable,
+				Amount:        0,
+				Note:          &note,
+			}},
+			ReverseCharge: true,
+		}, nil
+	}
+
+	taxable := map[taxdomain.TaxMode]int64{}
+	for _, line := range req.Lines {
+		taxable[lineTaxMode(line.TaxBehavior, def.TaxMode)] += line.Amount
+	}
+
+	result := &taxdomain.InvoiceTaxResult{}
+	for _, mode := range []taxdomain.TaxMode{taxdomain.TaxModeExclusive, taxdomain.TaxModeInclusive} {
+		amount, ok := taxable[mode]
+		if !ok {
+			continue
+		}
+		var tax int64
+		if mode == taxdomain.TaxModeInclusive {
+			tax = computeTaxInclusive(amount, def.Rate)
+		} else {
+			tax = computeTaxExclusive(amount, def.Rate)
+		}
+		result.Lines = append(result.Lines, taxdomain.TaxLine{
+			Code:          def.Code,
+			Name:          def.Name,
+			Mode:          mode,
+			Rate:          *def.Rate,
+			Jurisdiction:  jurisdiction,
+			TaxableAmount: amount,
+			Amount:        tax,
+		})
+	}
+	return result, nil
+}
+
+// matchDefinition picks the most specific definition for the jurisdiction:
+// country and state, then country, then the org-wide fallback. Ties go to
+// the oldest definition.
+func matchDefinition(defs []taxdomain.TaxDefinition, country, state string) *taxdomain.TaxDefinition {
+	var best *taxdomain.TaxDefinition
+	bestScore := -1
+	for i := range defs {
+		def := &defs[i]
+		score := 0
+		if def.Country != nil {
+			if !strings.EqualFold(*def.Country, country) {
+				continue
+			}
+			score = 1
+			if def.State != nil {
+				if !strings.EqualFold(*def.State, state) {
+					continue
+				}
+				score = 2
+			}
+		}
+		if score > bestScore {
+			best = def
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// isReverseCharge reports whether the customer is an EU business buying
+// across a border. The VAT ID is checked for format and country prefix only.
+func isReverseCharge(profile *taxdomain.CustomerTaxProfile) bool {
+	prefix, ok := euMemberStates[strings.ToUpper(profile.Country)]
+	if !ok {
+		return false
+	}
+	if strings.EqualFold(profile.SellerCountry, profile.Country) {
+		return false
+	}
+	return isValidVATID(profile.TaxID, prefix)
+}
+
+func isValidVATID(taxID, prefix string) bool {
+	taxID = strings.ToUpper(strings.TrimSpace(taxID))
+	return vatIDPattern.MatchString(taxID) && strings.HasPrefix(taxID, prefix)
+}
+
+func lineTaxMode(behavior pricedomain.TaxBehavior, fallback taxdomain.TaxMode) taxdomain.TaxMode {
+	switch pricedomain.TaxBehavior(strings.ToUpper(string(behavior))) {
+	case pricedomain.Inclusive:
+		return taxdomain.TaxModeInclusive
+	case pricedomain.Exclusive:
+		return taxdomain.TaxModeExclusive
+	default:
+		return fallback
+	}
+}
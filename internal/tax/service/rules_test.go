@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	customerdomain "github.com/railzwaylabs/railzway/internal/customer/domain"
+	organizationdomain "github.com/railzwaylabs/railzway/internal/organization/domain"
+	pricedomain "github.com/railzwaylabs/railzway/internal/price/domain"
+	taxdomain "github.com/railzwaylabs/railzway/internal/tax/domain"
+	"github.com/railzwaylabs/railzway/internal/tax/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestCalculateForInvoice_AppliesJurisdictionRules(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&organizationdomain.Organization{},
+		&customerdomain.Customer{},
+		&taxdomain.TaxDefinition{},
+	))
+
+	node, _ := snowflake.NewNode(1)
+	orgID := node.Generate()
+	require.NoError(t, db.Create(&organizationdomain.Organization{ID: orgID, Name: "Acme", Slug: "acme", CountryCode: "FR"}).Error)
+
+	define := func(code string, mode taxdomain.TaxMode, rate float64, country, state string) {
+		def := taxdomain.TaxDefinition{ID: node.Generate(), OrgID: orgID, Name: code, Code: code, TaxMode: mode, Rate: &rate, IsEnabled: true}
+		if country != "" {
+			def.Country = &country
+		}
+		if state != "" {
+			def.State = &state
+		}
+		require.NoError(t, db.Create(&def).Error)
+	}
+	define("DEFAULT", taxdomain.TaxModeExclusive, 0.10, "", "")
+	define("US_SALES_TAX", taxdomain.TaxModeExclusive, 0.05, "US", "")
+	define("US_CA_SALES_TAX", taxdomain.TaxModeExclusive, 0.0725, "US", "CA")
+	define("DE_VAT", taxdomain.TaxModeInclusive, 0.19, "DE", "")
+	define("FR_VAT", taxdomain.TaxModeExclusive, 0.20, "FR", "")
+
+	customer := func(country, state, taxID string) snowflake.ID {
+		c := customerdomain.Customer{ID: node.Generate(), OrgID: orgID, Name: "Buyer", Email: "buyer@example.com", Country: country, State: state, TaxID: taxID}
+		require.NoError(t, db.Create(&c).Error)
+		return c.ID
+	}
+
+	resolver := NewResolver(resolverParam{Repository: repository.NewRepository(db)})
+	ctx := context.Background()
+	calculate := func(customerID snowflake.ID, lines ...taxdomain.TaxableLine) *taxdomain.InvoiceTaxResult {
+		result, err := resolver.CalculateForInvoice(ctx, taxdomain.InvoiceTaxRequest{OrgID: orgID, CustomerID: customerID, Lines: lines})
+		require.NoError(t, err)
+		return result
+	}
+
+	// The state rule beats the country rule; each price keeps its behavior.
+	result := calculate(customer("US", "CA", ""),
+		taxdomain.TaxableLine{Amount: 10000, TaxBehavior: pricedomain.Exclusive},
+		taxdomain.TaxableLine{Amount: 10725, TaxBehavior: pricedomain.Inclusive},
+		taxdomain.TaxableLine{Amount: -2000},
+	)
+	require.Len(t, result.Lines, 2)
+	assert.Equal(t, "US_CA_SALES_TAX", result.Lines[0].Code)
+	assert.Equal(t, "US-CA", result.Lines[0].Jurisdiction)
+	assert.Equal(t, taxdomain.TaxModeExclusive, result.Lines[0].Mode)
+	assert.Equal(t, int64(8000), result.Lines[0].TaxableAmount)
+	assert.Equal(t, int64(580), result.Lines[0].Amount)
+	assert.Equal(t, taxdomain.TaxModeInclusive, result.Lines[1].Mode)
+	assert.Equal(t, int64(725), result.Lines[1].Amount)
+	assert.Equal(t, int64(1305), result.TotalAmount())
+
+	result = calculate(customer("US", "NY", ""), taxdomain.TaxableLine{Amount: 10000})
+	require.Len(t, result.Lines, 1)
+	assert.Equal(t, "US_SALES_TAX", result.Lines[0].Code)
+	assert.Equal(t, "US", result.Lines[0].Jurisdiction)
+	assert.Equal(t, int64(500), result.Lines[0].Amount)
+
+	result = calculate(customer("", "", ""), taxdomain.TaxableLine{Amount: 10000})
+	require.Len(t, result.Lines, 1)
+	assert.Equal(t, "DEFAULT", result.Lines[0].Code)
+	assert.Equal(t, int64(1000), result.Lines[0].Amount)
+
+	// An EU business abroad is reverse charged; without a valid VAT ID, or in
+	// the seller's own country, VAT applies.
+	result = calculate(customer("DE", "", "DE123456789"), taxdomain.TaxableLine{Amount: 11900})
+	assert.True(t, result.ReverseCharge)
+	require.Len(t, result.Lines, 1)
+	assert.Equal(t, "DE_VAT", result.Lines[0].Code)
+	assert.Zero(t, result.Lines[0].Amount)
+	assert.Zero(t, result.Lines[0].Rate)
+	assert.Equal(t, int64(11900), result.Lines[0].TaxableAmount)
+	require.NotNil(t, result.Lines[0].Note)
+	assert.Contains(t, *result.Lines[0].Note, "DE123456789")
+
+	result = calculate(customer("DE", "", "FR123456789"), taxdomain.TaxableLine{Amount: 11900})
+	assert.False(t, result.ReverseCharge)
+	require.Len(t, result.Lines, 1)
+	assert.Equal(t, int64(1900), result.Lines[0].Amount)
+
+	result = calculate(customer("FR", "", "FR12345678901"), taxdomain.TaxableLine{Amount: 10000})
+	assert.False(t, result.ReverseCharge)
+	require.Len(t, result.Lines, 1)
+	assert.Equal(t, int64(2000), result.Lines[0].Amount)
+}
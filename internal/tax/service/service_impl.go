@@ -23,15 +23,25 @@ func NewResolver(p resolverParam) taxdomain.TaxResolver {
 	return &resolver{repo: p.Repository}
 }
 
+// ResolveForInvoice returns the definition that applies to the customer's
+// jurisdiction, or nil when it charges no tax.
 func (r *resolver) ResolveForInvoice(ctx context.Context, orgID, customerID snowflake.ID) (*taxdomain.TaxDefinition, error) {
-	def, err := r.repo.GetActiveTaxDefinition(ctx, orgID)
+	profile, err := r.repo.FindCustomerTaxProfile(ctx, orgID, customerID)
 	if err != nil {
 		return nil, err
 	}
+	if profile == nil {
+		profile = &taxdomain.CustomerTaxProfile{}
+	}
+
+	defs, err := r.repo.ListActiveTaxDefinitions(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	def := matchDefinition(defs, profile.Country, profile.State)
 	if def == nil || def.Rate == nil || *def.Rate <= 0 {
 		return nil, nil
 	}
-	// customerID reserved for future customer-specific tax logic.
 	return def, nil
 }
 
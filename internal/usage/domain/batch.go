@@ -0,0 +1,29 @@
+package domain
+
+// MaxBatchIngestSize is the maximum number of events accepted by one batch
+// ingestion request.
+const MaxBatchIngestSize = 500
+
+const (
+	BatchEventStatusIngested  = "ingested"
+	BatchEventStatusDuplicate = "duplicate"
+	BatchEventStatusRejected  = "rejected"
+)
+
+// BatchIngestResult reports the outcome of a single event of a batch.
+type BatchIngestResult struct {
+	Index          int    `json:"index"`
+	Status         string `json:"status"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	UsageEventID   string `json:"usage_event_id,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+	Warning        string `json:"warning,omitempty"`
+}
+
+// BatchIngestResponse lists per-event results in request order.
+type BatchIngestResponse struct {
+	Results    []BatchIngestResult `json:"results"`
+	Ingested   int                 `json:"ingested"`
+	Duplicates int                 `json:"duplicates"`
+	Rejected   int                 `json:"rejected"`
+}
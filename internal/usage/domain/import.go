@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// DefaultImportBackfillWindow bounds how far in the past imported usage may be
+// recorded when the request does not override it.
+const DefaultImportBackfillWindow = 90 * 24 * time.Hour
+
+// ImportBatchSize is the number of CSV rows validated before a batch insert.
+const ImportBatchSize = 500
+
+// Import CSV columns. The header row is required; column order is free.
+const (
+	ImportColumnMeterCode          = "meter_code"
+	ImportColumnCustomerExternalID = "customer_external_id"
+	ImportColumnValue              = "value"
+	ImportColumnRecordedAt         = "recorded_at"
+	ImportColumnIdempotencyKey     = "idempotency_key"
+)
+
+const (
+	ImportRowStatusSuccess = "success"
+	ImportRowStatusSkipped = "skipped"
+	ImportRowStatusError   = "error"
+)
+
+// ImportUsageRequest configures a bulk CSV usage import.
+type ImportUsageRequest struct {
+	// BackfillWindow limits how old recorded_at may be. Zero uses the default.
+	BackfillWindow time.Duration `json:"-"`
+}
+
+// ImportRowResult reports the outcome of a single CSV data row.
+type ImportRowResult struct {
+	Row            int    `json:"row"`
+	Status         string `json:"status"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	UsageEventID   string `json:"usage_event_id,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+// ImportUsageSummary totals the rows processed by an import.
+type ImportUsageSummary struct {
+	ImportID  string `json:"import_id"`
+	Total     int    `json:"total"`
+	Succeeded int    `json:"succeeded"`
+	Skipped   int    `json:"skipped"`
+	Failed    int    `json:"failed"`
+}
@@ -2,6 +2,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
@@ -17,6 +18,31 @@ const (
 	UsageStatusUnmatchedSubscription = "unmatched_subscription"
 )
 
+// Usage event types. Adjustments correct usage reported earlier on a sum
+// meter and may be negative; rating nets them out.
+const (
+	UsageTypeUsage      = "usage"
+	UsageTypeAdjustment = "adjustment"
+)
+
+// WarningSubscriptionPastDue is reported for usage accepted while the
+// subscription is past due. Usage is still billed during the grace period.
+const WarningSubscriptionPastDue = "subscription_past_due"
+
+// NormalizeUsageType returns the canonical form of value, defaulting to
+// usage, and whether it is a supported type.
+func NormalizeUsageType(value string) (string, bool) {
+	usageType := strings.ToLower(strings.TrimSpace(value))
+	switch usageType {
+	case "":
+		return UsageTypeUsage, true
+	case UsageTypeUsage, UsageTypeAdjustment:
+		return usageType, true
+	default:
+		return "", false
+	}
+}
+
 // UsageEvent stores a single unit of metered activity.
 type UsageEvent struct {
 	ID         snowflake.ID `gorm:"primaryKey" json:"id"`
@@ -34,14 +60,21 @@ type UsageEvent struct {
 
 	MeterCode      string            `gorm:"type:text;not null" json:"meter_code"`
 	Value          float64           `gorm:"not null" json:"value"`
+	Type           string            `gorm:"type:text;not null;default:usage" json:"type"`
 	RecordedAt     time.Time         `gorm:"not null" json:"recorded_at"`
 	Status         string            `gorm:"type:text;not null;default:accepted" json:"-"`
 	Error          *string           `gorm:"type:text" json:"-"`
 	IdempotencyKey string            `gorm:"type:text" json:"idempotency_key"`
 	Metadata       datatypes.JSONMap `gorm:"type:jsonb" json:"metadata"`
+	Dimensions     datatypes.JSONMap `gorm:"type:jsonb" json:"dimensions,omitempty"`
 	SnapshotAt     *time.Time        `gorm:"" json:"-"`
+	ImportID       *snowflake.ID     `gorm:"" json:"import_id,omitempty"`
+	RolledUpAt     *time.Time        `gorm:"" json:"-"`
 	CreatedAt      time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"-"`
 	UpdatedAt      time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"-"`
+
+	// Warning is set on ingestion responses only and never stored.
+	Warning string `gorm:"-" json:"warning,omitempty"`
 }
 
 // TableName sets the database table name.
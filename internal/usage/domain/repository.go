@@ -2,7 +2,9 @@ package domain
 
 import (
 	"context"
+	"time"
 
+	"github.com/bwmarrin/snowflake"
 	"gorm.io/gorm"
 )
 
@@ -11,3 +13,14 @@ type SnapshotRepository interface {
 	LockAccepted(ctx context.Context, db *gorm.DB, limit int) ([]SnapshotCandidate, error)
 	UpdateSnapshot(ctx context.Context, db *gorm.DB, update SnapshotUpdate) error
 }
+
+// RollupRepository provides locking and update operations for hourly usage
+// rollups.
+type RollupRepository interface {
+	// LockPendingRollup locks enriched and rated events recorded before the
+	// cutoff that are not yet counted in a rollup.
+	LockPendingRollup(ctx context.Context, db *gorm.DB, cutoff time.Time, limit int) ([]RollupCandidate, error)
+	// AddToRollup merges rollup into the stored rollup of the same hour.
+	AddToRollup(ctx context.Context, db *gorm.DB, rollup UsageRollup) error
+	MarkRolledUp(ctx context.Context, db *gorm.DB, ids []snowflake.ID, at time.Time) error
+}
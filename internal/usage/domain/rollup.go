@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+)
+
+// UsageRollup aggregates the enriched usage of one meter on one subscription
+// within an hour, so rating reads closed hours without rescanning raw events.
+type UsageRollup struct {
+	OrgID          snowflake.ID `gorm:"primaryKey" json:"org_id"`
+	SubscriptionID snowflake.ID `gorm:"primaryKey" json:"subscription_id"`
+	MeterID        snowflake.ID `gorm:"primaryKey" json:"meter_id"`
+	BucketStart    time.Time    `gorm:"primaryKey" json:"bucket_start"`
+	SumValue       float64      `gorm:"not null;default:0" json:"sum_value"`
+	MaxValue       float64      `gorm:"not null;default:0" json:"max_value"`
+	EventCount     int64        `gorm:"not null;default:0" json:"event_count"`
+	LastValue      float64      `gorm:"not null;default:0" json:"last_value"`
+	LastRecordedAt time.Time    `gorm:"not null" json:"last_recorded_at"`
+	LastEventID    snowflake.ID `gorm:"not null" json:"last_event_id"`
+	CreatedAt      time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName sets the database table name.
+func (UsageRollup) TableName() string { return "usage_rollups" }
+
+// RollupCandidate is an enriched usage event not yet counted in a rollup.
+type RollupCandidate struct {
+	ID             snowflake.ID
+	OrgID          snowflake.ID
+	SubscriptionID snowflake.ID
+	MeterID        snowflake.ID
+	Value          float64
+	RecordedAt     time.Time
+}
+
+// RollupBucket returns the hour a usage event recorded at t is rolled up in.
+func RollupBucket(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour)
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"io"
 	"time"
 
 	"github.com/railzwaylabs/railzway/pkg/db/pagination"
@@ -13,14 +14,22 @@ type CreateIngestRequest struct {
 	MeterCode  string `json:"meter_code" validate:"required,min=1"`
 
 	// Usage can be zero or fractional; semantics resolved in rating.
+	// Only adjustments may be negative.
 	Value float64 `json:"value" validate:"required"`
 
+	// Type is usage by default. An adjustment corrects earlier usage of a
+	// SUM meter, e.g. a negative value to take back over-reported usage.
+	Type string `json:"type,omitempty"`
+
 	RecordedAt time.Time `json:"recorded_at" validate:"required"`
 
 	// Required; must be non-empty. Uniqueness enforced at DB level.
 	IdempotencyKey string `json:"idempotency_key" validate:"required,min=1"`
 
 	Metadata map[string]any `json:"metadata,omitempty"`
+
+	// Dimensions tag the event for per-dimension rating; keys and values must be strings.
+	Dimensions map[string]any `json:"dimensions,omitempty"`
 }
 
 type ListUsageRequest struct {
@@ -29,6 +38,7 @@ type ListUsageRequest struct {
 	MeterID        string     `json:"meter_id"`
 	MeterCode      string     `json:"meter_code"`
 	Status         string     `json:"status"`
+	ImportID       string     `json:"import_id"`
 	PageToken      string     `json:"page_token"`
 	PageSize       int32      `json:"page_size"`
 	RecordedFrom   *time.Time `json:"recorded_from,omitempty"`
@@ -42,8 +52,10 @@ type ListUsageResponse struct {
 
 type Service interface {
 	Ingest(context.Context, CreateIngestRequest) (*UsageEvent, error)
+	BatchIngest(context.Context, []CreateIngestRequest) (BatchIngestResponse, error)
 	List(context.Context, ListUsageRequest) (ListUsageResponse, error)
 	GetUsageSummary(context.Context, UsageSummaryRequest) (map[string]float64, error)
+	ImportCSV(context.Context, ImportUsageRequest, io.Reader, func(ImportRowResult) error) (ImportUsageSummary, error)
 }
 
 type UsageSummaryRequest struct {
@@ -63,4 +75,15 @@ var (
 	ErrInvalidRecordedAt       = errors.New("invalid_recorded_at")
 	ErrInvalidIdempotencyKey   = errors.New("invalid_idempotency_key")
 	ErrFeatureNotEntitled      = errors.New("feature_not_entitled")
+	ErrInvalidImportFile       = errors.New("invalid_import_file")
+	ErrInvalidImportID         = errors.New("invalid_import_id")
+	ErrInvalidBackfillWindow   = errors.New("invalid_backfill_window")
+	ErrOutsideBackfillWindow   = errors.New("outside_backfill_window")
+	ErrDuplicateIdempotencyKey = errors.New("duplicate_idempotency_key")
+	ErrInvalidBatchSize        = errors.New("invalid_batch_size")
+	ErrSubscriptionPaused      = errors.New("subscription_paused")
+	ErrInvalidDimensions       = errors.New("invalid_dimensions")
+	ErrUsageWindowClosed       = errors.New("usage_window_closed")
+	ErrInvalidUsageType        = errors.New("invalid_usage_type")
+	ErrAdjustmentNotSupported  = errors.New("adjustment_not_supported")
 )
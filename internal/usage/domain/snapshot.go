@@ -22,5 +22,7 @@ type SnapshotUpdate struct {
 	SubscriptionItemID *snowflake.ID
 	MeterID            *snowflake.ID
 	Status             string
-	SnapshotAt         time.Time
+	// Error records why an event was marked invalid.
+	Error      *string
+	SnapshotAt time.Time
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/railzwaylabs/railzway/internal/cache"
 	"github.com/railzwaylabs/railzway/internal/usage/liveevents"
 	"github.com/railzwaylabs/railzway/internal/usage/repository"
+	"github.com/railzwaylabs/railzway/internal/usage/rollup"
 	"github.com/railzwaylabs/railzway/internal/usage/service"
 	"github.com/railzwaylabs/railzway/internal/usage/snapshot"
 	"go.uber.org/fx"
@@ -12,7 +13,9 @@ import (
 var Module = fx.Module("usage.service",
 	fx.Provide(cache.NewUsageResolverCache),
 	fx.Provide(repository.ProvideSnapshot),
+	fx.Provide(repository.ProvideRollup),
 	liveevents.Module,
 	fx.Provide(service.NewService),
 	snapshot.Module,
+	rollup.Module,
 )
@@ -46,6 +46,7 @@ func (r *snapshotRepo) UpdateSnapshot(ctx context.Context, db *gorm.DB, update u
 		     subscription_item_id = ?,
 		     snapshot_at = ?,
 		     status = ?,
+		     error = ?,
 		     updated_at = ?
 		 WHERE id = ? AND status = ?`,
 		update.MeterID,
@@ -53,6 +54,7 @@ func (r *snapshotRepo) UpdateSnapshot(ctx context.Context, db *gorm.DB, update u
 		subscriptionItem,
 		update.SnapshotAt,
 		update.Status,
+		update.Error,
 		update.SnapshotAt,
 		update.ID,
 		usagedomain.UsageStatusAccepted,
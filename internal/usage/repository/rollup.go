@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"gorm.io/gorm"
+)
+
+type rollupRepo struct{}
+
+func ProvideRollup() usagedomain.RollupRepository {
+	return &rollupRepo{}
+}
+
+func (r *rollupRepo) LockPendingRollup(ctx context.Context, db *gorm.DB, cutoff time.Time, limit int) ([]usagedomain.RollupCandidate, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	query := `SELECT id, org_id, subscription_id, meter_id, value, recorded_at
+		 FROM usage_events
+		 WHERE status IN (?, ?) AND rolled_up_at IS NULL AND recorded_at < ?
+		 ORDER BY recorded_at ASC
+		 LIMIT ?`
+	if db.Dialector.Name() != "sqlite" {
+		query += " FOR UPDATE SKIP LOCKED"
+	}
+	var rows []usagedomain.RollupCandidate
+	err := db.WithContext(ctx).Raw(
+		query,
+		usagedomain.UsageStatusEnriched,
+		usagedomain.UsageStatusRated,
+		cutoff,
+		limit,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// AddToRollup adds the counts and sum of rollup to the stored hour, keeps the
+// larger maximum and the later of the two last values.
+func (r *rollupRepo) AddToRollup(ctx context.Context, db *gorm.DB, rollup usagedomain.UsageRollup) error {
+	return db.WithContext(ctx).Exec(
+		`INSERT INTO usage_rollups (
+			org_id, subscription_id, meter_id, bucket_start,
+			sum_value, max_value, event_count,
+			last_value, last_recorded_at, last_event_id,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (org_id, subscription_id, meter_id, bucket_start) DO UPDATE SET
+			sum_value = usage_rollups.sum_value + excluded.sum_value,
+			max_value = CASE WHEN excluded.max_value > usage_rollups.max_value
+				THEN excluded.max_value ELSE usage_rollups.max_value END,
+			event_count = usage_rollups.event_count + excluded.event_count,
+			last_value = CASE WHEN excluded.last_recorded_at > usage_rollups.last_recorded_at
+				OR (excluded.last_recorded_at = usage_rollups.last_recorded_at AND excluded.last_event_id > usage_rollups.last_event_id)
+				THEN excluded.last_value ELSE usage_rollups.last_value END,
+			last_event_id = CASE WHEN excluded.last_recorded_at > usage_rollups.last_recorded_at
+				OR (excluded.last_recorded_at = usage_rollups.last_recorded_at AND excluded.last_event_id > usage_rollups.last_event_id)
+				THEN excluded.last_event_id ELSE usage_rollups.last_event_id END,
+			last_recorded_at = CASE WHEN excluded.last_recorded_at > usage_rollups.last_recorded_at
+				THEN excluded.last_recorded_at ELSE usage_rollups.last_recorded_at END,
+			updated_at = excluded.updated_at`,
+		rollup.OrgID,
+		rollup.SubscriptionID,
+		rollup.MeterID,
+		rollup.BucketStart,
+		rollup.SumValue,
+		rollup.MaxValue,
+		rollup.EventCount,
+		rollup.LastValue,
+		rollup.LastRecordedAt,
+		rollup.LastEventID,
+		rollup.CreatedAt,
+		rollup.UpdatedAt,
+	).Error
+}
+
+func (r *rollupRepo) MarkRolledUp(ctx context.Context, db *gorm.DB, ids []snowflake.ID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.WithContext(ctx).Exec(
+		`UPDATE usage_events SET rolled_up_at = ? WHERE id IN ? AND rolled_up_at IS NULL`,
+		at,
+		ids,
+	).Error
+}
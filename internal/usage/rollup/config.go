@@ -0,0 +1,32 @@
+package rollup
+
+import "time"
+
+// Config controls the usage rollup worker loop.
+type Config struct {
+	BatchSize    int
+	PollInterval time.Duration
+	RunTimeout   time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BatchSize:    1000,
+		PollInterval: 30 * time.Second,
+		RunTimeout:   20 * time.Second,
+	}
+}
+
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaults.BatchSize
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaults.PollInterval
+	}
+	if c.RunTimeout <= 0 {
+		c.RunTimeout = defaults.RunTimeout
+	}
+	return c
+}
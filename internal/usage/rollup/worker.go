@@ -0,0 +1,171 @@
+package rollup
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Params struct {
+	fx.In
+
+	DB        *gorm.DB
+	Log       *zap.Logger
+	Clock     clock.Clock
+	UsageRepo usagedomain.RollupRepository
+	Config    Config `optional:"true"`
+}
+
+// Worker folds enriched usage events of closed hours into usage_rollups.
+// Each event is counted once: it is marked rolled up in the same transaction
+// that adds it to its hour.
+type Worker struct {
+	db        *gorm.DB
+	log       *zap.Logger
+	clock     clock.Clock
+	usageRepo usagedomain.RollupRepository
+	cfg       Config
+}
+
+func NewWorker(p Params) *Worker {
+	return &Worker{
+		db:        p.DB,
+		log:       p.Log.Named("usage.rollup"),
+		clock:     p.Clock,
+		usageRepo: p.UsageRepo,
+		cfg:       p.Config.withDefaults(),
+	}
+}
+
+func (w *Worker) RunForever(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.RunOnce(ctx); err != nil {
+			w.log.Warn("usage rollup run failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce rolls up batches until the backlog of closed hours is drained or
+// the run times out.
+func (w *Worker) RunOnce(parentCtx context.Context) error {
+	ctx, cancel := context.WithTimeout(parentCtx, w.cfg.RunTimeout)
+	defer cancel()
+
+	for {
+		processed, err := w.processBatch(ctx, w.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		if processed < w.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+func (w *Worker) processBatch(ctx context.Context, limit int) (int, error) {
+	now := w.clock.Now(ctx).UTC()
+	// Only closed hours are rolled up; the current hour is still filling.
+	cutoff := usagedomain.RollupBucket(now)
+
+	processed := 0
+	err := w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		events, err := w.usageRepo.LockPendingRollup(ctx, tx, cutoff, limit)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		for _, rollup := range buildRollups(events, now) {
+			if err := w.usageRepo.AddToRollup(ctx, tx, rollup); err != nil {
+				return err
+			}
+		}
+
+		ids := make([]snowflake.ID, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+		if err := w.usageRepo.MarkRolledUp(ctx, tx, ids, now); err != nil {
+			return err
+		}
+		processed = len(events)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return processed, nil
+}
+
+type rollupKey struct {
+	orgID          snowflake.ID
+	subscriptionID snowflake.ID
+	meterID        snowflake.ID
+	bucket         time.Time
+}
+
+// buildRollups groups events by subscription, meter and hour, in the order
+// the hours first appear.
+func buildRollups(events []usagedomain.RollupCandidate, now time.Time) []usagedomain.UsageRollup {
+	index := make(map[rollupKey]int)
+	var rollups []usagedomain.UsageRollup
+	for _, event := range events {
+		key := rollupKey{
+			orgID:          event.OrgID,
+			subscriptionID: event.SubscriptionID,
+			meterID:        event.MeterID,
+			bucket:         usagedomain.RollupBucket(event.RecordedAt),
+		}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(rollups)
+			rollups = append(rollups, usagedomain.UsageRollup{
+				OrgID:          event.OrgID,
+				SubscriptionID: event.SubscriptionID,
+				MeterID:        event.MeterID,
+				BucketStart:    key.bucket,
+				SumValue:       event.Value,
+				MaxValue:       event.Value,
+				EventCount:     1,
+				LastValue:      event.Value,
+				LastRecordedAt: event.RecordedAt.UTC(),
+				LastEventID:    event.ID,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			})
+			continue
+		}
+
+		rollup := &rollups[i]
+		rollup.SumValue += event.Value
+		rollup.EventCount++
+		if event.Value > rollup.MaxValue {
+			rollup.MaxValue = event.Value
+		}
+		recordedAt := event.RecordedAt.UTC()
+		if recordedAt.After(rollup.LastRecordedAt) ||
+			(recordedAt.Equal(rollup.LastRecordedAt) && event.ID > rollup.LastEventID) {
+			rollup.LastValue = event.Value
+			rollup.LastRecordedAt = recordedAt
+			rollup.LastEventID = event.ID
+		}
+	}
+	return rollups
+}
@@ -0,0 +1,128 @@
+package rollup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	"github.com/railzwaylabs/railzway/internal/clock"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	ratingrepository "github.com/railzwaylabs/railzway/internal/rating/repository"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/railzwaylabs/railzway/internal/usage/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TestWorker_RollsUpClosedHours rolls up usage and checks rating sees the
+// same totals from rollups as it did from raw events, including events that
+// arrive for an hour after it was rolled up.
+func TestWorker_RollsUpClosedHours(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&usagedomain.UsageEvent{}, &usagedomain.UsageRollup{}, &meterdomain.Meter{}))
+
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	orgID := node.Generate()
+	subID := node.Generate()
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(now)
+	worker := NewWorker(Params{
+		DB:        db,
+		Log:       zap.NewNop(),
+		Clock:     fakeClock,
+		UsageRepo: repository.ProvideRollup(),
+	})
+
+	meters := map[string]snowflake.ID{}
+	for _, aggregation := range []string{meterdomain.AggregationSum, meterdomain.AggregationMax, meterdomain.AggregationCount, meterdomain.AggregationLast} {
+		meter := meterdomain.Meter{
+			ID:          node.Generate(),
+			OrgID:       orgID,
+			Code:        "meter_" + aggregation,
+			Name:        aggregation,
+			Aggregation: aggregation,
+			Unit:        "unit",
+			Active:      true,
+		}
+		require.NoError(t, db.Create(&meter).Error)
+		meters[aggregation] = meter.ID
+	}
+
+	record := func(meterID snowflake.ID, value float64, at time.Time) {
+		require.NoError(t, db.Create(&usagedomain.UsageEvent{
+			ID:             node.Generate(),
+			OrgID:          orgID,
+			SubscriptionID: subID,
+			MeterID:        meterID,
+			Value:          value,
+			RecordedAt:     at,
+			Status:         usagedomain.UsageStatusEnriched,
+		}).Error)
+	}
+	for _, meterID := range meters {
+		record(meterID, 3, time.Date(2026, 3, 10, 9, 15, 0, 0, time.UTC))
+		record(meterID, 7, time.Date(2026, 3, 10, 9, 45, 0, 0, time.UTC))
+		record(meterID, 2, time.Date(2026, 3, 10, 10, 5, 0, 0, time.UTC))
+		// Still in the current hour, left for the next run.
+		record(meterID, 4, time.Date(2026, 3, 10, 12, 10, 0, 0, time.UTC))
+	}
+
+	require.NoError(t, worker.RunOnce(ctx))
+
+	var rollups []usagedomain.UsageRollup
+	require.NoError(t, db.Where("meter_id = ?", meters[meterdomain.AggregationSum]).Order("bucket_start ASC").Find(&rollups).Error)
+	require.Len(t, rollups, 2)
+	assert.Equal(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC), rollups[0].BucketStart.UTC())
+	assert.Equal(t, 10.0, rollups[0].SumValue)
+	assert.Equal(t, 7.0, rollups[0].MaxValue)
+	assert.Equal(t, int64(2), rollups[0].EventCount)
+	assert.Equal(t, 7.0, rollups[0].LastValue)
+
+	var pending int64
+	require.NoError(t, db.Model(&usagedomain.UsageEvent{}).Where("rolled_up_at IS NULL").Count(&pending).Error)
+	assert.Equal(t, int64(len(meters)), pending)
+
+	// A late event for an hour already rolled up is counted from raw until
+	// the next run folds it in.
+	for _, meterID := range meters {
+		record(meterID, 1, time.Date(2026, 3, 10, 9, 5, 0, 0, time.UTC))
+	}
+
+	ratingRepo := ratingrepository.NewRepository(db)
+	windowStart := time.Date(2026, 3, 10, 8, 30, 0, 0, time.UTC)
+	windowEnd := time.Date(2026, 3, 10, 12, 20, 0, 0, time.UTC)
+	expected := map[string]float64{
+		meterdomain.AggregationSum:   17,
+		meterdomain.AggregationMax:   7,
+		meterdomain.AggregationCount: 5,
+		meterdomain.AggregationLast:  4,
+	}
+	check := func() {
+		for aggregation, want := range expected {
+			qty, err := ratingRepo.AggregateUsage(ctx, orgID, subID, meters[aggregation], windowStart, windowEnd, nil)
+			require.NoError(t, err)
+			assert.Equal(t, want, qty, aggregation)
+		}
+	}
+	check()
+
+	fakeClock.Advance(time.Hour)
+	require.NoError(t, worker.RunOnce(ctx))
+	require.NoError(t, db.Model(&usagedomain.UsageEvent{}).Where("rolled_up_at IS NULL").Count(&pending).Error)
+	assert.Zero(t, pending)
+	check()
+
+	// The last value of a window ending inside a rolled-up hour comes from
+	// raw events, not the hour's rollup.
+	qty, err := ratingRepo.AggregateUsage(ctx, orgID, subID, meters[meterdomain.AggregationLast], windowStart, time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, qty)
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/railzwaylabs/railzway/internal/usage/liveevents"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// batchInsertSize bounds the rows of a single INSERT statement.
+const batchInsertSize = 100
+
+// batchSession memoizes lookups shared by the events of one batch.
+type batchSession struct {
+	orgID         snowflake.ID
+	now           time.Time
+	subscriptions map[string]subscriptiondomain.Subscription
+	meters        map[string]batchMeter
+	seen          map[string]int
+	// reRate holds closed cycles that accepted late usage in this batch.
+	reRate map[snowflake.ID]struct{}
+}
+
+// batchMeter is the part of a resolved meter the events of a batch need.
+type batchMeter struct {
+	Aggregation string
+}
+
+// BatchIngest validates and stores a batch of usage events. Each event goes
+// through the same meter and subscription checks as Ingest and is reported
+// on its own, so a rejected event never fails the batch. Accepted events
+// are written in a single transaction.
+func (s *Service) BatchIngest(
+	ctx context.Context,
+	reqs []usagedomain.CreateIngestRequest,
+) (usagedomain.BatchIngestResponse, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return usagedomain.BatchIngestResponse{}, usagedomain.ErrInvalidOrganization
+	}
+	if s.db == nil {
+		return usagedomain.BatchIngestResponse{}, errors.New("missing_db")
+	}
+	if len(reqs) == 0 || len(reqs) > usagedomain.MaxBatchIngestSize {
+		return usagedomain.BatchIngestResponse{}, usagedomain.ErrInvalidBatchSize
+	}
+	if s.subSvc == nil {
+		return usagedomain.BatchIngestResponse{}, errors.New("usage_ingestion_gating_unavailable")
+	}
+
+	if s.quotaSvc != nil {
+		if err := s.quotaSvc.CanIngestUsage(ctx, orgID); err != nil {
+			return usagedomain.BatchIngestResponse{}, err
+		}
+	}
+
+	session := &batchSession{
+		orgID:         orgID,
+		now:           time.Now().UTC(),
+		subscriptions: make(map[string]subscriptiondomain.Subscription),
+		meters:        make(map[string]batchMeter),
+		seen:          make(map[string]int, len(reqs)),
+		reRate:        make(map[snowflake.ID]struct{}),
+	}
+
+	keys := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		if key := normalizeIdempotencyKey(req.IdempotencyKey); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	// Replays are answered before any gating, like Ingest, so a retry never
+	// drifts with the subscription state.
+	existing, err := s.findUsageEventsByIdempotencyKeys(ctx, s.db, orgID, keys)
+	if err != nil {
+		return usagedomain.BatchIngestResponse{}, err
+	}
+
+	results := make([]usagedomain.BatchIngestResult, len(reqs))
+	records := make([]*usagedomain.UsageEvent, 0, len(reqs))
+	recordIndex := make(map[string]int, len(reqs))
+	for i, req := range reqs {
+		key := normalizeIdempotencyKey(req.IdempotencyKey)
+		results[i] = usagedomain.BatchIngestResult{Index: i, IdempotencyKey: key}
+
+		if record, ok := existing[key]; ok {
+			results[i].Status = usagedomain.BatchEventStatusDuplicate
+			results[i].UsageEventID = record.ID.String()
+			s.emitLiveUsageEvent(record, liveevents.StatusDeduplicated, liveevents.SourceAPI)
+			continue
+		}
+		if first, ok := session.seen[key]; ok {
+			results[i].Status = usagedomain.BatchEventStatusDuplicate
+			results[i].UsageEventID = results[first].UsageEventID
+			continue
+		}
+
+		record, err := s.prepareBatchEvent(ctx, session, req, key)
+		if err != nil {
+			if !isBatchRejection(err) {
+				return usagedomain.BatchIngestResponse{}, err
+			}
+			results[i].Status = usagedomain.BatchEventStatusRejected
+			results[i].Reason = err.Error()
+			continue
+		}
+
+		session.seen[key] = i
+		recordIndex[key] = i
+		results[i].Status = usagedomain.BatchEventStatusIngested
+		results[i].UsageEventID = record.ID.String()
+		results[i].Warning = record.Warning
+		records = append(records, record)
+	}
+
+	inserted := records
+	if len(records) > 0 {
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(buildIdempotencyConflictClause(tx)).
+				CreateInBatches(records, batchInsertSize).Error; err != nil {
+				return err
+			}
+			for cycleID := range session.reRate {
+				if err := s.reopenForReRating(ctx, tx, cycleID, session.now); err != nil {
+					return err
+				}
+			}
+
+			// A concurrent request may have claimed a key between the
+			// lookup above and the insert; those rows report the stored event.
+			stored, err := s.findUsageEventsByIdempotencyKeys(ctx, tx, orgID, keysOf(records))
+			if err != nil {
+				return err
+			}
+			inserted = make([]*usagedomain.UsageEvent, 0, len(records))
+			for _, record := range records {
+				current, ok := stored[record.IdempotencyKey]
+				if ok && current.ID == record.ID {
+					inserted = append(inserted, record)
+					continue
+				}
+				idx := recordIndex[record.IdempotencyKey]
+				results[idx].Status = usagedomain.BatchEventStatusDuplicate
+				if ok {
+					results[idx].UsageEventID = current.ID.String()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return usagedomain.BatchIngestResponse{}, err
+		}
+	}
+
+	for _, record := range inserted {
+		if s.metrics != nil {
+			go s.metrics.IncUsageEvent(orgID.String(), record.MeterCode)
+		}
+		if s.obsMetrics != nil {
+			s.obsMetrics.RecordUsageIngest(ctx, record.MeterCode)
+		}
+		s.emitUsageIngested(record)
+		s.emitLiveUsageEvent(record, liveevents.StatusAccepted, liveevents.SourceAPI)
+	}
+
+	resp := usagedomain.BatchIngestResponse{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case usagedomain.BatchEventStatusIngested:
+			resp.Ingested++
+		case usagedomain.BatchEventStatusDuplicate:
+			resp.Duplicates++
+		default:
+			resp.Rejected++
+		}
+	}
+
+	s.log.Debug("usage batch ingested",
+		zap.String("org_id", orgID.String()),
+		zap.Int("total", len(results)),
+		zap.Int("ingested", resp.Ingested),
+		zap.Int("duplicates", resp.Duplicates),
+		zap.Int("rejected", resp.Rejected),
+	)
+
+	return resp, nil
+}
+
+// prepareBatchEvent applies the Ingest checks to a single event and builds
+// the record to store.
+func (s *Service) prepareBatchEvent(
+	ctx context.Context,
+	session *batchSession,
+	req usagedomain.CreateIngestRequest,
+	idempotencyKey string,
+) (*usagedomain.UsageEvent, error) {
+	customerID, err := s.parseID(req.CustomerID, usagedomain.ErrInvalidCustomer)
+	if err != nil {
+		return nil, err
+	}
+
+	meterCode := meterdomain.NormalizeCode(req.MeterCode)
+	if meterCode == "" {
+		return nil, usagedomain.ErrInvalidMeterCode
+	}
+
+	if err := validateUsageEvent(req); err != nil {
+		return nil, err
+	}
+
+	sub, ok := session.subscriptions[req.CustomerID]
+	if !ok {
+		sub, err = s.resolveActiveSubscription(ctx, session.orgID, req.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		session.subscriptions[req.CustomerID] = sub
+	}
+	if sub.ID == 0 {
+		return nil, usagedomain.ErrInvalidSubscription
+	}
+	if sub.Status == subscriptiondomain.SubscriptionStatusPaused {
+		return nil, usagedomain.ErrSubscriptionPaused
+	}
+
+	meter, ok := session.meters[meterCode]
+	if !ok {
+		resolved, err := s.resolveMeter(ctx, session.orgID, meterCode)
+		if err != nil {
+			return nil, err
+		}
+		if resolved == nil {
+			return nil, usagedomain.ErrInvalidMeter
+		}
+		meter = batchMeter{Aggregation: resolved.Aggregation}
+		session.meters[meterCode] = meter
+	}
+	usageType, _ := usagedomain.NormalizeUsageType(req.Type)
+	if err := validateUsageTypeForMeter(usageType, meter.Aggregation); err != nil {
+		return nil, err
+	}
+
+	recordedAt := req.RecordedAt
+	if s.metrics != nil && session.now.Sub(recordedAt) > 24*time.Hour {
+		go s.metrics.IncUsageLateEvent(session.orgID.String(), meterCode)
+	}
+
+	reRateCycleID, err := s.checkLateArrival(ctx, s.db, session.orgID, sub.ID, recordedAt, session.now)
+	if err != nil {
+		return nil, err
+	}
+	if reRateCycleID != 0 {
+		session.reRate[reRateCycleID] = struct{}{}
+	}
+
+	record := &usagedomain.UsageEvent{
+		ID:             s.genID.Generate(),
+		OrgID:          session.orgID,
+		CustomerID:     customerID,
+		MeterCode:      meterCode,
+		Value:          req.Value,
+		Type:           usageType,
+		RecordedAt:     recordedAt,
+		Status:         usagedomain.UsageStatusAccepted,
+		IdempotencyKey: idempotencyKey,
+		CreatedAt:      session.now,
+		UpdatedAt:      session.now,
+	}
+	if req.Metadata != nil {
+		record.Metadata = datatypes.JSONMap(req.Metadata)
+	}
+	if len(req.Dimensions) > 0 {
+		record.Dimensions = datatypes.JSONMap(req.Dimensions)
+	}
+	if sub.Status == subscriptiondomain.SubscriptionStatusPastDue {
+		record.Warning = usagedomain.WarningSubscriptionPastDue
+	}
+	return record, nil
+}
+
+func (s *Service) findUsageEventsByIdempotencyKeys(ctx context.Context, db *gorm.DB, orgID snowflake.ID, keys []string) (map[string]*usagedomain.UsageEvent, error) {
+	found := make(map[string]*usagedomain.UsageEvent, len(keys))
+	if len(keys) == 0 {
+		return found, nil
+	}
+	var records []*usagedomain.UsageEvent
+	if err := db.WithContext(ctx).
+		Where("org_id = ? AND idempotency_key IN ?", orgID, keys).
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		found[record.IdempotencyKey] = record
+	}
+	return found, nil
+}
+
+func keysOf(records []*usagedomain.UsageEvent) []string {
+	keys := make([]string, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, record.IdempotencyKey)
+	}
+	return keys
+}
+
+// isBatchRejection reports whether err rejects a single event rather than
+// the whole batch.
+func isBatchRejection(err error) bool {
+	switch {
+	case errors.Is(err, usagedomain.ErrInvalidCustomer),
+		errors.Is(err, usagedomain.ErrInvalidSubscription),
+		errors.Is(err, usagedomain.ErrInvalidMeter),
+		errors.Is(err, usagedomain.ErrInvalidMeterCode),
+		errors.Is(err, usagedomain.ErrInvalidValue),
+		errors.Is(err, usagedomain.ErrInvalidUsageType),
+		errors.Is(err, usagedomain.ErrAdjustmentNotSupported),
+		errors.Is(err, usagedomain.ErrInvalidRecordedAt),
+		errors.Is(err, usagedomain.ErrInvalidIdempotencyKey),
+		errors.Is(err, usagedomain.ErrFeatureNotEntitled),
+		errors.Is(err, usagedomain.ErrSubscriptionPaused),
+		errors.Is(err, usagedomain.ErrUsageWindowClosed):
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestBatchIngest_ReportsPerEventStatus(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&usagedomain.UsageEvent{}, &billingcycledomain.BillingCycle{}))
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX uidx_usage_idempotency_key
+		ON usage_events (org_id, idempotency_key)`).Error)
+
+	node := mustNode(t)
+	orgID := node.Generate()
+	customerID := node.Generate()
+	unentitledCustomerID := node.Generate()
+	subID := node.Generate()
+	unentitledSubID := node.Generate()
+	pausedCustomerID := node.Generate()
+	meterID := node.Generate()
+
+	now := time.Now().UTC()
+	existing := usagedomain.UsageEvent{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		CustomerID:     customerID,
+		MeterCode:      "api_calls",
+		Value:          1,
+		RecordedAt:     now.Add(-time.Hour),
+		Status:         usagedomain.UsageStatusAccepted,
+		IdempotencyKey: "already-ingested",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	require.NoError(t, db.Create(&existing).Error)
+
+	mockMeter := new(meterMock)
+	mockMeter.On("GetByCode", mock.Anything, "api_calls").Return(&meterdomain.Response{ID: meterID.String(), Code: "api_calls"}, nil)
+	mockMeter.On("GetByCode", mock.Anything, "unknown").Return(nil, meterdomain.ErrMeterNotFound)
+	mockQuota := new(quotaMock)
+	mockQuota.On("CanIngestUsage", mock.Anything, orgID).Return(nil)
+	mockSub := new(subscriptionMock)
+	mockSub.On("GetActiveByCustomerID", mock.Anything, subscriptiondomain.GetActiveByCustomerIDRequest{CustomerID: customerID.String()}).
+		Return(subscriptiondomain.Subscription{ID: subID}, nil)
+	mockSub.On("GetActiveByCustomerID", mock.Anything, subscriptiondomain.GetActiveByCustomerIDRequest{CustomerID: unentitledCustomerID.String()}).
+		Return(subscriptiondomain.Subscription{ID: unentitledSubID}, nil)
+	mockSub.On("GetActiveByCustomerID", mock.Anything, subscriptiondomain.GetActiveByCustomerIDRequest{CustomerID: pausedCustomerID.String()}).
+		Return(subscriptiondomain.Subscription{ID: node.Generate(), Status: subscriptiondomain.SubscriptionStatusPaused}, nil)
+
+	svc := NewService(ServiceParam{
+		DB:       db,
+		Log:      zap.NewNop(),
+		GenID:    node,
+		MeterSvc: mockMeter,
+		SubSvc:   mockSub,
+		QuotaSvc: mockQuota,
+	})
+	ctx := WithTestOrgContext(context.Background(), orgID)
+
+	event := func(customer, meter, key string, value float64) usagedomain.CreateIngestRequest {
+		return usagedomain.CreateIngestRequest{
+			CustomerID:     customer,
+			MeterCode:      meter,
+			Value:          value,
+			RecordedAt:     now.Add(-time.Minute),
+			IdempotencyKey: key,
+		}
+	}
+	resp, err := svc.BatchIngest(ctx, []usagedomain.CreateIngestRequest{
+		event(customerID.String(), "api_calls", "event-1", 10),
+		event(customerID.String(), "api_calls", "already-ingested", 1),
+		event(customerID.String(), "unknown", "event-3", 1),
+		event(unentitledCustomerID.String(), "api_calls", "event-4", 1),
+		event(customerID.String(), "api_calls", "event-1", 10),
+		event(customerID.String(), "api_calls", "event-6", -1),
+		event(customerID.String(), "api_calls", "event-7", 2.5),
+		event(pausedCustomerID.String(), "api_calls", "event-8", 1),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 8)
+
+	statuses := make([]string, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		statuses = append(statuses, result.Status)
+	}
+	assert.Equal(t, []string{
+		usagedomain.BatchEventStatusIngested,
+		usagedomain.BatchEventStatusDuplicate,
+		usagedomain.BatchEventStatusRejected,
+		usagedomain.BatchEventStatusIngested,
+		usagedomain.BatchEventStatusDuplicate,
+		usagedomain.BatchEventStatusRejected,
+		usagedomain.BatchEventStatusIngested,
+		usagedomain.BatchEventStatusRejected,
+	}, statuses)
+	assert.Equal(t, 3, resp.Ingested)
+	assert.Equal(t, 2, resp.Duplicates)
+	assert.Equal(t, 3, resp.Rejected)
+
+	assert.Equal(t, existing.ID.String(), resp.Results[1].UsageEventID)
+	assert.Equal(t, resp.Results[0].UsageEventID, resp.Results[4].UsageEventID)
+	assert.Equal(t, usagedomain.ErrInvalidMeter.Error(), resp.Results[2].Reason)
+	// Entitlements are checked when the snapshot worker enriches the event.
+	assert.Empty(t, resp.Results[3].Reason)
+	assert.Equal(t, usagedomain.ErrInvalidValue.Error(), resp.Results[5].Reason)
+	assert.Equal(t, usagedomain.ErrSubscriptionPaused.Error(), resp.Results[7].Reason)
+
+	var count int64
+	require.NoError(t, db.Model(&usagedomain.UsageEvent{}).Where("org_id = ?", orgID).Count(&count).Error)
+	assert.Equal(t, int64(4), count)
+
+	// Replaying the batch ingests nothing new.
+	replay, err := svc.BatchIngest(ctx, []usagedomain.CreateIngestRequest{
+		event(customerID.String(), "api_calls", "event-1", 10),
+		event(customerID.String(), "api_calls", "event-7", 2.5),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, replay.Ingested)
+	assert.Equal(t, 2, replay.Duplicates)
+	assert.Equal(t, resp.Results[6].UsageEventID, replay.Results[1].UsageEventID)
+
+	_, err = svc.BatchIngest(ctx, make([]usagedomain.CreateIngestRequest, usagedomain.MaxBatchIngestSize+1))
+	assert.ErrorIs(t, err, usagedomain.ErrInvalidBatchSize)
+}
@@ -0,0 +1,522 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	"github.com/railzwaylabs/railzway/internal/orgcontext"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var importColumns = []string{
+	usagedomain.ImportColumnMeterCode,
+	usagedomain.ImportColumnCustomerExternalID,
+	usagedomain.ImportColumnValue,
+	usagedomain.ImportColumnRecordedAt,
+	usagedomain.ImportColumnIdempotencyKey,
+}
+
+type importRow struct {
+	result usagedomain.ImportRowResult
+	record *usagedomain.UsageEvent
+	// reRateCycleID is the closed cycle the row reopens once it is stored.
+	reRateCycleID snowflake.ID
+}
+
+// importSession carries the per-import state shared across batches.
+type importSession struct {
+	orgID    snowflake.ID
+	importID snowflake.ID
+	columns  map[string]int
+	earliest time.Time
+	now      time.Time
+
+	meters        map[string]snowflake.ID
+	customers     map[string]snowflake.ID
+	subscriptions map[snowflake.ID]subscriptiondomain.Subscription
+	seen          map[string]struct{}
+}
+
+// ImportCSV loads historical usage from a CSV stream. Every data row is
+// validated on its own; valid rows are inserted in batches tagged with a
+// shared import ID so the load can be identified afterwards. Row results are
+// passed to emit in file order once the batch holding them is written.
+func (s *Service) ImportCSV(
+	ctx context.Context,
+	req usagedomain.ImportUsageRequest,
+	r io.Reader,
+	emit func(usagedomain.ImportRowResult) error,
+) (usagedomain.ImportUsageSummary, error) {
+	orgID, ok := orgcontext.OrgIDFromContext(ctx)
+	if !ok || orgID == 0 {
+		return usagedomain.ImportUsageSummary{}, usagedomain.ErrInvalidOrganization
+	}
+	if s.db == nil {
+		return usagedomain.ImportUsageSummary{}, errors.New("missing_db")
+	}
+	if r == nil {
+		return usagedomain.ImportUsageSummary{}, usagedomain.ErrInvalidImportFile
+	}
+
+	window := req.BackfillWindow
+	if window < 0 {
+		return usagedomain.ImportUsageSummary{}, usagedomain.ErrInvalidBackfillWindow
+	}
+	if window == 0 {
+		window = usagedomain.DefaultImportBackfillWindow
+	}
+
+	if s.quotaSvc != nil {
+		if err := s.quotaSvc.CanIngestUsage(ctx, orgID); err != nil {
+			return usagedomain.ImportUsageSummary{}, err
+		}
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return usagedomain.ImportUsageSummary{}, usagedomain.ErrInvalidImportFile
+	}
+	columns, err := parseImportHeader(header)
+	if err != nil {
+		return usagedomain.ImportUsageSummary{}, err
+	}
+
+	now := time.Now().UTC()
+	session := &importSession{
+		orgID:         orgID,
+		importID:      s.genID.Generate(),
+		columns:       columns,
+		earliest:      now.Add(-window),
+		now:           now,
+		meters:        make(map[string]snowflake.ID),
+		customers:     make(map[string]snowflake.ID),
+		subscriptions: make(map[snowflake.ID]subscriptiondomain.Subscription),
+		seen:          make(map[string]struct{}),
+	}
+	summary := usagedomain.ImportUsageSummary{ImportID: session.importID.String()}
+
+	pending := make([]importRow, 0, usagedomain.ImportBatchSize)
+	rowNumber := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		rowNumber++
+
+		var row importRow
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return summary, err
+			}
+			row = importRowError(rowNumber, "", usagedomain.ErrInvalidImportFile)
+		} else {
+			row, err = s.prepareImportRow(ctx, session, rowNumber, record)
+			if err != nil {
+				return summary, err
+			}
+		}
+		pending = append(pending, row)
+
+		if len(pending) >= usagedomain.ImportBatchSize {
+			if err := s.flushImportBatch(ctx, session, pending, &summary, emit); err != nil {
+				return summary, err
+			}
+			pending = pending[:0]
+		}
+	}
+
+	if err := s.flushImportBatch(ctx, session, pending, &summary, emit); err != nil {
+		return summary, err
+	}
+
+	s.log.Info("usage csv import completed",
+		zap.String("org_id", orgID.String()),
+		zap.String("import_id", summary.ImportID),
+		zap.Int("total", summary.Total),
+		zap.Int("succeeded", summary.Succeeded),
+		zap.Int("skipped", summary.Skipped),
+		zap.Int("failed", summary.Failed),
+	)
+
+	return summary, nil
+}
+
+func parseImportHeader(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if name == "" {
+			continue
+		}
+		if _, exists := columns[name]; exists {
+			return nil, usagedomain.ErrInvalidImportFile
+		}
+		columns[name] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, usagedomain.ErrInvalidImportFile
+		}
+	}
+	return columns, nil
+}
+
+// prepareImportRow validates a single CSV row against the same subscription
+// and late-arrival checks as Ingest. Validation failures are reported on the
+// row; only infrastructure failures are returned as errors.
+func (s *Service) prepareImportRow(
+	ctx context.Context,
+	session *importSession,
+	rowNumber int,
+	record []string,
+) (importRow, error) {
+	field := func(name string) string {
+		idx := session.columns[name]
+		if idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	idempotencyKey := normalizeIdempotencyKey(field(usagedomain.ImportColumnIdempotencyKey))
+
+	meterCode := meterdomain.NormalizeCode(field(usagedomain.ImportColumnMeterCode))
+	if meterCode == "" {
+		return importRowError(rowNumber, idempotencyKey, usagedomain.ErrInvalidMeterCode), nil
+	}
+
+	value, err := strconv.ParseFloat(field(usagedomain.ImportColumnValue), 64)
+	if err != nil {
+		return importRowError(rowNumber, idempotencyKey, usagedomain.ErrInvalidValue), nil
+	}
+
+	recordedAt, err := time.Parse(time.RFC3339Nano, field(usagedomain.ImportColumnRecordedAt))
+	if err != nil {
+		return importRowError(rowNumber, idempotencyKey, usagedomain.ErrInvalidRecordedAt), nil
+	}
+	recordedAt = recordedAt.UTC()
+
+	if err := validateUsageEvent(usagedomain.CreateIngestRequest{
+		MeterCode:      meterCode,
+		Value:          value,
+		RecordedAt:     recordedAt,
+		IdempotencyKey: idempotencyKey,
+	}); err != nil {
+		return importRowError(rowNumber, idempotencyKey, err), nil
+	}
+	if recordedAt.Before(session.earliest) {
+		return importRowError(rowNumber, idempotencyKey, usagedomain.ErrOutsideBackfillWindow), nil
+	}
+
+	if _, dup := session.seen[idempotencyKey]; dup {
+		return importRowSkipped(rowNumber, idempotencyKey, usagedomain.ErrDuplicateIdempotencyKey), nil
+	}
+
+	meterID, err := s.resolveImportMeter(ctx, session, meterCode)
+	if err != nil {
+		if errors.Is(err, usagedomain.ErrInvalidMeter) || errors.Is(err, usagedomain.ErrInvalidMeterCode) {
+			return importRowError(rowNumber, idempotencyKey, err), nil
+		}
+		return importRow{}, err
+	}
+
+	customerID, err := s.resolveImportCustomer(ctx, session, field(usagedomain.ImportColumnCustomerExternalID))
+	if err != nil {
+		if errors.Is(err, usagedomain.ErrInvalidCustomer) {
+			return importRowError(rowNumber, idempotencyKey, err), nil
+		}
+		return importRow{}, err
+	}
+
+	sub, err := s.resolveImportSubscription(ctx, session, customerID)
+	if err != nil {
+		if isBatchRejection(err) {
+			return importRowError(rowNumber, idempotencyKey, err), nil
+		}
+		return importRow{}, err
+	}
+
+	// Unlike Ingest, entitlements are checked here rather than left to the
+	// snapshot worker: an import is not latency bound, and a row the
+	// subscription is not entitled to would otherwise be reported as
+	// imported but never rated.
+	if s.subSvc != nil {
+		if err := s.subSvc.ValidateUsageEntitlement(ctx, sub.ID, meterID, recordedAt); err != nil {
+			if errors.Is(err, subscriptiondomain.ErrFeatureNotEntitled) {
+				return importRowError(rowNumber, idempotencyKey, usagedomain.ErrFeatureNotEntitled), nil
+			}
+			return importRow{}, err
+		}
+	}
+
+	reRateCycleID, err := s.checkLateArrival(ctx, s.db, session.orgID, sub.ID, recordedAt, session.now)
+	if err != nil {
+		if errors.Is(err, usagedomain.ErrUsageWindowClosed) {
+			return importRowError(rowNumber, idempotencyKey, err), nil
+		}
+		return importRow{}, err
+	}
+
+	session.seen[idempotencyKey] = struct{}{}
+	importID := session.importID
+
+	return importRow{
+		result: usagedomain.ImportRowResult{
+			Row:            rowNumber,
+			IdempotencyKey: idempotencyKey,
+		},
+		record: &usagedomain.UsageEvent{
+			ID:             s.genID.Generate(),
+			OrgID:          session.orgID,
+			CustomerID:     customerID,
+			MeterID:        meterID,
+			MeterCode:      meterCode,
+			Value:          value,
+			RecordedAt:     recordedAt,
+			Status:         usagedomain.UsageStatusAccepted,
+			IdempotencyKey: idempotencyKey,
+			ImportID:       &importID,
+			CreatedAt:      session.now,
+			UpdatedAt:      session.now,
+		},
+		reRateCycleID: reRateCycleID,
+	}, nil
+}
+
+func (s *Service) resolveImportMeter(ctx context.Context, session *importSession, meterCode string) (snowflake.ID, error) {
+	if id, ok := session.meters[meterCode]; ok {
+		return id, nil
+	}
+	meter, err := s.resolveMeter(ctx, session.orgID, meterCode)
+	if err != nil {
+		return 0, err
+	}
+	if meter == nil {
+		return 0, usagedomain.ErrInvalidMeter
+	}
+	meterID, err := snowflake.ParseString(meter.ID)
+	if err != nil {
+		return 0, usagedomain.ErrInvalidMeter
+	}
+	session.meters[meterCode] = meterID
+	return meterID, nil
+}
+
+// resolveImportCustomer maps the CSV customer reference to a customer ID. The
+// reference is matched against metadata.external_id first and falls back to
+// the customer ID itself so exported usage can be re-imported unchanged.
+func (s *Service) resolveImportCustomer(ctx context.Context, session *importSession, externalID string) (snowflake.ID, error) {
+	if externalID == "" {
+		return 0, usagedomain.ErrInvalidCustomer
+	}
+	if id, ok := session.customers[externalID]; ok {
+		return id, nil
+	}
+
+	var ids []snowflake.ID
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT id FROM customers
+		WHERE org_id = ? AND metadata->>'external_id' = ?
+		ORDER BY id
+		LIMIT 2`,
+		session.orgID,
+		externalID,
+	).Scan(&ids).Error; err != nil {
+		return 0, err
+	}
+
+	var customerID snowflake.ID
+	switch len(ids) {
+	case 1:
+		customerID = ids[0]
+	case 0:
+		id, err := s.parseID(externalID, usagedomain.ErrInvalidCustomer)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.ensureCustomerExists(ctx, session.orgID, id); err != nil {
+			return 0, err
+		}
+		customerID = id
+	default:
+		// Ambiguous external IDs must not silently bill the wrong customer.
+		return 0, usagedomain.ErrInvalidCustomer
+	}
+
+	session.customers[externalID] = customerID
+	return customerID, nil
+}
+
+// resolveImportSubscription returns the active subscription of the customer,
+// rejecting customers without one or whose subscription is paused like
+// Ingest does.
+func (s *Service) resolveImportSubscription(ctx context.Context, session *importSession, customerID snowflake.ID) (subscriptiondomain.Subscription, error) {
+	sub, ok := session.subscriptions[customerID]
+	if !ok {
+		var err error
+		sub, err = s.resolveActiveSubscription(ctx, session.orgID, customerID.String())
+		if err != nil {
+			return subscriptiondomain.Subscription{}, err
+		}
+		session.subscriptions[customerID] = sub
+	}
+	if sub.ID == 0 {
+		return subscriptiondomain.Subscription{}, usagedomain.ErrInvalidSubscription
+	}
+	if sub.Status == subscriptiondomain.SubscriptionStatusPaused {
+		return subscriptiondomain.Subscription{}, usagedomain.ErrSubscriptionPaused
+	}
+	return sub, nil
+}
+
+// flushImportBatch writes the valid rows of a batch and reports every row.
+// Keys already present in usage_events, including keys claimed by concurrent
+// ingestion while the batch was written, are reported as skipped.
+func (s *Service) flushImportBatch(
+	ctx context.Context,
+	session *importSession,
+	rows []importRow,
+	summary *usagedomain.ImportUsageSummary,
+	emit func(usagedomain.ImportRowResult) error,
+) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.record != nil {
+			keys = append(keys, row.record.IdempotencyKey)
+		}
+	}
+
+	existing, err := s.findExistingIdempotencyKeys(ctx, session.orgID, keys)
+	if err != nil {
+		return err
+	}
+
+	records := make([]*usagedomain.UsageEvent, 0, len(keys))
+	reRate := make(map[snowflake.ID]struct{})
+	for i := range rows {
+		record := rows[i].record
+		if record == nil {
+			continue
+		}
+		if _, ok := existing[record.IdempotencyKey]; ok {
+			rows[i] = importRowSkipped(rows[i].result.Row, record.IdempotencyKey, usagedomain.ErrDuplicateIdempotencyKey)
+			continue
+		}
+		records = append(records, record)
+		if rows[i].reRateCycleID != 0 {
+			reRate[rows[i].reRateCycleID] = struct{}{}
+		}
+	}
+
+	var stored map[string]*usagedomain.UsageEvent
+	if len(records) > 0 {
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(buildIdempotencyConflictClause(tx)).
+				CreateInBatches(records, len(records)).Error; err != nil {
+				return err
+			}
+			for cycleID := range reRate {
+				if err := s.reopenForReRating(ctx, tx, cycleID, session.now); err != nil {
+					return err
+				}
+			}
+
+			// The conflict clause silently drops rows whose key was claimed
+			// between the lookup above and the insert.
+			var err error
+			stored, err = s.findUsageEventsByIdempotencyKeys(ctx, tx, session.orgID, keysOf(records))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range rows {
+		record := rows[i].record
+		if record == nil {
+			continue
+		}
+		if current, ok := stored[record.IdempotencyKey]; !ok || current.ID != record.ID {
+			rows[i] = importRowSkipped(rows[i].result.Row, record.IdempotencyKey, usagedomain.ErrDuplicateIdempotencyKey)
+			continue
+		}
+		rows[i].result.Status = usagedomain.ImportRowStatusSuccess
+		rows[i].result.UsageEventID = record.ID.String()
+	}
+
+	for _, row := range rows {
+		summary.Total++
+		switch row.result.Status {
+		case usagedomain.ImportRowStatusSuccess:
+			summary.Succeeded++
+		case usagedomain.ImportRowStatusSkipped:
+			summary.Skipped++
+		default:
+			summary.Failed++
+		}
+		if emit != nil {
+			if err := emit(row.result); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) findExistingIdempotencyKeys(ctx context.Context, orgID snowflake.ID, keys []string) (map[string]struct{}, error) {
+	existing := make(map[string]struct{})
+	if len(keys) == 0 {
+		return existing, nil
+	}
+	var found []string
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT idempotency_key FROM usage_events WHERE org_id = ? AND idempotency_key IN ?`,
+		orgID,
+		keys,
+	).Scan(&found).Error; err != nil {
+		return nil, err
+	}
+	for _, key := range found {
+		existing[key] = struct{}{}
+	}
+	return existing, nil
+}
+
+func importRowError(rowNumber int, idempotencyKey string, err error) importRow {
+	return importRow{result: usagedomain.ImportRowResult{
+		Row:            rowNumber,
+		Status:         usagedomain.ImportRowStatusError,
+		IdempotencyKey: idempotencyKey,
+		Reason:         err.Error(),
+	}}
+}
+
+func importRowSkipped(rowNumber int, idempotencyKey string, reason error) importRow {
+	return importRow{result: usagedomain.ImportRowResult{
+		Row:            rowNumber,
+		Status:         usagedomain.ImportRowStatusSkipped,
+		IdempotencyKey: idempotencyKey,
+		Reason:         reason.Error(),
+	}}
+}
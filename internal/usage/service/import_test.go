@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/config"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestImportCSV_MixedRows(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&usagedomain.UsageEvent{}, &billingcycledomain.BillingCycle{}, &ledgerdomain.LedgerEntry{}))
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX uidx_usage_idempotency_key
+		ON usage_events (org_id, idempotency_key)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE customers (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		metadata JSON
+	)`).Error)
+
+	node := mustNode(t)
+	orgID := node.Generate()
+	externalCustomerID := node.Generate()
+	directCustomerID := node.Generate()
+	idleCustomerID := node.Generate()
+	pausedCustomerID := node.Generate()
+	basicCustomerID := node.Generate()
+	meterID := node.Generate()
+	externalSubID := node.Generate()
+	directSubID := node.Generate()
+	basicSubID := node.Generate()
+
+	require.NoError(t, db.Exec(
+		`INSERT INTO customers (id, org_id, metadata) VALUES (?, ?, ?), (?, ?, ?), (?, ?, ?), (?, ?, ?), (?, ?, ?)`,
+		externalCustomerID, orgID, `{"external_id":"acme"}`,
+		directCustomerID, orgID, `{}`,
+		idleCustomerID, orgID, `{"external_id":"idle"}`,
+		pausedCustomerID, orgID, `{"external_id":"paused"}`,
+		basicCustomerID, orgID, `{"external_id":"basic"}`,
+	).Error)
+
+	now := time.Now().UTC()
+	closedCycle := func(start, end time.Time) billingcycledomain.BillingCycle {
+		c := billingcycledomain.BillingCycle{
+			ID:                node.Generate(),
+			OrgID:             orgID,
+			SubscriptionID:    directSubID,
+			PeriodStart:       start,
+			PeriodEnd:         end,
+			Status:            billingcycledomain.BillingCycleStatusClosed,
+			RatingCompletedAt: &end,
+			ClosedAt:          &end,
+		}
+		require.NoError(t, db.Create(&c).Error)
+		return c
+	}
+	recentCycle := closedCycle(now.Add(-5*24*time.Hour), now.Add(-24*time.Hour))
+	oldCycle := closedCycle(now.Add(-20*24*time.Hour), now.Add(-5*24*time.Hour))
+	require.NoError(t, db.Create(&usagedomain.UsageEvent{
+		ID:             node.Generate(),
+		OrgID:          orgID,
+		CustomerID:     externalCustomerID,
+		MeterCode:      "api_calls",
+		Value:          1,
+		RecordedAt:     now.Add(-time.Hour),
+		Status:         usagedomain.UsageStatusAccepted,
+		IdempotencyKey: "already-ingested",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}).Error)
+
+	mockMeter := new(meterMock)
+	mockMeter.On("GetByCode", mock.Anything, "api_calls").Return(&meterdomain.Response{ID: meterID.String(), Code: "api_calls"}, nil)
+	mockMeter.On("GetByCode", mock.Anything, "unknown").Return(nil, meterdomain.ErrMeterNotFound)
+	mockQuota := new(quotaMock)
+	mockQuota.On("CanIngestUsage", mock.Anything, orgID).Return(nil)
+	mockSub := new(subscriptionMock)
+	activeSub := func(customerID, subID snowflake.ID, status subscriptiondomain.SubscriptionStatus) {
+		mockSub.On("GetActiveByCustomerID", mock.Anything, subscriptiondomain.GetActiveByCustomerIDRequest{CustomerID: customerID.String()}).
+			Return(subscriptiondomain.Subscription{ID: subID, Status: status}, nil)
+	}
+	activeSub(externalCustomerID, externalSubID, subscriptiondomain.SubscriptionStatusActive)
+	activeSub(directCustomerID, directSubID, subscriptiondomain.SubscriptionStatusActive)
+	activeSub(pausedCustomerID, node.Generate(), subscriptiondomain.SubscriptionStatusPaused)
+	activeSub(basicCustomerID, basicSubID, subscriptiondomain.SubscriptionStatusActive)
+	mockSub.On("GetActiveByCustomerID", mock.Anything, subscriptiondomain.GetActiveByCustomerIDRequest{CustomerID: idleCustomerID.String()}).
+		Return(nil, subscriptiondomain.ErrSubscriptionNotFound)
+	mockSub.On("ValidateUsageEntitlement", mock.Anything, basicSubID, meterID, mock.Anything).Return(subscriptiondomain.ErrFeatureNotEntitled)
+	mockSub.On("ValidateUsageEntitlement", mock.Anything, mock.Anything, meterID, mock.Anything).Return(nil)
+
+	svc := NewService(ServiceParam{
+		DB:       db,
+		Log:      zap.NewNop(),
+		GenID:    node,
+		MeterSvc: mockMeter,
+		SubSvc:   mockSub,
+		QuotaSvc: mockQuota,
+		Cfg:      config.Config{Usage: config.UsageConfig{LateArrivalGraceHours: 72}},
+	})
+
+	recent := now.Add(-48 * time.Hour).Format(time.RFC3339)
+	rows := []string{
+		"meter_code,customer_external_id,value,recorded_at,idempotency_key",
+		"api_calls,acme,10," + recent + ",row-1",
+		fmt.Sprintf("api_calls,%s,2.5,%s,row-2", directCustomerID, recent),
+		"unknown,acme,1," + recent + ",row-3",
+		"api_calls,missing-customer,1," + recent + ",row-4",
+		"api_calls,acme,1," + now.Add(-40*24*time.Hour).Format(time.RFC3339) + ",row-5",
+		"api_calls,acme,1," + now.Add(time.Hour).Format(time.RFC3339) + ",row-6",
+		"api_calls,acme,abc," + recent + ",row-7",
+		"api_calls,acme,3," + recent + ",row-1",
+		"api_calls,acme,4," + recent + ",already-ingested",
+		"api_calls,acme,5," + recent + ",",
+		"api_calls,idle,1," + recent + ",row-11",
+		"api_calls,paused,1," + recent + ",row-12",
+		"api_calls,basic,1," + recent + ",row-13",
+		fmt.Sprintf("api_calls,%s,1,%s,row-14", directCustomerID, now.Add(-12*24*time.Hour).Format(time.RFC3339)),
+	}
+
+	ctx := WithTestOrgContext(context.Background(), orgID)
+	var results []usagedomain.ImportRowResult
+	summary, err := svc.ImportCSV(ctx, usagedomain.ImportUsageRequest{BackfillWindow: 30 * 24 * time.Hour},
+		strings.NewReader(strings.Join(rows, "\n")),
+		func(row usagedomain.ImportRowResult) error {
+			results = append(results, row)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 14, summary.Total)
+	assert.Equal(t, 2, summary.Succeeded)
+	assert.Equal(t, 2, summary.Skipped)
+	assert.Equal(t, 10, summary.Failed)
+	require.NotEmpty(t, summary.ImportID)
+
+	expected := []struct {
+		status string
+		reason string
+	}{
+		{usagedomain.ImportRowStatusSuccess, ""},
+		{usagedomain.ImportRowStatusSuccess, ""},
+		{usagedomain.ImportRowStatusError, usagedomain.ErrInvalidMeter.Error()},
+		{usagedomain.ImportRowStatusError, usagedomain.ErrInvalidCustomer.Error()},
+		{usagedomain.ImportRowStatusError, usagedomain.ErrOutsideBackfillWindow.Error()},
+		{usagedomain.ImportRowStatusError, usagedomain.ErrInvalidRecordedAt.Error()},
+		{usagedomain.ImportRowStatusError, usagedomain.ErrInvalidValue.Error()},
+		{usagedomain.ImportRowStatusSkipped, usagedomain.ErrDuplicateIdempotencyKey.Error()},
+		{usagedomain.ImportRowStatusSkipped, usagedomain.ErrDuplicateIdempotencyKey.Error()},
+		{usagedomain.ImportRowStatusError, usagedomain.ErrInvalidIdempotencyKey.Error()},
+		{usagedomain.ImportRowStatusError, usagedomain.ErrInvalidSubscription.Error()},
+		{usagedomain.ImportRowStatusError, usagedomain.ErrSubscriptionPaused.Error()},
+		{usagedomain.ImportRowStatusError, usagedomain.ErrFeatureNotEntitled.Error()},
+		{usagedomain.ImportRowStatusError, usagedomain.ErrUsageWindowClosed.Error()},
+	}
+	require.Len(t, results, len(expected))
+	for i, want := range expected {
+		assert.Equal(t, i+1, results[i].Row)
+		assert.Equal(t, want.status, results[i].Status, "row %d", i+1)
+		assert.Equal(t, want.reason, results[i].Reason, "row %d", i+1)
+	}
+
+	var imported []usagedomain.UsageEvent
+	require.NoError(t, db.Where("import_id IS NOT NULL").Order("idempotency_key").Find(&imported).Error)
+	require.Len(t, imported, 2)
+	assert.Equal(t, "row-1", imported[0].IdempotencyKey)
+	assert.Equal(t, externalCustomerID, imported[0].CustomerID)
+	assert.Equal(t, meterID, imported[0].MeterID)
+	assert.Equal(t, float64(10), imported[0].Value)
+	assert.Equal(t, usagedomain.UsageStatusAccepted, imported[0].Status)
+	assert.Equal(t, summary.ImportID, imported[0].ImportID.String())
+	assert.Equal(t, results[0].UsageEventID, imported[0].ID.String())
+	assert.Equal(t, "row-2", imported[1].IdempotencyKey)
+	assert.Equal(t, directCustomerID, imported[1].CustomerID)
+
+	var total int64
+	require.NoError(t, db.Model(&usagedomain.UsageEvent{}).Count(&total).Error)
+	assert.Equal(t, int64(3), total)
+
+	// Backfilled usage for a closed cycle within the grace window sends the
+	// cycle back to rating, like Ingest.
+	var reopened billingcycledomain.BillingCycle
+	require.NoError(t, db.First(&reopened, recentCycle.ID).Error)
+	assert.Equal(t, billingcycledomain.BillingCycleStatusClosing, reopened.Status)
+	var untouched billingcycledomain.BillingCycle
+	require.NoError(t, db.First(&untouched, oldCycle.ID).Error)
+	assert.Equal(t, billingcycledomain.BillingCycleStatusClosed, untouched.Status)
+}
+
+// TestImportCSV_ConcurrentlyIngestedRowsAreSkipped covers keys claimed by
+// another writer between the existing-key lookup and the insert: the conflict
+// clause drops those rows, so they must be reported as skipped.
+func TestImportCSV_ConcurrentlyIngestedRowsAreSkipped(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&usagedomain.UsageEvent{}, &billingcycledomain.BillingCycle{}))
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX uidx_usage_idempotency_key
+		ON usage_events (org_id, idempotency_key)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE customers (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		metadata JSON
+	)`).Error)
+
+	node := mustNode(t)
+	orgID := node.Generate()
+	customerID := node.Generate()
+	subID := node.Generate()
+	meterID := node.Generate()
+	concurrentID := node.Generate()
+	require.NoError(t, db.Exec(
+		`INSERT INTO customers (id, org_id, metadata) VALUES (?, ?, ?)`,
+		customerID, orgID, `{"external_id":"acme"}`,
+	).Error)
+
+	now := time.Now().UTC()
+	require.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:concurrent_ingest", func(tx *gorm.DB) {
+		if _, ok := tx.Statement.Dest.([]*usagedomain.UsageEvent); !ok {
+			return
+		}
+		require.NoError(t, tx.Session(&gorm.Session{NewDB: true}).Create(&usagedomain.UsageEvent{
+			ID:             concurrentID,
+			OrgID:          orgID,
+			CustomerID:     customerID,
+			MeterCode:      "api_calls",
+			Value:          1,
+			RecordedAt:     now,
+			Status:         usagedomain.UsageStatusAccepted,
+			IdempotencyKey: "row-2",
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}).Error)
+	}))
+
+	mockMeter := new(meterMock)
+	mockMeter.On("GetByCode", mock.Anything, "api_calls").Return(&meterdomain.Response{ID: meterID.String(), Code: "api_calls"}, nil)
+	mockSub := new(subscriptionMock)
+	mockSub.On("GetActiveByCustomerID", mock.Anything, subscriptiondomain.GetActiveByCustomerIDRequest{CustomerID: customerID.String()}).
+		Return(subscriptiondomain.Subscription{ID: subID}, nil)
+	mockSub.On("ValidateUsageEntitlement", mock.Anything, subID, meterID, mock.Anything).Return(nil)
+
+	svc := NewService(ServiceParam{
+		DB:       db,
+		Log:      zap.NewNop(),
+		GenID:    node,
+		MeterSvc: mockMeter,
+		SubSvc:   mockSub,
+	})
+
+	recent := now.Add(-time.Hour).Format(time.RFC3339)
+	rows := []string{
+		"meter_code,customer_external_id,value,recorded_at,idempotency_key",
+		"api_calls,acme,1," + recent + ",row-1",
+		"api_calls,acme,2," + recent + ",row-2",
+	}
+
+	ctx := WithTestOrgContext(context.Background(), orgID)
+	var results []usagedomain.ImportRowResult
+	summary, err := svc.ImportCSV(ctx, usagedomain.ImportUsageRequest{},
+		strings.NewReader(strings.Join(rows, "\n")),
+		func(row usagedomain.ImportRowResult) error {
+			results = append(results, row)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, summary.Total)
+	assert.Equal(t, 1, summary.Succeeded)
+	assert.Equal(t, 1, summary.Skipped)
+	require.Len(t, results, 2)
+	assert.Equal(t, usagedomain.ImportRowStatusSuccess, results[0].Status)
+	assert.Equal(t, usagedomain.ImportRowStatusSkipped, results[1].Status)
+	assert.Equal(t, usagedomain.ErrDuplicateIdempotencyKey.Error(), results[1].Reason)
+	assert.Empty(t, results[1].UsageEventID)
+
+	var stored usagedomain.UsageEvent
+	require.NoError(t, db.Where("idempotency_key = ?", "row-2").First(&stored).Error)
+	assert.Equal(t, concurrentID, stored.ID)
+	assert.Nil(t, stored.ImportID)
+}
+
+func TestImportCSV_RejectsMissingColumns(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+
+	node := mustNode(t)
+	svc := NewService(ServiceParam{DB: db, Log: zap.NewNop(), GenID: node})
+
+	ctx := WithTestOrgContext(context.Background(), node.Generate())
+	_, err = svc.ImportCSV(ctx, usagedomain.ImportUsageRequest{},
+		strings.NewReader("meter_code,value,recorded_at,idempotency_key\napi_calls,1,2026-01-01T00:00:00Z,k1\n"),
+		nil,
+	)
+	assert.ErrorIs(t, err, usagedomain.ErrInvalidImportFile)
+}
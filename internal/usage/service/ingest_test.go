@@ -8,6 +8,7 @@ import (
 
 	"github.com/bwmarrin/snowflake"
 	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
 	"github.com/railzwaylabs/railzway/internal/meter/domain"
 	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
 	"github.com/railzwaylabs/railzway/internal/orgcontext"
@@ -15,6 +16,7 @@ import (
 	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -59,9 +61,41 @@ func (m *subscriptionMock) GetSubscriptionItem(context.Context, subscriptiondoma
 func (m *subscriptionMock) TransitionSubscription(ctx context.Context, id string, status subscriptiondomain.SubscriptionStatus, reason subscriptiondomain.TransitionReason) error {
 	return nil
 }
+func (m *subscriptionMock) CancelSubscription(ctx context.Context, req subscriptiondomain.CancelSubscriptionRequest) error {
+	return nil
+}
+func (m *subscriptionMock) ListBillingCycles(ctx context.Context, req subscriptiondomain.ListBillingCyclesRequest) (subscriptiondomain.ListBillingCyclesResponse, error) {
+	return subscriptiondomain.ListBillingCyclesResponse{}, nil
+}
+func (m *subscriptionMock) ListEvents(ctx context.Context, req subscriptiondomain.ListSubscriptionEventsRequest) (subscriptiondomain.ListSubscriptionEventsResponse, error) {
+	return subscriptiondomain.ListSubscriptionEventsResponse{}, nil
+}
+
+func (m *subscriptionMock) UpdateMetadata(ctx context.Context, req subscriptiondomain.UpdateMetadataRequest) (subscriptiondomain.Subscription, error) {
+	return subscriptiondomain.Subscription{}, nil
+}
+
+func (m *subscriptionMock) CheckEntitlement(ctx context.Context, req subscriptiondomain.CheckEntitlementRequest) (subscriptiondomain.EntitlementCheckResponse, error) {
+	return subscriptiondomain.EntitlementCheckResponse{}, nil
+}
+func (m *subscriptionMock) GetSeatUsage(ctx context.Context, subscriptionID string) (subscriptiondomain.SeatUsageResponse, error) {
+	return subscriptiondomain.SeatUsageResponse{}, nil
+}
+func (m *subscriptionMock) ListItems(ctx context.Context, req subscriptiondomain.ListSubscriptionItemsRequest) (subscriptiondomain.ListSubscriptionItemsResponse, error) {
+	return subscriptiondomain.ListSubscriptionItemsResponse{}, nil
+}
+func (m *subscriptionMock) UpdateItemQuantity(ctx context.Context, req subscriptiondomain.UpdateItemQuantityRequest) (subscriptiondomain.UpdateItemQuantityResponse, error) {
+	return subscriptiondomain.UpdateItemQuantityResponse{}, nil
+}
 func (m *subscriptionMock) ChangePlan(ctx context.Context, req subscriptiondomain.ChangePlanRequest) error {
 	return nil
 }
+func (m *subscriptionMock) PreviewProration(ctx context.Context, req subscriptiondomain.PreviewProrationRequest) (subscriptiondomain.PreviewProrationResponse, error) {
+	return subscriptiondomain.PreviewProrationResponse{}, nil
+}
+func (m *subscriptionMock) GetEntitlementHistory(context.Context, subscriptiondomain.GetEntitlementHistoryRequest) (subscriptiondomain.EntitlementHistoryResponse, error) {
+	return subscriptiondomain.EntitlementHistoryResponse{}, nil
+}
 
 type meterMock struct {
 	mock.Mock
@@ -79,8 +113,8 @@ func (m *meterMock) GetByCode(ctx context.Context, code string) (*domain.Respons
 func (m *meterMock) Create(ctx context.Context, req domain.CreateRequest) (*domain.Response, error) {
 	return nil, nil
 }
-func (m *meterMock) List(ctx context.Context, req domain.ListRequest) ([]domain.Response, error) {
-	return nil, nil
+func (m *meterMock) List(ctx context.Context, req domain.ListRequest) (domain.ListResponse, error) {
+	return domain.ListResponse{}, nil
 }
 func (m *meterMock) GetByID(ctx context.Context, id string) (*domain.Response, error) {
 	return nil, nil
@@ -118,7 +152,10 @@ func TestIngest_EntitlementGating(t *testing.T) {
 		t.Fatal(err)
 	}
 	// Migrate usage_events table
-	if err := db.AutoMigrate(&usagedomain.UsageEvent{}); err != nil {
+	if err := db.AutoMigrate(&usagedomain.UsageEvent{}, &billingcycledomain.BillingCycle{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS ux_usage_events_idempotency ON usage_events(org_id, idempotency_key)").Error; err != nil {
 		t.Fatal(err)
 	}
 
@@ -132,11 +169,12 @@ func TestIngest_EntitlementGating(t *testing.T) {
 	subID := genID.Generate()
 
 	tests := []struct {
-		name         string
-		req          usagedomain.CreateIngestRequest
-		setupMocks   func(*subscriptionMock, *meterMock, *quotaMock)
-		expectedErr  error
-		expectIngest bool // If we expect ingestion to succeed
+		name          string
+		req           usagedomain.CreateIngestRequest
+		setupMocks    func(*subscriptionMock, *meterMock, *quotaMock)
+		expectedErr   error
+		expectIngest  bool // If we expect ingestion to succeed
+		expectWarning string
 	}{
 		{
 			name: "Success: Valid Entitlement",
@@ -165,6 +203,84 @@ func TestIngest_EntitlementGating(t *testing.T) {
 			expectedErr:  nil,
 			expectIngest: true,
 		},
+		{
+			name: "Success: Meter Code Case Normalized",
+			req: usagedomain.CreateIngestRequest{
+				CustomerID:     customerID.String(),
+				MeterCode:      " M1 ",
+				Value:          10,
+				RecordedAt:     time.Now(),
+				IdempotencyKey: "k_case",
+			},
+			setupMocks: func(s *subscriptionMock, m *meterMock, q *quotaMock) {
+				q.On("CanIngestUsage", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetByCode", mock.Anything, "m1").Return(&meterdomain.Response{
+					ID:   meterID.String(),
+					Code: "m1",
+				}, nil)
+				s.On("GetActiveByCustomerID", mock.Anything, mock.Anything).Return(subscriptiondomain.Subscription{ID: subID}, nil)
+				s.On("ValidateUsageEntitlement", mock.Anything, subID, meterID, mock.Anything).Return(nil)
+			},
+			expectedErr:  nil,
+			expectIngest: true,
+		},
+		{
+			name: "Success: Negative Adjustment",
+			req: usagedomain.CreateIngestRequest{
+				CustomerID:     customerID.String(),
+				MeterCode:      "m1",
+				Value:          -4,
+				Type:           usagedomain.UsageTypeAdjustment,
+				RecordedAt:     time.Now(),
+				IdempotencyKey: "k_adjustment",
+			},
+			setupMocks: func(s *subscriptionMock, m *meterMock, q *quotaMock) {
+				q.On("CanIngestUsage", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetByCode", mock.Anything, "m1").Return(&meterdomain.Response{
+					ID:          meterID.String(),
+					Code:        "m1",
+					Aggregation: meterdomain.AggregationSum,
+				}, nil)
+				s.On("GetActiveByCustomerID", mock.Anything, mock.Anything).Return(subscriptiondomain.Subscription{ID: subID}, nil)
+				s.On("ValidateUsageEntitlement", mock.Anything, subID, meterID, mock.Anything).Return(nil)
+			},
+			expectedErr:  nil,
+			expectIngest: true,
+		},
+		{
+			name: "Failure: Negative Usage",
+			req: usagedomain.CreateIngestRequest{
+				CustomerID:     customerID.String(),
+				MeterCode:      "m1",
+				Value:          -4,
+				RecordedAt:     time.Now(),
+				IdempotencyKey: "k_negative",
+			},
+			expectedErr:  usagedomain.ErrInvalidValue,
+			expectIngest: false,
+		},
+		{
+			name: "Failure: Adjustment On Max Meter",
+			req: usagedomain.CreateIngestRequest{
+				CustomerID:     customerID.String(),
+				MeterCode:      "m1",
+				Value:          -4,
+				Type:           usagedomain.UsageTypeAdjustment,
+				RecordedAt:     time.Now(),
+				IdempotencyKey: "k_adjustment_max",
+			},
+			setupMocks: func(s *subscriptionMock, m *meterMock, q *quotaMock) {
+				q.On("CanIngestUsage", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetByCode", mock.Anything, "m1").Return(&meterdomain.Response{
+					ID:          meterID.String(),
+					Code:        "m1",
+					Aggregation: meterdomain.AggregationMax,
+				}, nil)
+				s.On("GetActiveByCustomerID", mock.Anything, mock.Anything).Return(subscriptiondomain.Subscription{ID: subID}, nil)
+			},
+			expectedErr:  usagedomain.ErrAdjustmentNotSupported,
+			expectIngest: false,
+		},
 		{
 			name: "Failure: Quota Exceeded",
 			req: usagedomain.CreateIngestRequest{
@@ -217,7 +333,49 @@ func TestIngest_EntitlementGating(t *testing.T) {
 			expectIngest: false,
 		},
 		{
-			name: "Failure: Not Entitled",
+			name: "Failure: Subscription Paused",
+			req: usagedomain.CreateIngestRequest{
+				CustomerID:     customerID.String(),
+				MeterCode:      "m1",
+				Value:          10,
+				RecordedAt:     time.Now(),
+				IdempotencyKey: "k_paused",
+			},
+			setupMocks: func(s *subscriptionMock, m *meterMock, q *quotaMock) {
+				q.On("CanIngestUsage", mock.Anything, mock.Anything).Return(nil)
+				s.On("GetActiveByCustomerID", mock.Anything, mock.Anything).Return(subscriptiondomain.Subscription{
+					ID:     subID,
+					Status: subscriptiondomain.SubscriptionStatusPaused,
+				}, nil)
+			},
+			expectedErr:  usagedomain.ErrSubscriptionPaused,
+			expectIngest: false,
+		},
+		{
+			name: "Success: Subscription Past Due Warns",
+			req: usagedomain.CreateIngestRequest{
+				CustomerID:     customerID.String(),
+				MeterCode:      "m1",
+				Value:          10,
+				RecordedAt:     time.Now(),
+				IdempotencyKey: "k_past_due",
+			},
+			setupMocks: func(s *subscriptionMock, m *meterMock, q *quotaMock) {
+				q.On("CanIngestUsage", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetByCode", mock.Anything, "m1").Return(&meterdomain.Response{
+					ID:   meterID.String(),
+					Code: "m1",
+				}, nil)
+				s.On("GetActiveByCustomerID", mock.Anything, mock.Anything).Return(subscriptiondomain.Subscription{
+					ID:     subID,
+					Status: subscriptiondomain.SubscriptionStatusPastDue,
+				}, nil)
+			},
+			expectIngest:  true,
+			expectWarning: usagedomain.WarningSubscriptionPastDue,
+		},
+		{
+			name: "Success: Not Entitled Is Left To Enrichment",
 			req: usagedomain.CreateIngestRequest{
 				CustomerID:     customerID.String(),
 				MeterCode:      "m1",
@@ -232,11 +390,10 @@ func TestIngest_EntitlementGating(t *testing.T) {
 					Code: "m1",
 				}, nil)
 				s.On("GetActiveByCustomerID", mock.Anything, mock.Anything).Return(subscriptiondomain.Subscription{ID: subID}, nil)
-				// 3. Validate Entitlement -> FeatureNotEntitled
+				// 3. Entitlements are checked by the snapshot worker, not here.
 				s.On("ValidateUsageEntitlement", mock.Anything, subID, meterID, mock.Anything).Return(subscriptiondomain.ErrFeatureNotEntitled)
 			},
-			expectedErr:  usagedomain.ErrFeatureNotEntitled,
-			expectIngest: false,
+			expectIngest: true,
 		},
 	}
 
@@ -275,7 +432,8 @@ func TestIngest_EntitlementGating(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				if tt.expectIngest {
-					assert.NotNil(t, res)
+					require.NotNil(t, res)
+					assert.Equal(t, tt.expectWarning, res.Warning)
 				}
 			}
 		})
@@ -284,7 +442,7 @@ func TestIngest_EntitlementGating(t *testing.T) {
 
 func TestIngest_Idempotency_Strict(t *testing.T) {
 	db, _ := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	db.AutoMigrate(&usagedomain.UsageEvent{})
+	db.AutoMigrate(&usagedomain.UsageEvent{}, &billingcycledomain.BillingCycle{})
 	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS ux_usage_events_idempotency ON usage_events(org_id, idempotency_key)")
 
 	node, _ := snowflake.NewNode(1)
@@ -349,7 +507,7 @@ func TestIngest_Idempotency_Strict(t *testing.T) {
 func TestIngest_Idempotency_BypassEntitlementFailure(t *testing.T) {
 	// dedicated test for the "Entitlement Revoked" Case
 	db, _ := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	db.AutoMigrate(&usagedomain.UsageEvent{})
+	db.AutoMigrate(&usagedomain.UsageEvent{}, &billingcycledomain.BillingCycle{})
 	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS ux_usage_events_idempotency ON usage_events(org_id, idempotency_key)")
 
 	node, _ := snowflake.NewNode(1)
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// checkLateArrival finds the subscription's billing cycle covering
+// recordedAt. Usage for an open or closing cycle, or outside every cycle, is
+// accepted as is. Usage for a closed cycle is accepted until the grace window
+// after the cycle's end has passed, and the cycle is returned so it can be
+// re-rated; later usage is rejected with ErrUsageWindowClosed. So is usage
+// for a cycle already invoiced, whose invoice would not pick up the new
+// rating, or already re-rated into an adjustment of its ledger accrual, as
+// the scheduler posts one adjustment per cycle.
+func (s *Service) checkLateArrival(
+	ctx context.Context,
+	db *gorm.DB,
+	orgID, subscriptionID snowflake.ID,
+	recordedAt, now time.Time,
+) (snowflake.ID, error) {
+	if db == nil {
+		return 0, errors.New("missing_db")
+	}
+
+	var cycle struct {
+		ID         snowflake.ID
+		Status     billingcycledomain.BillingCycleStatus
+		PeriodEnd  time.Time
+		InvoicedAt *time.Time
+	}
+	err := db.WithContext(ctx).Raw(
+		`SELECT id, status, period_end, invoiced_at
+		 FROM billing_cycles
+		 WHERE org_id = ? AND subscription_id = ? AND period_start <= ? AND period_end > ?
+		 ORDER BY period_start DESC
+		 LIMIT 1`,
+		orgID, subscriptionID, recordedAt, recordedAt,
+	).Scan(&cycle).Error
+	if err != nil {
+		return 0, err
+	}
+	if cycle.ID == 0 || cycle.Status != billingcycledomain.BillingCycleStatusClosed {
+		return 0, nil
+	}
+	if cycle.InvoicedAt != nil || now.After(cycle.PeriodEnd.Add(s.lateArrivalGrace)) {
+		return 0, usagedomain.ErrUsageWindowClosed
+	}
+
+	var adjustments int64
+	err = db.WithContext(ctx).Raw(
+		`SELECT COUNT(1)
+		 FROM ledger_entries
+		 WHERE org_id = ? AND source_type = ? AND source_id = ?`,
+		orgID, ledgerdomain.SourceTypeAdjustment, cycle.ID,
+	).Scan(&adjustments).Error
+	if err != nil {
+		return 0, err
+	}
+	if adjustments > 0 {
+		return 0, usagedomain.ErrUsageWindowClosed
+	}
+	return cycle.ID, nil
+}
+
+// reopenForReRating moves a closed cycle back to closing with its rating
+// cleared, which queues it for the scheduler's rating job. A cycle invoiced
+// since checkLateArrival looked at it is left alone.
+func (s *Service) reopenForReRating(ctx context.Context, db *gorm.DB, cycleID snowflake.ID, now time.Time) error {
+	result := db.WithContext(ctx).Exec(
+		`UPDATE billing_cycles
+		 SET status = ?, closing_started_at = ?, rating_completed_at = NULL,
+		     closed_at = NULL, last_error = NULL, last_error_at = NULL, updated_at = ?
+		 WHERE id = ? AND status = ? AND invoiced_at IS NULL`,
+		billingcycledomain.BillingCycleStatusClosing,
+		now,
+		now,
+		cycleID,
+		billingcycledomain.BillingCycleStatusClosed,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		s.log.Info("billing cycle reopened for late usage",
+			zap.String("billing_cycle_id", cycleID.String()),
+		)
+	}
+	return nil
+}
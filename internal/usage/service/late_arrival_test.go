@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	billingcycledomain "github.com/railzwaylabs/railzway/internal/billingcycle/domain"
+	"github.com/railzwaylabs/railzway/internal/config"
+	ledgerdomain "github.com/railzwaylabs/railzway/internal/ledger/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TestIngest_LateArrivalReopensClosedCycle verifies that usage for a closed
+// cycle within the grace window sends the cycle back to rating, and that
+// usage past the window is rejected.
+func TestIngest_LateArrivalReopensClosedCycle(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&usagedomain.UsageEvent{}, &billingcycledomain.BillingCycle{}, &ledgerdomain.LedgerEntry{}))
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX uidx_usage_idempotency_key
+		ON usage_events (org_id, idempotency_key)`).Error)
+
+	node := mustNode(t)
+	orgID := node.Generate()
+	customerID := node.Generate()
+	subID := node.Generate()
+	meterID := node.Generate()
+
+	now := time.Now().UTC()
+	cycle := func(start, end time.Time) billingcycledomain.BillingCycle {
+		closedAt := end
+		ratedAt := end
+		c := billingcycledomain.BillingCycle{
+			ID:                node.Generate(),
+			OrgID:             orgID,
+			SubscriptionID:    subID,
+			PeriodStart:       start,
+			PeriodEnd:         end,
+			Status:            billingcycledomain.BillingCycleStatusClosed,
+			RatingCompletedAt: &ratedAt,
+			ClosedAt:          &closedAt,
+		}
+		require.NoError(t, db.Create(&c).Error)
+		return c
+	}
+	recentEnd := now.Add(-24 * time.Hour)
+	recent := cycle(recentEnd.AddDate(0, -1, 0), recentEnd)
+	old := cycle(recentEnd.AddDate(0, -2, 0), recentEnd.AddDate(0, -1, 0))
+
+	mockMeter := new(meterMock)
+	mockMeter.On("GetByCode", mock.Anything, "api_calls").Return(&meterdomain.Response{ID: meterID.String(), Code: "api_calls"}, nil)
+	mockQuota := new(quotaMock)
+	mockQuota.On("CanIngestUsage", mock.Anything, orgID).Return(nil)
+	mockSub := new(subscriptionMock)
+	mockSub.On("GetActiveByCustomerID", mock.Anything, subscriptiondomain.GetActiveByCustomerIDRequest{CustomerID: customerID.String()}).
+		Return(subscriptiondomain.Subscription{ID: subID}, nil)
+	mockSub.On("ValidateUsageEntitlement", mock.Anything, subID, meterID, mock.Anything).Return(nil)
+
+	svc := NewService(ServiceParam{
+		DB:       db,
+		Log:      zap.NewNop(),
+		GenID:    node,
+		MeterSvc: mockMeter,
+		SubSvc:   mockSub,
+		QuotaSvc: mockQuota,
+		Cfg:      config.Config{Usage: config.UsageConfig{LateArrivalGraceHours: 72}},
+	})
+	ctx := WithTestOrgContext(context.Background(), orgID)
+
+	event := func(key string, at time.Time) usagedomain.CreateIngestRequest {
+		return usagedomain.CreateIngestRequest{
+			CustomerID:     customerID.String(),
+			MeterCode:      "api_calls",
+			Value:          5,
+			RecordedAt:     at,
+			IdempotencyKey: key,
+		}
+	}
+
+	_, err = svc.Ingest(ctx, event("late-1", recent.PeriodStart.Add(time.Hour)))
+	require.NoError(t, err)
+
+	var reopened billingcycledomain.BillingCycle
+	require.NoError(t, db.First(&reopened, "id = ?", recent.ID).Error)
+	assert.Equal(t, billingcycledomain.BillingCycleStatusClosing, reopened.Status)
+	assert.Nil(t, reopened.RatingCompletedAt)
+	assert.Nil(t, reopened.ClosedAt)
+	require.NotNil(t, reopened.ClosingStartedAt)
+
+	_, err = svc.Ingest(ctx, event("late-2", old.PeriodStart.Add(time.Hour)))
+	assert.ErrorIs(t, err, usagedomain.ErrUsageWindowClosed)
+
+	resp, err := svc.BatchIngest(ctx, []usagedomain.CreateIngestRequest{
+		event("late-3", old.PeriodStart.Add(2*time.Hour)),
+		event("late-4", now.Add(-time.Minute)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, usagedomain.BatchEventStatusRejected, resp.Results[0].Status)
+	assert.Equal(t, usagedomain.ErrUsageWindowClosed.Error(), resp.Results[0].Reason)
+	assert.Equal(t, usagedomain.BatchEventStatusIngested, resp.Results[1].Status)
+
+	var stale billingcycledomain.BillingCycle
+	require.NoError(t, db.First(&stale, "id = ?", old.ID).Error)
+	assert.Equal(t, billingcycledomain.BillingCycleStatusClosed, stale.Status)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/bwmarrin/snowflake"
 	"github.com/railzwaylabs/railzway/internal/cache"
 	"github.com/railzwaylabs/railzway/internal/cloudmetrics"
+	"github.com/railzwaylabs/railzway/internal/config"
 	"github.com/railzwaylabs/railzway/internal/events"
 	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
 	obsmetrics "github.com/railzwaylabs/railzway/internal/observability/metrics"
@@ -42,6 +43,7 @@ type ServiceParam struct {
 	Outbox        *events.Outbox  `optional:"true"`
 	LiveEvents    *liveevents.Hub `optional:"true"`
 	QuotaSvc      quotadomain.Service
+	Cfg           config.Config
 }
 
 type Service struct {
@@ -58,6 +60,8 @@ type Service struct {
 	outbox        *events.Outbox
 	liveEvents    *liveevents.Hub
 	quotaSvc      quotadomain.Service
+
+	lateArrivalGrace time.Duration
 }
 
 func NewService(p ServiceParam) usagedomain.Service {
@@ -75,6 +79,8 @@ func NewService(p ServiceParam) usagedomain.Service {
 		outbox:        p.Outbox,
 		liveEvents:    p.LiveEvents,
 		quotaSvc:      p.QuotaSvc,
+
+		lateArrivalGrace: time.Duration(p.Cfg.Usage.LateArrivalGraceHours) * time.Hour,
 	}
 }
 
@@ -93,7 +99,7 @@ func (s *Service) Ingest(
 		return nil, err
 	}
 
-	meterCode := strings.TrimSpace(req.MeterCode)
+	meterCode := meterdomain.NormalizeCode(req.MeterCode)
 	if meterCode == "" {
 		return nil, usagedomain.ErrInvalidMeterCode
 	}
@@ -131,6 +137,9 @@ func (s *Service) Ingest(
 	if sub.ID == 0 {
 		return nil, usagedomain.ErrInvalidSubscription
 	}
+	if sub.Status == subscriptiondomain.SubscriptionStatusPaused {
+		return nil, usagedomain.ErrSubscriptionPaused
+	}
 
 	meter, err := s.resolveMeter(ctx, orgID, meterCode)
 	if err != nil {
@@ -139,6 +148,10 @@ func (s *Service) Ingest(
 	if meter == nil {
 		return nil, usagedomain.ErrInvalidMeter
 	}
+	usageType, _ := usagedomain.NormalizeUsageType(req.Type)
+	if err := validateUsageTypeForMeter(usageType, meter.Aggregation); err != nil {
+		return nil, err
+	}
 
 	now := time.Now().UTC()
 	recordedAt := req.RecordedAt
@@ -150,25 +163,19 @@ func (s *Service) Ingest(
 		go s.metrics.IncUsageLateEvent(orgID.String(), meterCode)
 	}
 
-	// Entitlement Check: Validate that this usage is allowed.
-	meterID, err := snowflake.ParseString(meter.ID)
+	// Entitlements are checked by the snapshot worker when it enriches the
+	// event, keeping them off the ingest path.
+
+	// Usage for a closed cycle reopens it for re-rating within the grace
+	// window and is rejected after it.
+	reRateCycleID, err := s.checkLateArrival(ctx, s.db, orgID, sub.ID, recordedAt, now)
 	if err != nil {
-		return nil, usagedomain.ErrInvalidMeter
+		return nil, err
 	}
-
-	if s.subSvc != nil {
-		if err := s.subSvc.ValidateUsageEntitlement(ctx, sub.ID, meterID, recordedAt); err != nil {
-			// If feature not entitled, we must reject.
-			if errors.Is(err, subscriptiondomain.ErrFeatureNotEntitled) {
-				return nil, usagedomain.ErrFeatureNotEntitled
-			}
-			// For other errors (db issues), return them?
-			// Strict gating -> if we can't validate, we shouldn't accept.
+	if reRateCycleID != 0 {
+		if err := s.reopenForReRating(ctx, s.db, reRateCycleID, now); err != nil {
 			return nil, err
 		}
-	} else {
-		// Critical: If subSvc is missing, we cannot enforce gating.
-		return nil, errors.New("usage_ingestion_gating_unavailable")
 	}
 
 	// idempotencyKey already normalized above
@@ -179,6 +186,7 @@ func (s *Service) Ingest(
 		CustomerID:     customerID,
 		MeterCode:      meterCode,
 		Value:          req.Value,
+		Type:           usageType,
 		RecordedAt:     recordedAt,
 		Status:         usagedomain.UsageStatusAccepted,
 		IdempotencyKey: idempotencyKey,
@@ -189,6 +197,9 @@ func (s *Service) Ingest(
 	if req.Metadata != nil {
 		record.Metadata = datatypes.JSONMap(req.Metadata)
 	}
+	if len(req.Dimensions) > 0 {
+		record.Dimensions = datatypes.JSONMap(req.Dimensions)
+	}
 
 	inserted, err := s.insertUsageEvent(ctx, record, idempotencyKey)
 	if err != nil {
@@ -220,6 +231,15 @@ func (s *Service) Ingest(
 		s.obsMetrics.RecordUsageIngest(ctx, meterCode)
 	}
 
+	if sub.Status == subscriptiondomain.SubscriptionStatusPastDue {
+		record.Warning = usagedomain.WarningSubscriptionPastDue
+		s.log.Warn("usage ingested for past due subscription",
+			zap.String("org_id", orgID.String()),
+			zap.String("subscription_id", sub.ID.String()),
+			zap.String("meter_code", meterCode),
+		)
+	}
+
 	s.emitUsageIngested(record)
 	s.emitLiveUsageEvent(record, liveevents.StatusAccepted, liveevents.SourceAPI)
 
@@ -388,8 +408,8 @@ func (s *Service) insertUsageEventSQLite(ctx context.Context, record *usagedomai
 	query := `INSERT INTO usage_events (
 		id, org_id, customer_id, subscription_id, subscription_item_id,
 		meter_id, meter_code, value, recorded_at, status, error,
-		idempotency_key, metadata, created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		idempotency_key, metadata, dimensions, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	if idempotencyKey != "" {
 		query += " ON CONFLICT (org_id, idempotency_key) DO NOTHING"
 	}
@@ -408,6 +428,7 @@ func (s *Service) insertUsageEventSQLite(ctx context.Context, record *usagedomai
 		record.Error,
 		idempotencyKey,
 		record.Metadata,
+		record.Dimensions,
 		record.CreatedAt,
 		record.UpdatedAt,
 	)
@@ -503,14 +524,32 @@ func buildIdempotencyConflictClause(db *gorm.DB) clause.OnConflict {
 	return conflict
 }
 
+// validateUsageTypeForMeter rejects adjustments on meters that do not sum
+// their usage, where a correction has no meaning. Meters with an unknown
+// aggregation are summed by rating and accept them.
+func validateUsageTypeForMeter(usageType string, aggregation string) error {
+	if usageType != usagedomain.UsageTypeAdjustment {
+		return nil
+	}
+	if normalized, ok := meterdomain.NormalizeAggregation(aggregation); ok && normalized != meterdomain.AggregationSum {
+		return usagedomain.ErrAdjustmentNotSupported
+	}
+	return nil
+}
+
 func validateUsageEvent(req usagedomain.CreateIngestRequest) error {
 	// Check for NaN and Inf
 	if math.IsNaN(req.Value) || math.IsInf(req.Value, 0) {
 		return usagedomain.ErrInvalidValue
 	}
 
-	// Reject negative values
-	if req.Value < 0 {
+	usageType, ok := usagedomain.NormalizeUsageType(req.Type)
+	if !ok {
+		return usagedomain.ErrInvalidUsageType
+	}
+
+	// Reject negative values unless they correct earlier usage
+	if req.Value < 0 && usageType != usagedomain.UsageTypeAdjustment {
 		return usagedomain.ErrInvalidValue
 	}
 
@@ -533,6 +572,20 @@ func validateUsageEvent(req usagedomain.CreateIngestRequest) error {
 		return usagedomain.ErrInvalidIdempotencyKey
 	}
 
+	return validateDimensions(req.Dimensions)
+}
+
+// validateDimensions accepts only non-empty string keys mapped to string
+// values so rating can filter on them with a plain text comparison.
+func validateDimensions(dimensions map[string]any) error {
+	for key, value := range dimensions {
+		if strings.TrimSpace(key) == "" {
+			return usagedomain.ErrInvalidDimensions
+		}
+		if _, ok := value.(string); !ok {
+			return usagedomain.ErrInvalidDimensions
+		}
+	}
 	return nil
 }
 
@@ -582,7 +635,7 @@ func (s *Service) buildUsageFilter(ctx context.Context, req usagedomain.ListUsag
 		filter.MeterID = meterID
 	}
 
-	if meterCode := strings.TrimSpace(req.MeterCode); meterCode != "" {
+	if meterCode := meterdomain.NormalizeCode(req.MeterCode); meterCode != "" {
 		filter.MeterCode = meterCode
 	}
 
@@ -590,6 +643,14 @@ func (s *Service) buildUsageFilter(ctx context.Context, req usagedomain.ListUsag
 		filter.Status = strings.ToLower(status)
 	}
 
+	if req.ImportID != "" {
+		importID, err := s.parseID(req.ImportID, usagedomain.ErrInvalidImportID)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.ImportID = &importID
+	}
+
 	pageSize := req.PageSize
 	if pageSize <= 0 {
 		pageSize = 50
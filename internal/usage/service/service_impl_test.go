@@ -29,8 +29,8 @@ func (m *meterStub) Create(ctx context.Context, req meterdomain.CreateRequest) (
 	return nil, m.err
 }
 
-func (m *meterStub) List(ctx context.Context, req meterdomain.ListRequest) ([]meterdomain.Response, error) {
-	return nil, m.err
+func (m *meterStub) List(ctx context.Context, req meterdomain.ListRequest) (meterdomain.ListResponse, error) {
+	return meterdomain.ListResponse{}, m.err
 }
 
 func (m *meterStub) GetByID(ctx context.Context, id string) (*meterdomain.Response, error) {
@@ -266,9 +266,41 @@ func (s *subscriptionStub) GetSubscriptionItem(context.Context, subscriptiondoma
 func (s *subscriptionStub) TransitionSubscription(ctx context.Context, id string, status subscriptiondomain.SubscriptionStatus, reason subscriptiondomain.TransitionReason) error {
 	return nil
 }
+func (s *subscriptionStub) CancelSubscription(ctx context.Context, req subscriptiondomain.CancelSubscriptionRequest) error {
+	return nil
+}
+func (s *subscriptionStub) ListBillingCycles(ctx context.Context, req subscriptiondomain.ListBillingCyclesRequest) (subscriptiondomain.ListBillingCyclesResponse, error) {
+	return subscriptiondomain.ListBillingCyclesResponse{}, nil
+}
+func (s *subscriptionStub) ListEvents(ctx context.Context, req subscriptiondomain.ListSubscriptionEventsRequest) (subscriptiondomain.ListSubscriptionEventsResponse, error) {
+	return subscriptiondomain.ListSubscriptionEventsResponse{}, nil
+}
+
+func (s *subscriptionStub) UpdateMetadata(ctx context.Context, req subscriptiondomain.UpdateMetadataRequest) (subscriptiondomain.Subscription, error) {
+	return subscriptiondomain.Subscription{}, nil
+}
+
+func (s *subscriptionStub) CheckEntitlement(ctx context.Context, req subscriptiondomain.CheckEntitlementRequest) (subscriptiondomain.EntitlementCheckResponse, error) {
+	return subscriptiondomain.EntitlementCheckResponse{}, nil
+}
+func (s *subscriptionStub) GetSeatUsage(ctx context.Context, subscriptionID string) (subscriptiondomain.SeatUsageResponse, error) {
+	return subscriptiondomain.SeatUsageResponse{}, nil
+}
+func (s *subscriptionStub) ListItems(ctx context.Context, req subscriptiondomain.ListSubscriptionItemsRequest) (subscriptiondomain.ListSubscriptionItemsResponse, error) {
+	return subscriptiondomain.ListSubscriptionItemsResponse{}, nil
+}
+func (s *subscriptionStub) UpdateItemQuantity(ctx context.Context, req subscriptiondomain.UpdateItemQuantityRequest) (subscriptiondomain.UpdateItemQuantityResponse, error) {
+	return subscriptiondomain.UpdateItemQuantityResponse{}, nil
+}
 func (s *subscriptionStub) ChangePlan(ctx context.Context, req subscriptiondomain.ChangePlanRequest) error {
 	return nil
 }
+func (s *subscriptionStub) PreviewProration(ctx context.Context, req subscriptiondomain.PreviewProrationRequest) (subscriptiondomain.PreviewProrationResponse, error) {
+	return subscriptiondomain.PreviewProrationResponse{}, nil
+}
+func (s *subscriptionStub) GetEntitlementHistory(context.Context, subscriptiondomain.GetEntitlementHistoryRequest) (subscriptiondomain.EntitlementHistoryResponse, error) {
+	return subscriptiondomain.EntitlementHistoryResponse{}, nil
+}
 
 func prepareUsageSchema(t *testing.T, db *gorm.DB) {
 	t.Helper()
@@ -292,6 +324,7 @@ func prepareUsageSchema(t *testing.T, db *gorm.DB) {
 		error TEXT,
 		idempotency_key TEXT,
 		metadata JSON,
+		dimensions JSON,
 		snapshot_at DATETIME,
 		created_at DATETIME NOT NULL,
 		updated_at DATETIME NOT NULL
@@ -302,6 +335,23 @@ func prepareUsageSchema(t *testing.T, db *gorm.DB) {
 		ON usage_events (org_id, idempotency_key)`).Error; err != nil {
 		t.Fatalf("create usage idempotency index: %v", err)
 	}
+	if err := db.Exec(`CREATE TABLE billing_cycles (
+		id BIGINT PRIMARY KEY,
+		org_id BIGINT NOT NULL,
+		subscription_id BIGINT NOT NULL,
+		period_start DATETIME NOT NULL,
+		period_end DATETIME NOT NULL,
+		status TEXT NOT NULL DEFAULT 'OPEN',
+		closing_started_at DATETIME,
+		rating_completed_at DATETIME,
+		closed_at DATETIME,
+		invoiced_at DATETIME,
+		last_error TEXT,
+		last_error_at DATETIME,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`).Error; err != nil {
+		t.Fatalf("create billing_cycles: %v", err)
+	}
 }
 
 func seedCustomer(t *testing.T, db *gorm.DB, orgID, customerID snowflake.ID) {
@@ -332,3 +382,28 @@ func mustNode(t *testing.T) *snowflake.Node {
 	}
 	return node
 }
+
+func TestValidateDimensions(t *testing.T) {
+	cases := []struct {
+		name       string
+		dimensions map[string]any
+		valid      bool
+	}{
+		{"empty", nil, true},
+		{"strings", map[string]any{"region": "us", "instance_type": "large"}, true},
+		{"blank key", map[string]any{" ": "us"}, false},
+		{"number value", map[string]any{"region": 1}, false},
+		{"nested value", map[string]any{"region": map[string]any{"name": "us"}}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateDimensions(tc.dimensions)
+			if tc.valid && err != nil {
+				t.Fatalf("expected valid dimensions, got %v", err)
+			}
+			if !tc.valid && err != usagedomain.ErrInvalidDimensions {
+				t.Fatalf("expected %v, got %v", usagedomain.ErrInvalidDimensions, err)
+			}
+		})
+	}
+}
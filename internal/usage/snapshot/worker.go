@@ -2,10 +2,10 @@ package snapshot
 
 import (
 	"context"
-	"strings"
 	"time"
 
 	"github.com/railzwaylabs/railzway/internal/clock"
+	featuredomain "github.com/railzwaylabs/railzway/internal/feature/domain"
 	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
 	"github.com/railzwaylabs/railzway/internal/observability/metrics"
 	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
@@ -139,7 +139,7 @@ func (w *Worker) buildSnapshot(
 		SnapshotAt: now,
 	}
 
-	meterCode := strings.TrimSpace(row.MeterCode)
+	meterCode := meterdomain.NormalizeCode(row.MeterCode)
 	meter, err := w.meterRepo.FindByCode(ctx, tx, row.OrgID, meterCode)
 	if err != nil {
 		return update, err
@@ -159,6 +159,25 @@ func (w *Worker) buildSnapshot(
 	}
 	update.SubscriptionID = subscription.ID
 
+	// Entitlements are checked here rather than at ingestion so the ingest
+	// path stays fast. Usage the subscription is not entitled to is kept but
+	// never rated.
+	entitlement, err := w.subscriptionRepo.FindEntitlement(ctx, tx, subscription.ID, meter.ID, row.RecordedAt)
+	if err != nil {
+		return update, err
+	}
+	if entitlement == nil || entitlement.FeatureType != string(featuredomain.FeatureTypeMetered) {
+		reason := usagedomain.ErrFeatureNotEntitled.Error()
+		update.Status = usagedomain.UsageStatusInvalid
+		update.Error = &reason
+		return update, nil
+	}
+
+	// The meter is stamped even when no item bills it, so seat meters of
+	// licensed items can still be counted.
+	meterID := meter.ID
+	update.MeterID = &meterID
+
 	item, err := w.subscriptionRepo.FindSubscriptionItemByMeterIDAt(ctx, tx, row.OrgID, subscription.ID, meter.ID, row.RecordedAt)
 	if err != nil {
 		return update, err
@@ -167,9 +186,6 @@ func (w *Worker) buildSnapshot(
 	if item != nil && item.ID != 0 {
 		itemID := item.ID
 		update.SubscriptionItemID = &itemID
-
-		meterID := item.MeterID
-		update.MeterID = meterID
 	}
 
 	return update, nil
@@ -0,0 +1,95 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	featuredomain "github.com/railzwaylabs/railzway/internal/feature/domain"
+	meterdomain "github.com/railzwaylabs/railzway/internal/meter/domain"
+	subscriptiondomain "github.com/railzwaylabs/railzway/internal/subscription/domain"
+	usagedomain "github.com/railzwaylabs/railzway/internal/usage/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type meterRepoStub struct {
+	meterdomain.Repository
+	meter *meterdomain.Meter
+}
+
+func (m meterRepoStub) FindByCode(ctx context.Context, db *gorm.DB, orgID snowflake.ID, code string) (*meterdomain.Meter, error) {
+	return m.meter, nil
+}
+
+type subscriptionRepoStub struct {
+	subscriptiondomain.Repository
+	subscription *subscriptiondomain.Subscription
+	entitlement  *subscriptiondomain.SubscriptionEntitlement
+	item         *subscriptiondomain.SubscriptionItem
+}
+
+func (s subscriptionRepoStub) FindActiveByCustomerIDAt(ctx context.Context, db *gorm.DB, orgID, customerID snowflake.ID, at time.Time) (*subscriptiondomain.Subscription, error) {
+	return s.subscription, nil
+}
+
+func (s subscriptionRepoStub) FindEntitlement(ctx context.Context, db *gorm.DB, subscriptionID, meterID snowflake.ID, at time.Time) (*subscriptiondomain.SubscriptionEntitlement, error) {
+	return s.entitlement, nil
+}
+
+func (s subscriptionRepoStub) FindSubscriptionItemByMeterIDAt(ctx context.Context, db *gorm.DB, orgID, subscriptionID, meterID snowflake.ID, at time.Time) (*subscriptiondomain.SubscriptionItem, error) {
+	return s.item, nil
+}
+
+// TestBuildSnapshot_ChecksEntitlement verifies that enrichment binds an
+// entitled event to its subscription item and marks usage the subscription
+// is not entitled to as invalid.
+func TestBuildSnapshot_ChecksEntitlement(t *testing.T) {
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	row := usagedomain.SnapshotCandidate{
+		ID:         node.Generate(),
+		OrgID:      node.Generate(),
+		CustomerID: node.Generate(),
+		MeterCode:  "api_calls",
+		RecordedAt: now.Add(-time.Minute),
+	}
+	meter := &meterdomain.Meter{ID: node.Generate(), Code: "api_calls"}
+	subscription := &subscriptiondomain.Subscription{ID: node.Generate()}
+	item := &subscriptiondomain.SubscriptionItem{ID: node.Generate()}
+
+	build := func(entitlement *subscriptiondomain.SubscriptionEntitlement) usagedomain.SnapshotUpdate {
+		worker := &Worker{
+			meterRepo: meterRepoStub{meter: meter},
+			subscriptionRepo: subscriptionRepoStub{
+				subscription: subscription,
+				entitlement:  entitlement,
+				item:         item,
+			},
+		}
+		update, err := worker.buildSnapshot(context.Background(), nil, row, now)
+		require.NoError(t, err)
+		return update
+	}
+
+	entitled := build(&subscriptiondomain.SubscriptionEntitlement{FeatureType: string(featuredomain.FeatureTypeMetered)})
+	assert.Equal(t, usagedomain.UsageStatusEnriched, entitled.Status)
+	assert.Nil(t, entitled.Error)
+	assert.Equal(t, subscription.ID, entitled.SubscriptionID)
+	require.NotNil(t, entitled.SubscriptionItemID)
+	assert.Equal(t, item.ID, *entitled.SubscriptionItemID)
+
+	for _, entitlement := range []*subscriptiondomain.SubscriptionEntitlement{
+		nil,
+		{FeatureType: string(featuredomain.FeatureTypeBoolean)},
+	} {
+		update := build(entitlement)
+		assert.Equal(t, usagedomain.UsageStatusInvalid, update.Status)
+		require.NotNil(t, update.Error)
+		assert.Equal(t, usagedomain.ErrFeatureNotEntitled.Error(), *update.Error)
+		assert.Nil(t, update.SubscriptionItemID)
+	}
+}
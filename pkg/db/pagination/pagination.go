@@ -5,9 +5,17 @@ import (
 	"encoding/json"
 )
 
+const (
+	// DefaultPageSize is the page size of a list request that does not ask
+	// for one.
+	DefaultPageSize = 50
+	// MaxPageSize bounds how many rows a single list request can load.
+	MaxPageSize = 200
+)
+
 type Pagination struct {
 	PageToken string `form:"page_token"`
-	PageSize  int    `form:"page_size,default=10" validate:"gte=1,lte=250"` // Min 1, Max 250
+	PageSize  int    `form:"page_size,default=10" validate:"gte=1,lte=200"` // Min 1, Max 200
 }
 
 type Cursor struct {
@@ -16,9 +24,23 @@ type Cursor struct {
 }
 
 type PageInfo struct {
-	NextPageToken      string `json:"next_page_token"`
-	PreviousPageToken  string `json:"previous_page_token"`
-	HasMore            bool   `json:"has_more"`
+	NextPageToken     string `json:"next_page_token"`
+	PreviousPageToken string `json:"previous_page_token"`
+	HasMore           bool   `json:"has_more"`
+	PageSize          int    `json:"page_size,omitempty"`
+}
+
+// ClampPageSize returns the page size a list request is served with:
+// DefaultPageSize when none (or a negative one) was asked for, and at most
+// MaxPageSize.
+func ClampPageSize[T ~int | ~int32 | ~int64](size T) T {
+	if size <= 0 {
+		return DefaultPageSize
+	}
+	if size > MaxPageSize {
+		return MaxPageSize
+	}
+	return size
 }
 
 func EncodeCursor(data Cursor) (string, error) {
@@ -46,7 +68,7 @@ func DecodeCursor(data string) (*Cursor, error) {
 
 func BuildCursorPageInfo[T any](data []*T, limit int32, extractCursor func(*T) string) *PageInfo {
 	if len(data) == 0 {
-		return &PageInfo{HasMore: false}
+		return &PageInfo{HasMore: false, PageSize: int(limit)}
 	}
 
 	hasMore := false
@@ -58,6 +80,7 @@ func BuildCursorPageInfo[T any](data []*T, limit int32, extractCursor func(*T) s
 	pageInfo := &PageInfo{
 		HasMore:       hasMore,
 		NextPageToken: extractCursor(data[len(data)-1]),
+		PageSize:      int(limit),
 	}
 
 	return pageInfo